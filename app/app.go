@@ -2,10 +2,12 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/getsentry/sentry-go"
@@ -14,17 +16,21 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/gitshopapp/gitshop/internal/address"
 	"github.com/gitshopapp/gitshop/internal/cache"
 	"github.com/gitshopapp/gitshop/internal/catalog"
 	"github.com/gitshopapp/gitshop/internal/config"
 	"github.com/gitshopapp/gitshop/internal/crypto"
 	"github.com/gitshopapp/gitshop/internal/db"
 	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/featureflags"
+	"github.com/gitshopapp/gitshop/internal/fx"
 	"github.com/gitshopapp/gitshop/internal/githubapp"
 	"github.com/gitshopapp/gitshop/internal/handlers"
 	"github.com/gitshopapp/gitshop/internal/logging"
 	"github.com/gitshopapp/gitshop/internal/services"
 	"github.com/gitshopapp/gitshop/internal/session"
+	"github.com/gitshopapp/gitshop/internal/storage"
 	"github.com/gitshopapp/gitshop/internal/stripe"
 )
 
@@ -34,10 +40,14 @@ type App struct {
 	Config         *config.Config
 	Logger         *slog.Logger
 	DB             *pgxpool.Pool
+	ReadPool       *db.ReadPool
 	CacheProvider  cache.Provider
 	SessionManager *session.Manager
 	Handlers       *handlers.Handlers
 	sentryEnabled  bool
+
+	stopWorkers context.CancelFunc
+	workers     sync.WaitGroup
 }
 
 func New() (*App, error) {
@@ -58,10 +68,15 @@ func New() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	readPool, err := db.NewReadPool(database, cfg.DatabaseReplicaURLs, logger.With("component", "read_pool"))
+	if err != nil {
+		database.Close()
+		return nil, err
+	}
 
 	githubAuth, err := githubapp.NewAuth(cfg.GitHubAppID, cfg.GitHubPrivateKeyBase64)
 	if err != nil {
-		database.Close()
+		closeDatabase(database, readPool)
 		return nil, fmt.Errorf("failed to initialize GitHub auth: %w", err)
 	}
 
@@ -70,7 +85,7 @@ func New() (*App, error) {
 		RedisConnectionString: cfg.RedisConnectionString,
 	})
 	if err != nil {
-		database.Close()
+		closeDatabase(database, readPool)
 		return nil, fmt.Errorf("failed to initialize cache provider: %w", err)
 	}
 
@@ -80,120 +95,308 @@ func New() (*App, error) {
 	})
 	if err != nil {
 		closeCacheProvider(logger, cacheProvider)
-		database.Close()
+		closeDatabase(database, readPool)
 		return nil, fmt.Errorf("failed to initialize session store: %w", err)
 	}
 	sessionManager := session.NewManager(sessionStore, handlers.SecureCookiesFromConfig(cfg))
 
-	encryptor, err := crypto.NewEncryptor(cfg.EncryptionKey)
+	encryptor, err := crypto.NewKeyring(cfg.EncryptionKey, cfg.EncryptionKeys)
 	if err != nil {
 		closeSessionManager(logger, sessionManager)
 		closeCacheProvider(logger, cacheProvider)
-		database.Close()
+		closeDatabase(database, readPool)
 		return nil, fmt.Errorf("failed to initialize encryptor: %w", err)
 	}
 
+	linkKey := sha256.Sum256([]byte("gitshop file links:" + cfg.EncryptionKey))
+	fileStorage, err := storage.NewProvider(storage.Config{
+		Provider:   cfg.StorageProvider,
+		BaseURL:    cfg.BaseURL,
+		SigningKey: linkKey[:],
+		LocalDir:   cfg.StorageLocalDir,
+		S3: storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		},
+	})
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to initialize file storage: %w", err)
+	}
+	var warehouseStorage storage.Provider
+	if cfg.WarehouseS3Bucket != "" {
+		warehouseStorage, err = storage.NewS3Provider(storage.S3Config{
+			Endpoint:        cfg.WarehouseS3Endpoint,
+			Region:          cfg.WarehouseS3Region,
+			Bucket:          cfg.WarehouseS3Bucket,
+			AccessKeyID:     cfg.WarehouseS3AccessKeyID,
+			SecretAccessKey: cfg.WarehouseS3SecretAccessKey,
+			PathStyle:       cfg.WarehouseS3PathStyle,
+		})
+		if err != nil {
+			closeSessionManager(logger, sessionManager)
+			closeCacheProvider(logger, cacheProvider)
+			closeDatabase(database, readPool)
+			return nil, fmt.Errorf("failed to initialize warehouse storage: %w", err)
+		}
+	}
+	addressValidator, err := address.NewValidator(address.Config{
+		Provider:       cfg.AddressValidationProvider,
+		EasyPostAPIKey: cfg.EasyPostAPIKey,
+		GoogleAPIKey:   cfg.GoogleMapsAPIKey,
+	})
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to initialize address validation: %w", err)
+	}
+	rateSource, err := fx.NewRateSource(fx.Config{
+		Provider: cfg.FXRatesProvider,
+		BaseURL:  cfg.FXRatesURL,
+	}, cacheProvider)
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to initialize exchange rates: %w", err)
+	}
+
 	shopStore, err := db.NewShopStore(database, encryptor)
 	if err != nil {
 		closeSessionManager(logger, sessionManager)
 		closeCacheProvider(logger, cacheProvider)
-		database.Close()
+		closeDatabase(database, readPool)
 		return nil, fmt.Errorf("failed to initialize shop store: %w", err)
 	}
-	orderStore := db.NewOrderStore(database)
-	githubClient := githubapp.NewClient(githubAuth, logger.With("component", "github_client"))
+	shopStore = shopStore.WithReadPool(readPool)
+	emailIndex, err := db.NewCustomerEmailIndex(cfg.EmailIndexKey)
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to initialize customer email index: %w", err)
+	}
+	orderStore, err := db.NewOrderStore(database, encryptor, emailIndex)
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to initialize order store: %w", err)
+	}
+	orderStore = orderStore.WithReadPool(readPool)
+	featureFlagRules, err := featureflags.ParseRules(cfg.FeatureFlags)
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to parse feature flags: %w", err)
+	}
+	featureFlags := featureflags.New(featureFlagRules, db.NewFeatureFlagStore(database), logger.With("component", "feature_flags"))
+	webhookStore, err := db.NewWebhookStore(database, encryptor)
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to initialize webhook store: %w", err)
+	}
+	keyRotationStore, err := db.NewKeyRotationStore(database, encryptor)
+	if err != nil {
+		closeSessionManager(logger, sessionManager)
+		closeCacheProvider(logger, cacheProvider)
+		closeDatabase(database, readPool)
+		return nil, fmt.Errorf("failed to initialize key rotation store: %w", err)
+	}
+	githubClient := githubapp.NewClient(githubAuth, logger.With("component", "github_client")).WithResponseCache(cacheProvider)
 	authService, err := services.NewAuthService(cfg, shopStore, logger.With("component", "auth_service"))
 	if err != nil {
 		closeSessionManager(logger, sessionManager)
 		closeCacheProvider(logger, cacheProvider)
-		database.Close()
+		closeDatabase(database, readPool)
 		return nil, fmt.Errorf("failed to initialize auth service: %w", err)
 	}
 
 	var stripePlatform *stripe.PlatformClient
 	if cfg.StripeConnectClientID != "" && cfg.BaseURL != "" && cfg.StripePlatformSecretKey != "" {
-		stripePlatform = stripe.NewPlatformClient(cfg.StripePlatformSecretKey, cfg.StripeConnectClientID, cfg.BaseURL)
+		stripePlatform = stripe.NewPlatformClient(cfg.StripePlatformSecretKey, cfg.StripeTestSecretKey, cfg.StripeConnectClientID, cfg.BaseURL, logger.With("component", "stripe_client"))
 	}
 
 	parser := catalog.NewParser()
 	validator := catalog.NewValidator()
 	pricer := catalog.NewPricer()
-	orderEmailer := services.NewShopOrderEmailSender(email.NewProviderFromShop)
+	emailTemplates := services.NewEmailTemplateLoader(githubClient, cacheProvider, logger.With("component", "email_templates"))
+	orderEmailer := services.NewShopOrderEmailSender(email.NewProviderFromShop, emailTemplates, cfg.BaseURL).WithSuppressions(orderStore).WithRateSource(rateSource)
+	webhookService := services.NewWebhookService(webhookStore, cfg.Region, logger.With("component", "webhook_service"))
+	payments := services.NewPaymentProviders(shopStore, orderStore, stripePlatform).WithRateSource(rateSource).WithFeatureFlags(featureFlags)
+	githubOutboxService := services.NewGitHubOutboxService(db.NewGitHubOutboxStore(database), githubClient, cfg.Region, logger.With("component", "github_outbox_service"))
 
 	orderService := services.NewOrderService(
 		shopStore,
 		orderStore,
 		githubClient,
 		stripePlatform,
+		payments,
 		parser,
 		validator,
 		pricer,
 		orderEmailer,
+		webhookService,
+		cacheProvider,
+		featureFlags,
 		logger.With("component", "order_service"),
 	)
 	installationService := services.NewInstallationService(shopStore, githubClient, logger.With("component", "installation_service"))
-	repoService := services.NewRepositoryService(shopStore, logger.With("component", "repo_service"))
-	githubRouter := handlers.NewGitHubEventRouter(orderService, installationService, repoService, logger.With("component", "github_router"))
-	stripeService := services.NewStripeService(shopStore, orderStore, githubClient, parser, orderEmailer, logger.With("component", "stripe_service"))
-	stripeRouter := handlers.NewStripeEventRouter(stripeService, logger.With("component", "stripe_router"))
-	stripeConnectService := services.NewStripeConnectService(shopStore, stripePlatform, cacheProvider, logger.With("component", "stripe_connect_service"))
 	adminService := services.NewAdminService(
 		shopStore,
 		orderStore,
 		githubClient,
 		stripePlatform,
+		payments,
 		parser,
 		validator,
 		orderEmailer,
+		webhookService,
 		catalog.NewTemplateSyncer,
 		email.NewProvider,
 		logger.With("component", "admin_service"),
 	)
+	repoStatusService := services.NewRepoStatusService(adminService, cacheProvider, logger.With("component", "repo_status_service"))
+	repoService := services.NewRepositoryService(shopStore, githubClient, repoStatusService, logger.With("component", "repo_service"))
+	githubRouter := handlers.NewGitHubEventRouter(orderService, installationService, repoService, logger.With("component", "github_router"))
+	firstOrderConcierge := services.NewFirstOrderConcierge(shopStore, orderEmailer, cfg.OperatorWebhookURL, logger.With("component", "first_order_concierge"))
+	stripeService := services.NewStripeService(shopStore, orderStore, stripePlatform, githubClient, parser, orderEmailer, webhookService, firstOrderConcierge, githubOutboxService, logger.With("component", "stripe_service")).WithAddressValidator(addressValidator)
+	paymentEventService := services.NewPaymentEventService(shopStore, orderStore, payments, stripeService, logger.With("component", "payment_event_service"))
+	emailDeliveryService := services.NewEmailDeliveryService(shopStore, orderStore, logger.With("component", "email_delivery_service"))
+	stripeAccountMonitor := services.NewStripeAccountMonitor(shopStore, githubClient, orderEmailer, logger.With("component", "stripe_account_monitor"))
+	stripeRouter := handlers.NewStripeEventRouter(stripeService, orderService, stripeAccountMonitor, logger.With("component", "stripe_router"))
+	stripeConnectService := services.NewStripeConnectService(shopStore, stripePlatform, cacheProvider, logger.With("component", "stripe_connect_service"))
+	checkoutReminderService := services.NewCheckoutReminderService(orderStore, shopStore, githubClient, orderEmailer, cfg.Region, logger.With("component", "checkout_reminder_service"))
+	shippingReminderService := services.NewShippingReminderService(shopStore, orderStore, githubClient, orderEmailer, cfg.BaseURL, cfg.Region, logger.With("component", "shipping_reminder_service"))
+	reviewService := services.NewReviewService(orderStore, shopStore, githubClient, cfg.Region, logger.With("component", "review_service"))
+	keyRotationService := services.NewKeyRotationService(keyRotationStore, orderStore, encryptor.CurrentKeyID(), logger.With("component", "key_rotation"))
+	repoReconciliationService := services.NewRepoReconciliationService(shopStore, githubClient, cfg.Region, logger.With("component", "repo_reconciliation_service"))
+	dataRetentionService := services.NewDataRetentionService(orderStore, shopStore, time.Duration(cfg.DataRetentionDays)*24*time.Hour, cfg.Region, logger.With("component", "data_retention_service"))
+	warehouseExportService := services.NewWarehouseExportService(orderStore, warehouseStorage, cfg.Region, logger.With("component", "warehouse_export_service"))
 
 	h, err := handlers.New(handlers.Dependencies{
-		Config:               cfg,
-		DB:                   database,
-		ShopStore:            shopStore,
-		OrderStore:           orderStore,
-		CacheProvider:        cacheProvider,
-		GitHubAuth:           githubAuth,
-		GitHubClient:         githubClient,
-		GitHubRouter:         githubRouter,
-		StripeRouter:         stripeRouter,
-		AuthService:          authService,
-		StripeConnectService: stripeConnectService,
-		SessionManager:       sessionManager,
-		AdminService:         adminService,
-		Logger:               logger,
+		Config:                  cfg,
+		DB:                      database,
+		ShopStore:               shopStore,
+		OrderStore:              orderStore,
+		CacheProvider:           cacheProvider,
+		GitHubAuth:              githubAuth,
+		GitHubClient:            githubClient,
+		GitHubRouter:            githubRouter,
+		StripeRouter:            stripeRouter,
+		AuthService:             authService,
+		StripeConnectService:    stripeConnectService,
+		PaymentEventService:     paymentEventService,
+		EmailDeliveryService:    emailDeliveryService,
+		SessionManager:          sessionManager,
+		AdminService:            adminService,
+		RepoStatusService:       repoStatusService,
+		BadgeService:            services.NewBadgeService(shopStore, repoStatusService),
+		WebhookService:          webhookService,
+		ProcessingRegionService: services.NewProcessingRegionService(shopStore, cfg.Regions),
+		DataRetentionService:    dataRetentionService,
+		ShopAccessService:       services.NewShopAccessService(githubClient, parser, validator, cacheProvider, logger.With("component", "shop_access_service")),
+		OperatorService:         services.NewOperatorService(shopStore, orderStore, db.NewOperatorAuditStore(database), featureFlags, logger.With("component", "operator_service")),
+		FileStorage:             fileStorage,
+		IdempotencyStore:        db.NewIdempotencyStore(database),
+		WebhookQueueStore:       db.NewWebhookQueueStore(database),
+		WebhookEventStore:       db.NewWebhookEventStore(database),
+		FeatureFlags:            featureFlags,
+		Logger:                  logger,
 	})
 	if err != nil {
 		closeSessionManager(logger, sessionManager)
 		closeCacheProvider(logger, cacheProvider)
-		database.Close()
+		closeDatabase(database, readPool)
 		return nil, fmt.Errorf("failed to initialize handlers: %w", err)
 	}
 
-	return &App{
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	application := &App{
 		Config:         cfg,
 		Logger:         logger,
 		DB:             database,
+		ReadPool:       readPool,
 		CacheProvider:  cacheProvider,
 		SessionManager: sessionManager,
 		Handlers:       h,
 		sentryEnabled:  sentryEnabled,
-	}, nil
+		stopWorkers:    stopWorkers,
+	}
+	application.workers.Go(func() {
+		readPool.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		webhookService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		h.RunWebhookQueue(workerCtx)
+	})
+	application.workers.Go(func() {
+		h.RunWebhookEventRetention(workerCtx)
+	})
+	application.workers.Go(func() {
+		h.RunIdempotencyKeyRetention(workerCtx)
+	})
+	application.workers.Go(func() {
+		dataRetentionService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		warehouseExportService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		checkoutReminderService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		shippingReminderService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		reviewService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		keyRotationService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		githubOutboxService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		repoStatusService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		repoReconciliationService.Run(workerCtx)
+	})
+	application.workers.Go(func() {
+		storage.RunCleanup(workerCtx, fileStorage, storage.DefaultRules, logger.With("component", "file_storage"))
+	})
+
+	return application, nil
 }
 
 func (a *App) Close() {
 	if a == nil {
 		return
 	}
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+		a.workers.Wait()
+	}
 	if a.SessionManager != nil {
 		closeSessionManager(a.Logger, a.SessionManager)
 	}
 	if a.CacheProvider != nil {
 		closeCacheProvider(a.Logger, a.CacheProvider)
 	}
+	a.ReadPool.Close()
 	if a.DB != nil {
 		a.DB.Close()
 	}
@@ -208,7 +411,7 @@ func newLogger(cfg *config.Config) (*slog.Logger, bool, error) {
 	consoleHandler := newConsoleHandler(cfg)
 	sentryDSN := strings.TrimSpace(cfg.SentryDSN)
 	if sentryDSN == "" {
-		return slog.New(consoleHandler), false, nil
+		return slog.New(logging.SamplingHandler(consoleHandler, cfg.LogDebugSampleEvery)), false, nil
 	}
 
 	if err := sentry.Init(sentry.ClientOptions{
@@ -224,7 +427,7 @@ func newLogger(cfg *config.Config) (*slog.Logger, bool, error) {
 
 	sentryHandler := sentryslog.Option{}.NewSentryHandler(context.Background())
 
-	return slog.New(logging.MultiHandler(consoleHandler, sentryHandler)), true, nil
+	return slog.New(logging.SamplingHandler(logging.MultiHandler(consoleHandler, sentryHandler), cfg.LogDebugSampleEvery)), true, nil
 }
 
 func sentryRelease(cfg *config.Config) string {
@@ -263,3 +466,8 @@ func closeCacheProvider(logger *slog.Logger, provider cache.Provider) {
 		logger.Warn("failed to close cache provider", "error", err)
 	}
 }
+
+func closeDatabase(database *pgxpool.Pool, readPool *db.ReadPool) {
+	readPool.Close()
+	database.Close()
+}
@@ -0,0 +1,77 @@
+package main
+
+// encrypt-customer-data encrypts customer details on orders, and the recipients in their email
+// log, written before they were encrypted at rest. Run it once after deploying the
+// encrypt_customer_data and encrypt_email_log_recipient migrations, with the same environment as
+// the server, and again after changing EMAIL_INDEX_KEY to rebuild the email hashes orders and
+// customers are looked up by. It is safe to run again or to stop partway.
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/config"
+	"github.com/gitshopapp/gitshop/internal/crypto"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 500, "orders to read per batch")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, logger, *batchSize); err != nil {
+		logger.Error("failed to encrypt customer data", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, logger *slog.Logger, batchSize int) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	database, err := db.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	encryptor, err := crypto.NewKeyring(cfg.EncryptionKey, cfg.EncryptionKeys)
+	if err != nil {
+		return err
+	}
+	emailIndex, err := db.NewCustomerEmailIndex(cfg.EmailIndexKey)
+	if err != nil {
+		return err
+	}
+	orderStore, err := db.NewOrderStore(database, encryptor, emailIndex)
+	if err != nil {
+		return err
+	}
+
+	after, total := uuid.Nil, 0
+	for {
+		next, updated, err := orderStore.EncryptCustomerData(ctx, after, batchSize)
+		total += updated
+		if err != nil {
+			logger.Info("stopped", "encrypted", total)
+			return err
+		}
+		if next == uuid.Nil {
+			logger.Info("done", "encrypted", total)
+			return nil
+		}
+		logger.Info("batch encrypted", "encrypted", total, "last_order_id", next)
+		after = next
+	}
+}
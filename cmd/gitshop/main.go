@@ -0,0 +1,39 @@
+package main
+
+// gitshop is the command-line tool shop owners run against their own repository.
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage: gitshop <command> [flags]
+
+Commands:
+  validate    Check gitshop.yaml and order templates the same way GitShop does
+
+Run "gitshop validate -h" for command flags.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
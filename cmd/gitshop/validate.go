@@ -0,0 +1,243 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+)
+
+const (
+	exitOK       = 0
+	exitProblems = 1
+	exitUsage    = 2
+
+	issueTemplateDir     = ".github/ISSUE_TEMPLATE"
+	defaultOrderTemplate = "order.yaml"
+)
+
+type orderTemplate struct {
+	path    string
+	content string
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: gitshop validate [flags] [repository root]")
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "path to gitshop.yaml (default: gitshop.yaml or gitshop.yml in the repository root)")
+	fixTemplate := flags.Bool("fix-template", false, "rewrite order templates to match gitshop.yaml and create templates for products none of them list")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	root := "."
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	if flags.NArg() == 1 {
+		root = flags.Arg(0)
+	}
+
+	config, ok := checkConfig(root, *configPath, stdout)
+	if !ok {
+		return exitProblems
+	}
+
+	templates, err := readOrderTemplates(root)
+	if err != nil {
+		fmt.Fprintf(stdout, "✗ %s: %v\n", issueTemplateDir, err)
+		return exitProblems
+	}
+	if *fixTemplate {
+		templates, err = fixOrderTemplates(root, templates, config, stdout)
+		if err != nil {
+			fmt.Fprintf(stdout, "✗ %v\n", err)
+			return exitProblems
+		}
+	}
+
+	if !checkOrderTemplates(templates, config, stdout) {
+		return exitProblems
+	}
+	return exitOK
+}
+
+// checkConfig parses and validates gitshop.yaml with the server's parser and validator.
+func checkConfig(root, configPath string, out io.Writer) (*catalog.GitShopConfig, bool) {
+	if configPath == "" {
+		configPath = filepath.Join(root, "gitshop.yaml")
+		if _, err := os.Stat(configPath); errors.Is(err, os.ErrNotExist) {
+			configPath = filepath.Join(root, "gitshop.yml")
+		}
+	}
+	name := filepath.Base(configPath)
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		fmt.Fprintf(out, "✗ %s: %v\n", name, err)
+		return nil, false
+	}
+	config, err := catalog.NewParser().Parse(content)
+	if err != nil {
+		fmt.Fprintf(out, "✗ %s: %v\n", name, err)
+		return nil, false
+	}
+	if config.NeedsUpgrade() {
+		for _, note := range catalog.UpgradeNotes(config.SourceVersion) {
+			fmt.Fprintf(out, "! %s: %s\n", name, note)
+		}
+	}
+	if err := catalog.NewValidator().Validate(config); err != nil {
+		fmt.Fprintf(out, "✗ %s: %v\n", name, err)
+		return nil, false
+	}
+
+	fmt.Fprintf(out, "✓ %s is valid (%d products)\n", name, len(config.Products))
+	return config, true
+}
+
+// readOrderTemplates returns the issue templates that carry the GitShop order marker.
+func readOrderTemplates(root string) ([]orderTemplate, error) {
+	entries, err := os.ReadDir(filepath.Join(root, issueTemplateDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	templates := []orderTemplate{}
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+		if entry.IsDir() || (!strings.HasSuffix(name, ".yml") && !strings.HasSuffix(name, ".yaml")) {
+			continue
+		}
+		path := filepath.Join(issueTemplateDir, entry.Name())
+		content, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			return nil, err
+		}
+		if catalog.HasOrderTemplateMarker(string(content)) {
+			templates = append(templates, orderTemplate{path: path, content: string(content)})
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].path < templates[j].path })
+	return templates, nil
+}
+
+func checkOrderTemplates(templates []orderTemplate, config *catalog.GitShopConfig, out io.Writer) bool {
+	if len(templates) == 0 {
+		fmt.Fprintf(out, "✗ no order template found in %s; order templates start with \"# gitshop:order-template\" (run with --fix-template to create %s)\n", issueTemplateDir, defaultOrderTemplate)
+		return false
+	}
+
+	ok := true
+	for _, template := range templates {
+		report := catalog.CheckOrderTemplate(template.content, config)
+		if report.Valid() {
+			fmt.Fprintf(out, "✓ %s matches gitshop.yaml\n", template.path)
+			for _, warning := range report.Warnings {
+				fmt.Fprintf(out, "    ! %s\n", warning)
+			}
+			continue
+		}
+		ok = false
+		fmt.Fprintf(out, "✗ %s:\n", template.path)
+		for _, problem := range report.Problems() {
+			fmt.Fprintf(out, "    - %s\n", problem)
+		}
+	}
+	if !ok {
+		fmt.Fprintln(out, "Run with --fix-template to regenerate order templates from gitshop.yaml.")
+	}
+	return ok
+}
+
+// fixOrderTemplates rewrites templates the same way the dashboard's sync does, then adds templates
+// for active products no template lists. Templates whose SKUs changed cannot be synced safely and
+// are reported instead.
+func fixOrderTemplates(root string, templates []orderTemplate, config *catalog.GitShopConfig, out io.Writer) ([]orderTemplate, error) {
+	syncer := catalog.NewTemplateSyncer(nil)
+	fixed := make([]orderTemplate, 0, len(templates))
+	covered := []string{}
+	for _, template := range templates {
+		for sku := range catalog.FindTemplateSKUs(template.content) {
+			covered = append(covered, sku)
+		}
+		if catalog.CheckOrderTemplate(template.content, config).Valid() {
+			fixed = append(fixed, template)
+			continue
+		}
+		simple, reason, err := syncer.IsSimpleSync(template.content, config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", template.path, err)
+		}
+		if !simple {
+			fmt.Fprintf(out, "! %s: %s\n", template.path, reason)
+			fixed = append(fixed, template)
+			continue
+		}
+		content, err := syncer.SyncTemplateContent(template.content, config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", template.path, err)
+		}
+		if err := os.WriteFile(filepath.Join(root, template.path), []byte(content), 0o644); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(out, "• updated %s\n", template.path)
+		fixed = append(fixed, orderTemplate{path: template.path, content: content})
+	}
+
+	taken, err := issueTemplatePaths(root)
+	if err != nil {
+		return nil, err
+	}
+	files, err := syncer.BuildTemplateFiles(config, covered, taken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build order templates: %w", err)
+	}
+	if len(files) > 0 {
+		if err := os.MkdirAll(filepath.Join(root, issueTemplateDir), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	for _, file := range files {
+		path := filepath.FromSlash(file.Path)
+		if err := os.WriteFile(filepath.Join(root, path), []byte(file.Content), 0o644); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(out, "• created %s\n", path)
+		fixed = append(fixed, orderTemplate{path: path, content: file.Content})
+	}
+	return fixed, nil
+}
+
+// issueTemplatePaths lists every file in the issue template directory, marker or not, so new
+// templates never overwrite one.
+func issueTemplatePaths(root string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, issueTemplateDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, filepath.ToSlash(filepath.Join(issueTemplateDir, entry.Name())))
+	}
+	return paths, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `version: 1
+shop:
+  name: Test Shop
+  currency: usd
+  shipping:
+    flat_rate_cents: 500
+    carrier: USPS
+products:
+  - sku: TSHIRT
+    name: T-Shirt
+    unit_price_cents: 2500
+    active: true
+`
+
+const testTemplate = `# gitshop:order-template
+name: Order
+description: Place an order
+labels: ["gitshop:order"]
+body:
+  - type: dropdown
+    id: product
+    attributes:
+      label: Product
+      options:
+        - "T-Shirt - $25.00 (SKU:TSHIRT)"
+    validations:
+      required: true
+  - type: input
+    id: quantity
+    attributes:
+      label: Quantity
+`
+
+func writeRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func TestRunValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantCode   int
+		wantOutput string
+	}{
+		{
+			name:       "missing config",
+			files:      map[string]string{},
+			wantCode:   exitProblems,
+			wantOutput: "✗ gitshop.yml",
+		},
+		{
+			name:       "invalid config",
+			files:      map[string]string{"gitshop.yaml": strings.Replace(testConfig, "usd", "eur", 1)},
+			wantCode:   exitProblems,
+			wantOutput: "only USD currency is supported",
+		},
+		{
+			name:       "no order template",
+			files:      map[string]string{"gitshop.yaml": testConfig},
+			wantCode:   exitProblems,
+			wantOutput: "no order template found",
+		},
+		{
+			name: "unknown sku",
+			files: map[string]string{
+				"gitshop.yaml":                     testConfig,
+				".github/ISSUE_TEMPLATE/order.yml": strings.Replace(testTemplate, "SKU:TSHIRT", "SKU:HOODIE", 1),
+			},
+			wantCode:   exitProblems,
+			wantOutput: "SKU HOODIE is not in gitshop.yaml",
+		},
+		{
+			name: "valid",
+			files: map[string]string{
+				"gitshop.yaml":                     testConfig,
+				".github/ISSUE_TEMPLATE/order.yml": testTemplate,
+			},
+			wantCode:   exitOK,
+			wantOutput: "✓ .github/ISSUE_TEMPLATE/order.yml matches gitshop.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := writeRepo(t, tt.files)
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"validate", root}, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("expected exit %d, got %d\n%s%s", tt.wantCode, code, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantOutput) {
+				t.Fatalf("expected output to contain %q, got:\n%s", tt.wantOutput, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunValidate_FixTemplateCreatesOrderTemplate(t *testing.T) {
+	t.Parallel()
+
+	root := writeRepo(t, map[string]string{"gitshop.yaml": testConfig})
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "--fix-template", root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit %d, got %d\n%s%s", exitOK, code, stdout.String(), stderr.String())
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, issueTemplateDir, defaultOrderTemplate))
+	if err != nil {
+		t.Fatalf("expected order template to be created: %v", err)
+	}
+	if !strings.Contains(string(content), "SKU:TSHIRT") {
+		t.Fatalf("expected generated template to list TSHIRT, got:\n%s", content)
+	}
+}
+
+func TestRunValidate_FixTemplateAddsTemplateForUncoveredProducts(t *testing.T) {
+	t.Parallel()
+
+	config := testConfig + `  - sku: MUG
+    name: Mug
+    unit_price_cents: 1500
+    active: true
+    options:
+      - name: color
+        label: Color
+        type: dropdown
+        required: true
+        values: ["White", "Black"]
+`
+	root := writeRepo(t, map[string]string{
+		"gitshop.yaml":                    config,
+		issueTemplateDir + "/tshirt.yaml": testTemplate,
+	})
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "--fix-template", root}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("expected exit %d, got %d\n%s%s", exitOK, code, stdout.String(), stderr.String())
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, issueTemplateDir, defaultOrderTemplate))
+	if err != nil {
+		t.Fatalf("expected a template for the mug: %v\n%s", err, stdout.String())
+	}
+	if !strings.Contains(string(content), "SKU:MUG") || strings.Contains(string(content), "SKU:TSHIRT") {
+		t.Fatalf("expected new template to list only MUG, got:\n%s", content)
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"deploy"}, &stdout, &stderr); code != exitUsage {
+		t.Fatalf("expected exit %d, got %d", exitUsage, code)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+// rotate-keys re-encrypts stored secrets and customer details with the newest key in
+// ENCRYPTION_KEYS. Run it with the same environment as the server after adding a key and
+// restarting the server; an older key can be removed once it reports no stale rows. It is safe
+// to run again or to stop partway.
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gitshopapp/gitshop/internal/config"
+	"github.com/gitshopapp/gitshop/internal/crypto"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/services"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 500, "rows to read per batch")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, logger, *batchSize); err != nil {
+		logger.Error("failed to rotate encryption keys", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, logger *slog.Logger, batchSize int) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	encryptor, err := crypto.NewKeyring(cfg.EncryptionKey, cfg.EncryptionKeys)
+	if err != nil {
+		return err
+	}
+	if encryptor.CurrentKeyID() == "" {
+		return errors.New("ENCRYPTION_KEYS is not set, so there is no newer key to rotate to")
+	}
+
+	database, err := db.Connect(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	emailIndex, err := db.NewCustomerEmailIndex(cfg.EmailIndexKey)
+	if err != nil {
+		return err
+	}
+	orderStore, err := db.NewOrderStore(database, encryptor, emailIndex)
+	if err != nil {
+		return err
+	}
+	keyRotationStore, err := db.NewKeyRotationStore(database, encryptor)
+	if err != nil {
+		return err
+	}
+	service := services.NewKeyRotationService(keyRotationStore, orderStore, encryptor.CurrentKeyID(), logger)
+
+	if _, err := service.Rotate(ctx, batchSize); err != nil {
+		return err
+	}
+	stale, err := service.ReportStale(ctx)
+	if err != nil {
+		return err
+	}
+	for kind, count := range stale {
+		if count > 0 {
+			logger.Warn("rows still on an old key", "kind", kind, "rows", count)
+		}
+	}
+	logger.Info("done", "key_id", encryptor.CurrentKeyID())
+	return nil
+}
@@ -0,0 +1,114 @@
+package address
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const defaultEasyPostBaseURL = "https://api.easypost.com/v2"
+
+// EasyPostValidator checks addresses with EasyPost's delivery verification.
+type EasyPostValidator struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewEasyPostValidator(apiKey string) *EasyPostValidator {
+	return NewEasyPostValidatorWithBaseURL(apiKey, defaultEasyPostBaseURL)
+}
+
+func NewEasyPostValidatorWithBaseURL(apiKey, baseURL string) *EasyPostValidator {
+	return &EasyPostValidator{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: observability.NewHTTPClient(10 * time.Second),
+	}
+}
+
+type easyPostFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Validate creates an EasyPost address with delivery verification. EasyPost answers a failed
+// verification with a normal response whose delivery check lists what is wrong.
+func (v *EasyPostValidator) Validate(ctx context.Context, address Address) (Result, error) {
+	payload, err := json.Marshal(map[string]any{
+		"verify": []string{"delivery"},
+		"address": map[string]string{
+			"street1": address.Line1,
+			"street2": address.Line2,
+			"city":    address.City,
+			"state":   address.State,
+			"zip":     address.PostalCode,
+			"country": address.Country,
+		},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.baseURL+"/addresses", bytes.NewReader(payload))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(v.apiKey, "")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if readErr != nil {
+		return Result{}, fmt.Errorf("failed to read response: %w", readErr)
+	}
+	if closeErr != nil {
+		return Result{}, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Result{}, fmt.Errorf("easypost API returned status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Verifications struct {
+			Delivery *struct {
+				Success bool                 `json:"success"`
+				Errors  []easyPostFieldError `json:"errors"`
+			} `json:"delivery"`
+		} `json:"verifications"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return Result{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	delivery := response.Verifications.Delivery
+	if delivery == nil {
+		return Result{}, fmt.Errorf("easypost response has no delivery verification")
+	}
+	if delivery.Success {
+		return Result{Deliverable: true}, nil
+	}
+	return Result{Reason: easyPostReason(delivery.Errors)}, nil
+}
+
+func easyPostReason(errors []easyPostFieldError) string {
+	messages := make([]string, 0, len(errors))
+	for _, fieldErr := range errors {
+		if message := strings.TrimSpace(fieldErr.Message); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	if len(messages) == 0 {
+		return "Address could not be verified"
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,119 @@
+package address
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const defaultGoogleBaseURL = "https://addressvalidation.googleapis.com/v1"
+
+// GoogleValidator checks addresses with the Google Maps Address Validation API.
+type GoogleValidator struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewGoogleValidator(apiKey string) *GoogleValidator {
+	return NewGoogleValidatorWithBaseURL(apiKey, defaultGoogleBaseURL)
+}
+
+func NewGoogleValidatorWithBaseURL(apiKey, baseURL string) *GoogleValidator {
+	return &GoogleValidator{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: observability.NewHTTPClient(10 * time.Second),
+	}
+}
+
+// googleDeliverableGranularity are the validation granularities precise enough to deliver to: a
+// building, or a unit inside one.
+var googleDeliverableGranularity = map[string]bool{
+	"PREMISE":     true,
+	"SUB_PREMISE": true,
+}
+
+// Validate asks Google whether the address is complete and resolves to a building. For US
+// addresses, a USPS delivery point check that fails also marks the address undeliverable.
+func (v *GoogleValidator) Validate(ctx context.Context, address Address) (Result, error) {
+	lines := []string{address.Line1}
+	if address.Line2 != "" {
+		lines = append(lines, address.Line2)
+	}
+	payload, err := json.Marshal(map[string]any{
+		"address": map[string]any{
+			"regionCode":         address.Country,
+			"addressLines":       lines,
+			"locality":           address.City,
+			"administrativeArea": address.State,
+			"postalCode":         address.PostalCode,
+		},
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+	endpoint := v.baseURL + ":validateAddress?key=" + url.QueryEscape(v.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if readErr != nil {
+		return Result{}, fmt.Errorf("failed to read response: %w", readErr)
+	}
+	if closeErr != nil {
+		return Result{}, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Result{}, fmt.Errorf("google address validation API returned status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result struct {
+			Verdict struct {
+				ValidationGranularity string `json:"validationGranularity"`
+				AddressComplete       bool   `json:"addressComplete"`
+			} `json:"verdict"`
+			Address struct {
+				MissingComponentTypes []string `json:"missingComponentTypes"`
+			} `json:"address"`
+			USPSData *struct {
+				DPVConfirmation string `json:"dpvConfirmation"`
+			} `json:"uspsData"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return Result{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	result := response.Result
+	switch {
+	case !result.Verdict.AddressComplete:
+		reason := "Address is incomplete"
+		if missing := result.Address.MissingComponentTypes; len(missing) > 0 {
+			reason += " (missing " + strings.Join(missing, ", ") + ")"
+		}
+		return Result{Reason: reason}, nil
+	case result.USPSData != nil && result.USPSData.DPVConfirmation == "N":
+		return Result{Reason: "USPS does not deliver to this address"}, nil
+	case !googleDeliverableGranularity[result.Verdict.ValidationGranularity]:
+		return Result{Reason: "Address could not be matched to a building"}, nil
+	}
+	return Result{Deliverable: true}, nil
+}
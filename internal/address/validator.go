@@ -0,0 +1,85 @@
+// Package address checks that a buyer's shipping address can be delivered to before the seller
+// ships an order.
+package address
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Address is a shipping address in the shape Stripe Checkout collects it.
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+// FromMap reads an address stored on an order, which uses Stripe's field names.
+func FromMap(m map[string]any) Address {
+	field := func(key string) string {
+		value, _ := m[key].(string)
+		return strings.TrimSpace(value)
+	}
+	return Address{
+		Line1:      field("line1"),
+		Line2:      field("line2"),
+		City:       field("city"),
+		State:      field("state"),
+		PostalCode: field("postal_code"),
+		Country:    strings.ToUpper(field("country")),
+	}
+}
+
+// Map returns the address in the shape orders store it.
+func (a Address) Map() map[string]any {
+	return map[string]any{
+		"line1":       a.Line1,
+		"line2":       a.Line2,
+		"city":        a.City,
+		"state":       a.State,
+		"postal_code": a.PostalCode,
+		"country":     a.Country,
+	}
+}
+
+// IsEmpty reports whether the buyer gave no address, as for digital products.
+func (a Address) IsEmpty() bool {
+	return a.Line1 == "" && a.City == "" && a.PostalCode == ""
+}
+
+// Result is a validator's verdict on an address. Reason explains an undeliverable address to the
+// seller and is empty for a deliverable one.
+type Result struct {
+	Deliverable bool
+	Reason      string
+}
+
+// Validator checks whether an address can be delivered to. An error means the check itself
+// failed, not that the address is bad.
+type Validator interface {
+	Validate(ctx context.Context, address Address) (Result, error)
+}
+
+type Config struct {
+	Provider       string
+	EasyPostAPIKey string
+	GoogleAPIKey   string
+}
+
+// NewValidator returns the configured validator, or nil when address validation is off.
+func NewValidator(cfg Config) (Validator, error) {
+	switch cfg.Provider {
+	case "none", "":
+		return nil, nil
+	case "easypost":
+		return NewEasyPostValidator(cfg.EasyPostAPIKey), nil
+	case "google":
+		return NewGoogleValidator(cfg.GoogleAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported address validation provider: %s", cfg.Provider)
+	}
+}
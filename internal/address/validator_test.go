@@ -0,0 +1,182 @@
+package address
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testAddress = Address{Line1: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"}
+
+func TestFromMapRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	got := FromMap(map[string]any{"line1": " 1 Main St ", "city": "Springfield", "state": "IL", "postal_code": "62701", "country": "us"})
+	if got != testAddress {
+		t.Fatalf("FromMap() = %+v, want %+v", got, testAddress)
+	}
+	if back := FromMap(got.Map()); back != got {
+		t.Fatalf("FromMap(Map()) = %+v, want %+v", back, got)
+	}
+	if !FromMap(nil).IsEmpty() {
+		t.Fatal("expected an empty address from a nil map")
+	}
+}
+
+func TestNewValidator(t *testing.T) {
+	t.Parallel()
+
+	for _, provider := range []string{"", "none"} {
+		validator, err := NewValidator(Config{Provider: provider})
+		if err != nil || validator != nil {
+			t.Fatalf("NewValidator(%q) = %v, %v; want nil, nil", provider, validator, err)
+		}
+	}
+	if _, err := NewValidator(Config{Provider: "easypost", EasyPostAPIKey: "key"}); err != nil {
+		t.Fatalf("NewValidator(easypost) error = %v", err)
+	}
+	if _, err := NewValidator(Config{Provider: "smartystreets"}); err == nil {
+		t.Fatal("expected an error for an unknown provider")
+	}
+}
+
+func TestEasyPostValidator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response string
+		want     Result
+	}{
+		{
+			name:     "deliverable",
+			response: `{"verifications":{"delivery":{"success":true,"errors":[]}}}`,
+			want:     Result{Deliverable: true},
+		},
+		{
+			name:     "undeliverable",
+			response: `{"verifications":{"delivery":{"success":false,"errors":[{"field":"street1","message":"House number is invalid"},{"field":"address","message":"Address not found"}]}}}`,
+			want:     Result{Reason: "House number is invalid; Address not found"},
+		},
+		{
+			name:     "undeliverable without details",
+			response: `{"verifications":{"delivery":{"success":false}}}`,
+			want:     Result{Reason: "Address could not be verified"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/addresses" {
+					t.Errorf("path = %q", r.URL.Path)
+				}
+				if user, _, _ := r.BasicAuth(); user != "ep_key" {
+					t.Errorf("basic auth user = %q", user)
+				}
+				var body struct {
+					Verify  []string          `json:"verify"`
+					Address map[string]string `json:"address"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				if strings.Join(body.Verify, ",") != "delivery" || body.Address["street1"] != "1 Main St" || body.Address["zip"] != "62701" {
+					t.Errorf("request body = %+v", body)
+				}
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			got, err := NewEasyPostValidatorWithBaseURL("ep_key", server.URL+"/v2").Validate(context.Background(), testAddress)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEasyPostValidatorAPIError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	if _, err := NewEasyPostValidatorWithBaseURL("bad", server.URL).Validate(context.Background(), testAddress); err == nil {
+		t.Fatal("expected an error when EasyPost rejects the request")
+	}
+}
+
+func TestGoogleValidator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response string
+		want     Result
+	}{
+		{
+			name:     "deliverable",
+			response: `{"result":{"verdict":{"validationGranularity":"PREMISE","addressComplete":true}}}`,
+			want:     Result{Deliverable: true},
+		},
+		{
+			name:     "incomplete",
+			response: `{"result":{"verdict":{"validationGranularity":"ROUTE"},"address":{"missingComponentTypes":["street_number"]}}}`,
+			want:     Result{Reason: "Address is incomplete (missing street_number)"},
+		},
+		{
+			name:     "usps rejects",
+			response: `{"result":{"verdict":{"validationGranularity":"PREMISE","addressComplete":true},"uspsData":{"dpvConfirmation":"N"}}}`,
+			want:     Result{Reason: "USPS does not deliver to this address"},
+		},
+		{
+			name:     "no building",
+			response: `{"result":{"verdict":{"validationGranularity":"ROUTE","addressComplete":true}}}`,
+			want:     Result{Reason: "Address could not be matched to a building"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1:validateAddress" || r.URL.Query().Get("key") != "g_key" {
+					t.Errorf("url = %q", r.URL.String())
+				}
+				var body struct {
+					Address struct {
+						RegionCode   string   `json:"regionCode"`
+						AddressLines []string `json:"addressLines"`
+					} `json:"address"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				if body.Address.RegionCode != "US" || len(body.Address.AddressLines) != 1 {
+					t.Errorf("request body = %+v", body)
+				}
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			got, err := NewGoogleValidatorWithBaseURL("g_key", server.URL+"/v1").Validate(context.Background(), testAddress)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
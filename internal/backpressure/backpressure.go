@@ -0,0 +1,152 @@
+// Package backpressure tracks how much inbound webhook work is waiting and decides when to defer
+// new events and shed non-critical side effects.
+package backpressure
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Limits struct {
+	// MaxInFlight is how many webhooks may be processed inline at once before new ones are deferred.
+	MaxInFlight int
+	// MaxDepth and MaxAge mark the queue as overloaded when either is reached.
+	MaxDepth int
+	MaxAge   time.Duration
+	// ShedCooldown keeps non-critical side effects disabled for a while after load drops, so the
+	// circuit does not flap while the backlog drains.
+	ShedCooldown time.Duration
+}
+
+// Snapshot is the load seen at one point in time. Depth counts webhooks being processed inline
+// plus deferred events still waiting in the queue.
+type Snapshot struct {
+	InFlight  int
+	Queued    int
+	Depth     int
+	OldestAge time.Duration
+}
+
+type Monitor struct {
+	limits Limits
+
+	mu           sync.Mutex
+	nextID       uint64
+	inflight     map[uint64]time.Time
+	queued       int
+	oldestQueued time.Time
+	shedUntil    time.Time
+}
+
+func NewMonitor(limits Limits) *Monitor {
+	return &Monitor{
+		limits:   limits,
+		inflight: make(map[uint64]time.Time),
+	}
+}
+
+// Begin records a webhook being processed inline. Call the returned func when it finishes.
+func (m *Monitor) Begin() func() {
+	if m == nil {
+		return func() {}
+	}
+
+	m.mu.Lock()
+	m.nextID++
+	id := m.nextID
+	m.inflight[id] = time.Now()
+	m.mu.Unlock()
+
+	return func() {
+		m.mu.Lock()
+		delete(m.inflight, id)
+		m.mu.Unlock()
+	}
+}
+
+// ShouldDefer reports whether a new webhook should be queued instead of processed inline.
+func (m *Monitor) ShouldDefer() bool {
+	if m == nil || m.limits.MaxInFlight <= 0 {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.inflight) >= m.limits.MaxInFlight
+}
+
+// SetQueued updates the deferred backlog, as last read from the queue table.
+func (m *Monitor) SetQueued(count int, oldest time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queued = count
+	m.oldestQueued = oldest
+}
+
+func (m *Monitor) Snapshot(now time.Time) Snapshot {
+	if m == nil {
+		return Snapshot{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshotLocked(now)
+}
+
+func (m *Monitor) snapshotLocked(now time.Time) Snapshot {
+	snapshot := Snapshot{
+		InFlight: len(m.inflight),
+		Queued:   m.queued,
+		Depth:    len(m.inflight) + m.queued,
+	}
+	oldest := m.oldestQueued
+	for _, started := range m.inflight {
+		if oldest.IsZero() || started.Before(oldest) {
+			oldest = started
+		}
+	}
+	if !oldest.IsZero() && now.After(oldest) {
+		snapshot.OldestAge = now.Sub(oldest)
+	}
+	return snapshot
+}
+
+// Overloaded reports whether non-critical side effects should be skipped. Once tripped it stays
+// open for ShedCooldown after the load falls back under the limits.
+func (m *Monitor) Overloaded(now time.Time) bool {
+	if m == nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := m.snapshotLocked(now)
+	over := (m.limits.MaxDepth > 0 && snapshot.Depth >= m.limits.MaxDepth) ||
+		(m.limits.MaxAge > 0 && snapshot.OldestAge >= m.limits.MaxAge)
+	if over {
+		m.shedUntil = now.Add(m.limits.ShedCooldown)
+		return true
+	}
+	return now.Before(m.shedUntil)
+}
+
+type shedContextKey struct{}
+
+// WithShedding marks ctx so services skip non-critical side effects.
+func WithShedding(ctx context.Context) context.Context {
+	return context.WithValue(ctx, shedContextKey{}, true)
+}
+
+// ShouldShed reports whether ctx was marked with WithShedding.
+func ShouldShed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	shed, _ := ctx.Value(shedContextKey{}).(bool)
+	return shed
+}
@@ -0,0 +1,85 @@
+package backpressure
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMonitor_ShouldDefer(t *testing.T) {
+	t.Parallel()
+
+	monitor := NewMonitor(Limits{MaxInFlight: 2})
+	first := monitor.Begin()
+	if monitor.ShouldDefer() {
+		t.Fatal("expected inline processing below the in-flight limit")
+	}
+	second := monitor.Begin()
+	if !monitor.ShouldDefer() {
+		t.Fatal("expected deferral at the in-flight limit")
+	}
+	second()
+	first()
+	if monitor.ShouldDefer() {
+		t.Fatal("expected inline processing after requests finish")
+	}
+}
+
+func TestMonitor_Overloaded(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		queued int
+		oldest time.Time
+		want   bool
+	}{
+		{name: "idle", want: false},
+		{name: "shallow and fresh", queued: 3, oldest: now.Add(-10 * time.Second), want: false},
+		{name: "deep backlog", queued: 10, oldest: now.Add(-10 * time.Second), want: true},
+		{name: "stale backlog", queued: 1, oldest: now.Add(-5 * time.Minute), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			monitor := NewMonitor(Limits{MaxDepth: 10, MaxAge: 2 * time.Minute, ShedCooldown: time.Minute})
+			monitor.SetQueued(tt.queued, tt.oldest)
+			if got := monitor.Overloaded(now); got != tt.want {
+				t.Fatalf("expected overloaded=%v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMonitor_OverloadedStaysOpenForCooldown(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	monitor := NewMonitor(Limits{MaxDepth: 5, ShedCooldown: time.Minute})
+	monitor.SetQueued(5, now)
+	if !monitor.Overloaded(now) {
+		t.Fatal("expected overload at max depth")
+	}
+
+	monitor.SetQueued(0, time.Time{})
+	if !monitor.Overloaded(now.Add(30 * time.Second)) {
+		t.Fatal("expected circuit to stay open during cooldown")
+	}
+	if monitor.Overloaded(now.Add(2 * time.Minute)) {
+		t.Fatal("expected circuit to close after cooldown")
+	}
+}
+
+func TestShouldShed(t *testing.T) {
+	t.Parallel()
+
+	if ShouldShed(context.Background()) {
+		t.Fatal("expected plain context not to shed")
+	}
+	if !ShouldShed(WithShedding(context.Background())) {
+		t.Fatal("expected marked context to shed")
+	}
+}
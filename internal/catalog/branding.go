@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// BrandingConfig is how the shop presents itself in buyer emails, on its public badge, and in
+// the footer of order comments. Every field is optional; an empty one keeps GitShop's default.
+type BrandingConfig struct {
+	LogoURL      string `yaml:"logo_url"`
+	AccentColor  string `yaml:"accent_color"`
+	SupportEmail string `yaml:"support_email"`
+	// ShopURL replaces the repository link buyers are sent back to.
+	ShopURL string `yaml:"shop_url"`
+}
+
+// IsZero reports whether the shop kept the default branding.
+func (b BrandingConfig) IsZero() bool {
+	return b == BrandingConfig{}
+}
+
+var accentColorRegex = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ValidateBranding checks that each value is safe to place in an HTML attribute as is, since
+// email templates insert them without escaping.
+func ValidateBranding(branding BrandingConfig) error {
+	if branding.LogoURL != "" {
+		if err := validateBrandingURL(branding.LogoURL); err != nil {
+			return &ValidationError{Path: "logo_url", Message: err.Error()}
+		}
+	}
+	if branding.AccentColor != "" && !accentColorRegex.MatchString(branding.AccentColor) {
+		return &ValidationError{Path: "accent_color", Message: "accent_color must be a hex color such as #2563eb"}
+	}
+	if branding.SupportEmail != "" {
+		address, err := mail.ParseAddress(branding.SupportEmail)
+		if err != nil || address.Address != branding.SupportEmail {
+			return &ValidationError{Path: "support_email", Message: "support_email must be a plain email address such as help@example.com"}
+		}
+	}
+	if branding.ShopURL != "" {
+		if err := validateBrandingURL(branding.ShopURL); err != nil {
+			return &ValidationError{Path: "shop_url", Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+func validateBrandingURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" || strings.ContainsAny(value, "\"'<> ") {
+		return fmt.Errorf("must be an https URL")
+	}
+	return nil
+}
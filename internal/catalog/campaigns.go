@@ -0,0 +1,181 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/money"
+)
+
+// CampaignConfig is a sale that discounts products between StartsAt and EndsAt. A campaign takes
+// either PercentOff or AmountOffCents off the unit price of each SKU it lists, or of every one-time
+// product when SKUs is empty. Option surcharges and shipping keep their full price.
+type CampaignConfig struct {
+	Name           string    `yaml:"name"`
+	StartsAt       time.Time `yaml:"starts_at"`
+	EndsAt         time.Time `yaml:"ends_at"`
+	PercentOff     int       `yaml:"percent_off"`
+	AmountOffCents int64     `yaml:"amount_off_cents"`
+	SKUs           []string  `yaml:"skus"`
+}
+
+// MaxCampaignNameLength keeps campaign names short enough for order comments and reports.
+const MaxCampaignNameLength = 50
+
+// Active reports whether the campaign's window includes now. EndsAt is exclusive.
+func (c CampaignConfig) Active(now time.Time) bool {
+	return !now.Before(c.StartsAt) && now.Before(c.EndsAt)
+}
+
+// Covers reports whether the campaign discounts product. Subscriptions and inquiries are never
+// discounted, since their price is not charged once at checkout.
+func (c CampaignConfig) Covers(product ProductConfig) bool {
+	if product.IsSubscription() || product.IsInquiry() {
+		return false
+	}
+	if len(c.SKUs) == 0 {
+		return true
+	}
+	for _, sku := range c.SKUs {
+		if strings.EqualFold(strings.TrimSpace(sku), product.SKU) {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns priceCents with the campaign's discount taken off, rounded to the nearest cent.
+func (c CampaignConfig) Discount(priceCents int64) int64 {
+	if c.PercentOff > 0 {
+		off := (priceCents*int64(c.PercentOff) + 50) / 100
+		return priceCents - off
+	}
+	return max(priceCents-c.AmountOffCents, 0)
+}
+
+// PriceCents is the unit price a buyer pays for the product before option surcharges: the sale
+// price while a campaign covers it, the list price otherwise.
+func (p ProductConfig) PriceCents() int64 {
+	if p.Campaign != nil {
+		return p.Campaign.Discount(p.UnitPriceCents)
+	}
+	return p.UnitPriceCents
+}
+
+// CampaignName is the name of the campaign discounting the product, empty at list price.
+func (p ProductConfig) CampaignName() string {
+	if p.Campaign == nil {
+		return ""
+	}
+	return strings.TrimSpace(p.Campaign.Name)
+}
+
+// ActiveCampaign returns the campaign discounting product at now, or nil when it sells at its
+// list price.
+func (c *GitShopConfig) ActiveCampaign(product ProductConfig, now time.Time) *CampaignConfig {
+	for i := range c.Campaigns {
+		campaign := &c.Campaigns[i]
+		if campaign.Active(now) && campaign.Covers(product) {
+			return campaign
+		}
+	}
+	return nil
+}
+
+// applyCampaigns records the campaign discounting each product at now.
+func (c *GitShopConfig) applyCampaigns(now time.Time) {
+	for i := range c.Products {
+		c.Products[i].Campaign = c.ActiveCampaign(c.Products[i], now)
+	}
+}
+
+// validateCampaigns checks each campaign against the catalog. Two campaigns may share a SKU only
+// when their windows do not overlap, so a product has at most one sale price at a time.
+func validateCampaigns(campaigns []CampaignConfig, products []ProductConfig, currency string) error {
+	names := make(map[string]int, len(campaigns))
+	for i, campaign := range campaigns {
+		path := fmt.Sprintf("[%d]", i)
+		name := strings.TrimSpace(campaign.Name)
+		if name == "" {
+			return &ValidationError{Path: path + ".name", Message: "campaign name is required"}
+		}
+		if len(name) > MaxCampaignNameLength {
+			return &ValidationError{Path: path + ".name", Message: fmt.Sprintf("campaign name must be at most %d characters", MaxCampaignNameLength)}
+		}
+		if first, ok := names[strings.ToLower(name)]; ok {
+			return &ValidationError{Path: path + ".name", Message: fmt.Sprintf("duplicate campaign name %s, also used by campaigns[%d]", name, first)}
+		}
+		names[strings.ToLower(name)] = i
+
+		if campaign.StartsAt.IsZero() || campaign.EndsAt.IsZero() {
+			return &ValidationError{Path: path, Message: "campaign starts_at and ends_at are required, as RFC 3339 times such as 2026-11-27T00:00:00Z"}
+		}
+		if !campaign.EndsAt.After(campaign.StartsAt) {
+			return &ValidationError{Path: path + ".ends_at", Message: "ends_at must be after starts_at"}
+		}
+
+		switch {
+		case campaign.PercentOff != 0 && campaign.AmountOffCents != 0:
+			return &ValidationError{Path: path, Message: "set either percent_off or amount_off_cents, not both"}
+		case campaign.PercentOff != 0:
+			if campaign.PercentOff < 1 || campaign.PercentOff > 99 {
+				return &ValidationError{Path: path + ".percent_off", Message: "percent_off must be between 1 and 99"}
+			}
+		case campaign.AmountOffCents != 0:
+			if campaign.AmountOffCents < 0 || campaign.AmountOffCents > money.MaxCents {
+				return &ValidationError{Path: path + ".amount_off_cents", Message: fmt.Sprintf("amount_off_cents must be between 1 and %d", money.MaxCents)}
+			}
+		default:
+			return &ValidationError{Path: path, Message: "campaign needs percent_off or amount_off_cents"}
+		}
+
+		for j, sku := range campaign.SKUs {
+			product := findCampaignProduct(products, sku)
+			if product == nil {
+				return &ValidationError{Path: fmt.Sprintf("%s.skus[%d]", path, j), Message: fmt.Sprintf("no product has SKU %s", sku)}
+			}
+			if !campaign.Covers(*product) {
+				return &ValidationError{Path: fmt.Sprintf("%s.skus[%d]", path, j), Message: fmt.Sprintf("%s is a subscription or inquiry and cannot be discounted", product.SKU)}
+			}
+		}
+
+		minimum := StripeMinimumCharge(currency)
+		for _, product := range products {
+			if !campaign.Covers(product) {
+				continue
+			}
+			if price := campaign.Discount(product.UnitPriceCents); price < minimum {
+				return &ValidationError{Path: path, Message: fmt.Sprintf("the sale price of %s, %s, is below Stripe's minimum charge of %s", product.SKU, money.Format(price), money.Format(minimum))}
+			}
+		}
+
+		for j := range i {
+			if other := campaigns[j]; campaignsOverlap(campaign, other, products) {
+				return &ValidationError{Path: path, Message: fmt.Sprintf("overlaps campaigns[%d] (%s) on a shared product", j, other.Name)}
+			}
+		}
+	}
+	return nil
+}
+
+func campaignsOverlap(a, b CampaignConfig, products []ProductConfig) bool {
+	if !a.StartsAt.Before(b.EndsAt) || !b.StartsAt.Before(a.EndsAt) {
+		return false
+	}
+	for _, product := range products {
+		if a.Covers(product) && b.Covers(product) {
+			return true
+		}
+	}
+	return false
+}
+
+func findCampaignProduct(products []ProductConfig, sku string) *ProductConfig {
+	for i := range products {
+		if strings.EqualFold(products[i].SKU, strings.TrimSpace(sku)) {
+			return &products[i]
+		}
+	}
+	return nil
+}
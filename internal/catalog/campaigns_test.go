@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	campaignStart = time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+	campaignEnd   = time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+)
+
+const campaignConfigYAML = `
+shop:
+  name: Test Shop
+  currency: usd
+products:
+  - sku: MUG_V1
+    name: Mug
+    unit_price_cents: 2500
+    active: true
+  - sku: TEE_V1
+    name: T-shirt
+    unit_price_cents: 3000
+    active: true
+  - sku: CLUB_V1
+    name: Coffee club
+    unit_price_cents: 1500
+    active: true
+    billing: monthly
+campaigns:
+  - name: Black Friday
+    starts_at: 2026-11-27T00:00:00Z
+    ends_at: 2026-12-01T00:00:00Z
+    percent_off: 20
+    skus: [MUG_V1]
+`
+
+func TestParser_ParseAppliesCampaigns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		now          time.Time
+		wantMug      int64
+		wantCampaign string
+	}{
+		{name: "before the campaign", now: campaignStart.Add(-time.Second), wantMug: 2500},
+		{name: "when the campaign starts", now: campaignStart, wantMug: 2000, wantCampaign: "Black Friday"},
+		{name: "when the campaign ends", now: campaignEnd, wantMug: 2500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := &Parser{now: func() time.Time { return tt.now }}
+			config, err := parser.ParseFromString(campaignConfigYAML)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			mug, tee := config.Products[0], config.Products[1]
+			if got := mug.PriceCents(); got != tt.wantMug {
+				t.Fatalf("mug PriceCents() = %d, want %d", got, tt.wantMug)
+			}
+			if got := mug.CampaignName(); got != tt.wantCampaign {
+				t.Fatalf("mug CampaignName() = %q, want %q", got, tt.wantCampaign)
+			}
+			if tee.Campaign != nil || tee.PriceCents() != 3000 {
+				t.Fatalf("tee is not in the campaign but got campaign %v at %d", tee.Campaign, tee.PriceCents())
+			}
+		})
+	}
+}
+
+func TestCampaignConfig(t *testing.T) {
+	t.Parallel()
+
+	mug := ProductConfig{SKU: "MUG_V1", UnitPriceCents: 2499}
+	club := ProductConfig{SKU: "CLUB_V1", UnitPriceCents: 1500, Billing: BillingMonthly}
+
+	tests := []struct {
+		name       string
+		campaign   CampaignConfig
+		product    ProductConfig
+		wantCovers bool
+		wantPrice  int64
+	}{
+		{name: "percent off rounds to the nearest cent", campaign: CampaignConfig{PercentOff: 15}, product: mug, wantCovers: true, wantPrice: 2124},
+		{name: "amount off", campaign: CampaignConfig{AmountOffCents: 500}, product: mug, wantCovers: true, wantPrice: 1999},
+		{name: "SKUs match regardless of case", campaign: CampaignConfig{PercentOff: 10, SKUs: []string{"mug_v1"}}, product: mug, wantCovers: true, wantPrice: 2249},
+		{name: "product not listed", campaign: CampaignConfig{PercentOff: 10, SKUs: []string{"TEE_V1"}}, product: mug},
+		{name: "subscriptions are never discounted", campaign: CampaignConfig{PercentOff: 10}, product: club},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.campaign.Covers(tt.product); got != tt.wantCovers {
+				t.Fatalf("Covers() = %v, want %v", got, tt.wantCovers)
+			}
+			if !tt.wantCovers {
+				return
+			}
+			if got := tt.campaign.Discount(tt.product.UnitPriceCents); got != tt.wantPrice {
+				t.Fatalf("Discount() = %d, want %d", got, tt.wantPrice)
+			}
+		})
+	}
+}
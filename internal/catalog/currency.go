@@ -0,0 +1,46 @@
+package catalog
+
+import "strings"
+
+// stripeCurrencies are the currencies Stripe Checkout can charge in.
+var stripeCurrencies = currencySet(`
+		usd aed afn all amd ang aoa ars aud awg azn bam bbd bdt bgn bif bmd bnd bob brl bsd bwp
+		byn bzd cad cdf chf clp cny cop crc cve czk djf dkk dop dzd egp etb eur fjd fkp gbp gel
+		gip gmd gnf gtq gyd hkd hnl htg huf idr ils inr isk jmd jpy kes kgs khr kmf krw kyd kzt
+		lak lbp lkr lrd lsl mad mdl mga mkd mmk mnt mop mur mvr mwk mxn myr mzn nad ngn nio nok
+		npr nzd pab pen pgk php pkr pln pyg qar ron rsd rub rwf sar sbd scr sek sgd shp sle sos
+		srd std szl thb tjs top try ttd twd tzs uah ugx uyu uzs vnd vuv wst xaf xcd xof xpf yer
+		zar zmw
+`)
+
+// stripeMinimumCharge is the smallest amount Stripe charges in a currency, in its minor unit.
+// Currencies missing here fall back to defaultStripeMinimumCharge.
+var stripeMinimumCharge = map[string]int64{
+	"usd": 50, "aud": 50, "brl": 50, "cad": 50, "chf": 50, "eur": 50, "inr": 50, "jpy": 50,
+	"nzd": 50, "sgd": 50, "aed": 200, "bgn": 100, "czk": 1500, "dkk": 250, "gbp": 30,
+	"hkd": 400, "huf": 17500, "mxn": 1000, "myr": 200, "nok": 300, "pln": 200, "ron": 200,
+	"sek": 300, "thb": 1000,
+}
+
+const defaultStripeMinimumCharge int64 = 50
+
+func currencySet(codes string) map[string]bool {
+	set := map[string]bool{}
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
+
+// IsStripeCurrency reports whether Stripe Checkout supports the lowercase ISO currency code.
+func IsStripeCurrency(currency string) bool {
+	return stripeCurrencies[currency]
+}
+
+// StripeMinimumCharge returns the smallest amount Stripe charges in the currency.
+func StripeMinimumCharge(currency string) int64 {
+	if minimum, ok := stripeMinimumCharge[currency]; ok {
+		return minimum
+	}
+	return defaultStripeMinimumCharge
+}
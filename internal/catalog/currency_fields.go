@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+// Field written into order templates when shop.checkout.estimate_currencies is set. A buyer who
+// picks a currency sees the order total estimated in it. The label stays in English because
+// order parsing matches on it.
+const (
+	CurrencyFieldID    = "estimate_currency"
+	CurrencyFieldLabel = "Show prices in"
+)
+
+// MaxEstimateCurrencies keeps the currency dropdown short.
+const MaxEstimateCurrencies = 10
+
+// EstimateCurrency returns the lowercase code of answer when it is one of the shop's estimate
+// currencies, such as "eur" for "EUR", and "" otherwise.
+func (c CheckoutConfig) EstimateCurrency(answer string) string {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, currency := range c.EstimateCurrencies {
+		if strings.ToLower(strings.TrimSpace(currency)) == answer {
+			return answer
+		}
+	}
+	return ""
+}
+
+func estimateCurrencyOptions(checkout CheckoutConfig) []string {
+	options := make([]string, 0, len(checkout.EstimateCurrencies))
+	for _, currency := range checkout.EstimateCurrencies {
+		options = append(options, strings.ToUpper(strings.TrimSpace(currency)))
+	}
+	return options
+}
+
+// currencyFields returns the optional estimate currency dropdown, or none when the shop does not
+// offer estimates.
+func currencyFields(loc i18n.Localizer, shop ShopConfig) []templateField {
+	if len(shop.Checkout.EstimateCurrencies) == 0 {
+		return nil
+	}
+	return []templateField{{
+		Type: "dropdown",
+		ID:   CurrencyFieldID,
+		Attributes: templateFieldAttributes{
+			Label:       CurrencyFieldLabel,
+			Description: loc.T("template.currency_description", strings.ToUpper(shop.Currency)),
+			Options:     estimateCurrencyOptions(shop.Checkout),
+		},
+	}}
+}
+
+// syncCurrencyField adds, refreshes, or removes the estimate currency dropdown so it follows
+// shop.checkout.estimate_currencies.
+func syncCurrencyField(loc i18n.Localizer, bodyNode *yaml.Node, shop ShopConfig) {
+	if len(shop.Checkout.EstimateCurrencies) == 0 {
+		removeFieldByID(bodyNode, CurrencyFieldID)
+		return
+	}
+	field := ensureFieldByID(bodyNode, CurrencyFieldID, "dropdown")
+	attrs := ensureMappingValue(field, "attributes")
+	setMappingScalar(attrs, "label", CurrencyFieldLabel)
+	setMappingScalar(attrs, "description", loc.T("template.currency_description", strings.ToUpper(shop.Currency)))
+	setFieldOptions(field, estimateCurrencyOptions(shop.Checkout))
+}
@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+// Fields written into order templates when shop.checkout.collect_email is set. The email
+// prefills Stripe Checkout and lets GitShop send one reminder before the checkout link expires;
+// ticking the opt-out checkbox skips that reminder. Labels stay in English because order
+// parsing matches on them.
+const (
+	EmailFieldID    = "email"
+	EmailFieldLabel = "Email"
+
+	ReminderOptOutFieldID    = "email_reminder_opt_out"
+	ReminderOptOutFieldLabel = "Checkout reminder"
+)
+
+// emailFields returns the email and reminder opt-out fields, or none when the shop does not
+// collect emails. Neither is required, so buyers can still order without sharing an address.
+func emailFields(loc i18n.Localizer, checkout CheckoutConfig) []templateField {
+	if !checkout.CollectEmail {
+		return nil
+	}
+	return []templateField{
+		{
+			Type: "input",
+			ID:   EmailFieldID,
+			Attributes: templateFieldAttributes{
+				Label:       EmailFieldLabel,
+				Description: loc.T("template.email_description"),
+			},
+		},
+		{
+			Type: "checkboxes",
+			ID:   ReminderOptOutFieldID,
+			Attributes: templateFieldAttributes{
+				Label:   ReminderOptOutFieldLabel,
+				Options: []templateCheckboxOption{{Label: loc.T("template.email_reminder_opt_out")}},
+			},
+		},
+	}
+}
+
+// syncEmailFields adds, refreshes, or removes the email fields so they follow
+// shop.checkout.collect_email.
+func syncEmailFields(loc i18n.Localizer, bodyNode *yaml.Node, checkout CheckoutConfig) {
+	if !checkout.CollectEmail {
+		removeFieldByID(bodyNode, EmailFieldID)
+		removeFieldByID(bodyNode, ReminderOptOutFieldID)
+		return
+	}
+
+	email := ensureFieldByID(bodyNode, EmailFieldID, "input")
+	attrs := ensureMappingValue(email, "attributes")
+	setMappingScalar(attrs, "label", EmailFieldLabel)
+	setMappingScalar(attrs, "description", loc.T("template.email_description"))
+
+	optOut := ensureFieldByID(bodyNode, ReminderOptOutFieldID, "checkboxes")
+	attrs = ensureMappingValue(optOut, "attributes")
+	setMappingScalar(attrs, "label", ReminderOptOutFieldLabel)
+	option := &yaml.Node{Kind: yaml.MappingNode}
+	setMappingScalar(option, "label", loc.T("template.email_reminder_opt_out"))
+	setMappingNode(attrs, "options", &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{option}})
+}
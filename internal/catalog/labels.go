@@ -0,0 +1,155 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LabelKey names one of the issue labels GitShop manages, independent of the shop's prefix.
+type LabelKey string
+
+const (
+	LabelOrder            LabelKey = "order"
+	LabelAwaitingApproval LabelKey = "awaiting-approval"
+	LabelPendingPayment   LabelKey = "pending-payment"
+	LabelPaid             LabelKey = "paid"
+	LabelAddressIssue     LabelKey = "address-issue"
+	LabelShipped          LabelKey = "shipped"
+	LabelDelivered        LabelKey = "delivered"
+	LabelExpired          LabelKey = "expired"
+	LabelCancelled        LabelKey = "cancelled"
+	LabelReturned         LabelKey = "returned"
+	LabelInquiry          LabelKey = "inquiry"
+	LabelTest             LabelKey = "test"
+)
+
+// DefaultLabelPrefix starts every label name unless the shop picks its own.
+const DefaultLabelPrefix = "gitshop"
+
+// maxLabelPrefixLength keeps the longest label name within GitHub's 50 character limit.
+const maxLabelPrefixLength = 25
+
+type labelSpec struct {
+	key         LabelKey
+	suffix      string
+	color       string
+	description string
+}
+
+var labelSpecs = []labelSpec{
+	{key: LabelOrder, suffix: "order", color: "0ea5e9", description: "GitShop order issue"},
+	{key: LabelAwaitingApproval, suffix: "status:awaiting-approval", color: "eab308", description: "Held for seller approval"},
+	{key: LabelPendingPayment, suffix: "status:pending-payment", color: "f59e0b", description: "Awaiting payment"},
+	{key: LabelPaid, suffix: "status:paid", color: "10b981", description: "Payment received"},
+	{key: LabelAddressIssue, suffix: "status:address-issue", color: "ef4444", description: "Shipping address needs confirming"},
+	{key: LabelShipped, suffix: "status:shipped", color: "3b82f6", description: "Order shipped"},
+	{key: LabelDelivered, suffix: "status:delivered", color: "22c55e", description: "Order delivered"},
+	{key: LabelExpired, suffix: "status:expired", color: "6b7280", description: "Order expired"},
+	{key: LabelCancelled, suffix: "status:cancelled", color: "9ca3af", description: "Order cancelled before payment"},
+	{key: LabelReturned, suffix: "status:returned", color: "f97316", description: "Order returned"},
+	{key: LabelInquiry, suffix: "inquiry", color: "a855f7", description: "Bulk inquiry awaiting a quote"},
+	{key: LabelTest, suffix: "test", color: "d946ef", description: "Placed in test mode; no real payment"},
+}
+
+// LabelsConfig customizes the labels GitShop puts on order issues, for repositories that already
+// use a conflicting scheme. Prefix replaces "gitshop" in every label name, and Colors overrides
+// the color of a label by its key, such as paid or shipped. The zero value is the default scheme.
+type LabelsConfig struct {
+	Prefix string            `yaml:"prefix"`
+	Colors map[string]string `yaml:"colors"`
+}
+
+// Label is a label definition with the shop's prefix and colors applied.
+type Label struct {
+	Key         LabelKey
+	Name        string
+	Color       string
+	Description string
+}
+
+// Name returns the repository label for key, such as "gitshop:status:paid".
+func (c LabelsConfig) Name(key LabelKey) string {
+	for _, spec := range labelSpecs {
+		if spec.key == key {
+			return c.prefix() + ":" + spec.suffix
+		}
+	}
+	return c.prefix() + ":" + string(key)
+}
+
+// Labels returns every label GitShop needs in the repository.
+func (c LabelsConfig) Labels() []Label {
+	labels := make([]Label, 0, len(labelSpecs))
+	for _, spec := range labelSpecs {
+		color := spec.color
+		if custom := strings.TrimSpace(c.Colors[string(spec.key)]); custom != "" {
+			color = strings.ToLower(strings.TrimPrefix(custom, "#"))
+		}
+		labels = append(labels, Label{
+			Key:         spec.key,
+			Name:        c.prefix() + ":" + spec.suffix,
+			Color:       color,
+			Description: spec.description,
+		})
+	}
+	return labels
+}
+
+// Owns reports whether name is in the shop's label namespace, such as any "gitshop:" label.
+func (c LabelsConfig) Owns(name string) bool {
+	return strings.HasPrefix(name, c.prefix()+":")
+}
+
+func (c LabelsConfig) prefix() string {
+	if prefix := strings.TrimSpace(c.Prefix); prefix != "" {
+		return prefix
+	}
+	return DefaultLabelPrefix
+}
+
+var (
+	labelPrefixRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	labelColorRegex  = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+)
+
+// ValidateLabels checks the prefix and that every color names a known label.
+func ValidateLabels(labels LabelsConfig) error {
+	if prefix := strings.TrimSpace(labels.Prefix); prefix != "" {
+		if len(prefix) > maxLabelPrefixLength || !labelPrefixRegex.MatchString(prefix) {
+			return &ValidationError{Path: "prefix", Message: fmt.Sprintf("prefix must be at most %d letters, digits, dots, dashes, or underscores", maxLabelPrefixLength)}
+		}
+	}
+	keys := make([]string, 0, len(labels.Colors))
+	for key := range labels.Colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !knownLabelKey(key) {
+			return &ValidationError{Path: "colors." + key, Message: fmt.Sprintf("unknown label %q; use one of %s", key, strings.Join(labelKeyNames(), ", "))}
+		}
+		if !labelColorRegex.MatchString(strings.TrimSpace(labels.Colors[key])) {
+			return &ValidationError{Path: "colors." + key, Message: "color must be a six digit hex color such as #10b981"}
+		}
+	}
+	return nil
+}
+
+func knownLabelKey(key string) bool {
+	for _, spec := range labelSpecs {
+		if string(spec.key) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func labelKeyNames() []string {
+	names := make([]string, 0, len(labelSpecs))
+	for _, spec := range labelSpecs {
+		names = append(names, string(spec.key))
+	}
+	return names
+}
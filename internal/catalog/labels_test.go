@@ -0,0 +1,42 @@
+package catalog
+
+import "testing"
+
+func TestLabelsConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		config    LabelsConfig
+		key       LabelKey
+		wantName  string
+		wantColor string
+	}{
+		{name: "default order label", key: LabelOrder, wantName: "gitshop:order", wantColor: "0ea5e9"},
+		{name: "default status label", key: LabelPaid, wantName: "gitshop:status:paid", wantColor: "10b981"},
+		{name: "custom prefix", config: LabelsConfig{Prefix: "shop"}, key: LabelShipped, wantName: "shop:status:shipped", wantColor: "3b82f6"},
+		{name: "custom color", config: LabelsConfig{Colors: map[string]string{"paid": "#00AA00"}}, key: LabelPaid, wantName: "gitshop:status:paid", wantColor: "00aa00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.config.Name(tt.key); got != tt.wantName {
+				t.Fatalf("Name() = %q, want %q", got, tt.wantName)
+			}
+			var found *Label
+			for _, label := range tt.config.Labels() {
+				if label.Key == tt.key {
+					found = &label
+				}
+			}
+			if found == nil || found.Name != tt.wantName || found.Color != tt.wantColor {
+				t.Fatalf("Labels() entry = %+v, want %s colored %s", found, tt.wantName, tt.wantColor)
+			}
+			if !tt.config.Owns(tt.wantName) {
+				t.Fatalf("Owns(%q) = false, want true", tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is the longest comment template accepted in gitshop.yaml.
+const MaxMessageLength = 2000
+
+// MessagesConfig overrides the bot comments posted on order issues. Each entry is a Go template
+// rendered with MessageData; an empty entry keeps GitShop's default wording.
+type MessagesConfig struct {
+	CheckoutLink    string `yaml:"checkout_link"`
+	PaymentReceived string `yaml:"payment_received"`
+	CheckoutExpired string `yaml:"checkout_expired"`
+	OrderShipped    string `yaml:"order_shipped"`
+}
+
+// MessageData holds the placeholders available to comment templates. CheckoutURL and ExpiresIn
+// are only set for checkout_link.
+type MessageData struct {
+	ShopName    string
+	OrderNumber int
+	CheckoutURL string
+	ExpiresIn   string
+}
+
+// RenderMessage renders a comment template. A template that references an unknown placeholder or
+// renders to nothing is an error.
+func RenderMessage(text string, data MessageData) (string, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", err
+	}
+	body := strings.TrimSpace(rendered.String())
+	if body == "" {
+		return "", fmt.Errorf("renders an empty comment")
+	}
+	return body, nil
+}
+
+func validateMessages(messages MessagesConfig) error {
+	sample := MessageData{
+		ShopName:    "Sample Shop",
+		OrderNumber: 1001,
+		CheckoutURL: "https://checkout.example.com/sample",
+		ExpiresIn:   "30 minutes",
+	}
+	entries := []struct {
+		key  string
+		text string
+	}{
+		{key: "checkout_link", text: messages.CheckoutLink},
+		{key: "payment_received", text: messages.PaymentReceived},
+		{key: "checkout_expired", text: messages.CheckoutExpired},
+		{key: "order_shipped", text: messages.OrderShipped},
+	}
+	for _, entry := range entries {
+		if entry.text == "" {
+			continue
+		}
+		if utf8.RuneCountInString(entry.text) > MaxMessageLength {
+			return fmt.Errorf("%s must be at most %d characters", entry.key, MaxMessageLength)
+		}
+		body, err := RenderMessage(entry.text, sample)
+		if err != nil {
+			return fmt.Errorf("%s is not a valid template: %w", entry.key, err)
+		}
+		if entry.key == "checkout_link" && !strings.Contains(body, sample.CheckoutURL) {
+			return fmt.Errorf("checkout_link must include {{.CheckoutURL}}")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package catalog
+
+// Options that only apply when another option of the product has a given value.
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+// OptionCondition makes an option apply only when an earlier dropdown option of the same product
+// is answered with Value, such as a frame color that only applies when framed is Yes.
+type OptionCondition struct {
+	Option string `yaml:"option"`
+	Value  string `yaml:"value"`
+}
+
+// Applies reports whether the option's condition is met by the buyer's answers. Options without
+// a condition always apply.
+func (o ProductOption) Applies(options map[string]any) bool {
+	if o.When == nil {
+		return true
+	}
+	answer, _ := options[o.When.Option].(string)
+	return strings.EqualFold(strings.TrimSpace(answer), o.When.Value)
+}
+
+// ApplyOptionConditions removes answers to options whose condition is not met, so they are not
+// priced or kept on the order.
+func ApplyOptionConditions(product ProductConfig, options map[string]any) {
+	// Conditions point at earlier options, so an answer removed here is already gone when the
+	// options depending on it are checked.
+	for _, option := range product.Options {
+		if !option.Applies(options) {
+			delete(options, option.Name)
+		}
+	}
+}
+
+func sameCondition(a, b *OptionCondition) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func validateOptionCondition(option ProductOption, earlier []ProductOption) error {
+	if option.When == nil {
+		return nil
+	}
+	if option.Name == "quantity" {
+		return &ValidationError{Path: "when", Message: "the quantity option cannot have a condition"}
+	}
+	if strings.TrimSpace(option.When.Option) == "" {
+		return &ValidationError{Path: "when.option", Message: "when.option is required"}
+	}
+	var target *ProductOption
+	for i := range earlier {
+		if earlier[i].Name == option.When.Option {
+			target = &earlier[i]
+		}
+	}
+	if target == nil || target.Name == "quantity" {
+		return &ValidationError{Path: "when.option", Message: fmt.Sprintf("%q must name an option listed before %s", option.When.Option, option.Name)}
+	}
+	if target.Type != OptionTypeDropdown {
+		return &ValidationError{Path: "when.option", Message: fmt.Sprintf("%s must be a dropdown to be used in a condition", target.Name)}
+	}
+	for _, value := range target.Values {
+		if strings.EqualFold(value, option.When.Value) {
+			return nil
+		}
+	}
+	return &ValidationError{Path: "when.value", Message: fmt.Sprintf("%q is not a value of %s", option.When.Value, target.Name)}
+}
+
+// optionConditionDescription tells the buyer when to answer a conditional option field. GitHub
+// issue forms cannot hide fields, so the condition is written in the field's description.
+func optionConditionDescription(loc i18n.Localizer, option normalizedOption) string {
+	if option.When == nil {
+		return ""
+	}
+	if option.Required {
+		return loc.T("template.option_condition_required", option.WhenLabel, option.When.Value)
+	}
+	return loc.T("template.option_condition", option.WhenLabel, option.When.Value)
+}
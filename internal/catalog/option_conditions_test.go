@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func framedPrintProduct() ProductConfig {
+	return ProductConfig{
+		SKU:            "PRINT",
+		Name:           "Print",
+		UnitPriceCents: 3000,
+		Active:         true,
+		Options: []ProductOption{
+			{Name: "framed", Label: "Framed", Type: "dropdown", Required: true, Values: []string{"No", "Yes"}},
+			{Name: "frame_color", Label: "Frame color", Type: "dropdown", Required: true, Values: []string{"Black", "Oak"},
+				ExtraCents: map[string]int64{"Oak": 1000}, When: &OptionCondition{Option: "framed", Value: "Yes"}},
+		},
+	}
+}
+
+func TestOptionConditions(t *testing.T) {
+	t.Parallel()
+
+	product := framedPrintProduct()
+
+	unframed := map[string]any{"framed": "No", "frame_color": "Oak"}
+	price, err := UnitPrice(product, unframed)
+	if err != nil || price != 3000 {
+		t.Fatalf("UnitPrice() = %d, %v, want the frame surcharge left out", price, err)
+	}
+	ApplyOptionConditions(product, unframed)
+	if _, ok := unframed["frame_color"]; ok {
+		t.Fatalf("expected the frame color answer to be dropped, got %v", unframed)
+	}
+	if err := CheckOptionAnswers(product, unframed); err != nil {
+		t.Fatalf("CheckOptionAnswers returned error: %v", err)
+	}
+
+	framed := map[string]any{"framed": "yes", "frame_color": "Oak"}
+	if price, err := UnitPrice(product, framed); err != nil || price != 4000 {
+		t.Fatalf("UnitPrice() = %d, %v, want 4000", price, err)
+	}
+
+	var answerErr *OptionAnswerError
+	if err := CheckOptionAnswers(product, map[string]any{"framed": "Yes"}); !errors.As(err, &answerErr) || answerErr.Reason != OptionAnswerRequired {
+		t.Fatalf("CheckOptionAnswers error = %v, want frame color required", err)
+	}
+}
+
+func TestOptionConditionTemplate(t *testing.T) {
+	t.Parallel()
+
+	config := &GitShopConfig{Products: []ProductConfig{framedPrintProduct()}}
+	template, err := NewTemplateSyncer(nil).BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(template, "Required when Framed is Yes; leave it blank otherwise.") {
+		t.Fatalf("expected the condition in the frame color description:\n%s", template)
+	}
+	if report := CheckOrderTemplate(template, config); !report.Valid() {
+		t.Fatalf("expected generated template to match gitshop.yaml, got %+v", report)
+	}
+
+	required := strings.Replace(template, "id: frame_color\n", "id: frame_color\n      validations:\n        required: true\n", 1)
+	mismatches := FindTemplateOptionMismatches(required, config)
+	if len(mismatches) != 1 || !strings.Contains(mismatches[0], "must be optional in the template") {
+		t.Fatalf("mismatches = %v, want the required conditional field reported", mismatches)
+	}
+}
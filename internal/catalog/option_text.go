@@ -0,0 +1,161 @@
+package catalog
+
+// Free-text product options and the rules their answers must follow.
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Product option types. Text is the original name of input and is still accepted.
+const (
+	OptionTypeDropdown = "dropdown"
+	OptionTypeInput    = "input"
+	OptionTypeTextarea = "textarea"
+	OptionTypeText     = "text"
+)
+
+// MaxOptionTextLength caps the length rules of a text option, in characters.
+const MaxOptionTextLength = 1000
+
+// OptionValidation limits a buyer's answer to an input or textarea option. Pattern must match
+// the whole answer. Zero lengths are not checked.
+type OptionValidation struct {
+	Pattern   string `yaml:"pattern"`
+	MinLength int    `yaml:"min_length"`
+	MaxLength int    `yaml:"max_length"`
+}
+
+func (v OptionValidation) isZero() bool {
+	return v == OptionValidation{}
+}
+
+// IsText reports whether buyers type the option's answer rather than pick it.
+func (o ProductOption) IsText() bool {
+	switch o.Type {
+	case OptionTypeInput, OptionTypeTextarea, OptionTypeText:
+		return true
+	}
+	return false
+}
+
+// FieldType is the issue form field type the option is asked with.
+func (o ProductOption) FieldType() string {
+	switch o.Type {
+	case "":
+		return OptionTypeDropdown
+	case OptionTypeText:
+		return OptionTypeInput
+	}
+	return o.Type
+}
+
+// Reasons an OptionAnswerError gives.
+const (
+	OptionAnswerRequired  = "required"
+	OptionAnswerTooShort  = "too_short"
+	OptionAnswerTooLong   = "too_long"
+	OptionAnswerNoPattern = "pattern"
+)
+
+// OptionAnswerError is a buyer's answer to a text option that breaks the option's rules. Limit
+// holds the length the answer missed for the too_short and too_long reasons.
+type OptionAnswerError struct {
+	Option ProductOption
+	Reason string
+	Limit  int
+}
+
+func (e *OptionAnswerError) Error() string {
+	label := e.Option.Label
+	switch e.Reason {
+	case OptionAnswerRequired:
+		return fmt.Sprintf("%s is required", label)
+	case OptionAnswerTooShort:
+		return fmt.Sprintf("%s must be at least %d characters", label, e.Limit)
+	case OptionAnswerTooLong:
+		return fmt.Sprintf("%s must be at most %d characters", label, e.Limit)
+	default:
+		return fmt.Sprintf("%s is not in the expected format", label)
+	}
+}
+
+// CheckAnswer checks a buyer's answer to a text option against its rules.
+func (o ProductOption) CheckAnswer(answer string) error {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		if o.Required {
+			return &OptionAnswerError{Option: o, Reason: OptionAnswerRequired}
+		}
+		return nil
+	}
+
+	rules := o.Validation
+	length := utf8.RuneCountInString(answer)
+	if rules.MinLength > 0 && length < rules.MinLength {
+		return &OptionAnswerError{Option: o, Reason: OptionAnswerTooShort, Limit: rules.MinLength}
+	}
+	if rules.MaxLength > 0 && length > rules.MaxLength {
+		return &OptionAnswerError{Option: o, Reason: OptionAnswerTooLong, Limit: rules.MaxLength}
+	}
+	if rules.Pattern != "" {
+		pattern, err := compileOptionPattern(rules.Pattern)
+		if err != nil || !pattern.MatchString(answer) {
+			return &OptionAnswerError{Option: o, Reason: OptionAnswerNoPattern}
+		}
+	}
+	return nil
+}
+
+// CheckOptionAnswers checks the answers to the product's text options in parsed order options,
+// and that required conditional options are answered when their condition is met. It returns
+// the first *OptionAnswerError.
+func CheckOptionAnswers(product ProductConfig, options map[string]any) error {
+	for _, option := range product.Options {
+		if !option.Applies(options) {
+			continue
+		}
+		answer, _ := options[option.Name].(string)
+		if option.When != nil && option.Required && strings.TrimSpace(answer) == "" {
+			return &OptionAnswerError{Option: option, Reason: OptionAnswerRequired}
+		}
+		if !option.IsText() {
+			continue
+		}
+		if err := option.CheckAnswer(answer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compileOptionPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+func validateOptionRules(option *ProductOption) error {
+	rules := option.Validation
+	if rules.isZero() {
+		return nil
+	}
+	if !option.IsText() {
+		return &ValidationError{Path: "validation", Message: "validation is only supported on input and textarea options"}
+	}
+	if rules.MinLength < 0 || rules.MinLength > MaxOptionTextLength {
+		return &ValidationError{Path: "validation.min_length", Message: fmt.Sprintf("min_length must be between 0 and %d", MaxOptionTextLength)}
+	}
+	if rules.MaxLength < 0 || rules.MaxLength > MaxOptionTextLength {
+		return &ValidationError{Path: "validation.max_length", Message: fmt.Sprintf("max_length must be between 0 and %d", MaxOptionTextLength)}
+	}
+	if rules.MaxLength > 0 && rules.MinLength > rules.MaxLength {
+		return &ValidationError{Path: "validation.min_length", Message: "min_length must not be larger than max_length"}
+	}
+	if rules.Pattern != "" {
+		if _, err := compileOptionPattern(rules.Pattern); err != nil {
+			return &ValidationError{Path: "validation.pattern", Message: fmt.Sprintf("invalid pattern: %v", err)}
+		}
+	}
+	return nil
+}
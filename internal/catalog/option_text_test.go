@@ -0,0 +1,86 @@
+package catalog
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTextOptionValidation(t *testing.T) {
+	t.Parallel()
+
+	config, err := NewParser().ParseFromString(`shop:
+  name: Test Shop
+  currency: usd
+  shipping:
+    flat_rate_cents: 500
+    carrier: USPS
+products:
+  - sku: MUG
+    name: Mug
+    unit_price_cents: 1500
+    active: true
+    options:
+      - name: engraving
+        label: Engraving
+        type: input
+        validation:
+          pattern: "[A-Za-z ]+"
+          max_length: 12
+`)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	option := config.Products[0].Options[0]
+	if option.Validation != (OptionValidation{Pattern: "[A-Za-z ]+", MaxLength: 12}) {
+		t.Fatalf("Validation = %+v", option.Validation)
+	}
+	if err := NewValidator().Validate(config); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+}
+
+func TestCheckOptionAnswers(t *testing.T) {
+	t.Parallel()
+
+	product := ProductConfig{SKU: "MUG", Options: []ProductOption{
+		{Name: "size", Label: "Size", Type: "dropdown", Values: []string{"M"}},
+		{Name: "engraving", Label: "Engraving", Type: "input", Required: true, Validation: OptionValidation{Pattern: "[A-Za-z ]+", MaxLength: 12}},
+		{Name: "notes", Label: "Notes", Type: "textarea", Validation: OptionValidation{MinLength: 5}},
+	}}
+
+	tests := []struct {
+		name       string
+		options    map[string]any
+		wantReason string
+		wantLimit  int
+	}{
+		{name: "valid", options: map[string]any{"engraving": "For Ada", "notes": "Gift wrap please"}},
+		{name: "optional answer skipped", options: map[string]any{"engraving": "For Ada"}},
+		{name: "required answer missing", options: map[string]any{"notes": "Gift wrap please"}, wantReason: OptionAnswerRequired},
+		{name: "too long", options: map[string]any{"engraving": "For my dearest Ada"}, wantReason: OptionAnswerTooLong, wantLimit: 12},
+		{name: "pattern must match the whole answer", options: map[string]any{"engraving": "Ada 1815"}, wantReason: OptionAnswerNoPattern},
+		{name: "too short", options: map[string]any{"engraving": "Ada", "notes": "Hi"}, wantReason: OptionAnswerTooShort, wantLimit: 5},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckOptionAnswers(product, tc.options)
+			if tc.wantReason == "" {
+				if err != nil {
+					t.Fatalf("CheckOptionAnswers returned error: %v", err)
+				}
+				return
+			}
+			var answerErr *OptionAnswerError
+			if !errors.As(err, &answerErr) {
+				t.Fatalf("CheckOptionAnswers error = %v, want *OptionAnswerError", err)
+			}
+			if answerErr.Reason != tc.wantReason || answerErr.Limit != tc.wantLimit {
+				t.Fatalf("error = %+v, want reason %s limit %d", answerErr, tc.wantReason, tc.wantLimit)
+			}
+		})
+	}
+}
@@ -4,34 +4,292 @@ package catalog
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type GitShopConfig struct {
+	Version  int             `yaml:"version"`
 	Shop     ShopConfig      `yaml:"shop"`
 	Products []ProductConfig `yaml:"products"`
+	Messages MessagesConfig  `yaml:"messages"`
+	Branding BrandingConfig  `yaml:"branding"`
+	Labels   LabelsConfig    `yaml:"labels"`
+	// Campaigns are sales that discount products for a limited time.
+	Campaigns []CampaignConfig `yaml:"campaigns"`
+
+	// SourceVersion is the schema version the file was written in, before it was migrated to
+	// Version.
+	SourceVersion int `yaml:"-"`
+}
+
+// NeedsUpgrade reports whether the file was written in an older schema and was migrated on read.
+func (c *GitShopConfig) NeedsUpgrade() bool {
+	return c != nil && c.SourceVersion < CurrentConfigVersion
 }
 
 type ShopConfig struct {
-	Name     string         `yaml:"name"`
-	Currency string         `yaml:"currency"`
-	Manager  string         `yaml:"manager"`
-	Shipping ShippingConfig `yaml:"shipping"`
+	Name          string              `yaml:"name"`
+	Currency      string              `yaml:"currency"`
+	Manager       string              `yaml:"manager"`
+	Locale        string              `yaml:"locale"`
+	MaxQuantity   int                 `yaml:"max_quantity"`
+	Shipping      ShippingConfig      `yaml:"shipping"`
+	Terms         TermsConfig         `yaml:"terms"`
+	Checkout      CheckoutConfig      `yaml:"checkout"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Admin         AdminConfig         `yaml:"admin"`
+	Reviews       ReviewsConfig       `yaml:"reviews"`
+}
+
+// Repository roles that can be required for dashboard access.
+const (
+	AdminRoleWrite    = "write"
+	AdminRoleMaintain = "maintain"
+	AdminRoleAdmin    = "admin"
+)
+
+// AdminConfig controls who may use the shop's dashboard. Any collaborator holding at least
+// MinRole on the repository is let in; write is the default and the lowest role allowed.
+type AdminConfig struct {
+	MinRole string `yaml:"min_role"`
+}
+
+func (a AdminConfig) RequiredRole() string {
+	switch role := strings.ToLower(strings.TrimSpace(a.MinRole)); role {
+	case AdminRoleMaintain, AdminRoleAdmin:
+		return role
+	default:
+		return AdminRoleWrite
+	}
+}
+
+// TermsConfig describes the terms of sale buyers agree to. The version is stored on each
+// order so the exact terms can be produced as dispute evidence.
+type TermsConfig struct {
+	URL             string `yaml:"url"`
+	Version         string `yaml:"version"`
+	RequireCheckbox bool   `yaml:"require_checkbox"`
+	StripeConsent   bool   `yaml:"stripe_consent"`
+}
+
+func (t TermsConfig) Enabled() bool {
+	return t.RequireCheckbox || t.StripeConsent
+}
+
+// Stripe keeps a Checkout Session open for at least 30 minutes and at most 24 hours.
+const (
+	DefaultCheckoutExpiry    = 30 * time.Minute
+	MinCheckoutExpiryMinutes = 30
+	MaxCheckoutExpiryMinutes = 24 * 60
+)
+
+// CheckoutConfig controls the checkout links posted on order issues.
+type CheckoutConfig struct {
+	ExpiresInMinutes int `yaml:"expires_in_minutes"`
+	// ApprovalOverCents holds orders with a larger total for the seller to approve before a
+	// checkout link is posted. Zero disables holds.
+	ApprovalOverCents int64 `yaml:"approval_over_cents"`
+	// CustomFields are extra questions Stripe Checkout asks the buyer. The answers are kept on
+	// the order.
+	CustomFields []CheckoutCustomField `yaml:"custom_fields"`
+	// CollectTaxID lets business buyers enter a VAT or other tax ID. Stripe Tax uses it to apply
+	// the reverse charge to cross-border EU sales.
+	CollectTaxID bool `yaml:"collect_tax_id"`
+	// CollectEmail adds an optional email field to the order template. The address prefills
+	// checkout and receives one reminder before the checkout link expires.
+	CollectEmail bool `yaml:"collect_email"`
+	// EstimateCurrencies adds an optional currency dropdown to the order template. A buyer who
+	// picks one sees the total estimated in it; checkout still charges the shop's currency.
+	EstimateCurrencies []string `yaml:"estimate_currencies"`
+	// QuietHours is a daily window when buyers are not sent checkout reminders.
+	QuietHours QuietHoursConfig `yaml:"quiet_hours"`
+}
+
+// QuietHoursConfig is a daily window such as 22:00 to 08:00 in an IANA timezone, UTC when
+// unset. A window whose start is after its end runs past midnight.
+type QuietHoursConfig struct {
+	Start    string `yaml:"start"`
+	End      string `yaml:"end"`
+	Timezone string `yaml:"timezone"`
+}
+
+const quietHoursLayout = "15:04"
+
+// Enabled reports whether a window is set.
+func (q QuietHoursConfig) Enabled() bool {
+	return q.Start != "" || q.End != ""
+}
+
+// Contains reports whether t falls inside the window. A window that does not parse contains
+// nothing, since callers may read gitshop.yaml without validating it.
+func (q QuietHoursConfig) Contains(t time.Time) bool {
+	from, to, location, err := q.parse()
+	if err != nil || from == to {
+		return false
+	}
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	if from < to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+// parse returns the window's start and end as minutes after midnight, and its timezone.
+func (q QuietHoursConfig) parse() (int, int, *time.Location, error) {
+	start, err := time.Parse(quietHoursLayout, q.Start)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("checkout quiet_hours start must look like 22:00")
+	}
+	end, err := time.Parse(quietHoursLayout, q.End)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("checkout quiet_hours end must look like 08:00")
+	}
+	location, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("checkout quiet_hours timezone %q is not a known timezone", q.Timezone)
+	}
+	return start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), location, nil
+}
+
+// Stripe Checkout takes at most three custom fields, each labelled in up to 50 characters.
+const (
+	MaxCheckoutCustomFields     = 3
+	MaxCheckoutCustomFieldLabel = 50
+)
+
+// Checkout custom field types. Text is the default.
+const (
+	CustomFieldText    = "text"
+	CustomFieldNumeric = "numeric"
+)
+
+// CheckoutCustomField is a question such as "Company name" or "VAT ID" asked at checkout. Key
+// identifies the answer and must be alphanumeric.
+type CheckoutCustomField struct {
+	Key      string `yaml:"key"`
+	Label    string `yaml:"label"`
+	Type     string `yaml:"type"`
+	Optional bool   `yaml:"optional"`
 }
 
+// ExpiresIn is how long a checkout link stays open, DefaultCheckoutExpiry when unset.
+func (c CheckoutConfig) ExpiresIn() time.Duration {
+	if c.ExpiresInMinutes <= 0 {
+		return DefaultCheckoutExpiry
+	}
+	return time.Duration(c.ExpiresInMinutes) * time.Minute
+}
+
+// RequiresApproval reports whether an order with this total must wait for the seller's approval.
+func (c CheckoutConfig) RequiresApproval(totalCents int64) bool {
+	return c.ApprovalOverCents > 0 && totalCents > c.ApprovalOverCents
+}
+
+// ReviewsConfig controls the review invitation GitShop posts on an order issue once the order is
+// delivered. Buyers rate with a reaction or a `.gitshop review` comment.
+type ReviewsConfig struct {
+	// Disabled turns the invitation off. Buyers can still rate delivered orders by comment.
+	Disabled bool `yaml:"disabled"`
+	// CloseAfterDays closes the order issue this many days after delivery. Zero leaves it open.
+	CloseAfterDays int `yaml:"close_after_days"`
+}
+
+// MaxReviewCloseAfterDays bounds how long a delivered order issue may be left open for reviews.
+const MaxReviewCloseAfterDays = 90
+
+// NotificationsConfig sets where seller notifications go. The shop owner's email is used
+// when no address is configured.
+type NotificationsConfig struct {
+	Email string `yaml:"email"`
+}
+
+// ShippingConfig sets the shipping charged per order. Orders whose subtotal reaches FreeOverCents
+// ship free unless their product sets its own shipping_cents.
 type ShippingConfig struct {
-	FlatRateCents int    `yaml:"flat_rate_cents"`
+	FlatRateCents int64  `yaml:"flat_rate_cents"`
+	FreeOverCents int64  `yaml:"free_over_cents"`
 	Carrier       string `yaml:"carrier"`
+	// ShipWithinDays is how long a paid order may wait before GitShop reminds the seller to ship
+	// it. Unset means DefaultShipWithinDays and 0 turns the reminders off.
+	ShipWithinDays *int `yaml:"ship_within_days"`
+	// OverdueIssue also keeps an internal issue, assigned to the shop manager, listing the
+	// overdue orders.
+	OverdueIssue bool `yaml:"overdue_issue"`
+}
+
+// Paid orders are overdue after five days unless the shop sets its own window.
+const (
+	DefaultShipWithinDays = 5
+	MaxShipWithinDays     = 60
+)
+
+// ShipWithin is the number of days a paid order may wait to ship, 0 when reminders are off.
+func (c ShippingConfig) ShipWithin() int {
+	if c.ShipWithinDays == nil {
+		return DefaultShipWithinDays
+	}
+	return *c.ShipWithinDays
 }
 
 type ProductConfig struct {
 	SKU            string          `yaml:"sku"`
 	Name           string          `yaml:"name"`
 	Description    string          `yaml:"description"`
-	UnitPriceCents int             `yaml:"unit_price_cents"`
+	UnitPriceCents int64           `yaml:"unit_price_cents"`
 	Active         bool            `yaml:"active"`
 	Options        []ProductOption `yaml:"options"`
+	Restricted     bool            `yaml:"restricted"`
+	VerifyIdentity bool            `yaml:"verify_identity"`
+	MinimumAge     int             `yaml:"minimum_age"`
+	MaxQuantity    int             `yaml:"max_quantity"`
+	ShippingCents  *int64          `yaml:"shipping_cents"`
+	Type           string          `yaml:"type"`
+	Category       string          `yaml:"category"`
+	Billing        string          `yaml:"billing"`
+	// Campaign is the sale discounting the product when the file was parsed, nil when none does.
+	Campaign *CampaignConfig `yaml:"-"`
+}
+
+// A product's max_quantity overrides the shop's, and DefaultMaxQuantity applies when neither is set.
+// MaxQuantityLimit keeps a generated quantity dropdown a usable length.
+const (
+	DefaultMaxQuantity = 10
+	MaxQuantityLimit   = 100
+)
+
+// QuantityLimit is the most units of product a single order may hold.
+func (s ShopConfig) QuantityLimit(product ProductConfig) int {
+	if product.MaxQuantity > 0 {
+		return product.MaxQuantity
+	}
+	if s.MaxQuantity > 0 {
+		return s.MaxQuantity
+	}
+	return DefaultMaxQuantity
+}
+
+// MaxCategoryLength is the longest product category accepted in gitshop.yaml.
+const MaxCategoryLength = 50
+
+// ProductTypeInquiry marks a bulk product that is quoted by the shop instead of paid at order time.
+const ProductTypeInquiry = "inquiry"
+
+func (p ProductConfig) IsInquiry() bool {
+	return p.Type == ProductTypeInquiry
+}
+
+// A product with billing set is sold as a Stripe subscription renewed every month or year.
+const (
+	BillingMonthly = "monthly"
+	BillingYearly  = "yearly"
+)
+
+func (p ProductConfig) IsSubscription() bool {
+	return p.Billing != ""
 }
 
 type ProductOption struct {
@@ -40,20 +298,49 @@ type ProductOption struct {
 	Type     string   `yaml:"type"`
 	Required bool     `yaml:"required"`
 	Values   []string `yaml:"values"`
+	// ExtraCents holds the surcharge of each value written as {value, extra_cents}.
+	ExtraCents map[string]int64 `yaml:"-"`
+	// Validation limits the buyer's answer to an input or textarea option.
+	Validation OptionValidation `yaml:"validation"`
+	// When makes the option apply only when another option has a value.
+	When *OptionCondition `yaml:"when"`
 }
 
-type Parser struct{}
+type Parser struct {
+	now func() time.Time
+}
 
 func NewParser() *Parser {
-	return &Parser{}
+	return &Parser{now: time.Now}
 }
 
+// Parse reads gitshop.yaml, migrating files written in an older schema version. Products on sale
+// when it is called carry the campaign that discounts them.
 func (p *Parser) Parse(content []byte) (*GitShopConfig, error) {
-	var config GitShopConfig
-	if err := yaml.Unmarshal(content, &config); err != nil {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
+	sourceVersion, err := migrateConfigDocument(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var config GitShopConfig
+	if doc.Kind != 0 {
+		if err := doc.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	}
+	config.SourceVersion = sourceVersion
+
+	now := time.Now
+	if p != nil && p.now != nil {
+		now = p.now
+	}
+	config.applyCampaigns(now())
+
 	return &config, nil
 }
 
@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParser_Parse(t *testing.T) {
@@ -74,3 +75,85 @@ products:
 		})
 	}
 }
+
+func TestCheckoutConfig_RequiresApproval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		config     CheckoutConfig
+		totalCents int64
+		want       bool
+	}{
+		{name: "disabled", config: CheckoutConfig{}, totalCents: 1_000_000, want: false},
+		{name: "below threshold", config: CheckoutConfig{ApprovalOverCents: 50000}, totalCents: 49999, want: false},
+		{name: "at threshold", config: CheckoutConfig{ApprovalOverCents: 50000}, totalCents: 50000, want: false},
+		{name: "above threshold", config: CheckoutConfig{ApprovalOverCents: 50000}, totalCents: 50001, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.config.RequiresApproval(tt.totalCents); got != tt.want {
+				t.Fatalf("RequiresApproval(%d) = %v, want %v", tt.totalCents, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHoursConfig_Contains(t *testing.T) {
+	t.Parallel()
+
+	overnight := QuietHoursConfig{Start: "22:00", End: "08:00", Timezone: "Europe/Berlin"}
+	daytime := QuietHoursConfig{Start: "12:00", End: "13:30"}
+	tests := []struct {
+		name   string
+		config QuietHoursConfig
+		at     time.Time
+		want   bool
+	}{
+		{name: "unset", config: QuietHoursConfig{}, at: time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC), want: false},
+		{name: "overnight before midnight", config: overnight, at: time.Date(2026, 10, 16, 21, 30, 0, 0, time.UTC), want: true},
+		{name: "overnight after midnight", config: overnight, at: time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC), want: true},
+		{name: "overnight ends", config: overnight, at: time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC), want: false},
+		{name: "daytime in UTC", config: daytime, at: time.Date(2026, 10, 16, 13, 29, 0, 0, time.UTC), want: true},
+		{name: "daytime end is open", config: daytime, at: time.Date(2026, 10, 16, 13, 30, 0, 0, time.UTC), want: false},
+		{name: "unparsed window", config: QuietHoursConfig{Start: "late", End: "08:00"}, at: time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.config.Contains(tt.at); got != tt.want {
+				t.Fatalf("Contains(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShippingConfig_ShipWithin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config ShippingConfig
+		want   int
+	}{
+		{name: "unset uses default", config: ShippingConfig{}, want: DefaultShipWithinDays},
+		{name: "zero turns reminders off", config: ShippingConfig{ShipWithinDays: intPtr(0)}, want: 0},
+		{name: "custom window", config: ShippingConfig{ShipWithinDays: intPtr(2)}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.config.ShipWithin(); got != tt.want {
+				t.Fatalf("ShipWithin() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -3,9 +3,19 @@ package catalog
 // Package catalog provides price calculation functionality.
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/money"
+)
+
+var (
+	// ErrQuantityExceeded is returned when an order asks for more units than the product allows.
+	ErrQuantityExceeded = errors.New("quantity exceeds the per-order limit")
+	ErrProductNotFound  = errors.New("product not found")
+	ErrProductInactive  = errors.New("product is not active")
 )
 
 type Pricer struct{}
@@ -14,22 +24,59 @@ func NewPricer() *Pricer {
 	return &Pricer{}
 }
 
-func (p *Pricer) ComputeSubtotal(config *GitShopConfig, sku string, options map[string]any) (int, error) {
+func (p *Pricer) ComputeSubtotal(config *GitShopConfig, sku string, options map[string]any) (int64, error) {
 	product := p.findProduct(config, sku)
 	if product == nil {
-		return 0, fmt.Errorf("product with SKU %s not found", sku)
+		return 0, fmt.Errorf("%w: %s", ErrProductNotFound, sku)
 	}
 
 	if !product.Active {
-		return 0, fmt.Errorf("product with SKU %s is not active", sku)
+		return 0, fmt.Errorf("%w: %s", ErrProductInactive, sku)
 	}
 
 	quantity := p.getQuantity(options)
-	return product.UnitPriceCents * quantity, nil
+	if limit := config.Shop.QuantityLimit(*product); quantity > limit {
+		return 0, fmt.Errorf("%w: %s allows at most %d per order", ErrQuantityExceeded, sku, limit)
+	}
+	unitPrice, err := UnitPrice(*product, options)
+	if err != nil {
+		return 0, fmt.Errorf("unit price for %s: %w", sku, err)
+	}
+	subtotal, err := money.Multiply(unitPrice, int64(quantity))
+	if err != nil {
+		return 0, fmt.Errorf("subtotal for %d × %s: %w", quantity, sku, err)
+	}
+	return subtotal, nil
 }
 
-func (p *Pricer) GetShippingCents(config *GitShopConfig) int {
-	return config.Shop.Shipping.FlatRateCents
+// UnitPrice is the price of one unit of product, at its sale price during a campaign, with the
+// surcharges of the chosen option values. Options whose condition is not met add nothing.
+func UnitPrice(product ProductConfig, options map[string]any) (int64, error) {
+	price := product.PriceCents()
+	for _, option := range product.Options {
+		chosen, ok := options[option.Name].(string)
+		if !ok || !option.Applies(options) {
+			continue
+		}
+		var err error
+		if price, err = money.Add(price, option.Surcharge(strings.TrimSpace(chosen))); err != nil {
+			return 0, err
+		}
+	}
+	return price, nil
+}
+
+// GetShippingCents is the shipping charged on an order for sku with the given subtotal. A product's
+// shipping_cents replaces the flat rate and is charged even past free_over_cents.
+func (p *Pricer) GetShippingCents(config *GitShopConfig, sku string, subtotalCents int64) int64 {
+	if product := p.findProduct(config, sku); product != nil && product.ShippingCents != nil {
+		return *product.ShippingCents
+	}
+	shipping := config.Shop.Shipping
+	if shipping.FreeOverCents > 0 && subtotalCents >= shipping.FreeOverCents {
+		return 0
+	}
+	return shipping.FlatRateCents
 }
 
 func (p *Pricer) findProduct(config *GitShopConfig, sku string) *ProductConfig {
@@ -1,6 +1,8 @@
 package catalog
 
 import (
+	"errors"
+	"math"
 	"testing"
 )
 
@@ -25,6 +27,12 @@ func TestPricer_ComputeSubtotal(t *testing.T) {
 					},
 				},
 			},
+			{
+				SKU:            "VAULT_V1",
+				Name:           "Vault",
+				UnitPriceCents: math.MaxInt64 / 2,
+				Active:         true,
+			},
 		},
 	}
 
@@ -32,7 +40,7 @@ func TestPricer_ComputeSubtotal(t *testing.T) {
 		name      string
 		sku       string
 		options   map[string]any
-		wantCents int
+		wantCents int64
 		wantErr   bool
 	}{
 		{
@@ -74,6 +82,12 @@ func TestPricer_ComputeSubtotal(t *testing.T) {
 			wantCents: 1800,
 			wantErr:   false,
 		},
+		{
+			name:    "subtotal overflow",
+			sku:     "VAULT_V1",
+			options: map[string]any{"quantity": 3},
+			wantErr: true,
+		},
 	}
 
 	pricer := NewPricer()
@@ -100,3 +114,136 @@ func TestPricer_ComputeSubtotal(t *testing.T) {
 		})
 	}
 }
+
+func TestPricer_ComputeSubtotalQuantityLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		shop     ShopConfig
+		product  ProductConfig
+		quantity int
+		wantErr  bool
+	}{
+		{name: "default limit", quantity: DefaultMaxQuantity},
+		{name: "above default limit", quantity: DefaultMaxQuantity + 1, wantErr: true},
+		{name: "shop limit", shop: ShopConfig{MaxQuantity: 25}, quantity: 25},
+		{name: "above shop limit", shop: ShopConfig{MaxQuantity: 3}, quantity: 4, wantErr: true},
+		{name: "product limit overrides shop", shop: ShopConfig{MaxQuantity: 3}, product: ProductConfig{MaxQuantity: 50}, quantity: 50},
+		{name: "above product limit", shop: ShopConfig{MaxQuantity: 50}, product: ProductConfig{MaxQuantity: 2}, quantity: 3, wantErr: true},
+	}
+
+	pricer := NewPricer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			product := tt.product
+			product.SKU = "COFFEE_V1"
+			product.UnitPriceCents = 100
+			product.Active = true
+			config := &GitShopConfig{Shop: tt.shop, Products: []ProductConfig{product}}
+
+			subtotal, err := pricer.ComputeSubtotal(config, "COFFEE_V1", map[string]any{"quantity": tt.quantity})
+			if tt.wantErr {
+				if !errors.Is(err, ErrQuantityExceeded) {
+					t.Fatalf("ComputeSubtotal() error = %v, want ErrQuantityExceeded", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ComputeSubtotal() error = %v", err)
+			}
+			if want := int64(tt.quantity) * 100; subtotal != want {
+				t.Errorf("ComputeSubtotal() = %d, want %d", subtotal, want)
+			}
+		})
+	}
+}
+
+func TestUnitPrice(t *testing.T) {
+	t.Parallel()
+
+	product := ProductConfig{
+		SKU:            "TSHIRT",
+		UnitPriceCents: 2500,
+		Options: []ProductOption{
+			{Name: "size", Type: "dropdown", Values: []string{"M", "XXL"}, ExtraCents: map[string]int64{"XXL": 500}},
+			{Name: "color", Type: "dropdown", Values: []string{"Black", "Gold"}, ExtraCents: map[string]int64{"Gold": 250}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		options map[string]any
+		want    int64
+	}{
+		{name: "no surcharge", options: map[string]any{"size": "M", "color": "Black"}, want: 2500},
+		{name: "one surcharge", options: map[string]any{"size": "XXL", "color": "Black"}, want: 3000},
+		{name: "surcharges add up", options: map[string]any{"size": "XXL", "color": "Gold"}, want: 3250},
+		{name: "option not chosen", options: map[string]any{"quantity": 2}, want: 2500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := UnitPrice(product, tt.options)
+			if err != nil {
+				t.Fatalf("UnitPrice() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("UnitPrice() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	config := &GitShopConfig{Products: []ProductConfig{{SKU: "TSHIRT", UnitPriceCents: 2500, Active: true, Options: product.Options}}}
+	subtotal, err := NewPricer().ComputeSubtotal(config, "TSHIRT", map[string]any{"size": "XXL", "quantity": 2})
+	if err != nil {
+		t.Fatalf("ComputeSubtotal() error = %v", err)
+	}
+	if subtotal != 6000 {
+		t.Errorf("ComputeSubtotal() = %d, want 6000", subtotal)
+	}
+}
+
+func TestPricer_GetShippingCents(t *testing.T) {
+	t.Parallel()
+
+	heavy := int64(1500)
+	free := int64(0)
+	config := &GitShopConfig{
+		Shop: ShopConfig{Shipping: ShippingConfig{FlatRateCents: 500, FreeOverCents: 5000}},
+		Products: []ProductConfig{
+			{SKU: "MUG"},
+			{SKU: "ANVIL", ShippingCents: &heavy},
+			{SKU: "EBOOK", ShippingCents: &free},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		sku      string
+		subtotal int64
+		want     int64
+	}{
+		{name: "flat rate", sku: "MUG", subtotal: 4999, want: 500},
+		{name: "free over threshold", sku: "MUG", subtotal: 5000, want: 0},
+		{name: "product override", sku: "ANVIL", subtotal: 1000, want: 1500},
+		{name: "product override past threshold", sku: "ANVIL", subtotal: 9000, want: 1500},
+		{name: "product ships free", sku: "EBOOK", subtotal: 1000, want: 0},
+		{name: "unknown sku", sku: "MISSING", subtotal: 1000, want: 500},
+	}
+
+	pricer := NewPricer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := pricer.GetShippingCents(config, tt.sku, tt.subtotal); got != tt.want {
+				t.Errorf("GetShippingCents() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
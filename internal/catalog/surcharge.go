@@ -0,0 +1,104 @@
+package catalog
+
+// Option values that add to a product's unit price.
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gitshopapp/gitshop/internal/money"
+)
+
+// optionValue is one entry of an option's values: either a plain string or
+// {value: "XXL", extra_cents: 500}.
+type optionValue struct {
+	Value      string `yaml:"value"`
+	ExtraCents int64  `yaml:"extra_cents"`
+}
+
+func (v *optionValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&v.Value)
+	}
+	type plain optionValue
+	return node.Decode((*plain)(v))
+}
+
+func (o *ProductOption) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		Name       string           `yaml:"name"`
+		Label      string           `yaml:"label"`
+		Type       string           `yaml:"type"`
+		Required   bool             `yaml:"required"`
+		Values     []optionValue    `yaml:"values"`
+		Validation OptionValidation `yaml:"validation"`
+		When       *OptionCondition `yaml:"when"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	*o = ProductOption{Name: raw.Name, Label: raw.Label, Type: raw.Type, Required: raw.Required, Validation: raw.Validation, When: raw.When}
+	if raw.Values == nil {
+		return nil
+	}
+	o.Values = make([]string, 0, len(raw.Values))
+	for _, value := range raw.Values {
+		o.Values = append(o.Values, value.Value)
+		if value.ExtraCents != 0 {
+			if o.ExtraCents == nil {
+				o.ExtraCents = make(map[string]int64)
+			}
+			o.ExtraCents[value.Value] = value.ExtraCents
+		}
+	}
+	return nil
+}
+
+// Surcharge is what choosing value adds to the unit price.
+func (o ProductOption) Surcharge(value string) int64 {
+	return o.ExtraCents[value]
+}
+
+// TemplateValues are the option's dropdown entries in an order template, with any surcharge
+// shown after the value.
+func (o ProductOption) TemplateValues() []string {
+	values := make([]string, 0, len(o.Values))
+	for _, value := range o.Values {
+		values = append(values, optionValueLabel(value, o.Surcharge(value)))
+	}
+	return values
+}
+
+func optionValueLabel(value string, extraCents int64) string {
+	if extraCents == 0 {
+		return value
+	}
+	return fmt.Sprintf("%s (+%s)", value, money.Format(extraCents))
+}
+
+var surchargeSuffix = regexp.MustCompile(`^(.*\S)\s+\(\+\$([0-9]+(?:\.[0-9]{2})?)\)$`)
+
+// parseOptionValueLabel splits an order template dropdown entry into its value and surcharge.
+func parseOptionValueLabel(label string) (string, int64) {
+	label = strings.TrimSpace(label)
+	match := surchargeSuffix.FindStringSubmatch(label)
+	if match == nil {
+		return label, 0
+	}
+	cents, err := money.Parse(match[2])
+	if err != nil {
+		return label, 0
+	}
+	return match[1], cents
+}
+
+// OptionValueFromLabel returns the option value a buyer picked from an order template dropdown,
+// without the surcharge shown next to it.
+func OptionValueFromLabel(label string) string {
+	value, _ := parseOptionValueLabel(label)
+	return value
+}
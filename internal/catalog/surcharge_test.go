@@ -0,0 +1,61 @@
+package catalog
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParser_ParseOptionSurcharges(t *testing.T) {
+	t.Parallel()
+
+	config, err := NewParser().ParseFromString(`
+products:
+  - sku: "TSHIRT"
+    options:
+      - name: "size"
+        label: "Size"
+        type: "dropdown"
+        values: ["M", {value: "XL", extra_cents: 300}, {value: "XXL", extra_cents: 500}]
+`)
+	if err != nil {
+		t.Fatalf("ParseFromString() error = %v", err)
+	}
+	option := config.Products[0].Options[0]
+	if want := []string{"M", "XL", "XXL"}; !slices.Equal(option.Values, want) {
+		t.Fatalf("Values = %v, want %v", option.Values, want)
+	}
+	if got := option.Surcharge("XXL"); got != 500 {
+		t.Errorf("Surcharge(XXL) = %d, want 500", got)
+	}
+	if got := option.Surcharge("M"); got != 0 {
+		t.Errorf("Surcharge(M) = %d, want 0", got)
+	}
+	if want := []string{"M", "XL (+$3.00)", "XXL (+$5.00)"}; !slices.Equal(option.TemplateValues(), want) {
+		t.Errorf("TemplateValues() = %v, want %v", option.TemplateValues(), want)
+	}
+}
+
+func TestOptionValueFromLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{label: "XXL (+$5.00)", want: "XXL"},
+		{label: " Whole Bean (+$1.50) ", want: "Whole Bean"},
+		{label: "Medium", want: "Medium"},
+		{label: "Gift (+$5)", want: "Gift"},
+		{label: "Note (+5.00)", want: "Note (+5.00)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			t.Parallel()
+
+			if got := OptionValueFromLabel(tt.label); got != tt.want {
+				t.Errorf("OptionValueFromLabel(%q) = %q, want %q", tt.label, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,20 @@
 package catalog
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/money"
 )
 
 type TemplateSyncer struct {
@@ -44,7 +48,7 @@ func (s *TemplateSyncer) BuildTemplateContent(config *GitShopConfig) (string, er
 	if _, err := sharedOptionDefinitions(products); err != nil {
 		return "", err
 	}
-	content, err := s.generateIssueTemplate(products)
+	content, err := s.generateIssueTemplate(defaultOrderTemplateName, products, config.Shop, config.Labels)
 	if err != nil {
 		return "", err
 	}
@@ -84,16 +88,24 @@ func (s *TemplateSyncer) SyncTemplateContent(existingTemplate string, config *Gi
 		return "", err
 	}
 
-	productField := ensureFieldByID(bodyNode, "product", "dropdown")
-	updateProductFieldOptions(productField, products)
+	loc := i18n.New(config.Shop.Locale)
+	if catalogField := findProductCatalogField(bodyNode); catalogField != nil && getFieldType(findFieldByID(bodyNode, "product")) == "input" {
+		setMappingScalar(ensureMappingValue(catalogField, "attributes"), "value", productCatalogMarkdown(loc, products))
+	} else {
+		productField := ensureFieldByID(bodyNode, "product", "dropdown")
+		updateProductFieldOptions(productField, products)
+	}
 
-	quantityValues := quantityOptionValues(products)
+	quantityValues := quantityOptionValues(products, config.Shop)
 	quantityField := ensureFieldByID(bodyNode, "quantity", "dropdown")
 	setFieldLabel(quantityField, "Quantity")
 	setFieldOptions(quantityField, quantityValues)
 	setFieldRequired(quantityField, true)
 
-	s.syncOptionFields(bodyNode, sharedOptions)
+	s.syncOptionFields(loc, bodyNode, sharedOptions)
+	syncEmailFields(loc, bodyNode, config.Shop.Checkout)
+	syncCurrencyField(loc, bodyNode, config.Shop)
+	syncAcknowledgementFields(bodyNode, acknowledgementFields(loc, products, config.Shop.Terms))
 	ensureLiteralStyleForMultilineScalars(&doc)
 
 	out, err := yaml.Marshal(&doc)
@@ -130,11 +142,14 @@ func (s *TemplateSyncer) IsSimpleSync(existingTemplate string, config *GitShopCo
 	if _, err := sharedOptionDefinitions(products); err != nil {
 		return false, "Sync is unavailable because template products do not share the same option schema. Split products across templates.", nil
 	}
+	if len(products) > MaxDropdownOptions && getFieldType(findFieldByID(bodyNode, "product")) == "dropdown" {
+		return false, fmt.Sprintf("Sync is unavailable because GitHub shows at most %d products in a dropdown. Regenerate the template to split it by category.", MaxDropdownOptions), nil
+	}
 
 	return true, "", nil
 }
 
-func (s *TemplateSyncer) syncOptionFields(bodyNode *yaml.Node, options []normalizedOption) {
+func (s *TemplateSyncer) syncOptionFields(loc i18n.Localizer, bodyNode *yaml.Node, options []normalizedOption) {
 	if bodyNode == nil || bodyNode.Kind != yaml.SequenceNode {
 		return
 	}
@@ -221,7 +236,10 @@ func (s *TemplateSyncer) syncOptionFields(bodyNode *yaml.Node, options []normali
 		if fieldType == "dropdown" {
 			setFieldOptions(field, opt.Values)
 		}
-		setFieldRequired(field, opt.Required)
+		if description := optionConditionDescription(loc, opt); description != "" {
+			setMappingScalar(ensureMappingValue(field, "attributes"), "description", description)
+		}
+		setFieldRequired(field, opt.TemplateRequired())
 		newBlock = append(newBlock, field)
 	}
 
@@ -254,38 +272,30 @@ func (s *TemplateSyncer) syncOptionFields(bodyNode *yaml.Node, options []normali
 	bodyNode.Content = updated
 }
 
-func (s *TemplateSyncer) generateIssueTemplate(products []ProductConfig) (string, error) {
+func (s *TemplateSyncer) generateIssueTemplate(name string, products []ProductConfig, shop ShopConfig, labels LabelsConfig) (string, error) {
+	loc := i18n.New(shop.Locale)
 	template := issueTemplate{
-		Name:        "🛒 Place an Order",
-		Description: "Order products from our store",
+		Name:        name,
+		Description: loc.T("template.description"),
 		Title:       "[ORDER] ",
-		Labels:      []string{"gitshop:order", "gitshop:status:pending-payment"},
+		Labels:      []string{labels.Name(LabelOrder), labels.Name(LabelPendingPayment)},
 		Body: []templateField{
 			{
 				Type: "markdown",
 				Attributes: templateFieldAttributes{
-					Value: "## Welcome to our store!\nFill out the form below to place your order. You'll receive a payment link after submitting.\n",
+					Value: loc.T("template.welcome"),
 				},
 			},
-			{
-				Type: "dropdown",
-				ID:   "product",
-				Attributes: templateFieldAttributes{
-					Label:       "Product",
-					Description: "Select the product you want to order",
-					Options:     productOptions(products),
-				},
-				Validations: &templateFieldValidations{Required: true},
-			},
 		},
 	}
+	template.Body = append(template.Body, productFields(loc, products)...)
 
 	template.Body = append(template.Body, templateField{
 		Type: "dropdown",
 		ID:   "quantity",
 		Attributes: templateFieldAttributes{
 			Label:   "Quantity",
-			Options: quantityOptionValues(products),
+			Options: quantityOptionValues(products, shop),
 		},
 		Validations: &templateFieldValidations{Required: true},
 	})
@@ -309,12 +319,31 @@ func (s *TemplateSyncer) generateIssueTemplate(products []ProductConfig) (string
 		if fieldType == "dropdown" {
 			field.Attributes.Options = append([]string{}, opt.Values...)
 		}
-		if opt.Required {
+		field.Attributes.Description = optionConditionDescription(loc, opt)
+		if opt.TemplateRequired() {
 			field.Validations = &templateFieldValidations{Required: true}
 		}
 		template.Body = append(template.Body, field)
 	}
 
+	template.Body = append(template.Body, emailFields(loc, shop.Checkout)...)
+	template.Body = append(template.Body, currencyFields(loc, shop)...)
+
+	for _, ack := range acknowledgementFields(loc, products, shop.Terms) {
+		if !ack.Enabled {
+			continue
+		}
+		template.Body = append(template.Body, templateField{
+			Type: "checkboxes",
+			ID:   ack.ID,
+			Attributes: templateFieldAttributes{
+				Label:       ack.Label,
+				Description: ack.Description,
+				Options:     []templateCheckboxOption{{Label: ack.CheckboxLabel, Required: true}},
+			},
+		})
+	}
+
 	template.Body = append(template.Body, templateField{
 		Type: "markdown",
 		Attributes: templateFieldAttributes{
@@ -375,10 +404,29 @@ type normalizedOption struct {
 	Type     string
 	Required bool
 	Values   []string
+	// When and WhenLabel describe the option's condition, with the label of the option it
+	// depends on. Conditional fields are optional in the template whatever Required says.
+	When      *OptionCondition
+	WhenLabel string
+}
+
+// TemplateRequired reports whether the option's field is required in the order template.
+func (o normalizedOption) TemplateRequired() bool {
+	return o.Required && o.When == nil
 }
 
 var skuPattern = regexp.MustCompile(`(?i)SKU:([A-Z0-9_]+)`)
 
+// Checkbox fields written into order templates for restricted products and
+// shop.terms.require_checkbox.
+const (
+	EligibilityFieldID    = "eligibility"
+	EligibilityFieldLabel = "Eligibility"
+
+	TermsFieldID    = "terms"
+	TermsFieldLabel = "Terms of sale"
+)
+
 func selectTemplateProducts(config *GitShopConfig, preferredSKUs []string) ([]ProductConfig, error) {
 	if config == nil {
 		return nil, fmt.Errorf("gitshop config is required")
@@ -441,9 +489,18 @@ func normalizeProductOptions(product ProductConfig) []normalizedOption {
 		item := normalizedOption{
 			Name:     option.Name,
 			Label:    option.Label,
-			Type:     option.Type,
+			Type:     option.FieldType(),
 			Required: option.Required,
-			Values:   optionValuesToStrings(option.Values),
+			Values:   option.TemplateValues(),
+		}
+		if option.When != nil {
+			item.When = option.When
+			item.WhenLabel = option.When.Option
+			for _, other := range product.Options {
+				if other.Name == option.When.Option && other.Label != "" {
+					item.WhenLabel = other.Label
+				}
+			}
 		}
 		normalized = append(normalized, item)
 	}
@@ -458,6 +515,9 @@ func normalizedOptionSlicesEqual(a, b []normalizedOption) bool {
 		if a[i].Name != b[i].Name || a[i].Label != b[i].Label || a[i].Type != b[i].Type || a[i].Required != b[i].Required {
 			return false
 		}
+		if !sameCondition(a[i].When, b[i].When) {
+			return false
+		}
 		if !slices.Equal(a[i].Values, b[i].Values) {
 			return false
 		}
@@ -465,24 +525,44 @@ func normalizedOptionSlicesEqual(a, b []normalizedOption) bool {
 	return true
 }
 
-func optionValuesToStrings(values []string) []string {
-	return append([]string{}, values...)
-}
-
-func quantityOptionValues(products []ProductConfig) []string {
-	if len(products) == 0 {
-		return []string{"1", "2", "3", "4", "5"}
+// quantityOptionValues lists the quantities offered in an order form. Configured values above the
+// products' max_quantity are dropped; without configured values the form offers 1 to 5, or 1 to the
+// max_quantity when one is set.
+func quantityOptionValues(products []ProductConfig, shop ShopConfig) []string {
+	limit := 0
+	limitSet := shop.MaxQuantity > 0
+	for _, product := range products {
+		limit = max(limit, shop.QuantityLimit(product))
+		limitSet = limitSet || product.MaxQuantity > 0
 	}
-	for _, option := range products[0].Options {
-		if option.Name != "quantity" {
-			continue
-		}
-		values := optionValuesToStrings(option.Values)
-		if len(values) > 0 {
-			return values
+
+	if len(products) > 0 {
+		for _, option := range products[0].Options {
+			if option.Name != "quantity" {
+				continue
+			}
+			values := make([]string, 0, len(option.Values))
+			for _, value := range option.Values {
+				if qty, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && qty > limit {
+					continue
+				}
+				values = append(values, value)
+			}
+			if len(values) > 0 {
+				return values
+			}
 		}
 	}
-	return []string{"1", "2", "3", "4", "5"}
+
+	count := 5
+	if limitSet {
+		count = limit
+	}
+	values := make([]string, 0, count)
+	for qty := 1; qty <= count; qty++ {
+		values = append(values, strconv.Itoa(qty))
+	}
+	return values
 }
 
 type issueTemplate struct {
@@ -501,30 +581,194 @@ type templateField struct {
 }
 
 type templateFieldAttributes struct {
-	Label       string   `yaml:"label,omitempty"`
-	Description string   `yaml:"description,omitempty"`
-	Options     []string `yaml:"options,omitempty"`
-	Value       string   `yaml:"value,omitempty"`
+	Label       string `yaml:"label,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Options     any    `yaml:"options,omitempty"`
+	Value       string `yaml:"value,omitempty"`
+}
+
+type templateCheckboxOption struct {
+	Label    string `yaml:"label"`
+	Required bool   `yaml:"required,omitempty"`
 }
 
 type templateFieldValidations struct {
 	Required bool `yaml:"required,omitempty"`
 }
 
+type acknowledgementField struct {
+	ID            string
+	Label         string
+	Description   string
+	CheckboxLabel string
+	Enabled       bool
+}
+
+// acknowledgementFields lists the managed checkbox fields in template order. Disabled entries
+// are kept so sync can remove fields that are no longer needed. Labels stay in English because
+// order parsing matches on them; descriptions and checkbox text follow the shop's locale.
+func acknowledgementFields(loc i18n.Localizer, products []ProductConfig, terms TermsConfig) []acknowledgementField {
+	restricted := false
+	minimumAge := 0
+	for _, product := range products {
+		if !product.Restricted {
+			continue
+		}
+		restricted = true
+		minimumAge = max(minimumAge, product.MinimumAge)
+	}
+
+	eligibilityDescription := loc.T("template.eligibility_description")
+	if minimumAge > 0 {
+		eligibilityDescription = loc.T("template.eligibility_minimum_age", minimumAge)
+	}
+
+	return []acknowledgementField{
+		{
+			ID:            EligibilityFieldID,
+			Label:         EligibilityFieldLabel,
+			Description:   eligibilityDescription,
+			CheckboxLabel: loc.T("template.eligibility_checkbox"),
+			Enabled:       restricted,
+		},
+		{
+			ID:            TermsFieldID,
+			Label:         TermsFieldLabel,
+			Description:   loc.T("template.terms_description", strings.TrimSpace(terms.URL), strings.TrimSpace(terms.Version)),
+			CheckboxLabel: loc.T("template.terms_checkbox"),
+			Enabled:       terms.RequireCheckbox,
+		},
+	}
+}
+
+// syncAcknowledgementFields adds, refreshes, or removes the managed checkbox fields so they
+// follow the shop config.
+func syncAcknowledgementFields(bodyNode *yaml.Node, fields []acknowledgementField) {
+	for _, ack := range fields {
+		if !ack.Enabled {
+			removeFieldByID(bodyNode, ack.ID)
+			continue
+		}
+
+		field := ensureFieldByID(bodyNode, ack.ID, "checkboxes")
+		attrs := ensureMappingValue(field, "attributes")
+		setMappingScalar(attrs, "label", ack.Label)
+		setMappingScalar(attrs, "description", ack.Description)
+		option := &yaml.Node{Kind: yaml.MappingNode}
+		setMappingScalar(option, "label", ack.CheckboxLabel)
+		setMappingBool(option, "required", true)
+		setMappingNode(attrs, "options", &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{option}})
+	}
+}
+
+// MaxDropdownOptions is the most options GitHub shows in an issue form dropdown. Templates with
+// more products than this list them in markdown and ask for the SKU instead.
+const MaxDropdownOptions = 25
+
+// productCatalogHeading starts the product list written above the SKU field.
+const productCatalogHeading = "### Products"
+
+func productFields(loc i18n.Localizer, products []ProductConfig) []templateField {
+	products = groupProductsByCategory(products)
+	if len(products) <= MaxDropdownOptions {
+		return []templateField{{
+			Type: "dropdown",
+			ID:   "product",
+			Attributes: templateFieldAttributes{
+				Label:       "Product",
+				Description: loc.T("template.product_description"),
+				Options:     productOptions(products),
+			},
+			Validations: &templateFieldValidations{Required: true},
+		}}
+	}
+	return []templateField{
+		{
+			Type:       "markdown",
+			Attributes: templateFieldAttributes{Value: productCatalogMarkdown(loc, products)},
+		},
+		{
+			Type: "input",
+			ID:   "product",
+			Attributes: templateFieldAttributes{
+				Label:       "Product SKU",
+				Description: loc.T("template.product_sku_description", products[0].SKU),
+			},
+			Validations: &templateFieldValidations{Required: true},
+		},
+	}
+}
+
+// productCatalogMarkdown lists the products under productCatalogHeading, with a section for each
+// category when the products have more than one.
+func productCatalogMarkdown(loc i18n.Localizer, products []ProductConfig) string {
+	products = groupProductsByCategory(products)
+	sections := 0
+	for i, product := range products {
+		if i == 0 || strings.TrimSpace(product.Category) != strings.TrimSpace(products[i-1].Category) {
+			sections++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(productCatalogHeading + "\n")
+	for i, product := range products {
+		category := strings.TrimSpace(product.Category)
+		if sections > 1 && (i == 0 || category != strings.TrimSpace(products[i-1].Category)) {
+			b.WriteString("\n#### " + cmp.Or(category, loc.T("template.category_other")) + "\n")
+		}
+		b.WriteString("- " + productOptionLabel(product) + "\n")
+	}
+	return b.String()
+}
+
+// groupProductsByCategory orders products so each category's products are together, keeping the
+// order categories and products first appear in gitshop.yaml.
+func groupProductsByCategory(products []ProductConfig) []ProductConfig {
+	var categories []string
+	for _, product := range products {
+		if category := strings.TrimSpace(product.Category); !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	grouped := make([]ProductConfig, 0, len(products))
+	for _, category := range categories {
+		for _, product := range products {
+			if strings.TrimSpace(product.Category) == category {
+				grouped = append(grouped, product)
+			}
+		}
+	}
+	return grouped
+}
+
 func productOptions(products []ProductConfig) []string {
 	options := make([]string, 0, len(products))
 	for _, product := range products {
-		options = append(options, fmt.Sprintf("%s — $%.2f (SKU:%s)", product.Name, float64(product.UnitPriceCents)/100, product.SKU))
+		options = append(options, productOptionLabel(product))
 	}
 	return options
 }
 
+func productOptionLabel(product ProductConfig) string {
+	if product.IsInquiry() {
+		return fmt.Sprintf("%s — request a quote (SKU:%s)", product.Name, product.SKU)
+	}
+	if product.Campaign != nil {
+		return fmt.Sprintf("%s — %s, was %s (SKU:%s)", product.Name, money.Format(product.PriceCents()), money.Format(product.UnitPriceCents), product.SKU)
+	}
+	return fmt.Sprintf("%s — %s (SKU:%s)", product.Name, money.Format(product.UnitPriceCents), product.SKU)
+}
+
 func extractProductSKUsFromTemplateBody(bodyNode *yaml.Node) []string {
 	productField := findFieldByID(bodyNode, "product")
 	if productField == nil {
 		return nil
 	}
 	options := getFieldOptions(productField)
+	if len(options) == 0 && getFieldType(productField) == "input" {
+		options = productCatalogLines(findProductCatalogField(bodyNode))
+	}
 	if len(options) == 0 {
 		return nil
 	}
@@ -539,12 +783,61 @@ func extractProductSKUsFromTemplateBody(bodyNode *yaml.Node) []string {
 	return skus
 }
 
-func updateProductFieldOptions(field *yaml.Node, products []ProductConfig) {
-	options := make([]string, 0, len(products))
-	for _, product := range products {
-		options = append(options, fmt.Sprintf("%s — $%.2f (SKU:%s)", product.Name, float64(product.UnitPriceCents)/100, product.SKU))
+// findProductCatalogField returns the markdown field listing the products of a template that asks
+// for the SKU instead of offering a dropdown.
+func findProductCatalogField(bodyNode *yaml.Node) *yaml.Node {
+	if bodyNode == nil || bodyNode.Kind != yaml.SequenceNode {
+		return nil
 	}
-	setFieldOptions(field, options)
+	for _, item := range bodyNode.Content {
+		if getFieldType(item) != "markdown" {
+			continue
+		}
+		attrs := findMappingValue(item, "attributes")
+		if attrs == nil {
+			continue
+		}
+		if value := findMappingValue(attrs, "value"); value != nil && strings.HasPrefix(value.Value, productCatalogHeading) {
+			return item
+		}
+	}
+	return nil
+}
+
+func productCatalogLines(field *yaml.Node) []string {
+	if field == nil {
+		return nil
+	}
+	attrs := findMappingValue(field, "attributes")
+	if attrs == nil {
+		return nil
+	}
+	value := findMappingValue(attrs, "value")
+	if value == nil {
+		return nil
+	}
+	lines := []string{}
+	for _, line := range strings.Split(value.Value, "\n") {
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok {
+			lines = append(lines, item)
+		}
+	}
+	return lines
+}
+
+func getFieldType(field *yaml.Node) string {
+	if field == nil || field.Kind != yaml.MappingNode {
+		return ""
+	}
+	typeNode := findMappingValue(field, "type")
+	if typeNode == nil {
+		return ""
+	}
+	return strings.TrimSpace(typeNode.Value)
+}
+
+func updateProductFieldOptions(field *yaml.Node, products []ProductConfig) {
+	setFieldOptions(field, productOptions(products))
 }
 
 func findFieldByID(bodyNode *yaml.Node, id string) *yaml.Node {
@@ -563,6 +856,20 @@ func findFieldByID(bodyNode *yaml.Node, id string) *yaml.Node {
 	return nil
 }
 
+func removeFieldByID(bodyNode *yaml.Node, id string) {
+	if bodyNode == nil || bodyNode.Kind != yaml.SequenceNode {
+		return
+	}
+	kept := bodyNode.Content[:0]
+	for _, item := range bodyNode.Content {
+		if getFieldID(item) == id {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	bodyNode.Content = kept
+}
+
 func getFieldID(field *yaml.Node) string {
 	if field == nil || field.Kind != yaml.MappingNode {
 		return ""
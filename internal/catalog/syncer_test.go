@@ -1,10 +1,14 @@
 package catalog
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/gitshopapp/gitshop/internal/i18n"
 )
 
 func TestSyncTemplateContent_PreservesCustomFieldsAndNormalizesManagedOptions(t *testing.T) {
@@ -240,6 +244,168 @@ func TestBuildTemplateContent_GeneratesYAML(t *testing.T) {
 	}
 }
 
+func TestBuildTemplateContent_AsksForSKUWhenCatalogIsTooLargeForDropdown(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTemplateSyncer(nil)
+	config := &GitShopConfig{}
+	for i := range MaxDropdownOptions + 1 {
+		config.Products = append(config.Products, ProductConfig{
+			SKU:            fmt.Sprintf("ITEM_%d", i),
+			Name:           fmt.Sprintf("Item %d", i),
+			UnitPriceCents: 1000,
+			Active:         true,
+		})
+	}
+
+	template, err := syncer.BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(template, "label: Product SKU") {
+		t.Fatalf("expected product SKU input, got:\n%s", template)
+	}
+	if report := CheckOrderTemplate(template, config); !report.Valid() || len(report.SKUs) != MaxDropdownOptions+1 {
+		t.Fatalf("expected template listing every SKU to validate, got %+v", report)
+	}
+
+	config.Products[0].UnitPriceCents = 1200
+	synced, err := syncer.SyncTemplateContent(template, config)
+	if err != nil {
+		t.Fatalf("SyncTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(synced, "Item 0 — $12.00 (SKU:ITEM_0)") {
+		t.Fatalf("expected synced product list to carry the new price, got:\n%s", synced)
+	}
+	if !strings.Contains(synced, "label: Product SKU") || strings.Contains(synced, "Select the product") {
+		t.Fatalf("expected sync to keep the SKU input, got:\n%s", synced)
+	}
+}
+
+func TestBuildTemplateContent_UsesShopLocale(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTemplateSyncer(nil)
+	config := &GitShopConfig{
+		Shop: ShopConfig{
+			Locale: "de",
+			Terms:  TermsConfig{URL: "https://example.com/terms", Version: "2026-01", RequireCheckbox: true},
+		},
+		Products: []ProductConfig{
+			{SKU: "MUG_V1", Name: "Mug", UnitPriceCents: 1200, Active: true},
+		},
+	}
+
+	template, err := syncer.BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	for _, want := range []string{
+		"description: Produkte aus unserem Shop bestellen",
+		"Wählen Sie das Produkt aus, das Sie bestellen möchten",
+		"label: Ich stimme den Verkaufsbedingungen zu",
+		"label: Product",
+		"label: Quantity",
+		"label: " + TermsFieldLabel,
+	} {
+		if !strings.Contains(template, want) {
+			t.Fatalf("expected %q in generated template:\n%s", want, template)
+		}
+	}
+}
+
+func TestProductCatalogMarkdown_GroupsByCategory(t *testing.T) {
+	t.Parallel()
+
+	products := []ProductConfig{
+		{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2500, Category: "Apparel"},
+		{SKU: "MUG", Name: "Mug", UnitPriceCents: 1500},
+		{SKU: "HOODIE", Name: "Hoodie", UnitPriceCents: 5000, Category: "Apparel"},
+	}
+	want := "### Products\n" +
+		"\n#### Apparel\n" +
+		"- T-Shirt — $25.00 (SKU:TSHIRT)\n" +
+		"- Hoodie — $50.00 (SKU:HOODIE)\n" +
+		"\n#### Other\n" +
+		"- Mug — $15.00 (SKU:MUG)\n"
+	if got := productCatalogMarkdown(i18n.Localizer{}, products); got != want {
+		t.Fatalf("productCatalogMarkdown() = %q, want %q", got, want)
+	}
+	if got := productCatalogMarkdown(i18n.Localizer{}, products[:1]); got != "### Products\n- T-Shirt — $25.00 (SKU:TSHIRT)\n" {
+		t.Fatalf("expected no section headers for one category, got %q", got)
+	}
+}
+
+func TestQuantityOptionValues(t *testing.T) {
+	t.Parallel()
+
+	configured := []ProductOption{{Name: "quantity", Label: "Quantity", Type: "dropdown", Values: []string{"1", "6", "12", "24"}}}
+	tests := []struct {
+		name     string
+		shop     ShopConfig
+		products []ProductConfig
+		want     []string
+	}{
+		{name: "no limit set", products: []ProductConfig{{SKU: "MUG"}}, want: []string{"1", "2", "3", "4", "5"}},
+		{name: "shop limit", shop: ShopConfig{MaxQuantity: 3}, products: []ProductConfig{{SKU: "MUG"}}, want: []string{"1", "2", "3"}},
+		{name: "largest product limit", shop: ShopConfig{MaxQuantity: 2}, products: []ProductConfig{{SKU: "MUG"}, {SKU: "CUP", MaxQuantity: 4}}, want: []string{"1", "2", "3", "4"}},
+		{name: "configured values within default limit", products: []ProductConfig{{SKU: "MUG", Options: configured}}, want: []string{"1", "6"}},
+		{name: "configured values within product limit", products: []ProductConfig{{SKU: "MUG", Options: configured, MaxQuantity: 24}}, want: []string{"1", "6", "12", "24"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := quantityOptionValues(tt.products, tt.shop); !slices.Equal(got, tt.want) {
+				t.Errorf("quantityOptionValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateAcknowledgementFields(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTemplateSyncer(nil)
+	config := &GitShopConfig{
+		Shop: ShopConfig{
+			Terms: TermsConfig{URL: "https://example.com/terms", Version: "2026-01", RequireCheckbox: true},
+		},
+		Products: []ProductConfig{
+			{SKU: "MUG_V1", Name: "Mug", UnitPriceCents: 1200, Active: true},
+		},
+	}
+
+	template, err := syncer.BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(template, "id: terms") || !strings.Contains(template, "label: I agree to the terms of sale") {
+		t.Fatalf("expected terms checkbox in generated template:\n%s", template)
+	}
+	if !strings.Contains(template, "version 2026-01") {
+		t.Fatalf("expected terms version in generated template:\n%s", template)
+	}
+	if strings.Contains(template, "id: "+EligibilityFieldID) {
+		t.Fatalf("expected no eligibility checkbox without restricted products:\n%s", template)
+	}
+
+	config.Shop.Terms = TermsConfig{}
+	config.Products[0].Restricted = true
+	config.Products[0].MinimumAge = 21
+	synced, err := syncer.SyncTemplateContent(template, config)
+	if err != nil {
+		t.Fatalf("SyncTemplateContent returned error: %v", err)
+	}
+	if strings.Contains(synced, "id: terms") {
+		t.Fatalf("expected terms checkbox to be removed once disabled:\n%s", synced)
+	}
+	if !strings.Contains(synced, "id: "+EligibilityFieldID) || !strings.Contains(synced, "at least 21 years old") {
+		t.Fatalf("expected eligibility checkbox for restricted product:\n%s", synced)
+	}
+}
+
 func TestExtractProductSKUsFromTemplateBody_AllowsLowercase(t *testing.T) {
 	t.Parallel()
 
@@ -262,3 +428,77 @@ func TestExtractProductSKUsFromTemplateBody_AllowsLowercase(t *testing.T) {
 		t.Fatalf("unexpected skus: %v", skus)
 	}
 }
+
+func TestTemplateEmailFields(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTemplateSyncer(nil)
+	config := &GitShopConfig{
+		Shop: ShopConfig{Checkout: CheckoutConfig{CollectEmail: true}},
+		Products: []ProductConfig{
+			{SKU: "MUG_V1", Name: "Mug", UnitPriceCents: 1200, Active: true},
+		},
+	}
+
+	template, err := syncer.BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(template, "id: "+EmailFieldID) || !strings.Contains(template, "id: "+ReminderOptOutFieldID) {
+		t.Fatalf("expected email fields in generated template:\n%s", template)
+	}
+
+	config.Shop.Checkout.CollectEmail = false
+	synced, err := syncer.SyncTemplateContent(template, config)
+	if err != nil {
+		t.Fatalf("SyncTemplateContent returned error: %v", err)
+	}
+	if strings.Contains(synced, "id: "+EmailFieldID) || strings.Contains(synced, "id: "+ReminderOptOutFieldID) {
+		t.Fatalf("expected email fields to be removed once disabled:\n%s", synced)
+	}
+
+	config.Shop.Checkout.CollectEmail = true
+	synced, err = syncer.SyncTemplateContent(synced, config)
+	if err != nil {
+		t.Fatalf("SyncTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(synced, "label: "+EmailFieldLabel) || !strings.Contains(synced, "Don't email me a reminder") {
+		t.Fatalf("expected email fields to be added back:\n%s", synced)
+	}
+}
+
+func TestTemplateCurrencyField(t *testing.T) {
+	t.Parallel()
+
+	syncer := NewTemplateSyncer(nil)
+	config := &GitShopConfig{
+		Shop: ShopConfig{Currency: "usd", Checkout: CheckoutConfig{EstimateCurrencies: []string{"eur", "jpy"}}},
+		Products: []ProductConfig{
+			{SKU: "MUG_V1", Name: "Mug", UnitPriceCents: 1200, Active: true},
+		},
+	}
+
+	template, err := syncer.BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(template, "id: "+CurrencyFieldID) || !strings.Contains(template, "- JPY") {
+		t.Fatalf("expected currency dropdown in generated template:\n%s", template)
+	}
+
+	config.Shop.Checkout.EstimateCurrencies = nil
+	synced, err := syncer.SyncTemplateContent(template, config)
+	if err != nil {
+		t.Fatalf("SyncTemplateContent returned error: %v", err)
+	}
+	if strings.Contains(synced, "id: "+CurrencyFieldID) {
+		t.Fatalf("expected currency dropdown to be removed once disabled:\n%s", synced)
+	}
+
+	if got := (CheckoutConfig{EstimateCurrencies: []string{"eur"}}).EstimateCurrency(" EUR "); got != "eur" {
+		t.Fatalf("EstimateCurrency(EUR) = %q, want eur", got)
+	}
+	if got := (CheckoutConfig{EstimateCurrencies: []string{"eur"}}).EstimateCurrency("GBP"); got != "" {
+		t.Fatalf("EstimateCurrency(GBP) = %q, want empty", got)
+	}
+}
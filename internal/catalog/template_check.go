@@ -0,0 +1,454 @@
+package catalog
+
+// Order template checks shared by the dashboard and the gitshop CLI.
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gitshopapp/gitshop/internal/money"
+)
+
+// TemplateReport is the result of checking one order issue template against gitshop.yaml.
+type TemplateReport struct {
+	// Label is the order label the template must apply to new issues.
+	Label            string
+	HasLabel         bool
+	SKUs             []string
+	UnknownSKUs      []string
+	OptionMismatches []string
+	PriceMismatches  []string
+	// Warnings describe problems that do not stop orders from matching gitshop.yaml but still
+	// break the form on GitHub.
+	Warnings []string
+}
+
+func (r TemplateReport) Valid() bool {
+	return r.HasLabel && len(r.SKUs) > 0 && len(r.UnknownSKUs) == 0 && len(r.OptionMismatches) == 0 && len(r.PriceMismatches) == 0
+}
+
+// Problems describes what to change in the template, one fix per line, followed by the warnings.
+func (r TemplateReport) Problems() []string {
+	problems := []string{}
+	if !r.HasLabel {
+		problems = append(problems, fmt.Sprintf("add %q to the template's labels", r.Label))
+	}
+	if len(r.SKUs) == 0 {
+		problems = append(problems, "list at least one product as an option ending in (SKU:YOUR_SKU)")
+	}
+	for _, sku := range r.UnknownSKUs {
+		problems = append(problems, fmt.Sprintf("SKU %s is not in gitshop.yaml; add the product or remove it from the template", sku))
+	}
+	for _, mismatch := range r.PriceMismatches {
+		problems = append(problems, "price differs from gitshop.yaml (template vs yaml): "+mismatch)
+	}
+	problems = append(problems, r.OptionMismatches...)
+	problems = append(problems, r.Warnings...)
+	return problems
+}
+
+// CheckOrderTemplate compares an order template with a valid config.
+func CheckOrderTemplate(template string, config *GitShopConfig) TemplateReport {
+	label := config.Labels.Name(LabelOrder)
+	report := TemplateReport{Label: label, HasLabel: TemplateHasLabel(template, label)}
+
+	yamlSKUs := make(map[string]struct{}, len(config.Products))
+	for _, product := range config.Products {
+		yamlSKUs[product.SKU] = struct{}{}
+	}
+	for sku := range FindTemplateSKUs(template) {
+		report.SKUs = append(report.SKUs, sku)
+		if _, ok := yamlSKUs[sku]; !ok {
+			report.UnknownSKUs = append(report.UnknownSKUs, sku)
+		}
+	}
+	sort.Strings(report.SKUs)
+	sort.Strings(report.UnknownSKUs)
+
+	report.OptionMismatches = FindTemplateOptionMismatches(template, config)
+	report.PriceMismatches = FindTemplatePriceMismatches(template, config)
+	report.Warnings = FindTemplateWarnings(template)
+	return report
+}
+
+// FindTemplateWarnings lists problems GitHub does not report when it renders the form, such as a
+// product dropdown with more options than it shows.
+func FindTemplateWarnings(template string) []string {
+	warnings := []string{}
+	form := checkedTemplate{}
+	if err := yaml.Unmarshal([]byte(template), &form); err != nil {
+		return warnings
+	}
+	for _, field := range form.Body {
+		if field.ID != "product" || field.Type != "dropdown" {
+			continue
+		}
+		if count := len(field.Attributes.Options); count > MaxDropdownOptions {
+			warnings = append(warnings, fmt.Sprintf("product dropdown has %d options but GitHub shows at most %d; regenerate the template to split it by category or ask for the SKU", count, MaxDropdownOptions))
+		}
+	}
+	return warnings
+}
+
+// HasOrderTemplateMarker reports whether an issue template starts with the GitShop order marker.
+func HasOrderTemplateMarker(template string) bool {
+	for _, line := range strings.Split(template, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		return trimmed == "# gitshop:order-template"
+	}
+	return false
+}
+
+func FindTemplateSKUs(template string) map[string]struct{} {
+	skus := make(map[string]struct{})
+	skuRegex := regexp.MustCompile(`(?i)SKU:([A-Z0-9_]+)`)
+	for _, line := range strings.Split(template, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		if !strings.Contains(trimmed, "SKU:") {
+			continue
+		}
+		if match := skuRegex.FindStringSubmatch(trimmed); len(match) >= 2 {
+			skus[match[1]] = struct{}{}
+		}
+	}
+	return skus
+}
+
+// FindTemplateProductLabels maps each SKU listed in the template to the text of its product
+// option, which is also the value that pre-fills the product field of a new issue.
+func FindTemplateProductLabels(template string) map[string]string {
+	labels := make(map[string]string)
+	skuRegex := regexp.MustCompile(`(?i)SKU:([A-Z0-9_]+)`)
+	for _, line := range strings.Split(template, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "-") || !strings.Contains(trimmed, "SKU:") {
+			continue
+		}
+		match := skuRegex.FindStringSubmatch(trimmed)
+		if len(match) < 2 {
+			continue
+		}
+		if _, seen := labels[match[1]]; seen {
+			continue
+		}
+		label := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+		if unquoted, err := strconv.Unquote(label); err == nil {
+			label = unquoted
+		}
+		labels[match[1]] = strings.Trim(label, "'")
+	}
+	return labels
+}
+
+// FindTemplatePriceMismatches lists products whose price in the template differs from gitshop.yaml.
+func FindTemplatePriceMismatches(template string, config *GitShopConfig) []string {
+	mismatches := []string{}
+	if config == nil {
+		return mismatches
+	}
+
+	productPrices := make(map[string]int64)
+	for _, product := range config.Products {
+		productPrices[product.SKU] = product.PriceCents()
+	}
+
+	skuRegex := regexp.MustCompile(`(?i)SKU:([A-Z0-9_]+)`)
+	priceRegex := regexp.MustCompile(`\$([0-9]+(?:\.[0-9]{2})?)`)
+	for _, line := range strings.Split(template, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "-") || !strings.Contains(trimmed, "SKU:") || !strings.Contains(trimmed, "$") {
+			continue
+		}
+		skuMatch := skuRegex.FindStringSubmatch(trimmed)
+		priceMatch := priceRegex.FindStringSubmatch(trimmed)
+		if len(skuMatch) < 2 || len(priceMatch) < 2 {
+			continue
+		}
+		sku := skuMatch[1]
+		templateCents, err := money.Parse(priceMatch[1])
+		if err != nil {
+			continue
+		}
+		if yamlCents, ok := productPrices[sku]; ok && yamlCents != templateCents {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s vs %s)", sku, money.Format(templateCents), money.Format(yamlCents)))
+		}
+	}
+
+	return append(mismatches, findTemplateSurchargeMismatches(template, config)...)
+}
+
+// findTemplateSurchargeMismatches lists option values whose surcharge in the template differs
+// from gitshop.yaml.
+func findTemplateSurchargeMismatches(template string, config *GitShopConfig) []string {
+	products := activeTemplateProducts(config, FindTemplateSKUs(template))
+	if len(products) == 0 {
+		return nil
+	}
+	form := checkedTemplate{}
+	if err := yaml.Unmarshal([]byte(template), &form); err != nil {
+		return nil
+	}
+	fields := make(map[string]checkedTemplateAttributes, len(form.Body))
+	for _, field := range form.Body {
+		if _, exists := fields[field.ID]; field.ID != "" && !exists {
+			fields[field.ID] = field.Attributes
+		}
+	}
+
+	mismatches := []string{}
+	for _, option := range products[0].Options {
+		field, ok := fields[option.Name]
+		if !ok || option.Type != "dropdown" || option.Name == "quantity" {
+			continue
+		}
+		for _, label := range filterTemplateOptionValues(anyValuesToStrings(field.Options)) {
+			value, templateCents := parseOptionValueLabel(label)
+			if !slices.Contains(option.Values, value) {
+				continue
+			}
+			if yamlCents := option.Surcharge(value); templateCents != yamlCents {
+				mismatches = append(mismatches, fmt.Sprintf("%s %s (+%s vs +%s)", option.Name, value, money.Format(templateCents), money.Format(yamlCents)))
+			}
+		}
+	}
+	return mismatches
+}
+
+type checkedTemplate struct {
+	Labels []string               `yaml:"labels"`
+	Body   []checkedTemplateField `yaml:"body"`
+}
+
+type checkedTemplateField struct {
+	Type        string                    `yaml:"type"`
+	ID          string                    `yaml:"id"`
+	Attributes  checkedTemplateAttributes `yaml:"attributes"`
+	Validations templateFieldValidations  `yaml:"validations"`
+}
+
+type checkedTemplateAttributes struct {
+	Label   string `yaml:"label"`
+	Options []any  `yaml:"options"`
+}
+
+// FindTemplateOptionMismatches lists option fields in the template that disagree with the options
+// of the products it sells.
+func FindTemplateOptionMismatches(template string, config *GitShopConfig) []string {
+	mismatches := []string{}
+	if config == nil {
+		return mismatches
+	}
+
+	form := checkedTemplate{}
+	if err := yaml.Unmarshal([]byte(template), &form); err != nil {
+		return mismatches
+	}
+
+	templateOptions := make(map[string]checkedTemplateAttributes)
+	requiredFields := make(map[string]bool)
+	duplicateIDs := make(map[string]struct{})
+	for _, field := range form.Body {
+		if field.ID == "" {
+			continue
+		}
+		if _, exists := templateOptions[field.ID]; exists {
+			if _, reported := duplicateIDs[field.ID]; !reported {
+				mismatches = append(mismatches, fmt.Sprintf("duplicate option field id: %s", field.ID))
+				duplicateIDs[field.ID] = struct{}{}
+			}
+			continue
+		}
+		templateOptions[field.ID] = field.Attributes
+		requiredFields[field.ID] = field.Validations.Required
+	}
+
+	templateSKUs := FindTemplateSKUs(template)
+	selectedProducts := activeTemplateProducts(config, templateSKUs)
+	if len(selectedProducts) == 0 {
+		return mismatches
+	}
+
+	baseProduct := selectedProducts[0]
+	baseOptions := normalizedOptionsForTemplate(baseProduct)
+	for _, product := range selectedProducts[1:] {
+		if !templateOptionDefsEqual(baseOptions, normalizedOptionsForTemplate(product)) {
+			mismatches = append(mismatches, "template includes products with different option sets; split these products into separate order templates")
+			return mismatches
+		}
+	}
+
+	quantityValues := quantityOptionValues(selectedProducts, config.Shop)
+	templateQuantity, hasQuantity := templateOptions["quantity"]
+	if !hasQuantity {
+		mismatches = append(mismatches, "missing option: quantity")
+	} else {
+		templateValues := filterTemplateOptionValues(anyValuesToStrings(templateQuantity.Options))
+		if len(templateValues) > 0 && !stringSlicesEqual(quantityValues, templateValues) {
+			mismatches = append(mismatches, fmt.Sprintf("values mismatch for quantity (template: %s, yaml: %s)", strings.Join(templateValues, ", "), strings.Join(quantityValues, ", ")))
+		}
+	}
+
+	for _, option := range baseProduct.Options {
+		if option.Name == "quantity" {
+			continue
+		}
+		templateAttr, ok := templateOptions[option.Name]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("missing option: %s", option.Name))
+			continue
+		}
+
+		if option.Label != "" && templateAttr.Label != "" && option.Label != templateAttr.Label {
+			mismatches = append(mismatches, fmt.Sprintf("label mismatch for %s (%s vs %s)", option.Name, templateAttr.Label, option.Label))
+		}
+
+		if option.When != nil && requiredFields[option.Name] {
+			mismatches = append(mismatches, fmt.Sprintf("%s only applies when %s is %s, so it must be optional in the template", option.Name, option.When.Option, option.When.Value))
+		}
+
+		if option.Type == "dropdown" {
+			yamlValues := anyValuesToStrings(option.Values)
+			templateValues := templateOptionValues(templateAttr.Options)
+			if !stringSlicesEqual(yamlValues, templateValues) {
+				mismatches = append(mismatches, fmt.Sprintf("values mismatch for %s (template: %s, yaml: %s)", option.Name, strings.Join(templateValues, ", "), strings.Join(yamlValues, ", ")))
+			}
+		}
+	}
+
+	return mismatches
+}
+
+func activeTemplateProducts(config *GitShopConfig, templateSKUs map[string]struct{}) []ProductConfig {
+	if config == nil || len(templateSKUs) == 0 {
+		return nil
+	}
+	selected := []ProductConfig{}
+	for _, product := range config.Products {
+		if !product.Active {
+			continue
+		}
+		if _, ok := templateSKUs[product.SKU]; ok {
+			selected = append(selected, product)
+		}
+	}
+	return selected
+}
+
+func normalizedOptionsForTemplate(product ProductConfig) []ProductOption {
+	options := []ProductOption{}
+	for _, option := range product.Options {
+		if option.Name == "quantity" {
+			continue
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+func templateOptionDefsEqual(a, b []ProductOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx].Name != b[idx].Name || a[idx].Label != b[idx].Label || a[idx].Type != b[idx].Type || a[idx].Required != b[idx].Required {
+			return false
+		}
+		if !sameCondition(a[idx].When, b[idx].When) {
+			return false
+		}
+		if !stringSlicesEqual(a[idx].TemplateValues(), b[idx].TemplateValues()) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyValuesToStrings(values any) []string {
+	converted := []string{}
+	switch v := values.(type) {
+	case []any:
+		for _, value := range v {
+			converted = append(converted, fmt.Sprintf("%v", value))
+		}
+	case []string:
+		converted = append(converted, v...)
+	}
+	return converted
+}
+
+// templateOptionValues lists the values of a template dropdown without their surcharges.
+func templateOptionValues(options []any) []string {
+	values := filterTemplateOptionValues(anyValuesToStrings(options))
+	for idx, value := range values {
+		values[idx] = OptionValueFromLabel(value)
+	}
+	return values
+}
+
+func filterTemplateOptionValues(values []string) []string {
+	filtered := make([]string, 0, len(values))
+	for _, value := range values {
+		cleaned := strings.TrimSpace(value)
+		if strings.EqualFold(cleaned, "N/A") || strings.EqualFold(cleaned, "None") || strings.EqualFold(cleaned, "Select one") {
+			continue
+		}
+		filtered = append(filtered, cleaned)
+	}
+	return filtered
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, value := range a {
+		counts[value]++
+	}
+	for _, value := range b {
+		if counts[value] == 0 {
+			return false
+		}
+		counts[value]--
+	}
+	return true
+}
+
+func TemplateHasLabel(template, label string) bool {
+	form := checkedTemplate{}
+	if err := yaml.Unmarshal([]byte(template), &form); err == nil {
+		for _, value := range form.Labels {
+			trimmed := strings.TrimSpace(value)
+			if trimmed == label {
+				return true
+			}
+		}
+		return false
+	}
+
+	re := regexp.MustCompile(`labels:\s*\[([^\]]+)\]`)
+	match := re.FindStringSubmatch(template)
+	if len(match) < 2 {
+		return false
+	}
+	values := strings.Split(match[1], ",")
+	for _, value := range values {
+		trimmed := strings.TrimSpace(strings.Trim(value, "\"'"))
+		if trimmed == label {
+			return true
+		}
+	}
+	return false
+}
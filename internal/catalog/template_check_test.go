@@ -0,0 +1,351 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFindTemplatePriceMismatches(t *testing.T) {
+	t.Parallel()
+
+	config := &GitShopConfig{
+		Products: []ProductConfig{
+			{
+				SKU:            "COFFEE_BEANS",
+				Name:           "Coffee Beans",
+				UnitPriceCents: 2000,
+				Active:         true,
+			},
+		},
+	}
+
+	template := `
+body:
+  - type: dropdown
+    id: product
+    attributes:
+      options:
+        - "Coffee Beans - $19.00 (SKU:COFFEE_BEANS)"
+`
+
+	mismatches := FindTemplatePriceMismatches(template, config)
+	if len(mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %d (%v)", len(mismatches), mismatches)
+	}
+	if !strings.Contains(mismatches[0], "COFFEE_BEANS") {
+		t.Fatalf("expected mismatch to include SKU, got %q", mismatches[0])
+	}
+}
+
+func TestOrderTemplateOptionSurcharges(t *testing.T) {
+	t.Parallel()
+
+	config := &GitShopConfig{
+		Products: []ProductConfig{
+			{
+				SKU:            "TSHIRT",
+				Name:           "T-Shirt",
+				UnitPriceCents: 2500,
+				Active:         true,
+				Options: []ProductOption{
+					{Name: "size", Label: "Size", Type: "dropdown", Required: true, Values: []string{"M", "XXL"}, ExtraCents: map[string]int64{"XXL": 500}},
+				},
+			},
+		},
+	}
+
+	template, err := NewTemplateSyncer(nil).BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	if !strings.Contains(template, "XXL (+$5.00)") {
+		t.Fatalf("expected surcharge in generated template:\n%s", template)
+	}
+	if report := CheckOrderTemplate(template, config); !report.Valid() {
+		t.Fatalf("expected generated template to match gitshop.yaml, got %+v", report)
+	}
+
+	config.Products[0].Options[0].ExtraCents["XXL"] = 700
+	report := CheckOrderTemplate(template, config)
+	if len(report.OptionMismatches) != 0 {
+		t.Fatalf("expected a surcharge change to leave option values matching, got %v", report.OptionMismatches)
+	}
+	if want := []string{"size XXL (+$5.00 vs +$7.00)"}; fmt.Sprint(report.PriceMismatches) != fmt.Sprint(want) {
+		t.Fatalf("PriceMismatches = %v, want %v", report.PriceMismatches, want)
+	}
+}
+
+func TestFindTemplateSKUs_AllowsLowercase(t *testing.T) {
+	t.Parallel()
+
+	template := `
+body:
+  - type: dropdown
+    id: product
+    attributes:
+      options:
+        - "GitShop Blend v2 — $16.00 (SKU:GITSHOP_BLEND_v1)"
+`
+
+	skus := FindTemplateSKUs(template)
+	if _, ok := skus["GITSHOP_BLEND_v1"]; !ok {
+		t.Fatalf("expected lowercase SKU suffix to be preserved, got %v", skus)
+	}
+}
+
+func TestFindTemplateOptionMismatches_DuplicateFieldID(t *testing.T) {
+	t.Parallel()
+
+	config := &GitShopConfig{
+		Products: []ProductConfig{
+			{
+				SKU:            "COFFEE_BLEND_V1",
+				Name:           "Coffee Blend V1",
+				UnitPriceCents: 1600,
+				Active:         true,
+				Options: []ProductOption{
+					{
+						Name:     "grind",
+						Label:    "Grind",
+						Type:     "dropdown",
+						Required: true,
+						Values:   []string{"Ground", "Whole Bean"},
+					},
+				},
+			},
+		},
+	}
+
+	template := `
+body:
+  - type: dropdown
+    id: product
+    attributes:
+      options:
+        - "Coffee Blend V1 — $16.00 (SKU:COFFEE_BLEND_V1)"
+  - type: dropdown
+    id: quantity
+    attributes:
+      options:
+        - "1"
+        - "2"
+        - "3"
+        - "4"
+        - "5"
+  - type: dropdown
+    id: grind
+    attributes:
+      label: Grind
+      options:
+        - Ground
+        - Whole Bean
+  - type: dropdown
+    id: grind
+    attributes:
+      label: Grind
+      options:
+        - Ground
+        - Whole Bean
+`
+
+	mismatches := FindTemplateOptionMismatches(template, config)
+	if len(mismatches) == 0 {
+		t.Fatalf("expected mismatches, got none")
+	}
+
+	found := false
+	for _, mismatch := range mismatches {
+		if strings.Contains(mismatch, "duplicate option field id: grind") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("expected duplicate field mismatch, got %v", mismatches)
+	}
+}
+
+func TestFindTemplateOptionMismatches_AllowsAdditionalTemplateFields(t *testing.T) {
+	t.Parallel()
+
+	config := &GitShopConfig{
+		Products: []ProductConfig{
+			{
+				SKU:            "COFFEE_BLEND_V1",
+				Name:           "Coffee Blend V1",
+				UnitPriceCents: 1600,
+				Active:         true,
+				Options: []ProductOption{
+					{
+						Name:     "grind",
+						Label:    "Grind",
+						Type:     "dropdown",
+						Required: true,
+						Values:   []string{"Ground", "Whole Bean"},
+					},
+				},
+			},
+		},
+	}
+
+	template := `
+body:
+  - type: dropdown
+    id: product
+    attributes:
+      options:
+        - "Coffee Blend V1 — $16.00 (SKU:COFFEE_BLEND_V1)"
+  - type: dropdown
+    id: quantity
+    attributes:
+      options:
+        - "1"
+        - "2"
+        - "3"
+        - "4"
+        - "5"
+  - type: dropdown
+    id: grind
+    attributes:
+      label: Grind
+      options:
+        - Ground
+        - Whole Bean
+  - type: textarea
+    id: order_notes
+    attributes:
+      label: Notes
+`
+
+	mismatches := FindTemplateOptionMismatches(template, config)
+	for _, mismatch := range mismatches {
+		if strings.Contains(mismatch, "unexpected option") {
+			t.Fatalf("did not expect unexpected-option mismatch, got %v", mismatches)
+		}
+	}
+}
+
+func TestFindTemplateProductLabels(t *testing.T) {
+	t.Parallel()
+
+	template := `body:
+  - type: dropdown
+    id: product
+    attributes:
+      options:
+        - "T-Shirt — $25.00 (SKU:TSHIRT)"
+        - Mug — $15.00 (SKU:MUG)
+`
+	labels := FindTemplateProductLabels(template)
+	if labels["TSHIRT"] != "T-Shirt — $25.00 (SKU:TSHIRT)" || labels["MUG"] != "Mug — $15.00 (SKU:MUG)" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+}
+
+func TestFindTemplateWarnings(t *testing.T) {
+	t.Parallel()
+
+	dropdown := func(count int) string {
+		var b strings.Builder
+		b.WriteString("body:\n  - type: dropdown\n    id: product\n    attributes:\n      label: Product\n      options:\n")
+		for i := range count {
+			fmt.Fprintf(&b, "        - \"Item %d (SKU:ITEM_%d)\"\n", i, i)
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     int
+	}{
+		{name: "dropdown within limit", template: dropdown(MaxDropdownOptions)},
+		{name: "dropdown over limit", template: dropdown(MaxDropdownOptions + 1), want: 1},
+		{name: "invalid yaml", template: "body: ["},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := FindTemplateWarnings(tt.template); len(got) != tt.want {
+				t.Fatalf("FindTemplateWarnings() = %v, want %d warnings", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateHasLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		label    string
+		want     bool
+	}{
+		{
+			name: "inline label list",
+			template: `
+# gitshop:order-template
+labels: ["gitshop:order", "gitshop:status:pending-payment"]
+`,
+			label: "gitshop:order",
+			want:  true,
+		},
+		{
+			name: "block label list",
+			template: `
+# gitshop:order-template
+labels:
+  - gitshop:order
+  - gitshop:status:pending-payment
+`,
+			label: "gitshop:order",
+			want:  true,
+		},
+		{
+			name: "label missing",
+			template: `
+# gitshop:order-template
+labels:
+  - gitshop:status:pending-payment
+`,
+			label: "gitshop:order",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := TemplateHasLabel(tt.template, tt.label)
+			if got != tt.want {
+				t.Fatalf("TemplateHasLabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindTemplatePriceMismatches_CampaignSalePrice(t *testing.T) {
+	t.Parallel()
+
+	campaign := &CampaignConfig{Name: "Spring Sale", PercentOff: 10}
+	product := ProductConfig{SKU: "COFFEE_BEANS", Name: "Coffee Beans", UnitPriceCents: 2000, Active: true, Campaign: campaign}
+	config := &GitShopConfig{Products: []ProductConfig{product}}
+
+	label := productOptionLabel(product)
+	if label != "Coffee Beans — $18.00, was $20.00 (SKU:COFFEE_BEANS)" {
+		t.Fatalf("productOptionLabel() = %q", label)
+	}
+	template := "body:\n  - type: dropdown\n    id: product\n    attributes:\n      options:\n        - \"" + label + "\"\n"
+	if mismatches := FindTemplatePriceMismatches(template, config); len(mismatches) != 0 {
+		t.Fatalf("expected sale price template to match, got %v", mismatches)
+	}
+
+	config.Products[0].Campaign = nil
+	if mismatches := FindTemplatePriceMismatches(template, config); len(mismatches) != 1 {
+		t.Fatalf("expected a mismatch once the campaign ends, got %v", mismatches)
+	}
+}
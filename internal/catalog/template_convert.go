@@ -0,0 +1,175 @@
+package catalog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+// orderTemplateHints are words in an issue form's name, title, labels, or field labels that
+// suggest it already collects orders or requests for things the repository sells.
+var orderTemplateHints = []string{"order", "buy", "purchase", "quote", "commission", "preorder", "pre-order", "merch", "shop", "quantity", "size", "shipping", "address"}
+
+// IssueTemplateAnalysis describes an existing issue template that is not an order template yet.
+type IssueTemplateAnalysis struct {
+	Name string
+	// Convertible is true for issue forms, which can gain a product dropdown. Markdown templates
+	// and the template chooser config cannot.
+	Convertible bool
+	// Suggested is true when the form looks like it already takes orders or requests.
+	Suggested bool
+	// Reason explains why the template is suggested or cannot be converted.
+	Reason string
+}
+
+type analyzedTemplate struct {
+	Name   string          `yaml:"name"`
+	Title  string          `yaml:"title"`
+	Labels yaml.Node       `yaml:"labels"`
+	Body   []analyzedField `yaml:"body"`
+}
+
+type analyzedField struct {
+	Type       string `yaml:"type"`
+	ID         string `yaml:"id"`
+	Attributes struct {
+		Label string `yaml:"label"`
+	} `yaml:"attributes"`
+}
+
+// AnalyzeIssueTemplate reports whether an existing issue template could become an order template.
+func AnalyzeIssueTemplate(content string) IssueTemplateAnalysis {
+	if HasOrderTemplateMarker(content) {
+		return IssueTemplateAnalysis{Reason: "Already a GitShop order template."}
+	}
+
+	form := analyzedTemplate{}
+	if err := yaml.Unmarshal([]byte(content), &form); err != nil {
+		return IssueTemplateAnalysis{Reason: "Not valid YAML, so it cannot be converted."}
+	}
+	analysis := IssueTemplateAnalysis{Name: form.Name}
+	if len(form.Body) == 0 {
+		analysis.Reason = "Not an issue form, so it cannot be converted."
+		return analysis
+	}
+	analysis.Convertible = true
+
+	words := append([]string{form.Name, form.Title}, templateLabels(&form.Labels)...)
+	for _, field := range form.Body {
+		words = append(words, field.ID, field.Attributes.Label)
+	}
+	haystack := strings.ToLower(strings.Join(words, " "))
+	for _, hint := range orderTemplateHints {
+		if strings.Contains(haystack, hint) {
+			analysis.Suggested = true
+			analysis.Reason = fmt.Sprintf("Mentions %q, so it may already collect orders.", hint)
+			return analysis
+		}
+	}
+	analysis.Reason = "An issue form that can take orders once it lists your products."
+	return analysis
+}
+
+// ConvertIssueTemplate turns an existing issue form into an order template: it adds the order
+// marker and labels, a product field for the active products not in coveredSKUs, and the
+// quantity, option, and acknowledgement fields those products need. Existing fields are kept.
+// When the products need different options, the form gets the first group that shares them.
+func (s *TemplateSyncer) ConvertIssueTemplate(existing string, config *GitShopConfig, coveredSKUs []string) (string, error) {
+	if HasOrderTemplateMarker(existing) {
+		return "", fmt.Errorf("template is already an order template")
+	}
+
+	products, err := selectTemplateProducts(config, nil)
+	if err != nil {
+		return "", err
+	}
+	uncovered := make([]ProductConfig, 0, len(products))
+	for _, product := range products {
+		if !slices.Contains(coveredSKUs, product.SKU) {
+			uncovered = append(uncovered, product)
+		}
+	}
+	if len(uncovered) == 0 {
+		return "", fmt.Errorf("every active product is already in an order template")
+	}
+	group := splitLargeGroupsByCategory(groupProductsByOptions(uncovered))[0]
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(existing), &doc); err != nil {
+		return "", fmt.Errorf("invalid template YAML: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0] == nil || doc.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("invalid template structure")
+	}
+	root := doc.Content[0]
+	bodyNode := findMappingValue(root, "body")
+	if bodyNode == nil || bodyNode.Kind != yaml.SequenceNode {
+		return "", fmt.Errorf("template is missing a valid body section")
+	}
+
+	labels := templateLabels(findMappingValue(root, "labels"))
+	// New orders start awaiting payment.
+	for _, label := range []string{config.Labels.Name(LabelOrder), config.Labels.Name(LabelPendingPayment)} {
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	setMappingSequence(root, "labels", labels)
+
+	fields, err := templateFieldNodes(productFields(i18n.New(config.Shop.Locale), group))
+	if err != nil {
+		return "", err
+	}
+	removeFieldByID(bodyNode, "product")
+	insertAt := 0
+	for insertAt < len(bodyNode.Content) && getFieldType(bodyNode.Content[insertAt]) == "markdown" {
+		insertAt++
+	}
+	bodyNode.Content = slices.Insert(bodyNode.Content, insertAt, fields...)
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode converted template: %w", err)
+	}
+	return s.SyncTemplateContent(withOrderTemplateMarker(string(out)), config)
+}
+
+// templateLabels reads an issue form's labels, which GitHub accepts as a list or a
+// comma-separated string.
+func templateLabels(node *yaml.Node) []string {
+	labels := []string{}
+	if node == nil {
+		return labels
+	}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if label := strings.TrimSpace(item.Value); label != "" {
+				labels = append(labels, label)
+			}
+		}
+	case yaml.ScalarNode:
+		for _, label := range strings.Split(node.Value, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+func templateFieldNodes(fields []templateField) ([]*yaml.Node, error) {
+	encoded, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template fields: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(encoded, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode template fields: %w", err)
+	}
+	return doc.Content[0].Content, nil
+}
@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+const commissionForm = `name: Commission request
+description: Ask for a custom piece
+labels: commission, triage
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for your interest!
+  - type: textarea
+    id: details
+    attributes:
+      label: What would you like?
+    validations:
+      required: true
+`
+
+func TestAnalyzeIssueTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		content         string
+		wantConvertible bool
+		wantSuggested   bool
+	}{
+		{name: "order-like form", content: commissionForm, wantConvertible: true, wantSuggested: true},
+		{
+			name:            "bug report form",
+			content:         "name: Bug report\nbody:\n  - type: textarea\n    id: what-happened\n    attributes:\n      label: What happened?\n",
+			wantConvertible: true,
+		},
+		{name: "chooser config", content: "blank_issues_enabled: false\n"},
+		{name: "order template", content: "# gitshop:order-template\nname: Order\nbody: []\n"},
+		{name: "invalid yaml", content: "name: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := AnalyzeIssueTemplate(tt.content)
+			if got.Convertible != tt.wantConvertible || got.Suggested != tt.wantSuggested {
+				t.Fatalf("AnalyzeIssueTemplate() = %+v, want convertible=%v suggested=%v", got, tt.wantConvertible, tt.wantSuggested)
+			}
+			if got.Reason == "" {
+				t.Fatal("expected a reason")
+			}
+		})
+	}
+}
+
+func TestConvertIssueTemplate(t *testing.T) {
+	t.Parallel()
+
+	size := ProductOption{Name: "size", Label: "Size", Type: "dropdown", Required: true, Values: []string{"S", "M", "L"}}
+	config := &GitShopConfig{
+		Products: []ProductConfig{
+			{SKU: "PRINT", Name: "Art Print", UnitPriceCents: 3000, Active: true, Options: []ProductOption{size}},
+			{SKU: "MUG", Name: "Mug", UnitPriceCents: 1500, Active: true},
+			{SKU: "POSTER", Name: "Poster", UnitPriceCents: 2000, Active: true, Options: []ProductOption{size}},
+		},
+	}
+	syncer := NewTemplateSyncer(nil)
+
+	converted, err := syncer.ConvertIssueTemplate(commissionForm, config, nil)
+	if err != nil {
+		t.Fatalf("ConvertIssueTemplate() error = %v", err)
+	}
+	if !HasOrderTemplateMarker(converted) {
+		t.Fatalf("converted template has no marker:\n%s", converted)
+	}
+	orderLabel := LabelsConfig{}.Name(LabelOrder)
+	if !TemplateHasLabel(converted, orderLabel) || !TemplateHasLabel(converted, "commission") {
+		t.Fatalf("converted template should keep its labels and add %s:\n%s", orderLabel, converted)
+	}
+	skus := FindTemplateSKUs(converted)
+	if _, ok := skus["PRINT"]; !ok {
+		t.Fatalf("expected PRINT in product dropdown, got %v", skus)
+	}
+	if _, ok := skus["MUG"]; ok {
+		t.Fatalf("products with different options should not share the form, got %v", skus)
+	}
+	if !strings.Contains(converted, "id: details") || !strings.Contains(converted, "id: quantity") || !strings.Contains(converted, "id: size") {
+		t.Fatalf("expected existing, quantity, and option fields:\n%s", converted)
+	}
+	if strings.Index(converted, "Thanks for your interest!") > strings.Index(converted, "id: product") {
+		t.Fatalf("product field should follow the intro markdown:\n%s", converted)
+	}
+	report := CheckOrderTemplate(converted, config)
+	if !report.Valid() {
+		t.Fatalf("converted template is not a valid order template: %+v", report)
+	}
+
+	converted, err = syncer.ConvertIssueTemplate(commissionForm, config, []string{"PRINT", "POSTER"})
+	if err != nil {
+		t.Fatalf("ConvertIssueTemplate() with covered SKUs error = %v", err)
+	}
+	if got := FindTemplateSKUs(converted); len(got) != 1 {
+		t.Fatalf("expected only MUG, got %v", got)
+	} else if _, ok := got["MUG"]; !ok {
+		t.Fatalf("expected only MUG, got %v", got)
+	}
+
+	if _, err := syncer.ConvertIssueTemplate(commissionForm, config, []string{"PRINT", "POSTER", "MUG"}); err == nil {
+		t.Fatal("expected an error when every product is covered")
+	}
+}
@@ -0,0 +1,293 @@
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	productLabelSKURegex   = regexp.MustCompile(`\(?SKU:([A-Za-z0-9_]+)\)?`)
+	productLabelPriceRegex = regexp.MustCompile(`\$[0-9]+(?:\.[0-9]{2})?`)
+)
+
+// TemplateChangelog summarizes how an order template changes, for pull request descriptions.
+type TemplateChangelog struct {
+	AddedProducts   []string
+	RemovedProducts []string
+	PriceChanges    []string
+	OptionChanges   []string
+	OtherChanges    []string
+}
+
+func (c TemplateChangelog) Empty() bool {
+	return len(c.AddedProducts) == 0 && len(c.RemovedProducts) == 0 && len(c.PriceChanges) == 0 &&
+		len(c.OptionChanges) == 0 && len(c.OtherChanges) == 0
+}
+
+// Markdown renders the changelog as a pull request section.
+func (c TemplateChangelog) Markdown() string {
+	var b strings.Builder
+	b.WriteString("### What changes\n")
+	if c.Empty() {
+		b.WriteString("\nNo products, prices, or options change; only the template formatting is updated.\n")
+		return b.String()
+	}
+
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Products added", c.AddedProducts},
+		{"Products removed", c.RemovedProducts},
+		{"Price changes", c.PriceChanges},
+		{"Options", c.OptionChanges},
+		{"Other", c.OtherChanges},
+	}
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n**%s**\n", section.title)
+		for _, item := range section.items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	return b.String()
+}
+
+type diffTemplate struct {
+	Body []diffTemplateField `yaml:"body"`
+}
+
+type diffTemplateField struct {
+	Type       string `yaml:"type"`
+	ID         string `yaml:"id"`
+	Attributes struct {
+		Label   string `yaml:"label"`
+		Options []any  `yaml:"options"`
+	} `yaml:"attributes"`
+	Validations struct {
+		Required bool `yaml:"required"`
+	} `yaml:"validations"`
+}
+
+type templateProduct struct {
+	name  string
+	price string
+}
+
+// DiffOrderTemplates compares two versions of an order template. An empty before describes a
+// new template.
+func DiffOrderTemplates(before, after string) (TemplateChangelog, error) {
+	oldFields, err := parseDiffTemplate(before)
+	if err != nil {
+		return TemplateChangelog{}, fmt.Errorf("invalid current template: %w", err)
+	}
+	newFields, err := parseDiffTemplate(after)
+	if err != nil {
+		return TemplateChangelog{}, fmt.Errorf("invalid updated template: %w", err)
+	}
+
+	changelog := TemplateChangelog{}
+	diffTemplateProducts(&changelog, templateProducts(oldFields), templateProducts(newFields))
+
+	for _, id := range sortedFieldIDs(oldFields, newFields) {
+		if id == "product" {
+			continue
+		}
+		oldField, hadField := oldFields[id]
+		newField, hasField := newFields[id]
+		if oldField.Type == "checkboxes" || newField.Type == "checkboxes" {
+			diffAcknowledgement(&changelog, id, oldField, hadField, newField, hasField)
+			continue
+		}
+		diffOptionField(&changelog, id, oldField, hadField, newField, hasField)
+	}
+	return changelog, nil
+}
+
+func parseDiffTemplate(content string) (map[string]diffTemplateField, error) {
+	fields := map[string]diffTemplateField{}
+	if strings.TrimSpace(content) == "" {
+		return fields, nil
+	}
+
+	var form diffTemplate
+	if err := yaml.Unmarshal([]byte(content), &form); err != nil {
+		return nil, err
+	}
+	for _, field := range form.Body {
+		if field.ID == "" || field.Type == "markdown" {
+			continue
+		}
+		if _, exists := fields[field.ID]; !exists {
+			fields[field.ID] = field
+		}
+	}
+	return fields, nil
+}
+
+func templateProducts(fields map[string]diffTemplateField) map[string]templateProduct {
+	products := map[string]templateProduct{}
+	field, ok := fields["product"]
+	if !ok {
+		return products
+	}
+	for _, option := range filterTemplateOptionValues(anyValuesToStrings(field.Attributes.Options)) {
+		match := productLabelSKURegex.FindStringSubmatch(option)
+		if len(match) < 2 {
+			continue
+		}
+		name := option[:strings.Index(option, match[0])]
+		price := productLabelPriceRegex.FindString(name)
+		if price != "" {
+			name = name[:strings.Index(name, price)]
+		} else if idx := strings.Index(name, "request a quote"); idx >= 0 {
+			name = name[:idx]
+			price = "quote on request"
+		}
+		name = strings.TrimRight(strings.TrimSpace(name), " —-")
+		products[match[1]] = templateProduct{name: name, price: price}
+	}
+	return products
+}
+
+func diffTemplateProducts(changelog *TemplateChangelog, before, after map[string]templateProduct) {
+	skus := make([]string, 0, len(before)+len(after))
+	for sku := range before {
+		skus = append(skus, sku)
+	}
+	for sku := range after {
+		if _, ok := before[sku]; !ok {
+			skus = append(skus, sku)
+		}
+	}
+	sort.Strings(skus)
+
+	for _, sku := range skus {
+		oldProduct, hadProduct := before[sku]
+		newProduct, hasProduct := after[sku]
+		switch {
+		case !hadProduct:
+			changelog.AddedProducts = append(changelog.AddedProducts, productSummary(sku, newProduct))
+		case !hasProduct:
+			changelog.RemovedProducts = append(changelog.RemovedProducts, productSummary(sku, oldProduct))
+		default:
+			if oldProduct.price != newProduct.price {
+				changelog.PriceChanges = append(changelog.PriceChanges, fmt.Sprintf("%s (`%s`): %s → %s", newProduct.name, sku, orUnknown(oldProduct.price), orUnknown(newProduct.price)))
+			}
+			if oldProduct.name != newProduct.name {
+				changelog.OtherChanges = append(changelog.OtherChanges, fmt.Sprintf("`%s` renamed from %s to %s", sku, oldProduct.name, newProduct.name))
+			}
+		}
+	}
+}
+
+func productSummary(sku string, product templateProduct) string {
+	if product.price == "" {
+		return fmt.Sprintf("%s (`%s`)", product.name, sku)
+	}
+	return fmt.Sprintf("%s (`%s`) at %s", product.name, sku, product.price)
+}
+
+func diffOptionField(changelog *TemplateChangelog, id string, oldField diffTemplateField, hadField bool, newField diffTemplateField, hasField bool) {
+	oldValues := filterTemplateOptionValues(anyValuesToStrings(oldField.Attributes.Options))
+	newValues := filterTemplateOptionValues(anyValuesToStrings(newField.Attributes.Options))
+	switch {
+	case !hadField:
+		summary := fmt.Sprintf("%s: new %s field", fieldName(id, newField), newField.Type)
+		if len(newValues) > 0 {
+			summary += " with " + strings.Join(newValues, ", ")
+		}
+		changelog.OptionChanges = append(changelog.OptionChanges, summary)
+		return
+	case !hasField:
+		changelog.OptionChanges = append(changelog.OptionChanges, fieldName(id, oldField)+": removed")
+		return
+	}
+
+	changes := []string{}
+	if oldField.Attributes.Label != newField.Attributes.Label {
+		changes = append(changes, fmt.Sprintf("label %q → %q", oldField.Attributes.Label, newField.Attributes.Label))
+	}
+	if oldField.Type != newField.Type {
+		changes = append(changes, fmt.Sprintf("type %s → %s", oldField.Type, newField.Type))
+	}
+	if added := missingValues(newValues, oldValues); len(added) > 0 {
+		changes = append(changes, "added "+strings.Join(added, ", "))
+	}
+	if removed := missingValues(oldValues, newValues); len(removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(removed, ", "))
+	}
+	if len(changes) == 0 && stringSlicesEqual(oldValues, newValues) && strings.Join(oldValues, "\n") != strings.Join(newValues, "\n") {
+		changes = append(changes, "values reordered")
+	}
+	if oldField.Validations.Required != newField.Validations.Required {
+		if newField.Validations.Required {
+			changes = append(changes, "now required")
+		} else {
+			changes = append(changes, "now optional")
+		}
+	}
+	if len(changes) > 0 {
+		changelog.OptionChanges = append(changelog.OptionChanges, fieldName(id, newField)+": "+strings.Join(changes, "; "))
+	}
+}
+
+func diffAcknowledgement(changelog *TemplateChangelog, id string, oldField diffTemplateField, hadField bool, newField diffTemplateField, hasField bool) {
+	switch {
+	case !hadField:
+		changelog.OtherChanges = append(changelog.OtherChanges, fieldName(id, newField)+": checkbox added")
+	case !hasField:
+		changelog.OtherChanges = append(changelog.OtherChanges, fieldName(id, oldField)+": checkbox removed")
+	case oldField.Attributes.Label != newField.Attributes.Label:
+		changelog.OtherChanges = append(changelog.OtherChanges, fmt.Sprintf("%s: label %q → %q", fieldName(id, newField), oldField.Attributes.Label, newField.Attributes.Label))
+	}
+}
+
+func fieldName(id string, field diffTemplateField) string {
+	if field.Attributes.Label == "" {
+		return fmt.Sprintf("`%s`", id)
+	}
+	return fmt.Sprintf("%s (`%s`)", field.Attributes.Label, id)
+}
+
+func sortedFieldIDs(before, after map[string]diffTemplateField) []string {
+	ids := make([]string, 0, len(before)+len(after))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// missingValues returns the values in a that b lacks, in a's order.
+func missingValues(a, b []string) []string {
+	present := make(map[string]struct{}, len(b))
+	for _, value := range b {
+		present[value] = struct{}{}
+	}
+	missing := []string{}
+	for _, value := range a {
+		if _, ok := present[value]; !ok {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+func orUnknown(price string) string {
+	if price == "" {
+		return "no price"
+	}
+	return price
+}
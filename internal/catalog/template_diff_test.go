@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffOrderTemplates_SyncedTemplate(t *testing.T) {
+	t.Parallel()
+
+	existing := `# gitshop:order-template
+name: Order
+body:
+  - type: dropdown
+    id: product
+    attributes:
+      label: Product
+      options:
+        - "T-Shirt - $20.00 (SKU:TSHIRT)"
+        - "Hoodie - $45.00 (SKU:HOODIE)"
+    validations:
+      required: true
+  - type: dropdown
+    id: quantity
+    attributes:
+      label: Quantity
+      options: ["1", "2", "3", "4", "5"]
+    validations:
+      required: true
+  - type: dropdown
+    id: size
+    attributes:
+      label: Size
+      options: ["S", "M", "L"]
+    validations:
+      required: true
+  - type: input
+    id: engraving
+    attributes:
+      label: Engraving
+`
+	config := &GitShopConfig{
+		Products: []ProductConfig{
+			{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2200, Active: true, Options: []ProductOption{
+				{Name: "size", Label: "Size", Type: "dropdown", Required: true, Values: []string{"M", "L", "XL"}},
+			}},
+			{SKU: "HOODIE", Name: "Hoodie", UnitPriceCents: 4500, Active: true, Options: []ProductOption{
+				{Name: "size", Label: "Size", Type: "dropdown", Required: true, Values: []string{"M", "L", "XL"}},
+			}},
+		},
+	}
+
+	synced, err := NewTemplateSyncer(nil).SyncTemplateContent(existing, config)
+	if err != nil {
+		t.Fatalf("SyncTemplateContent: %v", err)
+	}
+	changelog, err := DiffOrderTemplates(existing, synced)
+	if err != nil {
+		t.Fatalf("DiffOrderTemplates: %v", err)
+	}
+
+	if want := []string{"T-Shirt (`TSHIRT`): $20.00 → $22.00"}; !reflect.DeepEqual(changelog.PriceChanges, want) {
+		t.Fatalf("expected price changes %v, got %v", want, changelog.PriceChanges)
+	}
+	if len(changelog.AddedProducts) != 0 || len(changelog.RemovedProducts) != 0 {
+		t.Fatalf("expected no product changes, got %+v", changelog)
+	}
+	want := []string{"Size (`size`): added XL; removed S"}
+	if !reflect.DeepEqual(changelog.OptionChanges, want) {
+		t.Fatalf("expected option changes %v, got %v", want, changelog.OptionChanges)
+	}
+}
+
+func TestDiffOrderTemplates(t *testing.T) {
+	t.Parallel()
+
+	product := func(options ...string) string {
+		return "body:\n  - type: dropdown\n    id: product\n    attributes:\n      options:\n        - \"" + strings.Join(options, "\"\n        - \"") + "\"\n"
+	}
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   TemplateChangelog
+	}{
+		{
+			name:   "new template",
+			before: "",
+			after:  product("Mug — $12.00 (SKU:MUG)", "Print — request a quote (SKU:PRINT)"),
+			want:   TemplateChangelog{AddedProducts: []string{"Mug (`MUG`) at $12.00", "Print (`PRINT`) at quote on request"}},
+		},
+		{
+			name:   "product swapped and renamed",
+			before: product("Mug — $12.00 (SKU:MUG)", "Cap — $15.00 (SKU:CAP)"),
+			after:  product("Big Mug — $12.00 (SKU:MUG)", "Tote — $9.00 (SKU:TOTE)"),
+			want: TemplateChangelog{
+				AddedProducts:   []string{"Tote (`TOTE`) at $9.00"},
+				RemovedProducts: []string{"Cap (`CAP`) at $15.00"},
+				OtherChanges:    []string{"`MUG` renamed from Mug to Big Mug"},
+			},
+		},
+		{
+			name:   "unchanged",
+			before: product("Mug — $12.00 (SKU:MUG)"),
+			after:  "# gitshop:order-template\n" + product("Mug — $12.00 (SKU:MUG)"),
+			want:   TemplateChangelog{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DiffOrderTemplates(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("DiffOrderTemplates: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTemplateChangelogMarkdown(t *testing.T) {
+	t.Parallel()
+
+	markdown := TemplateChangelog{
+		PriceChanges:  []string{"Mug (`MUG`): $12.00 → $14.00"},
+		OptionChanges: []string{"Size (`size`): added XL"},
+	}.Markdown()
+	for _, want := range []string{"### What changes", "**Price changes**\n- Mug (`MUG`): $12.00 → $14.00", "**Options**\n- Size (`size`): added XL"} {
+		if !strings.Contains(markdown, want) {
+			t.Fatalf("expected %q in:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "Products added") {
+		t.Fatalf("expected empty sections to be omitted:\n%s", markdown)
+	}
+	if !strings.Contains(TemplateChangelog{}.Markdown(), "only the template formatting") {
+		t.Fatal("expected an empty changelog to say nothing changes")
+	}
+}
@@ -0,0 +1,28 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateFieldIDs maps the label of each answerable field in an order template to its field ID.
+// Issue bodies only carry labels, so this lets answers be matched by ID whatever language the
+// labels are written in.
+func TemplateFieldIDs(template string) (map[string]string, error) {
+	var form issueTemplate
+	if err := yaml.Unmarshal([]byte(template), &form); err != nil {
+		return nil, fmt.Errorf("invalid order template: %w", err)
+	}
+
+	ids := make(map[string]string, len(form.Body))
+	for _, field := range form.Body {
+		label := strings.TrimSpace(field.Attributes.Label)
+		if field.Type == "markdown" || field.ID == "" || label == "" {
+			continue
+		}
+		ids[label] = field.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,42 @@
+package catalog
+
+import "testing"
+
+func TestTemplateFieldIDs(t *testing.T) {
+	t.Parallel()
+
+	template := `# gitshop:order-template
+name: Pedido
+body:
+  - type: markdown
+    attributes:
+      value: Bienvenido
+  - type: dropdown
+    id: product
+    attributes:
+      label: Producto
+      options: ["Camiseta (SKU:TSHIRT)"]
+  - type: dropdown
+    id: size
+    attributes:
+      label: Talla
+      options: ["M", "L"]
+  - type: textarea
+    attributes:
+      label: Notas
+`
+	ids, err := TemplateFieldIDs(template)
+	if err != nil {
+		t.Fatalf("TemplateFieldIDs returned error: %v", err)
+	}
+	if ids["Producto"] != "product" || ids["Talla"] != "size" {
+		t.Fatalf("ids = %v, want labels mapped to field IDs", ids)
+	}
+	if _, ok := ids["Notas"]; ok {
+		t.Fatalf("expected a field without an ID to be skipped, got %v", ids)
+	}
+
+	if _, err := TemplateFieldIDs("body: ["); err == nil {
+		t.Fatal("expected an error for an invalid template")
+	}
+}
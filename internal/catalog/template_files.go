@@ -0,0 +1,188 @@
+package catalog
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+)
+
+const defaultOrderTemplateName = "🛒 Place an Order"
+
+// TemplateFile is a generated order template and the repository path it belongs at.
+type TemplateFile struct {
+	Path    string
+	Content string
+	SKUs    []string
+}
+
+// BuildTemplateFiles generates order templates for the active products that are not in
+// coveredSKUs, one per group of products sharing the same options. Groups too large for a dropdown
+// are split by product category. A single group is written to order.yaml unless that path is in
+// takenPaths; otherwise each template is named after its category or first product, e.g.
+// order-tshirt.yaml.
+func (s *TemplateSyncer) BuildTemplateFiles(config *GitShopConfig, coveredSKUs, takenPaths []string) ([]TemplateFile, error) {
+	products, err := selectTemplateProducts(config, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	uncovered := make([]ProductConfig, 0, len(products))
+	for _, product := range products {
+		if !slices.Contains(coveredSKUs, product.SKU) {
+			uncovered = append(uncovered, product)
+		}
+	}
+	groups := splitLargeGroupsByCategory(groupProductsByOptions(uncovered))
+
+	taken := map[string]struct{}{}
+	for _, p := range takenPaths {
+		taken[p] = struct{}{}
+	}
+
+	files := make([]TemplateFile, 0, len(groups))
+	for _, group := range groups {
+		name := defaultOrderTemplateName
+		category := groupCategory(group)
+		if len(groups) > 1 || len(coveredSKUs) > 0 {
+			name = "🛒 Order " + templateGroupLabel(group)
+			if category != "" {
+				name = "🛒 Order " + category
+			}
+		}
+		content, err := s.generateIssueTemplate(name, group, config.Shop, config.Labels)
+		if err != nil {
+			return nil, err
+		}
+
+		filePath := githubapp.OrderTemplatePath
+		if _, used := taken[filePath]; used || len(groups) > 1 {
+			filePath = uniqueTemplatePath(cmp.Or(category, group[0].SKU), taken)
+		}
+		taken[filePath] = struct{}{}
+
+		skus := make([]string, 0, len(group))
+		for _, product := range group {
+			skus = append(skus, product.SKU)
+		}
+		files = append(files, TemplateFile{
+			Path:    filePath,
+			Content: withOrderTemplateMarker(content),
+			SKUs:    skus,
+		})
+	}
+	return files, nil
+}
+
+// groupProductsByOptions splits products into groups that can share an order template, keeping
+// the order products appear in gitshop.yaml.
+func groupProductsByOptions(products []ProductConfig) [][]ProductConfig {
+	var (
+		groups  [][]ProductConfig
+		schemas [][]normalizedOption
+	)
+	for _, product := range products {
+		options := normalizeProductOptions(product)
+		index := slices.IndexFunc(schemas, func(schema []normalizedOption) bool {
+			return normalizedOptionSlicesEqual(schema, options)
+		})
+		if index < 0 {
+			schemas = append(schemas, options)
+			groups = append(groups, []ProductConfig{product})
+			continue
+		}
+		groups[index] = append(groups[index], product)
+	}
+	return groups
+}
+
+// splitLargeGroupsByCategory splits groups with more products than a dropdown can show into one
+// group per category, keeping the order categories first appear in gitshop.yaml. Uncategorized
+// products share a group.
+func splitLargeGroupsByCategory(groups [][]ProductConfig) [][]ProductConfig {
+	split := make([][]ProductConfig, 0, len(groups))
+	for _, group := range groups {
+		if len(group) <= MaxDropdownOptions {
+			split = append(split, group)
+			continue
+		}
+		var (
+			categories []string
+			byCategory [][]ProductConfig
+		)
+		for _, product := range group {
+			category := strings.TrimSpace(product.Category)
+			index := slices.Index(categories, category)
+			if index < 0 {
+				categories = append(categories, category)
+				byCategory = append(byCategory, []ProductConfig{product})
+				continue
+			}
+			byCategory[index] = append(byCategory[index], product)
+		}
+		split = append(split, byCategory...)
+	}
+	return split
+}
+
+// groupCategory returns the category every product in the group shares, if any.
+func groupCategory(products []ProductConfig) string {
+	category := strings.TrimSpace(products[0].Category)
+	for _, product := range products[1:] {
+		if strings.TrimSpace(product.Category) != category {
+			return ""
+		}
+	}
+	return category
+}
+
+func templateGroupLabel(products []ProductConfig) string {
+	const maxNames = 3
+	names := make([]string, 0, maxNames)
+	for _, product := range products {
+		if len(names) == maxNames {
+			break
+		}
+		names = append(names, product.Name)
+	}
+	label := strings.Join(names, ", ")
+	if extra := len(products) - len(names); extra > 0 {
+		label += fmt.Sprintf(" and %d more", extra)
+	}
+	return label
+}
+
+func uniqueTemplatePath(sku string, taken map[string]struct{}) string {
+	base := "order-" + templateSlug(sku)
+	candidate := path.Join(githubapp.IssueTemplateDir, base+".yaml")
+	for n := 2; ; n++ {
+		if _, used := taken[candidate]; !used {
+			return candidate
+		}
+		candidate = path.Join(githubapp.IssueTemplateDir, fmt.Sprintf("%s-%d.yaml", base, n))
+	}
+}
+
+func templateSlug(value string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "products"
+	}
+	return slug
+}
@@ -0,0 +1,142 @@
+package catalog
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestBuildTemplateFiles(t *testing.T) {
+	t.Parallel()
+
+	size := ProductOption{Name: "size", Label: "Size", Type: "dropdown", Required: true, Values: []string{"S", "M", "L"}}
+	color := ProductOption{Name: "color", Label: "Color", Type: "dropdown", Required: true, Values: []string{"White", "Black"}}
+	config := &GitShopConfig{
+		Products: []ProductConfig{
+			{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2500, Active: true, Options: []ProductOption{size}},
+			{SKU: "MUG_LARGE", Name: "Large Mug", UnitPriceCents: 1500, Active: true, Options: []ProductOption{color}},
+			{SKU: "HOODIE", Name: "Hoodie", UnitPriceCents: 5000, Active: true, Options: []ProductOption{size}},
+			{SKU: "RETIRED", Name: "Retired", UnitPriceCents: 1000, Active: false},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		covered    []string
+		taken      []string
+		wantPaths  []string
+		wantGroups [][]string
+	}{
+		{
+			name:       "divergent options split by schema",
+			wantPaths:  []string{".github/ISSUE_TEMPLATE/order-tshirt.yaml", ".github/ISSUE_TEMPLATE/order-mug-large.yaml"},
+			wantGroups: [][]string{{"TSHIRT", "HOODIE"}, {"MUG_LARGE"}},
+		},
+		{
+			name:       "single uncovered group uses order.yaml",
+			covered:    []string{"TSHIRT", "HOODIE"},
+			wantPaths:  []string{".github/ISSUE_TEMPLATE/order.yaml"},
+			wantGroups: [][]string{{"MUG_LARGE"}},
+		},
+		{
+			name:       "taken paths are skipped",
+			covered:    []string{"MUG_LARGE"},
+			taken:      []string{".github/ISSUE_TEMPLATE/order.yaml", ".github/ISSUE_TEMPLATE/order-tshirt.yaml"},
+			wantPaths:  []string{".github/ISSUE_TEMPLATE/order-tshirt-2.yaml"},
+			wantGroups: [][]string{{"TSHIRT", "HOODIE"}},
+		},
+		{
+			name:    "everything covered",
+			covered: []string{"TSHIRT", "HOODIE", "MUG_LARGE"},
+		},
+	}
+
+	syncer := NewTemplateSyncer(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			files, err := syncer.BuildTemplateFiles(config, tt.covered, tt.taken)
+			if err != nil {
+				t.Fatalf("BuildTemplateFiles returned error: %v", err)
+			}
+			if len(files) != len(tt.wantPaths) {
+				t.Fatalf("expected %d files, got %d", len(tt.wantPaths), len(files))
+			}
+			for i, file := range files {
+				if file.Path != tt.wantPaths[i] {
+					t.Fatalf("file %d: expected path %q, got %q", i, tt.wantPaths[i], file.Path)
+				}
+				if !slices.Equal(file.SKUs, tt.wantGroups[i]) {
+					t.Fatalf("file %d: expected SKUs %v, got %v", i, tt.wantGroups[i], file.SKUs)
+				}
+				if !HasOrderTemplateMarker(file.Content) {
+					t.Fatalf("file %d: expected order template marker", i)
+				}
+				if report := CheckOrderTemplate(file.Content, config); !report.Valid() {
+					t.Fatalf("file %d: expected generated template to validate, got %+v", i, report)
+				}
+				if simple, reason, err := syncer.IsSimpleSync(file.Content, config); err != nil || !simple {
+					t.Fatalf("file %d: expected template to stay syncable, got %v %q", i, err, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateSlug(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"TSHIRT":      "tshirt",
+		"MUG_LARGE":   "mug-large",
+		"__":          "products",
+		"COFFEE__V1_": "coffee-v1",
+	}
+	for input, want := range tests {
+		if got := templateSlug(input); got != want {
+			t.Fatalf("templateSlug(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestBuildTemplateFiles_SplitsLargeCatalogsByCategory(t *testing.T) {
+	t.Parallel()
+
+	config := &GitShopConfig{}
+	for i := range MaxDropdownOptions + 5 {
+		category := "Mugs"
+		if i%2 == 0 {
+			category = "Shirts"
+		}
+		config.Products = append(config.Products, ProductConfig{
+			SKU:            fmt.Sprintf("ITEM_%d", i),
+			Name:           fmt.Sprintf("Item %d", i),
+			UnitPriceCents: 1000,
+			Active:         true,
+			Category:       category,
+		})
+	}
+
+	syncer := NewTemplateSyncer(nil)
+	files, err := syncer.BuildTemplateFiles(config, nil, nil)
+	if err != nil {
+		t.Fatalf("BuildTemplateFiles returned error: %v", err)
+	}
+	wantPaths := []string{".github/ISSUE_TEMPLATE/order-shirts.yaml", ".github/ISSUE_TEMPLATE/order-mugs.yaml"}
+	if len(files) != len(wantPaths) {
+		t.Fatalf("expected %d files, got %d", len(wantPaths), len(files))
+	}
+	for i, file := range files {
+		if file.Path != wantPaths[i] {
+			t.Fatalf("file %d: expected path %q, got %q", i, wantPaths[i], file.Path)
+		}
+		if len(file.SKUs) > MaxDropdownOptions {
+			t.Fatalf("file %d: expected at most %d SKUs, got %d", i, MaxDropdownOptions, len(file.SKUs))
+		}
+		report := CheckOrderTemplate(file.Content, config)
+		if !report.Valid() || len(report.Warnings) > 0 {
+			t.Fatalf("file %d: expected generated template to validate without warnings, got %+v", i, report)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SampleIssue is the issue GitHub opens when a buyer submits an order template.
+type SampleIssue struct {
+	Title  string
+	Body   string
+	Labels []string
+	SKU    string
+}
+
+// SampleOrderIssue fills in an order template the way a buyer would: sku is picked in the
+// product field (the first listed product when sku is empty), every other dropdown takes its
+// first choice, and every checkbox is ticked. The body uses GitHub's issue form layout, so it
+// parses like a real order.
+func SampleOrderIssue(template, sku string) (SampleIssue, error) {
+	var form issueTemplate
+	if err := yaml.Unmarshal([]byte(template), &form); err != nil {
+		return SampleIssue{}, fmt.Errorf("invalid order template: %w", err)
+	}
+
+	sample := SampleIssue{
+		Title:  strings.TrimSpace(form.Title + "Simulated order"),
+		Labels: form.Labels,
+	}
+	var b strings.Builder
+	for _, field := range form.Body {
+		if field.Type == "markdown" {
+			continue
+		}
+		label := strings.TrimSpace(field.Attributes.Label)
+		if label == "" {
+			continue
+		}
+
+		var answer string
+		switch field.Type {
+		case "dropdown":
+			choices := sampleDropdownChoices(field.Attributes.Options)
+			if len(choices) == 0 {
+				continue
+			}
+			answer = choices[0]
+			if productSKU, choice := sampleProductChoice(choices, sku); productSKU != "" {
+				sample.SKU, answer = productSKU, choice
+			}
+		case "checkboxes":
+			lines := []string{}
+			for _, option := range sampleCheckboxLabels(field.Attributes.Options) {
+				lines = append(lines, "- [X] "+option)
+			}
+			answer = strings.Join(lines, "\n")
+		default:
+			answer = sampleTextAnswer(field)
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s\n\n", label, answer)
+	}
+
+	if sample.SKU == "" {
+		return SampleIssue{}, fmt.Errorf("order template lists no product with SKU %q", sku)
+	}
+	sample.Body = strings.TrimSpace(b.String())
+	return sample, nil
+}
+
+// sampleProductChoice returns the SKU and text of the product choice to pick, or an empty SKU
+// when the choices are not products.
+func sampleProductChoice(choices []string, sku string) (string, string) {
+	for _, choice := range choices {
+		match := productLabelSKURegex.FindStringSubmatch(choice)
+		if len(match) < 2 {
+			continue
+		}
+		if sku == "" || strings.EqualFold(match[1], sku) {
+			return match[1], choice
+		}
+	}
+	return "", ""
+}
+
+func sampleDropdownChoices(options any) []string {
+	values, _ := options.([]any)
+	choices := make([]string, 0, len(values))
+	for _, value := range values {
+		if choice := strings.TrimSpace(fmt.Sprint(value)); choice != "" {
+			choices = append(choices, choice)
+		}
+	}
+	return choices
+}
+
+func sampleCheckboxLabels(options any) []string {
+	values, _ := options.([]any)
+	labels := make([]string, 0, len(values))
+	for _, value := range values {
+		option, _ := value.(map[string]any)
+		if label, _ := option["label"].(string); strings.TrimSpace(label) != "" {
+			labels = append(labels, strings.TrimSpace(label))
+		}
+	}
+	return labels
+}
+
+// sampleTextAnswer answers an input or textarea: its default value when it has one, and
+// GitHub's placeholder for a skipped field when it is optional.
+func sampleTextAnswer(field templateField) string {
+	if value := strings.TrimSpace(field.Attributes.Value); value != "" {
+		return value
+	}
+	if field.ID == "quantity" {
+		return "1"
+	}
+	if field.Validations != nil && field.Validations.Required {
+		return "Simulated order"
+	}
+	return "_No response_"
+}
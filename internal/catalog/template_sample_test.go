@@ -0,0 +1,57 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSampleOrderIssue(t *testing.T) {
+	t.Parallel()
+
+	config := &GitShopConfig{
+		Shop: ShopConfig{Terms: TermsConfig{URL: "https://example.com/terms", RequireCheckbox: true}},
+		Products: []ProductConfig{
+			{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2500, Active: true, Options: []ProductOption{
+				{Name: "size", Label: "Size", Type: "dropdown", Required: true, Values: []string{"M", "XXL"}},
+			}},
+			{SKU: "HOODIE", Name: "Hoodie", UnitPriceCents: 4500, Active: true, Options: []ProductOption{
+				{Name: "size", Label: "Size", Type: "dropdown", Required: true, Values: []string{"M", "XXL"}},
+			}},
+		},
+	}
+	template, err := NewTemplateSyncer(nil).BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+
+	sample, err := SampleOrderIssue(template, "")
+	if err != nil {
+		t.Fatalf("SampleOrderIssue returned error: %v", err)
+	}
+	if sample.SKU != "TSHIRT" {
+		t.Fatalf("SKU = %q, want first listed product", sample.SKU)
+	}
+	if sample.Title != "[ORDER] Simulated order" {
+		t.Fatalf("Title = %q", sample.Title)
+	}
+	for _, want := range []string{"### Product\n\nT-Shirt", "### Quantity\n\n1", "### Size\n\nM", "- [X] I agree to the terms of sale"} {
+		if !strings.Contains(sample.Body, want) {
+			t.Fatalf("body missing %q:\n%s", want, sample.Body)
+		}
+	}
+	if !strings.Contains(strings.Join(sample.Labels, ","), "gitshop:order") {
+		t.Fatalf("Labels = %v, want the template's labels", sample.Labels)
+	}
+
+	sample, err = SampleOrderIssue(template, "HOODIE")
+	if err != nil {
+		t.Fatalf("SampleOrderIssue returned error: %v", err)
+	}
+	if sample.SKU != "HOODIE" || !strings.Contains(sample.Body, "### Product\n\nHoodie") {
+		t.Fatalf("expected the hoodie to be picked, got %q:\n%s", sample.SKU, sample.Body)
+	}
+
+	if _, err := SampleOrderIssue(template, "MISSING"); err == nil {
+		t.Fatal("expected an error for a SKU the template does not list")
+	}
+}
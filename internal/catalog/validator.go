@@ -3,9 +3,15 @@ package catalog
 // Package catalog provides configuration validation.
 
 import (
+	"errors"
 	"fmt"
+	"net/mail"
 	"regexp"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/money"
 )
 
 type Validator struct{}
@@ -21,25 +27,91 @@ func IsValidGitHubUsername(username string) bool {
 	return githubUsernameRegex.MatchString(username)
 }
 
+// ValidationError is a gitshop.yaml problem with the path of the field it is about, such as
+// products[1].options[0].name, so setup status can point at the line to fix.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// atPath places err under path. Errors that already carry a path are nested below it.
+func atPath(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var inner *ValidationError
+	if errors.As(err, &inner) {
+		switch {
+		case inner.Path == "":
+			return &ValidationError{Path: path, Message: inner.Message}
+		case strings.HasPrefix(inner.Path, "["):
+			return &ValidationError{Path: path + inner.Path, Message: inner.Message}
+		default:
+			return &ValidationError{Path: path + "." + inner.Path, Message: inner.Message}
+		}
+	}
+	return &ValidationError{Path: path, Message: err.Error()}
+}
+
+// reservedOptionNames are field IDs GitShop manages in order templates, which product options
+// cannot reuse. An option named quantity replaces the quantity field instead, so it must be a
+// dropdown like the field it replaces.
+var reservedOptionNames = map[string]bool{
+	"product":             true,
+	"product_sku":         true,
+	"sku":                 true,
+	TermsFieldID:          true,
+	EligibilityFieldID:    true,
+	EmailFieldID:          true,
+	ReminderOptOutFieldID: true,
+	CurrencyFieldID:       true,
+}
+
 func (v *Validator) Validate(config *GitShopConfig) error {
 	if err := v.validateShop(&config.Shop); err != nil {
-		return fmt.Errorf("shop validation failed: %w", err)
+		return atPath("shop", err)
 	}
 
 	if len(config.Products) == 0 {
-		return fmt.Errorf("at least one product is required")
+		return &ValidationError{Path: "products", Message: "at least one product is required"}
 	}
 
-	skus := make(map[string]bool)
+	skus := make(map[string]int)
 	for i, product := range config.Products {
-		if err := v.validateProduct(&product); err != nil {
-			return fmt.Errorf("product %d validation failed: %w", i, err)
+		path := fmt.Sprintf("products[%d]", i)
+		if err := v.validateProduct(&product, config.Shop.Currency); err != nil {
+			return atPath(path, err)
 		}
 
-		if skus[product.SKU] {
-			return fmt.Errorf("duplicate SKU: %s", product.SKU)
+		// Order issues are matched to products by SKU, and buyers rarely keep the case.
+		key := strings.ToUpper(product.SKU)
+		if first, ok := skus[key]; ok {
+			return &ValidationError{Path: path + ".sku", Message: fmt.Sprintf("duplicate SKU %s, also used by products[%d]", product.SKU, first)}
 		}
-		skus[product.SKU] = true
+		skus[key] = i
+	}
+
+	if err := validateCampaigns(config.Campaigns, config.Products, config.Shop.Currency); err != nil {
+		return atPath("campaigns", err)
+	}
+
+	if err := validateMessages(config.Messages); err != nil {
+		return atPath("messages", err)
+	}
+
+	if err := ValidateBranding(config.Branding); err != nil {
+		return atPath("branding", err)
+	}
+
+	if err := ValidateLabels(config.Labels); err != nil {
+		return atPath("labels", err)
 	}
 
 	return nil
@@ -50,27 +122,97 @@ func (v *Validator) validateShop(shop *ShopConfig) error {
 		return fmt.Errorf("shop name is required")
 	}
 
+	if !IsStripeCurrency(shop.Currency) {
+		return &ValidationError{Path: "currency", Message: fmt.Sprintf("%q is not a currency Stripe Checkout supports", shop.Currency)}
+	}
 	if shop.Currency != "usd" {
-		return fmt.Errorf("only USD currency is supported")
+		return &ValidationError{Path: "currency", Message: "only USD currency is supported"}
 	}
 
 	manager := strings.TrimSpace(shop.Manager)
+	if strings.HasPrefix(manager, "@") {
+		return &ValidationError{Path: "manager", Message: fmt.Sprintf("write the GitHub username without the @, as %s", strings.TrimPrefix(manager, "@"))}
+	}
 	if manager != "" && !IsValidGitHubUsername(manager) {
-		return fmt.Errorf("shop manager must be a valid GitHub username")
+		return &ValidationError{Path: "manager", Message: fmt.Sprintf("%q is not a valid GitHub username", manager)}
 	}
 
 	if shop.Shipping.FlatRateCents < 0 {
 		return fmt.Errorf("shipping flat rate must be zero or positive")
 	}
+	if shop.Shipping.FlatRateCents > money.MaxCents {
+		return fmt.Errorf("shipping flat rate must be at most %s", money.Format(money.MaxCents))
+	}
+	if shop.Shipping.FreeOverCents < 0 || shop.Shipping.FreeOverCents > money.MaxCents {
+		return fmt.Errorf("shipping free_over_cents must be between %s and %s", money.Format(0), money.Format(money.MaxCents))
+	}
 
 	if strings.TrimSpace(shop.Shipping.Carrier) == "" {
 		return fmt.Errorf("shipping carrier is required")
 	}
+	if days := shop.Shipping.ShipWithinDays; days != nil && (*days < 0 || *days > MaxShipWithinDays) {
+		return fmt.Errorf("shipping ship_within_days must be between 0 and %d", MaxShipWithinDays)
+	}
+
+	if shop.Terms.Enabled() {
+		if strings.TrimSpace(shop.Terms.URL) == "" {
+			return fmt.Errorf("terms url is required when terms acceptance is enabled")
+		}
+		if strings.TrimSpace(shop.Terms.Version) == "" {
+			return fmt.Errorf("terms version is required when terms acceptance is enabled")
+		}
+	}
+
+	if minutes := shop.Checkout.ExpiresInMinutes; minutes != 0 && (minutes < MinCheckoutExpiryMinutes || minutes > MaxCheckoutExpiryMinutes) {
+		return fmt.Errorf("checkout expires_in_minutes must be between %d and %d", MinCheckoutExpiryMinutes, MaxCheckoutExpiryMinutes)
+	}
+	if shop.Checkout.ApprovalOverCents < 0 || shop.Checkout.ApprovalOverCents > money.MaxCents {
+		return fmt.Errorf("checkout approval_over_cents must be between 0 and %d", money.MaxCents)
+	}
+	if err := validateCheckoutCustomFields(shop.Checkout.CustomFields); err != nil {
+		return err
+	}
+	if err := validateEstimateCurrencies(shop.Checkout.EstimateCurrencies, shop.Currency); err != nil {
+		return err
+	}
+	if quiet := shop.Checkout.QuietHours; quiet.Enabled() {
+		from, to, _, err := quiet.parse()
+		if err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("checkout quiet_hours start and end must differ")
+		}
+	}
+
+	if days := shop.Reviews.CloseAfterDays; days < 0 || days > MaxReviewCloseAfterDays {
+		return fmt.Errorf("reviews close_after_days must be between 0 and %d", MaxReviewCloseAfterDays)
+	}
+
+	if err := validateMaxQuantity(shop.MaxQuantity); err != nil {
+		return err
+	}
+
+	if !i18n.IsSupported(shop.Locale) {
+		return fmt.Errorf("locale must be one of %s", strings.Join(i18n.Supported(), ", "))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(shop.Admin.MinRole)) {
+	case "", AdminRoleWrite, AdminRoleMaintain, AdminRoleAdmin:
+	default:
+		return fmt.Errorf("admin min_role must be write, maintain, or admin")
+	}
+
+	if notifyEmail := strings.TrimSpace(shop.Notifications.Email); notifyEmail != "" {
+		if _, err := mail.ParseAddress(notifyEmail); err != nil {
+			return fmt.Errorf("notifications email must be a valid email address")
+		}
+	}
 
 	return nil
 }
 
-func (v *Validator) validateProduct(product *ProductConfig) error {
+func (v *Validator) validateProduct(product *ProductConfig, currency string) error {
 	if strings.TrimSpace(product.SKU) == "" {
 		return fmt.Errorf("product SKU is required")
 	}
@@ -79,18 +221,64 @@ func (v *Validator) validateProduct(product *ProductConfig) error {
 		return fmt.Errorf("product name is required")
 	}
 
-	if product.UnitPriceCents <= 0 {
-		return fmt.Errorf("product unit price must be positive")
+	if product.Type != "" && product.Type != ProductTypeInquiry {
+		return fmt.Errorf("product type must be empty or inquiry")
+	}
+
+	switch product.Billing {
+	case "", BillingMonthly, BillingYearly:
+	default:
+		return fmt.Errorf("product billing must be monthly or yearly")
+	}
+	if product.IsSubscription() && product.IsInquiry() {
+		return fmt.Errorf("inquiry products cannot use billing")
+	}
+
+	if product.IsInquiry() {
+		if product.UnitPriceCents < 0 {
+			return fmt.Errorf("product unit price must be zero or positive")
+		}
+	} else if product.UnitPriceCents <= 0 {
+		return &ValidationError{Path: "unit_price_cents", Message: "product unit price must be positive"}
+	} else if minimum := StripeMinimumCharge(currency); product.UnitPriceCents < minimum {
+		return &ValidationError{Path: "unit_price_cents", Message: fmt.Sprintf("%s is below Stripe's minimum charge of %s", money.Format(product.UnitPriceCents), money.Format(minimum))}
+	}
+	if product.UnitPriceCents > money.MaxCents {
+		return &ValidationError{Path: "unit_price_cents", Message: fmt.Sprintf("product unit price must be at most %s", money.Format(money.MaxCents))}
+	}
+
+	if err := validateCategory(product.Category); err != nil {
+		return err
+	}
+
+	if cents := product.ShippingCents; cents != nil && (*cents < 0 || *cents > money.MaxCents) {
+		return fmt.Errorf("product shipping_cents must be between %s and %s", money.Format(0), money.Format(money.MaxCents))
+	}
+
+	if err := validateMaxQuantity(product.MaxQuantity); err != nil {
+		return fmt.Errorf("product %w", err)
+	}
+
+	if product.MinimumAge < 0 {
+		return fmt.Errorf("product minimum age must be zero or positive")
+	}
+
+	if !product.Restricted && (product.VerifyIdentity || product.MinimumAge > 0) {
+		return fmt.Errorf("verify_identity and minimum_age require restricted: true")
 	}
 
 	optionNames := make(map[string]bool)
 	for i, option := range product.Options {
+		path := fmt.Sprintf("options[%d]", i)
 		if err := v.validateOption(&option); err != nil {
-			return fmt.Errorf("option %d validation failed: %w", i, err)
+			return atPath(path, err)
+		}
+		if err := validateOptionCondition(option, product.Options[:i]); err != nil {
+			return atPath(path, err)
 		}
 
 		if optionNames[option.Name] {
-			return fmt.Errorf("duplicate option name: %s", option.Name)
+			return &ValidationError{Path: path + ".name", Message: fmt.Sprintf("duplicate option name: %s", option.Name)}
 		}
 		optionNames[option.Name] = true
 	}
@@ -98,17 +286,102 @@ func (v *Validator) validateProduct(product *ProductConfig) error {
 	return nil
 }
 
+func validateMaxQuantity(maxQuantity int) error {
+	if maxQuantity < 0 || maxQuantity > MaxQuantityLimit {
+		return fmt.Errorf("max_quantity must be between 1 and %d", MaxQuantityLimit)
+	}
+	return nil
+}
+
+var customFieldKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]{1,200}$`)
+
+func validateCheckoutCustomFields(fields []CheckoutCustomField) error {
+	if len(fields) > MaxCheckoutCustomFields {
+		return fmt.Errorf("checkout custom_fields can have at most %d fields", MaxCheckoutCustomFields)
+	}
+	keys := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !customFieldKeyRegex.MatchString(field.Key) {
+			return fmt.Errorf("checkout custom field key %q must be letters and numbers only", field.Key)
+		}
+		if keys[field.Key] {
+			return fmt.Errorf("duplicate checkout custom field key: %s", field.Key)
+		}
+		keys[field.Key] = true
+		label := strings.TrimSpace(field.Label)
+		if label == "" {
+			return fmt.Errorf("checkout custom field %s needs a label", field.Key)
+		}
+		if utf8.RuneCountInString(label) > MaxCheckoutCustomFieldLabel {
+			return fmt.Errorf("checkout custom field %s label must be at most %d characters", field.Key, MaxCheckoutCustomFieldLabel)
+		}
+		switch field.Type {
+		case "", CustomFieldText, CustomFieldNumeric:
+		default:
+			return fmt.Errorf("checkout custom field %s type must be text or numeric", field.Key)
+		}
+	}
+	return nil
+}
+
+// validateEstimateCurrencies checks that buyers can only ask for estimates in real currencies
+// other than the one they are charged in.
+func validateEstimateCurrencies(currencies []string, shopCurrency string) error {
+	if len(currencies) > MaxEstimateCurrencies {
+		return &ValidationError{Path: "checkout.estimate_currencies", Message: fmt.Sprintf("estimate_currencies can list at most %d currencies", MaxEstimateCurrencies)}
+	}
+	seen := make(map[string]bool, len(currencies))
+	for i, currency := range currencies {
+		path := fmt.Sprintf("checkout.estimate_currencies[%d]", i)
+		code := strings.ToLower(strings.TrimSpace(currency))
+		switch {
+		case !IsStripeCurrency(code):
+			return &ValidationError{Path: path, Message: fmt.Sprintf("%q is not a currency code such as eur or gbp", currency)}
+		case code == shopCurrency:
+			return &ValidationError{Path: path, Message: fmt.Sprintf("orders are already charged in %s", strings.ToUpper(code))}
+		case seen[code]:
+			return &ValidationError{Path: path, Message: fmt.Sprintf("duplicate currency %s", strings.ToUpper(code))}
+		}
+		seen[code] = true
+	}
+	return nil
+}
+
+func validateCategory(category string) error {
+	if category == "" {
+		return nil
+	}
+	trimmed := strings.TrimSpace(category)
+	if trimmed == "" {
+		return fmt.Errorf("product category must not be blank")
+	}
+	if strings.ContainsAny(trimmed, "\r\n") {
+		return fmt.Errorf("product category must be a single line")
+	}
+	if utf8.RuneCountInString(trimmed) > MaxCategoryLength {
+		return fmt.Errorf("product category must be at most %d characters", MaxCategoryLength)
+	}
+	return nil
+}
+
 func (v *Validator) validateOption(option *ProductOption) error {
 	if strings.TrimSpace(option.Name) == "" {
 		return fmt.Errorf("option name is required")
 	}
 
+	if reservedOptionNames[option.Name] {
+		return &ValidationError{Path: "name", Message: fmt.Sprintf("%q is used by the order template; pick another option name", option.Name)}
+	}
+
 	if strings.TrimSpace(option.Label) == "" {
 		return fmt.Errorf("option label is required")
 	}
 
-	if option.Type != "dropdown" && option.Type != "text" {
-		return fmt.Errorf("only dropdown or text option types are supported")
+	if option.Type != OptionTypeDropdown && !option.IsText() {
+		return &ValidationError{Path: "type", Message: "only dropdown, input, or textarea option types are supported"}
+	}
+	if option.Name == "quantity" && option.Type != "dropdown" {
+		return &ValidationError{Path: "type", Message: "the quantity option must be a dropdown"}
 	}
 
 	if option.Type == "dropdown" && option.Values == nil {
@@ -121,5 +394,25 @@ func (v *Validator) validateOption(option *ProductOption) error {
 		}
 	}
 
+	if err := validateOptionRules(option); err != nil {
+		return err
+	}
+
+	if len(option.ExtraCents) > 0 && (option.Type != "dropdown" || option.Name == "quantity") {
+		return fmt.Errorf("extra_cents is only supported on dropdown options other than quantity")
+	}
+	for _, value := range option.Values {
+		extra, ok := option.ExtraCents[value]
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("option value is required when extra_cents is set")
+		}
+		if extra < 0 || extra > money.MaxCents {
+			return fmt.Errorf("extra_cents for %s must be between 0 and %d", value, money.MaxCents)
+		}
+	}
+
 	return nil
 }
@@ -1,6 +1,11 @@
 package catalog
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestValidator_Validate(t *testing.T) {
 	t.Parallel()
@@ -54,22 +59,720 @@ func TestValidator_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid admin min role",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Admin:    AdminConfig{MinRole: "read"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid manager",
 			config: &GitShopConfig{
 				Shop: ShopConfig{
 					Name:     "Test Shop",
 					Currency: "usd",
-					Manager:  "-bad",
+					Manager:  "-bad",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "COFFEE_V1",
+						Name:           "Coffee",
+						UnitPriceCents: 1500,
+						Active:         true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "terms version required when checkbox enabled",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Terms:    TermsConfig{URL: "https://example.com/terms", RequireCheckbox: true},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "COFFEE_V1",
+						Name:           "Coffee",
+						UnitPriceCents: 1500,
+						Active:         true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "identity verification requires restricted product",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "SAUCE_V1",
+						Name:           "Hot Sauce",
+						UnitPriceCents: 1200,
+						Active:         true,
+						VerifyIdentity: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid notifications email",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:          "Test Shop",
+					Currency:      "usd",
+					Shipping:      ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Notifications: NotificationsConfig{Email: "not-an-email"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "COFFEE_V1",
+						Name:           "Coffee",
+						UnitPriceCents: 1500,
+						Active:         true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inquiry product without price",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:    "BEANS_BULK",
+						Name:   "Wholesale Beans",
+						Active: true,
+						Type:   ProductTypeInquiry,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unit price above the charge limit",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "VAULT",
+						Name:           "Vault",
+						UnitPriceCents: 100_000_000,
+						Active:         true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "monthly subscription product",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "COFFEE_CLUB",
+						Name:           "Coffee Club",
+						UnitPriceCents: 2000,
+						Active:         true,
+						Billing:        BillingMonthly,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown billing interval",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "COFFEE_CLUB",
+						Name:           "Coffee Club",
+						UnitPriceCents: 2000,
+						Active:         true,
+						Billing:        "weekly",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inquiry product with billing",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:     "BEANS_BULK",
+						Name:    "Wholesale Beans",
+						Active:  true,
+						Type:    ProductTypeInquiry,
+						Billing: BillingYearly,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown product type",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{
+						SKU:            "BEANS_BULK",
+						Name:           "Wholesale Beans",
+						UnitPriceCents: 1200,
+						Active:         true,
+						Type:           "subscription",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "product category",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true, Category: "Coffee & Tea"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "blank product category",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true, Category: "  "},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "product category too long",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true, Category: strings.Repeat("x", MaxCategoryLength+1)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "checkout expiry within stripe limits",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{ExpiresInMinutes: 120},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "checkout expiry below stripe minimum",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{ExpiresInMinutes: 15},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "checkout expiry above stripe maximum",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{ExpiresInMinutes: 25 * 60},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom comment templates",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+				Messages: MessagesConfig{CheckoutLink: "Pay for order {{.OrderNumber}} at {{.CheckoutURL}} within {{.ExpiresIn}}.", PaymentReceived: "Thanks from {{.ShopName}}!"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "comment template with unknown placeholder",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+				Messages: MessagesConfig{OrderShipped: "Shipped via {{.Carrier}}"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "comment template that does not parse",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+				Messages: MessagesConfig{PaymentReceived: "Paid {{.OrderNumber"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "checkout link template without the link",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+				Messages: MessagesConfig{CheckoutLink: "Thanks for order {{.OrderNumber}}!"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "supported locale",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Locale:   "de",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "shop and product max quantity",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:        "Test Shop",
+					Currency:    "usd",
+					MaxQuantity: 20,
+					Shipping:    ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true, MaxQuantity: 4},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "shop max quantity above limit",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:        "Test Shop",
+					Currency:    "usd",
+					MaxQuantity: MaxQuantityLimit + 1,
+					Shipping:    ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative product max quantity",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true, MaxQuantity: -1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "option value surcharge",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2500, Active: true, Options: []ProductOption{
+						{Name: "size", Label: "Size", Type: "dropdown", Values: []string{"M", "XXL"}, ExtraCents: map[string]int64{"XXL": 500}},
+					}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative option value surcharge",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2500, Active: true, Options: []ProductOption{
+						{Name: "size", Label: "Size", Type: "dropdown", Values: []string{"M", "S"}, ExtraCents: map[string]int64{"S": -500}},
+					}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "surcharge on quantity option",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2500, Active: true, Options: []ProductOption{
+						{Name: "quantity", Label: "Quantity", Type: "dropdown", Values: []string{"1", "2"}, ExtraCents: map[string]int64{"2": 100}},
+					}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "free shipping threshold and product shipping",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, FreeOverCents: 5000, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "ANVIL", Name: "Anvil", UnitPriceCents: 9000, Active: true, ShippingCents: new(int64)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative free shipping threshold",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, FreeOverCents: -1, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ship within days out of range",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS", ShipWithinDays: intPtr(MaxShipWithinDays + 1)},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ship within days zero turns reminders off",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS", ShipWithinDays: intPtr(0)},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "review close after days out of range",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
 					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Reviews:  ReviewsConfig{CloseAfterDays: MaxReviewCloseAfterDays + 1},
 				},
 				Products: []ProductConfig{
-					{
-						SKU:            "COFFEE_V1",
-						Name:           "Coffee",
-						UnitPriceCents: 1500,
-						Active:         true,
-					},
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "approval threshold",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{ApprovalOverCents: 50000},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative approval threshold",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{ApprovalOverCents: -1},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "checkout quiet hours",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{QuietHours: QuietHoursConfig{Start: "22:00", End: "08:00", Timezone: "America/New_York"}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "checkout quiet hours without end",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{QuietHours: QuietHoursConfig{Start: "22:00"}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "checkout quiet hours unknown timezone",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{QuietHours: QuietHoursConfig{Start: "22:00", End: "08:00", Timezone: "Mars/Olympus"}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "checkout custom fields",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{CustomFields: []CheckoutCustomField{
+						{Key: "company", Label: "Company name", Optional: true},
+						{Key: "vatid", Label: "VAT ID", Type: CustomFieldText},
+					}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "checkout custom field key with punctuation",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{CustomFields: []CheckoutCustomField{
+						{Key: "vat-id", Label: "VAT ID"},
+					}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate checkout custom field key",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{CustomFields: []CheckoutCustomField{
+						{Key: "company", Label: "Company name"},
+						{Key: "company", Label: "Company"},
+					}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "too many checkout custom fields",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{CustomFields: []CheckoutCustomField{
+						{Key: "a", Label: "A"},
+						{Key: "b", Label: "B"},
+						{Key: "c", Label: "C"},
+						{Key: "d", Label: "D"},
+					}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown checkout custom field type",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+					Checkout: CheckoutConfig{CustomFields: []CheckoutCustomField{
+						{Key: "size", Label: "Size", Type: "dropdown"},
+					}},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported locale",
+			config: &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Locale:   "klingon",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
 				},
 			},
 			wantErr: true,
@@ -91,3 +794,243 @@ func TestValidator_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidator_ValidateErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mutate   func(config *GitShopConfig)
+		wantPath string
+		wantMsg  string
+	}{
+		{
+			name: "duplicate SKU differing in case",
+			mutate: func(config *GitShopConfig) {
+				config.Products = append(config.Products, ProductConfig{SKU: "coffee_v1", Name: "Decaf", UnitPriceCents: 1500, Active: true})
+			},
+			wantPath: "products[1].sku",
+			wantMsg:  "also used by products[0]",
+		},
+		{
+			name:     "price below Stripe minimum",
+			mutate:   func(config *GitShopConfig) { config.Products[0].UnitPriceCents = 25 },
+			wantPath: "products[0].unit_price_cents",
+			wantMsg:  "below Stripe's minimum charge of $0.50",
+		},
+		{
+			name:     "currency Stripe does not support",
+			mutate:   func(config *GitShopConfig) { config.Shop.Currency = "xyz" },
+			wantPath: "shop.currency",
+			wantMsg:  "not a currency Stripe Checkout supports",
+		},
+		{
+			name:     "Stripe currency GitShop does not support",
+			mutate:   func(config *GitShopConfig) { config.Shop.Currency = "eur" },
+			wantPath: "shop.currency",
+			wantMsg:  "only USD currency is supported",
+		},
+		{
+			name: "option named like a template field",
+			mutate: func(config *GitShopConfig) {
+				config.Products[0].Options = []ProductOption{{Name: "product", Label: "Product", Type: "text"}}
+			},
+			wantPath: "products[0].options[0].name",
+			wantMsg:  "used by the order template",
+		},
+		{
+			name: "text quantity option",
+			mutate: func(config *GitShopConfig) {
+				config.Products[0].Options = []ProductOption{{Name: "quantity", Label: "Quantity", Type: "text"}}
+			},
+			wantPath: "products[0].options[0].type",
+			wantMsg:  "must be a dropdown",
+		},
+		{
+			name: "validation on a dropdown",
+			mutate: func(config *GitShopConfig) {
+				config.Products[0].Options = []ProductOption{{Name: "size", Label: "Size", Type: "dropdown", Values: []string{"M"}, Validation: OptionValidation{MaxLength: 3}}}
+			},
+			wantPath: "products[0].options[0].validation",
+			wantMsg:  "only supported on input and textarea options",
+		},
+		{
+			name: "invalid option pattern",
+			mutate: func(config *GitShopConfig) {
+				config.Products[0].Options = []ProductOption{{Name: "engraving", Label: "Engraving", Type: "input", Validation: OptionValidation{Pattern: "[A-Z"}}}
+			},
+			wantPath: "products[0].options[0].validation.pattern",
+			wantMsg:  "invalid pattern",
+		},
+		{
+			name: "min_length over max_length",
+			mutate: func(config *GitShopConfig) {
+				config.Products[0].Options = []ProductOption{{Name: "notes", Label: "Notes", Type: "textarea", Validation: OptionValidation{MinLength: 10, MaxLength: 5}}}
+			},
+			wantPath: "products[0].options[0].validation.min_length",
+			wantMsg:  "must not be larger than max_length",
+		},
+		{
+			name: "condition on a later option",
+			mutate: func(config *GitShopConfig) {
+				config.Products[0].Options = []ProductOption{
+					{Name: "frame_color", Label: "Frame color", Type: "dropdown", Values: []string{"Oak"}, When: &OptionCondition{Option: "framed", Value: "Yes"}},
+					{Name: "framed", Label: "Framed", Type: "dropdown", Values: []string{"No", "Yes"}},
+				}
+			},
+			wantPath: "products[0].options[0].when.option",
+			wantMsg:  "must name an option listed before frame_color",
+		},
+		{
+			name: "condition on a missing value",
+			mutate: func(config *GitShopConfig) {
+				config.Products[0].Options = []ProductOption{
+					{Name: "framed", Label: "Framed", Type: "dropdown", Values: []string{"No", "Yes"}},
+					{Name: "frame_color", Label: "Frame color", Type: "dropdown", Values: []string{"Oak"}, When: &OptionCondition{Option: "framed", Value: "Maybe"}},
+				}
+			},
+			wantPath: "products[0].options[1].when.value",
+			wantMsg:  "not a value of framed",
+		},
+		{
+			name:     "manager with @",
+			mutate:   func(config *GitShopConfig) { config.Shop.Manager = "@octocat" },
+			wantPath: "shop.manager",
+			wantMsg:  "without the @",
+		},
+		{
+			name:     "invalid manager",
+			mutate:   func(config *GitShopConfig) { config.Shop.Manager = "octo_cat" },
+			wantPath: "shop.manager",
+			wantMsg:  "not a valid GitHub username",
+		},
+		{
+			name:     "estimate currency that is not a currency",
+			mutate:   func(config *GitShopConfig) { config.Shop.Checkout.EstimateCurrencies = []string{"eur", "euro"} },
+			wantPath: "shop.checkout.estimate_currencies[1]",
+			wantMsg:  "not a currency code",
+		},
+		{
+			name:     "estimate currency matching the shop currency",
+			mutate:   func(config *GitShopConfig) { config.Shop.Checkout.EstimateCurrencies = []string{"USD"} },
+			wantPath: "shop.checkout.estimate_currencies[0]",
+			wantMsg:  "charged in",
+		},
+		{
+			name:     "branding logo over http",
+			mutate:   func(config *GitShopConfig) { config.Branding.LogoURL = "http://example.com/logo.png" },
+			wantPath: "branding.logo_url",
+			wantMsg:  "https URL",
+		},
+		{
+			name:     "branding accent color name",
+			mutate:   func(config *GitShopConfig) { config.Branding.AccentColor = "teal" },
+			wantPath: "branding.accent_color",
+			wantMsg:  "hex color",
+		},
+		{
+			name:     "branding support email with a display name",
+			mutate:   func(config *GitShopConfig) { config.Branding.SupportEmail = "Help <help@example.com>" },
+			wantPath: "branding.support_email",
+			wantMsg:  "plain email address",
+		},
+		{
+			name:     "branding shop URL with a quote",
+			mutate:   func(config *GitShopConfig) { config.Branding.ShopURL = `https://example.com/"onload` },
+			wantPath: "branding.shop_url",
+			wantMsg:  "https URL",
+		},
+		{
+			name:     "label prefix with a colon",
+			mutate:   func(config *GitShopConfig) { config.Labels.Prefix = "shop:orders" },
+			wantPath: "labels.prefix",
+			wantMsg:  "prefix must be",
+		},
+		{
+			name:     "unknown label color",
+			mutate:   func(config *GitShopConfig) { config.Labels.Colors = map[string]string{"refunded": "#ff0000"} },
+			wantPath: "labels.colors.refunded",
+			wantMsg:  "unknown label",
+		},
+		{
+			name:     "short label color",
+			mutate:   func(config *GitShopConfig) { config.Labels.Colors = map[string]string{"paid": "#0f0"} },
+			wantPath: "labels.colors.paid",
+			wantMsg:  "six digit hex color",
+		},
+		{
+			name: "campaign ending before it starts",
+			mutate: func(config *GitShopConfig) {
+				config.Campaigns = []CampaignConfig{{Name: "Spring Sale", StartsAt: campaignStart.Add(time.Hour), EndsAt: campaignStart, PercentOff: 20}}
+			},
+			wantPath: "campaigns[0].ends_at",
+			wantMsg:  "after starts_at",
+		},
+		{
+			name: "campaign with both discounts",
+			mutate: func(config *GitShopConfig) {
+				config.Campaigns = []CampaignConfig{{Name: "Spring Sale", StartsAt: campaignStart, EndsAt: campaignEnd, PercentOff: 20, AmountOffCents: 100}}
+			},
+			wantPath: "campaigns[0]",
+			wantMsg:  "not both",
+		},
+		{
+			name: "campaign for an unknown SKU",
+			mutate: func(config *GitShopConfig) {
+				config.Campaigns = []CampaignConfig{{Name: "Spring Sale", StartsAt: campaignStart, EndsAt: campaignEnd, PercentOff: 20, SKUs: []string{"TEA_V1"}}}
+			},
+			wantPath: "campaigns[0].skus[0]",
+			wantMsg:  "no product has SKU TEA_V1",
+		},
+		{
+			name: "campaign sale price below Stripe minimum",
+			mutate: func(config *GitShopConfig) {
+				config.Campaigns = []CampaignConfig{{Name: "Spring Sale", StartsAt: campaignStart, EndsAt: campaignEnd, AmountOffCents: 1480}}
+			},
+			wantPath: "campaigns[0]",
+			wantMsg:  "below Stripe's minimum charge",
+		},
+		{
+			name: "overlapping campaigns on one product",
+			mutate: func(config *GitShopConfig) {
+				config.Campaigns = []CampaignConfig{
+					{Name: "Spring Sale", StartsAt: campaignStart, EndsAt: campaignEnd, PercentOff: 20},
+					{Name: "Flash Sale", StartsAt: campaignStart.Add(time.Hour), EndsAt: campaignEnd.Add(time.Hour), PercentOff: 30, SKUs: []string{"COFFEE_V1"}},
+				}
+			},
+			wantPath: "campaigns[1]",
+			wantMsg:  "overlaps campaigns[0]",
+		},
+	}
+
+	validator := NewValidator()
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := &GitShopConfig{
+				Shop: ShopConfig{
+					Name:     "Test Shop",
+					Currency: "usd",
+					Shipping: ShippingConfig{FlatRateCents: 500, Carrier: "USPS"},
+				},
+				Products: []ProductConfig{
+					{SKU: "COFFEE_V1", Name: "Coffee", UnitPriceCents: 1500, Active: true},
+				},
+			}
+			tc.mutate(config)
+
+			var validationErr *ValidationError
+			if err := validator.Validate(config); !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if validationErr.Path != tc.wantPath {
+				t.Fatalf("Path = %q, want %q", validationErr.Path, tc.wantPath)
+			}
+			if !strings.Contains(validationErr.Message, tc.wantMsg) {
+				t.Fatalf("Message = %q, want it to contain %q", validationErr.Message, tc.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentConfigVersion is the gitshop.yaml schema version this build reads natively. Files
+// without a version key are version 0 and are migrated when parsed.
+const CurrentConfigVersion = 1
+
+var ErrUnsupportedConfigVersion = errors.New("unsupported gitshop.yaml version")
+
+type configMigration struct {
+	// Note tells the seller what to change in their file to reach the next version.
+	Note    string
+	Migrate func(root *yaml.Node)
+}
+
+// configMigrations[v] upgrades a version v document to v+1.
+var configMigrations = []configMigration{
+	{
+		Note: "Add `version: 1` at the top of gitshop.yaml so GitShop can detect future format changes.",
+		Migrate: func(root *yaml.Node) {
+			if currency := findMappingValue(findMappingValue(root, "shop"), "currency"); currency != nil {
+				currency.Value = strings.ToLower(strings.TrimSpace(currency.Value))
+			}
+		},
+	},
+}
+
+// UpgradeNotes returns the changes needed to bring a version sourceVersion file up to date.
+func UpgradeNotes(sourceVersion int) []string {
+	notes := []string{}
+	for v := max(sourceVersion, 0); v < CurrentConfigVersion; v++ {
+		notes = append(notes, configMigrations[v].Note)
+	}
+	return notes
+}
+
+// UpgradeContent rewrites gitshop.yaml content in the current schema version. It reports false
+// when the content is already current.
+func UpgradeContent(content []byte) ([]byte, bool, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	sourceVersion, err := migrateConfigDocument(&doc)
+	if err != nil {
+		return nil, false, err
+	}
+	if sourceVersion == CurrentConfigVersion {
+		return content, false, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, false, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, false, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// migrateConfigDocument upgrades doc in place to CurrentConfigVersion and returns the version it
+// was written in. Documents that are not a mapping are left for decoding to reject.
+func migrateConfigDocument(doc *yaml.Node) (int, error) {
+	root := doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return CurrentConfigVersion, nil
+	}
+
+	sourceVersion := 0
+	versionNode := findMappingValue(root, "version")
+	if versionNode != nil {
+		parsed, err := strconv.Atoi(versionNode.Value)
+		if err != nil || parsed < 0 {
+			return 0, fmt.Errorf("version must be a whole number, got %q", versionNode.Value)
+		}
+		sourceVersion = parsed
+	}
+	if sourceVersion > CurrentConfigVersion {
+		return 0, fmt.Errorf("%w: %d (this GitShop supports up to %d)", ErrUnsupportedConfigVersion, sourceVersion, CurrentConfigVersion)
+	}
+	if sourceVersion == CurrentConfigVersion {
+		return sourceVersion, nil
+	}
+
+	for v := sourceVersion; v < CurrentConfigVersion; v++ {
+		configMigrations[v].Migrate(root)
+	}
+
+	current := strconv.Itoa(CurrentConfigVersion)
+	if versionNode != nil {
+		versionNode.Value = current
+		return sourceVersion, nil
+	}
+	versionKey := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	if len(root.Content) > 0 {
+		// Keep the file's leading comment above the new key.
+		versionKey.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{
+		versionKey,
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: current},
+	}, root.Content...)
+	return sourceVersion, nil
+}
@@ -0,0 +1,106 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const legacyConfig = `# My storefront
+shop:
+  name: "Test Shop"
+  currency: "USD"
+  shipping:
+    flat_rate_cents: 900
+    carrier: "USPS"
+products:
+  - sku: "TEST_V1"
+    name: "Test Product"
+    unit_price_cents: 1000
+    active: true
+`
+
+func TestParser_ParseMigratesUnversionedConfig(t *testing.T) {
+	t.Parallel()
+
+	config, err := NewParser().ParseFromString(legacyConfig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.SourceVersion != 0 || config.Version != CurrentConfigVersion {
+		t.Fatalf("expected version 0 migrated to %d, got source=%d version=%d", CurrentConfigVersion, config.SourceVersion, config.Version)
+	}
+	if !config.NeedsUpgrade() {
+		t.Fatal("expected unversioned config to need an upgrade")
+	}
+	if config.Shop.Currency != "usd" {
+		t.Fatalf("expected currency to be normalized, got %q", config.Shop.Currency)
+	}
+	if err := NewValidator().Validate(config); err != nil {
+		t.Fatalf("expected migrated config to validate, got %v", err)
+	}
+}
+
+func TestParser_ParseVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		version     string
+		wantErr     error
+		wantAnyErr  bool
+		wantUpgrade bool
+	}{
+		{name: "current", version: "1"},
+		{name: "future", version: "99", wantErr: ErrUnsupportedConfigVersion},
+		{name: "not a number", version: "latest", wantAnyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := NewParser().ParseFromString("version: " + tt.version + "\n" + legacyConfig)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+			case tt.wantAnyErr:
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if config.NeedsUpgrade() != tt.wantUpgrade {
+					t.Fatalf("expected needs upgrade=%v", tt.wantUpgrade)
+				}
+			}
+		})
+	}
+}
+
+func TestUpgradeContent(t *testing.T) {
+	t.Parallel()
+
+	upgraded, changed, err := UpgradeContent([]byte(legacyConfig))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed {
+		t.Fatal("expected unversioned config to be rewritten")
+	}
+	content := string(upgraded)
+	for _, want := range []string{"version: 1", "# My storefront", `currency: "usd"`} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected upgraded content to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	again, changed, err := UpgradeContent(upgraded)
+	if err != nil || changed || string(again) != content {
+		t.Fatalf("expected upgraded content to be current, changed=%v err=%v", changed, err)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/go-playground/validator/v10"
+
+	"github.com/gitshopapp/gitshop/internal/featureflags"
 )
 
 type Config struct {
@@ -17,11 +20,19 @@ type Config struct {
 	GitHubWebhookSecret    string `env:"GITHUB_WEBHOOK_SECRET,required" validate:"required"`
 	GitHubPrivateKeyBase64 string `env:"GITHUB_PRIVATE_KEY_BASE64,required" validate:"required"`
 
+	// DatabaseReplicaURLs lists read replicas for lag-tolerant reads such as dashboard lists and
+	// analytics. Reads fall back to DATABASE_URL while no replica is healthy.
+	DatabaseReplicaURLs []string `env:"DATABASE_REPLICA_URLS" envSeparator:"," validate:"dive,required"`
+
 	GitHubClientID     string `env:"GITHUB_CLIENT_ID"`
 	GitHubClientSecret string `env:"GITHUB_CLIENT_SECRET"`
 
 	StripePlatformSecretKey string `env:"STRIPE_SECRET_KEY"`
 	StripeWebhookSecret     string `env:"STRIPE_WEBHOOK_SECRET,required" validate:"required"`
+	// StripeTestSecretKey lets sellers put their shop in test mode. Test mode events are signed
+	// with StripeTestWebhookSecret when the test mode webhook is a separate endpoint in Stripe.
+	StripeTestSecretKey     string `env:"STRIPE_TEST_SECRET_KEY"`
+	StripeTestWebhookSecret string `env:"STRIPE_TEST_WEBHOOK_SECRET"`
 
 	StripeConnectClientID string `env:"STRIPE_CONNECT_CLIENT_ID"`
 	BaseURL               string `env:"BASE_URL" validate:"omitempty,url"`
@@ -31,11 +42,69 @@ type Config struct {
 	RedisConnectionString string `env:"REDIS_CONNECTION_STRING" envDefault:"redis://localhost:6379/0" validate:"required_if=CacheProvider redis,required_if=SessionStoreProvider redis"`
 
 	EncryptionKey string `env:"ENCRYPTION_KEY,required" validate:"required,len=32"`
+	// EncryptionKeys lists versioned keys as "<key ID>:<key>", oldest first. New secrets are
+	// encrypted with the last one; ENCRYPTION_KEY still decrypts secrets written before keys
+	// were versioned and keys file link signatures.
+	EncryptionKeys []string `env:"ENCRYPTION_KEYS" envSeparator:"," validate:"dive,required"`
+	// EmailIndexKey keys the hash customer emails are looked up by. Changing it leaves every stored
+	// hash stale until encrypt-customer-data rebuilds them.
+	EmailIndexKey string `env:"EMAIL_INDEX_KEY,required" validate:"required,len=32"`
+
+	// StorageProvider keeps generated files such as exports. Local files are served through
+	// signed links by this instance, so use s3 when running more than one.
+	StorageProvider   string `env:"STORAGE_PROVIDER" envDefault:"local" validate:"omitempty,oneof=local s3"`
+	StorageLocalDir   string `env:"STORAGE_LOCAL_DIR" envDefault:"data/files" validate:"required_if=StorageProvider local"`
+	S3Endpoint        string `env:"S3_ENDPOINT" validate:"omitempty,url"`
+	S3Region          string `env:"S3_REGION" envDefault:"us-east-1"`
+	S3Bucket          string `env:"S3_BUCKET" validate:"required_if=StorageProvider s3"`
+	S3AccessKeyID     string `env:"S3_ACCESS_KEY_ID" validate:"required_if=StorageProvider s3"`
+	S3SecretAccessKey string `env:"S3_SECRET_ACCESS_KEY" validate:"required_if=StorageProvider s3"`
+	S3PathStyle       bool   `env:"S3_PATH_STYLE"`
+
+	// WarehouseS3Bucket turns on a daily CSV export of orders and order events for analytics,
+	// written to its own S3-compatible bucket. Google Cloud Storage works through its
+	// interoperability endpoint with HMAC keys.
+	WarehouseS3Bucket          string `env:"WAREHOUSE_S3_BUCKET"`
+	WarehouseS3Endpoint        string `env:"WAREHOUSE_S3_ENDPOINT" validate:"omitempty,url"`
+	WarehouseS3Region          string `env:"WAREHOUSE_S3_REGION" envDefault:"us-east-1"`
+	WarehouseS3AccessKeyID     string `env:"WAREHOUSE_S3_ACCESS_KEY_ID" validate:"required_with=WarehouseS3Bucket"`
+	WarehouseS3SecretAccessKey string `env:"WAREHOUSE_S3_SECRET_ACCESS_KEY" validate:"required_with=WarehouseS3Bucket"`
+	WarehouseS3PathStyle       bool   `env:"WAREHOUSE_S3_PATH_STYLE"`
+
+	// AddressValidationProvider checks shipping addresses when a checkout completes. Orders with
+	// an undeliverable address are held until the buyer or seller fixes it.
+	AddressValidationProvider string `env:"ADDRESS_VALIDATION_PROVIDER" envDefault:"none" validate:"omitempty,oneof=none easypost google"`
+	EasyPostAPIKey            string `env:"EASYPOST_API_KEY" validate:"required_if=AddressValidationProvider easypost"`
+	GoogleMapsAPIKey          string `env:"GOOGLE_MAPS_API_KEY" validate:"required_if=AddressValidationProvider google"`
+
+	// FXRatesProvider supplies the exchange rates behind the local-currency estimates buyers can
+	// ask for. Rates are cached for a day; orders are still charged in the shop's currency.
+	FXRatesProvider string `env:"FX_RATES_PROVIDER" envDefault:"none" validate:"omitempty,oneof=none frankfurter"`
+	FXRatesURL      string `env:"FX_RATES_URL" validate:"omitempty,url"`
 
 	LogLevel    slog.Level `env:"LOG_LEVEL" envDefault:"INFO"`
 	LogFormat   string     `env:"LOG_FORMAT" envDefault:"text" validate:"omitempty,oneof=text json"`
 	Port        string     `env:"PORT" envDefault:"8080"`
 	Environment string     `env:"ENVIRONMENT" envDefault:"development" validate:"oneof=development production"`
+	// LogDebugSampleEvery keeps one in every n debug logs with the same message; 1 keeps all.
+	LogDebugSampleEvery int `env:"LOG_DEBUG_SAMPLE_EVERY" envDefault:"1" validate:"omitempty,gte=1"`
+	// Region names this instance's home region in multi-region deployments. Instances only claim
+	// queued work for shops pinned to their region; empty means single-region.
+	Region string `env:"REGION" validate:"omitempty,max=32,lowercase"`
+	// Regions lists the home regions sellers can choose for their shop's processing region.
+	Regions []string `env:"REGIONS" envSeparator:"," validate:"dive,required,max=32,lowercase"`
+
+	// DataRetentionDays is how long customer details are kept on orders of disconnected shops
+	// before they are anonymized. Zero keeps them indefinitely.
+	DataRetentionDays int `env:"DATA_RETENTION_DAYS" envDefault:"365" validate:"gte=0"`
+
+	// Operators are GitHub usernames allowed to use operator-only debugging tools.
+	Operators []string `env:"OPERATORS" envSeparator:","`
+	// OperatorWebhookURL receives a Slack-compatible JSON post when a shop makes its first sale.
+	OperatorWebhookURL string `env:"OPERATOR_WEBHOOK_URL" validate:"omitempty,url"`
+	// FeatureFlags sets the default rollout of each feature flag as flag:rule pairs, where a
+	// rule is on, off, or a percentage of shops, e.g. "subscriptions:10%".
+	FeatureFlags map[string]string `env:"FEATURE_FLAGS"`
 
 	SentryDSN              string  `env:"SENTRY_DSN"`
 	SentryTracesSampleRate float64 `env:"SENTRY_TRACES_SAMPLE_RATE" envDefault:"0.2" validate:"gte=0,lte=1"`
@@ -70,6 +139,18 @@ func (c *Config) validate() error {
 		return fmt.Errorf("GITHUB_CLIENT_ID and GITHUB_CLIENT_SECRET must be set together")
 	}
 
+	if key := strings.TrimSpace(c.StripeTestSecretKey); key != "" && !strings.HasPrefix(key, "sk_test_") && !strings.HasPrefix(key, "rk_test_") {
+		return fmt.Errorf("STRIPE_TEST_SECRET_KEY must be a Stripe test mode key")
+	}
+
+	if _, err := featureflags.ParseRules(c.FeatureFlags); err != nil {
+		return fmt.Errorf("FEATURE_FLAGS: %w", err)
+	}
+
+	if c.Region != "" && len(c.Regions) > 0 && !slices.Contains(c.Regions, c.Region) {
+		return fmt.Errorf("REGION must be one of REGIONS")
+	}
+
 	baseURL := strings.TrimSpace(c.BaseURL)
 	if (hasGitHubClientID || strings.TrimSpace(c.StripeConnectClientID) != "") && baseURL == "" {
 		return fmt.Errorf("BASE_URL is required when OAuth or Stripe Connect is enabled")
@@ -4,31 +4,76 @@ package crypto
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 )
 
 var (
 	ErrMissingKey         = errors.New("encryption key is required")
 	ErrInvalidKey         = errors.New("encryption key must be 32 bytes for AES-256")
 	ErrCiphertextTooShort = errors.New("ciphertext too short")
+	ErrUnknownKey         = errors.New("ciphertext was encrypted with an unknown key")
 )
 
 // Encryptor defines the contract for encrypting/decrypting sensitive values.
 type Encryptor interface {
 	Encrypt(plaintext string) (string, error)
 	Decrypt(ciphertext string) (string, error)
+	// CurrentKeyID returns the ID of the key Encrypt uses, or "" for the unversioned key.
+	CurrentKeyID() string
 }
 
-type aesGCMEncryptor struct {
-	aead cipher.AEAD
+// keyring encrypts with its newest key and decrypts with any of its keys. Ciphertexts written
+// with a versioned key start with "<key ID>:"; ones without a prefix were written with the
+// unversioned key from before keys had IDs.
+type keyring struct {
+	unversioned cipher.AEAD
+	keys        map[string]cipher.AEAD
+	current     string
 }
 
 // NewEncryptor creates an AES-256-GCM encryptor from a 32-byte key.
 func NewEncryptor(key string) (Encryptor, error) {
+	return NewKeyring(key, nil)
+}
+
+// NewKeyring creates an AES-256-GCM encryptor from the unversioned 32-byte key and versioned
+// keys given as "<key ID>:<32-byte key>", oldest first. It encrypts with the last versioned key,
+// or the unversioned key when there are none, and decrypts with any of them.
+func NewKeyring(key string, versioned []string) (Encryptor, error) {
+	unversioned, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ring := &keyring{unversioned: unversioned, keys: map[string]cipher.AEAD{}}
+	for _, entry := range versioned {
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || !validKeyID(id) {
+			return nil, fmt.Errorf("versioned encryption keys must look like <key ID>:<key>, with a key ID of letters, digits, - or _")
+		}
+		if _, exists := ring.keys[id]; exists {
+			return nil, fmt.Errorf("encryption key ID %q is listed twice", id)
+		}
+		aead, err := newAEAD(secret)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		ring.keys[id] = aead
+		ring.current = id
+	}
+
+	return ring, nil
+}
+
+func newAEAD(key string) (cipher.AEAD, error) {
 	if key == "" {
 		return nil, ErrMissingKey
 	}
@@ -48,37 +93,104 @@ func NewEncryptor(key string) (Encryptor, error) {
 		return nil, fmt.Errorf("failed to create GCM: %w", err)
 	}
 
-	return &aesGCMEncryptor{aead: aead}, nil
+	return aead, nil
 }
 
-// Encrypt encrypts plaintext using AES-256-GCM.
-func (e *aesGCMEncryptor) Encrypt(plaintext string) (string, error) {
-	nonce := make([]byte, e.aead.NonceSize())
+func validKeyID(id string) bool {
+	if id == "" || len(id) > 32 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// KeyID returns the ID of the key a ciphertext was encrypted with, or "" for the unversioned
+// key. Base64 never contains ":", so the prefix cannot be confused with ciphertext.
+func KeyID(ciphertext string) string {
+	id, _, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+func (k *keyring) CurrentKeyID() string {
+	return k.current
+}
+
+// Encrypt encrypts plaintext using AES-256-GCM with the current key.
+func (k *keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.unversioned
+	if k.current != "" {
+		aead = k.keys[k.current]
+	}
+
+	nonce := make([]byte, aead.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return "", fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
-	ciphertext := e.aead.Seal(nonce, nonce, []byte(plaintext), nil)
-	return base64.URLEncoding.EncodeToString(ciphertext), nil
+	ciphertext := base64.URLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(plaintext), nil))
+	if k.current != "" {
+		ciphertext = k.current + ":" + ciphertext
+	}
+	return ciphertext, nil
 }
 
-// Decrypt decrypts ciphertext that was encrypted with Encrypt.
-func (e *aesGCMEncryptor) Decrypt(ciphertext string) (string, error) {
+// Decrypt decrypts ciphertext that was encrypted with Encrypt under any key in the keyring.
+func (k *keyring) Decrypt(ciphertext string) (string, error) {
+	aead := k.unversioned
+	if id, encoded, ok := strings.Cut(ciphertext, ":"); ok {
+		if aead, ok = k.keys[id]; !ok {
+			return "", fmt.Errorf("%w %q", ErrUnknownKey, id)
+		}
+		ciphertext = encoded
+	}
+
 	data, err := base64.URLEncoding.DecodeString(ciphertext)
 	if err != nil {
 		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
 	}
 
-	nonceSize := e.aead.NonceSize()
+	nonceSize := aead.NonceSize()
 	if len(data) < nonceSize {
 		return "", ErrCiphertextTooShort
 	}
 
 	nonce, ciphertextBytes := data[:nonceSize], data[nonceSize:]
-	plaintext, err := e.aead.Open(nil, nonce, ciphertextBytes, nil)
+	plaintext, err := aead.Open(nil, nonce, ciphertextBytes, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to decrypt: %w", err)
 	}
 
 	return string(plaintext), nil
 }
+
+// BlindIndex hashes values with a secret key so equal values can be matched in the database
+// without storing them in plaintext.
+type BlindIndex struct {
+	key []byte
+}
+
+// NewBlindIndex creates a blind index keyed with a 32-byte key. Use a key derived for this
+// purpose rather than the encryption key itself.
+func NewBlindIndex(key []byte) (*BlindIndex, error) {
+	if len(key) == 0 {
+		return nil, ErrMissingKey
+	}
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	return &BlindIndex{key: append([]byte(nil), key...)}, nil
+}
+
+// Hash returns the hex HMAC-SHA256 of value.
+func (b *BlindIndex) Hash(value string) string {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
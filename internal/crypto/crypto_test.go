@@ -119,3 +119,104 @@ func TestDecryptErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestBlindIndex(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewBlindIndex(nil); !errors.Is(err, ErrMissingKey) {
+		t.Fatalf("expected ErrMissingKey, got %v", err)
+	}
+	if _, err := NewBlindIndex([]byte("short")); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+
+	indexA, err := NewBlindIndex([]byte(strings.Repeat("a", 32)))
+	if err != nil {
+		t.Fatalf("failed to build blind index A: %v", err)
+	}
+	indexB, err := NewBlindIndex([]byte(strings.Repeat("b", 32)))
+	if err != nil {
+		t.Fatalf("failed to build blind index B: %v", err)
+	}
+
+	first := indexA.Hash("mona@example.com")
+	if first != indexA.Hash("mona@example.com") {
+		t.Fatal("hash should be deterministic")
+	}
+	if first == indexA.Hash("hubot@example.com") {
+		t.Fatal("different values should hash differently")
+	}
+	if first == indexB.Hash("mona@example.com") {
+		t.Fatal("different keys should hash differently")
+	}
+	if strings.Contains(first, "mona") || len(first) != 64 {
+		t.Fatalf("unexpected hash %q", first)
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	t.Parallel()
+
+	legacyKey := strings.Repeat("a", 32)
+	legacy, err := NewEncryptor(legacyKey)
+	if err != nil {
+		t.Fatalf("failed to build legacy encryptor: %v", err)
+	}
+	legacyCiphertext, err := legacy.Encrypt("old-secret")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if legacy.CurrentKeyID() != "" || KeyID(legacyCiphertext) != "" {
+		t.Fatalf("unversioned encryptor should not prefix ciphertexts, got %q", legacyCiphertext)
+	}
+
+	before, err := NewKeyring(legacyKey, []string{"k1:" + strings.Repeat("b", 32)})
+	if err != nil {
+		t.Fatalf("failed to build keyring: %v", err)
+	}
+	k1Ciphertext, err := before.Encrypt("k1-secret")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+
+	ring, err := NewKeyring(legacyKey, []string{"k1:" + strings.Repeat("b", 32), "k2:" + strings.Repeat("c", 32)})
+	if err != nil {
+		t.Fatalf("failed to build keyring: %v", err)
+	}
+	if ring.CurrentKeyID() != "k2" {
+		t.Fatalf("CurrentKeyID() = %q, want k2", ring.CurrentKeyID())
+	}
+	k2Ciphertext, err := ring.Encrypt("k2-secret")
+	if err != nil {
+		t.Fatalf("encrypt failed: %v", err)
+	}
+	if KeyID(k2Ciphertext) != "k2" || KeyID(k1Ciphertext) != "k1" {
+		t.Fatalf("unexpected key IDs for %q and %q", k2Ciphertext, k1Ciphertext)
+	}
+
+	for ciphertext, want := range map[string]string{
+		legacyCiphertext: "old-secret",
+		k1Ciphertext:     "k1-secret",
+		k2Ciphertext:     "k2-secret",
+	} {
+		got, err := ring.Decrypt(ciphertext)
+		if err != nil || got != want {
+			t.Fatalf("Decrypt(%q) = %q, %v, want %q", ciphertext, got, err, want)
+		}
+	}
+	if _, err := legacy.Decrypt(k2Ciphertext); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+
+	for _, versioned := range [][]string{
+		{"k1"},
+		{":" + strings.Repeat("b", 32)},
+		{"k 1:" + strings.Repeat("b", 32)},
+		{"k1:short"},
+		{"k1:" + strings.Repeat("b", 32), "k1:" + strings.Repeat("c", 32)},
+	} {
+		if _, err := NewKeyring(legacyKey, versioned); err == nil {
+			t.Fatalf("NewKeyring(%q) should fail", versioned)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SummarizeAbandonedCheckouts counts the shop's checkouts that expired unpaid since since, and
+// how many of them were paid after all. Test mode orders are left out.
+func (s *OrderStore) SummarizeAbandonedCheckouts(ctx context.Context, shopID uuid.UUID, since time.Time) (AbandonedCheckoutSummary, error) {
+	var summary AbandonedCheckoutSummary
+	err := s.reader().QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = 'expired'),
+		       COALESCE(SUM(total_cents) FILTER (WHERE status = 'expired'), 0),
+		       COUNT(*) FILTER (WHERE paid_at IS NOT NULL),
+		       COALESCE(SUM(total_cents) FILTER (WHERE paid_at IS NOT NULL), 0)
+		FROM orders
+		WHERE shop_id = $1 AND checkout_expired_at >= $2 AND NOT test_mode
+	`, shopID, since).Scan(&summary.Abandoned, &summary.ValueCents, &summary.Recovered, &summary.RecoveredCents)
+	return summary, err
+}
+
+// ListAbandonedSKUs returns the products with the most checkouts that expired unpaid since since
+// and are still unpaid, most abandoned first.
+func (s *OrderStore) ListAbandonedSKUs(ctx context.Context, shopID uuid.UUID, since time.Time, limit int) ([]AbandonedSKU, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT sku, COUNT(*), COALESCE(SUM(total_cents), 0)
+		FROM orders
+		WHERE shop_id = $1 AND status = 'expired' AND checkout_expired_at >= $2 AND NOT test_mode
+		GROUP BY sku
+		ORDER BY COUNT(*) DESC, SUM(total_cents) DESC, sku
+		LIMIT $3
+	`, shopID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[AbandonedSKU])
+}
+
+// ListAbandonedOrders returns the shop's orders whose checkout expired unpaid since since,
+// most recent first.
+func (s *OrderStore) ListAbandonedOrders(ctx context.Context, shopID uuid.UUID, since time.Time, limit int) ([]*Order, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT id
+		FROM orders
+		WHERE shop_id = $1 AND status = 'expired' AND checkout_expired_at >= $2 AND NOT test_mode
+		ORDER BY checkout_expired_at DESC
+		LIMIT $3
+	`, shopID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		order, err := s.GetByID(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
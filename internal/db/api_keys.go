@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const apiKeyColumns = `id, shop_id, name, token_prefix, customer_data, created_by, created_at, last_used_at, revoked_at`
+
+// CreateAPIKey stores a new key for the shop under the hash of its token.
+func (s *ShopStore) CreateAPIKey(ctx context.Context, key *APIKey, tokenHash string) error {
+	return s.pool.QueryRow(ctx, `
+		INSERT INTO api_keys (shop_id, name, token_hash, token_prefix, customer_data, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, key.ShopID, key.Name, tokenHash, key.TokenPrefix, key.CustomerData, key.CreatedBy).Scan(&key.ID, &key.CreatedAt)
+}
+
+// ListAPIKeys returns the shop's keys that have not been revoked, newest first.
+func (s *ShopStore) ListAPIKeys(ctx context.Context, shopID uuid.UUID) ([]*APIKey, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE shop_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, shopID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[APIKey])
+}
+
+// RevokeAPIKey stops one of the shop's keys from working. It fails with pgx.ErrNoRows when the
+// shop has no such active key.
+func (s *ShopStore) RevokeAPIKey(ctx context.Context, shopID, keyID uuid.UUID) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE api_keys SET revoked_at = NOW()
+		WHERE id = $1 AND shop_id = $2 AND revoked_at IS NULL
+	`, keyID, shopID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// UseAPIKey returns the active key with the token hash and records that it was used, or
+// pgx.ErrNoRows when there is none.
+func (s *ShopStore) UseAPIKey(ctx context.Context, tokenHash string) (*APIKey, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+		RETURNING `+apiKeyColumns, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByPos[APIKey])
+}
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SummarizeCampaignRevenue totals the shop's orders paid since since by the campaign that priced
+// them, largest revenue first. Refunded, returned, and test mode orders are left out.
+func (s *OrderStore) SummarizeCampaignRevenue(ctx context.Context, shopID uuid.UUID, since time.Time) ([]CampaignRevenue, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT campaign, COUNT(*), COALESCE(SUM(total_cents), 0)
+		FROM orders
+		WHERE shop_id = $1 AND paid_at >= $2 AND status IN ('paid', 'address_issue', 'shipped', 'delivered') AND NOT test_mode
+		GROUP BY campaign
+		ORDER BY SUM(total_cents) DESC, campaign
+	`, shopID, since)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[CampaignRevenue])
+}
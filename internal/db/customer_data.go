@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/gitshopapp/gitshop/internal/db/queries"
+)
+
+// customerMatch returns the condition selecting a customer's orders and the argument to pass as
+// $2. Emails are encrypted, so they are matched by their blind index.
+func (s *OrderStore) customerMatch(kind CustomerSubjectKind, value string) (string, string, error) {
+	switch kind {
+	case CustomerSubjectEmail:
+		return "customer_email_hash = $2", s.emailHash(value).String, nil
+	case CustomerSubjectGitHubUsername:
+		return "LOWER(github_username) = $2", value, nil
+	default:
+		return "", "", fmt.Errorf("unknown customer subject kind %q", kind)
+	}
+}
+
+// ListCustomerOrders returns the shop's orders placed by the customer, oldest first. value must
+// already be lowercased. A non-empty category keeps only orders for products in that category.
+func (s *OrderStore) ListCustomerOrders(ctx context.Context, shopID uuid.UUID, kind CustomerSubjectKind, value, category string) ([]*Order, error) {
+	match, arg, err := s.customerMatch(kind, value)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT id FROM orders WHERE shop_id = $1 AND ` + match + ` AND ($3 = '' OR category = $3) ORDER BY created_at`
+	rows, err := s.pool.Query(ctx, query, shopID, arg, category)
+	if err != nil {
+		return nil, err
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		order, err := s.GetByID(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// EraseCustomer anonymizes the customer's orders in the shop, clears everything forgetOrderBuyers
+// covers, including the stored Stripe and GitHub webhooks that operators can replay, and records
+// the erasure in the audit log. It returns how many orders were anonymized.
+func (s *OrderStore) EraseCustomer(ctx context.Context, shopID uuid.UUID, kind CustomerSubjectKind, value, subjectHash, erasedBy string) (int, error) {
+	match, arg, err := s.customerMatch(kind, value)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `SELECT id FROM orders WHERE shop_id = $1 AND ` + match + ` FOR UPDATE`
+	rows, err := tx.Query(ctx, query, shopID, arg)
+	if err != nil {
+		return 0, err
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return 0, err
+	}
+	if err := forgetOrderBuyers(ctx, tx, orderIDs); err != nil {
+		return 0, err
+	}
+
+	// Recipients logged before encryption are still plaintext and have no hash until the
+	// encrypt-customer-data backfill runs, so they are matched directly too.
+	redactEmails := `
+		UPDATE order_emails SET recipient = '', recipient_hash = NULL, delivery_detail = ''
+		WHERE (recipient_hash = $1 OR LOWER(recipient) = NULLIF($3, '')) AND order_id IN (SELECT id FROM orders WHERE shop_id = $2)
+	`
+	recipient := ""
+	if kind == CustomerSubjectEmail {
+		recipient = value
+	}
+	if _, err := tx.Exec(ctx, redactEmails, s.emailHash(recipient), shopID, recipient); err != nil {
+		return 0, err
+	}
+
+	audit := `
+		INSERT INTO data_erasures (shop_id, subject_kind, subject_hash, order_count, erased_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(ctx, audit, shopID, string(kind), subjectHash, len(orderIDs), erasedBy); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(orderIDs), nil
+}
+
+// orderBuyerScrubs clear what the database holds about the buyers of the orders in $1 outside
+// the orders' own columns. The customer record goes first, while the orders still name the buyer,
+// and stored inbound webhooks are matched by the order, checkout, and payment IDs Stripe sends
+// back, or by the order issue for GitHub.
+var orderBuyerScrubs = []string{
+	`DELETE FROM customers c
+	 USING orders o
+	 WHERE o.id = ANY($1) AND o.github_username <> ''
+	   AND c.shop_id = o.shop_id AND c.github_username = LOWER(o.github_username)`,
+	`UPDATE order_emails SET recipient = '', recipient_hash = NULL, delivery_detail = '' WHERE order_id = ANY($1)`,
+	`DELETE FROM webhook_deliveries WHERE order_id = ANY($1)`,
+	`UPDATE order_edits SET edited_by = '' WHERE order_id = ANY($1)`,
+	`UPDATE order_reviews SET body = '' WHERE order_id = ANY($1)`,
+	`DELETE FROM order_notes WHERE order_id = ANY($1)`,
+	`DELETE FROM webhook_events w
+	 USING orders o JOIN shops s ON s.id = o.shop_id
+	 WHERE o.id = ANY($1) AND (
+	   (w.provider = 'stripe' AND (
+	     convert_from(w.payload, 'UTF8')::jsonb #>> '{data,object,metadata,order_id}' = o.id::text
+	     OR convert_from(w.payload, 'UTF8')::jsonb #>> '{data,object,id}' IN (o.stripe_checkout_session_id, o.stripe_payment_intent_id)))
+	   OR (w.provider = 'github'
+	     AND convert_from(w.payload, 'UTF8')::jsonb #>> '{repository,id}' = s.github_repo_id::text
+	     AND convert_from(w.payload, 'UTF8')::jsonb #>> '{issue,number}' = o.github_issue_number::text))`,
+	`UPDATE orders
+	 SET customer_email = NULL, customer_name = NULL, customer_email_hash = NULL, shipping_address = NULL, custom_fields = '[]', receipt_url = NULL, tax_id_type = NULL, tax_id = NULL,
+	     github_username = '', anonymized_at = NOW()
+	 WHERE id = ANY($1)`,
+}
+
+// forgetOrderBuyers anonymizes the orders and removes their buyers' customer records, email log
+// recipients, outbound webhook payloads, editor names, review text, seller notes, and stored
+// inbound webhooks. Ratings are kept.
+func forgetOrderBuyers(ctx context.Context, db queries.DBTX, orderIDs []uuid.UUID) error {
+	for _, query := range orderBuyerScrubs {
+		if _, err := db.Exec(ctx, query, orderIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListDataErasures returns the shop's most recent erasures, newest first.
+func (s *OrderStore) ListDataErasures(ctx context.Context, shopID uuid.UUID, limit int) ([]*DataErasure, error) {
+	query := `
+		SELECT id, shop_id, subject_kind, subject_hash, order_count, erased_by, erased_at
+		FROM data_erasures
+		WHERE shop_id = $1
+		ORDER BY erased_at DESC
+		LIMIT $2
+	`
+	rows, err := s.pool.Query(ctx, query, shopID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	erasures := []*DataErasure{}
+	for rows.Next() {
+		var (
+			erasure    DataErasure
+			kind       string
+			orderCount int32
+			erasedAt   pgtype.Timestamptz
+		)
+		if err := rows.Scan(&erasure.ID, &erasure.ShopID, &kind, &erasure.SubjectHash, &orderCount, &erasure.ErasedBy, &erasedAt); err != nil {
+			return nil, err
+		}
+		erasure.SubjectKind = CustomerSubjectKind(kind)
+		erasure.OrderCount = int(orderCount)
+		erasure.ErasedAt = erasedAt.Time.UTC()
+		erasures = append(erasures, &erasure)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return erasures, nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// scrubDB records which tables each statement writes to and the order IDs it was given.
+type scrubDB struct {
+	written map[string][]uuid.UUID
+}
+
+var writtenTable = regexp.MustCompile(`^\s*(?:DELETE FROM|UPDATE)\s+(\w+)`)
+
+func (d *scrubDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	match := writtenTable.FindStringSubmatch(sql)
+	if match == nil {
+		return pgconn.CommandTag{}, fmt.Errorf("unexpected statement %q", sql)
+	}
+	orderIDs, ok := args[0].([]uuid.UUID)
+	if !ok {
+		return pgconn.CommandTag{}, fmt.Errorf("order IDs = %T, want []uuid.UUID", args[0])
+	}
+	if d.written == nil {
+		d.written = make(map[string][]uuid.UUID)
+	}
+	d.written[match[1]] = orderIDs
+	return pgconn.CommandTag{}, nil
+}
+
+func (d *scrubDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, fmt.Errorf("unexpected query")
+}
+
+func (d *scrubDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return &readRow{err: fmt.Errorf("unexpected query row")}
+}
+
+func TestForgetOrderBuyersCoversEveryBuyerTable(t *testing.T) {
+	orderIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	db := &scrubDB{}
+	if err := forgetOrderBuyers(context.Background(), db, orderIDs); err != nil {
+		t.Fatalf("forgetOrderBuyers() error = %v", err)
+	}
+
+	for _, table := range []string{"orders", "customers", "order_emails", "webhook_deliveries", "order_edits", "order_reviews", "order_notes", "webhook_events"} {
+		got, ok := db.written[table]
+		if !ok {
+			t.Errorf("%s was not scrubbed", table)
+			continue
+		}
+		if len(got) != len(orderIDs) || got[0] != orderIDs[0] || got[1] != orderIDs[1] {
+			t.Errorf("%s scrubbed for %v, want %v", table, got, orderIDs)
+		}
+	}
+}
+
+func TestForgetOrderBuyersRemovesCustomersBeforeAnonymizingOrders(t *testing.T) {
+	customers, orders := -1, -1
+	for i, query := range orderBuyerScrubs {
+		switch writtenTable.FindStringSubmatch(query)[1] {
+		case "customers":
+			customers = i
+		case "orders":
+			orders = i
+		}
+	}
+	if customers < 0 || orders < 0 || customers > orders {
+		t.Fatalf("customers scrubbed at %d, orders at %d; the customer record must go while orders still name the buyer", customers, orders)
+	}
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxRelatedOrders caps how many of a buyer's other orders are linked from an order's page.
+const maxRelatedOrders = 20
+
+const customerColumns = `id, shop_id, github_username, COALESCE(email_hash, ''), orders_count, lifetime_value_cents,
+		       first_order_at, last_order_at, notes, notes_updated_by, created_at, updated_at`
+
+// ListRelatedOrders returns the buyer's other paid orders in the shop, newest first. GitHub
+// usernames are case-insensitive, and orders only match others placed in the same mode, so a
+// test order is never linked to real ones.
+func (s *OrderStore) ListRelatedOrders(ctx context.Context, order *Order) ([]RelatedOrder, error) {
+	username := strings.TrimSpace(order.GitHubUsername)
+	if username == "" {
+		return nil, nil
+	}
+	rows, err := s.reader().Query(ctx, `
+		SELECT id, order_number, status, total_cents, created_at
+		FROM orders
+		WHERE shop_id = $1 AND LOWER(github_username) = LOWER($2) AND id <> $3 AND paid_at IS NOT NULL AND test_mode = $4
+		ORDER BY created_at DESC
+		LIMIT $5
+	`, order.ShopID, username, order.ID, order.TestMode, maxRelatedOrders)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[RelatedOrder])
+}
+
+// CountPreviousOrders counts the buyer's paid orders in the shop placed before order.
+func (s *OrderStore) CountPreviousOrders(ctx context.Context, order *Order) (int, error) {
+	username := strings.TrimSpace(order.GitHubUsername)
+	if username == "" {
+		return 0, nil
+	}
+	var count int
+	err := s.reader().QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM orders
+		WHERE shop_id = $1 AND LOWER(github_username) = LOWER($2) AND id <> $3 AND paid_at IS NOT NULL AND test_mode = $4 AND created_at < $5
+	`, order.ShopID, username, order.ID, order.TestMode, order.CreatedAt).Scan(&count)
+	return count, err
+}
+
+// RefreshCustomer recomputes the customer record of the buyer who placed the order from their
+// paid orders, creating it on their first one. Test mode orders and erased buyers are left out.
+// It is safe to call again for the same order.
+func (s *OrderStore) RefreshCustomer(ctx context.Context, orderID uuid.UUID) error {
+	_, err := s.pool.Exec(ctx, `
+		WITH buyer AS (
+			SELECT shop_id, LOWER(github_username) AS github_username
+			FROM orders
+			WHERE id = $1 AND github_username <> ''
+		)
+		INSERT INTO customers (shop_id, github_username, email_hash, orders_count, lifetime_value_cents, first_order_at, last_order_at)
+		SELECT buyer.shop_id, buyer.github_username,
+		       (ARRAY_AGG(o.customer_email_hash ORDER BY o.created_at DESC) FILTER (WHERE o.customer_email_hash IS NOT NULL))[1],
+		       COUNT(*),
+		       COALESCE(SUM(o.total_cents) FILTER (WHERE o.status IN ('paid', 'address_issue', 'shipped', 'delivered')), 0),
+		       MIN(o.created_at), MAX(o.created_at)
+		FROM buyer
+		JOIN orders o ON o.shop_id = buyer.shop_id AND LOWER(o.github_username) = buyer.github_username
+		WHERE o.paid_at IS NOT NULL AND NOT o.test_mode
+		GROUP BY buyer.shop_id, buyer.github_username
+		ON CONFLICT (shop_id, github_username) DO UPDATE
+		SET email_hash = EXCLUDED.email_hash,
+		    orders_count = EXCLUDED.orders_count,
+		    lifetime_value_cents = EXCLUDED.lifetime_value_cents,
+		    first_order_at = EXCLUDED.first_order_at,
+		    last_order_at = EXCLUDED.last_order_at,
+		    updated_at = NOW()
+	`, orderID)
+	return err
+}
+
+// ListTopCustomers returns the shop's customers with the highest lifetime value first.
+func (s *OrderStore) ListTopCustomers(ctx context.Context, shopID uuid.UUID, limit int) ([]*Customer, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT `+customerColumns+`
+		FROM customers
+		WHERE shop_id = $1
+		ORDER BY lifetime_value_cents DESC, orders_count DESC, github_username
+		LIMIT $2
+	`, shopID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByPos[Customer])
+}
+
+// GetCustomer returns one of the shop's customers, or pgx.ErrNoRows when the shop has no such
+// customer.
+func (s *OrderStore) GetCustomer(ctx context.Context, shopID, customerID uuid.UUID) (*Customer, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+customerColumns+`
+		FROM customers
+		WHERE id = $1 AND shop_id = $2
+	`, customerID, shopID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByPos[Customer])
+}
+
+// SetCustomerNotes replaces the seller's private notes on a customer. It fails with
+// pgx.ErrNoRows when the shop has no such customer.
+func (s *OrderStore) SetCustomerNotes(ctx context.Context, shopID, customerID uuid.UUID, notes, updatedBy string) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE customers
+		SET notes = $3, notes_updated_by = $4, updated_at = NOW()
+		WHERE id = $1 AND shop_id = $2
+	`, customerID, shopID, notes, updatedBy)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxDeliveryDetail bounds the provider's explanation kept for a bounce.
+const maxDeliveryDetail = 500
+
+// RecordEmailDelivery updates the latest email of the given kind sent for one of the shop's
+// orders with what the provider reported. It returns false when no such email was logged. A
+// later "delivered" report does not hide an earlier bounce or complaint.
+func (s *OrderStore) RecordEmailDelivery(ctx context.Context, shopID, orderID uuid.UUID, kind OrderEmailKind, status EmailDeliveryStatus, detail string) (bool, error) {
+	query := `
+		UPDATE order_emails
+		SET delivery_status = $4, delivery_detail = $5, delivery_updated_at = NOW()
+		WHERE id = (
+			SELECT e.id
+			FROM order_emails e
+			JOIN orders o ON o.id = e.order_id
+			WHERE e.order_id = $1 AND o.shop_id = $2 AND e.kind = $3
+			ORDER BY e.sent_at DESC
+			LIMIT 1
+		)
+		AND ($4 <> 'delivered' OR delivery_status IN ('sent', 'delivered'))
+	`
+	result, err := s.pool.Exec(ctx, query, orderID, shopID, string(kind), string(status), truncateDetail(detail))
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// SuppressEmailRecipient stops the shop from emailing an address that hard-bounced or
+// complained. Only the address's blind index is stored.
+func (s *OrderStore) SuppressEmailRecipient(ctx context.Context, shopID uuid.UUID, recipient string, reason EmailDeliveryStatus, detail string) error {
+	hash := s.emailHash(recipient)
+	if !hash.Valid {
+		return nil
+	}
+	query := `
+		INSERT INTO email_suppressions (shop_id, recipient_hash, reason, detail)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (shop_id, recipient_hash) DO UPDATE
+		SET reason = EXCLUDED.reason, detail = EXCLUDED.detail, created_at = NOW()
+	`
+	_, err := s.pool.Exec(ctx, query, shopID, hash, string(reason), truncateDetail(detail))
+	return err
+}
+
+// IsEmailRecipientSuppressed reports whether the shop stopped emailing the address.
+func (s *OrderStore) IsEmailRecipientSuppressed(ctx context.Context, shopID uuid.UUID, recipient string) (bool, error) {
+	hash := s.emailHash(recipient)
+	if !hash.Valid {
+		return false, nil
+	}
+	var suppressed bool
+	query := `SELECT EXISTS (SELECT 1 FROM email_suppressions WHERE shop_id = $1 AND recipient_hash = $2)`
+	if err := s.pool.QueryRow(ctx, query, shopID, hash).Scan(&suppressed); err != nil {
+		return false, err
+	}
+	return suppressed, nil
+}
+
+// EmailFromBounce is set on a shop when mail to its own from address hard-bounced, so buyers'
+// replies to order emails go nowhere.
+type EmailFromBounce struct {
+	BouncedAt time.Time
+	Detail    string
+}
+
+// FlagEmailFromBounce records that the shop's from address hard-bounced.
+func (s *ShopStore) FlagEmailFromBounce(ctx context.Context, shopID uuid.UUID, detail string) error {
+	query := `UPDATE shops SET email_from_bounced_at = NOW(), email_from_bounce_detail = $2 WHERE id = $1`
+	_, err := s.pool.Exec(ctx, query, shopID, truncateDetail(detail))
+	return err
+}
+
+// ClearEmailFromBounce drops the flag once the seller has saved new email settings.
+func (s *ShopStore) ClearEmailFromBounce(ctx context.Context, shopID uuid.UUID) error {
+	query := `UPDATE shops SET email_from_bounced_at = NULL, email_from_bounce_detail = '' WHERE id = $1 AND email_from_bounced_at IS NOT NULL`
+	_, err := s.pool.Exec(ctx, query, shopID)
+	return err
+}
+
+// GetEmailFromBounce returns the shop's from address bounce, or nil when there is none.
+func (s *ShopStore) GetEmailFromBounce(ctx context.Context, shopID uuid.UUID) (*EmailFromBounce, error) {
+	var (
+		bouncedAt pgtype.Timestamptz
+		detail    string
+	)
+	query := `SELECT email_from_bounced_at, email_from_bounce_detail FROM shops WHERE id = $1`
+	if err := s.pool.QueryRow(ctx, query, shopID).Scan(&bouncedAt, &detail); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !bouncedAt.Valid {
+		return nil, nil
+	}
+	return &EmailFromBounce{BouncedAt: bouncedAt.Time, Detail: detail}, nil
+}
+
+func truncateDetail(detail string) string {
+	runes := []rune(detail)
+	if len(runes) > maxDeliveryDetail {
+		return string(runes[:maxDeliveryDetail])
+	}
+	return detail
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gitshopapp/gitshop/internal/featureflags"
+)
+
+// FeatureFlagStore reads the operator overrides of feature flag defaults.
+type FeatureFlagStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewFeatureFlagStore(pool *pgxpool.Pool) *FeatureFlagStore {
+	return &FeatureFlagStore{pool: pool}
+}
+
+// FeatureFlagOverrides loads every override, global and per shop.
+func (s *FeatureFlagStore) FeatureFlagOverrides(ctx context.Context) (featureflags.Overrides, error) {
+	overrides := featureflags.Overrides{
+		Global: map[featureflags.Flag]featureflags.Rule{},
+		Shops:  map[featureflags.Flag]map[uuid.UUID]bool{},
+	}
+	rows, err := s.pool.Query(ctx, `SELECT flag, shop_id, enabled, rollout_percent FROM feature_flag_overrides`)
+	if err != nil {
+		return overrides, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name    string
+			shopID  pgtype.UUID
+			enabled bool
+			percent int16
+		)
+		if err := rows.Scan(&name, &shopID, &enabled, &percent); err != nil {
+			return overrides, err
+		}
+		flag := featureflags.Flag(name)
+		if !shopID.Valid {
+			overrides.Global[flag] = featureflags.Rule{Enabled: enabled, Percent: int(percent)}
+			continue
+		}
+		if overrides.Shops[flag] == nil {
+			overrides.Shops[flag] = map[uuid.UUID]bool{}
+		}
+		overrides.Shops[flag][uuid.UUID(shopID.Bytes)] = enabled
+	}
+	return overrides, rows.Err()
+}
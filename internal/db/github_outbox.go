@@ -0,0 +1,164 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	githubEffectPending = "pending"
+	githubEffectDone    = "done"
+	githubEffectFailed  = "failed"
+)
+
+type GitHubOutboxStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewGitHubOutboxStore(pool *pgxpool.Pool) *GitHubOutboxStore {
+	return &GitHubOutboxStore{pool: pool}
+}
+
+// Enqueue stores effects in the order given. An effect whose idempotency key is already queued is
+// ignored, so enqueueing the same batch twice applies it once.
+func (s *GitHubOutboxStore) Enqueue(ctx context.Context, effects []*GitHubEffect) error {
+	if len(effects) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO github_outbox (idempotency_key, shop_id, order_id, installation_id, repo_full_name, issue_number, kind, value, position, event_kind, event_detail)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+	batch := &pgx.Batch{}
+	for i, effect := range effects {
+		orderID := pgtype.UUID{Bytes: effect.OrderID, Valid: effect.OrderID != uuid.Nil}
+		batch.Queue(query, effect.IdempotencyKey, effect.ShopID, orderID, effect.InstallationID, effect.RepoFullName, effect.IssueNumber, string(effect.Kind), effect.Value, i, string(effect.EventKind), effect.EventDetail)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ClaimDue locks due effects for shops in region, or every shop when region is empty, and pushes
+// their next attempt out by lease so other instances skip them while they are applied.
+func (s *GitHubOutboxStore) ClaimDue(ctx context.Context, region string, limit int, lease time.Duration) ([]*GitHubEffect, error) {
+	query := `
+		UPDATE github_outbox
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT o.id FROM github_outbox o
+			JOIN shops s ON s.id = o.shop_id
+			WHERE o.status = $3 AND o.next_attempt_at <= NOW() AND ($4 = '' OR s.region IN ('', $4))
+			ORDER BY o.created_at, o.position
+			LIMIT $1
+			FOR UPDATE OF o SKIP LOCKED
+		)
+		RETURNING id, idempotency_key, shop_id, order_id, installation_id, repo_full_name, issue_number, kind, value, attempts, created_at, position, event_kind, event_detail
+	`
+	rows, err := s.pool.Query(ctx, query, limit, lease.Seconds(), githubEffectPending, region)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type claimed struct {
+		effect   *GitHubEffect
+		position int32
+	}
+	var effects []claimed
+	for rows.Next() {
+		var (
+			effect      GitHubEffect
+			orderID     pgtype.UUID
+			issueNumber int32
+			kind        string
+			attempts    int32
+			createdAt   pgtype.Timestamptz
+			position    int32
+			eventKind   string
+		)
+		if err := rows.Scan(&effect.ID, &effect.IdempotencyKey, &effect.ShopID, &orderID, &effect.InstallationID, &effect.RepoFullName,
+			&issueNumber, &kind, &effect.Value, &attempts, &createdAt, &position, &eventKind, &effect.EventDetail); err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			effect.OrderID = orderID.Bytes
+		}
+		effect.IssueNumber = int(issueNumber)
+		effect.Kind = GitHubEffectKind(kind)
+		effect.EventKind = OrderEventKind(eventKind)
+		effect.Attempts = int(attempts)
+		effect.CreatedAt = createdAt.Time.UTC()
+		effects = append(effects, claimed{effect: &effect, position: position})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING does not keep the subquery's order.
+	slices.SortStableFunc(effects, func(a, b claimed) int {
+		return cmp.Or(a.effect.CreatedAt.Compare(b.effect.CreatedAt), cmp.Compare(a.position, b.position))
+	})
+	ordered := make([]*GitHubEffect, 0, len(effects))
+	for _, c := range effects {
+		ordered = append(ordered, c.effect)
+	}
+	return ordered, nil
+}
+
+// MarkDone records an applied effect, and adds the effect's order event, if it has one, to the
+// order's event log.
+func (s *GitHubOutboxStore) MarkDone(ctx context.Context, id uuid.UUID) error {
+	query := `
+		WITH done AS (
+			UPDATE github_outbox
+			SET status = $1, attempts = attempts + 1, last_error = NULL, completed_at = NOW()
+			WHERE id = $2
+			RETURNING order_id, event_kind, event_detail
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT order_id, event_kind, event_detail
+		FROM done
+		WHERE event_kind <> '' AND order_id IS NOT NULL
+	`
+	_, err := s.pool.Exec(ctx, query, githubEffectDone, id)
+	return err
+}
+
+// MarkRetry records a failed attempt and schedules the next one.
+func (s *GitHubOutboxStore) MarkRetry(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE github_outbox
+		SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+		WHERE id = $3
+	`
+	_, err := s.pool.Exec(ctx, query, lastError, nextAttemptAt, id)
+	return err
+}
+
+// MarkFailed records the final failed attempt; the effect is not retried again.
+func (s *GitHubOutboxStore) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE github_outbox
+		SET status = $1, attempts = attempts + 1, last_error = $2
+		WHERE id = $3
+	`
+	_, err := s.pool.Exec(ctx, query, githubEffectFailed, lastError, id)
+	return err
+}
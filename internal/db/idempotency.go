@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+// IdempotentResponse is the stored result of the first request made with an idempotency key.
+// StatusCode is zero while that request is still running.
+type IdempotentResponse struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       string
+}
+
+// IdempotencyScope is who an idempotency key belongs to: the signed-in user in the installation
+// they are working in. The same key sent by another session is a different request.
+type IdempotencyScope struct {
+	InstallationID int64
+	UserID         int64
+}
+
+type IdempotencyStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyStore(pool *pgxpool.Pool) *IdempotencyStore {
+	return &IdempotencyStore{pool: pool}
+}
+
+// Claim reserves key for path within scope until ttl passes. It reports false when another
+// request already holds an unexpired claim on the same key in the same scope. An expired claim
+// on the key is taken over, so expired rows only need pruning for space.
+func (s *IdempotencyStore) Claim(ctx context.Context, scope IdempotencyScope, key, path string, ttl time.Duration) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (installation_id, user_id, key, path, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+		ON CONFLICT (installation_id, user_id, key, path) DO UPDATE
+		SET status_code = NULL,
+		    headers = NULL,
+		    body = NULL,
+		    created_at = NOW(),
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < NOW()
+	`
+	tag, err := s.pool.Exec(ctx, query, scope.InstallationID, scope.UserID, key, path, ttl.Seconds())
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// DeleteExpired removes expired claims and returns how many were removed.
+func (s *IdempotencyStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+func (s *IdempotencyStore) Get(ctx context.Context, scope IdempotencyScope, key, path string) (*IdempotentResponse, error) {
+	query := `
+		SELECT status_code, headers, body
+		FROM idempotency_keys
+		WHERE installation_id = $1 AND user_id = $2 AND key = $3 AND path = $4 AND expires_at >= NOW()
+	`
+
+	var (
+		statusCode pgtype.Int4
+		headers    map[string]string
+		body       pgtype.Text
+	)
+	err := s.pool.QueryRow(ctx, query, scope.InstallationID, scope.UserID, key, path).Scan(&statusCode, &headers, &body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &IdempotentResponse{
+		StatusCode: int(statusCode.Int32),
+		Headers:    headers,
+		Body:       body.String,
+	}, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, scope IdempotencyScope, key, path string, response *IdempotentResponse) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, headers = $2, body = $3
+		WHERE installation_id = $4 AND user_id = $5 AND key = $6 AND path = $7
+	`
+	_, err := s.pool.Exec(ctx, query, response.StatusCode, response.Headers, response.Body, scope.InstallationID, scope.UserID, key, path)
+	return err
+}
+
+// Release drops a claim so the same key can be submitted again, e.g. after a failed request.
+func (s *IdempotencyStore) Release(ctx context.Context, scope IdempotencyScope, key, path string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE installation_id = $1 AND user_id = $2 AND key = $3 AND path = $4
+	`
+	_, err := s.pool.Exec(ctx, query, scope.InstallationID, scope.UserID, key, path)
+	return err
+}
@@ -0,0 +1,205 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gitshopapp/gitshop/internal/crypto"
+)
+
+// Kinds of stored secrets counted by KeyRotationStore.StaleCounts.
+const (
+	SecretKindShopEmail       = "shop_email"
+	SecretKindShopPayment     = "shop_payment"
+	SecretKindWebhookEndpoint = "webhook_endpoint"
+	SecretKindOrderCustomer   = "order_customer"
+)
+
+// KeyRotationStore re-encrypts the secrets stored on shops and webhook endpoints with the current
+// encryption key. Customer details on orders and their email log are rotated by
+// OrderStore.EncryptCustomerData.
+type KeyRotationStore struct {
+	pool   *pgxpool.Pool
+	crypto crypto.Encryptor
+}
+
+func NewKeyRotationStore(pool *pgxpool.Pool, encryptor crypto.Encryptor) (*KeyRotationStore, error) {
+	if encryptor == nil {
+		return nil, fmt.Errorf("encryptor is required")
+	}
+
+	return &KeyRotationStore{
+		pool:   pool,
+		crypto: encryptor,
+	}, nil
+}
+
+// StaleCounts returns how many rows of each secret kind are still plaintext or encrypted with an
+// older key.
+func (s *KeyRotationStore) StaleCounts(ctx context.Context) (map[string]int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM shops WHERE ` + staleSecretCondition("email_config->>'api_key'") + ` OR ` + staleSecretCondition("email_config->>'webhook_secret'") + `),
+			(SELECT COUNT(*) FROM shops WHERE ` + staleSecretCondition("payment_config->>'api_key'") + ` OR ` + staleSecretCondition("payment_config->>'webhook_secret'") + `),
+			(SELECT COUNT(*) FROM shop_webhooks WHERE ` + staleSecretCondition("secret") + `),
+			(SELECT COUNT(*) FROM orders
+			 WHERE ` + staleSecretCondition("customer_email") + `
+			    OR ` + staleSecretCondition("customer_name") + `
+			    OR jsonb_typeof(shipping_address) = 'object'
+			    OR (jsonb_typeof(shipping_address) = 'string' AND ` + staleSecretCondition("shipping_address #>> '{}'") + `)
+			    OR EXISTS (SELECT 1 FROM order_emails e WHERE e.order_id = orders.id AND ` + staleSecretCondition("e.recipient") + `))
+	`
+	var shopEmail, shopPayment, webhookEndpoint, orderCustomer int
+	if err := s.pool.QueryRow(ctx, query, s.crypto.CurrentKeyID()).Scan(&shopEmail, &shopPayment, &webhookEndpoint, &orderCustomer); err != nil {
+		return nil, err
+	}
+	return map[string]int{
+		SecretKindShopEmail:       shopEmail,
+		SecretKindShopPayment:     shopPayment,
+		SecretKindWebhookEndpoint: webhookEndpoint,
+		SecretKindOrderCustomer:   orderCustomer,
+	}, nil
+}
+
+// RotateShopSecrets re-encrypts the email and payment credentials of up to limit shops
+// after the given ID, in ID order. It returns the last shop ID read, or uuid.Nil once every shop
+// has been read, and how many shops were rewritten.
+func (s *KeyRotationStore) RotateShopSecrets(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, COALESCE(email_config->>'api_key', ''), COALESCE(email_config->>'webhook_secret', ''), COALESCE(payment_config->>'api_key', ''), COALESCE(payment_config->>'webhook_secret', '')
+		FROM shops
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`, after, limit)
+	if err != nil {
+		return after, 0, err
+	}
+	type storedShop struct {
+		ID                   uuid.UUID
+		EmailAPIKey          string
+		EmailWebhookSecret   string
+		PaymentAPIKey        string
+		PaymentWebhookSecret string
+	}
+	stored, err := pgx.CollectRows(rows, pgx.RowToStructByPos[storedShop])
+	if err != nil {
+		return after, 0, err
+	}
+	if len(stored) == 0 {
+		return uuid.Nil, 0, nil
+	}
+
+	updated := 0
+	for _, shop := range stored {
+		after = shop.ID
+		// Email API keys saved before encryption are still read as plaintext, so they are
+		// encrypted here too.
+		emailAPIKey, emailChanged, err := s.rotate(shop.EmailAPIKey, true)
+		if err != nil {
+			return after, updated, fmt.Errorf("shop %s email API key: %w", shop.ID, err)
+		}
+		emailWebhookSecret, emailSecretChanged, err := s.rotate(shop.EmailWebhookSecret, false)
+		if err != nil {
+			return after, updated, fmt.Errorf("shop %s email webhook secret: %w", shop.ID, err)
+		}
+		paymentAPIKey, apiKeyChanged, err := s.rotate(shop.PaymentAPIKey, false)
+		if err != nil {
+			return after, updated, fmt.Errorf("shop %s payment API key: %w", shop.ID, err)
+		}
+		webhookSecret, secretChanged, err := s.rotate(shop.PaymentWebhookSecret, false)
+		if err != nil {
+			return after, updated, fmt.Errorf("shop %s payment webhook secret: %w", shop.ID, err)
+		}
+		if !emailChanged && !emailSecretChanged && !apiKeyChanged && !secretChanged {
+			continue
+		}
+
+		if _, err := s.pool.Exec(ctx, `
+			UPDATE shops
+			SET email_config = CASE WHEN $2 THEN email_config || jsonb_strip_nulls(jsonb_build_object('api_key', NULLIF($3::text, ''), 'webhook_secret', NULLIF($7::text, ''))) ELSE email_config END,
+			    payment_config = CASE WHEN $4 THEN jsonb_set(jsonb_set(payment_config, '{api_key}', to_jsonb($5::text)), '{webhook_secret}', to_jsonb($6::text)) ELSE payment_config END
+			WHERE id = $1
+		`, shop.ID, emailChanged || emailSecretChanged, emailAPIKey, apiKeyChanged || secretChanged, paymentAPIKey, webhookSecret, emailWebhookSecret); err != nil {
+			return after, updated, fmt.Errorf("failed to rotate secrets of shop %s: %w", shop.ID, err)
+		}
+		updated++
+	}
+	return after, updated, nil
+}
+
+// RotateWebhookSecrets re-encrypts the signing secrets of up to limit webhook endpoints after
+// the given shop ID, in shop ID order, with the same return values as RotateShopSecrets.
+func (s *KeyRotationStore) RotateWebhookSecrets(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT shop_id, secret
+		FROM shop_webhooks
+		WHERE shop_id > $1
+		ORDER BY shop_id
+		LIMIT $2
+	`, after, limit)
+	if err != nil {
+		return after, 0, err
+	}
+	type storedEndpoint struct {
+		ShopID uuid.UUID
+		Secret string
+	}
+	stored, err := pgx.CollectRows(rows, pgx.RowToStructByPos[storedEndpoint])
+	if err != nil {
+		return after, 0, err
+	}
+	if len(stored) == 0 {
+		return uuid.Nil, 0, nil
+	}
+
+	updated := 0
+	for _, endpoint := range stored {
+		after = endpoint.ShopID
+		secret, changed, err := s.rotate(endpoint.Secret, false)
+		if err != nil {
+			return after, updated, fmt.Errorf("webhook endpoint of shop %s: %w", endpoint.ShopID, err)
+		}
+		if !changed {
+			continue
+		}
+		if _, err := s.pool.Exec(ctx, `UPDATE shop_webhooks SET secret = $2 WHERE shop_id = $1`, endpoint.ShopID, secret); err != nil {
+			return after, updated, fmt.Errorf("failed to rotate webhook secret of shop %s: %w", endpoint.ShopID, err)
+		}
+		updated++
+	}
+	return after, updated, nil
+}
+
+// rotate re-encrypts a stored secret with the current key and reports whether it changed. Values
+// already under the current key are returned as they are. When plaintextOK is set, a value that
+// does not decrypt is taken to be plaintext saved before encryption.
+func (s *KeyRotationStore) rotate(value string, plaintextOK bool) (string, bool, error) {
+	if value == "" {
+		return value, false, nil
+	}
+	plaintext, err := s.crypto.Decrypt(value)
+	if err != nil {
+		if !plaintextOK || looksSealed(value) {
+			return value, false, err
+		}
+		plaintext = value
+	} else if crypto.KeyID(value) == s.crypto.CurrentKeyID() {
+		return value, false, nil
+	}
+	ciphertext, err := s.crypto.Encrypt(plaintext)
+	if err != nil {
+		return value, false, err
+	}
+	return ciphertext, true, nil
+}
+
+// staleSecretCondition matches rows where the encrypted value expr is set and was not written
+// with the current key, passed as $1. Values without a key ID prefix count as the unversioned key.
+func staleSecretCondition(expr string) string {
+	return "(COALESCE(" + expr + ", '') <> '' AND COALESCE(NULLIF(split_part(" + expr + ", ':', 1), " + expr + "), '') <> $1)"
+}
@@ -5,13 +5,170 @@ import "github.com/gitshopapp/gitshop/internal/models"
 type Shop = models.Shop
 type Order = models.Order
 type OrderStatus = models.OrderStatus
+type ProcessingRegion = models.ProcessingRegion
+type StripeCapabilities = models.StripeCapabilities
+type ShopSuspension = models.ShopSuspension
+type SetupPullRequest = models.SetupPullRequest
+type RepoOrphanReason = models.RepoOrphanReason
+type OrphanedShop = models.OrphanedShop
+type OrderEmail = models.OrderEmail
+type ShopOrderSummary = models.ShopOrderSummary
+type AbandonedCheckoutSummary = models.AbandonedCheckoutSummary
+type AbandonedSKU = models.AbandonedSKU
+type CampaignRevenue = models.CampaignRevenue
+type RelatedOrder = models.RelatedOrder
+type WarehouseOrder = models.WarehouseOrder
+type WarehouseOrderEvent = models.WarehouseOrderEvent
+type OrderEmailKind = models.OrderEmailKind
+type EmailDeliveryStatus = models.EmailDeliveryStatus
+type OrderEdit = models.OrderEdit
+type OrderEvent = models.OrderEvent
+type OrderEventKind = models.OrderEventKind
+type OrderNote = models.OrderNote
+type OrderReview = models.OrderReview
+type ReviewSource = models.ReviewSource
+type ProductRating = models.ProductRating
+type OrderCustomField = models.OrderCustomField
+type VerificationStatus = models.VerificationStatus
+type CheckoutMethod = models.CheckoutMethod
+type SubscriptionStatus = models.SubscriptionStatus
+type PaymentProcessor = models.PaymentProcessor
+type PaymentSettings = models.PaymentSettings
+type LemonSqueezySettings = models.LemonSqueezySettings
+type WebhookEvent = models.WebhookEvent
+type WebhookEndpoint = models.WebhookEndpoint
+type WebhookDelivery = models.WebhookDelivery
+type WebhookDeliveryStatus = models.WebhookDeliveryStatus
+type QueuedWebhook = models.QueuedWebhook
+type ReceivedWebhook = models.ReceivedWebhook
+type ReceivedWebhookStatus = models.ReceivedWebhookStatus
+type WebhookCheckpoint = models.WebhookCheckpoint
+type CustomerSubjectKind = models.CustomerSubjectKind
+type DataErasure = models.DataErasure
+type Customer = models.Customer
+type APIKey = models.APIKey
+type GitHubEffect = models.GitHubEffect
+type GitHubEffectKind = models.GitHubEffectKind
+type OperatorAction = models.OperatorAction
+type OperatorAuditEntry = models.OperatorAuditEntry
 
 const (
-	StatusPendingPayment = models.StatusPendingPayment
-	StatusPaid           = models.StatusPaid
-	StatusPaymentFailed  = models.StatusPaymentFailed
-	StatusExpired        = models.StatusExpired
-	StatusShipped        = models.StatusShipped
-	StatusDelivered      = models.StatusDelivered
-	StatusRefunded       = models.StatusRefunded
+	StatusPendingPayment   = models.StatusPendingPayment
+	StatusPaid             = models.StatusPaid
+	StatusPaymentFailed    = models.StatusPaymentFailed
+	StatusExpired          = models.StatusExpired
+	StatusShipped          = models.StatusShipped
+	StatusDelivered        = models.StatusDelivered
+	StatusRefunded         = models.StatusRefunded
+	StatusInquiry          = models.StatusInquiry
+	StatusReturned         = models.StatusReturned
+	StatusCancelled        = models.StatusCancelled
+	StatusAwaitingApproval = models.StatusAwaitingApproval
+	StatusAddressIssue     = models.StatusAddressIssue
+)
+
+const (
+	RepoOrphanNotFound                = models.RepoOrphanNotFound
+	RepoOrphanInstallationUnavailable = models.RepoOrphanInstallationUnavailable
+	RepoOrphanTransferred             = models.RepoOrphanTransferred
+)
+
+const (
+	SubscriptionActive    = models.SubscriptionActive
+	SubscriptionCancelled = models.SubscriptionCancelled
+)
+
+const (
+	OrderEmailConfirmation = models.OrderEmailConfirmation
+	OrderEmailShipped      = models.OrderEmailShipped
+	OrderEmailDelivered    = models.OrderEmailDelivered
+	OrderEmailReturned     = models.OrderEmailReturned
+)
+
+const (
+	OrderEventCreated         = models.OrderEventCreated
+	OrderEventCheckoutCreated = models.OrderEventCheckoutCreated
+	OrderEventPaid            = models.OrderEventPaid
+	OrderEventCommentPosted   = models.OrderEventCommentPosted
+	OrderEventEmailSent       = models.OrderEventEmailSent
+	OrderEventShipped         = models.OrderEventShipped
+	OrderEventDelivered       = models.OrderEventDelivered
+	OrderEventFailed          = models.OrderEventFailed
+)
+
+const (
+	EmailSent       = models.EmailSent
+	EmailDelivered  = models.EmailDelivered
+	EmailBounced    = models.EmailBounced
+	EmailComplained = models.EmailComplained
+)
+
+const (
+	ReviewSourceReaction = models.ReviewSourceReaction
+	ReviewSourceComment  = models.ReviewSourceComment
+)
+
+const (
+	VerificationAttested      = models.VerificationAttested
+	VerificationPending       = models.VerificationPending
+	VerificationVerified      = models.VerificationVerified
+	VerificationRequiresInput = models.VerificationRequiresInput
+	VerificationRejected      = models.VerificationRejected
+)
+
+const (
+	WebhookOrderCreated   = models.WebhookOrderCreated
+	WebhookOrderPaid      = models.WebhookOrderPaid
+	WebhookOrderShipped   = models.WebhookOrderShipped
+	WebhookOrderDelivered = models.WebhookOrderDelivered
+	WebhookOrderReturned  = models.WebhookOrderReturned
+)
+
+const (
+	WebhookDeliveryPending   = models.WebhookDeliveryPending
+	WebhookDeliverySucceeded = models.WebhookDeliverySucceeded
+	WebhookDeliveryFailed    = models.WebhookDeliveryFailed
+)
+
+const (
+	ReceivedWebhookReceived  = models.ReceivedWebhookReceived
+	ReceivedWebhookQueued    = models.ReceivedWebhookQueued
+	ReceivedWebhookProcessed = models.ReceivedWebhookProcessed
+	ReceivedWebhookFailed    = models.ReceivedWebhookFailed
+)
+
+const (
+	CustomerSubjectEmail          = models.CustomerSubjectEmail
+	CustomerSubjectGitHubUsername = models.CustomerSubjectGitHubUsername
+)
+
+const (
+	CheckoutMethodSession      = models.CheckoutMethodSession
+	CheckoutMethodPaymentLink  = models.CheckoutMethodPaymentLink
+	CheckoutMethodLemonSqueezy = models.CheckoutMethodLemonSqueezy
+)
+
+const (
+	GitHubEffectComment                = models.GitHubEffectComment
+	GitHubEffectAddLabel               = models.GitHubEffectAddLabel
+	GitHubEffectRemoveLabel            = models.GitHubEffectRemoveLabel
+	GitHubEffectDeleteCheckoutComments = models.GitHubEffectDeleteCheckoutComments
+)
+
+const (
+	PaymentProcessorStripe       = models.PaymentProcessorStripe
+	PaymentProcessorLemonSqueezy = models.PaymentProcessorLemonSqueezy
+)
+
+const (
+	OperatorActionSearchShops          = models.OperatorActionSearchShops
+	OperatorActionViewShop             = models.OperatorActionViewShop
+	OperatorActionReplayWebhook        = models.OperatorActionReplayWebhook
+	OperatorActionRedeliverWebhooks    = models.OperatorActionRedeliverWebhooks
+	OperatorActionImpersonationStarted = models.OperatorActionImpersonationStarted
+	OperatorActionImpersonationEnded   = models.OperatorActionImpersonationEnded
+	OperatorActionSuspendShop          = models.OperatorActionSuspendShop
+	OperatorActionUnsuspendShop        = models.OperatorActionUnsuspendShop
+	OperatorActionSuspensionNotes      = models.OperatorActionSuspensionNotes
+	OperatorActionSupportView          = models.OperatorActionSupportView
 )
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OperatorAuditStore keeps the audit log of what platform operators did in the internal admin
+// area.
+type OperatorAuditStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewOperatorAuditStore(pool *pgxpool.Pool) *OperatorAuditStore {
+	return &OperatorAuditStore{pool: pool}
+}
+
+// Record appends an entry to the audit log.
+func (s *OperatorAuditStore) Record(ctx context.Context, entry *OperatorAuditEntry) error {
+	shopID := pgtype.UUID{Bytes: entry.ShopID, Valid: entry.ShopID != uuid.Nil}
+	query := `
+		INSERT INTO operator_audit_log (operator, action, shop_id, repo_full_name, detail)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := s.pool.Exec(ctx, query, entry.Operator, string(entry.Action), shopID, entry.RepoFullName, entry.Detail)
+	return err
+}
+
+// ListRecent returns the most recent operator actions, newest first.
+func (s *OperatorAuditStore) ListRecent(ctx context.Context, limit int) ([]*OperatorAuditEntry, error) {
+	query := `
+		SELECT id, operator, action, shop_id, repo_full_name, detail, created_at
+		FROM operator_audit_log
+		ORDER BY created_at DESC
+		LIMIT $1
+	`
+	rows, err := s.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*OperatorAuditEntry{}
+	for rows.Next() {
+		var (
+			entry     OperatorAuditEntry
+			action    string
+			shopID    pgtype.UUID
+			createdAt pgtype.Timestamptz
+		)
+		if err := rows.Scan(&entry.ID, &entry.Operator, &action, &shopID, &entry.RepoFullName, &entry.Detail, &createdAt); err != nil {
+			return nil, err
+		}
+		entry.Action = OperatorAction(action)
+		if shopID.Valid {
+			entry.ShopID = shopID.Bytes
+		}
+		entry.CreatedAt = createdAt.Time.UTC()
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// extraColumnsDB answers the populateExtraColumns query for the orders it knows, waiting
+// roundTrip per query to stand in for the network.
+type extraColumnsDB struct {
+	reasons   map[uuid.UUID]string
+	roundTrip time.Duration
+	queries   int
+}
+
+func (d *extraColumnsDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, fmt.Errorf("unexpected exec")
+}
+
+func (d *extraColumnsDB) Query(_ context.Context, _ string, args ...any) (pgx.Rows, error) {
+	d.queries++
+	time.Sleep(d.roundTrip)
+	orderIDs, ok := args[0].([]uuid.UUID)
+	if !ok {
+		return nil, fmt.Errorf("order IDs = %T, want []uuid.UUID", args[0])
+	}
+	rows := &extraColumnsRows{}
+	for _, orderID := range orderIDs {
+		if reason, ok := d.reasons[orderID]; ok {
+			rows.ids = append(rows.ids, orderID)
+			rows.reasons = append(rows.reasons, reason)
+		}
+	}
+	return rows, nil
+}
+
+func (d *extraColumnsDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return &readRow{err: fmt.Errorf("unexpected query row")}
+}
+
+type extraColumnsRows struct {
+	ids     []uuid.UUID
+	reasons []string
+	current int
+}
+
+func (r *extraColumnsRows) Close()                                       {}
+func (r *extraColumnsRows) Err() error                                   { return nil }
+func (r *extraColumnsRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *extraColumnsRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *extraColumnsRows) Values() ([]any, error)                       { return nil, nil }
+func (r *extraColumnsRows) RawValues() [][]byte                          { return nil }
+func (r *extraColumnsRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *extraColumnsRows) Next() bool {
+	r.current++
+	return r.current <= len(r.ids)
+}
+
+func (r *extraColumnsRows) Scan(dest ...any) error {
+	i := r.current - 1
+	*dest[0].(*uuid.UUID) = r.ids[i]
+	*dest[1].(*pgtype.Text) = pgtype.Text{String: r.reasons[i], Valid: true}
+	*dest[8].(*string) = ""
+	*dest[10].(*[]byte) = []byte(`[]`)
+	return nil
+}
+
+func newExtraColumnsOrders(n int) ([]*Order, *extraColumnsDB) {
+	db := &extraColumnsDB{reasons: make(map[uuid.UUID]string, n)}
+	orders := make([]*Order, n)
+	for i := range orders {
+		orders[i] = &Order{ID: uuid.New()}
+		db.reasons[orders[i].ID] = fmt.Sprintf("card declined %d", i)
+	}
+	return orders, db
+}
+
+func TestPopulateExtraColumnsBatchesOrders(t *testing.T) {
+	t.Parallel()
+
+	orders, db := newExtraColumnsOrders(20)
+	store := &OrderStore{}
+	if err := store.populateExtraColumns(context.Background(), db, orders...); err != nil {
+		t.Fatalf("populateExtraColumns() error = %v", err)
+	}
+	if db.queries != 1 {
+		t.Fatalf("queries = %d, want 1 for the whole page", db.queries)
+	}
+	for i, order := range orders {
+		if want := fmt.Sprintf("card declined %d", i); order.FailureReason != want {
+			t.Fatalf("order %d failure reason = %q, want %q", i, order.FailureReason, want)
+		}
+	}
+
+	missing := &Order{ID: uuid.New()}
+	if err := store.populateExtraColumns(context.Background(), db, orders[0], missing); err != pgx.ErrNoRows {
+		t.Fatalf("populateExtraColumns() with a missing order error = %v, want pgx.ErrNoRows", err)
+	}
+}
+
+// BenchmarkPopulateExtraColumns compares loading the extra columns of a 20-order dashboard page
+// one order at a time with loading them in one query, at 100µs per round trip.
+func BenchmarkPopulateExtraColumns(b *testing.B) {
+	store := &OrderStore{}
+	ctx := context.Background()
+
+	b.Run("per_order", func(b *testing.B) {
+		orders, db := newExtraColumnsOrders(20)
+		db.roundTrip = 100 * time.Microsecond
+		for b.Loop() {
+			for _, order := range orders {
+				if err := store.populateExtraColumns(ctx, db, order); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(db.queries)/float64(b.N), "queries/op")
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		orders, db := newExtraColumnsOrders(20)
+		db.roundTrip = 100 * time.Microsecond
+		for b.Loop() {
+			if err := store.populateExtraColumns(ctx, db, orders...); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(db.queries)/float64(b.N), "queries/op")
+	})
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/db/queries"
+)
+
+// RecordEvent adds an entry to the order's event log. State changes such as MarkPaid write their
+// own entry along with the change; this is for steps that leave the order as it was, such as a
+// comment on its issue.
+func (s *OrderStore) RecordEvent(ctx context.Context, orderID uuid.UUID, kind OrderEventKind, detail string) error {
+	return insertOrderEvent(ctx, s.pool, orderID, kind, detail)
+}
+
+func insertOrderEvent(ctx context.Context, db queries.DBTX, orderID uuid.UUID, kind OrderEventKind, detail string) error {
+	query := `
+		INSERT INTO order_events (order_id, kind, detail)
+		VALUES ($1, $2, $3)
+	`
+	_, err := db.Exec(ctx, query, orderID, string(kind), detail)
+	return err
+}
+
+// ListEvents returns the order's event log, oldest first.
+func (s *OrderStore) ListEvents(ctx context.Context, orderID uuid.UUID) ([]OrderEvent, error) {
+	query := `
+		SELECT id, order_id, kind, detail, created_at
+		FROM order_events
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := s.pool.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[OrderEvent])
+}
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddNote saves a private seller note on an order and fills in its ID and time.
+func (s *OrderStore) AddNote(ctx context.Context, note *OrderNote) error {
+	query := `
+		INSERT INTO order_notes (order_id, body, author)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	return s.pool.QueryRow(ctx, query, note.OrderID, note.Body, note.Author).Scan(&note.ID, &note.CreatedAt)
+}
+
+// ListNotes returns the order's notes, oldest first.
+func (s *OrderStore) ListNotes(ctx context.Context, orderID uuid.UUID) ([]OrderNote, error) {
+	query := `
+		SELECT id, order_id, body, author, created_at
+		FROM order_notes
+		WHERE order_id = $1
+		ORDER BY created_at
+	`
+	rows, err := s.pool.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[OrderNote])
+}
@@ -0,0 +1,311 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/gitshopapp/gitshop/internal/crypto"
+)
+
+// Customer email, name, and shipping address are encrypted at rest, as are email log recipients.
+// Emails also get a keyed hash so orders can still be looked up by email. Rows written before
+// encryption keep plaintext until EncryptCustomerData rewrites them, so reads accept both.
+
+// NewCustomerEmailIndex returns the blind index for customer emails. Its key is kept apart from
+// the encryption keys so rotating those leaves the index alone; a new index key needs
+// EncryptCustomerData to rebuild every stored hash.
+func NewCustomerEmailIndex(key string) (*crypto.BlindIndex, error) {
+	return crypto.NewBlindIndex([]byte(key))
+}
+
+// sealField encrypts a customer detail. Empty values stay empty.
+func (s *OrderStore) sealField(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	return s.crypto.Encrypt(value)
+}
+
+// openField decrypts a customer detail, returning the stored value when it is plaintext written
+// before encryption. A value with a key-ID prefix that does not decrypt is an error rather than
+// plaintext, so ciphertext under a missing key is never shown or stored as the detail itself.
+func (s *OrderStore) openField(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	decrypted, err := s.crypto.Decrypt(value)
+	if err == nil {
+		return decrypted, nil
+	}
+	if crypto.KeyID(value) != "" && looksSealed(value) {
+		return "", fmt.Errorf("failed to decrypt customer detail: %w", err)
+	}
+	return value, nil
+}
+
+// sealAddress encrypts a shipping address into a JSON string, so it still fits the JSONB column.
+func (s *OrderStore) sealAddress(address map[string]any) ([]byte, error) {
+	if address == nil {
+		return nil, nil
+	}
+	plaintext, err := json.Marshal(address)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := s.crypto.Encrypt(string(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(ciphertext)
+}
+
+// openAddress decodes a shipping address stored by sealAddress, or a plaintext JSON object
+// written before encryption.
+func (s *OrderStore) openAddress(raw []byte) (map[string]any, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var ciphertext string
+		if err := json.Unmarshal(raw, &ciphertext); err != nil {
+			return nil, err
+		}
+		plaintext, err := s.crypto.Decrypt(ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt shipping address: %w", err)
+		}
+		raw = []byte(plaintext)
+	}
+	var address map[string]any
+	if raw != nil {
+		if err := json.Unmarshal(raw, &address); err != nil {
+			return nil, err
+		}
+	}
+	return address, nil
+}
+
+// emailHash is the blind index of a customer email. Emails are matched case-insensitively.
+func (s *OrderStore) emailHash(email string) pgtype.Text {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: s.emailIndex.Hash(email), Valid: true}
+}
+
+// EncryptCustomerData encrypts the customer details of up to limit orders after the given ID, in
+// ID order, when they are plaintext or encrypted with an older key, and rewrites email hashes that
+// are missing or were made with another index key, along with the customer records using them. The recipients in those orders' email log get the same treatment. It returns the last
+// order ID read, or uuid.Nil once every order has been read, and how many orders and email log
+// entries were rewritten. Rows already under the current key are left alone, so it is safe to run
+// again.
+func (s *OrderStore) EncryptCustomerData(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, COALESCE(customer_email, ''), COALESCE(customer_name, ''), shipping_address, COALESCE(customer_email_hash, '')
+		FROM orders
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`, after, limit)
+	if err != nil {
+		return after, 0, err
+	}
+	type storedOrder struct {
+		ID      uuid.UUID
+		Email   string
+		Name    string
+		Address []byte
+		Hash    string
+	}
+	stored, err := pgx.CollectRows(rows, pgx.RowToStructByPos[storedOrder])
+	if err != nil {
+		return after, 0, err
+	}
+	if len(stored) == 0 {
+		return uuid.Nil, 0, nil
+	}
+
+	updated := 0
+	orderIDs := make([]uuid.UUID, 0, len(stored))
+	var rehashed []uuid.UUID
+	for _, order := range stored {
+		after = order.ID
+		orderIDs = append(orderIDs, order.ID)
+		email, err := s.openField(order.Email)
+		if err != nil {
+			return after, updated, fmt.Errorf("order %s: %w", order.ID, err)
+		}
+		name, err := s.openField(order.Name)
+		if err != nil {
+			return after, updated, fmt.Errorf("order %s: %w", order.ID, err)
+		}
+		emailPlain := email != "" && email == order.Email
+		namePlain := name != "" && name == order.Name
+		// A value that decodes like ciphertext but does not decrypt was most likely sealed with
+		// another key; encrypting it again would lose it.
+		if emailPlain && looksSealed(email) || namePlain && looksSealed(name) {
+			return after, updated, fmt.Errorf("order %s: customer details do not decrypt with this key", order.ID)
+		}
+		resealEmail := email != "" && (emailPlain || s.staleKey(order.Email))
+		resealName := name != "" && (namePlain || s.staleKey(order.Name))
+		resealAddress := len(order.Address) > 0 && order.Address[0] == '{' || s.staleAddress(order.Address)
+		hash := s.emailHash(email)
+		if !resealEmail && !resealName && !resealAddress && order.Hash == hash.String {
+			continue
+		}
+		if order.Hash != hash.String {
+			rehashed = append(rehashed, order.ID)
+		}
+
+		sealedEmail, sealedName, sealedAddress := order.Email, order.Name, order.Address
+		if resealEmail {
+			if sealedEmail, err = s.sealField(email); err != nil {
+				return after, updated, err
+			}
+		}
+		if resealName {
+			if sealedName, err = s.sealField(name); err != nil {
+				return after, updated, err
+			}
+		}
+		if resealAddress {
+			address, err := s.openAddress(order.Address)
+			if err != nil {
+				return after, updated, fmt.Errorf("order %s: %w", order.ID, err)
+			}
+			if sealedAddress, err = s.sealAddress(address); err != nil {
+				return after, updated, err
+			}
+		}
+		if _, err := s.pool.Exec(ctx, `
+			UPDATE orders
+			SET customer_email = NULLIF($2, ''), customer_name = NULLIF($3, ''), shipping_address = $4, customer_email_hash = $5
+			WHERE id = $1
+		`, order.ID, sealedEmail, sealedName, sealedAddress, hash); err != nil {
+			return after, updated, fmt.Errorf("failed to encrypt order %s: %w", order.ID, err)
+		}
+		updated++
+	}
+	if err := s.rehashCustomers(ctx, rehashed); err != nil {
+		return after, updated, err
+	}
+	logged, err := s.encryptEmailLog(ctx, orderIDs)
+	updated += logged
+	if err != nil {
+		return after, updated, err
+	}
+	return after, updated, nil
+}
+
+// encryptEmailLog encrypts the email log recipients of the given orders like EncryptCustomerData
+// does for the orders themselves, and returns how many entries were rewritten.
+func (s *OrderStore) encryptEmailLog(ctx context.Context, orderIDs []uuid.UUID) (int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, order_id, recipient, COALESCE(recipient_hash, '')
+		FROM order_emails
+		WHERE order_id = ANY($1) AND recipient <> ''
+		ORDER BY id
+	`, orderIDs)
+	if err != nil {
+		return 0, err
+	}
+	type loggedEmail struct {
+		ID        uuid.UUID
+		OrderID   uuid.UUID
+		Recipient string
+		Hash      string
+	}
+	logged, err := pgx.CollectRows(rows, pgx.RowToStructByPos[loggedEmail])
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, entry := range logged {
+		recipient, err := s.openField(entry.Recipient)
+		if err != nil {
+			return updated, fmt.Errorf("order %s: email log recipient: %w", entry.OrderID, err)
+		}
+		plain := recipient == entry.Recipient
+		if plain && looksSealed(recipient) {
+			return updated, fmt.Errorf("order %s: email log recipient does not decrypt with this key", entry.OrderID)
+		}
+		reseal := plain || s.staleKey(entry.Recipient)
+		hash := s.emailHash(recipient)
+		if !reseal && entry.Hash == hash.String {
+			continue
+		}
+
+		sealed := entry.Recipient
+		if reseal {
+			if sealed, err = s.sealField(recipient); err != nil {
+				return updated, err
+			}
+		}
+		if _, err := s.pool.Exec(ctx, `UPDATE order_emails SET recipient = $2, recipient_hash = $3 WHERE id = $1`, entry.ID, sealed, hash); err != nil {
+			return updated, fmt.Errorf("failed to encrypt email log of order %s: %w", entry.OrderID, err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// rehashCustomers copies the rebuilt email hashes of the given orders onto their buyers' customer
+// records, taking the latest paid order's hash as RefreshCustomer does.
+func (s *OrderStore) rehashCustomers(ctx context.Context, orderIDs []uuid.UUID) error {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+	_, err := s.pool.Exec(ctx, `
+		WITH buyers AS (
+			SELECT DISTINCT shop_id, LOWER(github_username) AS github_username
+			FROM orders
+			WHERE id = ANY($1) AND github_username <> ''
+		),
+		latest AS (
+			SELECT DISTINCT ON (o.shop_id, LOWER(o.github_username)) o.shop_id, LOWER(o.github_username) AS github_username, o.customer_email_hash
+			FROM orders o
+			JOIN buyers b ON b.shop_id = o.shop_id AND b.github_username = LOWER(o.github_username)
+			WHERE o.customer_email_hash IS NOT NULL AND o.paid_at IS NOT NULL AND NOT o.test_mode
+			ORDER BY o.shop_id, LOWER(o.github_username), o.created_at DESC
+		)
+		UPDATE customers c
+		SET email_hash = latest.customer_email_hash, updated_at = NOW()
+		FROM latest
+		WHERE c.shop_id = latest.shop_id AND c.github_username = latest.github_username
+		  AND c.email_hash IS DISTINCT FROM latest.customer_email_hash
+	`, orderIDs)
+	if err != nil {
+		return fmt.Errorf("failed to rehash customer emails: %w", err)
+	}
+	return nil
+}
+
+// staleKey reports whether a stored ciphertext was encrypted with a key other than the current one.
+func (s *OrderStore) staleKey(ciphertext string) bool {
+	return crypto.KeyID(ciphertext) != s.crypto.CurrentKeyID()
+}
+
+// staleAddress reports whether a sealed shipping address was encrypted with an older key.
+func (s *OrderStore) staleAddress(raw []byte) bool {
+	var ciphertext string
+	if len(raw) == 0 || raw[0] != '"' || json.Unmarshal(raw, &ciphertext) != nil {
+		return false
+	}
+	return s.staleKey(ciphertext)
+}
+
+// looksSealed reports whether value has the shape of an encrypted field: URL-safe base64 long
+// enough to hold a nonce and an authentication tag.
+func looksSealed(value string) bool {
+	if _, encoded, ok := strings.Cut(value, ":"); ok {
+		value = encoded
+	}
+	data, err := base64.URLEncoding.DecodeString(value)
+	return err == nil && len(data) >= 28
+}
@@ -6,28 +6,70 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/gitshopapp/gitshop/internal/crypto"
 	"github.com/gitshopapp/gitshop/internal/db/queries"
 )
 
 type OrderStore struct {
-	pool    *pgxpool.Pool
-	queries *queries.Queries
+	pool       *pgxpool.Pool
+	queries    *queries.Queries
+	crypto     crypto.Encryptor
+	emailIndex *crypto.BlindIndex
+	reads      *ReadPool
 }
 
-var ErrInvalidStatusTransition = errors.New("invalid order status transition")
+var (
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+	ErrOrderExists             = errors.New("order already exists for issue")
+)
+
+// orderIssueConstraint keeps each issue to one order.
+const orderIssueConstraint = "orders_shop_issue_key"
+
+func NewOrderStore(pool *pgxpool.Pool, encryptor crypto.Encryptor, emailIndex *crypto.BlindIndex) (*OrderStore, error) {
+	if encryptor == nil {
+		return nil, fmt.Errorf("encryptor is required")
+	}
+	if emailIndex == nil {
+		return nil, fmt.Errorf("email index is required")
+	}
 
-func NewOrderStore(pool *pgxpool.Pool) *OrderStore {
 	return &OrderStore{
-		pool:    pool,
-		queries: queries.New(pool),
+		pool:       pool,
+		queries:    queries.New(pool),
+		crypto:     encryptor,
+		emailIndex: emailIndex,
+	}, nil
+}
+
+// WithReadPool returns a store that sends lag-tolerant reads, such as dashboard lists and
+// analytics, through reads.
+func (s *OrderStore) WithReadPool(reads *ReadPool) *OrderStore {
+	store := *s
+	store.reads = reads
+	return &store
+}
+
+// reader returns where lag-tolerant reads go: the read pool when there is one, otherwise the
+// primary.
+func (s *OrderStore) reader() queries.DBTX {
+	if s.reads != nil {
+		return s.reads
 	}
+	return s.pool
 }
 
+// Create inserts the order, along with a created entry in its event log, and fills in its
+// generated fields. When the issue already has an order, such as on a redelivered webhook, order
+// is replaced by the existing one and the error wraps ErrOrderExists.
 func (s *OrderStore) Create(ctx context.Context, order *Order) error {
 	optionsJSON, err := json.Marshal(order.Options)
 	if err != nil {
@@ -38,59 +80,65 @@ func (s *OrderStore) Create(ctx context.Context, order *Order) error {
 	if err != nil {
 		return err
 	}
-	subtotalCents, err := intToInt32(order.SubtotalCents, "subtotal cents")
+	shippingAddressJSON, err := s.sealAddress(order.ShippingAddress)
 	if err != nil {
 		return err
 	}
-	shippingCents, err := intToInt32(order.ShippingCents, "shipping cents")
-	if err != nil {
-		return err
-	}
-	totalCents, err := intToInt32(order.TotalCents, "total cents")
+	taxCents := pgtype.Int8{Int64: order.TaxCents, Valid: order.TaxCents > 0}
+
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	var shippingAddressJSON []byte
-	if order.ShippingAddress != nil {
-		shippingAddressJSON, err = json.Marshal(order.ShippingAddress)
-		if err != nil {
-			return err
-		}
-	}
-	taxCents := pgtype.Int4{Valid: false}
-	if order.TaxCents > 0 {
-		taxInt32, convErr := intToInt32(order.TaxCents, "tax cents")
-		if convErr != nil {
-			return convErr
-		}
-		taxCents = pgtype.Int4{Int32: taxInt32, Valid: true}
-	}
-
-	row, err := s.queries.CreateOrder(ctx, queries.CreateOrderParams{
+	row, err := s.queries.WithTx(tx).CreateOrder(ctx, queries.CreateOrderParams{
 		ShopID:                  order.ShopID,
 		GithubIssueNumber:       issueNumber,
 		GithubIssueUrl:          pgtype.Text{String: order.GitHubIssueURL, Valid: order.GitHubIssueURL != ""},
 		GithubUsername:          order.GitHubUsername,
 		Sku:                     order.SKU,
 		Options:                 optionsJSON,
-		SubtotalCents:           subtotalCents,
-		ShippingCents:           shippingCents,
+		SubtotalCents:           order.SubtotalCents,
+		ShippingCents:           order.ShippingCents,
 		TaxCents:                taxCents,
-		TotalCents:              totalCents,
+		TotalCents:              order.TotalCents,
 		StripeCheckoutSessionID: pgtype.Text{String: "", Valid: false},
 		CustomerEmail:           pgtype.Text{String: "", Valid: false},
 		CustomerName:            pgtype.Text{String: "", Valid: false},
 		ShippingAddress:         shippingAddressJSON,
 		Status:                  string(order.Status),
+		TermsVersion:            pgtype.Text{String: order.TermsVersion, Valid: order.TermsVersion != ""},
+		TermsAcceptedAt:         pgtype.Timestamptz{Time: order.TermsAcceptedAt, Valid: !order.TermsAcceptedAt.IsZero()},
+		VerificationStatus:      pgtype.Text{String: string(order.VerificationStatus), Valid: order.VerificationStatus != ""},
+		Category:                order.Category,
+		TestMode:                order.TestMode,
+		Campaign:                order.Campaign,
+		DisplayCurrency:         order.DisplayCurrency,
 	})
+	if isOrderIssueConflict(err) {
+		_ = tx.Rollback(ctx)
+		existing, getErr := s.GetByShopAndIssue(ctx, order.ShopID, order.GitHubIssueNumber)
+		if getErr != nil {
+			return fmt.Errorf("failed to load existing order: %w", getErr)
+		}
+		*order = *existing
+		return fmt.Errorf("%w: issue %d", ErrOrderExists, order.GitHubIssueNumber)
+	}
 	if err != nil {
 		return err
 	}
+	if err := insertOrderEvent(ctx, tx, row.ID, OrderEventCreated, order.SKU); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
 
 	order.ID = row.ID
 	order.OrderNumber = int(row.OrderNumber)
 	order.CreatedAt = row.CreatedAt.Time
+	order.CheckoutMethod = CheckoutMethod(row.CheckoutMethod)
 	return nil
 }
 
@@ -125,11 +173,20 @@ func (s *OrderStore) GetByStripeSessionID(ctx context.Context, sessionID string)
 		PaidAt:                  row.PaidAt,
 		ShippedAt:               row.ShippedAt,
 		DeliveredAt:             row.DeliveredAt,
+		RefundedAt:              row.RefundedAt,
+		TermsVersion:            row.TermsVersion,
+		TermsAcceptedAt:         row.TermsAcceptedAt,
+		VerificationStatus:      row.VerificationStatus,
+		IdentityVerificationID:  row.IdentityVerificationID,
+		StripeInvoiceID:         row.StripeInvoiceID,
+		ReturnedAt:              row.ReturnedAt,
+		CheckoutMethod:          row.CheckoutMethod,
+		StripePaymentLinkID:     row.StripePaymentLinkID,
 	})
 	if err != nil {
 		return nil, err
 	}
-	if err := s.populateFailureReason(ctx, order); err != nil {
+	if err := s.populateExtraColumns(ctx, s.pool, order); err != nil {
 		return nil, err
 	}
 	return order, nil
@@ -174,11 +231,20 @@ func (s *OrderStore) GetByShopAndIssue(ctx context.Context, shopID uuid.UUID, is
 		PaidAt:                  row.PaidAt,
 		ShippedAt:               row.ShippedAt,
 		DeliveredAt:             row.DeliveredAt,
+		RefundedAt:              row.RefundedAt,
+		TermsVersion:            row.TermsVersion,
+		TermsAcceptedAt:         row.TermsAcceptedAt,
+		VerificationStatus:      row.VerificationStatus,
+		IdentityVerificationID:  row.IdentityVerificationID,
+		StripeInvoiceID:         row.StripeInvoiceID,
+		ReturnedAt:              row.ReturnedAt,
+		CheckoutMethod:          row.CheckoutMethod,
+		StripePaymentLinkID:     row.StripePaymentLinkID,
 	})
 	if err != nil {
 		return nil, err
 	}
-	if err := s.populateFailureReason(ctx, order); err != nil {
+	if err := s.populateExtraColumns(ctx, s.pool, order); err != nil {
 		return nil, err
 	}
 	return order, nil
@@ -215,11 +281,20 @@ func (s *OrderStore) GetByID(ctx context.Context, orderID uuid.UUID) (*Order, er
 		PaidAt:                  order.PaidAt,
 		ShippedAt:               order.ShippedAt,
 		DeliveredAt:             order.DeliveredAt,
+		RefundedAt:              order.RefundedAt,
+		TermsVersion:            order.TermsVersion,
+		TermsAcceptedAt:         order.TermsAcceptedAt,
+		VerificationStatus:      order.VerificationStatus,
+		IdentityVerificationID:  order.IdentityVerificationID,
+		StripeInvoiceID:         order.StripeInvoiceID,
+		ReturnedAt:              order.ReturnedAt,
+		CheckoutMethod:          order.CheckoutMethod,
+		StripePaymentLinkID:     order.StripePaymentLinkID,
 	})
 	if err != nil {
 		return nil, err
 	}
-	if err := s.populateFailureReason(ctx, converted); err != nil {
+	if err := s.populateExtraColumns(ctx, s.pool, converted); err != nil {
 		return nil, err
 	}
 	return converted, nil
@@ -231,7 +306,7 @@ func (s *OrderStore) GetOrdersByShop(ctx context.Context, shopID uuid.UUID, limi
 		return nil, err
 	}
 
-	rows, err := s.queries.GetOrdersByShop(ctx, queries.GetOrdersByShopParams{
+	rows, err := queries.New(s.reader()).GetOrdersByShop(ctx, queries.GetOrdersByShopParams{
 		ShopID: shopID,
 		Limit:  limitInt32,
 	})
@@ -239,85 +314,430 @@ func (s *OrderStore) GetOrdersByShop(ctx context.Context, shopID uuid.UUID, limi
 		return nil, err
 	}
 
-	orders := make([]*Order, len(rows))
-	for i, row := range rows {
-		order, err := s.rowToOrder(orderRow{
-			ID:                      row.ID,
-			ShopID:                  row.ShopID,
-			GithubIssueNumber:       row.GithubIssueNumber,
-			OrderNumber:             row.OrderNumber,
-			GithubIssueUrl:          row.GithubIssueUrl,
-			GithubUsername:          row.GithubUsername,
-			Sku:                     row.Sku,
-			Options:                 row.Options,
-			SubtotalCents:           row.SubtotalCents,
-			ShippingCents:           row.ShippingCents,
-			TaxCents:                row.TaxCents,
-			TotalCents:              row.TotalCents,
-			StripeCheckoutSessionID: row.StripeCheckoutSessionID,
-			StripePaymentIntentID:   row.StripePaymentIntentID,
-			CustomerEmail:           row.CustomerEmail,
-			CustomerName:            row.CustomerName,
-			ShippingAddress:         row.ShippingAddress,
-			TrackingNumber:          row.TrackingNumber,
-			TrackingUrl:             row.TrackingUrl,
-			Carrier:                 row.Carrier,
-			Status:                  row.Status,
-			CreatedAt:               row.CreatedAt,
-			PaidAt:                  row.PaidAt,
-			ShippedAt:               row.ShippedAt,
-			DeliveredAt:             row.DeliveredAt,
+	converted := make([]orderRow, 0, len(rows))
+	for _, row := range rows {
+		converted = append(converted, orderRow(row))
+	}
+	return s.ordersFromRows(ctx, converted)
+}
+
+// OrderCursor is a position in a shop's order list, which runs newest first with ties on
+// created_at broken by ID.
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// OrderPage is one page of a shop's order list. Next is set when older orders follow the page
+// and Prev when newer orders come before it.
+type OrderPage struct {
+	Orders []*Order
+	Next   *OrderCursor
+	Prev   *OrderCursor
+}
+
+// ListOrdersPage returns up to limit of the shop's orders, newest first: the first page, the
+// orders older than after, or the orders newer than before. Pages seek to the cursor, so they
+// cost the same however deep they are and do not shift when new orders arrive.
+func (s *OrderStore) ListOrdersPage(ctx context.Context, shopID uuid.UUID, after, before *OrderCursor, limit int) (*OrderPage, error) {
+	if after != nil && before != nil {
+		return nil, fmt.Errorf("only one of after and before may be set")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive")
+	}
+	// One extra row tells whether another page follows.
+	pageSize, err := intToInt32(limit+1, "limit")
+	if err != nil {
+		return nil, err
+	}
+
+	q := queries.New(s.reader())
+	var rows []orderRow
+	switch {
+	case after != nil:
+		found, err := q.GetOrdersByShopOlderThan(ctx, queries.GetOrdersByShopOlderThanParams{
+			ShopID:    shopID,
+			CreatedAt: pgtype.Timestamptz{Time: after.CreatedAt, Valid: true},
+			ID:        after.ID,
+			PageSize:  pageSize,
 		})
 		if err != nil {
 			return nil, err
 		}
-		if err := s.populateFailureReason(ctx, order); err != nil {
+		for _, row := range found {
+			rows = append(rows, orderRow(row))
+		}
+	case before != nil:
+		found, err := q.GetOrdersByShopNewerThan(ctx, queries.GetOrdersByShopNewerThanParams{
+			ShopID:    shopID,
+			CreatedAt: pgtype.Timestamptz{Time: before.CreatedAt, Valid: true},
+			ID:        before.ID,
+			PageSize:  pageSize,
+		})
+		if err != nil {
 			return nil, err
 		}
-		orders[i] = order
+		// Newer orders come back oldest first, nearest the cursor.
+		for i := len(found) - 1; i >= 0; i-- {
+			rows = append(rows, orderRow(found[i]))
+		}
+	default:
+		found, err := q.GetOrdersByShop(ctx, queries.GetOrdersByShopParams{ShopID: shopID, Limit: pageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range found {
+			rows = append(rows, orderRow(row))
+		}
 	}
 
+	more := len(rows) > limit
+	if more {
+		if before != nil {
+			rows = rows[len(rows)-limit:]
+		} else {
+			rows = rows[:limit]
+		}
+	}
+	orders, err := s.ordersFromRows(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &OrderPage{Orders: orders}
+	if len(orders) == 0 {
+		return page, nil
+	}
+	if more || before != nil {
+		page.Next = orderCursor(orders[len(orders)-1])
+	}
+	if more && before != nil || after != nil {
+		page.Prev = orderCursor(orders[0])
+	}
+	return page, nil
+}
+
+func orderCursor(order *Order) *OrderCursor {
+	return &OrderCursor{CreatedAt: order.CreatedAt, ID: order.ID}
+}
+
+// ordersFromRows converts rows of the shop order list, loading their extra columns from the
+// same pool the rows came from.
+func (s *OrderStore) ordersFromRows(ctx context.Context, rows []orderRow) ([]*Order, error) {
+	orders := make([]*Order, len(rows))
+	for i, row := range rows {
+		order, err := s.rowToOrder(row)
+		if err != nil {
+			return nil, err
+		}
+		orders[i] = order
+	}
+	if err := s.populateExtraColumns(ctx, s.reader(), orders...); err != nil {
+		return nil, err
+	}
 	return orders, nil
 }
 
+// SummarizeShopOrders counts orders for each shop: orders placed and revenue paid since since,
+// payments that failed since then, and paid orders still waiting to ship. Test mode orders are
+// left out, and shops without other orders are missing from the result.
+func (s *OrderStore) SummarizeShopOrders(ctx context.Context, shopIDs []uuid.UUID, since time.Time) (map[uuid.UUID]ShopOrderSummary, error) {
+	summaries := make(map[uuid.UUID]ShopOrderSummary, len(shopIDs))
+	if len(shopIDs) == 0 {
+		return summaries, nil
+	}
+	rows, err := s.reader().Query(ctx, `
+		SELECT shop_id,
+		       COUNT(*) FILTER (WHERE created_at >= $2),
+		       COALESCE(SUM(total_cents) FILTER (WHERE paid_at >= $2 AND status IN ('paid', 'address_issue', 'shipped', 'delivered')), 0),
+		       COUNT(*) FILTER (WHERE status = 'payment_failed' AND created_at >= $2),
+		       COUNT(*) FILTER (WHERE status IN ('paid', 'address_issue'))
+		FROM orders
+		WHERE shop_id = ANY($1) AND NOT test_mode
+		GROUP BY shop_id
+	`, shopIDs, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			shopID  uuid.UUID
+			summary ShopOrderSummary
+		)
+		if err := rows.Scan(&shopID, &summary.Orders, &summary.RevenueCents, &summary.FailedPayments, &summary.UnshippedPaid); err != nil {
+			return nil, err
+		}
+		summaries[shopID] = summary
+	}
+	return summaries, rows.Err()
+}
+
+// UpdateStripeSession records the buyer's new Checkout Session, along with a checkout_created
+// entry in the order's event log.
 func (s *OrderStore) UpdateStripeSession(ctx context.Context, orderID uuid.UUID, sessionID string) error {
-	// This needs a custom query - adding it to orders.sql would be better
-	// For now, using direct pool access
-	query := `UPDATE orders SET stripe_checkout_session_id = $1 WHERE id = $2`
-	_, err := s.pool.Exec(ctx, query, sessionID, orderID)
+	query := `
+		WITH updated AS (
+			UPDATE orders
+			SET stripe_checkout_session_id = $1, checkout_method = $2, stripe_payment_link_id = NULL
+			WHERE id = $3
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $4, $2 FROM updated
+	`
+	_, err := s.pool.Exec(ctx, query, sessionID, CheckoutMethodSession, orderID, string(OrderEventCheckoutCreated))
+	return err
+}
+
+// UpdateStripePaymentLink records that the buyer was sent a Payment Link instead of a Checkout
+// Session.
+func (s *OrderStore) UpdateStripePaymentLink(ctx context.Context, orderID uuid.UUID, paymentLinkID string) error {
+	query := `
+		WITH updated AS (
+			UPDATE orders
+			SET stripe_payment_link_id = $1, checkout_method = $2, stripe_checkout_session_id = NULL
+			WHERE id = $3
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $4, $2 FROM updated
+	`
+	_, err := s.pool.Exec(ctx, query, paymentLinkID, CheckoutMethodPaymentLink, orderID, string(OrderEventCheckoutCreated))
+	return err
+}
+
+// UpdateProviderCheckout records a checkout created at a processor other than Stripe. The Stripe
+// checkout ids are cleared so a stale session cannot be matched to the order.
+func (s *OrderStore) UpdateProviderCheckout(ctx context.Context, orderID uuid.UUID, method CheckoutMethod) error {
+	query := `
+		WITH updated AS (
+			UPDATE orders
+			SET checkout_method = $1, stripe_checkout_session_id = NULL, stripe_payment_link_id = NULL
+			WHERE id = $2
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $3, $1 FROM updated
+	`
+	_, err := s.pool.Exec(ctx, query, method, orderID, string(OrderEventCheckoutCreated))
+	return err
+}
+
+// MarkPendingProviderCheckout is MarkPendingPayment for a checkout at a processor other than
+// Stripe.
+func (s *OrderStore) MarkPendingProviderCheckout(ctx context.Context, orderID uuid.UUID, method CheckoutMethod) error {
+	query := `
+		WITH updated AS (
+			UPDATE orders
+			SET status = $1, checkout_method = $2, stripe_checkout_session_id = NULL,
+			    stripe_payment_link_id = NULL, failure_reason = NULL
+			WHERE id = $3 AND status IN ('payment_failed', 'pending_payment', 'expired')
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $4, $2 FROM updated
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusPendingPayment, method, orderID, string(OrderEventCheckoutCreated))
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected payment_failed/pending_payment/expired", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// SetProviderPaymentID records the processor's id for a payment taken outside Stripe.
+func (s *OrderStore) SetProviderPaymentID(ctx context.Context, orderID uuid.UUID, paymentID string) error {
+	_, err := s.pool.Exec(ctx, `UPDATE orders SET provider_payment_id = $1 WHERE id = $2`, paymentID, orderID)
+	return err
+}
+
+// GetProviderPaymentID returns the processor's id for a payment taken outside Stripe, or "".
+func (s *OrderStore) GetProviderPaymentID(ctx context.Context, orderID uuid.UUID) (string, error) {
+	var paymentID pgtype.Text
+	if err := s.pool.QueryRow(ctx, `SELECT provider_payment_id FROM orders WHERE id = $1`, orderID).Scan(&paymentID); err != nil {
+		return "", err
+	}
+	return paymentID.String, nil
+}
+
+// SetCheckoutExpiresAt records when the order's checkout link expires and clears any earlier
+// reminder, so a new link gets its own. A zero expiresAt is stored as NULL.
+func (s *OrderStore) SetCheckoutExpiresAt(ctx context.Context, orderID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		UPDATE orders
+		SET checkout_expires_at = $1, checkout_reminder_sent_at = NULL
+		WHERE id = $2
+	`
+	_, err := s.pool.Exec(ctx, query, pgtype.Timestamptz{Time: expiresAt, Valid: !expiresAt.IsZero()}, orderID)
 	return err
 }
 
-func (s *OrderStore) MarkPaid(ctx context.Context, orderID uuid.UUID, paymentIntentID, customerEmail, customerName string, shippingAddress map[string]any) error {
-	addressJSON, err := json.Marshal(shippingAddress)
+// CheckoutReminder is a pending order whose checkout link is about to expire.
+type CheckoutReminder struct {
+	OrderID   uuid.UUID
+	ExpiresAt time.Time
+}
+
+// ClaimCheckoutReminders marks up to limit pending orders whose checkout link expires before
+// dueBefore as reminded and returns them. Claiming first keeps two instances from reminding the
+// same buyer. An empty region covers every shop.
+func (s *OrderStore) ClaimCheckoutReminders(ctx context.Context, region string, dueBefore time.Time, limit int) ([]CheckoutReminder, error) {
+	query := `
+		WITH due AS (
+			SELECT o.id
+			FROM orders o
+			JOIN shops s ON s.id = o.shop_id
+			WHERE o.status = 'pending_payment'
+			  AND o.checkout_reminder_sent_at IS NULL
+			  AND o.checkout_expires_at > NOW()
+			  AND o.checkout_expires_at <= $1
+			  AND s.disconnected_at IS NULL
+			  AND ($2 = '' OR s.region IN ('', $2))
+			ORDER BY o.checkout_expires_at
+			LIMIT $3
+			FOR UPDATE OF o SKIP LOCKED
+		)
+		UPDATE orders
+		SET checkout_reminder_sent_at = NOW()
+		FROM due
+		WHERE orders.id = due.id
+		RETURNING orders.id, orders.checkout_expires_at
+	`
+	rows, err := s.pool.Query(ctx, query, dueBefore, region, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[CheckoutReminder])
+}
+
+// MarkPaid records the buyer's payment, along with a paid entry in the order's event log whose
+// detail is paidVia. An order that is already paid, such as on a redelivered webhook, is left as
+// it is and returns nil, so the event log gets one paid entry per order.
+func (s *OrderStore) MarkPaid(ctx context.Context, orderID uuid.UUID, paymentIntentID, customerEmail, customerName string, shippingAddress map[string]any, paidVia string) error {
+	sealedEmail, err := s.sealField(customerEmail)
+	if err != nil {
+		return err
+	}
+	sealedName, err := s.sealField(customerName)
 	if err != nil {
 		return err
 	}
+	addressJSON, err := s.sealAddress(shippingAddress)
+	if err != nil {
+		return err
+	}
+	return markPaid(ctx, s.pool, orderID, paymentIntentID, sealedEmail, sealedName, addressJSON, s.emailHash(customerEmail), paidVia)
+}
 
-	// Update order with paid status
+func markPaid(ctx context.Context, db queries.DBTX, orderID uuid.UUID, paymentIntentID, sealedEmail, sealedName string, addressJSON []byte, emailHash pgtype.Text, paidVia string) error {
+	query := `
+		WITH paid AS (
+			UPDATE orders
+			SET status = $1, stripe_payment_intent_id = $2, customer_email = $3,
+			    customer_name = $4, shipping_address = $5, customer_email_hash = $6, paid_at = NOW(), failure_reason = NULL
+			WHERE id = $7 AND status IN ('pending_payment', 'payment_failed')
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $8, $9 FROM paid
+	`
+	cmdTag, err := db.Exec(ctx, query, StatusPaid, paymentIntentID, sealedEmail, sealedName, addressJSON, emailHash, orderID, string(OrderEventPaid), paidVia)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() > 0 {
+		return nil
+	}
+
+	var status string
+	if err := db.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1`, orderID).Scan(&status); err != nil {
+		return err
+	}
+	if OrderStatus(status) == StatusPaid {
+		return nil
+	}
+	return fmt.Errorf("%w: expected pending_payment/payment_failed", ErrInvalidStatusTransition)
+}
+
+// SetCheckoutEmail records the email a buyer gave on the order form, before checkout, and
+// whether they opted out of the checkout reminder. Checkout overwrites the email when the order
+// is paid.
+func (s *OrderStore) SetCheckoutEmail(ctx context.Context, orderID uuid.UUID, customerEmail string, reminderOptOut bool) error {
+	sealedEmail, err := s.sealField(customerEmail)
+	if err != nil {
+		return err
+	}
 	query := `
 		UPDATE orders
-		SET status = $1, stripe_payment_intent_id = $2, customer_email = $3,
-		    customer_name = $4, shipping_address = $5, paid_at = NOW(), failure_reason = NULL
-		WHERE id = $6 AND status IN ('pending_payment', 'payment_failed', 'paid')
+		SET customer_email = $1, customer_email_hash = $2, checkout_reminder_opt_out = $3
+		WHERE id = $4 AND status IN ('pending_payment', 'awaiting_approval', 'inquiry')
 	`
-	cmdTag, err := s.pool.Exec(ctx, query, StatusPaid, paymentIntentID, customerEmail, customerName, addressJSON, orderID)
+	cmdTag, err := s.pool.Exec(ctx, query, sealedEmail, s.emailHash(customerEmail), reminderOptOut, orderID)
 	if err != nil {
 		return err
 	}
 	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("%w: expected pending_payment/payment_failed/paid", ErrInvalidStatusTransition)
+		return fmt.Errorf("%w: expected pending_payment/awaiting_approval/inquiry", ErrInvalidStatusTransition)
 	}
 	return nil
 }
 
-func (s *OrderStore) MarkShipped(ctx context.Context, orderID uuid.UUID, trackingNumber, carrier string) error {
+// MarkAddressIssue holds a paid order whose shipping address failed validation and records why.
+func (s *OrderStore) MarkAddressIssue(ctx context.Context, orderID uuid.UUID, reason string) error {
 	query := `
 		UPDATE orders
-		SET status = $1, tracking_number = $2, carrier = $3, shipped_at = NOW()
-		WHERE id = $4 AND status = 'paid'
+		SET status = $1, address_issue = $3
+		WHERE id = $2 AND status = 'paid'
 	`
-	cmdTag, err := s.pool.Exec(ctx, query, StatusShipped, trackingNumber, carrier, orderID)
+	cmdTag, err := s.pool.Exec(ctx, query, StatusAddressIssue, orderID, reason)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected paid", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// UpdateShippingAddress replaces the shipping address of a paid order. An order held for an
+// address issue is released back to paid.
+func (s *OrderStore) UpdateShippingAddress(ctx context.Context, orderID uuid.UUID, shippingAddress map[string]any) error {
+	addressJSON, err := s.sealAddress(shippingAddress)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE orders
+		SET status = $1, shipping_address = $2, address_issue = ''
+		WHERE id = $3 AND status IN ('paid', 'address_issue')
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusPaid, addressJSON, orderID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected paid/address_issue", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+func (s *OrderStore) MarkShipped(ctx context.Context, orderID uuid.UUID, trackingNumber, carrier string) error {
+	query := `
+		WITH shipped AS (
+			UPDATE orders
+			SET status = $1, tracking_number = $2, carrier = $3, shipped_at = NOW()
+			WHERE id = $4 AND status = 'paid'
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $5, $3 FROM shipped
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusShipped, trackingNumber, carrier, orderID, string(OrderEventShipped))
 	if err != nil {
 		return err
 	}
@@ -345,11 +765,16 @@ func (s *OrderStore) UpdateShipmentDetails(ctx context.Context, orderID uuid.UUI
 
 func (s *OrderStore) MarkShippedWithoutTracking(ctx context.Context, orderID uuid.UUID) error {
 	query := `
-		UPDATE orders
-		SET status = $1, shipped_at = NOW()
-		WHERE id = $2 AND status = 'paid'
+		WITH shipped AS (
+			UPDATE orders
+			SET status = $1, shipped_at = NOW()
+			WHERE id = $2 AND status = 'paid'
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind)
+		SELECT id, $3 FROM shipped
 	`
-	cmdTag, err := s.pool.Exec(ctx, query, StatusShipped, orderID)
+	cmdTag, err := s.pool.Exec(ctx, query, StatusShipped, orderID, string(OrderEventShipped))
 	if err != nil {
 		return err
 	}
@@ -361,11 +786,16 @@ func (s *OrderStore) MarkShippedWithoutTracking(ctx context.Context, orderID uui
 
 func (s *OrderStore) MarkDelivered(ctx context.Context, orderID uuid.UUID) error {
 	query := `
-		UPDATE orders
-		SET status = $1, delivered_at = NOW()
-		WHERE id = $2 AND status = 'shipped'
+		WITH delivered AS (
+			UPDATE orders
+			SET status = $1, delivered_at = NOW()
+			WHERE id = $2 AND status = 'shipped'
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind)
+		SELECT id, $3 FROM delivered
 	`
-	cmdTag, err := s.pool.Exec(ctx, query, StatusDelivered, orderID)
+	cmdTag, err := s.pool.Exec(ctx, query, StatusDelivered, orderID, string(OrderEventDelivered))
 	if err != nil {
 		return err
 	}
@@ -377,11 +807,16 @@ func (s *OrderStore) MarkDelivered(ctx context.Context, orderID uuid.UUID) error
 
 func (s *OrderStore) MarkFailed(ctx context.Context, orderID uuid.UUID, reason string) error {
 	query := `
-		UPDATE orders
-		SET status = $1, failure_reason = $3
-		WHERE id = $2 AND status IN ('pending_payment', 'payment_failed')
+		WITH failed AS (
+			UPDATE orders
+			SET status = $1, failure_reason = $3
+			WHERE id = $2 AND status IN ('pending_payment', 'payment_failed')
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $4, $3 FROM failed
 	`
-	cmdTag, err := s.pool.Exec(ctx, query, StatusPaymentFailed, orderID, reason)
+	cmdTag, err := s.pool.Exec(ctx, query, StatusPaymentFailed, orderID, reason, string(OrderEventFailed))
 	if err != nil {
 		return err
 	}
@@ -393,16 +828,42 @@ func (s *OrderStore) MarkFailed(ctx context.Context, orderID uuid.UUID, reason s
 
 func (s *OrderStore) MarkPendingPayment(ctx context.Context, orderID uuid.UUID, sessionID string) error {
 	query := `
-		UPDATE orders
-		SET status = $1, stripe_checkout_session_id = $2, failure_reason = NULL
-		WHERE id = $3 AND status IN ('payment_failed', 'pending_payment')
+		WITH updated AS (
+			UPDATE orders
+			SET status = $1, stripe_checkout_session_id = $2, checkout_method = $3,
+			    stripe_payment_link_id = NULL, failure_reason = NULL
+			WHERE id = $4 AND status IN ('payment_failed', 'pending_payment', 'expired')
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $5, $3 FROM updated
+	`
+	return s.markPendingPayment(ctx, query, sessionID, CheckoutMethodSession, orderID)
+}
+
+// MarkPendingPaymentLink is MarkPendingPayment for an order whose new checkout is a Payment Link.
+func (s *OrderStore) MarkPendingPaymentLink(ctx context.Context, orderID uuid.UUID, paymentLinkID string) error {
+	query := `
+		WITH updated AS (
+			UPDATE orders
+			SET status = $1, stripe_payment_link_id = $2, checkout_method = $3,
+			    stripe_checkout_session_id = NULL, failure_reason = NULL
+			WHERE id = $4 AND status IN ('payment_failed', 'pending_payment', 'expired')
+			RETURNING id
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT id, $5, $3 FROM updated
 	`
-	cmdTag, err := s.pool.Exec(ctx, query, StatusPendingPayment, sessionID, orderID)
+	return s.markPendingPayment(ctx, query, paymentLinkID, CheckoutMethodPaymentLink, orderID)
+}
+
+func (s *OrderStore) markPendingPayment(ctx context.Context, query, checkoutID string, method CheckoutMethod, orderID uuid.UUID) error {
+	cmdTag, err := s.pool.Exec(ctx, query, StatusPendingPayment, checkoutID, method, orderID, string(OrderEventCheckoutCreated))
 	if err != nil {
 		return err
 	}
 	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("%w: expected payment_failed/pending_payment", ErrInvalidStatusTransition)
+		return fmt.Errorf("%w: expected payment_failed/pending_payment/expired", ErrInvalidStatusTransition)
 	}
 	return nil
 }
@@ -410,7 +871,7 @@ func (s *OrderStore) MarkPendingPayment(ctx context.Context, orderID uuid.UUID,
 func (s *OrderStore) MarkExpired(ctx context.Context, orderID uuid.UUID) error {
 	query := `
 		UPDATE orders
-		SET status = $1
+		SET status = $1, checkout_expired_at = COALESCE(checkout_expired_at, NOW())
 		WHERE id = $2 AND status = 'pending_payment'
 	`
 	cmdTag, err := s.pool.Exec(ctx, query, StatusExpired, orderID)
@@ -423,6 +884,426 @@ func (s *OrderStore) MarkExpired(ctx context.Context, orderID uuid.UUID) error {
 	return nil
 }
 
+// MarkCancelled cancels an order that was never paid and records why.
+func (s *OrderStore) MarkCancelled(ctx context.Context, orderID uuid.UUID, reason string) error {
+	query := `
+		UPDATE orders
+		SET status = $1, cancelled_at = NOW(), cancellation_reason = $3
+		WHERE id = $2 AND status IN ('pending_payment', 'payment_failed', 'awaiting_approval')
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusCancelled, orderID, reason)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected pending_payment/payment_failed/awaiting_approval", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// MarkApproved releases a held order to pending payment and records who approved it.
+func (s *OrderStore) MarkApproved(ctx context.Context, orderID uuid.UUID, approvedBy string) error {
+	query := `
+		UPDATE orders
+		SET status = $1, approved_at = NOW(), approved_by = $3
+		WHERE id = $2 AND status = 'awaiting_approval'
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusPendingPayment, orderID, approvedBy)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected awaiting_approval", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+func (s *OrderStore) MarkRefunded(ctx context.Context, orderID uuid.UUID) error {
+	query := `
+		UPDATE orders
+		SET status = $1, refunded_at = NOW()
+		WHERE id = $2 AND status IN ('paid', 'address_issue', 'shipped', 'delivered')
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusRefunded, orderID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected paid/address_issue/shipped/delivered", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// MarkReturned records that a shipped or delivered order came back. refunded also stamps the
+// refund time when the payment was returned along with it.
+func (s *OrderStore) MarkReturned(ctx context.Context, orderID uuid.UUID, refunded bool) error {
+	query := `
+		UPDATE orders
+		SET status = $1, returned_at = NOW(), refunded_at = CASE WHEN $3 THEN NOW() ELSE refunded_at END
+		WHERE id = $2 AND status IN ('shipped', 'delivered')
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusReturned, orderID, refunded)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected shipped/delivered", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// ConvertInquiry prices a bulk inquiry and moves it into the normal payment flow.
+func (s *OrderStore) ConvertInquiry(ctx context.Context, orderID uuid.UUID, subtotalCents, shippingCents int64) error {
+	query := `
+		UPDATE orders
+		SET status = $1, subtotal_cents = $2, shipping_cents = $3, total_cents = $2 + $3
+		WHERE id = $4 AND status = 'inquiry'
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, StatusPendingPayment, subtotalCents, shippingCents, orderID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected inquiry", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// RepriceOrder replaces the items and totals of an order still awaiting payment and records the
+// change in the order's edit log. The order's category follows its new SKU.
+func (s *OrderStore) RepriceOrder(ctx context.Context, order *Order, edit *OrderEdit) error {
+	optionsJSON, err := json.Marshal(order.Options)
+	if err != nil {
+		return err
+	}
+	previousOptionsJSON, err := json.Marshal(edit.PreviousOptions)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		UPDATE orders
+		SET sku = $1, category = $2, options = $3, subtotal_cents = $4, shipping_cents = $5, total_cents = $6, campaign = $7
+		WHERE id = $8 AND status = $9
+	`
+	cmdTag, err := tx.Exec(ctx, query, order.SKU, order.Category, optionsJSON, order.SubtotalCents, order.ShippingCents, order.TotalCents, order.Campaign, order.ID, StatusPendingPayment)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected pending_payment", ErrInvalidStatusTransition)
+	}
+
+	audit := `
+		INSERT INTO order_edits (order_id, previous_sku, sku, previous_options, options, previous_total_cents, total_cents, edited_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, edited_at
+	`
+	var editedAt pgtype.Timestamptz
+	if err := tx.QueryRow(ctx, audit, order.ID, edit.PreviousSKU, order.SKU, previousOptionsJSON, optionsJSON, edit.PreviousTotalCents, order.TotalCents, edit.EditedBy).Scan(&edit.ID, &editedAt); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	edit.OrderID = order.ID
+	edit.SKU = order.SKU
+	edit.Options = order.Options
+	edit.TotalCents = order.TotalCents
+	edit.EditedAt = editedAt.Time
+	return nil
+}
+
+// SetCustomFields stores the buyer's answers to the checkout custom fields.
+func (s *OrderStore) SetCustomFields(ctx context.Context, orderID uuid.UUID, fields []OrderCustomField) error {
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode custom fields: %w", err)
+	}
+	query := `UPDATE orders SET custom_fields = $1 WHERE id = $2`
+	_, err = s.pool.Exec(ctx, query, payload, orderID)
+	return err
+}
+
+// SetTaxID stores the tax ID the buyer entered at checkout, such as an EU VAT number.
+func (s *OrderStore) SetTaxID(ctx context.Context, orderID uuid.UUID, taxIDType, taxID string) error {
+	query := `UPDATE orders SET tax_id_type = $1, tax_id = $2 WHERE id = $3`
+	_, err := s.pool.Exec(ctx, query, taxIDType, taxID, orderID)
+	return err
+}
+
+// SetReceiptURL stores the link to the order's paid Stripe invoice, where the buyer downloads
+// their receipt.
+func (s *OrderStore) SetReceiptURL(ctx context.Context, orderID uuid.UUID, receiptURL string) error {
+	query := `UPDATE orders SET receipt_url = $1 WHERE id = $2`
+	_, err := s.pool.Exec(ctx, query, receiptURL, orderID)
+	return err
+}
+
+// SetSubscription links the Stripe subscription a checkout started to its order and marks it active.
+func (s *OrderStore) SetSubscription(ctx context.Context, orderID uuid.UUID, subscriptionID string) error {
+	query := `UPDATE orders SET stripe_subscription_id = $1, subscription_status = $2 WHERE id = $3`
+	_, err := s.pool.Exec(ctx, query, subscriptionID, string(SubscriptionActive), orderID)
+	return err
+}
+
+// GetByStripeSubscriptionID returns the order whose checkout started the subscription.
+func (s *OrderStore) GetByStripeSubscriptionID(ctx context.Context, subscriptionID string) (*Order, error) {
+	var orderID uuid.UUID
+	query := `SELECT id FROM orders WHERE stripe_subscription_id = $1`
+	if err := s.pool.QueryRow(ctx, query, subscriptionID).Scan(&orderID); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, orderID)
+}
+
+// MarkSubscriptionCancelled records that an order's subscription ended. Cancelling an already
+// cancelled subscription is an invalid transition so a repeated webhook is easy to ignore.
+func (s *OrderStore) MarkSubscriptionCancelled(ctx context.Context, orderID uuid.UUID) error {
+	query := `
+		UPDATE orders
+		SET subscription_status = $1, subscription_cancelled_at = NOW()
+		WHERE id = $2 AND subscription_status = $3
+	`
+	cmdTag, err := s.pool.Exec(ctx, query, string(SubscriptionCancelled), orderID, string(SubscriptionActive))
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: expected an active subscription", ErrInvalidStatusTransition)
+	}
+	return nil
+}
+
+// ListOverdueOrders returns the shop's paid orders that have waited to ship since before
+// paidBefore, oldest first.
+func (s *OrderStore) ListOverdueOrders(ctx context.Context, shopID uuid.UUID, paidBefore time.Time, limit int) ([]*Order, error) {
+	query := `
+		SELECT id
+		FROM orders
+		WHERE shop_id = $1 AND status = 'paid' AND paid_at < $2
+		ORDER BY paid_at
+		LIMIT $3
+	`
+	rows, err := s.pool.Query(ctx, query, shopID, paidBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		order, err := s.GetByID(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// CountOverdueOrders counts the shop's paid orders that have waited to ship since before
+// paidBefore.
+func (s *OrderStore) CountOverdueOrders(ctx context.Context, shopID uuid.UUID, paidBefore time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM orders WHERE shop_id = $1 AND status = 'paid' AND paid_at < $2`
+	if err := s.pool.QueryRow(ctx, query, shopID, paidBefore).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListActiveSubscriptions returns the shop's orders with an active subscription, newest first.
+func (s *OrderStore) ListActiveSubscriptions(ctx context.Context, shopID uuid.UUID, limit int) ([]*Order, error) {
+	query := `
+		SELECT id
+		FROM orders
+		WHERE shop_id = $1 AND subscription_status = $2
+		ORDER BY paid_at DESC
+		LIMIT $3
+	`
+	rows, err := s.pool.Query(ctx, query, shopID, string(SubscriptionActive), limit)
+	if err != nil {
+		return nil, err
+	}
+	orderIDs := []uuid.UUID{}
+	for rows.Next() {
+		var orderID uuid.UUID
+		if err := rows.Scan(&orderID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	orders := make([]*Order, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		order, err := s.GetByID(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func (s *OrderStore) UpdateStripeInvoice(ctx context.Context, orderID uuid.UUID, invoiceID string) error {
+	query := `UPDATE orders SET stripe_invoice_id = $1 WHERE id = $2`
+	_, err := s.pool.Exec(ctx, query, invoiceID, orderID)
+	return err
+}
+
+// MarkTermsAccepted records acceptance collected later in the flow, such as Stripe checkout consent.
+// An earlier acceptance timestamp is kept.
+func (s *OrderStore) MarkTermsAccepted(ctx context.Context, orderID uuid.UUID) error {
+	query := `UPDATE orders SET terms_accepted_at = COALESCE(terms_accepted_at, NOW()) WHERE id = $1`
+	_, err := s.pool.Exec(ctx, query, orderID)
+	return err
+}
+
+// StartIdentityVerification links a Stripe Identity session to an order awaiting verification.
+func (s *OrderStore) StartIdentityVerification(ctx context.Context, orderID uuid.UUID, sessionID string) error {
+	query := `UPDATE orders SET verification_status = $1, identity_verification_id = $2 WHERE id = $3`
+	_, err := s.pool.Exec(ctx, query, string(VerificationPending), sessionID, orderID)
+	return err
+}
+
+// UpdateVerificationStatus moves an order out of a pending identity check. Orders that already
+// finished verification are left alone.
+func (s *OrderStore) UpdateVerificationStatus(ctx context.Context, orderID uuid.UUID, status VerificationStatus) error {
+	query := `UPDATE orders SET verification_status = $1 WHERE id = $2 AND verification_status IN ($3, $4)`
+	result, err := s.pool.Exec(ctx, query, string(status), orderID, string(VerificationPending), string(VerificationRequiresInput))
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%w: verification cannot move to %s", ErrInvalidStatusTransition, status)
+	}
+	return nil
+}
+
+// RecordEmail logs a sent order email, along with an email_sent entry in the order's event log.
+// The recipient is encrypted like the order's customer email, and left blank for shops that
+// opted out of keeping customer addresses in the log.
+func (s *OrderStore) RecordEmail(ctx context.Context, orderID uuid.UUID, kind OrderEmailKind, recipient string) error {
+	sealed, err := s.sealField(recipient)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt email recipient: %w", err)
+	}
+	query := `
+		WITH sent AS (
+			INSERT INTO order_emails (order_id, kind, recipient, recipient_hash)
+			SELECT o.id, $2, CASE WHEN s.retain_email_log THEN $3 ELSE '' END, CASE WHEN s.retain_email_log THEN $5 END
+			FROM orders o
+			JOIN shops s ON s.id = o.shop_id
+			WHERE o.id = $1
+			RETURNING order_id, kind
+		)
+		INSERT INTO order_events (order_id, kind, detail)
+		SELECT order_id, $4, kind FROM sent
+	`
+	_, err = s.pool.Exec(ctx, query, orderID, string(kind), sealed, string(OrderEventEmailSent), s.emailHash(recipient))
+	return err
+}
+
+func (s *OrderStore) ListEmails(ctx context.Context, orderID uuid.UUID) ([]*OrderEmail, error) {
+	query := `
+		SELECT id, order_id, kind, recipient, sent_at, delivery_status, delivery_detail
+		FROM order_emails
+		WHERE order_id = $1
+		ORDER BY sent_at ASC
+	`
+	rows, err := s.pool.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := []*OrderEmail{}
+	for rows.Next() {
+		var (
+			email  OrderEmail
+			kind   string
+			sentAt pgtype.Timestamptz
+			status string
+		)
+		if err := rows.Scan(&email.ID, &email.OrderID, &kind, &email.Recipient, &sentAt, &status, &email.DeliveryDetail); err != nil {
+			return nil, err
+		}
+		email.Kind = OrderEmailKind(kind)
+		if email.Recipient, err = s.openField(email.Recipient); err != nil {
+			return nil, err
+		}
+		email.DeliveryStatus = EmailDeliveryStatus(status)
+		if sentAt.Valid {
+			email.SentAt = sentAt.Time
+		}
+		emails = append(emails, &email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// AnonymizeDisconnectedBefore removes customer details from up to limit orders created before
+// cutoff in disconnected shops, and everything forgetOrderBuyers clears about their buyers. An
+// empty region covers every shop. It returns how many orders were anonymized.
+func (s *OrderStore) AnonymizeDisconnectedBefore(ctx context.Context, region string, cutoff time.Time, limit int) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `
+		SELECT o.id
+		FROM orders o
+		JOIN shops s ON s.id = o.shop_id
+		WHERE s.disconnected_at IS NOT NULL
+		  AND o.anonymized_at IS NULL
+		  AND o.created_at < $1
+		  AND ($2 = '' OR s.region IN ('', $2))
+		ORDER BY o.created_at
+		LIMIT $3
+		FOR UPDATE OF o SKIP LOCKED
+	`
+	rows, err := tx.Query(ctx, query, cutoff, region, limit)
+	if err != nil {
+		return 0, err
+	}
+	orderIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return 0, err
+	}
+	if len(orderIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := forgetOrderBuyers(ctx, tx, orderIDs); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(orderIDs), nil
+}
+
 type orderRow struct {
 	ID                      uuid.UUID
 	ShopID                  uuid.UUID
@@ -432,10 +1313,10 @@ type orderRow struct {
 	GithubUsername          string
 	Sku                     string
 	Options                 []byte
-	SubtotalCents           int32
-	ShippingCents           int32
-	TaxCents                pgtype.Int4
-	TotalCents              int32
+	SubtotalCents           int64
+	ShippingCents           int64
+	TaxCents                pgtype.Int8
+	TotalCents              int64
 	StripeCheckoutSessionID pgtype.Text
 	StripePaymentIntentID   pgtype.Text
 	CustomerEmail           pgtype.Text
@@ -449,6 +1330,15 @@ type orderRow struct {
 	PaidAt                  pgtype.Timestamptz
 	ShippedAt               pgtype.Timestamptz
 	DeliveredAt             pgtype.Timestamptz
+	RefundedAt              pgtype.Timestamptz
+	TermsVersion            pgtype.Text
+	TermsAcceptedAt         pgtype.Timestamptz
+	VerificationStatus      pgtype.Text
+	IdentityVerificationID  pgtype.Text
+	StripeInvoiceID         pgtype.Text
+	ReturnedAt              pgtype.Timestamptz
+	CheckoutMethod          string
+	StripePaymentLinkID     pgtype.Text
 }
 
 func (s *OrderStore) rowToOrder(row orderRow) (*Order, error) {
@@ -459,9 +1349,9 @@ func (s *OrderStore) rowToOrder(row orderRow) (*Order, error) {
 		OrderNumber:       int(row.OrderNumber),
 		GitHubUsername:    row.GithubUsername,
 		SKU:               row.Sku,
-		SubtotalCents:     int(row.SubtotalCents),
-		ShippingCents:     int(row.ShippingCents),
-		TotalCents:        int(row.TotalCents),
+		SubtotalCents:     row.SubtotalCents,
+		ShippingCents:     row.ShippingCents,
+		TotalCents:        row.TotalCents,
 		Status:            OrderStatus(row.Status),
 		CreatedAt:         row.CreatedAt.Time,
 	}
@@ -470,7 +1360,7 @@ func (s *OrderStore) rowToOrder(row orderRow) (*Order, error) {
 		order.GitHubIssueURL = row.GithubIssueUrl.String
 	}
 	if row.TaxCents.Valid {
-		order.TaxCents = int(row.TaxCents.Int32)
+		order.TaxCents = row.TaxCents.Int64
 	}
 	if row.StripeCheckoutSessionID.Valid {
 		order.StripeCheckoutSessionID = row.StripeCheckoutSessionID.String
@@ -479,10 +1369,18 @@ func (s *OrderStore) rowToOrder(row orderRow) (*Order, error) {
 		order.StripePaymentIntentID = row.StripePaymentIntentID.String
 	}
 	if row.CustomerEmail.Valid {
-		order.CustomerEmail = row.CustomerEmail.String
+		email, err := s.openField(row.CustomerEmail.String)
+		if err != nil {
+			return nil, err
+		}
+		order.CustomerEmail = email
 	}
 	if row.CustomerName.Valid {
-		order.CustomerName = row.CustomerName.String
+		name, err := s.openField(row.CustomerName.String)
+		if err != nil {
+			return nil, err
+		}
+		order.CustomerName = name
 	}
 	if row.TrackingNumber.Valid {
 		order.TrackingNumber = row.TrackingNumber.String
@@ -502,6 +1400,31 @@ func (s *OrderStore) rowToOrder(row orderRow) (*Order, error) {
 	if row.DeliveredAt.Valid {
 		order.DeliveredAt = row.DeliveredAt.Time
 	}
+	if row.RefundedAt.Valid {
+		order.RefundedAt = row.RefundedAt.Time
+	}
+	if row.TermsVersion.Valid {
+		order.TermsVersion = row.TermsVersion.String
+	}
+	if row.TermsAcceptedAt.Valid {
+		order.TermsAcceptedAt = row.TermsAcceptedAt.Time
+	}
+	if row.VerificationStatus.Valid {
+		order.VerificationStatus = VerificationStatus(row.VerificationStatus.String)
+	}
+	if row.IdentityVerificationID.Valid {
+		order.IdentityVerificationID = row.IdentityVerificationID.String
+	}
+	if row.StripeInvoiceID.Valid {
+		order.StripeInvoiceID = row.StripeInvoiceID.String
+	}
+	if row.ReturnedAt.Valid {
+		order.ReturnedAt = row.ReturnedAt.Time
+	}
+	order.CheckoutMethod = CheckoutMethod(row.CheckoutMethod)
+	if row.StripePaymentLinkID.Valid {
+		order.StripePaymentLinkID = row.StripePaymentLinkID.String
+	}
 
 	if row.Options != nil {
 		if err := json.Unmarshal(row.Options, &order.Options); err != nil {
@@ -509,29 +1432,114 @@ func (s *OrderStore) rowToOrder(row orderRow) (*Order, error) {
 		}
 	}
 
-	if row.ShippingAddress != nil {
-		if err := json.Unmarshal(row.ShippingAddress, &order.ShippingAddress); err != nil {
-			return nil, err
-		}
+	address, err := s.openAddress(row.ShippingAddress)
+	if err != nil {
+		return nil, err
 	}
+	order.ShippingAddress = address
 
 	return order, nil
 }
 
-func (s *OrderStore) populateFailureReason(ctx context.Context, order *Order) error {
-	if order == nil {
+// populateExtraColumns fills order fields whose columns the generated queries do not select,
+// with one query for all the orders.
+func (s *OrderStore) populateExtraColumns(ctx context.Context, db queries.DBTX, orders ...*Order) error {
+	byID := make(map[uuid.UUID]*Order, len(orders))
+	for _, order := range orders {
+		if order != nil {
+			byID[order.ID] = order
+		}
+	}
+	if len(byID) == 0 {
 		return nil
 	}
-	var failureReason pgtype.Text
-	if err := s.pool.QueryRow(ctx, "SELECT failure_reason FROM orders WHERE id = $1", order.ID).Scan(&failureReason); err != nil {
+	orderIDs := make([]uuid.UUID, 0, len(byID))
+	for orderID := range byID {
+		orderIDs = append(orderIDs, orderID)
+	}
+
+	query := "SELECT id, failure_reason, category, cancelled_at, cancellation_reason, approved_at, approved_by, stripe_subscription_id, subscription_status, subscription_cancelled_at, custom_fields, receipt_url, tax_id_type, tax_id, test_mode, address_issue, checkout_reminder_opt_out, checkout_expired_at, campaign, display_currency FROM orders WHERE id = ANY($1)"
+	rows, err := db.Query(ctx, query, orderIDs)
+	if err != nil {
 		return err
 	}
-	if failureReason.Valid {
-		order.FailureReason = failureReason.String
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderID        uuid.UUID
+			failureReason  pgtype.Text
+			category       string
+			cancelledAt    pgtype.Timestamptz
+			cancelReason   string
+			approvedAt     pgtype.Timestamptz
+			approvedBy     string
+			subscription   pgtype.Text
+			subStatus      string
+			subCancelled   pgtype.Timestamptz
+			customFields   []byte
+			receiptURL     pgtype.Text
+			taxIDType      pgtype.Text
+			taxID          pgtype.Text
+			testMode       bool
+			addressIssue   string
+			reminderOptOut bool
+			expiredAt      pgtype.Timestamptz
+			campaign       string
+			currency       string
+		)
+		if err := rows.Scan(&orderID, &failureReason, &category, &cancelledAt, &cancelReason, &approvedAt, &approvedBy, &subscription, &subStatus, &subCancelled, &customFields, &receiptURL, &taxIDType, &taxID, &testMode, &addressIssue, &reminderOptOut, &expiredAt, &campaign, &currency); err != nil {
+			return err
+		}
+		order, ok := byID[orderID]
+		if !ok {
+			continue
+		}
+		delete(byID, orderID)
+		if err := json.Unmarshal(customFields, &order.CustomFields); err != nil {
+			return fmt.Errorf("failed to decode custom fields: %w", err)
+		}
+		order.Category = category
+		order.CancellationReason = cancelReason
+		order.ApprovedBy = approvedBy
+		order.StripeSubscriptionID = subscription.String
+		order.ReceiptURL = receiptURL.String
+		order.TaxIDType = taxIDType.String
+		order.TaxID = taxID.String
+		order.TestMode = testMode
+		order.AddressIssue = addressIssue
+		order.CheckoutReminderOptOut = reminderOptOut
+		order.CheckoutExpiredAt = expiredAt.Time
+		order.Campaign = campaign
+		order.DisplayCurrency = currency
+		order.SubscriptionStatus = SubscriptionStatus(subStatus)
+		if subCancelled.Valid {
+			order.SubscriptionCancelledAt = subCancelled.Time
+		}
+		if failureReason.Valid {
+			order.FailureReason = failureReason.String
+		}
+		if cancelledAt.Valid {
+			order.CancelledAt = cancelledAt.Time
+		}
+		if approvedAt.Valid {
+			order.ApprovedAt = approvedAt.Time
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(byID) > 0 {
+		return pgx.ErrNoRows
 	}
 	return nil
 }
 
+func isOrderIssueConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == orderIssueConstraint
+}
+
 func intToInt32(value int, name string) (int32, error) {
 	if value < math.MinInt32 || value > math.MaxInt32 {
 		return 0, fmt.Errorf("%s out of int32 range: %d", name, value)
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/gitshopapp/gitshop/internal/crypto"
+)
+
+func TestIsOrderIssueConflict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "issue conflict", err: &pgconn.PgError{Code: "23505", ConstraintName: orderIssueConstraint}, want: true},
+		{name: "wrapped issue conflict", err: fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: orderIssueConstraint}), want: true},
+		{name: "other unique constraint", err: &pgconn.PgError{Code: "23505", ConstraintName: "orders_pkey"}, want: false},
+		{name: "other error", err: errors.New("connection reset"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isOrderIssueConflict(tt.err); got != tt.want {
+				t.Fatalf("isOrderIssueConflict() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// paymentDB is one order that moves to paid when markPaid's status guard allows it, counting the
+// paid events written alongside.
+type paymentDB struct {
+	status     OrderStatus
+	paidEvents int
+}
+
+var statusGuard = regexp.MustCompile(`status IN \(([^)]*)\)`)
+
+func (d *paymentDB) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	guard := statusGuard.FindStringSubmatch(sql)
+	if guard == nil {
+		return pgconn.CommandTag{}, fmt.Errorf("unexpected statement %q", sql)
+	}
+	if !strings.Contains(guard[1], "'"+string(d.status)+"'") {
+		return pgconn.NewCommandTag("INSERT 0 0"), nil
+	}
+	d.status = StatusPaid
+	d.paidEvents++
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (d *paymentDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, fmt.Errorf("unexpected query")
+}
+
+func (d *paymentDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return statusRow(d.status)
+}
+
+type statusRow OrderStatus
+
+func (r statusRow) Scan(dest ...any) error {
+	*dest[0].(*string) = string(r)
+	return nil
+}
+
+func TestMarkPaidRedeliveryWritesOnePaidEvent(t *testing.T) {
+	t.Parallel()
+
+	db := &paymentDB{status: StatusPendingPayment}
+	for delivery := 1; delivery <= 2; delivery++ {
+		if err := markPaid(context.Background(), db, uuid.New(), "pi_123", "", "", nil, pgtype.Text{}, "stripe_checkout"); err != nil {
+			t.Fatalf("delivery %d: markPaid() error = %v", delivery, err)
+		}
+	}
+	if db.paidEvents != 1 {
+		t.Fatalf("paid events = %d, want 1", db.paidEvents)
+	}
+
+	shipped := &paymentDB{status: StatusShipped}
+	if err := markPaid(context.Background(), shipped, uuid.New(), "pi_123", "", "", nil, pgtype.Text{}, "stripe_checkout"); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Fatalf("markPaid(shipped) error = %v, want ErrInvalidStatusTransition", err)
+	}
+	if shipped.paidEvents != 0 {
+		t.Fatalf("paid events for a shipped order = %d, want 0", shipped.paidEvents)
+	}
+}
+
+func TestOrderStoreCustomerDataEncryption(t *testing.T) {
+	t.Parallel()
+
+	encryptor, err := crypto.NewEncryptor(strings.Repeat("k", 32))
+	if err != nil {
+		t.Fatalf("NewEncryptor() error = %v", err)
+	}
+	emailIndex, err := NewCustomerEmailIndex(strings.Repeat("k", 32))
+	if err != nil {
+		t.Fatalf("NewCustomerEmailIndex() error = %v", err)
+	}
+	store := &OrderStore{crypto: encryptor, emailIndex: emailIndex}
+
+	sealed, err := store.sealField("mona@example.com")
+	if err != nil {
+		t.Fatalf("sealField() error = %v", err)
+	}
+	if sealed == "mona@example.com" {
+		t.Fatal("sealField() stored plaintext")
+	}
+	if got, err := store.openField(sealed); err != nil || got != "mona@example.com" {
+		t.Fatalf("openField(sealed) = %q, %v", got, err)
+	}
+	if got, err := store.openField("legacy@example.com"); err != nil || got != "legacy@example.com" {
+		t.Fatalf("openField(plaintext) = %q, %v", got, err)
+	}
+	if got, err := store.openField("Mona: shipping desk"); err != nil || got != "Mona: shipping desk" {
+		t.Fatalf("openField(plaintext with colon) = %q, %v", got, err)
+	}
+
+	rotated, err := crypto.NewKeyring(strings.Repeat("k", 32), []string{"v2:" + strings.Repeat("n", 32)})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	sealedElsewhere, err := rotated.Encrypt("mona@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if got, err := store.openField(sealedElsewhere); err == nil || got != "" {
+		t.Fatalf("openField(unknown key) = %q, %v, want an error", got, err)
+	}
+
+	address := map[string]any{"line1": "1 Main St", "city": "Springfield"}
+	sealedAddress, err := store.sealAddress(address)
+	if err != nil {
+		t.Fatalf("sealAddress() error = %v", err)
+	}
+	if strings.Contains(string(sealedAddress), "Main") {
+		t.Fatalf("sealAddress() = %s, want ciphertext", sealedAddress)
+	}
+	for name, raw := range map[string][]byte{
+		"sealed":    sealedAddress,
+		"plaintext": []byte(`{"line1":"1 Main St","city":"Springfield"}`),
+	} {
+		got, err := store.openAddress(raw)
+		if err != nil || got["line1"] != "1 Main St" || got["city"] != "Springfield" {
+			t.Fatalf("openAddress(%s) = %v, %v", name, got, err)
+		}
+	}
+	for _, raw := range [][]byte{nil, []byte("null")} {
+		if got, err := store.openAddress(raw); err != nil || got != nil {
+			t.Fatalf("openAddress(%q) = %v, %v, want nil", raw, got, err)
+		}
+	}
+
+	if store.emailHash(" Mona@Example.com ") != store.emailHash("mona@example.com") {
+		t.Fatal("emailHash() is not case-insensitive")
+	}
+	if store.emailHash("").Valid {
+		t.Fatal("emailHash(\"\") is valid, want NULL")
+	}
+}
+
+func TestKeyRotationStoreRotate(t *testing.T) {
+	t.Parallel()
+
+	legacyKey := strings.Repeat("a", 32)
+	old, err := crypto.NewKeyring(legacyKey, []string{"k1:" + strings.Repeat("b", 32)})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	current, err := crypto.NewKeyring(legacyKey, []string{"k1:" + strings.Repeat("b", 32), "k2:" + strings.Repeat("c", 32)})
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	store := &KeyRotationStore{crypto: current}
+
+	k1Secret, err := old.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	rotated, changed, err := store.rotate(k1Secret, false)
+	if err != nil || !changed || crypto.KeyID(rotated) != "k2" {
+		t.Fatalf("rotate(k1) = %q, %v, %v", rotated, changed, err)
+	}
+	if plaintext, err := current.Decrypt(rotated); err != nil || plaintext != "secret" {
+		t.Fatalf("Decrypt(rotated) = %q, %v", plaintext, err)
+	}
+	if again, changed, err := store.rotate(rotated, false); err != nil || changed || again != rotated {
+		t.Fatalf("rotate(k2) = %q, %v, %v, want unchanged", again, changed, err)
+	}
+
+	if _, _, err := store.rotate("plain-api-key", false); err == nil {
+		t.Fatal("rotate() should fail on a value that does not decrypt")
+	}
+	sealed, changed, err := store.rotate("plain-api-key", true)
+	if err != nil || !changed || crypto.KeyID(sealed) != "k2" {
+		t.Fatalf("rotate(plaintext) = %q, %v, %v", sealed, changed, err)
+	}
+
+	other, err := crypto.NewKeyring(strings.Repeat("z", 32), nil)
+	if err != nil {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	foreign, err := other.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if _, _, err := store.rotate(foreign, true); err == nil {
+		t.Fatal("rotate() should not re-encrypt ciphertext from another key as plaintext")
+	}
+}
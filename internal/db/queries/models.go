@@ -18,10 +18,10 @@ type Order struct {
 	GithubUsername          string             `json:"github_username"`
 	Sku                     string             `json:"sku"`
 	Options                 []byte             `json:"options"`
-	SubtotalCents           int32              `json:"subtotal_cents"`
-	ShippingCents           int32              `json:"shipping_cents"`
-	TaxCents                pgtype.Int4        `json:"tax_cents"`
-	TotalCents              int32              `json:"total_cents"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
 	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
 	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
 	CustomerEmail           pgtype.Text        `json:"customer_email"`
@@ -36,6 +36,24 @@ type Order struct {
 	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
 	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
 	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
+	Category                string             `json:"category"`
+}
+
+type OrderEmail struct {
+	ID        uuid.UUID          `json:"id"`
+	OrderID   uuid.UUID          `json:"order_id"`
+	Kind      string             `json:"kind"`
+	Recipient string             `json:"recipient"`
+	SentAt    pgtype.Timestamptz `json:"sent_at"`
 }
 
 type Shop struct {
@@ -54,4 +72,9 @@ type Shop struct {
 	OnboardedAt            pgtype.Timestamptz `json:"onboarded_at"`
 	CreatedAt              pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
+	StripeDetailsSubmitted bool               `json:"stripe_details_submitted"`
+	StripeChargesEnabled   bool               `json:"stripe_charges_enabled"`
+	StripePayoutsEnabled   bool               `json:"stripe_payouts_enabled"`
+	// When Stripe last reported the connected account capabilities; NULL means unknown
+	StripeCapabilitiesCheckedAt pgtype.Timestamptz `json:"stripe_capabilities_checked_at"`
 }
@@ -1,23 +1,31 @@
 -- name: CreateOrder :one
+WITH next_number AS (
+    INSERT INTO order_number_sequences (shop_id, last_order_number)
+    VALUES ($1, 1)
+    ON CONFLICT (shop_id) DO UPDATE SET last_order_number = order_number_sequences.last_order_number + 1
+    RETURNING last_order_number
+)
 INSERT INTO orders (
     shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
     options, subtotal_cents, shipping_cents, tax_cents, total_cents,
-    stripe_checkout_session_id, customer_email, customer_name, shipping_address, status
-) VALUES (
-    $1, $2, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+    stripe_checkout_session_id, customer_email, customer_name, shipping_address, status,
+    terms_version, terms_accepted_at, verification_status, category, test_mode, campaign, display_currency
 )
+SELECT
+    $1, $2, next_number.last_order_number, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
+FROM next_number
 RETURNING id, shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
           options, subtotal_cents, shipping_cents, tax_cents, total_cents,
           stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
           shipping_address, tracking_number, tracking_url, carrier, status,
-          created_at, paid_at, shipped_at, delivered_at;
+          created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id;
 
 -- name: GetOrderByStripeSessionID :one
 SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders 
 WHERE stripe_checkout_session_id = $1;
 
@@ -26,7 +34,7 @@ SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders
 WHERE id = $1;
 
@@ -35,7 +43,7 @@ SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders
 WHERE shop_id = $1 AND github_issue_number = $2;
 
@@ -44,12 +52,36 @@ SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders 
 WHERE shop_id = $1 
-ORDER BY created_at DESC 
+ORDER BY created_at DESC, id DESC 
 LIMIT $2;
 
+-- name: GetOrdersByShopNewerThan :many
+SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
+       options, subtotal_cents, shipping_cents, tax_cents, total_cents,
+       stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
+       shipping_address, tracking_number, tracking_url, carrier, status,
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
+FROM orders
+WHERE shop_id = sqlc.arg(shop_id)
+  AND (created_at, id) > (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(page_size);
+
+-- name: GetOrdersByShopOlderThan :many
+SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
+       options, subtotal_cents, shipping_cents, tax_cents, total_cents,
+       stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
+       shipping_address, tracking_number, tracking_url, carrier, status,
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
+FROM orders
+WHERE shop_id = sqlc.arg(shop_id)
+  AND (created_at, id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
 -- name: UpdateOrderStatus :exec
 UPDATE orders 
 SET status = $2
@@ -13,36 +13,51 @@ import (
 )
 
 const createOrder = `-- name: CreateOrder :one
+WITH next_number AS (
+    INSERT INTO order_number_sequences (shop_id, last_order_number)
+    VALUES ($1, 1)
+    ON CONFLICT (shop_id) DO UPDATE SET last_order_number = order_number_sequences.last_order_number + 1
+    RETURNING last_order_number
+)
 INSERT INTO orders (
     shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
     options, subtotal_cents, shipping_cents, tax_cents, total_cents,
-    stripe_checkout_session_id, customer_email, customer_name, shipping_address, status
-) VALUES (
-    $1, $2, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+    stripe_checkout_session_id, customer_email, customer_name, shipping_address, status,
+    terms_version, terms_accepted_at, verification_status, category, test_mode, campaign, display_currency
 )
+SELECT
+    $1, $2, next_number.last_order_number, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
+FROM next_number
 RETURNING id, shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
           options, subtotal_cents, shipping_cents, tax_cents, total_cents,
           stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
           shipping_address, tracking_number, tracking_url, carrier, status,
-          created_at, paid_at, shipped_at, delivered_at
+          created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 `
 
 type CreateOrderParams struct {
-	ShopID                  uuid.UUID   `json:"shop_id"`
-	GithubIssueNumber       int32       `json:"github_issue_number"`
-	GithubIssueUrl          pgtype.Text `json:"github_issue_url"`
-	GithubUsername          string      `json:"github_username"`
-	Sku                     string      `json:"sku"`
-	Options                 []byte      `json:"options"`
-	SubtotalCents           int32       `json:"subtotal_cents"`
-	ShippingCents           int32       `json:"shipping_cents"`
-	TaxCents                pgtype.Int4 `json:"tax_cents"`
-	TotalCents              int32       `json:"total_cents"`
-	StripeCheckoutSessionID pgtype.Text `json:"stripe_checkout_session_id"`
-	CustomerEmail           pgtype.Text `json:"customer_email"`
-	CustomerName            pgtype.Text `json:"customer_name"`
-	ShippingAddress         []byte      `json:"shipping_address"`
-	Status                  string      `json:"status"`
+	ShopID                  uuid.UUID          `json:"shop_id"`
+	GithubIssueNumber       int32              `json:"github_issue_number"`
+	GithubIssueUrl          pgtype.Text        `json:"github_issue_url"`
+	GithubUsername          string             `json:"github_username"`
+	Sku                     string             `json:"sku"`
+	Options                 []byte             `json:"options"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
+	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
+	CustomerEmail           pgtype.Text        `json:"customer_email"`
+	CustomerName            pgtype.Text        `json:"customer_name"`
+	ShippingAddress         []byte             `json:"shipping_address"`
+	Status                  string             `json:"status"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	Category                string             `json:"category"`
+	TestMode                bool               `json:"test_mode"`
+	Campaign                string             `json:"campaign"`
+	DisplayCurrency         string             `json:"display_currency"`
 }
 
 type CreateOrderRow struct {
@@ -54,10 +69,10 @@ type CreateOrderRow struct {
 	GithubUsername          string             `json:"github_username"`
 	Sku                     string             `json:"sku"`
 	Options                 []byte             `json:"options"`
-	SubtotalCents           int32              `json:"subtotal_cents"`
-	ShippingCents           int32              `json:"shipping_cents"`
-	TaxCents                pgtype.Int4        `json:"tax_cents"`
-	TotalCents              int32              `json:"total_cents"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
 	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
 	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
 	CustomerEmail           pgtype.Text        `json:"customer_email"`
@@ -71,6 +86,15 @@ type CreateOrderRow struct {
 	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
 	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
 	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (CreateOrderRow, error) {
@@ -90,6 +114,13 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Creat
 		arg.CustomerName,
 		arg.ShippingAddress,
 		arg.Status,
+		arg.TermsVersion,
+		arg.TermsAcceptedAt,
+		arg.VerificationStatus,
+		arg.Category,
+		arg.TestMode,
+		arg.Campaign,
+		arg.DisplayCurrency,
 	)
 	var i CreateOrderRow
 	err := row.Scan(
@@ -118,6 +149,15 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Creat
 		&i.PaidAt,
 		&i.ShippedAt,
 		&i.DeliveredAt,
+		&i.RefundedAt,
+		&i.TermsVersion,
+		&i.TermsAcceptedAt,
+		&i.VerificationStatus,
+		&i.IdentityVerificationID,
+		&i.StripeInvoiceID,
+		&i.ReturnedAt,
+		&i.CheckoutMethod,
+		&i.StripePaymentLinkID,
 	)
 	return i, err
 }
@@ -127,7 +167,7 @@ SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders
 WHERE id = $1
 `
@@ -141,10 +181,10 @@ type GetOrderByIDRow struct {
 	GithubUsername          string             `json:"github_username"`
 	Sku                     string             `json:"sku"`
 	Options                 []byte             `json:"options"`
-	SubtotalCents           int32              `json:"subtotal_cents"`
-	ShippingCents           int32              `json:"shipping_cents"`
-	TaxCents                pgtype.Int4        `json:"tax_cents"`
-	TotalCents              int32              `json:"total_cents"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
 	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
 	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
 	CustomerEmail           pgtype.Text        `json:"customer_email"`
@@ -158,6 +198,15 @@ type GetOrderByIDRow struct {
 	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
 	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
 	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
 }
 
 func (q *Queries) GetOrderByID(ctx context.Context, id uuid.UUID) (GetOrderByIDRow, error) {
@@ -189,6 +238,15 @@ func (q *Queries) GetOrderByID(ctx context.Context, id uuid.UUID) (GetOrderByIDR
 		&i.PaidAt,
 		&i.ShippedAt,
 		&i.DeliveredAt,
+		&i.RefundedAt,
+		&i.TermsVersion,
+		&i.TermsAcceptedAt,
+		&i.VerificationStatus,
+		&i.IdentityVerificationID,
+		&i.StripeInvoiceID,
+		&i.ReturnedAt,
+		&i.CheckoutMethod,
+		&i.StripePaymentLinkID,
 	)
 	return i, err
 }
@@ -198,7 +256,7 @@ SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders
 WHERE shop_id = $1 AND github_issue_number = $2
 `
@@ -217,10 +275,10 @@ type GetOrderByIssueNumberRow struct {
 	GithubUsername          string             `json:"github_username"`
 	Sku                     string             `json:"sku"`
 	Options                 []byte             `json:"options"`
-	SubtotalCents           int32              `json:"subtotal_cents"`
-	ShippingCents           int32              `json:"shipping_cents"`
-	TaxCents                pgtype.Int4        `json:"tax_cents"`
-	TotalCents              int32              `json:"total_cents"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
 	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
 	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
 	CustomerEmail           pgtype.Text        `json:"customer_email"`
@@ -234,6 +292,15 @@ type GetOrderByIssueNumberRow struct {
 	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
 	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
 	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
 }
 
 func (q *Queries) GetOrderByIssueNumber(ctx context.Context, arg GetOrderByIssueNumberParams) (GetOrderByIssueNumberRow, error) {
@@ -265,6 +332,15 @@ func (q *Queries) GetOrderByIssueNumber(ctx context.Context, arg GetOrderByIssue
 		&i.PaidAt,
 		&i.ShippedAt,
 		&i.DeliveredAt,
+		&i.RefundedAt,
+		&i.TermsVersion,
+		&i.TermsAcceptedAt,
+		&i.VerificationStatus,
+		&i.IdentityVerificationID,
+		&i.StripeInvoiceID,
+		&i.ReturnedAt,
+		&i.CheckoutMethod,
+		&i.StripePaymentLinkID,
 	)
 	return i, err
 }
@@ -274,7 +350,7 @@ SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders 
 WHERE stripe_checkout_session_id = $1
 `
@@ -288,10 +364,10 @@ type GetOrderByStripeSessionIDRow struct {
 	GithubUsername          string             `json:"github_username"`
 	Sku                     string             `json:"sku"`
 	Options                 []byte             `json:"options"`
-	SubtotalCents           int32              `json:"subtotal_cents"`
-	ShippingCents           int32              `json:"shipping_cents"`
-	TaxCents                pgtype.Int4        `json:"tax_cents"`
-	TotalCents              int32              `json:"total_cents"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
 	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
 	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
 	CustomerEmail           pgtype.Text        `json:"customer_email"`
@@ -305,6 +381,15 @@ type GetOrderByStripeSessionIDRow struct {
 	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
 	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
 	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
 }
 
 func (q *Queries) GetOrderByStripeSessionID(ctx context.Context, stripeCheckoutSessionID pgtype.Text) (GetOrderByStripeSessionIDRow, error) {
@@ -336,6 +421,15 @@ func (q *Queries) GetOrderByStripeSessionID(ctx context.Context, stripeCheckoutS
 		&i.PaidAt,
 		&i.ShippedAt,
 		&i.DeliveredAt,
+		&i.RefundedAt,
+		&i.TermsVersion,
+		&i.TermsAcceptedAt,
+		&i.VerificationStatus,
+		&i.IdentityVerificationID,
+		&i.StripeInvoiceID,
+		&i.ReturnedAt,
+		&i.CheckoutMethod,
+		&i.StripePaymentLinkID,
 	)
 	return i, err
 }
@@ -345,10 +439,10 @@ SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_
        options, subtotal_cents, shipping_cents, tax_cents, total_cents,
        stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
        shipping_address, tracking_number, tracking_url, carrier, status,
-       created_at, paid_at, shipped_at, delivered_at
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
 FROM orders 
 WHERE shop_id = $1 
-ORDER BY created_at DESC 
+ORDER BY created_at DESC, id DESC 
 LIMIT $2
 `
 
@@ -366,10 +460,10 @@ type GetOrdersByShopRow struct {
 	GithubUsername          string             `json:"github_username"`
 	Sku                     string             `json:"sku"`
 	Options                 []byte             `json:"options"`
-	SubtotalCents           int32              `json:"subtotal_cents"`
-	ShippingCents           int32              `json:"shipping_cents"`
-	TaxCents                pgtype.Int4        `json:"tax_cents"`
-	TotalCents              int32              `json:"total_cents"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
 	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
 	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
 	CustomerEmail           pgtype.Text        `json:"customer_email"`
@@ -383,6 +477,15 @@ type GetOrdersByShopRow struct {
 	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
 	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
 	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
 }
 
 func (q *Queries) GetOrdersByShop(ctx context.Context, arg GetOrdersByShopParams) ([]GetOrdersByShopRow, error) {
@@ -420,6 +523,249 @@ func (q *Queries) GetOrdersByShop(ctx context.Context, arg GetOrdersByShopParams
 			&i.PaidAt,
 			&i.ShippedAt,
 			&i.DeliveredAt,
+			&i.RefundedAt,
+			&i.TermsVersion,
+			&i.TermsAcceptedAt,
+			&i.VerificationStatus,
+			&i.IdentityVerificationID,
+			&i.StripeInvoiceID,
+			&i.ReturnedAt,
+			&i.CheckoutMethod,
+			&i.StripePaymentLinkID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersByShopNewerThan = `-- name: GetOrdersByShopNewerThan :many
+SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
+       options, subtotal_cents, shipping_cents, tax_cents, total_cents,
+       stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
+       shipping_address, tracking_number, tracking_url, carrier, status,
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
+FROM orders
+WHERE shop_id = $1
+  AND (created_at, id) > ($2::timestamptz, $3::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT $4
+`
+
+type GetOrdersByShopNewerThanParams struct {
+	ShopID    uuid.UUID          `json:"shop_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID        uuid.UUID          `json:"id"`
+	PageSize  int32              `json:"page_size"`
+}
+
+type GetOrdersByShopNewerThanRow struct {
+	ID                      uuid.UUID          `json:"id"`
+	ShopID                  uuid.UUID          `json:"shop_id"`
+	GithubIssueNumber       int32              `json:"github_issue_number"`
+	OrderNumber             int32              `json:"order_number"`
+	GithubIssueUrl          pgtype.Text        `json:"github_issue_url"`
+	GithubUsername          string             `json:"github_username"`
+	Sku                     string             `json:"sku"`
+	Options                 []byte             `json:"options"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
+	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
+	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
+	CustomerEmail           pgtype.Text        `json:"customer_email"`
+	CustomerName            pgtype.Text        `json:"customer_name"`
+	ShippingAddress         []byte             `json:"shipping_address"`
+	TrackingNumber          pgtype.Text        `json:"tracking_number"`
+	TrackingUrl             pgtype.Text        `json:"tracking_url"`
+	Carrier                 pgtype.Text        `json:"carrier"`
+	Status                  string             `json:"status"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
+	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
+	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
+}
+
+func (q *Queries) GetOrdersByShopNewerThan(ctx context.Context, arg GetOrdersByShopNewerThanParams) ([]GetOrdersByShopNewerThanRow, error) {
+	rows, err := q.db.Query(ctx, getOrdersByShopNewerThan,
+		arg.ShopID,
+		arg.CreatedAt,
+		arg.ID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrdersByShopNewerThanRow
+	for rows.Next() {
+		var i GetOrdersByShopNewerThanRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShopID,
+			&i.GithubIssueNumber,
+			&i.OrderNumber,
+			&i.GithubIssueUrl,
+			&i.GithubUsername,
+			&i.Sku,
+			&i.Options,
+			&i.SubtotalCents,
+			&i.ShippingCents,
+			&i.TaxCents,
+			&i.TotalCents,
+			&i.StripeCheckoutSessionID,
+			&i.StripePaymentIntentID,
+			&i.CustomerEmail,
+			&i.CustomerName,
+			&i.ShippingAddress,
+			&i.TrackingNumber,
+			&i.TrackingUrl,
+			&i.Carrier,
+			&i.Status,
+			&i.CreatedAt,
+			&i.PaidAt,
+			&i.ShippedAt,
+			&i.DeliveredAt,
+			&i.RefundedAt,
+			&i.TermsVersion,
+			&i.TermsAcceptedAt,
+			&i.VerificationStatus,
+			&i.IdentityVerificationID,
+			&i.StripeInvoiceID,
+			&i.ReturnedAt,
+			&i.CheckoutMethod,
+			&i.StripePaymentLinkID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrdersByShopOlderThan = `-- name: GetOrdersByShopOlderThan :many
+SELECT id, shop_id, github_issue_number, order_number, github_issue_url, github_username, sku,
+       options, subtotal_cents, shipping_cents, tax_cents, total_cents,
+       stripe_checkout_session_id, stripe_payment_intent_id, customer_email, customer_name,
+       shipping_address, tracking_number, tracking_url, carrier, status,
+       created_at, paid_at, shipped_at, delivered_at, refunded_at, terms_version, terms_accepted_at, verification_status, identity_verification_id, stripe_invoice_id, returned_at, checkout_method, stripe_payment_link_id
+FROM orders
+WHERE shop_id = $1
+  AND (created_at, id) < ($2::timestamptz, $3::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $4
+`
+
+type GetOrdersByShopOlderThanParams struct {
+	ShopID    uuid.UUID          `json:"shop_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID        uuid.UUID          `json:"id"`
+	PageSize  int32              `json:"page_size"`
+}
+
+type GetOrdersByShopOlderThanRow struct {
+	ID                      uuid.UUID          `json:"id"`
+	ShopID                  uuid.UUID          `json:"shop_id"`
+	GithubIssueNumber       int32              `json:"github_issue_number"`
+	OrderNumber             int32              `json:"order_number"`
+	GithubIssueUrl          pgtype.Text        `json:"github_issue_url"`
+	GithubUsername          string             `json:"github_username"`
+	Sku                     string             `json:"sku"`
+	Options                 []byte             `json:"options"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                pgtype.Int8        `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
+	StripeCheckoutSessionID pgtype.Text        `json:"stripe_checkout_session_id"`
+	StripePaymentIntentID   pgtype.Text        `json:"stripe_payment_intent_id"`
+	CustomerEmail           pgtype.Text        `json:"customer_email"`
+	CustomerName            pgtype.Text        `json:"customer_name"`
+	ShippingAddress         []byte             `json:"shipping_address"`
+	TrackingNumber          pgtype.Text        `json:"tracking_number"`
+	TrackingUrl             pgtype.Text        `json:"tracking_url"`
+	Carrier                 pgtype.Text        `json:"carrier"`
+	Status                  string             `json:"status"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+	PaidAt                  pgtype.Timestamptz `json:"paid_at"`
+	ShippedAt               pgtype.Timestamptz `json:"shipped_at"`
+	DeliveredAt             pgtype.Timestamptz `json:"delivered_at"`
+	RefundedAt              pgtype.Timestamptz `json:"refunded_at"`
+	TermsVersion            pgtype.Text        `json:"terms_version"`
+	TermsAcceptedAt         pgtype.Timestamptz `json:"terms_accepted_at"`
+	VerificationStatus      pgtype.Text        `json:"verification_status"`
+	IdentityVerificationID  pgtype.Text        `json:"identity_verification_id"`
+	StripeInvoiceID         pgtype.Text        `json:"stripe_invoice_id"`
+	ReturnedAt              pgtype.Timestamptz `json:"returned_at"`
+	CheckoutMethod          string             `json:"checkout_method"`
+	StripePaymentLinkID     pgtype.Text        `json:"stripe_payment_link_id"`
+}
+
+func (q *Queries) GetOrdersByShopOlderThan(ctx context.Context, arg GetOrdersByShopOlderThanParams) ([]GetOrdersByShopOlderThanRow, error) {
+	rows, err := q.db.Query(ctx, getOrdersByShopOlderThan,
+		arg.ShopID,
+		arg.CreatedAt,
+		arg.ID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrdersByShopOlderThanRow
+	for rows.Next() {
+		var i GetOrdersByShopOlderThanRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShopID,
+			&i.GithubIssueNumber,
+			&i.OrderNumber,
+			&i.GithubIssueUrl,
+			&i.GithubUsername,
+			&i.Sku,
+			&i.Options,
+			&i.SubtotalCents,
+			&i.ShippingCents,
+			&i.TaxCents,
+			&i.TotalCents,
+			&i.StripeCheckoutSessionID,
+			&i.StripePaymentIntentID,
+			&i.CustomerEmail,
+			&i.CustomerName,
+			&i.ShippingAddress,
+			&i.TrackingNumber,
+			&i.TrackingUrl,
+			&i.Carrier,
+			&i.Status,
+			&i.CreatedAt,
+			&i.PaidAt,
+			&i.ShippedAt,
+			&i.DeliveredAt,
+			&i.RefundedAt,
+			&i.TermsVersion,
+			&i.TermsAcceptedAt,
+			&i.VerificationStatus,
+			&i.IdentityVerificationID,
+			&i.StripeInvoiceID,
+			&i.ReturnedAt,
+			&i.CheckoutMethod,
+			&i.StripePaymentLinkID,
 		); err != nil {
 			return nil, err
 		}
@@ -23,6 +23,8 @@ type Querier interface {
 	GetOrderByIssueNumber(ctx context.Context, arg GetOrderByIssueNumberParams) (GetOrderByIssueNumberRow, error)
 	GetOrderByStripeSessionID(ctx context.Context, stripeCheckoutSessionID pgtype.Text) (GetOrderByStripeSessionIDRow, error)
 	GetOrdersByShop(ctx context.Context, arg GetOrdersByShopParams) ([]GetOrdersByShopRow, error)
+	GetOrdersByShopNewerThan(ctx context.Context, arg GetOrdersByShopNewerThanParams) ([]GetOrdersByShopNewerThanRow, error)
+	GetOrdersByShopOlderThan(ctx context.Context, arg GetOrdersByShopOlderThanParams) ([]GetOrdersByShopOlderThanRow, error)
 	GetShopByID(ctx context.Context, id uuid.UUID) (GetShopByIDRow, error)
 	GetShopByInstallationAndRepoID(ctx context.Context, arg GetShopByInstallationAndRepoIDParams) (GetShopByInstallationAndRepoIDRow, error)
 	GetShopByInstallationID(ctx context.Context, githubInstallationID int64) (GetShopByInstallationIDRow, error)
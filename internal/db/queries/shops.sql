@@ -59,8 +59,10 @@ WHERE id = $1;
 UPDATE shops
 SET disconnected_at = NOW(),
     stripe_connect_account_id = NULL,
+    stripe_capabilities_checked_at = NULL,
     email_config = '{}',
     email_verified = FALSE,
+    payment_config = '{}',
     updated_at = NOW()
 WHERE github_installation_id = $1 AND github_repo_id = $2;
 
@@ -77,9 +79,36 @@ WHERE id = $1;
 
 -- name: UpdateShopStripeConnectAccount :exec
 UPDATE shops
-SET stripe_connect_account_id = $2, updated_at = NOW()
+SET stripe_capabilities_checked_at = CASE
+        WHEN stripe_connect_account_id IS DISTINCT FROM $2 THEN NULL
+        ELSE stripe_capabilities_checked_at
+    END,
+    stripe_connect_account_id = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetShopStripeCapabilities :one
+SELECT stripe_details_submitted, stripe_charges_enabled, stripe_payouts_enabled, stripe_capabilities_checked_at
+FROM shops
 WHERE id = $1;
 
+-- name: RecordShopStripeCapabilities :one
+WITH previous AS (
+    SELECT id, stripe_details_submitted, stripe_charges_enabled, stripe_payouts_enabled, stripe_capabilities_checked_at
+    FROM shops
+    WHERE id = $1
+    FOR UPDATE
+)
+UPDATE shops
+SET stripe_details_submitted = $2,
+    stripe_charges_enabled = $3,
+    stripe_payouts_enabled = $4,
+    stripe_capabilities_checked_at = NOW(),
+    updated_at = NOW()
+FROM previous
+WHERE shops.id = previous.id
+RETURNING previous.stripe_details_submitted, previous.stripe_charges_enabled, previous.stripe_payouts_enabled, previous.stripe_capabilities_checked_at;
+
 -- name: MarkShopOnboarded :exec
 UPDATE shops
 SET onboarded_at = COALESCE(onboarded_at, NOW()),
@@ -84,8 +84,10 @@ const disconnectShop = `-- name: DisconnectShop :exec
 UPDATE shops
 SET disconnected_at = NOW(),
     stripe_connect_account_id = NULL,
+    stripe_capabilities_checked_at = NULL,
     email_config = '{}',
     email_verified = FALSE,
+    payment_config = '{}',
     updated_at = NOW()
 WHERE github_installation_id = $1 AND github_repo_id = $2
 `
@@ -416,6 +418,31 @@ func (q *Queries) GetShopByRepoID(ctx context.Context, githubRepoID int64) (GetS
 	return i, err
 }
 
+const getShopStripeCapabilities = `-- name: GetShopStripeCapabilities :one
+SELECT stripe_details_submitted, stripe_charges_enabled, stripe_payouts_enabled, stripe_capabilities_checked_at
+FROM shops
+WHERE id = $1
+`
+
+type GetShopStripeCapabilitiesRow struct {
+	StripeDetailsSubmitted      bool               `json:"stripe_details_submitted"`
+	StripeChargesEnabled        bool               `json:"stripe_charges_enabled"`
+	StripePayoutsEnabled        bool               `json:"stripe_payouts_enabled"`
+	StripeCapabilitiesCheckedAt pgtype.Timestamptz `json:"stripe_capabilities_checked_at"`
+}
+
+func (q *Queries) GetShopStripeCapabilities(ctx context.Context, id uuid.UUID) (GetShopStripeCapabilitiesRow, error) {
+	row := q.db.QueryRow(ctx, getShopStripeCapabilities, id)
+	var i GetShopStripeCapabilitiesRow
+	err := row.Scan(
+		&i.StripeDetailsSubmitted,
+		&i.StripeChargesEnabled,
+		&i.StripePayoutsEnabled,
+		&i.StripeCapabilitiesCheckedAt,
+	)
+	return i, err
+}
+
 const getShopsByInstallationID = `-- name: GetShopsByInstallationID :many
 SELECT id, github_installation_id, github_repo_id, github_repo_full_name, owner_email,
        email_provider, email_config, email_verified,
@@ -487,6 +514,55 @@ func (q *Queries) MarkShopOnboarded(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const recordShopStripeCapabilities = `-- name: RecordShopStripeCapabilities :one
+WITH previous AS (
+    SELECT id, stripe_details_submitted, stripe_charges_enabled, stripe_payouts_enabled, stripe_capabilities_checked_at
+    FROM shops
+    WHERE id = $1
+    FOR UPDATE
+)
+UPDATE shops
+SET stripe_details_submitted = $2,
+    stripe_charges_enabled = $3,
+    stripe_payouts_enabled = $4,
+    stripe_capabilities_checked_at = NOW(),
+    updated_at = NOW()
+FROM previous
+WHERE shops.id = previous.id
+RETURNING previous.stripe_details_submitted, previous.stripe_charges_enabled, previous.stripe_payouts_enabled, previous.stripe_capabilities_checked_at
+`
+
+type RecordShopStripeCapabilitiesParams struct {
+	ID                     uuid.UUID `json:"id"`
+	StripeDetailsSubmitted bool      `json:"stripe_details_submitted"`
+	StripeChargesEnabled   bool      `json:"stripe_charges_enabled"`
+	StripePayoutsEnabled   bool      `json:"stripe_payouts_enabled"`
+}
+
+type RecordShopStripeCapabilitiesRow struct {
+	StripeDetailsSubmitted      bool               `json:"stripe_details_submitted"`
+	StripeChargesEnabled        bool               `json:"stripe_charges_enabled"`
+	StripePayoutsEnabled        bool               `json:"stripe_payouts_enabled"`
+	StripeCapabilitiesCheckedAt pgtype.Timestamptz `json:"stripe_capabilities_checked_at"`
+}
+
+func (q *Queries) RecordShopStripeCapabilities(ctx context.Context, arg RecordShopStripeCapabilitiesParams) (RecordShopStripeCapabilitiesRow, error) {
+	row := q.db.QueryRow(ctx, recordShopStripeCapabilities,
+		arg.ID,
+		arg.StripeDetailsSubmitted,
+		arg.StripeChargesEnabled,
+		arg.StripePayoutsEnabled,
+	)
+	var i RecordShopStripeCapabilitiesRow
+	err := row.Scan(
+		&i.StripeDetailsSubmitted,
+		&i.StripeChargesEnabled,
+		&i.StripePayoutsEnabled,
+		&i.StripeCapabilitiesCheckedAt,
+	)
+	return i, err
+}
+
 const reconnectShop = `-- name: ReconnectShop :exec
 UPDATE shops
 SET disconnected_at = NULL,
@@ -546,7 +622,12 @@ func (q *Queries) UpdateShopRepoFullName(ctx context.Context, arg UpdateShopRepo
 
 const updateShopStripeConnectAccount = `-- name: UpdateShopStripeConnectAccount :exec
 UPDATE shops
-SET stripe_connect_account_id = $2, updated_at = NOW()
+SET stripe_capabilities_checked_at = CASE
+        WHEN stripe_connect_account_id IS DISTINCT FROM $2 THEN NULL
+        ELSE stripe_capabilities_checked_at
+    END,
+    stripe_connect_account_id = $2,
+    updated_at = NOW()
 WHERE id = $1
 `
 
@@ -0,0 +1,228 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	readPoolCheckInterval = 15 * time.Second
+	readPoolPingTimeout   = 3 * time.Second
+)
+
+// ReadPool sends read-only queries that can tolerate replication lag, such as dashboard lists
+// and analytics, to read replicas in turn. A replica that fails a health check or a query is
+// skipped until it answers a ping again, and queries fall back to the primary when no replica
+// is healthy. Without replicas every query goes to the primary.
+type ReadPool struct {
+	primary  *pgxpool.Pool
+	replicas []*replica
+	next     atomic.Uint64
+	logger   *slog.Logger
+}
+
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// NewReadPool creates pools for the replica DSNs without connecting to them, so a replica that
+// is down at startup only means reads start on the primary.
+func NewReadPool(primary *pgxpool.Pool, replicaURLs []string, logger *slog.Logger) (*ReadPool, error) {
+	readPool := &ReadPool{primary: primary, logger: logger}
+	for i, replicaURL := range replicaURLs {
+		config, err := pgxpool.ParseConfig(replicaURL)
+		if err != nil {
+			readPool.Close()
+			return nil, fmt.Errorf("failed to parse database replica URL %d: %w", i+1, err)
+		}
+		config.ConnConfig.Tracer = newQueryTracer()
+		pool, err := pgxpool.NewWithConfig(context.Background(), config)
+		if err != nil {
+			readPool.Close()
+			return nil, fmt.Errorf("failed to create replica connection pool %d: %w", i+1, err)
+		}
+		r := &replica{name: "replica-" + strconv.Itoa(i+1), pool: pool}
+		r.healthy.Store(true)
+		readPool.replicas = append(readPool.replicas, r)
+	}
+	return readPool, nil
+}
+
+// Close closes the replica pools. The primary belongs to the caller.
+func (p *ReadPool) Close() {
+	if p == nil {
+		return
+	}
+	for _, r := range p.replicas {
+		r.pool.Close()
+	}
+}
+
+// Run checks replica health and records connection metrics for every pool until ctx is
+// cancelled.
+func (p *ReadPool) Run(ctx context.Context) {
+	if p == nil {
+		return
+	}
+
+	ticker := time.NewTicker(readPoolCheckInterval)
+	defer ticker.Stop()
+	for {
+		p.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ReadPool) check(ctx context.Context) {
+	meter := observability.MeterFromContext(ctx)
+	recordPool(meter, "primary", p.primary)
+	for _, r := range p.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, readPoolPingTimeout)
+		err := r.pool.Ping(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		healthy := err == nil
+		if r.healthy.Swap(healthy) != healthy {
+			if healthy {
+				p.logger.Info("database replica is back", "replica", r.name)
+			} else {
+				p.logger.Warn("database replica is unavailable; reading from other pools", "replica", r.name, "error", err)
+			}
+		}
+		recordPool(meter, r.name, r.pool)
+		meter.Gauge("db.replica.healthy", boolGauge(healthy), sentry.WithAttributes(attribute.String("pool", r.name)))
+	}
+}
+
+func recordPool(meter sentry.Meter, name string, pool *pgxpool.Pool) {
+	if pool == nil {
+		return
+	}
+	stat := pool.Stat()
+	for state, count := range map[string]int32{
+		"acquired":     stat.AcquiredConns(),
+		"idle":         stat.IdleConns(),
+		"constructing": stat.ConstructingConns(),
+	} {
+		meter.Gauge("db.pool.connections", float64(count), sentry.WithAttributes(
+			attribute.String("pool", name),
+			attribute.String("state", state),
+		))
+	}
+	meter.Gauge("db.pool.max_connections", float64(stat.MaxConns()), sentry.WithAttributes(attribute.String("pool", name)))
+	meter.Gauge("db.pool.acquire_wait", stat.AcquireDuration().Seconds(), sentry.WithUnit("second"), sentry.WithAttributes(attribute.String("pool", name)))
+}
+
+func boolGauge(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// pick returns the next healthy replica, or nil when none is.
+func (p *ReadPool) pick() *replica {
+	if p == nil || len(p.replicas) == 0 {
+		return nil
+	}
+	start := p.next.Add(1)
+	for i := range p.replicas {
+		r := p.replicas[(start+uint64(i))%uint64(len(p.replicas))]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// Query runs a read-only query on a replica, retrying it on the primary when the replica
+// cannot be reached.
+func (p *ReadPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	r := p.pick()
+	if r == nil {
+		if len(p.replicas) > 0 {
+			p.recordFallback(ctx, "no_healthy_replica")
+		}
+		return p.primary.Query(ctx, sql, args...)
+	}
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err == nil || !isConnectionError(ctx, err) {
+		return rows, err
+	}
+	if r.healthy.Swap(false) {
+		p.logger.Warn("database replica query failed; reading from other pools", "replica", r.name, "error", err)
+	}
+	p.recordFallback(ctx, "replica_error")
+	return p.primary.Query(ctx, sql, args...)
+}
+
+// QueryRow is Query for a single row. It returns pgx.ErrNoRows from Scan when there is none.
+func (p *ReadPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := p.Query(ctx, sql, args...)
+	return &readRow{rows: rows, err: err}
+}
+
+// Exec runs on the primary; writes never go to a replica.
+func (p *ReadPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return p.primary.Exec(ctx, sql, args...)
+}
+
+func (p *ReadPool) recordFallback(ctx context.Context, reason string) {
+	observability.MeterFromContext(ctx).Count("db.replica.fallback", 1, sentry.WithAttributes(
+		attribute.String("reason", reason),
+	))
+}
+
+// isConnectionError reports whether err means the pool could not reach its server, as opposed
+// to the server rejecting the query, which the primary would reject as well.
+func isConnectionError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	return !errors.As(err, &pgErr)
+}
+
+type readRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *readRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	r.rows.Close()
+	return r.rows.Err()
+}
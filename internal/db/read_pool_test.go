@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestReadPoolPick(t *testing.T) {
+	t.Parallel()
+
+	var none *ReadPool
+	if none.pick() != nil {
+		t.Fatal("nil read pool should pick no replica")
+	}
+
+	first, second := &replica{name: "replica-1"}, &replica{name: "replica-2"}
+	first.healthy.Store(true)
+	second.healthy.Store(true)
+	pool := &ReadPool{replicas: []*replica{first, second}, logger: slog.Default()}
+
+	seen := map[string]int{}
+	for range 4 {
+		seen[pool.pick().name]++
+	}
+	if seen["replica-1"] != 2 || seen["replica-2"] != 2 {
+		t.Fatalf("picks = %v, want replicas in turn", seen)
+	}
+
+	first.healthy.Store(false)
+	for range 3 {
+		if got := pool.pick(); got != second {
+			t.Fatalf("pick() = %v, want the healthy replica", got)
+		}
+	}
+
+	second.healthy.Store(false)
+	if got := pool.pick(); got != nil {
+		t.Fatalf("pick() = %v, want nil so reads go to the primary", got)
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	t.Parallel()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{name: "dial failure", ctx: context.Background(), err: errors.New("failed to connect to host"), want: true},
+		{name: "server rejected query", ctx: context.Background(), err: fmt.Errorf("query: %w", &pgconn.PgError{Code: "42P01"}), want: false},
+		{name: "caller cancelled", ctx: cancelled, err: context.Canceled, want: false},
+	}
+	for _, tt := range tests {
+		if got := isConnectionError(tt.ctx, tt.err); got != tt.want {
+			t.Fatalf("%s: isConnectionError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const reviewColumns = `r.order_id, r.shop_id, r.sku, r.comment_id, r.requested_at, r.rating, r.body, r.source, r.rated_at`
+
+func scanReview(row pgx.Row) (*OrderReview, error) {
+	var (
+		review  OrderReview
+		rating  pgtype.Int2
+		source  string
+		ratedAt pgtype.Timestamptz
+	)
+	if err := row.Scan(&review.OrderID, &review.ShopID, &review.SKU, &review.CommentID, &review.RequestedAt, &rating, &review.Body, &source, &ratedAt); err != nil {
+		return nil, err
+	}
+	review.Rating = int(rating.Int16)
+	review.Source = ReviewSource(source)
+	review.RatedAt = ratedAt.Time
+	return &review, nil
+}
+
+func collectReviews(rows pgx.Rows) ([]OrderReview, error) {
+	defer rows.Close()
+	reviews := []OrderReview{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	return reviews, rows.Err()
+}
+
+// CreateReviewRequest records the review invitation posted on a delivered order. closeAt is when
+// the order issue should be closed; a zero closeAt leaves it open.
+func (s *OrderStore) CreateReviewRequest(ctx context.Context, review *OrderReview, closeAt time.Time) error {
+	query := `
+		INSERT INTO order_reviews (order_id, shop_id, sku, comment_id, close_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_id) DO UPDATE
+		SET comment_id = EXCLUDED.comment_id, requested_at = NOW(), close_at = EXCLUDED.close_at
+	`
+	_, err := s.pool.Exec(ctx, query, review.OrderID, review.ShopID, review.SKU, review.CommentID, pgtype.Timestamptz{Time: closeAt, Valid: !closeAt.IsZero()})
+	return err
+}
+
+// GetReview returns the order's review, or pgx.ErrNoRows when it has none.
+func (s *OrderStore) GetReview(ctx context.Context, orderID uuid.UUID) (*OrderReview, error) {
+	return scanReview(s.pool.QueryRow(ctx, `SELECT `+reviewColumns+` FROM order_reviews r WHERE r.order_id = $1`, orderID))
+}
+
+// SaveRating stores the buyer's rating for an order. A reaction never replaces a rating the buyer
+// wrote in a comment, but a later comment replaces anything before it.
+func (s *OrderStore) SaveRating(ctx context.Context, review *OrderReview) error {
+	query := `
+		INSERT INTO order_reviews (order_id, shop_id, sku, rating, body, source, rated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (order_id) DO UPDATE
+		SET rating = EXCLUDED.rating, body = EXCLUDED.body, source = EXCLUDED.source, rated_at = NOW()
+		WHERE order_reviews.source <> 'comment' OR EXCLUDED.source = 'comment'
+	`
+	_, err := s.pool.Exec(ctx, query, review.OrderID, review.ShopID, review.SKU, int16(review.Rating), review.Body, string(review.Source))
+	return err
+}
+
+// ClaimReviewChecks marks up to limit unrated invitations posted after requestedAfter, and not
+// checked since checkedBefore, as checked and returns them, so their reactions can be read. An
+// empty region covers every shop.
+func (s *OrderStore) ClaimReviewChecks(ctx context.Context, region string, checkedBefore, requestedAfter time.Time, limit int) ([]OrderReview, error) {
+	query := `
+		WITH due AS (
+			SELECT r.order_id
+			FROM order_reviews r
+			JOIN shops s ON s.id = r.shop_id
+			WHERE r.rating IS NULL
+			  AND r.comment_id <> 0
+			  AND r.requested_at > $1
+			  AND (r.checked_at IS NULL OR r.checked_at < $2)
+			  AND s.disconnected_at IS NULL
+			  AND ($3 = '' OR s.region IN ('', $3))
+			ORDER BY r.checked_at NULLS FIRST
+			LIMIT $4
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		UPDATE order_reviews r
+		SET checked_at = NOW()
+		FROM due
+		WHERE r.order_id = due.order_id
+		RETURNING ` + reviewColumns
+	rows, err := s.pool.Query(ctx, query, requestedAfter, checkedBefore, region, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectReviews(rows)
+}
+
+// ClaimReviewClosures clears the close time of up to limit reviews due before dueBefore and
+// returns them, so each order issue is closed once. An empty region covers every shop.
+func (s *OrderStore) ClaimReviewClosures(ctx context.Context, region string, dueBefore time.Time, limit int) ([]OrderReview, error) {
+	query := `
+		WITH due AS (
+			SELECT r.order_id
+			FROM order_reviews r
+			JOIN shops s ON s.id = r.shop_id
+			WHERE r.close_at <= $1
+			  AND s.disconnected_at IS NULL
+			  AND ($2 = '' OR s.region IN ('', $2))
+			ORDER BY r.close_at
+			LIMIT $3
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		UPDATE order_reviews r
+		SET close_at = NULL
+		FROM due
+		WHERE r.order_id = due.order_id
+		RETURNING ` + reviewColumns
+	rows, err := s.pool.Query(ctx, query, dueBefore, region, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectReviews(rows)
+}
+
+// ListProductRatings returns the number of ratings and the average rating of each of the shop's
+// rated products.
+func (s *OrderStore) ListProductRatings(ctx context.Context, shopID uuid.UUID) ([]ProductRating, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT sku, COUNT(*), AVG(rating)::float8
+		FROM order_reviews
+		WHERE shop_id = $1 AND rating IS NOT NULL
+		GROUP BY sku
+		ORDER BY sku
+	`, shopID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[ProductRating])
+}
@@ -3,9 +3,13 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -17,6 +21,7 @@ type ShopStore struct {
 	pool    *pgxpool.Pool
 	queries *queries.Queries
 	crypto  crypto.Encryptor
+	reads   *ReadPool
 }
 
 func NewShopStore(pool *pgxpool.Pool, encryptor crypto.Encryptor) (*ShopStore, error) {
@@ -31,6 +36,23 @@ func NewShopStore(pool *pgxpool.Pool, encryptor crypto.Encryptor) (*ShopStore, e
 	}, nil
 }
 
+// WithReadPool returns a store that sends lag-tolerant reads, such as operator shop search and
+// the repositories to review, through reads.
+func (s *ShopStore) WithReadPool(reads *ReadPool) *ShopStore {
+	store := *s
+	store.reads = reads
+	return &store
+}
+
+// reader returns where lag-tolerant reads go: the read pool when there is one, otherwise the
+// primary.
+func (s *ShopStore) reader() queries.DBTX {
+	if s.reads != nil {
+		return s.reads
+	}
+	return s.pool
+}
+
 func (s *ShopStore) GetByID(ctx context.Context, id uuid.UUID) (*Shop, error) {
 	shop, err := s.queries.GetShopByID(ctx, id)
 	if err != nil {
@@ -128,6 +150,11 @@ func (s *ShopStore) decryptEmailConfig(data []byte) map[string]any {
 			decoded.APIKey = decrypted
 		}
 	}
+	if decoded.WebhookSecret != "" {
+		if decrypted, err := s.crypto.Decrypt(decoded.WebhookSecret); err == nil {
+			decoded.WebhookSecret = decrypted
+		}
+	}
 	if decoded.FromEmail == "" {
 		decoded.FromEmail = decoded.From
 	}
@@ -148,6 +175,13 @@ func (s *ShopStore) encryptEmailConfig(config map[string]any) (map[string]any, e
 		}
 		decoded.APIKey = ciphertext
 	}
+	if decoded.WebhookSecret != "" {
+		ciphertext, err := s.crypto.Encrypt(decoded.WebhookSecret)
+		if err != nil {
+			return nil, err
+		}
+		decoded.WebhookSecret = ciphertext
+	}
 
 	return decoded.toMap(), nil
 }
@@ -192,6 +226,12 @@ func (s *ShopStore) UpdateEmailConfig(ctx context.Context, shopID uuid.UUID, pro
 	})
 }
 
+// SetEmailVerified records whether the provider confirmed the shop's sending domain.
+func (s *ShopStore) SetEmailVerified(ctx context.Context, shopID uuid.UUID, verified bool) error {
+	_, err := s.pool.Exec(ctx, `UPDATE shops SET email_verified = $2, updated_at = NOW() WHERE id = $1`, shopID, verified)
+	return err
+}
+
 func (s *ShopStore) UpdateStripeConnectAccount(ctx context.Context, shopID uuid.UUID, connectAccountID string) error {
 	valid := connectAccountID != ""
 	return s.queries.UpdateShopStripeConnectAccount(ctx, queries.UpdateShopStripeConnectAccountParams{
@@ -201,10 +241,191 @@ func (s *ShopStore) UpdateStripeConnectAccount(ctx context.Context, shopID uuid.
 }
 
 func (s *ShopStore) UpdateStripeConnectDetails(ctx context.Context, shopID uuid.UUID, accountID string, detailsSubmitted, chargesEnabled, payoutsEnabled bool) error {
-	return s.queries.UpdateShopStripeConnectAccount(ctx, queries.UpdateShopStripeConnectAccountParams{
+	if err := s.queries.UpdateShopStripeConnectAccount(ctx, queries.UpdateShopStripeConnectAccountParams{
 		ID:                     shopID,
 		StripeConnectAccountID: pgtype.Text{String: accountID, Valid: true},
+	}); err != nil {
+		return err
+	}
+	_, err := s.RecordStripeCapabilities(ctx, shopID, StripeCapabilities{
+		DetailsSubmitted: detailsSubmitted,
+		ChargesEnabled:   chargesEnabled,
+		PayoutsEnabled:   payoutsEnabled,
+	})
+	return err
+}
+
+// RecordStripeCapabilities saves what Stripe reported for the shop's account and returns the
+// capabilities it replaced, so callers can tell when one was lost.
+func (s *ShopStore) RecordStripeCapabilities(ctx context.Context, shopID uuid.UUID, capabilities StripeCapabilities) (StripeCapabilities, error) {
+	row, err := s.queries.RecordShopStripeCapabilities(ctx, queries.RecordShopStripeCapabilitiesParams{
+		ID:                     shopID,
+		StripeDetailsSubmitted: capabilities.DetailsSubmitted,
+		StripeChargesEnabled:   capabilities.ChargesEnabled,
+		StripePayoutsEnabled:   capabilities.PayoutsEnabled,
 	})
+	if err != nil {
+		return StripeCapabilities{}, err
+	}
+	return stripeCapabilitiesFromRow(queries.GetShopStripeCapabilitiesRow(row)), nil
+}
+
+func (s *ShopStore) GetStripeCapabilities(ctx context.Context, shopID uuid.UUID) (StripeCapabilities, error) {
+	row, err := s.queries.GetShopStripeCapabilities(ctx, shopID)
+	if err != nil {
+		return StripeCapabilities{}, err
+	}
+	return stripeCapabilitiesFromRow(row), nil
+}
+
+func stripeCapabilitiesFromRow(row queries.GetShopStripeCapabilitiesRow) StripeCapabilities {
+	capabilities := StripeCapabilities{
+		DetailsSubmitted: row.StripeDetailsSubmitted,
+		ChargesEnabled:   row.StripeChargesEnabled,
+		PayoutsEnabled:   row.StripePayoutsEnabled,
+	}
+	if row.StripeCapabilitiesCheckedAt.Valid {
+		capabilities.CheckedAt = row.StripeCapabilitiesCheckedAt.Time.UTC()
+	}
+	return capabilities
+}
+
+// GetShopsByStripeAccount returns the connected shops paid out to a Stripe account.
+func (s *ShopStore) GetShopsByStripeAccount(ctx context.Context, accountID string) ([]*Shop, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id FROM shops WHERE stripe_connect_account_id = $1 AND disconnected_at IS NULL`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	shopIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+
+	shops := make([]*Shop, 0, len(shopIDs))
+	for _, shopID := range shopIDs {
+		shop, err := s.GetByID(ctx, shopID)
+		if err != nil {
+			return nil, err
+		}
+		shops = append(shops, shop)
+	}
+	return shops, nil
+}
+
+// GetConnectedByRepoFullName returns the connected shop for a repository such as "octo/shop",
+// ignoring case.
+func (s *ShopStore) GetConnectedByRepoFullName(ctx context.Context, repoFullName string) (*Shop, error) {
+	var shopID uuid.UUID
+	query := `SELECT id FROM shops WHERE LOWER(github_repo_full_name) = LOWER($1) AND disconnected_at IS NULL LIMIT 1`
+	if err := s.pool.QueryRow(ctx, query, repoFullName).Scan(&shopID); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, shopID)
+}
+
+// likePatternEscaper escapes the LIKE wildcards in user input.
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchShops finds shops whose repository name contains query, or whose ID, Stripe account, or
+// GitHub installation ID is exactly query. Disconnected shops are included, newest first.
+func (s *ShopStore) SearchShops(ctx context.Context, query string, limit int) ([]*Shop, error) {
+	query = strings.TrimSpace(query)
+	pattern := "%" + likePatternEscaper.Replace(strings.ToLower(query)) + "%"
+	rows, err := s.reader().Query(ctx, `
+		SELECT id FROM shops
+		WHERE LOWER(github_repo_full_name) LIKE $1
+		   OR id::text = $2
+		   OR stripe_connect_account_id = $2
+		   OR github_installation_id::text = $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, pattern, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	shopIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+
+	shops := make([]*Shop, 0, len(shopIDs))
+	for _, shopID := range shopIDs {
+		shop, err := s.GetByID(ctx, shopID)
+		if err != nil {
+			return nil, err
+		}
+		shops = append(shops, shop)
+	}
+	return shops, nil
+}
+
+// GetPaymentSettings returns the shop's payment processor with its credentials decrypted.
+func (s *ShopStore) GetPaymentSettings(ctx context.Context, shopID uuid.UUID) (PaymentSettings, error) {
+	var (
+		settings PaymentSettings
+		config   []byte
+	)
+	query := `SELECT payment_processor, payment_config, stripe_test_mode FROM shops WHERE id = $1`
+	if err := s.pool.QueryRow(ctx, query, shopID).Scan(&settings.Processor, &config, &settings.StripeTestMode); err != nil {
+		return PaymentSettings{}, err
+	}
+	if len(config) == 0 {
+		return settings, nil
+	}
+	if err := json.Unmarshal(config, &settings.LemonSqueezy); err != nil {
+		return PaymentSettings{}, fmt.Errorf("failed to decode payment config: %w", err)
+	}
+	for _, secret := range []*string{&settings.LemonSqueezy.APIKey, &settings.LemonSqueezy.WebhookSecret} {
+		if *secret == "" {
+			continue
+		}
+		decrypted, err := s.crypto.Decrypt(*secret)
+		if err != nil {
+			return PaymentSettings{}, fmt.Errorf("failed to decrypt payment config: %w", err)
+		}
+		*secret = decrypted
+	}
+	return settings, nil
+}
+
+// UpdatePaymentSettings switches the shop's payment processor. Stripe shops keep no credentials
+// here, so their config is cleared. Test mode is set with SetStripeTestMode and is turned off
+// when the shop leaves Stripe.
+func (s *ShopStore) UpdatePaymentSettings(ctx context.Context, shopID uuid.UUID, settings PaymentSettings) error {
+	config := LemonSqueezySettings{}
+	if settings.Processor == PaymentProcessorLemonSqueezy {
+		config = settings.LemonSqueezy
+		for _, secret := range []*string{&config.APIKey, &config.WebhookSecret} {
+			if *secret == "" {
+				continue
+			}
+			ciphertext, err := s.crypto.Encrypt(*secret)
+			if err != nil {
+				return err
+			}
+			*secret = ciphertext
+		}
+	}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE shops
+		SET payment_processor = $1, payment_config = $2,
+		    stripe_test_mode = stripe_test_mode AND $1 = 'stripe', updated_at = NOW()
+		WHERE id = $3
+	`
+	_, err = s.pool.Exec(ctx, query, string(settings.Processor), configJSON, shopID)
+	return err
+}
+
+// SetStripeTestMode turns test mode on or off for the shop's new orders.
+func (s *ShopStore) SetStripeTestMode(ctx context.Context, shopID uuid.UUID, enabled bool) error {
+	query := `UPDATE shops SET stripe_test_mode = $2, updated_at = NOW() WHERE id = $1`
+	_, err := s.pool.Exec(ctx, query, shopID, enabled)
+	return err
 }
 
 func (s *ShopStore) MarkOnboarded(ctx context.Context, shopID uuid.UUID) error {
@@ -225,18 +446,189 @@ func (s *ShopStore) DisconnectShop(ctx context.Context, installationID int64, re
 	})
 }
 
-func (s *ShopStore) SuspendShop(ctx context.Context, installationID int64, repoID int64) error {
-	return s.queries.DisconnectShop(ctx, queries.DisconnectShopParams{
-		GithubInstallationID: installationID,
-		GithubRepoID:         repoID,
-	})
+// GetSuspension returns the shop's moderation state. Shops that were never suspended return a
+// zero ShopSuspension.
+func (s *ShopStore) GetSuspension(ctx context.Context, shopID uuid.UUID) (*ShopSuspension, error) {
+	var (
+		suspension  ShopSuspension
+		suspendedAt pgtype.Timestamptz
+		appealedAt  pgtype.Timestamptz
+	)
+	query := `
+		SELECT suspended_at, suspended_by, suspension_reason, suspension_notes, suspension_appeal, suspension_appealed_at
+		FROM shops WHERE id = $1
+	`
+	err := s.pool.QueryRow(ctx, query, shopID).Scan(&suspendedAt, &suspension.SuspendedBy, &suspension.Reason, &suspension.Notes, &suspension.Appeal, &appealedAt)
+	if err != nil {
+		return nil, err
+	}
+	if suspendedAt.Valid {
+		suspension.SuspendedAt = suspendedAt.Time.UTC()
+	}
+	if appealedAt.Valid {
+		suspension.AppealedAt = appealedAt.Time.UTC()
+	}
+	return &suspension, nil
 }
 
-func (s *ShopStore) UnsuspendShop(ctx context.Context, installationID int64, repoID int64) error {
-	return s.queries.ReconnectShop(ctx, queries.ReconnectShopParams{
-		GithubInstallationID: installationID,
-		GithubRepoID:         repoID,
-	})
+// SuspendShop suspends the shop for a terms violation. Any earlier appeal is cleared so the
+// owner can appeal this suspension.
+func (s *ShopStore) SuspendShop(ctx context.Context, shopID uuid.UUID, operator, reason string) error {
+	query := `
+		UPDATE shops
+		SET suspended_at = NOW(), suspended_by = $2, suspension_reason = $3,
+		    suspension_appeal = '', suspension_appealed_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+	return s.execAffectingShop(ctx, query, shopID, operator, reason)
+}
+
+// UnsuspendShop lifts the suspension. The reason and notes are kept as history.
+func (s *ShopStore) UnsuspendShop(ctx context.Context, shopID uuid.UUID) error {
+	query := `UPDATE shops SET suspended_at = NULL, updated_at = NOW() WHERE id = $1`
+	return s.execAffectingShop(ctx, query, shopID)
+}
+
+// UpdateSuspensionNotes replaces the operators' internal notes on the suspension.
+func (s *ShopStore) UpdateSuspensionNotes(ctx context.Context, shopID uuid.UUID, notes string) error {
+	query := `UPDATE shops SET suspension_notes = $2, updated_at = NOW() WHERE id = $1`
+	return s.execAffectingShop(ctx, query, shopID, notes)
+}
+
+// RecordSuspensionAppeal saves the owner's appeal. It fails with pgx.ErrNoRows unless the shop
+// is suspended.
+func (s *ShopStore) RecordSuspensionAppeal(ctx context.Context, shopID uuid.UUID, appeal string) error {
+	query := `
+		UPDATE shops SET suspension_appeal = $2, suspension_appealed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND suspended_at IS NOT NULL
+	`
+	return s.execAffectingShop(ctx, query, shopID, appeal)
+}
+
+// GetSetupPullRequest returns the pull request GitShop opened to bootstrap the shop repository.
+// Shops that never opened one return a zero SetupPullRequest.
+func (s *ShopStore) GetSetupPullRequest(ctx context.Context, shopID uuid.UUID) (*SetupPullRequest, error) {
+	var pr SetupPullRequest
+	query := `SELECT setup_pr_number, setup_pr_url FROM shops WHERE id = $1`
+	if err := s.pool.QueryRow(ctx, query, shopID).Scan(&pr.Number, &pr.URL); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// SetSetupPullRequest records the bootstrap pull request so the setup page can follow it.
+func (s *ShopStore) SetSetupPullRequest(ctx context.Context, shopID uuid.UUID, pr SetupPullRequest) error {
+	query := `UPDATE shops SET setup_pr_number = $2, setup_pr_url = $3, updated_at = NOW() WHERE id = $1`
+	return s.execAffectingShop(ctx, query, shopID, pr.Number, pr.URL)
+}
+
+// GetConfigIssueNumber returns the tracking issue open for the shop's broken configuration, or 0.
+func (s *ShopStore) GetConfigIssueNumber(ctx context.Context, shopID uuid.UUID) (int, error) {
+	var number int
+	query := `SELECT config_issue_number FROM shops WHERE id = $1`
+	if err := s.pool.QueryRow(ctx, query, shopID).Scan(&number); err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+// SetConfigIssueNumber records the configuration tracking issue; 0 clears it once closed.
+func (s *ShopStore) SetConfigIssueNumber(ctx context.Context, shopID uuid.UUID, number int) error {
+	query := `UPDATE shops SET config_issue_number = $2, updated_at = NOW() WHERE id = $1`
+	return s.execAffectingShop(ctx, query, shopID, number)
+}
+
+// ListConnectedShops returns connected shops homed in region, ordered by ID and starting after
+// afterID, so callers can page through every shop. An empty region covers every shop.
+func (s *ShopStore) ListConnectedShops(ctx context.Context, region string, afterID uuid.UUID, limit int) ([]*Shop, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id FROM shops
+		WHERE disconnected_at IS NULL
+		  AND id > $1
+		  AND ($2 = '' OR region IN ('', $2))
+		ORDER BY id
+		LIMIT $3
+	`, afterID, region, limit)
+	if err != nil {
+		return nil, err
+	}
+	shopIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, err
+	}
+
+	shops := make([]*Shop, 0, len(shopIDs))
+	for _, shopID := range shopIDs {
+		shop, err := s.GetByID(ctx, shopID)
+		if err != nil {
+			return nil, err
+		}
+		shops = append(shops, shop)
+	}
+	return shops, nil
+}
+
+// MarkRepoOrphaned flags the shop for review because its repository could not be resolved. The
+// time it was first flagged is kept while it stays orphaned.
+func (s *ShopStore) MarkRepoOrphaned(ctx context.Context, shopID uuid.UUID, reason RepoOrphanReason) error {
+	query := `
+		UPDATE shops
+		SET repo_orphaned_at = COALESCE(repo_orphaned_at, NOW()), repo_orphan_reason = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+	return s.execAffectingShop(ctx, query, shopID, string(reason))
+}
+
+// ClearRepoOrphaned removes the review flag once the repository resolves again. Shops that were
+// not flagged are left alone.
+func (s *ShopStore) ClearRepoOrphaned(ctx context.Context, shopID uuid.UUID) error {
+	query := `
+		UPDATE shops SET repo_orphaned_at = NULL, repo_orphan_reason = '', updated_at = NOW()
+		WHERE id = $1 AND repo_orphaned_at IS NOT NULL
+	`
+	_, err := s.pool.Exec(ctx, query, shopID)
+	return err
+}
+
+// ListOrphanedShops returns connected shops flagged for review, longest-orphaned first.
+func (s *ShopStore) ListOrphanedShops(ctx context.Context, limit int) ([]OrphanedShop, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT id, github_installation_id, github_repo_full_name, repo_orphaned_at, repo_orphan_reason
+		FROM shops
+		WHERE repo_orphaned_at IS NOT NULL AND disconnected_at IS NULL
+		ORDER BY repo_orphaned_at
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shops := []OrphanedShop{}
+	for rows.Next() {
+		var (
+			shop   OrphanedShop
+			reason string
+		)
+		if err := rows.Scan(&shop.ShopID, &shop.GitHubInstallationID, &shop.GitHubRepoFullName, &shop.OrphanedAt, &reason); err != nil {
+			return nil, err
+		}
+		shop.OrphanedAt = shop.OrphanedAt.UTC()
+		shop.Reason = RepoOrphanReason(reason)
+		shops = append(shops, shop)
+	}
+	return shops, rows.Err()
+}
+
+func (s *ShopStore) execAffectingShop(ctx context.Context, query string, shopID uuid.UUID, args ...any) error {
+	tag, err := s.pool.Exec(ctx, query, append([]any{shopID}, args...)...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
 }
 
 func (s *ShopStore) GetConnectedShopsByInstallationID(ctx context.Context, installationID int64) ([]*Shop, error) {
@@ -275,12 +667,144 @@ func (s *ShopStore) GetFirstConfiguredShop(ctx context.Context, installationID i
 	return s.convertShop(queries.GetShopByIDRow(shop)), nil
 }
 
+// RegionForRepo returns the home region of the shop for a repository, or "" when the shop is not
+// pinned to a region or does not exist.
+func (s *ShopStore) RegionForRepo(ctx context.Context, repoID int64) (string, error) {
+	return s.region(ctx, `SELECT region FROM shops WHERE github_repo_id = $1 LIMIT 1`, repoID)
+}
+
+// RegionForStripeAccount returns the home region of the shop connected to a Stripe account.
+func (s *ShopStore) RegionForStripeAccount(ctx context.Context, accountID string) (string, error) {
+	return s.region(ctx, `SELECT region FROM shops WHERE stripe_connect_account_id = $1 LIMIT 1`, accountID)
+}
+
+func (s *ShopStore) GetProcessingRegion(ctx context.Context, shopID uuid.UUID) (*ProcessingRegion, error) {
+	var settings ProcessingRegion
+	query := `SELECT region, retain_email_log FROM shops WHERE id = $1`
+	if err := s.pool.QueryRow(ctx, query, shopID).Scan(&settings.Region, &settings.RetainEmailLog); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateProcessingRegion saves the shop's home region and email log setting. Turning off the
+// email log also clears recipients already recorded for the shop's orders.
+func (s *ShopStore) UpdateProcessingRegion(ctx context.Context, shopID uuid.UUID, settings ProcessingRegion) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	query := `UPDATE shops SET region = $1, retain_email_log = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := tx.Exec(ctx, query, settings.Region, settings.RetainEmailLog, shopID); err != nil {
+		return err
+	}
+	if !settings.RetainEmailLog {
+		redact := `
+			UPDATE order_emails SET recipient = '', recipient_hash = NULL
+			WHERE recipient <> '' AND order_id IN (SELECT id FROM orders WHERE shop_id = $1)
+		`
+		if _, err := tx.Exec(ctx, redact, shopID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// DeleteShopData removes the shop's orders, customers, email log, and outbound webhooks, clears
+// its email and Stripe settings, and disconnects it. The shop row is kept, marked with
+// data_deleted_at, so the repository can be reconnected later as an empty shop.
+func (s *ShopStore) DeleteShopData(ctx context.Context, shopID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	statements := []string{
+		`DELETE FROM webhook_deliveries WHERE shop_id = $1`,
+		`DELETE FROM shop_webhooks WHERE shop_id = $1`,
+		`DELETE FROM customers WHERE shop_id = $1`,
+		`DELETE FROM api_keys WHERE shop_id = $1`,
+		`DELETE FROM orders WHERE shop_id = $1`,
+		`UPDATE shops
+		 SET owner_email = '',
+		     email_config = '{}',
+		     email_verified = FALSE,
+		     payment_config = '{}',
+		     stripe_connect_account_id = NULL,
+		     onboarded_at = NULL,
+		     disconnected_at = COALESCE(disconnected_at, NOW()),
+		     data_deleted_at = NOW(),
+		     updated_at = NOW()
+		 WHERE id = $1`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement, shopID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ClaimConfigUpgradeSuggestion records that an upgrade to version is being suggested for the
+// shop's gitshop.yaml. It returns false when one was already suggested, so each upgrade is offered
+// once.
+func (s *ShopStore) ClaimConfigUpgradeSuggestion(ctx context.Context, shopID uuid.UUID, version int) (bool, error) {
+	query := `
+		UPDATE shops SET config_upgrade_suggested_version = $1
+		WHERE id = $2 AND config_upgrade_suggested_version < $1
+	`
+	result, err := s.pool.Exec(ctx, query, version, shopID)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ClaimFirstPaidOrder records orderID as the shop's first paid order. It returns false when the
+// shop already had one, so the first-order flow runs once per shop.
+func (s *ShopStore) ClaimFirstPaidOrder(ctx context.Context, shopID, orderID uuid.UUID) (bool, error) {
+	query := `UPDATE shops SET first_paid_order_id = $1 WHERE id = $2 AND first_paid_order_id IS NULL`
+	result, err := s.pool.Exec(ctx, query, orderID, shopID)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// GetFirstPaidOrderID returns the shop's first paid order, or uuid.Nil before its first sale.
+func (s *ShopStore) GetFirstPaidOrderID(ctx context.Context, shopID uuid.UUID) (uuid.UUID, error) {
+	var orderID pgtype.UUID
+	if err := s.pool.QueryRow(ctx, `SELECT first_paid_order_id FROM shops WHERE id = $1`, shopID).Scan(&orderID); err != nil {
+		return uuid.Nil, err
+	}
+	if !orderID.Valid {
+		return uuid.Nil, nil
+	}
+	return uuid.UUID(orderID.Bytes), nil
+}
+
+func (s *ShopStore) region(ctx context.Context, query string, arg any) (string, error) {
+	var region string
+	err := s.pool.QueryRow(ctx, query, arg).Scan(&region)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return region, nil
+}
+
 type emailConfigData struct {
-	APIKey    string `json:"api_key"`
-	FromEmail string `json:"from_email"`
-	From      string `json:"from"`
-	Domain    string `json:"domain"`
-	BaseURL   string `json:"base_url"`
+	APIKey        string `json:"api_key"`
+	FromEmail     string `json:"from_email"`
+	From          string `json:"from"`
+	Domain        string `json:"domain"`
+	BaseURL       string `json:"base_url"`
+	WebhookSecret string `json:"webhook_secret"`
 }
 
 func decodeEmailConfig(data []byte) (emailConfigData, error) {
@@ -326,5 +850,64 @@ func (c emailConfigData) toMap() map[string]any {
 	if c.BaseURL != "" {
 		out["base_url"] = c.BaseURL
 	}
+	if c.WebhookSecret != "" {
+		out["webhook_secret"] = c.WebhookSecret
+	}
 	return out
 }
+
+// ShippingReminder is a shop claimed for its daily overdue-shipment check, with the internal
+// issue it has open for overdue orders, or 0.
+type ShippingReminder struct {
+	ShopID             uuid.UUID
+	OverdueIssueNumber int
+}
+
+// ClaimShippingReminders marks up to limit connected shops as checked and returns them. A shop is
+// due when it was last checked before checkedBefore and has a paid order waiting since before
+// paidBefore, or still has an overdue issue open. Claiming first keeps two instances from
+// reminding the same seller. An empty region covers every shop.
+func (s *ShopStore) ClaimShippingReminders(ctx context.Context, region string, checkedBefore, paidBefore time.Time, limit int) ([]ShippingReminder, error) {
+	query := `
+		WITH due AS (
+			SELECT s.id
+			FROM shops s
+			WHERE s.disconnected_at IS NULL
+			  AND ($1 = '' OR s.region IN ('', $1))
+			  AND (s.overdue_reminded_at IS NULL OR s.overdue_reminded_at < $2)
+			  AND (s.overdue_issue_number <> 0 OR EXISTS (
+				SELECT 1 FROM orders o
+				WHERE o.shop_id = s.id AND o.status = 'paid' AND o.paid_at < $3
+			  ))
+			ORDER BY s.id
+			LIMIT $4
+			FOR UPDATE OF s SKIP LOCKED
+		)
+		UPDATE shops
+		SET overdue_reminded_at = NOW()
+		FROM due
+		WHERE shops.id = due.id
+		RETURNING shops.id, shops.overdue_issue_number
+	`
+	rows, err := s.pool.Query(ctx, query, region, checkedBefore, paidBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[ShippingReminder])
+}
+
+// RecordShippingReminder saves the shipping window read from gitshop.yaml, which the dashboard
+// uses to flag overdue orders, and the overdue issue left open; 0 clears it.
+func (s *ShopStore) RecordShippingReminder(ctx context.Context, shopID uuid.UUID, shipWithinDays, overdueIssueNumber int) error {
+	query := `UPDATE shops SET ship_within_days = $2, overdue_issue_number = $3, updated_at = NOW() WHERE id = $1`
+	return s.execAffectingShop(ctx, query, shopID, shipWithinDays, overdueIssueNumber)
+}
+
+// GetShipWithinDays returns how many days a paid order may wait to ship, 0 when reminders are off.
+func (s *ShopStore) GetShipWithinDays(ctx context.Context, shopID uuid.UUID) (int, error) {
+	var days int
+	if err := s.pool.QueryRow(ctx, `SELECT ship_within_days FROM shops WHERE id = $1`, shopID).Scan(&days); err != nil {
+		return 0, err
+	}
+	return days, nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// orderEventsSQL lists the event log entries of orders in shops homed in region $3, or every shop
+// when empty, that were recorded in [$1, $2).
+const orderEventsSQL = `
+	SELECT o.id, o.shop_id, e.kind, e.detail, e.created_at
+	FROM order_events e
+	JOIN orders o ON o.id = e.order_id
+	JOIN shops s ON s.id = o.shop_id
+	WHERE e.created_at >= $1 AND e.created_at < $2 AND ($3 = '' OR s.region IN ('', $3))
+`
+
+// ListWarehouseOrderEvents returns the events that happened to orders in [from, to), oldest
+// first, for shops homed in region. An empty region covers every shop. It reads from the replica
+// when there is one.
+func (s *OrderStore) ListWarehouseOrderEvents(ctx context.Context, region string, from, to time.Time) ([]WarehouseOrderEvent, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT order_id, shop_id, event, detail, occurred_at
+		FROM (`+orderEventsSQL+`) AS events(order_id, shop_id, event, detail, occurred_at)
+		ORDER BY occurred_at, order_id
+	`, from, to, region)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[WarehouseOrderEvent])
+}
+
+// ListWarehouseOrders returns the orders with an event in [from, to), oldest first, as they are
+// now, for shops homed in region. An empty region covers every shop. It reads from the replica
+// when there is one.
+func (s *OrderStore) ListWarehouseOrders(ctx context.Context, region string, from, to time.Time) ([]WarehouseOrder, error) {
+	rows, err := s.reader().Query(ctx, `
+		SELECT id, shop_id, order_number, status, sku, category, campaign, subtotal_cents, shipping_cents,
+		       COALESCE(tax_cents, 0), total_cents, display_currency, test_mode, cancellation_reason,
+		       created_at, paid_at, shipped_at, delivered_at, refunded_at, returned_at, cancelled_at
+		FROM orders
+		WHERE id IN (
+			SELECT order_id FROM (`+orderEventsSQL+`) AS events(order_id, shop_id, event, detail, occurred_at)
+		)
+		ORDER BY created_at, id
+	`, from, to, region)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[WarehouseOrder])
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WebhookEventStore keeps recently received GitHub and Stripe webhooks so operators can replay
+// them after an outage.
+type WebhookEventStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookEventStore(pool *pgxpool.Pool) *WebhookEventStore {
+	return &WebhookEventStore{pool: pool}
+}
+
+// Record stores a verified webhook. Redeliveries of a stored delivery are ignored.
+func (s *WebhookEventStore) Record(ctx context.Context, provider, deliveryID, eventType string, payload []byte) error {
+	query := `
+		INSERT INTO webhook_events (provider, delivery_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, delivery_id) DO NOTHING
+	`
+	_, err := s.pool.Exec(ctx, query, provider, deliveryID, eventType, payload)
+	return err
+}
+
+// SetStatus records what became of a delivery. An empty lastError clears the previous one.
+func (s *WebhookEventStore) SetStatus(ctx context.Context, provider, deliveryID string, status ReceivedWebhookStatus, lastError string) error {
+	query := `
+		UPDATE webhook_events
+		SET status = $1,
+		    last_error = NULLIF($2, ''),
+		    processed_at = CASE WHEN $1 = $3 THEN NOW() ELSE processed_at END
+		WHERE provider = $4 AND delivery_id = $5
+	`
+	_, err := s.pool.Exec(ctx, query, string(status), lastError, string(ReceivedWebhookProcessed), provider, deliveryID)
+	return err
+}
+
+// MarkReplayed records the outcome of an operator replay.
+func (s *WebhookEventStore) MarkReplayed(ctx context.Context, id uuid.UUID, status ReceivedWebhookStatus, lastError string) error {
+	query := `
+		UPDATE webhook_events
+		SET status = $1,
+		    last_error = NULLIF($2, ''),
+		    processed_at = CASE WHEN $1 = $3 THEN NOW() ELSE processed_at END,
+		    replay_count = replay_count + 1,
+		    last_replayed_at = NOW()
+		WHERE id = $4
+	`
+	_, err := s.pool.Exec(ctx, query, string(status), lastError, string(ReceivedWebhookProcessed), id)
+	return err
+}
+
+// Get returns a stored webhook, or nil when it does not exist or has expired.
+func (s *WebhookEventStore) Get(ctx context.Context, id uuid.UUID) (*ReceivedWebhook, error) {
+	query := `
+		SELECT id, provider, delivery_id, event_type, payload, status, last_error, replay_count,
+		       received_at, processed_at, last_replayed_at
+		FROM webhook_events
+		WHERE id = $1
+	`
+	webhook, err := scanReceivedWebhook(s.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return webhook, err
+}
+
+// ListRecent returns the most recently received webhooks, newest first.
+func (s *WebhookEventStore) ListRecent(ctx context.Context, limit int) ([]*ReceivedWebhook, error) {
+	query := `
+		SELECT id, provider, delivery_id, event_type, payload, status, last_error, replay_count,
+		       received_at, processed_at, last_replayed_at
+		FROM webhook_events
+		ORDER BY received_at DESC
+		LIMIT $1
+	`
+	rows, err := s.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*ReceivedWebhook{}
+	for rows.Next() {
+		webhook, err := scanReceivedWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// DeleteReceivedBefore removes webhooks received before cutoff and returns how many were removed.
+func (s *WebhookEventStore) DeleteReceivedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := s.pool.Exec(ctx, `DELETE FROM webhook_events WHERE received_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// RecordCheckpoint marks deliveryID as the endpoint's latest successfully processed delivery.
+func (s *WebhookEventStore) RecordCheckpoint(ctx context.Context, provider, endpoint, deliveryID string) error {
+	query := `
+		INSERT INTO webhook_checkpoints (provider, endpoint, delivery_id, event_type, processed_at)
+		VALUES ($1, $2, $3, COALESCE((SELECT event_type FROM webhook_events WHERE provider = $1 AND delivery_id = $3), ''), NOW())
+		ON CONFLICT (provider, endpoint) DO UPDATE
+		SET delivery_id = EXCLUDED.delivery_id,
+		    event_type = EXCLUDED.event_type,
+		    processed_at = EXCLUDED.processed_at
+	`
+	_, err := s.pool.Exec(ctx, query, provider, endpoint, deliveryID)
+	return err
+}
+
+// ListCheckpoints returns every endpoint's checkpoint, ordered by provider and endpoint.
+func (s *WebhookEventStore) ListCheckpoints(ctx context.Context) ([]*WebhookCheckpoint, error) {
+	query := `
+		SELECT provider, endpoint, delivery_id, event_type, processed_at
+		FROM webhook_checkpoints
+		ORDER BY provider, endpoint
+	`
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkpoints := []*WebhookCheckpoint{}
+	for rows.Next() {
+		var (
+			checkpoint  WebhookCheckpoint
+			processedAt pgtype.Timestamptz
+		)
+		if err := rows.Scan(&checkpoint.Provider, &checkpoint.Endpoint, &checkpoint.DeliveryID, &checkpoint.EventType, &processedAt); err != nil {
+			return nil, err
+		}
+		checkpoint.ProcessedAt = processedAt.Time.UTC()
+		checkpoints = append(checkpoints, &checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return checkpoints, nil
+}
+
+// ProcessedDeliveryIDs returns which of deliveryIDs were stored and processed.
+func (s *WebhookEventStore) ProcessedDeliveryIDs(ctx context.Context, provider string, deliveryIDs []string) (map[string]bool, error) {
+	query := `
+		SELECT delivery_id
+		FROM webhook_events
+		WHERE provider = $1 AND delivery_id = ANY($2) AND status = $3
+	`
+	rows, err := s.pool.Query(ctx, query, provider, deliveryIDs, string(ReceivedWebhookProcessed))
+	if err != nil {
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	processed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		processed[id] = true
+	}
+	return processed, nil
+}
+
+func scanReceivedWebhook(row pgx.Row) (*ReceivedWebhook, error) {
+	var (
+		webhook        ReceivedWebhook
+		status         string
+		lastError      pgtype.Text
+		replayCount    int32
+		receivedAt     pgtype.Timestamptz
+		processedAt    pgtype.Timestamptz
+		lastReplayedAt pgtype.Timestamptz
+	)
+	if err := row.Scan(&webhook.ID, &webhook.Provider, &webhook.DeliveryID, &webhook.EventType, &webhook.Payload, &status,
+		&lastError, &replayCount, &receivedAt, &processedAt, &lastReplayedAt); err != nil {
+		return nil, err
+	}
+	webhook.Status = ReceivedWebhookStatus(status)
+	webhook.LastError = lastError.String
+	webhook.ReplayCount = int(replayCount)
+	webhook.ReceivedAt = receivedAt.Time.UTC()
+	if processedAt.Valid {
+		webhook.ProcessedAt = processedAt.Time.UTC()
+	}
+	if lastReplayedAt.Valid {
+		webhook.LastReplayedAt = lastReplayedAt.Time.UTC()
+	}
+	return &webhook, nil
+}
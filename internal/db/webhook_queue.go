@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	queuedWebhookPending   = "pending"
+	queuedWebhookProcessed = "processed"
+	queuedWebhookFailed    = "failed"
+)
+
+type WebhookQueueStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookQueueStore(pool *pgxpool.Pool) *WebhookQueueStore {
+	return &WebhookQueueStore{pool: pool}
+}
+
+// Enqueue stores a webhook for later processing by a worker in region, or any worker when region
+// is empty. A delivery that is already queued is ignored.
+func (s *WebhookQueueStore) Enqueue(ctx context.Context, region, provider, deliveryID, eventType string, payload []byte) error {
+	query := `
+		INSERT INTO inbound_webhook_queue (region, provider, delivery_id, event_type, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (provider, delivery_id) DO NOTHING
+	`
+	_, err := s.pool.Exec(ctx, query, region, provider, deliveryID, eventType, payload)
+	return err
+}
+
+// Backlog returns how many queued webhooks the region's workers can claim and when the oldest
+// one was received.
+func (s *WebhookQueueStore) Backlog(ctx context.Context, region string) (int, time.Time, error) {
+	query := `
+		SELECT COUNT(*), MIN(received_at) FROM inbound_webhook_queue
+		WHERE status = $1 AND ($2 = '' OR region IN ('', $2))
+	`
+
+	var (
+		count  int64
+		oldest pgtype.Timestamptz
+	)
+	if err := s.pool.QueryRow(ctx, query, queuedWebhookPending, region).Scan(&count, &oldest); err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(count), oldest.Time.UTC(), nil
+}
+
+// ClaimDue locks due webhooks that region's workers may process and pushes their next attempt out
+// by lease so other instances skip them while they are processed. An empty region claims all.
+func (s *WebhookQueueStore) ClaimDue(ctx context.Context, region string, limit int, lease time.Duration) ([]*QueuedWebhook, error) {
+	query := `
+		UPDATE inbound_webhook_queue
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM inbound_webhook_queue
+			WHERE status = $3 AND next_attempt_at <= NOW() AND ($4 = '' OR region IN ('', $4))
+			ORDER BY received_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, provider, delivery_id, event_type, payload, attempts, received_at
+	`
+	rows, err := s.pool.Query(ctx, query, limit, lease.Seconds(), queuedWebhookPending, region)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*QueuedWebhook{}
+	for rows.Next() {
+		var (
+			webhook    QueuedWebhook
+			attempts   int32
+			receivedAt pgtype.Timestamptz
+		)
+		if err := rows.Scan(&webhook.ID, &webhook.Provider, &webhook.DeliveryID, &webhook.EventType, &webhook.Payload, &attempts, &receivedAt); err != nil {
+			return nil, err
+		}
+		webhook.Attempts = int(attempts)
+		webhook.ReceivedAt = receivedAt.Time.UTC()
+		webhooks = append(webhooks, &webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s *WebhookQueueStore) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE inbound_webhook_queue
+		SET status = $1, attempts = attempts + 1, last_error = NULL, processed_at = NOW()
+		WHERE id = $2
+	`
+	_, err := s.pool.Exec(ctx, query, queuedWebhookProcessed, id)
+	return err
+}
+
+func (s *WebhookQueueStore) MarkRetry(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE inbound_webhook_queue
+		SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+		WHERE id = $3
+	`
+	_, err := s.pool.Exec(ctx, query, lastError, nextAttemptAt, id)
+	return err
+}
+
+func (s *WebhookQueueStore) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `
+		UPDATE inbound_webhook_queue
+		SET status = $1, attempts = attempts + 1, last_error = $2
+		WHERE id = $3
+	`
+	_, err := s.pool.Exec(ctx, query, queuedWebhookFailed, lastError, id)
+	return err
+}
@@ -0,0 +1,271 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gitshopapp/gitshop/internal/crypto"
+)
+
+type WebhookStore struct {
+	pool   *pgxpool.Pool
+	crypto crypto.Encryptor
+}
+
+func NewWebhookStore(pool *pgxpool.Pool, encryptor crypto.Encryptor) (*WebhookStore, error) {
+	if encryptor == nil {
+		return nil, fmt.Errorf("encryptor is required")
+	}
+
+	return &WebhookStore{
+		pool:   pool,
+		crypto: encryptor,
+	}, nil
+}
+
+// WebhookDispatch is a claimed delivery together with the signing secret of its endpoint.
+type WebhookDispatch struct {
+	Delivery *WebhookDelivery
+	Secret   string
+}
+
+// GetEndpoint returns the shop's webhook endpoint, or nil when none is configured.
+func (s *WebhookStore) GetEndpoint(ctx context.Context, shopID uuid.UUID) (*WebhookEndpoint, error) {
+	query := `SELECT shop_id, url, secret, created_at, updated_at FROM shop_webhooks WHERE shop_id = $1`
+
+	var (
+		endpoint  WebhookEndpoint
+		secret    string
+		createdAt pgtype.Timestamptz
+		updatedAt pgtype.Timestamptz
+	)
+	err := s.pool.QueryRow(ctx, query, shopID).Scan(&endpoint.ShopID, &endpoint.URL, &secret, &createdAt, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Secret, err = s.crypto.Decrypt(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	endpoint.CreatedAt = createdAt.Time.UTC()
+	endpoint.UpdatedAt = updatedAt.Time.UTC()
+	return &endpoint, nil
+}
+
+func (s *WebhookStore) UpsertEndpoint(ctx context.Context, shopID uuid.UUID, url, secret string) error {
+	encrypted, err := s.crypto.Encrypt(secret)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+
+	query := `
+		INSERT INTO shop_webhooks (shop_id, url, secret)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (shop_id) DO UPDATE SET url = EXCLUDED.url, secret = EXCLUDED.secret, updated_at = NOW()
+	`
+	_, err = s.pool.Exec(ctx, query, shopID, url, encrypted)
+	return err
+}
+
+// DeleteEndpoint removes the shop's endpoint and fails any deliveries still waiting to be sent.
+func (s *WebhookStore) DeleteEndpoint(ctx context.Context, shopID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM shop_webhooks WHERE shop_id = $1`, shopID); err != nil {
+		return err
+	}
+	failQuery := `UPDATE webhook_deliveries SET status = $1, last_error = $2 WHERE shop_id = $3 AND status = $4`
+	if _, err := tx.Exec(ctx, failQuery, string(WebhookDeliveryFailed), "webhook endpoint removed", shopID, string(WebhookDeliveryPending)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *WebhookStore) CreateDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, shop_id, order_id, event, url, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING status, next_attempt_at, created_at
+	`
+
+	var (
+		status        string
+		nextAttemptAt pgtype.Timestamptz
+		createdAt     pgtype.Timestamptz
+	)
+	orderID := pgtype.UUID{Bytes: delivery.OrderID, Valid: delivery.OrderID != uuid.Nil}
+	err := s.pool.QueryRow(ctx, query, delivery.ID, delivery.ShopID, orderID, string(delivery.Event), delivery.URL, delivery.Payload).
+		Scan(&status, &nextAttemptAt, &createdAt)
+	if err != nil {
+		return err
+	}
+	delivery.Status = WebhookDeliveryStatus(status)
+	delivery.NextAttemptAt = nextAttemptAt.Time.UTC()
+	delivery.CreatedAt = createdAt.Time.UTC()
+	return nil
+}
+
+// ClaimDueDeliveries locks pending deliveries that are due and pushes their next attempt out by
+// lease, so another instance polling at the same time skips them while they are in flight. Only
+// deliveries for shops pinned to region (or unpinned shops) are claimed; an empty region claims all.
+func (s *WebhookStore) ClaimDueDeliveries(ctx context.Context, region string, limit int, lease time.Duration) ([]*WebhookDispatch, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM shop_webhooks w
+		WHERE w.shop_id = d.shop_id
+		  AND d.id IN (
+			SELECT wd.id FROM webhook_deliveries wd
+			JOIN shops s ON s.id = wd.shop_id
+			WHERE wd.status = $3 AND wd.next_attempt_at <= NOW() AND ($4 = '' OR s.region IN ('', $4))
+			ORDER BY wd.next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING d.id, d.shop_id, d.order_id, d.event, d.url, d.payload, d.attempts, d.created_at, w.secret
+	`
+	rows, err := s.pool.Query(ctx, query, limit, lease.Seconds(), string(WebhookDeliveryPending), region)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dispatches := []*WebhookDispatch{}
+	for rows.Next() {
+		var (
+			delivery  WebhookDelivery
+			orderID   pgtype.UUID
+			event     string
+			attempts  int32
+			createdAt pgtype.Timestamptz
+			secret    string
+		)
+		if err := rows.Scan(&delivery.ID, &delivery.ShopID, &orderID, &event, &delivery.URL, &delivery.Payload, &attempts, &createdAt, &secret); err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			delivery.OrderID = orderID.Bytes
+		}
+		delivery.Event = WebhookEvent(event)
+		delivery.Status = WebhookDeliveryPending
+		delivery.Attempts = int(attempts)
+		delivery.CreatedAt = createdAt.Time.UTC()
+
+		decrypted, err := s.crypto.Decrypt(secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+		}
+		dispatches = append(dispatches, &WebhookDispatch{Delivery: &delivery, Secret: decrypted})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return dispatches, nil
+}
+
+func (s *WebhookStore) MarkDeliverySucceeded(ctx context.Context, id uuid.UUID, responseStatus int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = attempts + 1, last_response_status = $2, last_error = NULL, delivered_at = NOW()
+		WHERE id = $3
+	`
+	_, err := s.pool.Exec(ctx, query, string(WebhookDeliverySucceeded), nullableResponseStatus(responseStatus), id)
+	return err
+}
+
+// MarkDeliveryRetry records a failed attempt and schedules the next one.
+func (s *WebhookStore) MarkDeliveryRetry(ctx context.Context, id uuid.UUID, responseStatus int, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, last_response_status = $1, last_error = $2, next_attempt_at = $3
+		WHERE id = $4
+	`
+	_, err := s.pool.Exec(ctx, query, nullableResponseStatus(responseStatus), lastError, nextAttemptAt, id)
+	return err
+}
+
+// MarkDeliveryFailed records the final failed attempt; the delivery is not retried again.
+func (s *WebhookStore) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, responseStatus int, lastError string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = attempts + 1, last_response_status = $2, last_error = $3
+		WHERE id = $4
+	`
+	_, err := s.pool.Exec(ctx, query, string(WebhookDeliveryFailed), nullableResponseStatus(responseStatus), lastError, id)
+	return err
+}
+
+func (s *WebhookStore) ListDeliveries(ctx context.Context, shopID uuid.UUID, limit int) ([]*WebhookDelivery, error) {
+	query := `
+		SELECT id, shop_id, order_id, event, url, status, attempts, next_attempt_at,
+		       last_response_status, last_error, created_at, delivered_at
+		FROM webhook_deliveries
+		WHERE shop_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := s.pool.Query(ctx, query, shopID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*WebhookDelivery{}
+	for rows.Next() {
+		var (
+			delivery       WebhookDelivery
+			orderID        pgtype.UUID
+			event          string
+			status         string
+			attempts       int32
+			nextAttemptAt  pgtype.Timestamptz
+			responseStatus pgtype.Int4
+			lastError      pgtype.Text
+			createdAt      pgtype.Timestamptz
+			deliveredAt    pgtype.Timestamptz
+		)
+		if err := rows.Scan(&delivery.ID, &delivery.ShopID, &orderID, &event, &delivery.URL, &status, &attempts, &nextAttemptAt,
+			&responseStatus, &lastError, &createdAt, &deliveredAt); err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			delivery.OrderID = orderID.Bytes
+		}
+		delivery.Event = WebhookEvent(event)
+		delivery.Status = WebhookDeliveryStatus(status)
+		delivery.Attempts = int(attempts)
+		delivery.NextAttemptAt = nextAttemptAt.Time.UTC()
+		delivery.LastResponseStatus = int(responseStatus.Int32)
+		delivery.LastError = lastError.String
+		delivery.CreatedAt = createdAt.Time.UTC()
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = deliveredAt.Time.UTC()
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func nullableResponseStatus(status int) pgtype.Int4 {
+	return pgtype.Int4{Int32: int32(status), Valid: status > 0}
+}
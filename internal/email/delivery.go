@@ -0,0 +1,243 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	resend "github.com/resend/resend-go/v3"
+)
+
+// Metadata keys set on order emails. Providers echo them back in delivery webhooks, which is
+// how an event finds its entry in the order's email log.
+const (
+	MetadataOrderID   = "order_id"
+	MetadataEmailKind = "email_kind"
+)
+
+// mailgunSignatureTolerance bounds how old a signed Mailgun webhook may be, against replays.
+const mailgunSignatureTolerance = 5 * time.Minute
+
+// ErrInvalidWebhookSignature means a delivery webhook was not signed with the shop's secret.
+var ErrInvalidWebhookSignature = errors.New("invalid email webhook signature")
+
+type DeliveryEventType string
+
+const (
+	DeliveryBounced    DeliveryEventType = "bounced"
+	DeliveryComplained DeliveryEventType = "complained"
+	DeliveryDelivered  DeliveryEventType = "delivered"
+)
+
+// DeliveryEvent is a provider's report about one sent email. Type is empty for events gitshop
+// does not track, such as opens and clicks.
+type DeliveryEvent struct {
+	ID        string
+	Type      DeliveryEventType
+	Recipient string
+	// Permanent is set for hard bounces, where sending again cannot succeed.
+	Permanent bool
+	Detail    string
+	Metadata  map[string]string
+}
+
+// ParseDeliveryWebhook verifies a delivery webhook from the named provider with the shop's
+// signing secret and reads the event from it. Postmark does not sign webhooks, so its webhook
+// URL carries the secret as the basic auth password instead.
+func ParseDeliveryWebhook(provider, secret string, payload []byte, header http.Header) (DeliveryEvent, error) {
+	if secret == "" {
+		return DeliveryEvent{}, fmt.Errorf("%w: no signing secret configured", ErrInvalidWebhookSignature)
+	}
+	switch provider {
+	case "postmark":
+		return parsePostmarkEvent(secret, payload, header)
+	case "mailgun":
+		return parseMailgunEvent(secret, payload, time.Now())
+	case "resend":
+		return parseResendEvent(secret, payload, header)
+	default:
+		return DeliveryEvent{}, fmt.Errorf("email provider %q does not send delivery webhooks", provider)
+	}
+}
+
+type postmarkEvent struct {
+	RecordType  string            `json:"RecordType"`
+	ID          json.Number       `json:"ID"`
+	MessageID   string            `json:"MessageID"`
+	Type        string            `json:"Type"`
+	Email       string            `json:"Email"`
+	Recipient   string            `json:"Recipient"`
+	Description string            `json:"Description"`
+	Details     string            `json:"Details"`
+	Metadata    map[string]string `json:"Metadata"`
+}
+
+// postmarkSoftBounces are the Postmark bounce types worth sending to again later.
+var postmarkSoftBounces = map[string]bool{
+	"Transient":     true,
+	"SoftBounce":    true,
+	"DnsError":      true,
+	"AutoResponder": true,
+	"Unknown":       true,
+	"Blocked":       true,
+}
+
+func parsePostmarkEvent(secret string, payload []byte, header http.Header) (DeliveryEvent, error) {
+	request := http.Request{Header: header}
+	_, password, ok := request.BasicAuth()
+	if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(secret)) != 1 {
+		return DeliveryEvent{}, ErrInvalidWebhookSignature
+	}
+
+	var event postmarkEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return DeliveryEvent{}, fmt.Errorf("failed to parse postmark webhook: %w", err)
+	}
+	eventID := event.ID.String()
+	if eventID == "" {
+		eventID = event.MessageID
+	}
+	parsed := DeliveryEvent{
+		ID:       event.RecordType + ":" + eventID,
+		Metadata: event.Metadata,
+	}
+	switch event.RecordType {
+	case "Bounce":
+		parsed.Type = DeliveryBounced
+		parsed.Recipient = event.Email
+		parsed.Permanent = !postmarkSoftBounces[event.Type]
+		parsed.Detail = firstNonEmpty(event.Description, event.Details, event.Type)
+	case "SpamComplaint":
+		parsed.Type = DeliveryComplained
+		parsed.Recipient = event.Email
+	case "Delivery":
+		parsed.Type = DeliveryDelivered
+		parsed.Recipient = event.Recipient
+		parsed.Detail = event.Details
+	}
+	return parsed, nil
+}
+
+type mailgunWebhook struct {
+	Signature struct {
+		Timestamp string `json:"timestamp"`
+		Token     string `json:"token"`
+		Signature string `json:"signature"`
+	} `json:"signature"`
+	EventData struct {
+		ID             string            `json:"id"`
+		Event          string            `json:"event"`
+		Severity       string            `json:"severity"`
+		Recipient      string            `json:"recipient"`
+		Reason         string            `json:"reason"`
+		UserVariables  map[string]string `json:"user-variables"`
+		DeliveryStatus struct {
+			Message     string `json:"message"`
+			Description string `json:"description"`
+		} `json:"delivery-status"`
+	} `json:"event-data"`
+}
+
+func parseMailgunEvent(secret string, payload []byte, now time.Time) (DeliveryEvent, error) {
+	var webhook mailgunWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return DeliveryEvent{}, fmt.Errorf("failed to parse mailgun webhook: %w", err)
+	}
+
+	signature := webhook.Signature
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signature.Timestamp + signature.Token))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(signature.Signature)) {
+		return DeliveryEvent{}, ErrInvalidWebhookSignature
+	}
+	signedAt, err := strconv.ParseInt(signature.Timestamp, 10, 64)
+	if err != nil || now.Sub(time.Unix(signedAt, 0)).Abs() > mailgunSignatureTolerance {
+		return DeliveryEvent{}, fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidWebhookSignature)
+	}
+
+	data := webhook.EventData
+	event := DeliveryEvent{
+		ID:        data.ID,
+		Recipient: data.Recipient,
+		Metadata:  data.UserVariables,
+	}
+	switch data.Event {
+	case "failed":
+		event.Type = DeliveryBounced
+		event.Permanent = data.Severity == "permanent"
+		event.Detail = firstNonEmpty(data.DeliveryStatus.Description, data.DeliveryStatus.Message, data.Reason)
+	case "complained":
+		event.Type = DeliveryComplained
+	case "delivered":
+		event.Type = DeliveryDelivered
+	}
+	return event, nil
+}
+
+type resendEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		EmailID string            `json:"email_id"`
+		To      []string          `json:"to"`
+		Tags    map[string]string `json:"tags"`
+		Bounce  struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"bounce"`
+	} `json:"data"`
+}
+
+func parseResendEvent(secret string, payload []byte, header http.Header) (DeliveryEvent, error) {
+	err := new(resend.WebhooksSvcImpl).Verify(&resend.VerifyWebhookOptions{
+		Payload: string(payload),
+		Headers: resend.WebhookHeaders{
+			Id:        header.Get("svix-id"),
+			Timestamp: header.Get("svix-timestamp"),
+			Signature: header.Get("svix-signature"),
+		},
+		WebhookSecret: secret,
+	})
+	if err != nil {
+		return DeliveryEvent{}, fmt.Errorf("%w: %w", ErrInvalidWebhookSignature, err)
+	}
+
+	var event resendEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return DeliveryEvent{}, fmt.Errorf("failed to parse resend webhook: %w", err)
+	}
+	parsed := DeliveryEvent{
+		ID:       header.Get("svix-id"),
+		Metadata: event.Data.Tags,
+	}
+	if len(event.Data.To) > 0 {
+		parsed.Recipient = event.Data.To[0]
+	}
+	switch event.Type {
+	case "email.bounced":
+		parsed.Type = DeliveryBounced
+		parsed.Permanent = !strings.EqualFold(event.Data.Bounce.Type, "Transient")
+		parsed.Detail = event.Data.Bounce.Message
+	case "email.complained":
+		parsed.Type = DeliveryComplained
+	case "email.delivered":
+		parsed.Type = DeliveryDelivered
+	}
+	return parsed, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
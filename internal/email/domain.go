@@ -0,0 +1,228 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	resend "github.com/resend/resend-go/v3"
+)
+
+// ErrDomainVerificationUnsupported means the provider's API cannot report sending domain DNS
+// records with the credentials a shop saves, so the domain is verified in the provider's dashboard.
+var ErrDomainVerificationUnsupported = errors.New("email provider does not support domain verification")
+
+// DNSRecord is one record the provider needs published before it sends as the shop's domain.
+type DNSRecord struct {
+	// Purpose names what the record is for, such as DKIM, SPF, return path or tracking.
+	Purpose  string
+	Type     string
+	Name     string
+	Value    string
+	Priority string
+	Verified bool
+}
+
+// DomainStatus is the provider's view of the shop's sending domain.
+type DomainStatus struct {
+	Domain   string
+	Verified bool
+	Records  []DNSRecord
+}
+
+// DomainVerifier is implemented by providers whose API reports sending domain DNS records.
+type DomainVerifier interface {
+	// DomainStatus returns the DNS records for the sending domain, adding the domain to the
+	// provider's account first when it is missing.
+	DomainStatus(ctx context.Context) (*DomainStatus, error)
+	// VerifyDomain asks the provider to check DNS again and returns the updated status.
+	VerifyDomain(ctx context.Context) (*DomainStatus, error)
+}
+
+// SendingDomain returns the domain part of a from address.
+func SendingDomain(from string) string {
+	if address, err := mail.ParseAddress(from); err == nil {
+		from = address.Address
+	}
+	at := strings.LastIndex(from, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(from[at+1:]))
+}
+
+type mailgunDomainResponse struct {
+	Message string `json:"message"`
+	Domain  struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	} `json:"domain"`
+	SendingDNSRecords   []mailgunDNSRecord `json:"sending_dns_records"`
+	ReceivingDNSRecords []mailgunDNSRecord `json:"receiving_dns_records"`
+}
+
+type mailgunDNSRecord struct {
+	RecordType string `json:"record_type"`
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Priority   string `json:"priority"`
+	Valid      string `json:"valid"`
+}
+
+// DomainStatus returns the DNS records Mailgun needs for the provider's domain.
+func (m *MailgunProvider) DomainStatus(ctx context.Context) (*DomainStatus, error) {
+	status, code, err := m.domainRequest(ctx, http.MethodGet, "/domains/"+url.PathEscape(m.domain), nil)
+	if code == http.StatusNotFound {
+		form := url.Values{"name": {m.domain}}
+		status, _, err = m.domainRequest(ctx, http.MethodPost, "/domains", strings.NewReader(form.Encode()))
+	}
+	return status, err
+}
+
+// VerifyDomain asks Mailgun to check the domain's DNS records again.
+func (m *MailgunProvider) VerifyDomain(ctx context.Context) (*DomainStatus, error) {
+	status, _, err := m.domainRequest(ctx, http.MethodPut, "/domains/"+url.PathEscape(m.domain)+"/verify", nil)
+	return status, err
+}
+
+func (m *MailgunProvider) domainRequest(ctx context.Context, method, path string, body io.Reader) (*DomainStatus, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, m.baseURL+path, body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.SetBasicAuth("api", m.apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load mailgun domain: %w", err)
+	}
+	payload, readErr := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if readErr != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read mailgun domain response: %w", readErr)
+	}
+	if closeErr != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to close mailgun domain response body: %w", closeErr)
+	}
+
+	var decoded mailgunDomainResponse
+	if err := json.Unmarshal(payload, &decoded); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("failed to parse mailgun domain response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if decoded.Message != "" {
+			return nil, resp.StatusCode, fmt.Errorf("mailgun error: %s", decoded.Message)
+		}
+		return nil, resp.StatusCode, fmt.Errorf("mailgun API returned status %d", resp.StatusCode)
+	}
+
+	status := &DomainStatus{
+		Domain:   firstNonEmpty(decoded.Domain.Name, m.domain),
+		Verified: decoded.Domain.State == "active",
+	}
+	for _, record := range decoded.SendingDNSRecords {
+		status.Records = append(status.Records, record.toDNSRecord())
+	}
+	for _, record := range decoded.ReceivingDNSRecords {
+		status.Records = append(status.Records, record.toDNSRecord())
+	}
+	return status, resp.StatusCode, nil
+}
+
+func (r mailgunDNSRecord) toDNSRecord() DNSRecord {
+	purpose := "Tracking"
+	switch {
+	case r.RecordType == "MX":
+		purpose = "Return path"
+	case strings.HasPrefix(r.Value, "v=spf1"):
+		purpose = "SPF"
+	case strings.Contains(r.Name, "._domainkey."):
+		purpose = "DKIM"
+	}
+	return DNSRecord{
+		Purpose:  purpose,
+		Type:     r.RecordType,
+		Name:     r.Name,
+		Value:    r.Value,
+		Priority: r.Priority,
+		Verified: r.Valid == "valid",
+	}
+}
+
+// DomainStatus returns the DNS records Resend needs for the from address's domain.
+func (r *ResendProvider) DomainStatus(ctx context.Context) (*DomainStatus, error) {
+	domainID, err := r.domainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.domainStatus(ctx, domainID)
+}
+
+// VerifyDomain asks Resend to check the domain's DNS records again.
+func (r *ResendProvider) VerifyDomain(ctx context.Context) (*DomainStatus, error) {
+	domainID, err := r.domainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.client.Domains.VerifyWithContext(ctx, domainID); err != nil {
+		return nil, fmt.Errorf("failed to verify resend domain: %w", err)
+	}
+	return r.domainStatus(ctx, domainID)
+}
+
+func (r *ResendProvider) domainID(ctx context.Context) (string, error) {
+	if r.client == nil {
+		return "", fmt.Errorf("resend client not configured")
+	}
+	name := SendingDomain(r.from)
+	if name == "" {
+		return "", fmt.Errorf("from address has no domain")
+	}
+	domains, err := r.client.Domains.ListWithContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list resend domains: %w", err)
+	}
+	for _, domain := range domains.Data {
+		if strings.EqualFold(domain.Name, name) {
+			return domain.Id, nil
+		}
+	}
+	created, err := r.client.Domains.CreateWithContext(ctx, &resend.CreateDomainRequest{Name: name})
+	if err != nil {
+		return "", fmt.Errorf("failed to add resend domain: %w", err)
+	}
+	return created.Id, nil
+}
+
+func (r *ResendProvider) domainStatus(ctx context.Context, domainID string) (*DomainStatus, error) {
+	domain, err := r.client.Domains.GetWithContext(ctx, domainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load resend domain: %w", err)
+	}
+	status := &DomainStatus{
+		Domain:   domain.Name,
+		Verified: domain.Status == "verified",
+	}
+	for _, record := range domain.Records {
+		status.Records = append(status.Records, DNSRecord{
+			Purpose:  record.Record,
+			Type:     record.Type,
+			Name:     record.Name,
+			Value:    record.Value,
+			Priority: record.Priority.String(),
+			Verified: record.Status == "verified",
+		})
+	}
+	return status, nil
+}
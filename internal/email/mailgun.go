@@ -59,6 +59,9 @@ func (m *MailgunProvider) SendEmail(ctx context.Context, email *Email) error {
 	if email.HTML != "" {
 		data.Set("html", email.HTML)
 	}
+	for key, value := range email.Metadata {
+		data.Set("v:"+key, value)
+	}
 
 	apiURL := fmt.Sprintf("%s/%s/messages", m.baseURL, m.domain)
 
@@ -34,18 +34,18 @@ func NewPostmarkProvider(apiKey, from string) *PostmarkProvider {
 }
 
 type postmarkEmail struct {
-	From       string `json:"From"`
-	To         string `json:"To"`
-	Subject    string `json:"Subject"`
-	TextBody   string `json:"TextBody,omitempty"`
-	HtmlBody   string `json:"HtmlBody,omitempty"`
-	TrackOpens bool   `json:"TrackOpens"`
-	TrackLinks string `json:"TrackLinks"`
-	InlineCSS  bool   `json:"InlineCSS"`
-	Tag        string `json:"Tag,omitempty"`
-	Metadata   string `json:"Metadata,omitempty"`
-	ReplyTo    string `json:"ReplyTo,omitempty"`
-	Headers    string `json:"Headers,omitempty"`
+	From       string            `json:"From"`
+	To         string            `json:"To"`
+	Subject    string            `json:"Subject"`
+	TextBody   string            `json:"TextBody,omitempty"`
+	HtmlBody   string            `json:"HtmlBody,omitempty"`
+	TrackOpens bool              `json:"TrackOpens"`
+	TrackLinks string            `json:"TrackLinks"`
+	InlineCSS  bool              `json:"InlineCSS"`
+	Tag        string            `json:"Tag,omitempty"`
+	Metadata   map[string]string `json:"Metadata,omitempty"`
+	ReplyTo    string            `json:"ReplyTo,omitempty"`
+	Headers    string            `json:"Headers,omitempty"`
 }
 
 // SendEmail sends an email via the Postmark API
@@ -58,6 +58,7 @@ func (p *PostmarkProvider) SendEmail(ctx context.Context, email *Email) error {
 		HtmlBody:   email.HTML,
 		TrackOpens: true,
 		InlineCSS:  true,
+		Metadata:   email.Metadata,
 	}
 
 	jsonData, err := json.Marshal(payload)
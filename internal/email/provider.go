@@ -19,6 +19,8 @@ type Email struct {
 	Subject string
 	Text    string
 	HTML    string
+	// Metadata is attached to the message and echoed back in the provider's delivery webhooks.
+	Metadata map[string]string
 }
 
 type Config struct {
@@ -63,11 +65,22 @@ func NewProviderFromShop(shop *db.Shop) (Provider, error) {
 	}
 }
 
+// ShopDeliveryWebhook returns the shop's provider and the secret that signs its delivery
+// webhooks, which is empty until the seller saves one.
+func ShopDeliveryWebhook(shop *db.Shop) (provider, secret string, err error) {
+	cfg, err := decodeShopEmailConfig(shop.EmailConfig)
+	if err != nil {
+		return "", "", err
+	}
+	return shop.EmailProvider, cfg.WebhookSecret, nil
+}
+
 type shopEmailConfig struct {
-	APIKey    string `json:"api_key"`
-	FromEmail string `json:"from_email"`
-	Domain    string `json:"domain"`
-	BaseURL   string `json:"base_url"`
+	APIKey        string `json:"api_key"`
+	FromEmail     string `json:"from_email"`
+	Domain        string `json:"domain"`
+	BaseURL       string `json:"base_url"`
+	WebhookSecret string `json:"webhook_secret"`
 }
 
 func decodeShopEmailConfig(config map[string]any) (shopEmailConfig, error) {
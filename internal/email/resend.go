@@ -44,6 +44,9 @@ func (r *ResendProvider) SendEmail(ctx context.Context, email *Email) error {
 	if email.Text != "" {
 		params.Text = email.Text
 	}
+	for name, value := range email.Metadata {
+		params.Tags = append(params.Tags, resend.Tag{Name: name, Value: value})
+	}
 	if params.Html == "" && params.Text == "" {
 		return fmt.Errorf("email body is empty")
 	}
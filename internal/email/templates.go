@@ -5,18 +5,29 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"strings"
 	"text/template"
 	"time"
+
+	"github.com/gitshopapp/gitshop/internal/i18n"
 )
 
 // OrderInfo contains all the information needed for order email templates
 type OrderInfo struct {
-	OrderNumber         string
-	IssueURL            string
-	CustomerName        string
-	CustomerEmail       string
-	ShopName            string
-	ShopURL             string
+	// OrderID tags the sent email so delivery webhooks can find the order. Templates do not show it.
+	OrderID       string
+	OrderNumber   string
+	IssueURL      string
+	CustomerName  string
+	CustomerEmail string
+	ShopName      string
+	ShopURL       string
+	// LogoURL, AccentColor, and SupportEmail come from the shop's branding. The renderer fills
+	// them in, so callers leave them empty.
+	LogoURL             string
+	AccentColor         string
+	SupportEmail        string
 	ProductName         string
 	Quantity            int
 	UnitPrice           string
@@ -32,6 +43,33 @@ type OrderInfo struct {
 	Shipping            string
 	Tax                 string
 	Total               string
+	TotalEstimate       string
+	PreviousOrders      int
+	DashboardURL        string
+	Refunded            bool
+	ChargesPaused       bool
+	PayoutsPaused       bool
+	CustomFields        []OrderField
+	ReceiptURL          string
+	TaxID               string
+	ShipWithinDays      int
+	OverdueCount        int
+	OverdueOrders       []OverdueOrder
+}
+
+// OverdueOrder is a paid order listed in the seller's digest of orders waiting to ship.
+type OverdueOrder struct {
+	OrderNumber string
+	PaidDate    string
+	Total       string
+	DaysWaiting int
+	URL         string
+}
+
+// OrderField is a labelled answer the buyer gave at checkout, such as a VAT ID.
+type OrderField struct {
+	Label string
+	Value string
 }
 
 // OrderItem represents a single item in an order
@@ -52,13 +90,44 @@ type EmailTemplate struct {
 	Text    string
 }
 
+// Branding is the shop's look from gitshop.yaml. Its values are validated there and placed in
+// emails unescaped. ShopURL, when set, replaces the repository link.
+type Branding struct {
+	LogoURL      string
+	AccentColor  string
+	SupportEmail string
+	ShopURL      string
+}
+
 // Renderer provides methods to render email templates
 type Renderer struct {
 	templates *template.Template
+	loc       i18n.Localizer
+	branding  Branding
+}
+
+// WithBranding returns a copy of the renderer that applies the shop's branding to every email.
+func (r *Renderer) WithBranding(branding Branding) *Renderer {
+	branded := *r
+	branded.branding = branding
+	return &branded
 }
 
 // NewRenderer creates a new email template renderer with built-in templates
 func NewRenderer() (*Renderer, error) {
+	return NewLocalizedRenderer("", nil)
+}
+
+// NewRendererWithOverrides creates a renderer whose built-in templates are replaced by any
+// overrides, keyed by template name such as order_confirmation_html.
+func NewRendererWithOverrides(overrides map[string]string) (*Renderer, error) {
+	return NewLocalizedRenderer("", overrides)
+}
+
+// NewLocalizedRenderer creates a renderer whose buyer emails are written in locale. Seller emails
+// stay in English.
+func NewLocalizedRenderer(locale string, overrides map[string]string) (*Renderer, error) {
+	loc := i18n.New(locale)
 	templates := map[string]EmailTemplate{
 		"order_confirmation": {
 			Name:    "Order Confirmation",
@@ -78,15 +147,45 @@ func NewRenderer() (*Renderer, error) {
 			HTML:    orderDeliveredHTML,
 			Text:    orderDeliveredText,
 		},
-	}
-
-	funcMap := template.FuncMap{
-		"formatDate": func(t time.Time) string {
-			return t.Format("January 2, 2006")
+		"order_returned": {
+			Name:    "Order Returned",
+			Subject: "Your Return Is Complete - {{.OrderNumber}} - {{.ShopName}}",
+			HTML:    orderReturnedHTML,
+			Text:    orderReturnedText,
+		},
+		"new_order": {
+			Name:    "New Order",
+			Subject: "New Order - {{.OrderNumber}} - {{.ShopName}}",
+			HTML:    newOrderHTML,
+			Text:    newOrderText,
+		},
+		"first_order": {
+			Name:    "First Order",
+			Subject: "Your first order - {{.OrderNumber}} - {{.ShopName}}",
+			HTML:    firstOrderHTML,
+			Text:    firstOrderText,
+		},
+		"checkout_reminder": {
+			Name:    "Checkout Reminder",
+			Subject: "Your checkout expires soon - {{.OrderNumber}} - {{.ShopName}}",
+			HTML:    checkoutReminderHTML,
+			Text:    checkoutReminderText,
+		},
+		"stripe_account_restricted": {
+			Name:    "Stripe Account Restricted",
+			Subject: "Action needed: Stripe restricted {{.ShopName}}",
+			HTML:    stripeAccountRestrictedHTML,
+			Text:    stripeAccountRestrictedText,
+		},
+		"overdue_shipments": {
+			Name:    "Overdue Shipments",
+			Subject: "{{.OverdueCount}} paid orders waiting to ship - {{.ShopName}}",
+			HTML:    overdueShipmentsHTML,
+			Text:    overdueShipmentsText,
 		},
 	}
 
-	tmpl := template.New("email").Funcs(funcMap)
+	tmpl := template.New("email").Funcs(templateFuncs(loc))
 
 	for key, t := range templates {
 		_, err := tmpl.New(key + "_html").Parse(t.HTML)
@@ -99,15 +198,132 @@ func NewRenderer() (*Renderer, error) {
 		}
 	}
 
+	for name, content := range overrides {
+		if _, ok := overridableTemplates[name]; !ok {
+			return nil, fmt.Errorf("unknown email template %s", name)
+		}
+		if _, err := tmpl.New(name).Parse(content); err != nil {
+			return nil, fmt.Errorf("failed to parse template override %s: %w", name, err)
+		}
+	}
+
 	return &Renderer{
 		templates: tmpl,
+		loc:       loc,
 	}, nil
 }
 
+// templateFuncs are available to built-in templates and repo overrides. t looks up a message in
+// the shop's locale and link builds the anchor some messages take as an argument.
+func templateFuncs(loc i18n.Localizer) template.FuncMap {
+	return template.FuncMap{
+		"formatDate": func(t time.Time) string {
+			return t.Format("January 2, 2006")
+		},
+		"t": loc.T,
+		"link": func(url, text string) string {
+			return fmt.Sprintf(`<a href="%s">%s</a>`, url, text)
+		},
+	}
+}
+
+// overridableTemplates maps template names to the file shops commit under gitshop/emails.
+var overridableTemplates = map[string]string{
+	"order_confirmation_html": "order_confirmation.html",
+	"order_confirmation_text": "order_confirmation.txt",
+	"order_shipped_html":      "order_shipped.html",
+	"order_shipped_text":      "order_shipped.txt",
+	"order_delivered_html":    "order_delivered.html",
+	"order_delivered_text":    "order_delivered.txt",
+	"order_returned_html":     "order_returned.html",
+	"order_returned_text":     "order_returned.txt",
+}
+
+// TemplateNameForFile returns the template name a repo override file replaces.
+func TemplateNameForFile(fileName string) (string, bool) {
+	for name, file := range overridableTemplates {
+		if file == fileName {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// ValidateTemplate parses a template override and renders it against sample order data so
+// unknown fields and syntax errors surface before a customer email is sent.
+func ValidateTemplate(name, content string) error {
+	if _, ok := overridableTemplates[name]; !ok {
+		return fmt.Errorf("unknown email template %s", name)
+	}
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("template is empty")
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs(i18n.Localizer{})).Parse(content)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(io.Discard, sampleOrderInfo()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func sampleOrderInfo() *OrderInfo {
+	return &OrderInfo{
+		OrderNumber:     "#1001",
+		CustomerName:    "Sample Customer",
+		CustomerEmail:   "customer@example.com",
+		ShopName:        "octo/shop",
+		ProductName:     "SAMPLE",
+		Quantity:        1,
+		UnitPrice:       "$10.00",
+		TotalPrice:      "$10.00",
+		ShippingAddress: "123 Example Street",
+		OrderDate:       "January 2, 2006",
+		Items:           []OrderItem{{Name: "SAMPLE", SKU: "SAMPLE", Quantity: 1, UnitPrice: "$10.00", TotalPrice: "$10.00"}},
+		Subtotal:        "$10.00",
+		Shipping:        "$0.00",
+		Tax:             "$0.00",
+		Total:           "$10.00",
+	}
+}
+
+// Send renders a template and sends it through the provider
+func (r *Renderer) Send(ctx context.Context, p Provider, templateName string, orderInfo *OrderInfo) error {
+	if p == nil {
+		return nil
+	}
+
+	email, err := r.Render(ctx, templateName, orderInfo)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	if orderInfo != nil && orderInfo.OrderID != "" {
+		email.Metadata = map[string]string{
+			MetadataOrderID:   orderInfo.OrderID,
+			MetadataEmailKind: templateName,
+		}
+	}
+
+	return p.SendEmail(ctx, email)
+}
+
 // Render renders an email template with the given data
 func (r *Renderer) Render(ctx context.Context, templateName string, data *OrderInfo) (*Email, error) {
 	var htmlBuf, textBuf bytes.Buffer
 
+	if data != nil {
+		branded := *data
+		branded.LogoURL = r.branding.LogoURL
+		branded.AccentColor = r.branding.AccentColor
+		branded.SupportEmail = r.branding.SupportEmail
+		if r.branding.ShopURL != "" {
+			branded.ShopURL = r.branding.ShopURL
+		}
+		data = &branded
+	}
+
 	// Render HTML version
 	err := r.templates.ExecuteTemplate(&htmlBuf, templateName+"_html", data)
 	if err != nil {
@@ -124,11 +340,27 @@ func (r *Renderer) Render(ctx context.Context, templateName string, data *OrderI
 	subject := ""
 	switch templateName {
 	case "order_confirmation":
-		subject = fmt.Sprintf("Order Confirmed - %s - %s", data.OrderNumber, data.ShopName)
+		subject = r.loc.T("email.subject.order_confirmation", data.OrderNumber, data.ShopName)
 	case "order_shipped":
-		subject = fmt.Sprintf("Your Order Has Shipped - %s - %s", data.OrderNumber, data.ShopName)
+		subject = r.loc.T("email.subject.order_shipped", data.OrderNumber, data.ShopName)
 	case "order_delivered":
-		subject = fmt.Sprintf("Your Order Has Been Delivered - %s", data.OrderNumber)
+		subject = r.loc.T("email.subject.order_delivered", data.OrderNumber)
+	case "order_returned":
+		subject = r.loc.T("email.subject.order_returned", data.OrderNumber, data.ShopName)
+	case "new_order":
+		subject = fmt.Sprintf("New Order - %s - %s", data.OrderNumber, data.ShopName)
+	case "first_order":
+		subject = fmt.Sprintf("Your first order - %s - %s", data.OrderNumber, data.ShopName)
+	case "checkout_reminder":
+		subject = r.loc.T("email.subject.checkout_reminder", data.OrderNumber, data.ShopName)
+	case "stripe_account_restricted":
+		subject = fmt.Sprintf("Action needed: Stripe restricted %s", data.ShopName)
+	case "overdue_shipments":
+		if data.OverdueCount == 1 {
+			subject = fmt.Sprintf("1 paid order waiting to ship - %s", data.ShopName)
+		} else {
+			subject = fmt.Sprintf("%d paid orders waiting to ship - %s", data.OverdueCount, data.ShopName)
+		}
 	}
 
 	return &Email{
@@ -197,27 +429,33 @@ func SendOrderDelivered(ctx context.Context, p Provider, orderInfo *OrderInfo) e
 }
 
 // Template text content - Order Confirmation
-const orderConfirmationText = `Thank you for your order!
+const orderConfirmationText = `{{t "email.confirmation.thanks"}}
 
-Order Number: {{.OrderNumber}}
-Order Date: {{.OrderDate}}
-
-Items:
+{{t "email.order_number"}}: {{.OrderNumber}}
+{{t "email.order_date"}}: {{.OrderDate}}
+{{if .TaxID}}{{t "email.tax_id"}}: {{.TaxID}}
+{{end}}{{range .CustomFields}}{{.Label}}: {{.Value}}
+{{end}}
+{{t "email.items"}}:
 {{range .Items}}
 - {{.Name}}{{if .Options}} ({{.Options}}){{end}} x{{.Quantity}} - {{.TotalPrice}}
 {{end}}
 
-Subtotal: {{.Subtotal}}
-Shipping: {{.Shipping}}
-Tax: {{.Tax}}
-Total: {{.Total}}
+{{t "email.subtotal"}}: {{.Subtotal}}
+{{t "email.shipping"}}: {{.Shipping}}
+{{t "email.tax"}}: {{.Tax}}
+{{t "email.total"}}: {{.Total}}{{if .TotalEstimate}} ({{t "email.total_estimate" .TotalEstimate}}){{end}}{{if .ReceiptURL}}
+{{t "email.receipt"}}: {{.ReceiptURL}}{{end}}
 
-{{if .IssueURL}}Order Issue: {{.IssueURL}}{{end}}
+{{if .IssueURL}}{{t "email.order_issue"}}: {{.IssueURL}}{{end}}
 
-We'll send you another email when your order ships.
+{{t "email.confirmation.ship_notice"}}
 
-Thank you for shopping with {{.ShopName}}!
+{{t "email.thanks_for_shopping" .ShopName}}!
 {{.ShopURL}}
+{{- if .SupportEmail}}
+{{t "branding.support" .SupportEmail}}
+{{- end}}
 `
 
 // Template HTML content - Order Confirmation
@@ -226,10 +464,10 @@ const orderConfirmationHTML = `<!DOCTYPE html>
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>Order Confirmation</title>
+  <title>{{t "email.confirmation.title"}}</title>
   <style>
     body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
-    .header { background: #2563eb; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .header { background: {{or .AccentColor "#2563eb"}}; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
     .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
     .order-info { background: white; padding: 15px; border-radius: 6px; margin: 15px 0; }
     .items-table { width: 100%; border-collapse: collapse; margin: 15px 0; }
@@ -237,27 +475,36 @@ const orderConfirmationHTML = `<!DOCTYPE html>
     .items-table td { padding: 10px; border-bottom: 1px solid #e5e7eb; }
     .total { font-size: 18px; font-weight: bold; text-align: right; padding: 15px 0; }
     .footer { text-align: center; padding: 20px; color: #6b7280; font-size: 14px; }
-    .button { display: inline-block; background: #2563eb; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
+    .button { display: inline-block; background: {{or .AccentColor "#2563eb"}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
   </style>
 </head>
 <body>
   <div class="header">
-    <h1>Order Confirmed!</h1>
-    <p>Thank you for your order, {{.CustomerName}}</p>
+    {{- if .LogoURL}}
+    <img src="{{.LogoURL}}" alt="" style="max-height: 48px; margin-bottom: 8px;">
+    {{- end}}
+    <h1>{{t "email.confirmation.heading"}}</h1>
+    <p>{{t "email.confirmation.thanks_name" .CustomerName}}</p>
   </div>
   <div class="content">
     <div class="order-info">
-      <strong>Order Number:</strong> {{.OrderNumber}}<br>
-      <strong>Order Date:</strong> {{.OrderDate}}
+      <strong>{{t "email.order_number"}}:</strong> {{.OrderNumber}}<br>
+      <strong>{{t "email.order_date"}}:</strong> {{.OrderDate}}
+      {{- if .TaxID}}<br>
+      <strong>{{t "email.tax_id"}}:</strong> {{html .TaxID}}
+      {{- end}}
+      {{- range .CustomFields}}<br>
+      <strong>{{html .Label}}:</strong> {{html .Value}}
+      {{- end}}
     </div>
 
-    <h3>Order Summary</h3>
+    <h3>{{t "email.confirmation.summary"}}</h3>
     <table class="items-table">
       <thead>
         <tr>
-          <th>Item</th>
-          <th>Qty</th>
-          <th>Price</th>
+          <th>{{t "email.item"}}</th>
+          <th>{{t "email.qty"}}</th>
+          <th>{{t "email.price"}}</th>
         </tr>
       </thead>
       <tbody>
@@ -272,43 +519,50 @@ const orderConfirmationHTML = `<!DOCTYPE html>
     </table>
 
     <div class="total">
-      <p>Subtotal: {{.Subtotal}}</p>
-      <p>Shipping: {{.Shipping}}</p>
-      <p>Tax: {{.Tax}}</p>
-      <p>Total: {{.Total}}</p>
+      <p>{{t "email.subtotal"}}: {{.Subtotal}}</p>
+      <p>{{t "email.shipping"}}: {{.Shipping}}</p>
+      <p>{{t "email.tax"}}: {{.Tax}}</p>
+      <p>{{t "email.total"}}: {{.Total}}{{if .TotalEstimate}} ({{t "email.total_estimate" .TotalEstimate}}){{end}}</p>
     </div>
 
-    <p>We'll send you another email when your order ships.</p>
-    {{if .IssueURL}}<p><a href="{{.IssueURL}}" class="button">View your GitHub order issue</a></p>{{end}}
+    <p>{{t "email.confirmation.ship_notice"}}</p>{{if .ReceiptURL}}
+    <p><a href="{{.ReceiptURL}}">{{t "email.download_receipt"}}</a></p>{{end}}
+    {{if .IssueURL}}<p><a href="{{.IssueURL}}" class="button">{{t "email.view_order_issue"}}</a></p>{{end}}
   </div>
   <div class="footer">
-    <p>Thank you for shopping with <a href="{{.ShopURL}}">{{.ShopName}}</a></p>
+    <p>{{t "email.thanks_for_shopping" (link .ShopURL .ShopName)}}</p>
+    {{- if .SupportEmail}}
+    <p>{{t "branding.support" (link (print "mailto:" .SupportEmail) .SupportEmail)}}</p>
+    {{- end}}
   </div>
 </body>
 </html>
 `
 
 // Template text content - Order Shipped
-const orderShippedText = `Great news! Your order has shipped!
+const orderShippedText = `{{t "email.shipped.text_intro"}}
 
-Order Number: {{.OrderNumber}}
-Shipped Date: {{.OrderDate}}
+{{t "email.order_number"}}: {{.OrderNumber}}
+{{t "email.shipped.date"}}: {{.OrderDate}}
 
 {{if .TrackingNumber}}
-Tracking Number: {{.TrackingNumber}}
-Carrier: {{.TrackingCarrier}}
-{{if .TrackingURL}}Track your package: {{.TrackingURL}}{{end}}
+{{t "email.shipped.tracking_number"}}: {{.TrackingNumber}}
+{{t "email.carrier"}}: {{.TrackingCarrier}}
+{{if .TrackingURL}}{{t "email.shipped.track"}}: {{.TrackingURL}}{{end}}
 {{end}}
 
-Shipping Address:
+{{t "email.shipping_address"}}:
 {{.ShippingAddress}}
 
-{{if .IssueURL}}Order Issue: {{.IssueURL}}{{end}}
+{{if .IssueURL}}{{t "email.order_issue"}}: {{.IssueURL}}{{end}}
 
-We'll let you know when your package is delivered!
+{{t "email.shipped.delivery_notice"}}
 
-Thank you for shopping with {{.ShopName}}!
+{{t "email.thanks_for_shopping" .ShopName}}!
 {{.ShopURL}}
+{{- if .SupportEmail}}
+{{t "branding.support" .SupportEmail}}
+{{- end}}
 `
 
 // Template HTML content - Order Shipped
@@ -317,62 +571,71 @@ const orderShippedHTML = `<!DOCTYPE html>
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>Order Shipped</title>
+  <title>{{t "email.shipped.title"}}</title>
   <style>
     body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
-    .header { background: #059669; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .header { background: {{or .AccentColor "#059669"}}; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
     .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
     .tracking { background: white; padding: 20px; border-radius: 6px; margin: 15px 0; border-left: 4px solid #059669; }
     .tracking-number { font-size: 24px; font-weight: bold; color: #059669; }
-    .button { display: inline-block; background: #059669; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
+    .button { display: inline-block; background: {{or .AccentColor "#059669"}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
     .footer { text-align: center; padding: 20px; color: #6b7280; font-size: 14px; }
   </style>
 </head>
 <body>
   <div class="header">
-    <h1>Your Order Has Shipped! 📦</h1>
-    <p>Great news, {{.CustomerName}}! Your order is on its way.</p>
+    {{- if .LogoURL}}
+    <img src="{{.LogoURL}}" alt="" style="max-height: 48px; margin-bottom: 8px;">
+    {{- end}}
+    <h1>{{t "email.shipped.heading"}}</h1>
+    <p>{{t "email.shipped.intro" .CustomerName}}</p>
   </div>
   <div class="content">
-    <p><strong>Order Number:</strong> {{.OrderNumber}}</p>
-    <p><strong>Shipped Date:</strong> {{.OrderDate}}</p>
+    <p><strong>{{t "email.order_number"}}:</strong> {{.OrderNumber}}</p>
+    <p><strong>{{t "email.shipped.date"}}:</strong> {{.OrderDate}}</p>
 
     {{if .TrackingNumber}}
     <div class="tracking">
-      <p><strong>Carrier:</strong> {{.TrackingCarrier}}</p>
+      <p><strong>{{t "email.carrier"}}:</strong> {{.TrackingCarrier}}</p>
       <p class="tracking-number">{{.TrackingNumber}}</p>
       {{if .TrackingURL}}
-      <a href="{{.TrackingURL}}" class="button">Track Your Package</a>
+      <a href="{{.TrackingURL}}" class="button">{{t "email.shipped.track_button"}}</a>
       {{end}}
     </div>
     {{end}}
 
-    <h3>Shipping Address</h3>
+    <h3>{{t "email.shipping_address"}}</h3>
     <p>{{if .ShippingAddressHTML}}{{.ShippingAddressHTML}}{{else}}{{.ShippingAddress}}{{end}}</p>
 
-    {{if .IssueURL}}<p><a href="{{.IssueURL}}" class="button">View your GitHub order issue</a></p>{{end}}
-    <p>We'll let you know when your package is delivered!</p>
+    {{if .IssueURL}}<p><a href="{{.IssueURL}}" class="button">{{t "email.view_order_issue"}}</a></p>{{end}}
+    <p>{{t "email.shipped.delivery_notice"}}</p>
   </div>
   <div class="footer">
-    <p>Thank you for shopping with <a href="{{.ShopURL}}">{{.ShopName}}</a></p>
+    <p>{{t "email.thanks_for_shopping" (link .ShopURL .ShopName)}}</p>
+    {{- if .SupportEmail}}
+    <p>{{t "branding.support" (link (print "mailto:" .SupportEmail) .SupportEmail)}}</p>
+    {{- end}}
   </div>
 </body>
 </html>
 `
 
 // Template text content - Order Delivered
-const orderDeliveredText = `Your order has been delivered!
+const orderDeliveredText = `{{t "email.delivered.text_intro"}}
 
-Order Number: {{.OrderNumber}}
-Delivered Date: {{.OrderDate}}
+{{t "email.order_number"}}: {{.OrderNumber}}
+{{t "email.delivered.date"}}: {{.OrderDate}}
 
-Your package should have arrived at:
+{{t "email.delivered.arrived_at"}}
 {{.ShippingAddress}}
 
-We hope you enjoy your purchase! If you have any questions or concerns, please don't hesitate to reach out.
+{{t "email.delivered.enjoy"}}
 
-Thank you for shopping with {{.ShopName}}!
+{{t "email.thanks_for_shopping" .ShopName}}!
 {{.ShopURL}}
+{{- if .SupportEmail}}
+{{t "branding.support" .SupportEmail}}
+{{- end}}
 `
 
 // Template HTML content - Order Delivered
@@ -381,10 +644,10 @@ const orderDeliveredHTML = `<!DOCTYPE html>
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>Order Delivered</title>
+  <title>{{t "email.delivered.title"}}</title>
   <style>
     body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
-    .header { background: #7c3aed; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .header { background: {{or .AccentColor "#7c3aed"}}; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
     .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
     .delivered-badge { background: #7c3aed; color: white; padding: 20px; text-align: center; border-radius: 8px; margin: 15px 0; font-size: 48px; }
     .footer { text-align: center; padding: 20px; color: #6b7280; font-size: 14px; }
@@ -392,21 +655,408 @@ const orderDeliveredHTML = `<!DOCTYPE html>
 </head>
 <body>
   <div class="header">
-    <h1>Your Order Has Been Delivered! 🎉</h1>
-    <p>Your package has arrived, {{.CustomerName}}!</p>
+    {{- if .LogoURL}}
+    <img src="{{.LogoURL}}" alt="" style="max-height: 48px; margin-bottom: 8px;">
+    {{- end}}
+    <h1>{{t "email.delivered.heading"}}</h1>
+    <p>{{t "email.delivered.intro" .CustomerName}}</p>
   </div>
   <div class="content">
     <div class="delivered-badge">✓</div>
-    <p><strong>Order Number:</strong> {{.OrderNumber}}</p>
-    <p><strong>Delivered Date:</strong> {{.OrderDate}}</p>
+    <p><strong>{{t "email.order_number"}}:</strong> {{.OrderNumber}}</p>
+    <p><strong>{{t "email.delivered.date"}}:</strong> {{.OrderDate}}</p>
 
-    <h3>Delivered To</h3>
+    <h3>{{t "email.delivered.to"}}</h3>
     <p>{{.ShippingAddress}}</p>
 
-    <p>We hope you enjoy your purchase! If you have any questions or concerns about your order, please don't hesitate to reach out.</p>
+    <p>{{t "email.delivered.enjoy_order"}}</p>
+  </div>
+  <div class="footer">
+    <p>{{t "email.thanks_for_shopping" (link .ShopURL .ShopName)}}</p>
+    {{- if .SupportEmail}}
+    <p>{{t "branding.support" (link (print "mailto:" .SupportEmail) .SupportEmail)}}</p>
+    {{- end}}
+  </div>
+</body>
+</html>
+`
+
+// Template text content - Order Returned
+const orderReturnedText = `{{t "email.returned.text_intro"}}
+
+{{t "email.order_number"}}: {{.OrderNumber}}
+{{t "email.product"}}: {{.ProductName}}
+
+{{if .Refunded}}{{t "email.returned.refunded" .Total}}{{else}}{{t "email.returned.follow_up"}}{{end}}
+
+{{if .IssueURL}}{{t "email.order_issue"}}: {{.IssueURL}}{{end}}
+
+{{t "email.thanks_for_shopping" .ShopName}}!
+{{.ShopURL}}
+{{- if .SupportEmail}}
+{{t "branding.support" .SupportEmail}}
+{{- end}}
+`
+
+// Template HTML content - Order Returned
+const orderReturnedHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>{{t "email.returned.title"}}</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+    .header { background: {{or .AccentColor "#f97316"}}; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
+    .footer { text-align: center; padding: 20px; color: #6b7280; font-size: 14px; }
+  </style>
+</head>
+<body>
+  <div class="header">
+    {{- if .LogoURL}}
+    <img src="{{.LogoURL}}" alt="" style="max-height: 48px; margin-bottom: 8px;">
+    {{- end}}
+    <h1>{{t "email.returned.heading"}}</h1>
+    <p>{{t "email.returned.intro" .CustomerName}}</p>
+  </div>
+  <div class="content">
+    <p><strong>{{t "email.order_number"}}:</strong> {{.OrderNumber}}</p>
+    <p><strong>{{t "email.product"}}:</strong> {{.ProductName}}</p>
+    {{if .Refunded}}
+    <p>{{t "email.returned.refunded" (printf "<strong>%s</strong>" .Total)}}</p>
+    {{else}}
+    <p>{{t "email.returned.follow_up"}}</p>
+    {{end}}
+    {{if .IssueURL}}<p><a href="{{.IssueURL}}">{{t "email.returned.view_issue"}}</a></p>{{end}}
+  </div>
+  <div class="footer">
+    <p>{{t "email.thanks_for_shopping" (link .ShopURL .ShopName)}}</p>
+    {{- if .SupportEmail}}
+    <p>{{t "branding.support" (link (print "mailto:" .SupportEmail) .SupportEmail)}}</p>
+    {{- end}}
+  </div>
+</body>
+</html>
+`
+
+// Template text content - New Order (sent to the seller)
+const newOrderText = `You have a new paid order!
+
+Order Number: {{.OrderNumber}}
+Order Date: {{.OrderDate}}
+
+Items:
+{{range .Items}}
+- {{.Name}} ({{.SKU}}){{if .Options}} ({{.Options}}){{end}} x{{.Quantity}} - {{.TotalPrice}}
+{{end}}
+
+Subtotal: {{.Subtotal}}
+Shipping: {{.Shipping}}
+Tax: {{.Tax}}
+Total: {{.Total}}
+
+Customer: {{.CustomerName}}{{if .CustomerEmail}} <{{.CustomerEmail}}>{{end}}
+{{if .PreviousOrders}}Returning customer ({{.PreviousOrders}} previous order{{if gt .PreviousOrders 1}}s{{end}})
+{{end}}{{if .TaxID}}Tax ID: {{.TaxID}}
+{{end}}{{range .CustomFields}}{{.Label}}: {{.Value}}
+{{end}}
+Ship To:
+{{.ShippingAddress}}
+
+{{if .DashboardURL}}Ship this order: {{.DashboardURL}}{{end}}
+{{if .IssueURL}}Order Issue: {{.IssueURL}}{{end}}
+`
+
+// Template HTML content - New Order (sent to the seller)
+const newOrderHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>New Order</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+    .header { background: #111827; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
+    .order-info { background: white; padding: 15px; border-radius: 6px; margin: 15px 0; }
+    .items-table { width: 100%; border-collapse: collapse; margin: 15px 0; }
+    .items-table th { text-align: left; padding: 10px; background: #f3f4f6; border-bottom: 2px solid #e5e7eb; }
+    .items-table td { padding: 10px; border-bottom: 1px solid #e5e7eb; }
+    .total { font-size: 18px; font-weight: bold; text-align: right; padding: 15px 0; }
+    .button { display: inline-block; background: #111827; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
+  </style>
+</head>
+<body>
+  <div class="header">
+    <h1>New Order {{.OrderNumber}}</h1>
+    <p>Payment received for {{.ShopName}}</p>
+  </div>
+  <div class="content">
+    <div class="order-info">
+      <strong>Order Number:</strong> {{.OrderNumber}}<br>
+      <strong>Order Date:</strong> {{.OrderDate}}<br>
+      <strong>Customer:</strong> {{.CustomerName}}{{if .CustomerEmail}} &lt;{{.CustomerEmail}}&gt;{{end}}
+      {{- if .PreviousOrders}}<br>
+      <strong>Returning customer:</strong> {{.PreviousOrders}} previous order{{if gt .PreviousOrders 1}}s{{end}}
+      {{- end}}
+      {{- if .TaxID}}<br>
+      <strong>Tax ID:</strong> {{html .TaxID}}
+      {{- end}}
+      {{- range .CustomFields}}<br>
+      <strong>{{html .Label}}:</strong> {{html .Value}}
+      {{- end}}
+    </div>
+
+    <table class="items-table">
+      <thead>
+        <tr>
+          <th>Item</th>
+          <th>Qty</th>
+          <th>Price</th>
+        </tr>
+      </thead>
+      <tbody>
+        {{range .Items}}
+        <tr>
+          <td>{{.Name}} <small>({{.SKU}})</small>{{if .Options}} <br><small>{{.Options}}</small>{{end}}</td>
+          <td>{{.Quantity}}</td>
+          <td>{{.TotalPrice}}</td>
+        </tr>
+        {{end}}
+      </tbody>
+    </table>
+
+    <div class="total">
+      <p>Subtotal: {{.Subtotal}}</p>
+      <p>Shipping: {{.Shipping}}</p>
+      <p>Tax: {{.Tax}}</p>
+      <p>Total: {{.Total}}</p>
+    </div>
+
+    <h3>Ship To</h3>
+    <p>{{if .ShippingAddressHTML}}{{.ShippingAddressHTML}}{{else}}{{.ShippingAddress}}{{end}}</p>
+
+    {{if .DashboardURL}}<p><a href="{{.DashboardURL}}" class="button">Ship this order</a></p>{{end}}
+    {{if .IssueURL}}<p><a href="{{.IssueURL}}">View the GitHub order issue</a></p>{{end}}
+  </div>
+</body>
+</html>
+`
+
+// Template text content - First Order (sent to the seller once, alongside the new order email)
+const firstOrderText = `Congratulations on your first sale!
+
+{{.ShopName}} just received its first paid order, {{.OrderNumber}}, for {{.Total}}. The buyer has been emailed a confirmation and the order issue is labeled paid.
+
+Here's how to fulfill it:
+
+1. Pack and ship the items.
+{{range .Items}}   - {{.Name}} ({{.SKU}}){{if .Options}} ({{.Options}}){{end}} x{{.Quantity}}
+{{end}}
+2. Mark the order shipped from your dashboard and add the tracking number. GitShop emails the buyer and updates the order issue.
+3. Mark it delivered once it arrives.
+4. Can't fulfill it? Refund it from the same page. The payment is refunded in Stripe and the order issue says so.
+
+{{if .DashboardURL}}Open the order: {{.DashboardURL}}{{end}}
+{{if .IssueURL}}Order Issue: {{.IssueURL}}{{end}}
+`
+
+// Template HTML content - First Order (sent to the seller once, alongside the new order email)
+const firstOrderHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>Your First Order</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+    .header { background: #111827; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
+    .checklist { background: white; padding: 15px 15px 15px 35px; border-radius: 6px; margin: 15px 0; }
+    .checklist li { margin-bottom: 8px; }
+    .button { display: inline-block; background: #111827; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
+  </style>
+</head>
+<body>
+  <div class="header">
+    <h1>🎉 Your first order!</h1>
+    <p>{{.ShopName}} made its first sale</p>
+  </div>
+  <div class="content">
+    <p>Order <strong>{{.OrderNumber}}</strong> was paid for <strong>{{.Total}}</strong>. The buyer has been emailed a confirmation and the order issue is labeled paid.</p>
+
+    <h3>Fulfillment checklist</h3>
+    <ol class="checklist">
+      <li>Pack and ship the items:
+        <ul>
+          {{range .Items}}<li>{{.Name}} <small>({{.SKU}})</small>{{if .Options}} <small>{{.Options}}</small>{{end}} x{{.Quantity}}</li>{{end}}
+        </ul>
+      </li>
+      <li>Mark the order <strong>shipped</strong> from your dashboard and add the tracking number. GitShop emails the buyer and updates the order issue.</li>
+      <li>Mark it <strong>delivered</strong> once it arrives.</li>
+      <li>Can't fulfill it? <strong>Refund</strong> it from the same page. The payment is refunded in Stripe and the order issue says so.</li>
+    </ol>
+
+    {{if .DashboardURL}}<p><a href="{{.DashboardURL}}" class="button">Open the order</a></p>{{end}}
+    {{if .IssueURL}}<p><a href="{{.IssueURL}}">View the GitHub order issue</a></p>{{end}}
+  </div>
+</body>
+</html>
+`
+
+// Template text content - Checkout Reminder (sent to the buyer shortly before the checkout link expires)
+const checkoutReminderText = `{{t "email.reminder.text_intro" .OrderNumber}}
+
+{{t "email.product"}}: {{.ProductName}}
+{{t "email.total"}}: {{.Total}}{{if .TotalEstimate}} ({{t "email.total_estimate" .TotalEstimate}}){{end}}
+
+{{t "email.reminder.body"}}
+
+{{if .IssueURL}}{{t "email.order_issue"}}: {{.IssueURL}}{{end}}
+
+{{t "email.thanks_for_shopping" .ShopName}}!
+{{.ShopURL}}
+{{- if .SupportEmail}}
+{{t "branding.support" .SupportEmail}}
+{{- end}}
+`
+
+// Template HTML content - Checkout Reminder (sent to the buyer shortly before the checkout link expires)
+const checkoutReminderHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>{{t "email.reminder.title"}}</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+    .header { background: {{or .AccentColor "#d97706"}}; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
+    .button { display: inline-block; background: {{or .AccentColor "#111827"}}; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
+    .footer { text-align: center; padding: 20px; color: #6b7280; font-size: 14px; }
+  </style>
+</head>
+<body>
+  <div class="header">
+    {{- if .LogoURL}}
+    <img src="{{.LogoURL}}" alt="" style="max-height: 48px; margin-bottom: 8px;">
+    {{- end}}
+    <h1>{{t "email.reminder.heading"}}</h1>
+    <p>{{t "email.reminder.order" .OrderNumber}}</p>
+  </div>
+  <div class="content">
+    <p><strong>{{t "email.product"}}:</strong> {{.ProductName}}</p>
+    <p><strong>{{t "email.total"}}:</strong> {{.Total}}{{if .TotalEstimate}} ({{t "email.total_estimate" .TotalEstimate}}){{end}}</p>
+
+    <p>{{t "email.reminder.body"}}</p>
+
+    {{if .IssueURL}}<p><a href="{{.IssueURL}}" class="button">{{t "email.reminder.open_order"}}</a></p>{{end}}
+  </div>
+  <div class="footer">
+    <p>{{t "email.thanks_for_shopping" (link .ShopURL .ShopName)}}</p>
+    {{- if .SupportEmail}}
+    <p>{{t "branding.support" (link (print "mailto:" .SupportEmail) .SupportEmail)}}</p>
+    {{- end}}
+  </div>
+</body>
+</html>
+`
+
+// Template text content - Stripe Account Restricted (sent to the seller when Stripe turns off a capability)
+const stripeAccountRestrictedText = `Stripe has restricted the account behind {{.ShopName}}.
+
+{{if .ChargesPaused}}Charges are turned off, so GitShop has paused new orders. Buyers who open an order issue are told the shop can't take payments right now.
+{{end}}{{if .PayoutsPaused}}Payouts are turned off, so Stripe is holding your balance.
+{{end}}
+This usually means Stripe needs more information to verify your account. Sign in to Stripe and complete any outstanding requirements. GitShop picks up the change automatically{{if .ChargesPaused}} and starts taking orders again{{end}}.
+
+{{if .DashboardURL}}Open your shop settings: {{.DashboardURL}}{{end}}
+`
+
+// Template HTML content - Stripe Account Restricted (sent to the seller when Stripe turns off a capability)
+const stripeAccountRestrictedHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>Stripe Account Restricted</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+    .header { background: #b91c1c; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
+    .notice { background: white; padding: 15px 15px 15px 35px; border-radius: 6px; margin: 15px 0; }
+    .button { display: inline-block; background: #111827; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
+  </style>
+</head>
+<body>
+  <div class="header">
+    <h1>Action needed</h1>
+    <p>Stripe has restricted the account behind {{.ShopName}}</p>
+  </div>
+  <div class="content">
+    <ul class="notice">
+      {{if .ChargesPaused}}<li><strong>Charges are turned off</strong>, so GitShop has paused new orders. Buyers who open an order issue are told the shop can't take payments right now.</li>{{end}}
+      {{if .PayoutsPaused}}<li><strong>Payouts are turned off</strong>, so Stripe is holding your balance.</li>{{end}}
+    </ul>
+    <p>This usually means Stripe needs more information to verify your account. Sign in to Stripe and complete any outstanding requirements. GitShop picks up the change automatically{{if .ChargesPaused}} and starts taking orders again{{end}}.</p>
+
+    {{if .DashboardURL}}<p><a href="{{.DashboardURL}}" class="button">Open shop settings</a></p>{{end}}
+  </div>
+</body>
+</html>
+`
+
+// Template text content - Overdue Shipments (daily digest sent to the seller about paid orders past the shipping window)
+const overdueShipmentsText = `{{.OverdueCount}} paid order(s) in {{.ShopName}} have waited more than {{.ShipWithinDays}} day(s) to ship.
+
+{{range .OverdueOrders}}- {{.OrderNumber}}: {{.Total}}, paid {{.PaidDate}} ({{.DaysWaiting}} days ago){{if .URL}}
+  Ship it: {{.URL}}{{end}}
+{{end}}{{if gt .OverdueCount (len .OverdueOrders)}}...and {{.OverdueCount}} in total.
+{{end}}
+Mark each order shipped once it is on its way; GitShop emails the buyer their tracking details. You get this reminder once a day while orders are overdue. Change the window with shipping.ship_within_days in gitshop.yaml, or set it to 0 to turn these reminders off.
+
+{{if .DashboardURL}}Open your dashboard: {{.DashboardURL}}{{end}}
+`
+
+// Template HTML content - Overdue Shipments (daily digest sent to the seller about paid orders past the shipping window)
+const overdueShipmentsHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1.0">
+  <title>Orders Waiting to Ship</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; line-height: 1.6; color: #333; max-width: 600px; margin: 0 auto; padding: 20px; }
+    .header { background: #b45309; color: white; padding: 20px; text-align: center; border-radius: 8px 8px 0 0; }
+    .content { background: #f9fafb; padding: 20px; border: 1px solid #e5e7eb; }
+    .orders { background: white; padding: 15px; border-radius: 6px; margin: 15px 0; }
+    .order { display: flex; justify-content: space-between; padding: 8px 0; border-bottom: 1px solid #e5e7eb; }
+    .button { display: inline-block; background: #111827; color: white; padding: 12px 24px; text-decoration: none; border-radius: 6px; margin-top: 15px; }
+    .footer { text-align: center; padding: 20px; color: #6b7280; font-size: 14px; }
+  </style>
+</head>
+<body>
+  <div class="header">
+    <h1>Orders waiting to ship</h1>
+    <p>{{.OverdueCount}} paid order(s) in {{.ShopName}} have waited more than {{.ShipWithinDays}} day(s)</p>
+  </div>
+  <div class="content">
+    <div class="orders">
+      {{range .OverdueOrders}}
+      <div class="order">
+        <span>{{if .URL}}<a href="{{.URL}}">{{.OrderNumber}}</a>{{else}}{{.OrderNumber}}{{end}} &middot; {{.Total}}</span>
+        <span>paid {{.PaidDate}} ({{.DaysWaiting}} days ago)</span>
+      </div>
+      {{end}}
+      {{if gt .OverdueCount (len .OverdueOrders)}}<p>...and {{.OverdueCount}} in total.</p>{{end}}
+    </div>
+    <p>Mark each order shipped once it is on its way; GitShop emails the buyer their tracking details.</p>
+
+    {{if .DashboardURL}}<p><a href="{{.DashboardURL}}" class="button">Open dashboard</a></p>{{end}}
   </div>
   <div class="footer">
-    <p>Thank you for shopping with <a href="{{.ShopURL}}">{{.ShopName}}</a></p>
+    <p>You get this reminder once a day while orders are overdue. Change the window with <code>shipping.ship_within_days</code> in gitshop.yaml, or set it to 0 to turn these reminders off.</p>
   </div>
 </body>
 </html>
@@ -0,0 +1,249 @@
+// Package featureflags decides which shops get features that are still rolling out. Defaults
+// come from the FEATURE_FLAGS setting and operators override them in the database, globally or
+// for a single shop.
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Flag string
+
+const (
+	// Subscriptions lets a shop take orders for products with billing set.
+	Subscriptions Flag = "subscriptions"
+	// WebhookTap serves the operator webhook stream at /admin/debug/webhook-tap. It is not tied
+	// to a shop, so only its global rule counts.
+	WebhookTap Flag = "webhook_tap"
+)
+
+// Known lists every flag the code checks, in the order the operator page shows them. A new flag
+// is added here and checked with Enabled where its feature is entered.
+var Known = []Flag{Subscriptions, WebhookTap}
+
+// overrideTTL bounds how stale database overrides may be on an instance.
+const overrideTTL = 30 * time.Second
+
+// Rule says which shops get a flag. Enabled turns it on for every shop; otherwise Percent of
+// shops get it, chosen by a stable hash of the flag and shop ID.
+type Rule struct {
+	Enabled bool
+	Percent int
+}
+
+// includes reports whether the rule turns the flag on for the shop.
+func (r Rule) includes(flag Flag, shopID uuid.UUID) bool {
+	if r.Enabled || r.Percent >= 100 {
+		return true
+	}
+	if r.Percent <= 0 {
+		return false
+	}
+	return bucket(flag, shopID) < r.Percent
+}
+
+func (r Rule) String() string {
+	switch {
+	case r.Enabled || r.Percent >= 100:
+		return "on"
+	case r.Percent <= 0:
+		return "off"
+	default:
+		return strconv.Itoa(r.Percent) + "%"
+	}
+}
+
+// bucket places a shop in 0-99 for a flag. Hashing the flag with the shop spreads each flag's
+// rollout over different shops.
+func bucket(flag Flag, shopID uuid.UUID) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(flag))
+	hash.Write(shopID[:])
+	return int(hash.Sum32() % 100)
+}
+
+// ParseRules reads FEATURE_FLAGS values: "on", "off", or a percentage such as "25%".
+func ParseRules(raw map[string]string) (map[Flag]Rule, error) {
+	return parseRules(raw, Known)
+}
+
+func parseRules(raw map[string]string, known []Flag) (map[Flag]Rule, error) {
+	rules := make(map[Flag]Rule, len(raw))
+	for name, value := range raw {
+		flag := Flag(strings.TrimSpace(name))
+		if !slices.Contains(known, flag) {
+			return nil, fmt.Errorf("unknown feature flag %q", name)
+		}
+		rule, err := parseRule(value)
+		if err != nil {
+			return nil, fmt.Errorf("feature flag %q: %w", name, err)
+		}
+		rules[flag] = rule
+	}
+	return rules, nil
+}
+
+func parseRule(value string) (Rule, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "on", "true":
+		return Rule{Enabled: true}, nil
+	case "off", "false", "":
+		return Rule{}, nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || !strings.HasSuffix(value, "%") || percent < 0 || percent > 100 {
+		return Rule{}, fmt.Errorf("want on, off, or a percentage from 0%% to 100%%, got %q", value)
+	}
+	return Rule{Percent: percent}, nil
+}
+
+// Overrides are the operator-set rules stored in the database. Per-shop entries win over Global,
+// which wins over the configured defaults.
+type Overrides struct {
+	Global map[Flag]Rule
+	Shops  map[Flag]map[uuid.UUID]bool
+}
+
+// Store loads the database overrides.
+type Store interface {
+	FeatureFlagOverrides(ctx context.Context) (Overrides, error)
+}
+
+// Flags answers flag checks. A nil *Flags reports every flag off.
+type Flags struct {
+	defaults map[Flag]Rule
+	store    Store
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu        sync.Mutex
+	overrides Overrides
+	loadedAt  time.Time
+}
+
+func New(defaults map[Flag]Rule, store Store, logger *slog.Logger) *Flags {
+	return &Flags{
+		defaults: defaults,
+		store:    store,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Enabled reports whether the flag is on for the shop. When overrides cannot be loaded the last
+// loaded overrides are kept, so a database blip does not flip features.
+func (f *Flags) Enabled(ctx context.Context, flag Flag, shopID uuid.UUID) bool {
+	if f == nil {
+		return false
+	}
+	overrides := f.load(ctx)
+	if enabled, ok := overrides.Shops[flag][shopID]; ok {
+		return enabled
+	}
+	return f.rule(overrides, flag).includes(flag, shopID)
+}
+
+// EnabledEverywhere reports whether the flag is on for every shop, for features that are not
+// scoped to one shop. Percentage rollouts and per-shop overrides leave it off.
+func (f *Flags) EnabledEverywhere(ctx context.Context, flag Flag) bool {
+	if f == nil {
+		return false
+	}
+	rule := f.rule(f.load(ctx), flag)
+	return rule.Enabled || rule.Percent >= 100
+}
+
+func (f *Flags) rule(overrides Overrides, flag Flag) Rule {
+	if rule, ok := overrides.Global[flag]; ok {
+		return rule
+	}
+	return f.defaults[flag]
+}
+
+// State is how one flag is rolled out, for the operator page.
+type State struct {
+	Flag Flag
+	// Rule is the effective rule for shops without their own override.
+	Rule Rule
+	// Overridden is set when the database replaces the configured default.
+	Overridden bool
+	Default    Rule
+	ShopsOn    int
+	ShopsOff   int
+}
+
+// States describes every known flag.
+func (f *Flags) States(ctx context.Context) []State {
+	states := make([]State, 0, len(Known))
+	if f == nil {
+		for _, flag := range Known {
+			states = append(states, State{Flag: flag})
+		}
+		return states
+	}
+	overrides := f.load(ctx)
+	for _, flag := range Known {
+		_, overridden := overrides.Global[flag]
+		state := State{
+			Flag:       flag,
+			Rule:       f.rule(overrides, flag),
+			Overridden: overridden,
+			Default:    f.defaults[flag],
+		}
+		for _, enabled := range overrides.Shops[flag] {
+			if enabled {
+				state.ShopsOn++
+			} else {
+				state.ShopsOff++
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// ShopFlag is whether one flag is on for a shop.
+type ShopFlag struct {
+	Flag    Flag
+	Enabled bool
+}
+
+// ForShop reports every known flag for the shop.
+func (f *Flags) ForShop(ctx context.Context, shopID uuid.UUID) []ShopFlag {
+	flags := make([]ShopFlag, 0, len(Known))
+	for _, flag := range Known {
+		flags = append(flags, ShopFlag{Flag: flag, Enabled: f.Enabled(ctx, flag, shopID)})
+	}
+	return flags
+}
+
+func (f *Flags) load(ctx context.Context) Overrides {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.store == nil || (!f.loadedAt.IsZero() && f.now().Sub(f.loadedAt) < overrideTTL) {
+		return f.overrides
+	}
+	overrides, err := f.store.FeatureFlagOverrides(ctx)
+	// Retry a failed load on the next TTL rather than on every check.
+	f.loadedAt = f.now()
+	if err != nil {
+		if f.logger != nil {
+			f.logger.Warn("failed to load feature flag overrides", "error", err)
+		}
+		return f.overrides
+	}
+	f.overrides = overrides
+	return f.overrides
+}
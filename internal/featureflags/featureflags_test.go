@@ -0,0 +1,158 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeStore struct {
+	overrides Overrides
+	err       error
+	loads     int
+}
+
+func (s *fakeStore) FeatureFlagOverrides(context.Context) (Overrides, error) {
+	s.loads++
+	return s.overrides, s.err
+}
+
+const (
+	newCheckout Flag = "new_checkout"
+	bulkEdit    Flag = "bulk_edit"
+	darkMode    Flag = "dark_mode"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	known := []Flag{newCheckout, bulkEdit, darkMode}
+	rules, err := parseRules(map[string]string{"new_checkout": "on", "bulk_edit": "25%", "dark_mode": "off"}, known)
+	if err != nil {
+		t.Fatalf("parseRules() error = %v", err)
+	}
+	want := map[Flag]Rule{newCheckout: {Enabled: true}, bulkEdit: {Percent: 25}, darkMode: {}}
+	for flag, rule := range want {
+		if rules[flag] != rule {
+			t.Fatalf("rules[%s] = %+v, want %+v", flag, rules[flag], rule)
+		}
+	}
+
+	for _, raw := range []map[string]string{{"nope": "on"}, {"new_checkout": "25"}, {"new_checkout": "101%"}, {"new_checkout": "maybe"}} {
+		if _, err := parseRules(raw, known); err == nil {
+			t.Fatalf("parseRules(%v) succeeded, want an error", raw)
+		}
+	}
+	if rules, err := ParseRules(map[string]string{"subscriptions": "on"}); err != nil || !rules[Subscriptions].Enabled {
+		t.Fatalf("ParseRules(subscriptions) = %v, %v, want the flag on", rules, err)
+	}
+	if _, err := ParseRules(map[string]string{"tax": "on"}); err == nil {
+		t.Fatal("ParseRules accepted a flag the code does not check")
+	}
+}
+
+func TestFlagsEnabledPrecedence(t *testing.T) {
+	t.Parallel()
+
+	shopOn, shopOff, other := uuid.New(), uuid.New(), uuid.New()
+	store := &fakeStore{overrides: Overrides{
+		Global: map[Flag]Rule{newCheckout: {}},
+		Shops:  map[Flag]map[uuid.UUID]bool{newCheckout: {shopOn: true}, bulkEdit: {shopOff: false}},
+	}}
+	flags := New(map[Flag]Rule{newCheckout: {Enabled: true}, bulkEdit: {Enabled: true}}, store, nil)
+
+	tests := []struct {
+		name   string
+		flag   Flag
+		shopID uuid.UUID
+		want   bool
+	}{
+		{name: "shop override on", flag: newCheckout, shopID: shopOn, want: true},
+		{name: "global override beats default", flag: newCheckout, shopID: other, want: false},
+		{name: "shop override off", flag: bulkEdit, shopID: shopOff, want: false},
+		{name: "default applies", flag: bulkEdit, shopID: other, want: true},
+		{name: "unset flag is off", flag: darkMode, shopID: other, want: false},
+	}
+	for _, tt := range tests {
+		if got := flags.Enabled(t.Context(), tt.flag, tt.shopID); got != tt.want {
+			t.Fatalf("%s: Enabled() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if store.loads != 1 {
+		t.Fatalf("overrides loaded %d times, want once within the TTL", store.loads)
+	}
+
+	var nilFlags *Flags
+	if nilFlags.Enabled(t.Context(), newCheckout, other) {
+		t.Fatal("nil Flags reported a flag on")
+	}
+}
+
+func TestFlagsPercentRolloutIsStable(t *testing.T) {
+	t.Parallel()
+
+	flags := New(map[Flag]Rule{newCheckout: {Percent: 30}}, nil, nil)
+	enabled := 0
+	for range 2000 {
+		shopID := uuid.New()
+		on := flags.Enabled(t.Context(), newCheckout, shopID)
+		if on != flags.Enabled(t.Context(), newCheckout, shopID) {
+			t.Fatal("rollout changed between checks for the same shop")
+		}
+		if on {
+			enabled++
+		}
+	}
+	if enabled < 500 || enabled > 700 {
+		t.Fatalf("%d of 2000 shops enabled at 30%%, want about 600", enabled)
+	}
+}
+
+func TestFlagsEnabledEverywhere(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeStore{overrides: Overrides{
+		Global: map[Flag]Rule{bulkEdit: {}},
+		Shops:  map[Flag]map[uuid.UUID]bool{darkMode: {uuid.New(): true}},
+	}}
+	flags := New(map[Flag]Rule{newCheckout: {Enabled: true}, bulkEdit: {Enabled: true}, darkMode: {Percent: 99}}, store, nil)
+
+	tests := []struct {
+		flag Flag
+		want bool
+	}{
+		{flag: newCheckout, want: true},
+		{flag: bulkEdit, want: false},
+		{flag: darkMode, want: false},
+	}
+	for _, tt := range tests {
+		if got := flags.EnabledEverywhere(t.Context(), tt.flag); got != tt.want {
+			t.Fatalf("EnabledEverywhere(%s) = %v, want %v", tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestFlagsKeepOverridesWhenReloadFails(t *testing.T) {
+	t.Parallel()
+
+	shopID := uuid.New()
+	now := time.Now()
+	store := &fakeStore{overrides: Overrides{Global: map[Flag]Rule{newCheckout: {Enabled: true}}}}
+	flags := New(nil, store, nil)
+	flags.now = func() time.Time { return now }
+
+	if !flags.Enabled(t.Context(), newCheckout, shopID) {
+		t.Fatal("Enabled() = false, want the global override")
+	}
+	store.overrides, store.err = Overrides{}, errors.New("database down")
+	now = now.Add(overrideTTL)
+	if !flags.Enabled(t.Context(), newCheckout, shopID) {
+		t.Fatal("Enabled() = false after a failed reload, want the last overrides")
+	}
+	if store.loads != 2 {
+		t.Fatalf("overrides loaded %d times, want a reload after the TTL", store.loads)
+	}
+}
@@ -0,0 +1,47 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+)
+
+// rateCacheTTL keeps a day's rates until the next day's are published.
+const rateCacheTTL = 24 * time.Hour
+
+// CachedSource keeps each day's rates from source in a cache, so every order placed that day is
+// estimated with the same rates and the provider is asked about once a day.
+type CachedSource struct {
+	source RateSource
+	cache  cache.Provider
+	now    func() time.Time
+}
+
+func NewCachedSource(source RateSource, cacheProvider cache.Provider) *CachedSource {
+	return &CachedSource{source: source, cache: cacheProvider, now: time.Now}
+}
+
+func (s *CachedSource) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	if s.cache == nil {
+		return s.source.Rates(ctx, base)
+	}
+	key := "fx:rates:" + base + ":" + s.now().UTC().Format(time.DateOnly)
+	if cached, err := s.cache.Get(ctx, key); err == nil && cached != "" {
+		var rates map[string]float64
+		if json.Unmarshal([]byte(cached), &rates) == nil {
+			return rates, nil
+		}
+	}
+
+	rates, err := s.source.Rates(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	if encoded, err := json.Marshal(rates); err == nil {
+		// A cache that cannot be written only costs another lookup.
+		_ = s.cache.Set(ctx, key, string(encoded), rateCacheTTL)
+	}
+	return rates, nil
+}
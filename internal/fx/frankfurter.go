@@ -0,0 +1,72 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const defaultFrankfurterBaseURL = "https://api.frankfurter.app"
+
+// FrankfurterSource reads the European Central Bank's daily reference rates from the Frankfurter
+// API, which needs no key. The rates cover about 30 major currencies and change once a day.
+type FrankfurterSource struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewFrankfurterSource() *FrankfurterSource {
+	return NewFrankfurterSourceWithBaseURL(defaultFrankfurterBaseURL)
+}
+
+func NewFrankfurterSourceWithBaseURL(baseURL string) *FrankfurterSource {
+	return &FrankfurterSource{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: observability.NewHTTPClient(10 * time.Second),
+	}
+}
+
+type frankfurterResponse struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func (s *FrankfurterSource) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	endpoint := s.baseURL + "/latest?from=" + url.QueryEscape(strings.ToUpper(base))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read response: %w", readErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("frankfurter API returned status %d", resp.StatusCode)
+	}
+
+	var parsed frankfurterResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	rates := make(map[string]float64, len(parsed.Rates))
+	for code, rate := range parsed.Rates {
+		rates[strings.ToLower(code)] = rate
+	}
+	return rates, nil
+}
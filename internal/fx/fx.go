@@ -0,0 +1,103 @@
+// Package fx estimates what an amount is worth in another currency, for buyers who think in
+// their own. Estimates are only shown; orders are always charged in the shop's currency.
+package fx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+)
+
+// RateSource looks up exchange rates. Rates returns how much one unit of base buys in each
+// currency it knows, keyed by lowercase ISO code.
+type RateSource interface {
+	Rates(ctx context.Context, base string) (map[string]float64, error)
+}
+
+type Config struct {
+	Provider string
+	BaseURL  string
+}
+
+// NewRateSource returns the configured rate source, cached for a day in cacheProvider, or nil
+// when estimates are off.
+func NewRateSource(cfg Config, cacheProvider cache.Provider) (RateSource, error) {
+	switch cfg.Provider {
+	case "none", "":
+		return nil, nil
+	case "frankfurter":
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = defaultFrankfurterBaseURL
+		}
+		return NewCachedSource(NewFrankfurterSourceWithBaseURL(baseURL), cacheProvider), nil
+	default:
+		return nil, fmt.Errorf("unsupported exchange rate provider: %s", cfg.Provider)
+	}
+}
+
+// zeroDecimalCurrencies are charged in whole units, with no minor unit.
+var zeroDecimalCurrencies = map[string]bool{
+	"bif": true, "clp": true, "djf": true, "gnf": true, "jpy": true, "kmf": true, "krw": true,
+	"mga": true, "pyg": true, "rwf": true, "ugx": true, "vnd": true, "vuv": true, "xaf": true,
+	"xof": true, "xpf": true,
+}
+
+func minorUnitsPerUnit(currency string) float64 {
+	if zeroDecimalCurrencies[currency] {
+		return 1
+	}
+	return 100
+}
+
+// Convert turns amount, in minor units of from, into minor units of to at rate, rounded half
+// away from zero.
+func Convert(amount int64, from, to string, rate float64) int64 {
+	units := float64(amount) / minorUnitsPerUnit(from) * rate
+	return int64(math.Round(units * minorUnitsPerUnit(to)))
+}
+
+// Format writes an amount in minor units with its currency code, such as "23.10 EUR" or
+// "3,412 JPY".
+func Format(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	code := strings.ToUpper(currency)
+	if zeroDecimalCurrencies[currency] {
+		return sign + groupThousands(amount) + " " + code
+	}
+	return fmt.Sprintf("%s%s.%02d %s", sign, groupThousands(amount/100), amount%100, code)
+}
+
+func groupThousands(value int64) string {
+	digits := strconv.FormatInt(value, 10)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// Estimate converts amount from the shop's currency into to with source's current rate and
+// formats it. It fails when the source has no rate for to.
+func Estimate(ctx context.Context, source RateSource, amount int64, from, to string) (string, error) {
+	rates, err := source.Rates(ctx, from)
+	if err != nil {
+		return "", err
+	}
+	rate, ok := rates[to]
+	if !ok || rate <= 0 {
+		return "", fmt.Errorf("no %s rate for %s", from, to)
+	}
+	return Format(Convert(amount, from, to, rate), to), nil
+}
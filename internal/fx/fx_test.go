@@ -0,0 +1,71 @@
+package fx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+)
+
+func TestConvertAndFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		amount int64
+		to     string
+		rate   float64
+		want   string
+	}{
+		{name: "two decimal currency", amount: 2500, to: "eur", rate: 0.9241, want: "23.10 EUR"},
+		{name: "zero decimal currency", amount: 2500, to: "jpy", rate: 149.37, want: "3,734 JPY"},
+		{name: "thousands separator", amount: 150000, to: "sek", rate: 10.5, want: "15,750.00 SEK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Format(Convert(tt.amount, "usd", tt.to, tt.rate), tt.to); got != tt.want {
+				t.Fatalf("Format(Convert()) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedFrankfurterSource(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/latest" || r.URL.Query().Get("from") != "USD" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"amount":1.0,"base":"USD","date":"2026-10-15","rates":{"EUR":0.92,"GBP":0.79}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	memory, err := cache.NewMemoryProvider()
+	if err != nil {
+		t.Fatalf("NewMemoryProvider() error = %v", err)
+	}
+	source := NewCachedSource(NewFrankfurterSourceWithBaseURL(server.URL), memory)
+	for range 2 {
+		estimate, err := Estimate(t.Context(), source, 2500, "usd", "gbp")
+		if err != nil {
+			t.Fatalf("Estimate() error = %v", err)
+		}
+		if estimate != "19.75 GBP" {
+			t.Fatalf("Estimate() = %q, want 19.75 GBP", estimate)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("provider asked %d times, want once per day", got)
+	}
+	if _, err := Estimate(t.Context(), source, 2500, "usd", "mxn"); err == nil {
+		t.Fatal("expected an error for a currency without a rate")
+	}
+}
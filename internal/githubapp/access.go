@@ -0,0 +1,167 @@
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	// ContentAccessFull is Contents write access to the whole repository.
+	ContentAccessFull = "full"
+	// ContentAccessSingleFile is write access to only the files the owner listed when granting
+	// the single file permission.
+	ContentAccessSingleFile = "single_file"
+
+	contentAccessTTL = 5 * time.Minute
+
+	IssueTemplateDir  = ".github/ISSUE_TEMPLATE"
+	OrderTemplatePath = IssueTemplateDir + "/order.yaml"
+)
+
+// ErrContentAccessDenied is returned when the installation may not write the requested path, or
+// may not create the branches a pull request needs.
+var ErrContentAccessDenied = errors.New("github app cannot write this file")
+
+var errProtectedBranchNeedsPR = fmt.Errorf("%w: the default branch is protected and opening a pull request needs full contents access", ErrContentAccessDenied)
+
+// ContentAccess describes which repository files an installation may write.
+type ContentAccess struct {
+	Mode  string
+	Paths []string
+}
+
+type contentAccessEntry struct {
+	access    ContentAccess
+	expiresAt time.Time
+}
+
+func (a ContentAccess) SingleFile() bool {
+	return a.Mode == ContentAccessSingleFile
+}
+
+// CanWrite reports whether path can be created or updated on the default branch.
+func (a ContentAccess) CanWrite(filePath string) bool {
+	if !a.SingleFile() {
+		return true
+	}
+	return slices.Contains(a.Paths, strings.TrimPrefix(filePath, "/"))
+}
+
+// CanOpenPullRequests reports whether branches can be created, which pull requests and commits
+// touching several files need.
+func (a ContentAccess) CanOpenPullRequests() bool {
+	return !a.SingleFile()
+}
+
+// FilesIn returns the granted files directly inside dir. In single file mode directories cannot
+// be listed, so these are the only files GitShop can see there.
+func (a ContentAccess) FilesIn(repoFullName, dir string) []RepoFile {
+	files := []RepoFile{}
+	for _, filePath := range a.Paths {
+		if path.Dir(filePath) != dir {
+			continue
+		}
+		files = append(files, RepoFile{
+			Name:    path.Base(filePath),
+			Path:    filePath,
+			HTMLURL: fmt.Sprintf("https://github.com/%s/blob/HEAD/%s", repoFullName, filePath),
+		})
+	}
+	return files
+}
+
+// OrderTemplatePath returns where a new order template should be written, or "" when no
+// issue template path is writable.
+func (a ContentAccess) OrderTemplatePath() string {
+	if !a.SingleFile() {
+		return OrderTemplatePath
+	}
+	fallback := ""
+	for _, file := range a.FilesIn("", IssueTemplateDir) {
+		ext := strings.ToLower(path.Ext(file.Path))
+		if ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		if file.Path == OrderTemplatePath {
+			return file.Path
+		}
+		if fallback == "" {
+			fallback = file.Path
+		}
+	}
+	return fallback
+}
+
+func contentAccessFromInstallation(installation *github.Installation) ContentAccess {
+	perms := installation.GetPermissions()
+	if perms.GetContents() == "write" || perms.GetSingleFile() != "write" {
+		return ContentAccess{Mode: ContentAccessFull}
+	}
+
+	paths := []string{}
+	for _, filePath := range installation.SingleFilePaths {
+		if filePath = strings.TrimPrefix(strings.TrimSpace(filePath), "/"); filePath != "" {
+			paths = append(paths, filePath)
+		}
+	}
+	if name := strings.TrimPrefix(strings.TrimSpace(installation.GetSingleFileName()), "/"); name != "" && !slices.Contains(paths, name) {
+		paths = append(paths, name)
+	}
+	return ContentAccess{Mode: ContentAccessSingleFile, Paths: paths}
+}
+
+// ContentAccess looks up which files the installation may write. Results are cached for a few
+// minutes; call ForgetContentAccess when the installation's permissions change.
+func (c *Client) ContentAccess(ctx context.Context) (ContentAccess, error) {
+	if access, ok := c.auth.cachedContentAccess(c.installationID); ok {
+		return access, nil
+	}
+
+	appJWT, err := c.auth.CreateJWT()
+	if err != nil {
+		return ContentAccess{}, fmt.Errorf("failed to create JWT: %w", err)
+	}
+	httpClient := observability.NewHTTPClient(15 * time.Second)
+	installation, _, err := github.NewClient(httpClient).WithAuthToken(appJWT).Apps.GetInstallation(ctx, c.installationID)
+	if err != nil {
+		return ContentAccess{}, fmt.Errorf("failed to get installation: %w", err)
+	}
+
+	access := contentAccessFromInstallation(installation)
+	c.auth.cacheContentAccess(c.installationID, access)
+	return access, nil
+}
+
+func (c *Client) ForgetContentAccess(installationID int64) {
+	c.auth.cacheMu.Lock()
+	defer c.auth.cacheMu.Unlock()
+	delete(c.auth.accessCache, installationID)
+}
+
+func (a *Auth) cachedContentAccess(installationID int64) (ContentAccess, bool) {
+	a.cacheMu.RLock()
+	defer a.cacheMu.RUnlock()
+	entry, ok := a.accessCache[installationID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return ContentAccess{}, false
+	}
+	return entry.access, true
+}
+
+func (a *Auth) cacheContentAccess(installationID int64, access ContentAccess) {
+	a.cacheMu.Lock()
+	defer a.cacheMu.Unlock()
+	if a.accessCache == nil {
+		a.accessCache = make(map[int64]*contentAccessEntry)
+	}
+	a.accessCache[installationID] = &contentAccessEntry{access: access, expiresAt: time.Now().Add(contentAccessTTL)}
+}
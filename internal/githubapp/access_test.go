@@ -0,0 +1,87 @@
+package githubapp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestContentAccessFromInstallation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		installation *github.Installation
+		want         ContentAccess
+	}{
+		{
+			name: "contents write",
+			installation: &github.Installation{Permissions: &github.InstallationPermissions{
+				Contents:   github.String("write"),
+				SingleFile: github.String("write"),
+			}},
+			want: ContentAccess{Mode: ContentAccessFull},
+		},
+		{
+			name: "single file paths",
+			installation: &github.Installation{
+				Permissions:     &github.InstallationPermissions{Contents: github.String("read"), SingleFile: github.String("write")},
+				SingleFilePaths: []string{"/gitshop.yaml", ".github/ISSUE_TEMPLATE/order.yml", " "},
+			},
+			want: ContentAccess{Mode: ContentAccessSingleFile, Paths: []string{"gitshop.yaml", ".github/ISSUE_TEMPLATE/order.yml"}},
+		},
+		{
+			name: "legacy single file name",
+			installation: &github.Installation{
+				Permissions:    &github.InstallationPermissions{SingleFile: github.String("write")},
+				SingleFileName: github.String("gitshop.yaml"),
+			},
+			want: ContentAccess{Mode: ContentAccessSingleFile, Paths: []string{"gitshop.yaml"}},
+		},
+		{
+			name:         "no permissions reported",
+			installation: &github.Installation{},
+			want:         ContentAccess{Mode: ContentAccessFull},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := contentAccessFromInstallation(tt.installation); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestContentAccess_Paths(t *testing.T) {
+	t.Parallel()
+
+	full := ContentAccess{Mode: ContentAccessFull}
+	if !full.CanWrite("anything.txt") || !full.CanOpenPullRequests() || full.OrderTemplatePath() != OrderTemplatePath {
+		t.Fatalf("expected full access to write anywhere, got %+v", full)
+	}
+
+	single := ContentAccess{Mode: ContentAccessSingleFile, Paths: []string{"gitshop.yaml", ".github/ISSUE_TEMPLATE/config.md", ".github/ISSUE_TEMPLATE/buy.yml"}}
+	if !single.CanWrite("gitshop.yaml") || single.CanWrite("gitshop/emails/order_shipped.html") {
+		t.Fatal("expected single file access to be limited to granted paths")
+	}
+	if single.CanOpenPullRequests() {
+		t.Fatal("expected single file access not to open pull requests")
+	}
+	if got := single.OrderTemplatePath(); got != ".github/ISSUE_TEMPLATE/buy.yml" {
+		t.Fatalf("expected granted yaml template, got %q", got)
+	}
+	files := single.FilesIn("acme/shop", IssueTemplateDir)
+	if len(files) != 2 || files[1].HTMLURL != "https://github.com/acme/shop/blob/HEAD/.github/ISSUE_TEMPLATE/buy.yml" {
+		t.Fatalf("unexpected files %+v", files)
+	}
+
+	none := ContentAccess{Mode: ContentAccessSingleFile, Paths: []string{"gitshop.yaml"}}
+	if got := none.OrderTemplatePath(); got != "" {
+		t.Fatalf("expected no template path, got %q", got)
+	}
+}
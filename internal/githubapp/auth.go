@@ -7,6 +7,7 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,6 +21,10 @@ import (
 	"github.com/gitshopapp/gitshop/internal/observability"
 )
 
+// ErrInstallationUnavailable means GitHub refused to issue a token because the installation was
+// deleted or suspended.
+var ErrInstallationUnavailable = errors.New("github app installation unavailable")
+
 type tokenCacheEntry struct {
 	token     *oauth2.Token
 	expiresAt time.Time
@@ -30,7 +35,10 @@ type Auth struct {
 	privateKey *rsa.PrivateKey
 	httpClient *http.Client
 	tokenCache map[int64]*tokenCacheEntry
-	cacheMu    sync.RWMutex
+	// accessCache holds each installation's ContentAccess.
+	accessCache map[int64]*contentAccessEntry
+	cacheMu     sync.RWMutex
+	limits      *rateLimits
 }
 
 func NewAuth(appIDStr, privateKeyBase64 string) (*Auth, error) {
@@ -54,6 +62,7 @@ func NewAuth(appIDStr, privateKeyBase64 string) (*Auth, error) {
 		privateKey: privateKey,
 		httpClient: observability.NewHTTPClient(10 * time.Second),
 		tokenCache: make(map[int64]*tokenCacheEntry),
+		limits:     newRateLimits(),
 	}, nil
 }
 
@@ -119,6 +128,9 @@ func (a *Auth) GetInstallationToken(ctx context.Context, installationID int64) (
 		if closeErr != nil {
 			return nil, fmt.Errorf("GitHub API returned status %d (failed to close response body: %w)", resp.StatusCode, closeErr)
 		}
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("%w: GitHub API returned status %d: %s", ErrInstallationUnavailable, resp.StatusCode, string(body))
+		}
 		return nil, fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
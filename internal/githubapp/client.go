@@ -7,18 +7,22 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v66/github"
 	"golang.org/x/oauth2"
 
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/logging"
 	"github.com/gitshopapp/gitshop/internal/observability"
 )
 
 type Client struct {
 	auth           *Auth
 	installationID int64
+	responses      cache.Provider
 	logger         *slog.Logger
 }
 
@@ -29,10 +33,22 @@ func NewClient(auth *Auth, logger *slog.Logger) *Client {
 	}
 }
 
+// WithResponseCache returns a client that revalidates repository file reads against responses
+// kept in provider instead of downloading them again.
+func (c *Client) WithResponseCache(provider cache.Provider) *Client {
+	return &Client{
+		auth:           c.auth,
+		installationID: c.installationID,
+		responses:      provider,
+		logger:         c.logger,
+	}
+}
+
 func (c *Client) WithInstallation(installationID int64) *Client {
 	return &Client{
 		auth:           c.auth,
 		installationID: installationID,
+		responses:      c.responses,
 		logger:         c.logger,
 	}
 }
@@ -46,21 +62,45 @@ func (c *Client) getGitHubClient(ctx context.Context) (*github.Client, error) {
 	ts := oauth2.StaticTokenSource(token)
 	tc := oauth2.NewClient(ctx, ts)
 	tc.Timeout = 15 * time.Second
-	tc.Transport = observability.WrapRoundTripper(tc.Transport)
+	tc.Transport = c.rateLimited(logging.RoundTripper(observability.WrapRoundTripper(tc.Transport), "github", c.logger), c.installationID)
 
 	return github.NewClient(tc), nil
 }
 
-func (c *Client) EnsureGitShopYAMLForRepo(ctx context.Context, owner, repo, shopName string) (*YAMLCreationResult, error) {
+func (c *Client) rateLimited(base http.RoundTripper, installationID int64) http.RoundTripper {
+	if c.auth == nil || c.auth.limits == nil {
+		return base
+	}
+	return &rateLimitTransport{base: base, limits: c.auth.limits, installationID: installationID, logger: c.logger}
+}
+
+// getConditionalGitHubClient is getGitHubClient with GET responses revalidated by ETag, for
+// reads that repeat often and rarely change.
+func (c *Client) getConditionalGitHubClient(ctx context.Context) (*github.Client, error) {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil || c.responses == nil {
+		return client, err
+	}
+	httpClient := client.Client()
+	httpClient.Transport = &conditionalTransport{
+		base:           httpClient.Transport,
+		cache:          c.responses,
+		installationID: c.installationID,
+		logger:         c.logger,
+	}
+	return github.NewClient(httpClient), nil
+}
+
+func (c *Client) EnsureGitShopYAMLForRepo(ctx context.Context, owner, repo, shopName string, access ContentAccess) (*YAMLCreationResult, error) {
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return c.EnsureGitShopYAML(ctx, client, owner, repo, shopName)
+	return c.EnsureGitShopYAML(ctx, client, owner, repo, shopName, access)
 }
 
 func (c *Client) GetFile(ctx context.Context, repoFullName, path, ref string) ([]byte, error) {
-	client, err := c.getGitHubClient(ctx)
+	client, err := c.getConditionalGitHubClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +149,7 @@ type LabelDefinition struct {
 }
 
 func (c *Client) GetFileStatus(ctx context.Context, repoFullName, path string) (*FileStatus, error) {
-	client, err := c.getGitHubClient(ctx)
+	client, err := c.getConditionalGitHubClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +162,7 @@ func (c *Client) GetFileStatus(ctx context.Context, repoFullName, path string) (
 
 	fileContent, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, nil)
 	if err != nil {
-		if isNotFound(err) {
+		if IsNotFound(err) {
 			return &FileStatus{Exists: false}, nil
 		}
 		return nil, fmt.Errorf("failed to get file status %s: %w", path, err)
@@ -145,7 +185,7 @@ func (c *Client) GetFileStatus(ctx context.Context, repoFullName, path string) (
 }
 
 func (c *Client) ListDirectory(ctx context.Context, repoFullName, path string) ([]RepoFile, error) {
-	client, err := c.getGitHubClient(ctx)
+	client, err := c.getConditionalGitHubClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +198,7 @@ func (c *Client) ListDirectory(ctx context.Context, repoFullName, path string) (
 
 	fileContent, dirContent, _, err := client.Repositories.GetContents(ctx, owner, repo, path, nil)
 	if err != nil {
-		if isNotFound(err) {
+		if IsNotFound(err) {
 			return []RepoFile{}, nil
 		}
 		return nil, fmt.Errorf("failed to list directory %s: %w", path, err)
@@ -188,7 +228,8 @@ func (c *Client) ListDirectory(ctx context.Context, repoFullName, path string) (
 	return files, nil
 }
 
-func isNotFound(err error) bool {
+// IsNotFound reports whether err is a 404 from the GitHub API.
+func IsNotFound(err error) bool {
 	var errResp *github.ErrorResponse
 	if errors.As(err, &errResp) && errResp.Response != nil {
 		return errResp.Response.StatusCode == 404
@@ -196,6 +237,21 @@ func isNotFound(err error) bool {
 	return false
 }
 
+// GetRepositoryFullName looks a repository up by ID, so it resolves after renames and transfers.
+// It returns a 404 error when the installation cannot see the repository.
+func (c *Client) GetRepositoryFullName(ctx context.Context, repoID int64) (string, error) {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	repo, _, err := client.Repositories.GetByID(ctx, repoID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository %d: %w", repoID, err)
+	}
+	return repo.GetFullName(), nil
+}
+
 func (c *Client) CreateOrUpdateFile(ctx context.Context, repoFullName, path, content, message string) error {
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
@@ -254,6 +310,47 @@ func (c *Client) CreateComment(ctx context.Context, repoFullName string, issueNu
 	return nil
 }
 
+// CreateCommentWithID creates an issue comment and returns its ID so it can be edited later.
+func (c *Client) CreateCommentWithID(ctx context.Context, repoFullName string, issueNumber int, body string) (int64, error) {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid repo full name: %s", repoFullName)
+	}
+	owner, repo := parts[0], parts[1]
+
+	comment, _, err := client.Issues.CreateComment(ctx, owner, repo, issueNumber, &github.IssueComment{Body: &body})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return comment.GetID(), nil
+}
+
+func (c *Client) UpdateComment(ctx context.Context, repoFullName string, commentID int64, body string) error {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid repo full name: %s", repoFullName)
+	}
+	owner, repo := parts[0], parts[1]
+
+	_, _, err = client.Issues.EditComment(ctx, owner, repo, commentID, &github.IssueComment{Body: &body})
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Client) ListComments(ctx context.Context, repoFullName string, issueNumber int) ([]*github.IssueComment, error) {
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
@@ -303,6 +400,36 @@ func (c *Client) DeleteComment(ctx context.Context, repoFullName string, comment
 	return nil
 }
 
+// ListCommentReactions returns the reactions left on an issue comment, oldest first.
+func (c *Client) ListCommentReactions(ctx context.Context, repoFullName string, commentID int64) ([]*github.Reaction, error) {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid repo full name: %s", repoFullName)
+	}
+	owner, repo := parts[0], parts[1]
+
+	opts := &github.ListOptions{PerPage: 100}
+	reactions := []*github.Reaction{}
+	for {
+		pageReactions, resp, err := client.Reactions.ListIssueCommentReactions(ctx, owner, repo, commentID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comment reactions: %w", err)
+		}
+		reactions = append(reactions, pageReactions...)
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return reactions, nil
+}
+
 func (c *Client) AddLabels(ctx context.Context, repoFullName string, issueNumber int, labels []string) error {
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
@@ -399,6 +526,64 @@ func (c *Client) CreateIssue(ctx context.Context, repoFullName string, title, bo
 	return nil
 }
 
+// CreateIssueWithNumber opens an issue and returns its number, so it can be updated or closed
+// later.
+func (c *Client) CreateIssueWithNumber(ctx context.Context, repoFullName string, title, body string, labels []string) (int, error) {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid repo full name: %s", repoFullName)
+	}
+	owner, repo := parts[0], parts[1]
+
+	issueRequest := &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	}
+	if len(labels) > 0 {
+		issueRequest.Labels = &labels
+	}
+
+	issue, _, err := client.Issues.Create(ctx, owner, repo, issueRequest)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	return issue.GetNumber(), nil
+}
+
+// UpdateIssue replaces an issue's title and body and reopens it if someone closed it.
+func (c *Client) UpdateIssue(ctx context.Context, repoFullName string, issueNumber int, title, body string) error {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid repo full name: %s", repoFullName)
+	}
+	owner, repo := parts[0], parts[1]
+
+	state := "open"
+	issueRequest := &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+		State: &state,
+	}
+
+	_, _, err = client.Issues.Edit(ctx, owner, repo, issueNumber, issueRequest)
+	if err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Client) UpdateIssueTitle(ctx context.Context, repoFullName string, issueNumber int, title string) error {
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
@@ -461,6 +646,11 @@ func (c *Client) EnsureLabels(ctx context.Context, repoFullName string, labels [
 	}
 	owner, repo := parts[0], parts[1]
 
+	existing, err := c.ListLabels(ctx, repoFullName)
+	if err != nil {
+		return err
+	}
+
 	for _, label := range labels {
 		params := &github.Label{
 			Name:        github.String(label.Name),
@@ -468,6 +658,17 @@ func (c *Client) EnsureLabels(ctx context.Context, repoFullName string, labels [
 			Description: github.String(label.Description),
 		}
 
+		// Existing labels are recolored when the shop changed their color in gitshop.yaml.
+		if current, ok := existing[label.Name]; ok {
+			if strings.EqualFold(current.GetColor(), label.Color) {
+				continue
+			}
+			if _, _, err := client.Issues.EditLabel(ctx, owner, repo, label.Name, params); err != nil {
+				return fmt.Errorf("failed to update label %s: %w", label.Name, err)
+			}
+			continue
+		}
+
 		_, _, err := client.Issues.CreateLabel(ctx, owner, repo, params)
 		if err != nil {
 			if isLabelExists(err) {
@@ -554,6 +755,37 @@ func (c *Client) CheckPermission(ctx context.Context, repoFullName, username str
 	return *perm.Permission == "write" || *perm.Permission == "admin", nil
 }
 
+// RepositoryRole returns the user's role on the repository: read, triage, write, maintain, or
+// admin. Custom roles are reported as the base permission they extend, and "none" means no access.
+func (c *Client) RepositoryRole(ctx context.Context, repoFullName, username string) (string, error) {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(repoFullName, "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid repo full name: %s", repoFullName)
+	}
+
+	perm, _, err := client.Repositories.GetPermissionLevel(ctx, parts[0], parts[1], username)
+	if err != nil {
+		if IsNotFound(err) {
+			return "none", nil
+		}
+		return "", fmt.Errorf("failed to check permission: %w", err)
+	}
+
+	switch role := strings.ToLower(perm.GetRoleName()); role {
+	case "read", "triage", "write", "maintain", "admin":
+		return role, nil
+	}
+	if permission := strings.ToLower(perm.GetPermission()); permission != "" {
+		return permission, nil
+	}
+	return "none", nil
+}
+
 func (c *Client) GetInstallation(ctx context.Context, userAccessToken string, installationID int64) (*Installation, error) {
 	client := github.NewClient(nil)
 	client = client.WithAuthToken(userAccessToken)
@@ -0,0 +1,108 @@
+package githubapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	conditionalCacheTTL = 24 * time.Hour
+	// maxConditionalBody keeps large blobs out of the cache; repository reads are config and
+	// template files well under this size.
+	maxConditionalBody = 512 << 10
+)
+
+type cachedResponse struct {
+	ETag        string `json:"etag"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// conditionalTransport revalidates GET responses with If-None-Match. GitHub does not count a
+// 304 against the rate limit, so unchanged files cost nothing to read again. Entries are scoped
+// to the installation so one installation's token never serves another's data.
+type conditionalTransport struct {
+	base           http.RoundTripper
+	cache          cache.Provider
+	installationID int64
+	logger         *slog.Logger
+}
+
+func (t *conditionalTransport) cacheKey(req *http.Request) string {
+	return "github_etag:" + strconv.FormatInt(t.installationID, 10) + ":" + req.URL.String()
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || t.cache == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	key := t.cacheKey(req)
+	var cached *cachedResponse
+	if raw, err := t.cache.Get(ctx, key); err == nil && raw != "" {
+		var entry cachedResponse
+		if err := json.Unmarshal([]byte(raw), &entry); err == nil && entry.ETag != "" {
+			cached = &entry
+			req = req.Clone(ctx)
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	meter := observability.MeterFromContext(ctx)
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_ = resp.Body.Close()
+		meter.Count("github.conditional.not_modified", 1)
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Header.Set("Content-Type", cached.ContentType)
+		resp.Header.Set("ETag", cached.ETag)
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" || resp.ContentLength > maxConditionalBody {
+		return resp, nil
+	}
+	original := resp.Body
+	body, err := io.ReadAll(io.LimitReader(original, maxConditionalBody+1))
+	if err != nil {
+		_ = original.Close()
+		return nil, err
+	}
+	if len(body) > maxConditionalBody {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), original), original}
+		return resp, nil
+	}
+	_ = original.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	meter.Count("github.conditional.miss", 1)
+	encoded, err := json.Marshal(cachedResponse{ETag: etag, ContentType: resp.Header.Get("Content-Type"), Body: body})
+	if err == nil {
+		err = t.cache.Set(ctx, key, string(encoded), conditionalCacheTTL)
+	}
+	if err != nil {
+		logging.FromContext(ctx, t.logger).Warn("failed to cache GitHub response", "error", err, "url", req.URL.Path)
+	}
+	return resp, nil
+}
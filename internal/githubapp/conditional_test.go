@@ -0,0 +1,77 @@
+package githubapp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+)
+
+func TestConditionalTransport_RevalidatesWithETag(t *testing.T) {
+	t.Parallel()
+
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"name":"gitshop.yaml"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := cache.NewMemoryProvider()
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	client := &http.Client{Transport: &conditionalTransport{base: http.DefaultTransport, cache: provider, installationID: 1}}
+	other := &http.Client{Transport: &conditionalTransport{base: http.DefaultTransport, cache: provider, installationID: 2}}
+
+	for i, c := range []*http.Client{client, client, other} {
+		resp, err := c.Get(server.URL + "/repos/acme/shop/contents/gitshop.yaml")
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != `{"name":"gitshop.yaml"}` {
+			t.Fatalf("request %d: expected cached body with 200, got %d %q", i, resp.StatusCode, body)
+		}
+	}
+	if requests.Load() != 3 || notModified.Load() != 1 {
+		t.Fatalf("expected only the repeat from the same installation to revalidate, got %d requests and %d 304s", requests.Load(), notModified.Load())
+	}
+}
+
+func TestConditionalTransport_SkipsWrites(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("unexpected conditional header on %s", r.Method)
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := cache.NewMemoryProvider()
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	client := &http.Client{Transport: &conditionalTransport{base: http.DefaultTransport, cache: provider, installationID: 1}}
+	for range 2 {
+		resp, err := client.Post(server.URL+"/repos/acme/shop/issues", "application/json", nil)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+}
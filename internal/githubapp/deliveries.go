@@ -0,0 +1,125 @@
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const maxDeliveryPages = 20
+
+// AppDelivery is a webhook GitHub sent, or tried to send, to the app's webhook URL. ID names
+// this attempt and is what redelivery takes; GUID matches the X-GitHub-Delivery header and is
+// shared by every attempt of the same delivery.
+type AppDelivery struct {
+	ID          int64
+	GUID        string
+	Event       string
+	Action      string
+	StatusCode  int
+	Status      string
+	DeliveredAt time.Time
+}
+
+func (d AppDelivery) succeeded() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+// FailedAppDeliveries lists deliveries first attempted between since and until that no attempt
+// has delivered successfully, oldest first, with their latest attempt.
+func (c *Client) FailedAppDeliveries(ctx context.Context, since, until time.Time) ([]AppDelivery, error) {
+	client, err := c.appClient()
+	if err != nil {
+		return nil, err
+	}
+
+	type deliveryAttempts struct {
+		first     time.Time
+		latest    AppDelivery
+		succeeded bool
+	}
+	byGUID := map[string]*deliveryAttempts{}
+	opts := &github.ListCursorOptions{PerPage: 100}
+	for page := 0; page < maxDeliveryPages; page++ {
+		deliveries, resp, err := client.Apps.ListHookDeliveries(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list app webhook deliveries: %w", err)
+		}
+
+		reachedSince := false
+		for _, raw := range deliveries {
+			delivery := AppDelivery{
+				ID:          raw.GetID(),
+				GUID:        raw.GetGUID(),
+				Event:       raw.GetEvent(),
+				Action:      raw.GetAction(),
+				StatusCode:  raw.GetStatusCode(),
+				Status:      raw.GetStatus(),
+				DeliveredAt: raw.GetDeliveredAt().Time,
+			}
+			if delivery.DeliveredAt.Before(since) {
+				reachedSince = true
+				continue
+			}
+			attempts, ok := byGUID[delivery.GUID]
+			if !ok {
+				attempts = &deliveryAttempts{first: delivery.DeliveredAt, latest: delivery}
+				byGUID[delivery.GUID] = attempts
+			}
+			if delivery.DeliveredAt.Before(attempts.first) {
+				attempts.first = delivery.DeliveredAt
+			}
+			if delivery.DeliveredAt.After(attempts.latest.DeliveredAt) {
+				attempts.latest = delivery
+			}
+			attempts.succeeded = attempts.succeeded || delivery.succeeded()
+		}
+		if reachedSince || resp == nil || resp.Cursor == "" {
+			break
+		}
+		opts.Cursor = resp.Cursor
+	}
+
+	failed := []AppDelivery{}
+	for _, attempts := range byGUID {
+		if attempts.succeeded || !attempts.first.Before(until) {
+			continue
+		}
+		failed = append(failed, attempts.latest)
+	}
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].DeliveredAt.Before(failed[j].DeliveredAt)
+	})
+	return failed, nil
+}
+
+// RedeliverAppDelivery asks GitHub to send a delivery attempt again.
+func (c *Client) RedeliverAppDelivery(ctx context.Context, deliveryID int64) error {
+	client, err := c.appClient()
+	if err != nil {
+		return err
+	}
+	_, _, err = client.Apps.RedeliverHookDelivery(ctx, deliveryID)
+	var accepted *github.AcceptedError
+	if err != nil && !errors.As(err, &accepted) {
+		return fmt.Errorf("failed to redeliver webhook %d: %w", deliveryID, err)
+	}
+	return nil
+}
+
+// appClient authenticates as the app itself, which the app-level webhook endpoints require.
+func (c *Client) appClient() (*github.Client, error) {
+	appJWT, err := c.auth.CreateJWT()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWT: %w", err)
+	}
+	httpClient := observability.NewHTTPClient(15 * time.Second)
+	httpClient.Transport = c.rateLimited(httpClient.Transport, 0)
+	return github.NewClient(httpClient).WithAuthToken(appJWT), nil
+}
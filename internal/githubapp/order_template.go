@@ -4,6 +4,7 @@ package githubapp
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	"github.com/google/go-github/v66/github"
@@ -17,18 +18,21 @@ type FileCreationResult struct {
 	ErrorMessage string
 }
 
-func (c *Client) EnsureOrderTemplate(ctx context.Context, owner, repo, templateContent string) (*FileCreationResult, error) {
+// EnsureOrderTemplate creates the order template at templatePath unless a file already exists there.
+func (c *Client) EnsureOrderTemplate(ctx context.Context, owner, repo, templatePath, templateContent string, access ContentAccess) (*FileCreationResult, error) {
+	if !access.CanWrite(templatePath) {
+		return nil, fmt.Errorf("%w: %s", ErrContentAccessDenied, templatePath)
+	}
+
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	templatePath := ".github/ISSUE_TEMPLATE/order.yaml"
-
 	_, _, _, err = client.Repositories.GetContents(ctx, owner, repo, templatePath, nil)
 	if err == nil {
 		if c.logger != nil {
-			c.logger.Info("order template already exists", "repo", fmt.Sprintf("%s/%s", owner, repo))
+			c.logger.Info("order template already exists", "repo", fmt.Sprintf("%s/%s", owner, repo), "path", templatePath)
 		}
 		return &FileCreationResult{
 			Created: false,
@@ -55,24 +59,35 @@ func (c *Client) EnsureOrderTemplate(ctx context.Context, owner, repo, templateC
 	}
 
 	if strings.Contains(err.Error(), "409") || strings.Contains(err.Error(), "protected") {
+		if !access.CanOpenPullRequests() {
+			return nil, errProtectedBranchNeedsPR
+		}
 		if c.logger != nil {
 			c.logger.Info("Direct commit failed, trying PR approach", "repo", fmt.Sprintf("%s/%s", owner, repo), "error", err)
 		}
 		prTitle := "Setup GitShop - Add order template"
 		prBody := "This PR adds the GitShop order issue template.\n\nPlease review and merge to start accepting orders via GitHub issues."
-		return c.createFileViaPRWithPath(ctx, client, owner, repo, defaultBranch, templatePath, templateContent, prTitle, prBody, "gitshop/setup-order-template")
+		branchName := "gitshop/setup-order-template"
+		if templatePath != OrderTemplatePath {
+			branchName = "gitshop/setup-" + strings.TrimSuffix(path.Base(templatePath), path.Ext(templatePath))
+		}
+		return c.createFileViaPRWithPath(ctx, client, owner, repo, defaultBranch, templatePath, templateContent, prTitle, prBody, branchName)
 	}
 
 	return nil, fmt.Errorf("failed to create order template: %w", err)
 }
 
-func (c *Client) CreateOrUpdateOrderTemplate(ctx context.Context, owner, repo, templateContent string) (*FileCreationResult, error) {
+func (c *Client) CreateOrUpdateOrderTemplate(ctx context.Context, owner, repo, templateContent string, access ContentAccess) (*FileCreationResult, error) {
+	templatePath := access.OrderTemplatePath()
+	if templatePath == "" {
+		return nil, fmt.Errorf("%w: no issue template path is granted", ErrContentAccessDenied)
+	}
+
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	templatePath := ".github/ISSUE_TEMPLATE/order.yaml"
 	defaultBranch, err := c.getDefaultBranch(ctx, client, owner, repo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get default branch: %w", err)
@@ -84,6 +99,9 @@ func (c *Client) CreateOrUpdateOrderTemplate(ctx context.Context, owner, repo, t
 		if url, updateErr := c.updateFileDirectlyWithPath(ctx, client, owner, repo, defaultBranch, templatePath, templateContent, message, *existing.SHA); updateErr == nil {
 			return &FileCreationResult{Created: true, Method: "commit", URL: url}, nil
 		} else if strings.Contains(updateErr.Error(), "409") || strings.Contains(updateErr.Error(), "protected") {
+			if !access.CanOpenPullRequests() {
+				return nil, errProtectedBranchNeedsPR
+			}
 			prTitle := "Sync GitShop order template"
 			prBody := "This PR synchronizes the GitShop order issue template with your current `gitshop.yaml`."
 			return c.createFileViaPRWithPath(ctx, client, owner, repo, defaultBranch, templatePath, templateContent, prTitle, prBody, "gitshop/sync-order-template")
@@ -92,10 +110,13 @@ func (c *Client) CreateOrUpdateOrderTemplate(ctx context.Context, owner, repo, t
 		}
 	}
 
-	return c.EnsureOrderTemplate(ctx, owner, repo, templateContent)
+	return c.EnsureOrderTemplate(ctx, owner, repo, templatePath, templateContent, access)
 }
 
-func (c *Client) CreateOrUpdateFileWithPR(ctx context.Context, owner, repo, path, content, message, prTitle, prBody, branchName string) (*FileCreationResult, error) {
+func (c *Client) CreateOrUpdateFileWithPR(ctx context.Context, owner, repo, path, content, message, prTitle, prBody, branchName string, access ContentAccess) (*FileCreationResult, error) {
+	if !access.CanWrite(path) {
+		return nil, fmt.Errorf("%w: %s", ErrContentAccessDenied, path)
+	}
 	client, err := c.getGitHubClient(ctx)
 	if err != nil {
 		return nil, err
@@ -111,6 +132,9 @@ func (c *Client) CreateOrUpdateFileWithPR(ctx context.Context, owner, repo, path
 		if url, updateErr := c.updateFileDirectlyWithPath(ctx, client, owner, repo, defaultBranch, path, content, message, *existing.SHA); updateErr == nil {
 			return &FileCreationResult{Created: true, Method: "commit", URL: url}, nil
 		} else if strings.Contains(updateErr.Error(), "409") || strings.Contains(updateErr.Error(), "protected") {
+			if !access.CanOpenPullRequests() {
+				return nil, errProtectedBranchNeedsPR
+			}
 			return c.createFileViaPRWithPath(ctx, client, owner, repo, defaultBranch, path, content, prTitle, prBody, branchName)
 		} else {
 			return nil, fmt.Errorf("failed to update file: %w", updateErr)
@@ -120,6 +144,9 @@ func (c *Client) CreateOrUpdateFileWithPR(ctx context.Context, owner, repo, path
 	if url, createErr := c.createFileDirectlyWithPath(ctx, client, owner, repo, defaultBranch, path, content, message); createErr == nil {
 		return &FileCreationResult{Created: true, Method: "commit", URL: url}, nil
 	} else if strings.Contains(createErr.Error(), "409") || strings.Contains(createErr.Error(), "protected") {
+		if !access.CanOpenPullRequests() {
+			return nil, errProtectedBranchNeedsPR
+		}
 		return c.createFileViaPRWithPath(ctx, client, owner, repo, defaultBranch, path, content, prTitle, prBody, branchName)
 	} else {
 		return nil, fmt.Errorf("failed to create file: %w", createErr)
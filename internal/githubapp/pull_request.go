@@ -0,0 +1,131 @@
+// Package githubapp provides multi-file pull request helpers.
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+)
+
+type FileChange struct {
+	Path    string
+	Content string
+}
+
+// PullRequestState is where a pull request stands: open, merged, or closed without merging.
+type PullRequestState string
+
+const (
+	PullRequestOpen   PullRequestState = "open"
+	PullRequestMerged PullRequestState = "merged"
+	PullRequestClosed PullRequestState = "closed"
+)
+
+type PullRequestInput struct {
+	BranchName string
+	Message    string
+	Title      string
+	Body       string
+	Files      []FileChange
+}
+
+// CreatePullRequestWithFiles writes every file onto a fresh branch cut from the
+// default branch and opens a single pull request with the result.
+func (c *Client) CreatePullRequestWithFiles(ctx context.Context, owner, repo string, input PullRequestInput) (*FileCreationResult, error) {
+	if len(input.Files) == 0 {
+		return nil, fmt.Errorf("no files to commit")
+	}
+
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultBranch, err := c.getDefaultBranch(ctx, client, owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default branch: %w", err)
+	}
+
+	ref, _, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+defaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ref: %w", err)
+	}
+
+	_, _, err = client.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + input.BranchName),
+		Object: &github.GitObject{SHA: ref.Object.SHA},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create branch %s: %w", input.BranchName, err)
+	}
+
+	for _, file := range input.Files {
+		opts := &github.RepositoryContentFileOptions{
+			Message: github.String(input.Message),
+			Content: []byte(file.Content),
+			Branch:  github.String(input.BranchName),
+		}
+
+		existing, _, _, getErr := client.Repositories.GetContents(ctx, owner, repo, file.Path, &github.RepositoryContentGetOptions{Ref: input.BranchName})
+		if getErr == nil && existing != nil {
+			opts.SHA = existing.SHA
+		} else if getErr != nil && !IsNotFound(getErr) {
+			return nil, fmt.Errorf("failed to read %s on branch: %w", file.Path, getErr)
+		}
+
+		if _, _, err := client.Repositories.CreateFile(ctx, owner, repo, file.Path, opts); err != nil {
+			return nil, fmt.Errorf("failed to write %s on branch: %w", file.Path, err)
+		}
+	}
+
+	createdPR, _, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.String(input.Title),
+		Body:  github.String(input.Body),
+		Head:  github.String(input.BranchName),
+		Base:  github.String(defaultBranch),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	if c.logger != nil {
+		c.logger.Info("files committed via PR", "repo", fmt.Sprintf("%s/%s", owner, repo), "pr_number", createdPR.GetNumber(), "files", len(input.Files))
+	}
+
+	return &FileCreationResult{
+		Created:  true,
+		Method:   "pr",
+		URL:      createdPR.GetHTMLURL(),
+		PRNumber: createdPR.GetNumber(),
+	}, nil
+}
+
+// GetPullRequestState reports whether the pull request is open, merged, or closed without
+// merging.
+func (c *Client) GetPullRequestState(ctx context.Context, repoFullName string, number int) (PullRequestState, error) {
+	client, err := c.getGitHubClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	owner, repo, ok := strings.Cut(repoFullName, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid repo full name: %s", repoFullName)
+	}
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request %d: %w", number, err)
+	}
+
+	switch {
+	case pr.GetMerged():
+		return PullRequestMerged, nil
+	case pr.GetState() == "closed":
+		return PullRequestClosed, nil
+	default:
+		return PullRequestOpen, nil
+	}
+}
@@ -0,0 +1,238 @@
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// ErrRateLimited is returned instead of GitHub's 403 or 429 once an installation's quota is
+// spent and waiting it out inline would take too long.
+var ErrRateLimited = errors.New("github rate limit exceeded")
+
+const (
+	maxRateLimitRetries = 3
+	// maxRateLimitWait is the longest a request waits inline for quota; longer waits pause the
+	// installation instead.
+	maxRateLimitWait = 30 * time.Second
+	// secondaryRateLimitPause follows GitHub's advice to wait at least a minute after a secondary
+	// limit that does not say when to retry.
+	secondaryRateLimitPause = time.Minute
+	rateLimitBodyPeek       = 4 << 10
+)
+
+type rateLimitState struct {
+	remaining    int
+	limit        int
+	reset        time.Time
+	blockedUntil time.Time
+}
+
+// rateLimits remembers each installation's quota across clients. Installation 0 is the app
+// itself, authenticated by JWT.
+type rateLimits struct {
+	mu             sync.Mutex
+	byInstallation map[int64]*rateLimitState
+}
+
+func newRateLimits() *rateLimits {
+	return &rateLimits{byInstallation: make(map[int64]*rateLimitState)}
+}
+
+func (r *rateLimits) state(installationID int64) *rateLimitState {
+	state, ok := r.byInstallation[installationID]
+	if !ok {
+		state = &rateLimitState{}
+		r.byInstallation[installationID] = state
+	}
+	return state
+}
+
+func (r *rateLimits) blockedUntil(installationID int64, now time.Time) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.byInstallation[installationID]
+	if !ok || !now.Before(state.blockedUntil) {
+		return time.Time{}, false
+	}
+	return state.blockedUntil, true
+}
+
+func (r *rateLimits) block(installationID int64, until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := r.state(installationID)
+	if until.After(state.blockedUntil) {
+		state.blockedUntil = until
+	}
+}
+
+func (r *rateLimits) observe(installationID int64, remaining, limit int, reset time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := r.state(installationID)
+	state.remaining, state.limit, state.reset = remaining, limit, reset
+}
+
+// rateLimitTransport reports GitHub's quota headers, retries requests that hit a rate limit
+// after a short jittered wait, and stops calling GitHub for an installation whose quota will
+// not come back soon.
+type rateLimitTransport struct {
+	base           http.RoundTripper
+	limits         *rateLimits
+	installationID int64
+	logger         *slog.Logger
+	// sleep is replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	meter := observability.MeterFromContext(ctx)
+	installation := sentry.WithAttributes(attribute.Int64("installation_id", t.installationID))
+
+	if until, blocked := t.limits.blockedUntil(t.installationID, time.Now()); blocked {
+		meter.Count("github.ratelimit.rejected", 1, installation)
+		return nil, fmt.Errorf("%w: installation %d paused until %s", ErrRateLimited, t.installationID, until.UTC().Format(time.RFC3339))
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.record(meter, resp, installation)
+
+		wait, limited := rateLimitWait(resp, time.Now())
+		if !limited {
+			return resp, nil
+		}
+		meter.Count("github.ratelimit.limited", 1, installation)
+
+		retry, replayable := retryRequest(req)
+		if wait > maxRateLimitWait || attempt >= maxRateLimitRetries || !replayable {
+			_ = resp.Body.Close()
+			if wait < time.Second {
+				wait = time.Second
+			}
+			until := time.Now().Add(wait)
+			t.limits.block(t.installationID, until)
+			logging.FromContext(ctx, t.logger).Warn("paused GitHub calls for rate-limited installation", "installation_id", t.installationID, "until", until, "status", resp.StatusCode)
+			return nil, fmt.Errorf("%w: installation %d paused until %s", ErrRateLimited, t.installationID, until.UTC().Format(time.RFC3339))
+		}
+		_ = resp.Body.Close()
+
+		delay := wait
+		if backoff := time.Second << attempt; delay < backoff {
+			delay = backoff
+		}
+		delay += rand.N(delay/2 + 1)
+		if err := t.sleepFor(ctx, delay); err != nil {
+			return nil, err
+		}
+		req = retry
+	}
+}
+
+func (t *rateLimitTransport) sleepFor(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *rateLimitTransport) record(meter sentry.Meter, resp *http.Response, installation sentry.MeterOption) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	t.limits.observe(t.installationID, remaining, limit, rateLimitReset(resp))
+
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+	meter.Gauge("github.ratelimit.remaining", float64(remaining), sentry.WithAttributes(
+		attribute.Int64("installation_id", t.installationID),
+		attribute.String("resource", resource),
+	))
+}
+
+// rateLimitWait reports whether resp is a rate-limit rejection and how long GitHub asks callers
+// to wait before trying again.
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return max(rateLimitReset(resp).Sub(now), 0), true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || mentionsSecondaryRateLimit(resp) {
+		return secondaryRateLimitPause, true
+	}
+	return 0, false
+}
+
+// mentionsSecondaryRateLimit checks the start of the error body, leaving it readable for callers.
+func mentionsSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	peek, err := io.ReadAll(io.LimitReader(resp.Body, rateLimitBodyPeek))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+	return err == nil && strings.Contains(strings.ToLower(string(peek)), "secondary rate limit")
+}
+
+func rateLimitReset(resp *http.Response) time.Time {
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(reset, 0)
+}
+
+// retryRequest prepares req to be sent again, which needs a fresh body for writes.
+func retryRequest(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	return retry, true
+}
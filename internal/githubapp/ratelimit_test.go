@@ -0,0 +1,112 @@
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func noSleep(context.Context, time.Duration) error { return nil }
+
+func TestRateLimitTransport_RetriesShortLimits(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"message":"You have exceeded a secondary rate limit."}`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport, limits: newRateLimits(), installationID: 1, sleep: noSleep}}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"body":"hi"}`))
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != `{"body":"hi"}` || calls.Load() != 2 {
+		t.Fatalf("expected one retry with the original body, got %d %q after %d calls", resp.StatusCode, body, calls.Load())
+	}
+}
+
+func TestRateLimitTransport_PausesExhaustedInstallation(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	limits := newRateLimits()
+	exhausted := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport, limits: limits, installationID: 1, sleep: noSleep}}
+	for range 2 {
+		if _, err := exhausted.Get(server.URL); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("expected ErrRateLimited, got %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected the paused installation to stop calling GitHub, got %d calls", calls.Load())
+	}
+
+	other := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport, limits: limits, installationID: 2, sleep: noSleep}}
+	if _, err := other.Get(server.URL); !errors.Is(err, ErrRateLimited) || calls.Load() != 2 {
+		t.Fatalf("expected another installation to still reach GitHub, got %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name        string
+		status      int
+		header      map[string]string
+		body        string
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{name: "success", status: http.StatusOK, header: map[string]string{"X-RateLimit-Remaining": "0"}},
+		{name: "permission denied", status: http.StatusForbidden, body: `{"message":"Resource not accessible by integration"}`},
+		{name: "retry after", status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "7"}, wantWait: 7 * time.Second, wantLimited: true},
+		{name: "primary exhausted", status: http.StatusForbidden, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1700000012"}, wantWait: 12 * time.Second, wantLimited: true},
+		{name: "secondary without retry after", status: http.StatusForbidden, body: `{"message":"You have exceeded a secondary rate limit"}`, wantWait: secondaryRateLimitPause, wantLimited: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(tt.body))}
+			for key, value := range tt.header {
+				resp.Header.Set(key, value)
+			}
+			wait, limited := rateLimitWait(resp, now)
+			if wait != tt.wantWait || limited != tt.wantLimited {
+				t.Fatalf("expected (%v, %v), got (%v, %v)", tt.wantWait, tt.wantLimited, wait, limited)
+			}
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.body {
+				t.Fatalf("expected body to stay readable, got %q", body)
+			}
+		})
+	}
+}
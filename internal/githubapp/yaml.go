@@ -13,6 +13,8 @@ const DefaultGitShopYAML = `# GitShop Configuration
 # This file defines your product catalog and store settings.
 # The product below is a sample. Replace it with your own items.
 
+version: 1
+
 shop:
   name: "%s"
   currency: "usd"
@@ -45,7 +47,11 @@ type YAMLCreationResult struct {
 
 // EnsureGitShopYAML checks if gitshop.yaml exists in the repo and creates it if not.
 // It attempts to commit directly first, and falls back to creating a PR if the branch is protected.
-func (c *Client) EnsureGitShopYAML(ctx context.Context, client *github.Client, owner, repo, shopName string) (*YAMLCreationResult, error) {
+func (c *Client) EnsureGitShopYAML(ctx context.Context, client *github.Client, owner, repo, shopName string, access ContentAccess) (*YAMLCreationResult, error) {
+	if !access.CanWrite("gitshop.yaml") {
+		return nil, fmt.Errorf("%w: gitshop.yaml", ErrContentAccessDenied)
+	}
+
 	// Check if gitshop.yaml already exists
 	_, _, _, err := client.Repositories.GetContents(ctx, owner, repo, "gitshop.yaml", nil)
 	if err == nil {
@@ -82,6 +88,9 @@ func (c *Client) EnsureGitShopYAML(ctx context.Context, client *github.Client, o
 
 	// If direct commit failed (likely due to branch protection), try creating a PR
 	if strings.Contains(err.Error(), "409") || strings.Contains(err.Error(), "protect") {
+		if !access.CanOpenPullRequests() {
+			return nil, errProtectedBranchNeedsPR
+		}
 		if c.logger != nil {
 			c.logger.Info("Direct commit failed, trying PR approach", "repo", fmt.Sprintf("%s/%s", owner, repo), "error", err)
 		}
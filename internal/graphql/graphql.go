@@ -0,0 +1,485 @@
+// Package graphql runs read-only GraphQL queries against a schema of Go resolvers. It supports
+// what dashboards and API clients use day to day: operations with variables, aliases, fragments,
+// and the @include and @skip directives. Object types have no interfaces or unions, and the
+// schema is published as SDL instead of through introspection.
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// maxDepth and maxFields bound how deeply a query can nest fields and how many it can select,
+// counting each time a fragment is spread, so one request cannot fan out without limit.
+const (
+	maxDepth  = 10
+	maxFields = 500
+)
+
+// ErrForbidden is returned by resolvers for fields the caller may not read. The field resolves
+// to null and the rest of the query still runs.
+var ErrForbidden = errors.New("not authorized to read this field")
+
+// Schema is the set of types a query can select from, starting at Query.
+type Schema struct {
+	Query *Object
+}
+
+// Object is a type with fields, listed in the order the SDL shows them.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+// Field is one field of an object. Leaf fields leave Object nil and resolve to JSON-encodable
+// values; the others set Object to the type of the value, or of each element when Resolve
+// returns a slice. Type is how the SDL shows the field's type, such as "[Order!]!".
+type Field struct {
+	Name        string
+	Description string
+	Type        string
+	Object      *Object
+	Args        []Arg
+	Resolve     func(ctx context.Context, source any, args Args) (any, error)
+}
+
+// Arg is an argument a field accepts. Arguments whose Type ends in "!" are required.
+type Arg struct {
+	Name string
+	Type string
+}
+
+// Args holds a field's arguments after variables are substituted. Values come from the query or
+// from JSON variables, so numbers may be int64 or float64.
+type Args map[string]any
+
+// Int returns the integer argument name and whether it was given.
+func (a Args) Int(name string) (int, bool, error) {
+	switch value := a[name].(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		return int(value), true, nil
+	case float64:
+		if value != math.Trunc(value) || math.Abs(value) > math.MaxInt32 {
+			return 0, true, fmt.Errorf("argument %s must be an integer", name)
+		}
+		return int(value), true, nil
+	default:
+		return 0, true, fmt.Errorf("argument %s must be an integer", name)
+	}
+}
+
+// String returns the string argument name and whether it was given. Enum values count as strings.
+func (a Args) String(name string) (string, bool, error) {
+	switch value := a[name].(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return value, true, nil
+	case enumValue:
+		return string(value), true, nil
+	default:
+		return "", true, fmt.Errorf("argument %s must be a string", name)
+	}
+}
+
+func (o *Object) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Request is a query as clients POST it.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Response is the result of a query. Data is missing when the query could not run at all.
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+// Error describes a problem with the query, or with resolving the field at Path.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Execute parses, validates, and runs req against the schema.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: "Syntax error: " + err.Error()}}}
+	}
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if op.kind != "query" {
+		return &Response{Errors: []*Error{{Message: fmt.Sprintf("%s operations are not supported; the API is read-only", op.kind)}}}
+	}
+	variables, err := coerceVariables(op.variables, req.Variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{schema: s, doc: doc, variables: variables}
+	if err := e.validate(s.Query, op.selections, 1, nil); err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	data := e.executeObject(ctx, s.Query, nil, op.selections, nil)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the query has more than one operation")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("no operation named %s", name)
+}
+
+// coerceVariables applies default values and checks that required variables were given.
+func coerceVariables(definitions []variableDefinition, given map[string]any) (map[string]any, error) {
+	variables := make(map[string]any, len(definitions))
+	for _, definition := range definitions {
+		value, ok := given[definition.name]
+		switch {
+		case ok && value != nil:
+			variables[definition.name] = value
+		case !ok && definition.hasDefault:
+			variables[definition.name] = definition.defaultValue
+		case strings.HasSuffix(definition.typ, "!"):
+			return nil, fmt.Errorf("variable $%s of type %s is required", definition.name, definition.typ)
+		}
+	}
+	return variables, nil
+}
+
+type executor struct {
+	schema    *Schema
+	doc       *document
+	variables map[string]any
+	errors    []*Error
+	// fields counts the fields validated so far.
+	fields int
+}
+
+// validate checks the selections against the object before anything runs, so a mistyped field
+// fails the whole query instead of returning partial data.
+func (e *executor) validate(object *Object, selections []selection, depth int, spreading []string) error {
+	if depth > maxDepth {
+		return fmt.Errorf("query is nested more than %d levels deep", maxDepth)
+	}
+	for _, sel := range selections {
+		for _, d := range sel.directives {
+			if d.name != "include" && d.name != "skip" {
+				return fmt.Errorf("unknown directive @%s", d.name)
+			}
+			if _, err := e.directiveCondition(d); err != nil {
+				return err
+			}
+		}
+		switch {
+		case sel.spread != "":
+			frag, ok := e.doc.fragments[sel.spread]
+			if !ok {
+				return fmt.Errorf("unknown fragment %s", sel.spread)
+			}
+			if slices.Contains(spreading, frag.name) {
+				return fmt.Errorf("fragment %s spreads itself", frag.name)
+			}
+			if frag.typeCondition != object.Name {
+				return fmt.Errorf("fragment %s on %s cannot be spread on %s", frag.name, frag.typeCondition, object.Name)
+			}
+			if err := e.validate(object, frag.selections, depth, append(slices.Clip(spreading), frag.name)); err != nil {
+				return err
+			}
+		case sel.inline != nil:
+			if sel.inline.typeCondition != "" && sel.inline.typeCondition != object.Name {
+				return fmt.Errorf("fragment on %s cannot be spread on %s", sel.inline.typeCondition, object.Name)
+			}
+			if err := e.validate(object, sel.inline.selections, depth, spreading); err != nil {
+				return err
+			}
+		default:
+			if err := e.validateField(object, sel.field, depth, spreading); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *executor) validateField(object *Object, f *field, depth int, spreading []string) error {
+	e.fields++
+	if e.fields > maxFields {
+		return fmt.Errorf("query selects more than %d fields", maxFields)
+	}
+	if f.name == "__typename" {
+		if len(f.args) > 0 || len(f.selections) > 0 {
+			return fmt.Errorf("__typename takes no arguments or selections")
+		}
+		return nil
+	}
+	if strings.HasPrefix(f.name, "__") {
+		return fmt.Errorf("introspection is not supported; GET this endpoint for the schema")
+	}
+	def := object.field(f.name)
+	if def == nil {
+		return fmt.Errorf("%s has no field %s", object.Name, f.name)
+	}
+	for _, arg := range f.args {
+		if !slices.ContainsFunc(def.Args, func(a Arg) bool { return a.Name == arg.name }) {
+			return fmt.Errorf("%s.%s has no argument %s", object.Name, f.name, arg.name)
+		}
+	}
+	for _, arg := range def.Args {
+		if !strings.HasSuffix(arg.Type, "!") {
+			continue
+		}
+		i := slices.IndexFunc(f.args, func(a argument) bool { return a.name == arg.Name })
+		if i < 0 || e.value(f.args[i].value) == nil {
+			return fmt.Errorf("%s.%s requires argument %s", object.Name, f.name, arg.Name)
+		}
+	}
+	if def.Object == nil {
+		if len(f.selections) > 0 {
+			return fmt.Errorf("%s.%s is a %s and has no fields to select", object.Name, f.name, def.Type)
+		}
+		return nil
+	}
+	if len(f.selections) == 0 {
+		return fmt.Errorf("%s.%s needs a selection of %s fields", object.Name, f.name, def.Object.Name)
+	}
+	return e.validate(def.Object, f.selections, depth+1, spreading)
+}
+
+// collectFields flattens fragments and drops skipped selections, grouping fields by response
+// key in the order they first appear.
+func (e *executor) collectFields(selections []selection, keys []string, groups map[string][]*field) []string {
+	for _, sel := range selections {
+		if !e.included(sel.directives) {
+			continue
+		}
+		switch {
+		case sel.spread != "":
+			keys = e.collectFields(e.doc.fragments[sel.spread].selections, keys, groups)
+		case sel.inline != nil:
+			keys = e.collectFields(sel.inline.selections, keys, groups)
+		default:
+			key := sel.field.responseKey()
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], sel.field)
+		}
+	}
+	return keys
+}
+
+func (e *executor) included(directives []directive) bool {
+	for _, d := range directives {
+		condition, _ := e.directiveCondition(d)
+		if (d.name == "include" && !condition) || (d.name == "skip" && condition) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *executor) directiveCondition(d directive) (bool, error) {
+	if len(d.args) != 1 || d.args[0].name != "if" {
+		return false, fmt.Errorf("@%s takes one argument, if", d.name)
+	}
+	condition, ok := e.value(d.args[0].value).(bool)
+	if !ok {
+		return false, fmt.Errorf("@%s(if:) must be a Boolean", d.name)
+	}
+	return condition, nil
+}
+
+// value substitutes variables into an argument value.
+func (e *executor) value(v any) any {
+	switch v := v.(type) {
+	case variableRef:
+		return e.variables[string(v)]
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = e.value(item)
+		}
+		return list
+	case objectValue:
+		object := make(map[string]any, len(v))
+		for name, item := range v {
+			object[name] = e.value(item)
+		}
+		return object
+	}
+	return v
+}
+
+func (e *executor) executeObject(ctx context.Context, object *Object, source any, selections []selection, path []any) *orderedObject {
+	groups := map[string][]*field{}
+	keys := e.collectFields(selections, nil, groups)
+
+	result := &orderedObject{keys: keys, values: make([]any, len(keys))}
+	for i, key := range keys {
+		fields := groups[key]
+		f := fields[0]
+		if f.name == "__typename" {
+			result.values[i] = object.Name
+			continue
+		}
+		fieldPath := append(slices.Clip(path), key)
+		result.values[i] = e.executeField(ctx, object.field(f.name), source, fields, fieldPath)
+	}
+	return result
+}
+
+func (e *executor) executeField(ctx context.Context, def *Field, source any, fields []*field, path []any) any {
+	args := Args{}
+	for _, arg := range fields[0].args {
+		args[arg.name] = e.value(arg.value)
+	}
+	if err := ctx.Err(); err != nil {
+		e.fail(err, path)
+		return nil
+	}
+	value, err := def.Resolve(ctx, source, args)
+	if err != nil {
+		e.fail(err, path)
+		return nil
+	}
+	if def.Object == nil {
+		return value
+	}
+
+	var selections []selection
+	for _, f := range fields {
+		selections = append(selections, f.selections...)
+	}
+	return e.completeValue(ctx, def.Object, value, selections, path)
+}
+
+// completeValue resolves the selections on an object value, or on each element of a slice.
+func (e *executor) completeValue(ctx context.Context, object *Object, value any, selections []selection, path []any) any {
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+	case reflect.Slice:
+		list := make([]any, rv.Len())
+		for i := range list {
+			list[i] = e.completeValue(ctx, object, rv.Index(i).Interface(), selections, append(slices.Clip(path), i))
+		}
+		return list
+	}
+	return e.executeObject(ctx, object, value, selections, path)
+}
+
+func (e *executor) fail(err error, path []any) {
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+}
+
+// orderedObject keeps fields in the order the query selected them, as GraphQL responses do.
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// SDL describes the schema in the GraphQL schema definition language, starting from Query.
+func (s *Schema) SDL() string {
+	var b strings.Builder
+	seen := map[*Object]bool{}
+	var write func(object *Object)
+	write = func(object *Object) {
+		if seen[object] {
+			return
+		}
+		seen[object] = true
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		writeDescription(&b, "", object.Description)
+		b.WriteString("type " + object.Name + " {\n")
+		for _, f := range object.Fields {
+			writeDescription(&b, "  ", f.Description)
+			b.WriteString("  " + f.Name)
+			if len(f.Args) > 0 {
+				args := make([]string, len(f.Args))
+				for i, arg := range f.Args {
+					args[i] = arg.Name + ": " + arg.Type
+				}
+				b.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			b.WriteString(": " + f.Type + "\n")
+		}
+		b.WriteString("}\n")
+		for _, f := range object.Fields {
+			if f.Object != nil {
+				write(f.Object)
+			}
+		}
+	}
+	write(s.Query)
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	b.WriteString(indent + `"""` + description + `"""` + "\n")
+}
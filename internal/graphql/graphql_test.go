@@ -0,0 +1,174 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testBook struct {
+	Title string
+}
+
+func testSchema() *Schema {
+	book := &Object{Name: "Book", Fields: []*Field{
+		{Name: "title", Type: "String!", Resolve: func(_ context.Context, source any, _ Args) (any, error) {
+			return source.(testBook).Title, nil
+		}},
+		{Name: "secret", Type: "String", Resolve: func(context.Context, any, Args) (any, error) {
+			return nil, ErrForbidden
+		}},
+	}}
+	books := []testBook{{Title: "Dune"}, {Title: "Emma"}, {Title: "Ulysses"}}
+	query := &Object{Name: "Query", Fields: []*Field{
+		{Name: "books", Type: "[Book!]!", Object: book, Args: []Arg{{Name: "first", Type: "Int"}}, Resolve: func(_ context.Context, _ any, args Args) (any, error) {
+			first, ok, err := args.Int("first")
+			if err != nil {
+				return nil, err
+			}
+			if ok && first < len(books) {
+				return books[:first], nil
+			}
+			return books, nil
+		}},
+		{Name: "book", Type: "Book", Object: book, Args: []Arg{{Name: "title", Type: "String!"}}, Resolve: func(_ context.Context, _ any, args Args) (any, error) {
+			title, _, err := args.String("title")
+			if err != nil {
+				return nil, err
+			}
+			for _, b := range books {
+				if b.Title == title {
+					return b, nil
+				}
+			}
+			return nil, nil
+		}},
+	}}
+	return &Schema{Query: query}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		request   Request
+		wantJSON  string
+		wantError string
+	}{
+		{
+			name:     "aliases keep selection order",
+			request:  Request{Query: `{ z: books(first: 1) { title } a: book(title: "Emma") { title __typename } }`},
+			wantJSON: `{"data":{"z":[{"title":"Dune"}],"a":{"title":"Emma","__typename":"Book"}}}`,
+		},
+		{
+			name:     "variables and defaults",
+			request:  Request{Query: `query Q($n: Int = 2, $t: String!) { books(first: $n) { title } book(title: $t) { title } }`, Variables: map[string]any{"t": "Nope"}},
+			wantJSON: `{"data":{"books":[{"title":"Dune"},{"title":"Emma"}],"book":null}}`,
+		},
+		{
+			name:     "fragments and directives",
+			request:  Request{Query: `query { books(first: 2) { ...F ... on Book @skip(if: true) { secret } } } fragment F on Book { title }`},
+			wantJSON: `{"data":{"books":[{"title":"Dune"},{"title":"Emma"}]}}`,
+		},
+		{
+			name:     "forbidden field is null with an error",
+			request:  Request{Query: `{ book(title: "Dune") { title secret } }`},
+			wantJSON: `{"data":{"book":{"title":"Dune","secret":null}},"errors":[{"message":"not authorized to read this field","path":["book","secret"]}]}`,
+		},
+		{name: "unknown field", request: Request{Query: `{ books { isbn } }`}, wantError: "Book has no field isbn"},
+		{name: "missing required argument", request: Request{Query: `{ book { title } }`}, wantError: "requires argument title"},
+		{name: "missing required variable", request: Request{Query: `query($t: String!) { book(title: $t) { title } }`}, wantError: "variable $t of type String! is required"},
+		{name: "leaf without selections", request: Request{Query: `{ books }`}, wantError: "needs a selection of Book fields"},
+		{name: "mutations are rejected", request: Request{Query: `mutation { books { title } }`}, wantError: "read-only"},
+		{name: "introspection is rejected", request: Request{Query: `{ __schema { types { name } } }`}, wantError: "introspection is not supported"},
+		{name: "fragment cycle", request: Request{Query: `{ books { ...A } } fragment A on Book { ...A }`}, wantError: "spreads itself"},
+		{name: "syntax error", request: Request{Query: `{ books { title }`}, wantError: "Syntax error"},
+		{name: "ambiguous operation", request: Request{Query: `query A { books { title } } query B { books { title } }`}, wantError: "operationName is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			response := testSchema().Execute(context.Background(), tt.request)
+			encoded, err := json.Marshal(response)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if tt.wantError != "" {
+				if response.Data != nil || len(response.Errors) != 1 || !strings.Contains(response.Errors[0].Message, tt.wantError) {
+					t.Fatalf("expected only an error containing %q, got %s", tt.wantError, encoded)
+				}
+				return
+			}
+			if string(encoded) != tt.wantJSON {
+				t.Fatalf("response = %s, want %s", encoded, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestExecute_Limits(t *testing.T) {
+	t.Parallel()
+
+	node := &Object{Name: "Node"}
+	node.Fields = []*Field{
+		{Name: "id", Type: "Int!", Resolve: func(context.Context, any, Args) (any, error) { return 1, nil }},
+		{Name: "child", Type: "Node!", Object: node, Resolve: func(context.Context, any, Args) (any, error) { return struct{}{}, nil }},
+	}
+	schema := &Schema{Query: &Object{Name: "Query", Fields: []*Field{
+		{Name: "node", Type: "Node!", Object: node, Resolve: func(context.Context, any, Args) (any, error) { return struct{}{}, nil }},
+	}}}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantError string
+	}{
+		{name: "at the depth limit", query: "{ node " + strings.Repeat("{ child ", maxDepth-2) + "{ id }" + strings.Repeat(" }", maxDepth-2) + " }"},
+		{name: "past the depth limit", query: "{ node " + strings.Repeat("{ child ", maxDepth-1) + "{ id }" + strings.Repeat(" }", maxDepth-1) + " }", wantError: "nested more than"},
+		{name: "fragment bomb", query: fragmentBomb(12), wantError: "selects more than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			response := schema.Execute(context.Background(), Request{Query: tt.query})
+			if tt.wantError == "" {
+				if len(response.Errors) != 0 {
+					t.Fatalf("unexpected error: %s", response.Errors[0].Message)
+				}
+				return
+			}
+			if len(response.Errors) != 1 || !strings.Contains(response.Errors[0].Message, tt.wantError) {
+				t.Fatalf("expected an error containing %q, got %+v", tt.wantError, response.Errors)
+			}
+		})
+	}
+}
+
+// fragmentBomb builds a query whose fragments each spread the next one twice, selecting 2^levels
+// fields from a few lines of text.
+func fragmentBomb(levels int) string {
+	var b strings.Builder
+	b.WriteString("{ node { ...F0 } }\n")
+	for i := range levels {
+		fmt.Fprintf(&b, "fragment F%d on Node { ...F%d ...F%d }\n", i, i+1, i+1)
+	}
+	fmt.Fprintf(&b, "fragment F%d on Node { id }\n", levels)
+	return b.String()
+}
+
+func TestSchemaSDL(t *testing.T) {
+	t.Parallel()
+
+	sdl := testSchema().SDL()
+	for _, want := range []string{"type Query {\n  books(first: Int): [Book!]!\n  book(title: String!): Book\n}", "type Book {\n  title: String!\n  secret: String\n}"} {
+		if !strings.Contains(sdl, want) {
+			t.Fatalf("SDL missing %q:\n%s", want, sdl)
+		}
+	}
+}
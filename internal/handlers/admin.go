@@ -6,11 +6,14 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 
 	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/money"
 	"github.com/gitshopapp/gitshop/internal/services"
 	"github.com/gitshopapp/gitshop/ui/views"
 )
@@ -106,7 +109,7 @@ func (h *Handlers) AdminSetup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stripeReady := h.adminService.IsStripeReady(ctx, shop)
+	stripeReady := h.adminService.IsPaymentReady(ctx, shop)
 	needsStripe := !stripeReady
 	needsEmail := !services.IsEmailConfigured(shop)
 
@@ -120,9 +123,20 @@ func (h *Handlers) AdminSetup(w http.ResponseWriter, r *http.Request) {
 		repoCount = len(shops)
 	}
 
-	labelsStatus, yamlStatus, templateStatus, setupComplete := h.buildSetupStatus(ctx, shop, r.URL.Query(), stripeReady)
+	labelsStatus, yamlStatus, templateStatus, emailTemplatesStatus, setupComplete := h.buildSetupStatus(ctx, shop, r.URL.Query(), stripeReady)
+	if templateStatus != nil && !templateStatus.Exists && templateStatus.Method != "pr" {
+		candidates, err := h.adminService.AnalyzeIssueTemplates(ctx, shop)
+		if err != nil {
+			logger.Warn("failed to analyze existing issue templates", "error", err, "shop_id", shop.ID)
+		}
+		templateStatus.Candidates = issueTemplateCandidatesToView(candidates)
+	}
+	bootstrap := setupPullRequestStatusToView(h.adminService.SetupPullRequestStatus(ctx, shop))
+	if errMsg := r.URL.Query().Get("bootstrap_error"); errMsg != "" {
+		bootstrap.ErrorMessage = errMsg
+	}
 
-	if err := views.SetupPage(needsStripe, needsEmail, labelsStatus, yamlStatus, templateStatus, shop, ownerName, repoCount, setupComplete).Render(ctx, w); err != nil {
+	if err := views.SetupPage(needsStripe, needsEmail, labelsStatus, yamlStatus, templateStatus, emailTemplatesStatus, bootstrap, shop, h.paymentProcessorSettings(ctx, shop), ownerName, repoCount, setupComplete).Render(ctx, w); err != nil {
 		logger.Error("failed to render setup page", "error", err)
 	}
 }
@@ -139,6 +153,56 @@ func (h *Handlers) AdminSetupComplete(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin/dashboard?toast=order_shipped", http.StatusSeeOther)
 }
 
+// AdminSetupBootstrap creates the labels and opens one pull request with every setup file.
+func (h *Handlers) AdminSetupBootstrap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.setup.bootstrap",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	if _, err := h.adminService.BootstrapRepo(ctx, shop); err != nil {
+		h.loggerFromContext(ctx).Warn("failed to bootstrap repository", "error", err, "shop_id", shop.ID)
+		http.Redirect(w, r, "/admin/setup?bootstrap_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/setup", http.StatusSeeOther)
+}
+
+// AdminSetupConvertTemplate opens a pull request turning an existing issue form into the order
+// template.
+func (h *Handlers) AdminSetupConvertTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.setup.template.convert",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.adminService.ConvertIssueTemplate(ctx, shop, r.FormValue("path"))
+	if err != nil {
+		http.Redirect(w, r, "/admin/setup?template_error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/admin/setup?template_pr="+url.QueryEscape(result.URL), http.StatusSeeOther)
+}
+
 func (h *Handlers) AdminSetupLabels(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
@@ -159,6 +223,41 @@ func (h *Handlers) AdminSetupLabels(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin/setup", http.StatusSeeOther)
 }
 
+// AdminSetupSimulate runs a simulated order through the shop's repo and renders the report.
+func (h *Handlers) AdminSetupSimulate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.setup.simulate",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	view := views.OrderSimulationReport{}
+	report, err := h.adminService.SimulateOrder(ctx, shop)
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to simulate order", "error", err, "shop_id", shop.ID)
+		view.ErrorMessage = "Failed to run the simulation"
+	}
+	view.Passed = report.Passed()
+	view.IssueURL = report.IssueURL
+	for _, step := range report.Steps {
+		view.Steps = append(view.Steps, views.OrderSimulationStep(step))
+	}
+
+	if err := views.OrderSimulationResult(view).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render order simulation", "error", err)
+	}
+}
+
 func (h *Handlers) AdminSetupYAML(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
@@ -245,6 +344,34 @@ func (h *Handlers) AdminSyncTemplate(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/admin/dashboard", http.StatusSeeOther)
 }
 
+var dashboardToasts = map[string]views.ToastPayload{
+	"appeal_sent": {
+		Title:       "Appeal sent",
+		Description: "The GitShop team will review it. Until then the shop stays suspended.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"order_shipped": {
+		Title:       "Order marked as shipped",
+		Description: "Customer tracking details were saved and sent.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"customer_not_found": {
+		Title:       "No customer data found",
+		Description: "No orders in this shop match that email address or GitHub username.",
+		Variant:     views.ToastVariantWarning,
+	},
+	"customer_invalid": {
+		Title:       "Export failed",
+		Description: "Enter the email address the customer paid with or their GitHub username.",
+		Variant:     views.ToastVariantError,
+	},
+	"checkout_resent": {
+		Title:       "Checkout link sent",
+		Description: "A new checkout link was posted on the order issue.",
+		Variant:     views.ToastVariantSuccess,
+	},
+}
+
 func (h *Handlers) AdminDashboard(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
@@ -260,19 +387,48 @@ func (h *Handlers) AdminDashboard(w http.ResponseWriter, r *http.Request) {
 	shopSwitcher := h.buildShopSwitcher(ctx, sess)
 
 	var toastPayload *views.ToastPayload
-	if r.URL.Query().Get("toast") == "order_shipped" {
-		toastPayload = &views.ToastPayload{
-			Title:       "Order marked as shipped",
-			Description: "Customer tracking details were saved and sent.",
-			Variant:     views.ToastVariantSuccess,
-		}
+	if payload, ok := dashboardToasts[r.URL.Query().Get("toast")]; ok {
+		toastPayload = &payload
+	}
+
+	suspension, err := h.adminService.ShopSuspension(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load shop suspension", "error", err, "shop_id", shop.ID)
+		suspension = nil
+	}
+
+	now := time.Now()
+	overdue, err := h.adminService.OverdueShipments(ctx, shop.ID, now)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load overdue shipments", "error", err, "shop_id", shop.ID)
+		overdue = nil
 	}
 
-	if err := views.DashboardPage(shop, toastPayload, shopSwitcher).Render(ctx, w); err != nil {
+	if err := views.DashboardPage(shop, suspension, overdueShipmentsToView(overdue, now), toastPayload, shopSwitcher).Render(ctx, w); err != nil {
 		h.loggerFromContext(ctx).Error("failed to render dashboard page", "error", err)
 	}
 }
 
+func overdueShipmentsToView(overdue *services.OverdueShipments, now time.Time) *views.OverdueShipments {
+	if overdue == nil {
+		return nil
+	}
+	result := &views.OverdueShipments{
+		ShipWithinDays: overdue.ShipWithinDays,
+		Total:          overdue.Total,
+		Orders:         make([]views.OverdueOrder, 0, len(overdue.Orders)),
+	}
+	for _, order := range overdue.Orders {
+		result.Orders = append(result.Orders, views.OverdueOrder{
+			ID:          order.ID.String(),
+			OrderNumber: order.OrderNumber,
+			Total:       money.Format(order.TotalCents),
+			DaysWaiting: int(now.Sub(order.PaidAt) / (24 * time.Hour)),
+		})
+	}
+	return result
+}
+
 func (h *Handlers) AdminDashboardStorefront(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
@@ -285,7 +441,18 @@ func (h *Handlers) AdminDashboardStorefront(w http.ResponseWriter, r *http.Reque
 	}
 	shop := contextResult.Shop
 
-	repoStatus := h.buildRepoStatus(ctx, shop)
+	snapshot := h.repoStatusService.Snapshot(ctx, shop, r.URL.Query().Get("refresh") == "1")
+	repoStatus := repoStatusToView(snapshot.Status)
+	if repoStatus != nil {
+		repoStatus.BadgeMarkdown = badgeMarkdown(h.config.BaseURL, shop.GitHubRepoFullName)
+		repoStatus.CheckedAtLabel = snapshot.CheckedLabel()
+		repoStatus.Refreshing = snapshot.Refreshing
+		ratings, err := h.adminService.ProductRatings(ctx, shop.ID)
+		if err != nil {
+			h.loggerFromContext(ctx).Warn("failed to load product ratings", "error", err, "shop_id", shop.ID)
+		}
+		applyProductRatings(repoStatus.Products, ratings)
+	}
 	if err := views.DashboardStorefrontSection(repoStatus).Render(ctx, w); err != nil {
 		h.loggerFromContext(ctx).Error("failed to render dashboard storefront", "error", err)
 	}
@@ -308,12 +475,151 @@ func (h *Handlers) AdminDashboardOrders(w http.ResponseWriter, r *http.Request)
 		h.loggerFromContext(ctx).Error("failed to get orders", "error", err, "shop_id", shop.ID)
 		orders = []*db.Order{}
 	}
+	firstOrder, err := h.adminService.FirstOrderAwaitingShipment(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load first order", "error", err, "shop_id", shop.ID)
+	}
 
-	if err := views.DashboardOrdersSection(orders).Render(ctx, w); err != nil {
+	subscriptions, err := h.adminService.GetActiveSubscriptions(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load active subscriptions", "error", err, "shop_id", shop.ID)
+	}
+
+	if err := views.DashboardOrdersSection(orders, firstOrder, subscriptions).Render(ctx, w); err != nil {
 		h.loggerFromContext(ctx).Error("failed to render dashboard orders", "error", err)
 	}
 }
 
+// AdminDashboardAbandoned renders the abandoned checkouts panel. It renders nothing when no
+// checkout expired recently.
+func (h *Handlers) AdminDashboardAbandoned(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.dashboard.abandoned",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	now := time.Now()
+	abandoned, err := h.adminService.AbandonedCheckouts(ctx, shop.ID, now)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load abandoned checkouts", "error", err, "shop_id", shop.ID)
+		abandoned = nil
+	}
+
+	if err := views.DashboardAbandonedSection(abandonedCheckoutsToView(abandoned, now)).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render abandoned checkouts", "error", err)
+	}
+}
+
+func abandonedCheckoutsToView(abandoned *services.AbandonedCheckouts, now time.Time) *views.AbandonedCheckouts {
+	if abandoned == nil {
+		return nil
+	}
+	result := &views.AbandonedCheckouts{
+		Abandoned: abandoned.Summary.Abandoned,
+		Value:     money.Format(abandoned.Summary.ValueCents),
+		Recovered: abandoned.Summary.Recovered,
+		Products:  make([]views.AbandonedProduct, 0, len(abandoned.TopSKUs)),
+		Orders:    make([]views.AbandonedOrder, 0, len(abandoned.Orders)),
+	}
+	if abandoned.Summary.Recovered > 0 {
+		result.RecoveredValue = money.Format(abandoned.Summary.RecoveredCents)
+	}
+	for _, sku := range abandoned.TopSKUs {
+		result.Products = append(result.Products, views.AbandonedProduct{
+			SKU:       sku.SKU,
+			Checkouts: sku.Checkouts,
+			Value:     money.Format(sku.ValueCents),
+		})
+	}
+	for _, order := range abandoned.Orders {
+		result.Orders = append(result.Orders, views.AbandonedOrder{
+			ID:          order.ID.String(),
+			OrderNumber: order.OrderNumber,
+			SKU:         order.SKU,
+			Total:       money.Format(order.TotalCents),
+			DaysAgo:     int(now.Sub(order.CheckoutExpiredAt) / (24 * time.Hour)),
+		})
+	}
+	return result
+}
+
+// AdminDashboardCampaigns renders the campaign revenue panel. It renders nothing until a campaign
+// has priced a paid order.
+func (h *Handlers) AdminDashboardCampaigns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.dashboard.campaigns",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	revenue, err := h.adminService.CampaignRevenue(ctx, shop.ID, time.Now())
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load campaign revenue", "error", err, "shop_id", shop.ID)
+		revenue = nil
+	}
+
+	if err := views.DashboardCampaignsSection(campaignRevenueToView(revenue)).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render campaign revenue", "error", err)
+	}
+}
+
+func campaignRevenueToView(revenue *services.CampaignRevenue) *views.CampaignRevenue {
+	if revenue == nil {
+		return nil
+	}
+	result := &views.CampaignRevenue{
+		Campaigns: make([]views.CampaignSales, 0, len(revenue.Campaigns)),
+		ListPrice: views.CampaignSales{
+			Orders:  revenue.ListPrice.Orders,
+			Revenue: money.Format(revenue.ListPrice.RevenueCents),
+		},
+	}
+	for _, campaign := range revenue.Campaigns {
+		result.Campaigns = append(result.Campaigns, views.CampaignSales{
+			Name:    campaign.Campaign,
+			Orders:  campaign.Orders,
+			Revenue: money.Format(campaign.RevenueCents),
+		})
+	}
+	return result
+}
+
+// AdminDashboardCustomers renders the top customers panel. It renders nothing until a buyer has
+// paid for an order.
+func (h *Handlers) AdminDashboardCustomers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.dashboard.customers",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	customers, err := h.adminService.TopCustomers(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load top customers", "error", err, "shop_id", shop.ID)
+		customers = nil
+	}
+
+	if err := views.DashboardCustomersSection(customersToView(customers)).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render top customers", "error", err)
+	}
+}
+
 func (h *Handlers) htmxRedirect(w http.ResponseWriter, r *http.Request, url string) {
 	if strings.EqualFold(r.Header.Get("HX-Request"), "true") {
 		w.Header().Set("HX-Redirect", url)
@@ -323,15 +629,12 @@ func (h *Handlers) htmxRedirect(w http.ResponseWriter, r *http.Request, url stri
 	http.Redirect(w, r, url, http.StatusSeeOther)
 }
 
-func (h *Handlers) buildRepoStatus(ctx context.Context, shop *db.Shop) *views.RepoStatus {
-	return repoStatusToView(h.adminService.BuildRepoStatus(ctx, shop))
-}
-
-func (h *Handlers) buildSetupStatus(ctx context.Context, shop *db.Shop, query url.Values, stripeReady bool) (*views.RepoLabelsStatus, *views.GitShopYAMLStatus, *views.OrderTemplateStatus, bool) {
+func (h *Handlers) buildSetupStatus(ctx context.Context, shop *db.Shop, query url.Values, stripeReady bool) (*views.RepoLabelsStatus, *views.GitShopYAMLStatus, *views.OrderTemplateStatus, *views.EmailTemplatesStatus, bool) {
 	status := h.adminService.BuildSetupStatus(ctx, shop)
 	labelsStatus := repoLabelsStatusToView(status.Labels)
 	yamlStatus := yamlStatusToView(status.YAML)
 	templateStatus := templateStatusToView(status.Template)
+	emailTemplatesStatus := emailTemplatesStatusToView(status.EmailTemplates)
 
 	if errMsg := query.Get("labels_error"); errMsg != "" && labelsStatus != nil {
 		labelsStatus.ErrorMessage = errMsg
@@ -368,7 +671,37 @@ func (h *Handlers) buildSetupStatus(ctx context.Context, shop *db.Shop, query ur
 	setupComplete = setupComplete && yamlStatus != nil && yamlStatus.Valid
 	setupComplete = setupComplete && templateStatus != nil && templateStatus.Valid
 
-	return labelsStatus, yamlStatus, templateStatus, setupComplete
+	return labelsStatus, yamlStatus, templateStatus, emailTemplatesStatus, setupComplete
+}
+
+func issueTemplateCandidatesToView(candidates []services.IssueTemplateCandidate) []views.IssueTemplateCandidate {
+	result := make([]views.IssueTemplateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		result = append(result, views.IssueTemplateCandidate(candidate))
+	}
+	return result
+}
+
+func setupPullRequestStatusToView(status services.SetupPullRequestStatus) *views.SetupPullRequestStatus {
+	return &views.SetupPullRequestStatus{
+		Available: status.Available,
+		URL:       status.URL,
+		State:     string(status.State),
+	}
+}
+
+// applyProductRatings adds each product's review count and average rating to the catalog summary.
+func applyProductRatings(products []views.ProductSummary, ratings []db.ProductRating) {
+	bySKU := make(map[string]db.ProductRating, len(ratings))
+	for _, rating := range ratings {
+		bySKU[rating.SKU] = rating
+	}
+	for i := range products {
+		if rating, ok := bySKU[products[i].SKU]; ok {
+			products[i].Reviews = rating.Reviews
+			products[i].AverageRating = rating.Average
+		}
+	}
 }
 
 func repoStatusToView(status *services.RepoStatus) *views.RepoStatus {
@@ -387,12 +720,18 @@ func repoStatusToView(status *services.RepoStatus) *views.RepoStatus {
 
 	products := make([]views.ProductSummary, 0, len(status.Products))
 	for _, product := range status.Products {
-		products = append(products, views.ProductSummary{
+		summary := views.ProductSummary{
 			SKU:        product.SKU,
 			Name:       product.Name,
 			PriceCents: product.PriceCents,
 			Active:     product.Active,
-		})
+			Category:   product.Category,
+			OrderURL:   product.OrderURL,
+		}
+		if product.OrderURL != "" {
+			summary.BuyMarkdown = "[Buy " + product.Name + "](" + product.OrderURL + ")"
+		}
+		products = append(products, summary)
 	}
 
 	return &views.RepoStatus{
@@ -412,6 +751,7 @@ func repoStatusToView(status *services.RepoStatus) *views.RepoStatus {
 		TemplateExtraSKUs:        status.TemplateExtraSKUs,
 		TemplatePriceMismatches:  status.TemplatePriceMismatches,
 		TemplateOptionMismatches: status.TemplateOptionMismatches,
+		TemplateWarnings:         status.TemplateWarnings,
 		TemplateSyncAvailable:    status.TemplateSyncAvailable,
 		TemplateSyncMessage:      status.TemplateSyncMessage,
 		Products:                 products,
@@ -433,11 +773,18 @@ func yamlStatusToView(status services.GitShopYAMLStatus) *views.GitShopYAMLStatu
 		Method:           status.Method,
 		URL:              status.URL,
 		ErrorMessage:     status.ErrorMessage,
+		ErrorPath:        status.ErrorPath,
 		LastUpdatedLabel: status.LastUpdatedLabel,
+		UpgradeNotes:     status.UpgradeNotes,
+		AccessNotice:     status.AccessNotice,
 	}
 }
 
 func templateStatusToView(status services.OrderTemplateStatus) *views.OrderTemplateStatus {
+	files := make([]views.OrderTemplateFile, 0, len(status.Files))
+	for _, file := range status.Files {
+		files = append(files, views.OrderTemplateFile{Name: file.Name, URL: file.URL, Valid: file.Valid})
+	}
 	return &views.OrderTemplateStatus{
 		Exists:           status.Exists,
 		Valid:            status.Valid,
@@ -447,12 +794,29 @@ func templateStatusToView(status services.OrderTemplateStatus) *views.OrderTempl
 		UnknownSKUs:      status.UnknownSKUs,
 		PriceMismatches:  status.PriceMismatches,
 		OptionMismatches: status.OptionMismatches,
+		Warnings:         status.Warnings,
 		SyncAvailable:    status.SyncAvailable,
 		SyncMessage:      status.SyncMessage,
 		LastUpdatedLabel: status.LastUpdatedLabel,
 		Count:            status.Count,
+		Files:            files,
+		MissingSKUs:      status.MissingSKUs,
+		AccessNotice:     status.AccessNotice,
 	}
 }
+
+func emailTemplatesStatusToView(status services.EmailTemplatesStatus) *views.EmailTemplatesStatus {
+	issues := make([]string, 0, len(status.Issues))
+	for _, issue := range status.Issues {
+		issues = append(issues, issue.Path+": "+issue.Message)
+	}
+	return &views.EmailTemplatesStatus{
+		Files:        status.Files,
+		Issues:       issues,
+		ErrorMessage: status.ErrorMessage,
+	}
+}
+
 func (h *Handlers) AdminSettings(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
@@ -467,11 +831,345 @@ func (h *Handlers) AdminSettings(w http.ResponseWriter, r *http.Request) {
 	sess := contextResult.Session
 
 	shopSwitcher := h.buildShopSwitcher(ctx, sess)
-	if err := views.SettingsPage(shop, shopSwitcher).Render(ctx, w); err != nil {
+
+	targets, err := h.adminService.GetCloneTargets(ctx, shop)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to list clone targets", "error", err, "shop_id", shop.ID)
+	}
+	cloneTargets := make([]views.CloneTarget, 0, len(targets))
+	for _, target := range targets {
+		cloneTargets = append(cloneTargets, views.CloneTarget{ShopID: target.ID.String(), RepoFullName: target.GitHubRepoFullName})
+	}
+
+	var webhooks views.WebhookSettings
+	webhookSettings, err := h.webhookService.GetSettings(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load webhook settings", "error", err, "shop_id", shop.ID)
+	} else {
+		webhooks = views.WebhookSettings{
+			URL:        webhookSettings.URL,
+			HasSecret:  webhookSettings.HasSecret,
+			Deliveries: webhookSettings.Deliveries,
+		}
+	}
+
+	processingRegion := views.ProcessingRegionSettings{RetainEmailLog: true}
+	regionSettings, err := h.processingRegionService.GetSettings(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load processing region settings", "error", err, "shop_id", shop.ID)
+	} else {
+		processingRegion = views.ProcessingRegionSettings{
+			Region:         regionSettings.Region,
+			Regions:        regionSettings.Regions,
+			RetainEmailLog: regionSettings.RetainEmailLog,
+		}
+	}
+
+	if err := views.SettingsPage(shop, cloneTargets, h.paymentProcessorSettings(ctx, shop), h.emailDeliverySettings(ctx, shop), webhooks, processingRegion, h.apiKeys(ctx, shop), shopSwitcher).Render(ctx, w); err != nil {
 		h.loggerFromContext(ctx).Error("failed to render settings page", "error", err)
 	}
 }
 
+func (h *Handlers) AdminSettingsWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, ctx, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.webhooks",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	webhookURL := r.FormValue("url")
+	if err := h.webhookService.UpdateSettings(ctx, shop.ID, webhookURL, r.FormValue("secret")); err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderError(w, ctx, userErr.Message)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to update webhook settings", "error", err, "shop_id", shop.ID)
+		h.renderError(w, ctx, "Failed to save webhook settings")
+		return
+	}
+
+	if strings.TrimSpace(webhookURL) == "" {
+		h.renderSuccess(w, ctx, "Webhook removed")
+		return
+	}
+	h.renderSuccess(w, ctx, "Webhook saved")
+}
+
+// emailDeliverySettings loads the email card's webhook URL and from address bounce warning.
+func (h *Handlers) emailDeliverySettings(ctx context.Context, shop *db.Shop) views.EmailDeliverySettings {
+	settings := views.EmailDeliverySettings{
+		WebhookURL: strings.TrimRight(h.config.BaseURL, "/") + "/webhooks/email/" + shop.ID.String(),
+	}
+	bounce, err := h.adminService.GetEmailFromBounce(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load from address bounce", "error", err, "shop_id", shop.ID)
+		return settings
+	}
+	if bounce != nil {
+		settings.FromBouncedAt = &bounce.BouncedAt
+		settings.FromBounceDetail = bounce.Detail
+	}
+	return settings
+}
+
+// paymentProcessorSettings loads the payment processor form. Saved secrets are never sent back
+// to the browser, only whether they exist.
+func (h *Handlers) paymentProcessorSettings(ctx context.Context, shop *db.Shop) views.PaymentProcessorSettings {
+	settings := views.PaymentProcessorSettings{
+		Processor:         string(db.PaymentProcessorStripe),
+		WebhookURL:        strings.TrimRight(h.config.BaseURL, "/") + "/webhooks/payments/" + shop.ID.String(),
+		TestModeAvailable: h.adminService.StripeTestModeAvailable(),
+	}
+	saved, err := h.adminService.GetPaymentSettings(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load payment settings", "error", err, "shop_id", shop.ID)
+		return settings
+	}
+	settings.Processor = string(saved.Processor)
+	settings.StoreID = saved.LemonSqueezy.StoreID
+	settings.VariantID = saved.LemonSqueezy.VariantID
+	settings.HasAPIKey = saved.LemonSqueezy.APIKey != ""
+	settings.HasWebhookSecret = saved.LemonSqueezy.WebhookSecret != ""
+	settings.StripeTestMode = saved.StripeTestMode
+	return settings
+}
+
+func (h *Handlers) AdminSettingsPayments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, ctx, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.payments",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	err := h.adminService.UpdatePaymentSettings(ctx, shop.ID, services.PaymentSettingsInput{
+		Processor:     r.FormValue("processor"),
+		StoreID:       r.FormValue("store_id"),
+		VariantID:     r.FormValue("variant_id"),
+		APIKey:        r.FormValue("api_key"),
+		WebhookSecret: r.FormValue("webhook_secret"),
+	})
+	if err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderError(w, ctx, userErr.Message)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to update payment settings", "error", err, "shop_id", shop.ID)
+		h.renderError(w, ctx, "Failed to save payment processor")
+		return
+	}
+
+	h.renderSuccess(w, ctx, "Payment processor saved")
+}
+
+// AdminSettingsStripeTestMode turns Stripe test mode on or off and re-renders the switch in its
+// new state, or its old state when the switch failed.
+func (h *Handlers) AdminSettingsStripeTestMode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, ctx, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.payments.test_mode",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	enabled := r.FormValue("enabled") == "true"
+	testMode, message, success := enabled, "Live mode on: new orders take real payments", true
+	if enabled {
+		message = "Test mode on: new orders check out with Stripe test cards"
+	}
+	if err := h.adminService.SetStripeTestMode(ctx, shop.ID, enabled); err != nil {
+		testMode, success = !enabled, false
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			message = userErr.Message
+		} else {
+			h.loggerFromContext(ctx).Error("failed to set stripe test mode", "error", err, "shop_id", shop.ID)
+			message = "Failed to switch test mode"
+		}
+	}
+
+	if err := views.StripeTestModeResult(testMode, message, success).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render stripe test mode", "error", err)
+	}
+}
+
+func (h *Handlers) AdminSettingsProcessingRegion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, ctx, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.processing_region",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	retainEmailLog := r.FormValue("email_log") != views.EmailLogRedact
+	if err := h.processingRegionService.UpdateSettings(ctx, shop.ID, r.FormValue("region"), retainEmailLog); err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderError(w, ctx, userErr.Message)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to update processing region", "error", err, "shop_id", shop.ID)
+		h.renderError(w, ctx, "Failed to save processing region settings")
+		return
+	}
+
+	h.renderSuccess(w, ctx, "Processing region saved")
+}
+
+// AdminSettingsDeleteData deletes the shop's orders and customer details at the seller's request
+// and sends them back to the shop list, since the shop is disconnected afterwards.
+func (h *Handlers) AdminSettingsDeleteData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, ctx, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.delete_data",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	if err := h.dataRetentionService.DeleteShopData(ctx, shop, r.FormValue("confirm")); err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderError(w, ctx, userErr.Message)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to delete shop data", "error", err, "shop_id", shop.ID)
+		h.renderError(w, ctx, "Failed to delete shop data")
+		return
+	}
+
+	h.loggerFromContext(ctx).Info("deleted shop data at seller request", "shop_id", shop.ID, "repo", shop.GitHubRepoFullName)
+	h.htmxRedirect(w, r, "/admin/shops")
+}
+
+func (h *Handlers) AdminSettingsClone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, ctx, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.clone",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	targetShopID, err := uuid.Parse(r.FormValue("target_shop_id"))
+	if err != nil {
+		h.renderError(w, ctx, "Select a repository to clone into")
+		return
+	}
+
+	result, err := h.adminService.CloneShop(ctx, services.CloneShopInput{
+		InstallationID: shop.GitHubInstallationID,
+		SourceShopID:   shop.ID,
+		TargetShopID:   targetShopID,
+	})
+	if err != nil {
+		var userErr services.UserError
+		switch {
+		case errors.As(err, &userErr):
+			h.renderError(w, ctx, userErr.Message)
+		case errors.Is(err, services.ErrAdminShopNotFound):
+			h.renderError(w, ctx, "Target shop not found")
+		default:
+			h.loggerFromContext(ctx).Error("failed to clone shop", "error", err, "shop_id", shop.ID, "target_shop_id", targetShopID)
+			h.renderError(w, ctx, "Failed to clone storefront")
+		}
+		return
+	}
+
+	h.renderSuccess(w, ctx, "Clone pull request opened: "+result.PullRequestURL)
+}
+
 func (h *Handlers) AdminSettingsEmail(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -500,8 +1198,10 @@ func (h *Handlers) AdminSettingsEmail(w http.ResponseWriter, r *http.Request) {
 	apiKey := r.FormValue("api_key")
 	from := r.FormValue("from_email")
 	domain := r.FormValue("domain")
+	webhookSecret := r.FormValue("webhook_secret")
 
-	if err := h.adminService.UpdateEmailSettings(ctx, shopID, provider, apiKey, from, domain); err != nil {
+	verified, err := h.adminService.UpdateEmailSettings(ctx, shopID, provider, apiKey, from, domain, webhookSecret)
+	if err != nil {
 		var userErr services.UserError
 		if errors.As(err, &userErr) {
 			h.renderError(w, ctx, userErr.Message)
@@ -515,9 +1215,98 @@ func (h *Handlers) AdminSettingsEmail(w http.ResponseWriter, r *http.Request) {
 	if strings.EqualFold(r.Header.Get("HX-Request"), "true") {
 		w.Header().Set("HX-Trigger", "email-settings-updated")
 	}
+	if !verified {
+		h.renderSuccess(w, ctx, "Email settings saved. Add the DNS records shown to verify your sending domain.")
+		return
+	}
 	h.renderSuccess(w, ctx, "Email settings saved successfully!")
 }
 
+// AdminSettingsEmailDomain renders the sending domain's DNS records and verification status.
+func (h *Handlers) AdminSettingsEmailDomain(w http.ResponseWriter, r *http.Request) {
+	h.renderEmailDomain(w, r, "admin.settings.email.domain", h.adminService.EmailDomainStatus)
+}
+
+// AdminSettingsEmailDomainVerify asks the provider to check the sending domain's DNS again.
+func (h *Handlers) AdminSettingsEmailDomainVerify(w http.ResponseWriter, r *http.Request) {
+	h.renderEmailDomain(w, r, "admin.settings.email.domain.verify", h.adminService.VerifyEmailDomain)
+}
+
+func (h *Handlers) renderEmailDomain(w http.ResponseWriter, r *http.Request, route string, load func(context.Context, *db.Shop) (*email.DomainStatus, error)) {
+	ctx := r.Context()
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  route,
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	var props views.EmailDomainProps
+	status, err := load(ctx, shop)
+	var userErr services.UserError
+	switch {
+	case errors.Is(err, email.ErrDomainVerificationUnsupported):
+		props.Unsupported = true
+	case errors.As(err, &userErr):
+		props.Error = userErr.Message
+	case err != nil:
+		h.loggerFromContext(ctx).Warn("failed to load sending domain", "error", err, "shop_id", shop.ID)
+		props.Error = "Could not load your sending domain from the email provider. Retrying shortly."
+	default:
+		props.Domain = status.Domain
+		props.Verified = status.Verified
+		for _, record := range status.Records {
+			props.Records = append(props.Records, views.EmailDomainRecord(record))
+		}
+	}
+
+	if err := views.EmailDomainStatus(props).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render email domain status", "error", err)
+	}
+}
+
+func (h *Handlers) AdminSettingsEmailTest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.email.test",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	recipient, err := h.adminService.SendTestEmail(ctx, shop)
+	if err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderError(w, ctx, userErr.Message)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to send test email", "error", err, "shop_id", shop.ID)
+		h.renderError(w, ctx, "Failed to send test email")
+		return
+	}
+
+	h.renderSuccess(w, ctx, "Test email sent to "+recipient)
+}
+
 func (h *Handlers) AdminShipOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
@@ -578,5 +1367,9 @@ func (h *Handlers) AdminShipOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.Redirect(w, r, "/admin/dashboard", http.StatusSeeOther)
+	redirectURL := "/admin/dashboard"
+	if r.FormValue("redirect_to") == orderDetailPath(orderID) {
+		redirectURL = orderDetailPath(orderID) + "?toast=order_shipped"
+	}
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
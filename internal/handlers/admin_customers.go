@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+var customerDetailToasts = map[string]views.ToastPayload{
+	"notes_saved": {
+		Title:       "Notes saved",
+		Description: "Only shop admins can see them.",
+		Variant:     views.ToastVariantSuccess,
+	},
+}
+
+func customerPath(customerID uuid.UUID) string {
+	return "/admin/customers/" + customerID.String()
+}
+
+// AdminCustomers lists the shop's buyers by lifetime value.
+func (h *Handlers) AdminCustomers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.customers",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	customers, err := h.adminService.ListCustomers(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to list customers", "error", err, "shop_id", shop.ID)
+		http.Error(w, "Failed to load customers", http.StatusInternalServerError)
+		return
+	}
+
+	shopSwitcher := h.buildShopSwitcher(ctx, contextResult.Session)
+	if err := views.CustomersPage(shop, customersToView(customers), shopSwitcher).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render customers page", "error", err)
+	}
+}
+
+// AdminCustomerDetail shows one buyer with their notes and order history.
+func (h *Handlers) AdminCustomerDetail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.customers.detail",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := h.adminService.GetCustomerDetail(ctx, shop.ID, customerID)
+	if err != nil {
+		if errors.Is(err, services.ErrAdminCustomerNotFound) {
+			http.Error(w, "Customer not found", http.StatusNotFound)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to load customer", "error", err, "customer_id", customerID, "shop_id", shop.ID)
+		http.Error(w, "Failed to load customer", http.StatusInternalServerError)
+		return
+	}
+
+	var toastPayload *views.ToastPayload
+	if payload, ok := customerDetailToasts[r.URL.Query().Get("toast")]; ok {
+		toastPayload = &payload
+	}
+
+	shopSwitcher := h.buildShopSwitcher(ctx, contextResult.Session)
+	if err := views.CustomerDetailPage(shop, customerDetailToView(detail), toastPayload, shopSwitcher).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render customer page", "error", err)
+	}
+}
+
+// AdminCustomerNotes saves the seller's private notes on a buyer.
+func (h *Handlers) AdminCustomerNotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.customers.notes",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	customerID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.UpdateCustomerNotes(ctx, services.CustomerNotesInput{
+		ShopID:     shopID,
+		CustomerID: customerID,
+		Notes:      r.FormValue("notes"),
+		UpdatedBy:  contextResult.Session.GitHubUsername,
+	})
+	if err != nil {
+		var userErr services.UserError
+		switch {
+		case errors.Is(err, services.ErrAdminCustomerNotFound):
+			http.Error(w, "Customer not found", http.StatusNotFound)
+		case errors.As(err, &userErr):
+			http.Error(w, userErr.Message, http.StatusUnprocessableEntity)
+		default:
+			h.loggerFromContext(ctx).Error("failed to save customer notes", "error", err, "customer_id", customerID, "shop_id", shopID)
+			http.Error(w, "Failed to save notes", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, customerPath(customerID)+"?toast=notes_saved", http.StatusSeeOther)
+}
+
+func customersToView(customers []*db.Customer) []views.CustomerSummary {
+	result := make([]views.CustomerSummary, 0, len(customers))
+	for _, customer := range customers {
+		result = append(result, customerSummaryToView(customer))
+	}
+	return result
+}
+
+func customerSummaryToView(customer *db.Customer) views.CustomerSummary {
+	return views.CustomerSummary{
+		ID:             customer.ID.String(),
+		GitHubUsername: customer.GitHubUsername,
+		Orders:         customer.Orders,
+		LifetimeValue:  money.Format(customer.LifetimeValueCents),
+		FirstOrderAt:   customer.FirstOrderAt,
+		LastOrderAt:    customer.LastOrderAt,
+	}
+}
+
+func customerDetailToView(detail *services.CustomerDetail) *views.CustomerDetail {
+	return &views.CustomerDetail{
+		CustomerSummary: customerSummaryToView(detail.Customer),
+		Email:           detail.Email,
+		Notes:           detail.Customer.Notes,
+		NotesUpdatedBy:  detail.Customer.NotesUpdatedBy,
+		History:         detail.Orders,
+	}
+}
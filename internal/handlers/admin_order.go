@@ -0,0 +1,578 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/address"
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+var orderDetailToasts = map[string]views.ToastPayload{
+	"order_shipped": {
+		Title:       "Order marked as shipped",
+		Description: "Customer tracking details were saved and sent.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"order_approved": {
+		Title:       "Order approved",
+		Description: "A checkout link was posted on the order issue.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"address_updated": {
+		Title:       "Shipping address saved",
+		Description: "The order can ship to the new address.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"order_rejected": {
+		Title:       "Order rejected",
+		Description: "The order was cancelled and the buyer was told on the issue.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"subscription_cancelled": {
+		Title:       "Subscription cancelled",
+		Description: "The buyer will not be charged again and was told on the issue.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"order_delivered": {
+		Title:       "Order marked as delivered",
+		Description: "The customer was notified that the order arrived.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"order_refunded": {
+		Title:       "Order refunded",
+		Description: "The payment was refunded in Stripe.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"order_returned": {
+		Title:       "Order marked as returned",
+		Description: "The customer was notified that the return was received.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"inquiry_converted": {
+		Title:       "Quote sent",
+		Description: "The buyer can now pay for this order.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"order_email_sent": {
+		Title:       "Email sent",
+		Description: "The customer email was sent again.",
+		Variant:     views.ToastVariantSuccess,
+	},
+}
+
+func orderDetailPath(orderID uuid.UUID) string {
+	return "/admin/orders/" + orderID.String()
+}
+
+func (h *Handlers) AdminOrderDetail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.orders.detail",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := h.adminService.GetOrderDetail(ctx, services.OrderActionInput{ShopID: shop.ID, OrderID: orderID})
+	if err != nil {
+		if errors.Is(err, services.ErrAdminOrderNotFound) {
+			http.Error(w, "Order not found", http.StatusNotFound)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to load order detail", "error", err, "order_id", orderID, "shop_id", shop.ID)
+		http.Error(w, "Failed to load order", http.StatusInternalServerError)
+		return
+	}
+
+	var toastPayload *views.ToastPayload
+	if payload, ok := orderDetailToasts[r.URL.Query().Get("toast")]; ok {
+		toastPayload = &payload
+	}
+
+	detailView := orderDetailToView(detail)
+	detailView.OpenShipDialog = detail.CanShip && r.URL.Query().Get("action") == "ship"
+
+	shopSwitcher := h.buildShopSwitcher(ctx, contextResult.Session)
+	if err := views.OrderDetailPage(shop, detailView, toastPayload, shopSwitcher).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render order detail page", "error", err)
+	}
+}
+
+func (h *Handlers) AdminMarkDelivered(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.deliver",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.MarkDelivered(ctx, services.OrderActionInput{ShopID: shopID, OrderID: orderID})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only shipped orders can be marked as delivered", http.StatusConflict)
+		default:
+			h.loggerFromContext(ctx).Error("failed to mark order as delivered", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to mark order as delivered", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=order_delivered", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminApproveOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.approve",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.ApproveOrder(ctx, services.ApproveOrderInput{ShopID: shopID, OrderID: orderID, ApprovedBy: contextResult.Session.GitHubUsername})
+	if err != nil {
+		var userErr services.UserError
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrOrderNotAwaitingApproval), errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only orders awaiting approval can be approved", http.StatusConflict)
+		case errors.Is(err, services.ErrShopSuspended):
+			http.Error(w, shopSuspendedMessage, http.StatusForbidden)
+		case errors.As(err, &userErr):
+			http.Error(w, userErr.Message, http.StatusUnprocessableEntity)
+		default:
+			h.loggerFromContext(ctx).Error("failed to approve order", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to approve order", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=order_approved", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminUpdateOrderAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.address",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	shipTo := address.FromMap(map[string]any{
+		"line1":       r.FormValue("line1"),
+		"line2":       r.FormValue("line2"),
+		"city":        r.FormValue("city"),
+		"state":       r.FormValue("state"),
+		"postal_code": r.FormValue("postal_code"),
+		"country":     r.FormValue("country"),
+	})
+	err = h.adminService.UpdateShippingAddress(ctx, services.UpdateShippingAddressInput{ShopID: shopID, OrderID: orderID, Address: shipTo})
+	if err != nil {
+		var userErr services.UserError
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only paid orders that have not shipped can change address", http.StatusConflict)
+		case errors.As(err, &userErr):
+			http.Error(w, userErr.Message, http.StatusUnprocessableEntity)
+		default:
+			h.loggerFromContext(ctx).Error("failed to update shipping address", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to update shipping address", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=address_updated", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminRejectOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.reject",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.RejectOrder(ctx, services.OrderActionInput{ShopID: shopID, OrderID: orderID})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrOrderNotAwaitingApproval), errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only orders awaiting approval can be rejected", http.StatusConflict)
+		default:
+			h.loggerFromContext(ctx).Error("failed to reject order", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to reject order", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=order_rejected", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminCancelSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.cancel_subscription",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.CancelSubscription(ctx, services.OrderActionInput{ShopID: shopID, OrderID: orderID})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrOrderNotSubscribed), errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only active subscriptions can be cancelled", http.StatusConflict)
+		case errors.Is(err, services.ErrAdminSubscriptionUnavailable):
+			http.Error(w, "Subscriptions are not available", http.StatusServiceUnavailable)
+		default:
+			h.loggerFromContext(ctx).Error("failed to cancel subscription", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to cancel subscription", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=subscription_cancelled", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminRefundOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.refund",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.RefundOrder(ctx, services.OrderActionInput{ShopID: shopID, OrderID: orderID})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrAdminOrderNotRefundable), errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only paid, shipped, or delivered orders can be refunded", http.StatusConflict)
+		case errors.Is(err, services.ErrAdminRefundUnavailable):
+			http.Error(w, "Refunds are unavailable until Stripe is connected", http.StatusServiceUnavailable)
+		default:
+			h.loggerFromContext(ctx).Error("failed to refund order", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to refund order", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=order_refunded", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminReturnOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.return",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.ReturnOrder(ctx, services.ReturnOrderInput{
+		ShopID:  shopID,
+		OrderID: orderID,
+		Refund:  r.FormValue("refund") == "1",
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrOrderNotReturnable), errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only shipped or delivered orders can be returned", http.StatusConflict)
+		case errors.Is(err, services.ErrAdminRefundUnavailable):
+			http.Error(w, "This order cannot be refunded through Stripe", http.StatusServiceUnavailable)
+		default:
+			h.loggerFromContext(ctx).Error("failed to return order", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to return order", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=order_returned", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminResendOrderEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.resend_email",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.adminService.ResendOrderEmail(ctx, services.OrderActionInput{ShopID: shopID, OrderID: orderID}); err != nil {
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrAdminEmailUnavailable):
+			http.Error(w, "No customer email can be sent for this order", http.StatusConflict)
+		default:
+			h.loggerFromContext(ctx).Error("failed to resend order email", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to send email", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=order_email_sent", http.StatusSeeOther)
+}
+
+func (h *Handlers) AdminConvertInquiry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.convert",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	subtotalCents, err := services.ParseQuoteCents(r.FormValue("subtotal"))
+	if err != nil {
+		http.Error(w, "Enter the quoted subtotal as a dollar amount", http.StatusBadRequest)
+		return
+	}
+	shippingCents, err := services.ParseQuoteCents(r.FormValue("shipping"))
+	if err != nil {
+		http.Error(w, "Enter shipping as a dollar amount", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.ConvertInquiry(ctx, services.ConvertInquiryInput{
+		ShopID:        shopID,
+		OrderID:       orderID,
+		Method:        services.InquiryConversionMethod(r.FormValue("method")),
+		SubtotalCents: subtotalCents,
+		ShippingCents: shippingCents,
+		CustomerEmail: r.FormValue("customer_email"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrAdminInvalidQuote):
+			http.Error(w, "Enter a positive subtotal, and a customer email for invoices", http.StatusBadRequest)
+		case errors.Is(err, services.ErrAdminOrderNotInquiry), errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only open inquiries can be quoted", http.StatusConflict)
+		case errors.Is(err, services.ErrAdminInquiryUnavailable):
+			http.Error(w, "Quotes are unavailable until Stripe is connected", http.StatusServiceUnavailable)
+		case errors.Is(err, services.ErrShopSuspended):
+			http.Error(w, shopSuspendedMessage, http.StatusForbidden)
+		default:
+			h.loggerFromContext(ctx).Error("failed to convert inquiry", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to send quote", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, orderDetailPath(orderID)+"?toast=inquiry_converted", http.StatusSeeOther)
+}
+
+// AdminResendCheckout sends an order whose checkout expired a new checkout link from the
+// abandoned checkouts panel.
+func (h *Handlers) AdminResendCheckout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.orders.resend_checkout",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shopID := contextResult.Shop.ID
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.adminService.ResendCheckoutLink(ctx, services.OrderActionInput{ShopID: shopID, OrderID: orderID})
+	if err != nil {
+		var userErr services.UserError
+		switch {
+		case errors.Is(err, services.ErrAdminOrderNotFound):
+			http.Error(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrAdminOrderStatusConflict):
+			http.Error(w, "Only orders whose checkout expired can get a new link", http.StatusConflict)
+		case errors.Is(err, services.ErrShopSuspended):
+			http.Error(w, shopSuspendedMessage, http.StatusForbidden)
+		case errors.As(err, &userErr):
+			http.Error(w, userErr.Message, http.StatusUnprocessableEntity)
+		default:
+			h.loggerFromContext(ctx).Error("failed to resend checkout link", "error", err, "order_id", orderID, "shop_id", shopID)
+			http.Error(w, "Failed to send a new checkout link", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, "/admin/dashboard?toast=checkout_resent", http.StatusSeeOther)
+}
+
+func orderDetailToView(detail *services.OrderDetail) *views.OrderDetail {
+	timeline := make([]views.OrderTimelineEntry, 0, len(detail.Timeline))
+	for _, entry := range detail.Timeline {
+		timeline = append(timeline, views.OrderTimelineEntry{Label: entry.Label, At: entry.At})
+	}
+
+	emails := make([]views.OrderEmailEntry, 0, len(detail.Emails))
+	for _, sent := range detail.Emails {
+		if sent == nil {
+			continue
+		}
+		emails = append(emails, views.OrderEmailEntry{
+			Kind:           sent.Kind,
+			Recipient:      sent.Recipient,
+			SentAt:         sent.SentAt,
+			DeliveryStatus: sent.DeliveryStatus,
+			DeliveryDetail: sent.DeliveryDetail,
+		})
+	}
+
+	events := make([]views.OrderEventEntry, 0, len(detail.Events))
+	for _, event := range detail.Events {
+		events = append(events, views.OrderEventEntry{Kind: event.Kind, Detail: event.Detail, At: event.CreatedAt})
+	}
+
+	notes := make([]views.OrderNoteEntry, 0, len(detail.Notes))
+	for _, note := range detail.Notes {
+		notes = append(notes, views.OrderNoteEntry{Body: note.Body, Author: note.Author, CreatedAt: note.CreatedAt})
+	}
+
+	return &views.OrderDetail{
+		Order:                 detail.Order,
+		Timeline:              timeline,
+		Emails:                emails,
+		Events:                events,
+		Notes:                 notes,
+		ShippingAddress:       detail.ShippingAddress,
+		StripeCheckoutURL:     detail.StripeCheckoutURL,
+		StripePaymentURL:      detail.StripePaymentURL,
+		StripePaymentLinkURL:  detail.StripePaymentLinkURL,
+		CanShip:               detail.CanShip,
+		CanMarkDelivered:      detail.CanMarkDelivered,
+		CanRefund:             detail.CanRefund,
+		CanReturn:             detail.CanReturn,
+		ResendEmailKind:       detail.ResendEmailKind,
+		CanConvertInquiry:     detail.CanConvertInquiry,
+		CanApprove:            detail.CanApprove,
+		CanCancelSubscription: detail.CanCancelSubscription,
+		CanEditAddress:        detail.CanEditAddress,
+		Address:               address.FromMap(detail.Order.ShippingAddress),
+		RelatedOrders:         detail.RelatedOrders,
+		PreviousOrders:        detail.PreviousOrders,
+	}
+}
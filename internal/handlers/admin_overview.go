@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+// AdminOverview aggregates orders, revenue, and attention-needed items across every shop in the
+// installation that the user may manage, with links into each shop.
+func (h *Handlers) AdminOverview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := h.loggerFromContext(ctx)
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route: "admin.overview",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	sess := contextResult.Session
+
+	installationShops, err := h.adminService.GetInstallationShops(ctx, sess.InstallationID)
+	if err != nil {
+		logger.Error("failed to get shops", "error", err, "installation_id", sess.InstallationID)
+		http.Error(w, "Failed to load shops", http.StatusInternalServerError)
+		return
+	}
+	if len(installationShops) == 0 {
+		if h.handleNoConnectedShops(ctx, w, r, sess, "admin.overview") {
+			return
+		}
+		http.Redirect(w, r, "/admin/setup", http.StatusSeeOther)
+		return
+	}
+
+	// Shops the user lacks the repository role for are left out, as they are everywhere else.
+	shops := make([]*db.Shop, 0, len(installationShops))
+	statuses := make(map[uuid.UUID]*services.RepoStatus, len(installationShops))
+	for _, shop := range installationShops {
+		access, err := h.shopAccessService.Authorize(ctx, shop, sess.GitHubUsername)
+		if err != nil {
+			logger.Warn("failed to check shop access", "error", err, "shop_id", shop.ID, "username", sess.GitHubUsername)
+			continue
+		}
+		if !access.Allowed {
+			continue
+		}
+		shops = append(shops, shop)
+		if snapshot, ok := h.repoStatusService.Cached(ctx, shop); ok {
+			statuses[shop.ID] = snapshot.Status
+		}
+	}
+
+	overview, err := h.adminService.BuildInstallationOverview(ctx, shops, statuses, time.Now())
+	if err != nil {
+		logger.Error("failed to build installation overview", "error", err, "installation_id", sess.InstallationID)
+		http.Error(w, "Failed to load overview", http.StatusInternalServerError)
+		return
+	}
+
+	if err := views.OverviewPage(overviewToView(overview), h.buildShopSwitcher(ctx, sess)).Render(ctx, w); err != nil {
+		logger.Error("failed to render overview page", "error", err)
+	}
+}
+
+func overviewToView(overview *services.InstallationOverview) views.Overview {
+	result := views.Overview{
+		SinceLabel:     overview.Since.Format("Jan 2, 2006"),
+		Orders:         overview.Totals.Orders,
+		RevenueCents:   overview.Totals.RevenueCents,
+		FailedPayments: overview.Totals.FailedPayments,
+		UnshippedPaid:  overview.Totals.UnshippedPaid,
+		BrokenConfigs:  overview.BrokenConfigs,
+		Shops:          make([]views.OverviewShop, 0, len(overview.Shops)),
+	}
+	for _, shop := range overview.Shops {
+		result.Shops = append(result.Shops, views.OverviewShop{
+			ID:             shop.ShopID.String(),
+			RepoFullName:   shop.RepoFullName,
+			Onboarded:      shop.Onboarded,
+			Orders:         shop.Orders,
+			RevenueCents:   shop.RevenueCents,
+			FailedPayments: shop.FailedPayments,
+			UnshippedPaid:  shop.UnshippedPaid,
+			ConfigBroken:   shop.ConfigBroken,
+			ConfigIssueURL: shop.ConfigIssueURL,
+			NeedsAttention: shop.NeedsAttention(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/services"
+)
+
+// AdminMessagePreviews renders the bot comments and emails for an order state with sample data.
+// Without a message name it returns every message as plain text; with one, emails render as HTML.
+func (h *Handlers) AdminMessagePreviews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route: "admin.previews",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	previews, err := services.RenderMessagePreviews(ctx, db.OrderStatus(vars["status"]), h.config.BaseURL)
+	if err != nil {
+		if errors.Is(err, services.ErrMessagePreviewNotFound) {
+			http.Error(w, "Unknown order status", http.StatusNotFound)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to render message previews", "error", err, "status", vars["status"])
+		http.Error(w, "Failed to render previews", http.StatusInternalServerError)
+		return
+	}
+
+	name := vars["name"]
+	if name == "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(services.FormatMessagePreviews(previews)))
+		return
+	}
+
+	for _, preview := range previews {
+		if preview.Name != name {
+			continue
+		}
+		if preview.HTML != "" && r.URL.Query().Get("format") != "text" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(preview.HTML))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(services.FormatMessagePreviews([]services.MessagePreview{preview})))
+		return
+	}
+	http.Error(w, "Message not found", http.StatusNotFound)
+}
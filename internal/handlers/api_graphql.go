@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/graphql"
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/internal/session"
+)
+
+// graphQLMaxBodyBytes caps a GraphQL request, which holds a query and its variables.
+const graphQLMaxBodyBytes = 1 << 20
+
+type graphQLViewerKey struct{}
+
+// graphQLViewer is who a GraphQL query runs as: a signed-in seller, who reads the shops they can
+// open in the dashboard, or an API key, which reads only its own shop.
+type graphQLViewer struct {
+	session *session.Data
+	apiKey  *db.APIKey
+	keyShop *db.Shop
+}
+
+// customerData reports whether the viewer may read buyers' personal details. Sellers see them in
+// the dashboard already; API keys need to have been created with access to them.
+func (v *graphQLViewer) customerData() bool {
+	return v.apiKey == nil || v.apiKey.CustomerData
+}
+
+func graphQLViewerFromContext(ctx context.Context) *graphQLViewer {
+	viewer, _ := ctx.Value(graphQLViewerKey{}).(*graphQLViewer)
+	if viewer == nil {
+		return &graphQLViewer{}
+	}
+	return viewer
+}
+
+// graphQLPage is a page of a shop's orders. endCursor pages to older orders and startCursor to
+// newer ones; either is passed back as after.
+type graphQLPage struct {
+	page *services.OrderListPage
+}
+
+// APIGraphQL answers read-only GraphQL queries over shops, their products, orders, and order
+// events. Scripts authenticate with an API key as a bearer token; the dashboard uses its session.
+// GET returns the schema.
+func (h *Handlers) APIGraphQL(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := h.loggerFromContext(ctx)
+
+	viewer, err := h.authenticateGraphQL(r)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyInvalid) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gitshop"`)
+			http.Error(w, "Invalid API key", http.StatusUnauthorized)
+			return
+		}
+		logger.Error("failed to authenticate graphql request", "error", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if viewer == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	schema := h.graphQLSchema()
+	if r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(schema.SDL()))
+		return
+	}
+
+	// Requiring JSON keeps browsers from sending queries cross-site without a CORS preflight.
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	var request graphql.Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphQLMaxBodyBytes)).Decode(&request); err != nil {
+		http.Error(w, "Request body must be a JSON object with a query", http.StatusBadRequest)
+		return
+	}
+
+	response := schema.Execute(context.WithValue(ctx, graphQLViewerKey{}, viewer), request)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("failed to encode graphql response", "error", err)
+	}
+}
+
+// authenticateGraphQL returns the request's viewer, or nil when it has neither an API key nor a
+// dashboard session.
+func (h *Handlers) authenticateGraphQL(r *http.Request) (*graphQLViewer, error) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		key, shop, err := h.adminService.AuthenticateAPIKey(r.Context(), strings.TrimSpace(token))
+		if err != nil {
+			return nil, err
+		}
+		return &graphQLViewer{apiKey: key, keyShop: shop}, nil
+	}
+	sess := session.GetSessionFromContext(r.Context())
+	if sess == nil || sess.InstallationID <= 0 {
+		return nil, nil
+	}
+	return &graphQLViewer{session: sess}, nil
+}
+
+// viewerShops returns the shops the viewer can read.
+func (h *Handlers) viewerShops(ctx context.Context) ([]*db.Shop, error) {
+	viewer := graphQLViewerFromContext(ctx)
+	if viewer.apiKey != nil {
+		return []*db.Shop{viewer.keyShop}, nil
+	}
+	if viewer.session == nil {
+		return nil, nil
+	}
+	shops, err := h.adminService.GetInstallationShops(ctx, viewer.session.InstallationID)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]*db.Shop, 0, len(shops))
+	for _, shop := range shops {
+		access, err := h.shopAccessService.Authorize(ctx, shop, viewer.session.GitHubUsername)
+		if err != nil {
+			return nil, err
+		}
+		if access.Allowed {
+			allowed = append(allowed, shop)
+		}
+	}
+	return allowed, nil
+}
+
+// viewerShop returns the shop if the viewer can read it, or nil.
+func (h *Handlers) viewerShop(ctx context.Context, shopID uuid.UUID) (*db.Shop, error) {
+	viewer := graphQLViewerFromContext(ctx)
+	if viewer.apiKey != nil {
+		if viewer.keyShop.ID != shopID {
+			return nil, nil
+		}
+		return viewer.keyShop, nil
+	}
+	if viewer.session == nil {
+		return nil, nil
+	}
+	shop, err := h.adminService.GetShopForInstallation(ctx, viewer.session.InstallationID, shopID)
+	if errors.Is(err, services.ErrAdminShopNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	access, err := h.shopAccessService.Authorize(ctx, shop, viewer.session.GitHubUsername)
+	if err != nil {
+		return nil, err
+	}
+	if !access.Allowed {
+		return nil, nil
+	}
+	return shop, nil
+}
+
+// graphQLSchema builds the API's types. Every resolver receives the value its parent resolved.
+func (h *Handlers) graphQLSchema() *graphql.Schema {
+	address := &graphql.Object{Name: "Address", Fields: []*graphql.Field{
+		addressField("line1"), addressField("line2"), addressField("city"), addressField("state"),
+		{Name: "postalCode", Type: "String", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(map[string]any)["postal_code"], nil
+		}},
+		addressField("country"),
+	}}
+
+	event := &graphql.Object{Name: "OrderEvent", Description: "An entry in an order's event log, as shown on the order page.", Fields: []*graphql.Field{
+		{Name: "kind", Type: "String!", Description: "created, checkout_created, paid, comment_posted, email_sent, shipped, delivered, or failed.", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(db.OrderEvent).Kind, nil
+		}},
+		{Name: "detail", Type: "String!", Description: "Such as the checkout method, email kind, carrier, or failure reason. Empty when there is none.", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(db.OrderEvent).Detail, nil
+		}},
+		{Name: "at", Type: "String!", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return graphQLTime(source.(db.OrderEvent).CreatedAt), nil
+		}},
+	}}
+
+	order := &graphql.Object{Name: "Order", Fields: []*graphql.Field{
+		orderField("id", "ID!", func(o *db.Order) any { return o.ID }),
+		orderField("number", "Int!", func(o *db.Order) any { return o.OrderNumber }),
+		orderField("status", "String!", func(o *db.Order) any { return o.Status }),
+		orderField("sku", "String!", func(o *db.Order) any { return o.SKU }),
+		orderField("quantity", "Int!", func(o *db.Order) any { return services.NewOrderPayload(o).Quantity }),
+		orderField("subtotalCents", "Int!", func(o *db.Order) any { return o.SubtotalCents }),
+		orderField("shippingCents", "Int!", func(o *db.Order) any { return o.ShippingCents }),
+		orderField("taxCents", "Int!", func(o *db.Order) any { return o.TaxCents }),
+		orderField("totalCents", "Int!", func(o *db.Order) any { return o.TotalCents }),
+		orderField("testMode", "Boolean!", func(o *db.Order) any { return o.TestMode }),
+		orderField("issueNumber", "Int!", func(o *db.Order) any { return o.GitHubIssueNumber }),
+		orderField("issueUrl", "String!", func(o *db.Order) any { return o.GitHubIssueURL }),
+		orderField("carrier", "String", func(o *db.Order) any { return graphQLString(o.Carrier) }),
+		orderField("trackingNumber", "String", func(o *db.Order) any { return graphQLString(o.TrackingNumber) }),
+		orderField("createdAt", "String!", func(o *db.Order) any { return graphQLTime(o.CreatedAt) }),
+		orderField("paidAt", "String", func(o *db.Order) any { return graphQLTime(o.PaidAt) }),
+		orderField("shippedAt", "String", func(o *db.Order) any { return graphQLTime(o.ShippedAt) }),
+		orderField("deliveredAt", "String", func(o *db.Order) any { return graphQLTime(o.DeliveredAt) }),
+		{Name: "events", Type: "[OrderEvent!]!", Object: event, Description: "The order's event log, oldest first.", Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			order := source.(*db.Order)
+			return h.adminService.ListOrderEvents(ctx, services.OrderActionInput{ShopID: order.ShopID, OrderID: order.ID})
+		}},
+		customerField("githubUsername", "String", func(o *db.Order) any { return graphQLString(o.GitHubUsername) }),
+		customerField("customerName", "String", func(o *db.Order) any { return graphQLString(o.CustomerName) }),
+		customerField("customerEmail", "String", func(o *db.Order) any { return graphQLString(o.CustomerEmail) }),
+		{Name: "shippingAddress", Type: "Address", Object: address, Description: customerFieldDescription, Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			if !graphQLViewerFromContext(ctx).customerData() {
+				return nil, graphql.ErrForbidden
+			}
+			address := source.(*db.Order).ShippingAddress
+			if len(address) == 0 {
+				return nil, nil
+			}
+			return address, nil
+		}},
+	}}
+
+	pageInfo := &graphql.Object{Name: "PageInfo", Fields: []*graphql.Field{
+		{Name: "hasNextPage", Type: "Boolean!", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(graphQLPage).page.NextCursor != "", nil
+		}},
+		{Name: "endCursor", Type: "String", Description: "Pass as after to get the next, older page.", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return graphQLString(source.(graphQLPage).page.NextCursor), nil
+		}},
+		{Name: "hasPreviousPage", Type: "Boolean!", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(graphQLPage).page.PrevCursor != "", nil
+		}},
+		{Name: "startCursor", Type: "String", Description: "Pass as after to get the previous, newer page.", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return graphQLString(source.(graphQLPage).page.PrevCursor), nil
+		}},
+	}}
+
+	orderConnection := &graphql.Object{Name: "OrderConnection", Fields: []*graphql.Field{
+		{Name: "nodes", Type: "[Order!]!", Object: order, Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(graphQLPage).page.Orders, nil
+		}},
+		{Name: "pageInfo", Type: "PageInfo!", Object: pageInfo, Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source, nil
+		}},
+	}}
+
+	product := &graphql.Object{Name: "Product", Description: "An active product in the shop's gitshop.yaml, as last checked.", Fields: []*graphql.Field{
+		productField("sku", "String!", func(p services.ProductSummary) any { return p.SKU }),
+		productField("name", "String!", func(p services.ProductSummary) any { return p.Name }),
+		productField("priceCents", "Int!", func(p services.ProductSummary) any { return p.PriceCents }),
+		productField("category", "String", func(p services.ProductSummary) any { return graphQLString(p.Category) }),
+		productField("orderUrl", "String", func(p services.ProductSummary) any { return graphQLString(p.OrderURL) }),
+	}}
+
+	shop := &graphql.Object{Name: "Shop", Fields: []*graphql.Field{
+		{Name: "id", Type: "ID!", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(*db.Shop).ID, nil
+		}},
+		{Name: "repo", Type: "String!", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+			return source.(*db.Shop).GitHubRepoFullName, nil
+		}},
+		{Name: "products", Type: "[Product!]!", Object: product, Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+			snapshot, _ := h.repoStatusService.Cached(ctx, source.(*db.Shop))
+			if snapshot.Status == nil {
+				return []services.ProductSummary{}, nil
+			}
+			return snapshot.Status.Products, nil
+		}},
+		{Name: "orders", Type: "OrderConnection!", Object: orderConnection, Description: "The shop's orders, newest first, up to 100 at a time.",
+			Args: []graphql.Arg{{Name: "first", Type: "Int"}, {Name: "after", Type: "String"}},
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				first, _, err := args.Int("first")
+				if err != nil {
+					return nil, err
+				}
+				after, _, err := args.String("after")
+				if err != nil {
+					return nil, err
+				}
+				page, err := h.adminService.ListOrders(ctx, source.(*db.Shop).ID, after, first)
+				if err != nil {
+					return nil, h.graphQLError(ctx, err, "failed to list orders")
+				}
+				return graphQLPage{page: page}, nil
+			}},
+		{Name: "issueOrders", Type: "[Order!]!", Object: order, Description: "The orders placed from a GitHub issue, oldest first.",
+			Args: []graphql.Arg{{Name: "number", Type: "Int!"}},
+			Resolve: func(ctx context.Context, source any, args graphql.Args) (any, error) {
+				number, _, err := args.Int("number")
+				if err != nil {
+					return nil, err
+				}
+				orders, err := h.adminService.SearchOrders(ctx, source.(*db.Shop).ID, services.OrderSearch{IssueNumber: number})
+				if err != nil {
+					return nil, h.graphQLError(ctx, err, "failed to search orders")
+				}
+				return orders, nil
+			}},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{Name: "shops", Type: "[Shop!]!", Object: shop, Description: "The shops you can open in the dashboard, or the API key's shop.",
+			Resolve: func(ctx context.Context, _ any, _ graphql.Args) (any, error) {
+				shops, err := h.viewerShops(ctx)
+				if err != nil {
+					return nil, h.graphQLError(ctx, err, "failed to list shops")
+				}
+				return shops, nil
+			}},
+		{Name: "shop", Type: "Shop", Object: shop, Args: []graphql.Arg{{Name: "id", Type: "ID!"}},
+			Resolve: func(ctx context.Context, _ any, args graphql.Args) (any, error) {
+				id, _, err := args.String("id")
+				if err != nil {
+					return nil, err
+				}
+				shopID, err := uuid.Parse(id)
+				if err != nil {
+					return nil, nil
+				}
+				shop, err := h.viewerShop(ctx, shopID)
+				if err != nil {
+					return nil, h.graphQLError(ctx, err, "failed to load shop")
+				}
+				return shop, nil
+			}},
+	}}
+
+	return &graphql.Schema{Query: query}
+}
+
+// graphQLError shows user errors, such as a bad cursor, to the client and logs the rest.
+func (h *Handlers) graphQLError(ctx context.Context, err error, message string) error {
+	var userErr services.UserError
+	if errors.As(err, &userErr) {
+		return errors.New(userErr.Message)
+	}
+	h.loggerFromContext(ctx).Error(message, "error", err)
+	return errors.New("internal error")
+}
+
+const customerFieldDescription = "Only readable with a dashboard session or an API key with customer data access."
+
+func orderField(name, typ string, value func(*db.Order) any) *graphql.Field {
+	return &graphql.Field{Name: name, Type: typ, Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return value(source.(*db.Order)), nil
+	}}
+}
+
+// customerField is an order field holding a buyer's personal details, which API keys can only
+// read when they were created with customer data access.
+func customerField(name, typ string, value func(*db.Order) any) *graphql.Field {
+	return &graphql.Field{Name: name, Type: typ, Description: customerFieldDescription, Resolve: func(ctx context.Context, source any, _ graphql.Args) (any, error) {
+		if !graphQLViewerFromContext(ctx).customerData() {
+			return nil, graphql.ErrForbidden
+		}
+		return value(source.(*db.Order)), nil
+	}}
+}
+
+func productField(name, typ string, value func(services.ProductSummary) any) *graphql.Field {
+	return &graphql.Field{Name: name, Type: typ, Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return value(source.(services.ProductSummary)), nil
+	}}
+}
+
+func addressField(name string) *graphql.Field {
+	return &graphql.Field{Name: name, Type: "String", Resolve: func(_ context.Context, source any, _ graphql.Args) (any, error) {
+		return source.(map[string]any)[name], nil
+	}}
+}
+
+// graphQLString turns an empty string into null.
+func graphQLString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// graphQLTime formats a time as RFC 3339, or null when it is unset.
+func graphQLTime(value time.Time) any {
+	if value.IsZero() {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339)
+}
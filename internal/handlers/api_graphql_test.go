@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/graphql"
+	"github.com/gitshopapp/gitshop/internal/session"
+)
+
+func TestAPIGraphQL_RequiresAuthentication(t *testing.T) {
+	t.Parallel()
+
+	h := &Handlers{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(`{"query":"{ shops { id } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.APIGraphQL(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAPIGraphQL_Requests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantBody    string
+	}{
+		{name: "schema", method: http.MethodGet, wantStatus: http.StatusOK, wantBody: "orders(first: Int, after: String): OrderConnection!"},
+		{name: "form post", method: http.MethodPost, contentType: "application/x-www-form-urlencoded", body: "query={shops{id}}", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "malformed body", method: http.MethodPost, contentType: "application/json", body: `{"query":`, wantStatus: http.StatusBadRequest},
+		{name: "invalid query", method: http.MethodPost, contentType: "application/json; charset=utf-8", body: `{"query":"{ shops { secret } }"}`, wantStatus: http.StatusOK, wantBody: "Shop has no field secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, cookie := newAuthenticatedHandlerAndCookie(t, 42)
+			req := httptest.NewRequest(tt.method, "/api/graphql", strings.NewReader(tt.body))
+			req.AddCookie(cookie)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			h.SessionMiddleware(http.HandlerFunc(h.APIGraphQL)).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestGraphQLSchema_CustomerFields(t *testing.T) {
+	t.Parallel()
+
+	h := &Handlers{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	shop := graphQLFieldObject(t, h.graphQLSchema().Query, "shops")
+	order := &db.Order{OrderNumber: 7, CustomerEmail: "buyer@example.com", ShippingAddress: map[string]any{"city": "Portland", "postal_code": "97201"}}
+	schema := &graphql.Schema{Query: &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{Name: "order", Type: "Order!", Object: graphQLFieldObject(t, shop, "issueOrders"), Resolve: func(context.Context, any, graphql.Args) (any, error) {
+			return order, nil
+		}},
+	}}}
+	query := graphql.Request{Query: `{ order { number customerEmail shippingAddress { city postalCode } } }`}
+
+	tests := []struct {
+		name   string
+		viewer *graphQLViewer
+		want   string
+	}{
+		{name: "session", viewer: &graphQLViewer{session: &session.Data{InstallationID: 42}}, want: `{"number":7,"customerEmail":"buyer@example.com","shippingAddress":{"city":"Portland","postalCode":"97201"}}`},
+		{name: "key with customer data", viewer: &graphQLViewer{apiKey: &db.APIKey{CustomerData: true}}, want: `{"number":7,"customerEmail":"buyer@example.com","shippingAddress":{"city":"Portland","postalCode":"97201"}}`},
+		{name: "key without customer data", viewer: &graphQLViewer{apiKey: &db.APIKey{}}, want: `{"number":7,"customerEmail":null,"shippingAddress":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			response := schema.Execute(context.WithValue(context.Background(), graphQLViewerKey{}, tt.viewer), query)
+			encoded, err := json.Marshal(response.Data)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if got := string(encoded); got != `{"order":`+tt.want+`}` {
+				t.Fatalf("data = %s, want order %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func graphQLFieldObject(t *testing.T, object *graphql.Object, name string) *graphql.Object {
+	t.Helper()
+
+	for _, field := range object.Fields {
+		if field.Name == name {
+			return field.Object
+		}
+	}
+	t.Fatalf("%s has no field %s", object.Name, name)
+	return nil
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+// AdminSettingsAPIKeys creates an API key for the GraphQL API and shows its token once.
+func (h *Handlers) AdminSettingsAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, ctx, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.api_keys",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	token, key, err := h.adminService.CreateAPIKey(ctx, services.CreateAPIKeyInput{
+		ShopID:       shop.ID,
+		Name:         r.FormValue("name"),
+		CustomerData: r.FormValue("customer_data") == "1",
+		CreatedBy:    contextResult.Session.GitHubUsername,
+	})
+	if err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderError(w, ctx, userErr.Message)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to create api key", "error", err, "shop_id", shop.ID)
+		h.renderError(w, ctx, "Failed to create API key")
+		return
+	}
+	h.loggerFromContext(ctx).Info("created api key", "shop_id", shop.ID, "api_key_id", key.ID, "customer_data", key.CustomerData, "created_by", key.CreatedBy)
+
+	if err := views.APIKeyCreatedResult(token, h.apiKeys(ctx, shop)).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render api key", "error", err)
+	}
+}
+
+// AdminSettingsAPIKeyRevoke stops one of the shop's API keys from working.
+func (h *Handlers) AdminSettingsAPIKeyRevoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                  "admin.settings.api_keys.revoke",
+		RequireShop:            true,
+		MissingShopRedirectURL: "/admin/setup",
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderError(w, ctx, "Failed to load shop context")
+			return
+		}
+		h.renderError(w, ctx, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+
+	keyID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		h.renderError(w, ctx, "API key not found")
+		return
+	}
+	if err := h.adminService.RevokeAPIKey(ctx, shop.ID, keyID); err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			h.renderError(w, ctx, "API key not found")
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to revoke api key", "error", err, "shop_id", shop.ID, "api_key_id", keyID)
+		h.renderError(w, ctx, "Failed to revoke API key")
+		return
+	}
+	h.loggerFromContext(ctx).Info("revoked api key", "shop_id", shop.ID, "api_key_id", keyID, "revoked_by", contextResult.Session.GitHubUsername)
+
+	if err := views.APIKeyRevokedResult(h.apiKeys(ctx, shop)).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render api key revocation", "error", err)
+	}
+}
+
+// apiKeys loads the shop's active keys for the settings page. A failure shows an empty list.
+func (h *Handlers) apiKeys(ctx context.Context, shop *db.Shop) []*db.APIKey {
+	keys, err := h.adminService.ListAPIKeys(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to list api keys", "error", err, "shop_id", shop.ID)
+		return nil
+	}
+	return keys
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/internal/session"
+)
+
+type apiOrderEventsResponse struct {
+	Events []db.OrderEvent `json:"events"`
+}
+
+type apiOrdersResponse struct {
+	Orders     []services.OrderPayload `json:"orders"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	PrevCursor string                  `json:"prev_cursor,omitempty"`
+}
+
+// APIShopOrders looks up a shop's orders by `?issue=123` or `?customer=login`, for sellers
+// matching support requests to earlier purchases. Without either it lists the shop's orders
+// newest first, a page at a time through `?cursor=` and `?limit=`. It uses the dashboard session
+// and the same repository role check as the dashboard.
+func (h *Handlers) APIShopOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := h.loggerFromContext(ctx)
+
+	shop, ok := h.apiShop(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	if !query.Has("issue") && !query.Has("customer") {
+		h.listShopOrders(w, r, shop.ID)
+		return
+	}
+	search := services.OrderSearch{Customer: query.Get("customer")}
+	if issue := query.Get("issue"); issue != "" {
+		var err error
+		search.IssueNumber, err = strconv.Atoi(issue)
+		if err != nil || search.IssueNumber <= 0 {
+			http.Error(w, "issue must be a positive issue number", http.StatusBadRequest)
+			return
+		}
+	}
+	orders, err := h.adminService.SearchOrders(ctx, shop.ID, search)
+	if err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			http.Error(w, userErr.Message, http.StatusBadRequest)
+			return
+		}
+		logger.Error("failed to search orders", "error", err, "shop_id", shop.ID)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	response := apiOrdersResponse{Orders: make([]services.OrderPayload, 0, len(orders))}
+	for _, order := range orders {
+		response.Orders = append(response.Orders, services.NewOrderPayload(order))
+	}
+	h.writeAPIOrders(w, r, response)
+}
+
+func (h *Handlers) listShopOrders(w http.ResponseWriter, r *http.Request, shopID uuid.UUID) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+	page, err := h.adminService.ListOrders(ctx, shopID, query.Get("cursor"), limit)
+	if err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			http.Error(w, userErr.Message, http.StatusBadRequest)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to list orders", "error", err, "shop_id", shopID)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	response := apiOrdersResponse{
+		Orders:     make([]services.OrderPayload, 0, len(page.Orders)),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+	for _, order := range page.Orders {
+		response.Orders = append(response.Orders, services.NewOrderPayload(order))
+	}
+	h.writeAPIOrders(w, r, response)
+}
+
+func (h *Handlers) writeAPIOrders(w http.ResponseWriter, r *http.Request, response apiOrdersResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.loggerFromContext(r.Context()).Error("failed to encode orders response", "error", err)
+	}
+}
+
+// apiShop loads the shop named in the URL for a JSON API request, checking the session and the
+// seller's repository role. It writes the error response and returns false when access is denied.
+func (h *Handlers) apiShop(w http.ResponseWriter, r *http.Request) (*db.Shop, bool) {
+	ctx := r.Context()
+	logger := h.loggerFromContext(ctx)
+
+	sess := session.GetSessionFromContext(ctx)
+	if sess == nil || sess.InstallationID <= 0 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	shopID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Shop not found", http.StatusNotFound)
+		return nil, false
+	}
+	shop, err := h.adminService.GetShopForInstallation(ctx, sess.InstallationID, shopID)
+	if err != nil {
+		if errors.Is(err, services.ErrAdminShopNotFound) {
+			http.Error(w, "Shop not found", http.StatusNotFound)
+			return nil, false
+		}
+		logger.Error("failed to load shop for api request", "error", err, "shop_id", shopID)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return nil, false
+	}
+	access, err := h.shopAccessService.Authorize(ctx, shop, sess.GitHubUsername)
+	if err != nil {
+		logger.Error("failed to check shop access", "error", err, "shop_id", shop.ID, "username", sess.GitHubUsername)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return nil, false
+	}
+	if !access.Allowed {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return shop, true
+}
+
+// APIOrderEvents returns an order's event log as `{"events": [...]}`, oldest first, for support
+// tools that would otherwise search logs.
+func (h *Handlers) APIOrderEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	shop, ok := h.apiShop(w, r)
+	if !ok {
+		return
+	}
+	orderID, err := uuid.Parse(mux.Vars(r)["order_id"])
+	if err != nil {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	}
+	events, err := h.adminService.ListOrderEvents(ctx, services.OrderActionInput{ShopID: shop.ID, OrderID: orderID})
+	if err != nil {
+		if errors.Is(err, services.ErrAdminOrderNotFound) {
+			http.Error(w, "Order not found", http.StatusNotFound)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to list order events", "error", err, "shop_id", shop.ID, "order_id", orderID)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(apiOrderEventsResponse{Events: events}); err != nil {
+		h.loggerFromContext(ctx).Error("failed to encode order events response", "error", err)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIShopOrders_RequiresSession(t *testing.T) {
+	t.Parallel()
+
+	h := &Handlers{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/shops/00000000-0000-4000-8000-000000000001/orders?issue=12", nil)
+	rec := httptest.NewRecorder()
+
+	h.APIShopOrders(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAPIOrderEvents_RequiresSession(t *testing.T) {
+	t.Parallel()
+
+	h := &Handlers{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/shops/00000000-0000-4000-8000-000000000001/orders/00000000-0000-4000-8000-000000000002/events", nil)
+	rec := httptest.NewRecorder()
+
+	h.APIOrderEvents(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/services"
+)
+
+// badgeMaxAge keeps README badges from refetching on every view; image proxies such as GitHub's
+// honor it too.
+const badgeMaxAge = "public, max-age=300"
+
+var repoNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,100}$`)
+
+// ShopBadgeSVG serves the shop's README badge showing whether it is open for orders.
+func (h *Handlers) ShopBadgeSVG(w http.ResponseWriter, r *http.Request) {
+	badge, ok := h.loadShopBadge(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", badgeMaxAge)
+	if _, err := w.Write(badge.SVG()); err != nil {
+		h.loggerFromContext(r.Context()).Warn("failed to write badge", "error", err)
+	}
+}
+
+// ShopBadgeJSON serves the badge data for shops that build their own widget.
+func (h *Handlers) ShopBadgeJSON(w http.ResponseWriter, r *http.Request) {
+	badge, ok := h.loadShopBadge(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", badgeMaxAge)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if err := json.NewEncoder(w).Encode(badge); err != nil {
+		h.loggerFromContext(r.Context()).Error("failed to encode badge response", "error", err)
+	}
+}
+
+func (h *Handlers) loadShopBadge(w http.ResponseWriter, r *http.Request) (services.ShopBadge, bool) {
+	vars := mux.Vars(r)
+	owner, repo := vars["owner"], vars["repo"]
+	if !repoNamePattern.MatchString(owner) || !repoNamePattern.MatchString(repo) {
+		http.NotFound(w, r)
+		return services.ShopBadge{}, false
+	}
+
+	badge, err := h.badgeService.Badge(r.Context(), owner+"/"+repo)
+	if errors.Is(err, services.ErrBadgeShopNotFound) {
+		http.NotFound(w, r)
+		return services.ShopBadge{}, false
+	}
+	if err != nil {
+		h.loggerFromContext(r.Context()).Error("failed to load badge", "error", err, "repo", owner+"/"+repo)
+		http.Error(w, "Failed to load badge", http.StatusInternalServerError)
+		return services.ShopBadge{}, false
+	}
+	// Badges are embedded on other sites, so they opt out of the same-origin resource policy.
+	w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+	return badge, true
+}
+
+// badgeMarkdown is the README snippet that shows the shop's badge and links to its order forms.
+func badgeMarkdown(baseURL, repoFullName string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" || repoFullName == "" {
+		return ""
+	}
+	return fmt.Sprintf("[![GitShop](%s/badge/%s.svg)](https://github.com/%s/issues/new/choose)", baseURL, repoFullName, repoFullName)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestShopBadge_RejectsInvalidRepoNames(t *testing.T) {
+	t.Parallel()
+
+	h := &Handlers{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	req := httptest.NewRequest(http.MethodGet, "/badge/octo/shop%20name.svg", nil)
+	req = mux.SetURLVars(req, map[string]string{"owner": "octo", "repo": "shop name"})
+	rec := httptest.NewRecorder()
+
+	h.ShopBadgeSVG(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestBadgeMarkdown(t *testing.T) {
+	t.Parallel()
+
+	got := badgeMarkdown("https://gitshop.example/", "octo/shop")
+	want := "[![GitShop](https://gitshop.example/badge/octo/shop.svg)](https://github.com/octo/shop/issues/new/choose)"
+	if got != want {
+		t.Fatalf("badgeMarkdown() = %q, want %q", got, want)
+	}
+	if got := badgeMarkdown("", "octo/shop"); got != "" {
+		t.Fatalf("expected no snippet without a base URL, got %q", got)
+	}
+}
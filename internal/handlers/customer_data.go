@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/internal/storage"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+const exportLinkTTL = 15 * time.Minute
+
+func (h *Handlers) AdminDashboardCustomerData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.dashboard.customer_data",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	erasures, err := h.adminService.ListDataErasures(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to load data erasures", "error", err, "shop_id", shop.ID)
+	}
+
+	if err := views.DashboardCustomerDataSection(erasures).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render customer data section", "error", err)
+	}
+}
+
+// AdminCustomerDataExport stores everything the shop holds about one customer as JSON or CSV
+// and redirects to a short-lived download link.
+func (h *Handlers) AdminCustomerDataExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.customers.export",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+	logger := h.loggerFromContext(ctx)
+
+	export, err := h.adminService.ExportCustomerData(ctx, shop, r.URL.Query().Get("subject"), r.URL.Query().Get("category"))
+	if err != nil {
+		var userErr services.UserError
+		switch {
+		case errors.Is(err, services.ErrAdminCustomerNotFound):
+			http.Redirect(w, r, "/admin/dashboard?toast=customer_not_found", http.StatusSeeOther)
+		case errors.As(err, &userErr):
+			http.Redirect(w, r, "/admin/dashboard?toast=customer_invalid", http.StatusSeeOther)
+		default:
+			logger.Error("failed to export customer data", "error", err, "shop_id", shop.ID)
+			http.Error(w, "Failed to export customer data", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	format := "json"
+	contentType := "application/json"
+	encode := export.JSON
+	if r.URL.Query().Get("format") == "csv" {
+		format = "csv"
+		contentType = "text/csv; charset=utf-8"
+		encode = export.CSV
+	}
+	body, err := encode()
+	if err != nil {
+		logger.Error("failed to encode customer data export", "error", err, "shop_id", shop.ID, "format", format)
+		http.Error(w, "Failed to export customer data", http.StatusInternalServerError)
+		return
+	}
+
+	key := fmt.Sprintf("%s%s/%s.%s", storage.ExportsPrefix, shop.ID, uuid.New(), format)
+	if err := h.fileStorage.Put(ctx, key, bytes.NewReader(body), int64(len(body)), contentType); err != nil {
+		logger.Error("failed to store customer data export", "error", err, "shop_id", shop.ID)
+		http.Error(w, "Failed to export customer data", http.StatusInternalServerError)
+		return
+	}
+	filename := fmt.Sprintf("customer-data-%s.%s", time.Now().UTC().Format("2006-01-02"), format)
+	downloadURL, err := h.fileStorage.SignedURL(ctx, key, filename, exportLinkTTL)
+	if err != nil {
+		logger.Error("failed to sign customer data export link", "error", err, "shop_id", shop.ID)
+		http.Error(w, "Failed to export customer data", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("exported customer data", "shop_id", shop.ID, "orders", len(export.Orders), "format", format)
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, downloadURL, http.StatusSeeOther)
+}
+
+// AdminCustomerDataErase anonymizes one customer's orders and records the erasure.
+func (h *Handlers) AdminCustomerDataErase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderCustomerDataResult(w, r, "Failed to parse form", false)
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.customers.erase",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderCustomerDataResult(w, r, "Failed to load shop context", false)
+			return
+		}
+		h.renderCustomerDataResult(w, r, "Not authenticated", false)
+		return
+	}
+	shop := contextResult.Shop
+
+	erased, err := h.adminService.EraseCustomerData(ctx, shop.ID, r.FormValue("subject"), contextResult.Session.GitHubUsername)
+	if err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderCustomerDataResult(w, r, userErr.Message, false)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to erase customer data", "error", err, "shop_id", shop.ID)
+		h.renderCustomerDataResult(w, r, "Failed to erase customer data", false)
+		return
+	}
+
+	h.loggerFromContext(ctx).Info("erased customer data", "shop_id", shop.ID, "orders", erased, "erased_by", contextResult.Session.GitHubUsername)
+	message := fmt.Sprintf("Personal data was removed from %d orders.", erased)
+	switch erased {
+	case 0:
+		message = "No orders matched. The request was still recorded in the erasure log."
+	case 1:
+		message = "Personal data was removed from 1 order."
+	}
+	h.renderCustomerDataResult(w, r, message, true)
+}
+
+func (h *Handlers) renderCustomerDataResult(w http.ResponseWriter, r *http.Request, message string, success bool) {
+	if err := views.CustomerDataResult(message, success).Render(r.Context(), w); err != nil {
+		h.loggerFromContext(r.Context()).Error("failed to render customer data result", "error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// EmailWebhook receives bounce, complaint and delivery events from the email provider a shop
+// configures. Like PaymentWebhook, each shop has its own endpoint for its own signing secret.
+func (h *Handlers) EmailWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := h.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes)
+
+	shopID, err := uuid.Parse(mux.Vars(r)["shop_id"])
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		meter.Count("webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("webhook.reason", "invalid_payload"),
+		))
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	shop, provider, event, err := h.emailDeliveryService.ParseWebhook(ctx, shopID, payload, r.Header)
+	if err != nil {
+		if errors.Is(err, email.ErrInvalidWebhookSignature) {
+			meter.Count("webhook.failed", 1, sentry.WithAttributes(
+				attribute.String("webhook.reason", "invalid_signature"),
+			))
+			logger.Warn("email webhook signature rejected", "error", err, "shop_id", shopID)
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return
+		}
+		meter.Count("webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("webhook.reason", "invalid_payload"),
+		))
+		logger.Warn("failed to read email webhook", "error", err, "shop_id", shopID)
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+	provider = "email-" + provider
+	eventType := string(event.Type)
+	meter.SetAttributes(
+		attribute.String("webhook.provider", provider),
+		attribute.String("webhook.event_type", eventType),
+	)
+	meter.Count("webhook.received", 1)
+	h.tapWebhookReceived(provider, event.ID, eventType, payload)
+
+	cacheKey := cache.WebhookKey(provider, event.ID)
+	if event.ID != "" {
+		if _, err := h.cacheProvider.Get(ctx, cacheKey); err == nil {
+			meter.Count("webhook.duplicate", 1)
+			h.tapWebhookResult(provider, event.ID, eventType, "duplicate", time.Time{}, nil)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	ctx, done := h.beginWebhook(ctx)
+	defer done()
+
+	started := time.Now()
+	if err := h.emailDeliveryService.HandleDeliveryEvent(ctx, shop, event); err != nil {
+		meter.Count("webhook.failed", 1)
+		h.tapWebhookResult(provider, event.ID, eventType, "failed", started, err)
+		logger.Error("failed to process email webhook", "error", err, "provider", provider, "event", eventType)
+		http.Error(w, "Processing failed", http.StatusInternalServerError)
+		return
+	}
+
+	meter.Count("webhook.processed", 1)
+	h.tapWebhookResult(provider, event.ID, eventType, "processed", started, nil)
+	if event.ID != "" {
+		if err := h.cacheProvider.Set(ctx, cacheKey, "processed", stripeWebhookIdempotencyTTL); err != nil {
+			logger.Error("failed to mark webhook as processed in cache", "error", err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type signedFileServer interface {
+	ServeSigned(w http.ResponseWriter, r *http.Request, key string)
+}
+
+// SignedFile serves a locally stored file to anyone holding a valid signed link. Providers that
+// hand out their own links, such as S3, never route downloads here.
+func (h *Handlers) SignedFile(w http.ResponseWriter, r *http.Request) {
+	server, ok := h.fileStorage.(signedFileServer)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	server.ServeSigned(w, r, mux.Vars(r)["key"])
+}
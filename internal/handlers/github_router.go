@@ -62,6 +62,62 @@ func (r *GitHubEventRouter) Handle(ctx context.Context, eventType string, payloa
 
 	switch e := event.(type) {
 	case *github.IssuesEvent:
+		if e.GetAction() == "closed" || e.GetAction() == "deleted" {
+			issue := e.GetIssue()
+			repo := e.GetRepo()
+			installation := e.GetInstallation()
+			if issue == nil || repo == nil || installation == nil {
+				recordFailed("missing_issue_repo_or_installation")
+				return fmt.Errorf("missing issue, repository, or installation data")
+			}
+			err = r.orderService.HandleIssueClosed(ctx, services.IssueClosedInput{
+				InstallationID: installation.GetID(),
+				RepoID:         repo.GetID(),
+				RepoFullName:   repo.GetFullName(),
+				IssueNumber:    issue.GetNumber(),
+				Deleted:        e.GetAction() == "deleted",
+			})
+			if err != nil {
+				recordFailed("order_issue_closed_failed")
+				return err
+			}
+			meter.Count("webhook.router.processed", 1)
+			span.Status = sentry.SpanStatusOK
+			return nil
+		}
+		if e.GetAction() == "edited" {
+			issue := e.GetIssue()
+			repo := e.GetRepo()
+			installation := e.GetInstallation()
+			if issue == nil || repo == nil || installation == nil {
+				recordFailed("missing_issue_repo_or_installation")
+				return fmt.Errorf("missing issue, repository, or installation data")
+			}
+			// GitShop renames order issues itself, so title-only edits are not order changes.
+			if e.GetChanges().GetBody() == nil || !r.orderService.IsOrderIssue(ctx, installation.GetID(), repo.GetFullName(), issue) {
+				meter.Count("webhook.router.ignored", 1, sentry.WithAttributes(attribute.String("reason", "issue_body_not_edited")))
+				return nil
+			}
+			editor := ""
+			if e.Sender != nil {
+				editor = e.Sender.GetLogin()
+			}
+			err = r.orderService.HandleIssueEdited(ctx, services.IssueEditedInput{
+				InstallationID: installation.GetID(),
+				RepoID:         repo.GetID(),
+				RepoFullName:   repo.GetFullName(),
+				IssueNumber:    issue.GetNumber(),
+				IssueBody:      issue.GetBody(),
+				EditorLogin:    editor,
+			})
+			if err != nil {
+				recordFailed("order_issue_edited_failed")
+				return err
+			}
+			meter.Count("webhook.router.processed", 1)
+			span.Status = sentry.SpanStatusOK
+			return nil
+		}
 		if e.GetAction() != "opened" {
 			meter.Count("webhook.router.ignored", 1, sentry.WithAttributes(attribute.String("reason", "issues_action_not_opened")))
 			return nil
@@ -73,7 +129,7 @@ func (r *GitHubEventRouter) Handle(ctx context.Context, eventType string, payloa
 			recordFailed("missing_issue_repo_or_installation")
 			return fmt.Errorf("missing issue, repository, or installation data")
 		}
-		if !services.IsOrderIssue(issue) {
+		if !r.orderService.IsOrderIssue(ctx, installation.GetID(), repo.GetFullName(), issue) {
 			meter.Count("webhook.router.ignored", 1, sentry.WithAttributes(attribute.String("reason", "issue_not_order_template")))
 			return nil
 		}
@@ -141,12 +197,15 @@ func (r *GitHubEventRouter) Handle(ctx context.Context, eventType string, payloa
 			commits = append(commits, services.PushCommitInput{
 				Added:    append([]string{}, c.Added...),
 				Modified: append([]string{}, c.Modified...),
+				Removed:  append([]string{}, c.Removed...),
 			})
 		}
 		err = r.repoService.HandlePushEvent(ctx, services.PushEventInput{
-			RepoID:       repo.GetID(),
-			RepoFullName: repo.GetFullName(),
-			Commits:      commits,
+			RepoID:        repo.GetID(),
+			RepoFullName:  repo.GetFullName(),
+			Ref:           e.GetRef(),
+			DefaultBranch: repo.GetDefaultBranch(),
+			Commits:       commits,
 		})
 		if err != nil {
 			recordFailed("push_event_failed")
@@ -155,6 +214,26 @@ func (r *GitHubEventRouter) Handle(ctx context.Context, eventType string, payloa
 		meter.Count("webhook.router.processed", 1)
 		span.Status = sentry.SpanStatusOK
 		return nil
+	case *github.RepositoryEvent:
+		repo := e.GetRepo()
+		installation := e.GetInstallation()
+		if repo == nil || installation == nil {
+			meter.Count("webhook.router.ignored", 1, sentry.WithAttributes(attribute.String("reason", "missing_repo_or_installation")))
+			return nil
+		}
+		err = r.repoService.HandleRepositoryEvent(ctx, services.RepositoryEventInput{
+			Action:         e.GetAction(),
+			InstallationID: installation.GetID(),
+			RepoID:         repo.GetID(),
+			RepoFullName:   repo.GetFullName(),
+		})
+		if err != nil {
+			recordFailed("repository_event_failed")
+			return err
+		}
+		meter.Count("webhook.router.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
 	case *github.InstallationEvent:
 		installation := e.GetInstallation()
 		if installation == nil {
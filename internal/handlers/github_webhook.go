@@ -8,6 +8,7 @@ import (
 	"github.com/getsentry/sentry-go/attribute"
 
 	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
 	"github.com/gitshopapp/gitshop/internal/githubapp"
 	"github.com/gitshopapp/gitshop/internal/observability"
 )
@@ -49,12 +50,15 @@ func (h *Handlers) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
 		attribute.String("webhook.event_type", eventType),
 	}
 	meter.Count("webhook.received", 1, sentry.WithAttributes(baseAttrs...))
+	h.tapWebhookReceived("github", deliveryID, eventType, payload)
+	h.recordWebhookReceived(ctx, "github", deliveryID, eventType, payload)
 
 	cacheKey := cache.WebhookKey("github", deliveryID)
 	_, err = h.cacheProvider.Get(ctx, cacheKey)
 	if err == nil {
 		meter.Count("webhook.duplicate", 1, sentry.WithAttributes(baseAttrs...))
 		logger.Info("webhook already processed", "delivery_id", deliveryID)
+		h.tapWebhookResult("github", deliveryID, eventType, "duplicate", time.Time{}, nil)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
@@ -80,10 +84,29 @@ func (h *Handlers) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	region, err := h.githubWebhookRegion(ctx, payload)
+	if err != nil {
+		meter.Count("webhook.failed", 1, sentry.WithAttributes(baseAttrs...))
+		logger.Error("failed to route GitHub webhook", "error", err, "delivery_id", deliveryID)
+		http.Error(w, "Processing failed", http.StatusInternalServerError)
+		return
+	}
+	if h.routeWebhookHome(ctx, w, region, "github", deliveryID, eventType, payload) {
+		return
+	}
+	if h.loadMonitor.ShouldDefer() && h.deferWebhook(ctx, w, region, "github", deliveryID, eventType, payload) {
+		return
+	}
+	ctx, done := h.beginWebhook(ctx)
+	defer done()
+
+	started := time.Now()
 	processErr := h.githubRouter.Handle(ctx, eventType, payload)
 
 	if processErr == nil {
 		meter.Count("webhook.processed", 1, sentry.WithAttributes(baseAttrs...))
+		h.tapWebhookResult("github", deliveryID, eventType, "processed", started, nil)
+		h.recordWebhookResult(ctx, "github", deliveryID, db.ReceivedWebhookProcessed, nil)
 		if err := h.cacheProvider.Set(ctx, cacheKey, "processed", 24*time.Hour); err != nil {
 			logger.Error("failed to mark webhook as processed in cache", "error", err)
 		}
@@ -91,6 +114,8 @@ func (h *Handlers) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
 
 	if processErr != nil {
 		meter.Count("webhook.failed", 1, sentry.WithAttributes(baseAttrs...))
+		h.tapWebhookResult("github", deliveryID, eventType, "failed", started, processErr)
+		h.recordWebhookResult(ctx, "github", deliveryID, db.ReceivedWebhookFailed, processErr)
 		logger.Error("failed to process GitHub webhook", "error", processErr, "type", eventType)
 		http.Error(w, "Processing failed", http.StatusInternalServerError)
 		return
@@ -12,50 +12,85 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/gitshopapp/gitshop/internal/backpressure"
 	"github.com/gitshopapp/gitshop/internal/cache"
 	"github.com/gitshopapp/gitshop/internal/config"
 	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/featureflags"
 	"github.com/gitshopapp/gitshop/internal/githubapp"
 	"github.com/gitshopapp/gitshop/internal/logging"
 	"github.com/gitshopapp/gitshop/internal/services"
 	"github.com/gitshopapp/gitshop/internal/session"
+	"github.com/gitshopapp/gitshop/internal/storage"
+	"github.com/gitshopapp/gitshop/internal/webhooktap"
 )
 
 const maxWebhookBodyBytes = 1 << 20 // 1 MB
 
 // Handlers provides HTTP request handlers for the GitShop admin panel.
 type Handlers struct {
-	config               *config.Config
-	db                   *pgxpool.Pool
-	shopStore            *db.ShopStore
-	orderStore           *db.OrderStore
-	cacheProvider        cache.Provider
-	githubAuth           *githubapp.Auth
-	githubClient         *githubapp.Client
-	githubRouter         *GitHubEventRouter
-	stripeRouter         *StripeEventRouter
-	authService          *services.AuthService
-	stripeConnectService *services.StripeConnectService
-	sessionManager       *session.Manager
-	adminService         *services.AdminService
-	logger               *slog.Logger
+	config                  *config.Config
+	db                      *pgxpool.Pool
+	shopStore               *db.ShopStore
+	orderStore              *db.OrderStore
+	cacheProvider           cache.Provider
+	githubAuth              *githubapp.Auth
+	githubClient            *githubapp.Client
+	githubRouter            *GitHubEventRouter
+	stripeRouter            *StripeEventRouter
+	authService             *services.AuthService
+	stripeConnectService    *services.StripeConnectService
+	paymentEventService     *services.PaymentEventService
+	emailDeliveryService    *services.EmailDeliveryService
+	sessionManager          *session.Manager
+	adminService            *services.AdminService
+	repoStatusService       *services.RepoStatusService
+	badgeService            *services.BadgeService
+	webhookService          *services.WebhookService
+	processingRegionService *services.ProcessingRegionService
+	dataRetentionService    *services.DataRetentionService
+	shopAccessService       *services.ShopAccessService
+	operatorService         *services.OperatorService
+	fileStorage             storage.Provider
+	idempotency             idempotencyStore
+	webhookQueue            webhookQueueStore
+	webhookEvents           webhookEventStore
+	loadMonitor             *backpressure.Monitor
+	featureFlags            *featureflags.Flags
+	webhookTap              *webhooktap.Tap
+	inflight                inflightRequests
+	logger                  *slog.Logger
 }
 
 type Dependencies struct {
-	Config               *config.Config
-	DB                   *pgxpool.Pool
-	ShopStore            *db.ShopStore
-	OrderStore           *db.OrderStore
-	CacheProvider        cache.Provider
-	GitHubAuth           *githubapp.Auth
-	GitHubClient         *githubapp.Client
-	GitHubRouter         *GitHubEventRouter
-	StripeRouter         *StripeEventRouter
-	AuthService          *services.AuthService
-	StripeConnectService *services.StripeConnectService
-	SessionManager       *session.Manager
-	AdminService         *services.AdminService
-	Logger               *slog.Logger
+	Config                  *config.Config
+	DB                      *pgxpool.Pool
+	ShopStore               *db.ShopStore
+	OrderStore              *db.OrderStore
+	CacheProvider           cache.Provider
+	GitHubAuth              *githubapp.Auth
+	GitHubClient            *githubapp.Client
+	GitHubRouter            *GitHubEventRouter
+	StripeRouter            *StripeEventRouter
+	AuthService             *services.AuthService
+	StripeConnectService    *services.StripeConnectService
+	PaymentEventService     *services.PaymentEventService
+	EmailDeliveryService    *services.EmailDeliveryService
+	SessionManager          *session.Manager
+	AdminService            *services.AdminService
+	RepoStatusService       *services.RepoStatusService
+	BadgeService            *services.BadgeService
+	WebhookService          *services.WebhookService
+	ProcessingRegionService *services.ProcessingRegionService
+	DataRetentionService    *services.DataRetentionService
+	ShopAccessService       *services.ShopAccessService
+	OperatorService         *services.OperatorService
+	FileStorage             storage.Provider
+	IdempotencyStore        *db.IdempotencyStore
+	WebhookQueueStore       *db.WebhookQueueStore
+	WebhookEventStore       *db.WebhookEventStore
+	FeatureFlags            *featureflags.Flags
+	Logger                  *slog.Logger
 }
 
 func New(deps Dependencies) (*Handlers, error) {
@@ -100,25 +135,80 @@ func New(deps Dependencies) (*Handlers, error) {
 	if deps.AdminService == nil {
 		return nil, fmt.Errorf("handlers dependencies: adminService is required")
 	}
+	if deps.RepoStatusService == nil {
+		return nil, fmt.Errorf("handlers dependencies: repoStatusService is required")
+	}
+	if deps.BadgeService == nil {
+		return nil, fmt.Errorf("handlers dependencies: badgeService is required")
+	}
 	if deps.StripeConnectService == nil {
 		return nil, fmt.Errorf("handlers dependencies: stripeConnectService is required")
 	}
+	if deps.PaymentEventService == nil {
+		return nil, fmt.Errorf("handlers dependencies: paymentEventService is required")
+	}
+	if deps.EmailDeliveryService == nil {
+		return nil, fmt.Errorf("handlers dependencies: emailDeliveryService is required")
+	}
+	if deps.WebhookService == nil {
+		return nil, fmt.Errorf("handlers dependencies: webhookService is required")
+	}
+	if deps.ProcessingRegionService == nil {
+		return nil, fmt.Errorf("handlers dependencies: processingRegionService is required")
+	}
+	if deps.DataRetentionService == nil {
+		return nil, fmt.Errorf("handlers dependencies: dataRetentionService is required")
+	}
+	if deps.ShopAccessService == nil {
+		return nil, fmt.Errorf("handlers dependencies: shopAccessService is required")
+	}
+	if deps.OperatorService == nil {
+		return nil, fmt.Errorf("handlers dependencies: operatorService is required")
+	}
+	if deps.FileStorage == nil {
+		return nil, fmt.Errorf("handlers dependencies: fileStorage is required")
+	}
+	if deps.IdempotencyStore == nil {
+		return nil, fmt.Errorf("handlers dependencies: idempotencyStore is required")
+	}
+	if deps.WebhookQueueStore == nil {
+		return nil, fmt.Errorf("handlers dependencies: webhookQueueStore is required")
+	}
+	if deps.WebhookEventStore == nil {
+		return nil, fmt.Errorf("handlers dependencies: webhookEventStore is required")
+	}
 
 	return &Handlers{
-		config:               deps.Config,
-		db:                   deps.DB,
-		shopStore:            deps.ShopStore,
-		orderStore:           deps.OrderStore,
-		cacheProvider:        deps.CacheProvider,
-		githubAuth:           deps.GitHubAuth,
-		githubClient:         deps.GitHubClient,
-		githubRouter:         deps.GitHubRouter,
-		stripeRouter:         deps.StripeRouter,
-		authService:          deps.AuthService,
-		stripeConnectService: deps.StripeConnectService,
-		sessionManager:       deps.SessionManager,
-		adminService:         deps.AdminService,
-		logger:               logger.With("component", "handlers"),
+		config:                  deps.Config,
+		db:                      deps.DB,
+		shopStore:               deps.ShopStore,
+		orderStore:              deps.OrderStore,
+		cacheProvider:           deps.CacheProvider,
+		githubAuth:              deps.GitHubAuth,
+		githubClient:            deps.GitHubClient,
+		githubRouter:            deps.GitHubRouter,
+		stripeRouter:            deps.StripeRouter,
+		authService:             deps.AuthService,
+		stripeConnectService:    deps.StripeConnectService,
+		paymentEventService:     deps.PaymentEventService,
+		emailDeliveryService:    deps.EmailDeliveryService,
+		sessionManager:          deps.SessionManager,
+		adminService:            deps.AdminService,
+		repoStatusService:       deps.RepoStatusService,
+		badgeService:            deps.BadgeService,
+		webhookService:          deps.WebhookService,
+		processingRegionService: deps.ProcessingRegionService,
+		dataRetentionService:    deps.DataRetentionService,
+		shopAccessService:       deps.ShopAccessService,
+		operatorService:         deps.OperatorService,
+		fileStorage:             deps.FileStorage,
+		idempotency:             deps.IdempotencyStore,
+		webhookQueue:            deps.WebhookQueueStore,
+		webhookEvents:           deps.WebhookEventStore,
+		loadMonitor:             backpressure.NewMonitor(webhookLoadLimits),
+		featureFlags:            deps.FeatureFlags,
+		webhookTap:              webhooktap.New(),
+		logger:                  logger.With("component", "handlers"),
 	}, nil
 }
 
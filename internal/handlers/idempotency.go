@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/ui/components/admin/idempotency"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotencyKeyTTL        = 10 * time.Minute
+	idempotencyWaitTimeout   = 5 * time.Second
+	idempotencyPollInterval  = 100 * time.Millisecond
+	idempotencyMaxBodyBytes  = 64 << 10
+	idempotencyPruneInterval = time.Hour
+)
+
+// replayedHeaders are the response headers stored with a completed request and sent again when a
+// duplicate submission is answered from the stored response.
+var replayedHeaders = []string{"Content-Type", "Location", "HX-Redirect", "HX-Refresh", "HX-Trigger"}
+
+type idempotencyStore interface {
+	Claim(ctx context.Context, scope db.IdempotencyScope, key, path string, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, scope db.IdempotencyScope, key, path string) (*db.IdempotentResponse, error)
+	Complete(ctx context.Context, scope db.IdempotencyScope, key, path string, response *db.IdempotentResponse) error
+	Release(ctx context.Context, scope db.IdempotencyScope, key, path string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// RunIdempotencyKeyRetention deletes expired idempotency keys until ctx is cancelled. Claims take
+// over expired keys themselves, so this only keeps the table small.
+func (h *Handlers) RunIdempotencyKeyRetention(ctx context.Context) {
+	if h.idempotency == nil {
+		return
+	}
+
+	ticker := time.NewTicker(idempotencyPruneInterval)
+	defer ticker.Stop()
+	for {
+		deleted, err := h.idempotency.DeleteExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			h.logger.Error("failed to prune idempotency keys", "error", err)
+		} else if deleted > 0 {
+			h.logger.Info("pruned idempotency keys", "deleted", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// inflightRequests serializes requests that share an idempotency key within this process, so a
+// double click waits for the first request instead of racing it to the database.
+type inflightRequests struct {
+	mu    sync.Mutex
+	locks map[string]*inflightLock
+}
+
+type inflightLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+func (f *inflightRequests) lock(key string) func() {
+	f.mu.Lock()
+	if f.locks == nil {
+		f.locks = make(map[string]*inflightLock)
+	}
+	entry, ok := f.locks[key]
+	if !ok {
+		entry = &inflightLock{}
+		f.locks[key] = entry
+	}
+	entry.waiters++
+	f.mu.Unlock()
+
+	entry.mu.Lock()
+	return func() {
+		entry.mu.Unlock()
+		f.mu.Lock()
+		entry.waiters--
+		if entry.waiters == 0 {
+			delete(f.locks, key)
+		}
+		f.mu.Unlock()
+	}
+}
+
+// Idempotency deduplicates admin mutations that carry an idempotency key. The first request runs
+// and its response is stored for idempotencyKeyTTL; repeats within that window get the stored
+// response back without running the action again. Failed requests release their key. Keys are
+// scoped to the signed-in user and installation, so a key never replays another session's
+// response; requests without a session are not deduplicated.
+func (h *Handlers) Idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requestMutatesState(r.Method) || h.idempotency == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := idempotencyKeyFromRequest(r)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if _, err := uuid.Parse(key); err != nil {
+			http.Error(w, "Invalid idempotency key", http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+		sess := h.sessionFromRequest(ctx, r)
+		if sess == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		scope := db.IdempotencyScope{InstallationID: sess.InstallationID, UserID: sess.UserID}
+		logger := h.loggerFromContext(ctx).With("idempotency_key", key)
+		path := r.URL.Path
+
+		unlock := h.inflight.lock(fmt.Sprintf("%d %d %s %s", scope.InstallationID, scope.UserID, key, path))
+		defer unlock()
+
+		claimed, err := h.idempotency.Claim(ctx, scope, key, path, idempotencyKeyTTL)
+		if err != nil {
+			logger.Warn("failed to claim idempotency key", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !claimed {
+			h.replayIdempotentResponse(w, r, scope, key, path)
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		storeCtx := context.WithoutCancel(ctx)
+		if !recorder.storable() {
+			if err := h.idempotency.Release(storeCtx, scope, key, path); err != nil {
+				logger.Warn("failed to release idempotency key", "error", err)
+			}
+			return
+		}
+		if err := h.idempotency.Complete(storeCtx, scope, key, path, recorder.response()); err != nil {
+			logger.Warn("failed to store idempotent response", "error", err)
+		}
+	})
+}
+
+func (h *Handlers) replayIdempotentResponse(w http.ResponseWriter, r *http.Request, scope db.IdempotencyScope, key, path string) {
+	ctx := r.Context()
+	deadline := time.Now().Add(idempotencyWaitTimeout)
+	for {
+		response, err := h.idempotency.Get(ctx, scope, key, path)
+		switch {
+		case errors.Is(err, db.ErrIdempotencyKeyNotFound):
+			http.Error(w, "This request was already submitted. Refresh the page and try again.", http.StatusConflict)
+			return
+		case err != nil:
+			h.loggerFromContext(ctx).Error("failed to load idempotent response", "error", err, "idempotency_key", key)
+			http.Error(w, "Failed to process request", http.StatusInternalServerError)
+			return
+		case response.StatusCode != 0:
+			for name, value := range response.Headers {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(response.StatusCode)
+			_, _ = w.Write([]byte(response.Body))
+			return
+		}
+
+		if time.Now().After(deadline) {
+			http.Error(w, "This request is still being processed. Refresh the page in a moment.", http.StatusConflict)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+func idempotencyKeyFromRequest(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get(idempotencyKeyHeader)); key != "" {
+		return key
+	}
+	return strings.TrimSpace(r.PostFormValue(idempotency.FieldName))
+}
+
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *idempotencyRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.truncated {
+		if w.body.Len()+len(b) > idempotencyMaxBodyBytes {
+			w.truncated = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// storable reports whether the response can be replayed for duplicates. Client and server
+// errors are not stored so the user can fix the input or retry with the same form.
+func (w *idempotencyRecorder) storable() bool {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return status < http.StatusBadRequest && !w.truncated
+}
+
+func (w *idempotencyRecorder) response() *db.IdempotentResponse {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	headers := make(map[string]string)
+	for _, name := range replayedHeaders {
+		if value := w.Header().Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	return &db.IdempotentResponse{
+		StatusCode: status,
+		Headers:    headers,
+		Body:       w.body.String(),
+	}
+}
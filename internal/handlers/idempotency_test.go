@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/session"
+)
+
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	responses map[string]*db.IdempotentResponse
+}
+
+func idempotencyStoreKey(scope db.IdempotencyScope, key, path string) string {
+	return fmt.Sprintf("%d %d %s %s", scope.InstallationID, scope.UserID, key, path)
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{responses: make(map[string]*db.IdempotentResponse)}
+}
+
+func (s *memoryIdempotencyStore) Claim(_ context.Context, scope db.IdempotencyScope, key, path string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.responses[idempotencyStoreKey(scope, key, path)]; exists {
+		return false, nil
+	}
+	s.responses[idempotencyStoreKey(scope, key, path)] = &db.IdempotentResponse{}
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Get(_ context.Context, scope db.IdempotencyScope, key, path string) (*db.IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response, exists := s.responses[idempotencyStoreKey(scope, key, path)]
+	if !exists {
+		return nil, db.ErrIdempotencyKeyNotFound
+	}
+	return response, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, scope db.IdempotencyScope, key, path string, response *db.IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[idempotencyStoreKey(scope, key, path)] = response
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, scope db.IdempotencyScope, key, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.responses, idempotencyStoreKey(scope, key, path))
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotencyTestHandlers() *Handlers {
+	return &Handlers{
+		idempotency:    newMemoryIdempotencyStore(),
+		sessionManager: session.NewManager(session.NewMemoryStore(), false),
+	}
+}
+
+// idempotencySessionCookie creates a session for the user in installation 1 and returns its cookie.
+func idempotencySessionCookie(t *testing.T, h *Handlers, userID int64) *http.Cookie {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	if _, err := h.sessionManager.CreateSession(t.Context(), rec, &session.Data{UserID: userID, InstallationID: 1}); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	return rec.Result().Cookies()[0]
+}
+
+func idempotentFormRequest(key string, cookie *http.Cookie) *http.Request {
+	form := url.Values{"idempotency_key": {key}}
+	req := httptest.NewRequest(http.MethodPost, "https://example.com/admin/orders/1/ship", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestIdempotency_ReplaysDuplicateSubmission(t *testing.T) {
+	t.Parallel()
+
+	h := newIdempotencyTestHandlers()
+	cookie := idempotencySessionCookie(t, h, 7)
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Redirect(w, r, "/admin/orders/1?toast=order_shipped", http.StatusSeeOther)
+	})
+	handler := h.Idempotency(next)
+
+	const key = "6f1d3c8e-2b4a-4f7e-9a51-0c2d7e8b9f10"
+	for i := range 2 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, idempotentFormRequest(key, cookie))
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("request %d: expected status %d, got %d", i+1, http.StatusSeeOther, rec.Code)
+		}
+		if got := rec.Header().Get("Location"); got != "/admin/orders/1?toast=order_shipped" {
+			t.Fatalf("request %d: unexpected location %q", i+1, got)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_ReleasesKeyOnError(t *testing.T) {
+	t.Parallel()
+
+	h := newIdempotencyTestHandlers()
+	cookie := idempotencySessionCookie(t, h, 7)
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		http.Error(w, "Tracking number is required", http.StatusBadRequest)
+	})
+	handler := h.Idempotency(next)
+
+	const key = "0b7e2f54-6c1a-4d3b-8e9f-1a2b3c4d5e6f"
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentFormRequest(key, cookie))
+	}
+	if calls != 2 {
+		t.Fatalf("expected failed request to be retryable, handler ran %d times", calls)
+	}
+}
+
+func TestIdempotency_PassesThroughWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	h := newIdempotencyTestHandlers()
+	cookie := idempotencySessionCookie(t, h, 7)
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := h.Idempotency(next)
+
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentFormRequest("", cookie))
+	}
+	if calls != 2 {
+		t.Fatalf("expected handler to run for each request, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_RejectsMalformedKey(t *testing.T) {
+	t.Parallel()
+
+	h := newIdempotencyTestHandlers()
+	cookie := idempotencySessionCookie(t, h, 7)
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Fatal("handler should not run")
+	})
+
+	rec := httptest.NewRecorder()
+	h.Idempotency(next).ServeHTTP(rec, idempotentFormRequest("not-a-key", cookie))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestIdempotency_ScopesKeyToSession(t *testing.T) {
+	t.Parallel()
+
+	h := newIdempotencyTestHandlers()
+	var users []int64
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		users = append(users, h.sessionFromRequest(r.Context(), r).UserID)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := h.Idempotency(next)
+
+	const key = "3c9a1e72-5d8b-4f06-a2e4-7b1c0d9f8e35"
+	for _, userID := range []int64{7, 8} {
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentFormRequest(key, idempotencySessionCookie(t, h, userID)))
+	}
+	if len(users) != 2 || users[0] != 7 || users[1] != 8 {
+		t.Fatalf("handler ran for users %v, want each user's request to run", users)
+	}
+
+	calls := 0
+	h.Idempotency(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(httptest.NewRecorder(), idempotentFormRequest(key, nil))
+	if calls != 1 {
+		t.Fatalf("expected a request without a session to pass through, ran %d times", calls)
+	}
+}
@@ -24,6 +24,7 @@ const (
 	AdminContextDecisionAllow         AdminContextDecision = "allow"
 	AdminContextDecisionBadRequest    AdminContextDecision = "bad_request"
 	AdminContextDecisionRedirect      AdminContextDecision = "redirect"
+	AdminContextDecisionForbidden     AdminContextDecision = "forbidden"
 	AdminContextDecisionNotFound      AdminContextDecision = "not_found"
 	AdminContextDecisionInternalError AdminContextDecision = "internal_error"
 )
@@ -92,6 +93,10 @@ func (h *Handlers) ResolveAdminContext(ctx context.Context, r *http.Request, req
 	}
 	result.Session = sess
 
+	if req.RequireShop && sess.Impersonating(time.Now()) && h.isOperator(sess) {
+		return h.resolveImpersonatedContext(ctx, r, route, sess, recordDecision)
+	}
+
 	if req.AllowInstallationQueryOverride {
 		rawInstallationID := strings.TrimSpace(r.URL.Query().Get("installation_id"))
 		if rawInstallationID != "" {
@@ -209,6 +214,34 @@ func (h *Handlers) ResolveAdminContext(ctx context.Context, r *http.Request, req
 		}
 	}
 
+	if h.shopAccessService == nil {
+		recordDecision(AdminContextDecisionInternalError, "shop_access_unavailable")
+		return AdminContextResult{
+			Decision:   AdminContextDecisionInternalError,
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Shop access service unavailable",
+		}
+	}
+	access, err := h.shopAccessService.Authorize(ctx, shop, sess.GitHubUsername)
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to check shop access", "error", err, "route", req.Route, "shop_id", shop.ID, "username", sess.GitHubUsername)
+		recordDecision(AdminContextDecisionInternalError, "shop_access_check_failed")
+		return AdminContextResult{
+			Decision:   AdminContextDecisionInternalError,
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to check repository access",
+		}
+	}
+	if !access.Allowed {
+		recordDecision(AdminContextDecisionForbidden, "insufficient_repo_role")
+		return AdminContextResult{
+			Decision:   AdminContextDecisionForbidden,
+			StatusCode: http.StatusForbidden,
+			Message:    fmt.Sprintf("You need %s access to %s to manage this shop. Choose another shop at /admin/shops.", access.RequiredRole, shop.GitHubRepoFullName),
+			Session:    sess,
+		}
+	}
+
 	result.Shop = shop
 	if req.RequireOnboardingComplete {
 		if !h.adminService.IsOnboarded(shop) {
@@ -261,6 +294,13 @@ func (h *Handlers) WriteAdminContextDecision(w http.ResponseWriter, r *http.Requ
 		}
 		http.Error(w, message, statusCode)
 		return true
+	case AdminContextDecisionForbidden:
+		message := result.Message
+		if message == "" {
+			message = "Forbidden"
+		}
+		http.Error(w, message, http.StatusForbidden)
+		return true
 	case AdminContextDecisionNotFound:
 		statusCode := result.StatusCode
 		if statusCode <= 0 {
@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/config"
 	"github.com/gitshopapp/gitshop/internal/session"
 )
 
@@ -178,3 +183,101 @@ func TestWriteAdminContextDecision_RedirectsHTMXRequests(t *testing.T) {
 		t.Fatalf("unexpected HX-Redirect header: got=%q want=%q", got, "/auth/github/login")
 	}
 }
+
+func TestResolveAdminContext_ImpersonationIsReadOnly(t *testing.T) {
+	t.Parallel()
+
+	h, cookie := newImpersonatingHandlerAndCookie(t, time.Now().Add(time.Hour))
+	h.config = &config.Config{Operators: []string{"octocat"}}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/orders/123/refund", nil)
+	req.AddCookie(cookie)
+	result := h.ResolveAdminContext(req.Context(), req, AdminContextRequirements{
+		Route:       "admin.orders.refund",
+		RequireShop: true,
+	})
+
+	if result.Decision != AdminContextDecisionForbidden {
+		t.Fatalf("unexpected decision: got=%q want=%q", result.Decision, AdminContextDecisionForbidden)
+	}
+}
+
+func TestResolveAdminContext_ImpersonationBlocksCustomerDataDownloads(t *testing.T) {
+	t.Parallel()
+
+	h, cookie := newImpersonatingHandlerAndCookie(t, time.Now().Add(time.Hour))
+	h.config = &config.Config{Operators: []string{"octocat"}}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/customers/export?subject=buyer@example.com", nil)
+	req.AddCookie(cookie)
+	result := h.ResolveAdminContext(req.Context(), req, AdminContextRequirements{
+		Route:                     "admin.customers.export",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+
+	if result.Decision != AdminContextDecisionForbidden {
+		t.Fatalf("unexpected decision: got=%q want=%q", result.Decision, AdminContextDecisionForbidden)
+	}
+}
+
+func TestResolveAdminContext_ImpersonationIgnoredWithoutOperatorOrAfterExpiry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		operators []string
+		expiresAt time.Time
+	}{
+		{name: "not an operator", expiresAt: time.Now().Add(time.Hour)},
+		{name: "expired", operators: []string{"octocat"}, expiresAt: time.Now().Add(-time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, cookie := newImpersonatingHandlerAndCookie(t, tt.expiresAt)
+			h.config = &config.Config{Operators: tt.operators}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/orders/123/refund", nil)
+			req.AddCookie(cookie)
+			result := h.ResolveAdminContext(req.Context(), req, AdminContextRequirements{
+				Route:       "admin.orders.refund",
+				RequireShop: true,
+			})
+
+			// The session has no shop of its own, so the normal path redirects to shop selection.
+			if result.Decision != AdminContextDecisionRedirect {
+				t.Fatalf("unexpected decision: got=%q want=%q", result.Decision, AdminContextDecisionRedirect)
+			}
+		})
+	}
+}
+
+func newImpersonatingHandlerAndCookie(t *testing.T, expiresAt time.Time) (*Handlers, *http.Cookie) {
+	t.Helper()
+
+	sessionManager := session.NewManager(session.NewMemoryStore(), false)
+	h := &Handlers{
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		sessionManager: sessionManager,
+	}
+
+	createRec := httptest.NewRecorder()
+	_, err := sessionManager.CreateSession(context.Background(), createRec, &session.Data{
+		UserID:                 1,
+		GitHubUsername:         "octocat",
+		InstallationID:         111,
+		ImpersonatedShopID:     uuid.New(),
+		ImpersonationExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	cookies := createRec.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("expected session cookie to be set")
+	}
+	return h, cookies[0]
+}
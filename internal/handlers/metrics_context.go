@@ -15,7 +15,7 @@ import (
 func (h *Handlers) MetricsContext(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		requestID := requestIDFromRequest(r)
+		requestID := requestIDFromContext(r)
 
 		attrs := []attribute.Builder{
 			attribute.String("http.request_id", requestID),
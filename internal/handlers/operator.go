@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/internal/session"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+// operatorSession returns the signed-in operator's session, or writes a 403 and returns nil.
+func (h *Handlers) operatorSession(w http.ResponseWriter, r *http.Request) *session.Data {
+	sess := h.sessionFromRequest(r.Context(), r)
+	if !h.isOperator(sess) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return nil
+	}
+	return sess
+}
+
+// OperatorHome searches shops across every installation and lists recent operator actions.
+func (h *Handlers) OperatorHome(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sess := h.operatorSession(w, r)
+	if sess == nil {
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	shops, err := h.operatorService.SearchShops(ctx, sess.GitHubUsername, query)
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to search shops", "error", err, "operator", sess.GitHubUsername)
+		http.Error(w, "Failed to search shops", http.StatusInternalServerError)
+		return
+	}
+	orphaned, err := h.operatorService.OrphanedShops(ctx)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to list orphaned shops", "error", err)
+		orphaned = nil
+	}
+	entries, err := h.operatorService.RecentActions(ctx)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to list operator audit log", "error", err)
+		entries = nil
+	}
+
+	if err := views.OperatorPage(query, shops, orphaned, entries, h.operatorService.FeatureFlags(ctx), h.buildShopSwitcher(ctx, sess)).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render operator page", "error", err)
+	}
+}
+
+// OperatorShop shows any shop's recent orders read-only.
+func (h *Handlers) OperatorShop(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sess := h.operatorSession(w, r)
+	if sess == nil {
+		return
+	}
+	shopID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid shop ID", http.StatusBadRequest)
+		return
+	}
+
+	shop, orders, err := h.operatorService.ShopOrders(ctx, sess.GitHubUsername, shopID)
+	if err != nil {
+		if errors.Is(err, services.ErrOperatorShopNotFound) {
+			http.Error(w, "Shop not found", http.StatusNotFound)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to load shop for operator", "error", err, "shop_id", shopID)
+		http.Error(w, "Failed to load shop", http.StatusInternalServerError)
+		return
+	}
+
+	suspension, err := h.operatorService.Suspension(ctx, shop.ID)
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to load shop suspension", "error", err, "shop_id", shop.ID)
+		http.Error(w, "Failed to load shop", http.StatusInternalServerError)
+		return
+	}
+
+	var toastPayload *views.ToastPayload
+	if payload, ok := operatorShopToasts[r.URL.Query().Get("toast")]; ok {
+		toastPayload = &payload
+	}
+
+	if err := views.OperatorShopPage(shop, suspension, orders, h.operatorService.ShopFeatureFlags(ctx, shop.ID), toastPayload, h.buildShopSwitcher(ctx, sess)).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render operator shop page", "error", err)
+	}
+}
+
+// OperatorImpersonate puts the operator's session into read-only support mode for a shop, so
+// they see the dashboard the way its owner does.
+func (h *Handlers) OperatorImpersonate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sess := h.operatorSession(w, r)
+	if sess == nil {
+		return
+	}
+	shopID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid shop ID", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	shop, err := h.operatorService.StartImpersonation(ctx, sess.GitHubUsername, shopID, r.FormValue("reason"))
+	if err != nil {
+		if errors.Is(err, services.ErrOperatorShopNotFound) {
+			http.Error(w, "Shop not found", http.StatusNotFound)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to start impersonation", "error", err, "shop_id", shopID)
+		http.Error(w, "Failed to start support mode", http.StatusInternalServerError)
+		return
+	}
+
+	sess.ImpersonatedShopID = shop.ID
+	sess.ImpersonationExpiresAt = time.Now().Add(services.ImpersonationTTL).Unix()
+	if err := h.sessionManager.UpdateSession(ctx, r, sess); err != nil {
+		h.loggerFromContext(ctx).Error("failed to update session", "error", err)
+		http.Error(w, "Failed to update session", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin/dashboard", http.StatusSeeOther)
+}
+
+// OperatorEndImpersonation leaves support mode and returns to the internal admin area.
+func (h *Handlers) OperatorEndImpersonation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sess := h.operatorSession(w, r)
+	if sess == nil {
+		return
+	}
+
+	if sess.ImpersonatedShopID != uuid.Nil {
+		if err := h.operatorService.EndImpersonation(ctx, sess.GitHubUsername, sess.ImpersonatedShopID); err != nil {
+			h.loggerFromContext(ctx).Error("failed to end impersonation", "error", err, "shop_id", sess.ImpersonatedShopID)
+			http.Error(w, "Failed to end support mode", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	sess.ImpersonatedShopID = uuid.Nil
+	sess.ImpersonationExpiresAt = 0
+	if err := h.sessionManager.UpdateSession(ctx, r, sess); err != nil {
+		h.loggerFromContext(ctx).Error("failed to update session", "error", err)
+		http.Error(w, "Failed to update session", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/internal/admin", http.StatusSeeOther)
+}
+
+// supportModeBlockedRoutes are downloads of customer PII, which support mode never serves.
+var supportModeBlockedRoutes = map[string]bool{
+	"admin.customers.export":     true,
+	"admin.orders.packing_slip":  true,
+	"admin.orders.packing_slips": true,
+}
+
+// resolveImpersonatedContext resolves shop-scoped admin pages to the shop an operator is
+// impersonating. Support mode is read-only, so anything that would change the shop or download
+// customer data is refused, and every page served is written to the operator audit log.
+func (h *Handlers) resolveImpersonatedContext(ctx context.Context, r *http.Request, route string, sess *session.Data, recordDecision func(AdminContextDecision, string)) AdminContextResult {
+	if supportModeBlockedRoutes[route] {
+		recordDecision(AdminContextDecisionForbidden, "impersonation_pii_download")
+		return AdminContextResult{
+			Decision:   AdminContextDecisionForbidden,
+			StatusCode: http.StatusForbidden,
+			Message:    "Customer data downloads are not available in support mode.",
+			Session:    sess,
+		}
+	}
+	if requestMutatesState(r.Method) {
+		recordDecision(AdminContextDecisionForbidden, "impersonation_read_only")
+		return AdminContextResult{
+			Decision:   AdminContextDecisionForbidden,
+			StatusCode: http.StatusForbidden,
+			Message:    "Support mode is read-only. End support mode at /internal/admin to make changes to your own shops.",
+			Session:    sess,
+		}
+	}
+
+	shop, err := h.operatorService.GetShop(ctx, sess.ImpersonatedShopID)
+	if err != nil {
+		if errors.Is(err, services.ErrOperatorShopNotFound) {
+			recordDecision(AdminContextDecisionRedirect, "impersonated_shop_not_found")
+			return AdminContextResult{
+				Decision:    AdminContextDecisionRedirect,
+				RedirectURL: "/internal/admin",
+				Session:     sess,
+			}
+		}
+		h.loggerFromContext(ctx).Error("failed to load impersonated shop", "error", err, "shop_id", sess.ImpersonatedShopID)
+		recordDecision(AdminContextDecisionInternalError, "impersonated_shop_lookup_failed")
+		return AdminContextResult{
+			Decision:   AdminContextDecisionInternalError,
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to load shop",
+		}
+	}
+
+	if err := h.operatorService.RecordSupportView(ctx, sess.GitHubUsername, shop, route+" "+r.URL.RequestURI()); err != nil {
+		recordDecision(AdminContextDecisionInternalError, "impersonation_audit_failed")
+		return AdminContextResult{
+			Decision:   AdminContextDecisionInternalError,
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Failed to write audit log",
+		}
+	}
+
+	recordDecision(AdminContextDecisionAllow, "impersonating")
+	return AdminContextResult{
+		Decision: AdminContextDecisionAllow,
+		Session:  sess,
+		Shop:     shop,
+	}
+}
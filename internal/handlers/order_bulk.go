@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/internal/storage"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+// AdminBulkOrderAction applies one action to the orders checked on the dashboard and reports
+// the outcome for each. Exports redirect to a short-lived download link instead.
+func (h *Handlers) AdminBulkOrderAction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		h.renderBulkOrderError(w, r, "Failed to parse form")
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.orders.bulk",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if contextResult.Decision != AdminContextDecisionAllow {
+		if contextResult.Decision == AdminContextDecisionInternalError {
+			h.renderBulkOrderError(w, r, "Failed to load shop context")
+			return
+		}
+		h.renderBulkOrderError(w, r, "Not authenticated")
+		return
+	}
+	shop := contextResult.Shop
+	logger := h.loggerFromContext(ctx)
+
+	orderIDs := make([]uuid.UUID, 0, len(r.Form["order_id"]))
+	for _, value := range r.Form["order_id"] {
+		orderID, err := uuid.Parse(value)
+		if err != nil {
+			h.renderBulkOrderError(w, r, "Invalid order ID")
+			return
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+
+	result, err := h.adminService.BulkOrderAction(ctx, services.BulkOrderActionInput{
+		ShopID:      shop.ID,
+		OrderIDs:    orderIDs,
+		Action:      services.BulkOrderAction(r.FormValue("action")),
+		TrackingCSV: r.FormValue("tracking_csv"),
+		Note:        r.FormValue("note"),
+		Author:      contextResult.Session.GitHubUsername,
+	})
+	if err != nil {
+		var userErr services.UserError
+		if errors.As(err, &userErr) {
+			h.renderBulkOrderError(w, r, userErr.Message)
+			return
+		}
+		logger.Error("failed to apply bulk order action", "error", err, "shop_id", shop.ID, "action", r.FormValue("action"))
+		h.renderBulkOrderError(w, r, "Failed to apply the action")
+		return
+	}
+	logger.Info("applied bulk order action", "shop_id", shop.ID, "action", result.Action, "orders", len(result.Items), "succeeded", result.Succeeded())
+
+	if len(result.Export) > 0 {
+		key := fmt.Sprintf("%s%s/%s.csv", storage.ExportsPrefix, shop.ID, uuid.New())
+		if err := h.fileStorage.Put(ctx, key, bytes.NewReader(result.Export), int64(len(result.Export)), "text/csv; charset=utf-8"); err != nil {
+			logger.Error("failed to store order export", "error", err, "shop_id", shop.ID)
+			h.renderBulkOrderError(w, r, "Failed to export orders")
+			return
+		}
+		filename := fmt.Sprintf("orders-%s.csv", time.Now().UTC().Format("2006-01-02"))
+		downloadURL, err := h.fileStorage.SignedURL(ctx, key, filename, exportLinkTTL)
+		if err != nil {
+			logger.Error("failed to sign order export link", "error", err, "shop_id", shop.ID)
+			h.renderBulkOrderError(w, r, "Failed to export orders")
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		h.htmxRedirect(w, r, downloadURL)
+		return
+	}
+
+	if err := views.BulkOrderResults(bulkOrderResultToView(result)).Render(ctx, w); err != nil {
+		logger.Error("failed to render bulk order results", "error", err)
+	}
+}
+
+func (h *Handlers) renderBulkOrderError(w http.ResponseWriter, r *http.Request, message string) {
+	if err := views.BulkOrderError(message).Render(r.Context(), w); err != nil {
+		h.loggerFromContext(r.Context()).Error("failed to render bulk order error", "error", err)
+	}
+}
+
+func bulkOrderResultToView(result *services.BulkOrderActionResult) views.BulkOrderResult {
+	view := views.BulkOrderResult{Items: make([]views.BulkOrderResultItem, 0, len(result.Items))}
+	for _, item := range result.Items {
+		if item.Error == "" {
+			view.Succeeded++
+		} else {
+			view.Failed++
+		}
+		view.Items = append(view.Items, views.BulkOrderResultItem{
+			OrderID:     item.OrderID.String(),
+			OrderNumber: item.OrderNumber,
+			Detail:      item.Detail,
+			Error:       item.Error,
+		})
+	}
+	return view
+}
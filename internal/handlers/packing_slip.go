@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+// AdminPackingSlip renders a printable packing slip for one order.
+func (h *Handlers) AdminPackingSlip(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.orders.packing_slip",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	orderID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	slips, err := h.adminService.PackingSlip(ctx, services.OrderActionInput{ShopID: shop.ID, OrderID: orderID})
+	if err != nil {
+		if errors.Is(err, services.ErrAdminOrderNotFound) {
+			http.Error(w, "Order not found", http.StatusNotFound)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to load packing slip", "error", err, "order_id", orderID, "shop_id", shop.ID)
+		http.Error(w, "Failed to load packing slip", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPackingSlips(w, r, slips, "/admin/orders/"+orderID.String())
+}
+
+// AdminPackingSlips renders packing slips for every paid order waiting to ship, to print in
+// one go.
+func (h *Handlers) AdminPackingSlips(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:                     "admin.orders.packing_slips",
+		RequireShop:               true,
+		RequireOnboardingComplete: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+
+	slips, err := h.adminService.UnshippedPackingSlips(ctx, shop.ID, time.Now())
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to load packing slips", "error", err, "shop_id", shop.ID)
+		http.Error(w, "Failed to load packing slips", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderPackingSlips(w, r, slips, "/admin/dashboard")
+}
+
+func (h *Handlers) renderPackingSlips(w http.ResponseWriter, r *http.Request, slips *services.PackingSlips, backURL string) {
+	view := make([]views.PackingSlip, 0, len(slips.Slips))
+	for _, slip := range slips.Slips {
+		options := make([]views.PackingSlipLine, 0, len(slip.Options))
+		for _, option := range slip.Options {
+			options = append(options, views.PackingSlipLine{Label: option.Label, Value: option.Value})
+		}
+		view = append(view, views.PackingSlip{
+			OrderNumber:     slip.OrderNumber,
+			PlacedAt:        slip.PlacedAt,
+			SKU:             slip.SKU,
+			ProductName:     slip.ProductName,
+			Quantity:        slip.Quantity,
+			Options:         options,
+			CustomerName:    slip.CustomerName,
+			GitHubUsername:  slip.GitHubUsername,
+			ShippingAddress: slip.ShippingAddress,
+		})
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err := views.PackingSlipsPage(slips.ShopName, view, backURL).Render(r.Context(), w); err != nil {
+		h.loggerFromContext(r.Context()).Error("failed to render packing slips", "error", err)
+	}
+}
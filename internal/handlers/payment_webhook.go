@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// PaymentWebhook receives events from payment processors a shop configures itself. Each shop
+// has its own endpoint because the signing secret belongs to the shop, not the platform.
+func (h *Handlers) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := h.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes)
+
+	shopID, err := uuid.Parse(mux.Vars(r)["shop_id"])
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		meter.Count("webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("webhook.reason", "invalid_payload"),
+		))
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+
+	shop, processor, event, err := h.paymentEventService.ParseWebhook(ctx, shopID, payload, r.Header)
+	if err != nil {
+		meter.Count("webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("webhook.reason", "invalid_payload"),
+		))
+		logger.Warn("failed to read payment webhook", "error", err, "shop_id", shopID)
+		http.Error(w, "Invalid webhook", http.StatusBadRequest)
+		return
+	}
+	provider := string(processor)
+	meter.SetAttributes(
+		attribute.String("webhook.provider", provider),
+		attribute.String("webhook.event_type", event.Name),
+	)
+	meter.Count("webhook.received", 1)
+	h.tapWebhookReceived(provider, event.ID, event.Name, payload)
+
+	cacheKey := cache.WebhookKey(provider, event.ID)
+	if _, err := h.cacheProvider.Get(ctx, cacheKey); err == nil {
+		meter.Count("webhook.duplicate", 1)
+		h.tapWebhookResult(provider, event.ID, event.Name, "duplicate", time.Time{}, nil)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	ctx, done := h.beginWebhook(ctx)
+	defer done()
+
+	started := time.Now()
+	if err := h.paymentEventService.HandlePaymentEvent(ctx, shop, event); err != nil {
+		meter.Count("webhook.failed", 1)
+		h.tapWebhookResult(provider, event.ID, event.Name, "failed", started, err)
+		logger.Error("failed to process payment webhook", "error", err, "provider", provider, "event", event.Name)
+		http.Error(w, "Processing failed", http.StatusInternalServerError)
+		return
+	}
+
+	meter.Count("webhook.processed", 1)
+	h.tapWebhookResult(provider, event.ID, event.Name, "processed", started, nil)
+	if err := h.cacheProvider.Set(ctx, cacheKey, "processed", stripeWebhookIdempotencyTTL); err != nil {
+		logger.Error("failed to mark webhook as processed in cache", "error", err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/logging"
+)
+
+// maxRequestIDLength bounds request IDs accepted from a proxy in front of the app.
+const maxRequestIDLength = 128
+
+// RequestID gives every request an ID, reusing a well-formed X-Request-ID from a proxy so logs
+// can be joined across hops. The ID is echoed in the response and stored in the context, where
+// request logs, metrics, and outbound GitHub and Stripe call logs pick it up.
+func (h *Handlers) RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := requestIDFromRequest(r)
+		w.Header().Set("X-Request-ID", requestID)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// requestIDFromContext returns the ID set by RequestID, or a fresh one when the middleware did
+// not run.
+func requestIDFromContext(r *http.Request) string {
+	if requestID := logging.RequestID(r.Context()); requestID != "" {
+		return requestID
+	}
+	return requestIDFromRequest(r)
+}
+
+func requestIDFromRequest(r *http.Request) string {
+	if r == nil {
+		return newRequestID()
+	}
+	if requestID := strings.TrimSpace(r.Header.Get("X-Request-ID")); validRequestID(requestID) {
+		return requestID
+	}
+	return newRequestID()
+}
+
+// validRequestID accepts IDs made of characters that cannot break log lines or headers.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	return uuid.NewString()
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		reuse  bool
+	}{
+		{name: "generated", header: "", reuse: false},
+		{name: "from proxy", header: "abc-123.def_4:5", reuse: true},
+		{name: "unsafe characters", header: "abc\"} injected", reuse: false},
+		{name: "too long", header: strings.Repeat("a", maxRequestIDLength+1), reuse: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var seen string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = logging.RequestID(r.Context())
+				w.WriteHeader(http.StatusNoContent)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			(&Handlers{}).RequestID(next).ServeHTTP(rec, req)
+
+			if seen == "" || rec.Header().Get("X-Request-ID") != seen {
+				t.Fatalf("context request ID %q, response header %q", seen, rec.Header().Get("X-Request-ID"))
+			}
+			if (seen == tt.header) != tt.reuse {
+				t.Fatalf("request ID = %q, reuse header %q = %v", seen, tt.header, tt.reuse)
+			}
+		})
+	}
+}
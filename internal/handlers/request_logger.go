@@ -37,14 +37,18 @@ func (w *loggingResponseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer to flush streamed responses.
+func (w *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // RequestLogger logs all incoming requests and injects a request-scoped logger into context.
 func (h *Handlers) RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		route := routeLabel(r)
 
-		requestID := requestIDFromRequest(r)
-		w.Header().Set("X-Request-ID", requestID)
+		requestID := requestIDFromContext(r)
 
 		logger := h.logger.With(
 			"request_id", requestID,
@@ -138,23 +142,6 @@ func (h *Handlers) RequestLogger(next http.Handler) http.Handler {
 	})
 }
 
-func requestIDFromRequest(r *http.Request) string {
-	if r == nil {
-		return newRequestID()
-	}
-	if requestID := strings.TrimSpace(r.Header.Get("X-Request-ID")); requestID != "" {
-		return requestID
-	}
-	if requestID := strings.TrimSpace(r.Header.Get("X-Request-Id")); requestID != "" {
-		return requestID
-	}
-	return newRequestID()
-}
-
-func newRequestID() string {
-	return uuid.NewString()
-}
-
 func clientIP(r *http.Request) string {
 	if r == nil {
 		return ""
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/services"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+const shopSuspendedMessage = "This shop is suspended and cannot take new orders or payments. See the dashboard for details."
+
+var operatorShopToasts = map[string]views.ToastPayload{
+	"suspended": {
+		Title:       "Shop suspended",
+		Description: "It no longer takes orders or payments. The owner sees the reason in their dashboard.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"unsuspended": {
+		Title:       "Suspension lifted",
+		Description: "The shop takes orders again.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"notes_saved": {
+		Title:   "Notes saved",
+		Variant: views.ToastVariantSuccess,
+	},
+}
+
+// OperatorSuspendShop suspends a shop for a terms violation.
+func (h *Handlers) OperatorSuspendShop(w http.ResponseWriter, r *http.Request) {
+	h.operatorSuspensionAction(w, r, "suspended", func(r *http.Request, operator string, shopID uuid.UUID) error {
+		return h.operatorService.SuspendShop(r.Context(), operator, shopID, r.FormValue("reason"))
+	})
+}
+
+// OperatorUnsuspendShop lifts a shop's suspension.
+func (h *Handlers) OperatorUnsuspendShop(w http.ResponseWriter, r *http.Request) {
+	h.operatorSuspensionAction(w, r, "unsuspended", func(r *http.Request, operator string, shopID uuid.UUID) error {
+		return h.operatorService.UnsuspendShop(r.Context(), operator, shopID)
+	})
+}
+
+// OperatorSuspensionNotes saves the operators' internal notes on a suspension.
+func (h *Handlers) OperatorSuspensionNotes(w http.ResponseWriter, r *http.Request) {
+	h.operatorSuspensionAction(w, r, "notes_saved", func(r *http.Request, operator string, shopID uuid.UUID) error {
+		return h.operatorService.UpdateSuspensionNotes(r.Context(), operator, shopID, r.FormValue("notes"))
+	})
+}
+
+func (h *Handlers) operatorSuspensionAction(w http.ResponseWriter, r *http.Request, toast string, action func(r *http.Request, operator string, shopID uuid.UUID) error) {
+	ctx := r.Context()
+	sess := h.operatorSession(w, r)
+	if sess == nil {
+		return
+	}
+	shopID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid shop ID", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	if err := action(r, sess.GitHubUsername, shopID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrOperatorShopNotFound):
+			http.Error(w, "Shop not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrSuspensionReasonRequired):
+			http.Error(w, "Enter a reason for the suspension", http.StatusBadRequest)
+		default:
+			h.loggerFromContext(ctx).Error("failed to update shop suspension", "error", err, "shop_id", shopID)
+			http.Error(w, "Failed to update suspension", http.StatusInternalServerError)
+		}
+		return
+	}
+	http.Redirect(w, r, "/internal/admin/shops/"+shopID.String()+"?toast="+toast, http.StatusSeeOther)
+}
+
+// AdminSuspensionAppeal records the shop owner's appeal of a suspension.
+func (h *Handlers) AdminSuspensionAppeal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route:       "admin.suspension.appeal",
+		RequireShop: true,
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	shop := contextResult.Shop
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.adminService.AppealSuspension(ctx, shop.ID, r.FormValue("appeal")); err != nil {
+		if errors.Is(err, services.ErrAdminSuspensionAppealInvalid) {
+			http.Error(w, "Enter an appeal of up to 4000 characters for a suspended shop", http.StatusBadRequest)
+			return
+		}
+		h.loggerFromContext(ctx).Error("failed to record suspension appeal", "error", err, "shop_id", shop.ID)
+		http.Error(w, "Failed to send appeal", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/admin/dashboard?toast=appeal_sent", http.StatusSeeOther)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,6 +11,9 @@ import (
 )
 
 func (h *Handlers) buildShopSwitcher(ctx context.Context, sess *session.Data) *views.ShopSwitcherProps {
+	if sess.Impersonating(time.Now()) && h.isOperator(sess) {
+		return h.buildImpersonationSwitcher(ctx, sess)
+	}
 	if sess == nil || sess.InstallationID <= 0 {
 		return nil
 	}
@@ -41,3 +45,16 @@ func (h *Handlers) buildShopSwitcher(ctx context.Context, sess *session.Data) *v
 		Options:  options,
 	}
 }
+
+// buildImpersonationSwitcher replaces the shop switcher with the support-mode banner while an
+// operator is impersonating a shop.
+func (h *Handlers) buildImpersonationSwitcher(ctx context.Context, sess *session.Data) *views.ShopSwitcherProps {
+	label := sess.ImpersonatedShopID.String()
+	if shop, err := h.operatorService.GetShop(ctx, sess.ImpersonatedShopID); err == nil {
+		label = shop.GitHubRepoFullName
+	}
+	return &views.ShopSwitcherProps{
+		ActiveID:      sess.ImpersonatedShopID.String(),
+		Impersonating: label,
+	}
+}
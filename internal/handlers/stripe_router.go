@@ -15,14 +15,18 @@ import (
 )
 
 type StripeEventRouter struct {
-	service *services.StripeService
-	logger  *slog.Logger
+	service        *services.StripeService
+	orderService   *services.OrderService
+	accountMonitor *services.StripeAccountMonitor
+	logger         *slog.Logger
 }
 
-func NewStripeEventRouter(service *services.StripeService, logger *slog.Logger) *StripeEventRouter {
+func NewStripeEventRouter(service *services.StripeService, orderService *services.OrderService, accountMonitor *services.StripeAccountMonitor, logger *slog.Logger) *StripeEventRouter {
 	return &StripeEventRouter{
-		service: service,
-		logger:  logger,
+		service:        service,
+		orderService:   orderService,
+		accountMonitor: accountMonitor,
+		logger:         logger,
 	}
 }
 
@@ -82,6 +86,46 @@ func (r *StripeEventRouter) Handle(ctx context.Context, event *stripeapi.Event)
 		meter.Count("webhook.router.processed", 1)
 		span.Status = sentry.SpanStatusOK
 		return nil
+	case "invoice.paid":
+		if err := r.service.HandleInvoicePaid(ctx, payload); err != nil {
+			recordFailed("invoice_paid_failed")
+			return err
+		}
+		meter.Count("webhook.router.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
+	case "customer.subscription.deleted":
+		if err := r.service.HandleSubscriptionDeleted(ctx, payload); err != nil {
+			recordFailed("subscription_deleted_failed")
+			return err
+		}
+		meter.Count("webhook.router.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
+	case "identity.verification_session.verified":
+		if err := r.orderService.HandleIdentityVerificationVerified(ctx, payload); err != nil {
+			recordFailed("identity_verification_verified_failed")
+			return err
+		}
+		meter.Count("webhook.router.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
+	case "identity.verification_session.requires_input":
+		if err := r.orderService.HandleIdentityVerificationRequiresInput(ctx, payload); err != nil {
+			recordFailed("identity_verification_requires_input_failed")
+			return err
+		}
+		meter.Count("webhook.router.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
+	case "account.updated":
+		if err := r.accountMonitor.HandleAccountUpdated(ctx, event.Account, payload); err != nil {
+			recordFailed("account_updated_failed")
+			return err
+		}
+		meter.Count("webhook.router.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
 	default:
 		logger.Info("unhandled Stripe event type", "type", event.Type)
 		meter.Count("webhook.router.unhandled", 1)
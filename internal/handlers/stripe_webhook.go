@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/getsentry/sentry-go/attribute"
 
 	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
 	"github.com/gitshopapp/gitshop/internal/observability"
 	stripewebhook "github.com/gitshopapp/gitshop/internal/stripe"
 )
@@ -22,7 +24,7 @@ func (h *Handlers) StripeWebhook(w http.ResponseWriter, r *http.Request) {
 	meter.SetAttributes(attribute.String("webhook.provider", "stripe"))
 	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes)
 
-	event, err := stripewebhook.ReadWebhookEvent(r, h.config.StripeWebhookSecret)
+	event, err := stripewebhook.ReadWebhookEvent(r, h.config.StripeWebhookSecret, h.config.StripeTestWebhookSecret)
 	if err != nil {
 		meter.Count("webhook.failed", 1, sentry.WithAttributes(
 			attribute.String("webhook.reason", "invalid_payload"),
@@ -47,12 +49,18 @@ func (h *Handlers) StripeWebhook(w http.ResponseWriter, r *http.Request) {
 	}
 	meter.SetAttributes(attribute.String("webhook.event_type", eventType))
 	meter.Count("webhook.received", 1)
+	if h.webhookTap.Active() || h.webhookEvents != nil {
+		payload, _ := json.Marshal(event)
+		h.tapWebhookReceived("stripe", event.ID, eventType, payload)
+		h.recordWebhookReceived(ctx, "stripe", event.ID, eventType, payload)
+	}
 
 	cacheKey := cache.WebhookKey("stripe", event.ID)
 	_, err = h.cacheProvider.Get(ctx, cacheKey)
 	if err == nil {
 		meter.Count("webhook.duplicate", 1)
 		logger.Info("webhook already processed", "event_id", event.ID)
+		h.tapWebhookResult("stripe", event.ID, eventType, "duplicate", time.Time{}, nil)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
@@ -66,15 +74,46 @@ func (h *Handlers) StripeWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	region, err := h.stripeWebhookRegion(ctx, event.Account)
+	if err != nil {
+		meter.Count("webhook.failed", 1)
+		logger.Error("failed to route Stripe webhook", "error", err, "event_id", event.ID)
+		http.Error(w, "Processing failed", http.StatusInternalServerError)
+		return
+	}
+	remote := h.isRemoteRegion(region)
+	if remote || h.loadMonitor.ShouldDefer() {
+		payload, err := json.Marshal(event)
+		switch {
+		case err != nil:
+			logger.Error("failed to encode Stripe event for queue", "error", err, "event_id", event.ID)
+			if remote {
+				http.Error(w, "Processing failed", http.StatusInternalServerError)
+				return
+			}
+		case h.routeWebhookHome(ctx, w, region, "stripe", event.ID, eventType, payload):
+			return
+		case h.deferWebhook(ctx, w, region, "stripe", event.ID, eventType, payload):
+			return
+		}
+	}
+	ctx, done := h.beginWebhook(ctx)
+	defer done()
+
+	started := time.Now()
 	processErr := h.stripeRouter.Handle(ctx, event)
 	if processErr == nil {
 		meter.Count("webhook.processed", 1)
+		h.tapWebhookResult("stripe", event.ID, eventType, "processed", started, nil)
+		h.recordWebhookResult(ctx, "stripe", event.ID, db.ReceivedWebhookProcessed, nil)
 		if err := h.cacheProvider.Set(ctx, cacheKey, "processed", stripeWebhookIdempotencyTTL); err != nil {
 			logger.Error("failed to mark webhook as processed in cache", "error", err)
 		}
 	}
 	if processErr != nil {
 		meter.Count("webhook.failed", 1)
+		h.tapWebhookResult("stripe", event.ID, eventType, "failed", started, processErr)
+		h.recordWebhookResult(ctx, "stripe", event.ID, db.ReceivedWebhookFailed, processErr)
 		logger.Error("failed to process Stripe webhook", "error", processErr, "type", event.Type)
 		http.Error(w, "Processing failed", http.StatusInternalServerError)
 		return
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/ui/views"
+)
+
+const (
+	webhookEventRetention     = 14 * 24 * time.Hour
+	webhookEventPruneInterval = time.Hour
+	webhookEventListLimit     = 100
+)
+
+var errWebhookReplayRemote = errors.New("webhook belongs to another region")
+
+type webhookEventStore interface {
+	Record(ctx context.Context, provider, deliveryID, eventType string, payload []byte) error
+	SetStatus(ctx context.Context, provider, deliveryID string, status db.ReceivedWebhookStatus, lastError string) error
+	MarkReplayed(ctx context.Context, id uuid.UUID, status db.ReceivedWebhookStatus, lastError string) error
+	Get(ctx context.Context, id uuid.UUID) (*db.ReceivedWebhook, error)
+	ListRecent(ctx context.Context, limit int) ([]*db.ReceivedWebhook, error)
+	DeleteReceivedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	RecordCheckpoint(ctx context.Context, provider, endpoint, deliveryID string) error
+	ListCheckpoints(ctx context.Context) ([]*db.WebhookCheckpoint, error)
+	ProcessedDeliveryIDs(ctx context.Context, provider string, deliveryIDs []string) (map[string]bool, error)
+}
+
+var webhookReplayToasts = map[string]views.ToastPayload{
+	"replayed": {
+		Title:       "Webhook replayed",
+		Description: "The handler ran again without errors.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"replay_failed": {
+		Title:       "Replay failed",
+		Description: "The handler returned an error; it is shown next to the webhook.",
+		Variant:     views.ToastVariantError,
+	},
+	"replay_remote": {
+		Title:       "Replay from the shop's region",
+		Description: "This webhook belongs to a shop homed in another region. Replay it from an instance there.",
+		Variant:     views.ToastVariantWarning,
+	},
+	"redelivered": {
+		Title:       "Redelivery requested",
+		Description: "GitHub will resend the failed deliveries shortly.",
+		Variant:     views.ToastVariantSuccess,
+	},
+	"redelivery_none": {
+		Title:       "Nothing to redeliver",
+		Description: "GitHub reports no failed deliveries in that window that were not already processed.",
+		Variant:     views.ToastVariantInfo,
+	},
+	"redelivery_failed": {
+		Title:       "Redelivery failed",
+		Description: "Some deliveries could not be requested again; check the logs and retry.",
+		Variant:     views.ToastVariantError,
+	},
+}
+
+// recordWebhookReceived stores a verified webhook for the operator replay page. Failures are
+// logged rather than failing the delivery.
+func (h *Handlers) recordWebhookReceived(ctx context.Context, provider, deliveryID, eventType string, payload []byte) {
+	if h.webhookEvents == nil || len(payload) == 0 {
+		return
+	}
+	if err := h.webhookEvents.Record(ctx, provider, deliveryID, eventType, payload); err != nil {
+		h.loggerFromContext(ctx).Warn("failed to record webhook", "error", err, "provider", provider, "delivery_id", deliveryID)
+	}
+}
+
+func (h *Handlers) recordWebhookResult(ctx context.Context, provider, deliveryID string, status db.ReceivedWebhookStatus, processErr error) {
+	if h.webhookEvents == nil {
+		return
+	}
+	lastError := ""
+	if processErr != nil {
+		lastError = processErr.Error()
+	}
+	if err := h.webhookEvents.SetStatus(ctx, provider, deliveryID, status, lastError); err != nil {
+		h.loggerFromContext(ctx).Warn("failed to record webhook result", "error", err, "provider", provider, "delivery_id", deliveryID)
+	}
+	if status != db.ReceivedWebhookProcessed {
+		return
+	}
+	if err := h.webhookEvents.RecordCheckpoint(ctx, provider, h.webhookEndpoint(provider), deliveryID); err != nil {
+		h.loggerFromContext(ctx).Warn("failed to record webhook checkpoint", "error", err, "provider", provider, "delivery_id", deliveryID)
+	}
+}
+
+// RunWebhookEventRetention deletes stored webhooks older than webhookEventRetention until ctx is
+// cancelled.
+func (h *Handlers) RunWebhookEventRetention(ctx context.Context) {
+	if h.webhookEvents == nil {
+		return
+	}
+
+	ticker := time.NewTicker(webhookEventPruneInterval)
+	defer ticker.Stop()
+	for {
+		deleted, err := h.webhookEvents.DeleteReceivedBefore(ctx, time.Now().Add(-webhookEventRetention))
+		if err != nil && ctx.Err() == nil {
+			h.logger.Error("failed to prune stored webhooks", "error", err)
+		} else if deleted > 0 {
+			h.logger.Info("pruned stored webhooks", "deleted", deleted)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AdminWebhooks lists recently received GitHub and Stripe webhooks. Only operators can see it,
+// since deliveries span every shop.
+func (h *Handlers) AdminWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route: "admin.webhooks",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	if !h.isOperator(contextResult.Session) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	webhooks, err := h.webhookEvents.ListRecent(ctx, webhookEventListLimit)
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to list stored webhooks", "error", err)
+		http.Error(w, "Failed to load webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	checkpoints, err := h.webhookEvents.ListCheckpoints(ctx)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to list webhook checkpoints", "error", err)
+		checkpoints = nil
+	}
+	now := time.Now().UTC()
+
+	var toastPayload *views.ToastPayload
+	if payload, ok := webhookReplayToasts[r.URL.Query().Get("toast")]; ok {
+		toastPayload = &payload
+	}
+
+	shopSwitcher := h.buildShopSwitcher(ctx, contextResult.Session)
+	if err := views.WebhooksPage(webhooks, checkpoints, h.defaultRedeliverySince(checkpoints, now), now, toastPayload, shopSwitcher).Render(ctx, w); err != nil {
+		h.loggerFromContext(ctx).Error("failed to render webhooks page", "error", err)
+	}
+}
+
+// AdminWebhookReplay runs a stored webhook through its handler again, bypassing the duplicate
+// check, and records the outcome.
+func (h *Handlers) AdminWebhookReplay(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route: "admin.webhooks.replay",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	if !h.isOperator(contextResult.Session) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+	webhook, err := h.webhookEvents.Get(ctx, id)
+	if err != nil {
+		h.loggerFromContext(ctx).Error("failed to load stored webhook", "error", err, "webhook_id", id)
+		http.Error(w, "Failed to load webhook", http.StatusInternalServerError)
+		return
+	}
+	if webhook == nil {
+		http.Error(w, "Webhook not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.operatorService.RecordWebhookReplay(ctx, contextResult.Session.GitHubUsername, webhook); err != nil {
+		http.Error(w, "Failed to write audit log", http.StatusInternalServerError)
+		return
+	}
+
+	toast := "replayed"
+	if err := h.replayWebhook(ctx, webhook); err != nil {
+		toast = "replay_failed"
+		if errors.Is(err, errWebhookReplayRemote) {
+			toast = "replay_remote"
+		}
+	}
+	http.Redirect(w, r, "/admin/webhooks?toast="+toast, http.StatusSeeOther)
+}
+
+func (h *Handlers) replayWebhook(ctx context.Context, webhook *db.ReceivedWebhook) error {
+	logger := h.loggerFromContext(ctx).With("provider", webhook.Provider, "delivery_id", webhook.DeliveryID, "type", webhook.EventType)
+
+	region, err := h.storedWebhookRegion(ctx, webhook)
+	if err != nil {
+		logger.Error("failed to resolve region for webhook replay", "error", err)
+		return err
+	}
+	if h.isRemoteRegion(region) {
+		return fmt.Errorf("%w: %s", errWebhookReplayRemote, region)
+	}
+
+	started := time.Now()
+	processErr := h.routeQueuedWebhook(ctx, &db.QueuedWebhook{
+		ID:         webhook.ID,
+		Provider:   webhook.Provider,
+		DeliveryID: webhook.DeliveryID,
+		EventType:  webhook.EventType,
+		Payload:    webhook.Payload,
+		ReceivedAt: webhook.ReceivedAt,
+	})
+
+	observability.MeterFromContext(ctx).Count("webhook.replayed", 1, sentry.WithAttributes(
+		attribute.String("webhook.provider", webhook.Provider),
+		attribute.String("webhook.event_type", webhook.EventType),
+		attribute.Bool("webhook.success", processErr == nil),
+	))
+
+	status := db.ReceivedWebhookProcessed
+	outcome := "replayed"
+	lastError := ""
+	if processErr != nil {
+		status = db.ReceivedWebhookFailed
+		outcome = "replay_failed"
+		lastError = processErr.Error()
+		logger.Warn("webhook replay failed", "error", processErr)
+	} else if err := h.cacheProvider.Set(ctx, cache.WebhookKey(webhook.Provider, webhook.DeliveryID), "processed", webhookQueueProcessedTTL); err != nil {
+		logger.Error("failed to mark webhook as processed in cache", "error", err)
+	}
+	h.tapWebhookResult(webhook.Provider, webhook.DeliveryID, webhook.EventType, outcome, started, processErr)
+
+	if err := h.webhookEvents.MarkReplayed(ctx, webhook.ID, status, lastError); err != nil {
+		logger.Error("failed to record webhook replay", "error", err)
+	}
+	return processErr
+}
+
+func (h *Handlers) storedWebhookRegion(ctx context.Context, webhook *db.ReceivedWebhook) (string, error) {
+	switch webhook.Provider {
+	case "github":
+		return h.githubWebhookRegion(ctx, webhook.Payload)
+	case "stripe":
+		var event struct {
+			Account string `json:"account"`
+		}
+		if err := json.Unmarshal(webhook.Payload, &event); err != nil {
+			return "", fmt.Errorf("failed to decode stored stripe event: %w", err)
+		}
+		return h.stripeWebhookRegion(ctx, event.Account)
+	default:
+		return "", nil
+	}
+}
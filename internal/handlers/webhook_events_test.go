@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/config"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+type recordingWebhookEvents struct {
+	statuses    []string
+	checkpoints []string
+	processed   map[string]bool
+	replayed    []db.ReceivedWebhookStatus
+	errors      []string
+}
+
+func (s *recordingWebhookEvents) Record(context.Context, string, string, string, []byte) error {
+	return nil
+}
+
+func (s *recordingWebhookEvents) SetStatus(_ context.Context, provider, deliveryID string, status db.ReceivedWebhookStatus, _ string) error {
+	s.statuses = append(s.statuses, provider+":"+deliveryID+"="+string(status))
+	return nil
+}
+
+func (s *recordingWebhookEvents) MarkReplayed(_ context.Context, _ uuid.UUID, status db.ReceivedWebhookStatus, lastError string) error {
+	s.replayed = append(s.replayed, status)
+	s.errors = append(s.errors, lastError)
+	return nil
+}
+
+func (s *recordingWebhookEvents) Get(context.Context, uuid.UUID) (*db.ReceivedWebhook, error) {
+	return nil, nil
+}
+
+func (s *recordingWebhookEvents) ListRecent(context.Context, int) ([]*db.ReceivedWebhook, error) {
+	return nil, nil
+}
+
+func (s *recordingWebhookEvents) DeleteReceivedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (s *recordingWebhookEvents) RecordCheckpoint(_ context.Context, provider, endpoint, deliveryID string) error {
+	s.checkpoints = append(s.checkpoints, provider+":"+endpoint+"="+deliveryID)
+	return nil
+}
+
+func (s *recordingWebhookEvents) ListCheckpoints(context.Context) ([]*db.WebhookCheckpoint, error) {
+	return nil, nil
+}
+
+func (s *recordingWebhookEvents) ProcessedDeliveryIDs(context.Context, string, []string) (map[string]bool, error) {
+	return s.processed, nil
+}
+
+func TestReplayWebhook_RecordsFailure(t *testing.T) {
+	t.Parallel()
+
+	events := &recordingWebhookEvents{}
+	h := &Handlers{config: &config.Config{}, webhookEvents: events}
+	err := h.replayWebhook(context.Background(), &db.ReceivedWebhook{
+		ID:         uuid.New(),
+		Provider:   "github",
+		DeliveryID: "delivery-1",
+		EventType:  "issues",
+		Payload:    []byte(`{"action":"opened"}`),
+	})
+	if err == nil {
+		t.Fatal("expected replay without a router to fail")
+	}
+	if len(events.replayed) != 1 || events.replayed[0] != db.ReceivedWebhookFailed {
+		t.Fatalf("expected one failed replay, got %v", events.replayed)
+	}
+	if !strings.Contains(events.errors[0], "router not configured") {
+		t.Fatalf("expected handler error to be recorded, got %q", events.errors[0])
+	}
+}
+
+func TestDeferWebhook_RecordsQueuedStatus(t *testing.T) {
+	t.Parallel()
+
+	events := &recordingWebhookEvents{}
+	h := &Handlers{webhookQueue: &recordingWebhookQueue{}, webhookEvents: events}
+	if !h.deferWebhook(context.Background(), httptest.NewRecorder(), "", "stripe", "evt_1", "checkout.session.completed", []byte(`{}`)) {
+		t.Fatal("expected webhook to be deferred")
+	}
+	if len(events.statuses) != 1 || events.statuses[0] != "stripe:evt_1=queued" {
+		t.Fatalf("expected queued status, got %v", events.statuses)
+	}
+}
+
+func TestRecordWebhookResult_RecordsCheckpointWhenProcessed(t *testing.T) {
+	t.Parallel()
+
+	events := &recordingWebhookEvents{}
+	h := &Handlers{config: &config.Config{Region: "eu"}, webhookEvents: events}
+	h.recordWebhookResult(context.Background(), "github", "delivery-1", db.ReceivedWebhookFailed, nil)
+	h.recordWebhookResult(context.Background(), "github", "delivery-2", db.ReceivedWebhookProcessed, nil)
+
+	if len(events.checkpoints) != 1 || events.checkpoints[0] != "github:eu:/webhooks/github=delivery-2" {
+		t.Fatalf("expected one checkpoint for the processed delivery, got %v", events.checkpoints)
+	}
+}
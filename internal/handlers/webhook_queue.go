@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	stripeapi "github.com/stripe/stripe-go/v84"
+
+	"github.com/gitshopapp/gitshop/internal/backpressure"
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	webhookQueuePollInterval  = 5 * time.Second
+	webhookQueueBatchSize     = 10
+	webhookQueueLease         = 2 * time.Minute
+	webhookQueueMaxAttempts   = 5
+	webhookQueueRetryDelay    = 30 * time.Second
+	webhookQueueAlertInterval = 5 * time.Minute
+	webhookQueueProcessedTTL  = 24 * time.Hour
+)
+
+var webhookLoadLimits = backpressure.Limits{
+	MaxInFlight:  8,
+	MaxDepth:     25,
+	MaxAge:       2 * time.Minute,
+	ShedCooldown: time.Minute,
+}
+
+type webhookQueueStore interface {
+	Enqueue(ctx context.Context, region, provider, deliveryID, eventType string, payload []byte) error
+	Backlog(ctx context.Context, region string) (int, time.Time, error)
+	ClaimDue(ctx context.Context, region string, limit int, lease time.Duration) ([]*db.QueuedWebhook, error)
+	MarkProcessed(ctx context.Context, id uuid.UUID) error
+	MarkRetry(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+}
+
+// deferWebhook queues a verified webhook and answers 202 so the sender is not kept waiting while
+// inline processing is saturated. It returns false when the webhook should be processed inline.
+func (h *Handlers) deferWebhook(ctx context.Context, w http.ResponseWriter, region, provider, deliveryID, eventType string, payload []byte) bool {
+	if h.webhookQueue == nil {
+		return false
+	}
+
+	if err := h.webhookQueue.Enqueue(ctx, region, provider, deliveryID, eventType, payload); err != nil {
+		h.loggerFromContext(ctx).Error("failed to queue webhook, processing inline", "error", err, "provider", provider, "delivery_id", deliveryID)
+		return false
+	}
+
+	observability.MeterFromContext(ctx).Count("webhook.deferred", 1, sentry.WithAttributes(
+		attribute.String("webhook.provider", provider),
+		attribute.String("webhook.event_type", eventType),
+	))
+	h.tapWebhookResult(provider, deliveryID, eventType, "deferred", time.Time{}, nil)
+	h.recordWebhookResult(ctx, provider, deliveryID, db.ReceivedWebhookQueued, nil)
+	w.WriteHeader(http.StatusAccepted)
+	return true
+}
+
+// beginWebhook records inline processing for back-pressure and marks ctx to shed non-critical
+// side effects while the queue is overloaded.
+func (h *Handlers) beginWebhook(ctx context.Context) (context.Context, func()) {
+	done := h.loadMonitor.Begin()
+	if h.loadMonitor.Overloaded(time.Now()) {
+		observability.MeterFromContext(ctx).Count("webhook.shedding", 1)
+		ctx = backpressure.WithShedding(ctx)
+	}
+	return ctx, done
+}
+
+// RunWebhookQueue processes deferred webhooks and reports queue depth until ctx is cancelled.
+func (h *Handlers) RunWebhookQueue(ctx context.Context) {
+	if h.webhookQueue == nil {
+		return
+	}
+
+	ticker := time.NewTicker(webhookQueuePollInterval)
+	defer ticker.Stop()
+	var lastAlert time.Time
+	for {
+		lastAlert = h.reportWebhookQueue(ctx, lastAlert)
+		if err := h.drainWebhookQueue(ctx); err != nil && ctx.Err() == nil {
+			h.logger.Error("failed to process webhook queue", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportWebhookQueue refreshes the backlog, emits depth and age gauges, and raises an operator
+// alert at most once per webhookQueueAlertInterval while overloaded. It returns when it last alerted.
+func (h *Handlers) reportWebhookQueue(ctx context.Context, lastAlert time.Time) time.Time {
+	queued, oldest, err := h.webhookQueue.Backlog(ctx, h.config.Region)
+	if err != nil {
+		if ctx.Err() == nil {
+			h.logger.Warn("failed to read webhook queue backlog", "error", err)
+		}
+		return lastAlert
+	}
+	h.loadMonitor.SetQueued(queued, oldest)
+
+	now := time.Now()
+	snapshot := h.loadMonitor.Snapshot(now)
+	meter := observability.MeterFromContext(ctx)
+	meter.Gauge("webhook.queue.depth", float64(snapshot.Depth))
+	meter.Gauge("webhook.queue.oldest_age", snapshot.OldestAge.Seconds(), sentry.WithUnit("second"))
+
+	if !h.loadMonitor.Overloaded(now) || now.Sub(lastAlert) < webhookQueueAlertInterval {
+		return lastAlert
+	}
+	h.logger.Error("webhook queue is backed up; non-critical side effects are paused",
+		"depth", snapshot.Depth,
+		"in_flight", snapshot.InFlight,
+		"queued", snapshot.Queued,
+		"oldest_age", snapshot.OldestAge.Round(time.Second).String(),
+	)
+	return now
+}
+
+func (h *Handlers) drainWebhookQueue(ctx context.Context) error {
+	for {
+		webhooks, err := h.webhookQueue.ClaimDue(ctx, h.config.Region, webhookQueueBatchSize, webhookQueueLease)
+		if err != nil {
+			return fmt.Errorf("failed to claim queued webhooks: %w", err)
+		}
+		for _, webhook := range webhooks {
+			h.processQueuedWebhook(ctx, webhook)
+		}
+		if len(webhooks) < webhookQueueBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (h *Handlers) processQueuedWebhook(ctx context.Context, webhook *db.QueuedWebhook) {
+	logger := h.logger.With("provider", webhook.Provider, "delivery_id", webhook.DeliveryID, "type", webhook.EventType)
+	meter := observability.MeterFromContext(ctx)
+	attrs := sentry.WithAttributes(
+		attribute.String("webhook.provider", webhook.Provider),
+		attribute.String("webhook.event_type", webhook.EventType),
+	)
+
+	processCtx := ctx
+	if h.loadMonitor.Overloaded(time.Now()) {
+		processCtx = backpressure.WithShedding(ctx)
+	}
+
+	started := time.Now()
+	processErr := h.routeQueuedWebhook(processCtx, webhook)
+	if processErr == nil {
+		h.tapWebhookResult(webhook.Provider, webhook.DeliveryID, webhook.EventType, "processed", started, nil)
+		h.recordWebhookResult(ctx, webhook.Provider, webhook.DeliveryID, db.ReceivedWebhookProcessed, nil)
+		if err := h.webhookQueue.MarkProcessed(ctx, webhook.ID); err != nil {
+			logger.Error("failed to mark queued webhook processed", "error", err)
+		}
+		if err := h.cacheProvider.Set(ctx, cache.WebhookKey(webhook.Provider, webhook.DeliveryID), "processed", webhookQueueProcessedTTL); err != nil {
+			logger.Error("failed to mark webhook as processed in cache", "error", err)
+		}
+		meter.Count("webhook.processed", 1, attrs)
+		return
+	}
+
+	attempt := webhook.Attempts + 1
+	meter.Count("webhook.failed", 1, attrs)
+	h.tapWebhookResult(webhook.Provider, webhook.DeliveryID, webhook.EventType, "failed", started, processErr)
+	if attempt >= webhookQueueMaxAttempts {
+		if err := h.webhookQueue.MarkFailed(ctx, webhook.ID, processErr.Error()); err != nil {
+			logger.Error("failed to mark queued webhook failed", "error", err)
+		}
+		logger.Error("queued webhook failed permanently", "error", processErr, "attempts", attempt)
+		h.recordWebhookResult(ctx, webhook.Provider, webhook.DeliveryID, db.ReceivedWebhookFailed, processErr)
+		return
+	}
+	h.recordWebhookResult(ctx, webhook.Provider, webhook.DeliveryID, db.ReceivedWebhookQueued, processErr)
+	nextAttempt := time.Now().Add(time.Duration(attempt) * webhookQueueRetryDelay)
+	if err := h.webhookQueue.MarkRetry(ctx, webhook.ID, processErr.Error(), nextAttempt); err != nil {
+		logger.Error("failed to schedule queued webhook retry", "error", err)
+	}
+	logger.Warn("queued webhook failed, will retry", "error", processErr, "attempts", attempt)
+}
+
+func (h *Handlers) routeQueuedWebhook(ctx context.Context, webhook *db.QueuedWebhook) error {
+	switch webhook.Provider {
+	case "github":
+		if h.githubRouter == nil {
+			return fmt.Errorf("github event router not configured")
+		}
+		return h.githubRouter.Handle(ctx, webhook.EventType, webhook.Payload)
+	case "stripe":
+		if h.stripeRouter == nil {
+			return fmt.Errorf("stripe event router not configured")
+		}
+		var event stripeapi.Event
+		if err := json.Unmarshal(webhook.Payload, &event); err != nil {
+			return fmt.Errorf("failed to decode queued stripe event: %w", err)
+		}
+		return h.stripeRouter.Handle(ctx, &event)
+	default:
+		return fmt.Errorf("unknown webhook provider %q", webhook.Provider)
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+type recordingWebhookQueue struct {
+	enqueueErr error
+	enqueued   []string
+}
+
+func (q *recordingWebhookQueue) Enqueue(_ context.Context, region, provider, deliveryID, _ string, _ []byte) error {
+	if q.enqueueErr != nil {
+		return q.enqueueErr
+	}
+	q.enqueued = append(q.enqueued, region+"/"+provider+":"+deliveryID)
+	return nil
+}
+
+func (q *recordingWebhookQueue) Backlog(context.Context, string) (int, time.Time, error) {
+	return len(q.enqueued), time.Time{}, nil
+}
+
+func (q *recordingWebhookQueue) ClaimDue(context.Context, string, int, time.Duration) ([]*db.QueuedWebhook, error) {
+	return nil, nil
+}
+
+func (q *recordingWebhookQueue) MarkProcessed(context.Context, uuid.UUID) error { return nil }
+
+func (q *recordingWebhookQueue) MarkRetry(context.Context, uuid.UUID, string, time.Time) error {
+	return nil
+}
+
+func (q *recordingWebhookQueue) MarkFailed(context.Context, uuid.UUID, string) error { return nil }
+
+func TestDeferWebhook(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		queue      *recordingWebhookQueue
+		wantQueued bool
+	}{
+		{name: "queued", queue: &recordingWebhookQueue{}, wantQueued: true},
+		{name: "enqueue failure falls back inline", queue: &recordingWebhookQueue{enqueueErr: errors.New("db down")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &Handlers{webhookQueue: tt.queue}
+			rec := httptest.NewRecorder()
+			queued := h.deferWebhook(t.Context(), rec, "", "github", "delivery-1", "issues", []byte(`{}`))
+			if queued != tt.wantQueued {
+				t.Fatalf("expected queued=%v, got %v", tt.wantQueued, queued)
+			}
+			if tt.wantQueued && rec.Code != http.StatusAccepted {
+				t.Fatalf("expected status %d, got %d", http.StatusAccepted, rec.Code)
+			}
+			if tt.wantQueued && len(tt.queue.enqueued) != 1 {
+				t.Fatalf("expected one queued webhook, got %v", tt.queue.enqueued)
+			}
+		})
+	}
+}
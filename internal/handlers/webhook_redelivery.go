@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	redeliveryWindowLayout = "2006-01-02T15:04"
+	// redeliveryCheckpointSlack reaches back before the last processed delivery, since GitHub
+	// sends deliveries concurrently and a failure can land just before the checkpoint.
+	redeliveryCheckpointSlack = 5 * time.Minute
+	redeliveryDefaultWindow   = time.Hour
+	maxRedeliveries           = 100
+)
+
+type appDeliveryClient interface {
+	FailedAppDeliveries(ctx context.Context, since, until time.Time) ([]githubapp.AppDelivery, error)
+	RedeliverAppDelivery(ctx context.Context, deliveryID int64) error
+}
+
+type webhookRedeliveryResult struct {
+	Failed      int
+	Processed   int
+	Redelivered int
+	Errors      int
+}
+
+// webhookEndpoint names the endpoint a provider's webhooks arrive at on this instance, for
+// delivery checkpoints.
+func (h *Handlers) webhookEndpoint(provider string) string {
+	path := "/webhooks/" + provider
+	if h.config != nil && h.config.Region != "" {
+		return h.config.Region + ":" + path
+	}
+	return path
+}
+
+// defaultRedeliverySince is where a redelivery window starts when the operator does not pick
+// one: shortly before the last GitHub delivery this endpoint processed, or an hour ago.
+func (h *Handlers) defaultRedeliverySince(checkpoints []*db.WebhookCheckpoint, now time.Time) time.Time {
+	endpoint := h.webhookEndpoint("github")
+	for _, checkpoint := range checkpoints {
+		if checkpoint.Provider == "github" && checkpoint.Endpoint == endpoint && checkpoint.ProcessedAt.Before(now) {
+			return checkpoint.ProcessedAt.Add(-redeliveryCheckpointSlack)
+		}
+	}
+	return now.Add(-redeliveryDefaultWindow)
+}
+
+// AdminWebhookRedeliver asks GitHub to resend app webhook deliveries that failed between since
+// and until, typically the window of a deploy. Deliveries GitHub saw fail but this app stored as
+// processed are skipped.
+func (h *Handlers) AdminWebhookRedeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route: "admin.webhooks.redeliver",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	if !h.isOperator(contextResult.Session) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().UTC()
+	checkpoints, err := h.webhookEvents.ListCheckpoints(ctx)
+	if err != nil {
+		h.loggerFromContext(ctx).Warn("failed to list webhook checkpoints", "error", err)
+	}
+	since, err := parseRedeliveryTime(r.FormValue("since"), h.defaultRedeliverySince(checkpoints, now))
+	if err != nil {
+		http.Error(w, "Invalid start of window", http.StatusBadRequest)
+		return
+	}
+	until, err := parseRedeliveryTime(r.FormValue("until"), now)
+	if err != nil || !until.After(since) {
+		http.Error(w, "Invalid end of window", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.operatorService.RecordWebhookRedelivery(ctx, contextResult.Session.GitHubUsername, since, until); err != nil {
+		http.Error(w, "Failed to write audit log", http.StatusInternalServerError)
+		return
+	}
+
+	logger := h.loggerFromContext(ctx).With("since", since, "until", until)
+	result, err := h.redeliverFailedGitHubWebhooks(ctx, h.githubClient, since, until)
+	toast := "redelivered"
+	switch {
+	case err != nil:
+		logger.Error("failed to redeliver GitHub webhooks", "error", err)
+		toast = "redelivery_failed"
+	case result.Errors > 0:
+		toast = "redelivery_failed"
+	case result.Redelivered == 0:
+		toast = "redelivery_none"
+	}
+	logger.Info("redelivered failed GitHub webhooks", "failed", result.Failed, "already_processed", result.Processed, "redelivered", result.Redelivered, "errors", result.Errors, "operator", contextResult.Session.GitHubUsername)
+	http.Redirect(w, r, "/admin/webhooks?toast="+toast, http.StatusSeeOther)
+}
+
+func (h *Handlers) redeliverFailedGitHubWebhooks(ctx context.Context, client appDeliveryClient, since, until time.Time) (webhookRedeliveryResult, error) {
+	result := webhookRedeliveryResult{}
+	if client == nil {
+		return result, fmt.Errorf("github client not configured")
+	}
+
+	failed, err := client.FailedAppDeliveries(ctx, since, until)
+	if err != nil {
+		return result, err
+	}
+	result.Failed = len(failed)
+	if len(failed) == 0 {
+		return result, nil
+	}
+
+	processed := map[string]bool{}
+	if h.webhookEvents != nil {
+		guids := make([]string, 0, len(failed))
+		for _, delivery := range failed {
+			guids = append(guids, delivery.GUID)
+		}
+		processed, err = h.webhookEvents.ProcessedDeliveryIDs(ctx, "github", guids)
+		if err != nil {
+			return result, fmt.Errorf("failed to check processed deliveries: %w", err)
+		}
+	}
+
+	logger := h.loggerFromContext(ctx)
+	for _, delivery := range failed {
+		if processed[delivery.GUID] {
+			result.Processed++
+			continue
+		}
+		if result.Redelivered+result.Errors >= maxRedeliveries {
+			break
+		}
+		if err := client.RedeliverAppDelivery(ctx, delivery.ID); err != nil {
+			logger.Warn("failed to request webhook redelivery", "error", err, "delivery_id", delivery.GUID, "event", delivery.Event)
+			result.Errors++
+			continue
+		}
+		result.Redelivered++
+	}
+	observability.MeterFromContext(ctx).Count("webhook.redelivery_requested", int64(result.Redelivered))
+	return result, nil
+}
+
+func parseRedeliveryTime(value string, fallback time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+	return time.ParseInLocation(redeliveryWindowLayout, value, time.UTC)
+}
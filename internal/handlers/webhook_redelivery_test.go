@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/config"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+)
+
+type fakeAppDeliveryClient struct {
+	failed       []githubapp.AppDelivery
+	redeliverErr map[int64]error
+	redelivered  []int64
+}
+
+func (f *fakeAppDeliveryClient) FailedAppDeliveries(context.Context, time.Time, time.Time) ([]githubapp.AppDelivery, error) {
+	return f.failed, nil
+}
+
+func (f *fakeAppDeliveryClient) RedeliverAppDelivery(_ context.Context, deliveryID int64) error {
+	if err := f.redeliverErr[deliveryID]; err != nil {
+		return err
+	}
+	f.redelivered = append(f.redelivered, deliveryID)
+	return nil
+}
+
+func TestRedeliverFailedGitHubWebhooks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		failed          []githubapp.AppDelivery
+		processed       map[string]bool
+		redeliverErr    map[int64]error
+		wantRedelivered []int64
+		wantResult      webhookRedeliveryResult
+	}{
+		{
+			name:       "nothing failed",
+			wantResult: webhookRedeliveryResult{},
+		},
+		{
+			name: "skips deliveries already processed",
+			failed: []githubapp.AppDelivery{
+				{ID: 1, GUID: "guid-1"},
+				{ID: 2, GUID: "guid-2"},
+			},
+			processed:       map[string]bool{"guid-1": true},
+			wantRedelivered: []int64{2},
+			wantResult:      webhookRedeliveryResult{Failed: 2, Processed: 1, Redelivered: 1},
+		},
+		{
+			name: "counts redelivery errors and continues",
+			failed: []githubapp.AppDelivery{
+				{ID: 1, GUID: "guid-1"},
+				{ID: 2, GUID: "guid-2"},
+			},
+			redeliverErr:    map[int64]error{1: errors.New("boom")},
+			wantRedelivered: []int64{2},
+			wantResult:      webhookRedeliveryResult{Failed: 2, Redelivered: 1, Errors: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &fakeAppDeliveryClient{failed: tt.failed, redeliverErr: tt.redeliverErr}
+			h := &Handlers{webhookEvents: &recordingWebhookEvents{processed: tt.processed}}
+			result, err := h.redeliverFailedGitHubWebhooks(context.Background(), client, time.Now().Add(-time.Hour), time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.wantResult {
+				t.Fatalf("expected result %+v, got %+v", tt.wantResult, result)
+			}
+			if len(client.redelivered) != len(tt.wantRedelivered) {
+				t.Fatalf("expected redeliveries %v, got %v", tt.wantRedelivered, client.redelivered)
+			}
+			for i, id := range tt.wantRedelivered {
+				if client.redelivered[i] != id {
+					t.Fatalf("expected redeliveries %v, got %v", tt.wantRedelivered, client.redelivered)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultRedeliverySince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	processedAt := now.Add(-20 * time.Minute)
+	h := &Handlers{config: &config.Config{}}
+
+	if got := h.defaultRedeliverySince(nil, now); !got.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("expected an hour back without a checkpoint, got %v", got)
+	}
+	checkpoints := []*db.WebhookCheckpoint{
+		{Provider: "stripe", Endpoint: "/webhooks/stripe", ProcessedAt: now.Add(-time.Minute)},
+		{Provider: "github", Endpoint: "/webhooks/github", ProcessedAt: processedAt},
+	}
+	if got := h.defaultRedeliverySince(checkpoints, now); !got.Equal(processedAt.Add(-redeliveryCheckpointSlack)) {
+		t.Fatalf("expected the window to start before the GitHub checkpoint, got %v", got)
+	}
+}
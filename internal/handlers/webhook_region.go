@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// githubWebhookRegion returns the home region of the shop a GitHub webhook is about. Events
+// without a repository, and single-region deployments, resolve to "".
+func (h *Handlers) githubWebhookRegion(ctx context.Context, payload []byte) (string, error) {
+	if h.config.Region == "" {
+		return "", nil
+	}
+	repoID := githubPayloadRepoID(payload)
+	if repoID == 0 {
+		return "", nil
+	}
+	region, err := h.shopStore.RegionForRepo(ctx, repoID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve shop region for repo %d: %w", repoID, err)
+	}
+	return region, nil
+}
+
+// stripeWebhookRegion returns the home region of the shop connected to the account a Stripe
+// event was sent for.
+func (h *Handlers) stripeWebhookRegion(ctx context.Context, accountID string) (string, error) {
+	if h.config.Region == "" || accountID == "" {
+		return "", nil
+	}
+	region, err := h.shopStore.RegionForStripeAccount(ctx, accountID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve shop region for stripe account %s: %w", accountID, err)
+	}
+	return region, nil
+}
+
+func githubPayloadRepoID(payload []byte) int64 {
+	var envelope struct {
+		Repository *struct {
+			ID int64 `json:"id"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil || envelope.Repository == nil {
+		return 0
+	}
+	return envelope.Repository.ID
+}
+
+// isRemoteRegion reports whether a shop homed in region must be processed by another region's
+// workers.
+func (h *Handlers) isRemoteRegion(region string) bool {
+	return region != "" && h.config.Region != "" && region != h.config.Region
+}
+
+// routeWebhookHome queues a webhook for its shop's home region when that is not this instance's
+// region, so the shop's customer data is only processed there. It returns true once a response
+// has been written.
+func (h *Handlers) routeWebhookHome(ctx context.Context, w http.ResponseWriter, region, provider, deliveryID, eventType string, payload []byte) bool {
+	if !h.isRemoteRegion(region) {
+		return false
+	}
+
+	if err := h.webhookQueue.Enqueue(ctx, region, provider, deliveryID, eventType, payload); err != nil {
+		h.loggerFromContext(ctx).Error("failed to route webhook to home region", "error", err, "provider", provider, "delivery_id", deliveryID, "region", region)
+		http.Error(w, "Processing failed", http.StatusServiceUnavailable)
+		return true
+	}
+
+	observability.MeterFromContext(ctx).Count("webhook.routed", 1, sentry.WithAttributes(
+		attribute.String("webhook.provider", provider),
+		attribute.String("webhook.event_type", eventType),
+		attribute.String("webhook.region", region),
+	))
+	h.tapWebhookResult(provider, deliveryID, eventType, "routed:"+region, time.Time{}, nil)
+	h.recordWebhookResult(ctx, provider, deliveryID, db.ReceivedWebhookQueued, nil)
+	w.WriteHeader(http.StatusAccepted)
+	return true
+}
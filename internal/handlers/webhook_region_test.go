@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/config"
+)
+
+func TestGitHubPayloadRepoID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    int64
+	}{
+		{name: "repository event", payload: `{"action":"opened","repository":{"id":42,"full_name":"acme/shop"}}`, want: 42},
+		{name: "installation event", payload: `{"action":"created","installation":{"id":7}}`, want: 0},
+		{name: "invalid json", payload: `{`, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := githubPayloadRepoID([]byte(tt.payload)); got != tt.want {
+				t.Fatalf("expected repo id %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRouteWebhookHome(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		localRegion string
+		shopRegion  string
+		wantRouted  bool
+	}{
+		{name: "single region", shopRegion: "eu", wantRouted: false},
+		{name: "unpinned shop", localRegion: "us", wantRouted: false},
+		{name: "home region", localRegion: "eu", shopRegion: "eu", wantRouted: false},
+		{name: "other region", localRegion: "us", shopRegion: "eu", wantRouted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queue := &recordingWebhookQueue{}
+			h := &Handlers{config: &config.Config{Region: tt.localRegion}, webhookQueue: queue}
+			rec := httptest.NewRecorder()
+			routed := h.routeWebhookHome(t.Context(), rec, tt.shopRegion, "github", "delivery-1", "issues", []byte(`{}`))
+			if routed != tt.wantRouted {
+				t.Fatalf("expected routed=%v, got %v", tt.wantRouted, routed)
+			}
+			if !tt.wantRouted {
+				return
+			}
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("expected status %d, got %d", http.StatusAccepted, rec.Code)
+			}
+			if len(queue.enqueued) != 1 || queue.enqueued[0] != tt.shopRegion+"/github:delivery-1" {
+				t.Fatalf("expected webhook queued for %s, got %v", tt.shopRegion, queue.enqueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/featureflags"
+	"github.com/gitshopapp/gitshop/internal/session"
+	"github.com/gitshopapp/gitshop/internal/webhooktap"
+)
+
+const (
+	webhookTapBuffer    = 64
+	webhookTapKeepAlive = 15 * time.Second
+)
+
+// tapWebhookReceived mirrors a verified webhook to open tap streams with buyer details redacted.
+func (h *Handlers) tapWebhookReceived(provider, deliveryID, eventType string, payload []byte) {
+	if !h.webhookTap.Active() {
+		return
+	}
+	h.webhookTap.Publish(webhooktap.Event{
+		Kind:       webhooktap.KindReceived,
+		Provider:   provider,
+		DeliveryID: deliveryID,
+		EventType:  eventType,
+		Payload:    webhooktap.Sanitize(payload),
+	})
+}
+
+// tapWebhookResult mirrors what happened to a webhook. A zero started omits the duration.
+func (h *Handlers) tapWebhookResult(provider, deliveryID, eventType, outcome string, started time.Time, err error) {
+	if !h.webhookTap.Active() {
+		return
+	}
+	event := webhooktap.Event{
+		Kind:       webhooktap.KindResult,
+		Provider:   provider,
+		DeliveryID: deliveryID,
+		EventType:  eventType,
+		Outcome:    outcome,
+	}
+	if !started.IsZero() {
+		event.DurationMS = time.Since(started).Milliseconds()
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	h.webhookTap.Publish(event)
+}
+
+func (h *Handlers) isOperator(sess *session.Data) bool {
+	if sess == nil || sess.GitHubUsername == "" {
+		return false
+	}
+	for _, login := range h.config.Operators {
+		if strings.EqualFold(strings.TrimSpace(login), sess.GitHubUsername) {
+			return true
+		}
+	}
+	return false
+}
+
+// CloseStreams ends open debugging streams so the server can shut down.
+func (h *Handlers) CloseStreams() {
+	h.webhookTap.Close()
+}
+
+// AdminWebhookTap streams webhooks received by this instance, and what became of them, as
+// server-sent events. It is only served while the webhook_tap feature flag is on for every shop,
+// and only to operators.
+func (h *Handlers) AdminWebhookTap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.webhookTap == nil || !h.featureFlags.EnabledEverywhere(ctx, featureflags.WebhookTap) {
+		http.NotFound(w, r)
+		return
+	}
+
+	contextResult := h.ResolveAdminContext(ctx, r, AdminContextRequirements{
+		Route: "admin.debug.webhook_tap",
+	})
+	if h.WriteAdminContextDecision(w, r, contextResult) {
+		return
+	}
+	if !h.isOperator(contextResult.Session) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	// The server's write timeout would otherwise cut the stream off.
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	events, unsubscribe := h.webhookTap.Subscribe(webhookTapBuffer)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
+		return
+	}
+	_ = controller.Flush()
+
+	keepAlive := time.NewTicker(webhookTapKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.loggerFromContext(ctx).Error("failed to encode webhook tap event", "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind, data); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/config"
+	"github.com/gitshopapp/gitshop/internal/session"
+	"github.com/gitshopapp/gitshop/internal/webhooktap"
+)
+
+func TestIsOperator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		sess *session.Data
+		want bool
+	}{
+		{name: "no session", want: false},
+		{name: "listed", sess: &session.Data{GitHubUsername: "octocat"}, want: true},
+		{name: "case insensitive", sess: &session.Data{GitHubUsername: "OctoCat"}, want: true},
+		{name: "not listed", sess: &session.Data{GitHubUsername: "seller"}, want: false},
+		{name: "missing username", sess: &session.Data{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &Handlers{config: &config.Config{Operators: []string{"octocat", " hubot"}}}
+			if got := h.isOperator(tt.sess); got != tt.want {
+				t.Fatalf("expected operator=%v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAdminWebhookTap_DisabledIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	h := &Handlers{config: &config.Config{}}
+	rec := httptest.NewRecorder()
+	h.AdminWebhookTap(rec, httptest.NewRequest(http.MethodGet, "/admin/debug/webhook-tap", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestTapWebhookResult(t *testing.T) {
+	t.Parallel()
+
+	tap := webhooktap.New()
+	h := &Handlers{webhookTap: tap}
+	h.tapWebhookReceived("github", "delivery-1", "issues", []byte(`{"secret":"x"}`))
+
+	events, unsubscribe := tap.Subscribe(2)
+	defer unsubscribe()
+	h.tapWebhookReceived("github", "delivery-1", "issues", []byte(`{"client_secret":"x","action":"opened"}`))
+	h.tapWebhookResult("github", "delivery-1", "issues", "deferred", time.Time{}, nil)
+
+	received := <-events
+	if received.Kind != webhooktap.KindReceived || string(received.Payload) != `{"action":"opened","client_secret":"[redacted]"}` {
+		t.Fatalf("unexpected received event %+v (%s)", received, received.Payload)
+	}
+	result := <-events
+	if result.Kind != webhooktap.KindResult || result.Outcome != "deferred" || result.DurationMS != 0 {
+		t.Fatalf("unexpected result event %+v", result)
+	}
+}
@@ -0,0 +1,109 @@
+// Package i18n holds the translated text GitShop shows buyers: issue comments, the generated order
+// form, and buyer emails. Each locale is a flat JSON catalog under locales/ mapping a message ID to
+// a fmt format string. To add a locale, copy locales/en.json, translate the values, and keep the
+// same verbs; translators can reorder arguments with indexed verbs such as %[2]s.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used when a shop does not set one, and for any message a locale is missing.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+type catalogs map[string]map[string]string
+
+var loadCatalogs = sync.OnceValues(func() (catalogs, error) {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locales: %w", err)
+	}
+	loaded := make(catalogs, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale %s: %w", entry.Name(), err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("failed to parse locale %s: %w", entry.Name(), err)
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return loaded, nil
+})
+
+// Supported returns the locale codes that have a catalog, sorted.
+func Supported() []string {
+	loaded, err := loadCatalogs()
+	if err != nil {
+		return []string{DefaultLocale}
+	}
+	locales := make([]string, 0, len(loaded))
+	for locale := range loaded {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// IsSupported reports whether locale has a catalog. An empty locale means the default.
+func IsSupported(locale string) bool {
+	return locale == "" || slices.Contains(Supported(), locale)
+}
+
+// Localizer looks up messages for one locale. The zero value uses DefaultLocale.
+type Localizer struct {
+	locale string
+}
+
+// New returns a Localizer for locale, falling back to DefaultLocale when it is not supported.
+func New(locale string) Localizer {
+	if locale == "" || !IsSupported(locale) {
+		return Localizer{}
+	}
+	return Localizer{locale: locale}
+}
+
+// Locale returns the locale messages are looked up in.
+func (l Localizer) Locale() string {
+	if l.locale == "" {
+		return DefaultLocale
+	}
+	return l.locale
+}
+
+// T returns the message for id formatted with args. Messages missing from the locale come from
+// DefaultLocale, and an unknown id is returned as is so a gap shows up in the output rather than
+// as an error.
+func (l Localizer) T(id string, args ...any) string {
+	message, ok := lookup(l.Locale(), id)
+	if !ok {
+		message, ok = lookup(DefaultLocale, id)
+	}
+	if !ok {
+		return id
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+func lookup(locale, id string) (string, bool) {
+	loaded, err := loadCatalogs()
+	if err != nil {
+		return "", false
+	}
+	message, ok := loaded[locale][id]
+	return message, ok
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[a-z]`)
+
+func TestCatalogsMatchDefaultLocale(t *testing.T) {
+	t.Parallel()
+
+	loaded, err := loadCatalogs()
+	if err != nil {
+		t.Fatalf("loadCatalogs() error = %v", err)
+	}
+	base := loaded[DefaultLocale]
+	if len(base) == 0 {
+		t.Fatalf("default locale %q has no messages", DefaultLocale)
+	}
+
+	for locale, messages := range loaded {
+		for id, message := range base {
+			translated, ok := messages[id]
+			if !ok {
+				t.Errorf("%s: missing message %q", locale, id)
+				continue
+			}
+			if got, want := len(verbPattern.FindAllString(translated, -1)), len(verbPattern.FindAllString(message, -1)); got != want {
+				t.Errorf("%s: message %q has %d format verbs, want %d", locale, id, got, want)
+			}
+		}
+		for id := range messages {
+			if _, ok := base[id]; !ok {
+				t.Errorf("%s: message %q is not in %s", locale, id, DefaultLocale)
+			}
+		}
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		locale string
+		id     string
+		args   []any
+		want   string
+	}{
+		{name: "default", locale: "", id: "duration.hours", args: []any{3}, want: "3 hours"},
+		{name: "translated", locale: "de", id: "duration.hours", args: []any{3}, want: "3 Stunden"},
+		{name: "no args", locale: "fr", id: "template.category_other", want: "Autres"},
+		{name: "unknown locale", locale: "xx", id: "duration.minute", want: "1 minute"},
+		{name: "unknown id", locale: "ja", id: "missing.message", want: "missing.message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := New(tt.locale).T(tt.id, tt.args...); got != tt.want {
+				t.Errorf("T(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSupported(t *testing.T) {
+	t.Parallel()
+
+	for _, locale := range []string{"", "en", "de", "es", "fr", "ja"} {
+		if !IsSupported(locale) {
+			t.Errorf("IsSupported(%q) = false, want true", locale)
+		}
+	}
+	if IsSupported("pt") {
+		t.Error(`IsSupported("pt") = true, want false`)
+	}
+	if got := New("xx").Locale(); got != DefaultLocale {
+		t.Errorf("New(xx).Locale() = %q, want %q", got, DefaultLocale)
+	}
+}
@@ -0,0 +1,200 @@
+// Package lemonsqueezy provides the Lemon Squeezy API client used by shops that take payments
+// through Lemon Squeezy instead of Stripe.
+package lemonsqueezy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const defaultBaseURL = "https://api.lemonsqueezy.com/v1"
+
+// Client calls the Lemon Squeezy API with a seller's API key.
+type Client struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(apiKey string) *Client {
+	return NewClientWithBaseURL(apiKey, defaultBaseURL)
+}
+
+func NewClientWithBaseURL(apiKey, baseURL string) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: observability.NewHTTPClient(20 * time.Second),
+	}
+}
+
+// CheckoutParams describes a one-off checkout for a store variant sold at a custom price.
+type CheckoutParams struct {
+	StoreID       string
+	VariantID     string
+	Name          string
+	Description   string
+	PriceCents    int64
+	CustomerEmail string
+	RedirectURL   string
+	ExpiresAt     time.Time
+	CustomData    map[string]string
+}
+
+type Checkout struct {
+	ID  string
+	URL string
+}
+
+type Store struct {
+	ID       string
+	Name     string
+	Currency string
+}
+
+type resource struct {
+	Type          string         `json:"type"`
+	ID            string         `json:"id,omitempty"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Relationships map[string]any `json:"relationships,omitempty"`
+}
+
+// CreateCheckout creates a checkout and returns its hosted payment URL.
+func (c *Client) CreateCheckout(ctx context.Context, params CheckoutParams) (*Checkout, error) {
+	checkoutData := map[string]any{"custom": params.CustomData}
+	if params.CustomerEmail != "" {
+		checkoutData["email"] = params.CustomerEmail
+	}
+	attributes := map[string]any{
+		"custom_price": params.PriceCents,
+		"product_options": map[string]any{
+			"name":         params.Name,
+			"description":  params.Description,
+			"redirect_url": params.RedirectURL,
+		},
+		"checkout_data": checkoutData,
+	}
+	if !params.ExpiresAt.IsZero() {
+		attributes["expires_at"] = params.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	body := map[string]any{"data": resource{
+		Type:       "checkouts",
+		Attributes: attributes,
+		Relationships: map[string]any{
+			"store":   map[string]any{"data": resource{Type: "stores", ID: params.StoreID}},
+			"variant": map[string]any{"data": resource{Type: "variants", ID: params.VariantID}},
+		},
+	}}
+
+	var response struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				URL string `json:"url"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/checkouts", body, &response); err != nil {
+		return nil, fmt.Errorf("failed to create checkout: %w", err)
+	}
+	if response.Data.Attributes.URL == "" {
+		return nil, fmt.Errorf("failed to create checkout: response has no url")
+	}
+	return &Checkout{ID: response.Data.ID, URL: response.Data.Attributes.URL}, nil
+}
+
+// GetStore fetches a store, which also confirms the API key can reach it.
+func (c *Client) GetStore(ctx context.Context, storeID string) (*Store, error) {
+	var response struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Name     string `json:"name"`
+				Currency string `json:"currency"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/stores/"+storeID, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get store: %w", err)
+	}
+	return &Store{
+		ID:       response.Data.ID,
+		Name:     response.Data.Attributes.Name,
+		Currency: response.Data.Attributes.Currency,
+	}, nil
+}
+
+// RefundOrder refunds amountCents of a Lemon Squeezy order.
+func (c *Client) RefundOrder(ctx context.Context, orderID string, amountCents int64) error {
+	body := map[string]any{"data": resource{
+		Type:       "orders",
+		ID:         orderID,
+		Attributes: map[string]any{"amount": amountCents},
+	}}
+	if err := c.do(ctx, http.MethodPost, "/orders/"+orderID+"/refund", body, nil); err != nil {
+		return fmt.Errorf("failed to refund order: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.api+json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.api+json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	respBody, readErr := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if readErr != nil {
+		return fmt.Errorf("failed to read response: %w", readErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp struct {
+			Errors []struct {
+				Detail string `json:"detail"`
+			} `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &errResp) == nil && len(errResp.Errors) > 0 && errResp.Errors[0].Detail != "" {
+			return fmt.Errorf("lemon squeezy API returned status %d: %s", resp.StatusCode, errResp.Errors[0].Detail)
+		}
+		return fmt.Errorf("lemon squeezy API returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
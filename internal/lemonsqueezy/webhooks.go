@@ -0,0 +1,56 @@
+package lemonsqueezy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// WebhookEvent is the part of a Lemon Squeezy webhook GitShop reads. Order events carry the
+// custom data passed when the checkout was created.
+type WebhookEvent struct {
+	Meta struct {
+		EventName  string            `json:"event_name"`
+		CustomData map[string]string `json:"custom_data"`
+	} `json:"meta"`
+	Data struct {
+		Type       string `json:"type"`
+		ID         string `json:"id"`
+		Attributes struct {
+			Identifier string `json:"identifier"`
+			Status     string `json:"status"`
+			UserName   string `json:"user_name"`
+			UserEmail  string `json:"user_email"`
+			Total      int64  `json:"total"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// ParseWebhook verifies the X-Signature header, a hex HMAC-SHA256 of the body keyed with the
+// store's signing secret, and decodes the event.
+func ParseWebhook(payload []byte, signature, secret string) (*WebhookEvent, error) {
+	if signature == "" {
+		return nil, fmt.Errorf("missing lemon squeezy signature header")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("lemon squeezy signing secret is not configured")
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("webhook signature validation failed: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return nil, fmt.Errorf("webhook signature validation failed")
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	return &event, nil
+}
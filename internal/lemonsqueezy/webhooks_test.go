@@ -0,0 +1,57 @@
+package lemonsqueezy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseWebhook(t *testing.T) {
+	t.Parallel()
+
+	secret := "ls_signing_secret"
+	payload := []byte(`{"meta":{"event_name":"order_created","custom_data":{"order_id":"b3f4"}},"data":{"type":"orders","id":"42","attributes":{"status":"paid","user_email":"buyer@example.com","total":2500}}}`)
+
+	tests := []struct {
+		name      string
+		signature string
+		secret    string
+		wantErr   bool
+	}{
+		{name: "valid", signature: sign(payload, secret), secret: secret},
+		{name: "missing signature", signature: "", secret: secret, wantErr: true},
+		{name: "wrong secret", signature: sign(payload, "other"), secret: secret, wantErr: true},
+		{name: "not hex", signature: "zz", secret: secret, wantErr: true},
+		{name: "no secret configured", signature: sign(payload, secret), secret: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := ParseWebhook(payload, tt.signature, tt.secret)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.Meta.EventName != "order_created" || event.Data.ID != "42" || event.Meta.CustomData["order_id"] != "b3f4" {
+				t.Fatalf("unexpected event: %+v", event)
+			}
+			if event.Data.Attributes.Status != "paid" || event.Data.Attributes.Total != 2500 {
+				t.Fatalf("unexpected attributes: %+v", event.Data.Attributes)
+			}
+		})
+	}
+}
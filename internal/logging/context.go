@@ -19,14 +19,19 @@ func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
 }
 
 // FromContext returns the logger stored in context or the fallback logger.
-// If neither is available, it returns a no-op logger.
+// If neither is available, it returns a no-op logger. The request logger already
+// carries the request ID; a fallback logger gets it from the context.
 func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
 	if ctx != nil {
 		if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok && logger != nil {
 			return logger
 		}
 	}
-	return ensureLogger(fallback)
+	logger := ensureLogger(fallback)
+	if requestID := RequestID(ctx); requestID != "" {
+		logger = logger.With("request_id", requestID)
+	}
+	return logger
 }
 
 func ensureLogger(logger *slog.Logger) *slog.Logger {
@@ -0,0 +1,22 @@
+package logging
+
+import "context"
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID returns a context that carries the ID of the request being served.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored in context, or "" outside a request.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
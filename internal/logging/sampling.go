@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// SamplingHandler passes one in every n debug records with the same message and every record
+// above debug, so chatty debug logs can stay on in production without flooding the log store.
+// Kept records carry a sample_rate attribute so aggregations can scale counts back up.
+func SamplingHandler(handler slog.Handler, n int) slog.Handler {
+	if n <= 1 {
+		return handler
+	}
+	return &samplingHandler{handler: handler, every: uint64(n), seen: &sync.Map{}}
+}
+
+type samplingHandler struct {
+	handler slog.Handler
+	every   uint64
+	// seen counts debug records by message. It is shared with handlers derived by WithAttrs and
+	// WithGroup, so a message is sampled the same way whichever logger writes it.
+	seen *sync.Map
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level > slog.LevelDebug {
+		return h.handler.Handle(ctx, record)
+	}
+	counter, _ := h.seen.LoadOrStore(record.Message, new(atomic.Uint64))
+	if (counter.(*atomic.Uint64).Add(1)-1)%h.every != 0 {
+		return nil
+	}
+	record = record.Clone()
+	record.AddAttrs(slog.Uint64("sample_rate", h.every))
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{handler: h.handler.WithAttrs(attrs), every: h.every, seen: h.seen}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{handler: h.handler.WithGroup(name), every: h.every, seen: h.seen}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	logger := slog.New(SamplingHandler(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}), 3))
+	derived := logger.With("component", "worker")
+
+	for range 4 {
+		logger.Debug("cache hit")
+		derived.Debug("cache hit")
+	}
+	logger.Debug("cache miss")
+	logger.Info("request completed")
+	logger.Info("request completed")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	count := func(message string) int {
+		n := 0
+		for _, line := range lines {
+			if strings.Contains(line, "msg=\""+message+"\"") || strings.Contains(line, "msg="+message) {
+				n++
+			}
+		}
+		return n
+	}
+	// Eight "cache hit" records across both loggers keep the 1st, 4th, and 7th.
+	if got := count("cache hit"); got != 3 {
+		t.Fatalf("kept %d cache hit records, want 3:\n%s", got, out.String())
+	}
+	if got := count("cache miss"); got != 1 {
+		t.Fatalf("kept %d cache miss records, want 1", got)
+	}
+	if got := count("request completed"); got != 2 {
+		t.Fatalf("kept %d info records, want 2", got)
+	}
+	if !strings.Contains(lines[0], "sample_rate=3") || strings.Contains(out.String(), "request completed sample_rate") {
+		t.Fatalf("unexpected sample_rate attributes:\n%s", out.String())
+	}
+}
+
+func TestFromContextAddsRequestID(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	fallback := slog.New(slog.NewTextHandler(&out, nil))
+
+	FromContext(WithRequestID(context.Background(), "req-123"), fallback).Info("outbound call")
+	if !strings.Contains(out.String(), "request_id=req-123") {
+		t.Fatalf("expected request_id on fallback logger, got %q", out.String())
+	}
+
+	out.Reset()
+	requestLogger := fallback.With("request_id", "req-456")
+	ctx := WithLogger(WithRequestID(context.Background(), "req-456"), requestLogger)
+	FromContext(ctx, fallback).Info("handled")
+	if strings.Count(out.String(), "request_id=") != 1 {
+		t.Fatalf("expected one request_id from the request logger, got %q", out.String())
+	}
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RoundTripper logs each outbound call to service at debug level with its status and duration.
+// Calls made while serving a request are logged with that request's logger, so they share its
+// request ID; others use fallback.
+func RoundTripper(base http.RoundTripper, service string, fallback *slog.Logger) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &loggingTransport{base: base, service: service, fallback: fallback}
+}
+
+type loggingTransport struct {
+	base     http.RoundTripper
+	service  string
+	fallback *slog.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	ctx := req.Context()
+	logger := FromContext(ctx, t.fallback)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return resp, err
+	}
+	attrs := []any{
+		"service", t.service,
+		"method", req.Method,
+		"path", req.URL.Path,
+		"duration_ms", time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		logger.DebugContext(ctx, "outbound call failed", append(attrs, "error", err)...)
+		return resp, err
+	}
+	logger.DebugContext(ctx, "outbound call completed", append(attrs, "status", resp.StatusCode)...)
+	return resp, err
+}
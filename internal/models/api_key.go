@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey lets a script or third-party client read one shop through the GraphQL API. Only a hash
+// of the token is stored; TokenPrefix is its first characters, to tell keys apart. Keys without
+// CustomerData cannot read buyers' personal details.
+type APIKey struct {
+	ID           uuid.UUID  `json:"id"`
+	ShopID       uuid.UUID  `json:"shop_id"`
+	Name         string     `json:"name"`
+	TokenPrefix  string     `json:"token_prefix"`
+	CustomerData bool       `json:"customer_data"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer is a buyer's record in a shop, keyed by lowercased GitHub username. Its stats are
+// recomputed from the buyer's paid orders whenever one is paid, refunded, or returned.
+// LifetimeValueCents leaves out refunded and returned orders. EmailHash is the blind index of the
+// email on their latest order, never the email itself.
+type Customer struct {
+	ID                 uuid.UUID `json:"id"`
+	ShopID             uuid.UUID `json:"shop_id"`
+	GitHubUsername     string    `json:"github_username"`
+	EmailHash          string    `json:"-"`
+	Orders             int       `json:"orders"`
+	LifetimeValueCents int64     `json:"lifetime_value_cents"`
+	FirstOrderAt       time.Time `json:"first_order_at"`
+	LastOrderAt        time.Time `json:"last_order_at"`
+	Notes              string    `json:"notes"`
+	NotesUpdatedBy     string    `json:"notes_updated_by"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
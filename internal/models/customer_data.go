@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomerSubjectKind is how a customer was identified in a data request.
+type CustomerSubjectKind string
+
+const (
+	CustomerSubjectEmail          CustomerSubjectKind = "email"
+	CustomerSubjectGitHubUsername CustomerSubjectKind = "github_username"
+)
+
+// DataErasure is an audit record of a customer's data being erased from a shop. It keeps a hash
+// of the identifier rather than the identifier itself.
+type DataErasure struct {
+	ID          uuid.UUID           `json:"id"`
+	ShopID      uuid.UUID           `json:"shop_id"`
+	SubjectKind CustomerSubjectKind `json:"subject_kind"`
+	SubjectHash string              `json:"subject_hash"`
+	OrderCount  int                 `json:"order_count"`
+	ErasedBy    string              `json:"erased_by"`
+	ErasedAt    time.Time           `json:"erased_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GitHubEffectKind is the issue mutation a GitHub outbox entry applies.
+type GitHubEffectKind string
+
+const (
+	GitHubEffectComment                GitHubEffectKind = "comment"
+	GitHubEffectAddLabel               GitHubEffectKind = "add_label"
+	GitHubEffectRemoveLabel            GitHubEffectKind = "remove_label"
+	GitHubEffectDeleteCheckoutComments GitHubEffectKind = "delete_checkout_comments"
+)
+
+// GitHubEffect is an issue comment or label change queued in the outbox. Value holds the comment
+// body or label name. When EventKind is set, applying the effect adds it to the order's event log
+// with EventDetail.
+type GitHubEffect struct {
+	ID             uuid.UUID        `json:"id"`
+	IdempotencyKey string           `json:"idempotency_key"`
+	ShopID         uuid.UUID        `json:"shop_id"`
+	OrderID        uuid.UUID        `json:"order_id"`
+	InstallationID int64            `json:"installation_id"`
+	RepoFullName   string           `json:"repo_full_name"`
+	IssueNumber    int              `json:"issue_number"`
+	Kind           GitHubEffectKind `json:"kind"`
+	Value          string           `json:"value"`
+	EventKind      OrderEventKind   `json:"event_kind"`
+	EventDetail    string           `json:"event_detail"`
+	Attempts       int              `json:"attempts"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
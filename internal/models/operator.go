@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OperatorAction is something a platform operator did in the internal admin area.
+type OperatorAction string
+
+const (
+	OperatorActionSearchShops          OperatorAction = "search_shops"
+	OperatorActionViewShop             OperatorAction = "view_shop"
+	OperatorActionReplayWebhook        OperatorAction = "replay_webhook"
+	OperatorActionRedeliverWebhooks    OperatorAction = "redeliver_webhooks"
+	OperatorActionImpersonationStarted OperatorAction = "impersonation_started"
+	OperatorActionImpersonationEnded   OperatorAction = "impersonation_ended"
+	OperatorActionSuspendShop          OperatorAction = "suspend_shop"
+	OperatorActionUnsuspendShop        OperatorAction = "unsuspend_shop"
+	OperatorActionSuspensionNotes      OperatorAction = "suspension_notes"
+	OperatorActionSupportView          OperatorAction = "support_view"
+)
+
+// OperatorAuditEntry is an audit record of one operator action. ShopID is empty for actions
+// that span shops, and once the shop is deleted.
+type OperatorAuditEntry struct {
+	ID           uuid.UUID      `json:"id"`
+	Operator     string         `json:"operator"`
+	Action       OperatorAction `json:"action"`
+	ShopID       uuid.UUID      `json:"shop_id"`
+	RepoFullName string         `json:"repo_full_name"`
+	Detail       string         `json:"detail"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
@@ -16,33 +16,257 @@ const (
 	StatusShipped        OrderStatus = "shipped"
 	StatusDelivered      OrderStatus = "delivered"
 	StatusRefunded       OrderStatus = "refunded"
+	StatusInquiry        OrderStatus = "inquiry"
+	StatusReturned       OrderStatus = "returned"
+	StatusCancelled      OrderStatus = "cancelled"
+	// StatusAwaitingApproval holds an order until the seller approves it; it has no checkout yet.
+	StatusAwaitingApproval OrderStatus = "awaiting_approval"
+	// StatusAddressIssue holds a paid order whose shipping address failed validation until the
+	// seller confirms or corrects it.
+	StatusAddressIssue OrderStatus = "address_issue"
 )
 
 type Order struct {
-	ID                      uuid.UUID      `json:"id"`
-	ShopID                  uuid.UUID      `json:"shop_id"`
-	GitHubIssueNumber       int            `json:"github_issue_number"`
-	OrderNumber             int            `json:"order_number"`
-	GitHubIssueURL          string         `json:"github_issue_url"`
-	GitHubUsername          string         `json:"github_username"`
-	SKU                     string         `json:"sku"`
-	Options                 map[string]any `json:"options"`
-	SubtotalCents           int            `json:"subtotal_cents"`
-	ShippingCents           int            `json:"shipping_cents"`
-	TaxCents                int            `json:"tax_cents"`
-	TotalCents              int            `json:"total_cents"`
-	StripeCheckoutSessionID string         `json:"stripe_checkout_session_id"`
-	StripePaymentIntentID   string         `json:"stripe_payment_intent_id"`
-	CustomerEmail           string         `json:"customer_email"`
-	CustomerName            string         `json:"customer_name"`
-	ShippingAddress         map[string]any `json:"shipping_address"`
-	TrackingNumber          string         `json:"tracking_number"`
-	TrackingURL             string         `json:"tracking_url"`
-	Carrier                 string         `json:"carrier"`
-	FailureReason           string         `json:"failure_reason"`
-	Status                  OrderStatus    `json:"status"`
-	CreatedAt               time.Time      `json:"created_at"`
-	PaidAt                  time.Time      `json:"paid_at"`
-	ShippedAt               time.Time      `json:"shipped_at"`
-	DeliveredAt             time.Time      `json:"delivered_at"`
+	ID                      uuid.UUID          `json:"id"`
+	ShopID                  uuid.UUID          `json:"shop_id"`
+	GitHubIssueNumber       int                `json:"github_issue_number"`
+	OrderNumber             int                `json:"order_number"`
+	GitHubIssueURL          string             `json:"github_issue_url"`
+	GitHubUsername          string             `json:"github_username"`
+	SKU                     string             `json:"sku"`
+	Options                 map[string]any     `json:"options"`
+	SubtotalCents           int64              `json:"subtotal_cents"`
+	ShippingCents           int64              `json:"shipping_cents"`
+	TaxCents                int64              `json:"tax_cents"`
+	TotalCents              int64              `json:"total_cents"`
+	StripeCheckoutSessionID string             `json:"stripe_checkout_session_id"`
+	StripePaymentIntentID   string             `json:"stripe_payment_intent_id"`
+	CustomerEmail           string             `json:"customer_email"`
+	CustomerName            string             `json:"customer_name"`
+	ShippingAddress         map[string]any     `json:"shipping_address"`
+	TrackingNumber          string             `json:"tracking_number"`
+	TrackingURL             string             `json:"tracking_url"`
+	Carrier                 string             `json:"carrier"`
+	FailureReason           string             `json:"failure_reason"`
+	Status                  OrderStatus        `json:"status"`
+	CreatedAt               time.Time          `json:"created_at"`
+	PaidAt                  time.Time          `json:"paid_at"`
+	ShippedAt               time.Time          `json:"shipped_at"`
+	DeliveredAt             time.Time          `json:"delivered_at"`
+	RefundedAt              time.Time          `json:"refunded_at"`
+	TermsVersion            string             `json:"terms_version"`
+	TermsAcceptedAt         time.Time          `json:"terms_accepted_at"`
+	VerificationStatus      VerificationStatus `json:"verification_status"`
+	IdentityVerificationID  string             `json:"identity_verification_id"`
+	StripeInvoiceID         string             `json:"stripe_invoice_id"`
+	ReturnedAt              time.Time          `json:"returned_at"`
+	CheckoutMethod          CheckoutMethod     `json:"checkout_method"`
+	StripePaymentLinkID     string             `json:"stripe_payment_link_id"`
+	Category                string             `json:"category"`
+	CancelledAt             time.Time          `json:"cancelled_at"`
+	CancellationReason      string             `json:"cancellation_reason"`
+	ApprovedAt              time.Time          `json:"approved_at"`
+	ApprovedBy              string             `json:"approved_by"`
+	StripeSubscriptionID    string             `json:"stripe_subscription_id"`
+	SubscriptionStatus      SubscriptionStatus `json:"subscription_status"`
+	SubscriptionCancelledAt time.Time          `json:"subscription_cancelled_at"`
+	CustomFields            []OrderCustomField `json:"custom_fields"`
+	ReceiptURL              string             `json:"receipt_url"`
+	TaxIDType               string             `json:"tax_id_type"`
+	TaxID                   string             `json:"tax_id"`
+	TestMode                bool               `json:"test_mode"`
+	AddressIssue            string             `json:"address_issue"`
+	CheckoutReminderOptOut  bool               `json:"checkout_reminder_opt_out"`
+	CheckoutExpiredAt       time.Time          `json:"checkout_expired_at"`
+	// Campaign is the sale that priced the order, empty when it was sold at list price.
+	Campaign string `json:"campaign"`
+	// DisplayCurrency is the lowercase currency the buyer asked to see estimates in, empty for
+	// none. The order is still charged in the shop's currency.
+	DisplayCurrency string `json:"display_currency"`
+}
+
+// OrderCustomField is the buyer's answer to a checkout custom field from gitshop.yaml. The label
+// is kept as the buyer saw it, so renaming the field later does not change past orders.
+type OrderCustomField struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// SubscriptionStatus tracks the Stripe subscription behind an order for a product with billing
+// set. It is empty for one-time orders.
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive    SubscriptionStatus = "active"
+	SubscriptionCancelled SubscriptionStatus = "cancelled"
+)
+
+// CheckoutMethod is how the buyer was asked to pay. Payment Links are only used when a
+// Checkout Session could not be created.
+type CheckoutMethod string
+
+const (
+	CheckoutMethodSession      CheckoutMethod = "checkout_session"
+	CheckoutMethodPaymentLink  CheckoutMethod = "payment_link"
+	CheckoutMethodLemonSqueezy CheckoutMethod = "lemonsqueezy_checkout"
+)
+
+// VerificationStatus tracks the eligibility check for restricted products.
+type VerificationStatus string
+
+const (
+	VerificationAttested      VerificationStatus = "attested"
+	VerificationPending       VerificationStatus = "pending"
+	VerificationVerified      VerificationStatus = "verified"
+	VerificationRequiresInput VerificationStatus = "requires_input"
+	VerificationRejected      VerificationStatus = "rejected"
+)
+
+// ShopOrderSummary counts a shop's orders for the installation overview. Orders, RevenueCents,
+// and FailedPayments cover a recent window; UnshippedPaid counts every paid order not yet shipped.
+type ShopOrderSummary struct {
+	Orders         int   `json:"orders"`
+	RevenueCents   int64 `json:"revenue_cents"`
+	FailedPayments int   `json:"failed_payments"`
+	UnshippedPaid  int   `json:"unshipped_paid"`
+}
+
+// AbandonedCheckoutSummary counts a shop's checkouts that expired unpaid in a recent window.
+// Abandoned and ValueCents cover the orders still unpaid; Recovered and RecoveredCents cover the
+// ones paid after a new checkout link was sent.
+type AbandonedCheckoutSummary struct {
+	Abandoned      int   `json:"abandoned"`
+	ValueCents     int64 `json:"value_cents"`
+	Recovered      int   `json:"recovered"`
+	RecoveredCents int64 `json:"recovered_cents"`
+}
+
+// AbandonedSKU counts the abandoned checkouts of one product.
+type AbandonedSKU struct {
+	SKU        string `json:"sku"`
+	Checkouts  int    `json:"checkouts"`
+	ValueCents int64  `json:"value_cents"`
+}
+
+// CampaignRevenue totals a shop's paid orders priced by one campaign. An empty Campaign holds the
+// orders sold at list price.
+type CampaignRevenue struct {
+	Campaign     string `json:"campaign"`
+	Orders       int    `json:"orders"`
+	RevenueCents int64  `json:"revenue_cents"`
+}
+
+// RelatedOrder is another paid order placed by the same buyer, linked from an order's page.
+type RelatedOrder struct {
+	ID          uuid.UUID   `json:"id"`
+	OrderNumber int         `json:"order_number"`
+	Status      OrderStatus `json:"status"`
+	TotalCents  int64       `json:"total_cents"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type OrderEmailKind string
+
+const (
+	OrderEmailConfirmation OrderEmailKind = "order_confirmation"
+	OrderEmailShipped      OrderEmailKind = "order_shipped"
+	OrderEmailDelivered    OrderEmailKind = "order_delivered"
+	OrderEmailReturned     OrderEmailKind = "order_returned"
+)
+
+// EmailDeliveryStatus is what the shop's email provider last reported about a sent email.
+type EmailDeliveryStatus string
+
+const (
+	EmailSent       EmailDeliveryStatus = "sent"
+	EmailDelivered  EmailDeliveryStatus = "delivered"
+	EmailBounced    EmailDeliveryStatus = "bounced"
+	EmailComplained EmailDeliveryStatus = "complained"
+)
+
+type OrderEmail struct {
+	ID             uuid.UUID           `json:"id"`
+	OrderID        uuid.UUID           `json:"order_id"`
+	Kind           OrderEmailKind      `json:"kind"`
+	Recipient      string              `json:"recipient"`
+	SentAt         time.Time           `json:"sent_at"`
+	DeliveryStatus EmailDeliveryStatus `json:"delivery_status"`
+	DeliveryDetail string              `json:"delivery_detail,omitempty"`
+}
+
+// OrderEventKind names an entry in an order's event log.
+type OrderEventKind string
+
+const (
+	OrderEventCreated         OrderEventKind = "created"
+	OrderEventCheckoutCreated OrderEventKind = "checkout_created"
+	OrderEventPaid            OrderEventKind = "paid"
+	OrderEventCommentPosted   OrderEventKind = "comment_posted"
+	OrderEventEmailSent       OrderEventKind = "email_sent"
+	OrderEventShipped         OrderEventKind = "shipped"
+	OrderEventDelivered       OrderEventKind = "delivered"
+	OrderEventFailed          OrderEventKind = "failed"
+)
+
+// OrderEvent is one transition or side effect of an order, kept so support can see what happened
+// without searching logs. Detail never holds customer details.
+type OrderEvent struct {
+	ID        uuid.UUID      `json:"id"`
+	OrderID   uuid.UUID      `json:"order_id"`
+	Kind      OrderEventKind `json:"kind"`
+	Detail    string         `json:"detail,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// OrderEdit is an audit record of an unpaid order re-priced after its issue was edited.
+type OrderEdit struct {
+	ID                 uuid.UUID      `json:"id"`
+	OrderID            uuid.UUID      `json:"order_id"`
+	PreviousSKU        string         `json:"previous_sku"`
+	SKU                string         `json:"sku"`
+	PreviousOptions    map[string]any `json:"previous_options"`
+	Options            map[string]any `json:"options"`
+	PreviousTotalCents int64          `json:"previous_total_cents"`
+	TotalCents         int64          `json:"total_cents"`
+	EditedBy           string         `json:"edited_by"`
+	EditedAt           time.Time      `json:"edited_at"`
+}
+
+// OrderNote is a private note a seller left on an order. Buyers never see it.
+type OrderNote struct {
+	ID        uuid.UUID `json:"id"`
+	OrderID   uuid.UUID `json:"order_id"`
+	Body      string    `json:"body"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewSource is how the buyer left a rating.
+type ReviewSource string
+
+const (
+	ReviewSourceReaction ReviewSource = "reaction"
+	ReviewSourceComment  ReviewSource = "comment"
+)
+
+// OrderReview is the review invitation on a delivered order and the buyer's rating, if any.
+// Rating is 1 to 5 stars, or 0 while the buyer has not rated the order.
+type OrderReview struct {
+	OrderID     uuid.UUID    `json:"order_id"`
+	ShopID      uuid.UUID    `json:"shop_id"`
+	SKU         string       `json:"sku"`
+	CommentID   int64        `json:"comment_id"`
+	RequestedAt time.Time    `json:"requested_at"`
+	Rating      int          `json:"rating"`
+	Body        string       `json:"body"`
+	Source      ReviewSource `json:"source"`
+	RatedAt     time.Time    `json:"rated_at"`
+}
+
+// ProductRating aggregates the ratings buyers gave one product.
+type ProductRating struct {
+	SKU     string  `json:"sku"`
+	Reviews int     `json:"reviews"`
+	Average float64 `json:"average"`
 }
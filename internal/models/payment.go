@@ -0,0 +1,31 @@
+package models
+
+// PaymentProcessor is the service a shop takes payments through.
+type PaymentProcessor string
+
+const (
+	PaymentProcessorStripe       PaymentProcessor = "stripe"
+	PaymentProcessorLemonSqueezy PaymentProcessor = "lemonsqueezy"
+)
+
+// PaymentSettings selects a shop's payment processor. Stripe shops are paid through their
+// connected account; other processors keep the shop's own credentials here. StripeTestMode sends
+// new orders through the platform's Stripe test keys so the seller can try checkout end to end.
+type PaymentSettings struct {
+	Processor      PaymentProcessor     `json:"processor"`
+	LemonSqueezy   LemonSqueezySettings `json:"lemonsqueezy"`
+	StripeTestMode bool                 `json:"stripe_test_mode"`
+}
+
+// LemonSqueezySettings connects a shop to a Lemon Squeezy store. Every order is sold as the
+// given variant with a custom price, so the seller only has to create one product.
+type LemonSqueezySettings struct {
+	StoreID       string `json:"store_id"`
+	VariantID     string `json:"variant_id"`
+	APIKey        string `json:"api_key"`
+	WebhookSecret string `json:"webhook_secret"`
+}
+
+func (s LemonSqueezySettings) Configured() bool {
+	return s.StoreID != "" && s.VariantID != "" && s.APIKey != "" && s.WebhookSecret != ""
+}
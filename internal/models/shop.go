@@ -23,6 +23,75 @@ type Shop struct {
 	UpdatedAt              time.Time      `json:"updated_at"`
 }
 
+// ProcessingRegion is where a shop's order data is processed and which customer logs it keeps.
+// It does not move where the data is stored. An empty Region means the shop is not pinned and
+// any region may process it.
+type ProcessingRegion struct {
+	Region string `json:"region"`
+	// RetainEmailLog keeps customer addresses in the order email log. When false, sent emails
+	// are still logged without the recipient.
+	RetainEmailLog bool `json:"retain_email_log"`
+}
+
+// StripeCapabilities is what Stripe last reported the shop's connected account can do. CheckedAt
+// is zero until GitShop has seen the account, and unknown capabilities never pause a shop.
+type StripeCapabilities struct {
+	DetailsSubmitted bool      `json:"details_submitted"`
+	ChargesEnabled   bool      `json:"charges_enabled"`
+	PayoutsEnabled   bool      `json:"payouts_enabled"`
+	CheckedAt        time.Time `json:"checked_at"`
+}
+
+// ChargesPaused reports whether Stripe has stopped the account from taking payments.
+func (c StripeCapabilities) ChargesPaused() bool {
+	return !c.CheckedAt.IsZero() && !c.ChargesEnabled
+}
+
+// ShopSuspension is the platform's moderation state for a shop. A suspended shop keeps its
+// GitHub App installation and dashboard, but takes no new orders or payments.
+type ShopSuspension struct {
+	SuspendedAt time.Time `json:"suspended_at"`
+	SuspendedBy string    `json:"suspended_by"`
+	Reason      string    `json:"reason"`
+	Notes       string    `json:"notes"`
+	Appeal      string    `json:"appeal"`
+	AppealedAt  time.Time `json:"appealed_at"`
+}
+
+// Suspended reports whether the shop is currently suspended.
+func (s *ShopSuspension) Suspended() bool {
+	return s != nil && !s.SuspendedAt.IsZero()
+}
+
+// SetupPullRequest is the pull request GitShop opened to bootstrap a shop repository.
+type SetupPullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+// RepoOrphanReason says why GitShop could not resolve a shop's repository.
+type RepoOrphanReason string
+
+const (
+	// RepoOrphanNotFound means the installation cannot see the repository: it was deleted,
+	// transferred away, or removed from the installation without a webhook reaching GitShop.
+	RepoOrphanNotFound RepoOrphanReason = "repository_not_found"
+	// RepoOrphanInstallationUnavailable means GitHub no longer issues tokens for the installation.
+	RepoOrphanInstallationUnavailable RepoOrphanReason = "installation_unavailable"
+	// RepoOrphanTransferred means the repository moved to an account under another installation.
+	RepoOrphanTransferred RepoOrphanReason = "transferred"
+)
+
+// OrphanedShop is a connected shop whose repository GitShop could not resolve, listed for an
+// operator to review.
+type OrphanedShop struct {
+	ShopID               uuid.UUID        `json:"shop_id"`
+	GitHubInstallationID int64            `json:"github_installation_id"`
+	GitHubRepoFullName   string           `json:"github_repo_full_name"`
+	OrphanedAt           time.Time        `json:"orphaned_at"`
+	Reason               RepoOrphanReason `json:"reason"`
+}
+
 func (s *Shop) IsConnected() bool {
 	return s != nil && s.DisconnectedAt.IsZero()
 }
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WarehouseOrder is an order as exported for analytics. It leaves out the buyer's GitHub
+// username, email, name, and address, so exports can be shared with a warehouse freely.
+type WarehouseOrder struct {
+	ID                 uuid.UUID   `json:"id"`
+	ShopID             uuid.UUID   `json:"shop_id"`
+	OrderNumber        int         `json:"order_number"`
+	Status             OrderStatus `json:"status"`
+	SKU                string      `json:"sku"`
+	Category           string      `json:"category"`
+	Campaign           string      `json:"campaign"`
+	SubtotalCents      int64       `json:"subtotal_cents"`
+	ShippingCents      int64       `json:"shipping_cents"`
+	TaxCents           int64       `json:"tax_cents"`
+	TotalCents         int64       `json:"total_cents"`
+	DisplayCurrency    string      `json:"display_currency"`
+	TestMode           bool        `json:"test_mode"`
+	CancellationReason string      `json:"cancellation_reason"`
+	CreatedAt          time.Time   `json:"created_at"`
+	PaidAt             *time.Time  `json:"paid_at"`
+	ShippedAt          *time.Time  `json:"shipped_at"`
+	DeliveredAt        *time.Time  `json:"delivered_at"`
+	RefundedAt         *time.Time  `json:"refunded_at"`
+	ReturnedAt         *time.Time  `json:"returned_at"`
+	CancelledAt        *time.Time  `json:"cancelled_at"`
+}
+
+// WarehouseOrderEvent is an entry from an order's event log, such as paid or email_sent, with the
+// same detail as the order page shows.
+type WarehouseOrderEvent struct {
+	OrderID    uuid.UUID `json:"order_id"`
+	ShopID     uuid.UUID `json:"shop_id"`
+	Event      string    `json:"event"`
+	Detail     string    `json:"detail"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
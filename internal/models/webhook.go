@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookOrderCreated   WebhookEvent = "order.created"
+	WebhookOrderPaid      WebhookEvent = "order.paid"
+	WebhookOrderShipped   WebhookEvent = "order.shipped"
+	WebhookOrderDelivered WebhookEvent = "order.delivered"
+	WebhookOrderReturned  WebhookEvent = "order.returned"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookEndpoint is the URL a shop receives order events on. Secret is decrypted.
+type WebhookEndpoint struct {
+	ShopID    uuid.UUID `json:"shop_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID                 uuid.UUID             `json:"id"`
+	ShopID             uuid.UUID             `json:"shop_id"`
+	OrderID            uuid.UUID             `json:"order_id"`
+	Event              WebhookEvent          `json:"event"`
+	URL                string                `json:"url"`
+	Payload            []byte                `json:"payload"`
+	Status             WebhookDeliveryStatus `json:"status"`
+	Attempts           int                   `json:"attempts"`
+	NextAttemptAt      time.Time             `json:"next_attempt_at"`
+	LastResponseStatus int                   `json:"last_response_status"`
+	LastError          string                `json:"last_error"`
+	CreatedAt          time.Time             `json:"created_at"`
+	DeliveredAt        time.Time             `json:"delivered_at"`
+}
+
+// QueuedWebhook is an inbound GitHub or Stripe webhook that was accepted but deferred for the
+// queue worker to process.
+type QueuedWebhook struct {
+	ID         uuid.UUID
+	Provider   string
+	DeliveryID string
+	EventType  string
+	Payload    []byte
+	Attempts   int
+	ReceivedAt time.Time
+}
+
+type ReceivedWebhookStatus string
+
+const (
+	ReceivedWebhookReceived  ReceivedWebhookStatus = "received"
+	ReceivedWebhookQueued    ReceivedWebhookStatus = "queued"
+	ReceivedWebhookProcessed ReceivedWebhookStatus = "processed"
+	ReceivedWebhookFailed    ReceivedWebhookStatus = "failed"
+)
+
+// ReceivedWebhook is an inbound GitHub or Stripe webhook kept so operators can inspect and
+// replay it.
+type ReceivedWebhook struct {
+	ID             uuid.UUID
+	Provider       string
+	DeliveryID     string
+	EventType      string
+	Payload        []byte
+	Status         ReceivedWebhookStatus
+	LastError      string
+	ReplayCount    int
+	ReceivedAt     time.Time
+	ProcessedAt    time.Time
+	LastReplayedAt time.Time
+}
+
+// WebhookCheckpoint is the last delivery an endpoint processed successfully. After an outage
+// or deploy, anything GitHub or Stripe sent since ProcessedAt may need redelivering.
+type WebhookCheckpoint struct {
+	Provider    string
+	Endpoint    string
+	DeliveryID  string
+	EventType   string
+	ProcessedAt time.Time
+}
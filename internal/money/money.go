@@ -0,0 +1,132 @@
+// Package money does arithmetic on amounts held as int64 cents. Every operation that can
+// overflow reports it instead of wrapping, and rounding always goes half away from zero.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrOverflow      = errors.New("amount out of range")
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// MaxCents is the largest amount Stripe accepts for a single charge, $999,999.99. Amounts above it
+// are rejected before they reach a checkout session.
+const MaxCents int64 = 99_999_999
+
+// Add sums amounts, failing if the total leaves the int64 range.
+func Add(amounts ...int64) (int64, error) {
+	var total int64
+	for _, amount := range amounts {
+		if (amount > 0 && total > math.MaxInt64-amount) || (amount < 0 && total < math.MinInt64-amount) {
+			return 0, fmt.Errorf("%w: adding %d to %d", ErrOverflow, amount, total)
+		}
+		total += amount
+	}
+	return total, nil
+}
+
+// Multiply returns cents times quantity, failing if the product leaves the int64 range.
+func Multiply(cents, quantity int64) (int64, error) {
+	if cents == 0 || quantity == 0 {
+		return 0, nil
+	}
+	product := cents * quantity
+	if product/quantity != cents || (cents == -1 && quantity == math.MinInt64) || (quantity == -1 && cents == math.MinInt64) {
+		return 0, fmt.Errorf("%w: %d × %d", ErrOverflow, cents, quantity)
+	}
+	return product, nil
+}
+
+// Divide splits cents into parts equal shares, rounded half away from zero.
+func Divide(cents, parts int64) (int64, error) {
+	if parts <= 0 {
+		return 0, fmt.Errorf("%w: cannot divide into %d parts", ErrInvalidAmount, parts)
+	}
+	quotient, remainder := cents/parts, cents%parts
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	if remainder >= parts-remainder {
+		if cents < 0 {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return quotient, nil
+}
+
+// CheckChargeable rejects amounts Stripe cannot charge: negative ones and anything over MaxCents.
+func CheckChargeable(cents int64) error {
+	if cents < 0 || cents > MaxCents {
+		return fmt.Errorf("%w: %s is outside 0–%s", ErrOverflow, Format(cents), Format(MaxCents))
+	}
+	return nil
+}
+
+// Format renders cents as dollars, such as "$1250.50" or "-$0.05".
+func Format(cents int64) string {
+	sign := ""
+	magnitude := uint64(cents)
+	if cents < 0 {
+		sign = "-"
+		magnitude = uint64(-(cents + 1)) + 1
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, magnitude/100, magnitude%100)
+}
+
+// Parse converts a dollar amount such as "125", "125.5", or "125.505" to cents. Digits past the
+// cent are rounded half away from zero.
+func Parse(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	dollars, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrOverflow, value)
+	}
+	fraction += "000"
+	cents, _ := strconv.ParseInt(fraction[:2], 10, 64)
+	if fraction[2] >= '5' {
+		cents++
+	}
+
+	total, err := Multiply(dollars, 100)
+	if err != nil {
+		return 0, err
+	}
+	if total, err = Add(total, cents); err != nil {
+		return 0, err
+	}
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
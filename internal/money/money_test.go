@@ -0,0 +1,196 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+func TestAdd_MatchesBigIntArithmetic(t *testing.T) {
+	t.Parallel()
+
+	property := func(a, b, c int64) bool {
+		got, err := Add(a, b, c)
+		exact := new(big.Int).Add(big.NewInt(a), big.NewInt(b))
+		if !exact.IsInt64() {
+			return errors.Is(err, ErrOverflow)
+		}
+		exact.Add(exact, big.NewInt(c))
+		if !exact.IsInt64() {
+			return errors.Is(err, ErrOverflow)
+		}
+		return err == nil && got == exact.Int64()
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiply_MatchesBigIntArithmetic(t *testing.T) {
+	t.Parallel()
+
+	property := func(cents, quantity int64) bool {
+		got, err := Multiply(cents, quantity)
+		exact := new(big.Int).Mul(big.NewInt(cents), big.NewInt(quantity))
+		if !exact.IsInt64() {
+			return errors.Is(err, ErrOverflow)
+		}
+		return err == nil && got == exact.Int64()
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+	// Small quantities rarely overflow with random inputs, so check the edges directly.
+	for _, tt := range [][2]int64{{math.MaxInt64, 2}, {math.MinInt64, -1}, {-1, math.MinInt64}, {math.MaxInt64 / 100, 101}} {
+		if _, err := Multiply(tt[0], tt[1]); !errors.Is(err, ErrOverflow) {
+			t.Fatalf("expected overflow for %d × %d, got %v", tt[0], tt[1], err)
+		}
+	}
+}
+
+func TestDivide_RoundsHalfAwayFromZero(t *testing.T) {
+	t.Parallel()
+
+	property := func(cents int64, parts uint16) bool {
+		if parts == 0 {
+			parts = 1
+		}
+		got, err := Divide(cents, int64(parts))
+		if err != nil {
+			return false
+		}
+		// |cents - got*parts| * 2 <= parts, with ties resolved away from zero.
+		diff := new(big.Int).Sub(big.NewInt(cents), new(big.Int).Mul(big.NewInt(got), big.NewInt(int64(parts))))
+		doubled := new(big.Int).Mul(new(big.Int).Abs(diff), big.NewInt(2))
+		switch doubled.Cmp(big.NewInt(int64(parts))) {
+		case 1:
+			return false
+		case 0:
+			return (cents >= 0 && diff.Sign() <= 0) || (cents < 0 && diff.Sign() >= 0)
+		}
+		return true
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		cents, parts, want int64
+	}{
+		{cents: 1000, parts: 3, want: 333},
+		{cents: 1001, parts: 2, want: 501},
+		{cents: -1001, parts: 2, want: -501},
+		{cents: 5, parts: 10, want: 1},
+	}
+	for _, tt := range tests {
+		if got, _ := Divide(tt.cents, tt.parts); got != tt.want {
+			t.Fatalf("Divide(%d, %d) = %d, want %d", tt.cents, tt.parts, got, tt.want)
+		}
+	}
+	if _, err := Divide(100, 0); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount dividing by zero, got %v", err)
+	}
+}
+
+func TestFormatParse_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	property := func(cents int64) bool {
+		if cents == math.MinInt64 {
+			cents++
+		}
+		got, err := Parse(trimDollar(Format(cents)))
+		return err == nil && got == cents
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func trimDollar(value string) string {
+	if value[0] == '-' {
+		return "-" + value[2:]
+	}
+	return value[1:]
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cents int64
+		want  string
+	}{
+		{cents: 0, want: "$0.00"},
+		{cents: 5, want: "$0.05"},
+		{cents: 125050, want: "$1250.50"},
+		{cents: -5, want: "-$0.05"},
+		{cents: math.MinInt64, want: "-$92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.cents); got != tt.want {
+			t.Fatalf("Format(%d) = %q, want %q", tt.cents, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr error
+	}{
+		{name: "whole dollars", value: "125", want: 12500},
+		{name: "one decimal", value: "125.5", want: 12550},
+		{name: "two decimals", value: "0.99", want: 99},
+		{name: "leading dot", value: ".5", want: 50},
+		{name: "rounds half up", value: "1.005", want: 101},
+		{name: "rounds down", value: "1.004", want: 100},
+		{name: "negative", value: "-2.50", want: -250},
+		{name: "empty", value: "", wantErr: ErrInvalidAmount},
+		{name: "letters", value: "12a", wantErr: ErrInvalidAmount},
+		{name: "two dots", value: "1.2.3", wantErr: ErrInvalidAmount},
+		{name: "too large", value: "99999999999999999999", wantErr: ErrOverflow},
+		{name: "overflows in cents", value: "92233720368547758.08", wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckChargeable(t *testing.T) {
+	t.Parallel()
+
+	for _, cents := range []int64{0, 1, MaxCents} {
+		if err := CheckChargeable(cents); err != nil {
+			t.Fatalf("expected %d to be chargeable, got %v", cents, err)
+		}
+	}
+	for _, cents := range []int64{-1, MaxCents + 1} {
+		if err := CheckChargeable(cents); !errors.Is(err, ErrOverflow) {
+			t.Fatalf("expected %d to be rejected, got %v", cents, err)
+		}
+	}
+}
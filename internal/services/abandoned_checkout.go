@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// abandonedCheckoutWindow is how far back the dashboard looks for expired checkouts.
+const abandonedCheckoutWindow = 30 * 24 * time.Hour
+
+// AbandonedCheckouts is the shop's checkouts that expired unpaid in the last 30 days, for the
+// dashboard panel. TopSKUs lists the products abandoned most; Orders holds the most recent
+// abandoned orders, which can be sent a new checkout link.
+type AbandonedCheckouts struct {
+	Since   time.Time
+	Summary db.AbandonedCheckoutSummary
+	TopSKUs []db.AbandonedSKU
+	Orders  []*db.Order
+}
+
+// AbandonedCheckouts returns the shop's recent abandoned checkouts, or nil when none expired in
+// the window.
+func (s *AdminService) AbandonedCheckouts(ctx context.Context, shopID uuid.UUID, now time.Time) (*AbandonedCheckouts, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	since := now.Add(-abandonedCheckoutWindow)
+	summary, err := s.orderStore.SummarizeAbandonedCheckouts(ctx, shopID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize abandoned checkouts: %w", err)
+	}
+	if summary.Abandoned == 0 && summary.Recovered == 0 {
+		return nil, nil
+	}
+	skus, err := s.orderStore.ListAbandonedSKUs(ctx, shopID, since, 5)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list abandoned products: %w", err)
+	}
+	orders, err := s.orderStore.ListAbandonedOrders(ctx, shopID, since, 10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list abandoned orders: %w", err)
+	}
+	return &AbandonedCheckouts{Since: since, Summary: summary, TopSKUs: skus, Orders: orders}, nil
+}
+
+// ResendCheckoutLink gives an order whose checkout expired a new checkout, priced from the
+// current gitshop.yaml, and posts the link on the order issue.
+func (s *AdminService) ResendCheckoutLink(ctx context.Context, input OrderActionInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.resend_checkout_link",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("ResendCheckoutLink"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("checkout.recovery.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return err
+	}
+	if order.Status != db.StatusExpired {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: only orders whose checkout expired can get a new link", ErrAdminOrderStatusConflict)
+	}
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	repoFullName := shop.GitHubRepoFullName
+	config, err := s.fetchValidatedConfig(ctx, client, repoFullName)
+	if err != nil {
+		recordFailed("config_invalid")
+		return UserError{Message: err.Error()}
+	}
+	product := findProduct(config, order.SKU)
+	switch {
+	case product == nil:
+		recordFailed("sku_missing")
+		return UserError{Message: fmt.Sprintf("SKU %s is no longer in gitshop.yaml.", order.SKU)}
+	case product.IsInquiry():
+		recordFailed("quoted_order")
+		return UserError{Message: "This order was quoted. Send the buyer a new quote instead."}
+	case product.VerifyIdentity && order.VerificationStatus != db.VerificationVerified:
+		recordFailed("verification_incomplete")
+		return UserError{Message: "The buyer has not finished identity verification."}
+	}
+
+	checkout, err := createOrderCheckout(ctx, s.payments, shop, order, checkoutParamsForOrder(shop, order, config, product, repoFullName), "recovery")
+	if errors.Is(err, ErrSubscriptionsDisabled) {
+		recordFailed("subscriptions_disabled")
+		return UserError{Message: "Subscriptions are turned off for this shop."}
+	}
+	if err != nil {
+		recordFailed("checkout_create_failed")
+		return fmt.Errorf("failed to create checkout: %w", err)
+	}
+	if err := markPendingCheckout(ctx, s.orderStore, order, checkout); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("mark_pending_failed")
+		return fmt.Errorf("failed to update order with checkout: %w", err)
+	}
+
+	if err := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, checkout.comment(configLocalizer(config), order, config.Shop.Checkout.ExpiresIn(), shopCheckoutLinkComment(config))); err != nil {
+		recordFailed("checkout_comment_failed")
+		return fmt.Errorf("failed to comment checkout link: %w", err)
+	}
+	labels := shopLabels(config)
+	if err := client.RemoveLabel(ctx, repoFullName, order.GitHubIssueNumber, labels.Name(catalog.LabelExpired)); err != nil {
+		logger.Warn("failed to remove expired label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	if err := client.AddLabels(ctx, repoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelPendingPayment)}); err != nil {
+		logger.Warn("failed to add pending-payment label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	meter.Count("checkout.recovery.sent", 1)
+	meter.Count("checkout.session.created", 1, sentry.WithAttributes(
+		attribute.String("source", "recovery"),
+	))
+	return nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/address"
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// WithAddressValidator returns a copy of the service that checks the shipping address of each
+// paid order. A nil validator turns the check off.
+func (s *StripeService) WithAddressValidator(validator address.Validator) *StripeService {
+	service := *s
+	service.addressValidator = validator
+	return &service
+}
+
+// checkShippingAddress holds a paid order whose shipping address the validator finds
+// undeliverable and asks the buyer on the issue to confirm it. The paid issue updates are queued
+// first, so the outbox applies these label changes after them. A validator that cannot be
+// reached leaves the order paid rather than blocking fulfilment.
+func (s *StripeService) checkShippingAddress(ctx context.Context, shop *db.Shop, order *db.Order, config *catalog.GitShopConfig, repoFullName string, issueNumber int, shippingAddress map[string]any) {
+	if s.addressValidator == nil {
+		return
+	}
+	shipTo := address.FromMap(shippingAddress)
+	if shipTo.IsEmpty() {
+		return
+	}
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("address.validation.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	result, err := s.addressValidator.Validate(ctx, shipTo)
+	if err != nil {
+		recordFailed("validator_error")
+		logger.Warn("failed to validate shipping address", "error", err, "order_id", order.ID)
+		return
+	}
+	meter.Count("address.validation.checked", 1, sentry.WithAttributes(
+		attribute.Bool("deliverable", result.Deliverable),
+	))
+	if result.Deliverable {
+		return
+	}
+
+	if err := s.orderStore.MarkAddressIssue(ctx, order.ID, result.Reason); err != nil {
+		recordFailed("mark_address_issue_failed")
+		logger.Error("failed to hold order for address issue", "error", err, "order_id", order.ID)
+		return
+	}
+	order.Status = db.StatusAddressIssue
+	order.AddressIssue = result.Reason
+
+	comment := addressIssueComment(configLocalizer(config), order.OrderNumber)
+	if err := s.outbox.Enqueue(ctx, addressIssueEffects(shop, order, shopLabels(config), repoFullName, issueNumber, comment)); err != nil {
+		recordFailed("side_effects_enqueue_failed")
+		logger.Error("failed to queue address issue updates", "error", err, "order_id", order.ID)
+	}
+}
+
+// addressIssueEffects swap the paid label for the address issue label and ask the buyer to
+// confirm their address. Their keys are fixed per order, like paidOrderEffects.
+func addressIssueEffects(shop *db.Shop, order *db.Order, labels catalog.LabelsConfig, repoFullName string, issueNumber int, comment string) []*db.GitHubEffect {
+	effect := func(kind db.GitHubEffectKind, value string) *db.GitHubEffect {
+		return &db.GitHubEffect{
+			IdempotencyKey: fmt.Sprintf("order:%s:address_issue:%s", order.ID, kind),
+			ShopID:         shop.ID,
+			OrderID:        order.ID,
+			InstallationID: shop.GitHubInstallationID,
+			RepoFullName:   repoFullName,
+			IssueNumber:    issueNumber,
+			Kind:           kind,
+			Value:          value,
+		}
+	}
+	posted := effect(db.GitHubEffectComment, comment)
+	posted.EventKind = db.OrderEventCommentPosted
+	posted.EventDetail = "address_issue"
+	return []*db.GitHubEffect{
+		posted,
+		effect(db.GitHubEffectRemoveLabel, labels.Name(catalog.LabelPaid)),
+		effect(db.GitHubEffectAddLabel, labels.Name(catalog.LabelAddressIssue)),
+	}
+}
+
+type UpdateShippingAddressInput struct {
+	ShopID  uuid.UUID
+	OrderID uuid.UUID
+	Address address.Address
+}
+
+// UpdateShippingAddress replaces the shipping address of a paid order. The seller's correction
+// is trusted as is, so an order held for an address issue is released and the buyer is told on
+// the issue that the address is confirmed.
+func (s *AdminService) UpdateShippingAddress(ctx context.Context, input UpdateShippingAddressInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.update_shipping_address",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("UpdateShippingAddress"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("fulfillment.address_update.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	if missing := missingAddressFields(input.Address); len(missing) > 0 {
+		recordFailed("invalid_input")
+		return UserError{Message: "Enter the " + strings.Join(missing, ", ") + " of the shipping address."}
+	}
+
+	order, err := s.getShopOrder(ctx, OrderActionInput{ShopID: input.ShopID, OrderID: input.OrderID})
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return err
+	}
+	if order.Status != db.StatusPaid && order.Status != db.StatusAddressIssue {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: only paid orders that have not shipped can change address", ErrAdminOrderStatusConflict)
+	}
+
+	if err := s.orderStore.UpdateShippingAddress(ctx, order.ID, input.Address.Map()); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("update_address_failed")
+		return fmt.Errorf("failed to update shipping address: %w", err)
+	}
+	meter.Count("fulfillment.address_update.processed", 1, sentry.WithAttributes(
+		attribute.Bool("address_issue", order.Status == db.StatusAddressIssue),
+	))
+	if order.Status != db.StatusAddressIssue {
+		return nil
+	}
+
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	repoFullName := shop.GitHubRepoFullName
+	config := shopConfig(ctx, client, repoFullName)
+	labels := shopLabels(config)
+	if err := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, addressConfirmedComment(configLocalizer(config), order.OrderNumber)); err != nil {
+		logger.Error("failed to create address confirmed comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	if err := client.RemoveLabel(ctx, repoFullName, order.GitHubIssueNumber, labels.Name(catalog.LabelAddressIssue)); err != nil {
+		logger.Warn("failed to remove address-issue label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	if err := client.AddLabels(ctx, repoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelPaid)}); err != nil {
+		logger.Warn("failed to add paid label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	return nil
+}
+
+// missingAddressFields names the parts of an address a carrier needs that are blank.
+func missingAddressFields(shipTo address.Address) []string {
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"street address", shipTo.Line1},
+		{"city", shipTo.City},
+		{"postal code", shipTo.PostalCode},
+		{"country", shipTo.Country},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/address"
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+func TestAddressIssueEffects(t *testing.T) {
+	t.Parallel()
+
+	shop := &db.Shop{ID: uuid.New(), GitHubInstallationID: 42}
+	order := &db.Order{ID: uuid.New(), OrderNumber: 1001}
+	effects := addressIssueEffects(shop, order, catalog.LabelsConfig{}, "octo/shop", 7, addressIssueComment(i18n.Localizer{}, order.OrderNumber))
+
+	if len(effects) != 3 {
+		t.Fatalf("len(effects) = %d, want 3", len(effects))
+	}
+	want := []struct {
+		kind  db.GitHubEffectKind
+		value string
+	}{
+		{db.GitHubEffectComment, ""},
+		{db.GitHubEffectRemoveLabel, "gitshop:status:paid"},
+		{db.GitHubEffectAddLabel, "gitshop:status:address-issue"},
+	}
+	paidKeys := map[string]bool{}
+	for _, effect := range paidOrderEffects(shop, order, catalog.LabelsConfig{}, "octo/shop", 7, "") {
+		paidKeys[effect.IdempotencyKey] = true
+	}
+	for i, effect := range effects {
+		if effect.Kind != want[i].kind || (want[i].value != "" && effect.Value != want[i].value) {
+			t.Fatalf("effects[%d] = %s %q, want %s %q", i, effect.Kind, effect.Value, want[i].kind, want[i].value)
+		}
+		if paidKeys[effect.IdempotencyKey] {
+			t.Fatalf("effects[%d] key %q collides with a paid order effect", i, effect.IdempotencyKey)
+		}
+	}
+	if !strings.Contains(effects[0].Value, "`#1001`") {
+		t.Fatalf("comment = %q, want the order number", effects[0].Value)
+	}
+	if effects[0].EventKind != db.OrderEventCommentPosted || effects[0].EventDetail != "address_issue" {
+		t.Fatalf("comment event = %s %q, want %s %q", effects[0].EventKind, effects[0].EventDetail, db.OrderEventCommentPosted, "address_issue")
+	}
+	for i, effect := range effects[1:] {
+		if effect.EventKind != "" {
+			t.Fatalf("effects[%d] event = %s, want none for a label change", i+1, effect.EventKind)
+		}
+	}
+}
+
+func TestMissingAddressFields(t *testing.T) {
+	t.Parallel()
+
+	complete := address.Address{Line1: "1 Main St", City: "Springfield", PostalCode: "62701", Country: "US"}
+	if missing := missingAddressFields(complete); len(missing) != 0 {
+		t.Fatalf("missingAddressFields(complete) = %v, want none", missing)
+	}
+	missing := missingAddressFields(address.Address{City: "Springfield", Country: "US"})
+	if got := strings.Join(missing, ", "); got != "street address, postal code" {
+		t.Fatalf("missingAddressFields() = %q", got)
+	}
+}
@@ -51,7 +51,9 @@ type AdminService struct {
 	orderStore     *db.OrderStore
 	githubClient   *githubapp.Client
 	stripePlatform *stripe.PlatformClient
+	payments       *PaymentProviders
 	orderEmailer   OrderEmailSender
+	webhooks       OrderWebhookPublisher
 	parser         configParser
 	validator      configValidator
 	newSyncer      func(client *githubapp.Client) *catalog.TemplateSyncer
@@ -64,9 +66,11 @@ func NewAdminService(
 	orderStore *db.OrderStore,
 	githubClient *githubapp.Client,
 	stripePlatform *stripe.PlatformClient,
+	payments *PaymentProviders,
 	parser configParser,
 	validator configValidator,
 	orderEmailer OrderEmailSender,
+	webhooks OrderWebhookPublisher,
 	newSyncer func(client *githubapp.Client) *catalog.TemplateSyncer,
 	newProvider func(config email.Config) (email.Provider, error),
 	logger *slog.Logger,
@@ -77,13 +81,18 @@ func NewAdminService(
 	if orderEmailer == nil {
 		orderEmailer = noopOrderEmailSender{}
 	}
+	if webhooks == nil {
+		webhooks = noopOrderWebhookPublisher{}
+	}
 
 	return &AdminService{
 		shopStore:      shopStore,
 		orderStore:     orderStore,
 		githubClient:   githubClient,
 		stripePlatform: stripePlatform,
+		payments:       payments,
 		orderEmailer:   orderEmailer,
+		webhooks:       webhooks,
 		parser:         parser,
 		validator:      validator,
 		newSyncer:      newSyncer,
@@ -96,17 +105,22 @@ func (s *AdminService) loggerFromContext(ctx context.Context) *slog.Logger {
 	return logging.FromContext(ctx, s.logger)
 }
 
-func (s *AdminService) UpdateEmailSettings(ctx context.Context, shopID uuid.UUID, provider, apiKey, from, domain string) error {
+// UpdateEmailSettings validates and saves the shop's email provider. It reports whether the
+// provider confirmed the sending domain; until it does the shop's email stays unverified and the
+// settings page shows the DNS records to add. A blank webhook secret keeps the one saved for the
+// same provider. Saving clears any from address bounce flag, since the seller has had the chance
+// to fix it.
+func (s *AdminService) UpdateEmailSettings(ctx context.Context, shopID uuid.UUID, provider, apiKey, from, domain, webhookSecret string) (bool, error) {
 	if provider != "postmark" && provider != "mailgun" && provider != "resend" {
-		return UserError{Message: "Provider must be postmark, mailgun, or resend"}
+		return false, UserError{Message: "Provider must be postmark, mailgun, or resend"}
 	}
 
 	if apiKey == "" || from == "" {
-		return UserError{Message: "API key and from email are required"}
+		return false, UserError{Message: "API key and from email are required"}
 	}
 
 	if provider == "mailgun" && domain == "" {
-		return UserError{Message: "Domain is required for mailgun"}
+		return false, UserError{Message: "Domain is required for mailgun"}
 	}
 
 	emailConfig := map[string]any{
@@ -116,22 +130,102 @@ func (s *AdminService) UpdateEmailSettings(ctx context.Context, shopID uuid.UUID
 	if provider == "mailgun" {
 		emailConfig["domain"] = domain
 	}
+	webhookSecret = strings.TrimSpace(webhookSecret)
+	if webhookSecret == "" {
+		current, err := s.shopStore.GetByID(ctx, shopID)
+		if err != nil {
+			return false, fmt.Errorf("failed to load shop: %w", err)
+		}
+		if saved, _ := current.EmailConfig["webhook_secret"].(string); saved != "" && current.EmailProvider == provider {
+			webhookSecret = saved
+		}
+	}
+	if webhookSecret != "" {
+		emailConfig["webhook_secret"] = webhookSecret
+	}
 
-	_, err := s.newProvider(email.Config{
+	emailProvider, err := s.newProvider(email.Config{
 		Provider: provider,
 		APIKey:   apiKey,
 		From:     from,
 		Domain:   domain,
 	})
 	if err != nil {
-		return UserError{Message: fmt.Sprintf("Invalid email configuration: %s", err.Error())}
+		return false, UserError{Message: fmt.Sprintf("Invalid email configuration: %s", err.Error())}
+	}
+	verified, err := sendingDomainVerified(ctx, emailProvider)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to check sending domain", "error", err, "shop_id", shopID, "provider", provider)
 	}
 
-	if err := s.shopStore.UpdateEmailConfig(ctx, shopID, provider, emailConfig, true); err != nil {
-		return fmt.Errorf("failed to update email config: %w", err)
+	if err := s.shopStore.UpdateEmailConfig(ctx, shopID, provider, emailConfig, verified); err != nil {
+		return false, fmt.Errorf("failed to update email config: %w", err)
+	}
+	if err := s.shopStore.ClearEmailFromBounce(ctx, shopID); err != nil {
+		s.loggerFromContext(ctx).Warn("failed to clear from address bounce", "error", err, "shop_id", shopID)
 	}
 
-	return nil
+	return verified, nil
+}
+
+// GetEmailFromBounce returns the shop's from address bounce, or nil when mail to it is fine.
+func (s *AdminService) GetEmailFromBounce(ctx context.Context, shopID uuid.UUID) (*db.EmailFromBounce, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, fmt.Errorf("%w: shop store unavailable", ErrAdminServiceUnavailable)
+	}
+	return s.shopStore.GetEmailFromBounce(ctx, shopID)
+}
+
+// SendTestEmail sends a sample order confirmation through the shop's email provider so owners
+// can check delivery before going live. It returns the recipient address.
+func (s *AdminService) SendTestEmail(ctx context.Context, shop *db.Shop) (string, error) {
+	if shop == nil {
+		return "", fmt.Errorf("shop is required")
+	}
+	if shop.EmailProvider == "" {
+		return "", UserError{Message: "Configure an email provider before sending a test email"}
+	}
+
+	recipient := strings.TrimSpace(shop.OwnerEmail)
+	if recipient == "" {
+		recipient = strings.TrimSpace(shop.EmailFrom)
+	}
+	if recipient == "" {
+		return "", UserError{Message: "No owner email is available to send the test to"}
+	}
+
+	provider, err := email.NewProviderFromShop(shop)
+	if err != nil {
+		return "", UserError{Message: fmt.Sprintf("Invalid email configuration: %s", err.Error())}
+	}
+
+	if err := email.SendOrderConfirmation(ctx, provider, sampleOrderInfo(shop, recipient)); err != nil {
+		s.loggerFromContext(ctx).Warn("test email failed", "error", err, "shop_id", shop.ID, "provider", shop.EmailProvider)
+		return "", UserError{Message: fmt.Sprintf("Test email failed: %s", err.Error())}
+	}
+
+	return recipient, nil
+}
+
+func sampleOrderInfo(shop *db.Shop, recipient string) *email.OrderInfo {
+	order := &db.Order{
+		OrderNumber:    1001,
+		GitHubUsername: "octocat",
+		SKU:            "SAMPLE_PRODUCT",
+		Options:        map[string]any{"quantity": 2, "size": "M"},
+		SubtotalCents:  5000,
+		ShippingCents:  500,
+		TotalCents:     5500,
+	}
+	if shop != nil && shop.GitHubRepoFullName != "" {
+		order.GitHubIssueURL = fmt.Sprintf("https://github.com/%s/issues", shop.GitHubRepoFullName)
+	}
+
+	return BuildOrderInfo(shop, order, OrderInfoOverrides{
+		CustomerName:    "Sample Customer",
+		CustomerEmail:   recipient,
+		ShippingAddress: "123 Example Street\nSpringfield, IL 62701\nUS",
+	})
 }
 
 func (s *AdminService) EnsureRepoLabels(ctx context.Context, shop *db.Shop) error {
@@ -143,7 +237,8 @@ func (s *AdminService) EnsureRepoLabels(ctx context.Context, shop *db.Shop) erro
 	}
 
 	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
-	if err := client.EnsureLabels(ctx, shop.GitHubRepoFullName, RequiredRepoLabels()); err != nil {
+	labels := shopLabels(shopConfig(ctx, client, shop.GitHubRepoFullName))
+	if err := client.EnsureLabels(ctx, shop.GitHubRepoFullName, RequiredRepoLabels(labels)); err != nil {
 		return err
 	}
 
@@ -164,14 +259,42 @@ func (s *AdminService) EnsureGitShopYAML(ctx context.Context, shop *db.Shop) (*g
 	}
 
 	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
-	result, err := client.EnsureGitShopYAMLForRepo(ctx, owner, repo, shop.GitHubRepoFullName)
+	access := contentAccess(ctx, client, s.logger)
+	if notice := yamlAccessNotice(access); notice != "" {
+		return nil, UserError{Message: notice}
+	}
+	result, err := client.EnsureGitShopYAMLForRepo(ctx, owner, repo, shop.GitHubRepoFullName, access)
 	if err != nil {
-		return nil, err
+		return nil, contentAccessUserError(err, "gitshop.yaml")
 	}
 
 	return result, nil
 }
 
+// FirstOrderAwaitingShipment returns the shop's first paid order while it still needs shipping,
+// so the dashboard can walk a new seller through fulfilling it. It returns nil otherwise.
+func (s *AdminService) FirstOrderAwaitingShipment(ctx context.Context, shopID uuid.UUID) (*db.Order, error) {
+	if s == nil || s.shopStore == nil || s.orderStore == nil {
+		return nil, fmt.Errorf("%w: stores unavailable", ErrAdminServiceUnavailable)
+	}
+
+	orderID, err := s.shopStore.GetFirstPaidOrderID(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load first paid order: %w", err)
+	}
+	if orderID == uuid.Nil {
+		return nil, nil
+	}
+	order, err := s.orderStore.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load first paid order: %w", err)
+	}
+	if order == nil || order.ShopID != shopID || order.Status != db.StatusPaid {
+		return nil, nil
+	}
+	return order, nil
+}
+
 func (s *AdminService) GetRecentOrders(ctx context.Context, shopID uuid.UUID, limit int) ([]*db.Order, error) {
 	if s == nil || s.orderStore == nil {
 		return nil, fmt.Errorf("%w: order store unavailable", ErrAdminServiceUnavailable)
@@ -191,6 +314,9 @@ func (s *AdminService) GetRecentOrders(ctx context.Context, shopID uuid.UUID, li
 	return orders, nil
 }
 
+// EnsureOrderTemplate creates order templates for active products that no existing template
+// lists, one per option schema. It returns the pull request when one was needed, otherwise the
+// last commit.
 func (s *AdminService) EnsureOrderTemplate(ctx context.Context, shop *db.Shop) (*githubapp.FileCreationResult, error) {
 	if shop == nil {
 		return nil, fmt.Errorf("shop is required")
@@ -202,21 +328,40 @@ func (s *AdminService) EnsureOrderTemplate(ctx context.Context, shop *db.Shop) (
 		return nil, err
 	}
 
-	syncer := s.newSyncer(s.githubClient)
-	templateContent, err := syncer.BuildTemplateContent(config)
+	owner, repo, err := splitRepoFullName(shop.GitHubRepoFullName)
 	if err != nil {
 		return nil, err
 	}
 
-	owner, repo, err := splitRepoFullName(shop.GitHubRepoFullName)
+	access := contentAccess(ctx, client, s.logger)
+	existing, err := readOrderTemplates(ctx, client, shop.GitHubRepoFullName, access)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := client.EnsureOrderTemplate(ctx, owner, repo, templateContent)
+	syncer := s.newSyncer(s.githubClient)
+	files, err := syncer.BuildTemplateFiles(config, existing.coveredSKUs(), existing.paths)
+	if err != nil {
+		return nil, err
+	}
+	files, err = placeTemplateFiles(files, access, existing.paths)
 	if err != nil {
 		return nil, err
 	}
+	if len(files) == 0 {
+		return &githubapp.FileCreationResult{Method: "exists"}, nil
+	}
+
+	var result *githubapp.FileCreationResult
+	for _, file := range files {
+		fileResult, err := client.EnsureOrderTemplate(ctx, owner, repo, file.Path, file.Content, access)
+		if err != nil {
+			return nil, contentAccessUserError(err, file.Path)
+		}
+		if result == nil || result.Method != "pr" {
+			result = fileResult
+		}
+	}
 
 	return result, nil
 }
@@ -239,66 +384,118 @@ func (s *AdminService) SyncOrderTemplates(ctx context.Context, shop *db.Shop) (s
 		return "", err
 	}
 
-	templates, listErr := client.ListDirectory(ctx, shop.GitHubRepoFullName, ".github/ISSUE_TEMPLATE")
-	if listErr != nil {
-		return "", listErr
+	access := contentAccess(ctx, client, s.logger)
+	existing, err := readOrderTemplates(ctx, client, shop.GitHubRepoFullName, access)
+	if err != nil {
+		return "", err
 	}
 
-	markerFiles := []githubapp.RepoFile{}
-	for _, file := range filterTemplateFiles(templates) {
-		content, readErr := client.GetFile(ctx, shop.GitHubRepoFullName, file.Path, "")
-		if readErr != nil {
-			continue
-		}
-		if hasOrderTemplateMarker(string(content)) {
-			markerFiles = append(markerFiles, file)
+	var prURL string
+	recordResult := func(result *githubapp.FileCreationResult) {
+		if result != nil && result.Method == "pr" && result.URL != "" && prURL == "" {
+			prURL = result.URL
 		}
 	}
 
-	if len(markerFiles) == 0 {
-		markerFiles = append(markerFiles, githubapp.RepoFile{
-			Name: "order.yaml",
-			Path: ".github/ISSUE_TEMPLATE/order.yaml",
-		})
-	}
+	for _, template := range existing.templates {
+		simple, reason, simpleErr := syncer.IsSimpleSync(template.content, config)
+		if simpleErr != nil {
+			return "", simpleErr
+		}
+		if !simple {
+			return "", UserError{Message: reason}
+		}
 
-	var prURL string
-	for _, file := range markerFiles {
-		var syncedContent string
-		currentContent, err := client.GetFile(ctx, shop.GitHubRepoFullName, file.Path, "")
+		syncedContent, err := syncer.SyncTemplateContent(template.content, config)
 		if err != nil {
-			syncedContent, err = syncer.BuildTemplateContent(config)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			simple, reason, simpleErr := syncer.IsSimpleSync(string(currentContent), config)
-			if simpleErr != nil {
-				return "", simpleErr
-			}
-			if !simple {
-				return "", UserError{Message: reason}
-			}
-
-			syncedContent, err = syncer.SyncTemplateContent(string(currentContent), config)
-			if err != nil {
-				return "", err
-			}
+			return "", err
 		}
 
-		branchSuffix := strings.ReplaceAll(strings.TrimSuffix(file.Name, filepath.Ext(file.Name)), "/", "-")
-		result, err := client.CreateOrUpdateFileWithPR(ctx, owner, repo, file.Path, syncedContent, "Sync GitShop order template", "Sync GitShop order template", "This PR synchronizes the GitShop order issue template with your current `gitshop.yaml`.", "gitshop/sync-order-template-"+branchSuffix)
+		branchSuffix := strings.ReplaceAll(strings.TrimSuffix(template.file.Name, filepath.Ext(template.file.Name)), "/", "-")
+		prBody := s.templateChangePRBody(ctx, "This PR synchronizes the GitShop order issue template with your current `gitshop.yaml`.", template.content, syncedContent)
+		result, err := client.CreateOrUpdateFileWithPR(ctx, owner, repo, template.file.Path, syncedContent, "Sync GitShop order template", "Sync GitShop order template", prBody, "gitshop/sync-order-template-"+branchSuffix, access)
 		if err != nil {
-			return "", err
+			return "", contentAccessUserError(err, template.file.Path)
 		}
-		if result != nil && result.Method == "pr" && result.URL != "" && prURL == "" {
-			prURL = result.URL
+		recordResult(result)
+	}
+
+	files, err := syncer.BuildTemplateFiles(config, existing.coveredSKUs(), existing.paths)
+	if err != nil {
+		return "", err
+	}
+	files, err = placeTemplateFiles(files, access, existing.paths)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		name := filepath.Base(file.Path)
+		branchSuffix := strings.TrimSuffix(name, filepath.Ext(name))
+		prBody := s.templateChangePRBody(ctx, "This PR adds a GitShop order issue template for products in `gitshop.yaml` that no existing template lists.", "", file.Content)
+		result, err := client.CreateOrUpdateFileWithPR(ctx, owner, repo, file.Path, file.Content, "Add GitShop order template", "Add GitShop order template", prBody, "gitshop/add-order-template-"+branchSuffix, access)
+		if err != nil {
+			return "", contentAccessUserError(err, file.Path)
 		}
+		recordResult(result)
 	}
 
 	return prURL, nil
 }
 
+// templateChangePRBody follows intro with a summary of the products, prices, and options the
+// template change touches, so owners can review it without reading the YAML.
+func (s *AdminService) templateChangePRBody(ctx context.Context, intro, before, after string) string {
+	changelog, err := catalog.DiffOrderTemplates(before, after)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to summarize order template changes", "error", err)
+		return intro
+	}
+	return intro + "\n\n" + changelog.Markdown()
+}
+
+type repoOrderTemplate struct {
+	file    githubapp.RepoFile
+	content string
+}
+
+// repoOrderTemplates is what GitShop can see in the repository's issue template directory.
+type repoOrderTemplates struct {
+	// templates carry the order template marker; paths lists every readable template file, so new
+	// templates never overwrite one.
+	templates []repoOrderTemplate
+	paths     []string
+}
+
+func (t repoOrderTemplates) coveredSKUs() []string {
+	skus := []string{}
+	for _, template := range t.templates {
+		for sku := range catalog.FindTemplateSKUs(template.content) {
+			skus = append(skus, sku)
+		}
+	}
+	return skus
+}
+
+func readOrderTemplates(ctx context.Context, client *githubapp.Client, repoFullName string, access githubapp.ContentAccess) (repoOrderTemplates, error) {
+	files, err := listRepoDirectory(ctx, client, repoFullName, githubapp.IssueTemplateDir, access)
+	if err != nil {
+		return repoOrderTemplates{}, err
+	}
+
+	result := repoOrderTemplates{}
+	for _, file := range filterTemplateFiles(files) {
+		content, readErr := client.GetFile(ctx, repoFullName, file.Path, "")
+		if readErr != nil {
+			continue
+		}
+		result.paths = append(result.paths, file.Path)
+		if catalog.HasOrderTemplateMarker(string(content)) {
+			result.templates = append(result.templates, repoOrderTemplate{file: file, content: string(content)})
+		}
+	}
+	return result, nil
+}
+
 func (s *AdminService) ShipOrder(ctx context.Context, input ShipOrderInput) error {
 	span := sentry.StartSpan(
 		ctx,
@@ -385,12 +582,22 @@ func (s *AdminService) ShipOrder(ctx context.Context, input ShipOrderInput) erro
 			attribute.String("reason", "shipping_email_failed"),
 		))
 		logger.Error("failed to send shipping email", "error", err, "order_id", input.OrderID)
+	} else if order.CustomerEmail != "" {
+		if err := s.orderStore.RecordEmail(ctx, order.ID, db.OrderEmailShipped, order.CustomerEmail); err != nil {
+			logger.Warn("failed to record shipping email", "error", err, "order_id", input.OrderID)
+		}
 	}
 
 	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
-	commentBody := "🚚 Your order has shipped! Tracking details were sent by email."
-	if order.Status == db.StatusShipped {
-		commentBody = "🔄 Shipment details were updated. Check the latest tracking details in your email."
+	config, err := s.fetchValidatedConfig(ctx, client, shop.GitHubRepoFullName)
+	if err != nil {
+		logger.Warn("using default shipped comment", "error", err, "shop_id", shop.ID)
+		config = &catalog.GitShopConfig{}
+	}
+	loc := configLocalizer(config)
+	commentBody := shipmentUpdatedComment(loc)
+	if order.Status == db.StatusPaid {
+		commentBody = shopComment(config, config.Messages.OrderShipped, catalog.MessageData{OrderNumber: order.OrderNumber}, orderShippedComment(loc))
 	}
 
 	if err := client.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, commentBody); err != nil {
@@ -398,19 +605,25 @@ func (s *AdminService) ShipOrder(ctx context.Context, input ShipOrderInput) erro
 			attribute.String("reason", "github_comment_failed"),
 		))
 		logger.Error("failed to create GitHub comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	} else if err := recordOrderEvent(ctx, s.orderStore, order.ID, db.OrderEventCommentPosted, action); err != nil {
+		logger.Warn("failed to record shipment comment", "error", err, "order_id", input.OrderID)
 	}
-	if err := client.RemoveLabel(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, "gitshop:status:paid"); err != nil {
+	labels := shopLabels(config)
+	if err := client.RemoveLabel(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, labels.Name(catalog.LabelPaid)); err != nil {
 		meter.Count("fulfillment.shipment.side_effect_failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "github_remove_label_failed"),
 		))
 		logger.Warn("failed to remove paid label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
 	}
-	if err := client.AddLabels(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, []string{"gitshop:status:shipped"}); err != nil {
+	if err := client.AddLabels(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelShipped)}); err != nil {
 		meter.Count("fulfillment.shipment.side_effect_failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "github_add_label_failed"),
 		))
 		logger.Warn("failed to add shipped label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
 	}
+	if action == "mark_shipped" {
+		s.publishOrderEvent(ctx, shop, order.ID, db.WebhookOrderShipped)
+	}
 	meter.Count("fulfillment.shipment.processed", 1, sentry.WithAttributes(
 		attribute.String("action", action),
 	))
@@ -457,14 +670,3 @@ func filterTemplateFiles(files []githubapp.RepoFile) []githubapp.RepoFile {
 	}
 	return candidates
 }
-
-func hasOrderTemplateMarker(template string) bool {
-	for _, line := range strings.Split(template, "\n") {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			continue
-		}
-		return trimmed == "# gitshop:order-template"
-	}
-	return false
-}
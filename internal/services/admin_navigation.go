@@ -25,22 +25,22 @@ type ShopSwitcher struct {
 	Options      []ShopSwitcherOption
 }
 
-func (s *AdminService) IsStripeReady(ctx context.Context, shop *db.Shop) bool {
-	if s == nil || shop == nil || shop.StripeConnectAccountID == "" || s.stripePlatform == nil || s.shopStore == nil {
+// IsPaymentReady reports whether the shop's payment processor can take charges and pay out.
+func (s *AdminService) IsPaymentReady(ctx context.Context, shop *db.Shop) bool {
+	if s == nil || shop == nil {
 		return false
 	}
-
-	account, err := s.stripePlatform.GetAccount(ctx, shop.StripeConnectAccountID)
+	provider, err := s.payments.ForShop(ctx, shop)
 	if err != nil {
-		s.loggerFromContext(ctx).Warn("failed to verify stripe account", "error", err, "shop_id", shop.ID)
 		return false
 	}
 
-	if err := s.shopStore.UpdateStripeConnectDetails(ctx, shop.ID, shop.StripeConnectAccountID, account.DetailsSubmitted, account.ChargesEnabled, account.PayoutsEnabled); err != nil {
-		s.loggerFromContext(ctx).Warn("failed to persist stripe account details", "error", err, "shop_id", shop.ID)
+	status, err := provider.GetAccountStatus(ctx, shop)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to verify payment account", "error", err, "shop_id", shop.ID, "processor", provider.Processor())
+		return false
 	}
-
-	return account.ChargesEnabled && account.PayoutsEnabled
+	return status.ChargesEnabled && status.PayoutsEnabled
 }
 
 func (s *AdminService) IsOnboardingComplete(ctx context.Context, shop *db.Shop) bool {
@@ -48,7 +48,7 @@ func (s *AdminService) IsOnboardingComplete(ctx context.Context, shop *db.Shop)
 		return false
 	}
 
-	stripeReady := s.IsStripeReady(ctx, shop)
+	stripeReady := s.IsPaymentReady(ctx, shop)
 	status := s.BuildSetupStatus(ctx, shop)
 
 	return stripeReady &&
@@ -128,3 +128,28 @@ func TestAdminService_GetRecentOrders_InvalidShopID(t *testing.T) {
 		t.Fatalf("expected ErrAdminShopNotFound, got %v", err)
 	}
 }
+
+func TestStripeCapabilitiesFresh(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := []struct {
+		name      string
+		checkedAt time.Time
+		want      bool
+	}{
+		{name: "never checked", want: false},
+		{name: "checked recently", checkedAt: now.Add(-time.Minute), want: true},
+		{name: "checked too long ago", checkedAt: now.Add(-stripeCapabilitiesFreshFor - time.Second), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			capabilities := db.StripeCapabilities{ChargesEnabled: true, PayoutsEnabled: true, CheckedAt: tt.checkedAt}
+			if got := stripeCapabilitiesFresh(capabilities, now); got != tt.want {
+				t.Fatalf("stripeCapabilitiesFresh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+var (
+	ErrAdminRefundUnavailable  = errors.New("refund unavailable")
+	ErrAdminEmailUnavailable   = errors.New("order email unavailable")
+	ErrAdminOrderNotRefundable = errors.New("order not refundable")
+)
+
+type OrderTimelineEntry struct {
+	Status db.OrderStatus
+	Label  string
+	At     time.Time
+}
+
+type OrderDetail struct {
+	Order             *db.Order
+	Timeline          []OrderTimelineEntry
+	Emails            []*db.OrderEmail
+	Events            []db.OrderEvent
+	Notes             []db.OrderNote
+	ShippingAddress   string
+	StripeCheckoutURL string
+	StripePaymentURL  string
+	// StripePaymentLinkURL is set when the buyer was sent a fallback Payment Link.
+	StripePaymentLinkURL  string
+	CanShip               bool
+	CanMarkDelivered      bool
+	CanRefund             bool
+	CanReturn             bool
+	ResendEmailKind       db.OrderEmailKind
+	CanConvertInquiry     bool
+	CanApprove            bool
+	CanCancelSubscription bool
+	CanEditAddress        bool
+	// RelatedOrders are the buyer's other paid orders, and PreviousOrders counts those placed
+	// before this one.
+	RelatedOrders  []db.RelatedOrder
+	PreviousOrders int
+}
+
+type OrderActionInput struct {
+	ShopID  uuid.UUID
+	OrderID uuid.UUID
+}
+
+type ReturnOrderInput struct {
+	ShopID  uuid.UUID
+	OrderID uuid.UUID
+	Refund  bool
+}
+
+func (s *AdminService) GetOrderDetail(ctx context.Context, input OrderActionInput) (*OrderDetail, error) {
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	emails, err := s.orderStore.ListEmails(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order emails: %w", err)
+	}
+	notes, err := s.orderStore.ListNotes(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order notes: %w", err)
+	}
+	events, err := s.orderStore.ListEvents(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order events: %w", err)
+	}
+
+	detail := &OrderDetail{
+		Order:                 order,
+		Timeline:              BuildOrderTimeline(order),
+		Emails:                emails,
+		Events:                events,
+		Notes:                 notes,
+		ShippingAddress:       formatAddressMap(order.ShippingAddress),
+		CanShip:               order.Status == db.StatusPaid || order.Status == db.StatusShipped,
+		CanMarkDelivered:      order.Status == db.StatusShipped,
+		CanRefund:             s.payments != nil && isRefundable(order),
+		CanReturn:             isReturnable(order),
+		ResendEmailKind:       resendableEmailKind(order),
+		CanConvertInquiry:     s.stripePlatform != nil && order.Status == db.StatusInquiry,
+		CanApprove:            order.Status == db.StatusAwaitingApproval,
+		CanCancelSubscription: s.stripePlatform != nil && order.SubscriptionStatus == db.SubscriptionActive,
+		CanEditAddress:        order.Status == db.StatusPaid || order.Status == db.StatusAddressIssue,
+	}
+	if order.StripeCheckoutSessionID != "" {
+		detail.StripeCheckoutURL = "https://dashboard.stripe.com/checkout/sessions/" + order.StripeCheckoutSessionID
+	}
+	if order.StripePaymentIntentID != "" {
+		detail.StripePaymentURL = "https://dashboard.stripe.com/payments/" + order.StripePaymentIntentID
+	}
+	if order.StripePaymentLinkID != "" {
+		detail.StripePaymentLinkURL = "https://dashboard.stripe.com/payment-links/" + order.StripePaymentLinkID
+	}
+	s.loadRelatedOrders(ctx, detail)
+
+	return detail, nil
+}
+
+func (s *AdminService) RefundOrder(ctx context.Context, input OrderActionInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.refund_order",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("RefundOrder"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	meter.Count("fulfillment.refund.received", 1)
+	recordFailed := func(reason string) {
+		meter.Count("fulfillment.refund.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	if s.payments == nil {
+		recordFailed("payments_unavailable")
+		return fmt.Errorf("%w: payment providers not configured", ErrAdminRefundUnavailable)
+	}
+
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return err
+	}
+	if !isRefundable(order) {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: only paid, shipped, or delivered orders can be refunded", ErrAdminOrderNotRefundable)
+	}
+
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	provider, err := s.payments.ForShop(ctx, shop)
+	if err != nil {
+		recordFailed("payments_not_connected")
+		return fmt.Errorf("%w: %w", ErrAdminRefundUnavailable, err)
+	}
+
+	if err := provider.Refund(ctx, shop, order); err != nil {
+		recordFailed("refund_failed")
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	if err := s.orderStore.MarkRefunded(ctx, order.ID); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("mark_refunded_failed")
+		return fmt.Errorf("failed to mark order as refunded: %w", err)
+	}
+	refreshCustomer(ctx, s.orderStore, order.ID)
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	if err := client.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, orderRefundedComment(shopLocalizer(ctx, client, shop.GitHubRepoFullName))); err != nil {
+		meter.Count("fulfillment.refund.side_effect_failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "github_comment_failed"),
+		))
+		logger.Error("failed to create refund comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	meter.Count("fulfillment.refund.processed", 1)
+
+	return nil
+}
+
+// ReturnOrder records a returned shipment, refunding the payment too when input.Refund is set.
+func (s *AdminService) ReturnOrder(ctx context.Context, input ReturnOrderInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.return_order",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("ReturnOrder"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	order, err := s.getShopOrder(ctx, OrderActionInput{ShopID: input.ShopID, OrderID: input.OrderID})
+	if err != nil {
+		return err
+	}
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+
+	returns := orderReturn{
+		orderStore: s.orderStore,
+		payments:   s.payments,
+		emailer:    s.orderEmailer,
+		webhooks:   s.webhooks,
+		logger:     s.logger,
+	}
+	return returns.process(ctx, s.githubClient.WithInstallation(shop.GitHubInstallationID), shop, order, input.Refund)
+}
+
+// MarkDelivered records that a shipped order arrived and sends the buyer the delivered email.
+func (s *AdminService) MarkDelivered(ctx context.Context, input OrderActionInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.mark_delivered",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("MarkDelivered"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("fulfillment.delivery.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return err
+	}
+	if order.Status != db.StatusShipped {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: only shipped orders can be marked delivered", ErrAdminOrderStatusConflict)
+	}
+
+	if err := s.orderStore.MarkDelivered(ctx, order.ID); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("mark_delivered_failed")
+		return fmt.Errorf("failed to mark order as delivered: %w", err)
+	}
+
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+
+	if order.CustomerEmail != "" {
+		if err := s.orderEmailer.SendOrderDelivered(ctx, shop, order); err != nil {
+			meter.Count("fulfillment.delivery.side_effect_failed", 1, sentry.WithAttributes(
+				attribute.String("reason", "delivered_email_failed"),
+			))
+			logger.Error("failed to send delivered email", "error", err, "order_id", order.ID)
+		} else if err := s.orderStore.RecordEmail(ctx, order.ID, db.OrderEmailDelivered, order.CustomerEmail); err != nil {
+			logger.Warn("failed to record delivered email", "error", err, "order_id", order.ID)
+		}
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	config := shopConfig(ctx, client, shop.GitHubRepoFullName)
+	labels := shopLabels(config)
+	if err := client.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, orderDeliveredComment(configLocalizer(config))); err != nil {
+		meter.Count("fulfillment.delivery.side_effect_failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "github_comment_failed"),
+		))
+		logger.Error("failed to create delivered comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	s.requestReview(ctx, client, shop, order, time.Now())
+	if err := client.RemoveLabel(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, labels.Name(catalog.LabelShipped)); err != nil {
+		logger.Warn("failed to remove shipped label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	if err := client.AddLabels(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelDelivered)}); err != nil {
+		logger.Warn("failed to add delivered label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	s.publishOrderEvent(ctx, shop, order.ID, db.WebhookOrderDelivered)
+	meter.Count("fulfillment.delivery.processed", 1)
+
+	return nil
+}
+
+// publishOrderEvent reloads the order so the webhook payload reflects the change just made.
+func (s *AdminService) publishOrderEvent(ctx context.Context, shop *db.Shop, orderID uuid.UUID, event db.WebhookEvent) {
+	logger := s.loggerFromContext(ctx)
+	order, err := s.orderStore.GetByID(ctx, orderID)
+	if err != nil {
+		logger.Warn("failed to reload order for webhook", "error", err, "order_id", orderID, "event", event)
+		return
+	}
+	if err := s.webhooks.PublishOrderEvent(ctx, shop, order, event); err != nil {
+		logger.Warn("failed to queue order webhook", "error", err, "order_id", orderID, "event", event)
+	}
+}
+
+func (s *AdminService) ResendOrderEmail(ctx context.Context, input OrderActionInput) (db.OrderEmailKind, error) {
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	kind := resendableEmailKind(order)
+	if kind == "" {
+		return "", fmt.Errorf("%w: order has no customer email for its current status", ErrAdminEmailUnavailable)
+	}
+
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+
+	switch kind {
+	case db.OrderEmailConfirmation:
+		err = s.orderEmailer.SendOrderConfirmation(ctx, shop, order, OrderConfirmationEmailInput{})
+	case db.OrderEmailShipped:
+		err = s.orderEmailer.SendOrderShipped(ctx, shop, order, OrderShipmentEmailInput{
+			TrackingNumber:  order.TrackingNumber,
+			TrackingURL:     BuildTrackingURL(order.Carrier, order.TrackingNumber),
+			TrackingCarrier: order.Carrier,
+		})
+	case db.OrderEmailDelivered:
+		err = s.orderEmailer.SendOrderDelivered(ctx, shop, order)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to send order email: %w", err)
+	}
+
+	if recordErr := s.orderStore.RecordEmail(ctx, order.ID, kind, order.CustomerEmail); recordErr != nil {
+		s.loggerFromContext(ctx).Warn("failed to record order email", "error", recordErr, "order_id", order.ID, "kind", kind)
+	}
+
+	return kind, nil
+}
+
+func (s *AdminService) getShopOrder(ctx context.Context, input OrderActionInput) (*db.Order, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, fmt.Errorf("%w: order store unavailable", ErrAdminServiceUnavailable)
+	}
+	if input.ShopID == uuid.Nil || input.OrderID == uuid.Nil {
+		return nil, fmt.Errorf("%w: shop and order IDs are required", ErrAdminOrderNotFound)
+	}
+
+	order, err := s.orderStore.GetByID(ctx, input.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAdminOrderNotFound, err)
+	}
+	if order.ShopID != input.ShopID {
+		return nil, fmt.Errorf("%w: order does not belong to shop", ErrAdminOrderNotFound)
+	}
+
+	return order, nil
+}
+
+// BuildOrderTimeline returns the recorded status transitions of an order, oldest first.
+func BuildOrderTimeline(order *db.Order) []OrderTimelineEntry {
+	if order == nil {
+		return nil
+	}
+
+	approvedLabel := "Approved"
+	if order.ApprovedBy != "" {
+		approvedLabel += " by @" + order.ApprovedBy
+	}
+	candidates := []OrderTimelineEntry{
+		{Status: db.StatusPendingPayment, Label: "Order placed", At: order.CreatedAt},
+		{Status: db.StatusPendingPayment, Label: approvedLabel, At: order.ApprovedAt},
+		{Status: db.StatusPaid, Label: "Payment received", At: order.PaidAt},
+		{Status: db.StatusShipped, Label: "Shipped", At: order.ShippedAt},
+		{Status: db.StatusDelivered, Label: "Delivered", At: order.DeliveredAt},
+		{Status: db.StatusRefunded, Label: "Refunded", At: order.RefundedAt},
+		{Status: db.StatusReturned, Label: "Returned", At: order.ReturnedAt},
+		{Status: db.StatusCancelled, Label: "Subscription cancelled", At: order.SubscriptionCancelledAt},
+	}
+
+	timeline := make([]OrderTimelineEntry, 0, len(candidates)+1)
+	for _, entry := range candidates {
+		if entry.At.IsZero() {
+			continue
+		}
+		timeline = append(timeline, entry)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+
+	switch order.Status {
+	case db.StatusPaymentFailed:
+		label := "Payment failed"
+		if reason := strings.TrimSpace(order.FailureReason); reason != "" {
+			label += " (" + reason + ")"
+		}
+		timeline = append(timeline, OrderTimelineEntry{Status: db.StatusPaymentFailed, Label: label})
+	case db.StatusExpired:
+		timeline = append(timeline, OrderTimelineEntry{Status: db.StatusExpired, Label: "Checkout expired"})
+	case db.StatusAwaitingApproval:
+		timeline = append(timeline, OrderTimelineEntry{Status: db.StatusAwaitingApproval, Label: "Awaiting approval"})
+	case db.StatusAddressIssue:
+		timeline = append(timeline, OrderTimelineEntry{Status: db.StatusAddressIssue, Label: "Held for address issue"})
+	case db.StatusCancelled:
+		label := "Cancelled"
+		switch order.CancellationReason {
+		case cancellationIssueClosed:
+			label += " (issue closed)"
+		case cancellationIssueDeleted:
+			label += " (issue deleted)"
+		case cancellationApprovalRejected:
+			label += " (rejected)"
+		}
+		timeline = append(timeline, OrderTimelineEntry{Status: db.StatusCancelled, Label: label, At: order.CancelledAt})
+	}
+
+	return timeline
+}
+
+func isRefundable(order *db.Order) bool {
+	if order == nil || order.StripePaymentIntentID == "" {
+		return false
+	}
+	switch order.Status {
+	case db.StatusPaid, db.StatusAddressIssue, db.StatusShipped, db.StatusDelivered:
+		return true
+	default:
+		return false
+	}
+}
+
+func resendableEmailKind(order *db.Order) db.OrderEmailKind {
+	if order == nil || strings.TrimSpace(order.CustomerEmail) == "" {
+		return ""
+	}
+	switch order.Status {
+	case db.StatusPaid:
+		return db.OrderEmailConfirmation
+	case db.StatusShipped:
+		return db.OrderEmailShipped
+	case db.StatusDelivered:
+		return db.OrderEmailDelivered
+	default:
+		return ""
+	}
+}
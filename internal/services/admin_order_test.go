@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestBuildOrderTimeline(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		order  *db.Order
+		labels []string
+	}{
+		{
+			name:   "nil order",
+			order:  nil,
+			labels: nil,
+		},
+		{
+			name: "shipped order",
+			order: &db.Order{
+				Status:    db.StatusShipped,
+				CreatedAt: created,
+				PaidAt:    created.Add(time.Minute),
+				ShippedAt: created.Add(time.Hour),
+			},
+			labels: []string{"Order placed", "Payment received", "Shipped"},
+		},
+		{
+			name: "refunded after delivery",
+			order: &db.Order{
+				Status:      db.StatusRefunded,
+				CreatedAt:   created,
+				PaidAt:      created.Add(time.Minute),
+				DeliveredAt: created.Add(48 * time.Hour),
+				RefundedAt:  created.Add(72 * time.Hour),
+			},
+			labels: []string{"Order placed", "Payment received", "Delivered", "Refunded"},
+		},
+		{
+			name: "returned with refund",
+			order: &db.Order{
+				Status:     db.StatusReturned,
+				CreatedAt:  created,
+				PaidAt:     created.Add(time.Minute),
+				ShippedAt:  created.Add(time.Hour),
+				RefundedAt: created.Add(96 * time.Hour),
+				ReturnedAt: created.Add(96 * time.Hour),
+			},
+			labels: []string{"Order placed", "Payment received", "Shipped", "Refunded", "Returned"},
+		},
+		{
+			name: "payment failed",
+			order: &db.Order{
+				Status:        db.StatusPaymentFailed,
+				CreatedAt:     created,
+				FailureReason: "card_declined",
+			},
+			labels: []string{"Order placed", "Payment failed (card_declined)"},
+		},
+		{
+			name: "cancelled by closing the issue",
+			order: &db.Order{
+				Status:             db.StatusCancelled,
+				CreatedAt:          created,
+				CancelledAt:        created.Add(time.Hour),
+				CancellationReason: "issue_closed",
+			},
+			labels: []string{"Order placed", "Cancelled (issue closed)"},
+		},
+		{
+			name: "awaiting approval",
+			order: &db.Order{
+				Status:    db.StatusAwaitingApproval,
+				CreatedAt: created,
+			},
+			labels: []string{"Order placed", "Awaiting approval"},
+		},
+		{
+			name: "approved then paid",
+			order: &db.Order{
+				Status:     db.StatusPaid,
+				CreatedAt:  created,
+				ApprovedAt: created.Add(time.Hour),
+				ApprovedBy: "octocat",
+				PaidAt:     created.Add(2 * time.Hour),
+			},
+			labels: []string{"Order placed", "Approved by @octocat", "Payment received"},
+		},
+		{
+			name: "rejected while held",
+			order: &db.Order{
+				Status:             db.StatusCancelled,
+				CreatedAt:          created,
+				CancelledAt:        created.Add(time.Hour),
+				CancellationReason: "approval_rejected",
+			},
+			labels: []string{"Order placed", "Cancelled (rejected)"},
+		},
+		{
+			name: "subscription cancelled after shipping",
+			order: &db.Order{
+				Status:                  db.StatusShipped,
+				CreatedAt:               created,
+				PaidAt:                  created.Add(time.Minute),
+				ShippedAt:               created.Add(time.Hour),
+				SubscriptionStatus:      db.SubscriptionCancelled,
+				SubscriptionCancelledAt: created.Add(30 * 24 * time.Hour),
+			},
+			labels: []string{"Order placed", "Payment received", "Shipped", "Subscription cancelled"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			timeline := BuildOrderTimeline(tt.order)
+			if len(timeline) != len(tt.labels) {
+				t.Fatalf("expected %d entries, got %d (%v)", len(tt.labels), len(timeline), timeline)
+			}
+			for i, label := range tt.labels {
+				if timeline[i].Label != label {
+					t.Fatalf("entry %d: expected %q, got %q", i, label, timeline[i].Label)
+				}
+			}
+		})
+	}
+}
+
+func TestResendableEmailKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		order *db.Order
+		want  db.OrderEmailKind
+	}{
+		{name: "paid", order: &db.Order{Status: db.StatusPaid, CustomerEmail: "a@example.com"}, want: db.OrderEmailConfirmation},
+		{name: "shipped", order: &db.Order{Status: db.StatusShipped, CustomerEmail: "a@example.com"}, want: db.OrderEmailShipped},
+		{name: "delivered", order: &db.Order{Status: db.StatusDelivered, CustomerEmail: "a@example.com"}, want: db.OrderEmailDelivered},
+		{name: "pending payment", order: &db.Order{Status: db.StatusPendingPayment, CustomerEmail: "a@example.com"}, want: ""},
+		{name: "missing email", order: &db.Order{Status: db.StatusPaid}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := resendableEmailKind(tt.order); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAdminService_RefundOrder_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{}
+
+	err := service.RefundOrder(t.Context(), OrderActionInput{ShopID: uuid.New(), OrderID: uuid.New()})
+	if !errors.Is(err, ErrAdminRefundUnavailable) {
+		t.Fatalf("expected ErrAdminRefundUnavailable, got %v", err)
+	}
+}
+
+func TestAdminService_GetOrderDetail_ServiceUnavailable(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{}
+
+	_, err := service.GetOrderDetail(t.Context(), OrderActionInput{ShopID: uuid.New(), OrderID: uuid.New()})
+	if !errors.Is(err, ErrAdminServiceUnavailable) {
+		t.Fatalf("expected ErrAdminServiceUnavailable, got %v", err)
+	}
+}
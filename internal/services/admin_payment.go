@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+// PaymentSettingsInput is the payment processor form. Blank secrets keep the saved ones.
+type PaymentSettingsInput struct {
+	Processor     string
+	StoreID       string
+	VariantID     string
+	APIKey        string
+	WebhookSecret string
+}
+
+func (s *AdminService) GetPaymentSettings(ctx context.Context, shopID uuid.UUID) (db.PaymentSettings, error) {
+	if s == nil || s.shopStore == nil {
+		return db.PaymentSettings{}, fmt.Errorf("%w: shop store unavailable", ErrAdminServiceUnavailable)
+	}
+	return s.shopStore.GetPaymentSettings(ctx, shopID)
+}
+
+// UpdatePaymentSettings switches the shop's payment processor. Lemon Squeezy credentials are
+// checked against the store before they are saved.
+func (s *AdminService) UpdatePaymentSettings(ctx context.Context, shopID uuid.UUID, input PaymentSettingsInput) error {
+	processor := db.PaymentProcessor(strings.TrimSpace(input.Processor))
+	if processor != db.PaymentProcessorStripe && processor != db.PaymentProcessorLemonSqueezy {
+		return UserError{Message: "Payment processor must be stripe or lemonsqueezy"}
+	}
+	settings := db.PaymentSettings{Processor: processor}
+	if processor == db.PaymentProcessorLemonSqueezy {
+		settings.LemonSqueezy = db.LemonSqueezySettings{
+			StoreID:       strings.TrimSpace(input.StoreID),
+			VariantID:     strings.TrimSpace(input.VariantID),
+			APIKey:        strings.TrimSpace(input.APIKey),
+			WebhookSecret: strings.TrimSpace(input.WebhookSecret),
+		}
+		if settings.LemonSqueezy.StoreID == "" || settings.LemonSqueezy.VariantID == "" {
+			return UserError{Message: "Store ID and variant ID are required for Lemon Squeezy"}
+		}
+	}
+
+	if s == nil || s.shopStore == nil || s.payments == nil {
+		return fmt.Errorf("%w: payment settings unavailable", ErrAdminServiceUnavailable)
+	}
+	if processor == db.PaymentProcessorStripe {
+		return s.shopStore.UpdatePaymentSettings(ctx, shopID, settings)
+	}
+
+	existing, err := s.shopStore.GetPaymentSettings(ctx, shopID)
+	if err != nil {
+		return fmt.Errorf("failed to load payment settings: %w", err)
+	}
+	if settings.LemonSqueezy.APIKey == "" {
+		settings.LemonSqueezy.APIKey = existing.LemonSqueezy.APIKey
+	}
+	if settings.LemonSqueezy.WebhookSecret == "" {
+		settings.LemonSqueezy.WebhookSecret = existing.LemonSqueezy.WebhookSecret
+	}
+	if !settings.LemonSqueezy.Configured() {
+		return UserError{Message: "An API key and webhook signing secret are required for Lemon Squeezy"}
+	}
+	if _, err := s.payments.lemonSqueezy(settings.LemonSqueezy).GetAccountStatus(ctx, nil); err != nil {
+		return UserError{Message: fmt.Sprintf("Could not reach the Lemon Squeezy store: %s", err.Error())}
+	}
+
+	return s.shopStore.UpdatePaymentSettings(ctx, shopID, settings)
+}
+
+// StripeTestModeAvailable reports whether this GitShop instance has Stripe test keys, without
+// which shops cannot be put in test mode.
+func (s *AdminService) StripeTestModeAvailable() bool {
+	return s != nil && s.stripePlatform.TestModeAvailable()
+}
+
+// SetStripeTestMode turns test mode on or off for the shop's new orders. Orders already placed
+// keep the mode they were placed in.
+func (s *AdminService) SetStripeTestMode(ctx context.Context, shopID uuid.UUID, enabled bool) error {
+	if enabled && !s.StripeTestModeAvailable() {
+		return UserError{Message: "Test mode is not available on this GitShop instance"}
+	}
+	if s == nil || s.shopStore == nil {
+		return fmt.Errorf("%w: shop store unavailable", ErrAdminServiceUnavailable)
+	}
+	if enabled {
+		settings, err := s.shopStore.GetPaymentSettings(ctx, shopID)
+		if err != nil {
+			return fmt.Errorf("failed to load payment settings: %w", err)
+		}
+		if settings.Processor != db.PaymentProcessorStripe {
+			return UserError{Message: "Test mode is only available for shops that take payments through Stripe"}
+		}
+	}
+	return s.shopStore.SetStripeTestMode(ctx, shopID, enabled)
+}
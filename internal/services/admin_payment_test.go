@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAdminService_UpdatePaymentSettings_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input PaymentSettingsInput
+	}{
+		{name: "unknown processor", input: PaymentSettingsInput{Processor: "paypal"}},
+		{name: "missing processor", input: PaymentSettingsInput{}},
+		{name: "lemon squeezy without store", input: PaymentSettingsInput{Processor: "lemonsqueezy", VariantID: "2", APIKey: "key", WebhookSecret: "secret"}},
+		{name: "lemon squeezy without variant", input: PaymentSettingsInput{Processor: "lemonsqueezy", StoreID: "1", APIKey: "key", WebhookSecret: "secret"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &AdminService{}
+			err := service.UpdatePaymentSettings(t.Context(), uuid.New(), tt.input)
+			var userErr UserError
+			if !errors.As(err, &userErr) {
+				t.Fatalf("expected UserError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAdminService_SetStripeTestMode_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{}
+	err := service.SetStripeTestMode(t.Context(), uuid.New(), true)
+	var userErr UserError
+	if !errors.As(err, &userErr) {
+		t.Fatalf("expected UserError, got %v", err)
+	}
+}
@@ -2,39 +2,47 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
+	"net/url"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
 	"github.com/gitshopapp/gitshop/internal/catalog"
 	"github.com/gitshopapp/gitshop/internal/db"
 	"github.com/gitshopapp/gitshop/internal/githubapp"
 )
 
 type SetupStatus struct {
-	Labels   RepoLabelsStatus
-	YAML     GitShopYAMLStatus
-	Template OrderTemplateStatus
+	Labels         RepoLabelsStatus
+	YAML           GitShopYAMLStatus
+	Template       OrderTemplateStatus
+	EmailTemplates EmailTemplatesStatus
 }
 
 type RepoLabelsStatus struct {
-	Ready        bool
-	Missing      []string
+	Ready   bool
+	Missing []string
+	// Recolor lists labels that exist but whose color differs from gitshop.yaml.
+	Recolor      []string
 	ErrorMessage string
 }
 
 type GitShopYAMLStatus struct {
-	Exists           bool
-	Valid            bool
-	Method           string
-	URL              string
-	ErrorMessage     string
+	Exists       bool
+	Valid        bool
+	Method       string
+	URL          string
+	ErrorMessage string
+	// ErrorPath is the gitshop.yaml field ErrorMessage is about, such as products[0].sku, when
+	// validation could point at one.
+	ErrorPath        string
 	LastUpdatedLabel string
+	// UpgradeNotes lists what to change when the file is written in an older schema version.
+	UpgradeNotes []string
+	// AccessNotice explains what GitShop cannot do with the installation's file permissions.
+	AccessNotice string
 }
 
 type OrderTemplateStatus struct {
@@ -46,17 +54,31 @@ type OrderTemplateStatus struct {
 	UnknownSKUs      []string
 	PriceMismatches  []string
 	OptionMismatches []string
+	Warnings         []string
 	SyncAvailable    bool
 	SyncMessage      string
 	LastUpdatedLabel string
 	Count            int
+	Files            []TemplateFile
+	MissingSKUs      []string
+	AccessNotice     string
+}
+
+type EmailTemplatesStatus struct {
+	Files        []string
+	Issues       []EmailTemplateIssue
+	ErrorMessage string
 }
 
 type ProductSummary struct {
 	SKU        string
 	Name       string
-	PriceCents int
+	PriceCents int64
 	Active     bool
+	Category   string
+	// OrderURL opens a new order issue with this product already selected, when a template
+	// lists it.
+	OrderURL string
 }
 
 type RepoStatus struct {
@@ -76,9 +98,11 @@ type RepoStatus struct {
 	TemplateExtraSKUs        []string
 	TemplatePriceMismatches  []string
 	TemplateOptionMismatches []string
+	TemplateWarnings         []string
 	TemplateSyncAvailable    bool
 	TemplateSyncMessage      string
 	Products                 []ProductSummary
+	Branding                 catalog.BrandingConfig
 }
 
 type TemplateFile struct {
@@ -91,17 +115,8 @@ func IsEmailConfigured(shop *db.Shop) bool {
 	return shop != nil && shop.EmailVerified && shop.EmailProvider != "" && len(shop.EmailConfig) > 0
 }
 
-func RequiredRepoLabels() []githubapp.LabelDefinition {
-	return []githubapp.LabelDefinition{
-		{Name: "gitshop:order", Color: "0ea5e9", Description: "GitShop order issue"},
-		{Name: "gitshop:status:pending-payment", Color: "f59e0b", Description: "Awaiting payment"},
-		{Name: "gitshop:status:paid", Color: "10b981", Description: "Payment received"},
-		{Name: "gitshop:status:shipped", Color: "3b82f6", Description: "Order shipped"},
-		{Name: "gitshop:status:delivered", Color: "22c55e", Description: "Order delivered"},
-		{Name: "gitshop:status:expired", Color: "6b7280", Description: "Order expired"},
-	}
-}
-
+// BuildSetupStatus checks the shop repository for the setup page. Independent GitHub calls run
+// concurrently; the template check waits for gitshop.yaml and the installation's file access.
 func (s *AdminService) BuildSetupStatus(ctx context.Context, shop *db.Shop) SetupStatus {
 	status := SetupStatus{}
 	if shop == nil || shop.GitHubRepoFullName == "" {
@@ -110,12 +125,46 @@ func (s *AdminService) BuildSetupStatus(ctx context.Context, shop *db.Shop) Setu
 		status.Template.ErrorMessage = "shop is required"
 		return status
 	}
+	defer recordStatusLatency(ctx, "setup", time.Now())
 
 	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
-	status.Labels = s.buildLabelsStatus(ctx, client, shop.GitHubRepoFullName)
-	yamlStatus, config := s.buildYAMLStatus(ctx, client, shop.GitHubRepoFullName)
+	repoFullName := shop.GitHubRepoFullName
+	var (
+		access     githubapp.ContentAccess
+		yamlStatus GitShopYAMLStatus
+		config     *catalog.GitShopConfig
+	)
+	runConcurrently(
+		func() {
+			callCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+			defer cancel()
+			access = contentAccess(callCtx, client, s.logger)
+		},
+		func() {
+			callCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+			defer cancel()
+			status.Labels = s.buildLabelsStatus(callCtx, client, repoFullName)
+		},
+		func() {
+			callCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+			defer cancel()
+			yamlStatus, config = s.buildYAMLStatus(callCtx, client, repoFullName)
+		},
+	)
+	yamlStatus.AccessNotice = yamlAccessNotice(access)
 	status.YAML = yamlStatus
-	status.Template = s.buildTemplateStatus(ctx, client, shop.GitHubRepoFullName, yamlStatus, config)
+
+	runConcurrently(
+		func() {
+			status.Template = s.buildTemplateStatus(ctx, client, repoFullName, access, yamlStatus, config)
+			status.Template.AccessNotice = templateAccessNotice(access)
+		},
+		func() {
+			callCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+			defer cancel()
+			status.EmailTemplates = buildEmailTemplatesStatus(callCtx, client, repoFullName, access)
+		},
+	)
 	return status
 }
 
@@ -124,36 +173,42 @@ func (s *AdminService) BuildRepoStatus(ctx context.Context, shop *db.Shop) *Repo
 		return nil
 	}
 
-	status := &RepoStatus{
-		StripeReady:     s.IsStripeReady(ctx, shop),
-		EmailConfigured: IsEmailConfigured(shop),
-	}
-	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
-
-	yamlFileStatus, yamlPath, err := s.getGitShopFileStatus(ctx, client, shop.GitHubRepoFullName)
-	if err == nil && yamlFileStatus != nil {
-		status.YAMLExists = yamlFileStatus.Exists
-		status.YAMLURL = yamlFileStatus.HTMLURL
-		if !yamlFileStatus.LastUpdated.IsZero() {
-			status.YAMLLastUpdatedLabel = humanizeSince(yamlFileStatus.LastUpdated)
-		}
-	}
+	defer recordStatusLatency(ctx, "repo", time.Now())
 
-	var config *catalog.GitShopConfig
-	if status.YAMLExists {
-		content, contentErr := s.getGitShopFile(ctx, client, shop.GitHubRepoFullName, yamlPath)
-		if contentErr == nil {
-			parsed, parseErr := s.parser.Parse(content)
-			if parseErr == nil {
-				if validateErr := s.validator.Validate(parsed); validateErr == nil {
-					status.YAMLValid = true
-					config = parsed
-				}
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	repoFullName := shop.GitHubRepoFullName
+	status := &RepoStatus{EmailConfigured: IsEmailConfigured(shop)}
+	var (
+		config        *catalog.GitShopConfig
+		templateFiles []templateFileContent
+		listErr       error
+	)
+	runConcurrently(
+		func() {
+			status.StripeReady = s.IsPaymentReady(ctx, shop)
+		},
+		func() {
+			callCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+			defer cancel()
+			config = s.loadRepoConfig(callCtx, client, repoFullName, status)
+		},
+		func() {
+			listCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+			access := contentAccess(listCtx, client, s.logger)
+			files, err := listRepoDirectory(listCtx, client, repoFullName, githubapp.IssueTemplateDir, access)
+			cancel()
+			if err != nil {
+				listErr = err
+				return
 			}
-		}
-	}
+			candidates := filterTemplateFiles(files)
+			status.TemplateCount = len(candidates)
+			templateFiles = fetchOrderTemplates(ctx, client, repoFullName, candidates)
+		},
+	)
 
 	if config != nil {
+		status.Branding = shopBranding(config)
 		for _, product := range config.Products {
 			if !product.Active {
 				continue
@@ -161,39 +216,26 @@ func (s *AdminService) BuildRepoStatus(ctx context.Context, shop *db.Shop) *Repo
 			status.Products = append(status.Products, ProductSummary{
 				SKU:        product.SKU,
 				Name:       product.Name,
-				PriceCents: product.UnitPriceCents,
+				PriceCents: product.PriceCents(),
 				Active:     product.Active,
+				Category:   strings.TrimSpace(product.Category),
 			})
 		}
 	}
-
-	templateFiles, err := client.ListDirectory(ctx, shop.GitHubRepoFullName, ".github/ISSUE_TEMPLATE")
-	if err != nil {
+	if listErr != nil {
 		return status
 	}
 
-	templateCandidates := filterTemplateFiles(templateFiles)
-	status.TemplateCount = len(templateCandidates)
-
 	latestTemplateUpdate := time.Time{}
 	templateExtraSKUs := make(map[string]struct{})
+	templateSKUs := make(map[string]struct{})
+	orderURLs := make(map[string]string)
 	anyValidTemplate := false
 
-	for _, file := range templateCandidates {
-		fileStatus, statusErr := client.GetFileStatus(ctx, shop.GitHubRepoFullName, file.Path)
-		if statusErr == nil && fileStatus != nil && fileStatus.LastUpdated.After(latestTemplateUpdate) {
-			latestTemplateUpdate = fileStatus.LastUpdated
-		}
-
-		content, readErr := client.GetFile(ctx, shop.GitHubRepoFullName, file.Path, "")
-		if readErr != nil {
-			continue
-		}
-
-		templateContent := string(content)
-		hasMarker := hasOrderTemplateMarker(templateContent)
-		if !hasMarker {
-			continue
+	for _, template := range templateFiles {
+		file, templateContent := template.file, template.content
+		if template.lastUpdated.After(latestTemplateUpdate) {
+			latestTemplateUpdate = template.lastUpdated
 		}
 
 		if status.TemplateURL == "" {
@@ -201,31 +243,24 @@ func (s *AdminService) BuildRepoStatus(ctx context.Context, shop *db.Shop) *Repo
 		}
 		status.TemplateExists = true
 
-		hasLabel := templateHasLabel(templateContent, "gitshop:order")
-		fileValid := hasMarker && hasLabel
+		fileValid := catalog.TemplateHasLabel(templateContent, shopLabels(config).Name(catalog.LabelOrder))
 
 		if status.YAMLValid && config != nil {
-			templateSKUs := findTemplateSKUs(templateContent)
-			yamlSKUs := make(map[string]struct{}, len(config.Products))
-			for _, product := range config.Products {
-				yamlSKUs[product.SKU] = struct{}{}
+			report := catalog.CheckOrderTemplate(templateContent, config)
+			for _, sku := range report.UnknownSKUs {
+				templateExtraSKUs[sku] = struct{}{}
 			}
-			unknownForFile := false
-			for sku := range templateSKUs {
-				if _, ok := yamlSKUs[sku]; !ok {
-					templateExtraSKUs[sku] = struct{}{}
-					unknownForFile = true
-				}
-			}
-
-			optionMismatches := findTemplateOptionMismatches(templateContent, config)
-			status.TemplateOptionMismatches = append(status.TemplateOptionMismatches, optionMismatches...)
-
-			priceMismatches := findTemplatePriceMismatches(templateContent, config)
-			status.TemplatePriceMismatches = append(status.TemplatePriceMismatches, priceMismatches...)
-
-			if len(templateSKUs) == 0 || unknownForFile || len(optionMismatches) > 0 || len(priceMismatches) > 0 {
-				fileValid = false
+			status.TemplateOptionMismatches = append(status.TemplateOptionMismatches, report.OptionMismatches...)
+			status.TemplatePriceMismatches = append(status.TemplatePriceMismatches, report.PriceMismatches...)
+			status.TemplateWarnings = append(status.TemplateWarnings, report.Warnings...)
+			fileValid = report.Valid()
+		}
+		for sku := range catalog.FindTemplateSKUs(templateContent) {
+			templateSKUs[sku] = struct{}{}
+		}
+		for sku, label := range catalog.FindTemplateProductLabels(templateContent) {
+			if _, ok := orderURLs[sku]; !ok {
+				orderURLs[sku] = productOrderURL(repoFullName, file.Name, label)
 			}
 		}
 
@@ -243,13 +278,19 @@ func (s *AdminService) BuildRepoStatus(ctx context.Context, shop *db.Shop) *Repo
 	if !latestTemplateUpdate.IsZero() {
 		status.TemplateLastUpdatedLabel = humanizeSince(latestTemplateUpdate)
 	}
+	for i := range status.Products {
+		status.Products[i].OrderURL = orderURLs[status.Products[i].SKU]
+	}
 
 	for sku := range templateExtraSKUs {
 		status.TemplateExtraSKUs = append(status.TemplateExtraSKUs, sku)
 	}
 	sort.Strings(status.TemplateExtraSKUs)
+	if status.TemplateExists && status.YAMLValid {
+		status.TemplateMissingSKUs = templateMissingSKUs(config, templateSKUs)
+	}
 
-	if anyValidTemplate && len(status.TemplateExtraSKUs) == 0 && len(status.TemplatePriceMismatches) == 0 && len(status.TemplateOptionMismatches) == 0 {
+	if anyValidTemplate && len(status.TemplateExtraSKUs) == 0 && len(status.TemplateMissingSKUs) == 0 && len(status.TemplatePriceMismatches) == 0 && len(status.TemplateOptionMismatches) == 0 {
 		status.TemplateValid = true
 	}
 
@@ -257,6 +298,34 @@ func (s *AdminService) BuildRepoStatus(ctx context.Context, shop *db.Shop) *Repo
 	return status
 }
 
+// loadRepoConfig fills in the gitshop.yaml fields of status and returns the config when it is
+// valid.
+func (s *AdminService) loadRepoConfig(ctx context.Context, client *githubapp.Client, repoFullName string, status *RepoStatus) *catalog.GitShopConfig {
+	yamlFileStatus, yamlPath, err := s.getGitShopFileStatus(ctx, client, repoFullName)
+	if err != nil || yamlFileStatus == nil {
+		return nil
+	}
+	status.YAMLExists = yamlFileStatus.Exists
+	status.YAMLURL = yamlFileStatus.HTMLURL
+	if !yamlFileStatus.LastUpdated.IsZero() {
+		status.YAMLLastUpdatedLabel = humanizeSince(yamlFileStatus.LastUpdated)
+	}
+	if !status.YAMLExists {
+		return nil
+	}
+
+	content, err := s.getGitShopFile(ctx, client, repoFullName, yamlPath)
+	if err != nil {
+		return nil
+	}
+	parsed, err := s.parser.Parse(content)
+	if err != nil || s.validator.Validate(parsed) != nil {
+		return nil
+	}
+	status.YAMLValid = true
+	return parsed
+}
+
 func (s *AdminService) buildLabelsStatus(ctx context.Context, client *githubapp.Client, repoFullName string) RepoLabelsStatus {
 	status := RepoLabelsStatus{}
 	labels, err := client.ListLabels(ctx, repoFullName)
@@ -265,13 +334,17 @@ func (s *AdminService) buildLabelsStatus(ctx context.Context, client *githubapp.
 		return status
 	}
 
-	for _, label := range RequiredRepoLabels() {
-		if _, ok := labels[label.Name]; !ok {
+	for _, label := range RequiredRepoLabels(shopLabels(shopConfig(ctx, client, repoFullName))) {
+		existing, ok := labels[label.Name]
+		switch {
+		case !ok:
 			status.Missing = append(status.Missing, label.Name)
+		case !strings.EqualFold(existing.GetColor(), label.Color):
+			status.Recolor = append(status.Recolor, label.Name)
 		}
 	}
 
-	status.Ready = len(status.Missing) == 0
+	status.Ready = len(status.Missing) == 0 && len(status.Recolor) == 0
 	return status
 }
 
@@ -307,8 +380,16 @@ func (s *AdminService) buildYAMLStatus(ctx context.Context, client *githubapp.Cl
 		return status, nil
 	}
 
+	if config.NeedsUpgrade() {
+		status.UpgradeNotes = catalog.UpgradeNotes(config.SourceVersion)
+	}
+
 	if err := s.validator.Validate(config); err != nil {
 		status.ErrorMessage = err.Error()
+		var validationErr *catalog.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Path != "" {
+			status.ErrorPath, status.ErrorMessage = validationErr.Path, validationErr.Message
+		}
 		return status, nil
 	}
 
@@ -316,9 +397,11 @@ func (s *AdminService) buildYAMLStatus(ctx context.Context, client *githubapp.Cl
 	return status, config
 }
 
-func (s *AdminService) buildTemplateStatus(ctx context.Context, client *githubapp.Client, repoFullName string, yamlStatus GitShopYAMLStatus, config *catalog.GitShopConfig) OrderTemplateStatus {
+func (s *AdminService) buildTemplateStatus(ctx context.Context, client *githubapp.Client, repoFullName string, access githubapp.ContentAccess, yamlStatus GitShopYAMLStatus, config *catalog.GitShopConfig) OrderTemplateStatus {
 	status := OrderTemplateStatus{}
-	files, err := client.ListDirectory(ctx, repoFullName, ".github/ISSUE_TEMPLATE")
+	listCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+	files, err := listRepoDirectory(listCtx, client, repoFullName, githubapp.IssueTemplateDir, access)
+	cancel()
 	if err != nil {
 		status.ErrorMessage = err.Error()
 		return status
@@ -329,62 +412,31 @@ func (s *AdminService) buildTemplateStatus(ctx context.Context, client *githubap
 	anyValid := false
 	firstURL := ""
 	firstInvalidURL := ""
+	coveredSKUs := map[string]struct{}{}
 
-	for _, file := range templateCandidates {
-		content, readErr := client.GetFile(ctx, repoFullName, file.Path, "")
-		if readErr != nil {
-			continue
-		}
-
-		templateContent := string(content)
-		hasMarker := hasOrderTemplateMarker(templateContent)
-		hasLabel := templateHasLabel(templateContent, "gitshop:order")
-		if !hasMarker {
-			continue
-		}
-
+	for _, template := range fetchOrderTemplates(ctx, client, repoFullName, templateCandidates) {
+		file, templateContent := template.file, template.content
 		status.Count++
 		if firstURL == "" {
 			firstURL = file.HTMLURL
 		}
-
-		fileStatus, statusErr := client.GetFileStatus(ctx, repoFullName, file.Path)
-		if statusErr == nil && fileStatus != nil && fileStatus.LastUpdated.After(latestUpdate) {
-			latestUpdate = fileStatus.LastUpdated
+		if template.lastUpdated.After(latestUpdate) {
+			latestUpdate = template.lastUpdated
 		}
 
-		fileValid := hasMarker && hasLabel
-		if !yamlStatus.Valid || config == nil {
-			fileValid = false
-		} else {
-			templateSKUs := findTemplateSKUs(templateContent)
-			yamlSKUs := make(map[string]struct{}, len(config.Products))
-			for _, product := range config.Products {
-				yamlSKUs[product.SKU] = struct{}{}
-			}
-			unknown := false
-			for sku := range templateSKUs {
-				if _, ok := yamlSKUs[sku]; !ok {
-					status.UnknownSKUs = append(status.UnknownSKUs, sku)
-					unknown = true
-				}
-			}
-			if len(templateSKUs) == 0 || unknown {
-				fileValid = false
-			}
-
-			optionMismatches := findTemplateOptionMismatches(templateContent, config)
-			if len(optionMismatches) > 0 {
-				status.OptionMismatches = append(status.OptionMismatches, optionMismatches...)
-				fileValid = false
-			}
-
-			priceMismatches := findTemplatePriceMismatches(templateContent, config)
-			if len(priceMismatches) > 0 {
-				status.PriceMismatches = append(status.PriceMismatches, priceMismatches...)
-				fileValid = false
-			}
+		fileValid := false
+		if yamlStatus.Valid && config != nil {
+			report := catalog.CheckOrderTemplate(templateContent, config)
+			status.UnknownSKUs = append(status.UnknownSKUs, report.UnknownSKUs...)
+			status.OptionMismatches = append(status.OptionMismatches, report.OptionMismatches...)
+			status.PriceMismatches = append(status.PriceMismatches, report.PriceMismatches...)
+			status.Warnings = append(status.Warnings, report.Warnings...)
+			fileValid = report.Valid()
 		}
+		for sku := range catalog.FindTemplateSKUs(templateContent) {
+			coveredSKUs[sku] = struct{}{}
+		}
+		status.Files = append(status.Files, TemplateFile{Name: file.Name, URL: file.HTMLURL, Valid: fileValid})
 
 		if fileValid {
 			anyValid = true
@@ -392,6 +444,9 @@ func (s *AdminService) buildTemplateStatus(ctx context.Context, client *githubap
 			firstInvalidURL = file.HTMLURL
 		}
 	}
+	if status.Count > 0 && yamlStatus.Valid {
+		status.MissingSKUs = templateMissingSKUs(config, coveredSKUs)
+	}
 
 	status.Exists = status.Count > 0
 	if firstInvalidURL != "" {
@@ -404,7 +459,7 @@ func (s *AdminService) buildTemplateStatus(ctx context.Context, client *githubap
 	}
 
 	sort.Strings(status.UnknownSKUs)
-	status.Valid = status.Exists && anyValid && len(status.UnknownSKUs) == 0 && len(status.PriceMismatches) == 0 && len(status.OptionMismatches) == 0
+	status.Valid = status.Exists && anyValid && len(status.UnknownSKUs) == 0 && len(status.MissingSKUs) == 0 && len(status.PriceMismatches) == 0 && len(status.OptionMismatches) == 0
 	status.SyncAvailable, status.SyncMessage = computeTemplateSyncAvailability(status.Exists, yamlStatus.Valid, status.UnknownSKUs)
 
 	if !yamlStatus.Valid && status.Exists {
@@ -414,6 +469,17 @@ func (s *AdminService) buildTemplateStatus(ctx context.Context, client *githubap
 	return status
 }
 
+func buildEmailTemplatesStatus(ctx context.Context, client *githubapp.Client, repoFullName string, access githubapp.ContentAccess) EmailTemplatesStatus {
+	templates, err := fetchRepoEmailTemplates(ctx, client, repoFullName, access)
+	if err != nil {
+		return EmailTemplatesStatus{ErrorMessage: err.Error()}
+	}
+	return EmailTemplatesStatus{
+		Files:  templates.Files,
+		Issues: templates.Issues,
+	}
+}
+
 func (s *AdminService) getGitShopFileStatus(ctx context.Context, client *githubapp.Client, repoFullName string) (*githubapp.FileStatus, string, error) {
 	for _, path := range []string{"gitshop.yaml", "gitshop.yml"} {
 		status, err := client.GetFileStatus(ctx, repoFullName, path)
@@ -443,307 +509,28 @@ func (s *AdminService) getGitShopFile(ctx context.Context, client *githubapp.Cli
 	return nil, fmt.Errorf("gitshop.yaml not found")
 }
 
-func findTemplateSKUs(template string) map[string]struct{} {
-	skus := make(map[string]struct{})
-	skuRegex := regexp.MustCompile(`(?i)SKU:([A-Z0-9_]+)`)
-	for _, line := range strings.Split(template, "\n") {
-		trimmed := strings.TrimSpace(line)
-		if !strings.HasPrefix(trimmed, "-") {
-			continue
-		}
-		if !strings.Contains(trimmed, "SKU:") {
-			continue
-		}
-		if match := skuRegex.FindStringSubmatch(trimmed); len(match) >= 2 {
-			skus[match[1]] = struct{}{}
-		}
-	}
-	return skus
+// templateMissingSKUs returns the active products that no order template lists, in config order.
+// productOrderURL links to a new issue from the named template with the product field
+// pre-filled.
+func productOrderURL(repoFullName, templateName, productLabel string) string {
+	query := url.Values{"template": {templateName}, "product": {productLabel}}
+	return "https://github.com/" + repoFullName + "/issues/new?" + query.Encode()
 }
 
-func findTemplatePriceMismatches(template string, config *catalog.GitShopConfig) []string {
-	mismatches := []string{}
+func templateMissingSKUs(config *catalog.GitShopConfig, covered map[string]struct{}) []string {
 	if config == nil {
-		return mismatches
-	}
-
-	productPrices := make(map[string]int)
-	for _, product := range config.Products {
-		productPrices[product.SKU] = product.UnitPriceCents
-	}
-
-	skuRegex := regexp.MustCompile(`(?i)SKU:([A-Z0-9_]+)`)
-	priceRegex := regexp.MustCompile(`\$([0-9]+(?:\.[0-9]{2})?)`)
-	for _, line := range strings.Split(template, "\n") {
-		trimmed := strings.TrimSpace(line)
-		if !strings.HasPrefix(trimmed, "-") || !strings.Contains(trimmed, "SKU:") || !strings.Contains(trimmed, "$") {
-			continue
-		}
-		skuMatch := skuRegex.FindStringSubmatch(trimmed)
-		priceMatch := priceRegex.FindStringSubmatch(trimmed)
-		if len(skuMatch) < 2 || len(priceMatch) < 2 {
-			continue
-		}
-		sku := skuMatch[1]
-		templateCents, err := parsePriceToCents(priceMatch[1])
-		if err != nil {
-			continue
-		}
-		if yamlCents, ok := productPrices[sku]; ok && yamlCents != templateCents {
-			mismatches = append(mismatches, fmt.Sprintf("%s ($%.2f vs $%.2f)", sku, float64(templateCents)/100, float64(yamlCents)/100))
-		}
-	}
-
-	return mismatches
-}
-
-type templateForm struct {
-	Labels []string        `yaml:"labels"`
-	Body   []templateField `yaml:"body"`
-}
-
-type templateField struct {
-	Type       string             `yaml:"type"`
-	ID         string             `yaml:"id"`
-	Attributes templateAttributes `yaml:"attributes"`
-}
-
-type templateAttributes struct {
-	Label   string `yaml:"label"`
-	Options []any  `yaml:"options"`
-}
-
-func findTemplateOptionMismatches(template string, config *catalog.GitShopConfig) []string {
-	mismatches := []string{}
-	if config == nil {
-		return mismatches
-	}
-
-	form := templateForm{}
-	if err := yaml.Unmarshal([]byte(template), &form); err != nil {
-		return mismatches
-	}
-
-	templateOptions := make(map[string]templateAttributes)
-	duplicateIDs := make(map[string]struct{})
-	for _, field := range form.Body {
-		if field.ID == "" {
-			continue
-		}
-		if _, exists := templateOptions[field.ID]; exists {
-			if _, reported := duplicateIDs[field.ID]; !reported {
-				mismatches = append(mismatches, fmt.Sprintf("duplicate option field id: %s", field.ID))
-				duplicateIDs[field.ID] = struct{}{}
-			}
-			continue
-		}
-		templateOptions[field.ID] = field.Attributes
-	}
-
-	templateSKUs := findTemplateSKUs(template)
-	selectedProducts := activeTemplateProducts(config, templateSKUs)
-	if len(selectedProducts) == 0 {
-		return mismatches
-	}
-
-	baseProduct := selectedProducts[0]
-	baseOptions := normalizedOptionsForTemplate(baseProduct)
-	for _, product := range selectedProducts[1:] {
-		if !templateOptionDefsEqual(baseOptions, normalizedOptionsForTemplate(product)) {
-			mismatches = append(mismatches, "template includes products with different option sets; split these products into separate order templates")
-			return mismatches
-		}
-	}
-
-	quantityValues := quantityValuesForProduct(baseProduct)
-	templateQuantity, hasQuantity := templateOptions["quantity"]
-	if !hasQuantity {
-		mismatches = append(mismatches, "missing option: quantity")
-	} else {
-		templateValues := filterTemplateOptionValues(optionValuesToStrings(templateQuantity.Options))
-		if len(templateValues) > 0 && !stringSlicesEqual(quantityValues, templateValues) {
-			mismatches = append(mismatches, fmt.Sprintf("values mismatch for quantity (template: %s, yaml: %s)", strings.Join(templateValues, ", "), strings.Join(quantityValues, ", ")))
-		}
-	}
-
-	for _, option := range baseProduct.Options {
-		if option.Name == "quantity" {
-			continue
-		}
-		templateAttr, ok := templateOptions[option.Name]
-		if !ok {
-			mismatches = append(mismatches, fmt.Sprintf("missing option: %s", option.Name))
-			continue
-		}
-
-		if option.Label != "" && templateAttr.Label != "" && option.Label != templateAttr.Label {
-			mismatches = append(mismatches, fmt.Sprintf("label mismatch for %s (%s vs %s)", option.Name, templateAttr.Label, option.Label))
-		}
-
-		if option.Type == "dropdown" {
-			yamlValues := optionValuesToStrings(option.Values)
-			templateValues := filterTemplateOptionValues(optionValuesToStrings(templateAttr.Options))
-			if !stringSlicesEqual(yamlValues, templateValues) {
-				mismatches = append(mismatches, fmt.Sprintf("values mismatch for %s (template: %s, yaml: %s)", option.Name, strings.Join(templateValues, ", "), strings.Join(yamlValues, ", ")))
-			}
-		}
-	}
-
-	return mismatches
-}
-
-func activeTemplateProducts(config *catalog.GitShopConfig, templateSKUs map[string]struct{}) []catalog.ProductConfig {
-	if config == nil || len(templateSKUs) == 0 {
 		return nil
 	}
-	selected := []catalog.ProductConfig{}
+	missing := []string{}
 	for _, product := range config.Products {
 		if !product.Active {
 			continue
 		}
-		if _, ok := templateSKUs[product.SKU]; ok {
-			selected = append(selected, product)
-		}
-	}
-	return selected
-}
-
-func normalizedOptionsForTemplate(product catalog.ProductConfig) []catalog.ProductOption {
-	options := []catalog.ProductOption{}
-	for _, option := range product.Options {
-		if option.Name == "quantity" {
-			continue
-		}
-		options = append(options, option)
-	}
-	return options
-}
-
-func templateOptionDefsEqual(a, b []catalog.ProductOption) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for idx := range a {
-		if a[idx].Name != b[idx].Name || a[idx].Label != b[idx].Label || a[idx].Type != b[idx].Type || a[idx].Required != b[idx].Required {
-			return false
-		}
-		if !stringSlicesEqual(optionValuesToStrings(a[idx].Values), optionValuesToStrings(b[idx].Values)) {
-			return false
-		}
-	}
-	return true
-}
-
-func quantityValuesForProduct(product catalog.ProductConfig) []string {
-	for _, option := range product.Options {
-		if option.Name != "quantity" {
-			continue
-		}
-		values := optionValuesToStrings(option.Values)
-		if len(values) > 0 {
-			return values
-		}
-	}
-	return []string{"1", "2", "3", "4", "5"}
-}
-
-func optionValuesToStrings(values any) []string {
-	converted := []string{}
-	switch v := values.(type) {
-	case []any:
-		for _, value := range v {
-			converted = append(converted, fmt.Sprintf("%v", value))
-		}
-	case []string:
-		converted = append(converted, v...)
-	}
-	return converted
-}
-
-func filterTemplateOptionValues(values []string) []string {
-	filtered := make([]string, 0, len(values))
-	for _, value := range values {
-		cleaned := strings.TrimSpace(value)
-		if strings.EqualFold(cleaned, "N/A") || strings.EqualFold(cleaned, "None") || strings.EqualFold(cleaned, "Select one") {
-			continue
-		}
-		filtered = append(filtered, cleaned)
-	}
-	return filtered
-}
-
-func stringSlicesEqual(a, b []string) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	counts := make(map[string]int)
-	for _, value := range a {
-		counts[value]++
-	}
-	for _, value := range b {
-		if counts[value] == 0 {
-			return false
-		}
-		counts[value]--
-	}
-	return true
-}
-
-func parsePriceToCents(price string) (int, error) {
-	parts := strings.Split(price, ".")
-	if len(parts) == 1 {
-		dollars, err := strconv.Atoi(parts[0])
-		if err != nil {
-			return 0, err
-		}
-		return dollars * 100, nil
-	}
-	if len(parts) != 2 {
-		return 0, fmt.Errorf("invalid price: %s", price)
-	}
-	dollars, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return 0, err
-	}
-	centsStr := parts[1]
-	if len(centsStr) == 1 {
-		centsStr += "0"
-	}
-	if len(centsStr) > 2 {
-		centsStr = centsStr[:2]
-	}
-	cents, err := strconv.Atoi(centsStr)
-	if err != nil {
-		return 0, err
-	}
-	return dollars*100 + cents, nil
-}
-
-func templateHasLabel(template, label string) bool {
-	form := templateForm{}
-	if err := yaml.Unmarshal([]byte(template), &form); err == nil {
-		for _, value := range form.Labels {
-			trimmed := strings.TrimSpace(value)
-			if trimmed == label {
-				return true
-			}
-		}
-		return false
-	}
-
-	re := regexp.MustCompile(`labels:\s*\[([^\]]+)\]`)
-	match := re.FindStringSubmatch(template)
-	if len(match) < 2 {
-		return false
-	}
-	values := strings.Split(match[1], ",")
-	for _, value := range values {
-		trimmed := strings.TrimSpace(strings.Trim(value, "\"'"))
-		if trimmed == label {
-			return true
+		if _, ok := covered[product.SKU]; !ok {
+			missing = append(missing, product.SKU)
 		}
 	}
-	return false
+	return missing
 }
 
 func computeTemplateSyncAvailability(templateExists, yamlValid bool, unknownSKUs []string) (bool, string) {
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	// statusCallTimeout bounds each GitHub call behind a status page, so one slow request shows
+	// up as a missing check instead of a stalled page.
+	statusCallTimeout = 5 * time.Second
+	// statusFetchConcurrency caps the template reads in flight for one page load.
+	statusFetchConcurrency = 4
+)
+
+type templateFileContent struct {
+	file        githubapp.RepoFile
+	content     string
+	lastUpdated time.Time
+}
+
+// runConcurrently calls each function in its own goroutine and waits for all of them.
+func runConcurrently(fns ...func()) {
+	var group errgroup.Group
+	for _, fn := range fns {
+		group.Go(func() error {
+			fn()
+			return nil
+		})
+	}
+	_ = group.Wait()
+}
+
+// fetchOrderTemplates reads the candidate files concurrently and returns the order templates
+// among them in their original order. Files that cannot be read are skipped.
+func fetchOrderTemplates(ctx context.Context, client *githubapp.Client, repoFullName string, files []githubapp.RepoFile) []templateFileContent {
+	results := make([]*templateFileContent, len(files))
+	var group errgroup.Group
+	group.SetLimit(statusFetchConcurrency)
+	for i, file := range files {
+		group.Go(func() error {
+			callCtx, cancel := context.WithTimeout(ctx, statusCallTimeout)
+			defer cancel()
+
+			content, err := client.GetFile(callCtx, repoFullName, file.Path, "")
+			if err != nil || !catalog.HasOrderTemplateMarker(string(content)) {
+				return nil
+			}
+			result := &templateFileContent{file: file, content: string(content)}
+			if fileStatus, err := client.GetFileStatus(callCtx, repoFullName, file.Path); err == nil && fileStatus != nil {
+				result.lastUpdated = fileStatus.LastUpdated
+			}
+			results[i] = result
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	templates := make([]templateFileContent, 0, len(files))
+	for _, result := range results {
+		if result != nil {
+			templates = append(templates, *result)
+		}
+	}
+	return templates
+}
+
+func recordStatusLatency(ctx context.Context, page string, started time.Time) {
+	observability.MeterFromContext(ctx).Distribution(
+		"admin.status.duration",
+		float64(time.Since(started).Milliseconds()),
+		sentry.WithUnit("millisecond"),
+		sentry.WithAttributes(attribute.String("page", page)),
+	)
+}
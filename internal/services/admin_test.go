@@ -7,266 +7,87 @@ import (
 
 	"github.com/google/uuid"
 
-	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
 )
 
-func TestFindTemplatePriceMismatches(t *testing.T) {
-	t.Parallel()
-
-	config := &catalog.GitShopConfig{
-		Products: []catalog.ProductConfig{
-			{
-				SKU:            "COFFEE_BEANS",
-				Name:           "Coffee Beans",
-				UnitPriceCents: 2000,
-				Active:         true,
-			},
-		},
-	}
-
-	template := `
-body:
-  - type: dropdown
-    id: product
-    attributes:
-      options:
-        - "Coffee Beans - $19.00 (SKU:COFFEE_BEANS)"
-`
-
-	mismatches := findTemplatePriceMismatches(template, config)
-	if len(mismatches) != 1 {
-		t.Fatalf("expected 1 mismatch, got %d (%v)", len(mismatches), mismatches)
-	}
-	if !strings.Contains(mismatches[0], "COFFEE_BEANS") {
-		t.Fatalf("expected mismatch to include SKU, got %q", mismatches[0])
-	}
-}
-
-func TestFindTemplateSKUs_AllowsLowercase(t *testing.T) {
+func TestAdminService_ShipOrder_InvalidInput(t *testing.T) {
 	t.Parallel()
 
-	template := `
-body:
-  - type: dropdown
-    id: product
-    attributes:
-      options:
-        - "GitShop Blend v2 — $16.00 (SKU:GITSHOP_BLEND_v1)"
-`
+	service := &AdminService{}
 
-	skus := findTemplateSKUs(template)
-	if _, ok := skus["GITSHOP_BLEND_v1"]; !ok {
-		t.Fatalf("expected lowercase SKU suffix to be preserved, got %v", skus)
+	err := service.ShipOrder(t.Context(), ShipOrderInput{})
+	if !errors.Is(err, ErrAdminInvalidShipmentInput) {
+		t.Fatalf("expected ErrAdminInvalidShipmentInput, got %v", err)
 	}
 }
 
-func TestFindTemplateOptionMismatches_DuplicateFieldID(t *testing.T) {
+func TestAdminService_ShipOrder_RequiresTrackingAndCarrier(t *testing.T) {
 	t.Parallel()
 
-	config := &catalog.GitShopConfig{
-		Products: []catalog.ProductConfig{
-			{
-				SKU:            "COFFEE_BLEND_V1",
-				Name:           "Coffee Blend V1",
-				UnitPriceCents: 1600,
-				Active:         true,
-				Options: []catalog.ProductOption{
-					{
-						Name:     "grind",
-						Label:    "Grind",
-						Type:     "dropdown",
-						Required: true,
-						Values:   []string{"Ground", "Whole Bean"},
-					},
-				},
-			},
-		},
-	}
-
-	template := `
-body:
-  - type: dropdown
-    id: product
-    attributes:
-      options:
-        - "Coffee Blend V1 — $16.00 (SKU:COFFEE_BLEND_V1)"
-  - type: dropdown
-    id: quantity
-    attributes:
-      options:
-        - "1"
-        - "2"
-        - "3"
-        - "4"
-        - "5"
-  - type: dropdown
-    id: grind
-    attributes:
-      label: Grind
-      options:
-        - Ground
-        - Whole Bean
-  - type: dropdown
-    id: grind
-    attributes:
-      label: Grind
-      options:
-        - Ground
-        - Whole Bean
-`
-
-	mismatches := findTemplateOptionMismatches(template, config)
-	if len(mismatches) == 0 {
-		t.Fatalf("expected mismatches, got none")
-	}
+	service := &AdminService{}
 
-	found := false
-	for _, mismatch := range mismatches {
-		if strings.Contains(mismatch, "duplicate option field id: grind") {
-			found = true
-			break
-		}
-	}
-	if !found {
-		t.Fatalf("expected duplicate field mismatch, got %v", mismatches)
+	err := service.ShipOrder(t.Context(), ShipOrderInput{
+		ShopID:  uuid.New(),
+		OrderID: uuid.New(),
+	})
+	if !errors.Is(err, ErrAdminInvalidShipmentInput) {
+		t.Fatalf("expected ErrAdminInvalidShipmentInput, got %v", err)
 	}
 }
 
-func TestFindTemplateOptionMismatches_AllowsAdditionalTemplateFields(t *testing.T) {
+func TestAdminService_SendTestEmail_RequiresProviderAndRecipient(t *testing.T) {
 	t.Parallel()
 
-	config := &catalog.GitShopConfig{
-		Products: []catalog.ProductConfig{
-			{
-				SKU:            "COFFEE_BLEND_V1",
-				Name:           "Coffee Blend V1",
-				UnitPriceCents: 1600,
-				Active:         true,
-				Options: []catalog.ProductOption{
-					{
-						Name:     "grind",
-						Label:    "Grind",
-						Type:     "dropdown",
-						Required: true,
-						Values:   []string{"Ground", "Whole Bean"},
-					},
-				},
-			},
-		},
-	}
-
-	template := `
-body:
-  - type: dropdown
-    id: product
-    attributes:
-      options:
-        - "Coffee Blend V1 — $16.00 (SKU:COFFEE_BLEND_V1)"
-  - type: dropdown
-    id: quantity
-    attributes:
-      options:
-        - "1"
-        - "2"
-        - "3"
-        - "4"
-        - "5"
-  - type: dropdown
-    id: grind
-    attributes:
-      label: Grind
-      options:
-        - Ground
-        - Whole Bean
-  - type: textarea
-    id: order_notes
-    attributes:
-      label: Notes
-`
-
-	mismatches := findTemplateOptionMismatches(template, config)
-	for _, mismatch := range mismatches {
-		if strings.Contains(mismatch, "unexpected option") {
-			t.Fatalf("did not expect unexpected-option mismatch, got %v", mismatches)
-		}
-	}
-}
-
-func TestTemplateHasLabel(t *testing.T) {
-	t.Parallel()
+	service := &AdminService{}
 
 	tests := []struct {
-		name     string
-		template string
-		label    string
-		want     bool
+		name string
+		shop *db.Shop
+		want string
 	}{
 		{
-			name: "inline label list",
-			template: `
-# gitshop:order-template
-labels: ["gitshop:order", "gitshop:status:pending-payment"]
-`,
-			label: "gitshop:order",
-			want:  true,
+			name: "no provider",
+			shop: &db.Shop{OwnerEmail: "owner@example.com"},
+			want: "Configure an email provider",
 		},
 		{
-			name: "block label list",
-			template: `
-# gitshop:order-template
-labels:
-  - gitshop:order
-  - gitshop:status:pending-payment
-`,
-			label: "gitshop:order",
-			want:  true,
-		},
-		{
-			name: "label missing",
-			template: `
-# gitshop:order-template
-labels:
-  - gitshop:status:pending-payment
-`,
-			label: "gitshop:order",
-			want:  false,
+			name: "no recipient",
+			shop: &db.Shop{EmailProvider: "resend"},
+			want: "No owner email",
 		},
 	}
 
 	for _, tt := range tests {
-		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := templateHasLabel(tt.template, tt.label)
-			if got != tt.want {
-				t.Fatalf("templateHasLabel() = %v, want %v", got, tt.want)
+			_, err := service.SendTestEmail(t.Context(), tt.shop)
+			var userErr UserError
+			if !errors.As(err, &userErr) || !strings.Contains(userErr.Message, tt.want) {
+				t.Fatalf("expected user error containing %q, got %v", tt.want, err)
 			}
 		})
 	}
 }
 
-func TestAdminService_ShipOrder_InvalidInput(t *testing.T) {
+func TestSampleOrderInfo(t *testing.T) {
 	t.Parallel()
 
-	service := &AdminService{}
-
-	err := service.ShipOrder(t.Context(), ShipOrderInput{})
-	if !errors.Is(err, ErrAdminInvalidShipmentInput) {
-		t.Fatalf("expected ErrAdminInvalidShipmentInput, got %v", err)
+	info := sampleOrderInfo(&db.Shop{GitHubRepoFullName: "octo/shop"}, "owner@example.com")
+	if info.CustomerEmail != "owner@example.com" {
+		t.Fatalf("expected recipient to be the customer email, got %q", info.CustomerEmail)
+	}
+	if info.ShopName != "octo/shop" || info.Quantity != 2 || info.Total != "$55.00" {
+		t.Fatalf("unexpected sample order info: %+v", info)
 	}
 }
 
-func TestAdminService_ShipOrder_RequiresTrackingAndCarrier(t *testing.T) {
+func TestProductOrderURL(t *testing.T) {
 	t.Parallel()
 
-	service := &AdminService{}
-
-	err := service.ShipOrder(t.Context(), ShipOrderInput{
-		ShopID:  uuid.New(),
-		OrderID: uuid.New(),
-	})
-	if !errors.Is(err, ErrAdminInvalidShipmentInput) {
-		t.Fatalf("expected ErrAdminInvalidShipmentInput, got %v", err)
+	got := productOrderURL("octo/shop", "order.yaml", "Mug — $15.00 (SKU:MUG)")
+	want := "https://github.com/octo/shop/issues/new?product=Mug+%E2%80%94+%2415.00+%28SKU%3AMUG%29&template=order.yaml"
+	if got != want {
+		t.Fatalf("productOrderURL() = %q, want %q", got, want)
 	}
 }
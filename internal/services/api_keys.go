@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+const (
+	// apiKeyTokenPrefix marks GitShop API tokens, so secret scanners and people can recognize them.
+	apiKeyTokenPrefix = "gsk_"
+	// apiKeyDisplayLength is how much of a token is kept to tell keys apart in settings.
+	apiKeyDisplayLength    = len(apiKeyTokenPrefix) + 6
+	apiKeyNameMaxLength    = 100
+	apiKeyMaxActivePerShop = 20
+)
+
+var (
+	ErrAPIKeyInvalid  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+type CreateAPIKeyInput struct {
+	ShopID       uuid.UUID
+	Name         string
+	CustomerData bool
+	CreatedBy    string
+}
+
+// ListAPIKeys returns the shop's active API keys, newest first.
+func (s *AdminService) ListAPIKeys(ctx context.Context, shopID uuid.UUID) ([]*db.APIKey, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	keys, err := s.shopStore.ListAPIKeys(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// CreateAPIKey makes a new key for the shop and returns its token. The token is not stored, so
+// this is the only time it can be shown.
+func (s *AdminService) CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (string, *db.APIKey, error) {
+	if s == nil || s.shopStore == nil {
+		return "", nil, ErrAdminServiceUnavailable
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return "", nil, UserError{Message: "Name the key after the script or app that will use it."}
+	}
+	if len(name) > apiKeyNameMaxLength {
+		return "", nil, UserError{Message: fmt.Sprintf("Keep the name under %d characters.", apiKeyNameMaxLength)}
+	}
+	existing, err := s.shopStore.ListAPIKeys(ctx, input.ShopID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	if len(existing) >= apiKeyMaxActivePerShop {
+		return "", nil, UserError{Message: fmt.Sprintf("A shop can have up to %d API keys. Revoke one you no longer use first.", apiKeyMaxActivePerShop)}
+	}
+
+	token, err := generateAPIKeyToken()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := &db.APIKey{
+		ShopID:       input.ShopID,
+		Name:         name,
+		TokenPrefix:  token[:apiKeyDisplayLength],
+		CustomerData: input.CustomerData,
+		CreatedBy:    input.CreatedBy,
+	}
+	if err := s.shopStore.CreateAPIKey(ctx, key, hashAPIKeyToken(token)); err != nil {
+		return "", nil, fmt.Errorf("failed to save api key: %w", err)
+	}
+	return token, key, nil
+}
+
+// RevokeAPIKey stops one of the shop's keys from working.
+func (s *AdminService) RevokeAPIKey(ctx context.Context, shopID, keyID uuid.UUID) error {
+	if s == nil || s.shopStore == nil {
+		return ErrAdminServiceUnavailable
+	}
+	err := s.shopStore.RevokeAPIKey(ctx, shopID, keyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the key a token belongs to and the shop it reads. Revoked keys and
+// keys of disconnected shops fail with ErrAPIKeyInvalid.
+func (s *AdminService) AuthenticateAPIKey(ctx context.Context, token string) (*db.APIKey, *db.Shop, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, nil, ErrAdminServiceUnavailable
+	}
+	if !strings.HasPrefix(token, apiKeyTokenPrefix) {
+		return nil, nil, ErrAPIKeyInvalid
+	}
+	key, err := s.shopStore.UseAPIKey(ctx, hashAPIKeyToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+	shop, err := s.shopStore.GetByID(ctx, key.ShopID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load api key shop: %w", err)
+	}
+	if !shop.IsConnected() {
+		return nil, nil, ErrAPIKeyInvalid
+	}
+	return key, shop, nil
+}
+
+func generateAPIKeyToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPIKeyToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
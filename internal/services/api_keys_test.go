@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestGenerateAPIKeyToken(t *testing.T) {
+	t.Parallel()
+
+	first, err := generateAPIKeyToken()
+	if err != nil {
+		t.Fatalf("generateAPIKeyToken() error = %v", err)
+	}
+	second, err := generateAPIKeyToken()
+	if err != nil {
+		t.Fatalf("generateAPIKeyToken() error = %v", err)
+	}
+	if !strings.HasPrefix(first, apiKeyTokenPrefix) || len(first) <= apiKeyDisplayLength {
+		t.Fatalf("token = %q, want %q prefix and more than %d characters", first, apiKeyTokenPrefix, apiKeyDisplayLength)
+	}
+	if first == second {
+		t.Fatal("expected distinct tokens")
+	}
+	if hashAPIKeyToken(first) == hashAPIKeyToken(second) || hashAPIKeyToken(first) != hashAPIKeyToken(first) {
+		t.Fatal("expected hashes to be stable per token and differ between tokens")
+	}
+}
+
+func TestAuthenticateAPIKey_RejectsForeignTokens(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{shopStore: &db.ShopStore{}}
+	for _, token := range []string{"", "ghp_abcdef", "gsk"} {
+		if _, _, err := service.AuthenticateAPIKey(context.Background(), token); !errors.Is(err, ErrAPIKeyInvalid) {
+			t.Fatalf("AuthenticateAPIKey(%q) error = %v, want ErrAPIKeyInvalid", token, err)
+		}
+	}
+}
+
+func TestCreateAPIKey_ValidatesName(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{shopStore: &db.ShopStore{}}
+	for _, name := range []string{"  ", strings.Repeat("a", apiKeyNameMaxLength+1)} {
+		_, _, err := service.CreateAPIKey(context.Background(), CreateAPIKeyInput{Name: name})
+		var userErr UserError
+		if !errors.As(err, &userErr) {
+			t.Fatalf("CreateAPIKey(%q) error = %v, want UserError", name, err)
+		}
+	}
+}
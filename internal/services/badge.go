@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+var ErrBadgeShopNotFound = errors.New("badge shop not found")
+
+// ShopBadgeStatus is what a README badge says about a shop.
+type ShopBadgeStatus string
+
+const (
+	ShopBadgeOpen     ShopBadgeStatus = "open"
+	ShopBadgeClosed   ShopBadgeStatus = "closed"
+	ShopBadgeChecking ShopBadgeStatus = "checking"
+)
+
+// ShopBadge is the public summary of a shop shown on its README badge.
+type ShopBadge struct {
+	Repo     string          `json:"repo"`
+	Status   ShopBadgeStatus `json:"status"`
+	Open     bool            `json:"open"`
+	Products int             `json:"products"`
+	// Branding is the shop's own look from gitshop.yaml, for widgets built on the JSON badge.
+	Branding *ShopBadgeBranding `json:"branding,omitempty"`
+}
+
+// ShopBadgeBranding is the public part of the shop's branding config.
+type ShopBadgeBranding struct {
+	LogoURL      string `json:"logo_url,omitempty"`
+	AccentColor  string `json:"accent_color,omitempty"`
+	SupportEmail string `json:"support_email,omitempty"`
+	ShopURL      string `json:"shop_url,omitempty"`
+}
+
+type badgeShopStore interface {
+	GetConnectedByRepoFullName(ctx context.Context, repoFullName string) (*db.Shop, error)
+}
+
+type badgeStatusSource interface {
+	Cached(ctx context.Context, shop *db.Shop) (RepoStatusSnapshot, bool)
+}
+
+// BadgeService answers public badge requests from cached storefront status, so anonymous README
+// views never reach GitHub.
+type BadgeService struct {
+	shopStore badgeShopStore
+	statuses  badgeStatusSource
+}
+
+func NewBadgeService(shopStore *db.ShopStore, statuses *RepoStatusService) *BadgeService {
+	return newBadgeService(shopStore, statuses)
+}
+
+func newBadgeService(shopStore badgeShopStore, statuses badgeStatusSource) *BadgeService {
+	return &BadgeService{shopStore: shopStore, statuses: statuses}
+}
+
+// Badge returns the badge for the connected shop in repoFullName.
+func (s *BadgeService) Badge(ctx context.Context, repoFullName string) (ShopBadge, error) {
+	shop, err := s.shopStore.GetConnectedByRepoFullName(ctx, repoFullName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ShopBadge{}, fmt.Errorf("%w: %s", ErrBadgeShopNotFound, repoFullName)
+	}
+	if err != nil {
+		return ShopBadge{}, fmt.Errorf("failed to load shop: %w", err)
+	}
+
+	badge := ShopBadge{Repo: shop.GitHubRepoFullName, Status: ShopBadgeChecking}
+	snapshot, ok := s.statuses.Cached(ctx, shop)
+	if !ok || snapshot.Status == nil {
+		return badge, nil
+	}
+	status := snapshot.Status
+	if !status.Branding.IsZero() {
+		badge.Branding = &ShopBadgeBranding{
+			LogoURL:      status.Branding.LogoURL,
+			AccentColor:  status.Branding.AccentColor,
+			SupportEmail: status.Branding.SupportEmail,
+			ShopURL:      status.Branding.ShopURL,
+		}
+	}
+	badge.Products = len(status.Products)
+	badge.Open = shop.IsOnboarded() && status.StripeReady && status.YAMLValid && status.TemplateValid && badge.Products > 0
+	badge.Status = ShopBadgeClosed
+	if badge.Open {
+		badge.Status = ShopBadgeOpen
+	}
+	return badge, nil
+}
+
+// Message is the right-hand text of the badge.
+func (b ShopBadge) Message() string {
+	switch b.Status {
+	case ShopBadgeOpen:
+		if b.Products == 1 {
+			return "open for orders · 1 product"
+		}
+		return fmt.Sprintf("open for orders · %d products", b.Products)
+	case ShopBadgeClosed:
+		return "closed"
+	default:
+		return "checking"
+	}
+}
+
+// SVG renders the badge in the flat style used by most README badges.
+func (b ShopBadge) SVG() []byte {
+	const label = "GitShop"
+	color := "#9f9f9f"
+	switch b.Status {
+	case ShopBadgeOpen:
+		color = "#2ea44f"
+		if b.Branding != nil && b.Branding.AccentColor != "" {
+			color = b.Branding.AccentColor
+		}
+	case ShopBadgeClosed:
+		color = "#cb2431"
+	}
+	message := b.Message()
+	labelWidth := badgeTextWidth(label)
+	messageWidth := badgeTextWidth(message)
+	width := labelWidth + messageWidth
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, width, label, html.EscapeString(message))
+	fmt.Fprintf(&svg, `<title>%s: %s</title>`, label, html.EscapeString(message))
+	svg.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&svg, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(&svg, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`, labelWidth, labelWidth, messageWidth, color, width)
+	svg.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&svg, `<text x="%d" y="14">%s</text>`, labelWidth/2, label)
+	fmt.Fprintf(&svg, `<text x="%d" y="14">%s</text>`, labelWidth+messageWidth/2, html.EscapeString(message))
+	svg.WriteString(`</g></svg>`)
+	return []byte(svg.String())
+}
+
+// badgeTextWidth estimates the width of text set in 11px Verdana, plus padding.
+func badgeTextWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+type fakeBadgeShopStore struct {
+	shop *db.Shop
+}
+
+func (s fakeBadgeShopStore) GetConnectedByRepoFullName(context.Context, string) (*db.Shop, error) {
+	if s.shop == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return s.shop, nil
+}
+
+type fakeBadgeStatusSource struct {
+	status *RepoStatus
+}
+
+func (s fakeBadgeStatusSource) Cached(context.Context, *db.Shop) (RepoStatusSnapshot, bool) {
+	if s.status == nil {
+		return RepoStatusSnapshot{}, false
+	}
+	return RepoStatusSnapshot{Status: s.status, CheckedAt: time.Now()}, true
+}
+
+func TestBadgeService_Badge(t *testing.T) {
+	t.Parallel()
+
+	onboarded := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop", OnboardedAt: time.Now()}
+	ready := &RepoStatus{
+		StripeReady:   true,
+		YAMLValid:     true,
+		TemplateValid: true,
+		Products:      []ProductSummary{{SKU: "TSHIRT"}, {SKU: "MUG"}},
+	}
+
+	tests := []struct {
+		name         string
+		shop         *db.Shop
+		status       *RepoStatus
+		wantErr      error
+		wantStatus   ShopBadgeStatus
+		wantProducts int
+		wantMessage  string
+	}{
+		{name: "unknown shop", wantErr: ErrBadgeShopNotFound},
+		{name: "no snapshot yet", shop: onboarded, wantStatus: ShopBadgeChecking, wantMessage: "checking"},
+		{name: "ready shop", shop: onboarded, status: ready, wantStatus: ShopBadgeOpen, wantProducts: 2, wantMessage: "open for orders · 2 products"},
+		{name: "stripe not ready", shop: onboarded, status: &RepoStatus{YAMLValid: true, TemplateValid: true, Products: ready.Products}, wantStatus: ShopBadgeClosed, wantProducts: 2, wantMessage: "closed"},
+		{name: "not onboarded", shop: &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}, status: ready, wantStatus: ShopBadgeClosed, wantProducts: 2, wantMessage: "closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := newBadgeService(fakeBadgeShopStore{shop: tt.shop}, fakeBadgeStatusSource{status: tt.status})
+			badge, err := service.Badge(t.Context(), "Octo/Shop")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Badge() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Badge() error = %v", err)
+			}
+			if badge.Status != tt.wantStatus || badge.Products != tt.wantProducts || badge.Open != (tt.wantStatus == ShopBadgeOpen) {
+				t.Fatalf("Badge() = %+v, want status %s with %d products", badge, tt.wantStatus, tt.wantProducts)
+			}
+			if got := badge.Message(); got != tt.wantMessage {
+				t.Fatalf("Message() = %q, want %q", got, tt.wantMessage)
+			}
+			if svg := string(badge.SVG()); !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, tt.wantMessage) {
+				t.Fatalf("SVG() missing message %q: %s", tt.wantMessage, svg)
+			}
+		})
+	}
+}
+
+func TestBadgeService_BadgeBranding(t *testing.T) {
+	t.Parallel()
+
+	shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop", OnboardedAt: time.Now()}
+	status := &RepoStatus{
+		StripeReady:   true,
+		YAMLValid:     true,
+		TemplateValid: true,
+		Products:      []ProductSummary{{SKU: "TSHIRT"}},
+		Branding:      catalog.BrandingConfig{AccentColor: "#ff6600", ShopURL: "https://octo.example"},
+	}
+
+	service := newBadgeService(fakeBadgeShopStore{shop: shop}, fakeBadgeStatusSource{status: status})
+	badge, err := service.Badge(t.Context(), "octo/shop")
+	if err != nil {
+		t.Fatalf("Badge() error = %v", err)
+	}
+	if badge.Branding == nil || badge.Branding.ShopURL != "https://octo.example" {
+		t.Fatalf("Badge().Branding = %+v, want shop URL", badge.Branding)
+	}
+	if svg := string(badge.SVG()); !strings.Contains(svg, `fill="#ff6600"`) {
+		t.Fatalf("SVG() does not use the accent color: %s", svg)
+	}
+
+	badge.Status = ShopBadgeClosed
+	if svg := string(badge.SVG()); strings.Contains(svg, `fill="#ff6600"`) {
+		t.Fatalf("closed SVG() uses the accent color: %s", svg)
+	}
+}
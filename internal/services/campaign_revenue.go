@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+// campaignRevenueWindow is how far back the dashboard reports campaign sales.
+const campaignRevenueWindow = 90 * 24 * time.Hour
+
+// CampaignRevenue is the shop's paid orders in the last 90 days, split by the gitshop.yaml
+// campaign that priced them. ListPrice holds the orders sold outside any campaign.
+type CampaignRevenue struct {
+	Since     time.Time
+	Campaigns []db.CampaignRevenue
+	ListPrice db.CampaignRevenue
+}
+
+// CampaignRevenue returns the shop's recent sales by campaign, or nil when no campaign priced a
+// paid order in the window.
+func (s *AdminService) CampaignRevenue(ctx context.Context, shopID uuid.UUID, now time.Time) (*CampaignRevenue, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	since := now.Add(-campaignRevenueWindow)
+	totals, err := s.orderStore.SummarizeCampaignRevenue(ctx, shopID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize campaign revenue: %w", err)
+	}
+	revenue := &CampaignRevenue{Since: since}
+	for _, total := range totals {
+		if total.Campaign == "" {
+			revenue.ListPrice = total
+			continue
+		}
+		revenue.Campaigns = append(revenue.Campaigns, total)
+	}
+	if len(revenue.Campaigns) == 0 {
+		return nil, nil
+	}
+	return revenue, nil
+}
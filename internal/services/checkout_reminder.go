@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	checkoutReminderLead      = 10 * time.Minute
+	checkoutReminderInterval  = time.Minute
+	checkoutReminderBatchSize = 100
+)
+
+type checkoutReminderOrderStore interface {
+	ClaimCheckoutReminders(ctx context.Context, region string, dueBefore time.Time, limit int) ([]db.CheckoutReminder, error)
+	GetByID(ctx context.Context, orderID uuid.UUID) (*db.Order, error)
+}
+
+type checkoutReminderShopStore interface {
+	GetByID(ctx context.Context, shopID uuid.UUID) (*db.Shop, error)
+}
+
+type reminderCommenter interface {
+	CreateComment(ctx context.Context, repoFullName string, issueNumber int, body string) error
+	GetFile(ctx context.Context, repoFullName, path, ref string) ([]byte, error)
+}
+
+// CheckoutReminderService reminds buyers on the order issue, and by email when the order has
+// their address and they did not opt out, shortly before an unpaid checkout link expires.
+// Reminders due during the shop's quiet hours are skipped rather than held, since the link
+// expires before the window ends.
+type CheckoutReminderService struct {
+	orderStore  checkoutReminderOrderStore
+	shopStore   checkoutReminderShopStore
+	comments    func(installationID int64) reminderCommenter
+	emailSender OrderEmailSender
+	region      string
+	logger      *slog.Logger
+}
+
+func NewCheckoutReminderService(orderStore *db.OrderStore, shopStore *db.ShopStore, githubClient *githubapp.Client, emailSender OrderEmailSender, region string, logger *slog.Logger) *CheckoutReminderService {
+	var comments func(int64) reminderCommenter
+	if githubClient != nil {
+		comments = func(installationID int64) reminderCommenter {
+			return githubClient.WithInstallation(installationID)
+		}
+	}
+	return newCheckoutReminderService(orderStore, shopStore, comments, emailSender, region, logger)
+}
+
+func newCheckoutReminderService(orderStore checkoutReminderOrderStore, shopStore checkoutReminderShopStore, comments func(int64) reminderCommenter, emailSender OrderEmailSender, region string, logger *slog.Logger) *CheckoutReminderService {
+	if emailSender == nil {
+		emailSender = noopOrderEmailSender{}
+	}
+	return &CheckoutReminderService{
+		orderStore:  orderStore,
+		shopStore:   shopStore,
+		comments:    comments,
+		emailSender: emailSender,
+		region:      region,
+		logger:      logger,
+	}
+}
+
+// Run sends due reminders every minute until ctx is cancelled.
+func (s *CheckoutReminderService) Run(ctx context.Context) {
+	if s == nil || s.orderStore == nil || s.comments == nil {
+		return
+	}
+
+	ticker := time.NewTicker(checkoutReminderInterval)
+	defer ticker.Stop()
+	for {
+		sent, err := s.SendDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to send checkout reminders", "error", err)
+		} else if sent > 0 {
+			s.logger.Info("sent checkout reminders", "orders", sent)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue reminds the buyer of every order whose checkout link expires within
+// checkoutReminderLead of now and returns how many were reminded. Each order is claimed before
+// it is reminded, so a failed comment is not retried.
+func (s *CheckoutReminderService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		reminders, err := s.orderStore.ClaimCheckoutReminders(ctx, s.region, now.Add(checkoutReminderLead), checkoutReminderBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("failed to claim checkout reminders: %w", err)
+		}
+		for _, reminder := range reminders {
+			if s.remind(ctx, reminder, now) {
+				total++
+			}
+		}
+		if len(reminders) < checkoutReminderBatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+func (s *CheckoutReminderService) remind(ctx context.Context, reminder db.CheckoutReminder, now time.Time) bool {
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("checkout.reminder.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	logger := s.logger.With("order_id", reminder.OrderID)
+
+	order, err := s.orderStore.GetByID(ctx, reminder.OrderID)
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		logger.Error("failed to load order for checkout reminder", "error", err)
+		return false
+	}
+	shop, err := s.shopStore.GetByID(ctx, order.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		logger.Error("failed to load shop for checkout reminder", "error", err)
+		return false
+	}
+
+	comments := s.comments(shop.GitHubInstallationID)
+	config := shopConfig(ctx, comments, shop.GitHubRepoFullName)
+	if config != nil && config.Shop.Checkout.QuietHours.Contains(now) {
+		meter.Count("checkout.reminder.skipped", 1, sentry.WithAttributes(
+			attribute.String("reason", "quiet_hours"),
+		))
+		logger.Info("skipped checkout reminder during quiet hours")
+		return false
+	}
+
+	remaining := max(reminder.ExpiresAt.Sub(now), time.Minute)
+	comment := checkoutReminderComment(configLocalizer(config), order.OrderNumber, remaining)
+	if err := comments.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, comment); err != nil {
+		recordFailed("comment_failed")
+		logger.Error("failed to post checkout reminder", "error", err, "repo", shop.GitHubRepoFullName)
+		return false
+	}
+
+	if strings.TrimSpace(order.CustomerEmail) != "" && !order.CheckoutReminderOptOut {
+		if err := s.emailSender.SendCheckoutReminder(ctx, shop, order); err != nil {
+			logger.Warn("failed to email checkout reminder", "error", err)
+		}
+	}
+
+	meter.Count("checkout.reminder.sent", 1)
+	return true
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+type fakeCheckoutReminderStore struct {
+	reminders []db.CheckoutReminder
+	orders    map[uuid.UUID]*db.Order
+	dueBefore time.Time
+}
+
+func (s *fakeCheckoutReminderStore) ClaimCheckoutReminders(_ context.Context, _ string, dueBefore time.Time, _ int) ([]db.CheckoutReminder, error) {
+	s.dueBefore = dueBefore
+	claimed := s.reminders
+	s.reminders = nil
+	return claimed, nil
+}
+
+func (s *fakeCheckoutReminderStore) GetByID(_ context.Context, orderID uuid.UUID) (*db.Order, error) {
+	return s.orders[orderID], nil
+}
+
+type fakeReminderShopStore struct {
+	shop *db.Shop
+}
+
+func (s fakeReminderShopStore) GetByID(context.Context, uuid.UUID) (*db.Shop, error) {
+	return s.shop, nil
+}
+
+type capturingReminderCommenter struct {
+	bodies []string
+	config string
+}
+
+func (c *capturingReminderCommenter) CreateComment(_ context.Context, _ string, _ int, body string) error {
+	c.bodies = append(c.bodies, body)
+	return nil
+}
+
+func (c *capturingReminderCommenter) GetFile(_ context.Context, _, path, _ string) ([]byte, error) {
+	if path == "gitshop.yaml" && c.config != "" {
+		return []byte(c.config), nil
+	}
+	return nil, fmt.Errorf("%s not found", path)
+}
+
+func TestCheckoutReminderService_SendDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		email       string
+		optOut      bool
+		expiresAt   time.Time
+		wantComment string
+		wantEmails  int
+	}{
+		{
+			name:        "buyer with email",
+			email:       "buyer@example.com",
+			expiresAt:   now.Add(8 * time.Minute),
+			wantComment: "expires in 8 minutes",
+			wantEmails:  1,
+		},
+		{
+			name:        "buyer opted out of the email",
+			email:       "buyer@example.com",
+			optOut:      true,
+			expiresAt:   now.Add(8 * time.Minute),
+			wantComment: "expires in 8 minutes",
+		},
+		{
+			name:        "buyer without email",
+			expiresAt:   now.Add(10 * time.Minute),
+			wantComment: "expires in 10 minutes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop", EmailProvider: "postmark"}
+			order := &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 7, GitHubIssueNumber: 42, CustomerEmail: tt.email, CheckoutReminderOptOut: tt.optOut}
+			store := &fakeCheckoutReminderStore{
+				reminders: []db.CheckoutReminder{{OrderID: order.ID, ExpiresAt: tt.expiresAt}},
+				orders:    map[uuid.UUID]*db.Order{order.ID: order},
+			}
+			commenter := &capturingReminderCommenter{}
+			provider := &capturingEmailProvider{}
+			sender := NewShopOrderEmailSender(func(*db.Shop) (email.Provider, error) {
+				return provider, nil
+			}, nil, "")
+			service := newCheckoutReminderService(store, fakeReminderShopStore{shop: shop}, func(int64) reminderCommenter { return commenter }, sender, "", slog.Default())
+
+			sent, err := service.SendDue(t.Context(), now)
+			if err != nil {
+				t.Fatalf("SendDue() error = %v", err)
+			}
+			if sent != 1 {
+				t.Fatalf("SendDue() = %d, want 1", sent)
+			}
+			if !store.dueBefore.Equal(now.Add(checkoutReminderLead)) {
+				t.Fatalf("claimed reminders due before %v, want %v", store.dueBefore, now.Add(checkoutReminderLead))
+			}
+			if len(commenter.bodies) != 1 || !strings.Contains(commenter.bodies[0], tt.wantComment) {
+				t.Fatalf("comments = %q, want one containing %q", commenter.bodies, tt.wantComment)
+			}
+			if len(provider.sent) != tt.wantEmails {
+				t.Fatalf("emails sent = %d, want %d", len(provider.sent), tt.wantEmails)
+			}
+			if tt.wantEmails > 0 && provider.sent[0].To != tt.email {
+				t.Fatalf("email sent to %q, want %q", provider.sent[0].To, tt.email)
+			}
+		})
+	}
+}
+
+func TestCheckoutReminderService_SendDueSkipsQuietHours(t *testing.T) {
+	t.Parallel()
+
+	config := `
+shop:
+  name: "Test Shop"
+  checkout:
+    quiet_hours:
+      start: "22:00"
+      end: "08:00"
+      timezone: "Europe/Berlin"
+`
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantSent int
+	}{
+		{name: "inside quiet hours", now: time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC), wantSent: 0},
+		{name: "outside quiet hours", now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), wantSent: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop", EmailProvider: "postmark"}
+			order := &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 7, GitHubIssueNumber: 42, CustomerEmail: "buyer@example.com"}
+			store := &fakeCheckoutReminderStore{
+				reminders: []db.CheckoutReminder{{OrderID: order.ID, ExpiresAt: tt.now.Add(8 * time.Minute)}},
+				orders:    map[uuid.UUID]*db.Order{order.ID: order},
+			}
+			commenter := &capturingReminderCommenter{config: config}
+			provider := &capturingEmailProvider{}
+			sender := NewShopOrderEmailSender(func(*db.Shop) (email.Provider, error) {
+				return provider, nil
+			}, nil, "")
+			service := newCheckoutReminderService(store, fakeReminderShopStore{shop: shop}, func(int64) reminderCommenter { return commenter }, sender, "", slog.Default())
+
+			sent, err := service.SendDue(t.Context(), tt.now)
+			if err != nil {
+				t.Fatalf("SendDue() error = %v", err)
+			}
+			if sent != tt.wantSent || len(commenter.bodies) != tt.wantSent || len(provider.sent) != tt.wantSent {
+				t.Fatalf("sent = %d, comments = %d, emails = %d, want %d of each", sent, len(commenter.bodies), len(provider.sent), tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const configIssueTitle = "GitShop can't read this shop's configuration"
+
+// touchesShopConfig reports whether a pushed path is gitshop.yaml or an issue template.
+func touchesShopConfig(file string) bool {
+	return file == "gitshop.yaml" || file == "gitshop.yml" || path.Dir(file) == githubapp.IssueTemplateDir
+}
+
+// checkConfigDrift re-checks gitshop.yaml and the order templates after a push changed them, and
+// keeps a single tracking issue in the repository open while they are broken. The issue is
+// updated with each push that leaves them broken and closed by the push that fixes them.
+func (s *RepositoryService) checkConfigDrift(ctx context.Context, shop *db.Shop) error {
+	if s.githubClient == nil {
+		return nil
+	}
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+
+	configName := "gitshop.yaml"
+	content, err := client.GetFile(ctx, shop.GitHubRepoFullName, configName, "")
+	if err != nil && githubapp.IsNotFound(err) {
+		configName = "gitshop.yml"
+		content, err = client.GetFile(ctx, shop.GitHubRepoFullName, configName, "")
+	}
+	if err != nil && !githubapp.IsNotFound(err) {
+		return fmt.Errorf("failed to read gitshop.yaml: %w", err)
+	}
+
+	access := contentAccess(ctx, client, s.logger)
+	templates, err := readOrderTemplates(ctx, client, shop.GitHubRepoFullName, access)
+	if err != nil {
+		return fmt.Errorf("failed to read order templates: %w", err)
+	}
+
+	problems := shopConfigProblems(configName, content, templates.templates)
+	return s.syncConfigIssue(ctx, client, shop, problems)
+}
+
+// shopConfigProblems lists what keeps the shop from taking orders, prefixed with the file to
+// fix. content is nil when the repository has no gitshop.yaml.
+func shopConfigProblems(configName string, content []byte, templates []repoOrderTemplate) []string {
+	if content == nil {
+		return []string{"gitshop.yaml: not found on the default branch; GitShop cannot accept orders without it"}
+	}
+	config, err := catalog.NewParser().Parse(content)
+	if err != nil {
+		return []string{configName + ": " + err.Error()}
+	}
+	if err := catalog.NewValidator().Validate(config); err != nil {
+		return []string{configName + ": " + err.Error()}
+	}
+
+	if len(templates) == 0 {
+		return []string{githubapp.IssueTemplateDir + ": no order template found; order templates start with \"# gitshop:order-template\""}
+	}
+	problems := []string{}
+	for _, template := range templates {
+		report := catalog.CheckOrderTemplate(template.content, config)
+		if report.Valid() {
+			continue
+		}
+		for _, problem := range report.Problems() {
+			problems = append(problems, template.file.Path+": "+problem)
+		}
+	}
+	return problems
+}
+
+func configIssueBody(problems []string) string {
+	var body strings.Builder
+	body.WriteString("⚠️ The latest push left this shop's configuration with errors, so new orders may be rejected or priced wrong:\n\n")
+	for _, problem := range problems {
+		body.WriteString("- " + problem + "\n")
+	}
+	body.WriteString("\nFix these on the default branch, or sync the templates from the GitShop dashboard. GitShop checks again on every push that touches `gitshop.yaml` or `" + githubapp.IssueTemplateDir + "` and closes this issue once everything is valid.")
+	return body.String()
+}
+
+func (s *RepositoryService) syncConfigIssue(ctx context.Context, client *githubapp.Client, shop *db.Shop, problems []string) error {
+	meter := observability.MeterFromContext(ctx)
+	number, err := s.shopStore.GetConfigIssueNumber(ctx, shop.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load config issue: %w", err)
+	}
+
+	if len(problems) == 0 {
+		if number == 0 {
+			return nil
+		}
+		if err := client.CreateComment(ctx, shop.GitHubRepoFullName, number, "✅ The latest push fixed the configuration. Closing this issue."); err != nil {
+			s.loggerFromContext(ctx).Warn("failed to comment on config issue", "error", err, "repo", shop.GitHubRepoFullName, "issue", number)
+		}
+		if err := client.CloseIssue(ctx, shop.GitHubRepoFullName, number); err != nil {
+			return err
+		}
+		meter.Count("repository.config_drift.resolved", 1)
+		return s.shopStore.SetConfigIssueNumber(ctx, shop.ID, 0)
+	}
+
+	meter.Count("repository.config_drift.detected", 1)
+	body := configIssueBody(problems)
+	if number != 0 {
+		return client.UpdateIssue(ctx, shop.GitHubRepoFullName, number, configIssueTitle, body)
+	}
+	number, err = client.CreateIssueWithNumber(ctx, shop.GitHubRepoFullName, configIssueTitle, body, []string{"gitshop-internal"})
+	if err != nil {
+		return err
+	}
+	s.loggerFromContext(ctx).Info("opened config issue", "repo", shop.GitHubRepoFullName, "issue", number, "problems", len(problems))
+	return s.shopStore.SetConfigIssueNumber(ctx, shop.ID, number)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+)
+
+const driftTestConfig = `version: 1
+shop:
+  name: Test Shop
+  currency: usd
+  shipping:
+    flat_rate_cents: 500
+    carrier: USPS
+products:
+  - sku: TSHIRT
+    name: T-Shirt
+    unit_price_cents: 2500
+    active: true
+`
+
+const driftTestTemplate = `# gitshop:order-template
+name: Order
+labels: ["gitshop:order"]
+body:
+  - type: dropdown
+    id: product
+    attributes:
+      label: Product
+      options:
+        - "T-Shirt - $25.00 (SKU:TSHIRT)"
+  - type: input
+    id: quantity
+    attributes:
+      label: Quantity
+`
+
+func TestShopConfigProblems(t *testing.T) {
+	t.Parallel()
+
+	orderTemplate := func(content string) []repoOrderTemplate {
+		return []repoOrderTemplate{{file: githubapp.RepoFile{Path: ".github/ISSUE_TEMPLATE/order.yaml"}, content: content}}
+	}
+
+	tests := []struct {
+		name      string
+		content   []byte
+		templates []repoOrderTemplate
+		want      []string
+	}{
+		{name: "valid", content: []byte(driftTestConfig), templates: orderTemplate(driftTestTemplate)},
+		{name: "missing config", want: []string{"gitshop.yaml: not found"}},
+		{name: "invalid yaml", content: []byte("products: [\n"), templates: orderTemplate(driftTestTemplate), want: []string{"gitshop.yaml: "}},
+		{name: "no order template", content: []byte(driftTestConfig), want: []string{".github/ISSUE_TEMPLATE: no order template"}},
+		{
+			name:      "template drifted from config",
+			content:   []byte(driftTestConfig),
+			templates: orderTemplate(strings.ReplaceAll(driftTestTemplate, "SKU:TSHIRT", "SKU:HOODIE")),
+			want:      []string{".github/ISSUE_TEMPLATE/order.yaml: SKU HOODIE is not in gitshop.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := shopConfigProblems("gitshop.yaml", tt.content, tt.templates)
+			if len(tt.want) == 0 {
+				if len(got) != 0 {
+					t.Fatalf("expected no problems, got %v", got)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				found := false
+				for _, problem := range got {
+					found = found || strings.HasPrefix(problem, want)
+				}
+				if !found {
+					t.Errorf("expected a problem starting with %q, got %v", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTouchesShopConfig(t *testing.T) {
+	t.Parallel()
+
+	for file, want := range map[string]bool{
+		"gitshop.yaml":                       true,
+		"gitshop.yml":                        true,
+		".github/ISSUE_TEMPLATE/order.yaml":  true,
+		".github/ISSUE_TEMPLATE/config.yml":  true,
+		"README.md":                          false,
+		"docs/gitshop.yaml":                  false,
+		".github/workflows/ci.yml":           false,
+		".github/ISSUE_TEMPLATE/old/bug.yml": false,
+	} {
+		if got := touchesShopConfig(file); got != want {
+			t.Errorf("touchesShopConfig(%q) = %v, want %v", file, got, want)
+		}
+	}
+}
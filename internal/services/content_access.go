@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/logging"
+)
+
+// contentAccess returns which files the installation may write. When the lookup fails GitShop
+// assumes full access, so GitHub's own permission errors are shown instead of a guess.
+func contentAccess(ctx context.Context, client *githubapp.Client, logger *slog.Logger) githubapp.ContentAccess {
+	access, err := client.ContentAccess(ctx)
+	if err != nil {
+		logging.FromContext(ctx, logger).Warn("failed to look up github content access", "error", err)
+		return githubapp.ContentAccess{Mode: githubapp.ContentAccessFull}
+	}
+	return access
+}
+
+// listRepoDirectory lists dir, or the granted files in it when the installation only has
+// single file access and cannot list directories.
+func listRepoDirectory(ctx context.Context, client *githubapp.Client, repoFullName, dir string, access githubapp.ContentAccess) ([]githubapp.RepoFile, error) {
+	if access.SingleFile() {
+		return access.FilesIn(repoFullName, dir), nil
+	}
+	return client.ListDirectory(ctx, repoFullName, dir)
+}
+
+func yamlAccessNotice(access githubapp.ContentAccess) string {
+	if !access.SingleFile() || access.CanWrite("gitshop.yaml") || access.CanWrite("gitshop.yml") {
+		return ""
+	}
+	return "GitShop has single-file access and gitshop.yaml is not one of the granted files. Add it to the app's file paths in your installation settings, or commit it yourself."
+}
+
+func templateAccessNotice(access githubapp.ContentAccess) string {
+	if !access.SingleFile() {
+		return ""
+	}
+	if access.OrderTemplatePath() == "" {
+		return "GitShop has single-file access and no order template is one of the granted files. Add " + githubapp.OrderTemplatePath + " to the app's file paths in your installation settings, or commit the template yourself."
+	}
+	templates := []string{}
+	for _, file := range access.FilesIn("", githubapp.IssueTemplateDir) {
+		templates = append(templates, file.Path)
+	}
+	return "GitShop has single-file access, so it only checks and syncs " + strings.Join(templates, ", ") + ". Changes are committed directly; if your default branch is protected, apply them yourself."
+}
+
+// contentAccessUserError turns a missing permission into a message the seller can act on.
+func contentAccessUserError(err error, file string) error {
+	if !errors.Is(err, githubapp.ErrContentAccessDenied) {
+		return err
+	}
+	return UserError{Message: "GitShop can't write " + file + " with its current GitHub permissions. Protected branches need Contents write access so GitShop can open a pull request; otherwise commit the change yourself."}
+}
+
+// placeTemplateFiles moves new order templates onto granted paths when the installation has
+// single file access. Paths in taken already hold a template and are never reused.
+func placeTemplateFiles(files []catalog.TemplateFile, access githubapp.ContentAccess, taken []string) ([]catalog.TemplateFile, error) {
+	if !access.SingleFile() || len(files) == 0 {
+		return files, nil
+	}
+
+	free := []string{}
+	for _, file := range filterTemplateFiles(access.FilesIn("", githubapp.IssueTemplateDir)) {
+		if !slices.Contains(taken, file.Path) {
+			free = append(free, file.Path)
+		}
+	}
+	if len(free) < len(files) {
+		return nil, UserError{Message: fmt.Sprintf("Your products need %d more order templates, but GitShop has single-file access to %d unused template paths. Grant more files under %s in your installation settings, or commit the templates yourself.", len(files), len(free), githubapp.IssueTemplateDir)}
+	}
+
+	placed := make([]catalog.TemplateFile, 0, len(files))
+	for i, file := range files {
+		file.Path = free[i]
+		placed = append(placed, file)
+	}
+	return placed, nil
+}
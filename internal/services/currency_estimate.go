@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/fx"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// chargeCurrency is the currency every order is charged in.
+const chargeCurrency = "usd"
+
+// takeEstimateCurrency removes the estimate currency answer from the parsed options and returns
+// it when it is one the shop offers.
+func takeEstimateCurrency(options map[string]any, checkout catalog.CheckoutConfig) string {
+	return checkout.EstimateCurrency(takeTextAnswer(options, catalog.CurrencyFieldID, catalog.CurrencyFieldLabel))
+}
+
+// orderTotalEstimate is the order total in the buyer's chosen currency, such as "23.10 EUR", or
+// "" when the buyer chose none or no rate is available. Estimates are a courtesy, so a rate
+// source that cannot be reached only leaves them out.
+func orderTotalEstimate(ctx context.Context, rates fx.RateSource, order *db.Order) string {
+	if rates == nil || order == nil || order.DisplayCurrency == "" {
+		return ""
+	}
+	estimate, err := fx.Estimate(ctx, rates, order.TotalCents, chargeCurrency, order.DisplayCurrency)
+	if err != nil {
+		observability.MeterFromContext(ctx).Count("fx.estimate.failed", 1)
+		logging.FromContext(ctx, nil).Warn("failed to estimate order total", "error", err, "order_id", order.ID, "currency", order.DisplayCurrency)
+		return ""
+	}
+	return estimate
+}
+
+// currencyEstimateNote tells the buyer what the order total is worth in their currency and that
+// the charge is still in the shop's.
+func currencyEstimateNote(loc i18n.Localizer, estimate string, totalCents int64) string {
+	return loc.T("comment.currency_estimate", estimate, money.Format(totalCents)+" USD")
+}
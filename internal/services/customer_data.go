@@ -0,0 +1,293 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const dataErasureListLimit = 20
+
+var ErrAdminCustomerNotFound = errors.New("no orders for customer")
+
+var githubUsernamePattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,38})$`)
+
+// CustomerSubject identifies the customer a data request is about, by email address or GitHub
+// username. Value is lowercased.
+type CustomerSubject struct {
+	Kind  db.CustomerSubjectKind
+	Value string
+}
+
+// ParseCustomerSubject reads an email address or GitHub username, with or without a leading @.
+func ParseCustomerSubject(input string) (CustomerSubject, error) {
+	value := strings.ToLower(strings.TrimSpace(input))
+	if value == "" {
+		return CustomerSubject{}, UserError{Message: "Enter the customer's email address or GitHub username"}
+	}
+	if strings.HasPrefix(value, "@") {
+		value = strings.TrimPrefix(value, "@")
+	} else if strings.Contains(value, "@") {
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return CustomerSubject{}, UserError{Message: "Enter a valid email address"}
+		}
+		return CustomerSubject{Kind: db.CustomerSubjectEmail, Value: value}, nil
+	}
+	if !githubUsernamePattern.MatchString(value) {
+		return CustomerSubject{}, UserError{Message: "Enter a valid GitHub username"}
+	}
+	return CustomerSubject{Kind: db.CustomerSubjectGitHubUsername, Value: value}, nil
+}
+
+func (s CustomerSubject) hash() string {
+	sum := sha256.Sum256([]byte(string(s.Kind) + ":" + s.Value))
+	return hex.EncodeToString(sum[:])
+}
+
+// CustomerDataExport is everything a shop holds about one customer.
+type CustomerDataExport struct {
+	Shop        string                `json:"shop"`
+	Subject     string                `json:"subject"`
+	SubjectKind string                `json:"subject_kind"`
+	Category    string                `json:"category,omitempty"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	Orders      []CustomerOrderRecord `json:"orders"`
+}
+
+type CustomerOrderRecord struct {
+	OrderNumber     int                   `json:"order_number"`
+	IssueURL        string                `json:"issue_url"`
+	GitHubUsername  string                `json:"github_username"`
+	Email           string                `json:"email,omitempty"`
+	Name            string                `json:"name,omitempty"`
+	ShippingAddress map[string]any        `json:"shipping_address,omitempty"`
+	SKU             string                `json:"sku"`
+	Category        string                `json:"category,omitempty"`
+	Options         map[string]any        `json:"options,omitempty"`
+	CustomFields    []db.OrderCustomField `json:"custom_fields,omitempty"`
+	TaxIDType       string                `json:"tax_id_type,omitempty"`
+	TaxID           string                `json:"tax_id,omitempty"`
+	TotalCents      int64                 `json:"total_cents"`
+	Status          string                `json:"status"`
+	TrackingNumber  string                `json:"tracking_number,omitempty"`
+	Carrier         string                `json:"carrier,omitempty"`
+	ReceiptURL      string                `json:"receipt_url,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	PaidAt          *time.Time            `json:"paid_at,omitempty"`
+	ShippedAt       *time.Time            `json:"shipped_at,omitempty"`
+	DeliveredAt     *time.Time            `json:"delivered_at,omitempty"`
+	RefundedAt      *time.Time            `json:"refunded_at,omitempty"`
+	Emails          []CustomerEmailRecord `json:"emails,omitempty"`
+}
+
+type CustomerEmailRecord struct {
+	Kind      string    `json:"kind"`
+	Recipient string    `json:"recipient,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+// ExportCustomerData collects every order the customer placed in the shop, with the emails sent
+// about them. A non-empty category limits the export to orders for products in that category.
+func (s *AdminService) ExportCustomerData(ctx context.Context, shop *db.Shop, input, category string) (*CustomerDataExport, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, fmt.Errorf("%w: order store unavailable", ErrAdminServiceUnavailable)
+	}
+	if shop == nil {
+		return nil, fmt.Errorf("%w: shop is required", ErrAdminShopNotFound)
+	}
+	subject, err := ParseCustomerSubject(input)
+	if err != nil {
+		return nil, err
+	}
+
+	category = strings.TrimSpace(category)
+	orders, err := s.orderStore.ListCustomerOrders(ctx, shop.ID, subject.Kind, subject.Value, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load customer orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAdminCustomerNotFound, subject.Kind)
+	}
+
+	export := &CustomerDataExport{
+		Shop:        shop.GitHubRepoFullName,
+		Subject:     subject.Value,
+		SubjectKind: string(subject.Kind),
+		Category:    category,
+		GeneratedAt: time.Now().UTC(),
+		Orders:      make([]CustomerOrderRecord, 0, len(orders)),
+	}
+	for _, order := range orders {
+		emails, err := s.orderStore.ListEmails(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load order emails: %w", err)
+		}
+		export.Orders = append(export.Orders, customerOrderRecord(order, emails))
+	}
+	observability.MeterFromContext(ctx).Count("customer_data.exported", 1)
+	return export, nil
+}
+
+// EraseCustomerData anonymizes every order the customer placed in the shop and records the
+// erasure in the audit log, even when no orders matched. It returns how many orders changed.
+func (s *AdminService) EraseCustomerData(ctx context.Context, shopID uuid.UUID, input, erasedBy string) (int, error) {
+	if s == nil || s.orderStore == nil {
+		return 0, fmt.Errorf("%w: order store unavailable", ErrAdminServiceUnavailable)
+	}
+	subject, err := ParseCustomerSubject(input)
+	if err != nil {
+		return 0, err
+	}
+
+	erased, err := s.orderStore.EraseCustomer(ctx, shopID, subject.Kind, subject.Value, subject.hash(), erasedBy)
+	if err != nil {
+		return 0, fmt.Errorf("failed to erase customer data: %w", err)
+	}
+	observability.MeterFromContext(ctx).Count("customer_data.erased", 1)
+	return erased, nil
+}
+
+// ListDataErasures returns the shop's recent erasure audit records.
+func (s *AdminService) ListDataErasures(ctx context.Context, shopID uuid.UUID) ([]*db.DataErasure, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, fmt.Errorf("%w: order store unavailable", ErrAdminServiceUnavailable)
+	}
+	return s.orderStore.ListDataErasures(ctx, shopID, dataErasureListLimit)
+}
+
+func customerOrderRecord(order *db.Order, emails []*db.OrderEmail) CustomerOrderRecord {
+	record := CustomerOrderRecord{
+		OrderNumber:     order.OrderNumber,
+		IssueURL:        order.GitHubIssueURL,
+		GitHubUsername:  order.GitHubUsername,
+		Email:           order.CustomerEmail,
+		Name:            order.CustomerName,
+		ShippingAddress: order.ShippingAddress,
+		SKU:             order.SKU,
+		Category:        order.Category,
+		Options:         order.Options,
+		CustomFields:    order.CustomFields,
+		TaxIDType:       order.TaxIDType,
+		TaxID:           order.TaxID,
+		TotalCents:      order.TotalCents,
+		Status:          string(order.Status),
+		TrackingNumber:  order.TrackingNumber,
+		Carrier:         order.Carrier,
+		ReceiptURL:      order.ReceiptURL,
+		CreatedAt:       order.CreatedAt,
+		PaidAt:          optionalTime(order.PaidAt),
+		ShippedAt:       optionalTime(order.ShippedAt),
+		DeliveredAt:     optionalTime(order.DeliveredAt),
+		RefundedAt:      optionalTime(order.RefundedAt),
+	}
+	for _, sent := range emails {
+		record.Emails = append(record.Emails, CustomerEmailRecord{
+			Kind:      string(sent.Kind),
+			Recipient: sent.Recipient,
+			SentAt:    sent.SentAt,
+		})
+	}
+	return record
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
+
+// JSON encodes the export for download.
+func (e *CustomerDataExport) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// CSV encodes the export with one row per order, as orderRecordsCSV does.
+func (e *CustomerDataExport) CSV() ([]byte, error) {
+	return orderRecordsCSV(e.Orders)
+}
+
+// orderRecordsCSV encodes orders with one row each. Addresses and options are JSON-encoded cells;
+// checkout custom fields and sent emails are each listed in a single cell.
+func orderRecordsCSV(orders []CustomerOrderRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := []string{
+		"order_number", "issue_url", "github_username", "email", "name", "shipping_address", "sku", "category", "options",
+		"custom_fields", "tax_id", "total_cents", "status", "tracking_number", "carrier", "created_at", "paid_at", "shipped_at", "delivered_at",
+		"refunded_at", "emails",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		address, err := jsonCell(order.ShippingAddress)
+		if err != nil {
+			return nil, err
+		}
+		options, err := jsonCell(order.Options)
+		if err != nil {
+			return nil, err
+		}
+		fields := make([]string, 0, len(order.CustomFields))
+		for _, field := range order.CustomFields {
+			fields = append(fields, field.Label+": "+field.Value)
+		}
+		sent := make([]string, 0, len(order.Emails))
+		for _, email := range order.Emails {
+			entry := email.Kind + " " + email.SentAt.Format(time.RFC3339)
+			if email.Recipient != "" {
+				entry += " to " + email.Recipient
+			}
+			sent = append(sent, entry)
+		}
+		row := []string{
+			strconv.Itoa(order.OrderNumber), order.IssueURL, order.GitHubUsername, order.Email, order.Name, address,
+			order.SKU, order.Category, options, strings.Join(fields, "; "), order.TaxID, strconv.FormatInt(order.TotalCents, 10), order.Status, order.TrackingNumber, order.Carrier,
+			order.CreatedAt.Format(time.RFC3339), csvTime(order.PaidAt), csvTime(order.ShippedAt),
+			csvTime(order.DeliveredAt), csvTime(order.RefundedAt), strings.Join(sent, "; "),
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func jsonCell(value map[string]any) (string, error) {
+	if len(value) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func csvTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestParseCustomerSubject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		wantKind db.CustomerSubjectKind
+		want     string
+		wantErr  bool
+	}{
+		{name: "email", input: " Buyer@Example.com ", wantKind: db.CustomerSubjectEmail, want: "buyer@example.com"},
+		{name: "username", input: "OctoCat", wantKind: db.CustomerSubjectGitHubUsername, want: "octocat"},
+		{name: "username with at", input: "@mona-lisa", wantKind: db.CustomerSubjectGitHubUsername, want: "mona-lisa"},
+		{name: "empty", input: "  ", wantErr: true},
+		{name: "display name email", input: "Buyer <buyer@example.com>", wantErr: true},
+		{name: "invalid username", input: "not a user", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subject, err := ParseCustomerSubject(tt.input)
+			if tt.wantErr {
+				var userErr UserError
+				if !errors.As(err, &userErr) {
+					t.Fatalf("expected a user error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCustomerSubject: %v", err)
+			}
+			if subject.Kind != tt.wantKind || subject.Value != tt.want {
+				t.Fatalf("expected %s %q, got %s %q", tt.wantKind, tt.want, subject.Kind, subject.Value)
+			}
+		})
+	}
+}
+
+func TestCustomerSubjectHash(t *testing.T) {
+	t.Parallel()
+
+	email := CustomerSubject{Kind: db.CustomerSubjectEmail, Value: "octocat"}
+	username := CustomerSubject{Kind: db.CustomerSubjectGitHubUsername, Value: "octocat"}
+	if email.hash() == username.hash() {
+		t.Fatal("expected the subject kind to be part of the hash")
+	}
+	if len(email.hash()) != 64 || strings.Contains(email.hash(), "octocat") {
+		t.Fatalf("expected a hex SHA-256 without the identifier, got %q", email.hash())
+	}
+}
+
+func TestCustomerDataExportEncoding(t *testing.T) {
+	t.Parallel()
+
+	paidAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	order := &db.Order{
+		ID:              uuid.New(),
+		OrderNumber:     12,
+		GitHubUsername:  "octocat",
+		CustomerEmail:   "buyer@example.com",
+		CustomerName:    "Mona, Lisa",
+		ShippingAddress: map[string]any{"city": "Berlin"},
+		SKU:             "MUG",
+		Category:        "Kitchen",
+		TotalCents:      2500,
+		Status:          db.StatusPaid,
+		CreatedAt:       paidAt.Add(-time.Hour),
+		PaidAt:          paidAt,
+	}
+	emails := []*db.OrderEmail{{Kind: db.OrderEmailConfirmation, Recipient: "buyer@example.com", SentAt: paidAt}}
+	export := &CustomerDataExport{
+		Shop:        "acme/shop",
+		Subject:     "buyer@example.com",
+		SubjectKind: string(db.CustomerSubjectEmail),
+		Orders:      []CustomerOrderRecord{customerOrderRecord(order, emails)},
+	}
+
+	encoded, err := export.JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	var decoded CustomerDataExport
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("decode JSON export: %v", err)
+	}
+	if len(decoded.Orders) != 1 || decoded.Orders[0].ShippedAt != nil || decoded.Orders[0].PaidAt == nil {
+		t.Fatalf("unexpected decoded orders: %+v", decoded.Orders)
+	}
+
+	csvBody, err := export.CSV()
+	if err != nil {
+		t.Fatalf("CSV: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(csvBody))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV export: %v", err)
+	}
+	if len(records) != 2 || len(records[0]) != len(records[1]) {
+		t.Fatalf("expected a header and one order row, got %v", records)
+	}
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	if row["name"] != "Mona, Lisa" || row["shipping_address"] != `{"city":"Berlin"}` || row["shipped_at"] != "" || row["category"] != "Kitchen" {
+		t.Fatalf("unexpected CSV row: %v", row)
+	}
+	if !strings.Contains(row["emails"], "order_confirmation 2026-03-02T10:00:00Z to buyer@example.com") {
+		t.Fatalf("expected sent email in CSV row, got %q", row["emails"])
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/logging"
+)
+
+const (
+	// topCustomersLimit is how many buyers the dashboard's customers panel lists.
+	topCustomersLimit = 10
+	// customersPageLimit is how many buyers the customers page lists.
+	customersPageLimit = 100
+	// customerNotesMaxLength keeps a customer's notes to a readable size.
+	customerNotesMaxLength = 5000
+)
+
+// CustomerDetail is a customer with their order history, newest first. Email is the one on their
+// latest order that has one.
+type CustomerDetail struct {
+	Customer *db.Customer
+	Email    string
+	Orders   []*db.Order
+}
+
+type CustomerNotesInput struct {
+	ShopID     uuid.UUID
+	CustomerID uuid.UUID
+	Notes      string
+	UpdatedBy  string
+}
+
+// TopCustomers returns the shop's buyers with the highest lifetime value, for the dashboard.
+func (s *AdminService) TopCustomers(ctx context.Context, shopID uuid.UUID) ([]*db.Customer, error) {
+	return s.listCustomers(ctx, shopID, topCustomersLimit)
+}
+
+// ListCustomers returns the shop's buyers by lifetime value, for the customers page.
+func (s *AdminService) ListCustomers(ctx context.Context, shopID uuid.UUID) ([]*db.Customer, error) {
+	return s.listCustomers(ctx, shopID, customersPageLimit)
+}
+
+func (s *AdminService) listCustomers(ctx context.Context, shopID uuid.UUID, limit int) ([]*db.Customer, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	customers, err := s.orderStore.ListTopCustomers(ctx, shopID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customers: %w", err)
+	}
+	return customers, nil
+}
+
+// GetCustomerDetail returns one of the shop's customers with every order they placed.
+func (s *AdminService) GetCustomerDetail(ctx context.Context, shopID, customerID uuid.UUID) (*CustomerDetail, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	customer, err := s.orderStore.GetCustomer(ctx, shopID, customerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAdminCustomerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	orders, err := s.orderStore.ListCustomerOrders(ctx, shopID, db.CustomerSubjectGitHubUsername, customer.GitHubUsername, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer orders: %w", err)
+	}
+	slices.Reverse(orders)
+
+	detail := &CustomerDetail{Customer: customer, Orders: orders}
+	for _, order := range orders {
+		if order.CustomerEmail != "" {
+			detail.Email = order.CustomerEmail
+			break
+		}
+	}
+	return detail, nil
+}
+
+// UpdateCustomerNotes replaces the seller's private notes on a customer. Empty notes clear them.
+func (s *AdminService) UpdateCustomerNotes(ctx context.Context, input CustomerNotesInput) error {
+	if s == nil || s.orderStore == nil {
+		return ErrAdminServiceUnavailable
+	}
+	notes := strings.TrimSpace(input.Notes)
+	if len(notes) > customerNotesMaxLength {
+		return UserError{Message: fmt.Sprintf("Keep notes under %d characters.", customerNotesMaxLength)}
+	}
+	err := s.orderStore.SetCustomerNotes(ctx, input.ShopID, input.CustomerID, notes, input.UpdatedBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrAdminCustomerNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save customer notes: %w", err)
+	}
+	return nil
+}
+
+// refreshCustomer updates the buyer's customer record after one of their orders is paid,
+// refunded, or returned. The order change has already been saved, so a failure is only logged.
+func refreshCustomer(ctx context.Context, orderStore *db.OrderStore, orderID uuid.UUID) {
+	if err := orderStore.RefreshCustomer(ctx, orderID); err != nil {
+		logging.FromContext(ctx, nil).Warn("failed to refresh customer", "error", err, "order_id", orderID)
+	}
+}
+
+// loadRelatedOrders links the buyer's other orders from the order page. They are a convenience,
+// so a failed lookup leaves them out rather than failing the page.
+func (s *AdminService) loadRelatedOrders(ctx context.Context, detail *OrderDetail) {
+	order := detail.Order
+	related, err := s.orderStore.ListRelatedOrders(ctx, order)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to list related orders", "error", err, "order_id", order.ID)
+		return
+	}
+	previous, err := s.orderStore.CountPreviousOrders(ctx, order)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to count previous orders", "error", err, "order_id", order.ID)
+		return
+	}
+	detail.RelatedOrders = related
+	detail.PreviousOrders = previous
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	dataRetentionInterval  = time.Hour
+	dataRetentionBatchSize = 200
+)
+
+type retentionOrderStore interface {
+	AnonymizeDisconnectedBefore(ctx context.Context, region string, cutoff time.Time, limit int) (int, error)
+}
+
+type shopDataStore interface {
+	DeleteShopData(ctx context.Context, shopID uuid.UUID) error
+}
+
+// DataRetentionService anonymizes customer details on old orders of disconnected shops and
+// deletes a shop's data when its seller asks for it.
+type DataRetentionService struct {
+	orderStore retentionOrderStore
+	shopStore  shopDataStore
+	retention  time.Duration
+	region     string
+	logger     *slog.Logger
+}
+
+// NewDataRetentionService creates a service that anonymizes orders older than retention for shops
+// homed in region. A zero retention turns anonymization off; an empty region covers every shop.
+func NewDataRetentionService(orderStore *db.OrderStore, shopStore *db.ShopStore, retention time.Duration, region string, logger *slog.Logger) *DataRetentionService {
+	return &DataRetentionService{
+		orderStore: orderStore,
+		shopStore:  shopStore,
+		retention:  retention,
+		region:     region,
+		logger:     logger,
+	}
+}
+
+// Run anonymizes expired orders every hour until ctx is cancelled.
+func (s *DataRetentionService) Run(ctx context.Context) {
+	if s == nil || s.orderStore == nil || s.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(dataRetentionInterval)
+	defer ticker.Stop()
+	for {
+		anonymized, err := s.AnonymizeExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to anonymize expired orders", "error", err)
+		} else if anonymized > 0 {
+			s.logger.Info("anonymized expired orders", "orders", anonymized)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AnonymizeExpired removes customer details from every order of a disconnected shop that is older
+// than the retention period, one batch at a time, and returns how many orders it changed.
+func (s *DataRetentionService) AnonymizeExpired(ctx context.Context) (int, error) {
+	if s == nil || s.orderStore == nil || s.retention <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-s.retention)
+	total := 0
+	for {
+		anonymized, err := s.orderStore.AnonymizeDisconnectedBefore(ctx, s.region, cutoff, dataRetentionBatchSize)
+		total += anonymized
+		if err != nil {
+			return total, fmt.Errorf("failed to anonymize orders: %w", err)
+		}
+		if anonymized > 0 {
+			observability.MeterFromContext(ctx).Count("data_retention.orders_anonymized", int64(anonymized))
+		}
+		if anonymized < dataRetentionBatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// DeleteShopData permanently deletes the shop's orders and customer details and disconnects it.
+// The seller confirms by typing the repository's full name.
+func (s *DataRetentionService) DeleteShopData(ctx context.Context, shop *db.Shop, confirmation string) error {
+	if shop == nil {
+		return fmt.Errorf("shop is required")
+	}
+	if !strings.EqualFold(strings.TrimSpace(confirmation), shop.GitHubRepoFullName) {
+		return UserError{Message: fmt.Sprintf("Type %s to confirm", shop.GitHubRepoFullName)}
+	}
+	if s == nil || s.shopStore == nil {
+		return fmt.Errorf("shop data store is not configured")
+	}
+
+	if err := s.shopStore.DeleteShopData(ctx, shop.ID); err != nil {
+		return fmt.Errorf("failed to delete shop data: %w", err)
+	}
+	observability.MeterFromContext(ctx).Count("data_retention.shop_deleted", 1)
+	return nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+type fakeRetentionOrderStore struct {
+	batches []int
+	calls   int
+	region  string
+	cutoff  time.Time
+}
+
+func (s *fakeRetentionOrderStore) AnonymizeDisconnectedBefore(_ context.Context, region string, cutoff time.Time, _ int) (int, error) {
+	s.region = region
+	s.cutoff = cutoff
+	if s.calls >= len(s.batches) {
+		return 0, nil
+	}
+	anonymized := s.batches[s.calls]
+	s.calls++
+	return anonymized, nil
+}
+
+type fakeShopDataStore struct {
+	deleted []uuid.UUID
+}
+
+func (s *fakeShopDataStore) DeleteShopData(_ context.Context, shopID uuid.UUID) error {
+	s.deleted = append(s.deleted, shopID)
+	return nil
+}
+
+func TestDataRetentionService_AnonymizeExpired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		batches   []int
+		wantTotal int
+		wantCalls int
+	}{
+		{name: "disabled", retention: 0, batches: []int{5}, wantTotal: 0, wantCalls: 0},
+		{name: "single batch", retention: 24 * time.Hour, batches: []int{3}, wantTotal: 3, wantCalls: 1},
+		{name: "full batches continue", retention: 24 * time.Hour, batches: []int{dataRetentionBatchSize, dataRetentionBatchSize, 7}, wantTotal: 2*dataRetentionBatchSize + 7, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &fakeRetentionOrderStore{batches: tt.batches}
+			service := &DataRetentionService{orderStore: store, retention: tt.retention, region: "eu"}
+			total, err := service.AnonymizeExpired(context.Background())
+			if err != nil {
+				t.Fatalf("AnonymizeExpired: %v", err)
+			}
+			if total != tt.wantTotal || store.calls != tt.wantCalls {
+				t.Fatalf("expected %d orders in %d calls, got %d in %d", tt.wantTotal, tt.wantCalls, total, store.calls)
+			}
+			if tt.wantCalls > 0 {
+				if store.region != "eu" {
+					t.Fatalf("expected region eu, got %q", store.region)
+				}
+				if age := time.Since(store.cutoff); age < tt.retention || age > tt.retention+time.Minute {
+					t.Fatalf("expected cutoff about %s ago, got %s", tt.retention, age)
+				}
+			}
+		})
+	}
+}
+
+func TestDataRetentionService_DeleteShopData(t *testing.T) {
+	t.Parallel()
+
+	shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "acme/shop"}
+
+	tests := []struct {
+		name         string
+		confirmation string
+		wantDeleted  bool
+	}{
+		{name: "matching name", confirmation: "acme/shop", wantDeleted: true},
+		{name: "case and whitespace ignored", confirmation: "  Acme/Shop ", wantDeleted: true},
+		{name: "wrong name", confirmation: "acme/other", wantDeleted: false},
+		{name: "empty", confirmation: "", wantDeleted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &fakeShopDataStore{}
+			service := &DataRetentionService{shopStore: store}
+			err := service.DeleteShopData(context.Background(), shop, tt.confirmation)
+			if tt.wantDeleted {
+				if err != nil {
+					t.Fatalf("DeleteShopData: %v", err)
+				}
+				if len(store.deleted) != 1 || store.deleted[0] != shop.ID {
+					t.Fatalf("expected shop %s to be deleted, got %v", shop.ID, store.deleted)
+				}
+				return
+			}
+			var userErr UserError
+			if !errors.As(err, &userErr) {
+				t.Fatalf("expected a user error, got %v", err)
+			}
+			if len(store.deleted) != 0 {
+				t.Fatalf("expected nothing deleted, got %v", store.deleted)
+			}
+		})
+	}
+}
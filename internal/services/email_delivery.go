@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// ErrRecipientSuppressed is returned instead of sending to an address that hard-bounced or
+// complained before.
+var ErrRecipientSuppressed = errors.New("recipient previously bounced or complained")
+
+type emailDeliveryShopStore interface {
+	GetByID(ctx context.Context, shopID uuid.UUID) (*db.Shop, error)
+	FlagEmailFromBounce(ctx context.Context, shopID uuid.UUID, detail string) error
+}
+
+type emailDeliveryOrderStore interface {
+	RecordEmailDelivery(ctx context.Context, shopID, orderID uuid.UUID, kind db.OrderEmailKind, status db.EmailDeliveryStatus, detail string) (bool, error)
+	SuppressEmailRecipient(ctx context.Context, shopID uuid.UUID, recipient string, reason db.EmailDeliveryStatus, detail string) error
+}
+
+// EmailDeliveryService handles bounce, complaint and delivery webhooks from the email provider a
+// shop configures. Each shop has its own endpoint because the signing secret belongs to the shop.
+type EmailDeliveryService struct {
+	shopStore  emailDeliveryShopStore
+	orderStore emailDeliveryOrderStore
+	logger     *slog.Logger
+}
+
+func NewEmailDeliveryService(shopStore *db.ShopStore, orderStore *db.OrderStore, logger *slog.Logger) *EmailDeliveryService {
+	return newEmailDeliveryService(shopStore, orderStore, logger)
+}
+
+func newEmailDeliveryService(shopStore emailDeliveryShopStore, orderStore emailDeliveryOrderStore, logger *slog.Logger) *EmailDeliveryService {
+	return &EmailDeliveryService{
+		shopStore:  shopStore,
+		orderStore: orderStore,
+		logger:     logger,
+	}
+}
+
+// ParseWebhook verifies a delivery webhook against the shop's email settings. It returns the
+// provider name with the event so callers can deduplicate deliveries.
+func (s *EmailDeliveryService) ParseWebhook(ctx context.Context, shopID uuid.UUID, payload []byte, header http.Header) (*db.Shop, string, email.DeliveryEvent, error) {
+	shop, err := s.shopStore.GetByID(ctx, shopID)
+	if err != nil {
+		return nil, "", email.DeliveryEvent{}, fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	provider, secret, err := email.ShopDeliveryWebhook(shop)
+	if err != nil {
+		return nil, "", email.DeliveryEvent{}, err
+	}
+	event, err := email.ParseDeliveryWebhook(provider, secret, payload, header)
+	if err != nil {
+		return nil, "", email.DeliveryEvent{}, err
+	}
+	return shop, provider, event, nil
+}
+
+// HandleDeliveryEvent records the event against the order's email log. Hard bounces and
+// complaints also stop the shop from emailing the address again, and a hard bounce of the shop's
+// own from address flags the shop so the seller fixes it.
+func (s *EmailDeliveryService) HandleDeliveryEvent(ctx context.Context, shop *db.Shop, event email.DeliveryEvent) error {
+	logger := logging.FromContext(ctx, s.logger)
+	meter := observability.MeterFromContext(ctx)
+
+	status, tracked := deliveryStatuses[event.Type]
+	if !tracked {
+		meter.Count("email.delivery.ignored", 1)
+		return nil
+	}
+	permanent := event.Type == email.DeliveryComplained || (event.Type == email.DeliveryBounced && event.Permanent)
+	meter.Count("email.delivery.received", 1, sentry.WithAttributes(
+		attribute.String("status", string(status)),
+		attribute.Bool("permanent", permanent),
+	))
+
+	if permanent {
+		if err := s.orderStore.SuppressEmailRecipient(ctx, shop.ID, event.Recipient, status, event.Detail); err != nil {
+			return fmt.Errorf("failed to suppress recipient: %w", err)
+		}
+	}
+	if event.Type == email.DeliveryBounced && event.Permanent && sameAddress(event.Recipient, shop.EmailFrom) {
+		if err := s.shopStore.FlagEmailFromBounce(ctx, shop.ID, event.Detail); err != nil {
+			return fmt.Errorf("failed to flag from address bounce: %w", err)
+		}
+		meter.Count("email.delivery.from_bounced", 1)
+		logger.Warn("shop from address is hard-bouncing", "shop_id", shop.ID)
+	}
+
+	orderID, err := uuid.Parse(event.Metadata[email.MetadataOrderID])
+	kind := db.OrderEmailKind(event.Metadata[email.MetadataEmailKind])
+	if err != nil || kind == "" {
+		// Seller notifications and test emails are not in an order's email log.
+		return nil
+	}
+	recorded, err := s.orderStore.RecordEmailDelivery(ctx, shop.ID, orderID, kind, status, event.Detail)
+	if err != nil {
+		return fmt.Errorf("failed to record email delivery: %w", err)
+	}
+	if !recorded {
+		logger.Debug("no logged email matches delivery event", "shop_id", shop.ID, "order_id", orderID, "kind", kind)
+	}
+	return nil
+}
+
+var deliveryStatuses = map[email.DeliveryEventType]db.EmailDeliveryStatus{
+	email.DeliveryBounced:    db.EmailBounced,
+	email.DeliveryComplained: db.EmailComplained,
+	email.DeliveryDelivered:  db.EmailDelivered,
+}
+
+func sameAddress(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	return a != "" && strings.EqualFold(a, b)
+}
+
+type emailSuppressionChecker interface {
+	IsEmailRecipientSuppressed(ctx context.Context, shopID uuid.UUID, recipient string) (bool, error)
+}
+
+// suppressingProvider refuses to send to addresses the shop has stopped emailing, so a bad
+// address is not retried on every later order event.
+type suppressingProvider struct {
+	email.Provider
+	shopID       uuid.UUID
+	suppressions emailSuppressionChecker
+}
+
+func (p suppressingProvider) SendEmail(ctx context.Context, message *email.Email) error {
+	if message != nil {
+		suppressed, err := p.suppressions.IsEmailRecipientSuppressed(ctx, p.shopID, message.To)
+		if err != nil {
+			logging.FromContext(ctx, nil).Warn("failed to check email suppressions, sending anyway", "error", err, "shop_id", p.shopID)
+		} else if suppressed {
+			observability.MeterFromContext(ctx).Count("email.suppressed", 1)
+			return ErrRecipientSuppressed
+		}
+	}
+	return p.Provider.SendEmail(ctx, message)
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+type fakeEmailDeliveryShopStore struct {
+	shop        *db.Shop
+	fromBounced []string
+}
+
+func (s *fakeEmailDeliveryShopStore) GetByID(_ context.Context, shopID uuid.UUID) (*db.Shop, error) {
+	if s.shop == nil || s.shop.ID != shopID {
+		return nil, errors.New("not found")
+	}
+	return s.shop, nil
+}
+
+func (s *fakeEmailDeliveryShopStore) FlagEmailFromBounce(_ context.Context, _ uuid.UUID, detail string) error {
+	s.fromBounced = append(s.fromBounced, detail)
+	return nil
+}
+
+type recordedDelivery struct {
+	orderID uuid.UUID
+	kind    db.OrderEmailKind
+	status  db.EmailDeliveryStatus
+}
+
+type fakeEmailDeliveryOrderStore struct {
+	deliveries []recordedDelivery
+	suppressed map[string]db.EmailDeliveryStatus
+}
+
+func (s *fakeEmailDeliveryOrderStore) RecordEmailDelivery(_ context.Context, _, orderID uuid.UUID, kind db.OrderEmailKind, status db.EmailDeliveryStatus, _ string) (bool, error) {
+	s.deliveries = append(s.deliveries, recordedDelivery{orderID: orderID, kind: kind, status: status})
+	return true, nil
+}
+
+func (s *fakeEmailDeliveryOrderStore) SuppressEmailRecipient(_ context.Context, _ uuid.UUID, recipient string, reason db.EmailDeliveryStatus, _ string) error {
+	if s.suppressed == nil {
+		s.suppressed = map[string]db.EmailDeliveryStatus{}
+	}
+	s.suppressed[recipient] = reason
+	return nil
+}
+
+func (s *fakeEmailDeliveryOrderStore) IsEmailRecipientSuppressed(_ context.Context, _ uuid.UUID, recipient string) (bool, error) {
+	_, ok := s.suppressed[recipient]
+	return ok, nil
+}
+
+func signedMailgunPayload(secret, event, severity, recipient string, variables string) []byte {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	token := "token-" + uuid.NewString()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + token))
+	return fmt.Appendf(nil, `{
+		"signature": {"timestamp": %q, "token": %q, "signature": %q},
+		"event-data": {"id": "evt-1", "event": %q, "severity": %q, "recipient": %q, "user-variables": %s}
+	}`, timestamp, token, hex.EncodeToString(mac.Sum(nil)), event, severity, recipient, variables)
+}
+
+func TestEmailDeliveryService_ParseWebhook(t *testing.T) {
+	t.Parallel()
+
+	shop := &db.Shop{
+		ID:            uuid.New(),
+		EmailProvider: "mailgun",
+		EmailConfig:   map[string]any{"api_key": "key", "domain": "mg.example.com", "webhook_secret": "signing-key"},
+	}
+	service := newEmailDeliveryService(&fakeEmailDeliveryShopStore{shop: shop}, &fakeEmailDeliveryOrderStore{}, nil)
+
+	payload := signedMailgunPayload("signing-key", "failed", "permanent", "mona@example.com", `{"order_id": "abc"}`)
+	_, provider, event, err := service.ParseWebhook(t.Context(), shop.ID, payload, nil)
+	if err != nil {
+		t.Fatalf("ParseWebhook() error = %v", err)
+	}
+	if provider != "mailgun" || event.Type != email.DeliveryBounced || !event.Permanent || event.Recipient != "mona@example.com" || event.Metadata["order_id"] != "abc" {
+		t.Fatalf("ParseWebhook() = %q, %+v", provider, event)
+	}
+
+	forged := signedMailgunPayload("other-key", "failed", "permanent", "mona@example.com", `{}`)
+	if _, _, _, err := service.ParseWebhook(t.Context(), shop.ID, forged, nil); !errors.Is(err, email.ErrInvalidWebhookSignature) {
+		t.Fatalf("ParseWebhook(forged) error = %v, want ErrInvalidWebhookSignature", err)
+	}
+
+	shop.EmailConfig = map[string]any{"api_key": "key", "domain": "mg.example.com"}
+	if _, _, _, err := service.ParseWebhook(t.Context(), shop.ID, payload, nil); !errors.Is(err, email.ErrInvalidWebhookSignature) {
+		t.Fatalf("ParseWebhook(no secret) error = %v, want ErrInvalidWebhookSignature", err)
+	}
+}
+
+func TestEmailDeliveryService_HandleDeliveryEvent(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New()
+	metadata := map[string]string{
+		email.MetadataOrderID:   orderID.String(),
+		email.MetadataEmailKind: string(db.OrderEmailConfirmation),
+	}
+	tests := []struct {
+		name           string
+		event          email.DeliveryEvent
+		wantStatus     db.EmailDeliveryStatus
+		wantSuppressed bool
+		wantFlagged    bool
+	}{
+		{
+			name:           "hard bounce suppresses recipient",
+			event:          email.DeliveryEvent{Type: email.DeliveryBounced, Permanent: true, Recipient: "mona@example.com", Metadata: metadata},
+			wantStatus:     db.EmailBounced,
+			wantSuppressed: true,
+		},
+		{
+			name:       "soft bounce is only recorded",
+			event:      email.DeliveryEvent{Type: email.DeliveryBounced, Recipient: "mona@example.com", Metadata: metadata},
+			wantStatus: db.EmailBounced,
+		},
+		{
+			name:           "complaint suppresses recipient",
+			event:          email.DeliveryEvent{Type: email.DeliveryComplained, Recipient: "mona@example.com", Metadata: metadata},
+			wantStatus:     db.EmailComplained,
+			wantSuppressed: true,
+		},
+		{
+			name:       "delivery is recorded",
+			event:      email.DeliveryEvent{Type: email.DeliveryDelivered, Recipient: "mona@example.com", Metadata: metadata},
+			wantStatus: db.EmailDelivered,
+		},
+		{
+			name:           "from address bounce flags the shop",
+			event:          email.DeliveryEvent{Type: email.DeliveryBounced, Permanent: true, Recipient: "Shop@Example.com", Detail: "mailbox does not exist"},
+			wantSuppressed: true,
+			wantFlagged:    true,
+		},
+		{
+			name:  "untracked events are ignored",
+			event: email.DeliveryEvent{Recipient: "mona@example.com", Metadata: metadata},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shop := &db.Shop{ID: uuid.New(), EmailFrom: "shop@example.com"}
+			shops := &fakeEmailDeliveryShopStore{shop: shop}
+			orders := &fakeEmailDeliveryOrderStore{}
+			service := newEmailDeliveryService(shops, orders, nil)
+
+			if err := service.HandleDeliveryEvent(t.Context(), shop, tt.event); err != nil {
+				t.Fatalf("HandleDeliveryEvent() error = %v", err)
+			}
+			if _, suppressed := orders.suppressed[tt.event.Recipient]; suppressed != tt.wantSuppressed {
+				t.Fatalf("suppressed = %v, want %v", suppressed, tt.wantSuppressed)
+			}
+			if flagged := len(shops.fromBounced) > 0; flagged != tt.wantFlagged {
+				t.Fatalf("from address flagged = %v, want %v", flagged, tt.wantFlagged)
+			}
+			if tt.wantStatus == "" {
+				if len(orders.deliveries) != 0 {
+					t.Fatalf("recorded deliveries = %+v, want none", orders.deliveries)
+				}
+				return
+			}
+			want := recordedDelivery{orderID: orderID, kind: db.OrderEmailConfirmation, status: tt.wantStatus}
+			if len(orders.deliveries) != 1 || orders.deliveries[0] != want {
+				t.Fatalf("recorded deliveries = %+v, want %+v", orders.deliveries, want)
+			}
+		})
+	}
+}
+
+func TestSuppressingProviderSkipsSuppressedRecipients(t *testing.T) {
+	t.Parallel()
+
+	shopID := uuid.New()
+	orders := &fakeEmailDeliveryOrderStore{suppressed: map[string]db.EmailDeliveryStatus{"gone@example.com": db.EmailBounced}}
+	inner := &capturingEmailProvider{}
+	provider := suppressingProvider{Provider: inner, shopID: shopID, suppressions: orders}
+
+	if err := provider.SendEmail(t.Context(), &email.Email{To: "gone@example.com"}); !errors.Is(err, ErrRecipientSuppressed) {
+		t.Fatalf("SendEmail(suppressed) error = %v, want ErrRecipientSuppressed", err)
+	}
+	if err := provider.SendEmail(t.Context(), &email.Email{To: "mona@example.com"}); err != nil {
+		t.Fatalf("SendEmail() error = %v", err)
+	}
+	if len(inner.sent) != 1 || inner.sent[0].To != "mona@example.com" {
+		t.Fatalf("sent = %+v, want only mona@example.com", inner.sent)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+// EmailDomainStatus returns the DNS records the shop's email provider needs for its sending
+// domain. The shop's email is marked verified once the provider reports the domain verified. It
+// returns email.ErrDomainVerificationUnsupported for providers verified in their own dashboard.
+func (s *AdminService) EmailDomainStatus(ctx context.Context, shop *db.Shop) (*email.DomainStatus, error) {
+	verifier, err := shopDomainVerifier(shop)
+	if err != nil {
+		return nil, err
+	}
+	status, err := verifier.DomainStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sending domain: %w", err)
+	}
+	return status, s.markEmailVerified(ctx, shop, status)
+}
+
+// VerifyEmailDomain asks the shop's email provider to check DNS again, then works like
+// EmailDomainStatus.
+func (s *AdminService) VerifyEmailDomain(ctx context.Context, shop *db.Shop) (*email.DomainStatus, error) {
+	verifier, err := shopDomainVerifier(shop)
+	if err != nil {
+		return nil, err
+	}
+	status, err := verifier.VerifyDomain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify sending domain: %w", err)
+	}
+	return status, s.markEmailVerified(ctx, shop, status)
+}
+
+func shopDomainVerifier(shop *db.Shop) (email.DomainVerifier, error) {
+	if shop == nil || shop.EmailProvider == "" {
+		return nil, UserError{Message: "Configure an email provider first"}
+	}
+	provider, err := email.NewProviderFromShop(shop)
+	if err != nil {
+		return nil, UserError{Message: fmt.Sprintf("Invalid email configuration: %s", err.Error())}
+	}
+	verifier, ok := provider.(email.DomainVerifier)
+	if !ok {
+		return nil, email.ErrDomainVerificationUnsupported
+	}
+	return verifier, nil
+}
+
+func (s *AdminService) markEmailVerified(ctx context.Context, shop *db.Shop, status *email.DomainStatus) error {
+	if !status.Verified || shop.EmailVerified {
+		return nil
+	}
+	if s == nil || s.shopStore == nil {
+		return fmt.Errorf("%w: shop store unavailable", ErrAdminServiceUnavailable)
+	}
+	if err := s.shopStore.SetEmailVerified(ctx, shop.ID, true); err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	shop.EmailVerified = true
+	return nil
+}
+
+// sendingDomainVerified reports whether the provider confirms the sending domain. Providers that
+// cannot report it count as verified, since their API key check is all gitshop can do.
+func sendingDomainVerified(ctx context.Context, provider email.Provider) (bool, error) {
+	verifier, ok := provider.(email.DomainVerifier)
+	if !ok {
+		return true, nil
+	}
+	status, err := verifier.DomainStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+	return status.Verified, nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+func TestAdminService_EmailDomainStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/domains/mg.example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"domain": {"name": "mg.example.com", "state": "unverified"},
+			"sending_dns_records": [
+				{"record_type": "TXT", "name": "mg.example.com", "value": "v=spf1 include:mailgun.org ~all", "valid": "valid"},
+				{"record_type": "TXT", "name": "k1._domainkey.mg.example.com", "value": "k=rsa; p=MIGf", "valid": "unknown"}
+			],
+			"receiving_dns_records": [
+				{"record_type": "MX", "priority": "10", "value": "mxa.mailgun.org", "valid": "unknown"}
+			]
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	service := &AdminService{}
+	shop := &db.Shop{
+		ID:            uuid.New(),
+		EmailProvider: "mailgun",
+		EmailConfig: map[string]any{
+			"api_key":    "key",
+			"domain":     "mg.example.com",
+			"from_email": "orders@mg.example.com",
+			"base_url":   server.URL,
+		},
+	}
+
+	status, err := service.EmailDomainStatus(t.Context(), shop)
+	if err != nil {
+		t.Fatalf("EmailDomainStatus() error = %v", err)
+	}
+	if status.Verified || shop.EmailVerified {
+		t.Fatal("unverified domain marked verified")
+	}
+	wantPurposes := []string{"SPF", "DKIM", "Return path"}
+	if len(status.Records) != len(wantPurposes) {
+		t.Fatalf("records = %+v, want %d", status.Records, len(wantPurposes))
+	}
+	for i, purpose := range wantPurposes {
+		if status.Records[i].Purpose != purpose {
+			t.Fatalf("record %d purpose = %q, want %q", i, status.Records[i].Purpose, purpose)
+		}
+	}
+	if !status.Records[0].Verified || status.Records[1].Verified {
+		t.Fatalf("record verification = %+v", status.Records)
+	}
+
+	postmark := &db.Shop{ID: uuid.New(), EmailProvider: "postmark", EmailConfig: map[string]any{"api_key": "key"}}
+	if _, err := service.EmailDomainStatus(t.Context(), postmark); !errors.Is(err, email.ErrDomainVerificationUnsupported) {
+		t.Fatalf("EmailDomainStatus(postmark) error = %v, want ErrDomainVerificationUnsupported", err)
+	}
+}
@@ -3,15 +3,37 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/gitshopapp/gitshop/internal/catalog"
 	"github.com/gitshopapp/gitshop/internal/db"
 	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/fx"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/money"
 )
 
 type OrderEmailSender interface {
 	SendOrderConfirmation(ctx context.Context, shop *db.Shop, order *db.Order, input OrderConfirmationEmailInput) error
 	SendOrderShipped(ctx context.Context, shop *db.Shop, order *db.Order, input OrderShipmentEmailInput) error
 	SendOrderDelivered(ctx context.Context, shop *db.Shop, order *db.Order) error
+	SendOrderReturned(ctx context.Context, shop *db.Shop, order *db.Order, refunded bool) error
+	SendNewOrderNotification(ctx context.Context, shop *db.Shop, order *db.Order, input NewOrderNotificationInput) error
+	SendFirstOrderWelcome(ctx context.Context, shop *db.Shop, order *db.Order, recipient string) error
+	SendStripeAccountRestricted(ctx context.Context, shop *db.Shop, capabilities db.StripeCapabilities, recipient string) error
+	SendCheckoutReminder(ctx context.Context, shop *db.Shop, order *db.Order) error
+	SendOverdueShipments(ctx context.Context, shop *db.Shop, input OverdueShipmentsEmailInput) error
+}
+
+// OverdueShipmentsEmailInput is the seller's daily digest of paid orders that have waited longer
+// than the shop's shipping window. Orders holds the oldest of them; Total counts them all.
+type OverdueShipmentsEmailInput struct {
+	Recipient      string
+	ShipWithinDays int
+	Total          int
+	Orders         []*db.Order
+	Now            time.Time
 }
 
 type OrderConfirmationEmailInput struct {
@@ -26,21 +48,57 @@ type OrderShipmentEmailInput struct {
 	TrackingCarrier string
 }
 
+// NewOrderNotificationInput describes the seller notification for a paid order. Recipient is
+// the shop's notifications address rather than the customer.
+type NewOrderNotificationInput struct {
+	Recipient       string
+	CustomerName    string
+	CustomerEmail   string
+	ShippingAddress string
+	// PreviousOrders counts the buyer's earlier paid orders, so the seller can spot returning
+	// customers.
+	PreviousOrders int
+}
+
 type ShopEmailProviderFactory func(shop *db.Shop) (email.Provider, error)
 
 type ShopOrderEmailSender struct {
 	providerFromShop ShopEmailProviderFactory
+	templates        *EmailTemplateLoader
+	baseURL          string
+	suppressions     emailSuppressionChecker
+	rates            fx.RateSource
 }
 
-func NewShopOrderEmailSender(providerFromShop ShopEmailProviderFactory) *ShopOrderEmailSender {
+func NewShopOrderEmailSender(providerFromShop ShopEmailProviderFactory, templates *EmailTemplateLoader, baseURL string) *ShopOrderEmailSender {
 	if providerFromShop == nil {
 		providerFromShop = email.NewProviderFromShop
 	}
 	return &ShopOrderEmailSender{
 		providerFromShop: providerFromShop,
+		templates:        templates,
+		baseURL:          baseURL,
 	}
 }
 
+// WithSuppressions returns a copy of the sender that skips recipients the shop stopped emailing
+// after a hard bounce or complaint, returning ErrRecipientSuppressed for them.
+func (s *ShopOrderEmailSender) WithSuppressions(suppressions *db.OrderStore) *ShopOrderEmailSender {
+	sender := *s
+	if suppressions != nil {
+		sender.suppressions = suppressions
+	}
+	return &sender
+}
+
+// WithRateSource returns a copy of the sender that adds an estimated total in the buyer's chosen
+// currency to confirmation and reminder emails.
+func (s *ShopOrderEmailSender) WithRateSource(rates fx.RateSource) *ShopOrderEmailSender {
+	sender := *s
+	sender.rates = rates
+	return &sender
+}
+
 func (s *ShopOrderEmailSender) SendOrderConfirmation(ctx context.Context, shop *db.Shop, order *db.Order, input OrderConfirmationEmailInput) error {
 	provider, err := s.provider(shop)
 	if err != nil {
@@ -52,8 +110,14 @@ func (s *ShopOrderEmailSender) SendOrderConfirmation(ctx context.Context, shop *
 		CustomerEmail:   input.CustomerEmail,
 		ShippingAddress: input.ShippingAddress,
 	})
+	orderInfo.TotalEstimate = orderTotalEstimate(ctx, s.rates, order)
+
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
 
-	return email.SendOrderConfirmation(ctx, provider, orderInfo)
+	return renderer.Send(ctx, provider, "order_confirmation", orderInfo)
 }
 
 func (s *ShopOrderEmailSender) SendOrderShipped(ctx context.Context, shop *db.Shop, order *db.Order, input OrderShipmentEmailInput) error {
@@ -68,7 +132,12 @@ func (s *ShopOrderEmailSender) SendOrderShipped(ctx context.Context, shop *db.Sh
 		TrackingCarrier: input.TrackingCarrier,
 	})
 
-	return email.SendOrderShipped(ctx, provider, orderInfo)
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Send(ctx, provider, "order_shipped", orderInfo)
 }
 
 func (s *ShopOrderEmailSender) SendOrderDelivered(ctx context.Context, shop *db.Shop, order *db.Order) error {
@@ -79,7 +148,226 @@ func (s *ShopOrderEmailSender) SendOrderDelivered(ctx context.Context, shop *db.
 
 	orderInfo := BuildOrderInfo(shop, order, OrderInfoOverrides{})
 
-	return email.SendOrderDelivered(ctx, provider, orderInfo)
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Send(ctx, provider, "order_delivered", orderInfo)
+}
+
+func (s *ShopOrderEmailSender) SendOrderReturned(ctx context.Context, shop *db.Shop, order *db.Order, refunded bool) error {
+	provider, err := s.provider(shop)
+	if err != nil {
+		return err
+	}
+
+	orderInfo := BuildOrderInfo(shop, order, OrderInfoOverrides{})
+	orderInfo.Refunded = refunded
+
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Send(ctx, provider, "order_returned", orderInfo)
+}
+
+func (s *ShopOrderEmailSender) SendNewOrderNotification(ctx context.Context, shop *db.Shop, order *db.Order, input NewOrderNotificationInput) error {
+	recipient := strings.TrimSpace(input.Recipient)
+	if recipient == "" {
+		return fmt.Errorf("notification recipient is required")
+	}
+
+	provider, err := s.provider(shop)
+	if err != nil {
+		return err
+	}
+
+	orderInfo := BuildOrderInfo(shop, order, OrderInfoOverrides{
+		CustomerName:    input.CustomerName,
+		CustomerEmail:   input.CustomerEmail,
+		ShippingAddress: input.ShippingAddress,
+	})
+	orderInfo.DashboardURL = shipOrderURL(s.baseURL, order)
+	orderInfo.PreviousOrders = input.PreviousOrders
+
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	message, err := renderer.Render(ctx, "new_order", orderInfo)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	message.To = recipient
+
+	return provider.SendEmail(ctx, message)
+}
+
+// SendFirstOrderWelcome congratulates the seller on their first paid order and walks them through
+// fulfilling it.
+func (s *ShopOrderEmailSender) SendFirstOrderWelcome(ctx context.Context, shop *db.Shop, order *db.Order, recipient string) error {
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" {
+		return fmt.Errorf("welcome recipient is required")
+	}
+
+	provider, err := s.provider(shop)
+	if err != nil {
+		return err
+	}
+
+	orderInfo := BuildOrderInfo(shop, order, OrderInfoOverrides{})
+	orderInfo.DashboardURL = orderDetailURL(s.baseURL, order)
+
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	message, err := renderer.Render(ctx, "first_order", orderInfo)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	message.To = recipient
+
+	return provider.SendEmail(ctx, message)
+}
+
+// SendStripeAccountRestricted tells the seller that Stripe turned off charges or payouts for their
+// account.
+func (s *ShopOrderEmailSender) SendStripeAccountRestricted(ctx context.Context, shop *db.Shop, capabilities db.StripeCapabilities, recipient string) error {
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" {
+		return fmt.Errorf("restriction recipient is required")
+	}
+
+	provider, err := s.provider(shop)
+	if err != nil {
+		return err
+	}
+
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	message, err := renderer.Render(ctx, "stripe_account_restricted", &email.OrderInfo{
+		ShopName:      shop.GitHubRepoFullName,
+		ShopURL:       fmt.Sprintf("https://github.com/%s", shop.GitHubRepoFullName),
+		DashboardURL:  settingsURL(s.baseURL),
+		ChargesPaused: !capabilities.ChargesEnabled,
+		PayoutsPaused: !capabilities.PayoutsEnabled,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	message.To = recipient
+
+	return provider.SendEmail(ctx, message)
+}
+
+// SendCheckoutReminder tells the buyer their checkout link is about to expire. It needs the
+// buyer's email from the order form; orders without one only get the issue comment.
+func (s *ShopOrderEmailSender) SendCheckoutReminder(ctx context.Context, shop *db.Shop, order *db.Order) error {
+	provider, err := s.provider(shop)
+	if err != nil {
+		return err
+	}
+
+	orderInfo := BuildOrderInfo(shop, order, OrderInfoOverrides{})
+	orderInfo.TotalEstimate = orderTotalEstimate(ctx, s.rates, order)
+
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Send(ctx, provider, "checkout_reminder", orderInfo)
+}
+
+// SendOverdueShipments emails the seller a digest of paid orders that are past the shop's
+// shipping window, each with a link to ship it.
+func (s *ShopOrderEmailSender) SendOverdueShipments(ctx context.Context, shop *db.Shop, input OverdueShipmentsEmailInput) error {
+	recipient := strings.TrimSpace(input.Recipient)
+	if recipient == "" {
+		return fmt.Errorf("overdue shipments recipient is required")
+	}
+
+	provider, err := s.provider(shop)
+	if err != nil {
+		return err
+	}
+
+	renderer, err := s.renderer(ctx, shop)
+	if err != nil {
+		return err
+	}
+
+	orders := make([]email.OverdueOrder, 0, len(input.Orders))
+	for _, order := range input.Orders {
+		orders = append(orders, email.OverdueOrder{
+			OrderNumber: fmt.Sprintf("#%d", order.OrderNumber),
+			PaidDate:    order.PaidAt.Format("January 2, 2006"),
+			Total:       money.Format(order.TotalCents),
+			DaysWaiting: int(input.Now.Sub(order.PaidAt) / (24 * time.Hour)),
+			URL:         shipOrderURL(s.baseURL, order),
+		})
+	}
+
+	message, err := renderer.Render(ctx, "overdue_shipments", &email.OrderInfo{
+		ShopName:       shop.GitHubRepoFullName,
+		ShopURL:        fmt.Sprintf("https://github.com/%s", shop.GitHubRepoFullName),
+		DashboardURL:   dashboardURL(s.baseURL),
+		ShipWithinDays: input.ShipWithinDays,
+		OverdueCount:   input.Total,
+		OverdueOrders:  orders,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	message.To = recipient
+
+	return provider.SendEmail(ctx, message)
+}
+
+// shipOrderURL links to the dashboard order page with the ship form open. It is empty when
+// no base URL is configured.
+func shipOrderURL(baseURL string, order *db.Order) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" || order == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/admin/orders/%s?action=ship", baseURL, order.ID)
+}
+
+// orderDetailURL links to the dashboard order page, or is empty when no base URL is configured.
+func orderDetailURL(baseURL string, order *db.Order) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" || order == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/admin/orders/%s", baseURL, order.ID)
+}
+
+// dashboardURL links to the orders dashboard, or is empty when no base URL is configured.
+func dashboardURL(baseURL string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return ""
+	}
+	return baseURL + "/admin/dashboard"
+}
+
+// settingsURL links to the dashboard settings page, or is empty when no base URL is configured.
+func settingsURL(baseURL string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return ""
+	}
+	return baseURL + "/admin/settings"
 }
 
 func (s *ShopOrderEmailSender) provider(shop *db.Shop) (email.Provider, error) {
@@ -94,10 +382,46 @@ func (s *ShopOrderEmailSender) provider(shop *db.Shop) (email.Provider, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get email provider: %w", err)
 	}
+	if s.suppressions != nil {
+		provider = suppressingProvider{Provider: provider, shopID: shop.ID, suppressions: s.suppressions}
+	}
 
 	return provider, nil
 }
 
+// renderer builds a renderer in the shop's locale with its repo template overrides. Any problem
+// loading them falls back to the built-in templates so order emails still go out.
+func (s *ShopOrderEmailSender) renderer(ctx context.Context, shop *db.Shop) (*email.Renderer, error) {
+	if s.templates == nil {
+		return email.NewRenderer()
+	}
+
+	logger := logging.FromContext(ctx, s.templates.logger)
+	repoTemplates, err := s.templates.Load(ctx, shop)
+	if err != nil {
+		logger.Warn("failed to load repo email templates, using defaults", "error", err, "shop_id", shop.ID)
+		return email.NewRenderer()
+	}
+	renderer, err := email.NewLocalizedRenderer(repoTemplates.Locale, repoTemplates.Overrides)
+	if err != nil {
+		logger.Warn("invalid repo email templates, using defaults", "error", err, "shop_id", shop.ID)
+		renderer, err = email.NewLocalizedRenderer(repoTemplates.Locale, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return renderer.WithBranding(emailBranding(repoTemplates.Branding)), nil
+}
+
+func emailBranding(branding catalog.BrandingConfig) email.Branding {
+	return email.Branding{
+		LogoURL:      branding.LogoURL,
+		AccentColor:  branding.AccentColor,
+		SupportEmail: branding.SupportEmail,
+		ShopURL:      branding.ShopURL,
+	}
+}
+
 type noopOrderEmailSender struct{}
 
 func (noopOrderEmailSender) SendOrderConfirmation(context.Context, *db.Shop, *db.Order, OrderConfirmationEmailInput) error {
@@ -111,3 +435,27 @@ func (noopOrderEmailSender) SendOrderShipped(context.Context, *db.Shop, *db.Orde
 func (noopOrderEmailSender) SendOrderDelivered(context.Context, *db.Shop, *db.Order) error {
 	return nil
 }
+
+func (noopOrderEmailSender) SendOrderReturned(context.Context, *db.Shop, *db.Order, bool) error {
+	return nil
+}
+
+func (noopOrderEmailSender) SendNewOrderNotification(context.Context, *db.Shop, *db.Order, NewOrderNotificationInput) error {
+	return nil
+}
+
+func (noopOrderEmailSender) SendFirstOrderWelcome(context.Context, *db.Shop, *db.Order, string) error {
+	return nil
+}
+
+func (noopOrderEmailSender) SendStripeAccountRestricted(context.Context, *db.Shop, db.StripeCapabilities, string) error {
+	return nil
+}
+
+func (noopOrderEmailSender) SendCheckoutReminder(context.Context, *db.Shop, *db.Order) error {
+	return nil
+}
+
+func (noopOrderEmailSender) SendOverdueShipments(context.Context, *db.Shop, OverdueShipmentsEmailInput) error {
+	return nil
+}
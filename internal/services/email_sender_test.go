@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+type capturingEmailProvider struct {
+	sent []*email.Email
+}
+
+func (p *capturingEmailProvider) SendEmail(_ context.Context, message *email.Email) error {
+	p.sent = append(p.sent, message)
+	return nil
+}
+
+func (p *capturingEmailProvider) ValidateAPIKey(context.Context) error {
+	return nil
+}
+
+func TestShopOrderEmailSender_SendNewOrderNotification(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New()
+	tests := []struct {
+		name      string
+		baseURL   string
+		wantLink  string
+		recipient string
+		wantErr   bool
+	}{
+		{
+			name:      "includes ship link",
+			baseURL:   "https://gitshop.example.com/",
+			wantLink:  "https://gitshop.example.com/admin/orders/" + orderID.String() + "?action=ship",
+			recipient: "orders@example.com",
+		},
+		{
+			name:      "no base url omits link",
+			recipient: "orders@example.com",
+		},
+		{
+			name:    "recipient required",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &capturingEmailProvider{}
+			sender := NewShopOrderEmailSender(func(*db.Shop) (email.Provider, error) {
+				return provider, nil
+			}, nil, tt.baseURL)
+
+			shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}
+			order := &db.Order{ID: orderID, OrderNumber: 42, SKU: "COFFEE_V1", SubtotalCents: 1500, TotalCents: 1500}
+			err := sender.SendNewOrderNotification(t.Context(), shop, order, NewOrderNotificationInput{
+				Recipient:       tt.recipient,
+				CustomerName:    "Mona",
+				CustomerEmail:   "mona@example.com",
+				ShippingAddress: "Mona\n1 Main St",
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(provider.sent) != 1 {
+				t.Fatalf("expected 1 email, got %d", len(provider.sent))
+			}
+
+			sent := provider.sent[0]
+			if sent.To != tt.recipient {
+				t.Fatalf("expected recipient %q, got %q", tt.recipient, sent.To)
+			}
+			if !strings.HasPrefix(sent.Subject, "New Order - #42") {
+				t.Fatalf("unexpected subject %q", sent.Subject)
+			}
+			if !strings.Contains(sent.Text, "1 Main St") || !strings.Contains(sent.Text, "mona@example.com") {
+				t.Fatalf("expected customer details in text body, got %q", sent.Text)
+			}
+			if tt.wantLink != "" && !strings.Contains(sent.Text, tt.wantLink) {
+				t.Fatalf("expected text to contain %q, got %q", tt.wantLink, sent.Text)
+			}
+			if tt.wantLink == "" && strings.Contains(sent.Text, "Ship this order") {
+				t.Fatalf("expected no ship link, got %q", sent.Text)
+			}
+		})
+	}
+}
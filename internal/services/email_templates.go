@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/logging"
+)
+
+const (
+	emailTemplatesDir      = "gitshop/emails"
+	emailTemplatesCacheTTL = 10 * time.Minute
+)
+
+type EmailTemplateIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// RepoEmailTemplates holds the valid template overrides committed to a shop repo, along with the
+// shop's locale and branding from gitshop.yaml. Invalid files are reported as issues and the
+// built-in template is used instead.
+type RepoEmailTemplates struct {
+	Overrides map[string]string      `json:"overrides"`
+	Files     []string               `json:"files"`
+	Issues    []EmailTemplateIssue   `json:"issues"`
+	Locale    string                 `json:"locale"`
+	Branding  catalog.BrandingConfig `json:"branding"`
+}
+
+type EmailTemplateLoader struct {
+	githubClient  *githubapp.Client
+	cacheProvider cache.Provider
+	logger        *slog.Logger
+}
+
+func NewEmailTemplateLoader(githubClient *githubapp.Client, cacheProvider cache.Provider, logger *slog.Logger) *EmailTemplateLoader {
+	return &EmailTemplateLoader{
+		githubClient:  githubClient,
+		cacheProvider: cacheProvider,
+		logger:        logger,
+	}
+}
+
+func emailTemplatesCacheKey(shop *db.Shop) string {
+	return "email_templates:" + shop.ID.String()
+}
+
+// Load returns the shop's repo email templates, using the cache when available.
+func (l *EmailTemplateLoader) Load(ctx context.Context, shop *db.Shop) (*RepoEmailTemplates, error) {
+	if l == nil || l.githubClient == nil {
+		return &RepoEmailTemplates{}, nil
+	}
+	if shop == nil || shop.GitHubRepoFullName == "" {
+		return nil, fmt.Errorf("shop is required")
+	}
+
+	logger := logging.FromContext(ctx, l.logger)
+	cacheKey := emailTemplatesCacheKey(shop)
+	if l.cacheProvider != nil {
+		if cached, err := l.cacheProvider.Get(ctx, cacheKey); err == nil && cached != "" {
+			var templates RepoEmailTemplates
+			if err := json.Unmarshal([]byte(cached), &templates); err == nil {
+				return &templates, nil
+			}
+		}
+	}
+
+	client := l.githubClient.WithInstallation(shop.GitHubInstallationID)
+	templates, err := fetchRepoEmailTemplates(ctx, client, shop.GitHubRepoFullName, contentAccess(ctx, client, l.logger))
+	if err != nil {
+		return nil, err
+	}
+
+	if l.cacheProvider != nil {
+		if encoded, err := json.Marshal(templates); err == nil {
+			if err := l.cacheProvider.Set(ctx, cacheKey, string(encoded), emailTemplatesCacheTTL); err != nil {
+				logger.Warn("failed to cache email templates", "error", err, "shop_id", shop.ID)
+			}
+		}
+	}
+	return templates, nil
+}
+
+func fetchRepoEmailTemplates(ctx context.Context, client *githubapp.Client, repoFullName string, access githubapp.ContentAccess) (*RepoEmailTemplates, error) {
+	files, err := listRepoDirectory(ctx, client, repoFullName, emailTemplatesDir, access)
+	if err != nil {
+		return nil, err
+	}
+
+	config := shopConfig(ctx, client, repoFullName)
+	templates := &RepoEmailTemplates{
+		Overrides: map[string]string{},
+		Locale:    configLocalizer(config).Locale(),
+		Branding:  shopBranding(config),
+	}
+	for _, file := range files {
+		name, ok := email.TemplateNameForFile(path.Base(file.Path))
+		if !ok {
+			continue
+		}
+		templates.Files = append(templates.Files, file.Path)
+
+		content, readErr := client.GetFile(ctx, repoFullName, file.Path, "")
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read email template %s: %w", file.Path, readErr)
+		}
+		if err := email.ValidateTemplate(name, string(content)); err != nil {
+			templates.Issues = append(templates.Issues, EmailTemplateIssue{Path: file.Path, Message: err.Error()})
+			continue
+		}
+		templates.Overrides[name] = string(content)
+	}
+
+	sort.Strings(templates.Files)
+	return templates, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+)
+
+func TestShopOrderEmailSender_RendererUsesRepoOverrides(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		wantHTML  string
+	}{
+		{
+			name:      "override applied",
+			overrides: map[string]string{"order_confirmation_html": "<p>Custom {{.OrderNumber}}</p>"},
+			wantHTML:  "<p>Custom #1001</p>",
+		},
+		{
+			name:      "no overrides uses defaults",
+			overrides: map[string]string{},
+			wantHTML:  "#1001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := cache.NewMemoryProvider()
+			if err != nil {
+				t.Fatalf("failed to create cache: %v", err)
+			}
+			shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}
+			encoded, err := json.Marshal(RepoEmailTemplates{Overrides: tt.overrides})
+			if err != nil {
+				t.Fatalf("failed to encode templates: %v", err)
+			}
+			if err := provider.Set(t.Context(), emailTemplatesCacheKey(shop), string(encoded), emailTemplatesCacheTTL); err != nil {
+				t.Fatalf("failed to seed cache: %v", err)
+			}
+
+			loader := NewEmailTemplateLoader(githubapp.NewClient(nil, slog.Default()), provider, slog.Default())
+			sender := NewShopOrderEmailSender(nil, loader, "")
+
+			renderer, err := sender.renderer(t.Context(), shop)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			rendered, err := renderer.Render(t.Context(), "order_confirmation", &email.OrderInfo{OrderNumber: "#1001"})
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			if !strings.Contains(rendered.HTML, tt.wantHTML) {
+				t.Fatalf("expected HTML to contain %q, got %q", tt.wantHTML, rendered.HTML)
+			}
+		})
+	}
+}
+
+func TestValidateEmailTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid", content: "Order {{.OrderNumber}} for {{.CustomerName}}", wantErr: false},
+		{name: "syntax error", content: "Order {{.OrderNumber", wantErr: true},
+		{name: "unknown field", content: "Order {{.Missing}}", wantErr: true},
+		{name: "empty", content: "  ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := email.ValidateTemplate("order_confirmation_text", tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const operatorNotifyTimeout = 5 * time.Second
+
+type firstOrderStore interface {
+	ClaimFirstPaidOrder(ctx context.Context, shopID, orderID uuid.UUID) (bool, error)
+}
+
+// FirstOrderConcierge welcomes sellers to their first sale: it emails them a fulfillment
+// checklist and, when configured, tells the platform operators.
+type FirstOrderConcierge struct {
+	shopStore          firstOrderStore
+	emailSender        OrderEmailSender
+	operatorWebhookURL string
+	httpClient         *http.Client
+	logger             *slog.Logger
+}
+
+func NewFirstOrderConcierge(shopStore *db.ShopStore, emailSender OrderEmailSender, operatorWebhookURL string, logger *slog.Logger) *FirstOrderConcierge {
+	if emailSender == nil {
+		emailSender = noopOrderEmailSender{}
+	}
+	return &FirstOrderConcierge{
+		shopStore:          shopStore,
+		emailSender:        emailSender,
+		operatorWebhookURL: strings.TrimSpace(operatorWebhookURL),
+		httpClient:         &http.Client{Timeout: operatorNotifyTimeout},
+		logger:             logger,
+	}
+}
+
+// HandlePaidOrder starts the first-order flow when order is the shop's first paid order. Later
+// orders, and failures along the way, are logged and otherwise ignored so payment handling is
+// never held up.
+func (c *FirstOrderConcierge) HandlePaidOrder(ctx context.Context, shop *db.Shop, order *db.Order, recipient string) {
+	if c == nil || c.shopStore == nil || shop == nil || order == nil {
+		return
+	}
+
+	logger := logging.FromContext(ctx, c.logger).With("shop_id", shop.ID, "order_id", order.ID)
+	claimed, err := c.shopStore.ClaimFirstPaidOrder(ctx, shop.ID, order.ID)
+	if err != nil {
+		logger.Warn("failed to record first paid order", "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+	observability.MeterFromContext(ctx).Count("shop.first_order", 1)
+	logger.Info("shop received its first paid order", "repo", shop.GitHubRepoFullName)
+
+	if strings.TrimSpace(recipient) != "" {
+		if err := c.emailSender.SendFirstOrderWelcome(ctx, shop, order, recipient); err != nil {
+			logger.Error("failed to send first order welcome email", "error", err)
+		}
+	}
+
+	if err := c.notifyOperators(ctx, shop, order); err != nil {
+		logger.Warn("failed to notify operators of first order", "error", err)
+	}
+}
+
+func (c *FirstOrderConcierge) notifyOperators(ctx context.Context, shop *db.Shop, order *db.Order) error {
+	if c.operatorWebhookURL == "" {
+		return nil
+	}
+
+	orderInfo := BuildOrderInfo(shop, order, OrderInfoOverrides{})
+	body, err := json.Marshal(map[string]string{
+		"event":        "shop.first_order",
+		"shop":         shop.GitHubRepoFullName,
+		"order_number": orderInfo.OrderNumber,
+		"total":        orderInfo.Total,
+		"text":         fmt.Sprintf("🎉 %s made its first sale: order %s for %s", shop.GitHubRepoFullName, orderInfo.OrderNumber, orderInfo.Total),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.operatorWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("operator webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+type fakeFirstOrderStore struct {
+	claimed bool
+}
+
+func (s *fakeFirstOrderStore) ClaimFirstPaidOrder(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	if s.claimed {
+		return false, nil
+	}
+	s.claimed = true
+	return true, nil
+}
+
+func TestFirstOrderConcierge_HandlePaidOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		posts []map[string]string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode operator post: %v", err)
+		}
+		mu.Lock()
+		posts = append(posts, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := &capturingEmailProvider{}
+	sender := NewShopOrderEmailSender(func(*db.Shop) (email.Provider, error) {
+		return provider, nil
+	}, nil, "https://gitshop.example.com")
+	concierge := NewFirstOrderConcierge(nil, sender, server.URL, nil)
+	concierge.shopStore = &fakeFirstOrderStore{}
+
+	shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}
+	first := &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 7, SKU: "MUG", SubtotalCents: 2000, TotalCents: 2000}
+	second := &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 8, SKU: "MUG", SubtotalCents: 2000, TotalCents: 2000}
+
+	concierge.HandlePaidOrder(t.Context(), shop, first, "owner@example.com")
+	concierge.HandlePaidOrder(t.Context(), shop, second, "owner@example.com")
+
+	if len(provider.sent) != 1 {
+		t.Fatalf("expected one welcome email, got %d", len(provider.sent))
+	}
+	welcome := provider.sent[0]
+	if welcome.To != "owner@example.com" || !strings.HasPrefix(welcome.Subject, "Your first order - #7") {
+		t.Fatalf("unexpected welcome email to %q with subject %q", welcome.To, welcome.Subject)
+	}
+	if !strings.Contains(welcome.Text, "https://gitshop.example.com/admin/orders/"+first.ID.String()) {
+		t.Fatalf("expected order link in welcome email, got %q", welcome.Text)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posts) != 1 {
+		t.Fatalf("expected one operator notification, got %d", len(posts))
+	}
+	if posts[0]["event"] != "shop.first_order" || posts[0]["shop"] != "octo/shop" || !strings.Contains(posts[0]["text"], "#7") {
+		t.Fatalf("unexpected operator notification: %v", posts[0])
+	}
+}
+
+func TestFirstOrderConcierge_SkipsMissingRecipientAndOperatorURL(t *testing.T) {
+	t.Parallel()
+
+	provider := &capturingEmailProvider{}
+	sender := NewShopOrderEmailSender(func(*db.Shop) (email.Provider, error) {
+		return provider, nil
+	}, nil, "")
+	store := &fakeFirstOrderStore{}
+	concierge := NewFirstOrderConcierge(nil, sender, "", nil)
+	concierge.shopStore = store
+
+	shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}
+	concierge.HandlePaidOrder(t.Context(), shop, &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 1}, " ")
+
+	if !store.claimed {
+		t.Fatal("expected the first order to be recorded without a recipient")
+	}
+	if len(provider.sent) != 0 {
+		t.Fatalf("expected no email without a recipient, got %d", len(provider.sent))
+	}
+}
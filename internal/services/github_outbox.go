@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/go-github/v66/github"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	githubOutboxPollInterval   = 10 * time.Second
+	githubOutboxClaimBatchSize = 50
+	githubOutboxClaimLease     = 2 * time.Minute
+	githubOutboxMaxAttempts    = 10
+	githubOutboxInitialBackoff = 15 * time.Second
+	githubOutboxMaxBackoff     = time.Hour
+)
+
+// GitHubEffectQueue queues issue comments and label changes for the outbox worker.
+type GitHubEffectQueue interface {
+	Enqueue(ctx context.Context, effects []*db.GitHubEffect) error
+}
+
+type githubOutboxStore interface {
+	GitHubEffectQueue
+	ClaimDue(ctx context.Context, region string, limit int, lease time.Duration) ([]*db.GitHubEffect, error)
+	MarkDone(ctx context.Context, id uuid.UUID) error
+	MarkRetry(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error
+}
+
+type outboxIssueClient interface {
+	CreateComment(ctx context.Context, repoFullName string, issueNumber int, body string) error
+	ListComments(ctx context.Context, repoFullName string, issueNumber int) ([]*github.IssueComment, error)
+	DeleteComment(ctx context.Context, repoFullName string, commentID int64) error
+	AddLabels(ctx context.Context, repoFullName string, issueNumber int, labels []string) error
+	RemoveLabel(ctx context.Context, repoFullName string, issueNumber int, label string) error
+}
+
+// GitHubOutboxService applies queued GitHub side effects with retries, so a GitHub outage after a
+// payment delays the issue update instead of losing it.
+type GitHubOutboxService struct {
+	store  githubOutboxStore
+	issues func(installationID int64) outboxIssueClient
+	region string
+	logger *slog.Logger
+}
+
+// NewGitHubOutboxService creates a worker that applies effects for shops homed in region. An empty
+// region covers every shop.
+func NewGitHubOutboxService(store *db.GitHubOutboxStore, githubClient *githubapp.Client, region string, logger *slog.Logger) *GitHubOutboxService {
+	var issues func(int64) outboxIssueClient
+	if githubClient != nil {
+		issues = func(installationID int64) outboxIssueClient {
+			return githubClient.WithInstallation(installationID)
+		}
+	}
+	return newGitHubOutboxService(store, issues, region, logger)
+}
+
+func newGitHubOutboxService(store githubOutboxStore, issues func(int64) outboxIssueClient, region string, logger *slog.Logger) *GitHubOutboxService {
+	return &GitHubOutboxService{store: store, issues: issues, region: region, logger: logger}
+}
+
+// Enqueue stores effects for the worker to apply.
+func (s *GitHubOutboxService) Enqueue(ctx context.Context, effects []*db.GitHubEffect) error {
+	if err := s.store.Enqueue(ctx, effects); err != nil {
+		return fmt.Errorf("failed to queue github side effects: %w", err)
+	}
+	observability.MeterFromContext(ctx).Count("github.outbox.queued", int64(len(effects)))
+	return nil
+}
+
+// Run applies due effects until ctx is cancelled.
+func (s *GitHubOutboxService) Run(ctx context.Context) {
+	if s == nil || s.store == nil || s.issues == nil {
+		return
+	}
+
+	ticker := time.NewTicker(githubOutboxPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.ApplyDue(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to apply github side effects", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ApplyDue applies every effect whose next attempt is due, one batch at a time.
+func (s *GitHubOutboxService) ApplyDue(ctx context.Context) error {
+	for {
+		effects, err := s.store.ClaimDue(ctx, s.region, githubOutboxClaimBatchSize, githubOutboxClaimLease)
+		if err != nil {
+			return fmt.Errorf("failed to claim github side effects: %w", err)
+		}
+		for _, effect := range effects {
+			s.process(ctx, effect)
+		}
+		if len(effects) < githubOutboxClaimBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (s *GitHubOutboxService) process(ctx context.Context, effect *db.GitHubEffect) {
+	logger := logging.FromContext(ctx, s.logger).With("effect_id", effect.ID, "kind", effect.Kind, "repo", effect.RepoFullName, "issue", effect.IssueNumber)
+	meter := observability.MeterFromContext(ctx)
+	kind := sentry.WithAttributes(attribute.String("kind", string(effect.Kind)))
+
+	applyErr := s.apply(ctx, effect)
+	if applyErr == nil {
+		if err := s.store.MarkDone(ctx, effect.ID); err != nil {
+			logger.Error("failed to record github side effect", "error", err)
+		}
+		meter.Count("github.outbox.applied", 1, kind)
+		return
+	}
+
+	attempt := effect.Attempts + 1
+	if attempt >= githubOutboxMaxAttempts {
+		if err := s.store.MarkFailed(ctx, effect.ID, applyErr.Error()); err != nil {
+			logger.Error("failed to record github side effect failure", "error", err)
+		}
+		meter.Count("github.outbox.failed", 1, kind)
+		logger.Warn("github side effect failed permanently", "error", applyErr, "attempts", attempt)
+		return
+	}
+
+	if err := s.store.MarkRetry(ctx, effect.ID, applyErr.Error(), time.Now().Add(githubOutboxBackoff(attempt))); err != nil {
+		logger.Error("failed to schedule github side effect retry", "error", err)
+	}
+	meter.Count("github.outbox.retried", 1, kind)
+	logger.Info("github side effect failed, will retry", "error", applyErr, "attempts", attempt)
+}
+
+func (s *GitHubOutboxService) apply(ctx context.Context, effect *db.GitHubEffect) error {
+	client := s.issues(effect.InstallationID)
+	switch effect.Kind {
+	case db.GitHubEffectComment:
+		marker := githubEffectMarker(effect.IdempotencyKey)
+		// An earlier attempt may have posted the comment before failing to record it.
+		if effect.Attempts > 0 {
+			comments, err := client.ListComments(ctx, effect.RepoFullName, effect.IssueNumber)
+			if err != nil {
+				return err
+			}
+			for _, comment := range comments {
+				if strings.Contains(comment.GetBody(), marker) {
+					return nil
+				}
+			}
+		}
+		return client.CreateComment(ctx, effect.RepoFullName, effect.IssueNumber, effect.Value+"\n\n"+marker)
+	case db.GitHubEffectAddLabel:
+		return client.AddLabels(ctx, effect.RepoFullName, effect.IssueNumber, []string{effect.Value})
+	case db.GitHubEffectRemoveLabel:
+		err := client.RemoveLabel(ctx, effect.RepoFullName, effect.IssueNumber, effect.Value)
+		if githubapp.IsNotFound(err) {
+			return nil
+		}
+		return err
+	case db.GitHubEffectDeleteCheckoutComments:
+		comments, err := client.ListComments(ctx, effect.RepoFullName, effect.IssueNumber)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if comment == nil || comment.ID == nil || !strings.Contains(comment.GetBody(), "gitshop:checkout-link") {
+				continue
+			}
+			if err := client.DeleteComment(ctx, effect.RepoFullName, comment.GetID()); err != nil && !githubapp.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown github side effect %q", effect.Kind)
+	}
+}
+
+// githubEffectMarker tags a queued comment so a retry can tell it was already posted.
+func githubEffectMarker(idempotencyKey string) string {
+	return "<!-- gitshop:effect:" + idempotencyKey + " -->"
+}
+
+// githubOutboxBackoff doubles the wait after each failed attempt, up to an hour.
+func githubOutboxBackoff(attempt int) time.Duration {
+	backoff := githubOutboxInitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= githubOutboxMaxBackoff {
+			return githubOutboxMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// paidOrderEffects are the issue updates for a paid order. Their keys are fixed per order, so a
+// repeated payment webhook does not post the comment twice.
+func paidOrderEffects(shop *db.Shop, order *db.Order, labels catalog.LabelsConfig, repoFullName string, issueNumber int, comment string) []*db.GitHubEffect {
+	effect := func(kind db.GitHubEffectKind, value string) *db.GitHubEffect {
+		return &db.GitHubEffect{
+			IdempotencyKey: fmt.Sprintf("order:%s:paid:%s", order.ID, kind),
+			ShopID:         shop.ID,
+			OrderID:        order.ID,
+			InstallationID: shop.GitHubInstallationID,
+			RepoFullName:   repoFullName,
+			IssueNumber:    issueNumber,
+			Kind:           kind,
+			Value:          value,
+		}
+	}
+	posted := effect(db.GitHubEffectComment, comment)
+	posted.EventKind = db.OrderEventCommentPosted
+	posted.EventDetail = "paid"
+	return []*db.GitHubEffect{
+		posted,
+		effect(db.GitHubEffectRemoveLabel, labels.Name(catalog.LabelPendingPayment)),
+		effect(db.GitHubEffectAddLabel, labels.Name(catalog.LabelPaid)),
+		effect(db.GitHubEffectDeleteCheckoutComments, ""),
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+type fakeGitHubOutboxStore struct {
+	due     []*db.GitHubEffect
+	done    []uuid.UUID
+	retried []uuid.UUID
+	failed  []uuid.UUID
+}
+
+func (s *fakeGitHubOutboxStore) Enqueue(context.Context, []*db.GitHubEffect) error {
+	return nil
+}
+
+func (s *fakeGitHubOutboxStore) ClaimDue(context.Context, string, int, time.Duration) ([]*db.GitHubEffect, error) {
+	due := s.due
+	s.due = nil
+	return due, nil
+}
+
+func (s *fakeGitHubOutboxStore) MarkDone(_ context.Context, id uuid.UUID) error {
+	s.done = append(s.done, id)
+	return nil
+}
+
+func (s *fakeGitHubOutboxStore) MarkRetry(_ context.Context, id uuid.UUID, _ string, _ time.Time) error {
+	s.retried = append(s.retried, id)
+	return nil
+}
+
+func (s *fakeGitHubOutboxStore) MarkFailed(_ context.Context, id uuid.UUID, _ string) error {
+	s.failed = append(s.failed, id)
+	return nil
+}
+
+type fakeOutboxIssueClient struct {
+	comments  []*github.IssueComment
+	created   []string
+	deleted   []int64
+	added     []string
+	removed   []string
+	createErr error
+}
+
+func (c *fakeOutboxIssueClient) CreateComment(_ context.Context, _ string, _ int, body string) error {
+	if c.createErr != nil {
+		return c.createErr
+	}
+	c.created = append(c.created, body)
+	return nil
+}
+
+func (c *fakeOutboxIssueClient) ListComments(context.Context, string, int) ([]*github.IssueComment, error) {
+	return c.comments, nil
+}
+
+func (c *fakeOutboxIssueClient) DeleteComment(_ context.Context, _ string, commentID int64) error {
+	c.deleted = append(c.deleted, commentID)
+	return nil
+}
+
+func (c *fakeOutboxIssueClient) AddLabels(_ context.Context, _ string, _ int, labels []string) error {
+	c.added = append(c.added, labels...)
+	return nil
+}
+
+func (c *fakeOutboxIssueClient) RemoveLabel(_ context.Context, _ string, _ int, label string) error {
+	c.removed = append(c.removed, label)
+	return nil
+}
+
+func TestGitHubOutboxService_ApplyDue(t *testing.T) {
+	t.Parallel()
+
+	shop := &db.Shop{ID: uuid.New(), GitHubInstallationID: 42}
+	order := &db.Order{ID: uuid.New()}
+	paidEffects := func() []*db.GitHubEffect {
+		effects := paidOrderEffects(shop, order, catalog.LabelsConfig{}, "octo/shop", 7, paymentReceivedComment(i18n.Localizer{}))
+		for _, effect := range effects {
+			effect.ID = uuid.New()
+		}
+		return effects
+	}
+	commentKey := paidOrderEffects(shop, order, catalog.LabelsConfig{}, "octo/shop", 7, paymentReceivedComment(i18n.Localizer{}))[0].IdempotencyKey
+
+	tests := []struct {
+		name        string
+		attempts    int
+		comments    []*github.IssueComment
+		createErr   error
+		wantCreated int
+		wantDeleted []int64
+		wantDone    int
+		wantRetried int
+		wantFailed  int
+	}{
+		{
+			name:        "applies paid effects",
+			comments:    []*github.IssueComment{{ID: github.Int64(1), Body: github.String("Pay here <!-- gitshop:checkout-link -->")}, {ID: github.Int64(2), Body: github.String("hello")}},
+			wantCreated: 1,
+			wantDeleted: []int64{1},
+			wantDone:    4,
+		},
+		{
+			name:     "retry skips a comment that was already posted",
+			attempts: 1,
+			comments: []*github.IssueComment{{ID: github.Int64(3), Body: github.String(paymentReceivedComment(i18n.Localizer{}) + "\n\n" + githubEffectMarker(commentKey))}},
+			wantDone: 4,
+		},
+		{
+			name:        "failed comment is retried",
+			createErr:   errors.New("github unavailable"),
+			wantDone:    3,
+			wantRetried: 1,
+		},
+		{
+			name:       "failed comment gives up after the last attempt",
+			attempts:   githubOutboxMaxAttempts - 1,
+			createErr:  errors.New("github unavailable"),
+			wantDone:   3,
+			wantFailed: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			effects := paidEffects()
+			for _, effect := range effects {
+				effect.Attempts = tt.attempts
+			}
+			store := &fakeGitHubOutboxStore{due: effects}
+			client := &fakeOutboxIssueClient{comments: tt.comments, createErr: tt.createErr}
+			service := newGitHubOutboxService(store, func(installationID int64) outboxIssueClient {
+				if installationID != shop.GitHubInstallationID {
+					t.Fatalf("installation = %d, want %d", installationID, shop.GitHubInstallationID)
+				}
+				return client
+			}, "", slog.New(slog.DiscardHandler))
+
+			if err := service.ApplyDue(t.Context()); err != nil {
+				t.Fatalf("ApplyDue() error = %v", err)
+			}
+			if len(client.created) != tt.wantCreated {
+				t.Fatalf("created comments = %q, want %d", client.created, tt.wantCreated)
+			}
+			for _, body := range client.created {
+				if !strings.HasSuffix(body, githubEffectMarker(commentKey)) {
+					t.Fatalf("comment %q missing idempotency marker", body)
+				}
+			}
+			if len(client.deleted) != len(tt.wantDeleted) || (len(tt.wantDeleted) > 0 && client.deleted[0] != tt.wantDeleted[0]) {
+				t.Fatalf("deleted comments = %v, want %v", client.deleted, tt.wantDeleted)
+			}
+			if len(client.removed) != 1 || client.removed[0] != "gitshop:status:pending-payment" {
+				t.Fatalf("removed labels = %v", client.removed)
+			}
+			if len(client.added) != 1 || client.added[0] != "gitshop:status:paid" {
+				t.Fatalf("added labels = %v", client.added)
+			}
+			if len(store.done) != tt.wantDone || len(store.retried) != tt.wantRetried || len(store.failed) != tt.wantFailed {
+				t.Fatalf("done/retried/failed = %d/%d/%d, want %d/%d/%d", len(store.done), len(store.retried), len(store.failed), tt.wantDone, tt.wantRetried, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestGitHubOutboxBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 15 * time.Second},
+		{attempt: 2, want: 30 * time.Second},
+		{attempt: 4, want: 2 * time.Minute},
+		{attempt: 9, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := githubOutboxBackoff(tt.attempt); got != tt.want {
+			t.Fatalf("githubOutboxBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+var (
+	ErrAdminInquiryUnavailable = errors.New("inquiry conversion unavailable")
+	ErrAdminOrderNotInquiry    = errors.New("order is not an inquiry")
+	ErrAdminInvalidQuote       = errors.New("invalid quote")
+)
+
+type InquiryConversionMethod string
+
+const (
+	InquiryConversionCheckout InquiryConversionMethod = "checkout"
+	InquiryConversionInvoice  InquiryConversionMethod = "invoice"
+)
+
+type ConvertInquiryInput struct {
+	ShopID        uuid.UUID
+	OrderID       uuid.UUID
+	Method        InquiryConversionMethod
+	SubtotalCents int64
+	ShippingCents int64
+	CustomerEmail string
+}
+
+// routeInquiry hands a bulk inquiry to the shop manager instead of starting checkout.
+func (s *OrderService) routeInquiry(ctx context.Context, client *githubapp.Client, loc i18n.Localizer, order *db.Order, product *catalog.ProductConfig, labels catalog.LabelsConfig, input IssueOpenedInput) error {
+	summary := s.appendManagerMention(ctx, client, input.RepoFullName, buildInquirySummary(loc, order, product))
+	if err := client.CreateComment(ctx, input.RepoFullName, input.IssueNumber, summary); err != nil {
+		return fmt.Errorf("failed to comment inquiry summary: %w", err)
+	}
+
+	s.ensureOrderNumberInTitle(ctx, client, input.RepoFullName, input.IssueNumber, order.OrderNumber, input.IssueTitle)
+
+	if err := client.AddLabels(ctx, input.RepoFullName, input.IssueNumber, []string{labels.Name(catalog.LabelInquiry)}); err != nil {
+		return fmt.Errorf("failed to add label: %w", err)
+	}
+	return nil
+}
+
+func buildInquirySummary(loc i18n.Localizer, order *db.Order, product *catalog.ProductConfig) string {
+	var b strings.Builder
+	b.WriteString(loc.T("comment.inquiry_received") + "\n\n")
+	b.WriteString("| | |\n|---|---|\n")
+	b.WriteString(loc.T("comment.inquiry_order", commentOrderNumber(order.OrderNumber)) + "\n")
+	b.WriteString(loc.T("comment.inquiry_product", product.Name, product.SKU) + "\n")
+	b.WriteString(loc.T("comment.inquiry_quantity", orderQuantity(order.Options)) + "\n")
+
+	keys := make([]string, 0, len(order.Options))
+	for key := range order.Options {
+		if key == "quantity" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := strings.ReplaceAll(fmt.Sprintf("%v", order.Options[key]), "\n", " ")
+		fmt.Fprintf(&b, "| %s | %s |\n", key, strings.ReplaceAll(value, "|", "\\|"))
+	}
+	if order.GitHubUsername != "" {
+		b.WriteString(loc.T("comment.inquiry_requested_by", order.GitHubUsername) + "\n")
+	}
+	if product.UnitPriceCents > 0 {
+		b.WriteString(loc.T("comment.inquiry_list_price", money.Format(product.UnitPriceCents)) + "\n")
+	}
+	return b.String()
+}
+
+// ConvertInquiry prices a bulk inquiry and sends the buyer either a checkout link or a Stripe
+// invoice. Payment then follows the normal paid, shipped, delivered flow.
+func (s *AdminService) ConvertInquiry(ctx context.Context, input ConvertInquiryInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.convert_inquiry",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("ConvertInquiry"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("order.inquiry.convert_failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	if s.stripePlatform == nil {
+		recordFailed("stripe_unavailable")
+		return fmt.Errorf("%w: stripe platform not configured", ErrAdminInquiryUnavailable)
+	}
+	if input.SubtotalCents <= 0 || input.ShippingCents < 0 {
+		recordFailed("invalid_quote")
+		return fmt.Errorf("%w: quote must be positive", ErrAdminInvalidQuote)
+	}
+	totalCents, err := money.Add(input.SubtotalCents, input.ShippingCents)
+	if err == nil {
+		err = money.CheckChargeable(totalCents)
+	}
+	if err != nil {
+		recordFailed("invalid_quote")
+		return fmt.Errorf("%w: %w", ErrAdminInvalidQuote, err)
+	}
+	switch input.Method {
+	case InquiryConversionCheckout:
+	case InquiryConversionInvoice:
+		if _, err := mail.ParseAddress(strings.TrimSpace(input.CustomerEmail)); err != nil {
+			recordFailed("invalid_email")
+			return fmt.Errorf("%w: a valid customer email is required for invoices", ErrAdminInvalidQuote)
+		}
+	default:
+		recordFailed("invalid_method")
+		return fmt.Errorf("%w: unknown conversion method %q", ErrAdminInvalidQuote, input.Method)
+	}
+
+	order, err := s.getShopOrder(ctx, OrderActionInput{ShopID: input.ShopID, OrderID: input.OrderID})
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return err
+	}
+	if order.Status != db.StatusInquiry {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: order status is %s", ErrAdminOrderNotInquiry, order.Status)
+	}
+
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	if shop.StripeConnectAccountID == "" {
+		recordFailed("stripe_not_connected")
+		return fmt.Errorf("%w: stripe account not connected", ErrAdminInquiryUnavailable)
+	}
+	if err := checkShopSuspension(ctx, s.shopStore, shop); err != nil {
+		recordFailed("shop_suspended")
+		return err
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	repoFullName := shop.GitHubRepoFullName
+	description := fmt.Sprintf("Bulk order %s x%d", order.SKU, orderQuantity(order.Options))
+	repoConfig := shopConfig(ctx, client, repoFullName)
+	loc := configLocalizer(repoConfig)
+	labels := shopLabels(repoConfig)
+
+	var comment string
+	switch input.Method {
+	case InquiryConversionCheckout:
+		carrier := ""
+		expiresIn := catalog.DefaultCheckoutExpiry
+		var customFields []stripe.CheckoutCustomField
+		collectTaxID := false
+		if content, fileErr := s.getGitShopFile(ctx, client, repoFullName, ""); fileErr == nil {
+			if config, parseErr := s.parser.Parse(content); parseErr == nil {
+				carrier = config.Shop.Shipping.Carrier
+				expiresIn = config.Shop.Checkout.ExpiresIn()
+				customFields = checkoutCustomFields(config.Shop.Checkout)
+				collectTaxID = config.Shop.Checkout.CollectTaxID
+			}
+		}
+		expiresAt := time.Now().Add(expiresIn)
+		session, err := s.stripePlatform.CreateCheckoutSession(stripe.WithTestMode(ctx, order.TestMode), stripe.CheckoutSessionParams{
+			OrderID:         order.ID,
+			ShopID:          shop.ID,
+			IssueNumber:     order.GitHubIssueNumber,
+			RepoFullName:    repoFullName,
+			ProductName:     description,
+			UnitPriceCents:  input.SubtotalCents,
+			Quantity:        1,
+			ShippingCents:   input.ShippingCents,
+			ShippingCarrier: carrier,
+			SuccessURL:      issueURL(order),
+			CancelURL:       issueURL(order),
+			StripeAccountID: shop.StripeConnectAccountID,
+			ExpiresAt:       expiresAt,
+			CustomFields:    customFields,
+			CollectTaxID:    collectTaxID,
+		})
+		if err != nil {
+			recordFailed("checkout_create_failed")
+			return fmt.Errorf("failed to create checkout session: %w", err)
+		}
+		if err := s.convertInquiryOrder(ctx, order.ID, input); err != nil {
+			recordFailed("convert_failed")
+			return err
+		}
+		if err := s.orderStore.UpdateStripeSession(ctx, order.ID, session.ID); err != nil {
+			recordFailed("update_session_failed")
+			return fmt.Errorf("failed to update order with session ID: %w", err)
+		}
+		if err := s.orderStore.SetCheckoutExpiresAt(ctx, order.ID, expiresAt); err != nil {
+			recordFailed("update_session_failed")
+			return fmt.Errorf("failed to record checkout expiry: %w", err)
+		}
+		comment = quoteCheckoutComment(loc, totalCents, session.URL, expiresIn)
+	case InquiryConversionInvoice:
+		invoice, err := s.stripePlatform.CreateInvoice(stripe.WithTestMode(ctx, order.TestMode), stripe.InvoiceParams{
+			OrderID:         order.ID,
+			ShopID:          shop.ID,
+			IssueNumber:     order.GitHubIssueNumber,
+			RepoFullName:    repoFullName,
+			Description:     description,
+			CustomerEmail:   strings.TrimSpace(input.CustomerEmail),
+			AmountCents:     input.SubtotalCents,
+			ShippingCents:   input.ShippingCents,
+			StripeAccountID: shop.StripeConnectAccountID,
+		})
+		if err != nil {
+			recordFailed("invoice_create_failed")
+			return fmt.Errorf("failed to create invoice: %w", err)
+		}
+		if err := s.convertInquiryOrder(ctx, order.ID, input); err != nil {
+			recordFailed("convert_failed")
+			return err
+		}
+		if err := s.orderStore.UpdateStripeInvoice(ctx, order.ID, invoice.ID); err != nil {
+			recordFailed("update_invoice_failed")
+			return fmt.Errorf("failed to update order with invoice ID: %w", err)
+		}
+		comment = quoteInvoiceComment(loc, totalCents, invoice.HostedInvoiceURL)
+	}
+
+	logger := s.loggerFromContext(ctx)
+	if err := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, comment); err != nil {
+		logger.Error("failed to create quote comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	if err := client.RemoveLabel(ctx, repoFullName, order.GitHubIssueNumber, labels.Name(catalog.LabelInquiry)); err != nil {
+		logger.Warn("failed to remove inquiry label", "error", err, "issue", order.GitHubIssueNumber)
+	}
+	if err := client.AddLabels(ctx, repoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelPendingPayment)}); err != nil {
+		logger.Warn("failed to add pending-payment label", "error", err, "issue", order.GitHubIssueNumber)
+	}
+	meter.Count("order.inquiry.converted", 1, sentry.WithAttributes(
+		attribute.String("method", string(input.Method)),
+	))
+
+	return nil
+}
+
+func (s *AdminService) convertInquiryOrder(ctx context.Context, orderID uuid.UUID, input ConvertInquiryInput) error {
+	if err := s.orderStore.ConvertInquiry(ctx, orderID, input.SubtotalCents, input.ShippingCents); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		return fmt.Errorf("failed to convert inquiry: %w", err)
+	}
+	return nil
+}
+
+// ParseQuoteCents converts a dollar amount such as "125" or "125.50" entered on the dashboard.
+func ParseQuoteCents(value string) (int64, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(value), "$")
+	if trimmed == "" {
+		return 0, nil
+	}
+	cents, err := money.Parse(strings.ReplaceAll(trimmed, ",", ""))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a valid amount", ErrAdminInvalidQuote, value)
+	}
+	return cents, nil
+}
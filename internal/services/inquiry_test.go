@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+func TestBuildInquirySummary(t *testing.T) {
+	t.Parallel()
+
+	order := &db.Order{
+		GitHubUsername: "octocat",
+		Options: map[string]any{
+			"quantity":      500,
+			"company":       "Octo Cafe",
+			"delivery_date": "March | April",
+		},
+	}
+	product := &catalog.ProductConfig{SKU: "BEANS_BULK", Name: "Wholesale Beans", Type: catalog.ProductTypeInquiry}
+
+	summary := buildInquirySummary(i18n.Localizer{}, order, product)
+	for _, want := range []string{
+		"| Product | Wholesale Beans (`BEANS_BULK`) |",
+		"| Quantity | 500 |",
+		"| company | Octo Cafe |",
+		"| delivery_date | March \\| April |",
+		"| Requested by | @octocat |",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Fatalf("expected summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+	if strings.Contains(summary, "List price") {
+		t.Fatalf("expected no list price for unpriced inquiry, got:\n%s", summary)
+	}
+
+	german := buildInquirySummary(i18n.New("de"), order, product)
+	for _, want := range []string{"**Großanfrage erhalten**", "| Menge | 500 |", "| Angefragt von | @octocat |"} {
+		if !strings.Contains(german, want) {
+			t.Fatalf("expected German summary to contain %q, got:\n%s", want, german)
+		}
+	}
+}
+
+func TestParseQuoteCents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "whole dollars", value: "125", want: 12500},
+		{name: "dollars and cents", value: "$1,250.50", want: 125050},
+		{name: "empty", value: " ", want: 0},
+		{name: "rounds to the cent", value: "19.995", want: 2000},
+		{name: "invalid", value: "abc", wantErr: true},
+		{name: "too large", value: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseQuoteCents(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrAdminInvalidQuote) {
+					t.Fatalf("expected ErrAdminInvalidQuote, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAdminService_ConvertInquiry_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{}
+
+	err := service.ConvertInquiry(t.Context(), ConvertInquiryInput{
+		ShopID:        uuid.New(),
+		OrderID:       uuid.New(),
+		Method:        InquiryConversionCheckout,
+		SubtotalCents: 10000,
+	})
+	if !errors.Is(err, ErrAdminInquiryUnavailable) {
+		t.Fatalf("expected ErrAdminInquiryUnavailable, got %v", err)
+	}
+}
+
+func TestAdminService_ConvertInquiry_RejectsUnchargeableTotal(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{stripePlatform: &stripe.PlatformClient{}}
+
+	err := service.ConvertInquiry(t.Context(), ConvertInquiryInput{
+		ShopID:        uuid.New(),
+		OrderID:       uuid.New(),
+		Method:        InquiryConversionCheckout,
+		SubtotalCents: money.MaxCents,
+		ShippingCents: 1,
+	})
+	if !errors.Is(err, ErrAdminInvalidQuote) || !errors.Is(err, money.ErrOverflow) {
+		t.Fatalf("expected an out-of-range quote to be rejected, got %v", err)
+	}
+}
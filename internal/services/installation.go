@@ -146,7 +146,9 @@ func (s *InstallationService) HandleInstallationEvent(ctx context.Context, event
 			"total_shops", len(shops))
 
 	case "suspend":
-		logger.Info("installation suspended - suspending shops", "installation_id", event.InstallationID)
+		// The account owner paused the GitHub App, so GitShop cannot act on the repositories. This
+		// disconnects the shops; platform suspensions for terms violations are separate.
+		logger.Info("installation suspended - disconnecting shops", "installation_id", event.InstallationID)
 
 		shops, err := s.shopStore.GetShopsByInstallationID(ctx, event.InstallationID)
 		if err != nil {
@@ -156,7 +158,7 @@ func (s *InstallationService) HandleInstallationEvent(ctx context.Context, event
 
 		suspendedCount := 0
 		for _, shop := range shops {
-			if err := s.shopStore.SuspendShop(ctx, event.InstallationID, shop.GitHubRepoID); err != nil {
+			if err := s.shopStore.DisconnectShop(ctx, event.InstallationID, shop.GitHubRepoID); err != nil {
 				logger.Error("failed to suspend shop", "error", err, "shop_id", shop.ID, "repo_id", shop.GitHubRepoID)
 			} else {
 				logger.Info("shop suspended", "shop_id", shop.ID, "repo_id", shop.GitHubRepoID)
@@ -170,7 +172,7 @@ func (s *InstallationService) HandleInstallationEvent(ctx context.Context, event
 			"total_shops", len(shops))
 
 	case "unsuspend":
-		logger.Info("installation unsuspended - unsuspending shops", "installation_id", event.InstallationID)
+		logger.Info("installation unsuspended - reconnecting shops", "installation_id", event.InstallationID)
 
 		shops, err := s.shopStore.GetShopsByInstallationID(ctx, event.InstallationID)
 		if err != nil {
@@ -180,7 +182,7 @@ func (s *InstallationService) HandleInstallationEvent(ctx context.Context, event
 
 		unsuspendedCount := 0
 		for _, shop := range shops {
-			if err := s.shopStore.UnsuspendShop(ctx, event.InstallationID, shop.GitHubRepoID); err != nil {
+			if err := s.shopStore.ReconnectShop(ctx, event.InstallationID, shop.GitHubRepoID); err != nil {
 				logger.Error("failed to unsuspend shop", "error", err, "shop_id", shop.ID, "repo_id", shop.GitHubRepoID)
 			} else {
 				logger.Info("shop unsuspended", "shop_id", shop.ID, "repo_id", shop.GitHubRepoID)
@@ -194,6 +196,9 @@ func (s *InstallationService) HandleInstallationEvent(ctx context.Context, event
 			"total_shops", len(shops))
 
 	case "new_permissions_accepted":
+		if s.githubClient != nil {
+			s.githubClient.ForgetContentAccess(event.InstallationID)
+		}
 		logger.Info("installation new permissions accepted",
 			"installation_id", event.InstallationID,
 			"event", "new_permissions_accepted")
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+// OverviewWindow is how far back the installation overview counts orders and revenue.
+const OverviewWindow = 30 * 24 * time.Hour
+
+// ShopOverview is one shop's row on the installation overview.
+type ShopOverview struct {
+	ShopID       uuid.UUID
+	RepoFullName string
+	Onboarded    bool
+	db.ShopOrderSummary
+	// ConfigBroken is set while a push has left gitshop.yaml or the order templates broken, or
+	// the last storefront check found them invalid.
+	ConfigBroken bool
+	// ConfigIssueURL links the tracking issue GitShop keeps open for a broken configuration.
+	ConfigIssueURL string
+}
+
+// NeedsAttention reports whether the seller has something to act on in this shop.
+func (o ShopOverview) NeedsAttention() bool {
+	return o.FailedPayments > 0 || o.UnshippedPaid > 0 || o.ConfigBroken
+}
+
+// InstallationOverview aggregates orders, revenue, and attention-needed items across the shops
+// of one installation, so owners of many shops do not have to open each one.
+type InstallationOverview struct {
+	Since  time.Time
+	Shops  []ShopOverview
+	Totals db.ShopOrderSummary
+	// BrokenConfigs counts shops whose configuration needs fixing.
+	BrokenConfigs int
+}
+
+// BuildInstallationOverview summarizes the given shops, those needing attention first. The caller
+// has already checked that the user may manage them. statuses holds each shop's cached storefront
+// status, when there is one; the overview never checks GitHub itself.
+func (s *AdminService) BuildInstallationOverview(ctx context.Context, shops []*db.Shop, statuses map[uuid.UUID]*RepoStatus, now time.Time) (*InstallationOverview, error) {
+	if s == nil || s.orderStore == nil || s.shopStore == nil {
+		return nil, fmt.Errorf("%w: store unavailable", ErrAdminServiceUnavailable)
+	}
+
+	since := now.Add(-OverviewWindow)
+	shopIDs := make([]uuid.UUID, 0, len(shops))
+	for _, shop := range shops {
+		shopIDs = append(shopIDs, shop.ID)
+	}
+	summaries, err := s.orderStore.SummarizeShopOrders(ctx, shopIDs, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize orders: %w", err)
+	}
+
+	configIssues := make(map[uuid.UUID]int, len(shops))
+	for _, shop := range shops {
+		number, err := s.shopStore.GetConfigIssueNumber(ctx, shop.ID)
+		if err != nil {
+			s.loggerFromContext(ctx).Warn("failed to load config issue", "error", err, "shop_id", shop.ID)
+			continue
+		}
+		configIssues[shop.ID] = number
+	}
+
+	return buildInstallationOverview(shops, summaries, configIssues, statuses, since), nil
+}
+
+func buildInstallationOverview(shops []*db.Shop, summaries map[uuid.UUID]db.ShopOrderSummary, configIssues map[uuid.UUID]int, statuses map[uuid.UUID]*RepoStatus, since time.Time) *InstallationOverview {
+	overview := &InstallationOverview{Since: since, Shops: make([]ShopOverview, 0, len(shops))}
+	for _, shop := range shops {
+		row := ShopOverview{
+			ShopID:           shop.ID,
+			RepoFullName:     shop.GitHubRepoFullName,
+			Onboarded:        shop.IsOnboarded(),
+			ShopOrderSummary: summaries[shop.ID],
+		}
+		if number := configIssues[shop.ID]; number > 0 {
+			row.ConfigBroken = true
+			row.ConfigIssueURL = "https://github.com/" + shop.GitHubRepoFullName + "/issues/" + strconv.Itoa(number)
+		}
+		if status := statuses[shop.ID]; status != nil && row.Onboarded {
+			row.ConfigBroken = row.ConfigBroken || !status.YAMLValid || !status.TemplateValid
+		}
+
+		overview.Totals.Orders += row.Orders
+		overview.Totals.RevenueCents += row.RevenueCents
+		overview.Totals.FailedPayments += row.FailedPayments
+		overview.Totals.UnshippedPaid += row.UnshippedPaid
+		if row.ConfigBroken {
+			overview.BrokenConfigs++
+		}
+		overview.Shops = append(overview.Shops, row)
+	}
+	slices.SortStableFunc(overview.Shops, func(a, b ShopOverview) int {
+		switch {
+		case a.NeedsAttention() == b.NeedsAttention():
+			return 0
+		case a.NeedsAttention():
+			return -1
+		default:
+			return 1
+		}
+	})
+	return overview
+}
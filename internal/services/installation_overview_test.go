@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestBuildInstallationOverview(t *testing.T) {
+	t.Parallel()
+
+	onboardedAt := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	quiet := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/quiet", OnboardedAt: onboardedAt}
+	busy := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/busy", OnboardedAt: onboardedAt}
+	broken := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/broken", OnboardedAt: onboardedAt}
+	invalid := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/invalid", OnboardedAt: onboardedAt}
+	setup := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/setup"}
+
+	summaries := map[uuid.UUID]db.ShopOrderSummary{
+		quiet.ID: {Orders: 2, RevenueCents: 5000},
+		busy.ID:  {Orders: 5, RevenueCents: 12500, FailedPayments: 1, UnshippedPaid: 3},
+	}
+	configIssues := map[uuid.UUID]int{broken.ID: 17}
+	statuses := map[uuid.UUID]*RepoStatus{
+		quiet.ID:   {YAMLValid: true, TemplateValid: true},
+		invalid.ID: {YAMLValid: true, TemplateValid: false},
+		// Shops still in setup are expected to be incomplete.
+		setup.ID: {},
+	}
+
+	overview := buildInstallationOverview([]*db.Shop{quiet, busy, broken, invalid, setup}, summaries, configIssues, statuses, onboardedAt)
+
+	if overview.Totals != (db.ShopOrderSummary{Orders: 7, RevenueCents: 17500, FailedPayments: 1, UnshippedPaid: 3}) {
+		t.Fatalf("totals = %+v", overview.Totals)
+	}
+	if overview.BrokenConfigs != 2 {
+		t.Fatalf("broken configs = %d, want 2", overview.BrokenConfigs)
+	}
+
+	got := make([]string, 0, len(overview.Shops))
+	for _, shop := range overview.Shops {
+		got = append(got, shop.RepoFullName)
+	}
+	want := []string{"octo/busy", "octo/broken", "octo/invalid", "octo/quiet", "octo/setup"}
+	if len(got) != len(want) {
+		t.Fatalf("shops = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("shops = %v, want %v", got, want)
+		}
+	}
+
+	if url := overview.Shops[1].ConfigIssueURL; url != "https://github.com/octo/broken/issues/17" {
+		t.Fatalf("config issue URL = %q", url)
+	}
+	if overview.Shops[4].Onboarded || overview.Shops[4].NeedsAttention() {
+		t.Fatalf("setup shop = %+v, want not onboarded and nothing to do", overview.Shops[4])
+	}
+}
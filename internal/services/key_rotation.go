@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const keyRotationReportInterval = time.Hour
+
+type keyRotationSecretStore interface {
+	StaleCounts(ctx context.Context) (map[string]int, error)
+	RotateShopSecrets(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error)
+	RotateWebhookSecrets(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error)
+}
+
+type keyRotationOrderStore interface {
+	EncryptCustomerData(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error)
+}
+
+// KeyRotationService re-encrypts stored secrets and customer details with the newest encryption
+// key, and reports how many rows are still on older keys so operators can tell when an old key
+// can be removed.
+type KeyRotationService struct {
+	secretStore keyRotationSecretStore
+	orderStore  keyRotationOrderStore
+	keyID       string
+	logger      *slog.Logger
+}
+
+func NewKeyRotationService(secretStore *db.KeyRotationStore, orderStore *db.OrderStore, keyID string, logger *slog.Logger) *KeyRotationService {
+	return newKeyRotationService(secretStore, orderStore, keyID, logger)
+}
+
+func newKeyRotationService(secretStore keyRotationSecretStore, orderStore keyRotationOrderStore, keyID string, logger *slog.Logger) *KeyRotationService {
+	return &KeyRotationService{
+		secretStore: secretStore,
+		orderStore:  orderStore,
+		keyID:       keyID,
+		logger:      logger,
+	}
+}
+
+// Run reports stale rows every hour until ctx is cancelled.
+func (s *KeyRotationService) Run(ctx context.Context) {
+	if s == nil || s.secretStore == nil {
+		return
+	}
+
+	ticker := time.NewTicker(keyRotationReportInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.ReportStale(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to count rows on old encryption keys", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReportStale counts the rows of each secret kind not yet on the current key and records them as
+// gauges.
+func (s *KeyRotationService) ReportStale(ctx context.Context) (map[string]int, error) {
+	counts, err := s.secretStore.StaleCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	meter := observability.MeterFromContext(ctx)
+	for kind, count := range counts {
+		meter.Gauge("crypto.key_rotation.stale_rows", float64(count), sentry.WithAttributes(
+			attribute.String("kind", kind),
+			attribute.String("key_id", s.keyID),
+		))
+	}
+	return counts, nil
+}
+
+// Rotate re-encrypts everything on older keys in batches and returns how many rows of each table
+// were rewritten. It stops at the first row that cannot be decrypted with any configured key.
+func (s *KeyRotationService) Rotate(ctx context.Context, batchSize int) (map[string]int, error) {
+	passes := []struct {
+		table  string
+		rotate func(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error)
+	}{
+		{table: "shops", rotate: s.secretStore.RotateShopSecrets},
+		{table: "shop_webhooks", rotate: s.secretStore.RotateWebhookSecrets},
+		{table: "orders", rotate: s.orderStore.EncryptCustomerData},
+	}
+
+	rotated := make(map[string]int, len(passes))
+	for _, pass := range passes {
+		after := uuid.Nil
+		for {
+			next, updated, err := pass.rotate(ctx, after, batchSize)
+			rotated[pass.table] += updated
+			if err != nil {
+				return rotated, fmt.Errorf("failed to rotate %s: %w", pass.table, err)
+			}
+			if next == uuid.Nil {
+				break
+			}
+			after = next
+		}
+		s.logger.Info("rotated encryption key", "table", pass.table, "rows", rotated[pass.table], "key_id", s.keyID)
+	}
+	return rotated, nil
+}
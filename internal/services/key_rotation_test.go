@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+// fakeKeyRotationPages serves rotation batches from a list of pages, each rewriting one row.
+type fakeKeyRotationPages struct {
+	ids   []uuid.UUID
+	calls int
+	err   error
+}
+
+func (p *fakeKeyRotationPages) rotate(_ context.Context, after uuid.UUID, _ int) (uuid.UUID, int, error) {
+	p.calls++
+	if p.err != nil {
+		return after, 0, p.err
+	}
+	for i, id := range p.ids {
+		if after == uuid.Nil || (i > 0 && p.ids[i-1] == after) {
+			return id, 1, nil
+		}
+	}
+	return uuid.Nil, 0, nil
+}
+
+type fakeKeyRotationSecretStore struct {
+	shops    *fakeKeyRotationPages
+	webhooks *fakeKeyRotationPages
+	stale    map[string]int
+}
+
+func (s *fakeKeyRotationSecretStore) StaleCounts(context.Context) (map[string]int, error) {
+	return s.stale, nil
+}
+
+func (s *fakeKeyRotationSecretStore) RotateShopSecrets(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	return s.shops.rotate(ctx, after, limit)
+}
+
+func (s *fakeKeyRotationSecretStore) RotateWebhookSecrets(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	return s.webhooks.rotate(ctx, after, limit)
+}
+
+type fakeKeyRotationOrderStore struct {
+	orders *fakeKeyRotationPages
+}
+
+func (s *fakeKeyRotationOrderStore) EncryptCustomerData(ctx context.Context, after uuid.UUID, limit int) (uuid.UUID, int, error) {
+	return s.orders.rotate(ctx, after, limit)
+}
+
+func TestKeyRotationService_Rotate(t *testing.T) {
+	t.Parallel()
+
+	secrets := &fakeKeyRotationSecretStore{
+		shops:    &fakeKeyRotationPages{ids: []uuid.UUID{uuid.New(), uuid.New()}},
+		webhooks: &fakeKeyRotationPages{},
+		stale:    map[string]int{db.SecretKindShopEmail: 0},
+	}
+	orders := &fakeKeyRotationOrderStore{orders: &fakeKeyRotationPages{ids: []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}}}
+	service := newKeyRotationService(secrets, orders, "k2", slog.Default())
+
+	rotated, err := service.Rotate(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if rotated["shops"] != 2 || rotated["shop_webhooks"] != 0 || rotated["orders"] != 3 {
+		t.Fatalf("Rotate() = %v", rotated)
+	}
+	if secrets.shops.calls != 3 || secrets.webhooks.calls != 1 || orders.orders.calls != 4 {
+		t.Fatalf("batch calls = %d shops, %d webhooks, %d orders", secrets.shops.calls, secrets.webhooks.calls, orders.orders.calls)
+	}
+
+	stale, err := service.ReportStale(t.Context())
+	if err != nil || stale[db.SecretKindShopEmail] != 0 {
+		t.Fatalf("ReportStale() = %v, %v", stale, err)
+	}
+}
+
+func TestKeyRotationService_RotateStopsOnError(t *testing.T) {
+	t.Parallel()
+
+	secrets := &fakeKeyRotationSecretStore{
+		shops:    &fakeKeyRotationPages{err: errors.New("does not decrypt")},
+		webhooks: &fakeKeyRotationPages{},
+	}
+	orders := &fakeKeyRotationOrderStore{orders: &fakeKeyRotationPages{}}
+	service := newKeyRotationService(secrets, orders, "k2", slog.Default())
+
+	if _, err := service.Rotate(t.Context(), 100); err == nil {
+		t.Fatal("Rotate() should fail when a row does not decrypt")
+	}
+	if secrets.webhooks.calls != 0 || orders.orders.calls != 0 {
+		t.Fatal("Rotate() should stop at the first failing table")
+	}
+}
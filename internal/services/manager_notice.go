@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	managerNoticeWindow    = time.Hour
+	managerNoticeMaxIssues = 20
+)
+
+// managerNotice is the comment that mentioned the shop manager about one class of order failure.
+type managerNotice struct {
+	IssueNumber int       `json:"issue_number"`
+	CommentID   int64     `json:"comment_id"`
+	Body        string    `json:"body"`
+	Affected    []int     `json:"affected,omitempty"`
+	MoreCount   int       `json:"more_count,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func managerNoticeKey(repoFullName, class string) string {
+	return fmt.Sprintf("manager_notice:%s:%s", strings.ToLower(repoFullName), class)
+}
+
+// commentWithManagerNotice tells the buyer why their order failed. The first failure of a class
+// in a repo each hour mentions the shop manager; later ones skip the mention and are listed on
+// that first comment instead, so a broken gitshop.yaml does not page the manager on every order.
+func (s *OrderService) commentWithManagerNotice(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, class, message string) error {
+	config := s.shopManagerConfig(ctx, client, repoFullName)
+	loc := configLocalizer(config)
+	if s.cacheProvider == nil {
+		return client.CreateComment(ctx, repoFullName, issueNumber, withManagerMention(loc, message, configManagerMention(config)))
+	}
+
+	logger := s.loggerFromContext(ctx)
+	key := managerNoticeKey(repoFullName, class)
+	if notice, ok := s.activeManagerNotice(ctx, key); ok && notice.IssueNumber != issueNumber {
+		observability.MeterFromContext(ctx).Count("order.manager_notice.consolidated", 1, sentry.WithAttributes(
+			attribute.String("reason", class),
+		))
+		if err := client.CreateComment(ctx, repoFullName, issueNumber, message+"\n\n"+loc.T("comment.manager_already_notified", notice.IssueNumber)); err != nil {
+			return err
+		}
+
+		notice.addAffected(issueNumber)
+		if err := client.UpdateComment(ctx, repoFullName, notice.CommentID, notice.render(loc)); err != nil {
+			logger.Warn("failed to update manager notice", "error", err, "repo", repoFullName, "comment_id", notice.CommentID)
+		}
+		s.saveManagerNotice(ctx, key, notice)
+		return nil
+	}
+
+	body := withManagerMention(loc, message, configManagerMention(config))
+	commentID, err := client.CreateCommentWithID(ctx, repoFullName, issueNumber, body)
+	if err != nil {
+		return err
+	}
+	s.saveManagerNotice(ctx, key, managerNotice{
+		IssueNumber: issueNumber,
+		CommentID:   commentID,
+		Body:        body,
+		ExpiresAt:   time.Now().Add(managerNoticeWindow),
+	})
+	return nil
+}
+
+func (s *OrderService) activeManagerNotice(ctx context.Context, key string) (managerNotice, bool) {
+	cached, err := s.cacheProvider.Get(ctx, key)
+	if err != nil || cached == "" {
+		return managerNotice{}, false
+	}
+	var notice managerNotice
+	if err := json.Unmarshal([]byte(cached), &notice); err != nil || notice.CommentID == 0 || !time.Now().Before(notice.ExpiresAt) {
+		return managerNotice{}, false
+	}
+	return notice, true
+}
+
+// saveManagerNotice keeps the notice until its original window ends, so consolidating into it
+// never extends how long the manager goes without a fresh mention.
+func (s *OrderService) saveManagerNotice(ctx context.Context, key string, notice managerNotice) {
+	ttl := time.Until(notice.ExpiresAt)
+	if ttl <= 0 {
+		return
+	}
+	encoded, err := json.Marshal(notice)
+	if err != nil {
+		return
+	}
+	if err := s.cacheProvider.Set(ctx, key, string(encoded), ttl); err != nil {
+		s.loggerFromContext(ctx).Warn("failed to store manager notice", "error", err, "key", key)
+	}
+}
+
+func (n *managerNotice) addAffected(issueNumber int) {
+	for _, existing := range n.Affected {
+		if existing == issueNumber {
+			return
+		}
+	}
+	if len(n.Affected) >= managerNoticeMaxIssues {
+		n.MoreCount++
+		return
+	}
+	n.Affected = append(n.Affected, issueNumber)
+}
+
+func (n managerNotice) render(loc i18n.Localizer) string {
+	if len(n.Affected) == 0 {
+		return n.Body
+	}
+	refs := make([]string, 0, len(n.Affected))
+	for _, issue := range n.Affected {
+		refs = append(refs, fmt.Sprintf("#%d", issue))
+	}
+	line := loc.T("comment.manager_notice_affected", strings.Join(refs, ", "))
+	if n.MoreCount > 0 {
+		line = loc.T("comment.manager_notice_affected_more", strings.Join(refs, ", "), n.MoreCount)
+	}
+	return n.Body + "\n\n" + line
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+func TestManagerNoticeRender(t *testing.T) {
+	t.Parallel()
+
+	notice := managerNotice{Body: "❌ `gitshop.yaml` is invalid.\n\nShop manager: @owner"}
+	if got := notice.render(i18n.Localizer{}); got != notice.Body {
+		t.Fatalf("expected untouched body without affected issues, got %q", got)
+	}
+
+	for issue := 10; issue < 10+managerNoticeMaxIssues+2; issue++ {
+		notice.addAffected(issue)
+	}
+	notice.addAffected(10)
+
+	rendered := notice.render(i18n.Localizer{})
+	if !strings.HasPrefix(rendered, notice.Body) {
+		t.Fatalf("expected original body to be kept, got %q", rendered)
+	}
+	if !strings.Contains(rendered, "**Also affected:** #10, #11") || !strings.HasSuffix(rendered, " and 2 more") {
+		t.Fatalf("unexpected affected line: %q", rendered)
+	}
+	if len(notice.Affected) != managerNoticeMaxIssues {
+		t.Fatalf("expected %d listed issues, got %d", managerNoticeMaxIssues, len(notice.Affected))
+	}
+
+	german := notice.render(i18n.New("de"))
+	if !strings.Contains(german, "**Ebenfalls betroffen:** #10, #11") || !strings.HasSuffix(german, " und 2 weitere") {
+		t.Fatalf("unexpected German affected line: %q", german)
+	}
+}
+
+func TestWithManagerMentionLocalized(t *testing.T) {
+	t.Parallel()
+
+	if got := withManagerMention(i18n.Localizer{}, "❌ Broken.", ""); got != "❌ Broken." {
+		t.Fatalf("expected no mention without a manager, got %q", got)
+	}
+	if got := withManagerMention(i18n.Localizer{}, "❌ Broken.", "@owner"); got != "❌ Broken.\n\nShop manager: @owner" {
+		t.Fatalf("unexpected English mention: %q", got)
+	}
+	if got := withManagerMention(i18n.New("fr"), "❌ Cassé.", "@owner"); got != "❌ Cassé.\n\nResponsable de la boutique : @owner" {
+		t.Fatalf("unexpected French mention: %q", got)
+	}
+}
+
+func TestActiveManagerNotice(t *testing.T) {
+	t.Parallel()
+
+	provider, err := cache.NewMemoryProvider()
+	if err != nil {
+		t.Fatalf("NewMemoryProvider: %v", err)
+	}
+	service := &OrderService{cacheProvider: provider}
+	ctx := context.Background()
+	key := managerNoticeKey("Acme/Shop", "config_invalid")
+	if key != managerNoticeKey("acme/shop", "config_invalid") {
+		t.Fatal("expected repo names to be case-insensitive")
+	}
+
+	if _, ok := service.activeManagerNotice(ctx, key); ok {
+		t.Fatal("expected no notice before one is saved")
+	}
+
+	service.saveManagerNotice(ctx, key, managerNotice{IssueNumber: 7, CommentID: 42, ExpiresAt: time.Now().Add(time.Minute)})
+	notice, ok := service.activeManagerNotice(ctx, key)
+	if !ok || notice.IssueNumber != 7 || notice.CommentID != 42 {
+		t.Fatalf("expected saved notice, got %+v (ok=%v)", notice, ok)
+	}
+
+	expiredKey := managerNoticeKey("acme/shop", "sku_missing")
+	service.saveManagerNotice(ctx, expiredKey, managerNotice{IssueNumber: 8, CommentID: 43, ExpiresAt: time.Now().Add(-time.Second)})
+	if _, ok := service.activeManagerNotice(ctx, expiredKey); ok {
+		t.Fatal("expected an expired notice not to be stored")
+	}
+}
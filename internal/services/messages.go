@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/money"
+)
+
+// Comments posted on order issues as the order moves through its lifecycle. The wording lives in
+// the i18n catalogs so shops can pick a locale; message previews and golden tests cover the
+// English copy.
+func paymentReceivedComment(loc i18n.Localizer) string { return loc.T("comment.payment_received") }
+func checkoutExpiredComment(loc i18n.Localizer) string { return loc.T("comment.checkout_expired") }
+func paymentFailedComment(loc i18n.Localizer) string   { return loc.T("comment.payment_failed") }
+func orderShippedComment(loc i18n.Localizer) string    { return loc.T("comment.order_shipped") }
+func shipmentUpdatedComment(loc i18n.Localizer) string { return loc.T("comment.shipment_updated") }
+func orderDeliveredComment(loc i18n.Localizer) string  { return loc.T("comment.order_delivered") }
+func orderRefundedComment(loc i18n.Localizer) string   { return loc.T("comment.order_refunded") }
+
+func orderReturnedComment(loc i18n.Localizer, refunded bool) string {
+	if refunded {
+		return loc.T("comment.order_returned_refunded")
+	}
+	return loc.T("comment.order_returned")
+}
+
+// configLocalizer returns the localizer for the shop's configured locale, or English when there
+// is no config.
+func configLocalizer(config *catalog.GitShopConfig) i18n.Localizer {
+	if config == nil {
+		return i18n.Localizer{}
+	}
+	return i18n.New(config.Shop.Locale)
+}
+
+// shopLocalizer reads the shop's locale from gitshop.yaml for code paths that do not otherwise
+// need the config. Any failure falls back to English so a comment is still posted.
+func shopLocalizer(ctx context.Context, files repoFileReader, repoFullName string) i18n.Localizer {
+	return configLocalizer(shopConfig(ctx, files, repoFullName))
+}
+
+type repoFileReader interface {
+	GetFile(ctx context.Context, repoFullName, path, ref string) ([]byte, error)
+}
+
+// shopConfig parses gitshop.yaml without validating it, for settings such as the locale and
+// branding that should still apply while another part of the file is broken. It returns nil
+// when the file is missing or does not parse.
+func shopConfig(ctx context.Context, files repoFileReader, repoFullName string) *catalog.GitShopConfig {
+	parser := catalog.NewParser()
+	for _, path := range []string{"gitshop.yaml", "gitshop.yml"} {
+		content, err := files.GetFile(ctx, repoFullName, path, "")
+		if err != nil {
+			continue
+		}
+		config, err := parser.Parse(content)
+		if err != nil {
+			return nil
+		}
+		return config
+	}
+	return nil
+}
+
+// shopBranding returns the branding from gitshop.yaml, or the default when the config is
+// missing or its branding is invalid. Callers may get an unvalidated config from shopConfig, and
+// branding values end up unescaped in emails.
+func shopBranding(config *catalog.GitShopConfig) catalog.BrandingConfig {
+	if config == nil || catalog.ValidateBranding(config.Branding) != nil {
+		return catalog.BrandingConfig{}
+	}
+	return config.Branding
+}
+
+// commentOrderNumber formats an order number for issue comments. It is wrapped in code so GitHub
+// does not link it to the unrelated issue that happens to have the same number.
+func commentOrderNumber(orderNumber int) string {
+	return fmt.Sprintf("`#%d`", orderNumber)
+}
+
+// checkoutExpiryText spells out how long a checkout link stays open, in hours when it is a whole
+// number of them.
+func checkoutExpiryText(loc i18n.Localizer, expiresIn time.Duration) string {
+	minutes := int(expiresIn.Round(time.Minute) / time.Minute)
+	switch {
+	case minutes == 60:
+		return loc.T("duration.hour")
+	case minutes > 60 && minutes%60 == 0:
+		return loc.T("duration.hours", minutes/60)
+	case minutes == 1:
+		return loc.T("duration.minute")
+	default:
+		return loc.T("duration.minutes", minutes)
+	}
+}
+
+// checkoutLinkMarker lets GitShop find a checkout comment again when its link stops working.
+const checkoutLinkMarker = "\n\n<!-- gitshop:checkout-link -->"
+
+func checkoutLinkComment(loc i18n.Localizer, orderNumber int, checkoutURL string, expiresIn time.Duration) string {
+	return loc.T("comment.checkout_link", commentOrderNumber(orderNumber), checkoutURL, checkoutExpiryText(loc, expiresIn)) + checkoutLinkMarker
+}
+
+func identityVerifiedCheckoutComment(loc i18n.Localizer, orderNumber int, checkoutURL string, expiresIn time.Duration) string {
+	return loc.T("comment.identity_verified_checkout", commentOrderNumber(orderNumber), checkoutURL, checkoutExpiryText(loc, expiresIn)) + checkoutLinkMarker
+}
+
+func checkoutReminderComment(loc i18n.Localizer, orderNumber int, remaining time.Duration) string {
+	return loc.T("comment.checkout_reminder", commentOrderNumber(orderNumber), checkoutExpiryText(loc, remaining))
+}
+
+func orderEditedCheckoutComment(loc i18n.Localizer, orderNumber int, totalCents int64, checkoutURL string, expiresIn time.Duration) string {
+	return loc.T("comment.order_edited_checkout", commentOrderNumber(orderNumber), money.Format(totalCents), checkoutURL, checkoutExpiryText(loc, expiresIn)) + checkoutLinkMarker
+}
+
+func orderEditRejectedComment(loc i18n.Localizer, orderNumber int, reason string) string {
+	return loc.T("comment.order_edit_rejected", commentOrderNumber(orderNumber), reason)
+}
+
+// optionAnswerMessage explains to the buyer which rule their answer to a text option broke.
+func optionAnswerMessage(loc i18n.Localizer, err *catalog.OptionAnswerError) string {
+	switch err.Reason {
+	case catalog.OptionAnswerTooShort, catalog.OptionAnswerTooLong:
+		return loc.T("option."+err.Reason, err.Option.Label, err.Limit)
+	default:
+		return loc.T("option."+err.Reason, err.Option.Label)
+	}
+}
+
+// orderParseMessage explains to the buyer why their order issue could not be read.
+func orderParseMessage(loc i18n.Localizer, err error) string {
+	if errors.Is(err, errNoSKU) {
+		return loc.T("parse.no_sku")
+	}
+	return loc.T("parse.unreadable")
+}
+
+// pricingFailedMessage explains to the buyer why an order for sku could not be priced.
+func pricingFailedMessage(loc i18n.Localizer, err error, sku string) string {
+	switch {
+	case errors.Is(err, catalog.ErrProductInactive):
+		return loc.T("pricing.product_inactive", sku)
+	case errors.Is(err, catalog.ErrProductNotFound):
+		return loc.T("pricing.product_not_found", sku)
+	case errors.Is(err, money.ErrOverflow):
+		return loc.T("pricing.total_too_large")
+	default:
+		return loc.T("pricing.unavailable")
+	}
+}
+
+func pricingFailedComment(loc i18n.Localizer, err error, sku string) string {
+	return loc.T("comment.pricing_failed", pricingFailedMessage(loc, err, sku))
+}
+
+func optionInvalidComment(loc i18n.Localizer, err *catalog.OptionAnswerError) string {
+	return loc.T("comment.option_invalid", optionAnswerMessage(loc, err))
+}
+
+func orderCancelledComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.order_cancelled", commentOrderNumber(orderNumber))
+}
+
+func orderHeldComment(loc i18n.Localizer, orderNumber int, totalCents int64) string {
+	return loc.T("comment.order_held", commentOrderNumber(orderNumber), money.Format(totalCents))
+}
+
+func approvedIdentityRequiredComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.approved_identity_required", commentOrderNumber(orderNumber))
+}
+
+func orderRejectedComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.order_rejected", commentOrderNumber(orderNumber))
+}
+
+// addressIssueComment asks the buyer to confirm their address privately; order issues can be
+// public, so the address itself is never posted.
+func addressIssueComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.address_issue", commentOrderNumber(orderNumber))
+}
+
+func addressConfirmedComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.address_confirmed", commentOrderNumber(orderNumber))
+}
+
+func subscriptionRenewedComment(loc i18n.Localizer, orderNumber int, amountCents int64) string {
+	return loc.T("comment.subscription_renewed", commentOrderNumber(orderNumber), money.Format(amountCents))
+}
+
+func subscriptionCancelledComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.subscription_cancelled", commentOrderNumber(orderNumber))
+}
+
+// shopComment renders a comment template from the shop's gitshop.yaml. It returns fallback when
+// the shop kept the default or the template fails to render, so a bad template never blocks an
+// order update. Either way the shop's branding footer is added.
+func shopComment(config *catalog.GitShopConfig, text string, data catalog.MessageData, fallback string) string {
+	body, ok := renderShopMessage(config, text, data)
+	if !ok {
+		body = fallback
+	}
+	return body + brandingFooter(configLocalizer(config), config)
+}
+
+// shopCheckoutLinkComment renders checkout_link from gitshop.yaml, keeping the marker that lets
+// GitShop find the comment again when the link stops working.
+func shopCheckoutLinkComment(config *catalog.GitShopConfig) func(i18n.Localizer, int, string, time.Duration) string {
+	return func(loc i18n.Localizer, orderNumber int, checkoutURL string, expiresIn time.Duration) string {
+		var body string
+		ok := false
+		if config != nil {
+			body, ok = renderShopMessage(config, config.Messages.CheckoutLink, catalog.MessageData{
+				OrderNumber: orderNumber,
+				CheckoutURL: checkoutURL,
+				ExpiresIn:   checkoutExpiryText(loc, expiresIn),
+			})
+		}
+		if !ok {
+			body = strings.TrimSuffix(checkoutLinkComment(loc, orderNumber, checkoutURL, expiresIn), checkoutLinkMarker)
+		}
+		return body + brandingFooter(loc, config) + checkoutLinkMarker
+	}
+}
+
+func renderShopMessage(config *catalog.GitShopConfig, text string, data catalog.MessageData) (string, bool) {
+	if config == nil || text == "" {
+		return "", false
+	}
+	data.ShopName = config.Shop.Name
+	body, err := catalog.RenderMessage(text, data)
+	if err != nil || body == "" {
+		return "", false
+	}
+	return body, true
+}
+
+// brandingFooter is the small line under bot comments pointing buyers at the shop's support
+// email and site. It is empty when gitshop.yaml sets neither.
+func brandingFooter(loc i18n.Localizer, config *catalog.GitShopConfig) string {
+	branding := shopBranding(config)
+	var parts []string
+	if branding.SupportEmail != "" {
+		parts = append(parts, loc.T("branding.support", branding.SupportEmail))
+	}
+	if branding.ShopURL != "" {
+		name := strings.TrimSpace(config.Shop.Name)
+		if name == "" {
+			name = branding.ShopURL
+		}
+		parts = append(parts, fmt.Sprintf("[%s](%s)", name, branding.ShopURL))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "\n\n<sub>" + strings.Join(parts, " · ") + "</sub>"
+}
+
+// paymentLinkComment is sent instead of a checkout link when Stripe could not create a Checkout
+// Session. Payment Links stay open until paid, so it has no expiry notice.
+func paymentLinkComment(loc i18n.Localizer, orderNumber int, paymentLinkURL string) string {
+	return loc.T("comment.payment_link", commentOrderNumber(orderNumber), paymentLinkURL) + checkoutLinkMarker
+}
+
+func quoteCheckoutComment(loc i18n.Localizer, totalCents int64, checkoutURL string, expiresIn time.Duration) string {
+	return loc.T("comment.quote_checkout", money.Format(totalCents), checkoutURL, checkoutExpiryText(loc, expiresIn)) + checkoutLinkMarker
+}
+
+func quoteInvoiceComment(loc i18n.Localizer, totalCents int64, hostedInvoiceURL string) string {
+	if hostedInvoiceURL != "" {
+		return loc.T("comment.quote_invoice_with_link", money.Format(totalCents), hostedInvoiceURL)
+	}
+	return loc.T("comment.quote_invoice", money.Format(totalCents))
+}
+
+// reviewRequestComment invites the buyer to rate a delivered order. closeAfterDays is 0 when the
+// issue stays open.
+func reviewRequestComment(loc i18n.Localizer, orderNumber, closeAfterDays int) string {
+	body := loc.T("comment.review_request", commentOrderNumber(orderNumber))
+	if closeAfterDays > 0 {
+		body += "\n\n" + loc.T("comment.review_request_closes", closeAfterDays)
+	}
+	return body
+}
+
+func reviewThanksComment(loc i18n.Localizer, orderNumber, rating int) string {
+	return loc.T("comment.review_thanks", commentOrderNumber(orderNumber), reviewStars(rating))
+}
+
+func reviewUsageComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.review_usage", commentOrderNumber(orderNumber))
+}
+
+func reviewNotDeliveredComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.review_not_delivered", commentOrderNumber(orderNumber))
+}
+
+func reviewClosedComment(loc i18n.Localizer, orderNumber int) string {
+	return loc.T("comment.review_closed", commentOrderNumber(orderNumber))
+}
+
+// reviewStars draws a 1 to 5 rating as filled and empty stars.
+func reviewStars(rating int) string {
+	rating = min(max(rating, 0), 5)
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+var ErrMessagePreviewNotFound = errors.New("message preview not found")
+
+type MessageKind string
+
+const (
+	MessageKindComment MessageKind = "comment"
+	MessageKindEmail   MessageKind = "email"
+)
+
+// MessagePreview is one rendered bot comment or email. Emails carry both bodies; comments only
+// have Text.
+type MessagePreview struct {
+	Name    string
+	Kind    MessageKind
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// PreviewOrderStatuses lists the order states that have message previews, in lifecycle order.
+func PreviewOrderStatuses() []db.OrderStatus {
+	return []db.OrderStatus{
+		db.StatusInquiry,
+		db.StatusAwaitingApproval,
+		db.StatusPendingPayment,
+		db.StatusPaid,
+		db.StatusAddressIssue,
+		db.StatusShipped,
+		db.StatusDelivered,
+		db.StatusExpired,
+		db.StatusCancelled,
+		db.StatusPaymentFailed,
+		db.StatusRefunded,
+		db.StatusReturned,
+	}
+}
+
+// RenderMessagePreviews renders every comment and built-in email sent for an order in the given
+// state, using fixed sample data so the output is stable enough to diff.
+func RenderMessagePreviews(ctx context.Context, status db.OrderStatus, baseURL string) ([]MessagePreview, error) {
+	shop, order := samplePreviewOrder(status)
+	checkoutURL := "https://checkout.stripe.com/c/pay/cs_test_preview"
+	var loc i18n.Localizer
+
+	var previews []MessagePreview
+	addComment := func(name, body string) {
+		previews = append(previews, MessagePreview{Name: name, Kind: MessageKindComment, Text: body})
+	}
+
+	switch status {
+	case db.StatusInquiry:
+		product := &catalog.ProductConfig{SKU: order.SKU, Name: "Wholesale Coffee Beans", Type: catalog.ProductTypeInquiry}
+		addComment("inquiry_summary", buildInquirySummary(loc, order, product))
+	case db.StatusAwaitingApproval:
+		addComment("order_held", orderHeldComment(loc, order.OrderNumber, order.TotalCents))
+	case db.StatusPendingPayment:
+		addComment("checkout_link", checkoutLinkComment(loc, order.OrderNumber, checkoutURL, catalog.DefaultCheckoutExpiry))
+		addComment("identity_verified_checkout_link", identityVerifiedCheckoutComment(loc, order.OrderNumber, checkoutURL, catalog.DefaultCheckoutExpiry))
+		addComment("checkout_reminder", checkoutReminderComment(loc, order.OrderNumber, checkoutReminderLead))
+		addComment("payment_link", paymentLinkComment(loc, order.OrderNumber, "https://buy.stripe.com/preview"))
+		addComment("quote_checkout_link", quoteCheckoutComment(loc, order.TotalCents, checkoutURL, catalog.DefaultCheckoutExpiry))
+		addComment("quote_invoice", quoteInvoiceComment(loc, order.TotalCents, "https://invoice.stripe.com/i/preview"))
+	case db.StatusPaid:
+		addComment("payment_received", paymentReceivedComment(loc))
+	case db.StatusAddressIssue:
+		addComment("address_issue", addressIssueComment(loc, order.OrderNumber))
+		addComment("address_confirmed", addressConfirmedComment(loc, order.OrderNumber))
+	case db.StatusShipped:
+		addComment("order_shipped", orderShippedComment(loc))
+		addComment("shipment_updated", shipmentUpdatedComment(loc))
+	case db.StatusExpired:
+		addComment("checkout_expired", checkoutExpiredComment(loc))
+	case db.StatusCancelled:
+		addComment("order_cancelled", orderCancelledComment(loc, order.OrderNumber))
+		addComment("order_rejected", orderRejectedComment(loc, order.OrderNumber))
+	case db.StatusPaymentFailed:
+		addComment("payment_failed", paymentFailedComment(loc))
+	case db.StatusRefunded:
+		addComment("order_refunded", orderRefundedComment(loc))
+	case db.StatusDelivered:
+		addComment("order_delivered", orderDeliveredComment(loc))
+		addComment("review_request", reviewRequestComment(loc, order.OrderNumber, 14))
+		addComment("review_thanks", reviewThanksComment(loc, order.OrderNumber, 4))
+		addComment("review_closed", reviewClosedComment(loc, order.OrderNumber))
+	case db.StatusReturned:
+		addComment("order_returned", orderReturnedComment(loc, false))
+		addComment("order_returned_refunded", orderReturnedComment(loc, true))
+	default:
+		return nil, fmt.Errorf("%w: no messages for order status %q", ErrMessagePreviewNotFound, status)
+	}
+
+	emails, err := renderPreviewEmails(ctx, shop, order, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return append(previews, emails...), nil
+}
+
+func renderPreviewEmails(ctx context.Context, shop *db.Shop, order *db.Order, baseURL string) ([]MessagePreview, error) {
+	var templateNames []string
+	overrides := OrderInfoOverrides{
+		ShippingAddress: "Mona Octocat\n88 Colin P Kelly Jr St\nSan Francisco, CA 94107\nUS",
+		OrderDate:       order.CreatedAt,
+	}
+	switch order.Status {
+	case db.StatusPendingPayment:
+		templateNames = []string{"checkout_reminder"}
+	case db.StatusPaid:
+		templateNames = []string{"order_confirmation", "new_order"}
+	case db.StatusShipped:
+		templateNames = []string{"order_shipped"}
+		overrides.TrackingNumber = order.TrackingNumber
+		overrides.TrackingCarrier = order.Carrier
+		overrides.TrackingURL = BuildTrackingURL(order.Carrier, order.TrackingNumber)
+	case db.StatusDelivered:
+		templateNames = []string{"order_delivered"}
+	case db.StatusReturned:
+		templateNames = []string{"order_returned"}
+	default:
+		return nil, nil
+	}
+
+	renderer, err := email.NewRenderer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create renderer: %w", err)
+	}
+
+	previews := make([]MessagePreview, 0, len(templateNames))
+	for _, name := range templateNames {
+		info := BuildOrderInfo(shop, order, overrides)
+		if name == "new_order" {
+			info.DashboardURL = shipOrderURL(baseURL, order)
+		}
+		rendered, err := renderer.Render(ctx, name, info)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		previews = append(previews, MessagePreview{
+			Name:    name,
+			Kind:    MessageKindEmail,
+			Subject: rendered.Subject,
+			Text:    rendered.Text,
+			HTML:    rendered.HTML,
+		})
+	}
+	return previews, nil
+}
+
+func samplePreviewOrder(status db.OrderStatus) (*db.Shop, *db.Order) {
+	shop := &db.Shop{
+		ID:                 uuid.MustParse("00000000-0000-4000-8000-000000000001"),
+		GitHubRepoFullName: "octo/shop",
+	}
+	order := &db.Order{
+		ID:                uuid.MustParse("00000000-0000-4000-8000-000000001001"),
+		ShopID:            shop.ID,
+		OrderNumber:       1001,
+		GitHubIssueURL:    "https://github.com/octo/shop/issues/42",
+		GitHubIssueNumber: 42,
+		GitHubUsername:    "monalisa",
+		SKU:               "TSHIRT_BLACK_V1",
+		Options:           map[string]any{"quantity": 2, "size": "L"},
+		SubtotalCents:     5000,
+		ShippingCents:     500,
+		TotalCents:        5500,
+		CustomerName:      "Mona Octocat",
+		CustomerEmail:     "mona@example.com",
+		Status:            status,
+		CreatedAt:         time.Date(2026, time.January, 2, 15, 4, 5, 0, time.UTC),
+	}
+	if status == db.StatusInquiry {
+		order.SKU = "BEANS_WHOLESALE"
+		order.Options = map[string]any{"quantity": 500, "company": "Octo Cafe"}
+	}
+	if status == db.StatusShipped || status == db.StatusDelivered {
+		order.Carrier = "USPS"
+		order.TrackingNumber = "9400111899223856928499"
+	}
+	return shop, order
+}
+
+// FormatMessagePreviews writes previews as plain text, the format used by the golden files.
+func FormatMessagePreviews(previews []MessagePreview) string {
+	var b strings.Builder
+	for i, preview := range previews {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "===== %s: %s =====\n", preview.Kind, preview.Name)
+		if preview.Subject != "" {
+			fmt.Fprintf(&b, "Subject: %s\n", preview.Subject)
+		}
+		b.WriteString("\n")
+		b.WriteString(strings.TrimRight(preview.Text, "\n"))
+		b.WriteString("\n")
+		if preview.HTML != "" {
+			b.WriteString("\n----- html -----\n")
+			b.WriteString(strings.TrimRight(preview.HTML, "\n"))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestRenderMessagePreviews_Golden(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range PreviewOrderStatuses() {
+		t.Run(string(status), func(t *testing.T) {
+			t.Parallel()
+
+			previews, err := RenderMessagePreviews(t.Context(), status, "https://gitshop.example.com")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(previews) == 0 {
+				t.Fatalf("expected previews for %s", status)
+			}
+			got := FormatMessagePreviews(previews)
+
+			goldenPath := filepath.Join("testdata", "messages", string(status)+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, []byte(got), 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run go test ./internal/services -run Golden -update): %v", err)
+			}
+			if got != string(want) {
+				t.Fatalf("%s does not match rendered messages; review the diff and rerun with -update\n\ngot:\n%s", goldenPath, got)
+			}
+		})
+	}
+}
+
+func TestRenderMessagePreviews_UnknownStatus(t *testing.T) {
+	t.Parallel()
+
+	_, err := RenderMessagePreviews(t.Context(), "bogus", "")
+	if !errors.Is(err, ErrMessagePreviewNotFound) {
+		t.Fatalf("expected ErrMessagePreviewNotFound, got %v", err)
+	}
+}
+
+func TestShopCheckoutLinkComment(t *testing.T) {
+	t.Parallel()
+
+	const checkoutURL = "https://checkout.stripe.com/c/pay/cs_test"
+	defaultComment := checkoutLinkComment(i18n.Localizer{}, 12, checkoutURL, time.Hour)
+
+	tests := []struct {
+		name   string
+		config *catalog.GitShopConfig
+		want   string
+	}{
+		{name: "no config", want: defaultComment},
+		{name: "no template", config: &catalog.GitShopConfig{}, want: defaultComment},
+		{
+			name: "custom template",
+			config: &catalog.GitShopConfig{
+				Shop:     catalog.ShopConfig{Name: "Octo Roasters"},
+				Messages: catalog.MessagesConfig{CheckoutLink: "{{.ShopName}} got order {{.OrderNumber}}. Pay within {{.ExpiresIn}}: {{.CheckoutURL}}"},
+			},
+			want: "Octo Roasters got order 12. Pay within 1 hour: " + checkoutURL + "\n\n<!-- gitshop:checkout-link -->",
+		},
+		{
+			name:   "broken template falls back",
+			config: &catalog.GitShopConfig{Messages: catalog.MessagesConfig{CheckoutLink: "Pay at {{.Link}}"}},
+			want:   defaultComment,
+		},
+		{
+			name: "branding footer before marker",
+			config: &catalog.GitShopConfig{
+				Shop:     catalog.ShopConfig{Name: "Octo Roasters"},
+				Branding: catalog.BrandingConfig{SupportEmail: "help@octo.example", ShopURL: "https://octo.example"},
+			},
+			want: strings.TrimSuffix(defaultComment, "\n\n<!-- gitshop:checkout-link -->") +
+				"\n\n<sub>Questions? Contact help@octo.example · [Octo Roasters](https://octo.example)</sub>\n\n<!-- gitshop:checkout-link -->",
+		},
+		{
+			name:   "invalid branding is left out",
+			config: &catalog.GitShopConfig{Branding: catalog.BrandingConfig{SupportEmail: "help@octo.example", ShopURL: "http://octo.example"}},
+			want:   defaultComment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := shopCheckoutLinkComment(tt.config)(i18n.Localizer{}, 12, checkoutURL, time.Hour); got != tt.want {
+				t.Fatalf("comment = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizedMessages(t *testing.T) {
+	t.Parallel()
+
+	loc := i18n.New("fr")
+	if got := checkoutLinkComment(loc, 12, "https://checkout.stripe.com/c/pay/cs_test", time.Hour); !strings.Contains(got, "Ce lien de paiement expire dans 1 heure.") || !strings.HasSuffix(got, "<!-- gitshop:checkout-link -->") {
+		t.Fatalf("checkout link comment = %q", got)
+	}
+
+	tooLong := &catalog.OptionAnswerError{Option: catalog.ProductOption{Label: "Gravure"}, Reason: catalog.OptionAnswerTooLong, Limit: 12}
+	if got := optionInvalidComment(loc, tooLong); !strings.Contains(got, "Gravure doit comporter au plus 12 caractères.") {
+		t.Fatalf("option invalid comment = %q", got)
+	}
+
+	if _, err := parseOrderFromIssue("### Notes\n\nHello\n", nil); !strings.Contains(orderParseMessage(loc, err), "Nous n'avons trouvé aucun produit") {
+		t.Fatalf("order parse message = %q", orderParseMessage(loc, err))
+	}
+	inactive := fmt.Errorf("%w: MUG", catalog.ErrProductInactive)
+	if got := pricingFailedComment(loc, inactive, "MUG"); !strings.Contains(got, "`MUG` ne peut pas être commandé pour le moment.") {
+		t.Fatalf("pricing failed comment = %q", got)
+	}
+
+	shop, order := samplePreviewOrder(db.StatusPaid)
+	renderer, err := email.NewLocalizedRenderer("de", nil)
+	if err != nil {
+		t.Fatalf("NewLocalizedRenderer() error = %v", err)
+	}
+	rendered, err := renderer.Render(t.Context(), "order_confirmation", BuildOrderInfo(shop, order, OrderInfoOverrides{}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.HasPrefix(rendered.Subject, "Bestellung bestätigt - ") {
+		t.Fatalf("subject = %q", rendered.Subject)
+	}
+	for _, want := range []string{"Zwischensumme", "Danke für Ihren Einkauf bei <a href="} {
+		if !strings.Contains(rendered.HTML, want) {
+			t.Fatalf("html missing %q:\n%s", want, rendered.HTML)
+		}
+	}
+
+	seller, err := renderer.Render(t.Context(), "new_order", BuildOrderInfo(shop, order, OrderInfoOverrides{}))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.HasPrefix(seller.Subject, "New Order - ") {
+		t.Fatalf("seller email subject = %q, want English", seller.Subject)
+	}
+}
+
+func TestNewOrderEmail_ReturningCustomer(t *testing.T) {
+	t.Parallel()
+
+	shop, order := samplePreviewOrder(db.StatusPaid)
+	renderer, err := email.NewLocalizedRenderer("en", nil)
+	if err != nil {
+		t.Fatalf("NewLocalizedRenderer() error = %v", err)
+	}
+
+	info := BuildOrderInfo(shop, order, OrderInfoOverrides{})
+	first, err := renderer.Render(t.Context(), "new_order", info)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(first.Text, "Returning customer") {
+		t.Fatalf("first-time buyer marked as returning:\n%s", first.Text)
+	}
+
+	info.PreviousOrders = 3
+	returning, err := renderer.Render(t.Context(), "new_order", info)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(returning.Text, "Returning customer (3 previous orders)") {
+		t.Fatalf("text missing returning customer hint:\n%s", returning.Text)
+	}
+	if !strings.Contains(returning.HTML, "<strong>Returning customer:</strong> 3 previous orders") {
+		t.Fatalf("html missing returning customer hint:\n%s", returning.HTML)
+	}
+}
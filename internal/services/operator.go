@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/featureflags"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+var (
+	ErrOperatorServiceUnavailable = errors.New("operator service unavailable")
+	ErrOperatorShopNotFound       = errors.New("shop not found")
+	ErrSuspensionReasonRequired   = errors.New("suspension reason required")
+)
+
+// ImpersonationTTL is how long support mode lasts before the operator is back to their own view.
+const ImpersonationTTL = time.Hour
+
+const (
+	operatorSearchLimit = 25
+	operatorOrdersLimit = 50
+	operatorAuditLimit  = 50
+	operatorOrphanLimit = 100
+)
+
+// OperatorService backs the internal admin area, where platform operators look across every
+// shop for support. Each action is written to the operator audit log before it runs, and an
+// action whose audit entry cannot be written does not run.
+type OperatorService struct {
+	shopStore  *db.ShopStore
+	orderStore *db.OrderStore
+	audit      *db.OperatorAuditStore
+	flags      *featureflags.Flags
+	logger     *slog.Logger
+}
+
+func NewOperatorService(shopStore *db.ShopStore, orderStore *db.OrderStore, audit *db.OperatorAuditStore, flags *featureflags.Flags, logger *slog.Logger) *OperatorService {
+	return &OperatorService{
+		shopStore:  shopStore,
+		orderStore: orderStore,
+		audit:      audit,
+		flags:      flags,
+		logger:     logger,
+	}
+}
+
+func (s *OperatorService) loggerFromContext(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, s.logger)
+}
+
+// SearchShops finds shops by repository name, shop ID, Stripe account, or installation ID.
+func (s *OperatorService) SearchShops(ctx context.Context, operator, query string) ([]*db.Shop, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, ErrOperatorServiceUnavailable
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	if err := s.record(ctx, operator, db.OperatorActionSearchShops, nil, query); err != nil {
+		return nil, err
+	}
+	return s.shopStore.SearchShops(ctx, query, operatorSearchLimit)
+}
+
+// ShopOrders returns a shop and its most recent orders for read-only viewing.
+func (s *OperatorService) ShopOrders(ctx context.Context, operator string, shopID uuid.UUID) (*db.Shop, []*db.Order, error) {
+	shop, err := s.GetShop(ctx, shopID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.record(ctx, operator, db.OperatorActionViewShop, shop, ""); err != nil {
+		return nil, nil, err
+	}
+	orders, err := s.orderStore.GetOrdersByShop(ctx, shop.ID, operatorOrdersLimit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return shop, orders, nil
+}
+
+// StartImpersonation records an operator entering support mode for a shop and returns the shop.
+func (s *OperatorService) StartImpersonation(ctx context.Context, operator string, shopID uuid.UUID, reason string) (*db.Shop, error) {
+	shop, err := s.GetShop(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.record(ctx, operator, db.OperatorActionImpersonationStarted, shop, strings.TrimSpace(reason)); err != nil {
+		return nil, err
+	}
+	s.loggerFromContext(ctx).Info("operator started impersonating shop", "operator", operator, "shop_id", shop.ID, "repo", shop.GitHubRepoFullName)
+	return shop, nil
+}
+
+// EndImpersonation records an operator leaving support mode. A shop deleted in the meantime is
+// still recorded by ID.
+func (s *OperatorService) EndImpersonation(ctx context.Context, operator string, shopID uuid.UUID) error {
+	shop, err := s.GetShop(ctx, shopID)
+	if err != nil {
+		if !errors.Is(err, ErrOperatorShopNotFound) {
+			return err
+		}
+		shop = &db.Shop{ID: shopID}
+	}
+	return s.record(ctx, operator, db.OperatorActionImpersonationEnded, shop, "")
+}
+
+// Suspension returns a shop's moderation state without writing to the audit log.
+func (s *OperatorService) Suspension(ctx context.Context, shopID uuid.UUID) (*db.ShopSuspension, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, ErrOperatorServiceUnavailable
+	}
+	suspension, err := s.shopStore.GetSuspension(ctx, shopID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOperatorShopNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspension: %w", err)
+	}
+	return suspension, nil
+}
+
+// SuspendShop suspends a shop for a terms violation. It stops taking orders and payments until
+// an operator lifts the suspension; the owner sees the reason in the dashboard and can appeal.
+func (s *OperatorService) SuspendShop(ctx context.Context, operator string, shopID uuid.UUID, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrSuspensionReasonRequired
+	}
+	shop, err := s.GetShop(ctx, shopID)
+	if err != nil {
+		return err
+	}
+	if err := s.record(ctx, operator, db.OperatorActionSuspendShop, shop, reason); err != nil {
+		return err
+	}
+	if err := s.shopStore.SuspendShop(ctx, shop.ID, operator, reason); err != nil {
+		return fmt.Errorf("failed to suspend shop: %w", err)
+	}
+	observability.MeterFromContext(ctx).Count("shop.suspended", 1)
+	s.loggerFromContext(ctx).Info("operator suspended shop", "operator", operator, "shop_id", shop.ID, "repo", shop.GitHubRepoFullName)
+	return nil
+}
+
+// UnsuspendShop lifts a suspension so the shop takes orders again.
+func (s *OperatorService) UnsuspendShop(ctx context.Context, operator string, shopID uuid.UUID) error {
+	shop, err := s.GetShop(ctx, shopID)
+	if err != nil {
+		return err
+	}
+	suspension, err := s.Suspension(ctx, shop.ID)
+	if err != nil {
+		return err
+	}
+	if !suspension.Suspended() {
+		return nil
+	}
+	if err := s.record(ctx, operator, db.OperatorActionUnsuspendShop, shop, ""); err != nil {
+		return err
+	}
+	if err := s.shopStore.UnsuspendShop(ctx, shop.ID); err != nil {
+		return fmt.Errorf("failed to unsuspend shop: %w", err)
+	}
+	observability.MeterFromContext(ctx).Count("shop.unsuspended", 1, sentry.WithAttributes(
+		attribute.Bool("appealed", suspension.Appeal != ""),
+	))
+	s.loggerFromContext(ctx).Info("operator unsuspended shop", "operator", operator, "shop_id", shop.ID, "repo", shop.GitHubRepoFullName)
+	return nil
+}
+
+// UpdateSuspensionNotes saves the operators' internal notes on a shop's suspension and appeal.
+func (s *OperatorService) UpdateSuspensionNotes(ctx context.Context, operator string, shopID uuid.UUID, notes string) error {
+	shop, err := s.GetShop(ctx, shopID)
+	if err != nil {
+		return err
+	}
+	if err := s.record(ctx, operator, db.OperatorActionSuspensionNotes, shop, ""); err != nil {
+		return err
+	}
+	if err := s.shopStore.UpdateSuspensionNotes(ctx, shop.ID, strings.TrimSpace(notes)); err != nil {
+		return fmt.Errorf("failed to update suspension notes: %w", err)
+	}
+	return nil
+}
+
+// RecordWebhookReplay records an operator replaying a stored webhook.
+func (s *OperatorService) RecordWebhookReplay(ctx context.Context, operator string, webhook *db.ReceivedWebhook) error {
+	return s.record(ctx, operator, db.OperatorActionReplayWebhook, nil, fmt.Sprintf("%s %s %s", webhook.Provider, webhook.EventType, webhook.DeliveryID))
+}
+
+// RecordWebhookRedelivery records an operator asking GitHub to redeliver failed webhooks.
+func (s *OperatorService) RecordWebhookRedelivery(ctx context.Context, operator string, since, until time.Time) error {
+	return s.record(ctx, operator, db.OperatorActionRedeliverWebhooks, nil, fmt.Sprintf("%s to %s", since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339)))
+}
+
+// RecordSupportView writes the audit entry for a page an operator opens in support mode.
+func (s *OperatorService) RecordSupportView(ctx context.Context, operator string, shop *db.Shop, detail string) error {
+	return s.record(ctx, operator, db.OperatorActionSupportView, shop, detail)
+}
+
+// RecentActions returns the latest entries of the operator audit log.
+func (s *OperatorService) RecentActions(ctx context.Context) ([]*db.OperatorAuditEntry, error) {
+	if s == nil || s.audit == nil {
+		return nil, ErrOperatorServiceUnavailable
+	}
+	return s.audit.ListRecent(ctx, operatorAuditLimit)
+}
+
+// OrphanedShops lists connected shops whose repository GitShop could not resolve.
+func (s *OperatorService) OrphanedShops(ctx context.Context) ([]db.OrphanedShop, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, ErrOperatorServiceUnavailable
+	}
+	return s.shopStore.ListOrphanedShops(ctx, operatorOrphanLimit)
+}
+
+// FeatureFlags describes how each feature flag is rolled out.
+func (s *OperatorService) FeatureFlags(ctx context.Context) []featureflags.State {
+	if s == nil {
+		return nil
+	}
+	return s.flags.States(ctx)
+}
+
+// ShopFeatureFlags reports which feature flags are on for the shop.
+func (s *OperatorService) ShopFeatureFlags(ctx context.Context, shopID uuid.UUID) []featureflags.ShopFlag {
+	if s == nil {
+		return nil
+	}
+	return s.flags.ForShop(ctx, shopID)
+}
+
+// GetShop loads any shop by ID, whoever installed it.
+func (s *OperatorService) GetShop(ctx context.Context, shopID uuid.UUID) (*db.Shop, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, ErrOperatorServiceUnavailable
+	}
+	if shopID == uuid.Nil {
+		return nil, ErrOperatorShopNotFound
+	}
+	shop, err := s.shopStore.GetByID(ctx, shopID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOperatorShopNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop: %w", err)
+	}
+	return shop, nil
+}
+
+func (s *OperatorService) record(ctx context.Context, operator string, action db.OperatorAction, shop *db.Shop, detail string) error {
+	if s == nil || s.audit == nil {
+		return ErrOperatorServiceUnavailable
+	}
+	entry := &db.OperatorAuditEntry{
+		Operator: operator,
+		Action:   action,
+		Detail:   detail,
+	}
+	if shop != nil {
+		entry.ShopID = shop.ID
+		entry.RepoFullName = shop.GitHubRepoFullName
+	}
+	if err := s.audit.Record(ctx, entry); err != nil {
+		s.loggerFromContext(ctx).Error("failed to write operator audit log", "error", err, "operator", operator, "action", action)
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
@@ -2,22 +2,30 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/mail"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/getsentry/sentry-go/attribute"
 	"github.com/google/go-github/v66/github"
 	"github.com/jackc/pgx/v5"
 
+	"github.com/gitshopapp/gitshop/internal/backpressure"
+	"github.com/gitshopapp/gitshop/internal/cache"
 	"github.com/gitshopapp/gitshop/internal/catalog"
 	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/featureflags"
 	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
 	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/money"
 	"github.com/gitshopapp/gitshop/internal/observability"
 	"github.com/gitshopapp/gitshop/internal/stripe"
 )
@@ -27,10 +35,14 @@ type OrderService struct {
 	orderStore     *db.OrderStore
 	githubClient   *githubapp.Client
 	stripePlatform *stripe.PlatformClient
+	payments       *PaymentProviders
 	parser         configParser
 	validator      configValidator
 	pricer         orderPricer
 	emailSender    OrderEmailSender
+	webhooks       OrderWebhookPublisher
+	cacheProvider  cache.Provider
+	flags          *featureflags.Flags
 	logger         *slog.Logger
 }
 
@@ -43,24 +55,31 @@ type configValidator interface {
 }
 
 type orderPricer interface {
-	ComputeSubtotal(config *catalog.GitShopConfig, sku string, options map[string]any) (int, error)
-	GetShippingCents(config *catalog.GitShopConfig) int
+	ComputeSubtotal(config *catalog.GitShopConfig, sku string, options map[string]any) (int64, error)
+	GetShippingCents(config *catalog.GitShopConfig, sku string, subtotalCents int64) int64
 }
 
-func NewOrderService(shopStore *db.ShopStore, orderStore *db.OrderStore, githubClient *githubapp.Client, stripePlatform *stripe.PlatformClient, parser configParser, validator configValidator, pricer orderPricer, emailSender OrderEmailSender, logger *slog.Logger) *OrderService {
+func NewOrderService(shopStore *db.ShopStore, orderStore *db.OrderStore, githubClient *githubapp.Client, stripePlatform *stripe.PlatformClient, payments *PaymentProviders, parser configParser, validator configValidator, pricer orderPricer, emailSender OrderEmailSender, webhooks OrderWebhookPublisher, cacheProvider cache.Provider, flags *featureflags.Flags, logger *slog.Logger) *OrderService {
 	if emailSender == nil {
 		emailSender = noopOrderEmailSender{}
 	}
+	if webhooks == nil {
+		webhooks = noopOrderWebhookPublisher{}
+	}
 
 	return &OrderService{
 		shopStore:      shopStore,
 		orderStore:     orderStore,
 		githubClient:   githubClient,
 		stripePlatform: stripePlatform,
+		payments:       payments,
 		parser:         parser,
 		validator:      validator,
 		pricer:         pricer,
 		emailSender:    emailSender,
+		webhooks:       webhooks,
+		cacheProvider:  cacheProvider,
+		flags:          flags,
 		logger:         logger,
 	}
 }
@@ -130,21 +149,41 @@ func (s *OrderService) HandleIssueOpened(ctx context.Context, input IssueOpenedI
 		recordFailure("shop_disconnected")
 		return fmt.Errorf("shop is disconnected, cannot process orders: %s", input.RepoFullName)
 	}
-	if shop.StripeConnectAccountID == "" {
+	if err := checkShopSuspension(ctx, s.shopStore, shop); err != nil {
+		if !errors.Is(err, ErrShopSuspended) {
+			recordFailure("suspension_lookup_failed")
+			return err
+		}
+		recordFailure("shop_suspended")
+		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, shopLocalizer(ctx, githubClient, input.RepoFullName).T("comment.shop_suspended")); commentErr != nil {
+			logger.Warn("failed to create shop-suspended comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return nil
+	}
+	provider, err := s.payments.ForShop(ctx, shop)
+	if errors.Is(err, ErrPaymentProviderNotConfigured) {
 		recordFailure("stripe_not_connected")
-		comment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, "⚠️ Payments are not ready yet for this storefront. Ask the shop owner to complete Stripe setup in the GitShop dashboard.")
-		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+		if commentErr := s.commentWithManagerNotice(ctx, githubClient, input.RepoFullName, input.IssueNumber, "stripe_not_connected", shopLocalizer(ctx, githubClient, input.RepoFullName).T("comment.payments_not_ready")); commentErr != nil {
 			logger.Warn("failed to create stripe-not-connected comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
 		}
-		return fmt.Errorf("stripe not connected for shop: %s", shop.ID.String())
+		return fmt.Errorf("payments not connected for shop %s: %w", shop.ID.String(), err)
 	}
-	if s.stripePlatform == nil {
+	if err != nil {
 		recordFailure("stripe_unavailable")
-		comment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, "⚠️ Payments are temporarily unavailable for this GitShop instance.")
+		comment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, shopLocalizer(ctx, githubClient, input.RepoFullName).T("comment.payments_unavailable"))
 		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
 			logger.Warn("failed to create stripe-unavailable comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
 		}
-		return fmt.Errorf("stripe platform not configured")
+		return fmt.Errorf("payment provider unavailable: %w", err)
+	}
+	if status, err := provider.GetAccountStatus(ctx, shop); err != nil {
+		logger.Warn("failed to check payment account", "error", err, "shop_id", shop.ID, "processor", provider.Processor())
+	} else if !status.ChargesEnabled {
+		recordFailure("stripe_charges_paused")
+		if commentErr := s.commentWithManagerNotice(ctx, githubClient, input.RepoFullName, input.IssueNumber, "stripe_charges_paused", shopLocalizer(ctx, githubClient, input.RepoFullName).T("comment.charges_paused")); commentErr != nil {
+			logger.Warn("failed to create charges-paused comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return fmt.Errorf("charges paused for shop: %s", shop.ID.String())
 	}
 
 	if shop.GitHubRepoFullName != input.RepoFullName {
@@ -157,19 +196,11 @@ func (s *OrderService) HandleIssueOpened(ctx context.Context, input IssueOpenedI
 		}
 	}
 
-	orderData, err := parseOrderFromIssue(input.IssueBody)
+	orderData, err := parseOrderFromIssue(input.IssueBody, s.orderTemplateFieldIDs(ctx, githubClient, input.RepoFullName))
 	if err != nil {
 		recordFailure("order_parse_failed")
-		comment := fmt.Sprintf(`❌ **Order Error**
-
-%s
-
-**How to fix:**
-1. Use the order template by clicking "New Issue" → "Place an Order"
-2. Fill in all required fields
-3. Make sure to select a product from the dropdown
-
-Need help? Check our [documentation](https://github.com/%s/blob/main/README.md) or open a support issue.`, err.Error(), input.RepoFullName)
+		loc := shopLocalizer(ctx, githubClient, input.RepoFullName)
+		comment := loc.T("comment.order_parse_error", orderParseMessage(loc, err), input.RepoFullName)
 
 		if createErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); createErr != nil {
 			logger.Error("failed to create error comment", "error", createErr)
@@ -180,8 +211,7 @@ Need help? Check our [documentation](https://github.com/%s/blob/main/README.md)
 	configContent, err := s.getGitShopConfigFile(ctx, githubClient, input.RepoFullName)
 	if err != nil {
 		recordFailure("config_missing")
-		comment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, "❌ Could not find `gitshop.yaml` in the repo. Create it in the repo root to enable ordering.")
-		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+		if commentErr := s.commentWithManagerNotice(ctx, githubClient, input.RepoFullName, input.IssueNumber, "config_missing", shopLocalizer(ctx, githubClient, input.RepoFullName).T("comment.config_missing")); commentErr != nil {
 			logger.Warn("failed to create missing-config comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
 		}
 		return fmt.Errorf("failed to fetch gitshop.yaml: %w", err)
@@ -196,104 +226,247 @@ Need help? Check our [documentation](https://github.com/%s/blob/main/README.md)
 	validateErr := s.validator.Validate(config)
 	if validateErr != nil {
 		recordFailure("config_invalid")
-		comment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, fmt.Sprintf("❌ `gitshop.yaml` is invalid: %s\n\nFix the file and try again.", validateErr.Error()))
-		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+		if commentErr := s.commentWithManagerNotice(ctx, githubClient, input.RepoFullName, input.IssueNumber, "config_invalid", configLocalizer(config).T("comment.config_invalid", validateErr.Error())); commentErr != nil {
 			logger.Warn("failed to create invalid-config comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
 		}
 		return fmt.Errorf("invalid gitshop.yaml: %w", validateErr)
 	}
 	s.assignShopManager(ctx, githubClient, input.RepoFullName, input.IssueNumber, config)
 
+	product := findProduct(config, orderData.SKU)
+	if product == nil {
+		recordFailure("sku_missing")
+		if commentErr := s.commentWithManagerNotice(ctx, githubClient, input.RepoFullName, input.IssueNumber, "sku_missing", configLocalizer(config).T("comment.sku_missing", orderData.SKU)); commentErr != nil {
+			logger.Warn("failed to create missing-sku comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return fmt.Errorf("sku not found: %s", orderData.SKU)
+	}
+
+	// Checked again when the checkout is created; failing here keeps the order from being stored.
+	if err := checkSubscriptionsEnabled(ctx, s.flags, shop, product.IsSubscription()); err != nil {
+		recordFailure("subscriptions_disabled")
+		comment := configLocalizer(config).T("comment.subscriptions_unavailable")
+		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+			logger.Warn("failed to create subscriptions-unavailable comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return err
+	}
+
+	eligibilityAttested := takeCheckboxAnswer(orderData.Options, catalog.EligibilityFieldID, catalog.EligibilityFieldLabel)
+	if product.Restricted && !eligibilityAttested {
+		recordFailure("eligibility_not_attested")
+		comment := configLocalizer(config).T("comment.eligibility_required")
+		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+			logger.Warn("failed to create eligibility comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return fmt.Errorf("eligibility not attested for restricted product %s", product.SKU)
+	}
+
+	terms := config.Shop.Terms
+	termsAccepted := takeCheckboxAnswer(orderData.Options, catalog.TermsFieldID, catalog.TermsFieldLabel)
+	if terms.RequireCheckbox && !termsAccepted {
+		recordFailure("terms_not_accepted")
+		comment := configLocalizer(config).T("comment.terms_required", terms.URL)
+		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+			logger.Warn("failed to create terms-not-accepted comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return fmt.Errorf("terms of sale not accepted")
+	}
+
+	buyerEmail, reminderOptOut, emailValid := takeBuyerEmail(orderData.Options)
+	if !emailValid {
+		meter.Count("order.email.invalid", 1)
+		logger.Info("ignoring invalid buyer email on order form", "repo", input.RepoFullName, "issue", input.IssueNumber)
+	}
+	displayCurrency := takeEstimateCurrency(orderData.Options, config.Shop.Checkout)
+
+	catalog.ApplyOptionConditions(*product, orderData.Options)
+	var answerErr *catalog.OptionAnswerError
+	if err := catalog.CheckOptionAnswers(*product, orderData.Options); errors.As(err, &answerErr) {
+		recordFailure("option_invalid")
+		loc := configLocalizer(config)
+		comment := optionInvalidComment(loc, answerErr)
+		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+			logger.Warn("failed to create invalid-option comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return fmt.Errorf("invalid option answer: %w", err)
+	}
+
 	subtotalCents, err := s.pricer.ComputeSubtotal(config, orderData.SKU, orderData.Options)
+	if errors.Is(err, catalog.ErrQuantityExceeded) {
+		recordFailure("quantity_exceeded")
+		comment := configLocalizer(config).T("comment.quantity_exceeded", config.Shop.QuantityLimit(*product), orderData.SKU)
+		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+			logger.Warn("failed to create quantity-exceeded comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		}
+		return fmt.Errorf("failed to compute subtotal: %w", err)
+	}
 	if err != nil {
 		recordFailure("pricing_failed")
-		comment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, fmt.Sprintf("❌ We couldn't price this order yet: %s", err.Error()))
-		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
+		if commentErr := s.commentWithManagerNotice(ctx, githubClient, input.RepoFullName, input.IssueNumber, "pricing_failed", pricingFailedComment(configLocalizer(config), err, orderData.SKU)); commentErr != nil {
 			logger.Warn("failed to create pricing-error comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
 		}
 		return fmt.Errorf("failed to compute subtotal: %w", err)
 	}
 
-	shippingCents := s.pricer.GetShippingCents(config)
-
-	product := findProduct(config, orderData.SKU)
-	if product == nil {
-		recordFailure("sku_missing")
-		comment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, fmt.Sprintf("❌ SKU `%s` not found in `gitshop.yaml`. Update the file and try again.", orderData.SKU))
-		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); commentErr != nil {
-			logger.Warn("failed to create missing-sku comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
+	shippingCents := s.pricer.GetShippingCents(config, orderData.SKU, subtotalCents)
+	totalCents, err := money.Add(subtotalCents, shippingCents)
+	if err == nil {
+		err = money.CheckChargeable(totalCents)
+	}
+	if err != nil {
+		recordFailure("pricing_failed")
+		if commentErr := s.commentWithManagerNotice(ctx, githubClient, input.RepoFullName, input.IssueNumber, "pricing_failed", configLocalizer(config).T("comment.total_too_large")); commentErr != nil {
+			logger.Warn("failed to create pricing-error comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
 		}
-		return fmt.Errorf("sku not found: %s", orderData.SKU)
+		return fmt.Errorf("failed to compute total: %w", err)
+	}
+
+	// An order keeps the mode it was placed in, so its retries and refunds stay in test mode
+	// after the seller goes live.
+	paymentSettings, err := s.shopStore.GetPaymentSettings(ctx, shop.ID)
+	if err != nil {
+		recordFailure("payment_settings_lookup_failed")
+		return fmt.Errorf("failed to load payment settings: %w", err)
 	}
 
 	order := &db.Order{
 		ShopID:            shop.ID,
 		GitHubIssueNumber: input.IssueNumber,
-		OrderNumber:       input.IssueNumber,
 		GitHubIssueURL:    input.IssueURL,
 		GitHubUsername:    input.IssueUsername,
 		SKU:               orderData.SKU,
+		Category:          strings.TrimSpace(product.Category),
 		Options:           orderData.Options,
 		SubtotalCents:     subtotalCents,
 		ShippingCents:     shippingCents,
-		TotalCents:        subtotalCents + shippingCents,
+		TotalCents:        totalCents,
 		Status:            db.StatusPendingPayment,
+		TestMode:          paymentSettings.StripeTestMode,
+		Campaign:          product.CampaignName(),
+		DisplayCurrency:   displayCurrency,
+	}
+	if terms.Enabled() {
+		order.TermsVersion = terms.Version
+		if termsAccepted {
+			order.TermsAcceptedAt = time.Now()
+		}
+	}
+	if product.Restricted {
+		order.VerificationStatus = db.VerificationAttested
+	}
+	holdForApproval := holdsForApproval(*product, config.Shop.Checkout, totalCents)
+	switch {
+	case product.IsInquiry():
+		order.Status = db.StatusInquiry
+	case holdForApproval:
+		order.Status = db.StatusAwaitingApproval
 	}
 
 	createErr := s.orderStore.Create(ctx, order)
+	if errors.Is(createErr, db.ErrOrderExists) {
+		// Another delivery of this webhook already took the order and owns its checkout.
+		meter.Count("order.intake.duplicate", 1)
+		logger.Info("reusing existing order for redelivered issue", "order_id", order.ID, "repo", input.RepoFullName, "issue", input.IssueNumber)
+		return nil
+	}
 	if createErr != nil {
 		recordFailure("order_create_failed")
 		return fmt.Errorf("failed to create order: %w", createErr)
 	}
 	meter.Count("order.created", 1)
+	if buyerEmail != "" {
+		if err := s.orderStore.SetCheckoutEmail(ctx, order.ID, buyerEmail, reminderOptOut); err != nil {
+			logger.Warn("failed to save buyer email", "error", err, "order_id", order.ID)
+		} else {
+			order.CustomerEmail = buyerEmail
+			order.CheckoutReminderOptOut = reminderOptOut
+		}
+	}
+	if err := s.webhooks.PublishOrderEvent(ctx, shop, order, db.WebhookOrderCreated); err != nil {
+		logger.Warn("failed to queue order.created webhook", "error", err, "order_id", order.ID)
+	}
+	labels := shopLabels(config)
+	if order.TestMode {
+		if err := githubClient.AddLabels(ctx, input.RepoFullName, input.IssueNumber, []string{labels.Name(catalog.LabelTest)}); err != nil {
+			logger.Warn("failed to label test order", "error", err, "order_id", order.ID)
+		}
+	}
 
-	quantity := int64(orderQuantity(orderData.Options))
-	checkoutParams := stripe.CheckoutSessionParams{
-		OrderID:         order.ID,
-		ShopID:          shop.ID,
-		IssueNumber:     input.IssueNumber,
-		RepoFullName:    input.RepoFullName,
-		ProductName:     product.Name,
-		UnitPriceCents:  int64(product.UnitPriceCents),
-		Quantity:        quantity,
-		ShippingCents:   int64(shippingCents),
-		ShippingCarrier: config.Shop.Shipping.Carrier,
-		CustomerEmail:   "",
-		SuccessURL:      fmt.Sprintf("https://github.com/%s/issues/%d", input.RepoFullName, input.IssueNumber),
-		CancelURL:       fmt.Sprintf("https://github.com/%s/issues/%d", input.RepoFullName, input.IssueNumber),
-		StripeAccountID: shop.StripeConnectAccountID,
+	if product.IsInquiry() {
+		if err := s.routeInquiry(ctx, githubClient, configLocalizer(config), order, product, labels, input); err != nil {
+			recordFailure("inquiry_routing_failed")
+			return err
+		}
+		meter.Count("order.inquiry.received", 1)
+		return nil
 	}
 
-	session, err := s.stripePlatform.CreateCheckoutSession(ctx, checkoutParams)
+	if holdForApproval {
+		held := s.appendManagerMention(ctx, githubClient, input.RepoFullName, orderHeldComment(configLocalizer(config), order.OrderNumber, totalCents))
+		if err := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, held); err != nil {
+			recordFailure("approval_comment_failed")
+			return fmt.Errorf("failed to comment order hold: %w", err)
+		}
+		if err := recordOrderEvent(ctx, s.orderStore, order.ID, db.OrderEventCommentPosted, "awaiting_approval"); err != nil {
+			recordFailure("order_event_failed")
+			return err
+		}
+		s.ensureOrderNumberInTitle(ctx, githubClient, input.RepoFullName, input.IssueNumber, order.OrderNumber, input.IssueTitle)
+		if err := githubClient.AddLabels(ctx, input.RepoFullName, input.IssueNumber, []string{labels.Name(catalog.LabelAwaitingApproval)}); err != nil {
+			recordFailure("label_add_failed")
+			return fmt.Errorf("failed to add label: %w", err)
+		}
+		meter.Count("order.approval.held", 1)
+		return nil
+	}
+
+	if product.VerifyIdentity {
+		if err := s.requestIdentityVerification(ctx, githubClient, shop, order, input.RepoFullName, configLocalizer(config), configLocalizer(config).T("comment.identity_required")); err != nil {
+			recordFailure("identity_verification_failed")
+			return err
+		}
+		s.ensureOrderNumberInTitle(ctx, githubClient, input.RepoFullName, input.IssueNumber, order.OrderNumber, input.IssueTitle)
+		if err := githubClient.AddLabels(ctx, input.RepoFullName, input.IssueNumber, []string{labels.Name(catalog.LabelPendingPayment)}); err != nil {
+			recordFailure("label_add_failed")
+			return fmt.Errorf("failed to add label: %w", err)
+		}
+		return nil
+	}
+
+	checkoutParams := checkoutParamsForOrder(shop, order, config, product, input.RepoFullName)
+
+	checkout, err := createOrderCheckout(ctx, s.payments, shop, order, checkoutParams, "issue_opened")
 	if err != nil {
 		recordFailure("checkout_create_failed")
-		meter.Count("checkout.session.failed", 1, sentry.WithAttributes(
-			attribute.String("reason", "create_failed"),
-		))
 		if markErr := s.orderStore.MarkFailed(ctx, order.ID, "stripe_checkout_failed"); markErr != nil {
 			logger.Warn("failed to mark order failed after checkout error", "error", markErr, "order_id", order.ID)
 		}
-		failComment := s.appendManagerMention(ctx, githubClient, input.RepoFullName, "⚠️ Thanks for your order. We couldn't create a checkout link right now.\n\nAsk the shop owner for help or add a new comment `.gitshop retry` to try again.")
+		loc := configLocalizer(config)
+		failComment := checkoutFailedComment(loc, err, s.appendManagerMention(ctx, githubClient, input.RepoFullName, loc.T("comment.checkout_failed")))
 		if commentErr := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, failComment); commentErr != nil {
 			logger.Warn("failed to create checkout-failed comment", "error", commentErr, "repo", input.RepoFullName, "issue", input.IssueNumber)
 		}
 		return fmt.Errorf("failed to create checkout session: %w", err)
 	}
 
-	if err := s.orderStore.UpdateStripeSession(ctx, order.ID, session.ID); err != nil {
+	if err := saveOrderCheckout(ctx, s.orderStore, order, checkout); err != nil {
 		recordFailure("order_update_stripe_session_failed")
-		return fmt.Errorf("failed to update order with session ID: %w", err)
+		return fmt.Errorf("failed to update order with checkout: %w", err)
 	}
 
-	comment := fmt.Sprintf("🛍️ Thanks for your order! Complete payment here: %s\n\nThis checkout link expires in 30 minutes.\n\n<!-- gitshop:checkout-link -->", session.URL)
-	if err := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, comment); err != nil {
+	if err := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, checkout.comment(configLocalizer(config), order, config.Shop.Checkout.ExpiresIn(), shopCheckoutLinkComment(config))); err != nil {
 		recordFailure("checkout_comment_failed")
 		return fmt.Errorf("failed to create comment: %w", err)
 	}
+	if err := recordOrderEvent(ctx, s.orderStore, order.ID, db.OrderEventCommentPosted, "checkout_link"); err != nil {
+		recordFailure("order_event_failed")
+		return err
+	}
 
-	s.ensureIssueNumberInTitle(ctx, githubClient, input.RepoFullName, input.IssueNumber, input.IssueTitle)
+	s.ensureOrderNumberInTitle(ctx, githubClient, input.RepoFullName, input.IssueNumber, order.OrderNumber, input.IssueTitle)
 
-	if err := githubClient.AddLabels(ctx, input.RepoFullName, input.IssueNumber, []string{"gitshop:status:pending-payment"}); err != nil {
+	if err := githubClient.AddLabels(ctx, input.RepoFullName, input.IssueNumber, []string{labels.Name(catalog.LabelPendingPayment)}); err != nil {
 		recordFailure("label_add_failed")
 		return fmt.Errorf("failed to add label: %w", err)
 	}
@@ -315,10 +488,11 @@ func (s *OrderService) HandleIssueCommentCreated(ctx context.Context, input Issu
 
 	meter := observability.MeterFromContext(ctx)
 	commentBody := strings.TrimSpace(input.CommentBody)
-	if commentBody != ".gitshop retry" {
+	command, ok := orderCommandName(commentBody)
+	if !ok {
 		return nil
 	}
-	meter.Count("order.retry.received", 1, sentry.WithAttributes(
+	meter.Count("order."+command+".received", 1, sentry.WithAttributes(
 		attribute.String("source", "issue_comment"),
 	))
 
@@ -327,29 +501,33 @@ func (s *OrderService) HandleIssueCommentCreated(ctx context.Context, input Issu
 	hasPermission := false
 	permission, err := githubClient.CheckPermission(ctx, input.RepoFullName, input.CommenterLogin)
 	if err != nil {
-		s.loggerFromContext(ctx).Warn("failed to check permission for retry", "error", err, "repo", input.RepoFullName, "commenter", input.CommenterLogin)
+		s.loggerFromContext(ctx).Warn("failed to check permission for command", "error", err, "command", command, "repo", input.RepoFullName, "commenter", input.CommenterLogin)
 	} else {
 		hasPermission = permission
 	}
 	shop, err := s.shopStore.GetByInstallationAndRepoID(ctx, input.InstallationID, input.RepoID)
 	if err != nil {
-		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
+		meter.Count("order."+command+".failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "shop_lookup_failed"),
 		))
 		return fmt.Errorf("failed to get shop: %w", err)
 	}
 
 	if !shop.IsConnected() {
-		meter.Count("order.retry.rejected", 1, sentry.WithAttributes(
+		meter.Count("order."+command+".rejected", 1, sentry.WithAttributes(
 			attribute.String("reason", "shop_disconnected"),
 		))
-		return githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber,
-			"❌ This shop is currently disconnected. Please reconnect the GitHub App to use GitShop commands.")
+		return githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, shopLocalizer(ctx, githubClient, input.RepoFullName).T("comment.shop_disconnected"))
+	}
+
+	// Order lookups work on any issue, such as a support request that is not an order itself.
+	if args, ok := ordersCommandArgs(commentBody); ok {
+		return s.handleOrdersCommand(ctx, githubClient, input.RepoFullName, input.IssueNumber, args, input.CommenterLogin, shop)
 	}
 
 	order, err := s.orderStore.GetByShopAndIssue(ctx, shop.ID, input.IssueNumber)
 	if err != nil {
-		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
+		meter.Count("order."+command+".failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "order_lookup_failed"),
 		))
 		return fmt.Errorf("failed to get order: %w", err)
@@ -358,14 +536,98 @@ func (s *OrderService) HandleIssueCommentCreated(ctx context.Context, input Issu
 	return s.executeCommand(ctx, githubClient, input.RepoFullName, input.IssueNumber, order, commentBody, input.CommenterLogin, hasPermission, shop)
 }
 
+const (
+	retryCommand        = ".gitshop retry"
+	returnCommand       = ".gitshop return"
+	returnRefundCommand = ".gitshop return --refund"
+	approveCommand      = ".gitshop approve"
+	rejectCommand       = ".gitshop reject"
+)
+
+// orderCommandName maps an issue comment to the command it invokes, such as "retry".
+func orderCommandName(commentBody string) (string, bool) {
+	switch commentBody {
+	case retryCommand:
+		return "retry", true
+	case returnCommand, returnRefundCommand:
+		return "return", true
+	case approveCommand:
+		return "approve", true
+	case rejectCommand:
+		return "reject", true
+	}
+	if _, ok := updateCommandArgs(commentBody); ok {
+		return "update", true
+	}
+	if _, ok := reviewCommandArgs(commentBody); ok {
+		return "review", true
+	}
+	if _, ok := ordersCommandArgs(commentBody); ok {
+		return "orders", true
+	}
+	return "", false
+}
+
 func (s *OrderService) executeCommand(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, order *db.Order, commentBody, commenterLogin string, hasPermission bool, shop *db.Shop) error {
-	if commentBody == ".gitshop retry" {
+	switch commentBody {
+	case retryCommand:
 		return s.handleRetryCommand(ctx, client, repoFullName, issueNumber, order, commenterLogin, hasPermission, shop)
+	case returnCommand, returnRefundCommand:
+		return s.handleReturnCommand(ctx, client, repoFullName, issueNumber, order, hasPermission, shop, commentBody == returnRefundCommand)
+	case approveCommand, rejectCommand:
+		return s.handleApprovalCommand(ctx, client, repoFullName, issueNumber, order, commenterLogin, hasPermission, shop, commentBody == approveCommand)
+	}
+	if args, ok := updateCommandArgs(commentBody); ok {
+		return s.handleUpdateCommand(ctx, client, repoFullName, issueNumber, order, args, commenterLogin, hasPermission, shop)
+	}
+	if args, ok := reviewCommandArgs(commentBody); ok {
+		return s.handleReviewCommand(ctx, client, repoFullName, issueNumber, order, args, commenterLogin, shop)
 	}
 
 	return nil
 }
 
+// handleReturnCommand records a return from `.gitshop return`. Adding `--refund` refunds the
+// payment as well. Only repo admins can run it.
+func (s *OrderService) handleReturnCommand(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, order *db.Order, hasPermission bool, shop *db.Shop, refund bool) error {
+	meter := observability.MeterFromContext(ctx)
+	recordRejected := func(reason string) {
+		meter.Count("order.return.rejected", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	loc := shopLocalizer(ctx, client, repoFullName)
+	if order == nil || shop == nil {
+		recordRejected("order_not_found")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.order_not_found"))
+	}
+	if !hasPermission {
+		recordRejected("permission_denied")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.return_permission_denied"))
+	}
+
+	returns := orderReturn{
+		orderStore: s.orderStore,
+		payments:   s.payments,
+		emailer:    s.emailSender,
+		webhooks:   s.webhooks,
+		logger:     s.logger,
+	}
+	err := returns.process(ctx, client, shop, order, refund)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrOrderNotReturnable), errors.Is(err, ErrAdminOrderStatusConflict):
+		recordRejected("invalid_order_status")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.return_not_returnable"))
+	case errors.Is(err, ErrAdminRefundUnavailable):
+		recordRejected("refund_unavailable")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.return_refund_unavailable"))
+	default:
+		return err
+	}
+}
+
 func (s *OrderService) handleRetryCommand(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, order *db.Order, commenterLogin string, hasPermission bool, shop *db.Shop) error {
 	span := sentry.StartSpan(
 		ctx,
@@ -378,32 +640,33 @@ func (s *OrderService) handleRetryCommand(ctx context.Context, client *githubapp
 	ctx = span.Context()
 
 	meter := observability.MeterFromContext(ctx)
+	loc := shopLocalizer(ctx, client, repoFullName)
 	if order == nil || shop == nil {
 		meter.Count("order.retry.rejected", 1, sentry.WithAttributes(
 			attribute.String("reason", "order_not_found"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, "❌ Order not found.")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.order_not_found"))
 	}
 
 	if !hasPermission && commenterLogin != order.GitHubUsername {
 		meter.Count("order.retry.rejected", 1, sentry.WithAttributes(
 			attribute.String("reason", "permission_denied"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, "❌ Only the issue author or a repo admin can retry order creation.")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.retry_permission_denied"))
 	}
 
 	if order.Status != db.StatusPaymentFailed {
 		meter.Count("order.retry.rejected", 1, sentry.WithAttributes(
 			attribute.String("reason", "invalid_order_status"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, "⚠️ This order doesn't need a retry right now.")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.retry_not_needed"))
 	}
 
-	if s.stripePlatform == nil || shop.StripeConnectAccountID == "" {
+	if _, err := s.payments.ForShop(ctx, shop); err != nil {
 		meter.Count("order.retry.rejected", 1, sentry.WithAttributes(
 			attribute.String("reason", "stripe_unavailable"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, s.appendManagerMention(ctx, client, repoFullName, "❌ Stripe is not connected for this shop yet."))
+		return s.commentWithManagerNotice(ctx, client, repoFullName, issueNumber, "stripe_not_connected", loc.T("comment.retry_payments_not_connected"))
 	}
 
 	configContent, err := s.getGitShopConfigFile(ctx, client, repoFullName)
@@ -411,7 +674,7 @@ func (s *OrderService) handleRetryCommand(ctx context.Context, client *githubapp
 		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "config_missing"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, s.appendManagerMention(ctx, client, repoFullName, "❌ `gitshop.yaml` is missing. Fix it before retrying."))
+		return s.commentWithManagerNotice(ctx, client, repoFullName, issueNumber, "config_missing", loc.T("comment.retry_config_missing"))
 	}
 
 	config, err := s.parser.Parse(configContent)
@@ -419,62 +682,58 @@ func (s *OrderService) handleRetryCommand(ctx context.Context, client *githubapp
 		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "config_invalid"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, s.appendManagerMention(ctx, client, repoFullName, "❌ `gitshop.yaml` is invalid. Fix it before retrying."))
+		return s.commentWithManagerNotice(ctx, client, repoFullName, issueNumber, "config_invalid", loc.T("comment.retry_config_invalid"))
 	}
 
 	if validateErr := s.validator.Validate(config); validateErr != nil {
 		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "config_invalid"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, s.appendManagerMention(ctx, client, repoFullName, "❌ `gitshop.yaml` is invalid. Fix it before retrying."))
+		return s.commentWithManagerNotice(ctx, client, repoFullName, issueNumber, "config_invalid", loc.T("comment.retry_config_invalid"))
 	}
 
+	loc = configLocalizer(config)
 	product := findProduct(config, order.SKU)
 	if product == nil {
 		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "sku_missing"),
 		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, s.appendManagerMention(ctx, client, repoFullName, "❌ SKU not found in `gitshop.yaml`. Update the file and retry."))
+		return s.commentWithManagerNotice(ctx, client, repoFullName, issueNumber, "sku_missing", loc.T("comment.retry_sku_missing"))
 	}
 
-	quantity := int64(orderQuantity(order.Options))
-	checkoutParams := stripe.CheckoutSessionParams{
-		OrderID:         order.ID,
-		ShopID:          shop.ID,
-		IssueNumber:     issueNumber,
-		RepoFullName:    repoFullName,
-		ProductName:     product.Name,
-		UnitPriceCents:  int64(product.UnitPriceCents),
-		Quantity:        quantity,
-		ShippingCents:   int64(order.ShippingCents),
-		ShippingCarrier: config.Shop.Shipping.Carrier,
-		CustomerEmail:   "",
-		SuccessURL:      fmt.Sprintf("https://github.com/%s/issues/%d", repoFullName, issueNumber),
-		CancelURL:       fmt.Sprintf("https://github.com/%s/issues/%d", repoFullName, issueNumber),
-		StripeAccountID: shop.StripeConnectAccountID,
+	if product.IsInquiry() {
+		meter.Count("order.retry.rejected", 1, sentry.WithAttributes(
+			attribute.String("reason", "quoted_order"),
+		))
+		return client.CreateComment(ctx, repoFullName, issueNumber, s.appendManagerMention(ctx, client, repoFullName, loc.T("comment.retry_quoted_order")))
+	}
+
+	if needsIdentityVerification(*product, order) {
+		meter.Count("order.retry.rejected", 1, sentry.WithAttributes(
+			attribute.String("reason", "verification_incomplete"),
+		))
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.identity_incomplete"))
 	}
 
-	session, err := s.stripePlatform.CreateCheckoutSession(ctx, checkoutParams)
+	checkoutParams := checkoutParamsForOrder(shop, order, config, product, repoFullName)
+
+	s.deactivatePaymentLink(ctx, shop, order)
+	checkout, err := createOrderCheckout(ctx, s.payments, shop, order, checkoutParams, "retry")
 	if err != nil {
 		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "checkout_create_failed"),
 		))
-		meter.Count("checkout.session.failed", 1, sentry.WithAttributes(
-			attribute.String("source", "retry"),
-			attribute.String("reason", "create_failed"),
-		))
-		return client.CreateComment(ctx, repoFullName, issueNumber, s.appendManagerMention(ctx, client, repoFullName, "❌ Retry failed to create a checkout link. Please try again later."))
+		return client.CreateComment(ctx, repoFullName, issueNumber, checkoutFailedComment(loc, err, s.appendManagerMention(ctx, client, repoFullName, loc.T("comment.retry_checkout_failed"))))
 	}
 
-	if err := s.orderStore.MarkPendingPayment(ctx, order.ID, session.ID); err != nil {
+	if err := markPendingCheckout(ctx, s.orderStore, order, checkout); err != nil {
 		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "mark_pending_failed"),
 		))
 		return fmt.Errorf("failed to update order after retry: %w", err)
 	}
 
-	comment := fmt.Sprintf("🛍️ Thanks for your order! Complete payment here: %s\n\nThis checkout link expires in 30 minutes.\n\n<!-- gitshop:checkout-link -->", session.URL)
-	if err := client.CreateComment(ctx, repoFullName, issueNumber, comment); err != nil {
+	if err := client.CreateComment(ctx, repoFullName, issueNumber, checkout.comment(loc, order, config.Shop.Checkout.ExpiresIn(), shopCheckoutLinkComment(config))); err != nil {
 		meter.Count("order.retry.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "checkout_comment_failed"),
 		))
@@ -491,23 +750,44 @@ func (s *OrderService) handleRetryCommand(ctx context.Context, client *githubapp
 }
 
 func (s *OrderService) appendManagerMention(ctx context.Context, client *githubapp.Client, repoFullName, message string) string {
-	mention := s.shopManagerMention(ctx, client, repoFullName)
+	config := s.shopManagerConfig(ctx, client, repoFullName)
+	return withManagerMention(configLocalizer(config), message, configManagerMention(config))
+}
+
+func withManagerMention(loc i18n.Localizer, message, mention string) string {
 	if mention == "" {
 		return message
 	}
-	return message + "\n\nShop manager: " + mention
+	return message + "\n\n" + loc.T("comment.shop_manager", mention)
 }
 
-func (s *OrderService) shopManagerMention(ctx context.Context, client *githubapp.Client, repoFullName string) string {
+// shopManagerConfig reads the gitshop.yaml that names the shop manager and sets the locale the
+// mention is written in, or returns nil when it is missing or does not parse.
+func (s *OrderService) shopManagerConfig(ctx context.Context, client *githubapp.Client, repoFullName string) *catalog.GitShopConfig {
 	if client == nil || repoFullName == "" {
-		return ""
+		return nil
 	}
 	content, err := s.getGitShopConfigFile(ctx, client, repoFullName)
 	if err != nil {
-		return ""
+		return nil
 	}
 	config, err := s.parser.Parse(content)
-	if err != nil || config == nil {
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// holdsForApproval reports whether a new order waits for the seller before checkout. Inquiries
+// are quoted by hand so they are never held. Identity checks run once the order is approved.
+func holdsForApproval(product catalog.ProductConfig, checkout catalog.CheckoutConfig, totalCents int64) bool {
+	return !product.IsInquiry() && checkout.RequiresApproval(totalCents)
+}
+
+// configManagerMention returns "@manager" for the shop manager set in config, or "" when there is
+// none.
+func configManagerMention(config *catalog.GitShopConfig) string {
+	if config == nil {
 		return ""
 	}
 	manager := strings.TrimSpace(config.Shop.Manager)
@@ -517,16 +797,27 @@ func (s *OrderService) shopManagerMention(ctx context.Context, client *githubapp
 	return "@" + manager
 }
 
-func IsOrderIssue(issue *github.Issue) bool {
-	if issue == nil {
+// IsOrderIssue reports whether issue was opened from one of the repo's order templates. The
+// order label comes from the repo's gitshop.yaml, so it is only read for labeled issues.
+func (s *OrderService) IsOrderIssue(ctx context.Context, installationID int64, repoFullName string, issue *github.Issue) bool {
+	labels := catalog.LabelsConfig{}
+	if issue != nil && len(issue.Labels) > 0 && s.githubClient != nil {
+		labels = shopLabels(shopConfig(ctx, s.githubClient.WithInstallation(installationID), repoFullName))
+	}
+	return IsOrderIssue(issue, labels)
+}
+
+func IsOrderIssue(issue *github.Issue, labels catalog.LabelsConfig) bool {
+	if issue == nil || strings.Contains(issue.GetBody(), simulationMarker) {
 		return false
 	}
 
+	orderLabel := labels.Name(catalog.LabelOrder)
 	for _, label := range issue.Labels {
 		if label == nil {
 			continue
 		}
-		if label.GetName() == "gitshop:order" {
+		if label.GetName() == orderLabel {
 			return true
 		}
 	}
@@ -540,36 +831,48 @@ type OrderData struct {
 	Options map[string]any `json:"options"`
 }
 
-func parseOrderFromIssue(body string) (*OrderData, error) {
+// issueNoResponse is what GitHub writes for an optional issue form field left blank.
+const issueNoResponse = "_No response_"
+
+// errNoSKU means an order issue names no product.
+var errNoSKU = errors.New("no SKU found in issue body")
+
+// parseOrderFromIssue reads the product and options from an order issue. Bodies are either a JSON
+// object keyed by field ID, as API clients send, or GitHub's issue form rendering with a
+// "### Label" heading per field. fieldIDs maps normalized field labels to their template field
+// IDs, so localized labels still land on the right option; headings it does not cover are keyed
+// by their normalized text.
+func parseOrderFromIssue(body string, fieldIDs map[string]string) (*OrderData, error) {
 	sku := ""
 	options := make(map[string]any)
-
-	lines := strings.Split(body, "\n")
-	currentHeader := ""
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "### ") {
-			currentHeader = strings.TrimSpace(strings.TrimPrefix(trimmed, "### "))
-			continue
-		}
-		if trimmed == "" || strings.HasPrefix(trimmed, "<!--") {
-			continue
+	setAnswer := func(key, value string) {
+		switch key {
+		case "product", "product_sku", "sku":
+			sku = extractSKU(strings.Split(value, "\n")[0])
+		case "quantity":
+			if qty := parseQuantity(value); qty > 0 {
+				options["quantity"] = qty
+			}
+		default:
+			options[key] = catalog.OptionValueFromLabel(value)
 		}
+	}
 
-		if currentHeader != "" {
-			key := normalizeHeader(currentHeader)
-			switch key {
-			case "product", "product_sku", "sku":
-				sku = extractSKU(trimmed)
-			case "quantity":
-				if qty := parseQuantity(trimmed); qty > 0 {
-					options["quantity"] = qty
-				}
-			default:
-				options[key] = trimmed
+	if answers, ok := parseOrderJSON(body); ok {
+		for key, value := range answers {
+			setAnswer(normalizeHeader(key), value)
+		}
+	} else {
+		for _, section := range issueFormSections(body) {
+			key := normalizeHeader(section.label)
+			if id, ok := fieldIDs[key]; ok {
+				key = id
 			}
-			currentHeader = ""
-			continue
+			value, ok := issueFormAnswer(section.lines)
+			if !ok {
+				continue
+			}
+			setAnswer(key, value)
 		}
 	}
 
@@ -582,7 +885,7 @@ func parseOrderFromIssue(body string) (*OrderData, error) {
 	}
 
 	if sku == "" {
-		return nil, fmt.Errorf("no SKU found in issue body. Please include 'SKU: <product-sku>' in your order")
+		return nil, errNoSKU
 	}
 
 	return &OrderData{
@@ -591,6 +894,223 @@ func parseOrderFromIssue(body string) (*OrderData, error) {
 	}, nil
 }
 
+type issueFormSection struct {
+	label string
+	lines []string
+}
+
+// issueFormSections splits an issue form body into its "### Label" sections. Text before the
+// first heading and HTML comments are dropped.
+func issueFormSections(body string) []issueFormSection {
+	sections := []issueFormSection{}
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "### ") {
+			sections = append(sections, issueFormSection{label: strings.TrimSpace(strings.TrimPrefix(trimmed, "### "))})
+			continue
+		}
+		if len(sections) == 0 || strings.HasPrefix(trimmed, "<!--") {
+			continue
+		}
+		current := &sections[len(sections)-1]
+		current.lines = append(current.lines, strings.TrimRight(line, " \t\r"))
+	}
+	return sections
+}
+
+// issueFormAnswer returns a section's answer, or false when the field was left blank. Checkbox
+// fields answer with their ticked labels joined by ", ", and multi-line text keeps its lines.
+func issueFormAnswer(lines []string) (string, bool) {
+	answer := strings.TrimSpace(strings.Join(lines, "\n"))
+	if answer == "" || answer == issueNoResponse {
+		return "", false
+	}
+
+	checked := []string{}
+	for _, line := range strings.Split(answer, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		lower := strings.ToLower(trimmed)
+		switch {
+		case strings.HasPrefix(lower, "- [x]"):
+			checked = append(checked, strings.TrimSpace(trimmed[len("- [x]"):]))
+		case strings.HasPrefix(lower, "- [ ]"):
+		default:
+			return answer, true
+		}
+	}
+	if len(checked) == 0 {
+		return "", false
+	}
+	return strings.Join(checked, ", "), true
+}
+
+// parseOrderJSON reads a body that is a JSON object, bare or in a ```json fence, as answers keyed
+// by field ID. Lists become comma-separated answers like a multi-select field.
+func parseOrderJSON(body string) (map[string]string, bool) {
+	trimmed := strings.TrimSpace(body)
+	if fenced, ok := strings.CutPrefix(trimmed, "```json"); ok {
+		trimmed = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(fenced), "```"))
+	}
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, false
+	}
+
+	answers := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case nil:
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, strings.TrimSpace(fmt.Sprint(item)))
+			}
+			if len(items) > 0 {
+				answers[key] = strings.Join(items, ", ")
+			}
+		case bool:
+			if v {
+				answers[key] = "yes"
+			}
+		default:
+			if answer := strings.TrimSpace(fmt.Sprint(v)); answer != "" {
+				answers[key] = answer
+			}
+		}
+	}
+	return answers, true
+}
+
+// takeCheckboxAnswer removes a managed checkbox answer from the parsed options and reports
+// whether the buyer ticked it. The answer is keyed by the field ID when the template was known,
+// and by the English label otherwise.
+func takeCheckboxAnswer(options map[string]any, fieldID, fieldLabel string) bool {
+	checked := false
+	for _, key := range []string{fieldID, normalizeHeader(fieldLabel)} {
+		value, ok := options[key]
+		if !ok {
+			continue
+		}
+		delete(options, key)
+		answer, _ := value.(string)
+		checked = checked || strings.TrimSpace(answer) != ""
+	}
+	return checked
+}
+
+// takeTextAnswer removes a managed text answer from the parsed options and returns it trimmed,
+// keyed like takeCheckboxAnswer.
+func takeTextAnswer(options map[string]any, fieldID, fieldLabel string) string {
+	text := ""
+	for _, key := range []string{fieldID, normalizeHeader(fieldLabel)} {
+		value, ok := options[key]
+		if !ok {
+			continue
+		}
+		delete(options, key)
+		if answer, _ := value.(string); text == "" {
+			text = strings.TrimSpace(answer)
+		}
+	}
+	return text
+}
+
+// takeBuyerEmail removes the email and reminder opt-out answers from the parsed options. The
+// email is returned only when it is a single bare address, and reports false when the buyer
+// entered something else, so a typo drops the email without blocking the order.
+func takeBuyerEmail(options map[string]any) (string, bool, bool) {
+	optOut := takeCheckboxAnswer(options, catalog.ReminderOptOutFieldID, catalog.ReminderOptOutFieldLabel)
+	answer := takeTextAnswer(options, catalog.EmailFieldID, catalog.EmailFieldLabel)
+	if answer == "" {
+		return "", optOut, true
+	}
+	address, err := mail.ParseAddress(answer)
+	if err != nil || address.Address != answer {
+		return "", optOut, false
+	}
+	return answer, optOut, true
+}
+
+// orderTemplateFieldIDs maps the normalized field labels of the repo's order templates to their
+// field IDs. It returns nil when the templates cannot be read, and parsing falls back to labels.
+func (s *OrderService) orderTemplateFieldIDs(ctx context.Context, client *githubapp.Client, repoFullName string) map[string]string {
+	templates, err := readOrderTemplates(ctx, client, repoFullName, contentAccess(ctx, client, s.logger))
+	if err != nil {
+		s.loggerFromContext(ctx).Debug("failed to read order templates for field IDs", "error", err, "repo", repoFullName)
+		return nil
+	}
+	fieldIDs := map[string]string{}
+	for _, template := range templates.templates {
+		for label, id := range templateFieldIDs(template.content) {
+			fieldIDs[label] = id
+		}
+	}
+	return fieldIDs
+}
+
+// templateFieldIDs maps the normalized field labels of one order template to their field IDs.
+func templateFieldIDs(template string) map[string]string {
+	ids, err := catalog.TemplateFieldIDs(template)
+	if err != nil {
+		return nil
+	}
+	fieldIDs := make(map[string]string, len(ids))
+	for label, id := range ids {
+		fieldIDs[normalizeHeader(label)] = id
+	}
+	return fieldIDs
+}
+
+func checkoutParamsForOrder(shop *db.Shop, order *db.Order, config *catalog.GitShopConfig, product *catalog.ProductConfig, repoFullName string) stripe.CheckoutSessionParams {
+	issueURL := fmt.Sprintf("https://github.com/%s/issues/%d", repoFullName, order.GitHubIssueNumber)
+	// The order's subtotal was priced from these options, so its unit price cannot overflow.
+	unitPriceCents, _ := catalog.UnitPrice(*product, order.Options)
+	return stripe.CheckoutSessionParams{
+		OrderID:         order.ID,
+		ShopID:          shop.ID,
+		IssueNumber:     order.GitHubIssueNumber,
+		RepoFullName:    repoFullName,
+		ProductName:     product.Name,
+		UnitPriceCents:  unitPriceCents,
+		Quantity:        int64(orderQuantity(order.Options)),
+		ShippingCents:   order.ShippingCents,
+		ShippingCarrier: config.Shop.Shipping.Carrier,
+		CustomerEmail:   order.CustomerEmail,
+		SuccessURL:      issueURL,
+		CancelURL:       issueURL,
+		StripeAccountID: shop.StripeConnectAccountID,
+		TermsURL:        config.Shop.Terms.URL,
+		TermsVersion:    order.TermsVersion,
+		RequireConsent:  config.Shop.Terms.StripeConsent,
+		ExpiresAt:       time.Now().Add(config.Shop.Checkout.ExpiresIn()),
+		Billing:         product.Billing,
+		CustomFields:    checkoutCustomFields(config.Shop.Checkout),
+		CollectTaxID:    config.Shop.Checkout.CollectTaxID,
+	}
+}
+
+func checkoutCustomFields(checkout catalog.CheckoutConfig) []stripe.CheckoutCustomField {
+	fields := make([]stripe.CheckoutCustomField, 0, len(checkout.CustomFields))
+	for _, field := range checkout.CustomFields {
+		fields = append(fields, stripe.CheckoutCustomField{
+			Key:      field.Key,
+			Label:    strings.TrimSpace(field.Label),
+			Numeric:  field.Type == catalog.CustomFieldNumeric,
+			Optional: field.Optional,
+		})
+	}
+	return fields
+}
+
 func extractSKU(value string) string {
 	skuRegex := regexp.MustCompile(`(?i)SKU[:\s]*([A-Z0-9_]+)`)
 	if matches := skuRegex.FindStringSubmatch(value); len(matches) >= 2 {
@@ -615,18 +1135,12 @@ func normalizeHeader(value string) string {
 func parseQuantity(value string) int {
 	value = strings.TrimSpace(value)
 	if qty, err := strconv.Atoi(value); err == nil && qty > 0 {
-		if qty > 10 {
-			return 10
-		}
 		return qty
 	}
 
 	numberRegex := regexp.MustCompile(`\d+`)
 	if match := numberRegex.FindString(value); match != "" {
 		if qty, err := strconv.Atoi(match); err == nil && qty > 0 {
-			if qty > 10 {
-				return 10
-			}
 			return qty
 		}
 	}
@@ -646,12 +1160,16 @@ func findProduct(config *catalog.GitShopConfig, sku string) *catalog.ProductConf
 	return nil
 }
 
-func (s *OrderService) ensureIssueNumberInTitle(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, title string) {
+func (s *OrderService) ensureOrderNumberInTitle(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber, orderNumber int, title string) {
 	if strings.TrimSpace(title) == "" {
 		return
 	}
+	if backpressure.ShouldShed(ctx) {
+		observability.MeterFromContext(ctx).Count("order.title_suffix.skipped", 1)
+		return
+	}
 
-	suffix := fmt.Sprintf("#%d", issueNumber)
+	suffix := fmt.Sprintf("(Order #%d)", orderNumber)
 	if strings.Contains(title, suffix) {
 		return
 	}
@@ -674,6 +1192,22 @@ func (s *OrderService) getGitShopConfigFile(ctx context.Context, client *githuba
 	return nil, err
 }
 
+// validatedConfig fetches, parses, and validates the shop's gitshop.yaml.
+func (s *OrderService) validatedConfig(ctx context.Context, client *githubapp.Client, repoFullName string) (*catalog.GitShopConfig, error) {
+	content, err := s.getGitShopConfigFile(ctx, client, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.parser.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Validate(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 func (s *OrderService) assignShopManager(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, config *catalog.GitShopConfig) {
 	if client == nil || config == nil {
 		return
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+var ErrOrderNotAwaitingApproval = errors.New("order not awaiting approval")
+
+const cancellationApprovalRejected = "approval_rejected"
+
+// orderApproval releases or declines orders held by checkout.approval_over_cents, for both the
+// dashboard and the `.gitshop approve` and `.gitshop reject` commands.
+type orderApproval struct {
+	orderStore     *db.OrderStore
+	payments       *PaymentProviders
+	stripePlatform *stripe.PlatformClient
+	logger         *slog.Logger
+}
+
+// approve moves a held order to pending payment and posts its checkout link. Products that need
+// an identity check get the verification link instead, and checkout follows once it passes. When
+// the checkout cannot be created the order is marked failed so the buyer can use `.gitshop retry`.
+func (a orderApproval) approve(ctx context.Context, client *githubapp.Client, shop *db.Shop, order *db.Order, config *catalog.GitShopConfig, approvedBy string) error {
+	logger := logging.FromContext(ctx, a.logger)
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("order.approval.failed", 1, sentry.WithAttributes(
+			attribute.String("action", "approve"),
+			attribute.String("reason", reason),
+		))
+	}
+
+	if order.Status != db.StatusAwaitingApproval {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: order status is %s", ErrOrderNotAwaitingApproval, order.Status)
+	}
+	product := findProduct(config, order.SKU)
+	if product == nil {
+		recordFailed("sku_missing")
+		return fmt.Errorf("sku not found: %s", order.SKU)
+	}
+	if err := checkShopSuspension(ctx, a.payments.suspensionStore(), shop); err != nil {
+		recordFailed("shop_suspended")
+		return err
+	}
+
+	if err := a.orderStore.MarkApproved(ctx, order.ID, approvedBy); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("mark_approved_failed")
+		return fmt.Errorf("failed to approve order: %w", err)
+	}
+
+	repoFullName := shop.GitHubRepoFullName
+	labels := shopLabels(config)
+	if err := client.RemoveLabel(ctx, repoFullName, order.GitHubIssueNumber, labels.Name(catalog.LabelAwaitingApproval)); err != nil {
+		logger.Warn("failed to remove awaiting-approval label", "error", err, "issue", order.GitHubIssueNumber)
+	}
+
+	if needsIdentityVerification(*product, order) {
+		loc := configLocalizer(config)
+		err := startIdentityVerification(ctx, a.stripePlatform, a.orderStore, client, shop, order, repoFullName, loc, approvedIdentityRequiredComment(loc, order.OrderNumber))
+		if errors.Is(err, errIdentityVerificationNotStarted) {
+			comment := withManagerMention(loc, loc.T("comment.identity_start_failed"), configManagerMention(config))
+			if commentErr := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, comment); commentErr != nil {
+				logger.Warn("failed to create verification-failed comment", "error", commentErr, "issue", order.GitHubIssueNumber)
+			}
+		}
+		if err != nil {
+			recordFailed("identity_verification_failed")
+			return err
+		}
+		if err := client.AddLabels(ctx, repoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelPendingPayment)}); err != nil {
+			logger.Warn("failed to add pending-payment label", "error", err, "issue", order.GitHubIssueNumber)
+		}
+		meter.Count("order.approval.approved", 1)
+		return nil
+	}
+
+	checkout, err := createOrderCheckout(ctx, a.payments, shop, order, checkoutParamsForOrder(shop, order, config, product, repoFullName), "approval")
+	if err != nil {
+		recordFailed("checkout_create_failed")
+		if markErr := a.orderStore.MarkFailed(ctx, order.ID, "stripe_checkout_failed"); markErr != nil {
+			logger.Warn("failed to mark order failed after checkout error", "error", markErr, "order_id", order.ID)
+		}
+		loc := configLocalizer(config)
+		failComment := checkoutFailedComment(loc, err, loc.T("comment.approval_checkout_failed"))
+		if commentErr := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, failComment); commentErr != nil {
+			logger.Warn("failed to create checkout-failed comment", "error", commentErr, "issue", order.GitHubIssueNumber)
+		}
+		return fmt.Errorf("failed to create checkout session: %w", err)
+	}
+	if err := saveOrderCheckout(ctx, a.orderStore, order, checkout); err != nil {
+		recordFailed("order_update_stripe_session_failed")
+		return fmt.Errorf("failed to update order with checkout: %w", err)
+	}
+
+	if err := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, checkout.comment(configLocalizer(config), order, config.Shop.Checkout.ExpiresIn(), shopCheckoutLinkComment(config))); err != nil {
+		recordFailed("checkout_comment_failed")
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	if err := client.AddLabels(ctx, repoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelPendingPayment)}); err != nil {
+		logger.Warn("failed to add pending-payment label", "error", err, "issue", order.GitHubIssueNumber)
+	}
+	meter.Count("order.approval.approved", 1)
+	meter.Count("checkout.session.created", 1, sentry.WithAttributes(
+		attribute.String("source", "approval"),
+	))
+	return nil
+}
+
+// reject cancels a held order. No checkout was ever created, so there is nothing to close.
+func (a orderApproval) reject(ctx context.Context, client *githubapp.Client, shop *db.Shop, order *db.Order) error {
+	logger := logging.FromContext(ctx, a.logger)
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("order.approval.failed", 1, sentry.WithAttributes(
+			attribute.String("action", "reject"),
+			attribute.String("reason", reason),
+		))
+	}
+
+	if order.Status != db.StatusAwaitingApproval {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: order status is %s", ErrOrderNotAwaitingApproval, order.Status)
+	}
+	if err := a.orderStore.MarkCancelled(ctx, order.ID, cancellationApprovalRejected); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("mark_cancelled_failed")
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	repoFullName := shop.GitHubRepoFullName
+	config := shopConfig(ctx, client, repoFullName)
+	labels := shopLabels(config)
+	if err := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, orderRejectedComment(configLocalizer(config), order.OrderNumber)); err != nil {
+		logger.Error("failed to create order-rejected comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	if err := client.RemoveLabel(ctx, repoFullName, order.GitHubIssueNumber, labels.Name(catalog.LabelAwaitingApproval)); err != nil {
+		logger.Warn("failed to remove awaiting-approval label", "error", err, "issue", order.GitHubIssueNumber)
+	}
+	if err := client.AddLabels(ctx, repoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelCancelled)}); err != nil {
+		logger.Warn("failed to add cancelled label", "error", err, "issue", order.GitHubIssueNumber)
+	}
+	meter.Count("order.approval.rejected", 1)
+	return nil
+}
+
+// handleApprovalCommand approves or rejects a held order from `.gitshop approve` or
+// `.gitshop reject`. Only repo admins can run them.
+func (s *OrderService) handleApprovalCommand(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, order *db.Order, commenterLogin string, hasPermission bool, shop *db.Shop, approve bool) error {
+	command := "reject"
+	if approve {
+		command = "approve"
+	}
+	meter := observability.MeterFromContext(ctx)
+	recordRejected := func(reason string) {
+		meter.Count("order."+command+".rejected", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	loc := shopLocalizer(ctx, client, repoFullName)
+	if order == nil || shop == nil {
+		recordRejected("order_not_found")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.order_not_found"))
+	}
+	if !hasPermission {
+		recordRejected("permission_denied")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.approval_permission_denied"))
+	}
+	if order.Status != db.StatusAwaitingApproval {
+		recordRejected("invalid_order_status")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.approval_not_awaiting"))
+	}
+
+	approval := orderApproval{
+		orderStore:     s.orderStore,
+		payments:       s.payments,
+		stripePlatform: s.stripePlatform,
+		logger:         s.logger,
+	}
+	var err error
+	if approve {
+		var config *catalog.GitShopConfig
+		config, err = s.validatedConfig(ctx, client, repoFullName)
+		if err != nil {
+			recordRejected("config_invalid")
+			return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.approval_config_invalid"))
+		}
+		err = approval.approve(ctx, client, shop, order, config, commenterLogin)
+	} else {
+		err = approval.reject(ctx, client, shop, order)
+	}
+	if errors.Is(err, ErrOrderNotAwaitingApproval) || errors.Is(err, ErrAdminOrderStatusConflict) {
+		recordRejected("invalid_order_status")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.approval_not_awaiting"))
+	}
+	return err
+}
+
+type ApproveOrderInput struct {
+	ShopID     uuid.UUID
+	OrderID    uuid.UUID
+	ApprovedBy string
+}
+
+// ApproveOrder releases an order held for approval and posts its checkout link, or its identity
+// verification link, on the issue.
+func (s *AdminService) ApproveOrder(ctx context.Context, input ApproveOrderInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.approve_order",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("ApproveOrder"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	order, shop, err := s.heldOrder(ctx, OrderActionInput{ShopID: input.ShopID, OrderID: input.OrderID})
+	if err != nil {
+		return err
+	}
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	config, err := s.fetchValidatedConfig(ctx, client, shop.GitHubRepoFullName)
+	if err != nil {
+		return UserError{Message: err.Error()}
+	}
+	return s.orderApproval().approve(ctx, client, shop, order, config, input.ApprovedBy)
+}
+
+// RejectOrder cancels an order held for approval and tells the buyer on the issue.
+func (s *AdminService) RejectOrder(ctx context.Context, input OrderActionInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.reject_order",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("RejectOrder"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	order, shop, err := s.heldOrder(ctx, input)
+	if err != nil {
+		return err
+	}
+	return s.orderApproval().reject(ctx, s.githubClient.WithInstallation(shop.GitHubInstallationID), shop, order)
+}
+
+func (s *AdminService) heldOrder(ctx context.Context, input OrderActionInput) (*db.Order, *db.Shop, error) {
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order.Status != db.StatusAwaitingApproval {
+		return nil, nil, fmt.Errorf("%w: order status is %s", ErrOrderNotAwaitingApproval, order.Status)
+	}
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	return order, shop, nil
+}
+
+func (s *AdminService) orderApproval() orderApproval {
+	return orderApproval{
+		orderStore:     s.orderStore,
+		payments:       s.payments,
+		stripePlatform: s.stripePlatform,
+		logger:         s.logger,
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestHoldsForApproval(t *testing.T) {
+	t.Parallel()
+
+	checkout := catalog.CheckoutConfig{ApprovalOverCents: 50000}
+
+	tests := []struct {
+		name       string
+		product    catalog.ProductConfig
+		totalCents int64
+		want       bool
+	}{
+		{name: "over threshold", product: catalog.ProductConfig{SKU: "espresso"}, totalCents: 50001, want: true},
+		{name: "at threshold", product: catalog.ProductConfig{SKU: "espresso"}, totalCents: 50000, want: false},
+		{name: "identity check over threshold", product: catalog.ProductConfig{SKU: "knife", VerifyIdentity: true}, totalCents: 75000, want: true},
+		{name: "identity check under threshold", product: catalog.ProductConfig{SKU: "knife", VerifyIdentity: true}, totalCents: 1000, want: false},
+		{name: "inquiry over threshold", product: catalog.ProductConfig{SKU: "wholesale", Type: catalog.ProductTypeInquiry}, totalCents: 75000, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := holdsForApproval(tt.product, checkout, tt.totalCents); got != tt.want {
+				t.Fatalf("holdsForApproval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeedsIdentityVerification(t *testing.T) {
+	t.Parallel()
+
+	gated := catalog.ProductConfig{SKU: "knife", VerifyIdentity: true}
+
+	tests := []struct {
+		name    string
+		product catalog.ProductConfig
+		order   *db.Order
+		want    bool
+	}{
+		{name: "approved and not verified", product: gated, order: &db.Order{Status: db.StatusPendingPayment}, want: true},
+		{name: "verification pending", product: gated, order: &db.Order{VerificationStatus: db.VerificationPending}, want: true},
+		{name: "already verified", product: gated, order: &db.Order{VerificationStatus: db.VerificationVerified}, want: false},
+		{name: "no identity check", product: catalog.ProductConfig{SKU: "espresso"}, order: &db.Order{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := needsIdentityVerification(tt.product, tt.order); got != tt.want {
+				t.Fatalf("needsIdentityVerification() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// BulkOrderAction is an action the seller applies to several orders at once from the dashboard.
+type BulkOrderAction string
+
+const (
+	BulkOrderShip        BulkOrderAction = "ship"
+	BulkOrderExport      BulkOrderAction = "export"
+	BulkOrderNote        BulkOrderAction = "note"
+	BulkOrderResendEmail BulkOrderAction = "resend_email"
+)
+
+const (
+	// bulkOrderLimit caps the orders one bulk action touches. Each order can comment and label
+	// its issue, so a larger batch risks GitHub's secondary rate limits.
+	bulkOrderLimit = 50
+
+	orderNoteMaxLength = 2000
+)
+
+type BulkOrderActionInput struct {
+	ShopID   uuid.UUID
+	OrderIDs []uuid.UUID
+	Action   BulkOrderAction
+	// TrackingCSV has one "order number, tracking number, carrier" row per order to ship. A
+	// header row is allowed.
+	TrackingCSV string
+	Note        string
+	Author      string
+}
+
+// BulkOrderItemResult is the outcome of a bulk action for one order. Error is empty when the
+// action succeeded and is written for the seller otherwise.
+type BulkOrderItemResult struct {
+	OrderID     uuid.UUID
+	OrderNumber int
+	Detail      string
+	Error       string
+}
+
+// BulkOrderActionResult lists the outcome for each selected order, in the order they were
+// selected. Export holds the CSV of the selected orders for BulkOrderExport.
+type BulkOrderActionResult struct {
+	Action BulkOrderAction
+	Items  []BulkOrderItemResult
+	Export []byte
+}
+
+// Succeeded counts the orders the action succeeded for.
+func (r *BulkOrderActionResult) Succeeded() int {
+	succeeded := 0
+	for _, item := range r.Items {
+		if item.Error == "" {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+// BulkOrderAction applies one action to each selected order. A failure on one order is reported
+// in its result and does not stop the others; an error is returned only when the request itself
+// is invalid, such as a malformed tracking CSV.
+func (s *AdminService) BulkOrderAction(ctx context.Context, input BulkOrderActionInput) (*BulkOrderActionResult, error) {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.bulk_order_action",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("BulkOrderAction"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	if s == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	meter := observability.MeterFromContext(ctx)
+	recordRejected := func(reason string) {
+		meter.Count("order.bulk.rejected", 1, sentry.WithAttributes(
+			attribute.String("action", string(input.Action)),
+			attribute.String("reason", reason),
+		))
+	}
+
+	orderIDs := uniqueOrderIDs(input.OrderIDs)
+	switch {
+	case len(orderIDs) == 0:
+		recordRejected("no_orders_selected")
+		return nil, UserError{Message: "Select at least one order."}
+	case len(orderIDs) > bulkOrderLimit:
+		recordRejected("too_many_orders")
+		return nil, UserError{Message: fmt.Sprintf("Select at most %d orders at a time.", bulkOrderLimit)}
+	}
+
+	var apply func(context.Context, *db.Order) (string, error)
+	var exported []CustomerOrderRecord
+	switch input.Action {
+	case BulkOrderShip:
+		shipments, err := parseTrackingCSV(input.TrackingCSV)
+		if err != nil {
+			recordRejected("invalid_tracking_csv")
+			return nil, err
+		}
+		apply = func(ctx context.Context, order *db.Order) (string, error) {
+			return s.bulkShip(ctx, order, shipments)
+		}
+	case BulkOrderNote:
+		note := strings.TrimSpace(input.Note)
+		switch {
+		case note == "":
+			recordRejected("empty_note")
+			return nil, UserError{Message: "Enter a note to add."}
+		case len(note) > orderNoteMaxLength:
+			recordRejected("note_too_long")
+			return nil, UserError{Message: fmt.Sprintf("Keep notes under %d characters.", orderNoteMaxLength)}
+		}
+		apply = func(ctx context.Context, order *db.Order) (string, error) {
+			if err := s.orderStore.AddNote(ctx, &db.OrderNote{OrderID: order.ID, Body: note, Author: input.Author}); err != nil {
+				return "", fmt.Errorf("failed to add note: %w", err)
+			}
+			return "Note added", nil
+		}
+	case BulkOrderResendEmail:
+		apply = func(ctx context.Context, order *db.Order) (string, error) {
+			kind, err := s.ResendOrderEmail(ctx, OrderActionInput{ShopID: input.ShopID, OrderID: order.ID})
+			if err != nil {
+				return "", err
+			}
+			return "Resent the " + strings.ReplaceAll(string(kind), "_", " ") + " email", nil
+		}
+	case BulkOrderExport:
+		apply = func(ctx context.Context, order *db.Order) (string, error) {
+			emails, err := s.orderStore.ListEmails(ctx, order.ID)
+			if err != nil {
+				return "", fmt.Errorf("failed to load order emails: %w", err)
+			}
+			exported = append(exported, customerOrderRecord(order, emails))
+			return "Exported", nil
+		}
+	default:
+		recordRejected("unknown_action")
+		return nil, UserError{Message: "Choose an action for the selected orders."}
+	}
+
+	logger := s.loggerFromContext(ctx)
+	result := &BulkOrderActionResult{Action: input.Action, Items: make([]BulkOrderItemResult, 0, len(orderIDs))}
+	for _, orderID := range orderIDs {
+		item := BulkOrderItemResult{OrderID: orderID}
+		order, err := s.getShopOrder(ctx, OrderActionInput{ShopID: input.ShopID, OrderID: orderID})
+		if err == nil {
+			item.OrderNumber = order.OrderNumber
+			item.Detail, err = apply(ctx, order)
+		}
+		if err != nil {
+			item.Detail = ""
+			item.Error = bulkOrderErrorMessage(err)
+			meter.Count("order.bulk.item_failed", 1, sentry.WithAttributes(
+				attribute.String("action", string(input.Action)),
+			))
+			logger.Warn("bulk order action failed", "error", err, "action", input.Action, "order_id", orderID)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	if input.Action == BulkOrderExport && len(exported) > 0 {
+		export, err := orderRecordsCSV(exported)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode order export: %w", err)
+		}
+		result.Export = export
+	}
+	meter.Count("order.bulk.processed", 1, sentry.WithAttributes(
+		attribute.String("action", string(input.Action)),
+		attribute.Int("orders", len(result.Items)),
+		attribute.Int("succeeded", result.Succeeded()),
+	))
+	return result, nil
+}
+
+// bulkShip ships an order with its row from the tracking CSV, or updates the tracking number
+// of an order already shipped, like the ship dialog does.
+func (s *AdminService) bulkShip(ctx context.Context, order *db.Order, shipments map[int]trackingRow) (string, error) {
+	shipment, ok := shipments[order.OrderNumber]
+	if !ok {
+		return "", UserError{Message: "No row for this order in the tracking CSV."}
+	}
+	err := s.ShipOrder(ctx, ShipOrderInput{
+		ShopID:         order.ShopID,
+		OrderID:        order.ID,
+		TrackingNumber: shipment.trackingNumber,
+		Carrier:        shipment.carrier,
+	})
+	if err != nil {
+		return "", err
+	}
+	if order.Status == db.StatusShipped {
+		return "Tracking updated", nil
+	}
+	return "Marked shipped", nil
+}
+
+// bulkOrderErrorMessage turns the error from one order of a bulk action into a message for the
+// seller. Unexpected errors are not shown, since they can carry internal details.
+func bulkOrderErrorMessage(err error) string {
+	var userErr UserError
+	switch {
+	case errors.As(err, &userErr):
+		return userErr.Message
+	case errors.Is(err, ErrAdminOrderNotFound):
+		return "Order not found."
+	case errors.Is(err, ErrAdminOrderStatusConflict):
+		return "Only paid or shipped orders can be shipped."
+	case errors.Is(err, ErrAdminInvalidShipmentInput):
+		return "A tracking number and carrier are required."
+	case errors.Is(err, ErrAdminEmailUnavailable):
+		return "No customer email to resend for this order's status."
+	default:
+		return "Something went wrong. Try this order again."
+	}
+}
+
+type trackingRow struct {
+	trackingNumber string
+	carrier        string
+}
+
+// parseTrackingCSV reads "order number, tracking number, carrier" rows keyed by order number. A
+// leading # on the order number and a header row are allowed.
+func parseTrackingCSV(text string) (map[int]trackingRow, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows := map[int]trackingRow{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, UserError{Message: fmt.Sprintf("Tracking CSV line %d: %s.", line, csvErrorReason(err))}
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		orderNumber, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(record[0]), "#"))
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, UserError{Message: fmt.Sprintf("Tracking CSV line %d: %q is not an order number.", line, record[0])}
+		}
+		if len(record) < 3 || strings.TrimSpace(record[1]) == "" || strings.TrimSpace(record[2]) == "" {
+			return nil, UserError{Message: fmt.Sprintf("Tracking CSV line %d: expected order number, tracking number, carrier.", line)}
+		}
+		if _, ok := rows[orderNumber]; ok {
+			return nil, UserError{Message: fmt.Sprintf("Tracking CSV line %d: order #%d is listed twice.", line, orderNumber)}
+		}
+		rows[orderNumber] = trackingRow{trackingNumber: strings.TrimSpace(record[1]), carrier: strings.TrimSpace(record[2])}
+	}
+	if len(rows) == 0 {
+		return nil, UserError{Message: "Paste a tracking CSV with one row per order: order number, tracking number, carrier."}
+	}
+	return rows, nil
+}
+
+func csvErrorReason(err error) string {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// uniqueOrderIDs drops repeated and empty IDs, keeping the first occurrence of each.
+func uniqueOrderIDs(orderIDs []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(orderIDs))
+	unique := make([]uuid.UUID, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if orderID == uuid.Nil || seen[orderID] {
+			continue
+		}
+		seen[orderID] = true
+		unique = append(unique, orderID)
+	}
+	return unique
+}
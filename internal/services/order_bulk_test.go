@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseTrackingCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		text    string
+		want    map[int]trackingRow
+		wantErr string
+	}{
+		{
+			name: "header and order number prefix",
+			text: "order, tracking, carrier\n#1042, 1Z999AA10123456784, ups\n1043,9400100000000000000000,usps\n\n",
+			want: map[int]trackingRow{
+				1042: {trackingNumber: "1Z999AA10123456784", carrier: "ups"},
+				1043: {trackingNumber: "9400100000000000000000", carrier: "usps"},
+			},
+		},
+		{
+			name:    "missing carrier",
+			text:    "1042, 1Z999AA10123456784\n",
+			wantErr: "line 1: expected order number, tracking number, carrier",
+		},
+		{
+			name:    "not an order number",
+			text:    "1042, 1Z999AA10123456784, ups\nnext, 1Z999AA10123456785, ups\n",
+			wantErr: `line 2: "next" is not an order number`,
+		},
+		{
+			name:    "order listed twice",
+			text:    "1042, 1Z999AA10123456784, ups\n1042, 1Z999AA10123456785, ups\n",
+			wantErr: "line 2: order #1042 is listed twice",
+		},
+		{
+			name:    "empty",
+			text:    "  \n",
+			wantErr: "Paste a tracking CSV",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseTrackingCSV(tc.text)
+			if tc.wantErr != "" {
+				var userErr UserError
+				if !errors.As(err, &userErr) || !strings.Contains(userErr.Message, tc.wantErr) {
+					t.Fatalf("parseTrackingCSV() error = %v, want a UserError containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTrackingCSV() error = %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("parseTrackingCSV() = %v, want %v", got, tc.want)
+			}
+			for orderNumber, want := range tc.want {
+				if got[orderNumber] != want {
+					t.Fatalf("row for #%d = %+v, want %+v", orderNumber, got[orderNumber], want)
+				}
+			}
+		})
+	}
+}
+
+func TestUniqueOrderIDs(t *testing.T) {
+	t.Parallel()
+
+	first, second := uuid.New(), uuid.New()
+	got := uniqueOrderIDs([]uuid.UUID{first, uuid.Nil, second, first})
+	if len(got) != 2 || got[0] != first || got[1] != second {
+		t.Fatalf("uniqueOrderIDs() = %v, want [%s %s]", got, first, second)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	cancellationIssueClosed  = "issue_closed"
+	cancellationIssueDeleted = "issue_deleted"
+)
+
+type IssueClosedInput struct {
+	InstallationID int64
+	RepoID         int64
+	RepoFullName   string
+	IssueNumber    int
+	Deleted        bool
+}
+
+// HandleIssueClosed cancels the issue's order when it was never paid, after closing its checkout.
+// Orders whose checkout cannot be closed are left alone because a payment may be in flight.
+func (s *OrderService) HandleIssueClosed(ctx context.Context, input IssueClosedInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.order.handle_issue_closed",
+		sentry.WithOpName("service.order"),
+		sentry.WithDescription("HandleIssueClosed"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	reason := cancellationIssueClosed
+	if input.Deleted {
+		reason = cancellationIssueDeleted
+	}
+	logger := s.loggerFromContext(ctx).With("repo", input.RepoFullName, "issue", input.IssueNumber)
+	meter := observability.MeterFromContext(ctx)
+	meter.SetAttributes(attribute.String("source", reason))
+	recordIgnored := func(why string) {
+		meter.Count("order.cancel.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", why),
+		))
+	}
+	recordFailure := func(why string) {
+		meter.Count("order.cancel.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", why),
+		))
+	}
+
+	shop, err := s.shopStore.GetByInstallationAndRepoID(ctx, input.InstallationID, input.RepoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		recordIgnored("shop_not_found")
+		return nil
+	}
+	if err != nil {
+		recordFailure("shop_lookup_failed")
+		return fmt.Errorf("failed to get shop: %w", err)
+	}
+	order, err := s.orderStore.GetByShopAndIssue(ctx, shop.ID, input.IssueNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		recordIgnored("order_not_found")
+		return nil
+	}
+	if err != nil {
+		recordFailure("order_lookup_failed")
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != db.StatusPendingPayment && order.Status != db.StatusPaymentFailed && order.Status != db.StatusAwaitingApproval {
+		recordIgnored("order_not_pending")
+		return nil
+	}
+
+	if order.Status == db.StatusPendingPayment {
+		if err := s.closeOrderCheckout(ctx, shop, order); err != nil {
+			recordFailure("checkout_close_failed")
+			logger.Warn("kept order whose checkout could not be closed", "error", err, "order_id", order.ID)
+			return nil
+		}
+	}
+	if err := s.orderStore.MarkCancelled(ctx, order.ID, reason); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordIgnored("order_not_pending")
+			return nil
+		}
+		recordFailure("mark_cancelled_failed")
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	meter.Count("order.cancelled", 1)
+	logger.Info("cancelled unpaid order", "order_id", order.ID, "reason", reason)
+
+	if input.Deleted {
+		return nil
+	}
+	githubClient := s.githubClient.WithInstallation(input.InstallationID)
+	config := shopConfig(ctx, githubClient, input.RepoFullName)
+	labels := shopLabels(config)
+	if err := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, orderCancelledComment(configLocalizer(config), order.OrderNumber)); err != nil {
+		logger.Warn("failed to create order-cancelled comment", "error", err)
+	}
+	for _, label := range []string{labels.Name(catalog.LabelPendingPayment), labels.Name(catalog.LabelAwaitingApproval)} {
+		if err := githubClient.RemoveLabel(ctx, input.RepoFullName, input.IssueNumber, label); err != nil {
+			logger.Debug("failed to remove label for cancellation", "error", err, "label", label)
+		}
+	}
+	if err := githubClient.AddLabels(ctx, input.RepoFullName, input.IssueNumber, []string{labels.Name(catalog.LabelCancelled)}); err != nil {
+		logger.Warn("failed to add cancelled label", "error", err)
+	}
+	deleteCheckoutLinkComments(ctx, logger, githubClient, input.RepoFullName, input.IssueNumber)
+	return nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+// comment is the issue comment that hands the checkout to the buyer. sessionComment renders it
+// for a checkout that expires; Payment Links do not, so they get their own wording.
+func (c PaymentCheckout) comment(loc i18n.Localizer, order *db.Order, expiresIn time.Duration, sessionComment func(i18n.Localizer, int, string, time.Duration) string) string {
+	var body string
+	if c.Method == db.CheckoutMethodPaymentLink {
+		body = paymentLinkComment(loc, order.OrderNumber, c.URL)
+	} else {
+		body = sessionComment(loc, order.OrderNumber, c.URL, expiresIn)
+	}
+	if c.Estimate != "" {
+		body = currencyEstimateNote(loc, c.Estimate, order.TotalCents) + "\n\n" + body
+	}
+	if order.Campaign != "" {
+		body = loc.T("comment.campaign_applied", order.Campaign) + "\n\n" + body
+	}
+	return body
+}
+
+// createOrderCheckout creates the buyer's checkout with the shop's payment processor, in test
+// mode for test orders. It fails with ErrShopSuspended while the platform has suspended the shop,
+// and with ErrSubscriptionsDisabled for a subscription the shop is not allowed to sell.
+func createOrderCheckout(ctx context.Context, payments *PaymentProviders, shop *db.Shop, order *db.Order, params stripe.CheckoutSessionParams, source string) (PaymentCheckout, error) {
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(processor db.PaymentProcessor) {
+		meter.Count("checkout.session.failed", 1, sentry.WithAttributes(
+			attribute.String("source", source),
+			attribute.String("reason", "create_failed"),
+			attribute.String("processor", string(processor)),
+		))
+	}
+
+	if err := checkShopSuspension(ctx, payments.suspensionStore(), shop); err != nil {
+		if errors.Is(err, ErrShopSuspended) {
+			meter.Count("checkout.session.failed", 1, sentry.WithAttributes(
+				attribute.String("source", source),
+				attribute.String("reason", "shop_suspended"),
+			))
+		} else {
+			recordFailed("")
+		}
+		return PaymentCheckout{}, err
+	}
+	if err := checkSubscriptionsEnabled(ctx, payments.featureFlags(), shop, params.IsSubscription()); err != nil {
+		meter.Count("checkout.session.failed", 1, sentry.WithAttributes(
+			attribute.String("source", source),
+			attribute.String("reason", "subscriptions_disabled"),
+		))
+		return PaymentCheckout{}, err
+	}
+	provider, err := payments.ForShop(ctx, shop)
+	if err != nil {
+		recordFailed("")
+		return PaymentCheckout{}, err
+	}
+	checkout, err := provider.CreateCheckout(stripe.WithTestMode(ctx, order.TestMode), params)
+	if err != nil || checkout.Method == db.CheckoutMethodPaymentLink {
+		recordFailed(provider.Processor())
+	}
+	if err == nil {
+		checkout.Estimate = orderTotalEstimate(ctx, payments.rateSource(), order)
+	}
+	return checkout, err
+}
+
+// saveOrderCheckout records which checkout the order's buyer was sent.
+func saveOrderCheckout(ctx context.Context, orderStore *db.OrderStore, order *db.Order, checkout PaymentCheckout) error {
+	var err error
+	switch checkout.Method {
+	case db.CheckoutMethodPaymentLink:
+		err = orderStore.UpdateStripePaymentLink(ctx, order.ID, checkout.ID)
+	case db.CheckoutMethodSession:
+		err = orderStore.UpdateStripeSession(ctx, order.ID, checkout.ID)
+	default:
+		err = orderStore.UpdateProviderCheckout(ctx, order.ID, checkout.Method)
+	}
+	if err != nil {
+		return err
+	}
+	return orderStore.SetCheckoutExpiresAt(ctx, order.ID, checkout.ExpiresAt)
+}
+
+// markPendingCheckout moves a failed or expired order back to pending payment with its new
+// checkout.
+func markPendingCheckout(ctx context.Context, orderStore *db.OrderStore, order *db.Order, checkout PaymentCheckout) error {
+	var err error
+	switch checkout.Method {
+	case db.CheckoutMethodPaymentLink:
+		err = orderStore.MarkPendingPaymentLink(ctx, order.ID, checkout.ID)
+	case db.CheckoutMethodSession:
+		err = orderStore.MarkPendingPayment(ctx, order.ID, checkout.ID)
+	default:
+		err = orderStore.MarkPendingProviderCheckout(ctx, order.ID, checkout.Method)
+	}
+	if err != nil {
+		return err
+	}
+	return orderStore.SetCheckoutExpiresAt(ctx, order.ID, checkout.ExpiresAt)
+}
+
+// deactivatePaymentLink closes an order's earlier Payment Link before a new checkout replaces it,
+// so the buyer cannot pay twice.
+func (s *OrderService) deactivatePaymentLink(ctx context.Context, shop *db.Shop, order *db.Order) {
+	if order.CheckoutMethod != db.CheckoutMethodPaymentLink || order.StripePaymentLinkID == "" || s.stripePlatform == nil {
+		return
+	}
+	if err := s.stripePlatform.DeactivatePaymentLink(stripe.WithTestMode(ctx, order.TestMode), shop.StripeConnectAccountID, order.StripePaymentLinkID); err != nil {
+		s.loggerFromContext(ctx).Warn("failed to deactivate payment link", "error", err, "order_id", order.ID, "payment_link_id", order.StripePaymentLinkID)
+	}
+}
+
+// closeOrderCheckout stops the order's open checkout from taking payment. A Checkout Session that
+// cannot be expired may already be paid, so that error is returned rather than logged.
+func (s *OrderService) closeOrderCheckout(ctx context.Context, shop *db.Shop, order *db.Order) error {
+	if order.CheckoutMethod == db.CheckoutMethodPaymentLink {
+		s.deactivatePaymentLink(ctx, shop, order)
+		return nil
+	}
+	if order.StripeCheckoutSessionID == "" {
+		return nil
+	}
+	if s.stripePlatform == nil {
+		return errors.New("stripe is not configured")
+	}
+	return s.stripePlatform.ExpireCheckoutSession(stripe.WithTestMode(ctx, order.TestMode), shop.StripeConnectAccountID, order.StripeCheckoutSessionID)
+}
+
+// checkoutFailedComment is the comment for a checkout that could not be created: the
+// suspension notice when the shop is suspended, the subscriptions notice when it may not sell
+// subscriptions, otherwise fallback, which callers build from loc so the buyer reads any of them
+// in the shop's language.
+func checkoutFailedComment(loc i18n.Localizer, err error, fallback string) string {
+	switch {
+	case errors.Is(err, ErrShopSuspended):
+		return loc.T("comment.shop_suspended")
+	case errors.Is(err, ErrSubscriptionsDisabled):
+		return loc.T("comment.subscriptions_unavailable")
+	}
+	return fallback
+}
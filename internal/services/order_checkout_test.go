@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/featureflags"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+// countingCheckoutProvider creates a checkout session for every call and counts them.
+type countingCheckoutProvider struct {
+	PaymentProvider
+	created int
+}
+
+func (p *countingCheckoutProvider) Processor() db.PaymentProcessor {
+	return db.PaymentProcessorStripe
+}
+
+func (p *countingCheckoutProvider) CreateCheckout(context.Context, stripe.CheckoutSessionParams) (PaymentCheckout, error) {
+	p.created++
+	return PaymentCheckout{Method: db.CheckoutMethodSession, ID: "cs_test", URL: "https://checkout.stripe.com/c/pay/cs_test"}, nil
+}
+
+func TestCreateOrderCheckout_SubscriptionsFlag(t *testing.T) {
+	t.Parallel()
+
+	enabled := featureflags.New(map[featureflags.Flag]featureflags.Rule{featureflags.Subscriptions: {Enabled: true}}, nil, nil)
+	tests := []struct {
+		name        string
+		flags       *featureflags.Flags
+		billing     string
+		wantErr     error
+		wantCreated int
+	}{
+		{name: "one-time order with flag off", billing: "", wantCreated: 1},
+		{name: "subscription with flag off", billing: "monthly", wantErr: ErrSubscriptionsDisabled},
+		{name: "subscription with flag on", flags: enabled, billing: "monthly", wantCreated: 1},
+	}
+
+	// Retries, edits, approvals, identity verification, and recovery all create their checkout
+	// through createOrderCheckout, so a retry stands in for each of them.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &countingCheckoutProvider{}
+			payments := (&PaymentProviders{
+				settings: fakePaymentSettingsStore{settings: db.PaymentSettings{Processor: db.PaymentProcessorStripe}},
+				stripe:   provider,
+			}).WithFeatureFlags(tt.flags)
+			shop := &db.Shop{ID: uuid.New(), StripeConnectAccountID: "acct_1"}
+			order := &db.Order{ID: uuid.New(), ShopID: shop.ID}
+
+			_, err := createOrderCheckout(t.Context(), payments, shop, order, stripe.CheckoutSessionParams{Billing: tt.billing}, "retry")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("createOrderCheckout() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("createOrderCheckout() error = %v", err)
+			}
+			if provider.created != tt.wantCreated {
+				t.Fatalf("checkouts created = %d, want %d", provider.created, tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestPaymentCheckout_Comment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		checkout    PaymentCheckout
+		campaign    string
+		wantContain string
+		expiresIn   time.Duration
+		wantExpiry  string
+	}{
+		{
+			name:        "checkout session",
+			checkout:    PaymentCheckout{Method: db.CheckoutMethodSession, ID: "cs_123", URL: "https://checkout.stripe.com/c/pay/cs_123"},
+			wantContain: "https://checkout.stripe.com/c/pay/cs_123",
+			expiresIn:   30 * time.Minute,
+			wantExpiry:  "expires in 30 minutes",
+		},
+		{
+			name:        "checkout session with longer expiry",
+			checkout:    PaymentCheckout{Method: db.CheckoutMethodSession, ID: "cs_456", URL: "https://checkout.stripe.com/c/pay/cs_456"},
+			wantContain: "https://checkout.stripe.com/c/pay/cs_456",
+			expiresIn:   2 * time.Hour,
+			wantExpiry:  "expires in 2 hours",
+		},
+		{
+			name:        "payment link",
+			checkout:    PaymentCheckout{Method: db.CheckoutMethodPaymentLink, ID: "plink_123", URL: "https://buy.stripe.com/test_123"},
+			wantContain: "https://buy.stripe.com/test_123",
+			expiresIn:   30 * time.Minute,
+		},
+		{
+			name:        "campaign order",
+			checkout:    PaymentCheckout{Method: db.CheckoutMethodSession, ID: "cs_789", URL: "https://checkout.stripe.com/c/pay/cs_789"},
+			campaign:    "Spring Sale",
+			wantContain: "Spring Sale sale price applied",
+			expiresIn:   30 * time.Minute,
+			wantExpiry:  "expires in 30 minutes",
+		},
+		{
+			name:        "currency estimate",
+			checkout:    PaymentCheckout{Method: db.CheckoutMethodSession, ID: "cs_321", URL: "https://checkout.stripe.com/c/pay/cs_321", Estimate: "23.10 EUR"},
+			wantContain: "about 23.10 EUR at today's exchange rate",
+			expiresIn:   30 * time.Minute,
+			wantExpiry:  "expires in 30 minutes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.checkout.comment(i18n.Localizer{}, &db.Order{OrderNumber: 42, Campaign: tt.campaign}, tt.expiresIn, checkoutLinkComment)
+			if !strings.Contains(got, tt.wantContain) {
+				t.Fatalf("comment %q missing %q", got, tt.wantContain)
+			}
+			if !strings.Contains(got, "<!-- gitshop:checkout-link -->") {
+				t.Fatalf("comment %q missing checkout link marker", got)
+			}
+			if tt.wantExpiry == "" && strings.Contains(got, "expire") {
+				t.Fatalf("comment %q mentions an expiry", got)
+			}
+			if tt.wantExpiry != "" && !strings.Contains(got, tt.wantExpiry) {
+				t.Fatalf("comment %q missing %q", got, tt.wantExpiry)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+type IssueEditedInput struct {
+	InstallationID int64
+	RepoID         int64
+	RepoFullName   string
+	IssueNumber    int
+	IssueBody      string
+	EditorLogin    string
+}
+
+// HandleIssueEdited re-prices an unpaid order after its issue body was edited. The old checkout is
+// closed before the order changes, so the buyer can never pay the old price for the new items.
+func (s *OrderService) HandleIssueEdited(ctx context.Context, input IssueEditedInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.order.handle_issue_edited",
+		sentry.WithOpName("service.order"),
+		sentry.WithDescription("HandleIssueEdited"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	meter.SetAttributes(attribute.String("source", "issue_edited"))
+	recordIgnored := func(reason string) {
+		meter.Count("order.edit.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	recordFailure := func(reason string) {
+		meter.Count("order.edit.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	shop, err := s.shopStore.GetByInstallationAndRepoID(ctx, input.InstallationID, input.RepoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		recordIgnored("shop_not_found")
+		return nil
+	}
+	if err != nil {
+		recordFailure("shop_lookup_failed")
+		return fmt.Errorf("failed to get shop: %w", err)
+	}
+	order, err := s.orderStore.GetByShopAndIssue(ctx, shop.ID, input.IssueNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		recordIgnored("order_not_found")
+		return nil
+	}
+	if err != nil {
+		recordFailure("order_lookup_failed")
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != db.StatusPendingPayment {
+		recordIgnored("order_not_pending")
+		return nil
+	}
+
+	githubClient := s.githubClient.WithInstallation(input.InstallationID)
+	rejectEdit := func(reason, message string) error {
+		recordFailure(reason)
+		if err := githubClient.CreateComment(ctx, input.RepoFullName, input.IssueNumber, message); err != nil {
+			logger.Warn("failed to create order-edit comment", "error", err, "repo", input.RepoFullName, "issue", input.IssueNumber, "reason", reason)
+		}
+		return nil
+	}
+
+	orderData, err := parseOrderFromIssue(input.IssueBody, s.orderTemplateFieldIDs(ctx, githubClient, input.RepoFullName))
+	if err != nil {
+		loc := shopLocalizer(ctx, githubClient, input.RepoFullName)
+		return rejectEdit("order_parse_failed", orderEditRejectedComment(loc, order.OrderNumber, orderParseMessage(loc, err)))
+	}
+	eligibilityAttested := takeCheckboxAnswer(orderData.Options, catalog.EligibilityFieldID, catalog.EligibilityFieldLabel)
+	termsAccepted := takeCheckboxAnswer(orderData.Options, catalog.TermsFieldID, catalog.TermsFieldLabel)
+	takeBuyerEmail(orderData.Options)
+	takeTextAnswer(orderData.Options, catalog.CurrencyFieldID, catalog.CurrencyFieldLabel)
+	if !orderEditChanged(order, orderData) {
+		recordIgnored("order_unchanged")
+		return nil
+	}
+	if !canReplaceCheckout(order) {
+		loc := shopLocalizer(ctx, githubClient, input.RepoFullName)
+		return rejectEdit("checkout_not_replaceable", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.checkout_not_replaceable")))
+	}
+
+	configContent, err := s.getGitShopConfigFile(ctx, githubClient, input.RepoFullName)
+	if err != nil {
+		recordFailure("config_missing")
+		return fmt.Errorf("failed to fetch gitshop.yaml: %w", err)
+	}
+	config, err := s.parser.Parse(configContent)
+	if err != nil {
+		recordFailure("config_parse_failed")
+		return fmt.Errorf("failed to parse gitshop.yaml: %w", err)
+	}
+	if err := s.validator.Validate(config); err != nil {
+		recordFailure("config_invalid")
+		return fmt.Errorf("invalid gitshop.yaml: %w", err)
+	}
+	return s.repriceOrder(ctx, githubClient, shop, order, config, orderData, orderRepricing{
+		RepoFullName:        input.RepoFullName,
+		IssueNumber:         input.IssueNumber,
+		EditedBy:            input.EditorLogin,
+		Source:              "issue_edited",
+		EligibilityAttested: eligibilityAttested,
+		TermsAccepted:       termsAccepted,
+	})
+}
+
+// orderRepricing describes where an order change came from for repriceOrder.
+type orderRepricing struct {
+	RepoFullName        string
+	IssueNumber         int
+	EditedBy            string
+	Source              string
+	EligibilityAttested bool
+	TermsAccepted       bool
+}
+
+// repriceOrder applies new items to an unpaid order whose checkout can be replaced, records the
+// change in its edit log, and replaces its checkout and checkout comment. Changes that can't be
+// applied are explained in a comment and leave the order as it was.
+func (s *OrderService) repriceOrder(ctx context.Context, githubClient *githubapp.Client, shop *db.Shop, order *db.Order, config *catalog.GitShopConfig, orderData *OrderData, req orderRepricing) error {
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	recordFailure := func(reason string) {
+		meter.Count("order.edit.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	rejectEdit := func(reason, message string) error {
+		recordFailure(reason)
+		if err := githubClient.CreateComment(ctx, req.RepoFullName, req.IssueNumber, message); err != nil {
+			logger.Warn("failed to create order-edit comment", "error", err, "repo", req.RepoFullName, "issue", req.IssueNumber, "reason", reason)
+		}
+		return nil
+	}
+
+	loc := configLocalizer(config)
+
+	product := findProduct(config, orderData.SKU)
+	if product == nil {
+		return rejectEdit("sku_missing", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.sku_missing", orderData.SKU)))
+	}
+	if product.IsInquiry() || product.VerifyIdentity {
+		return rejectEdit("product_not_editable", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.product_not_editable", product.SKU)))
+	}
+	if product.Restricted && !req.EligibilityAttested {
+		return rejectEdit("eligibility_not_attested", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.eligibility")))
+	}
+	if config.Shop.Terms.RequireCheckbox && !req.TermsAccepted && order.TermsAcceptedAt.IsZero() {
+		return rejectEdit("terms_not_accepted", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.terms", config.Shop.Terms.URL)))
+	}
+
+	catalog.ApplyOptionConditions(*product, orderData.Options)
+	var answerErr *catalog.OptionAnswerError
+	if errors.As(catalog.CheckOptionAnswers(*product, orderData.Options), &answerErr) {
+		return rejectEdit("option_invalid", orderEditRejectedComment(loc, order.OrderNumber, optionAnswerMessage(loc, answerErr)))
+	}
+
+	subtotalCents, err := s.pricer.ComputeSubtotal(config, orderData.SKU, orderData.Options)
+	if errors.Is(err, catalog.ErrQuantityExceeded) {
+		return rejectEdit("quantity_exceeded", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.quantity_exceeded", config.Shop.QuantityLimit(*product), product.SKU)))
+	}
+	if err != nil {
+		return rejectEdit("pricing_failed", orderEditRejectedComment(loc, order.OrderNumber, pricingFailedMessage(loc, err, orderData.SKU)))
+	}
+	shippingCents := s.pricer.GetShippingCents(config, orderData.SKU, subtotalCents)
+	totalCents, err := money.Add(subtotalCents, shippingCents)
+	if err == nil {
+		err = money.CheckChargeable(totalCents)
+	}
+	if err != nil {
+		return rejectEdit("pricing_failed", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.total_too_large")))
+	}
+
+	if err := s.closeOrderCheckout(ctx, shop, order); err != nil {
+		logger.Warn("failed to close checkout before re-pricing", "error", err, "order_id", order.ID)
+		return rejectEdit("checkout_close_failed", orderEditRejectedComment(loc, order.OrderNumber, loc.T("edit.checkout_close_failed")))
+	}
+
+	edit := &db.OrderEdit{
+		PreviousSKU:        order.SKU,
+		PreviousOptions:    order.Options,
+		PreviousTotalCents: order.TotalCents,
+		EditedBy:           req.EditedBy,
+	}
+	order.SKU = orderData.SKU
+	order.Category = strings.TrimSpace(product.Category)
+	order.Options = orderData.Options
+	order.SubtotalCents = subtotalCents
+	order.ShippingCents = shippingCents
+	order.TotalCents = totalCents
+	order.Campaign = product.CampaignName()
+	if err := s.orderStore.RepriceOrder(ctx, order, edit); err != nil {
+		recordFailure("order_update_failed")
+		return fmt.Errorf("failed to re-price order: %w", err)
+	}
+	logger.Info("re-priced order", "order_id", order.ID, "previous_sku", edit.PreviousSKU, "sku", order.SKU, "previous_total_cents", edit.PreviousTotalCents, "total_cents", order.TotalCents)
+
+	checkout, err := createOrderCheckout(ctx, s.payments, shop, order, checkoutParamsForOrder(shop, order, config, product, req.RepoFullName), req.Source)
+	if err != nil {
+		recordFailure("checkout_create_failed")
+		if markErr := s.orderStore.MarkFailed(ctx, order.ID, "stripe_checkout_failed"); markErr != nil {
+			logger.Warn("failed to mark order failed after checkout error", "error", markErr, "order_id", order.ID)
+		}
+		failComment := checkoutFailedComment(loc, err, s.appendManagerMention(ctx, githubClient, req.RepoFullName, loc.T("comment.edit_checkout_failed")))
+		if commentErr := githubClient.CreateComment(ctx, req.RepoFullName, req.IssueNumber, failComment); commentErr != nil {
+			logger.Warn("failed to create checkout-failed comment", "error", commentErr, "repo", req.RepoFullName, "issue", req.IssueNumber)
+		}
+		return fmt.Errorf("failed to create checkout session: %w", err)
+	}
+	if err := saveOrderCheckout(ctx, s.orderStore, order, checkout); err != nil {
+		recordFailure("order_update_stripe_session_failed")
+		return fmt.Errorf("failed to update order with checkout: %w", err)
+	}
+
+	comment := checkout.comment(loc, order, config.Shop.Checkout.ExpiresIn(), func(loc i18n.Localizer, orderNumber int, checkoutURL string, expiresIn time.Duration) string {
+		return orderEditedCheckoutComment(loc, orderNumber, order.TotalCents, checkoutURL, expiresIn)
+	})
+	if err := s.replaceCheckoutComment(ctx, githubClient, req.RepoFullName, req.IssueNumber, comment); err != nil {
+		recordFailure("checkout_comment_failed")
+		return fmt.Errorf("failed to update checkout comment: %w", err)
+	}
+
+	meter.Count("order.edit.repriced", 1)
+	meter.Count("checkout.session.created", 1, sentry.WithAttributes(
+		attribute.String("source", req.Source),
+	))
+	return nil
+}
+
+// orderEditChanged reports whether the edited issue asks for different items than the order has.
+// Options are compared as JSON because stored quantities come back as floats.
+func orderEditChanged(order *db.Order, data *OrderData) bool {
+	if order.SKU != data.SKU {
+		return true
+	}
+	if len(order.Options) == 0 && len(data.Options) == 0 {
+		return false
+	}
+	before, err := json.Marshal(order.Options)
+	if err != nil {
+		return true
+	}
+	after, err := json.Marshal(data.Options)
+	if err != nil {
+		return true
+	}
+	return string(before) != string(after)
+}
+
+// canReplaceCheckout reports whether the order's open checkout is one GitShop can close.
+func canReplaceCheckout(order *db.Order) bool {
+	switch order.CheckoutMethod {
+	case db.CheckoutMethodSession:
+		return order.StripeCheckoutSessionID != ""
+	case db.CheckoutMethodPaymentLink:
+		return order.StripePaymentLinkID != ""
+	default:
+		return false
+	}
+}
+
+// replaceCheckoutComment rewrites the newest checkout link comment on the issue, or posts body
+// when there is none.
+func (s *OrderService) replaceCheckoutComment(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, body string) error {
+	comments, err := client.ListComments(ctx, repoFullName, issueNumber)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to list comments for checkout update", "error", err, "repo", repoFullName, "issue", issueNumber)
+	}
+	for i := len(comments) - 1; i >= 0; i-- {
+		comment := comments[i]
+		if comment == nil || comment.ID == nil || !strings.Contains(comment.GetBody(), "gitshop:checkout-link") {
+			continue
+		}
+		return client.UpdateComment(ctx, repoFullName, comment.GetID(), body)
+	}
+	return client.CreateComment(ctx, repoFullName, issueNumber, body)
+}
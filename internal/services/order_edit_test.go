@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+func TestOrderEditChanged(t *testing.T) {
+	t.Parallel()
+
+	// Orders read back from the database hold JSON numbers as float64.
+	stored := &db.Order{SKU: "TSHIRT", Options: map[string]any{"quantity": float64(2), "size": "M"}}
+
+	tests := []struct {
+		name string
+		data *OrderData
+		want bool
+	}{
+		{name: "unchanged", data: &OrderData{SKU: "TSHIRT", Options: map[string]any{"quantity": 2, "size": "M"}}, want: false},
+		{name: "quantity changed", data: &OrderData{SKU: "TSHIRT", Options: map[string]any{"quantity": 3, "size": "M"}}, want: true},
+		{name: "option changed", data: &OrderData{SKU: "TSHIRT", Options: map[string]any{"quantity": 2, "size": "L"}}, want: true},
+		{name: "option removed", data: &OrderData{SKU: "TSHIRT", Options: map[string]any{"quantity": 2}}, want: true},
+		{name: "sku changed", data: &OrderData{SKU: "MUG", Options: map[string]any{"quantity": 2, "size": "M"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := orderEditChanged(stored, tt.data); got != tt.want {
+				t.Fatalf("orderEditChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if orderEditChanged(&db.Order{SKU: "MUG"}, &OrderData{SKU: "MUG", Options: map[string]any{}}) {
+		t.Fatal("orderEditChanged() treats missing and empty options as a change")
+	}
+}
+
+func TestCanReplaceCheckout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		order *db.Order
+		want  bool
+	}{
+		{name: "open session", order: &db.Order{CheckoutMethod: db.CheckoutMethodSession, StripeCheckoutSessionID: "cs_123"}, want: true},
+		{name: "session not created yet", order: &db.Order{CheckoutMethod: db.CheckoutMethodSession}, want: false},
+		{name: "payment link", order: &db.Order{CheckoutMethod: db.CheckoutMethodPaymentLink, StripePaymentLinkID: "plink_123"}, want: true},
+		{name: "lemon squeezy", order: &db.Order{CheckoutMethod: db.CheckoutMethodLemonSqueezy}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := canReplaceCheckout(tt.order); got != tt.want {
+				t.Fatalf("canReplaceCheckout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderEditedCheckoutComment(t *testing.T) {
+	t.Parallel()
+
+	checkout := PaymentCheckout{Method: db.CheckoutMethodSession, ID: "cs_456", URL: "https://checkout.stripe.com/c/pay/cs_456"}
+	got := checkout.comment(i18n.Localizer{}, &db.Order{OrderNumber: 7}, 30*time.Minute, func(loc i18n.Localizer, orderNumber int, checkoutURL string, expiresIn time.Duration) string {
+		return orderEditedCheckoutComment(loc, orderNumber, 4500, checkoutURL, expiresIn)
+	})
+	for _, want := range []string{"$45.00", checkout.URL, "expires in 30 minutes", "<!-- gitshop:checkout-link -->"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("comment %q missing %q", got, want)
+		}
+	}
+}
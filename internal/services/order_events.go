@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+// ListOrderEvents returns the event log of one of the shop's orders, oldest first.
+func (s *AdminService) ListOrderEvents(ctx context.Context, input OrderActionInput) ([]db.OrderEvent, error) {
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.orderStore.ListEvents(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order events: %w", err)
+	}
+	return events, nil
+}
+
+// recordOrderEvent adds an entry to the order's event log for a step that does not change the
+// order, such as a comment on its issue. The store writes state changes' entries itself.
+func recordOrderEvent(ctx context.Context, orderStore *db.OrderStore, orderID uuid.UUID, kind db.OrderEventKind, detail string) error {
+	if orderStore == nil {
+		return nil
+	}
+	if err := orderStore.RecordEvent(ctx, orderID, kind, detail); err != nil {
+		return fmt.Errorf("failed to record %s order event: %w", kind, err)
+	}
+	return nil
+}
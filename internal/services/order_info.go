@@ -9,6 +9,7 @@ import (
 
 	"github.com/gitshopapp/gitshop/internal/db"
 	"github.com/gitshopapp/gitshop/internal/email"
+	"github.com/gitshopapp/gitshop/internal/money"
 )
 
 // OrderInfoOverrides provides optional overrides when building order email data.
@@ -52,17 +53,15 @@ func BuildOrderInfo(shop *db.Shop, order *db.Order, overrides OrderInfoOverrides
 		quantity = orderQuantity(order.Options)
 	}
 
-	unitPriceCents := 0
+	var unitPriceCents int64
 	if order != nil {
 		unitPriceCents = order.SubtotalCents
 		if quantity > 0 {
-			unitPriceCents = order.SubtotalCents / quantity
+			unitPriceCents, _ = money.Divide(order.SubtotalCents, int64(quantity))
 		}
 	}
 
-	subtotal := 0
-	shipping := 0
-	total := 0
+	var subtotal, shipping, total int64
 	sku := ""
 	if order != nil {
 		subtotal = order.SubtotalCents
@@ -80,14 +79,25 @@ func BuildOrderInfo(shop *db.Shop, order *db.Order, overrides OrderInfoOverrides
 		}
 	}
 
+	orderID := ""
 	orderNumber := 0
 	options := map[string]any(nil)
+	var customFields []email.OrderField
+	receiptURL := ""
+	taxID := ""
 	if order != nil {
+		orderID = order.ID.String()
 		orderNumber = order.OrderNumber
 		options = order.Options
+		receiptURL = order.ReceiptURL
+		taxID = order.TaxID
+		for _, field := range order.CustomFields {
+			customFields = append(customFields, email.OrderField{Label: field.Label, Value: field.Value})
+		}
 	}
 
 	return &email.OrderInfo{
+		OrderID:             orderID,
 		OrderNumber:         fmt.Sprintf("#%d", orderNumber),
 		IssueURL:            issueURL(order),
 		CustomerName:        customerName,
@@ -96,36 +106,34 @@ func BuildOrderInfo(shop *db.Shop, order *db.Order, overrides OrderInfoOverrides
 		ShopURL:             shopURL,
 		ProductName:         sku,
 		Quantity:            quantity,
-		UnitPrice:           formatPrice(unitPriceCents),
-		TotalPrice:          formatPrice(total),
+		UnitPrice:           money.Format(unitPriceCents),
+		TotalPrice:          money.Format(total),
 		ShippingAddress:     shippingAddress,
 		ShippingAddressHTML: strings.ReplaceAll(shippingAddress, "\n", "<br>"),
 		TrackingNumber:      overrides.TrackingNumber,
 		TrackingURL:         overrides.TrackingURL,
 		TrackingCarrier:     overrides.TrackingCarrier,
 		OrderDate:           orderDate.Format("January 2, 2006"),
-		Subtotal:            formatPrice(subtotal),
-		Shipping:            formatPrice(shipping),
+		Subtotal:            money.Format(subtotal),
+		Shipping:            money.Format(shipping),
 		Tax:                 "$0.00",
-		Total:               formatPrice(total),
+		Total:               money.Format(total),
+		CustomFields:        customFields,
+		ReceiptURL:          receiptURL,
+		TaxID:               taxID,
 		Items: []email.OrderItem{
 			{
 				Name:       sku,
 				SKU:        sku,
 				Quantity:   quantity,
-				UnitPrice:  formatPrice(unitPriceCents),
-				TotalPrice: formatPrice(subtotal),
+				UnitPrice:  money.Format(unitPriceCents),
+				TotalPrice: money.Format(subtotal),
 				Options:    formatMap(options),
 			},
 		},
 	}
 }
 
-func formatPrice(cents int) string {
-	dollars := float64(cents) / 100.0
-	return fmt.Sprintf("$%.2f", dollars)
-}
-
 func formatMap(m map[string]any) string {
 	if m == nil {
 		return ""
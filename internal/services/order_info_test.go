@@ -0,0 +1,46 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+func TestBuildOrderInfo_CheckoutDetailsInConfirmation(t *testing.T) {
+	t.Parallel()
+
+	order := &db.Order{
+		OrderNumber: 1001,
+		SKU:         "COFFEE_V1",
+		CustomFields: []db.OrderCustomField{
+			{Key: "company", Label: "Company name", Value: "Acme <GmbH>"},
+			{Key: "ponumber", Label: "PO number", Value: "4500012345"},
+		},
+		ReceiptURL: "https://invoice.stripe.com/i/acct_123/test_456",
+		TaxIDType:  "eu_vat",
+		TaxID:      "DE123456789",
+	}
+	info := BuildOrderInfo(&db.Shop{GitHubRepoFullName: "octo/shop"}, order, OrderInfoOverrides{})
+
+	renderer, err := email.NewRenderer()
+	if err != nil {
+		t.Fatalf("failed to create renderer: %v", err)
+	}
+	rendered, err := renderer.Render(t.Context(), "order_confirmation", info)
+	if err != nil {
+		t.Fatalf("failed to render confirmation: %v", err)
+	}
+
+	for _, want := range []string{"Tax ID: DE123456789", "PO number: 4500012345", "Company name: Acme <GmbH>", "Receipt: " + order.ReceiptURL} {
+		if !strings.Contains(rendered.Text, want) {
+			t.Errorf("text email missing %q:\n%s", want, rendered.Text)
+		}
+	}
+	for _, want := range []string{"Acme &lt;GmbH&gt;", `<a href="` + order.ReceiptURL + `">Download your receipt</a>`} {
+		if !strings.Contains(rendered.HTML, want) {
+			t.Errorf("HTML email missing %q", want)
+		}
+	}
+}
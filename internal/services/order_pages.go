@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+const (
+	orderPageDefaultLimit = 20
+	orderPageMaxLimit     = 100
+)
+
+// OrderListPage is one page of a shop's orders, newest first. NextCursor fetches the older
+// orders after it and PrevCursor the newer orders before it; each is empty when there are none.
+type OrderListPage struct {
+	Orders     []*db.Order
+	NextCursor string
+	PrevCursor string
+}
+
+// ListOrders returns a page of the shop's orders, starting from the newest when cursor is empty.
+// The limit defaults to 20 and is capped at 100.
+func (s *AdminService) ListOrders(ctx context.Context, shopID uuid.UUID, cursor string, limit int) (*OrderListPage, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, fmt.Errorf("%w: order store unavailable", ErrAdminServiceUnavailable)
+	}
+	if limit <= 0 {
+		limit = orderPageDefaultLimit
+	}
+	limit = min(limit, orderPageMaxLimit)
+
+	var after, before *db.OrderCursor
+	if cursor != "" {
+		position, older, err := decodeOrderCursor(cursor)
+		if err != nil {
+			return nil, UserError{Message: "Invalid cursor"}
+		}
+		if older {
+			after = position
+		} else {
+			before = position
+		}
+	}
+
+	page, err := s.orderStore.ListOrdersPage(ctx, shopID, after, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	listed := &OrderListPage{Orders: page.Orders}
+	if page.Next != nil {
+		listed.NextCursor = encodeOrderCursor(page.Next, true)
+	}
+	if page.Prev != nil {
+		listed.PrevCursor = encodeOrderCursor(page.Prev, false)
+	}
+	return listed, nil
+}
+
+// encodeOrderCursor makes an opaque cursor for the orders older than position, or newer when
+// older is false.
+func encodeOrderCursor(position *db.OrderCursor, older bool) string {
+	direction := "n"
+	if older {
+		direction = "o"
+	}
+	raw := direction + "." + strconv.FormatInt(position.CreatedAt.UnixMicro(), 10) + "." + position.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeOrderCursor(cursor string) (*db.OrderCursor, bool, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, false, err
+	}
+	parts := strings.Split(string(raw), ".")
+	if len(parts) != 3 || (parts[0] != "o" && parts[0] != "n") {
+		return nil, false, fmt.Errorf("malformed order cursor")
+	}
+	micros, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, false, err
+	}
+	orderID, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, false, err
+	}
+	return &db.OrderCursor{CreatedAt: time.UnixMicro(micros).UTC(), ID: orderID}, parts[0] == "o", nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestOrderCursorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	position := &db.OrderCursor{
+		CreatedAt: time.Date(2026, 3, 4, 5, 6, 7, 891011000, time.UTC),
+		ID:        uuid.MustParse("00000000-0000-4000-8000-000000000001"),
+	}
+	for _, older := range []bool{true, false} {
+		cursor := encodeOrderCursor(position, older)
+		got, gotOlder, err := decodeOrderCursor(cursor)
+		if err != nil {
+			t.Fatalf("decodeOrderCursor(%q) error = %v", cursor, err)
+		}
+		if !got.CreatedAt.Equal(position.CreatedAt) || got.ID != position.ID || gotOlder != older {
+			t.Fatalf("decodeOrderCursor(%q) = %+v, %v, want %+v, %v", cursor, got, gotOlder, position, older)
+		}
+	}
+
+	for _, cursor := range []string{"not base64!", "eC4xLjI", encodeOrderCursor(position, true)[:10]} {
+		if _, _, err := decodeOrderCursor(cursor); err == nil {
+			t.Fatalf("decodeOrderCursor(%q) succeeded, want an error", cursor)
+		}
+	}
+}
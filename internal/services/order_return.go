@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+var ErrOrderNotReturnable = errors.New("order not returnable")
+
+// orderReturn records returns for both the dashboard and the `.gitshop return` command.
+type orderReturn struct {
+	orderStore *db.OrderStore
+	payments   *PaymentProviders
+	emailer    OrderEmailSender
+	webhooks   OrderWebhookPublisher
+	logger     *slog.Logger
+}
+
+// process moves a shipped or delivered order to returned, refunding the payment first when asked.
+// Inventory is not tracked yet, so nothing is restocked.
+func (r orderReturn) process(ctx context.Context, client *githubapp.Client, shop *db.Shop, order *db.Order, refund bool) error {
+	logger := logging.FromContext(ctx, r.logger)
+	meter := observability.MeterFromContext(ctx)
+	meter.Count("fulfillment.return.received", 1)
+	recordFailed := func(reason string) {
+		meter.Count("fulfillment.return.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	if !isReturnable(order) {
+		recordFailed("invalid_order_status")
+		return fmt.Errorf("%w: only shipped or delivered orders can be returned", ErrOrderNotReturnable)
+	}
+
+	if refund {
+		provider, err := r.payments.ForShop(ctx, shop)
+		if err != nil {
+			recordFailed("payments_unavailable")
+			return fmt.Errorf("%w: %w", ErrAdminRefundUnavailable, err)
+		}
+		if err := provider.Refund(ctx, shop, order); err != nil {
+			recordFailed("refund_failed")
+			return fmt.Errorf("failed to refund payment: %w", err)
+		}
+	}
+
+	if err := r.orderStore.MarkReturned(ctx, order.ID, refund); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("mark_returned_failed")
+		return fmt.Errorf("failed to mark order as returned: %w", err)
+	}
+	refreshCustomer(ctx, r.orderStore, order.ID)
+
+	recordSideEffect := func(reason string) {
+		meter.Count("fulfillment.return.side_effect_failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	config := shopConfig(ctx, client, shop.GitHubRepoFullName)
+	labels := shopLabels(config)
+	if err := client.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, orderReturnedComment(configLocalizer(config), refund)); err != nil {
+		recordSideEffect("github_comment_failed")
+		logger.Error("failed to create return comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	for _, label := range []string{labels.Name(catalog.LabelShipped), labels.Name(catalog.LabelDelivered)} {
+		if err := client.RemoveLabel(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, label); err != nil {
+			logger.Debug("failed to remove label for return", "error", err, "label", label, "issue", order.GitHubIssueNumber)
+		}
+	}
+	if err := client.AddLabels(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, []string{labels.Name(catalog.LabelReturned)}); err != nil {
+		logger.Warn("failed to add returned label", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+
+	if order.CustomerEmail != "" {
+		if err := r.emailer.SendOrderReturned(ctx, shop, order, refund); err != nil {
+			recordSideEffect("returned_email_failed")
+			logger.Error("failed to send return email", "error", err, "order_id", order.ID)
+		} else if err := r.orderStore.RecordEmail(ctx, order.ID, db.OrderEmailReturned, order.CustomerEmail); err != nil {
+			logger.Warn("failed to record return email", "error", err, "order_id", order.ID)
+		}
+	}
+
+	if updated, err := r.orderStore.GetByID(ctx, order.ID); err != nil {
+		logger.Warn("failed to reload order for webhook", "error", err, "order_id", order.ID, "event", db.WebhookOrderReturned)
+	} else if err := r.webhooks.PublishOrderEvent(ctx, shop, updated, db.WebhookOrderReturned); err != nil {
+		logger.Warn("failed to queue order webhook", "error", err, "order_id", order.ID, "event", db.WebhookOrderReturned)
+	}
+	meter.Count("fulfillment.return.processed", 1, sentry.WithAttributes(
+		attribute.Bool("refunded", refund),
+	))
+	return nil
+}
+
+func isReturnable(order *db.Order) bool {
+	return order != nil && (order.Status == db.StatusShipped || order.Status == db.StatusDelivered)
+}
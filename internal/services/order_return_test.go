@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestIsReturnable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		order *db.Order
+		want  bool
+	}{
+		{name: "nil order", order: nil, want: false},
+		{name: "paid", order: &db.Order{Status: db.StatusPaid}, want: false},
+		{name: "shipped", order: &db.Order{Status: db.StatusShipped}, want: true},
+		{name: "delivered", order: &db.Order{Status: db.StatusDelivered}, want: true},
+		{name: "already returned", order: &db.Order{Status: db.StatusReturned}, want: false},
+		{name: "refunded", order: &db.Order{Status: db.StatusRefunded}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isReturnable(tt.order); got != tt.want {
+				t.Fatalf("isReturnable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderCommandName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{body: ".gitshop retry", want: "retry", wantOK: true},
+		{body: ".gitshop return", want: "return", wantOK: true},
+		{body: ".gitshop return --refund", want: "return", wantOK: true},
+		{body: ".gitshop approve", want: "approve", wantOK: true},
+		{body: ".gitshop reject", want: "reject", wantOK: true},
+		{body: ".gitshop update quantity=2", want: "update", wantOK: true},
+		{body: ".gitshop review 5 Great coffee!", want: "review", wantOK: true},
+		{body: ".gitshop orders @octocat", want: "orders", wantOK: true},
+		{body: ".gitshop return please", wantOK: false},
+		{body: "thanks!", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			t.Parallel()
+			got, ok := orderCommandName(tt.body)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("orderCommandName(%q) = %q, %v, want %q, %v", tt.body, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
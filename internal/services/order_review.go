@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/go-github/v66/github"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const reviewCommand = ".gitshop review"
+
+// Reactions on a review invitation are read every few hours for two weeks after it is posted;
+// a rating left later can still be given with `.gitshop review`.
+const (
+	reviewInterval       = time.Hour
+	reviewCheckEvery     = 6 * time.Hour
+	reviewReactionWindow = 14 * 24 * time.Hour
+	reviewBatchSize      = 50
+	maxReviewBodyLength  = 1000
+)
+
+var errReviewArgs = errors.New("invalid review arguments")
+
+// reviewCommandArgs returns what follows `.gitshop review` in a comment.
+func reviewCommandArgs(commentBody string) (string, bool) {
+	if commentBody == reviewCommand {
+		return "", true
+	}
+	rest, ok := strings.CutPrefix(commentBody, reviewCommand)
+	if !ok || rest == "" || !unicode.IsSpace(rune(rest[0])) {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// parseReviewArgs reads a 1 to 5 star rating followed by an optional note.
+func parseReviewArgs(args string) (int, string, error) {
+	value, body, _ := strings.Cut(args, " ")
+	value = strings.TrimSuffix(value, "/5")
+	rating, err := strconv.Atoi(value)
+	if err != nil || rating < 1 || rating > 5 {
+		return 0, "", fmt.Errorf("%w: rating must be 1 to 5", errReviewArgs)
+	}
+	body = strings.TrimSpace(body)
+	if runes := []rune(body); len(runes) > maxReviewBodyLength {
+		body = string(runes[:maxReviewBodyLength])
+	}
+	return rating, body, nil
+}
+
+// reactionRating maps a reaction on the review invitation to a star rating, or 0 for reactions
+// that do not say how the order went.
+func reactionRating(content string) int {
+	switch content {
+	case "+1", "heart", "hooray", "rocket":
+		return 5
+	case "laugh":
+		return 4
+	case "confused":
+		return 2
+	case "-1":
+		return 1
+	}
+	return 0
+}
+
+// buyerReactionRating returns the rating from the buyer's latest rating reaction, or 0. Reactions
+// from anyone else are ignored.
+func buyerReactionRating(reactions []*github.Reaction, buyer string) int {
+	rating := 0
+	for _, reaction := range reactions {
+		if !strings.EqualFold(reaction.GetUser().GetLogin(), buyer) {
+			continue
+		}
+		if r := reactionRating(reaction.GetContent()); r > 0 {
+			rating = r
+		}
+	}
+	return rating
+}
+
+// requestReview invites the buyer to rate a delivered order unless gitshop.yaml turns reviews
+// off. Without a readable config the invitation is skipped, since the seller's choice is unknown.
+func (s *AdminService) requestReview(ctx context.Context, client *githubapp.Client, shop *db.Shop, order *db.Order, now time.Time) {
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+
+	config, ok := readShopConfig(ctx, client, shop.GitHubRepoFullName)
+	if !ok || config.Shop.Reviews.Disabled {
+		return
+	}
+	days := config.Shop.Reviews.CloseAfterDays
+	commentID, err := client.CreateCommentWithID(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, reviewRequestComment(configLocalizer(config), order.OrderNumber, days))
+	if err != nil {
+		meter.Count("fulfillment.delivery.side_effect_failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "review_request_failed"),
+		))
+		logger.Error("failed to create review request comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+		return
+	}
+
+	var closeAt time.Time
+	if days > 0 {
+		closeAt = now.AddDate(0, 0, days)
+	}
+	review := &db.OrderReview{OrderID: order.ID, ShopID: shop.ID, SKU: order.SKU, CommentID: commentID}
+	if err := s.orderStore.CreateReviewRequest(ctx, review, closeAt); err != nil {
+		logger.Warn("failed to record review request", "error", err, "order_id", order.ID)
+		return
+	}
+	meter.Count("order.review.requested", 1)
+}
+
+// handleReviewCommand stores the buyer's rating from `.gitshop review 5 Great coffee!`. Only the
+// buyer can review, and only once the order is delivered; a later review replaces an earlier one.
+func (s *OrderService) handleReviewCommand(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, order *db.Order, args, commenterLogin string, shop *db.Shop) error {
+	meter := observability.MeterFromContext(ctx)
+	recordRejected := func(reason string) {
+		meter.Count("order.review.rejected", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	config, ok := readShopConfig(ctx, client, repoFullName)
+	loc := configLocalizer(config)
+	if order == nil || shop == nil {
+		recordRejected("order_not_found")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.order_not_found"))
+	}
+	if !strings.EqualFold(commenterLogin, order.GitHubUsername) {
+		recordRejected("permission_denied")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.review_permission_denied"))
+	}
+	if ok && config.Shop.Reviews.Disabled {
+		recordRejected("reviews_disabled")
+		return nil
+	}
+	if order.Status != db.StatusDelivered {
+		recordRejected("invalid_order_status")
+		return client.CreateComment(ctx, repoFullName, issueNumber, reviewNotDeliveredComment(loc, order.OrderNumber))
+	}
+	rating, body, err := parseReviewArgs(args)
+	if err != nil {
+		recordRejected("invalid_arguments")
+		return client.CreateComment(ctx, repoFullName, issueNumber, reviewUsageComment(loc, order.OrderNumber))
+	}
+
+	if err := s.orderStore.SaveRating(ctx, &db.OrderReview{
+		OrderID: order.ID,
+		ShopID:  shop.ID,
+		SKU:     order.SKU,
+		Rating:  rating,
+		Body:    body,
+		Source:  db.ReviewSourceComment,
+	}); err != nil {
+		recordRejected("persist_failed")
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+	meter.Count("order.review.rated", 1, sentry.WithAttributes(
+		attribute.String("source", string(db.ReviewSourceComment)),
+		attribute.Int("rating", rating),
+	))
+	return client.CreateComment(ctx, repoFullName, issueNumber, reviewThanksComment(loc, order.OrderNumber, rating))
+}
+
+// ProductRatings returns how many ratings each of the shop's products has and their average.
+func (s *AdminService) ProductRatings(ctx context.Context, shopID uuid.UUID) ([]db.ProductRating, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	ratings, err := s.orderStore.ListProductRatings(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+type reviewOrderStore interface {
+	ClaimReviewChecks(ctx context.Context, region string, checkedBefore, requestedAfter time.Time, limit int) ([]db.OrderReview, error)
+	ClaimReviewClosures(ctx context.Context, region string, dueBefore time.Time, limit int) ([]db.OrderReview, error)
+	SaveRating(ctx context.Context, review *db.OrderReview) error
+	GetByID(ctx context.Context, orderID uuid.UUID) (*db.Order, error)
+}
+
+type reviewShopStore interface {
+	GetByID(ctx context.Context, shopID uuid.UUID) (*db.Shop, error)
+}
+
+type reviewIssueClient interface {
+	GetFile(ctx context.Context, repoFullName, path, ref string) ([]byte, error)
+	ListCommentReactions(ctx context.Context, repoFullName string, commentID int64) ([]*github.Reaction, error)
+	CreateComment(ctx context.Context, repoFullName string, issueNumber int, body string) error
+	CloseIssue(ctx context.Context, repoFullName string, issueNumber int) error
+}
+
+// ReviewService follows up on review invitations: it turns the buyer's reaction on an invitation
+// into a rating, and closes order issues once the shop's reviews.close_after_days have passed.
+type ReviewService struct {
+	orderStore reviewOrderStore
+	shopStore  reviewShopStore
+	github     func(installationID int64) reviewIssueClient
+	region     string
+	logger     *slog.Logger
+}
+
+func NewReviewService(orderStore *db.OrderStore, shopStore *db.ShopStore, githubClient *githubapp.Client, region string, logger *slog.Logger) *ReviewService {
+	var github func(int64) reviewIssueClient
+	if githubClient != nil {
+		github = func(installationID int64) reviewIssueClient {
+			return githubClient.WithInstallation(installationID)
+		}
+	}
+	return newReviewService(orderStore, shopStore, github, region, logger)
+}
+
+func newReviewService(orderStore reviewOrderStore, shopStore reviewShopStore, github func(int64) reviewIssueClient, region string, logger *slog.Logger) *ReviewService {
+	return &ReviewService{
+		orderStore: orderStore,
+		shopStore:  shopStore,
+		github:     github,
+		region:     region,
+		logger:     logger,
+	}
+}
+
+// Run checks reactions and due issue closures every hour until ctx is cancelled.
+func (s *ReviewService) Run(ctx context.Context) {
+	if s == nil || s.orderStore == nil || s.github == nil {
+		return
+	}
+
+	ticker := time.NewTicker(reviewInterval)
+	defer ticker.Stop()
+	for {
+		rated, closed, err := s.ProcessDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to process reviews", "error", err)
+		} else if rated > 0 || closed > 0 {
+			s.logger.Info("processed reviews", "rated", rated, "closed", closed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue records ratings left as reactions and closes the order issues that are due. It
+// returns how many orders were rated and how many issues were closed.
+func (s *ReviewService) ProcessDue(ctx context.Context, now time.Time) (int, int, error) {
+	rated := 0
+	for {
+		claims, err := s.orderStore.ClaimReviewChecks(ctx, s.region, now.Add(-reviewCheckEvery), now.Add(-reviewReactionWindow), reviewBatchSize)
+		if err != nil {
+			return rated, 0, fmt.Errorf("failed to claim review checks: %w", err)
+		}
+		for _, claim := range claims {
+			if s.checkReactions(ctx, claim) {
+				rated++
+			}
+		}
+		if len(claims) < reviewBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	closed := 0
+	for {
+		claims, err := s.orderStore.ClaimReviewClosures(ctx, s.region, now, reviewBatchSize)
+		if err != nil {
+			return rated, closed, fmt.Errorf("failed to claim review closures: %w", err)
+		}
+		for _, claim := range claims {
+			if s.closeIssue(ctx, claim) {
+				closed++
+			}
+		}
+		if len(claims) < reviewBatchSize || ctx.Err() != nil {
+			return rated, closed, nil
+		}
+	}
+}
+
+func (s *ReviewService) recordFailed(ctx context.Context, reason string) {
+	observability.MeterFromContext(ctx).Count("order.review.failed", 1, sentry.WithAttributes(
+		attribute.String("reason", reason),
+	))
+}
+
+// loadReview returns the order and shop behind a claimed review.
+func (s *ReviewService) loadReview(ctx context.Context, claim db.OrderReview) (*db.Order, *db.Shop, bool) {
+	logger := s.logger.With("order_id", claim.OrderID, "shop_id", claim.ShopID)
+	order, err := s.orderStore.GetByID(ctx, claim.OrderID)
+	if err != nil {
+		s.recordFailed(ctx, "order_lookup_failed")
+		logger.Error("failed to load order for review", "error", err)
+		return nil, nil, false
+	}
+	shop, err := s.shopStore.GetByID(ctx, claim.ShopID)
+	if err != nil {
+		s.recordFailed(ctx, "shop_lookup_failed")
+		logger.Error("failed to load shop for review", "error", err)
+		return nil, nil, false
+	}
+	return order, shop, true
+}
+
+// checkReactions saves the buyer's reaction on the invitation as their rating and reports
+// whether there was one.
+func (s *ReviewService) checkReactions(ctx context.Context, claim db.OrderReview) bool {
+	order, shop, ok := s.loadReview(ctx, claim)
+	if !ok {
+		return false
+	}
+	reactions, err := s.github(shop.GitHubInstallationID).ListCommentReactions(ctx, shop.GitHubRepoFullName, claim.CommentID)
+	if err != nil {
+		s.recordFailed(ctx, "reactions_failed")
+		s.logger.Warn("failed to list review reactions", "error", err, "repo", shop.GitHubRepoFullName, "comment_id", claim.CommentID)
+		return false
+	}
+	rating := buyerReactionRating(reactions, order.GitHubUsername)
+	if rating == 0 {
+		return false
+	}
+
+	claim.Rating = rating
+	claim.Body = ""
+	claim.Source = db.ReviewSourceReaction
+	if err := s.orderStore.SaveRating(ctx, &claim); err != nil {
+		s.recordFailed(ctx, "persist_failed")
+		s.logger.Error("failed to save review rating", "error", err, "order_id", order.ID)
+		return false
+	}
+	observability.MeterFromContext(ctx).Count("order.review.rated", 1, sentry.WithAttributes(
+		attribute.String("source", string(db.ReviewSourceReaction)),
+		attribute.Int("rating", rating),
+	))
+	return true
+}
+
+// closeIssue closes a delivered order's issue with a short thank-you. Orders that moved on, such
+// as returns, keep their issue open.
+func (s *ReviewService) closeIssue(ctx context.Context, claim db.OrderReview) bool {
+	order, shop, ok := s.loadReview(ctx, claim)
+	if !ok || order.Status != db.StatusDelivered || order.GitHubIssueNumber == 0 {
+		return false
+	}
+	client := s.github(shop.GitHubInstallationID)
+	config, _ := readShopConfig(ctx, client, shop.GitHubRepoFullName)
+	if err := client.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, reviewClosedComment(configLocalizer(config), order.OrderNumber)); err != nil {
+		s.logger.Warn("failed to comment before closing order issue", "error", err, "repo", shop.GitHubRepoFullName, "issue", order.GitHubIssueNumber)
+	}
+	if err := client.CloseIssue(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber); err != nil {
+		s.recordFailed(ctx, "close_failed")
+		s.logger.Warn("failed to close order issue", "error", err, "repo", shop.GitHubRepoFullName, "issue", order.GitHubIssueNumber)
+		return false
+	}
+	observability.MeterFromContext(ctx).Count("order.review.closed", 1)
+	return true
+}
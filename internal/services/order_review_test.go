@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestReviewCommandArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{body: ".gitshop review", want: "", wantOK: true},
+		{body: ".gitshop review 5 Great coffee!", want: "5 Great coffee!", wantOK: true},
+		{body: ".gitshop reviewed", wantOK: false},
+		{body: ".gitshop retry", wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := reviewCommandArgs(tt.body)
+		if got != tt.want || ok != tt.wantOK {
+			t.Fatalf("reviewCommandArgs(%q) = %q, %v, want %q, %v", tt.body, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseReviewArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		args       string
+		wantRating int
+		wantBody   string
+		wantErr    bool
+	}{
+		{name: "rating only", args: "4", wantRating: 4},
+		{name: "rating with note", args: "5 Arrived quickly,  thanks", wantRating: 5, wantBody: "Arrived quickly,  thanks"},
+		{name: "out of five", args: "3/5 fine", wantRating: 3, wantBody: "fine"},
+		{name: "missing rating", args: "", wantErr: true},
+		{name: "too high", args: "6", wantErr: true},
+		{name: "not a number", args: "great", wantErr: true},
+		{name: "long note is cut", args: "2 " + strings.Repeat("é", maxReviewBodyLength+10), wantRating: 2, wantBody: strings.Repeat("é", maxReviewBodyLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rating, body, err := parseReviewArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReviewArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if rating != tt.wantRating || body != tt.wantBody {
+				t.Fatalf("parseReviewArgs() = %d, %q, want %d, %q", rating, body, tt.wantRating, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestBuyerReactionRating(t *testing.T) {
+	t.Parallel()
+
+	reaction := func(login, content string) *github.Reaction {
+		return &github.Reaction{User: &github.User{Login: github.String(login)}, Content: github.String(content)}
+	}
+	tests := []struct {
+		name      string
+		reactions []*github.Reaction
+		want      int
+	}{
+		{name: "no reactions", want: 0},
+		{name: "thumbs up", reactions: []*github.Reaction{reaction("Mona", "+1")}, want: 5},
+		{name: "latest rating reaction wins", reactions: []*github.Reaction{reaction("mona", "heart"), reaction("mona", "-1"), reaction("mona", "eyes")}, want: 1},
+		{name: "other users ignored", reactions: []*github.Reaction{reaction("hubot", "confused"), reaction("mona", "laugh")}, want: 4},
+		{name: "only non-rating reactions", reactions: []*github.Reaction{reaction("mona", "eyes")}, want: 0},
+	}
+	for _, tt := range tests {
+		if got := buyerReactionRating(tt.reactions, "mona"); got != tt.want {
+			t.Fatalf("%s: buyerReactionRating() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+type fakeReviewOrderStore struct {
+	checks   []db.OrderReview
+	closures []db.OrderReview
+	orders   map[uuid.UUID]*db.Order
+	saved    []db.OrderReview
+}
+
+func (s *fakeReviewOrderStore) ClaimReviewChecks(context.Context, string, time.Time, time.Time, int) ([]db.OrderReview, error) {
+	claimed := s.checks
+	s.checks = nil
+	return claimed, nil
+}
+
+func (s *fakeReviewOrderStore) ClaimReviewClosures(context.Context, string, time.Time, int) ([]db.OrderReview, error) {
+	claimed := s.closures
+	s.closures = nil
+	return claimed, nil
+}
+
+func (s *fakeReviewOrderStore) SaveRating(_ context.Context, review *db.OrderReview) error {
+	s.saved = append(s.saved, *review)
+	return nil
+}
+
+func (s *fakeReviewOrderStore) GetByID(_ context.Context, orderID uuid.UUID) (*db.Order, error) {
+	order, ok := s.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order %s not found", orderID)
+	}
+	return order, nil
+}
+
+type fakeReviewShopStore struct {
+	shop *db.Shop
+}
+
+func (s fakeReviewShopStore) GetByID(context.Context, uuid.UUID) (*db.Shop, error) {
+	return s.shop, nil
+}
+
+type fakeReviewIssueClient struct {
+	reactions map[int64][]*github.Reaction
+	comments  []string
+	closed    []int
+}
+
+func (c *fakeReviewIssueClient) GetFile(_ context.Context, _, path, _ string) ([]byte, error) {
+	return nil, fmt.Errorf("%s not found", path)
+}
+
+func (c *fakeReviewIssueClient) ListCommentReactions(_ context.Context, _ string, commentID int64) ([]*github.Reaction, error) {
+	return c.reactions[commentID], nil
+}
+
+func (c *fakeReviewIssueClient) CreateComment(_ context.Context, _ string, _ int, body string) error {
+	c.comments = append(c.comments, body)
+	return nil
+}
+
+func (c *fakeReviewIssueClient) CloseIssue(_ context.Context, _ string, number int) error {
+	c.closed = append(c.closed, number)
+	return nil
+}
+
+func TestReviewService_ProcessDue(t *testing.T) {
+	t.Parallel()
+
+	shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}
+	rated := &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 1, GitHubIssueNumber: 11, GitHubUsername: "mona", SKU: "COFFEE_V1", Status: db.StatusDelivered}
+	unrated := &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 2, GitHubIssueNumber: 12, GitHubUsername: "mona", SKU: "COFFEE_V1", Status: db.StatusDelivered}
+	returned := &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: 3, GitHubIssueNumber: 13, GitHubUsername: "mona", SKU: "COFFEE_V1", Status: db.StatusReturned}
+
+	orders := &fakeReviewOrderStore{
+		checks: []db.OrderReview{
+			{OrderID: rated.ID, ShopID: shop.ID, SKU: rated.SKU, CommentID: 101},
+			{OrderID: unrated.ID, ShopID: shop.ID, SKU: unrated.SKU, CommentID: 102},
+		},
+		closures: []db.OrderReview{
+			{OrderID: rated.ID, ShopID: shop.ID, SKU: rated.SKU},
+			{OrderID: returned.ID, ShopID: shop.ID, SKU: returned.SKU},
+		},
+		orders: map[uuid.UUID]*db.Order{rated.ID: rated, unrated.ID: unrated, returned.ID: returned},
+	}
+	client := &fakeReviewIssueClient{reactions: map[int64][]*github.Reaction{
+		101: {{User: &github.User{Login: github.String("mona")}, Content: github.String("hooray")}},
+		102: {{User: &github.User{Login: github.String("hubot")}, Content: github.String("+1")}},
+	}}
+	service := newReviewService(orders, fakeReviewShopStore{shop: shop}, func(int64) reviewIssueClient { return client }, "", slog.Default())
+
+	gotRated, gotClosed, err := service.ProcessDue(t.Context(), time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ProcessDue() error = %v", err)
+	}
+	if gotRated != 1 || gotClosed != 1 {
+		t.Fatalf("ProcessDue() = %d rated, %d closed, want 1, 1", gotRated, gotClosed)
+	}
+	if len(orders.saved) != 1 || orders.saved[0].OrderID != rated.ID || orders.saved[0].Rating != 5 || orders.saved[0].Source != db.ReviewSourceReaction {
+		t.Fatalf("saved ratings = %+v", orders.saved)
+	}
+	if len(client.closed) != 1 || client.closed[0] != rated.GitHubIssueNumber {
+		t.Fatalf("closed issues = %v, want [%d]", client.closed, rated.GitHubIssueNumber)
+	}
+	if len(client.comments) != 1 || !strings.Contains(client.comments[0], "`#1`") {
+		t.Fatalf("comments = %q", client.comments)
+	}
+}
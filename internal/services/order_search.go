@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	ordersCommand = ".gitshop orders"
+	// ordersCommandListed caps the orders listed in one comment, newest first.
+	ordersCommandListed = 20
+)
+
+// OrderSearch looks orders up by the issue they were placed in or by the buyer's GitHub
+// username. Exactly one of the two is set.
+type OrderSearch struct {
+	IssueNumber int
+	Customer    string
+}
+
+// SearchOrders returns the shop's orders matching the search, oldest first. No match is an
+// empty list rather than an error.
+func (s *AdminService) SearchOrders(ctx context.Context, shopID uuid.UUID, search OrderSearch) ([]*db.Order, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	customer := strings.TrimSpace(search.Customer)
+	if (search.IssueNumber > 0) == (customer != "") {
+		return nil, UserError{Message: "Search by either issue or customer"}
+	}
+
+	if search.IssueNumber > 0 {
+		order, err := s.orderStore.GetByShopAndIssue(ctx, shopID, search.IssueNumber)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []*db.Order{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order by issue: %w", err)
+		}
+		return []*db.Order{order}, nil
+	}
+
+	subject, err := ParseCustomerSubject(customer)
+	if err != nil {
+		return nil, err
+	}
+	if subject.Kind != db.CustomerSubjectGitHubUsername {
+		return nil, UserError{Message: "Enter the customer's GitHub username"}
+	}
+	orders, err := s.orderStore.ListCustomerOrders(ctx, shopID, subject.Kind, subject.Value, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer orders: %w", err)
+	}
+	return orders, nil
+}
+
+// ordersCommandArgs returns what follows `.gitshop orders` in a comment.
+func ordersCommandArgs(commentBody string) (string, bool) {
+	if commentBody == ordersCommand {
+		return "", true
+	}
+	rest, ok := strings.CutPrefix(commentBody, ordersCommand)
+	if !ok || rest == "" || !unicode.IsSpace(rune(rest[0])) {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// handleOrdersCommand lists a buyer's past orders in the shop from `.gitshop orders @user`, so a
+// seller can find the purchase a support issue is about. It works on any issue in the repo, and
+// only repo admins can run it; write access is not enough, since the reply is public.
+func (s *OrderService) handleOrdersCommand(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, args, commenterLogin string, shop *db.Shop) error {
+	meter := observability.MeterFromContext(ctx)
+	recordRejected := func(reason string) {
+		meter.Count("order.orders.rejected", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	loc := shopLocalizer(ctx, client, repoFullName)
+	role, err := client.RepositoryRole(ctx, repoFullName, commenterLogin)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to check repository role for orders command", "error", err, "repo", repoFullName, "commenter", commenterLogin)
+	}
+	if role != "admin" {
+		recordRejected("permission_denied")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.orders_permission_denied"))
+	}
+	subject, err := ParseCustomerSubject(args)
+	if err != nil || subject.Kind != db.CustomerSubjectGitHubUsername {
+		recordRejected("invalid_arguments")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.orders_usage"))
+	}
+
+	orders, err := s.orderStore.ListCustomerOrders(ctx, shop.ID, subject.Kind, subject.Value, "")
+	if err != nil {
+		meter.Count("order.orders.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "order_lookup_failed"),
+		))
+		return fmt.Errorf("failed to list customer orders: %w", err)
+	}
+	meter.Count("order.orders.processed", 1)
+	return client.CreateComment(ctx, repoFullName, issueNumber, buyerOrdersComment(loc, subject.Value, orders))
+}
+
+// buyerOrdersComment lists orders newest first. The username is wrapped in code so the buyer is
+// not mentioned, while each issue number links to the order issue.
+func buyerOrdersComment(loc i18n.Localizer, username string, orders []*db.Order) string {
+	if len(orders) == 0 {
+		return loc.T("comment.orders_none", username)
+	}
+
+	newestFirst := slices.Clone(orders)
+	slices.Reverse(newestFirst)
+	var body strings.Builder
+	body.WriteString(loc.T("comment.orders_list", username, len(orders)) + "\n\n")
+	dateLayout := loc.T("comment.orders_date_layout")
+	for _, order := range newestFirst[:min(len(newestFirst), ordersCommandListed)] {
+		number, total, placed := commentOrderNumber(order.OrderNumber), money.Format(order.TotalCents), order.CreatedAt.Format(dateLayout)
+		line := loc.T("comment.orders_line", number, order.SKU, total, order.Status, placed)
+		if order.GitHubIssueNumber > 0 {
+			line = loc.T("comment.orders_line_issue", number, order.SKU, total, order.Status, placed, order.GitHubIssueNumber)
+		}
+		body.WriteString(line + "\n")
+	}
+	if len(orders) > ordersCommandListed {
+		body.WriteString(loc.T("comment.orders_older", len(orders)-ordersCommandListed) + "\n")
+	}
+	return strings.TrimSuffix(body.String(), "\n")
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+func TestOrdersCommandArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{body: ".gitshop orders @octocat", want: "@octocat", wantOK: true},
+		{body: ".gitshop orders", want: "", wantOK: true},
+		{body: ".gitshop ordersfor", wantOK: false},
+		{body: "see .gitshop orders @octocat", wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := ordersCommandArgs(tt.body)
+		if got != tt.want || ok != tt.wantOK {
+			t.Fatalf("ordersCommandArgs(%q) = %q, %v, want %q, %v", tt.body, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestBuyerOrdersComment(t *testing.T) {
+	t.Parallel()
+
+	if got := buyerOrdersComment(i18n.Localizer{}, "octocat", nil); got != "🔍 `@octocat` has no orders in this shop." {
+		t.Fatalf("empty comment = %q", got)
+	}
+
+	created := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	orders := make([]*db.Order, 0, ordersCommandListed+2)
+	for i := range ordersCommandListed + 2 {
+		orders = append(orders, &db.Order{
+			OrderNumber:       1001 + i,
+			GitHubIssueNumber: 10 + i,
+			SKU:               "COFFEE_V1",
+			TotalCents:        2000,
+			Status:            db.StatusDelivered,
+			CreatedAt:         created.AddDate(0, 0, i),
+		})
+	}
+
+	got := buyerOrdersComment(i18n.Localizer{}, "octocat", orders)
+	lines := strings.Split(got, "\n")
+	if lines[0] != "🔍 `@octocat` has placed 22 order(s) in this shop:" {
+		t.Fatalf("header = %q", lines[0])
+	}
+	if want := "- `#1022`, COFFEE_V1, $20.00, delivered, placed Jan 22, 2026 in #31"; lines[2] != want {
+		t.Fatalf("newest order line = %q, want %q", lines[2], want)
+	}
+	if last := lines[len(lines)-1]; last != "- …and 2 older" {
+		t.Fatalf("last line = %q", last)
+	}
+	if strings.Contains(got, "#1001`") {
+		t.Fatalf("oldest orders should be cut from the list:\n%s", got)
+	}
+
+	got = buyerOrdersComment(i18n.New("de"), "octocat", orders[:1])
+	if want := "- `#1001`, COFFEE_V1, $20.00, delivered, bestellt am 1.1.2026 in #10"; !strings.HasSuffix(got, want) {
+		t.Fatalf("German comment = %q, want it to end with %q", got, want)
+	}
+}
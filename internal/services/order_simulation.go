@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// simulationMarker tags the issues opened by an order simulation. Webhooks for these issues are
+// ignored, so a simulation never creates an order or a checkout.
+const simulationMarker = "<!-- gitshop:simulation -->"
+
+// OrderSimulationStep is one check of an order simulation.
+type OrderSimulationStep struct {
+	Name   string
+	Passed bool
+	Detail string
+}
+
+// OrderSimulationReport lists the checks an order simulation ran, in order. A simulation stops at
+// the first check later steps depend on, so a failed report can be shorter than a passing one.
+type OrderSimulationReport struct {
+	Steps    []OrderSimulationStep
+	IssueURL string
+}
+
+// Passed reports whether every check passed.
+func (r OrderSimulationReport) Passed() bool {
+	for _, step := range r.Steps {
+		if !step.Passed {
+			return false
+		}
+	}
+	return len(r.Steps) > 0
+}
+
+func (r *OrderSimulationReport) pass(name, detail string) {
+	r.Steps = append(r.Steps, OrderSimulationStep{Name: name, Passed: true, Detail: detail})
+}
+
+func (r *OrderSimulationReport) fail(name, detail string) {
+	r.Steps = append(r.Steps, OrderSimulationStep{Name: name, Detail: detail})
+}
+
+// SimulateOrder places a pretend order in the shop's repo. It opens an issue from the order
+// template, parses and prices it like a real order, comments the result, and closes the issue.
+// No order is saved and nothing is charged. The report covers the repo labels, gitshop.yaml,
+// the order template, and the app's permission to open, comment on, and close issues.
+func (s *AdminService) SimulateOrder(ctx context.Context, shop *db.Shop) (OrderSimulationReport, error) {
+	if s == nil || s.githubClient == nil || s.parser == nil || s.validator == nil {
+		return OrderSimulationReport{}, ErrAdminServiceUnavailable
+	}
+	if shop == nil || shop.GitHubRepoFullName == "" {
+		return OrderSimulationReport{}, fmt.Errorf("%w: shop is required", ErrAdminShopNotFound)
+	}
+
+	report := OrderSimulationReport{}
+	defer func() {
+		observability.MeterFromContext(ctx).Count("setup.simulation.completed", 1, sentry.WithAttributes(
+			attribute.Bool("passed", report.Passed()),
+		))
+	}()
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	repoFullName := shop.GitHubRepoFullName
+
+	labels := s.buildLabelsStatus(ctx, client, repoFullName)
+	switch {
+	case labels.ErrorMessage != "":
+		report.fail("Labels", "Could not list repo labels: "+labels.ErrorMessage)
+	case len(labels.Missing) > 0:
+		report.fail("Labels", "Missing: "+strings.Join(labels.Missing, ", "))
+	case len(labels.Recolor) > 0:
+		report.fail("Labels", "Color differs from gitshop.yaml: "+strings.Join(labels.Recolor, ", "))
+	default:
+		report.pass("Labels", "All GitShop labels exist")
+	}
+
+	config, err := s.fetchValidatedConfig(ctx, client, repoFullName)
+	if err != nil {
+		report.fail("gitshop.yaml", err.Error())
+		return report, nil
+	}
+	report.pass("gitshop.yaml", fmt.Sprintf("Valid, %d products", len(config.Products)))
+
+	templates, err := readOrderTemplates(ctx, client, repoFullName, contentAccess(ctx, client, s.logger))
+	if err != nil {
+		report.fail("Order template", "Could not read issue templates: "+err.Error())
+		return report, nil
+	}
+	if len(templates.templates) == 0 {
+		report.fail("Order template", "No order template found in "+githubapp.IssueTemplateDir)
+		return report, nil
+	}
+	template := templates.templates[0]
+	sample, err := catalog.SampleOrderIssue(template.content, "")
+	if err != nil {
+		report.fail("Order template", err.Error())
+		return report, nil
+	}
+	if check := catalog.CheckOrderTemplate(template.content, config); !check.Valid() {
+		report.fail("Order template", template.file.Name+": "+strings.Join(check.Problems(), "; "))
+	} else {
+		report.pass("Order template", template.file.Name+" matches gitshop.yaml")
+	}
+
+	issueNumber, err := client.CreateIssueWithNumber(ctx, repoFullName, sample.Title, sample.Body+"\n\n"+simulationMarker, sample.Labels)
+	if err != nil {
+		report.fail("Open issue", "GitShop could not open an issue: "+err.Error())
+		return report, nil
+	}
+	report.IssueURL = fmt.Sprintf("https://github.com/%s/issues/%d", repoFullName, issueNumber)
+	report.pass("Open issue", fmt.Sprintf("Opened #%d", issueNumber))
+
+	if comment, ok := simulateOrderPricing(&report, config, sample.Body, templateFieldIDs(template.content)); ok {
+		if err := client.CreateComment(ctx, repoFullName, issueNumber, comment); err != nil {
+			report.fail("Comment", "GitShop could not comment on the issue: "+err.Error())
+		} else {
+			report.pass("Comment", "Posted the order summary")
+		}
+	}
+
+	if err := client.CloseIssue(ctx, repoFullName, issueNumber); err != nil {
+		report.fail("Clean up", fmt.Sprintf("Could not close #%d, close it by hand: %s", issueNumber, err.Error()))
+	} else {
+		report.pass("Clean up", fmt.Sprintf("Closed #%d", issueNumber))
+	}
+	return report, nil
+}
+
+// simulateOrderPricing parses and prices the simulated issue like HandleIssueOpened does and
+// returns the comment to post, or false when the order could not be priced.
+func simulateOrderPricing(report *OrderSimulationReport, config *catalog.GitShopConfig, body string, fieldIDs map[string]string) (string, bool) {
+	orderData, err := parseOrderFromIssue(body, fieldIDs)
+	if err != nil {
+		report.fail("Parse", err.Error())
+		return "", false
+	}
+	product := findProduct(config, orderData.SKU)
+	if product == nil {
+		report.fail("Parse", fmt.Sprintf("SKU %s is not in gitshop.yaml", orderData.SKU))
+		return "", false
+	}
+	takeCheckboxAnswer(orderData.Options, catalog.EligibilityFieldID, catalog.EligibilityFieldLabel)
+	takeCheckboxAnswer(orderData.Options, catalog.TermsFieldID, catalog.TermsFieldLabel)
+	takeBuyerEmail(orderData.Options)
+	takeTextAnswer(orderData.Options, catalog.CurrencyFieldID, catalog.CurrencyFieldLabel)
+	catalog.ApplyOptionConditions(*product, orderData.Options)
+	if err := catalog.CheckOptionAnswers(*product, orderData.Options); err != nil {
+		report.fail("Parse", err.Error())
+		return "", false
+	}
+	report.pass("Parse", "Found "+product.Name+" ("+product.SKU+")")
+
+	pricer := catalog.NewPricer()
+	subtotalCents, err := pricer.ComputeSubtotal(config, orderData.SKU, orderData.Options)
+	if err != nil {
+		report.fail("Price", err.Error())
+		return "", false
+	}
+	shippingCents := pricer.GetShippingCents(config, orderData.SKU, subtotalCents)
+	totalCents, err := money.Add(subtotalCents, shippingCents)
+	if err == nil {
+		err = money.CheckChargeable(totalCents)
+	}
+	if err != nil {
+		report.fail("Price", err.Error())
+		return "", false
+	}
+	summary := fmt.Sprintf("%s + %s shipping = %s", money.Format(subtotalCents), money.Format(shippingCents), money.Format(totalCents))
+	report.pass("Price", summary)
+
+	comment := fmt.Sprintf("🧪 **Simulated order**\n\nThis issue was opened from the GitShop setup page to check the order flow. The buyer would pay %s for %s (%s).\n\nA real order gets a payment link here. Nothing was charged, and this issue closes on its own.", money.Format(totalCents), product.Name, summary)
+	return comment, true
+}
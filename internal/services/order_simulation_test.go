@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+)
+
+func TestSimulateOrderPricing(t *testing.T) {
+	t.Parallel()
+
+	config := &catalog.GitShopConfig{
+		Shop: catalog.ShopConfig{Terms: catalog.TermsConfig{URL: "https://example.com/terms", RequireCheckbox: true}},
+		Products: []catalog.ProductConfig{
+			{SKU: "TSHIRT", Name: "T-Shirt", UnitPriceCents: 2500, Active: true},
+		},
+	}
+	template, err := catalog.NewTemplateSyncer(nil).BuildTemplateContent(config)
+	if err != nil {
+		t.Fatalf("BuildTemplateContent returned error: %v", err)
+	}
+	sample, err := catalog.SampleOrderIssue(template, "")
+	if err != nil {
+		t.Fatalf("SampleOrderIssue returned error: %v", err)
+	}
+
+	report := OrderSimulationReport{}
+	comment, ok := simulateOrderPricing(&report, config, sample.Body, templateFieldIDs(template))
+	if !ok || !report.Passed() {
+		t.Fatalf("expected the sample order to price, got %+v", report.Steps)
+	}
+	if !strings.Contains(comment, "$25.00") {
+		t.Fatalf("comment missing total:\n%s", comment)
+	}
+
+	report = OrderSimulationReport{}
+	config.Products[0].SKU = "HOODIE"
+	if _, ok := simulateOrderPricing(&report, config, sample.Body, templateFieldIDs(template)); ok || report.Passed() {
+		t.Fatalf("expected an unknown SKU to fail, got %+v", report.Steps)
+	}
+}
+
+func TestSimulateOrderPricing_ChainedConditions(t *testing.T) {
+	t.Parallel()
+
+	product := catalog.ProductConfig{
+		SKU:            "PRINT",
+		Name:           "Print",
+		UnitPriceCents: 3000,
+		Active:         true,
+		Options: []catalog.ProductOption{
+			{Name: "framed", Label: "Framed", Type: "dropdown", Required: true, Values: []string{"No", "Yes"}},
+			{Name: "frame_color", Label: "Frame color", Type: "dropdown", Values: []string{"Black", "Oak"},
+				ExtraCents: map[string]int64{"Oak": 1000}, When: &catalog.OptionCondition{Option: "framed", Value: "Yes"}},
+			{Name: "mat", Label: "Mat", Type: "dropdown", Values: []string{"No", "Yes"},
+				ExtraCents: map[string]int64{"Yes": 500}, When: &catalog.OptionCondition{Option: "frame_color", Value: "Oak"}},
+		},
+	}
+	config := &catalog.GitShopConfig{Products: []catalog.ProductConfig{product}}
+
+	tests := []struct {
+		name   string
+		framed string
+		want   string
+	}{
+		// The mat depends on the frame color, which is dropped for an unframed print, so the mat
+		// must be dropped before pricing too.
+		{name: "unframed drops the whole chain", framed: "No", want: "$30.00 + $0.00 shipping = $30.00"},
+		{name: "framed keeps the whole chain", framed: "Yes", want: "$45.00 + $0.00 shipping = $45.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := "### Product\n\nPrint (SKU:PRINT)\n\n### Framed\n\n" + tt.framed + "\n\n### Frame color\n\nOak (+$10.00)\n\n### Mat\n\nYes (+$5.00)\n"
+			report := OrderSimulationReport{}
+			if _, ok := simulateOrderPricing(&report, config, body, nil); !ok {
+				t.Fatalf("expected the order to price, got %+v", report.Steps)
+			}
+			price := report.Steps[len(report.Steps)-1]
+			if price.Name != "Price" || price.Detail != tt.want {
+				t.Fatalf("price step = %+v, want %q", price, tt.want)
+			}
+		})
+	}
+}
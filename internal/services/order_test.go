@@ -4,15 +4,18 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v66/github"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
 )
 
 func TestIsOrderIssue(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name  string
-		issue *github.Issue
-		want  bool
+		name   string
+		issue  *github.Issue
+		labels catalog.LabelsConfig
+		want   bool
 	}{
 		{
 			name: "label based order issue",
@@ -21,6 +24,22 @@ func TestIsOrderIssue(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "custom prefix order label",
+			issue: &github.Issue{
+				Labels: []*github.Label{{Name: github.String("shop:order")}},
+			},
+			labels: catalog.LabelsConfig{Prefix: "shop"},
+			want:   true,
+		},
+		{
+			name: "default label after the prefix changed",
+			issue: &github.Issue{
+				Labels: []*github.Label{{Name: github.String("gitshop:order")}},
+			},
+			labels: catalog.LabelsConfig{Prefix: "shop"},
+			want:   false,
+		},
 		{
 			name: "marker based order issue",
 			issue: &github.Issue{
@@ -28,6 +47,14 @@ func TestIsOrderIssue(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "simulated order issue",
+			issue: &github.Issue{
+				Labels: []*github.Label{{Name: github.String("gitshop:order")}},
+				Body:   github.String("### Product\n\nT-Shirt (SKU:TSHIRT)\n\n" + simulationMarker),
+			},
+			want: false,
+		},
 		{
 			name: "generic text is not enough",
 			issue: &github.Issue{
@@ -41,10 +68,198 @@ func TestIsOrderIssue(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			got := IsOrderIssue(tc.issue)
+			got := IsOrderIssue(tc.issue, tc.labels)
 			if got != tc.want {
 				t.Fatalf("isOrderIssue() = %v, want %v", got, tc.want)
 			}
 		})
 	}
 }
+
+func TestTakeCheckboxAnswer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		body    string
+		wantAck bool
+	}{
+		{
+			name:    "checked",
+			body:    "### Product\n\nMug (SKU:MUG_V1)\n\n### Terms of sale\n\n- [X] I agree to the terms of sale\n",
+			wantAck: true,
+		},
+		{
+			name:    "unchecked",
+			body:    "### Product\n\nMug (SKU:MUG_V1)\n\n### Terms of sale\n\n- [ ] I agree to the terms of sale\n",
+			wantAck: false,
+		},
+		{
+			name:    "missing field",
+			body:    "### Product\n\nMug (SKU:MUG_V1)\n",
+			wantAck: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := parseOrderFromIssue(tc.body, nil)
+			if err != nil {
+				t.Fatalf("parseOrderFromIssue() error = %v", err)
+			}
+			if got := takeCheckboxAnswer(data.Options, catalog.TermsFieldID, catalog.TermsFieldLabel); got != tc.wantAck {
+				t.Fatalf("takeCheckboxAnswer() = %v, want %v", got, tc.wantAck)
+			}
+			if _, ok := data.Options["terms_of_sale"]; ok {
+				t.Fatalf("expected terms answer to be removed from options")
+			}
+		})
+	}
+}
+
+func TestTakeBuyerEmail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantEmail  string
+		wantOptOut bool
+		wantValid  bool
+	}{
+		{
+			name:      "email",
+			body:      "### Product\n\nMug (SKU:MUG_V1)\n\n### Email\n\n buyer@example.com \n\n### Checkout reminder\n\n- [ ] Don't email me a reminder about this order\n",
+			wantEmail: "buyer@example.com",
+			wantValid: true,
+		},
+		{
+			name:       "opted out",
+			body:       "### Product\n\nMug (SKU:MUG_V1)\n\n### Email\n\nbuyer@example.com\n\n### Checkout reminder\n\n- [X] Don't email me a reminder about this order\n",
+			wantEmail:  "buyer@example.com",
+			wantOptOut: true,
+			wantValid:  true,
+		},
+		{
+			name:      "skipped",
+			body:      "### Product\n\nMug (SKU:MUG_V1)\n\n### Email\n\n_No response_\n",
+			wantValid: true,
+		},
+		{
+			name: "not an address",
+			body: "### Product\n\nMug (SKU:MUG_V1)\n\n### Email\n\nBuyer <buyer@example.com>\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := parseOrderFromIssue(tc.body, nil)
+			if err != nil {
+				t.Fatalf("parseOrderFromIssue() error = %v", err)
+			}
+			email, optOut, valid := takeBuyerEmail(data.Options)
+			if email != tc.wantEmail || optOut != tc.wantOptOut || valid != tc.wantValid {
+				t.Fatalf("takeBuyerEmail() = %q, %v, %v, want %q, %v, %v", email, optOut, valid, tc.wantEmail, tc.wantOptOut, tc.wantValid)
+			}
+			if len(data.Options) != 0 {
+				t.Fatalf("options = %v, want the email answers removed", data.Options)
+			}
+		})
+	}
+}
+
+func TestParseOrderFromIssue_StripsOptionSurcharge(t *testing.T) {
+	t.Parallel()
+
+	data, err := parseOrderFromIssue("### Product\n\nT-Shirt (SKU:TSHIRT)\n\n### Quantity\n\n2\n\n### Size\n\nXXL (+$5.00)\n", nil)
+	if err != nil {
+		t.Fatalf("parseOrderFromIssue() error = %v", err)
+	}
+	if got := data.Options["size"]; got != "XXL" {
+		t.Fatalf("size = %v, want XXL", got)
+	}
+}
+
+func TestParseOrderFromIssue_FieldIDs(t *testing.T) {
+	t.Parallel()
+
+	spanish := map[string]string{
+		"producto":             "product",
+		"cantidad":             "quantity",
+		"talla":                "size",
+		"notas":                "notes",
+		"extras":               "extras",
+		"condiciones_de_venta": "terms",
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		fieldIDs    map[string]string
+		wantOptions map[string]any
+		wantTerms   bool
+	}{
+		{
+			name:     "localized labels",
+			body:     "### Producto\n\nCamiseta (SKU:TSHIRT)\n\n### Cantidad\n\n2\n\n### Talla\n\nXXL (+$5.00)\n\n### Condiciones de venta\n\n- [X] Acepto las condiciones\n",
+			fieldIDs: spanish,
+			wantOptions: map[string]any{
+				"quantity": 2,
+				"size":     "XXL",
+			},
+			wantTerms: true,
+		},
+		{
+			name:     "multi-line text and multi-select checkboxes",
+			body:     "### Producto\n\nCamiseta (SKU:TSHIRT)\n\n### Notas\n\nFirst line\nSecond line\n\n### Extras\n\n- [x] Gift wrap\n- [ ] Sticker\n- [X] Card\n",
+			fieldIDs: spanish,
+			wantOptions: map[string]any{
+				"notes":  "First line\nSecond line",
+				"extras": "Gift wrap, Card",
+			},
+		},
+		{
+			name:        "skipped optional field",
+			body:        "### Product\n\nT-Shirt (SKU:TSHIRT)\n\n### Notes\n\n_No response_\n",
+			wantOptions: map[string]any{},
+		},
+		{
+			name: "json body keyed by field ID",
+			body: "```json\n{\"product\": \"TSHIRT\", \"quantity\": 3, \"size\": \"M\", \"extras\": [\"Gift wrap\", \"Card\"], \"terms\": true}\n```",
+			wantOptions: map[string]any{
+				"quantity": 3,
+				"size":     "M",
+				"extras":   "Gift wrap, Card",
+			},
+			wantTerms: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := parseOrderFromIssue(tc.body, tc.fieldIDs)
+			if err != nil {
+				t.Fatalf("parseOrderFromIssue() error = %v", err)
+			}
+			if data.SKU != "TSHIRT" {
+				t.Fatalf("SKU = %q, want TSHIRT", data.SKU)
+			}
+			if got := takeCheckboxAnswer(data.Options, catalog.TermsFieldID, catalog.TermsFieldLabel); got != tc.wantTerms {
+				t.Fatalf("takeCheckboxAnswer() = %v, want %v", got, tc.wantTerms)
+			}
+			if len(data.Options) != len(tc.wantOptions) {
+				t.Fatalf("options = %v, want %v", data.Options, tc.wantOptions)
+			}
+			for key, want := range tc.wantOptions {
+				if got := data.Options[key]; got != want {
+					t.Fatalf("options[%q] = %#v, want %#v", key, got, want)
+				}
+			}
+		})
+	}
+}
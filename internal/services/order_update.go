@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const updateCommand = ".gitshop update"
+
+var errUpdateArgs = errors.New("invalid update arguments")
+
+// updateCommandArgs returns what follows `.gitshop update` in a comment.
+func updateCommandArgs(commentBody string) (string, bool) {
+	if commentBody == updateCommand {
+		return "", true
+	}
+	rest, ok := strings.CutPrefix(commentBody, updateCommand)
+	if !ok || rest == "" || !unicode.IsSpace(rune(rest[0])) {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// handleUpdateCommand changes the options of an unpaid order from
+// `.gitshop update quantity=3 size=L`, then re-prices it like an issue edit.
+func (s *OrderService) handleUpdateCommand(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int, order *db.Order, args, commenterLogin string, hasPermission bool, shop *db.Shop) error {
+	meter := observability.MeterFromContext(ctx)
+	meter.SetAttributes(attribute.String("source", "update_command"))
+	recordRejected := func(reason string) {
+		meter.Count("order.update.rejected", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	loc := shopLocalizer(ctx, client, repoFullName)
+	if order == nil || shop == nil {
+		recordRejected("order_not_found")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.order_not_found"))
+	}
+	if !hasPermission && commenterLogin != order.GitHubUsername {
+		recordRejected("permission_denied")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.update_permission_denied"))
+	}
+	if order.Status != db.StatusPendingPayment {
+		recordRejected("invalid_order_status")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.update_not_pending"))
+	}
+
+	config, err := s.validatedConfig(ctx, client, repoFullName)
+	if err != nil {
+		recordRejected("config_invalid")
+		return s.commentWithManagerNotice(ctx, client, repoFullName, issueNumber, "config_invalid", loc.T("comment.update_config_invalid"))
+	}
+	reject := func(reason, message string) error {
+		recordRejected(reason)
+		return client.CreateComment(ctx, repoFullName, issueNumber, orderEditRejectedComment(loc, order.OrderNumber, message))
+	}
+
+	updates, err := parseUpdateArgs(args)
+	if err != nil {
+		return reject("invalid_arguments", loc.T("edit.update_usage"))
+	}
+	product := findProduct(config, order.SKU)
+	if product == nil {
+		return reject("sku_missing", loc.T("edit.sku_missing", order.SKU))
+	}
+	options, err := applyOptionUpdates(loc, product, order.Options, updates)
+	if err != nil {
+		return reject("invalid_option", err.Error())
+	}
+	orderData := &OrderData{SKU: order.SKU, Options: options}
+	if !orderEditChanged(order, orderData) {
+		recordRejected("order_unchanged")
+		return client.CreateComment(ctx, repoFullName, issueNumber, loc.T("comment.order_update_unchanged", commentOrderNumber(order.OrderNumber)))
+	}
+	if !canReplaceCheckout(order) {
+		return reject("checkout_not_replaceable", loc.T("edit.checkout_not_replaceable"))
+	}
+
+	return s.repriceOrder(ctx, client, shop, order, config, orderData, orderRepricing{
+		RepoFullName:        repoFullName,
+		IssueNumber:         issueNumber,
+		EditedBy:            commenterLogin,
+		Source:              "update_command",
+		EligibilityAttested: order.VerificationStatus == db.VerificationAttested,
+	})
+}
+
+// parseUpdateArgs reads `name=value` pairs. Values with spaces can be double-quoted.
+func parseUpdateArgs(args string) (map[string]string, error) {
+	updates := make(map[string]string)
+	rest := strings.TrimSpace(args)
+	for rest != "" {
+		name, after, ok := strings.Cut(rest, "=")
+		if !ok || name == "" || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("%w: expected name=value in %q", errUpdateArgs, rest)
+		}
+
+		var value string
+		if quoted, ok := strings.CutPrefix(after, `"`); ok {
+			end := strings.IndexByte(quoted, '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated quote for %s", errUpdateArgs, name)
+			}
+			value, rest = quoted[:end], quoted[end+1:]
+		} else if end := strings.IndexFunc(after, unicode.IsSpace); end >= 0 {
+			value, rest = after[:end], after[end:]
+		} else {
+			value, rest = after, ""
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, fmt.Errorf("%w: %s has no value", errUpdateArgs, name)
+		}
+		updates[normalizeHeader(name)] = value
+		rest = strings.TrimSpace(rest)
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("%w: nothing to update", errUpdateArgs)
+	}
+	return updates, nil
+}
+
+// applyOptionUpdates returns the order's options with updates applied, checked against the
+// product's options in gitshop.yaml. The error is a localized reason for the buyer.
+func applyOptionUpdates(loc i18n.Localizer, product *catalog.ProductConfig, current map[string]any, updates map[string]string) (map[string]any, error) {
+	options := make(map[string]any, len(current)+len(updates))
+	maps.Copy(options, current)
+
+	for name, value := range updates {
+		if name == "quantity" {
+			quantity, err := strconv.Atoi(value)
+			if err != nil || quantity < 1 {
+				return nil, errors.New(loc.T("edit.invalid_quantity", value))
+			}
+			options["quantity"] = quantity
+			continue
+		}
+
+		option := findProductOption(product, name)
+		if option == nil {
+			return nil, errors.New(loc.T("edit.unknown_option", name, product.SKU))
+		}
+		if len(option.Values) > 0 {
+			chosen, ok := matchOptionValue(option.Values, catalog.OptionValueFromLabel(value))
+			if !ok {
+				return nil, errors.New(loc.T("edit.invalid_option_value", value, option.Name, strings.Join(option.Values, ", ")))
+			}
+			value = chosen
+		}
+		options[option.Name] = value
+	}
+	return options, nil
+}
+
+// findProductOption matches name against an option's name or its order template label.
+func findProductOption(product *catalog.ProductConfig, name string) *catalog.ProductOption {
+	for i := range product.Options {
+		option := &product.Options[i]
+		if option.Name == "quantity" {
+			continue
+		}
+		if normalizeHeader(option.Name) == name || normalizeHeader(option.Label) == name {
+			return option
+		}
+	}
+	return nil
+}
+
+func matchOptionValue(values []string, value string) (string, bool) {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+func TestUpdateCommandArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{body: ".gitshop update quantity=3 size=L", want: "quantity=3 size=L", wantOK: true},
+		{body: ".gitshop update", want: "", wantOK: true},
+		{body: ".gitshop updates quantity=3", wantOK: false},
+		{body: ".gitshop retry", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			t.Parallel()
+			got, ok := updateCommandArgs(tt.body)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("updateCommandArgs(%q) = %q, %v, want %q, %v", tt.body, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseUpdateArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "pairs", args: "quantity=3 size=L", want: map[string]string{"quantity": "3", "size": "L"}},
+		{name: "quoted value", args: `Gift-Note="Happy birthday" quantity=1`, want: map[string]string{"gift_note": "Happy birthday", "quantity": "1"}},
+		{name: "empty", args: "", wantErr: true},
+		{name: "missing value", args: "size=", wantErr: true},
+		{name: "missing equals", args: "size L", wantErr: true},
+		{name: "unterminated quote", args: `note="hi`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseUpdateArgs(tt.args)
+			if tt.wantErr {
+				if !errors.Is(err, errUpdateArgs) {
+					t.Fatalf("parseUpdateArgs(%q) error = %v, want errUpdateArgs", tt.args, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseUpdateArgs(%q) unexpected error: %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseUpdateArgs(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyOptionUpdates(t *testing.T) {
+	t.Parallel()
+
+	product := &catalog.ProductConfig{
+		SKU: "TSHIRT",
+		Options: []catalog.ProductOption{
+			{Name: "size", Label: "Size", Type: "dropdown", Values: []string{"S", "M", "L", "XXL"}, ExtraCents: map[string]int64{"XXL": 500}},
+			{Name: "gift_note", Label: "Gift note", Type: "input"},
+		},
+	}
+	current := map[string]any{"quantity": float64(1), "size": "M"}
+
+	tests := []struct {
+		name    string
+		updates map[string]string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name:    "quantity and size",
+			updates: map[string]string{"quantity": "3", "size": "l"},
+			want:    map[string]any{"quantity": 3, "size": "L"},
+		},
+		{
+			name:    "surcharge label",
+			updates: map[string]string{"size": "XXL (+$5.00)"},
+			want:    map[string]any{"quantity": float64(1), "size": "XXL"},
+		},
+		{
+			name:    "free text option",
+			updates: map[string]string{"gift_note": "Happy birthday"},
+			want:    map[string]any{"quantity": float64(1), "size": "M", "gift_note": "Happy birthday"},
+		},
+		{
+			name:    "invalid quantity",
+			updates: map[string]string{"quantity": "0"},
+			wantErr: "Quantity must be a whole number of at least 1, not `0`.",
+		},
+		{
+			name:    "unknown option",
+			updates: map[string]string{"color": "red"},
+			wantErr: "`color` is not an option of `TSHIRT`.",
+		},
+		{
+			name:    "value not offered",
+			updates: map[string]string{"size": "XL"},
+			wantErr: "`XL` is not a choice for size. Choose one of: S, M, L, XXL.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := applyOptionUpdates(i18n.Localizer{}, product, current, tt.updates)
+			if current["size"] != "M" {
+				t.Fatalf("applyOptionUpdates() changed the order's options: %v", current)
+			}
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("applyOptionUpdates() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyOptionUpdates() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("applyOptionUpdates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	stripeapi "github.com/stripe/stripe-go/v84"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+// requestIdentityVerification starts a Stripe Identity check for a restricted order and posts
+// the verification link after intro. Checkout is created once Stripe reports the session as
+// verified.
+func (s *OrderService) requestIdentityVerification(ctx context.Context, client *githubapp.Client, shop *db.Shop, order *db.Order, repoFullName string, loc i18n.Localizer, intro string) error {
+	err := startIdentityVerification(ctx, s.stripePlatform, s.orderStore, client, shop, order, repoFullName, loc, intro)
+	if errors.Is(err, errIdentityVerificationNotStarted) {
+		comment := s.appendManagerMention(ctx, client, repoFullName, loc.T("comment.identity_start_failed"))
+		if commentErr := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, comment); commentErr != nil {
+			s.loggerFromContext(ctx).Warn("failed to create verification-failed comment", "error", commentErr, "repo", repoFullName, "issue", order.GitHubIssueNumber)
+		}
+	}
+	return err
+}
+
+var errIdentityVerificationNotStarted = errors.New("identity verification not started")
+
+// startIdentityVerification creates the Stripe Identity session behind requestIdentityVerification
+// and posts its link. It returns errIdentityVerificationNotStarted when Stripe could not create
+// the session, so the caller can tell the buyer.
+func startIdentityVerification(ctx context.Context, platform *stripe.PlatformClient, orderStore *db.OrderStore, client *githubapp.Client, shop *db.Shop, order *db.Order, repoFullName string, loc i18n.Localizer, intro string) error {
+	if platform == nil {
+		return fmt.Errorf("stripe platform not configured")
+	}
+
+	session, err := platform.CreateIdentityVerification(stripe.WithTestMode(ctx, order.TestMode), stripe.IdentityVerificationParams{
+		OrderID:         order.ID,
+		ShopID:          shop.ID,
+		IssueNumber:     order.GitHubIssueNumber,
+		RepoFullName:    repoFullName,
+		ReturnURL:       fmt.Sprintf("https://github.com/%s/issues/%d", repoFullName, order.GitHubIssueNumber),
+		StripeAccountID: shop.StripeConnectAccountID,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to create identity verification: %w", errIdentityVerificationNotStarted, err)
+	}
+
+	if err := orderStore.StartIdentityVerification(ctx, order.ID, session.ID); err != nil {
+		return fmt.Errorf("failed to record identity verification: %w", err)
+	}
+
+	comment := loc.T("comment.identity_verify_link", intro, session.URL)
+	if err := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, comment); err != nil {
+		return fmt.Errorf("failed to comment verification link: %w", err)
+	}
+	return nil
+}
+
+// needsIdentityVerification reports whether order must pass an identity check before it gets a
+// checkout link.
+func needsIdentityVerification(product catalog.ProductConfig, order *db.Order) bool {
+	return product.VerifyIdentity && order.VerificationStatus != db.VerificationVerified
+}
+
+func (s *OrderService) HandleIdentityVerificationVerified(ctx context.Context, payload []byte) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.order.identity_verification_verified",
+		sentry.WithOpName("service.order"),
+		sentry.WithDescription("HandleIdentityVerificationVerified"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	meter := observability.MeterFromContext(ctx)
+	logger := s.loggerFromContext(ctx)
+
+	order, shop, session, err := s.loadVerificationOrder(ctx, payload)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return nil
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	repoFullName := shop.GitHubRepoFullName
+
+	configContent, err := s.getGitShopConfigFile(ctx, client, repoFullName)
+	if err != nil {
+		return fmt.Errorf("failed to load gitshop.yaml: %w", err)
+	}
+	config, err := s.parser.Parse(configContent)
+	if err != nil {
+		return fmt.Errorf("failed to parse gitshop.yaml: %w", err)
+	}
+	product := findProduct(config, order.SKU)
+	if product == nil {
+		if commentErr := s.commentWithManagerNotice(ctx, client, repoFullName, order.GitHubIssueNumber, "sku_missing", configLocalizer(config).T("comment.retry_sku_missing")); commentErr != nil {
+			logger.Warn("failed to create missing-sku comment", "error", commentErr, "repo", repoFullName, "issue", order.GitHubIssueNumber)
+		}
+		return fmt.Errorf("sku not found: %s", order.SKU)
+	}
+
+	if product.MinimumAge > 0 {
+		verified, err := s.stripePlatform.GetIdentityVerification(stripe.WithTestMode(ctx, order.TestMode), shop.StripeConnectAccountID, session.ID)
+		if err != nil {
+			return err
+		}
+		if !meetsMinimumAge(verified.VerifiedOutputs, product.MinimumAge, time.Now()) {
+			meter.Count("order.verification.rejected", 1, sentry.WithAttributes(
+				attribute.String("reason", "minimum_age"),
+			))
+			if err := s.orderStore.UpdateVerificationStatus(ctx, order.ID, db.VerificationRejected); err != nil {
+				return fmt.Errorf("failed to reject verification: %w", err)
+			}
+			if err := s.orderStore.MarkFailed(ctx, order.ID, "age_requirement_not_met"); err != nil {
+				logger.Warn("failed to mark order failed after age check", "error", err, "order_id", order.ID)
+			}
+			return client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, configLocalizer(config).T("comment.minimum_age", product.MinimumAge))
+		}
+	}
+
+	if err := s.orderStore.UpdateVerificationStatus(ctx, order.ID, db.VerificationVerified); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			logger.Info("ignoring identity verification update due to state transition", "order_id", order.ID, "error", err)
+			return nil
+		}
+		return fmt.Errorf("failed to mark verification verified: %w", err)
+	}
+	meter.Count("order.verification.verified", 1)
+
+	checkout, err := createOrderCheckout(ctx, s.payments, shop, order, checkoutParamsForOrder(shop, order, config, product, repoFullName), "identity_verification")
+	if err != nil {
+		if markErr := s.orderStore.MarkFailed(ctx, order.ID, "stripe_checkout_failed"); markErr != nil {
+			logger.Warn("failed to mark order failed after checkout error", "error", markErr, "order_id", order.ID)
+		}
+		loc := configLocalizer(config)
+		failComment := checkoutFailedComment(loc, err, s.appendManagerMention(ctx, client, repoFullName, loc.T("comment.identity_checkout_failed")))
+		if commentErr := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, failComment); commentErr != nil {
+			logger.Warn("failed to create checkout-failed comment", "error", commentErr, "repo", repoFullName, "issue", order.GitHubIssueNumber)
+		}
+		return fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	if err := saveOrderCheckout(ctx, s.orderStore, order, checkout); err != nil {
+		return fmt.Errorf("failed to update order with checkout: %w", err)
+	}
+
+	if err := client.CreateComment(ctx, repoFullName, order.GitHubIssueNumber, checkout.comment(configLocalizer(config), order, config.Shop.Checkout.ExpiresIn(), identityVerifiedCheckoutComment)); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	meter.Count("checkout.session.created", 1, sentry.WithAttributes(
+		attribute.String("source", "identity_verification"),
+	))
+	return nil
+}
+
+func (s *OrderService) HandleIdentityVerificationRequiresInput(ctx context.Context, payload []byte) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.order.identity_verification_requires_input",
+		sentry.WithOpName("service.order"),
+		sentry.WithDescription("HandleIdentityVerificationRequiresInput"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	order, shop, session, err := s.loadVerificationOrder(ctx, payload)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return nil
+	}
+
+	if err := s.orderStore.UpdateVerificationStatus(ctx, order.ID, db.VerificationRequiresInput); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			return nil
+		}
+		return fmt.Errorf("failed to update verification status: %w", err)
+	}
+	observability.MeterFromContext(ctx).Count("order.verification.requires_input", 1)
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	loc := shopLocalizer(ctx, client, shop.GitHubRepoFullName)
+	intro := loc.T("comment.identity_unverified")
+	if session.LastError != nil && session.LastError.Reason != "" {
+		intro = loc.T("comment.identity_unverified_reason", session.LastError.Reason)
+	}
+	return s.requestIdentityVerification(ctx, client, shop, order, shop.GitHubRepoFullName, loc, intro)
+}
+
+// loadVerificationOrder resolves the order behind an Identity webhook. A nil order means the
+// event is stale and should be ignored.
+func (s *OrderService) loadVerificationOrder(ctx context.Context, payload []byte) (*db.Order, *db.Shop, *stripeapi.IdentityVerificationSession, error) {
+	var session stripeapi.IdentityVerificationSession
+	if err := json.Unmarshal(payload, &session); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid event object: %w", err)
+	}
+
+	orderID, _, _, err := parseStripeMetadata(session.Metadata)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	order, err := s.orderStore.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != db.StatusPendingPayment || order.IdentityVerificationID != session.ID {
+		s.loggerFromContext(ctx).Info("ignoring stale identity verification event", "order_id", order.ID, "session_id", session.ID)
+		return nil, nil, nil, nil
+	}
+
+	shop, err := s.shopStore.GetByID(ctx, order.ShopID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get shop: %w", err)
+	}
+	return order, shop, &session, nil
+}
+
+func meetsMinimumAge(outputs *stripeapi.IdentityVerificationSessionVerifiedOutputs, minimumAge int, now time.Time) bool {
+	if outputs == nil || outputs.DOB == nil || outputs.DOB.Year == 0 {
+		return false
+	}
+	dob := outputs.DOB
+	age := now.Year() - int(dob.Year)
+	if now.Month() < time.Month(dob.Month) || (now.Month() == time.Month(dob.Month) && now.Day() < int(dob.Day)) {
+		age--
+	}
+	return age >= minimumAge
+}
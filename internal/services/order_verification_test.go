@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	stripeapi "github.com/stripe/stripe-go/v84"
+)
+
+func TestMeetsMinimumAge(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		outputs *stripeapi.IdentityVerificationSessionVerifiedOutputs
+		want    bool
+	}{
+		{
+			name:    "birthday today",
+			outputs: &stripeapi.IdentityVerificationSessionVerifiedOutputs{DOB: &stripeapi.IdentityVerificationSessionVerifiedOutputsDOB{Year: 2008, Month: 6, Day: 15}},
+			want:    true,
+		},
+		{
+			name:    "birthday tomorrow",
+			outputs: &stripeapi.IdentityVerificationSessionVerifiedOutputs{DOB: &stripeapi.IdentityVerificationSessionVerifiedOutputsDOB{Year: 2008, Month: 6, Day: 16}},
+			want:    false,
+		},
+		{
+			name:    "missing date of birth",
+			outputs: &stripeapi.IdentityVerificationSessionVerifiedOutputs{},
+			want:    false,
+		},
+		{
+			name:    "no outputs",
+			outputs: nil,
+			want:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := meetsMinimumAge(tc.outputs, 18, now); got != tc.want {
+				t.Fatalf("meetsMinimumAge() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
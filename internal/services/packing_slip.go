@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+// packingSlipLimit caps the slips printed at once for unshipped orders, oldest paid first.
+const packingSlipLimit = 100
+
+// PackingSlips are printable slips for the orders going into boxes. They list what to pack and
+// where it goes, and never prices, since the slip travels with the parcel.
+type PackingSlips struct {
+	ShopName string
+	Slips    []PackingSlip
+}
+
+type PackingSlip struct {
+	OrderNumber int
+	PlacedAt    time.Time
+	SKU         string
+	// ProductName is empty when the product is no longer in gitshop.yaml.
+	ProductName     string
+	Quantity        int
+	Options         []PackingSlipLine
+	CustomerName    string
+	GitHubUsername  string
+	ShippingAddress string
+}
+
+// PackingSlipLine is one product option or checkout answer printed on a slip.
+type PackingSlipLine struct {
+	Label string
+	Value string
+}
+
+// PackingSlip returns the packing slip for one order.
+func (s *AdminService) PackingSlip(ctx context.Context, input OrderActionInput) (*PackingSlips, error) {
+	if s == nil || s.orderStore == nil || s.shopStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	return s.packingSlips(ctx, shop, []*db.Order{order}), nil
+}
+
+// UnshippedPackingSlips returns packing slips for the shop's paid orders that have not shipped,
+// oldest paid first.
+func (s *AdminService) UnshippedPackingSlips(ctx context.Context, shopID uuid.UUID, now time.Time) (*PackingSlips, error) {
+	if s == nil || s.orderStore == nil || s.shopStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	shop, err := s.shopStore.GetByID(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	// Every paid order is overdue as of now, so this lists all of them waiting to ship.
+	orders, err := s.orderStore.ListOverdueOrders(ctx, shopID, now, packingSlipLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unshipped orders: %w", err)
+	}
+	return s.packingSlips(ctx, shop, orders), nil
+}
+
+// packingSlips names products and options from the current gitshop.yaml. When it cannot be
+// read, the slips fall back to SKUs and option keys rather than failing to print.
+func (s *AdminService) packingSlips(ctx context.Context, shop *db.Shop, orders []*db.Order) *PackingSlips {
+	slips := &PackingSlips{ShopName: shop.GitHubRepoFullName, Slips: make([]PackingSlip, 0, len(orders))}
+	if len(orders) == 0 {
+		return slips
+	}
+
+	var config *catalog.GitShopConfig
+	if s.githubClient != nil {
+		client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+		var err error
+		config, err = s.fetchValidatedConfig(ctx, client, shop.GitHubRepoFullName)
+		if err != nil {
+			s.loggerFromContext(ctx).Warn("failed to load config for packing slips", "error", err, "shop_id", shop.ID)
+			config = nil
+		}
+	}
+	if config != nil && strings.TrimSpace(config.Shop.Name) != "" {
+		slips.ShopName = config.Shop.Name
+	}
+	for _, order := range orders {
+		slips.Slips = append(slips.Slips, packingSlipForOrder(order, findProduct(config, order.SKU)))
+	}
+	return slips
+}
+
+func packingSlipForOrder(order *db.Order, product *catalog.ProductConfig) PackingSlip {
+	slip := PackingSlip{
+		OrderNumber:     order.OrderNumber,
+		PlacedAt:        order.CreatedAt,
+		SKU:             order.SKU,
+		Quantity:        orderQuantity(order.Options),
+		CustomerName:    order.CustomerName,
+		GitHubUsername:  order.GitHubUsername,
+		ShippingAddress: formatAddressMap(order.ShippingAddress),
+	}
+	if product != nil {
+		slip.ProductName = product.Name
+	}
+
+	keys := make([]string, 0, len(order.Options))
+	for key := range order.Options {
+		if key != "quantity" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		label := key
+		if product != nil {
+			if option := findProductOption(product, key); option != nil && option.Label != "" {
+				label = option.Label
+			}
+		}
+		slip.Options = append(slip.Options, PackingSlipLine{Label: label, Value: fmt.Sprintf("%v", order.Options[key])})
+	}
+	for _, field := range order.CustomFields {
+		slip.Options = append(slip.Options, PackingSlipLine{Label: field.Label, Value: field.Value})
+	}
+	return slip
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestPackingSlipForOrder(t *testing.T) {
+	t.Parallel()
+
+	order := &db.Order{
+		OrderNumber:     7,
+		SKU:             "TSHIRT",
+		Options:         map[string]any{"quantity": float64(2), "size": "M"},
+		CustomerName:    "Mona Lisa",
+		ShippingAddress: map[string]any{"line1": "1 Main St", "city": "Springfield", "state": "IL", "postal_code": "62701", "country": "US"},
+		CustomFields:    []db.OrderCustomField{{Key: "gift_note", Label: "Gift note", Value: "Happy birthday"}},
+	}
+	product := &catalog.ProductConfig{
+		SKU:     "TSHIRT",
+		Name:    "T-Shirt",
+		Options: []catalog.ProductOption{{Name: "size", Label: "Size"}},
+	}
+
+	slip := packingSlipForOrder(order, product)
+	if slip.ProductName != "T-Shirt" || slip.Quantity != 2 {
+		t.Fatalf("slip = %+v, want T-Shirt x2", slip)
+	}
+	want := []PackingSlipLine{{Label: "Size", Value: "M"}, {Label: "Gift note", Value: "Happy birthday"}}
+	if len(slip.Options) != len(want) {
+		t.Fatalf("options = %+v, want %+v", slip.Options, want)
+	}
+	for i := range want {
+		if slip.Options[i] != want[i] {
+			t.Fatalf("options[%d] = %+v, want %+v", i, slip.Options[i], want[i])
+		}
+	}
+	if slip.ShippingAddress == "" {
+		t.Fatalf("expected a formatted shipping address")
+	}
+
+	slip = packingSlipForOrder(order, nil)
+	if slip.ProductName != "" || slip.Options[0].Label != "size" {
+		t.Fatalf("slip without product = %+v, want SKU and option keys", slip)
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// PaymentEventService handles webhooks from payment processors that deliver to a per-shop
+// endpoint. Stripe events go through StripeService instead.
+type PaymentEventService struct {
+	shopStore  *db.ShopStore
+	orderStore *db.OrderStore
+	payments   *PaymentProviders
+	completer  *StripeService
+	logger     *slog.Logger
+}
+
+func NewPaymentEventService(shopStore *db.ShopStore, orderStore *db.OrderStore, payments *PaymentProviders, completer *StripeService, logger *slog.Logger) *PaymentEventService {
+	return &PaymentEventService{
+		shopStore:  shopStore,
+		orderStore: orderStore,
+		payments:   payments,
+		completer:  completer,
+		logger:     logger,
+	}
+}
+
+// ParseWebhook verifies a webhook against the shop's processor settings. It returns the
+// processor name with the event so callers can deduplicate deliveries.
+func (s *PaymentEventService) ParseWebhook(ctx context.Context, shopID uuid.UUID, payload []byte, header http.Header) (*db.Shop, db.PaymentProcessor, PaymentEvent, error) {
+	shop, err := s.shopStore.GetByID(ctx, shopID)
+	if err != nil {
+		return nil, "", PaymentEvent{}, fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+	provider, err := s.payments.ForShop(ctx, shop)
+	if err != nil {
+		return nil, "", PaymentEvent{}, err
+	}
+	event, err := provider.ParseWebhook(payload, header)
+	if err != nil {
+		return nil, "", PaymentEvent{}, err
+	}
+	return shop, provider.Processor(), event, nil
+}
+
+// HandlePaymentEvent marks the order paid and runs the same side effects as a completed Stripe
+// checkout. Events for other shops' orders are rejected.
+func (s *PaymentEventService) HandlePaymentEvent(ctx context.Context, shop *db.Shop, event PaymentEvent) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.payment_events.handle",
+		sentry.WithOpName("service.payment_events"),
+		sentry.WithDescription("HandlePaymentEvent"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := logging.FromContext(ctx, s.logger)
+	meter := observability.MeterFromContext(ctx)
+	meter.SetAttributes(attribute.String("event", event.Name))
+	recordFailed := func(reason string) {
+		meter.Count("payment.webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	meter.Count("payment.webhook.received", 1)
+
+	if event.Type != PaymentEventPaid {
+		meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", "unhandled_event"),
+		))
+		return nil
+	}
+
+	order, err := s.orderStore.GetByID(ctx, event.OrderID)
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.ShopID != shop.ID {
+		recordFailed("shop_mismatch")
+		return fmt.Errorf("order %s does not belong to shop %s", order.ID, shop.ID)
+	}
+
+	if err := s.orderStore.MarkPaid(ctx, order.ID, "", event.CustomerEmail, event.CustomerName, nil, string(order.CheckoutMethod)); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+				attribute.String("reason", "invalid_status_transition"),
+			))
+			logger.Info("ignoring payment event due to state transition", "order_id", order.ID, "event", event.ID, "error", err)
+			return nil
+		}
+		recordFailed("mark_paid_failed")
+		return fmt.Errorf("failed to mark order as paid: %w", err)
+	}
+	refreshCustomer(ctx, s.orderStore, order.ID)
+	if err := s.orderStore.SetProviderPaymentID(ctx, order.ID, event.PaymentID); err != nil {
+		logger.Warn("failed to record processor payment id", "error", err, "order_id", order.ID)
+	}
+	meter.Count("payment.succeeded", 1, sentry.WithAttributes(
+		attribute.String("source", event.Name),
+	))
+
+	if err := s.completer.completePaidOrder(ctx, shop, order, shop.GitHubRepoFullName, order.GitHubIssueNumber, event.CustomerEmail, event.CustomerName, nil); err != nil {
+		recordFailed("side_effects_enqueue_failed")
+		return err
+	}
+	meter.Count("payment.webhook.processed", 1)
+	span.Status = sentry.SpanStatusOK
+	return nil
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/lemonsqueezy"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+type providerPaymentStore interface {
+	GetProviderPaymentID(ctx context.Context, orderID uuid.UUID) (string, error)
+}
+
+// lemonSqueezyPaymentProvider takes payments through the seller's own Lemon Squeezy store. Lemon
+// Squeezy is the merchant of record, so tax is handled there and no shipping address is collected.
+type lemonSqueezyPaymentProvider struct {
+	client     *lemonsqueezy.Client
+	settings   db.LemonSqueezySettings
+	orderStore providerPaymentStore
+}
+
+func (p *lemonSqueezyPaymentProvider) Processor() db.PaymentProcessor {
+	return db.PaymentProcessorLemonSqueezy
+}
+
+func (p *lemonSqueezyPaymentProvider) CreateCheckout(ctx context.Context, params stripe.CheckoutSessionParams) (PaymentCheckout, error) {
+	if params.IsSubscription() {
+		return PaymentCheckout{}, fmt.Errorf("%w: lemon squeezy checkouts are one-time payments", ErrPaymentSubscriptionUnsupported)
+	}
+	description := fmt.Sprintf("Quantity: %d", params.Quantity)
+	if params.ShippingCents > 0 {
+		description += " · includes shipping"
+	}
+	expiresAt := params.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(catalog.DefaultCheckoutExpiry)
+	}
+	checkout, err := p.client.CreateCheckout(ctx, lemonsqueezy.CheckoutParams{
+		StoreID:       p.settings.StoreID,
+		VariantID:     p.settings.VariantID,
+		Name:          params.ProductName,
+		Description:   description,
+		PriceCents:    params.UnitPriceCents*params.Quantity + params.ShippingCents,
+		CustomerEmail: params.CustomerEmail,
+		RedirectURL:   params.SuccessURL,
+		ExpiresAt:     expiresAt,
+		CustomData: map[string]string{
+			"order_id":              params.OrderID.String(),
+			"shop_id":               params.ShopID.String(),
+			"github_issue_number":   strconv.Itoa(params.IssueNumber),
+			"github_repo_full_name": params.RepoFullName,
+		},
+	})
+	if err != nil {
+		return PaymentCheckout{}, err
+	}
+	return PaymentCheckout{Method: db.CheckoutMethodLemonSqueezy, ID: checkout.ID, URL: checkout.URL, ExpiresAt: expiresAt}, nil
+}
+
+// GetAccountStatus treats a store the API key can read as ready; Lemon Squeezy holds payouts
+// itself and does not report them per store.
+func (p *lemonSqueezyPaymentProvider) GetAccountStatus(ctx context.Context, _ *db.Shop) (PaymentAccountStatus, error) {
+	if _, err := p.client.GetStore(ctx, p.settings.StoreID); err != nil {
+		return PaymentAccountStatus{}, err
+	}
+	return PaymentAccountStatus{ChargesEnabled: true, PayoutsEnabled: true}, nil
+}
+
+func (p *lemonSqueezyPaymentProvider) Refund(ctx context.Context, _ *db.Shop, order *db.Order) error {
+	paymentID, err := p.orderStore.GetProviderPaymentID(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load lemon squeezy order: %w", err)
+	}
+	if paymentID == "" {
+		return fmt.Errorf("%w: no lemon squeezy payment to refund", ErrAdminRefundUnavailable)
+	}
+	return p.client.RefundOrder(ctx, paymentID, order.TotalCents)
+}
+
+func (p *lemonSqueezyPaymentProvider) ParseWebhook(payload []byte, header http.Header) (PaymentEvent, error) {
+	event, err := lemonsqueezy.ParseWebhook(payload, header.Get("X-Signature"), p.settings.WebhookSecret)
+	if err != nil {
+		return PaymentEvent{}, err
+	}
+	return lemonSqueezyPaymentEvent(event)
+}
+
+// lemonSqueezyPaymentEvent maps a Lemon Squeezy webhook to a PaymentEvent. Only paid orders from
+// GitShop checkouts are acted on.
+func lemonSqueezyPaymentEvent(event *lemonsqueezy.WebhookEvent) (PaymentEvent, error) {
+	payment := PaymentEvent{
+		ID:   event.Meta.EventName + ":" + event.Data.ID,
+		Name: event.Meta.EventName,
+	}
+	rawOrderID, ok := event.Meta.CustomData["order_id"]
+	if event.Meta.EventName != "order_created" || event.Data.Attributes.Status != "paid" || !ok {
+		return payment, nil
+	}
+
+	orderID, err := uuid.Parse(rawOrderID)
+	if err != nil {
+		return PaymentEvent{}, fmt.Errorf("invalid order_id in lemon squeezy custom data: %w", err)
+	}
+	payment.Type = PaymentEventPaid
+	payment.OrderID = orderID
+	payment.PaymentID = event.Data.ID
+	payment.CustomerEmail = event.Data.Attributes.UserEmail
+	payment.CustomerName = event.Data.Attributes.UserName
+	return payment, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/featureflags"
+	"github.com/gitshopapp/gitshop/internal/fx"
+	"github.com/gitshopapp/gitshop/internal/lemonsqueezy"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+var (
+	// ErrPaymentProviderUnavailable means the processor is not set up on this GitShop instance.
+	ErrPaymentProviderUnavailable = errors.New("payment provider unavailable")
+	// ErrPaymentProviderNotConfigured means the shop has not finished connecting its processor.
+	ErrPaymentProviderNotConfigured = errors.New("payment provider not configured")
+	ErrPaymentWebhookUnsupported    = errors.New("payment provider does not accept shop webhooks")
+	// ErrPaymentSubscriptionUnsupported means the processor cannot bill products with billing set.
+	ErrPaymentSubscriptionUnsupported = errors.New("payment provider does not support subscriptions")
+)
+
+// PaymentProvider is a payment processor a shop takes orders through. Stripe is the default;
+// other processors let shops in countries Stripe does not support use GitShop.
+type PaymentProvider interface {
+	Processor() db.PaymentProcessor
+	// CreateCheckout creates the payment page sent to the buyer for an order.
+	CreateCheckout(ctx context.Context, params stripe.CheckoutSessionParams) (PaymentCheckout, error)
+	GetAccountStatus(ctx context.Context, shop *db.Shop) (PaymentAccountStatus, error)
+	// Refund returns the full payment for a paid order.
+	Refund(ctx context.Context, shop *db.Shop, order *db.Order) error
+	// ParseWebhook verifies a webhook sent to the shop's payment endpoint and reads the payment
+	// event in it.
+	ParseWebhook(payload []byte, header http.Header) (PaymentEvent, error)
+}
+
+// PaymentCheckout is the payment page sent to the buyer for an order.
+type PaymentCheckout struct {
+	Method    db.CheckoutMethod
+	ID        string
+	URL       string
+	ExpiresAt time.Time // zero for checkouts that stay open until paid
+	// Estimate is the order total in the buyer's chosen currency, empty when they chose none.
+	Estimate string
+}
+
+// PaymentAccountStatus is what the processor reports the shop's account can do.
+type PaymentAccountStatus struct {
+	ChargesEnabled bool
+	PayoutsEnabled bool
+}
+
+type PaymentEventType string
+
+const PaymentEventPaid PaymentEventType = "paid"
+
+// PaymentEvent is a processor webhook reduced to what order processing needs. Type is empty for
+// events GitShop does not act on.
+type PaymentEvent struct {
+	ID            string
+	Name          string
+	Type          PaymentEventType
+	OrderID       uuid.UUID
+	PaymentID     string
+	CustomerEmail string
+	CustomerName  string
+}
+
+type paymentSettingsStore interface {
+	GetPaymentSettings(ctx context.Context, shopID uuid.UUID) (db.PaymentSettings, error)
+}
+
+// PaymentProviders picks the payment processor configured for each shop.
+type PaymentProviders struct {
+	settings     paymentSettingsStore
+	suspensions  shopSuspensionStore
+	stripe       PaymentProvider
+	lemonSqueezy func(settings db.LemonSqueezySettings) PaymentProvider
+	rates        fx.RateSource
+	flags        *featureflags.Flags
+}
+
+func NewPaymentProviders(shopStore *db.ShopStore, orderStore *db.OrderStore, stripePlatform *stripe.PlatformClient) *PaymentProviders {
+	var stripeProvider PaymentProvider
+	if stripePlatform != nil {
+		stripeProvider = &stripePaymentProvider{platform: stripePlatform, shopStore: shopStore}
+	}
+	return &PaymentProviders{
+		settings:    shopStore,
+		suspensions: shopStore,
+		stripe:      stripeProvider,
+		lemonSqueezy: func(settings db.LemonSqueezySettings) PaymentProvider {
+			return &lemonSqueezyPaymentProvider{
+				client:     lemonsqueezy.NewClient(settings.APIKey),
+				settings:   settings,
+				orderStore: orderStore,
+			}
+		},
+	}
+}
+
+// WithRateSource returns a copy of the providers that estimates each checkout's total in the
+// currency its buyer picked. A nil source turns estimates off.
+func (p *PaymentProviders) WithRateSource(rates fx.RateSource) *PaymentProviders {
+	providers := *p
+	providers.rates = rates
+	return &providers
+}
+
+// WithFeatureFlags returns a copy of the providers that refuses subscription checkouts for shops
+// without the subscriptions flag. Without flags every subscription checkout is refused.
+func (p *PaymentProviders) WithFeatureFlags(flags *featureflags.Flags) *PaymentProviders {
+	providers := *p
+	providers.flags = flags
+	return &providers
+}
+
+func (p *PaymentProviders) featureFlags() *featureflags.Flags {
+	if p == nil {
+		return nil
+	}
+	return p.flags
+}
+
+func (p *PaymentProviders) rateSource() fx.RateSource {
+	if p == nil {
+		return nil
+	}
+	return p.rates
+}
+
+func (p *PaymentProviders) suspensionStore() shopSuspensionStore {
+	if p == nil {
+		return nil
+	}
+	return p.suspensions
+}
+
+// ForShop returns the processor the shop takes payments through. It fails with
+// ErrPaymentProviderNotConfigured until the shop has connected that processor.
+func (p *PaymentProviders) ForShop(ctx context.Context, shop *db.Shop) (PaymentProvider, error) {
+	if p == nil || shop == nil {
+		return nil, ErrPaymentProviderUnavailable
+	}
+	settings, err := p.settings.GetPaymentSettings(ctx, shop.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment settings: %w", err)
+	}
+
+	switch settings.Processor {
+	case db.PaymentProcessorLemonSqueezy:
+		if !settings.LemonSqueezy.Configured() {
+			return nil, fmt.Errorf("%w: lemon squeezy store is not connected", ErrPaymentProviderNotConfigured)
+		}
+		return p.lemonSqueezy(settings.LemonSqueezy), nil
+	case db.PaymentProcessorStripe, "":
+		if p.stripe == nil {
+			return nil, fmt.Errorf("%w: stripe platform not configured", ErrPaymentProviderUnavailable)
+		}
+		if shop.StripeConnectAccountID == "" {
+			return nil, fmt.Errorf("%w: stripe account not connected", ErrPaymentProviderNotConfigured)
+		}
+		return p.stripe, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown payment processor %q", ErrPaymentProviderUnavailable, settings.Processor)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/lemonsqueezy"
+)
+
+type fakePaymentSettingsStore struct {
+	settings db.PaymentSettings
+}
+
+func (s fakePaymentSettingsStore) GetPaymentSettings(context.Context, uuid.UUID) (db.PaymentSettings, error) {
+	return s.settings, nil
+}
+
+func TestPaymentProviders_ForShop(t *testing.T) {
+	t.Parallel()
+
+	lemonSqueezy := db.LemonSqueezySettings{StoreID: "1", VariantID: "2", APIKey: "key", WebhookSecret: "secret"}
+	tests := []struct {
+		name          string
+		settings      db.PaymentSettings
+		stripe        PaymentProvider
+		accountID     string
+		wantProcessor db.PaymentProcessor
+		wantErr       error
+	}{
+		{
+			name:          "stripe connected",
+			settings:      db.PaymentSettings{Processor: db.PaymentProcessorStripe},
+			stripe:        &stripePaymentProvider{},
+			accountID:     "acct_1",
+			wantProcessor: db.PaymentProcessorStripe,
+		},
+		{
+			name:     "stripe not connected",
+			settings: db.PaymentSettings{Processor: db.PaymentProcessorStripe},
+			stripe:   &stripePaymentProvider{},
+			wantErr:  ErrPaymentProviderNotConfigured,
+		},
+		{
+			name:      "stripe platform missing",
+			settings:  db.PaymentSettings{Processor: db.PaymentProcessorStripe},
+			accountID: "acct_1",
+			wantErr:   ErrPaymentProviderUnavailable,
+		},
+		{
+			name:          "lemon squeezy without stripe platform",
+			settings:      db.PaymentSettings{Processor: db.PaymentProcessorLemonSqueezy, LemonSqueezy: lemonSqueezy},
+			wantProcessor: db.PaymentProcessorLemonSqueezy,
+		},
+		{
+			name:     "lemon squeezy incomplete",
+			settings: db.PaymentSettings{Processor: db.PaymentProcessorLemonSqueezy, LemonSqueezy: db.LemonSqueezySettings{StoreID: "1"}},
+			wantErr:  ErrPaymentProviderNotConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			providers := &PaymentProviders{
+				settings: fakePaymentSettingsStore{settings: tt.settings},
+				stripe:   tt.stripe,
+				lemonSqueezy: func(settings db.LemonSqueezySettings) PaymentProvider {
+					return &lemonSqueezyPaymentProvider{settings: settings}
+				},
+			}
+			provider, err := providers.ForShop(t.Context(), &db.Shop{ID: uuid.New(), StripeConnectAccountID: tt.accountID})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ForShop() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ForShop() error = %v", err)
+			}
+			if provider.Processor() != tt.wantProcessor {
+				t.Fatalf("Processor() = %q, want %q", provider.Processor(), tt.wantProcessor)
+			}
+		})
+	}
+}
+
+func TestLemonSqueezyPaymentEvent(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New()
+	newEvent := func(name, status, rawOrderID string) *lemonsqueezy.WebhookEvent {
+		event := &lemonsqueezy.WebhookEvent{}
+		event.Meta.EventName = name
+		event.Meta.CustomData = map[string]string{"order_id": rawOrderID}
+		event.Data.ID = "42"
+		event.Data.Attributes.Status = status
+		event.Data.Attributes.UserEmail = "buyer@example.com"
+		return event
+	}
+
+	tests := []struct {
+		name     string
+		event    *lemonsqueezy.WebhookEvent
+		wantType PaymentEventType
+		wantErr  bool
+	}{
+		{name: "paid order", event: newEvent("order_created", "paid", orderID.String()), wantType: PaymentEventPaid},
+		{name: "pending order", event: newEvent("order_created", "pending", orderID.String())},
+		{name: "refund", event: newEvent("order_refunded", "refunded", orderID.String())},
+		{name: "invalid order id", event: newEvent("order_created", "paid", "not-a-uuid"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := lemonSqueezyPaymentEvent(tt.event)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Type != tt.wantType {
+				t.Fatalf("Type = %q, want %q", got.Type, tt.wantType)
+			}
+			if got.ID != tt.event.Meta.EventName+":42" {
+				t.Fatalf("ID = %q", got.ID)
+			}
+			if tt.wantType == PaymentEventPaid && (got.OrderID != orderID || got.PaymentID != "42" || got.CustomerEmail != "buyer@example.com") {
+				t.Fatalf("unexpected paid event: %+v", got)
+			}
+		})
+	}
+}
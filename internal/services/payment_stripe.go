@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+// stripeCapabilitiesFreshFor is how long saved Stripe capabilities are trusted before the account
+// is fetched again. account.updated events keep them current in between.
+const stripeCapabilitiesFreshFor = 15 * time.Minute
+
+type stripeCapabilityStore interface {
+	GetStripeCapabilities(ctx context.Context, shopID uuid.UUID) (db.StripeCapabilities, error)
+	UpdateStripeConnectDetails(ctx context.Context, shopID uuid.UUID, accountID string, detailsSubmitted, chargesEnabled, payoutsEnabled bool) error
+}
+
+// stripePaymentProvider takes payments through the shop's Stripe Connect account.
+type stripePaymentProvider struct {
+	platform  *stripe.PlatformClient
+	shopStore stripeCapabilityStore
+}
+
+func (p *stripePaymentProvider) Processor() db.PaymentProcessor {
+	return db.PaymentProcessorStripe
+}
+
+// CreateCheckout creates a Checkout Session for the order. When Stripe rejects the session it
+// falls back to a single-use Payment Link for the same items, so a transient Checkout failure does
+// not fail the order. Subscriptions have no fallback.
+func (p *stripePaymentProvider) CreateCheckout(ctx context.Context, params stripe.CheckoutSessionParams) (PaymentCheckout, error) {
+	session, err := p.platform.CreateCheckoutSession(ctx, params)
+	if err == nil {
+		checkout := PaymentCheckout{Method: db.CheckoutMethodSession, ID: session.ID, URL: session.URL}
+		if session.ExpiresAt > 0 {
+			checkout.ExpiresAt = time.Unix(session.ExpiresAt, 0)
+		}
+		return checkout, nil
+	}
+	if params.IsSubscription() {
+		return PaymentCheckout{}, err
+	}
+	meter := observability.MeterFromContext(ctx)
+	logging.FromContext(ctx, nil).Warn("failed to create checkout session, falling back to a payment link", "error", err, "order_id", params.OrderID)
+
+	link, linkErr := p.platform.CreatePaymentLink(ctx, params)
+	if linkErr != nil {
+		meter.Count("checkout.payment_link.failed", 1)
+		return PaymentCheckout{}, fmt.Errorf("%w (payment link fallback: %v)", err, linkErr)
+	}
+	meter.Count("checkout.payment_link.created", 1)
+	return PaymentCheckout{Method: db.CheckoutMethodPaymentLink, ID: link.ID, URL: link.URL}, nil
+}
+
+// GetAccountStatus uses the saved capabilities while they are fresh and otherwise fetches the
+// account from Stripe and saves what it reports.
+func (p *stripePaymentProvider) GetAccountStatus(ctx context.Context, shop *db.Shop) (PaymentAccountStatus, error) {
+	capabilities, err := p.shopStore.GetStripeCapabilities(ctx, shop.ID)
+	if err != nil {
+		logging.FromContext(ctx, nil).Warn("failed to load stripe capabilities", "error", err, "shop_id", shop.ID)
+	} else if stripeCapabilitiesFresh(capabilities, time.Now()) {
+		return PaymentAccountStatus{ChargesEnabled: capabilities.ChargesEnabled, PayoutsEnabled: capabilities.PayoutsEnabled}, nil
+	}
+
+	account, err := p.platform.GetAccount(ctx, shop.StripeConnectAccountID)
+	if err != nil {
+		return PaymentAccountStatus{}, fmt.Errorf("failed to verify stripe account: %w", err)
+	}
+	if err := p.shopStore.UpdateStripeConnectDetails(ctx, shop.ID, shop.StripeConnectAccountID, account.DetailsSubmitted, account.ChargesEnabled, account.PayoutsEnabled); err != nil {
+		logging.FromContext(ctx, nil).Warn("failed to persist stripe account details", "error", err, "shop_id", shop.ID)
+	}
+	return PaymentAccountStatus{ChargesEnabled: account.ChargesEnabled, PayoutsEnabled: account.PayoutsEnabled}, nil
+}
+
+func stripeCapabilitiesFresh(capabilities db.StripeCapabilities, now time.Time) bool {
+	return !capabilities.CheckedAt.IsZero() && now.Sub(capabilities.CheckedAt) < stripeCapabilitiesFreshFor
+}
+
+func (p *stripePaymentProvider) Refund(ctx context.Context, shop *db.Shop, order *db.Order) error {
+	if order.StripePaymentIntentID == "" {
+		return fmt.Errorf("%w: no stripe payment to refund", ErrAdminRefundUnavailable)
+	}
+	if _, err := p.platform.CreateRefund(stripe.WithTestMode(ctx, order.TestMode), shop.StripeConnectAccountID, order.StripePaymentIntentID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseWebhook always fails: Stripe sends every shop's events to the platform endpoint, which
+// routes them by account.
+func (p *stripePaymentProvider) ParseWebhook([]byte, http.Header) (PaymentEvent, error) {
+	return PaymentEvent{}, fmt.Errorf("%w: stripe events are delivered to /webhooks/stripe", ErrPaymentWebhookUnsupported)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+var errProcessingRegionUnavailable = errors.New("processing region settings are not available")
+
+type ProcessingRegionSettings struct {
+	db.ProcessingRegion
+	// Regions are the home regions this deployment offers. Empty means it runs in a single region.
+	Regions []string
+}
+
+type ProcessingRegionService struct {
+	shopStore *db.ShopStore
+	regions   []string
+}
+
+func NewProcessingRegionService(shopStore *db.ShopStore, regions []string) *ProcessingRegionService {
+	return &ProcessingRegionService{shopStore: shopStore, regions: regions}
+}
+
+func (s *ProcessingRegionService) GetSettings(ctx context.Context, shopID uuid.UUID) (*ProcessingRegionSettings, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, errProcessingRegionUnavailable
+	}
+
+	settings, err := s.shopStore.GetProcessingRegion(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load processing region: %w", err)
+	}
+	return &ProcessingRegionSettings{ProcessingRegion: *settings, Regions: s.regions}, nil
+}
+
+// UpdateSettings pins the shop to region and sets which customer logs it keeps. An empty region
+// unpins the shop.
+func (s *ProcessingRegionService) UpdateSettings(ctx context.Context, shopID uuid.UUID, region string, retainEmailLog bool) error {
+	if s == nil {
+		return errProcessingRegionUnavailable
+	}
+	region = strings.ToLower(strings.TrimSpace(region))
+	if region != "" && !slices.Contains(s.regions, region) {
+		return UserError{Message: "Choose one of the available regions"}
+	}
+	if s.shopStore == nil {
+		return errProcessingRegionUnavailable
+	}
+
+	return s.shopStore.UpdateProcessingRegion(ctx, shopID, db.ProcessingRegion{
+		Region:         region,
+		RetainEmailLog: retainEmailLog,
+	})
+}
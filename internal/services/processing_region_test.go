@@ -0,0 +1,40 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestProcessingRegionService_UpdateSettingsValidatesRegion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		regions     []string
+		region      string
+		wantUserErr bool
+	}{
+		{name: "unpinned", regions: []string{"eu", "us"}, region: "", wantUserErr: false},
+		{name: "available region", regions: []string{"eu", "us"}, region: " EU ", wantUserErr: false},
+		{name: "unknown region", regions: []string{"eu", "us"}, region: "ap", wantUserErr: true},
+		{name: "single region deployment", region: "eu", wantUserErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := NewProcessingRegionService(nil, tt.regions)
+			err := service.UpdateSettings(t.Context(), uuid.New(), tt.region, true)
+			var userErr UserError
+			if got := errors.As(err, &userErr); got != tt.wantUserErr {
+				t.Fatalf("expected user error=%v, got %v", tt.wantUserErr, err)
+			}
+			if !tt.wantUserErr && !errors.Is(err, errProcessingRegionUnavailable) {
+				t.Fatalf("expected store to be required after validation, got %v", err)
+			}
+		})
+	}
+}
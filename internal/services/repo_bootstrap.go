@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// readmeShopMarker tags the README section GitShop adds, so bootstrapping never adds it twice.
+const readmeShopMarker = "<!-- gitshop:shop -->"
+
+// SetupPullRequestStatus is the bootstrap pull request as the setup page shows it.
+type SetupPullRequestStatus struct {
+	// Available is false when the installation cannot open pull requests, so setup falls back to
+	// the individual steps.
+	Available bool
+	URL       string
+	// State is empty when no pull request was opened or GitHub could not be asked about it.
+	State githubapp.PullRequestState
+}
+
+// BootstrapRepo gets a new shop repository ready in one step: it creates the GitShop labels and
+// opens a single pull request adding gitshop.yaml, order templates for its products, and a
+// README section linking to the order form. Files that already exist are left alone. While an
+// earlier bootstrap pull request is still open, that one is returned instead of a new one.
+func (s *AdminService) BootstrapRepo(ctx context.Context, shop *db.Shop) (*githubapp.FileCreationResult, error) {
+	if s == nil || s.githubClient == nil || s.shopStore == nil {
+		return nil, fmt.Errorf("%w: github client unavailable", ErrAdminServiceUnavailable)
+	}
+	if shop == nil {
+		return nil, fmt.Errorf("shop is required")
+	}
+
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("setup.bootstrap.failed", 1, sentry.WithAttributes(attribute.String("reason", reason)))
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	if !contentAccess(ctx, client, s.logger).CanOpenPullRequests() {
+		recordFailed("single_file_access")
+		return nil, UserError{Message: "One-click setup opens a pull request, which needs Contents write access. GitShop only has single-file access to this repository, so use the individual steps below."}
+	}
+
+	previous, err := s.shopStore.GetSetupPullRequest(ctx, shop.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load setup pull request: %w", err)
+	}
+	if previous.Number > 0 {
+		state, err := client.GetPullRequestState(ctx, shop.GitHubRepoFullName, previous.Number)
+		if err != nil {
+			s.loggerFromContext(ctx).Warn("failed to check setup pull request", "error", err, "repo", shop.GitHubRepoFullName, "pr_number", previous.Number)
+		} else if state == githubapp.PullRequestOpen {
+			return &githubapp.FileCreationResult{Method: "pr", URL: previous.URL, PRNumber: previous.Number}, nil
+		}
+	}
+
+	labels := shopLabels(shopConfig(ctx, client, shop.GitHubRepoFullName))
+	if err := client.EnsureLabels(ctx, shop.GitHubRepoFullName, RequiredRepoLabels(labels)); err != nil {
+		recordFailed("labels")
+		return nil, fmt.Errorf("failed to create labels: %w", err)
+	}
+
+	files, err := s.bootstrapFiles(ctx, client, shop.GitHubRepoFullName)
+	if err != nil {
+		recordFailed("files")
+		return nil, err
+	}
+	if len(files) == 0 {
+		meter.Count("setup.bootstrap.skipped", 1)
+		return &githubapp.FileCreationResult{Method: "exists"}, nil
+	}
+
+	owner, repo, err := splitRepoFullName(shop.GitHubRepoFullName)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, "- `"+file.Path+"`")
+	}
+	pr, err := client.CreatePullRequestWithFiles(ctx, owner, repo, githubapp.PullRequestInput{
+		BranchName: "gitshop/setup-" + time.Now().UTC().Format("20060102150405"),
+		Message:    "Set up GitShop",
+		Title:      "Set up GitShop",
+		Body:       "This PR sets up GitShop for this repository:\n\n" + strings.Join(paths, "\n") + "\n\nThe order labels are already created. Replace the sample product in `gitshop.yaml` with your own, then merge to start taking orders through GitHub issues.",
+		Files:      files,
+	})
+	if err != nil {
+		recordFailed("pull_request")
+		return nil, contentAccessUserError(err, "the setup files")
+	}
+
+	if err := s.shopStore.SetSetupPullRequest(ctx, shop.ID, db.SetupPullRequest{Number: pr.PRNumber, URL: pr.URL}); err != nil {
+		s.loggerFromContext(ctx).Warn("failed to record setup pull request", "error", err, "shop_id", shop.ID, "pr_url", pr.URL)
+	}
+
+	meter.Count("setup.bootstrap.opened", 1, sentry.WithAttributes(attribute.Int("files", len(files))))
+	s.loggerFromContext(ctx).Info("opened setup pull request", "repo", shop.GitHubRepoFullName, "pr_url", pr.URL, "files", len(files))
+	return pr, nil
+}
+
+// bootstrapFiles lists the setup files the repository is missing. Templates are generated from
+// the repository's own gitshop.yaml when it has one, otherwise from the sample catalog.
+func (s *AdminService) bootstrapFiles(ctx context.Context, client *githubapp.Client, repoFullName string) ([]githubapp.FileChange, error) {
+	files := []githubapp.FileChange{}
+
+	yamlStatus, _, err := s.getGitShopFileStatus(ctx, client, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+	var config *catalog.GitShopConfig
+	if yamlStatus.Exists {
+		config, err = s.fetchValidatedConfig(ctx, client, repoFullName)
+		if err != nil {
+			return nil, UserError{Message: "Fix gitshop.yaml before running setup: " + err.Error()}
+		}
+	} else {
+		content := fmt.Sprintf(githubapp.DefaultGitShopYAML, repoFullName)
+		config, err = s.parser.Parse([]byte(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse default gitshop.yaml: %w", err)
+		}
+		files = append(files, githubapp.FileChange{Path: "gitshop.yaml", Content: content})
+	}
+
+	access := githubapp.ContentAccess{Mode: githubapp.ContentAccessFull}
+	existing, err := readOrderTemplates(ctx, client, repoFullName, access)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := s.newSyncer(s.githubClient).BuildTemplateFiles(config, existing.coveredSKUs(), existing.paths)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		files = append(files, githubapp.FileChange{Path: template.Path, Content: template.Content})
+	}
+
+	readme, err := client.GetFile(ctx, repoFullName, "README.md", "")
+	if err != nil && !githubapp.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to read README.md: %w", err)
+	}
+	if content, changed := withShopReadmeSection(string(readme), repoFullName); changed {
+		files = append(files, githubapp.FileChange{Path: "README.md", Content: content})
+	}
+
+	return files, nil
+}
+
+// withShopReadmeSection appends a section pointing buyers at the order form, or starts a README
+// when the repository has none. It reports false when the section is already there.
+func withShopReadmeSection(readme, repoFullName string) (string, bool) {
+	if strings.Contains(readme, readmeShopMarker) {
+		return readme, false
+	}
+	section := readmeShopMarker + "\n## Shop\n\nTo order, [open an order issue](https://github.com/" + repoFullName + "/issues/new/choose) and pick a product. GitShop replies on the issue with a checkout link and keeps it updated through payment and shipping.\n"
+	if strings.TrimSpace(readme) == "" {
+		_, repo, _ := strings.Cut(repoFullName, "/")
+		return "# " + repo + "\n\n" + section, true
+	}
+	return strings.TrimRight(readme, "\n") + "\n\n" + section, true
+}
+
+// SetupPullRequestStatus reports on the bootstrap pull request for the setup page.
+func (s *AdminService) SetupPullRequestStatus(ctx context.Context, shop *db.Shop) SetupPullRequestStatus {
+	status := SetupPullRequestStatus{}
+	if s == nil || s.githubClient == nil || s.shopStore == nil || shop == nil {
+		return status
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	status.Available = contentAccess(ctx, client, s.logger).CanOpenPullRequests()
+
+	pr, err := s.shopStore.GetSetupPullRequest(ctx, shop.ID)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to load setup pull request", "error", err, "shop_id", shop.ID)
+		return status
+	}
+	if pr.Number == 0 {
+		return status
+	}
+	status.URL = pr.URL
+	state, err := client.GetPullRequestState(ctx, shop.GitHubRepoFullName, pr.Number)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to check setup pull request", "error", err, "repo", shop.GitHubRepoFullName, "pr_number", pr.Number)
+		return status
+	}
+	status.State = state
+	return status
+}
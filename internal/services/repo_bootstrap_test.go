@@ -0,0 +1,44 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithShopReadmeSection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("starts a README when there is none", func(t *testing.T) {
+		t.Parallel()
+		got, changed := withShopReadmeSection("", "octo/shop")
+		if !changed {
+			t.Fatal("expected a new README")
+		}
+		if !strings.HasPrefix(got, "# shop\n\n"+readmeShopMarker) {
+			t.Fatalf("unexpected README start:\n%s", got)
+		}
+		if !strings.Contains(got, "https://github.com/octo/shop/issues/new/choose") {
+			t.Fatalf("README does not link to the order form:\n%s", got)
+		}
+	})
+
+	t.Run("appends to an existing README", func(t *testing.T) {
+		t.Parallel()
+		got, changed := withShopReadmeSection("# Octo Shop\n\nStickers and mugs.\n\n\n", "octo/shop")
+		if !changed {
+			t.Fatal("expected the section to be appended")
+		}
+		if !strings.HasPrefix(got, "# Octo Shop\n\nStickers and mugs.\n\n"+readmeShopMarker) {
+			t.Fatalf("section not appended after existing content:\n%s", got)
+		}
+	})
+
+	t.Run("leaves a README that already has the section", func(t *testing.T) {
+		t.Parallel()
+		readme, _ := withShopReadmeSection("# Octo Shop\n", "octo/shop")
+		got, changed := withShopReadmeSection(readme, "octo/shop")
+		if changed || got != readme {
+			t.Fatalf("expected README unchanged, got changed=%v:\n%s", changed, got)
+		}
+	})
+}
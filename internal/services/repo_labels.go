@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+)
+
+// shopLabels is the label scheme every label change and check reads. A missing gitshop.yaml or
+// an invalid labels section keeps the default "gitshop:" labels.
+func shopLabels(config *catalog.GitShopConfig) catalog.LabelsConfig {
+	if config == nil || catalog.ValidateLabels(config.Labels) != nil {
+		return catalog.LabelsConfig{}
+	}
+	return config.Labels
+}
+
+// RequiredRepoLabels are the labels GitShop puts on order issues, named and colored by the
+// shop's labels config.
+func RequiredRepoLabels(labels catalog.LabelsConfig) []githubapp.LabelDefinition {
+	resolved := labels.Labels()
+	definitions := make([]githubapp.LabelDefinition, 0, len(resolved))
+	for _, label := range resolved {
+		definitions = append(definitions, githubapp.LabelDefinition{Name: label.Name, Color: label.Color, Description: label.Description})
+	}
+	return definitions
+}
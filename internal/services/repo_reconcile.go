@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	// repoReconcileHour is the UTC hour the nightly reconciliation runs.
+	repoReconcileHour      = 3
+	repoReconcileBatchSize = 100
+)
+
+type repoReconcileShopStore interface {
+	ListConnectedShops(ctx context.Context, region string, afterID uuid.UUID, limit int) ([]*db.Shop, error)
+	UpdateRepoFullName(ctx context.Context, shopID uuid.UUID, repoFullName string) error
+	MarkRepoOrphaned(ctx context.Context, shopID uuid.UUID, reason db.RepoOrphanReason) error
+	ClearRepoOrphaned(ctx context.Context, shopID uuid.UUID) error
+}
+
+type repoResolver interface {
+	GetRepositoryFullName(ctx context.Context, repoID int64) (string, error)
+}
+
+// RepoReconcileResult counts what a reconciliation pass found.
+type RepoReconcileResult struct {
+	Checked  int
+	Renamed  int
+	Orphaned int
+	Failed   int
+}
+
+// RepoReconciliationService re-resolves every connected shop's repository through the GitHub
+// API each night. Renames and transfers that no webhook reported are picked up, and shops whose
+// repository or installation is gone are flagged for an operator to review.
+type RepoReconciliationService struct {
+	shopStore repoReconcileShopStore
+	repos     func(installationID int64) repoResolver
+	region    string
+	logger    *slog.Logger
+}
+
+func NewRepoReconciliationService(shopStore *db.ShopStore, githubClient *githubapp.Client, region string, logger *slog.Logger) *RepoReconciliationService {
+	var repos func(int64) repoResolver
+	if githubClient != nil {
+		repos = func(installationID int64) repoResolver {
+			return githubClient.WithInstallation(installationID)
+		}
+	}
+	return newRepoReconciliationService(shopStore, repos, region, logger)
+}
+
+func newRepoReconciliationService(shopStore repoReconcileShopStore, repos func(int64) repoResolver, region string, logger *slog.Logger) *RepoReconciliationService {
+	return &RepoReconciliationService{
+		shopStore: shopStore,
+		repos:     repos,
+		region:    region,
+		logger:    logger,
+	}
+}
+
+// Run reconciles every night at repoReconcileHour UTC until ctx is cancelled.
+func (s *RepoReconciliationService) Run(ctx context.Context) {
+	if s == nil || s.shopStore == nil || s.repos == nil {
+		return
+	}
+
+	for {
+		timer := time.NewTimer(time.Until(nextRepoReconcile(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		result, err := s.Reconcile(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to reconcile shop repositories", "error", err)
+		}
+		s.logger.Info("reconciled shop repositories",
+			"checked", result.Checked,
+			"renamed", result.Renamed,
+			"orphaned", result.Orphaned,
+			"failed", result.Failed)
+	}
+}
+
+// nextRepoReconcile returns the first repoReconcileHour UTC after now.
+func nextRepoReconcile(now time.Time) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), repoReconcileHour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Reconcile checks every connected shop once, one batch at a time. A shop that cannot be checked
+// because of a transient GitHub error is counted as failed and left as it was.
+func (s *RepoReconciliationService) Reconcile(ctx context.Context) (RepoReconcileResult, error) {
+	result := RepoReconcileResult{}
+	afterID := uuid.Nil
+	for {
+		shops, err := s.shopStore.ListConnectedShops(ctx, s.region, afterID, repoReconcileBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list shops: %w", err)
+		}
+		for _, shop := range shops {
+			result.Checked++
+			switch outcome, err := s.reconcileShop(ctx, shop); {
+			case err != nil:
+				result.Failed++
+				s.logger.Warn("failed to reconcile shop repository", "error", err, "shop_id", shop.ID, "repo", shop.GitHubRepoFullName)
+			case outcome == repoRenamed:
+				result.Renamed++
+			case outcome == repoOrphaned:
+				result.Orphaned++
+			}
+			afterID = shop.ID
+		}
+		if len(shops) < repoReconcileBatchSize || ctx.Err() != nil {
+			return result, nil
+		}
+	}
+}
+
+type repoReconcileOutcome int
+
+const (
+	repoUnchanged repoReconcileOutcome = iota
+	repoRenamed
+	repoOrphaned
+)
+
+func (s *RepoReconciliationService) reconcileShop(ctx context.Context, shop *db.Shop) (repoReconcileOutcome, error) {
+	meter := observability.MeterFromContext(ctx)
+
+	fullName, err := s.repos(shop.GitHubInstallationID).GetRepositoryFullName(ctx, shop.GitHubRepoID)
+	if err != nil {
+		reason := db.RepoOrphanReason("")
+		switch {
+		case errors.Is(err, githubapp.ErrInstallationUnavailable):
+			reason = db.RepoOrphanInstallationUnavailable
+		case githubapp.IsNotFound(err):
+			reason = db.RepoOrphanNotFound
+		default:
+			meter.Count("repository.reconcile.failed", 1)
+			return repoUnchanged, err
+		}
+		if err := s.shopStore.MarkRepoOrphaned(ctx, shop.ID, reason); err != nil {
+			return repoUnchanged, fmt.Errorf("failed to flag shop: %w", err)
+		}
+		meter.Count("repository.reconcile.orphaned", 1, sentry.WithAttributes(attribute.String("reason", string(reason))))
+		s.logger.Warn("shop repository could not be resolved", "shop_id", shop.ID, "repo", shop.GitHubRepoFullName, "reason", reason)
+		return repoOrphaned, nil
+	}
+
+	outcome := repoUnchanged
+	if fullName != "" && fullName != shop.GitHubRepoFullName {
+		if err := s.shopStore.UpdateRepoFullName(ctx, shop.ID, fullName); err != nil {
+			return repoUnchanged, fmt.Errorf("failed to update repo full name: %w", err)
+		}
+		meter.Count("repository.reconcile.renamed", 1)
+		s.logger.Info("repository name changed, updating shop", "shop_id", shop.ID, "old_name", shop.GitHubRepoFullName, "new_name", fullName)
+		outcome = repoRenamed
+	}
+	if err := s.shopStore.ClearRepoOrphaned(ctx, shop.ID); err != nil {
+		return outcome, fmt.Errorf("failed to clear review flag: %w", err)
+	}
+	return outcome, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+)
+
+type fakeRepoReconcileStore struct {
+	shops    []*db.Shop
+	renamed  map[uuid.UUID]string
+	orphaned map[uuid.UUID]db.RepoOrphanReason
+	cleared  []uuid.UUID
+}
+
+func (s *fakeRepoReconcileStore) ListConnectedShops(_ context.Context, _ string, afterID uuid.UUID, limit int) ([]*db.Shop, error) {
+	page := []*db.Shop{}
+	for _, shop := range s.shops {
+		if afterID != uuid.Nil && shop.ID.String() <= afterID.String() {
+			continue
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, shop)
+	}
+	return page, nil
+}
+
+func (s *fakeRepoReconcileStore) UpdateRepoFullName(_ context.Context, shopID uuid.UUID, repoFullName string) error {
+	s.renamed[shopID] = repoFullName
+	return nil
+}
+
+func (s *fakeRepoReconcileStore) MarkRepoOrphaned(_ context.Context, shopID uuid.UUID, reason db.RepoOrphanReason) error {
+	s.orphaned[shopID] = reason
+	return nil
+}
+
+func (s *fakeRepoReconcileStore) ClearRepoOrphaned(_ context.Context, shopID uuid.UUID) error {
+	s.cleared = append(s.cleared, shopID)
+	return nil
+}
+
+type fakeRepoResolver struct {
+	fullName string
+	err      error
+}
+
+func (r fakeRepoResolver) GetRepositoryFullName(context.Context, int64) (string, error) {
+	return r.fullName, r.err
+}
+
+func TestRepoReconciliationService_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	notFound := fmt.Errorf("failed to get repository: %w", &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}})
+	tests := []struct {
+		name        string
+		resolver    fakeRepoResolver
+		wantResult  RepoReconcileResult
+		wantRename  string
+		wantOrphan  db.RepoOrphanReason
+		wantCleared bool
+	}{
+		{
+			name:        "unchanged",
+			resolver:    fakeRepoResolver{fullName: "octo/shop"},
+			wantResult:  RepoReconcileResult{Checked: 1},
+			wantCleared: true,
+		},
+		{
+			name:        "renamed",
+			resolver:    fakeRepoResolver{fullName: "octo/store"},
+			wantResult:  RepoReconcileResult{Checked: 1, Renamed: 1},
+			wantRename:  "octo/store",
+			wantCleared: true,
+		},
+		{
+			name:       "repository gone",
+			resolver:   fakeRepoResolver{err: notFound},
+			wantResult: RepoReconcileResult{Checked: 1, Orphaned: 1},
+			wantOrphan: db.RepoOrphanNotFound,
+		},
+		{
+			name:       "installation gone",
+			resolver:   fakeRepoResolver{err: fmt.Errorf("%w: GitHub API returned status 404", githubapp.ErrInstallationUnavailable)},
+			wantResult: RepoReconcileResult{Checked: 1, Orphaned: 1},
+			wantOrphan: db.RepoOrphanInstallationUnavailable,
+		},
+		{
+			name:       "transient error",
+			resolver:   fakeRepoResolver{err: errors.New("connection reset")},
+			wantResult: RepoReconcileResult{Checked: 1, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shop := &db.Shop{ID: uuid.New(), GitHubInstallationID: 7, GitHubRepoID: 42, GitHubRepoFullName: "octo/shop"}
+			store := &fakeRepoReconcileStore{
+				shops:    []*db.Shop{shop},
+				renamed:  map[uuid.UUID]string{},
+				orphaned: map[uuid.UUID]db.RepoOrphanReason{},
+			}
+			service := newRepoReconciliationService(store, func(int64) repoResolver { return tt.resolver }, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			result, err := service.Reconcile(context.Background())
+			if err != nil {
+				t.Fatalf("Reconcile returned error: %v", err)
+			}
+			if result != tt.wantResult {
+				t.Fatalf("result = %+v, want %+v", result, tt.wantResult)
+			}
+			if got := store.renamed[shop.ID]; got != tt.wantRename {
+				t.Fatalf("renamed to %q, want %q", got, tt.wantRename)
+			}
+			if got := store.orphaned[shop.ID]; got != tt.wantOrphan {
+				t.Fatalf("orphan reason = %q, want %q", got, tt.wantOrphan)
+			}
+			if got := len(store.cleared) == 1; got != tt.wantCleared {
+				t.Fatalf("cleared = %v, want %v", got, tt.wantCleared)
+			}
+		})
+	}
+}
+
+func TestNextRepoReconcile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "before the hour",
+			now:  time.Date(2026, 10, 16, 1, 30, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "on the hour",
+			now:  time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "other time zone",
+			now:  time.Date(2026, 10, 16, 23, 0, 0, 0, time.FixedZone("PDT", -7*60*60)),
+			want: time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := nextRepoReconcile(tt.now); !got.Equal(tt.want) {
+				t.Fatalf("nextRepoReconcile(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
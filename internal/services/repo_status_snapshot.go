@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	// repoStatusFreshFor is how long a snapshot is shown without refreshing it in the background.
+	repoStatusFreshFor     = 5 * time.Minute
+	repoStatusSnapshotTTL  = 24 * time.Hour
+	repoStatusRefreshQueue = 64
+	repoStatusRefreshers   = 2
+	repoStatusBuildTimeout = 30 * time.Second
+)
+
+// RepoStatusSnapshot is the last storefront status computed for a shop.
+type RepoStatusSnapshot struct {
+	Status    *RepoStatus `json:"status"`
+	CheckedAt time.Time   `json:"checked_at"`
+	// Refreshing is set while a newer status is being computed in the background.
+	Refreshing bool `json:"-"`
+}
+
+// CheckedLabel describes how old the snapshot is, such as "5m ago".
+func (s RepoStatusSnapshot) CheckedLabel() string {
+	return humanizeSince(s.CheckedAt)
+}
+
+type repoStatusBuilder interface {
+	BuildRepoStatus(ctx context.Context, shop *db.Shop) *RepoStatus
+}
+
+// RepoStatusService serves storefront status from cached snapshots so the dashboard does not wait
+// on GitHub, and refreshes stale snapshots on a background worker.
+type RepoStatusService struct {
+	builder       repoStatusBuilder
+	cacheProvider cache.Provider
+	queue         chan *db.Shop
+	mu            sync.Mutex
+	pending       map[uuid.UUID]bool
+	logger        *slog.Logger
+}
+
+func NewRepoStatusService(builder *AdminService, cacheProvider cache.Provider, logger *slog.Logger) *RepoStatusService {
+	return newRepoStatusService(builder, cacheProvider, logger)
+}
+
+func newRepoStatusService(builder repoStatusBuilder, cacheProvider cache.Provider, logger *slog.Logger) *RepoStatusService {
+	return &RepoStatusService{
+		builder:       builder,
+		cacheProvider: cacheProvider,
+		queue:         make(chan *db.Shop, repoStatusRefreshQueue),
+		pending:       make(map[uuid.UUID]bool),
+		logger:        logger,
+	}
+}
+
+func repoStatusSnapshotKey(shopID uuid.UUID) string {
+	return "repo_status:" + shopID.String()
+}
+
+// Snapshot returns the shop's last storefront status. The first request for a shop computes it
+// inline; after that a stale snapshot, or one the seller asked to refresh, is returned at once
+// while a refresh is queued.
+func (s *RepoStatusService) Snapshot(ctx context.Context, shop *db.Shop, refresh bool) RepoStatusSnapshot {
+	if shop == nil {
+		return RepoStatusSnapshot{}
+	}
+	meter := observability.MeterFromContext(ctx)
+
+	snapshot, ok := s.load(ctx, shop.ID)
+	if !ok {
+		meter.Count("admin.repo_status.snapshot_miss", 1)
+		return s.rebuild(ctx, shop)
+	}
+	if refresh || time.Since(snapshot.CheckedAt) > repoStatusFreshFor {
+		snapshot.Refreshing = s.enqueue(shop)
+		return snapshot
+	}
+	snapshot.Refreshing = s.isPending(shop.ID)
+	return snapshot
+}
+
+// Cached returns the shop's last storefront status without ever checking GitHub inline, and
+// queues a refresh when there is none or it is stale. It suits public requests that must not cost
+// GitHub API calls.
+func (s *RepoStatusService) Cached(ctx context.Context, shop *db.Shop) (RepoStatusSnapshot, bool) {
+	if s == nil || shop == nil {
+		return RepoStatusSnapshot{}, false
+	}
+	snapshot, ok := s.load(ctx, shop.ID)
+	if !ok || time.Since(snapshot.CheckedAt) > repoStatusFreshFor {
+		snapshot.Refreshing = s.enqueue(shop)
+	}
+	return snapshot, ok
+}
+
+// Invalidate drops the shop's snapshot so the next dashboard load checks the repository again.
+func (s *RepoStatusService) Invalidate(ctx context.Context, shopID uuid.UUID) {
+	if s == nil || s.cacheProvider == nil {
+		return
+	}
+	if err := s.cacheProvider.Delete(ctx, repoStatusSnapshotKey(shopID)); err != nil {
+		logging.FromContext(ctx, s.logger).Warn("failed to drop repo status snapshot", "error", err, "shop_id", shopID)
+	}
+}
+
+// Run refreshes queued shops until ctx is cancelled.
+func (s *RepoStatusService) Run(ctx context.Context) {
+	var workers sync.WaitGroup
+	for range repoStatusRefreshers {
+		workers.Go(func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case shop := <-s.queue:
+					buildCtx, cancel := context.WithTimeout(ctx, repoStatusBuildTimeout)
+					s.rebuild(buildCtx, shop)
+					cancel()
+					s.finish(shop.ID)
+				}
+			}
+		})
+	}
+	workers.Wait()
+}
+
+func (s *RepoStatusService) rebuild(ctx context.Context, shop *db.Shop) RepoStatusSnapshot {
+	snapshot := RepoStatusSnapshot{Status: s.builder.BuildRepoStatus(ctx, shop), CheckedAt: time.Now()}
+	if snapshot.Status == nil || ctx.Err() != nil || s.cacheProvider == nil {
+		return snapshot
+	}
+	encoded, err := json.Marshal(snapshot)
+	if err == nil {
+		err = s.cacheProvider.Set(ctx, repoStatusSnapshotKey(shop.ID), string(encoded), repoStatusSnapshotTTL)
+	}
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Warn("failed to store repo status snapshot", "error", err, "shop_id", shop.ID)
+	}
+	return snapshot
+}
+
+func (s *RepoStatusService) load(ctx context.Context, shopID uuid.UUID) (RepoStatusSnapshot, bool) {
+	if s.cacheProvider == nil {
+		return RepoStatusSnapshot{}, false
+	}
+	raw, err := s.cacheProvider.Get(ctx, repoStatusSnapshotKey(shopID))
+	if err != nil || raw == "" {
+		return RepoStatusSnapshot{}, false
+	}
+	var snapshot RepoStatusSnapshot
+	if err := json.Unmarshal([]byte(raw), &snapshot); err != nil || snapshot.Status == nil {
+		return RepoStatusSnapshot{}, false
+	}
+	return snapshot, true
+}
+
+// enqueue queues a refresh unless one is already pending, and reports whether one is pending.
+func (s *RepoStatusService) enqueue(shop *db.Shop) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[shop.ID] {
+		return true
+	}
+	select {
+	case s.queue <- shop:
+		s.pending[shop.ID] = true
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *RepoStatusService) isPending(shopID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending[shopID]
+}
+
+func (s *RepoStatusService) finish(shopID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, shopID)
+}
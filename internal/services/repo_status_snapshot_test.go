@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+type countingStatusBuilder struct {
+	calls atomic.Int32
+}
+
+func (b *countingStatusBuilder) BuildRepoStatus(context.Context, *db.Shop) *RepoStatus {
+	b.calls.Add(1)
+	return &RepoStatus{YAMLExists: true, YAMLValid: true}
+}
+
+func TestRepoStatusService_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		seedAge        time.Duration
+		seed           bool
+		refresh        bool
+		wantBuilds     int32
+		wantRefreshing bool
+	}{
+		{name: "missing snapshot is built inline", wantBuilds: 1},
+		{name: "fresh snapshot is served as is", seed: true, seedAge: time.Minute},
+		{name: "stale snapshot queues a refresh", seed: true, seedAge: 10 * time.Minute, wantRefreshing: true},
+		{name: "refresh request queues a refresh", seed: true, seedAge: time.Minute, refresh: true, wantRefreshing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := cache.NewMemoryProvider()
+			if err != nil {
+				t.Fatalf("failed to create cache: %v", err)
+			}
+			shop := &db.Shop{ID: uuid.New()}
+			checkedAt := time.Now().Add(-tt.seedAge)
+			if tt.seed {
+				encoded, err := json.Marshal(RepoStatusSnapshot{Status: &RepoStatus{YAMLExists: true}, CheckedAt: checkedAt})
+				if err != nil {
+					t.Fatalf("failed to encode snapshot: %v", err)
+				}
+				if err := provider.Set(t.Context(), repoStatusSnapshotKey(shop.ID), string(encoded), repoStatusSnapshotTTL); err != nil {
+					t.Fatalf("failed to seed cache: %v", err)
+				}
+			}
+
+			builder := &countingStatusBuilder{}
+			service := newRepoStatusService(builder, provider, slog.Default())
+			snapshot := service.Snapshot(t.Context(), shop, tt.refresh)
+
+			if snapshot.Status == nil {
+				t.Fatal("expected a status")
+			}
+			if got := builder.calls.Load(); got != tt.wantBuilds {
+				t.Fatalf("builds = %d, want %d", got, tt.wantBuilds)
+			}
+			if snapshot.Refreshing != tt.wantRefreshing {
+				t.Fatalf("refreshing = %v, want %v", snapshot.Refreshing, tt.wantRefreshing)
+			}
+			if got := len(service.queue); got != 0 && !tt.wantRefreshing {
+				t.Fatalf("queued %d refreshes, want none", got)
+			}
+		})
+	}
+}
+
+func TestRepoStatusService_CachedNeverBuildsInline(t *testing.T) {
+	t.Parallel()
+
+	provider, err := cache.NewMemoryProvider()
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	builder := &countingStatusBuilder{}
+	service := newRepoStatusService(builder, provider, slog.Default())
+
+	snapshot, ok := service.Cached(t.Context(), &db.Shop{ID: uuid.New()})
+	if ok || snapshot.Status != nil {
+		t.Fatalf("expected no cached snapshot, got %+v", snapshot)
+	}
+	if got := builder.calls.Load(); got != 0 {
+		t.Fatalf("builds = %d, want 0", got)
+	}
+	if !snapshot.Refreshing || len(service.queue) != 1 {
+		t.Fatalf("expected a queued refresh, refreshing = %v, queued = %d", snapshot.Refreshing, len(service.queue))
+	}
+}
+
+func TestRepoStatusService_RunRefreshesQueuedShops(t *testing.T) {
+	t.Parallel()
+
+	provider, err := cache.NewMemoryProvider()
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	builder := &countingStatusBuilder{}
+	service := newRepoStatusService(builder, provider, slog.Default())
+	shop := &db.Shop{ID: uuid.New()}
+
+	service.Snapshot(t.Context(), shop, false)
+	// Repeated refresh requests collapse into one queued rebuild.
+	for range 3 {
+		if snapshot := service.Snapshot(t.Context(), shop, true); !snapshot.Refreshing {
+			t.Fatal("expected refresh to be pending")
+		}
+	}
+	if got := len(service.queue); got != 1 {
+		t.Fatalf("queued %d refreshes, want 1", got)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for service.isPending(shop.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("refresh did not complete")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if got := builder.calls.Load(); got != 2 {
+		t.Fatalf("builds = %d, want 2", got)
+	}
+	if snapshot := service.Snapshot(t.Context(), shop, false); snapshot.Refreshing {
+		t.Fatal("expected no pending refresh after Run")
+	}
+
+	service.Invalidate(t.Context(), shop.ID)
+	service.Snapshot(t.Context(), shop, false)
+	if got := builder.calls.Load(); got != 3 {
+		t.Fatalf("builds after invalidate = %d, want 3", got)
+	}
+}
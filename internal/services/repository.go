@@ -2,23 +2,33 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/getsentry/sentry-go/attribute"
+	"github.com/jackc/pgx/v5"
 
+	"github.com/gitshopapp/gitshop/internal/backpressure"
+	"github.com/gitshopapp/gitshop/internal/catalog"
 	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
 	"github.com/gitshopapp/gitshop/internal/logging"
 	"github.com/gitshopapp/gitshop/internal/observability"
 )
 
 type RepositoryService struct {
-	shopStore *db.ShopStore
-	logger    *slog.Logger
+	shopStore       *db.ShopStore
+	githubClient    *githubapp.Client
+	statusSnapshots *RepoStatusService
+	logger          *slog.Logger
 }
 
-func NewRepositoryService(shopStore *db.ShopStore, logger *slog.Logger) *RepositoryService {
-	return &RepositoryService{shopStore: shopStore, logger: logger}
+func NewRepositoryService(shopStore *db.ShopStore, githubClient *githubapp.Client, statusSnapshots *RepoStatusService, logger *slog.Logger) *RepositoryService {
+	return &RepositoryService{shopStore: shopStore, githubClient: githubClient, statusSnapshots: statusSnapshots, logger: logger}
 }
 
 func (s *RepositoryService) loggerFromContext(ctx context.Context) *slog.Logger {
@@ -28,12 +38,17 @@ func (s *RepositoryService) loggerFromContext(ctx context.Context) *slog.Logger
 type PushEventInput struct {
 	RepoID       int64
 	RepoFullName string
-	Commits      []PushCommitInput
+	// Ref is the pushed ref, such as refs/heads/main; only pushes to DefaultBranch are checked
+	// for configuration drift.
+	Ref           string
+	DefaultBranch string
+	Commits       []PushCommitInput
 }
 
 type PushCommitInput struct {
 	Added    []string
 	Modified []string
+	Removed  []string
 }
 
 func (s *RepositoryService) HandlePushEvent(ctx context.Context, event PushEventInput) error {
@@ -72,29 +87,198 @@ func (s *RepositoryService) HandlePushEvent(ctx context.Context, event PushEvent
 		meter.Count("repository.event.ignored", 1, sentry.WithAttributes(attribute.String("reason", "shop_not_found")))
 		return nil
 	}
+	// Any commit can touch gitshop.yaml or an issue template, so the dashboard checks again.
+	s.statusSnapshots.Invalidate(ctx, shop.ID)
 
-	gitshopYamlModified := false
+	configPath := ""
+	configTouched := false
 	for _, commit := range event.Commits {
 		for _, f := range append(commit.Added, commit.Modified...) {
-			if f == "gitshop.yaml" || f == "gitshop.yml" {
-				gitshopYamlModified = true
-				break
+			if configPath == "" && (f == "gitshop.yaml" || f == "gitshop.yml") {
+				configPath = f
 			}
 		}
-		if gitshopYamlModified {
-			break
+		for _, f := range slices.Concat(commit.Added, commit.Modified, commit.Removed) {
+			configTouched = configTouched || touchesShopConfig(f)
 		}
 	}
 
-	if !gitshopYamlModified {
+	if !configTouched {
 		meter.Count("repository.event.ignored", 1, sentry.WithAttributes(attribute.String("reason", "gitshop_config_unchanged")))
 		return nil
 	}
 
+	// Shops still in setup see problems on the setup page instead of a tracking issue.
+	if event.Ref == "refs/heads/"+event.DefaultBranch && shop.IsOnboarded() {
+		if err := s.checkConfigDrift(ctx, shop); err != nil {
+			recordFailed("config_drift_check_failed")
+			s.loggerFromContext(ctx).Warn("failed to check configuration after push", "error", err, "repo", event.RepoFullName)
+		}
+	}
+
+	if configPath == "" {
+		meter.Count("repository.event.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
+	}
+
 	s.loggerFromContext(ctx).Info("gitshop.yaml modified, skipping template sync (manual setup)", "repo", event.RepoFullName)
+	if backpressure.ShouldShed(ctx) {
+		meter.Count("repository.config_upgrade.skipped", 1)
+	} else if err := s.suggestConfigUpgrade(ctx, shop, configPath); err != nil {
+		s.loggerFromContext(ctx).Warn("failed to suggest gitshop.yaml upgrade", "error", err, "repo", event.RepoFullName)
+	}
 	meter.Count("repository.event.processed", 1)
 	span.SetData("repository.repo_id", event.RepoID)
 	span.SetData("repository.repo_full_name", event.RepoFullName)
 	span.Status = sentry.SpanStatusOK
 	return nil
 }
+
+type RepositoryEventInput struct {
+	Action         string
+	InstallationID int64
+	RepoID         int64
+	RepoFullName   string
+}
+
+// HandleRepositoryEvent keeps the shop's repository name current when the repository is renamed
+// or transferred. A repository that moved to an account under another installation is flagged
+// for an operator to review instead of moving the shop with it.
+func (s *RepositoryService) HandleRepositoryEvent(ctx context.Context, event RepositoryEventInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.repository.handle_repository_event",
+		sentry.WithOpName("service.repository"),
+		sentry.WithDescription("HandleRepositoryEvent"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	meter := observability.MeterFromContext(ctx)
+	meter.SetAttributes(
+		attribute.String("event", "repository"),
+		attribute.String("action", event.Action),
+		attribute.String("service", "repository"),
+	)
+	meter.Count("repository.event.received", 1)
+	recordFailed := func(reason string) {
+		meter.Count("repository.event.failed", 1, sentry.WithAttributes(attribute.String("reason", reason)))
+	}
+	logger := s.loggerFromContext(ctx)
+
+	if event.Action != "renamed" && event.Action != "transferred" {
+		meter.Count("repository.event.ignored", 1, sentry.WithAttributes(attribute.String("reason", "unhandled_action")))
+		return nil
+	}
+
+	shop, err := s.shopStore.GetByRepoID(ctx, event.RepoID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && shop == nil) {
+		meter.Count("repository.event.ignored", 1, sentry.WithAttributes(attribute.String("reason", "shop_not_found")))
+		return nil
+	}
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("failed to find shop for repo: %w", err)
+	}
+
+	if shop.GitHubInstallationID != event.InstallationID {
+		logger.Warn("repository transferred to another installation, flagging shop for review",
+			"shop_id", shop.ID,
+			"repo", event.RepoFullName,
+			"installation_id", shop.GitHubInstallationID,
+			"new_installation_id", event.InstallationID)
+		if err := s.shopStore.MarkRepoOrphaned(ctx, shop.ID, db.RepoOrphanTransferred); err != nil {
+			recordFailed("orphan_flag_failed")
+			return fmt.Errorf("failed to flag shop: %w", err)
+		}
+		meter.Count("repository.event.processed", 1)
+		span.Status = sentry.SpanStatusOK
+		return nil
+	}
+
+	if event.RepoFullName != "" && shop.GitHubRepoFullName != event.RepoFullName {
+		logger.Info("repository name changed, updating shop",
+			"shop_id", shop.ID,
+			"old_name", shop.GitHubRepoFullName,
+			"new_name", event.RepoFullName)
+		if err := s.shopStore.UpdateRepoFullName(ctx, shop.ID, event.RepoFullName); err != nil {
+			recordFailed("repo_name_update_failed")
+			return fmt.Errorf("failed to update repo full name: %w", err)
+		}
+	}
+	if err := s.shopStore.ClearRepoOrphaned(ctx, shop.ID); err != nil {
+		logger.Warn("failed to clear shop review flag", "error", err, "shop_id", shop.ID)
+	}
+	s.statusSnapshots.Invalidate(ctx, shop.ID)
+
+	meter.Count("repository.event.processed", 1)
+	span.SetData("repository.repo_id", event.RepoID)
+	span.SetData("repository.repo_full_name", event.RepoFullName)
+	span.Status = sentry.SpanStatusOK
+	return nil
+}
+
+// suggestConfigUpgrade opens a pull request migrating gitshop.yaml to the current schema version.
+// Each version is suggested at most once per shop, so closing the PR dismisses it.
+func (s *RepositoryService) suggestConfigUpgrade(ctx context.Context, shop *db.Shop, path string) error {
+	if s.githubClient == nil {
+		return nil
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	content, err := client.GetFile(ctx, shop.GitHubRepoFullName, path, "")
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	config, err := catalog.NewParser().Parse(content)
+	if err != nil || !config.NeedsUpgrade() {
+		return nil
+	}
+	upgraded, changed, err := catalog.UpgradeContent(content)
+	if err != nil || !changed {
+		return err
+	}
+
+	// Without Contents write access the pull request cannot be opened; the setup page still
+	// lists the upgrade notes.
+	if !contentAccess(ctx, client, s.logger).CanOpenPullRequests() {
+		observability.MeterFromContext(ctx).Count("repository.config_upgrade.skipped", 1)
+		return nil
+	}
+
+	claimed, err := s.shopStore.ClaimConfigUpgradeSuggestion(ctx, shop.ID, catalog.CurrentConfigVersion)
+	if err != nil {
+		return fmt.Errorf("failed to record upgrade suggestion: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+
+	owner, repo, err := splitRepoFullName(shop.GitHubRepoFullName)
+	if err != nil {
+		return err
+	}
+	var body strings.Builder
+	body.WriteString("Your `" + path + "` uses an older GitShop format. GitShop still reads it, but this PR updates it so future changes are detected:\n\n")
+	for _, note := range catalog.UpgradeNotes(config.SourceVersion) {
+		body.WriteString("- " + note + "\n")
+	}
+	body.WriteString("\nReview the diff before merging; formatting may change slightly. Closing this PR will not reopen it.")
+
+	pr, err := client.CreatePullRequestWithFiles(ctx, owner, repo, githubapp.PullRequestInput{
+		BranchName: fmt.Sprintf("gitshop/upgrade-config-v%d", catalog.CurrentConfigVersion),
+		Message:    fmt.Sprintf("Upgrade %s to version %d", path, catalog.CurrentConfigVersion),
+		Title:      fmt.Sprintf("Upgrade %s to GitShop config version %d", path, catalog.CurrentConfigVersion),
+		Body:       body.String(),
+		Files:      []githubapp.FileChange{{Path: path, Content: string(upgraded)}},
+	})
+	if err != nil {
+		return err
+	}
+
+	observability.MeterFromContext(ctx).Count("repository.config_upgrade.suggested", 1)
+	s.loggerFromContext(ctx).Info("suggested gitshop.yaml upgrade", "repo", shop.GitHubRepoFullName, "pr_url", pr.URL, "from_version", config.SourceVersion)
+	return nil
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/money"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// Each shop is checked about once a day. Claims are retried a little early so the check does not
+// drift later by an interval every day.
+const (
+	shippingReminderInterval  = time.Hour
+	shippingReminderEvery     = 23 * time.Hour
+	shippingReminderBatchSize = 50
+	overdueOrdersListed       = 25
+)
+
+const overdueIssueTitle = "Paid orders waiting to ship"
+
+type shippingReminderShopStore interface {
+	ClaimShippingReminders(ctx context.Context, region string, checkedBefore, paidBefore time.Time, limit int) ([]db.ShippingReminder, error)
+	RecordShippingReminder(ctx context.Context, shopID uuid.UUID, shipWithinDays, overdueIssueNumber int) error
+	GetByID(ctx context.Context, shopID uuid.UUID) (*db.Shop, error)
+}
+
+type shippingReminderOrderStore interface {
+	ListOverdueOrders(ctx context.Context, shopID uuid.UUID, paidBefore time.Time, limit int) ([]*db.Order, error)
+	CountOverdueOrders(ctx context.Context, shopID uuid.UUID, paidBefore time.Time) (int, error)
+}
+
+type overdueIssueClient interface {
+	GetFile(ctx context.Context, repoFullName, path, ref string) ([]byte, error)
+	CreateIssueWithNumber(ctx context.Context, repoFullName string, title, body string, labels []string) (int, error)
+	UpdateIssue(ctx context.Context, repoFullName string, issueNumber int, title, body string) error
+	AssignIssue(ctx context.Context, repoFullName string, issueNumber int, assignees []string) error
+	CreateComment(ctx context.Context, repoFullName string, issueNumber int, body string) error
+	CloseIssue(ctx context.Context, repoFullName string, issueNumber int) error
+}
+
+// ShippingReminderService nudges sellers once a day about paid orders that have waited longer
+// than the shop's shipping window: a digest email and, when gitshop.yaml asks for it, an internal
+// issue assigned to the shop manager that is closed once everything has shipped.
+type ShippingReminderService struct {
+	shopStore   shippingReminderShopStore
+	orderStore  shippingReminderOrderStore
+	github      func(installationID int64) overdueIssueClient
+	emailSender OrderEmailSender
+	baseURL     string
+	region      string
+	logger      *slog.Logger
+}
+
+func NewShippingReminderService(shopStore *db.ShopStore, orderStore *db.OrderStore, githubClient *githubapp.Client, emailSender OrderEmailSender, baseURL, region string, logger *slog.Logger) *ShippingReminderService {
+	var github func(int64) overdueIssueClient
+	if githubClient != nil {
+		github = func(installationID int64) overdueIssueClient {
+			return githubClient.WithInstallation(installationID)
+		}
+	}
+	return newShippingReminderService(shopStore, orderStore, github, emailSender, baseURL, region, logger)
+}
+
+func newShippingReminderService(shopStore shippingReminderShopStore, orderStore shippingReminderOrderStore, github func(int64) overdueIssueClient, emailSender OrderEmailSender, baseURL, region string, logger *slog.Logger) *ShippingReminderService {
+	if emailSender == nil {
+		emailSender = noopOrderEmailSender{}
+	}
+	return &ShippingReminderService{
+		shopStore:   shopStore,
+		orderStore:  orderStore,
+		github:      github,
+		emailSender: emailSender,
+		baseURL:     baseURL,
+		region:      region,
+		logger:      logger,
+	}
+}
+
+// Run checks for due shops every hour until ctx is cancelled.
+func (s *ShippingReminderService) Run(ctx context.Context) {
+	if s == nil || s.shopStore == nil || s.github == nil {
+		return
+	}
+
+	ticker := time.NewTicker(shippingReminderInterval)
+	defer ticker.Stop()
+	for {
+		reminded, err := s.RemindDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to send shipping reminders", "error", err)
+		} else if reminded > 0 {
+			s.logger.Info("sent shipping reminders", "shops", reminded)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RemindDue checks every shop that is due and returns how many had overdue orders. A shop is due
+// once a day while it has a paid order older than a day, the shortest window gitshop.yaml
+// allows, or an overdue issue still open.
+func (s *ShippingReminderService) RemindDue(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		claims, err := s.shopStore.ClaimShippingReminders(ctx, s.region, now.Add(-shippingReminderEvery), now.Add(-24*time.Hour), shippingReminderBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("failed to claim shipping reminders: %w", err)
+		}
+		for _, claim := range claims {
+			if s.remind(ctx, claim, now) {
+				total++
+			}
+		}
+		if len(claims) < shippingReminderBatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// remind reads the shop's shipping window, emails the seller when orders are past it, and keeps
+// the overdue issue in step. It reports whether the shop had overdue orders.
+func (s *ShippingReminderService) remind(ctx context.Context, claim db.ShippingReminder, now time.Time) bool {
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("shipping.overdue.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	logger := s.logger.With("shop_id", claim.ShopID)
+
+	shop, err := s.shopStore.GetByID(ctx, claim.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		logger.Error("failed to load shop for shipping reminder", "error", err)
+		return false
+	}
+	client := s.github(shop.GitHubInstallationID)
+
+	// Without a readable gitshop.yaml the default window still applies, but an open issue is left
+	// alone because the seller's overdue_issue setting is unknown.
+	config, configOK := readShopConfig(ctx, client, shop.GitHubRepoFullName)
+	days := config.Shop.Shipping.ShipWithin()
+
+	var (
+		orders []*db.Order
+		count  int
+	)
+	if days > 0 {
+		paidBefore := now.Add(-time.Duration(days) * 24 * time.Hour)
+		count, err = s.orderStore.CountOverdueOrders(ctx, shop.ID, paidBefore)
+		if err == nil && count > 0 {
+			orders, err = s.orderStore.ListOverdueOrders(ctx, shop.ID, paidBefore, overdueOrdersListed)
+		}
+		if err != nil {
+			recordFailed("order_lookup_failed")
+			logger.Error("failed to list overdue orders", "error", err)
+			return false
+		}
+	}
+
+	if count > 0 {
+		recipient := strings.TrimSpace(config.Shop.Notifications.Email)
+		if recipient == "" {
+			recipient = strings.TrimSpace(shop.OwnerEmail)
+		}
+		if recipient != "" {
+			if err := s.emailSender.SendOverdueShipments(ctx, shop, OverdueShipmentsEmailInput{
+				Recipient:      recipient,
+				ShipWithinDays: days,
+				Total:          count,
+				Orders:         orders,
+				Now:            now,
+			}); err != nil {
+				recordFailed("email_failed")
+				logger.Warn("failed to email overdue shipments", "error", err)
+			}
+		}
+		meter.Count("shipping.overdue.reminded", 1)
+		meter.Count("shipping.overdue.orders", int64(count))
+	}
+
+	issueNumber := claim.OverdueIssueNumber
+	if configOK {
+		number, err := s.syncOverdueIssue(ctx, client, shop, config, issueNumber, overdueIssueBody(s.baseURL, days, count, orders, now))
+		if err != nil {
+			recordFailed("issue_failed")
+			logger.Warn("failed to update overdue issue", "error", err, "repo", shop.GitHubRepoFullName)
+		} else {
+			issueNumber = number
+		}
+	}
+
+	if err := s.shopStore.RecordShippingReminder(ctx, shop.ID, days, issueNumber); err != nil {
+		recordFailed("persist_failed")
+		logger.Error("failed to record shipping reminder", "error", err)
+	}
+	return count > 0
+}
+
+// syncOverdueIssue keeps one internal issue open while orders are overdue and the shop asks for
+// it, and closes it otherwise. body is empty when nothing is overdue. It returns the issue left
+// open, or 0.
+func (s *ShippingReminderService) syncOverdueIssue(ctx context.Context, client overdueIssueClient, shop *db.Shop, config *catalog.GitShopConfig, number int, body string) (int, error) {
+	if body == "" || !config.Shop.Shipping.OverdueIssue {
+		if number == 0 {
+			return 0, nil
+		}
+		if err := client.CreateComment(ctx, shop.GitHubRepoFullName, number, "✅ No paid orders are waiting past the shipping window. Closing this issue."); err != nil {
+			s.logger.Warn("failed to comment on overdue issue", "error", err, "repo", shop.GitHubRepoFullName, "issue", number)
+		}
+		if err := client.CloseIssue(ctx, shop.GitHubRepoFullName, number); err != nil {
+			return number, err
+		}
+		return 0, nil
+	}
+
+	if number != 0 {
+		return number, client.UpdateIssue(ctx, shop.GitHubRepoFullName, number, overdueIssueTitle, body)
+	}
+	number, err := client.CreateIssueWithNumber(ctx, shop.GitHubRepoFullName, overdueIssueTitle, body, []string{"gitshop-internal"})
+	if err != nil {
+		return 0, err
+	}
+	if manager := strings.TrimSpace(config.Shop.Manager); manager != "" && catalog.IsValidGitHubUsername(manager) {
+		if err := client.AssignIssue(ctx, shop.GitHubRepoFullName, number, []string{manager}); err != nil {
+			s.logger.Warn("failed to assign overdue issue", "error", err, "repo", shop.GitHubRepoFullName, "issue", number)
+		}
+	}
+	return number, nil
+}
+
+// readShopConfig reads gitshop.yaml for background jobs and reports whether it was valid. An
+// unreadable or invalid file yields an empty config, so defaults apply.
+func readShopConfig(ctx context.Context, files interface {
+	GetFile(ctx context.Context, repoFullName, path, ref string) ([]byte, error)
+}, repoFullName string) (*catalog.GitShopConfig, bool) {
+	for _, path := range []string{"gitshop.yaml", "gitshop.yml"} {
+		content, err := files.GetFile(ctx, repoFullName, path, "")
+		if err != nil {
+			continue
+		}
+		config, err := catalog.NewParser().Parse(content)
+		if err != nil || catalog.NewValidator().Validate(config) != nil {
+			return &catalog.GitShopConfig{}, false
+		}
+		return config, true
+	}
+	return &catalog.GitShopConfig{}, false
+}
+
+func overdueIssueBody(baseURL string, days, count int, orders []*db.Order, now time.Time) string {
+	if count == 0 {
+		return ""
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "📦 %d paid order(s) have waited more than %d day(s) to ship:\n\n", count, days)
+	for _, order := range orders {
+		waited := int(now.Sub(order.PaidAt) / (24 * time.Hour))
+		line := fmt.Sprintf("- %s, %s, paid %s (%d days ago)", commentOrderNumber(order.OrderNumber), money.Format(order.TotalCents), order.PaidAt.Format("Jan 2"), waited)
+		if order.GitHubIssueNumber > 0 {
+			line += fmt.Sprintf(" #%d", order.GitHubIssueNumber)
+		}
+		if url := shipOrderURL(baseURL, order); url != "" {
+			line += fmt.Sprintf(" · [ship](%s)", url)
+		}
+		body.WriteString(line + "\n")
+	}
+	if count > len(orders) {
+		fmt.Fprintf(&body, "- …and %d more\n", count-len(orders))
+	}
+	body.WriteString("\nGitShop updates this list once a day and closes the issue when every order has shipped. Change the window with `shipping.ship_within_days` in `gitshop.yaml`, or turn this issue off with `shipping.overdue_issue: false`.")
+	return body.String()
+}
+
+// OverdueShipments lists the shop's paid orders past its shipping window for the dashboard
+// banner. Orders holds the oldest of them; Total counts them all.
+type OverdueShipments struct {
+	ShipWithinDays int
+	Total          int
+	Orders         []*db.Order
+}
+
+// OverdueShipments returns the shop's overdue orders, or nil when none are overdue or reminders
+// are off. The window is the one last read from gitshop.yaml by the reminder job.
+func (s *AdminService) OverdueShipments(ctx context.Context, shopID uuid.UUID, now time.Time) (*OverdueShipments, error) {
+	if s == nil || s.shopStore == nil || s.orderStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	days, err := s.shopStore.GetShipWithinDays(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipping window: %w", err)
+	}
+	if days <= 0 {
+		return nil, nil
+	}
+
+	paidBefore := now.Add(-time.Duration(days) * 24 * time.Hour)
+	total, err := s.orderStore.CountOverdueOrders(ctx, shopID, paidBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count overdue orders: %w", err)
+	}
+	if total == 0 {
+		return nil, nil
+	}
+	orders, err := s.orderStore.ListOverdueOrders(ctx, shopID, paidBefore, 5)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overdue orders: %w", err)
+	}
+	return &OverdueShipments{ShipWithinDays: days, Total: total, Orders: orders}, nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+type fakeShippingReminderShopStore struct {
+	claims        []db.ShippingReminder
+	shop          *db.Shop
+	recordedDays  int
+	recordedIssue int
+}
+
+func (s *fakeShippingReminderShopStore) ClaimShippingReminders(context.Context, string, time.Time, time.Time, int) ([]db.ShippingReminder, error) {
+	claimed := s.claims
+	s.claims = nil
+	return claimed, nil
+}
+
+func (s *fakeShippingReminderShopStore) RecordShippingReminder(_ context.Context, _ uuid.UUID, shipWithinDays, overdueIssueNumber int) error {
+	s.recordedDays = shipWithinDays
+	s.recordedIssue = overdueIssueNumber
+	return nil
+}
+
+func (s *fakeShippingReminderShopStore) GetByID(context.Context, uuid.UUID) (*db.Shop, error) {
+	return s.shop, nil
+}
+
+type fakeShippingReminderOrderStore struct {
+	orders []*db.Order
+}
+
+func (s fakeShippingReminderOrderStore) overdue(paidBefore time.Time) []*db.Order {
+	overdue := []*db.Order{}
+	for _, order := range s.orders {
+		if order.PaidAt.Before(paidBefore) {
+			overdue = append(overdue, order)
+		}
+	}
+	return overdue
+}
+
+func (s fakeShippingReminderOrderStore) ListOverdueOrders(_ context.Context, _ uuid.UUID, paidBefore time.Time, _ int) ([]*db.Order, error) {
+	return s.overdue(paidBefore), nil
+}
+
+func (s fakeShippingReminderOrderStore) CountOverdueOrders(_ context.Context, _ uuid.UUID, paidBefore time.Time) (int, error) {
+	return len(s.overdue(paidBefore)), nil
+}
+
+type fakeOverdueIssueClient struct {
+	config    string
+	created   []string
+	updated   []string
+	assignees []string
+	comments  []string
+	closed    []int
+}
+
+func (c *fakeOverdueIssueClient) GetFile(_ context.Context, _, path, _ string) ([]byte, error) {
+	if path != "gitshop.yaml" || c.config == "" {
+		return nil, fmt.Errorf("%s not found", path)
+	}
+	return []byte(c.config), nil
+}
+
+func (c *fakeOverdueIssueClient) CreateIssueWithNumber(_ context.Context, _ string, _, body string, _ []string) (int, error) {
+	c.created = append(c.created, body)
+	return 9, nil
+}
+
+func (c *fakeOverdueIssueClient) UpdateIssue(_ context.Context, _ string, _ int, _, body string) error {
+	c.updated = append(c.updated, body)
+	return nil
+}
+
+func (c *fakeOverdueIssueClient) AssignIssue(_ context.Context, _ string, _ int, assignees []string) error {
+	c.assignees = append(c.assignees, assignees...)
+	return nil
+}
+
+func (c *fakeOverdueIssueClient) CreateComment(_ context.Context, _ string, _ int, body string) error {
+	c.comments = append(c.comments, body)
+	return nil
+}
+
+func (c *fakeOverdueIssueClient) CloseIssue(_ context.Context, _ string, number int) error {
+	c.closed = append(c.closed, number)
+	return nil
+}
+
+func shippingReminderYAML(shipping string) string {
+	return `shop:
+  name: "Test Shop"
+  currency: "usd"
+  manager: "octocat"
+  notifications:
+    email: "orders@example.com"
+  shipping:
+    flat_rate_cents: 500
+    carrier: "USPS"
+` + shipping + `
+products:
+  - sku: "COFFEE_V1"
+    name: "Coffee"
+    unit_price_cents: 1500
+    active: true
+`
+}
+
+func TestShippingReminderService_RemindDue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		config        string
+		openIssue     int
+		paidDaysAgo   []int
+		wantReminded  int
+		wantEmailTo   string
+		wantDays      int
+		wantIssue     int
+		wantCreated   bool
+		wantClosed    bool
+		wantAssignees []string
+	}{
+		{
+			name:         "default window without config emails the owner",
+			paidDaysAgo:  []int{6, 2},
+			wantReminded: 1,
+			wantEmailTo:  "owner@example.com",
+			wantDays:     5,
+		},
+		{
+			name:          "overdue issue assigned to the manager",
+			config:        shippingReminderYAML("    ship_within_days: 1\n    overdue_issue: true"),
+			paidDaysAgo:   []int{2},
+			wantReminded:  1,
+			wantEmailTo:   "orders@example.com",
+			wantDays:      1,
+			wantIssue:     9,
+			wantCreated:   true,
+			wantAssignees: []string{"octocat"},
+		},
+		{
+			name:        "issue closed once everything shipped",
+			config:      shippingReminderYAML("    overdue_issue: true"),
+			openIssue:   9,
+			paidDaysAgo: []int{2},
+			wantDays:    5,
+			wantClosed:  true,
+		},
+		{
+			name:        "zero turns reminders off",
+			config:      shippingReminderYAML("    ship_within_days: 0"),
+			paidDaysAgo: []int{30},
+			wantDays:    0,
+		},
+		{
+			name:         "unreadable config leaves an open issue alone",
+			openIssue:    9,
+			paidDaysAgo:  []int{6},
+			wantReminded: 1,
+			wantEmailTo:  "owner@example.com",
+			wantDays:     5,
+			wantIssue:    9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop", OwnerEmail: "owner@example.com", EmailProvider: "postmark"}
+			orders := fakeShippingReminderOrderStore{}
+			for i, days := range tt.paidDaysAgo {
+				orders.orders = append(orders.orders, &db.Order{ID: uuid.New(), ShopID: shop.ID, OrderNumber: i + 1, TotalCents: 2000, PaidAt: now.Add(-time.Duration(days) * 24 * time.Hour)})
+			}
+			shops := &fakeShippingReminderShopStore{
+				claims: []db.ShippingReminder{{ShopID: shop.ID, OverdueIssueNumber: tt.openIssue}},
+				shop:   shop,
+			}
+			client := &fakeOverdueIssueClient{config: tt.config}
+			provider := &capturingEmailProvider{}
+			sender := NewShopOrderEmailSender(func(*db.Shop) (email.Provider, error) {
+				return provider, nil
+			}, nil, "https://gitshop.example.com")
+			service := newShippingReminderService(shops, orders, func(int64) overdueIssueClient { return client }, sender, "https://gitshop.example.com", "", slog.Default())
+
+			reminded, err := service.RemindDue(t.Context(), now)
+			if err != nil {
+				t.Fatalf("RemindDue() error = %v", err)
+			}
+			if reminded != tt.wantReminded {
+				t.Fatalf("RemindDue() = %d, want %d", reminded, tt.wantReminded)
+			}
+			if tt.wantEmailTo == "" {
+				if len(provider.sent) != 0 {
+					t.Fatalf("emails sent = %d, want none", len(provider.sent))
+				}
+			} else {
+				if len(provider.sent) != 1 || provider.sent[0].To != tt.wantEmailTo {
+					t.Fatalf("emails = %+v, want one to %q", provider.sent, tt.wantEmailTo)
+				}
+				if !strings.Contains(provider.sent[0].Text, "/admin/orders/") {
+					t.Fatalf("digest is missing ship links: %q", provider.sent[0].Text)
+				}
+			}
+			if shops.recordedDays != tt.wantDays || shops.recordedIssue != tt.wantIssue {
+				t.Fatalf("recorded days=%d issue=%d, want days=%d issue=%d", shops.recordedDays, shops.recordedIssue, tt.wantDays, tt.wantIssue)
+			}
+			if (len(client.created) == 1) != tt.wantCreated {
+				t.Fatalf("issues created = %d, want created %v", len(client.created), tt.wantCreated)
+			}
+			if (len(client.closed) == 1) != tt.wantClosed {
+				t.Fatalf("issues closed = %v, want closed %v", client.closed, tt.wantClosed)
+			}
+			if strings.Join(client.assignees, ",") != strings.Join(tt.wantAssignees, ",") {
+				t.Fatalf("assignees = %v, want %v", client.assignees, tt.wantAssignees)
+			}
+		})
+	}
+}
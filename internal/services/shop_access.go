@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const shopAccessCacheTTL = 5 * time.Minute
+
+var repoRoleRank = map[string]int{
+	"read":                    1,
+	"triage":                  2,
+	catalog.AdminRoleWrite:    3,
+	catalog.AdminRoleMaintain: 4,
+	catalog.AdminRoleAdmin:    5,
+}
+
+// ShopAccess is a user's standing on a shop's repository.
+type ShopAccess struct {
+	Role         string `json:"role"`
+	RequiredRole string `json:"required_role"`
+	Allowed      bool   `json:"allowed"`
+}
+
+type repoAccessSource interface {
+	RepositoryRole(ctx context.Context, shop *db.Shop, username string) (string, error)
+	RequiredRole(ctx context.Context, shop *db.Shop) string
+}
+
+// ShopAccessService decides who may manage a shop: any GitHub user holding at least the role
+// set by shop.admin.min_role in gitshop.yaml on the shop repository.
+type ShopAccessService struct {
+	source        repoAccessSource
+	cacheProvider cache.Provider
+	logger        *slog.Logger
+}
+
+func NewShopAccessService(githubClient *githubapp.Client, parser configParser, validator configValidator, cacheProvider cache.Provider, logger *slog.Logger) *ShopAccessService {
+	return &ShopAccessService{
+		source:        githubRepoAccess{githubClient: githubClient, parser: parser, validator: validator},
+		cacheProvider: cacheProvider,
+		logger:        logger,
+	}
+}
+
+func shopAccessCacheKey(shop *db.Shop, username string) string {
+	return "shop_access:" + shop.ID.String() + ":" + strings.ToLower(username)
+}
+
+// Authorize looks up the user's role on the shop repository. Results are cached briefly, so
+// removing a collaborator on GitHub takes effect within a few minutes.
+func (s *ShopAccessService) Authorize(ctx context.Context, shop *db.Shop, username string) (ShopAccess, error) {
+	if s == nil || s.source == nil {
+		return ShopAccess{}, fmt.Errorf("%w: shop access unavailable", ErrAdminServiceUnavailable)
+	}
+	if shop == nil {
+		return ShopAccess{}, fmt.Errorf("%w: shop is required", ErrAdminShopNotFound)
+	}
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return ShopAccess{Role: "none", RequiredRole: catalog.AdminRoleWrite}, nil
+	}
+
+	logger := logging.FromContext(ctx, s.logger)
+	cacheKey := shopAccessCacheKey(shop, username)
+	if s.cacheProvider != nil {
+		if cached, err := s.cacheProvider.Get(ctx, cacheKey); err == nil && cached != "" {
+			var access ShopAccess
+			if err := json.Unmarshal([]byte(cached), &access); err == nil {
+				return access, nil
+			}
+		}
+	}
+
+	role, err := s.source.RepositoryRole(ctx, shop, username)
+	if err != nil {
+		return ShopAccess{}, fmt.Errorf("failed to check repository role: %w", err)
+	}
+	access := ShopAccess{Role: role, RequiredRole: s.source.RequiredRole(ctx, shop)}
+	access.Allowed = repoRoleRank[access.Role] >= repoRoleRank[access.RequiredRole]
+	if !access.Allowed {
+		observability.MeterFromContext(ctx).Count("admin.access.denied", 1)
+		logger.Info("denied dashboard access", "shop_id", shop.ID, "username", username, "role", access.Role, "required_role", access.RequiredRole)
+	}
+
+	if s.cacheProvider != nil {
+		if encoded, err := json.Marshal(access); err == nil {
+			if err := s.cacheProvider.Set(ctx, cacheKey, string(encoded), shopAccessCacheTTL); err != nil {
+				logger.Warn("failed to cache shop access", "error", err, "shop_id", shop.ID)
+			}
+		}
+	}
+	return access, nil
+}
+
+type githubRepoAccess struct {
+	githubClient *githubapp.Client
+	parser       configParser
+	validator    configValidator
+}
+
+func (g githubRepoAccess) RepositoryRole(ctx context.Context, shop *db.Shop, username string) (string, error) {
+	if g.githubClient == nil {
+		return "", fmt.Errorf("github client unavailable")
+	}
+	return g.githubClient.WithInstallation(shop.GitHubInstallationID).RepositoryRole(ctx, shop.GitHubRepoFullName, username)
+}
+
+// RequiredRole falls back to write when gitshop.yaml is missing or invalid. Anyone with write
+// access could edit the file to lower the role anyway, so this grants nothing new.
+func (g githubRepoAccess) RequiredRole(ctx context.Context, shop *db.Shop) string {
+	if g.githubClient == nil || g.parser == nil {
+		return catalog.AdminRoleWrite
+	}
+	client := g.githubClient.WithInstallation(shop.GitHubInstallationID)
+	content, err := client.GetFile(ctx, shop.GitHubRepoFullName, "gitshop.yaml", "")
+	if err != nil {
+		content, err = client.GetFile(ctx, shop.GitHubRepoFullName, "gitshop.yml", "")
+	}
+	if err != nil {
+		return catalog.AdminRoleWrite
+	}
+	config, err := g.parser.Parse(content)
+	if err != nil {
+		return catalog.AdminRoleWrite
+	}
+	if g.validator != nil && g.validator.Validate(config) != nil {
+		return catalog.AdminRoleWrite
+	}
+	return config.Shop.Admin.RequiredRole()
+}
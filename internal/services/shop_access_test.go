@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/cache"
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+type fakeRepoAccess struct {
+	roles        map[string]string
+	requiredRole string
+	lookups      int
+}
+
+func (f *fakeRepoAccess) RepositoryRole(_ context.Context, _ *db.Shop, username string) (string, error) {
+	f.lookups++
+	if role, ok := f.roles[username]; ok {
+		return role, nil
+	}
+	return "none", nil
+}
+
+func (f *fakeRepoAccess) RequiredRole(context.Context, *db.Shop) string {
+	return f.requiredRole
+}
+
+func TestShopAccessService_Authorize(t *testing.T) {
+	t.Parallel()
+
+	roles := map[string]string{"owner": "admin", "maintainer": "maintain", "writer": "write", "triager": "triage"}
+	tests := []struct {
+		name         string
+		requiredRole string
+		username     string
+		want         bool
+	}{
+		{name: "writer with default role", requiredRole: "write", username: "writer", want: true},
+		{name: "triage is never enough", requiredRole: "write", username: "triager", want: false},
+		{name: "outsider", requiredRole: "write", username: "stranger", want: false},
+		{name: "writer below maintain", requiredRole: "maintain", username: "writer", want: false},
+		{name: "maintainer at maintain", requiredRole: "maintain", username: "maintainer", want: true},
+		{name: "maintainer below admin", requiredRole: "admin", username: "maintainer", want: false},
+		{name: "admin", requiredRole: "admin", username: "owner", want: true},
+		{name: "no username", requiredRole: "write", username: " ", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &ShopAccessService{source: &fakeRepoAccess{roles: roles, requiredRole: tt.requiredRole}}
+			access, err := service.Authorize(t.Context(), &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}, tt.username)
+			if err != nil {
+				t.Fatalf("Authorize: %v", err)
+			}
+			if access.Allowed != tt.want {
+				t.Fatalf("expected allowed=%v, got %+v", tt.want, access)
+			}
+		})
+	}
+}
+
+func TestShopAccessService_CachesDecisions(t *testing.T) {
+	t.Parallel()
+
+	memory, err := cache.NewMemoryProvider()
+	if err != nil {
+		t.Fatalf("NewMemoryProvider: %v", err)
+	}
+	source := &fakeRepoAccess{roles: map[string]string{"writer": "write"}, requiredRole: "write"}
+	service := &ShopAccessService{source: source, cacheProvider: memory}
+	shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop"}
+
+	for range 3 {
+		access, err := service.Authorize(t.Context(), shop, "writer")
+		if err != nil {
+			t.Fatalf("Authorize: %v", err)
+		}
+		if !access.Allowed {
+			t.Fatalf("expected access, got %+v", access)
+		}
+	}
+	if source.lookups != 1 {
+		t.Fatalf("expected one role lookup, got %d", source.lookups)
+	}
+}
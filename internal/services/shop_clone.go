@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+)
+
+type CloneShopInput struct {
+	InstallationID int64
+	SourceShopID   uuid.UUID
+	TargetShopID   uuid.UUID
+}
+
+type CloneShopResult struct {
+	PullRequestURL string
+	FilesCopied    int
+	LabelsCopied   int
+	SettingsCopied bool
+}
+
+// GetCloneTargets lists the other connected shops in the installation that a shop can be cloned into.
+func (s *AdminService) GetCloneTargets(ctx context.Context, shop *db.Shop) ([]*db.Shop, error) {
+	if shop == nil {
+		return []*db.Shop{}, nil
+	}
+
+	shops, err := s.GetInstallationShops(ctx, shop.GitHubInstallationID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]*db.Shop, 0, len(shops))
+	for _, candidate := range shops {
+		if candidate == nil || candidate.ID == shop.ID {
+			continue
+		}
+		targets = append(targets, candidate)
+	}
+	return targets, nil
+}
+
+// CloneShop copies the storefront configuration of one shop into another repo in the same
+// installation. Repo files land as a single pull request; labels and non-secret settings are
+// applied directly since they do not live in the repository.
+func (s *AdminService) CloneShop(ctx context.Context, input CloneShopInput) (*CloneShopResult, error) {
+	if s == nil || s.githubClient == nil || s.shopStore == nil {
+		return nil, fmt.Errorf("%w: github client unavailable", ErrAdminServiceUnavailable)
+	}
+	if input.SourceShopID == input.TargetShopID {
+		return nil, UserError{Message: "Choose a different shop to clone into"}
+	}
+
+	source, err := s.GetShopForInstallation(ctx, input.InstallationID, input.SourceShopID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.GetShopForInstallation(ctx, input.InstallationID, input.TargetShopID)
+	if err != nil {
+		return nil, err
+	}
+	if !target.IsConnected() {
+		return nil, UserError{Message: "The target repository is no longer connected"}
+	}
+
+	client := s.githubClient.WithInstallation(input.InstallationID)
+	access := contentAccess(ctx, client, s.logger)
+	if !access.CanOpenPullRequests() {
+		return nil, UserError{Message: "Cloning opens a pull request, which needs Contents write access. GitShop only has single-file access to these repositories."}
+	}
+
+	files, err := s.collectCloneFiles(ctx, client, source.GitHubRepoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, repo, err := splitRepoFullName(target.GitHubRepoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CloneShopResult{FilesCopied: len(files)}
+
+	prBody := fmt.Sprintf("This PR copies the GitShop storefront configuration from `%s`.\n\nReview product names, prices, and shop settings in `gitshop.yaml` before merging.", source.GitHubRepoFullName)
+	pr, err := client.CreatePullRequestWithFiles(ctx, owner, repo, githubapp.PullRequestInput{
+		BranchName: fmt.Sprintf("gitshop/clone-%s", time.Now().UTC().Format("20060102150405")),
+		Message:    "Clone GitShop configuration from " + source.GitHubRepoFullName,
+		Title:      "Clone GitShop storefront from " + source.GitHubRepoFullName,
+		Body:       prBody,
+		Files:      files,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.PullRequestURL = pr.URL
+
+	labels, err := cloneLabelDefinitions(ctx, client, source.GitHubRepoFullName)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.EnsureLabels(ctx, target.GitHubRepoFullName, labels); err != nil {
+		return nil, fmt.Errorf("failed to copy labels: %w", err)
+	}
+	result.LabelsCopied = len(labels)
+
+	if target.EmailProvider == "" && source.EmailProvider != "" {
+		if err := s.shopStore.UpdateEmailConfig(ctx, target.ID, source.EmailProvider, withoutEmailSecrets(source.EmailConfig), false); err != nil {
+			return nil, fmt.Errorf("failed to copy email settings: %w", err)
+		}
+		result.SettingsCopied = true
+	}
+
+	s.loggerFromContext(ctx).Info("shop configuration cloned", "source_shop_id", source.ID, "target_shop_id", target.ID, "files", result.FilesCopied, "labels", result.LabelsCopied)
+
+	return result, nil
+}
+
+func (s *AdminService) collectCloneFiles(ctx context.Context, client *githubapp.Client, repoFullName string) ([]githubapp.FileChange, error) {
+	_, yamlPath, err := s.getGitShopFileStatus(ctx, client, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+	yamlContent, err := s.getGitShopFile(ctx, client, repoFullName, yamlPath)
+	if err != nil {
+		return nil, UserError{Message: "The source shop has no gitshop.yaml to clone"}
+	}
+
+	files := []githubapp.FileChange{{Path: yamlPath, Content: string(yamlContent)}}
+
+	templates, err := client.ListDirectory(ctx, repoFullName, ".github/ISSUE_TEMPLATE")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range filterTemplateFiles(templates) {
+		content, readErr := client.GetFile(ctx, repoFullName, file.Path, "")
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", file.Path, readErr)
+		}
+		if !catalog.HasOrderTemplateMarker(string(content)) {
+			continue
+		}
+		files = append(files, githubapp.FileChange{Path: file.Path, Content: string(content)})
+	}
+
+	return files, nil
+}
+
+func cloneLabelDefinitions(ctx context.Context, client *githubapp.Client, repoFullName string) ([]githubapp.LabelDefinition, error) {
+	existing, err := client.ListLabels(ctx, repoFullName)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := shopLabels(shopConfig(ctx, client, repoFullName))
+	byName := make(map[string]githubapp.LabelDefinition)
+	for _, label := range RequiredRepoLabels(labels) {
+		byName[label.Name] = label
+	}
+	for name, label := range existing {
+		if !labels.Owns(name) {
+			continue
+		}
+		byName[name] = githubapp.LabelDefinition{
+			Name:        name,
+			Color:       label.GetColor(),
+			Description: label.GetDescription(),
+		}
+	}
+
+	definitions := make([]githubapp.LabelDefinition, 0, len(byName))
+	for _, label := range byName {
+		definitions = append(definitions, label)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions, nil
+}
+
+func withoutEmailSecrets(config map[string]any) map[string]any {
+	copied := make(map[string]any, len(config))
+	for key, value := range config {
+		if key == "api_key" || key == "webhook_secret" {
+			continue
+		}
+		copied[key] = value
+	}
+	return copied
+}
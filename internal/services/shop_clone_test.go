@@ -0,0 +1,40 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWithoutEmailSecrets(t *testing.T) {
+	t.Parallel()
+
+	config := map[string]any{
+		"api_key":    "secret",
+		"from_email": "shop@example.com",
+		"domain":     "mg.example.com",
+	}
+
+	copied := withoutEmailSecrets(config)
+	if _, ok := copied["api_key"]; ok {
+		t.Fatalf("expected api_key to be dropped, got %v", copied)
+	}
+	if copied["from_email"] != "shop@example.com" || copied["domain"] != "mg.example.com" {
+		t.Fatalf("expected non-secret settings to be kept, got %v", copied)
+	}
+	if config["api_key"] != "secret" {
+		t.Fatal("expected source config to be left untouched")
+	}
+}
+
+func TestAdminService_CloneShop_ServiceUnavailable(t *testing.T) {
+	t.Parallel()
+
+	service := &AdminService{}
+
+	_, err := service.CloneShop(t.Context(), CloneShopInput{SourceShopID: uuid.New(), TargetShopID: uuid.New()})
+	if !errors.Is(err, ErrAdminServiceUnavailable) {
+		t.Fatalf("expected ErrAdminServiceUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// ErrShopSuspended means the platform suspended the shop, so it takes no new orders or payments.
+var ErrShopSuspended = errors.New("shop is suspended")
+
+type shopSuspensionStore interface {
+	GetSuspension(ctx context.Context, shopID uuid.UUID) (*db.ShopSuspension, error)
+}
+
+// checkShopSuspension fails with ErrShopSuspended while the shop is suspended.
+func checkShopSuspension(ctx context.Context, store shopSuspensionStore, shop *db.Shop) error {
+	if store == nil || shop == nil {
+		return nil
+	}
+	suspension, err := store.GetSuspension(ctx, shop.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load shop suspension: %w", err)
+	}
+	if suspension.Suspended() {
+		return ErrShopSuspended
+	}
+	return nil
+}
+
+// ErrAdminSuspensionAppealInvalid means the appeal was empty, too long, or the shop is not
+// suspended.
+var ErrAdminSuspensionAppealInvalid = errors.New("invalid suspension appeal")
+
+const maxSuspensionAppealLength = 4000
+
+// ShopSuspension returns the shop's suspension, or nil when the shop is not suspended.
+func (s *AdminService) ShopSuspension(ctx context.Context, shopID uuid.UUID) (*db.ShopSuspension, error) {
+	if s == nil || s.shopStore == nil {
+		return nil, ErrAdminServiceUnavailable
+	}
+	suspension, err := s.shopStore.GetSuspension(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suspension: %w", err)
+	}
+	if !suspension.Suspended() {
+		return nil, nil
+	}
+	return suspension, nil
+}
+
+// AppealSuspension records the shop owner's appeal for operators to review. A later appeal
+// replaces the earlier one.
+func (s *AdminService) AppealSuspension(ctx context.Context, shopID uuid.UUID, appeal string) error {
+	if s == nil || s.shopStore == nil {
+		return ErrAdminServiceUnavailable
+	}
+	appeal = strings.TrimSpace(appeal)
+	if appeal == "" || len(appeal) > maxSuspensionAppealLength {
+		return ErrAdminSuspensionAppealInvalid
+	}
+	if err := s.shopStore.RecordSuspensionAppeal(ctx, shopID, appeal); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: shop is not suspended", ErrAdminSuspensionAppealInvalid)
+		}
+		return fmt.Errorf("failed to record appeal: %w", err)
+	}
+	observability.MeterFromContext(ctx).Count("shop.suspension.appealed", 1)
+	s.loggerFromContext(ctx).Info("shop owner appealed suspension", "shop_id", shopID)
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/i18n"
+)
+
+type fakeSuspensionStore struct {
+	suspension *db.ShopSuspension
+	err        error
+}
+
+func (f fakeSuspensionStore) GetSuspension(context.Context, uuid.UUID) (*db.ShopSuspension, error) {
+	return f.suspension, f.err
+}
+
+func TestCheckShopSuspension(t *testing.T) {
+	t.Parallel()
+
+	shop := &db.Shop{ID: uuid.New()}
+	suspendedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		store   shopSuspensionStore
+		wantErr error
+	}{
+		{name: "no store", store: nil},
+		{name: "not suspended", store: fakeSuspensionStore{suspension: &db.ShopSuspension{}}},
+		{name: "suspended", store: fakeSuspensionStore{suspension: &db.ShopSuspension{SuspendedAt: suspendedAt, Reason: "spam"}}, wantErr: ErrShopSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := checkShopSuspension(t.Context(), tt.store, shop); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkShopSuspension() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	lookupErr := errors.New("db down")
+	if err := checkShopSuspension(t.Context(), fakeSuspensionStore{err: lookupErr}, shop); !errors.Is(err, lookupErr) || errors.Is(err, ErrShopSuspended) {
+		t.Fatalf("expected lookup error to be returned, got %v", err)
+	}
+}
+
+func TestCheckoutFailedComment(t *testing.T) {
+	t.Parallel()
+
+	fallback := "Could not create checkout."
+	if got := checkoutFailedComment(i18n.Localizer{}, errors.New("stripe down"), fallback); got != fallback {
+		t.Fatalf("expected fallback comment, got %q", got)
+	}
+	got := checkoutFailedComment(i18n.Localizer{}, fmt.Errorf("checkout: %w", ErrShopSuspended), fallback)
+	if got == fallback || !strings.Contains(got, "isn't accepting orders") {
+		t.Fatalf("expected suspension comment, got %q", got)
+	}
+	got = checkoutFailedComment(i18n.Localizer{}, fmt.Errorf("checkout: %w", ErrSubscriptionsDisabled), fallback)
+	if got != (i18n.Localizer{}).T("comment.subscriptions_unavailable") {
+		t.Fatalf("expected subscriptions comment, got %q", got)
+	}
+}
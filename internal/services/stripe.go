@@ -8,40 +8,56 @@ import (
 	"log/slog"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/getsentry/sentry-go/attribute"
 	"github.com/google/uuid"
 	stripeapi "github.com/stripe/stripe-go/v84"
 
+	"github.com/gitshopapp/gitshop/internal/address"
 	"github.com/gitshopapp/gitshop/internal/catalog"
 	"github.com/gitshopapp/gitshop/internal/db"
 	"github.com/gitshopapp/gitshop/internal/githubapp"
 	"github.com/gitshopapp/gitshop/internal/logging"
 	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/stripe"
 )
 
 type StripeService struct {
-	shopStore    *db.ShopStore
-	orderStore   *db.OrderStore
-	githubClient *githubapp.Client
-	parser       configParser
-	emailSender  OrderEmailSender
-	logger       *slog.Logger
+	shopStore      *db.ShopStore
+	orderStore     *db.OrderStore
+	stripePlatform *stripe.PlatformClient
+	githubClient   *githubapp.Client
+	parser         configParser
+	emailSender    OrderEmailSender
+	webhooks       OrderWebhookPublisher
+	concierge      *FirstOrderConcierge
+	outbox         GitHubEffectQueue
+	logger         *slog.Logger
+
+	addressValidator address.Validator
 }
 
-func NewStripeService(shopStore *db.ShopStore, orderStore *db.OrderStore, githubClient *githubapp.Client, parser configParser, emailSender OrderEmailSender, logger *slog.Logger) *StripeService {
+func NewStripeService(shopStore *db.ShopStore, orderStore *db.OrderStore, stripePlatform *stripe.PlatformClient, githubClient *githubapp.Client, parser configParser, emailSender OrderEmailSender, webhooks OrderWebhookPublisher, concierge *FirstOrderConcierge, outbox GitHubEffectQueue, logger *slog.Logger) *StripeService {
 	if emailSender == nil {
 		emailSender = noopOrderEmailSender{}
 	}
+	if webhooks == nil {
+		webhooks = noopOrderWebhookPublisher{}
+	}
 
 	return &StripeService{
-		shopStore:    shopStore,
-		orderStore:   orderStore,
-		githubClient: githubClient,
-		parser:       parser,
-		emailSender:  emailSender,
-		logger:       logger,
+		shopStore:      shopStore,
+		orderStore:     orderStore,
+		stripePlatform: stripePlatform,
+		githubClient:   githubClient,
+		parser:         parser,
+		emailSender:    emailSender,
+		webhooks:       webhooks,
+		concierge:      concierge,
+		outbox:         outbox,
+		logger:         logger,
 	}
 }
 
@@ -92,7 +108,7 @@ func (s *StripeService) HandleCheckoutSessionCompleted(ctx context.Context, payl
 		return err
 	}
 
-	order, err := s.orderStore.GetByStripeSessionID(ctx, session.ID)
+	order, err := s.orderForCheckoutSession(ctx, &session.CheckoutSession, orderID)
 	if err != nil {
 		recordFailed("order_lookup_failed")
 		return fmt.Errorf("failed to get order: %w", err)
@@ -105,8 +121,35 @@ func (s *StripeService) HandleCheckoutSessionCompleted(ctx context.Context, payl
 	if session.PaymentIntent != nil {
 		paymentIntentID = session.PaymentIntent.ID
 	}
+	// Subscription checkouts have no payment intent; the subscription is what renews and
+	// what the seller cancels, so it is linked before the order is marked paid.
+	if session.Subscription != nil && session.Subscription.ID != "" {
+		if err := s.orderStore.SetSubscription(ctx, orderID, session.Subscription.ID); err != nil {
+			recordFailed("set_subscription_failed")
+			return fmt.Errorf("failed to record subscription: %w", err)
+		}
+	}
+
+	if customFields := sessionCustomFields(session.CustomFields); len(customFields) > 0 {
+		if err := s.orderStore.SetCustomFields(ctx, orderID, customFields); err != nil {
+			recordFailed("set_custom_fields_failed")
+			return fmt.Errorf("failed to record custom fields: %w", err)
+		}
+		order.CustomFields = customFields
+	}
+
+	// Checkout collects at most one tax ID. Stripe Tax has already applied any reverse charge.
+	if details := session.CustomerDetails; details != nil && len(details.TaxIDs) > 0 && details.TaxIDs[0] != nil {
+		taxID := details.TaxIDs[0]
+		if err := s.orderStore.SetTaxID(ctx, orderID, string(taxID.Type), taxID.Value); err != nil {
+			recordFailed("set_tax_id_failed")
+			return fmt.Errorf("failed to record tax ID: %w", err)
+		}
+		order.TaxIDType = string(taxID.Type)
+		order.TaxID = taxID.Value
+	}
 
-	if markErr := s.orderStore.MarkPaid(ctx, orderID, paymentIntentID, customerEmail, customerName, shippingAddress); markErr != nil {
+	if markErr := s.orderStore.MarkPaid(ctx, orderID, paymentIntentID, customerEmail, customerName, shippingAddress, string(order.CheckoutMethod)); markErr != nil {
 		if errors.Is(markErr, db.ErrInvalidStatusTransition) {
 			meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
 				attribute.String("reason", "invalid_status_transition"),
@@ -117,10 +160,17 @@ func (s *StripeService) HandleCheckoutSessionCompleted(ctx context.Context, payl
 		recordFailed("mark_paid_failed")
 		return fmt.Errorf("failed to mark order as paid: %w", markErr)
 	}
+	refreshCustomer(ctx, s.orderStore, orderID)
 	meter.Count("payment.succeeded", 1, sentry.WithAttributes(
 		attribute.String("source", "checkout_session_completed"),
 	))
 
+	if session.Consent != nil && session.Consent.TermsOfService == stripeapi.CheckoutSessionConsentTermsOfServiceAccepted {
+		if err := s.orderStore.MarkTermsAccepted(ctx, orderID); err != nil {
+			logger.Warn("failed to record terms consent", "error", err, "order_id", orderID)
+		}
+	}
+
 	shop, err := s.shopStore.GetByID(ctx, order.ShopID)
 	if err != nil {
 		recordFailed("shop_lookup_failed")
@@ -128,47 +178,208 @@ func (s *StripeService) HandleCheckoutSessionCompleted(ctx context.Context, payl
 		return fmt.Errorf("failed to get shop: %w", err)
 	}
 
-	githubClient := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	if session.Invoice != nil && session.Invoice.ID != "" {
+		s.recordCheckoutReceipt(ctx, shop, order, session.Invoice.ID)
+	}
 
-	comment := "✅ Payment received! We’re preparing your order now."
-	if err := githubClient.CreateComment(ctx, repoFullName, issueNumber, comment); err != nil {
-		meter.Count("payment.side_effect.failed", 1, sentry.WithAttributes(
-			attribute.String("reason", "github_comment_failed"),
+	if err := s.completePaidOrder(ctx, shop, order, repoFullName, issueNumber, customerEmail, customerName, shippingAddress); err != nil {
+		recordFailed("side_effects_enqueue_failed")
+		return err
+	}
+	meter.Count("payment.webhook.processed", 1)
+
+	return nil
+}
+
+// recordCheckoutReceipt links the invoice Stripe created for a checkout as the order's receipt.
+// The session event only carries the invoice ID, so the hosted page is looked up on the
+// connected account. A missing receipt never holds up the order.
+func (s *StripeService) recordCheckoutReceipt(ctx context.Context, shop *db.Shop, order *db.Order, invoiceID string) {
+	if s.stripePlatform == nil {
+		return
+	}
+	invoice, err := s.stripePlatform.GetInvoice(stripe.WithTestMode(ctx, order.TestMode), shop.StripeConnectAccountID, invoiceID)
+	if err != nil {
+		observability.MeterFromContext(ctx).Count("payment.side_effect.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "receipt_lookup_failed"),
 		))
-		logger.Error("failed to create payment received comment", "error", err, "repo", repoFullName, "issue", issueNumber)
+		s.loggerFromContext(ctx).Warn("failed to get checkout invoice", "error", err, "order_id", order.ID, "invoice_id", invoiceID)
+		return
 	}
+	s.recordReceipt(ctx, order, invoice.HostedInvoiceURL)
+}
 
-	if err := githubClient.RemoveLabel(ctx, repoFullName, issueNumber, "gitshop:status:pending-payment"); err != nil {
-		logger.Warn("failed to remove pending-payment label", "error", err)
+func (s *StripeService) recordReceipt(ctx context.Context, order *db.Order, receiptURL string) {
+	if receiptURL == "" {
+		return
+	}
+	if err := s.orderStore.SetReceiptURL(ctx, order.ID, receiptURL); err != nil {
+		observability.MeterFromContext(ctx).Count("payment.side_effect.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "receipt_save_failed"),
+		))
+		s.loggerFromContext(ctx).Warn("failed to save receipt link", "error", err, "order_id", order.ID)
+		return
 	}
+	order.ReceiptURL = receiptURL
+}
+
+// HandleInvoicePaid marks a quoted inquiry paid once the buyer settles its Stripe invoice.
+// Renewal invoices for subscription orders are acknowledged on the order issue instead.
+func (s *StripeService) HandleInvoicePaid(ctx context.Context, payload []byte) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.stripe.invoice_paid",
+		sentry.WithOpName("service.stripe"),
+		sentry.WithDescription("HandleInvoicePaid"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
 
-	if err := githubClient.AddLabels(ctx, repoFullName, issueNumber, []string{"gitshop:status:paid"}); err != nil {
-		logger.Error("failed to add paid label", "error", err, "repo", repoFullName, "issue", issueNumber)
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	meter.SetAttributes(attribute.String("event", "invoice.paid"))
+	recordFailed := func(reason string) {
+		meter.Count("payment.webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	meter.Count("payment.webhook.received", 1)
+
+	var invoice stripeapi.Invoice
+	if err := json.Unmarshal(payload, &invoice); err != nil {
+		recordFailed("invalid_payload")
+		return fmt.Errorf("invalid event object: %w", err)
+	}
+	if subscriptionID := invoiceSubscriptionID(&invoice); subscriptionID != "" {
+		return s.handleSubscriptionInvoicePaid(ctx, &invoice, subscriptionID)
+	}
+	if _, ok := invoice.Metadata["order_id"]; !ok {
+		// Invoices not created by GitShop carry no order metadata.
+		meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", "not_gitshop_invoice"),
+		))
+		return nil
+	}
+
+	orderID, issueNumber, repoFullName, err := parseStripeMetadata(invoice.Metadata)
+	if err != nil {
+		recordFailed("invalid_metadata")
+		return err
 	}
 
-	s.deleteCheckoutLinkComments(ctx, githubClient, repoFullName, issueNumber)
+	order, err := s.orderStore.GetByID(ctx, orderID)
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.StripeInvoiceID != invoice.ID {
+		meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", "stale_invoice"),
+		))
+		logger.Info("ignoring invoice.paid for a replaced invoice", "order_id", orderID, "invoice_id", invoice.ID)
+		return nil
+	}
+
+	var shippingAddress map[string]any
+	customerName := invoice.CustomerName
+	if invoice.CustomerShipping != nil {
+		shippingAddress = buildShippingAddress(invoice.CustomerShipping, nil)
+		if customerName == "" {
+			customerName = invoice.CustomerShipping.Name
+		}
+	}
+
+	if markErr := s.orderStore.MarkPaid(ctx, orderID, "", invoice.CustomerEmail, customerName, shippingAddress, "invoice"); markErr != nil {
+		if errors.Is(markErr, db.ErrInvalidStatusTransition) {
+			meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+				attribute.String("reason", "invalid_status_transition"),
+			))
+			logger.Info("ignoring invoice.paid due to state transition", "order_id", orderID, "invoice_id", invoice.ID, "error", markErr)
+			return nil
+		}
+		recordFailed("mark_paid_failed")
+		return fmt.Errorf("failed to mark order as paid: %w", markErr)
+	}
+	refreshCustomer(ctx, s.orderStore, orderID)
+	meter.Count("payment.succeeded", 1, sentry.WithAttributes(
+		attribute.String("source", "invoice_paid"),
+	))
+
+	shop, err := s.shopStore.GetByID(ctx, order.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("failed to get shop: %w", err)
+	}
+
+	s.recordReceipt(ctx, order, invoice.HostedInvoiceURL)
+
+	if err := s.completePaidOrder(ctx, shop, order, repoFullName, issueNumber, invoice.CustomerEmail, customerName, shippingAddress); err != nil {
+		recordFailed("side_effects_enqueue_failed")
+		return err
+	}
+	meter.Count("payment.webhook.processed", 1)
+
+	return nil
+}
+
+// completePaidOrder queues the issue updates for a successful payment and then sends the emails
+// and webhooks. Only a failure to queue the issue updates is returned, so the payment event is
+// retried before any buyer-facing email goes out; later failures are logged and recorded.
+func (s *StripeService) completePaidOrder(ctx context.Context, shop *db.Shop, order *db.Order, repoFullName string, issueNumber int, customerEmail, customerName string, shippingAddress map[string]any) error {
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	githubClient := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+
+	config := s.loadShopConfig(ctx, githubClient, repoFullName)
+	comment := shopComment(config, config.Messages.PaymentReceived, catalog.MessageData{OrderNumber: order.OrderNumber}, paymentReceivedComment(configLocalizer(config)))
+	if err := s.outbox.Enqueue(ctx, paidOrderEffects(shop, order, shopLabels(config), repoFullName, issueNumber, comment)); err != nil {
+		logger.Error("failed to queue paid order issue updates", "error", err, "order_id", order.ID)
+		return fmt.Errorf("failed to queue paid order issue updates: %w", err)
+	}
+	s.checkShippingAddress(ctx, shop, order, config, repoFullName, issueNumber, shippingAddress)
 
 	if err := s.sendOrderConfirmationEmail(ctx, shop, order, customerEmail, customerName, shippingAddress); err != nil {
 		meter.Count("payment.side_effect.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "email_confirmation_failed"),
 		))
-		logger.Error("failed to send order confirmation email", "error", err, "order_id", orderID)
+		logger.Error("failed to send order confirmation email", "error", err, "order_id", order.ID)
 		internalIssueTitle := fmt.Sprintf("[GitShop Internal] Email failed for order #%d", order.OrderNumber)
-		internalIssueBody := fmt.Sprintf("**Order #%d** on %s\n\n**Error:** Email delivery failed. Check server logs for details.\n\n**Order Issue:** https://github.com/%s/issues/%d", order.OrderNumber, shop.GitHubRepoFullName, repoFullName, issueNumber)
+		internalIssueBody := fmt.Sprintf("**Order %s** on %s\n\n**Error:** Email delivery failed. Check server logs for details.\n\n**Order Issue:** https://github.com/%s/issues/%d", commentOrderNumber(order.OrderNumber), shop.GitHubRepoFullName, repoFullName, issueNumber)
 		assignees := s.shopManagerAssignees(ctx, githubClient, repoFullName)
 		if createErr := githubClient.CreateIssue(ctx, repoFullName, internalIssueTitle, internalIssueBody, []string{"gitshop-internal", "email-failed"}, assignees); createErr != nil {
 			if len(assignees) > 0 {
-				logger.Warn("failed to create internal issue with assignee, retrying without assignee", "error", createErr, "repo", repoFullName, "order_id", orderID)
+				logger.Warn("failed to create internal issue with assignee, retrying without assignee", "error", createErr, "repo", repoFullName, "order_id", order.ID)
 				if retryErr := githubClient.CreateIssue(ctx, repoFullName, internalIssueTitle, internalIssueBody, []string{"gitshop-internal", "email-failed"}, nil); retryErr != nil {
-					logger.Error("failed to create internal issue for email failure", "error", retryErr, "repo", repoFullName, "order_id", orderID)
+					logger.Error("failed to create internal issue for email failure", "error", retryErr, "repo", repoFullName, "order_id", order.ID)
 				}
 			} else {
-				logger.Error("failed to create internal issue for email failure", "error", createErr, "repo", repoFullName, "order_id", orderID)
+				logger.Error("failed to create internal issue for email failure", "error", createErr, "repo", repoFullName, "order_id", order.ID)
 			}
 		}
+	} else if customerEmail != "" {
+		if err := s.orderStore.RecordEmail(ctx, order.ID, db.OrderEmailConfirmation, customerEmail); err != nil {
+			logger.Warn("failed to record order confirmation email", "error", err, "order_id", order.ID)
+		}
 	}
-	meter.Count("payment.webhook.processed", 1)
 
+	sellerRecipient := s.notificationRecipient(ctx, githubClient, shop, repoFullName)
+	if err := s.sendNewOrderNotification(ctx, shop, order, sellerRecipient, customerEmail, customerName, shippingAddress); err != nil {
+		meter.Count("payment.side_effect.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "email_seller_notification_failed"),
+		))
+		logger.Error("failed to send new order notification", "error", err, "order_id", order.ID)
+	}
+	s.concierge.HandlePaidOrder(ctx, shop, order, sellerRecipient)
+
+	paidOrder, err := s.orderStore.GetByID(ctx, order.ID)
+	if err != nil {
+		logger.Warn("failed to reload paid order for webhook", "error", err, "order_id", order.ID)
+		return nil
+	}
+	if err := s.webhooks.PublishOrderEvent(ctx, shop, paidOrder, db.WebhookOrderPaid); err != nil {
+		logger.Warn("failed to queue order.paid webhook", "error", err, "order_id", order.ID)
+	}
 	return nil
 }
 
@@ -210,6 +421,24 @@ func (s *StripeService) HandleCheckoutSessionExpired(ctx context.Context, payloa
 		return err
 	}
 
+	// An abandoned session from a Payment Link leaves the link itself open, so the order stays
+	// pending.
+	if session.PaymentLink != nil {
+		meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", "payment_link_session"),
+		))
+		return nil
+	}
+
+	// Stripe only closes a session before its expiry when GitShop expired it to replace the
+	// checkout, such as after the buyer edited the order, so the order stays pending.
+	if session.ExpiresAt > 0 && time.Now().Unix() < session.ExpiresAt {
+		meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", "session_replaced"),
+		))
+		return nil
+	}
+
 	order, err := s.orderStore.GetByStripeSessionID(ctx, session.ID)
 	if err != nil {
 		recordFailed("order_lookup_failed")
@@ -237,21 +466,22 @@ func (s *StripeService) HandleCheckoutSessionExpired(ctx context.Context, payloa
 		return fmt.Errorf("failed to get shop: %w", err)
 	}
 
-	expireComment := "⏰ Your checkout link expired. Please place a new order when you're ready."
 	githubClient := s.githubClient.WithInstallation(shop.GitHubInstallationID)
-	if err := githubClient.CreateComment(ctx, repoFullName, issueNumber, expireComment); err != nil {
+	config := s.loadShopConfig(ctx, githubClient, repoFullName)
+	comment := shopComment(config, config.Messages.CheckoutExpired, catalog.MessageData{OrderNumber: order.OrderNumber}, checkoutExpiredComment(configLocalizer(config)))
+	if err := githubClient.CreateComment(ctx, repoFullName, issueNumber, comment); err != nil {
 		meter.Count("payment.side_effect.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "github_comment_failed"),
 		))
 		logger.Error("failed to create expiration comment", "error", err, "repo", repoFullName, "issue", issueNumber)
 	}
-	if err := githubClient.RemoveLabel(ctx, repoFullName, issueNumber, "gitshop:status:pending-payment"); err != nil {
+	if err := githubClient.RemoveLabel(ctx, repoFullName, issueNumber, shopLabels(config).Name(catalog.LabelPendingPayment)); err != nil {
 		logger.Warn("failed to remove pending-payment label", "error", err, "repo", repoFullName, "issue", issueNumber)
 	}
-	if err := githubClient.AddLabels(ctx, repoFullName, issueNumber, []string{"gitshop:status:expired"}); err != nil {
+	if err := githubClient.AddLabels(ctx, repoFullName, issueNumber, []string{shopLabels(config).Name(catalog.LabelExpired)}); err != nil {
 		logger.Warn("failed to add expired label", "error", err, "repo", repoFullName, "issue", issueNumber)
 	}
-	s.deleteCheckoutLinkComments(ctx, githubClient, repoFullName, issueNumber)
+	deleteCheckoutLinkComments(ctx, s.loggerFromContext(ctx), githubClient, repoFullName, issueNumber)
 
 	logger.Info("checkout session expired handled", "order_id", orderID, "repo", repoFullName, "issue", issueNumber)
 	meter.Count("payment.webhook.processed", 1)
@@ -331,21 +561,21 @@ func (s *StripeService) HandlePaymentIntentFailed(ctx context.Context, payload [
 		return fmt.Errorf("failed to get shop: %w", err)
 	}
 
-	failComment := "❌ Payment failed. The checkout link is no longer active. Ask the seller for help or add a new comment `.gitshop retry`."
 	githubClient := s.githubClient.WithInstallation(shop.GitHubInstallationID)
-	if err := githubClient.CreateComment(ctx, repoFullName, issueNumber, failComment); err != nil {
+	config := s.loadShopConfig(ctx, githubClient, repoFullName)
+	if err := githubClient.CreateComment(ctx, repoFullName, issueNumber, paymentFailedComment(configLocalizer(config))); err != nil {
 		meter.Count("payment.side_effect.failed", 1, sentry.WithAttributes(
 			attribute.String("reason", "github_comment_failed"),
 		))
 		logger.Error("failed to create payment failure comment", "error", err, "repo", repoFullName, "issue", issueNumber)
 	}
-	if err := githubClient.RemoveLabel(ctx, repoFullName, issueNumber, "gitshop:status:pending-payment"); err != nil {
+	if err := githubClient.RemoveLabel(ctx, repoFullName, issueNumber, shopLabels(config).Name(catalog.LabelPendingPayment)); err != nil {
 		logger.Warn("failed to remove pending-payment label", "error", err, "repo", repoFullName, "issue", issueNumber)
 	}
-	if err := githubClient.AddLabels(ctx, repoFullName, issueNumber, []string{"gitshop:status:expired"}); err != nil {
+	if err := githubClient.AddLabels(ctx, repoFullName, issueNumber, []string{shopLabels(config).Name(catalog.LabelExpired)}); err != nil {
 		logger.Warn("failed to add expired label", "error", err, "repo", repoFullName, "issue", issueNumber)
 	}
-	s.deleteCheckoutLinkComments(ctx, githubClient, repoFullName, issueNumber)
+	deleteCheckoutLinkComments(ctx, s.loggerFromContext(ctx), githubClient, repoFullName, issueNumber)
 
 	logger.Info("payment failure handled", "order_id", orderID, "repo", repoFullName, "issue", issueNumber)
 	meter.Count("payment.webhook.processed", 1)
@@ -379,6 +609,35 @@ func extractCustomerDetails(session *checkoutSessionPayload) (string, string) {
 	return customerEmail, customerName
 }
 
+// sessionCustomFields reads the buyer's answers to the checkout custom fields. Optional fields
+// left blank are skipped.
+func sessionCustomFields(fields []*stripeapi.CheckoutSessionCustomField) []db.OrderCustomField {
+	answers := make([]db.OrderCustomField, 0, len(fields))
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		var value string
+		switch {
+		case field.Text != nil:
+			value = field.Text.Value
+		case field.Numeric != nil:
+			value = field.Numeric.Value
+		case field.Dropdown != nil:
+			value = field.Dropdown.Value
+		}
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		label := field.Key
+		if field.Label != nil && field.Label.Custom != "" {
+			label = field.Label.Custom
+		}
+		answers = append(answers, db.OrderCustomField{Key: field.Key, Label: label, Value: strings.TrimSpace(value)})
+	}
+	return answers
+}
+
 func buildShippingAddress(details *stripeapi.ShippingDetails, customerDetails *stripeapi.CheckoutSessionCustomerDetails) map[string]any {
 	var address *stripeapi.Address
 	if details != nil && details.Address != nil {
@@ -400,6 +659,23 @@ func buildShippingAddress(details *stripeapi.ShippingDetails, customerDetails *s
 	}
 }
 
+// orderForCheckoutSession finds the order a completed session pays for. Sessions created from a
+// fallback Payment Link are not known in advance, so they are matched through the order ID in
+// the link metadata and must come from the link the order was sent.
+func (s *StripeService) orderForCheckoutSession(ctx context.Context, session *stripeapi.CheckoutSession, orderID uuid.UUID) (*db.Order, error) {
+	if session.PaymentLink == nil {
+		return s.orderStore.GetByStripeSessionID(ctx, session.ID)
+	}
+	order, err := s.orderStore.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.StripePaymentLinkID != session.PaymentLink.ID {
+		return nil, fmt.Errorf("payment link %s does not belong to order %s", session.PaymentLink.ID, orderID)
+	}
+	return order, nil
+}
+
 func parseStripeMetadata(metadata map[string]string) (uuid.UUID, int, string, error) {
 	if metadata == nil {
 		return uuid.Nil, 0, "", fmt.Errorf("missing metadata")
@@ -433,8 +709,9 @@ func parseStripeMetadata(metadata map[string]string) (uuid.UUID, int, string, er
 	return orderID, issueNumber, repoFullName, nil
 }
 
-func (s *StripeService) deleteCheckoutLinkComments(ctx context.Context, client *githubapp.Client, repoFullName string, issueNumber int) {
-	logger := s.loggerFromContext(ctx)
+// deleteCheckoutLinkComments removes the issue's checkout link comments once their links can no
+// longer be paid.
+func deleteCheckoutLinkComments(ctx context.Context, logger *slog.Logger, client *githubapp.Client, repoFullName string, issueNumber int) {
 	comments, err := client.ListComments(ctx, repoFullName, issueNumber)
 	if err != nil {
 		logger.Error("failed to list comments for checkout cleanup", "error", err, "repo", repoFullName, "issue", issueNumber)
@@ -454,11 +731,64 @@ func (s *StripeService) deleteCheckoutLinkComments(ctx context.Context, client *
 }
 
 func (s *StripeService) sendOrderConfirmationEmail(ctx context.Context, shop *db.Shop, order *db.Order, customerEmail, customerName string, shippingAddress map[string]any) error {
-	decodedAddress, err := decodeShippingAddress(shippingAddress)
+	address, err := formatShippingAddress(customerName, shippingAddress)
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendOrderConfirmation(ctx, shop, order, OrderConfirmationEmailInput{
+		CustomerName:    customerName,
+		CustomerEmail:   customerEmail,
+		ShippingAddress: address,
+	})
+}
+
+// sendNewOrderNotification tells the seller about a paid order. It is skipped when neither a
+// notifications address nor an owner email is known.
+func (s *StripeService) sendNewOrderNotification(ctx context.Context, shop *db.Shop, order *db.Order, recipient, customerEmail, customerName string, shippingAddress map[string]any) error {
+	if recipient == "" {
+		return nil
+	}
+
+	address, err := formatShippingAddress(customerName, shippingAddress)
 	if err != nil {
 		return err
 	}
 
+	previousOrders, err := s.orderStore.CountPreviousOrders(ctx, order)
+	if err != nil {
+		s.loggerFromContext(ctx).Warn("failed to count previous orders", "error", err, "order_id", order.ID)
+	}
+
+	return s.emailSender.SendNewOrderNotification(ctx, shop, order, NewOrderNotificationInput{
+		Recipient:       recipient,
+		CustomerName:    customerName,
+		CustomerEmail:   customerEmail,
+		ShippingAddress: address,
+		PreviousOrders:  previousOrders,
+	})
+}
+
+// notificationRecipient prefers the notifications email in gitshop.yaml over the shop owner's.
+func (s *StripeService) notificationRecipient(ctx context.Context, client *githubapp.Client, shop *db.Shop, repoFullName string) string {
+	if client != nil && s.parser != nil {
+		if content, err := s.getGitShopConfigFile(ctx, client, repoFullName); err == nil {
+			if config, parseErr := s.parser.Parse(content); parseErr == nil && config != nil {
+				if configured := strings.TrimSpace(config.Shop.Notifications.Email); configured != "" {
+					return configured
+				}
+			}
+		}
+	}
+	return strings.TrimSpace(shop.OwnerEmail)
+}
+
+func formatShippingAddress(customerName string, shippingAddress map[string]any) (string, error) {
+	decodedAddress, err := decodeShippingAddress(shippingAddress)
+	if err != nil {
+		return "", err
+	}
+
 	addressLines := []string{
 		customerName,
 	}
@@ -478,12 +808,7 @@ func (s *StripeService) sendOrderConfirmationEmail(ctx context.Context, shop *db
 	if country != "" {
 		addressLines = append(addressLines, country)
 	}
-
-	return s.emailSender.SendOrderConfirmation(ctx, shop, order, OrderConfirmationEmailInput{
-		CustomerName:    customerName,
-		CustomerEmail:   customerEmail,
-		ShippingAddress: strings.Join(addressLines, "\n"),
-	})
+	return strings.Join(addressLines, "\n"), nil
 }
 
 type shippingAddressPayload struct {
@@ -532,6 +857,20 @@ func (s *StripeService) shopManagerAssignees(ctx context.Context, client *github
 	return []string{manager}
 }
 
+// loadShopConfig reads gitshop.yaml for optional settings such as comment templates. It returns
+// an empty config when the file is missing or invalid, so callers fall back to defaults.
+func (s *StripeService) loadShopConfig(ctx context.Context, client *githubapp.Client, repoFullName string) *catalog.GitShopConfig {
+	content, err := s.getGitShopConfigFile(ctx, client, repoFullName)
+	if err != nil {
+		return &catalog.GitShopConfig{}
+	}
+	config, err := s.parser.Parse(content)
+	if err != nil || config == nil || catalog.NewValidator().Validate(config) != nil {
+		return &catalog.GitShopConfig{}
+	}
+	return config
+}
+
 func (s *StripeService) getGitShopConfigFile(ctx context.Context, client *githubapp.Client, repoFullName string) ([]byte, error) {
 	content, err := client.GetFile(ctx, repoFullName, "gitshop.yaml", "")
 	if err == nil {
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	stripeapi "github.com/stripe/stripe-go/v84"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+type stripeAccountStore interface {
+	GetShopsByStripeAccount(ctx context.Context, accountID string) ([]*db.Shop, error)
+	RecordStripeCapabilities(ctx context.Context, shopID uuid.UUID, capabilities db.StripeCapabilities) (db.StripeCapabilities, error)
+}
+
+type restrictionIssueCreator interface {
+	CreateIssue(ctx context.Context, repoFullName string, title, body string, labels []string, assignees []string) error
+}
+
+// StripeAccountMonitor keeps each shop's Stripe capabilities current from account.updated events
+// and alerts the owner when Stripe takes one away.
+type StripeAccountMonitor struct {
+	shopStore   stripeAccountStore
+	issues      func(installationID int64) restrictionIssueCreator
+	emailSender OrderEmailSender
+	logger      *slog.Logger
+}
+
+func NewStripeAccountMonitor(shopStore *db.ShopStore, githubClient *githubapp.Client, emailSender OrderEmailSender, logger *slog.Logger) *StripeAccountMonitor {
+	var issues func(int64) restrictionIssueCreator
+	if githubClient != nil {
+		issues = func(installationID int64) restrictionIssueCreator {
+			return githubClient.WithInstallation(installationID)
+		}
+	}
+	return newStripeAccountMonitor(shopStore, issues, emailSender, logger)
+}
+
+func newStripeAccountMonitor(shopStore stripeAccountStore, issues func(int64) restrictionIssueCreator, emailSender OrderEmailSender, logger *slog.Logger) *StripeAccountMonitor {
+	if emailSender == nil {
+		emailSender = noopOrderEmailSender{}
+	}
+	return &StripeAccountMonitor{
+		shopStore:   shopStore,
+		issues:      issues,
+		emailSender: emailSender,
+		logger:      logger,
+	}
+}
+
+// HandleAccountUpdated records the capabilities in an account.updated event for every shop on
+// the account. Order intake reads them, so a shop that loses charges stops taking orders.
+func (m *StripeAccountMonitor) HandleAccountUpdated(ctx context.Context, accountID string, payload []byte) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.stripe_account.handle_account_updated",
+		sentry.WithOpName("service.stripe_account"),
+		sentry.WithDescription("HandleAccountUpdated"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	meter := observability.MeterFromContext(ctx)
+	meter.Count("stripe.account.updated.received", 1)
+	recordFailed := func(reason string) {
+		meter.Count("stripe.account.updated.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	var account stripeapi.Account
+	if err := json.Unmarshal(payload, &account); err != nil {
+		recordFailed("invalid_payload")
+		return fmt.Errorf("failed to decode stripe account: %w", err)
+	}
+	if accountID == "" {
+		accountID = account.ID
+	}
+	if accountID == "" {
+		recordFailed("missing_account_id")
+		return fmt.Errorf("stripe account event is missing the account id")
+	}
+
+	shops, err := m.shopStore.GetShopsByStripeAccount(ctx, accountID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("failed to find shops for stripe account: %w", err)
+	}
+
+	current := db.StripeCapabilities{
+		DetailsSubmitted: account.DetailsSubmitted,
+		ChargesEnabled:   account.ChargesEnabled,
+		PayoutsEnabled:   account.PayoutsEnabled,
+	}
+	for _, shop := range shops {
+		previous, err := m.shopStore.RecordStripeCapabilities(ctx, shop.ID, current)
+		if err != nil {
+			recordFailed("persist_capabilities_failed")
+			return fmt.Errorf("failed to record stripe capabilities for shop %s: %w", shop.ID, err)
+		}
+		if capabilitiesLost(previous, current) {
+			meter.Count("stripe.account.restricted", 1, sentry.WithAttributes(
+				attribute.Bool("charges_enabled", current.ChargesEnabled),
+				attribute.Bool("payouts_enabled", current.PayoutsEnabled),
+			))
+			m.alertOwner(ctx, shop, current)
+		}
+	}
+
+	meter.Count("stripe.account.updated.processed", 1)
+	span.Status = sentry.SpanStatusOK
+	return nil
+}
+
+// alertOwner emails the shop owner and opens an issue in the shop repo. Either can fail without
+// the other; the new capabilities are already saved.
+func (m *StripeAccountMonitor) alertOwner(ctx context.Context, shop *db.Shop, capabilities db.StripeCapabilities) {
+	logger := logging.FromContext(ctx, m.logger).With("shop_id", shop.ID)
+	logger.Warn("stripe restricted the shop's account",
+		"charges_enabled", capabilities.ChargesEnabled,
+		"payouts_enabled", capabilities.PayoutsEnabled)
+
+	if recipient := strings.TrimSpace(shop.OwnerEmail); recipient != "" {
+		if err := m.emailSender.SendStripeAccountRestricted(ctx, shop, capabilities, recipient); err != nil {
+			logger.Error("failed to send stripe restriction email", "error", err)
+		}
+	}
+
+	if m.issues == nil {
+		return
+	}
+	title, body := stripeRestrictionIssue(capabilities)
+	if err := m.issues(shop.GitHubInstallationID).CreateIssue(ctx, shop.GitHubRepoFullName, title, body, nil, nil); err != nil {
+		logger.Error("failed to open stripe restriction issue", "error", err, "repo", shop.GitHubRepoFullName)
+	}
+}
+
+// capabilitiesLost reports whether charges or payouts were on and are now off.
+func capabilitiesLost(previous, current db.StripeCapabilities) bool {
+	return (previous.ChargesEnabled && !current.ChargesEnabled) ||
+		(previous.PayoutsEnabled && !current.PayoutsEnabled)
+}
+
+func stripeRestrictionIssue(capabilities db.StripeCapabilities) (string, string) {
+	var lost []string
+	if !capabilities.ChargesEnabled {
+		lost = append(lost, "- **Charges are off.** GitShop has paused new orders and tells buyers the shop can't take payments right now.")
+	}
+	if !capabilities.PayoutsEnabled {
+		lost = append(lost, "- **Payouts are off.** Stripe is holding your balance.")
+	}
+	body := fmt.Sprintf(`⚠️ Stripe has restricted the account connected to this shop.
+
+%s
+
+This usually means Stripe needs more information to verify the account. Sign in to Stripe and complete any outstanding requirements. GitShop picks up the change automatically, and you can close this issue once it is resolved.`, strings.Join(lost, "\n"))
+	return "Stripe restricted this shop's account", body
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/email"
+)
+
+type fakeStripeAccountStore struct {
+	shops        []*db.Shop
+	capabilities map[uuid.UUID]db.StripeCapabilities
+}
+
+func (s *fakeStripeAccountStore) GetShopsByStripeAccount(context.Context, string) ([]*db.Shop, error) {
+	return s.shops, nil
+}
+
+func (s *fakeStripeAccountStore) RecordStripeCapabilities(_ context.Context, shopID uuid.UUID, capabilities db.StripeCapabilities) (db.StripeCapabilities, error) {
+	previous := s.capabilities[shopID]
+	capabilities.CheckedAt = time.Now()
+	s.capabilities[shopID] = capabilities
+	return previous, nil
+}
+
+type capturingIssueCreator struct {
+	titles []string
+	bodies []string
+}
+
+func (c *capturingIssueCreator) CreateIssue(_ context.Context, _ string, title, body string, _ []string, _ []string) error {
+	c.titles = append(c.titles, title)
+	c.bodies = append(c.bodies, body)
+	return nil
+}
+
+func TestStripeAccountMonitor_HandleAccountUpdated(t *testing.T) {
+	t.Parallel()
+
+	enabled := db.StripeCapabilities{DetailsSubmitted: true, ChargesEnabled: true, PayoutsEnabled: true, CheckedAt: time.Now()}
+	tests := []struct {
+		name       string
+		previous   db.StripeCapabilities
+		account    map[string]any
+		wantAlerts int
+		wantBody   string
+	}{
+		{
+			name:       "charges revoked",
+			previous:   enabled,
+			account:    map[string]any{"id": "acct_1", "details_submitted": true, "charges_enabled": false, "payouts_enabled": true},
+			wantAlerts: 1,
+			wantBody:   "Charges are off",
+		},
+		{
+			name:       "payouts revoked",
+			previous:   enabled,
+			account:    map[string]any{"id": "acct_1", "details_submitted": true, "charges_enabled": true, "payouts_enabled": false},
+			wantAlerts: 1,
+			wantBody:   "Payouts are off",
+		},
+		{
+			name:     "still enabled",
+			previous: enabled,
+			account:  map[string]any{"id": "acct_1", "details_submitted": true, "charges_enabled": true, "payouts_enabled": true},
+		},
+		{
+			name:    "never enabled",
+			account: map[string]any{"id": "acct_1", "details_submitted": false, "charges_enabled": false, "payouts_enabled": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			shop := &db.Shop{ID: uuid.New(), GitHubRepoFullName: "octo/shop", OwnerEmail: "owner@example.com", EmailProvider: "postmark"}
+			store := &fakeStripeAccountStore{
+				shops:        []*db.Shop{shop},
+				capabilities: map[uuid.UUID]db.StripeCapabilities{shop.ID: tt.previous},
+			}
+			issues := &capturingIssueCreator{}
+			provider := &capturingEmailProvider{}
+			sender := NewShopOrderEmailSender(func(*db.Shop) (email.Provider, error) {
+				return provider, nil
+			}, nil, "https://gitshop.example")
+			monitor := newStripeAccountMonitor(store, func(int64) restrictionIssueCreator { return issues }, sender, nil)
+
+			payload, err := json.Marshal(tt.account)
+			if err != nil {
+				t.Fatalf("failed to encode account: %v", err)
+			}
+			if err := monitor.HandleAccountUpdated(t.Context(), "acct_1", payload); err != nil {
+				t.Fatalf("HandleAccountUpdated() error = %v", err)
+			}
+
+			recorded := store.capabilities[shop.ID]
+			if recorded.ChargesEnabled != tt.account["charges_enabled"] || recorded.PayoutsEnabled != tt.account["payouts_enabled"] {
+				t.Fatalf("recorded capabilities = %+v, want %v", recorded, tt.account)
+			}
+			if len(issues.titles) != tt.wantAlerts || len(provider.sent) != tt.wantAlerts {
+				t.Fatalf("issues = %d, emails = %d, want %d of each", len(issues.titles), len(provider.sent), tt.wantAlerts)
+			}
+			if tt.wantAlerts == 0 {
+				return
+			}
+			if !strings.Contains(issues.bodies[0], tt.wantBody) {
+				t.Fatalf("issue body %q missing %q", issues.bodies[0], tt.wantBody)
+			}
+			message := provider.sent[0]
+			if message.To != shop.OwnerEmail {
+				t.Fatalf("email sent to %q, want %q", message.To, shop.OwnerEmail)
+			}
+			if !strings.Contains(message.Text, "https://gitshop.example/admin/settings") {
+				t.Fatalf("email text missing settings link: %q", message.Text)
+			}
+		})
+	}
+}
+
+func TestStripeCapabilities_ChargesPaused(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		capabilities db.StripeCapabilities
+		want         bool
+	}{
+		{name: "unknown", capabilities: db.StripeCapabilities{}, want: false},
+		{name: "charges enabled", capabilities: db.StripeCapabilities{ChargesEnabled: true, CheckedAt: time.Now()}, want: false},
+		{name: "charges disabled", capabilities: db.StripeCapabilities{CheckedAt: time.Now()}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.capabilities.ChargesPaused(); got != tt.want {
+				t.Fatalf("ChargesPaused() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -292,6 +292,10 @@ func (s *StripeConnectService) GetConnectionStatus(ctx context.Context, shopID u
 		return status, nil
 	}
 
+	if err := s.shopStore.UpdateStripeConnectDetails(ctx, shopID, shop.StripeConnectAccountID, account.DetailsSubmitted, account.ChargesEnabled, account.PayoutsEnabled); err != nil {
+		s.loggerFromContext(ctx).Warn("failed to persist stripe connect details", "error", err, "shop_id", shopID)
+	}
+
 	status.DetailsSubmitted = account.DetailsSubmitted
 	status.ChargesEnabled = account.ChargesEnabled
 	status.PayoutsEnabled = account.PayoutsEnabled
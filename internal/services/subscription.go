@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	stripeapi "github.com/stripe/stripe-go/v84"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/featureflags"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/stripe"
+)
+
+var (
+	ErrAdminSubscriptionUnavailable = errors.New("subscription cancellation unavailable")
+	ErrOrderNotSubscribed           = errors.New("order has no active subscription")
+	// ErrSubscriptionsDisabled means the order bills on a schedule but the subscriptions flag is
+	// off for its shop.
+	ErrSubscriptionsDisabled = errors.New("subscriptions are not enabled for this shop")
+)
+
+const activeSubscriptionsLimit = 50
+
+// checkSubscriptionsEnabled fails with ErrSubscriptionsDisabled when a subscription checkout is
+// asked for while the subscriptions flag is off for the shop. createOrderCheckout runs it, so an
+// order taken while the flag was on cannot reach a subscription checkout through a retry, edit,
+// approval, or recovery after it is turned off.
+func checkSubscriptionsEnabled(ctx context.Context, flags *featureflags.Flags, shop *db.Shop, subscription bool) error {
+	if subscription && !flags.Enabled(ctx, featureflags.Subscriptions, shop.ID) {
+		return fmt.Errorf("%w: shop %s", ErrSubscriptionsDisabled, shop.ID)
+	}
+	return nil
+}
+
+// invoiceSubscriptionID returns the subscription that generated an invoice, if any.
+func invoiceSubscriptionID(invoice *stripeapi.Invoice) string {
+	if invoice.Parent == nil || invoice.Parent.SubscriptionDetails == nil || invoice.Parent.SubscriptionDetails.Subscription == nil {
+		return ""
+	}
+	return invoice.Parent.SubscriptionDetails.Subscription.ID
+}
+
+// handleSubscriptionInvoicePaid acknowledges a renewal on the order issue. The first invoice is
+// paid inside the checkout, so checkout.session.completed has already marked the order paid.
+func (s *StripeService) handleSubscriptionInvoicePaid(ctx context.Context, invoice *stripeapi.Invoice, subscriptionID string) error {
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	ignore := func(reason string) error {
+		meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+		return nil
+	}
+
+	if invoice.BillingReason != stripeapi.InvoiceBillingReasonSubscriptionCycle {
+		return ignore("subscription_not_renewal")
+	}
+
+	order, err := s.orderStore.GetByStripeSubscriptionID(ctx, subscriptionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ignore("unknown_subscription")
+	}
+	if err != nil {
+		meter.Count("payment.webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "order_lookup_failed"),
+		))
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	shop, err := s.shopStore.GetByID(ctx, order.ShopID)
+	if err != nil {
+		meter.Count("payment.webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "shop_lookup_failed"),
+		))
+		return fmt.Errorf("failed to get shop: %w", err)
+	}
+	meter.Count("payment.succeeded", 1, sentry.WithAttributes(
+		attribute.String("source", "subscription_renewal"),
+	))
+
+	githubClient := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	config := s.loadShopConfig(ctx, githubClient, shop.GitHubRepoFullName)
+	comment := subscriptionRenewedComment(configLocalizer(config), order.OrderNumber, invoice.AmountPaid)
+	if err := githubClient.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, comment); err != nil {
+		meter.Count("payment.side_effect.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "github_comment_failed"),
+		))
+		logger.Error("failed to create renewal comment", "error", err, "order_id", order.ID, "invoice_id", invoice.ID)
+	}
+	meter.Count("payment.webhook.processed", 1)
+	return nil
+}
+
+// HandleSubscriptionDeleted records a subscription that ended in Stripe, whether the seller, the
+// buyer, or failed renewals cancelled it.
+func (s *StripeService) HandleSubscriptionDeleted(ctx context.Context, payload []byte) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.stripe.subscription_deleted",
+		sentry.WithOpName("service.stripe"),
+		sentry.WithDescription("HandleSubscriptionDeleted"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	meter.SetAttributes(attribute.String("event", "customer.subscription.deleted"))
+	recordFailed := func(reason string) {
+		meter.Count("payment.webhook.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+	ignore := func(reason string) error {
+		meter.Count("payment.webhook.ignored", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+		return nil
+	}
+	meter.Count("payment.webhook.received", 1)
+
+	var subscription stripeapi.Subscription
+	if err := json.Unmarshal(payload, &subscription); err != nil {
+		recordFailed("invalid_payload")
+		return fmt.Errorf("invalid event object: %w", err)
+	}
+	if subscription.ID == "" {
+		recordFailed("missing_subscription_id")
+		return fmt.Errorf("missing subscription ID")
+	}
+
+	order, err := s.orderStore.GetByStripeSubscriptionID(ctx, subscription.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ignore("unknown_subscription")
+	}
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if err := s.orderStore.MarkSubscriptionCancelled(ctx, order.ID); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			// The seller cancelled from the dashboard, which already updated the order.
+			logger.Info("ignoring customer.subscription.deleted due to state transition", "order_id", order.ID, "subscription_id", subscription.ID)
+			return ignore("invalid_status_transition")
+		}
+		recordFailed("mark_cancelled_failed")
+		return fmt.Errorf("failed to mark subscription cancelled: %w", err)
+	}
+	meter.Count("subscription.cancelled", 1, sentry.WithAttributes(
+		attribute.String("source", "customer_subscription_deleted"),
+	))
+
+	shop, err := s.shopStore.GetByID(ctx, order.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("failed to get shop: %w", err)
+	}
+	githubClient := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	config := s.loadShopConfig(ctx, githubClient, shop.GitHubRepoFullName)
+	if err := githubClient.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, subscriptionCancelledComment(configLocalizer(config), order.OrderNumber)); err != nil {
+		meter.Count("payment.side_effect.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", "github_comment_failed"),
+		))
+		logger.Error("failed to create subscription cancelled comment", "error", err, "order_id", order.ID)
+	}
+	meter.Count("payment.webhook.processed", 1)
+	span.Status = sentry.SpanStatusOK
+	return nil
+}
+
+// GetActiveSubscriptions lists the shop's orders whose subscription is still renewing.
+func (s *AdminService) GetActiveSubscriptions(ctx context.Context, shopID uuid.UUID) ([]*db.Order, error) {
+	if s == nil || s.orderStore == nil {
+		return nil, fmt.Errorf("%w: order store unavailable", ErrAdminServiceUnavailable)
+	}
+	if shopID == uuid.Nil {
+		return nil, fmt.Errorf("%w: empty shop id", ErrAdminShopNotFound)
+	}
+	return s.orderStore.ListActiveSubscriptions(ctx, shopID, activeSubscriptionsLimit)
+}
+
+// CancelSubscription cancels the order's Stripe subscription immediately and tells the buyer on
+// the order issue. The order itself keeps its fulfillment status.
+func (s *AdminService) CancelSubscription(ctx context.Context, input OrderActionInput) error {
+	span := sentry.StartSpan(
+		ctx,
+		"service.admin.cancel_subscription",
+		sentry.WithOpName("service.admin"),
+		sentry.WithDescription("CancelSubscription"),
+		sentry.WithSpanOrigin(sentry.SpanOriginManual),
+	)
+	defer span.Finish()
+	ctx = span.Context()
+
+	logger := s.loggerFromContext(ctx)
+	meter := observability.MeterFromContext(ctx)
+	recordFailed := func(reason string) {
+		meter.Count("subscription.cancel.failed", 1, sentry.WithAttributes(
+			attribute.String("reason", reason),
+		))
+	}
+
+	if s.stripePlatform == nil {
+		recordFailed("stripe_unavailable")
+		return fmt.Errorf("%w: stripe platform not configured", ErrAdminSubscriptionUnavailable)
+	}
+
+	order, err := s.getShopOrder(ctx, input)
+	if err != nil {
+		recordFailed("order_lookup_failed")
+		return err
+	}
+	if order.SubscriptionStatus != db.SubscriptionActive || order.StripeSubscriptionID == "" {
+		recordFailed("not_subscribed")
+		return ErrOrderNotSubscribed
+	}
+
+	shop, err := s.shopStore.GetByID(ctx, input.ShopID)
+	if err != nil {
+		recordFailed("shop_lookup_failed")
+		return fmt.Errorf("%w: %w", ErrAdminShopNotFound, err)
+	}
+
+	if err := s.stripePlatform.CancelSubscription(stripe.WithTestMode(ctx, order.TestMode), shop.StripeConnectAccountID, order.StripeSubscriptionID); err != nil {
+		recordFailed("stripe_cancel_failed")
+		return err
+	}
+	if err := s.orderStore.MarkSubscriptionCancelled(ctx, order.ID); err != nil {
+		if errors.Is(err, db.ErrInvalidStatusTransition) {
+			recordFailed("invalid_status_transition")
+			return fmt.Errorf("%w: %w", ErrAdminOrderStatusConflict, err)
+		}
+		recordFailed("mark_cancelled_failed")
+		return fmt.Errorf("failed to mark subscription cancelled: %w", err)
+	}
+	meter.Count("subscription.cancelled", 1, sentry.WithAttributes(
+		attribute.String("source", "admin"),
+	))
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	if err := client.CreateComment(ctx, shop.GitHubRepoFullName, order.GitHubIssueNumber, subscriptionCancelledComment(shopLocalizer(ctx, client, shop.GitHubRepoFullName), order.OrderNumber)); err != nil {
+		logger.Error("failed to create subscription cancelled comment", "error", err, "issue", order.GitHubIssueNumber, "shop_id", shop.ID)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gitshopapp/gitshop/internal/catalog"
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/githubapp"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+// IssueTemplateCandidate is an existing issue template in the shop repository that is not an
+// order template yet.
+type IssueTemplateCandidate struct {
+	Path        string
+	Name        string
+	URL         string
+	Convertible bool
+	Suggested   bool
+	Reason      string
+}
+
+// AnalyzeIssueTemplates lists the repository's existing issue templates that could become order
+// templates, the likeliest first, so sellers can keep a form buyers already know instead of
+// adding a new order.yaml.
+func (s *AdminService) AnalyzeIssueTemplates(ctx context.Context, shop *db.Shop) ([]IssueTemplateCandidate, error) {
+	if s == nil || s.githubClient == nil {
+		return nil, fmt.Errorf("%w: github client unavailable", ErrAdminServiceUnavailable)
+	}
+	if shop == nil {
+		return nil, fmt.Errorf("shop is required")
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	access := contentAccess(ctx, client, s.logger)
+	files, err := listRepoDirectory(ctx, client, shop.GitHubRepoFullName, githubapp.IssueTemplateDir, access)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []IssueTemplateCandidate{}
+	for _, file := range filterTemplateFiles(files) {
+		// config.yml configures GitHub's template chooser and is not a template.
+		if name := strings.ToLower(file.Name); name == "config.yml" || name == "config.yaml" {
+			continue
+		}
+		content, err := client.GetFile(ctx, shop.GitHubRepoFullName, file.Path, "")
+		if err != nil || catalog.HasOrderTemplateMarker(string(content)) {
+			continue
+		}
+		analysis := catalog.AnalyzeIssueTemplate(string(content))
+		candidates = append(candidates, IssueTemplateCandidate{
+			Path:        file.Path,
+			Name:        cmp.Or(analysis.Name, file.Name),
+			URL:         file.HTMLURL,
+			Convertible: analysis.Convertible && access.CanOpenPullRequests(),
+			Suggested:   analysis.Suggested,
+			Reason:      analysis.Reason,
+		})
+	}
+	slices.SortStableFunc(candidates, func(a, b IssueTemplateCandidate) int {
+		return rankCandidate(a) - rankCandidate(b)
+	})
+	return candidates, nil
+}
+
+func rankCandidate(candidate IssueTemplateCandidate) int {
+	switch {
+	case candidate.Convertible && candidate.Suggested:
+		return 0
+	case candidate.Convertible:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ConvertIssueTemplate opens a pull request turning an existing issue form into an order
+// template. The form keeps its fields and gains the order marker and label, a product dropdown
+// for products no order template lists yet, and the quantity and option fields they need.
+func (s *AdminService) ConvertIssueTemplate(ctx context.Context, shop *db.Shop, templatePath string) (*githubapp.FileCreationResult, error) {
+	if s == nil || s.githubClient == nil {
+		return nil, fmt.Errorf("%w: github client unavailable", ErrAdminServiceUnavailable)
+	}
+	if shop == nil {
+		return nil, fmt.Errorf("shop is required")
+	}
+	templatePath = strings.TrimSpace(templatePath)
+	if ext := strings.ToLower(path.Ext(templatePath)); path.Dir(templatePath) != githubapp.IssueTemplateDir || (ext != ".yml" && ext != ".yaml") {
+		return nil, UserError{Message: "Choose an issue form in " + githubapp.IssueTemplateDir + " to convert"}
+	}
+
+	client := s.githubClient.WithInstallation(shop.GitHubInstallationID)
+	access := contentAccess(ctx, client, s.logger)
+	if !access.CanOpenPullRequests() {
+		return nil, UserError{Message: "Converting a template opens a pull request, which needs Contents write access. GitShop only has single-file access to this repository."}
+	}
+
+	config, err := s.fetchValidatedConfig(ctx, client, shop.GitHubRepoFullName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := client.GetFile(ctx, shop.GitHubRepoFullName, templatePath, "")
+	if err != nil {
+		return nil, UserError{Message: "Could not read " + templatePath}
+	}
+	existing, err := readOrderTemplates(ctx, client, shop.GitHubRepoFullName, access)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := s.newSyncer(s.githubClient).ConvertIssueTemplate(string(content), config, existing.coveredSKUs())
+	if err != nil {
+		return nil, UserError{Message: "Could not convert " + path.Base(templatePath) + ": " + err.Error()}
+	}
+
+	owner, repo, err := splitRepoFullName(shop.GitHubRepoFullName)
+	if err != nil {
+		return nil, err
+	}
+	name := path.Base(templatePath)
+	prBody := s.templateChangePRBody(ctx, "This PR turns the `"+name+"` issue form into a GitShop order template. Its existing fields stay as they are; GitShop adds the order label, a product dropdown, and the fields your products need.", string(content), converted)
+	result, err := client.CreatePullRequestWithFiles(ctx, owner, repo, githubapp.PullRequestInput{
+		BranchName: "gitshop/convert-" + strings.TrimSuffix(name, path.Ext(name)),
+		Message:    "Convert " + name + " to a GitShop order template",
+		Title:      "Take orders with the " + name + " issue form",
+		Body:       prBody,
+		Files:      []githubapp.FileChange{{Path: templatePath, Content: converted}},
+	})
+	if err != nil {
+		return nil, contentAccessUserError(err, templatePath)
+	}
+
+	observability.MeterFromContext(ctx).Count("template.conversion.opened", 1)
+	s.loggerFromContext(ctx).Info("opened issue template conversion", "repo", shop.GitHubRepoFullName, "path", templatePath, "pr_url", result.URL)
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/observability"
+	"github.com/gitshopapp/gitshop/internal/storage"
+)
+
+const warehouseExportInterval = time.Hour
+
+type warehouseOrderStore interface {
+	ListWarehouseOrders(ctx context.Context, region string, from, to time.Time) ([]db.WarehouseOrder, error)
+	ListWarehouseOrderEvents(ctx context.Context, region string, from, to time.Time) ([]db.WarehouseOrderEvent, error)
+}
+
+// WarehouseExportService writes each day's orders and order events to a bucket as CSV, so
+// analytics can run in a warehouse instead of against the production database. Buyers' personal
+// details are never exported.
+type WarehouseExportService struct {
+	orderStore warehouseOrderStore
+	storage    storage.Provider
+	region     string
+	logger     *slog.Logger
+	now        func() time.Time
+	// exportedDay is the last UTC day exported by this instance.
+	exportedDay time.Time
+}
+
+// NewWarehouseExportService creates a service that exports the orders of shops homed in region
+// to provider. An empty region covers every shop; a nil provider turns exports off.
+func NewWarehouseExportService(orderStore *db.OrderStore, provider storage.Provider, region string, logger *slog.Logger) *WarehouseExportService {
+	return &WarehouseExportService{
+		orderStore: orderStore,
+		storage:    provider,
+		region:     region,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Run exports the previous UTC day once it has ended, checking every hour until ctx is
+// cancelled. An export overwrites the day's files, so a restart exporting the same day again is
+// harmless.
+func (s *WarehouseExportService) Run(ctx context.Context) {
+	if s == nil || s.orderStore == nil || s.storage == nil {
+		return
+	}
+
+	ticker := time.NewTicker(warehouseExportInterval)
+	defer ticker.Stop()
+	for {
+		day := s.now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+		if day.After(s.exportedDay) {
+			orders, events, err := s.ExportDay(ctx, day)
+			if err != nil && ctx.Err() == nil {
+				s.logger.Error("failed to export orders to the warehouse", "error", err, "day", day.Format(time.DateOnly))
+			} else if err == nil {
+				s.exportedDay = day
+				s.logger.Info("exported orders to the warehouse", "day", day.Format(time.DateOnly), "orders", orders, "events", events)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExportDay writes the orders with an event on day, as they are now, and the events themselves,
+// and returns how many of each it wrote. Orders that changed on several days appear in each of
+// those days' files, so the warehouse should keep the latest row per order ID.
+func (s *WarehouseExportService) ExportDay(ctx context.Context, day time.Time) (int, int, error) {
+	if s == nil || s.orderStore == nil || s.storage == nil {
+		return 0, 0, nil
+	}
+
+	from := day.UTC().Truncate(24 * time.Hour)
+	to := from.Add(24 * time.Hour)
+
+	orders, err := s.orderStore.ListWarehouseOrders(ctx, s.region, from, to)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+	ordersCSV, err := warehouseOrdersCSV(orders)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to encode orders: %w", err)
+	}
+	if err := s.put(ctx, s.warehouseKey("orders", from), ordersCSV); err != nil {
+		return 0, 0, fmt.Errorf("failed to store orders: %w", err)
+	}
+
+	events, err := s.orderStore.ListWarehouseOrderEvents(ctx, s.region, from, to)
+	if err != nil {
+		return len(orders), 0, fmt.Errorf("failed to list order events: %w", err)
+	}
+	eventsCSV, err := warehouseEventsCSV(events)
+	if err != nil {
+		return len(orders), 0, fmt.Errorf("failed to encode order events: %w", err)
+	}
+	if err := s.put(ctx, s.warehouseKey("order_events", from), eventsCSV); err != nil {
+		return len(orders), 0, fmt.Errorf("failed to store order events: %w", err)
+	}
+
+	meter := observability.MeterFromContext(ctx)
+	meter.Count("warehouse_export.orders", int64(len(orders)))
+	meter.Count("warehouse_export.order_events", int64(len(events)))
+	return len(orders), len(events), nil
+}
+
+func (s *WarehouseExportService) put(ctx context.Context, key string, body []byte) error {
+	return s.storage.Put(ctx, key, bytes.NewReader(body), int64(len(body)), "text/csv; charset=utf-8")
+}
+
+// warehouseKey names a table's file for one day, such as warehouse/orders/2026-10-15/eu.csv.
+// Each region writes its own file, so instances in different regions never overwrite each other.
+func (s *WarehouseExportService) warehouseKey(table string, day time.Time) string {
+	scope := s.region
+	if scope == "" {
+		scope = "all"
+	}
+	return fmt.Sprintf("%s%s/%s/%s.csv", storage.WarehousePrefix, table, day.Format(time.DateOnly), scope)
+}
+
+func warehouseOrdersCSV(orders []db.WarehouseOrder) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := []string{
+		"id", "shop_id", "order_number", "status", "sku", "category", "campaign", "subtotal_cents", "shipping_cents",
+		"tax_cents", "total_cents", "display_currency", "test_mode", "cancellation_reason", "created_at", "paid_at",
+		"shipped_at", "delivered_at", "refunded_at", "returned_at", "cancelled_at",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		row := []string{
+			order.ID.String(), order.ShopID.String(), strconv.Itoa(order.OrderNumber), string(order.Status), order.SKU,
+			order.Category, order.Campaign, strconv.FormatInt(order.SubtotalCents, 10), strconv.FormatInt(order.ShippingCents, 10),
+			strconv.FormatInt(order.TaxCents, 10), strconv.FormatInt(order.TotalCents, 10), order.DisplayCurrency,
+			strconv.FormatBool(order.TestMode), order.CancellationReason, order.CreatedAt.UTC().Format(time.RFC3339),
+			csvTime(order.PaidAt), csvTime(order.ShippedAt), csvTime(order.DeliveredAt), csvTime(order.RefundedAt),
+			csvTime(order.ReturnedAt), csvTime(order.CancelledAt),
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func warehouseEventsCSV(events []db.WarehouseOrderEvent) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write([]string{"order_id", "shop_id", "event", "detail", "occurred_at"}); err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		row := []string{event.OrderID.String(), event.ShopID.String(), event.Event, event.Detail, event.OccurredAt.UTC().Format(time.RFC3339)}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
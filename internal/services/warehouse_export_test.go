@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+type fakeWarehouseOrderStore struct {
+	orders   []db.WarehouseOrder
+	events   []db.WarehouseOrderEvent
+	region   string
+	from, to time.Time
+}
+
+func (s *fakeWarehouseOrderStore) ListWarehouseOrders(_ context.Context, region string, from, to time.Time) ([]db.WarehouseOrder, error) {
+	s.region, s.from, s.to = region, from, to
+	return s.orders, nil
+}
+
+func (s *fakeWarehouseOrderStore) ListWarehouseOrderEvents(_ context.Context, _ string, _, _ time.Time) ([]db.WarehouseOrderEvent, error) {
+	return s.events, nil
+}
+
+type fakeFileStorage struct {
+	files map[string]string
+}
+
+func (s *fakeFileStorage) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if s.files == nil {
+		s.files = map[string]string{}
+	}
+	s.files[key] = string(data)
+	return nil
+}
+
+func (s *fakeFileStorage) Delete(context.Context, string) error { return nil }
+
+func (s *fakeFileStorage) SignedURL(context.Context, string, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+func (s *fakeFileStorage) DeleteOlderThan(context.Context, string, time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestWarehouseExportService_ExportDay(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New()
+	paidAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	store := &fakeWarehouseOrderStore{
+		orders: []db.WarehouseOrder{{
+			ID: orderID, OrderNumber: 42, Status: db.StatusPaid, SKU: "MUG_V1", Campaign: "Fall Sale",
+			SubtotalCents: 1800, ShippingCents: 500, TotalCents: 2300, CreatedAt: paidAt.Add(-time.Hour), PaidAt: &paidAt,
+		}},
+		events: []db.WarehouseOrderEvent{
+			{OrderID: orderID, Event: "paid", OccurredAt: paidAt},
+			{OrderID: orderID, Event: "email_sent", Detail: "order_confirmation", OccurredAt: paidAt.Add(time.Minute)},
+		},
+	}
+	files := &fakeFileStorage{}
+	service := &WarehouseExportService{orderStore: store, storage: files, region: "eu"}
+
+	orders, events, err := service.ExportDay(context.Background(), time.Date(2026, 10, 15, 18, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ExportDay: %v", err)
+	}
+	if orders != 1 || events != 2 {
+		t.Fatalf("expected 1 order and 2 events, got %d and %d", orders, events)
+	}
+	if store.region != "eu" || !store.from.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)) || store.to.Sub(store.from) != 24*time.Hour {
+		t.Fatalf("expected the whole day in eu, got %q from %s to %s", store.region, store.from, store.to)
+	}
+
+	ordersCSV := files.files["warehouse/orders/2026-10-15/eu.csv"]
+	for _, want := range []string{"id,shop_id,order_number,status", orderID.String() + ",", ",42,paid,MUG_V1,,Fall Sale,1800,500,0,2300,", "2026-10-15T09:30:00Z"} {
+		if !strings.Contains(ordersCSV, want) {
+			t.Fatalf("orders file missing %q:\n%s", want, ordersCSV)
+		}
+	}
+	for _, notWant := range []string{"github_username", "email", "address"} {
+		if strings.Contains(ordersCSV, notWant) {
+			t.Fatalf("orders file should not include %s:\n%s", notWant, ordersCSV)
+		}
+	}
+
+	eventsCSV := files.files["warehouse/order_events/2026-10-15/eu.csv"]
+	if lines := strings.Count(eventsCSV, "\n"); lines != 3 {
+		t.Fatalf("expected a header and 2 events, got %d lines:\n%s", lines, eventsCSV)
+	}
+	if !strings.Contains(eventsCSV, ",email_sent,order_confirmation,2026-10-15T09:31:00Z") {
+		t.Fatalf("events file missing the confirmation email:\n%s", eventsCSV)
+	}
+}
+
+func TestWarehouseExportService_WarehouseKey(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		region string
+		want   string
+	}{
+		{region: "", want: "warehouse/orders/2026-10-15/all.csv"},
+		{region: "us", want: "warehouse/orders/2026-10-15/us.csv"},
+	}
+	for _, tt := range tests {
+		service := &WarehouseExportService{region: tt.region}
+		if got := service.warehouseKey("orders", day); got != tt.want {
+			t.Fatalf("warehouseKey(%q) = %q, want %q", tt.region, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/getsentry/sentry-go/attribute"
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/logging"
+	"github.com/gitshopapp/gitshop/internal/observability"
+)
+
+const (
+	webhookSignatureHeader   = "X-GitShop-Signature"
+	webhookEventHeader       = "X-GitShop-Event"
+	webhookDeliveryHeader    = "X-GitShop-Delivery"
+	webhookPollInterval      = 15 * time.Second
+	webhookClaimBatchSize    = 20
+	webhookClaimLease        = 2 * time.Minute
+	webhookRequestTimeout    = 10 * time.Second
+	webhookMaxAttempts       = 8
+	webhookInitialBackoff    = 30 * time.Second
+	webhookMaxBackoff        = 6 * time.Hour
+	webhookMinSecretLength   = 16
+	webhookDeliveryLogLength = 25
+)
+
+// OrderWebhookPublisher queues order events for the shop's outbound webhook.
+type OrderWebhookPublisher interface {
+	PublishOrderEvent(ctx context.Context, shop *db.Shop, order *db.Order, event db.WebhookEvent) error
+}
+
+type WebhookService struct {
+	store      *db.WebhookStore
+	region     string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+// NewWebhookService creates a service that only delivers webhooks for shops homed in region. An
+// empty region delivers for every shop.
+func NewWebhookService(store *db.WebhookStore, region string, logger *slog.Logger) *WebhookService {
+	return &WebhookService{
+		store:      store,
+		region:     region,
+		httpClient: &http.Client{Timeout: webhookRequestTimeout},
+		logger:     logger,
+	}
+}
+
+type webhookPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	Event     db.WebhookEvent `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Shop      webhookShopData `json:"shop"`
+	Order     OrderPayload    `json:"order"`
+}
+
+type webhookShopData struct {
+	ID   uuid.UUID `json:"id"`
+	Repo string    `json:"repo"`
+}
+
+// OrderPayload is an order as webhook deliveries and the orders API present it.
+type OrderPayload struct {
+	ID              uuid.UUID      `json:"id"`
+	Number          int            `json:"number"`
+	Status          db.OrderStatus `json:"status"`
+	SKU             string         `json:"sku"`
+	Quantity        int            `json:"quantity"`
+	Options         map[string]any `json:"options"`
+	SubtotalCents   int64          `json:"subtotal_cents"`
+	ShippingCents   int64          `json:"shipping_cents"`
+	TotalCents      int64          `json:"total_cents"`
+	CustomerName    string         `json:"customer_name,omitempty"`
+	CustomerEmail   string         `json:"customer_email,omitempty"`
+	ShippingAddress map[string]any `json:"shipping_address,omitempty"`
+	Carrier         string         `json:"carrier,omitempty"`
+	TrackingNumber  string         `json:"tracking_number,omitempty"`
+	IssueNumber     int            `json:"issue_number"`
+	IssueURL        string         `json:"issue_url"`
+	GitHubUsername  string         `json:"github_username"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+// PublishOrderEvent writes a delivery to the outbox when the shop has a webhook configured.
+// The background worker sends it, so a slow endpoint never holds up order processing.
+func (s *WebhookService) PublishOrderEvent(ctx context.Context, shop *db.Shop, order *db.Order, event db.WebhookEvent) error {
+	if s == nil || s.store == nil || shop == nil || order == nil {
+		return nil
+	}
+
+	endpoint, err := s.store.GetEndpoint(ctx, shop.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook endpoint: %w", err)
+	}
+	if endpoint == nil {
+		return nil
+	}
+
+	deliveryID := uuid.New()
+	payload, err := json.Marshal(buildWebhookPayload(deliveryID, shop, order, event, time.Now().UTC()))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delivery := &db.WebhookDelivery{
+		ID:      deliveryID,
+		ShopID:  shop.ID,
+		OrderID: order.ID,
+		Event:   event,
+		URL:     endpoint.URL,
+		Payload: payload,
+	}
+	if err := s.store.CreateDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	observability.MeterFromContext(ctx).Count("webhook.delivery.queued", 1, sentry.WithAttributes(
+		attribute.String("event", string(event)),
+	))
+	return nil
+}
+
+func buildWebhookPayload(deliveryID uuid.UUID, shop *db.Shop, order *db.Order, event db.WebhookEvent, now time.Time) webhookPayload {
+	return webhookPayload{
+		ID:        deliveryID,
+		Event:     event,
+		CreatedAt: now,
+		Shop: webhookShopData{
+			ID:   shop.ID,
+			Repo: shop.GitHubRepoFullName,
+		},
+		Order: NewOrderPayload(order),
+	}
+}
+
+func NewOrderPayload(order *db.Order) OrderPayload {
+	return OrderPayload{
+		ID:              order.ID,
+		Number:          order.OrderNumber,
+		Status:          order.Status,
+		SKU:             order.SKU,
+		Quantity:        orderQuantity(order.Options),
+		Options:         order.Options,
+		SubtotalCents:   order.SubtotalCents,
+		ShippingCents:   order.ShippingCents,
+		TotalCents:      order.TotalCents,
+		CustomerName:    order.CustomerName,
+		CustomerEmail:   order.CustomerEmail,
+		ShippingAddress: order.ShippingAddress,
+		Carrier:         order.Carrier,
+		TrackingNumber:  order.TrackingNumber,
+		IssueNumber:     order.GitHubIssueNumber,
+		IssueURL:        issueURL(order),
+		GitHubUsername:  order.GitHubUsername,
+		CreatedAt:       order.CreatedAt,
+	}
+}
+
+// Run delivers queued webhooks until ctx is cancelled.
+func (s *WebhookService) Run(ctx context.Context) {
+	if s == nil || s.store == nil {
+		return
+	}
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("failed to deliver webhooks", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends every delivery whose next attempt is due, one batch at a time.
+func (s *WebhookService) DeliverDue(ctx context.Context) error {
+	for {
+		dispatches, err := s.store.ClaimDueDeliveries(ctx, s.region, webhookClaimBatchSize, webhookClaimLease)
+		if err != nil {
+			return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+		for _, dispatch := range dispatches {
+			s.deliver(ctx, dispatch)
+		}
+		if len(dispatches) < webhookClaimBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (s *WebhookService) deliver(ctx context.Context, dispatch *db.WebhookDispatch) {
+	delivery := dispatch.Delivery
+	logger := logging.FromContext(ctx, s.logger).With("delivery_id", delivery.ID, "shop_id", delivery.ShopID, "event", delivery.Event)
+	meter := observability.MeterFromContext(ctx)
+
+	responseStatus, sendErr := s.send(ctx, delivery, dispatch.Secret, time.Now())
+	if sendErr == nil {
+		if err := s.store.MarkDeliverySucceeded(ctx, delivery.ID, responseStatus); err != nil {
+			logger.Error("failed to record webhook delivery", "error", err)
+		}
+		meter.Count("webhook.delivery.succeeded", 1)
+		return
+	}
+
+	attempt := delivery.Attempts + 1
+	if attempt >= webhookMaxAttempts {
+		if err := s.store.MarkDeliveryFailed(ctx, delivery.ID, responseStatus, sendErr.Error()); err != nil {
+			logger.Error("failed to record webhook failure", "error", err)
+		}
+		meter.Count("webhook.delivery.failed", 1)
+		logger.Warn("webhook delivery failed permanently", "error", sendErr, "attempts", attempt)
+		return
+	}
+
+	if err := s.store.MarkDeliveryRetry(ctx, delivery.ID, responseStatus, sendErr.Error(), time.Now().Add(webhookBackoff(attempt))); err != nil {
+		logger.Error("failed to schedule webhook retry", "error", err)
+	}
+	meter.Count("webhook.delivery.retried", 1)
+	logger.Info("webhook delivery failed, will retry", "error", sendErr, "attempts", attempt)
+}
+
+func (s *WebhookService) send(ctx context.Context, delivery *db.WebhookDelivery, secret string, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GitShop-Webhooks/1.0")
+	req.Header.Set(webhookEventHeader, string(delivery.Event))
+	req.Header.Set(webhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(webhookSignatureHeader, SignWebhookPayload(secret, now, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value for a payload: the unix timestamp and
+// an HMAC-SHA256 of "timestamp.payload" keyed with the shop's webhook secret.
+func SignWebhookPayload(secret string, at time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// webhookBackoff doubles the wait after each failed attempt, starting at 30 seconds.
+func webhookBackoff(attempt int) time.Duration {
+	backoff := webhookInitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return backoff
+}
+
+type WebhookSettings struct {
+	URL        string
+	HasSecret  bool
+	Deliveries []*db.WebhookDelivery
+}
+
+// GetSettings returns the shop's webhook endpoint and its most recent deliveries.
+func (s *WebhookService) GetSettings(ctx context.Context, shopID uuid.UUID) (*WebhookSettings, error) {
+	settings := &WebhookSettings{}
+	if s == nil || s.store == nil {
+		return settings, nil
+	}
+
+	endpoint, err := s.store.GetEndpoint(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook endpoint: %w", err)
+	}
+	if endpoint != nil {
+		settings.URL = endpoint.URL
+		settings.HasSecret = endpoint.Secret != ""
+	}
+
+	deliveries, err := s.store.ListDeliveries(ctx, shopID, webhookDeliveryLogLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	settings.Deliveries = deliveries
+	return settings, nil
+}
+
+var errWebhookUnavailable = errors.New("webhooks are not available")
+
+// UpdateSettings saves the shop's webhook endpoint. An empty URL removes it; an empty secret
+// keeps the current one.
+func (s *WebhookService) UpdateSettings(ctx context.Context, shopID uuid.UUID, rawURL, secret string) error {
+	rawURL = strings.TrimSpace(rawURL)
+	secret = strings.TrimSpace(secret)
+	var parsed *url.URL
+	if rawURL != "" {
+		var err error
+		parsed, err = url.Parse(rawURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return UserError{Message: "Webhook URL must be a valid https:// URL"}
+		}
+	}
+
+	if s == nil || s.store == nil {
+		return errWebhookUnavailable
+	}
+	if parsed == nil {
+		return s.store.DeleteEndpoint(ctx, shopID)
+	}
+
+	if secret == "" {
+		existing, err := s.store.GetEndpoint(ctx, shopID)
+		if err != nil {
+			return fmt.Errorf("failed to load webhook endpoint: %w", err)
+		}
+		if existing == nil {
+			return UserError{Message: "A signing secret is required"}
+		}
+		secret = existing.Secret
+	}
+	if len(secret) < webhookMinSecretLength {
+		return UserError{Message: fmt.Sprintf("Signing secret must be at least %d characters", webhookMinSecretLength)}
+	}
+
+	return s.store.UpsertEndpoint(ctx, shopID, parsed.String(), secret)
+}
+
+type noopOrderWebhookPublisher struct{}
+
+func (noopOrderWebhookPublisher) PublishOrderEvent(context.Context, *db.Shop, *db.Order, db.WebhookEvent) error {
+	return nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"event":"order.paid"}`)
+	at := time.Unix(1767366245, 0)
+	got := SignWebhookPayload("whsec_0123456789abcdef", at, payload)
+
+	mac := hmac.New(sha256.New, []byte("whsec_0123456789abcdef"))
+	mac.Write([]byte("1767366245." + string(payload)))
+	want := "t=1767366245,v1=" + hex.EncodeToString(mac.Sum(nil))
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if other := SignWebhookPayload("another-secret-value", at, payload); other == got {
+		t.Fatal("expected signature to depend on the secret")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 30 * time.Second},
+		{attempt: 2, want: time.Minute},
+		{attempt: 4, want: 4 * time.Minute},
+		{attempt: 20, want: webhookMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempt); got != tt.want {
+			t.Fatalf("attempt %d: expected %s, got %s", tt.attempt, tt.want, got)
+		}
+	}
+}
+
+func TestBuildWebhookPayload(t *testing.T) {
+	t.Parallel()
+
+	shop, order := samplePreviewOrder(db.StatusShipped)
+	deliveryID := uuid.MustParse("00000000-0000-4000-8000-000000002001")
+	now := time.Date(2026, time.January, 3, 10, 0, 0, 0, time.UTC)
+
+	payload := buildWebhookPayload(deliveryID, shop, order, db.WebhookOrderShipped, now)
+	if payload.ID != deliveryID || payload.Event != db.WebhookOrderShipped || !payload.CreatedAt.Equal(now) {
+		t.Fatalf("unexpected envelope: %+v", payload)
+	}
+	if payload.Shop.Repo != "octo/shop" {
+		t.Fatalf("expected shop repo, got %q", payload.Shop.Repo)
+	}
+	if payload.Order.Number != 1001 || payload.Order.Quantity != 2 || payload.Order.TotalCents != 5500 {
+		t.Fatalf("unexpected order data: %+v", payload.Order)
+	}
+	if payload.Order.Carrier != "USPS" || payload.Order.TrackingNumber == "" {
+		t.Fatalf("expected tracking details, got %+v", payload.Order)
+	}
+}
+
+func TestWebhookServiceSend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     int
+		wantErr    bool
+		wantStatus int
+	}{
+		{name: "success", status: http.StatusNoContent, wantStatus: http.StatusNoContent},
+		{name: "server error", status: http.StatusBadGateway, wantErr: true, wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotSignature, gotEvent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotSignature = r.Header.Get(webhookSignatureHeader)
+				gotEvent = r.Header.Get(webhookEventHeader)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			service := NewWebhookService(nil, "", nil)
+			delivery := &db.WebhookDelivery{
+				ID:      uuid.New(),
+				Event:   db.WebhookOrderPaid,
+				URL:     server.URL,
+				Payload: []byte(`{"event":"order.paid"}`),
+			}
+			now := time.Unix(1767366245, 0)
+			status, err := service.send(t.Context(), delivery, "whsec_0123456789abcdef", now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if gotEvent != string(db.WebhookOrderPaid) {
+				t.Fatalf("expected event header, got %q", gotEvent)
+			}
+			if want := SignWebhookPayload("whsec_0123456789abcdef", now, delivery.Payload); gotSignature != want {
+				t.Fatalf("expected signature %q, got %q", want, gotSignature)
+			}
+		})
+	}
+}
+
+func TestWebhookServiceUpdateSettings_ValidatesURL(t *testing.T) {
+	t.Parallel()
+
+	service := NewWebhookService(nil, "", nil)
+	for _, rawURL := range []string{"http://example.com/hook", "example.com/hook", "https://"} {
+		err := service.UpdateSettings(t.Context(), uuid.New(), rawURL, strings.Repeat("s", webhookMinSecretLength))
+		var userErr UserError
+		if !errors.As(err, &userErr) {
+			t.Fatalf("%q: expected UserError, got %v", rawURL, err)
+		}
+	}
+
+	err := service.UpdateSettings(t.Context(), uuid.New(), "https://example.com/hook", "")
+	if !errors.Is(err, errWebhookUnavailable) {
+		t.Fatalf("expected errWebhookUnavailable, got %v", err)
+	}
+}
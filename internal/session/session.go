@@ -22,6 +22,18 @@ type Data struct {
 	InstallationID int64     `json:"installation_id"`
 	ShopID         uuid.UUID `json:"shop_id"`
 	CreatedAt      int64     `json:"created_at"`
+
+	// ImpersonatedShopID is set while a platform operator views a shop in support mode.
+	ImpersonatedShopID     uuid.UUID `json:"impersonated_shop_id,omitempty"`
+	ImpersonationExpiresAt int64     `json:"impersonation_expires_at,omitempty"`
+}
+
+// Impersonating reports whether the session is in an unexpired support-mode impersonation.
+func (d *Data) Impersonating(now time.Time) bool {
+	if d == nil || d.ImpersonatedShopID == uuid.Nil {
+		return false
+	}
+	return now.Unix() < d.ImpersonationExpiresAt
 }
 
 // Manager handles session creation, validation, and storage
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const cleanupInterval = time.Hour
+
+// Rule expires files under Prefix once they are older than MaxAge.
+type Rule struct {
+	Prefix string
+	MaxAge time.Duration
+}
+
+// DefaultRules keeps downloads only as long as their links can be used, and documents the
+// sellers may come back for somewhat longer.
+var DefaultRules = []Rule{
+	{Prefix: ExportsPrefix, MaxAge: 24 * time.Hour},
+	{Prefix: LabelsPrefix, MaxAge: 30 * 24 * time.Hour},
+	{Prefix: InvoicesPrefix, MaxAge: 400 * 24 * time.Hour},
+}
+
+// Cleanup applies each rule once and returns how many files were removed. A failing rule does
+// not stop the others; the first error is returned.
+func Cleanup(ctx context.Context, provider Provider, rules []Rule, now time.Time) (int, error) {
+	var (
+		deleted  int
+		firstErr error
+	)
+	for _, rule := range rules {
+		if rule.MaxAge <= 0 {
+			continue
+		}
+		removed, err := provider.DeleteOlderThan(ctx, rule.Prefix, now.Add(-rule.MaxAge))
+		deleted += removed
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return deleted, firstErr
+}
+
+// RunCleanup applies rules hourly until ctx is cancelled.
+func RunCleanup(ctx context.Context, provider Provider, rules []Rule, logger *slog.Logger) {
+	if provider == nil || len(rules) == 0 {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	for {
+		deleted, err := Cleanup(ctx, provider, rules, time.Now())
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("failed to clean up stored files", "error", err)
+		}
+		if deleted > 0 {
+			logger.Info("removed expired stored files", "count", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
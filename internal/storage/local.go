@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalPathPrefix is the route that serves signed links to locally stored files.
+const LocalPathPrefix = "/files/"
+
+// LocalProvider keeps files on disk and serves them through signed links to LocalPathPrefix.
+// It suits single-instance deployments; use S3 when several instances share files.
+type LocalProvider struct {
+	dir        string
+	baseURL    string
+	signingKey []byte
+	now        func() time.Time
+}
+
+func NewLocalProvider(dir, baseURL string, signingKey []byte) (*LocalProvider, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("local storage directory is required")
+	}
+	if len(signingKey) == 0 {
+		return nil, fmt.Errorf("local storage signing key is required")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+	}
+	return &LocalProvider{
+		dir:        dir,
+		baseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		signingKey: signingKey,
+		now:        time.Now,
+	}, nil
+}
+
+func (p *LocalProvider) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	target := p.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (p *LocalProvider) Delete(_ context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(p.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (p *LocalProvider) SignedURL(_ context.Context, key, filename string, ttl time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(p.now().Add(ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	if filename != "" {
+		query.Set("filename", filename)
+	}
+	query.Set("signature", p.sign(key, expires, filename))
+	return p.baseURL + LocalPathPrefix + key + "?" + query.Encode(), nil
+}
+
+// ServeSigned writes the file at key when the request carries a valid, unexpired signature.
+func (p *LocalProvider) ServeSigned(w http.ResponseWriter, r *http.Request, key string) {
+	query := r.URL.Query()
+	expires := query.Get("expires")
+	filename := query.Get("filename")
+	if validateKey(key) != nil || !p.verify(key, expires, filename, query.Get("signature")) {
+		http.Error(w, "Link is invalid or has expired", http.StatusForbidden)
+		return
+	}
+
+	file, err := os.Open(p.path(key))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if filename != "" {
+		w.Header().Set("Content-Disposition", attachmentDisposition(filename))
+	}
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, path.Base(key), info.ModTime(), file)
+}
+
+func (p *LocalProvider) DeleteOlderThan(ctx context.Context, prefix string, cutoff time.Time) (int, error) {
+	root := filepath.Join(p.dir, filepath.FromSlash(strings.TrimSuffix(prefix, "/")))
+	if !strings.HasPrefix(root, filepath.Clean(p.dir)) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidKey, prefix)
+	}
+
+	deleted := 0
+	err := filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		deleted++
+		return nil
+	})
+	return deleted, err
+}
+
+func (p *LocalProvider) path(key string) string {
+	return filepath.Join(p.dir, filepath.FromSlash(key))
+}
+
+func (p *LocalProvider) sign(key, expires, filename string) string {
+	mac := hmac.New(sha256.New, p.signingKey)
+	mac.Write([]byte(key + "\n" + expires + "\n" + filename))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *LocalProvider) verify(key, expires, filename, signature string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || p.now().Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(p.sign(key, expires, filename)))
+}
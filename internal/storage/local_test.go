@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLocalProvider(t *testing.T) *LocalProvider {
+	t.Helper()
+
+	provider, err := NewLocalProvider(t.TempDir(), "https://gitshop.example.com/", []byte("signing-key"))
+	if err != nil {
+		t.Fatalf("NewLocalProvider: %v", err)
+	}
+	return provider
+}
+
+func TestLocalProviderServesSignedLinks(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestLocalProvider(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+
+	key := "exports/shop/file.json"
+	if err := provider.Put(t.Context(), key, strings.NewReader(`{"ok":true}`), 11, "application/json"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	signed, err := provider.SignedURL(t.Context(), key, "customer-data.json", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	if !strings.HasPrefix(signed, "https://gitshop.example.com/files/exports/shop/file.json?") {
+		t.Fatalf("unexpected signed URL %q", signed)
+	}
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse signed URL: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		rawQuery   string
+		advance    time.Duration
+		wantStatus int
+	}{
+		{name: "valid", key: key, rawQuery: parsed.RawQuery, wantStatus: http.StatusOK},
+		{name: "expired", key: key, rawQuery: parsed.RawQuery, advance: 2 * time.Minute, wantStatus: http.StatusForbidden},
+		{name: "other key", key: "exports/shop/other.json", rawQuery: parsed.RawQuery, wantStatus: http.StatusForbidden},
+		{name: "changed filename", key: key, rawQuery: strings.Replace(parsed.RawQuery, "customer-data", "renamed", 1), wantStatus: http.StatusForbidden},
+		{name: "traversal", key: "../secret", rawQuery: parsed.RawQuery, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			verifier := *provider
+			verifier.now = func() time.Time { return now.Add(tt.advance) }
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/files/"+tt.key+"?"+tt.rawQuery, nil)
+			verifier.ServeSigned(rec, req, tt.key)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if rec.Body.String() != `{"ok":true}` {
+				t.Fatalf("unexpected body %q", rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="customer-data.json"` {
+				t.Fatalf("unexpected Content-Disposition %q", got)
+			}
+		})
+	}
+}
+
+func TestLocalProviderRejectsInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestLocalProvider(t)
+	for _, key := range []string{"", "/abs", "../up", "exports/../up", "exports//double", ".hidden"} {
+		if err := provider.Put(t.Context(), key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Fatalf("expected key %q to be rejected", key)
+		}
+	}
+}
+
+func TestCleanupRemovesExpiredFiles(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestLocalProvider(t)
+	now := time.Now()
+	files := map[string]time.Duration{
+		"exports/shop/old.csv": 48 * time.Hour,
+		"exports/shop/new.csv": time.Hour,
+		"labels/shop/old.pdf":  48 * time.Hour,
+	}
+	for key, age := range files {
+		if err := provider.Put(t.Context(), key, strings.NewReader("x"), 1, ""); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+		modTime := now.Add(-age)
+		if err := os.Chtimes(provider.path(key), modTime, modTime); err != nil {
+			t.Fatalf("Chtimes %s: %v", key, err)
+		}
+	}
+
+	rules := []Rule{{Prefix: ExportsPrefix, MaxAge: 24 * time.Hour}, {Prefix: InvoicesPrefix, MaxAge: time.Hour}}
+	deleted, err := Cleanup(t.Context(), provider, rules, now)
+	if err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected one removed file, got %d", deleted)
+	}
+	for key, wantExists := range map[string]bool{"exports/shop/old.csv": false, "exports/shop/new.csv": true, "labels/shop/old.pdf": true} {
+		_, err := os.Stat(filepath.Join(provider.dir, filepath.FromSlash(key)))
+		if exists := err == nil; exists != wantExists {
+			t.Fatalf("expected %s exists=%v", key, wantExists)
+		}
+	}
+}
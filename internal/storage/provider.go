@@ -0,0 +1,83 @@
+// Package storage keeps generated files such as exports, invoices, and shipping labels.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid file key")
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*(/[A-Za-z0-9_-][A-Za-z0-9._-]*)*$`)
+
+// Key prefixes group files by the feature that creates them, so lifecycle rules can expire each
+// kind on its own schedule.
+const (
+	ExportsPrefix  = "exports/"
+	InvoicesPrefix = "invoices/"
+	LabelsPrefix   = "labels/"
+	// WarehousePrefix holds the daily analytics exports. It has no default lifecycle rule, since
+	// the warehouse loads from it and operators decide how long to keep it.
+	WarehousePrefix = "warehouse/"
+)
+
+// Provider stores files under slash-separated keys and hands out time-limited download links.
+type Provider interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a link that downloads the file as filename until ttl has passed.
+	SignedURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error)
+	// DeleteOlderThan removes files under prefix last written before cutoff and returns how many
+	// were removed.
+	DeleteOlderThan(ctx context.Context, prefix string, cutoff time.Time) (int, error)
+}
+
+type Config struct {
+	Provider string
+	// BaseURL and SigningKey sign download links for locally stored files.
+	BaseURL    string
+	SigningKey []byte
+	LocalDir   string
+	S3         S3Config
+}
+
+func NewProvider(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case "local", "":
+		return NewLocalProvider(cfg.LocalDir, cfg.BaseURL, cfg.SigningKey)
+	case "s3":
+		return NewS3Provider(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unsupported storage provider: %s", cfg.Provider)
+	}
+}
+
+func validateKey(key string) error {
+	if len(key) > 512 || !keyPattern.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// attachmentDisposition builds a Content-Disposition header value, dropping characters that
+// could break out of the quoted filename.
+func attachmentDisposition(filename string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, filename)
+	if cleaned == "" {
+		return "attachment"
+	}
+	return fmt.Sprintf(`attachment; filename="%s"`, cleaned)
+}
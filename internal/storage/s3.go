@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Timeout        = 30 * time.Second
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3MaxPresignTTL  = 7 * 24 * time.Hour
+	s3AmzDateLayout  = "20060102T150405Z"
+	s3DateOnlyLayout = "20060102"
+)
+
+// S3Config points at an S3-compatible bucket. PathStyle addresses the bucket in the URL path
+// instead of the host name, which most self-hosted implementations need.
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+}
+
+// S3Provider stores files in an S3-compatible bucket, signing requests with AWS Signature
+// Version 4.
+type S3Provider struct {
+	endpoint   *url.URL
+	cfg        S3Config
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func NewS3Provider(cfg S3Config) (*S3Provider, error) {
+	if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 storage requires a bucket and credentials")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	if endpoint == "" {
+		endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	parsed, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	return &S3Provider{
+		endpoint:   parsed,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: s3Timeout},
+		now:        time.Now,
+	}, nil
+}
+
+func (p *S3Provider) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.objectURL(key, nil).String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	_, err = p.do(req)
+	return err
+}
+
+func (p *S3Provider) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, p.objectURL(key, nil).String(), nil)
+	if err != nil {
+		return err
+	}
+	_, err = p.do(req)
+	return err
+}
+
+func (p *S3Provider) SignedURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error) {
+	_ = ctx
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	if ttl <= 0 || ttl > s3MaxPresignTTL {
+		return "", fmt.Errorf("s3 signed links must expire within %s", s3MaxPresignTTL)
+	}
+
+	now := p.now().UTC()
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", p.cfg.AccessKeyID+"/"+p.scope(now))
+	query.Set("X-Amz-Date", now.Format(s3AmzDateLayout))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if filename != "" {
+		query.Set("response-content-disposition", attachmentDisposition(filename))
+	}
+
+	target := p.objectURL(key, query)
+	headers := http.Header{}
+	signature := p.signature(now, http.MethodGet, target, headers, target.Host, s3UnsignedBody)
+	target.RawQuery += "&X-Amz-Signature=" + signature
+	return target.String(), nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (p *S3Provider) DeleteOlderThan(ctx context.Context, prefix string, cutoff time.Time) (int, error) {
+	deleted := 0
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.bucketURL(query).String(), nil)
+		if err != nil {
+			return deleted, err
+		}
+		body, err := p.do(req)
+		if err != nil {
+			return deleted, err
+		}
+		var result s3ListResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return deleted, fmt.Errorf("failed to decode s3 object list: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			if !object.LastModified.Before(cutoff) {
+				continue
+			}
+			if err := p.Delete(ctx, object.Key); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return deleted, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (p *S3Provider) do(req *http.Request) ([]byte, error) {
+	now := p.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3AmzDateLayout))
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedBody)
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		signed = append(signed, "content-type")
+	}
+	sort.Strings(signed)
+
+	headers := http.Header{}
+	for _, name := range signed {
+		if name != "host" {
+			headers.Set(name, req.Header.Get(name))
+		}
+	}
+	signature := p.signature(now, req.Method, req.URL, headers, req.URL.Host, s3UnsignedBody)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.cfg.AccessKeyID, p.scope(now), strings.Join(signed, ";"), signature,
+	))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound && req.Method == http.MethodDelete {
+		return body, nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("s3 %s returned %d: %s", req.Method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func (p *S3Provider) bucketURL(query url.Values) *url.URL {
+	target := *p.endpoint
+	if p.cfg.PathStyle {
+		target.Path = p.endpoint.Path + "/" + p.cfg.Bucket + "/"
+	} else {
+		target.Host = p.cfg.Bucket + "." + p.endpoint.Host
+		target.Path = p.endpoint.Path + "/"
+	}
+	target.RawQuery = canonicalQuery(query)
+	return &target
+}
+
+func (p *S3Provider) objectURL(key string, query url.Values) *url.URL {
+	target := p.bucketURL(query)
+	target.Path += key
+	target.RawPath = escapePath(target.Path)
+	return target
+}
+
+func (p *S3Provider) scope(now time.Time) string {
+	return now.Format(s3DateOnlyLayout) + "/" + p.cfg.Region + "/s3/aws4_request"
+}
+
+// signature computes the Signature Version 4 signature for a request. headers holds the signed
+// headers other than host.
+func (p *S3Provider) signature(now time.Time, method string, target *url.URL, headers http.Header, host, payloadHash string) string {
+	names := []string{"host"}
+	values := map[string]string{"host": host}
+	for name := range headers {
+		lower := strings.ToLower(name)
+		names = append(names, lower)
+		values[lower] = strings.TrimSpace(headers.Get(name))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		escapePath(target.Path),
+		target.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(names, ";"),
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format(s3AmzDateLayout),
+		p.scope(now),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+p.cfg.SecretAccessKey), now.Format(s3DateOnlyLayout))
+	key = hmacSHA256(key, p.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters sorted by name with RFC 3986 escaping, as Signature
+// Version 4 requires.
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEscape(name)+"="+uriEscape(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = uriEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func uriEscape(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
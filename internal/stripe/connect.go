@@ -4,31 +4,42 @@ package stripe
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stripe/stripe-go/v84"
 
+	"github.com/gitshopapp/gitshop/internal/logging"
 	"github.com/gitshopapp/gitshop/internal/observability"
 )
 
 // PlatformClient handles Stripe Connect platform operations
 type PlatformClient struct {
-	client   *stripe.Client
-	clientID string
-	baseURL  string
+	client *stripe.Client
+	// testClient uses the platform's test keys for shops in test mode. It is nil when no test
+	// key is configured.
+	testClient *stripe.Client
+	clientID   string
+	baseURL    string
 }
 
-// NewPlatformClient creates a new Stripe Connect client
-func NewPlatformClient(secretKey, clientID, baseURL string) *PlatformClient {
+// NewPlatformClient creates a new Stripe Connect client. testSecretKey may be empty, in which
+// case test mode is unavailable.
+func NewPlatformClient(secretKey, testSecretKey, clientID, baseURL string, logger *slog.Logger) *PlatformClient {
 	httpClient := observability.NewHTTPClient(20 * time.Second)
+	httpClient.Transport = logging.RoundTripper(httpClient.Transport, "stripe", logger)
 	backends := stripe.NewBackends(httpClient)
 
-	return &PlatformClient{
+	client := &PlatformClient{
 		client:   stripe.NewClient(secretKey, stripe.WithBackends(backends)),
 		clientID: clientID,
 		baseURL:  baseURL,
 	}
+	if testSecretKey != "" {
+		client.testClient = stripe.NewClient(testSecretKey, stripe.WithBackends(backends))
+	}
+	return client
 }
 
 // CreateAccount creates a Standard connected account for a seller
@@ -84,8 +95,12 @@ func (c *PlatformClient) GetAccount(ctx context.Context, accountID string) (*str
 	if ctx == nil {
 		return nil, fmt.Errorf("context is required")
 	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	account, err := c.client.V1Accounts.GetByID(ctx, accountID, nil)
+	account, err := api.V1Accounts.GetByID(ctx, accountID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
@@ -125,6 +140,51 @@ type CheckoutSessionParams struct {
 	SuccessURL      string
 	CancelURL       string
 	StripeAccountID string // For Stripe Connect
+	TermsURL        string
+	TermsVersion    string
+	RequireConsent  bool // Require the Stripe terms of service checkbox
+	ExpiresAt       time.Time
+	Billing         string // "monthly" or "yearly" to start a subscription instead of a one-time payment
+	CustomFields    []CheckoutCustomField
+	CollectTaxID    bool // Let business buyers enter a tax ID
+}
+
+// CheckoutCustomField is an extra question the checkout asks the buyer, such as a VAT ID.
+type CheckoutCustomField struct {
+	Key      string
+	Label    string
+	Numeric  bool
+	Optional bool
+}
+
+func (f CheckoutCustomField) fieldType() string {
+	if f.Numeric {
+		return string(stripe.CheckoutSessionCustomFieldTypeNumeric)
+	}
+	return string(stripe.CheckoutSessionCustomFieldTypeText)
+}
+
+// IsSubscription reports whether the checkout starts a recurring subscription.
+func (p CheckoutSessionParams) IsSubscription() bool {
+	return recurringInterval(p.Billing) != ""
+}
+
+func recurringInterval(billing string) string {
+	switch billing {
+	case "monthly":
+		return string(stripe.PriceRecurringIntervalMonth)
+	case "yearly":
+		return string(stripe.PriceRecurringIntervalYear)
+	default:
+		return ""
+	}
+}
+
+func shippingDisplayName(params CheckoutSessionParams) string {
+	if params.ShippingCents == 0 {
+		return fmt.Sprintf("Free shipping (%s)", params.ShippingCarrier)
+	}
+	return fmt.Sprintf("Shipping (%s)", params.ShippingCarrier)
 }
 
 // CreateCheckoutSession creates a checkout session for an order
@@ -132,6 +192,10 @@ func (c *PlatformClient) CreateCheckoutSession(ctx context.Context, params Check
 	if ctx == nil {
 		return nil, fmt.Errorf("context is required")
 	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	if params.Quantity <= 0 {
 		params.Quantity = 1
@@ -157,7 +221,7 @@ func (c *PlatformClient) CreateCheckoutSession(ctx context.Context, params Check
 		ShippingOptions: []*stripe.CheckoutSessionCreateShippingOptionParams{
 			{
 				ShippingRateData: &stripe.CheckoutSessionCreateShippingOptionShippingRateDataParams{
-					DisplayName: stripe.String(fmt.Sprintf("Shipping (%s)", params.ShippingCarrier)),
+					DisplayName: stripe.String(shippingDisplayName(params)),
 					Type:        stripe.String(string(stripe.ShippingRateTypeFixedAmount)),
 					FixedAmount: &stripe.CheckoutSessionCreateShippingOptionShippingRateDataFixedAmountParams{
 						Amount:   stripe.Int64(params.ShippingCents),
@@ -172,29 +236,488 @@ func (c *PlatformClient) CreateCheckoutSession(ctx context.Context, params Check
 		ShippingAddressCollection: &stripe.CheckoutSessionCreateShippingAddressCollectionParams{
 			AllowedCountries: stripe.StringSlice([]string{"US"}),
 		},
+		// A paid invoice gives the buyer a downloadable receipt for their VAT records.
+		InvoiceCreation: &stripe.CheckoutSessionCreateInvoiceCreationParams{
+			Enabled: stripe.Bool(true),
+		},
 		// Customer email is optional. Only send if present to avoid Stripe validation errors.
 		CustomerEmail: stripe.String(params.CustomerEmail),
-		Metadata: map[string]string{
-			"order_id":              params.OrderID.String(),
-			"shop_id":               params.ShopID.String(),
-			"github_issue_number":   fmt.Sprintf("%d", params.IssueNumber),
-			"github_repo_full_name": params.RepoFullName,
-		},
+		Metadata:      checkoutMetadata(params),
 	}
 
 	if params.CustomerEmail == "" {
 		sessionParams.CustomerEmail = nil
 	}
 
+	for _, field := range params.CustomFields {
+		sessionParams.CustomFields = append(sessionParams.CustomFields, &stripe.CheckoutSessionCreateCustomFieldParams{
+			Key: stripe.String(field.Key),
+			Label: &stripe.CheckoutSessionCreateCustomFieldLabelParams{
+				Type:   stripe.String(string(stripe.CheckoutSessionCustomFieldLabelTypeCustom)),
+				Custom: stripe.String(field.Label),
+			},
+			Type:     stripe.String(field.fieldType()),
+			Optional: stripe.Bool(field.Optional),
+		})
+	}
+
+	if params.CollectTaxID {
+		// Tax IDs are saved on a Stripe customer, which payment-mode sessions only create on request.
+		sessionParams.TaxIDCollection = &stripe.CheckoutSessionCreateTaxIDCollectionParams{
+			Enabled: stripe.Bool(true),
+		}
+		sessionParams.CustomerCreation = stripe.String(string(stripe.CheckoutSessionCustomerCreationAlways))
+	}
+
+	if interval := recurringInterval(params.Billing); interval != "" {
+		// Subscription sessions cannot take shipping rates, so shipping renews as its own line item.
+		// They always produce invoices and customers, so neither is requested.
+		sessionParams.Mode = stripe.String(string(stripe.CheckoutSessionModeSubscription))
+		sessionParams.ShippingOptions = nil
+		sessionParams.InvoiceCreation = nil
+		sessionParams.CustomerCreation = nil
+		sessionParams.LineItems[0].PriceData.Recurring = &stripe.CheckoutSessionCreateLineItemPriceDataRecurringParams{
+			Interval: stripe.String(interval),
+		}
+		if params.ShippingCents > 0 {
+			sessionParams.LineItems = append(sessionParams.LineItems, &stripe.CheckoutSessionCreateLineItemParams{
+				PriceData: &stripe.CheckoutSessionCreateLineItemPriceDataParams{
+					Currency: stripe.String("usd"),
+					ProductData: &stripe.CheckoutSessionCreateLineItemPriceDataProductDataParams{
+						Name: stripe.String(shippingDisplayName(params)),
+					},
+					UnitAmount: stripe.Int64(params.ShippingCents),
+					Recurring: &stripe.CheckoutSessionCreateLineItemPriceDataRecurringParams{
+						Interval: stripe.String(interval),
+					},
+				},
+				Quantity: stripe.Int64(1),
+			})
+		}
+		// Renewal invoices carry the subscription metadata, which ties them back to the order.
+		sessionParams.SubscriptionData = &stripe.CheckoutSessionCreateSubscriptionDataParams{
+			Metadata: checkoutMetadata(params),
+		}
+	}
+
+	if !params.ExpiresAt.IsZero() {
+		sessionParams.ExpiresAt = stripe.Int64(params.ExpiresAt.Unix())
+	}
+
+	if params.RequireConsent {
+		sessionParams.ConsentCollection = &stripe.CheckoutSessionCreateConsentCollectionParams{
+			TermsOfService: stripe.String(string(stripe.CheckoutSessionConsentCollectionTermsOfServiceRequired)),
+		}
+		if params.TermsURL != "" {
+			sessionParams.CustomText = &stripe.CheckoutSessionCreateCustomTextParams{
+				TermsOfServiceAcceptance: &stripe.CheckoutSessionCreateCustomTextTermsOfServiceAcceptanceParams{
+					Message: stripe.String(fmt.Sprintf("I agree to the [terms of sale](%s) (version %s).", params.TermsURL, params.TermsVersion)),
+				},
+			}
+		}
+	}
+
 	// Use Stripe Connect if shop has connected account
 	if params.StripeAccountID != "" {
 		sessionParams.SetStripeAccount(params.StripeAccountID)
 	}
 
-	sess, err := c.client.V1CheckoutSessions.Create(ctx, sessionParams)
+	sess, err := api.V1CheckoutSessions.Create(ctx, sessionParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create checkout session: %w", err)
 	}
 
 	return sess, nil
 }
+
+// CreatePaymentLink creates a single-use Payment Link for the same items as a checkout session.
+// Payment Links cannot take inline shipping rates, so shipping is charged as its own line item.
+// The link carries the order metadata, which Stripe copies onto the checkout session it creates.
+func (c *PlatformClient) CreatePaymentLink(ctx context.Context, params CheckoutSessionParams) (*stripe.PaymentLink, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context is required")
+	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Quantity <= 0 {
+		params.Quantity = 1
+	}
+
+	metadata := checkoutMetadata(params)
+	linkParams := &stripe.PaymentLinkCreateParams{
+		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
+		LineItems: []*stripe.PaymentLinkCreateLineItemParams{
+			{
+				PriceData: &stripe.PaymentLinkCreateLineItemPriceDataParams{
+					Currency: stripe.String("usd"),
+					ProductData: &stripe.PaymentLinkCreateLineItemPriceDataProductDataParams{
+						Name: stripe.String(params.ProductName),
+					},
+					UnitAmount: stripe.Int64(params.UnitPriceCents),
+				},
+				Quantity: stripe.Int64(params.Quantity),
+			},
+		},
+		AfterCompletion: &stripe.PaymentLinkCreateAfterCompletionParams{
+			Type: stripe.String(string(stripe.PaymentLinkAfterCompletionTypeRedirect)),
+			Redirect: &stripe.PaymentLinkCreateAfterCompletionRedirectParams{
+				URL: stripe.String(params.SuccessURL),
+			},
+		},
+		AutomaticTax: &stripe.PaymentLinkCreateAutomaticTaxParams{
+			Enabled: stripe.Bool(true),
+		},
+		ShippingAddressCollection: &stripe.PaymentLinkCreateShippingAddressCollectionParams{
+			AllowedCountries: stripe.StringSlice([]string{"US"}),
+		},
+		Restrictions: &stripe.PaymentLinkCreateRestrictionsParams{
+			CompletedSessions: &stripe.PaymentLinkCreateRestrictionsCompletedSessionsParams{
+				Limit: stripe.Int64(1),
+			},
+		},
+		PaymentIntentData: &stripe.PaymentLinkCreatePaymentIntentDataParams{
+			Metadata: metadata,
+		},
+		InvoiceCreation: &stripe.PaymentLinkCreateInvoiceCreationParams{
+			Enabled: stripe.Bool(true),
+		},
+		Metadata: metadata,
+	}
+	for _, field := range params.CustomFields {
+		linkParams.CustomFields = append(linkParams.CustomFields, &stripe.PaymentLinkCreateCustomFieldParams{
+			Key: stripe.String(field.Key),
+			Label: &stripe.PaymentLinkCreateCustomFieldLabelParams{
+				Type:   stripe.String(string(stripe.PaymentLinkCustomFieldLabelTypeCustom)),
+				Custom: stripe.String(field.Label),
+			},
+			Type:     stripe.String(field.fieldType()),
+			Optional: stripe.Bool(field.Optional),
+		})
+	}
+	if params.CollectTaxID {
+		linkParams.TaxIDCollection = &stripe.PaymentLinkCreateTaxIDCollectionParams{
+			Enabled: stripe.Bool(true),
+		}
+		linkParams.CustomerCreation = stripe.String(string(stripe.PaymentLinkCustomerCreationAlways))
+	}
+	if params.ShippingCents > 0 {
+		linkParams.LineItems = append(linkParams.LineItems, &stripe.PaymentLinkCreateLineItemParams{
+			PriceData: &stripe.PaymentLinkCreateLineItemPriceDataParams{
+				Currency: stripe.String("usd"),
+				ProductData: &stripe.PaymentLinkCreateLineItemPriceDataProductDataParams{
+					Name: stripe.String(fmt.Sprintf("Shipping (%s)", params.ShippingCarrier)),
+				},
+				UnitAmount: stripe.Int64(params.ShippingCents),
+			},
+			Quantity: stripe.Int64(1),
+		})
+	}
+
+	if params.RequireConsent {
+		linkParams.ConsentCollection = &stripe.PaymentLinkCreateConsentCollectionParams{
+			TermsOfService: stripe.String(string(stripe.PaymentLinkConsentCollectionTermsOfServiceRequired)),
+		}
+		if params.TermsURL != "" {
+			linkParams.CustomText = &stripe.PaymentLinkCreateCustomTextParams{
+				TermsOfServiceAcceptance: &stripe.PaymentLinkCreateCustomTextTermsOfServiceAcceptanceParams{
+					Message: stripe.String(fmt.Sprintf("I agree to the [terms of sale](%s) (version %s).", params.TermsURL, params.TermsVersion)),
+				},
+			}
+		}
+	}
+
+	if params.StripeAccountID != "" {
+		linkParams.SetStripeAccount(params.StripeAccountID)
+	}
+
+	link, err := api.V1PaymentLinks.Create(ctx, linkParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment link: %w", err)
+	}
+
+	return link, nil
+}
+
+// DeactivatePaymentLink stops a Payment Link from accepting further payments.
+func (c *PlatformClient) DeactivatePaymentLink(ctx context.Context, accountID, paymentLinkID string) error {
+	api, err := c.api(ctx)
+	if err != nil {
+		return err
+	}
+	params := &stripe.PaymentLinkUpdateParams{
+		Active: stripe.Bool(false),
+	}
+	if accountID != "" {
+		params.SetStripeAccount(accountID)
+	}
+
+	if _, err := api.V1PaymentLinks.Update(ctx, paymentLinkID, params); err != nil {
+		return fmt.Errorf("failed to deactivate payment link: %w", err)
+	}
+	return nil
+}
+
+// ExpireCheckoutSession closes an open Checkout Session so it can no longer be paid. It fails
+// when the session is already complete.
+func (c *PlatformClient) ExpireCheckoutSession(ctx context.Context, accountID, sessionID string) error {
+	api, err := c.api(ctx)
+	if err != nil {
+		return err
+	}
+	params := &stripe.CheckoutSessionExpireParams{}
+	if accountID != "" {
+		params.SetStripeAccount(accountID)
+	}
+
+	if _, err := api.V1CheckoutSessions.Expire(ctx, sessionID, params); err != nil {
+		return fmt.Errorf("failed to expire checkout session: %w", err)
+	}
+	return nil
+}
+
+// CancelSubscription cancels a subscription immediately, so the buyer is not charged again.
+func (c *PlatformClient) CancelSubscription(ctx context.Context, accountID, subscriptionID string) error {
+	api, err := c.api(ctx)
+	if err != nil {
+		return err
+	}
+	params := &stripe.SubscriptionCancelParams{}
+	if accountID != "" {
+		params.SetStripeAccount(accountID)
+	}
+
+	if _, err := api.V1Subscriptions.Cancel(ctx, subscriptionID, params); err != nil {
+		return fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+	return nil
+}
+
+// GetInvoice fetches an invoice from a connected account.
+func (c *PlatformClient) GetInvoice(ctx context.Context, accountID, invoiceID string) (*stripe.Invoice, error) {
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params := &stripe.InvoiceRetrieveParams{}
+	if accountID != "" {
+		params.SetStripeAccount(accountID)
+	}
+
+	invoice, err := api.V1Invoices.Retrieve(ctx, invoiceID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+func checkoutMetadata(params CheckoutSessionParams) map[string]string {
+	metadata := map[string]string{
+		"order_id":              params.OrderID.String(),
+		"shop_id":               params.ShopID.String(),
+		"github_issue_number":   fmt.Sprintf("%d", params.IssueNumber),
+		"github_repo_full_name": params.RepoFullName,
+	}
+	if params.TermsVersion != "" {
+		metadata["terms_version"] = params.TermsVersion
+	}
+	return metadata
+}
+
+// CreateRefund issues a full refund for a payment intent on a connected account
+func (c *PlatformClient) CreateRefund(ctx context.Context, accountID, paymentIntentID string) (*stripe.Refund, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context is required")
+	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &stripe.RefundCreateParams{
+		PaymentIntent: stripe.String(paymentIntentID),
+	}
+	if accountID != "" {
+		params.SetStripeAccount(accountID)
+	}
+
+	refund, err := api.V1Refunds.Create(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refund: %w", err)
+	}
+
+	return refund, nil
+}
+
+type IdentityVerificationParams struct {
+	OrderID         uuid.UUID
+	ShopID          uuid.UUID
+	IssueNumber     int
+	RepoFullName    string
+	ReturnURL       string
+	StripeAccountID string
+}
+
+// CreateIdentityVerification starts a Stripe Identity document check for a restricted order
+func (c *PlatformClient) CreateIdentityVerification(ctx context.Context, params IdentityVerificationParams) (*stripe.IdentityVerificationSession, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context is required")
+	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionParams := &stripe.IdentityVerificationSessionCreateParams{
+		Type:      stripe.String(string(stripe.IdentityVerificationSessionTypeDocument)),
+		ReturnURL: stripe.String(params.ReturnURL),
+		Metadata: map[string]string{
+			"order_id":              params.OrderID.String(),
+			"shop_id":               params.ShopID.String(),
+			"github_issue_number":   fmt.Sprintf("%d", params.IssueNumber),
+			"github_repo_full_name": params.RepoFullName,
+		},
+	}
+	if params.StripeAccountID != "" {
+		sessionParams.SetStripeAccount(params.StripeAccountID)
+	}
+
+	session, err := api.V1IdentityVerificationSessions.Create(ctx, sessionParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create identity verification: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetIdentityVerification loads a verification session with the verified date of birth expanded
+func (c *PlatformClient) GetIdentityVerification(ctx context.Context, accountID, sessionID string) (*stripe.IdentityVerificationSession, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context is required")
+	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &stripe.IdentityVerificationSessionRetrieveParams{}
+	params.AddExpand("verified_outputs.dob")
+	if accountID != "" {
+		params.SetStripeAccount(accountID)
+	}
+
+	session, err := api.V1IdentityVerificationSessions.Retrieve(ctx, sessionID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity verification: %w", err)
+	}
+
+	return session, nil
+}
+
+type InvoiceParams struct {
+	OrderID         uuid.UUID
+	ShopID          uuid.UUID
+	IssueNumber     int
+	RepoFullName    string
+	Description     string
+	CustomerEmail   string
+	AmountCents     int64
+	ShippingCents   int64
+	DaysUntilDue    int64
+	StripeAccountID string
+}
+
+// CreateInvoice creates, finalizes, and emails an invoice for a quoted order
+func (c *PlatformClient) CreateInvoice(ctx context.Context, params InvoiceParams) (*stripe.Invoice, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("context is required")
+	}
+	api, err := c.api(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if params.DaysUntilDue <= 0 {
+		params.DaysUntilDue = 30
+	}
+
+	metadata := map[string]string{
+		"order_id":              params.OrderID.String(),
+		"shop_id":               params.ShopID.String(),
+		"github_issue_number":   fmt.Sprintf("%d", params.IssueNumber),
+		"github_repo_full_name": params.RepoFullName,
+	}
+
+	customerParams := &stripe.CustomerCreateParams{
+		Email:    stripe.String(params.CustomerEmail),
+		Metadata: metadata,
+	}
+	if params.StripeAccountID != "" {
+		customerParams.SetStripeAccount(params.StripeAccountID)
+	}
+	customer, err := api.V1Customers.Create(ctx, customerParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	invoiceParams := &stripe.InvoiceCreateParams{
+		Customer:         stripe.String(customer.ID),
+		CollectionMethod: stripe.String(string(stripe.InvoiceCollectionMethodSendInvoice)),
+		DaysUntilDue:     stripe.Int64(params.DaysUntilDue),
+		Description:      stripe.String(params.Description),
+		Metadata:         metadata,
+	}
+	if params.StripeAccountID != "" {
+		invoiceParams.SetStripeAccount(params.StripeAccountID)
+	}
+	invoice, err := api.V1Invoices.Create(ctx, invoiceParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	items := []struct {
+		description string
+		amount      int64
+	}{
+		{description: params.Description, amount: params.AmountCents},
+		{description: "Shipping", amount: params.ShippingCents},
+	}
+	for _, item := range items {
+		if item.amount <= 0 {
+			continue
+		}
+		itemParams := &stripe.InvoiceItemCreateParams{
+			Customer:    stripe.String(customer.ID),
+			Invoice:     stripe.String(invoice.ID),
+			Amount:      stripe.Int64(item.amount),
+			Currency:    stripe.String("usd"),
+			Description: stripe.String(item.description),
+		}
+		if params.StripeAccountID != "" {
+			itemParams.SetStripeAccount(params.StripeAccountID)
+		}
+		if _, err := api.V1InvoiceItems.Create(ctx, itemParams); err != nil {
+			return nil, fmt.Errorf("failed to add invoice item: %w", err)
+		}
+	}
+
+	finalizeParams := &stripe.InvoiceFinalizeInvoiceParams{}
+	if params.StripeAccountID != "" {
+		finalizeParams.SetStripeAccount(params.StripeAccountID)
+	}
+	if _, err := api.V1Invoices.FinalizeInvoice(ctx, invoice.ID, finalizeParams); err != nil {
+		return nil, fmt.Errorf("failed to finalize invoice: %w", err)
+	}
+
+	sendParams := &stripe.InvoiceSendInvoiceParams{}
+	if params.StripeAccountID != "" {
+		sendParams.SetStripeAccount(params.StripeAccountID)
+	}
+	sent, err := api.V1Invoices.SendInvoice(ctx, invoice.ID, sendParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send invoice: %w", err)
+	}
+
+	return sent, nil
+}
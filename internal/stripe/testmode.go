@@ -0,0 +1,42 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stripe/stripe-go/v84"
+)
+
+// ErrTestModeUnavailable means a test mode call was made but no Stripe test key is configured.
+var ErrTestModeUnavailable = errors.New("stripe test mode is not configured")
+
+type testModeKey struct{}
+
+// WithTestMode marks calls made with the returned context as test mode calls, which go through
+// the platform's test keys. Connected accounts work in both modes, so a shop can try checkout
+// without being onboarded again.
+func WithTestMode(ctx context.Context, testMode bool) context.Context {
+	return context.WithValue(ctx, testModeKey{}, testMode)
+}
+
+// TestMode reports whether the context was marked by WithTestMode.
+func TestMode(ctx context.Context) bool {
+	testMode, _ := ctx.Value(testModeKey{}).(bool)
+	return testMode
+}
+
+// TestModeAvailable reports whether a Stripe test key is configured.
+func (c *PlatformClient) TestModeAvailable() bool {
+	return c != nil && c.testClient != nil
+}
+
+// api returns the client for the context's mode. Account onboarding always uses the live client.
+func (c *PlatformClient) api(ctx context.Context) (*stripe.Client, error) {
+	if !TestMode(ctx) {
+		return c.client, nil
+	}
+	if c.testClient == nil {
+		return nil, ErrTestModeUnavailable
+	}
+	return c.testClient, nil
+}
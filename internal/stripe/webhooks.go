@@ -10,7 +10,9 @@ import (
 	"github.com/stripe/stripe-go/v84/webhook"
 )
 
-func ReadWebhookEvent(r *http.Request, secret string) (*stripeapi.Event, error) {
+// ReadWebhookEvent verifies the request against each non-empty secret in turn, so one endpoint can
+// take events signed for both the live and the test mode webhook.
+func ReadWebhookEvent(r *http.Request, secrets ...string) (*stripeapi.Event, error) {
 	signature := r.Header.Get("Stripe-Signature")
 	if signature == "" {
 		return nil, fmt.Errorf("missing stripe signature header")
@@ -21,10 +23,16 @@ func ReadWebhookEvent(r *http.Request, secret string) (*stripeapi.Event, error)
 		return nil, fmt.Errorf("failed to read request body: %w", err)
 	}
 
-	event, err := webhook.ConstructEvent(payload, signature, secret)
-	if err != nil {
-		return nil, fmt.Errorf("webhook signature validation failed: %w", err)
+	err = fmt.Errorf("no webhook secret configured")
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		var event stripeapi.Event
+		event, err = webhook.ConstructEvent(payload, signature, secret)
+		if err == nil {
+			return &event, nil
+		}
 	}
-
-	return &event, nil
+	return nil, fmt.Errorf("webhook signature validation failed: %w", err)
 }
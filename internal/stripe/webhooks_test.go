@@ -43,3 +43,31 @@ func TestReadWebhookEvent_Valid(t *testing.T) {
 		t.Fatalf("unexpected event: %+v", event)
 	}
 }
+
+func TestReadWebhookEvent_TestModeSecret(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"id":"evt_test","object":"event","api_version":"2026-01-28.clover","livemode":false,"type":"checkout.session.completed","data":{"object":{"id":"cs_test","object":"checkout.session"}}}`)
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{
+		Payload:   payload,
+		Secret:    "whsec_test_mode",
+		Timestamp: time.Now(),
+		Scheme:    "v1",
+	})
+
+	req := httptest.NewRequest("POST", "/webhooks/stripe", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signed.Header)
+	event, err := ReadWebhookEvent(req, "whsec_live", "whsec_test_mode")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.ID != "evt_test" || event.Livemode {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	req = httptest.NewRequest("POST", "/webhooks/stripe", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signed.Header)
+	if _, err := ReadWebhookEvent(req, "whsec_live", ""); err == nil {
+		t.Fatal("expected error for event signed with an unknown secret")
+	}
+}
@@ -0,0 +1,167 @@
+// Package webhooktap mirrors inbound webhooks and their processing results to live debugging
+// subscribers on this instance.
+package webhooktap
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	KindReceived = "received"
+	KindResult   = "result"
+
+	redacted = "[redacted]"
+)
+
+type Event struct {
+	Time       time.Time       `json:"time"`
+	Kind       string          `json:"kind"`
+	Provider   string          `json:"provider"`
+	DeliveryID string          `json:"delivery_id"`
+	EventType  string          `json:"event_type"`
+	Outcome    string          `json:"outcome,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DurationMS int64           `json:"duration_ms,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// Tap fans events out to subscribers. Slow subscribers miss events rather than holding up
+// webhook processing.
+type Tap struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	subscribers map[uint64]chan Event
+	closed      bool
+}
+
+func New() *Tap {
+	return &Tap{subscribers: make(map[uint64]chan Event)}
+}
+
+// Active reports whether anyone is listening, so callers can skip building events.
+func (t *Tap) Active() bool {
+	if t == nil {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.subscribers) > 0
+}
+
+// Subscribe returns a channel of events and a func that unsubscribes. The channel is closed on
+// unsubscribe or when the tap is closed.
+func (t *Tap) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	t.nextID++
+	id := t.nextID
+	t.subscribers[id] = ch
+
+	return ch, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.subscribers[id]; ok {
+			delete(t.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// Close ends every subscription so open streams finish during shutdown.
+func (t *Tap) Close() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for id, ch := range t.subscribers {
+		delete(t.subscribers, id)
+		close(ch)
+	}
+}
+
+func (t *Tap) Publish(event Event) {
+	if t == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, ch := range t.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// sensitiveKeys are payload fields that can hold buyer details or credentials. Matching is on
+// the lowercased key, and any key containing "secret", "token" or "email" is also redacted.
+var sensitiveKeys = map[string]struct{}{
+	"address":          {},
+	"billing_details":  {},
+	"customer_details": {},
+	"shipping":         {},
+	"shipping_details": {},
+	"phone":            {},
+	"dob":              {},
+	"verified_outputs": {},
+	"ip_address":       {},
+	"key":              {},
+}
+
+// Sanitize redacts buyer details and credentials from a JSON payload. Payloads that are not
+// JSON are dropped entirely.
+func Sanitize(payload []byte) json.RawMessage {
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return json.RawMessage(`"` + redacted + `"`)
+	}
+	sanitized, err := json.Marshal(sanitizeValue(value))
+	if err != nil {
+		return json.RawMessage(`"` + redacted + `"`)
+	}
+	return sanitized
+}
+
+func sanitizeValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isSensitiveKey(key) && field != nil {
+				v[key] = redacted
+				continue
+			}
+			v[key] = sanitizeValue(field)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = sanitizeValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := sensitiveKeys[key]; ok {
+		return true
+	}
+	return strings.Contains(key, "secret") || strings.Contains(key, "token") || strings.Contains(key, "email")
+}
@@ -0,0 +1,103 @@
+package webhooktap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTap_PublishReachesSubscribers(t *testing.T) {
+	t.Parallel()
+
+	tap := New()
+	if tap.Active() {
+		t.Fatal("expected new tap to be inactive")
+	}
+
+	events, unsubscribe := tap.Subscribe(1)
+	if !tap.Active() {
+		t.Fatal("expected tap with a subscriber to be active")
+	}
+	tap.Publish(Event{Kind: KindReceived, DeliveryID: "d1"})
+	tap.Publish(Event{Kind: KindReceived, DeliveryID: "dropped"})
+
+	event := <-events
+	if event.DeliveryID != "d1" || event.Time.IsZero() {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-events; ok {
+		t.Fatal("expected channel to be closed after unsubscribe")
+	}
+	if tap.Active() {
+		t.Fatal("expected tap to be inactive after unsubscribe")
+	}
+}
+
+func TestTap_CloseEndsSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	tap := New()
+	events, unsubscribe := tap.Subscribe(1)
+	tap.Close()
+	if _, ok := <-events; ok {
+		t.Fatal("expected channel to be closed by Close")
+	}
+	unsubscribe()
+
+	late, _ := tap.Subscribe(1)
+	if _, ok := <-late; ok {
+		t.Fatal("expected subscriptions after Close to be closed")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{
+			name:    "github issue keeps public fields",
+			payload: `{"action":"opened","issue":{"number":7,"user":{"login":"octocat"}}}`,
+			want:    `{"action":"opened","issue":{"number":7,"user":{"login":"octocat"}}}`,
+		},
+		{
+			name:    "stripe session redacts buyer details",
+			payload: `{"data":{"object":{"id":"cs_1","customer_details":{"email":"a@b.c"},"shipping_details":{"address":{"line1":"1 Main"}},"customer_email":"a@b.c","client_secret":"cs_secret"}}}`,
+			want:    `{"data":{"object":{"client_secret":"[redacted]","customer_details":"[redacted]","customer_email":"[redacted]","id":"cs_1","shipping_details":"[redacted]"}}}`,
+		},
+		{
+			name:    "nested arrays",
+			payload: `{"items":[{"phone":"555","sku":"A"}]}`,
+			want:    `{"items":[{"phone":"[redacted]","sku":"A"}]}`,
+		},
+		{
+			name:    "null sensitive field stays null",
+			payload: `{"shipping":null}`,
+			want:    `{"shipping":null}`,
+		},
+		{
+			name:    "not json",
+			payload: `installation_token=abc`,
+			want:    `"[redacted]"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Sanitize([]byte(tt.payload))
+			if !json.Valid(got) {
+				t.Fatalf("expected valid JSON, got %s", got)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
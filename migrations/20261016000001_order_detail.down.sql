@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS order_emails;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS refunded_at;
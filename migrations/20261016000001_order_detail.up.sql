@@ -0,0 +1,11 @@
+ALTER TABLE orders ADD COLUMN refunded_at TIMESTAMPTZ;
+
+CREATE TABLE order_emails (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_emails_order_id ON order_emails(order_id);
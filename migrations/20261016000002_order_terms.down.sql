@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS terms_accepted_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS terms_version;
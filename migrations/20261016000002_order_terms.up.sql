@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN terms_version TEXT;
+ALTER TABLE orders ADD COLUMN terms_accepted_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS identity_verification_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS verification_status;
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN verification_status TEXT;
+ALTER TABLE orders ADD COLUMN identity_verification_id TEXT;
@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS stripe_invoice_id;
@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN stripe_invoice_id TEXT;
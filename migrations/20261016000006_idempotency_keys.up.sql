@@ -0,0 +1,12 @@
+CREATE TABLE idempotency_keys (
+    key TEXT NOT NULL,
+    path TEXT NOT NULL,
+    status_code INTEGER,
+    headers JSONB,
+    body TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key, path)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_inbound_webhook_queue_region_due;
+ALTER TABLE inbound_webhook_queue DROP COLUMN IF EXISTS region;
+ALTER TABLE shops DROP COLUMN IF EXISTS region;
@@ -0,0 +1,4 @@
+ALTER TABLE shops ADD COLUMN region TEXT NOT NULL DEFAULT '';
+ALTER TABLE inbound_webhook_queue ADD COLUMN region TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_inbound_webhook_queue_region_due ON inbound_webhook_queue(region, next_attempt_at) WHERE status = 'pending';
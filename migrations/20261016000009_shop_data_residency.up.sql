@@ -0,0 +1,4 @@
+ALTER TABLE shops ADD COLUMN retain_email_log BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN shops.region IS 'Home region that processes this shop''s order data; empty means any region';
+COMMENT ON COLUMN shops.retain_email_log IS 'Whether customer addresses are kept in order_emails';
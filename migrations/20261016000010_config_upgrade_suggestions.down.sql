@@ -0,0 +1 @@
+ALTER TABLE shops DROP COLUMN IF EXISTS config_upgrade_suggested_version;
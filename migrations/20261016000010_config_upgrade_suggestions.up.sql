@@ -0,0 +1,3 @@
+ALTER TABLE shops ADD COLUMN config_upgrade_suggested_version INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN shops.config_upgrade_suggested_version IS 'Latest gitshop.yaml schema version GitShop has opened an upgrade PR for';
@@ -0,0 +1,16 @@
+CREATE TABLE webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider TEXT NOT NULL,
+    delivery_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    status TEXT NOT NULL DEFAULT 'received',
+    last_error TEXT,
+    replay_count INTEGER NOT NULL DEFAULT 0,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ,
+    last_replayed_at TIMESTAMPTZ,
+    UNIQUE (provider, delivery_id)
+);
+
+CREATE INDEX idx_webhook_events_received_at ON webhook_events(received_at DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE shops DROP COLUMN IF EXISTS data_deleted_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS anonymized_at;
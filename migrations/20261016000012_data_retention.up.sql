@@ -0,0 +1,5 @@
+ALTER TABLE orders ADD COLUMN anonymized_at TIMESTAMPTZ;
+ALTER TABLE shops ADD COLUMN data_deleted_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN orders.anonymized_at IS 'When customer details were removed under the data retention policy';
+COMMENT ON COLUMN shops.data_deleted_at IS 'When the seller deleted the shop''s order data';
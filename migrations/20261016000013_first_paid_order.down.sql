@@ -0,0 +1 @@
+ALTER TABLE shops DROP COLUMN IF EXISTS first_paid_order_id;
@@ -0,0 +1,11 @@
+ALTER TABLE shops ADD COLUMN first_paid_order_id UUID REFERENCES orders(id) ON DELETE SET NULL;
+
+UPDATE shops
+SET first_paid_order_id = (
+    SELECT o.id FROM orders o
+    WHERE o.shop_id = shops.id AND o.paid_at IS NOT NULL
+    ORDER BY o.paid_at
+    LIMIT 1
+);
+
+COMMENT ON COLUMN shops.first_paid_order_id IS 'The shop''s first paid order, which started the first-order welcome flow';
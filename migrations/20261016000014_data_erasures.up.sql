@@ -0,0 +1,14 @@
+CREATE TABLE data_erasures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    subject_kind TEXT NOT NULL,
+    subject_hash TEXT NOT NULL,
+    order_count INTEGER NOT NULL,
+    erased_by TEXT NOT NULL,
+    erased_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_data_erasures_shop_erased ON data_erasures(shop_id, erased_at DESC);
+
+COMMENT ON TABLE data_erasures IS 'Audit log of customer data erasure requests handled by sellers';
+COMMENT ON COLUMN data_erasures.subject_hash IS 'SHA-256 of the normalized email or GitHub username, so the log does not keep the erased identifier';
@@ -0,0 +1,11 @@
+CREATE TABLE webhook_checkpoints (
+    provider TEXT NOT NULL,
+    endpoint TEXT NOT NULL,
+    delivery_id TEXT NOT NULL,
+    event_type TEXT NOT NULL DEFAULT '',
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, endpoint)
+);
+
+COMMENT ON TABLE webhook_checkpoints IS 'Last successfully processed GitHub delivery or Stripe event per receiving endpoint';
+COMMENT ON COLUMN webhook_checkpoints.endpoint IS 'Webhook path, prefixed with the region for multi-region deployments';
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ALTER COLUMN subtotal_cents TYPE INTEGER,
+    ALTER COLUMN shipping_cents TYPE INTEGER,
+    ALTER COLUMN tax_cents TYPE INTEGER,
+    ALTER COLUMN total_cents TYPE INTEGER;
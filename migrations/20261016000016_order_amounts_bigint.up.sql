@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ALTER COLUMN subtotal_cents TYPE BIGINT,
+    ALTER COLUMN shipping_cents TYPE BIGINT,
+    ALTER COLUMN tax_cents TYPE BIGINT,
+    ALTER COLUMN total_cents TYPE BIGINT;
@@ -0,0 +1,5 @@
+UPDATE orders SET order_number = github_issue_number WHERE order_number <> github_issue_number;
+
+ALTER TABLE orders ADD CONSTRAINT orders_order_number_matches_issue CHECK (order_number = github_issue_number);
+
+DROP TABLE IF EXISTS order_number_sequences;
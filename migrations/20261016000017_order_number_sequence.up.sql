@@ -0,0 +1,12 @@
+CREATE TABLE order_number_sequences (
+    shop_id UUID PRIMARY KEY REFERENCES shops(id) ON DELETE CASCADE,
+    last_order_number INTEGER NOT NULL
+);
+
+INSERT INTO order_number_sequences (shop_id, last_order_number)
+SELECT shop_id, MAX(order_number) FROM orders GROUP BY shop_id;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_order_number_matches_issue;
+
+COMMENT ON TABLE order_number_sequences IS 'Last order number handed out per shop; orders take the next one when they are created';
+COMMENT ON COLUMN orders.order_number IS 'Per-shop order number shown to buyers and sellers, independent of the GitHub issue number';
@@ -0,0 +1,3 @@
+UPDATE orders SET status = 'delivered' WHERE status = 'returned';
+
+ALTER TABLE orders DROP COLUMN IF EXISTS returned_at;
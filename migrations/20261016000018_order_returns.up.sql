@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN returned_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN orders.returned_at IS 'When the seller recorded that a shipped or delivered order was returned';
@@ -0,0 +1,5 @@
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS stripe_capabilities_checked_at,
+    DROP COLUMN IF EXISTS stripe_payouts_enabled,
+    DROP COLUMN IF EXISTS stripe_charges_enabled,
+    DROP COLUMN IF EXISTS stripe_details_submitted;
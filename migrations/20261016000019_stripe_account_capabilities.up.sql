@@ -0,0 +1,15 @@
+ALTER TABLE shops
+    ADD COLUMN stripe_details_submitted BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN stripe_charges_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN stripe_payouts_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN stripe_capabilities_checked_at TIMESTAMPTZ;
+
+-- Onboarding requires charges and payouts, so onboarded shops had both when they finished it.
+UPDATE shops
+SET stripe_details_submitted = TRUE,
+    stripe_charges_enabled = TRUE,
+    stripe_payouts_enabled = TRUE,
+    stripe_capabilities_checked_at = onboarded_at
+WHERE onboarded_at IS NOT NULL AND stripe_connect_account_id IS NOT NULL;
+
+COMMENT ON COLUMN shops.stripe_capabilities_checked_at IS 'When Stripe last reported the connected account capabilities; NULL means unknown';
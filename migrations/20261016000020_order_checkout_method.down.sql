@@ -0,0 +1,5 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_checkout_method_check;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS stripe_payment_link_id,
+    DROP COLUMN IF EXISTS checkout_method;
@@ -0,0 +1,9 @@
+ALTER TABLE orders
+    ADD COLUMN checkout_method TEXT NOT NULL DEFAULT 'checkout_session',
+    ADD COLUMN stripe_payment_link_id TEXT;
+
+ALTER TABLE orders
+    ADD CONSTRAINT orders_checkout_method_check CHECK (checkout_method IN ('checkout_session', 'payment_link'));
+
+COMMENT ON COLUMN orders.checkout_method IS 'How the buyer was asked to pay; payment_link means Checkout Session creation failed and a Payment Link was sent instead';
+COMMENT ON COLUMN orders.stripe_payment_link_id IS 'Stripe Payment Link sent when checkout_method is payment_link';
@@ -0,0 +1,11 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_checkout_method_check;
+UPDATE orders SET checkout_method = 'checkout_session' WHERE checkout_method = 'lemonsqueezy_checkout';
+ALTER TABLE orders
+    ADD CONSTRAINT orders_checkout_method_check CHECK (checkout_method IN ('checkout_session', 'payment_link'));
+
+ALTER TABLE orders DROP COLUMN IF EXISTS provider_payment_id;
+
+ALTER TABLE shops DROP CONSTRAINT IF EXISTS shops_payment_processor_check;
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS payment_config,
+    DROP COLUMN IF EXISTS payment_processor;
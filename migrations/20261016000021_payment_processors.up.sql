@@ -0,0 +1,18 @@
+ALTER TABLE shops
+    ADD COLUMN payment_processor TEXT NOT NULL DEFAULT 'stripe',
+    ADD COLUMN payment_config JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE shops
+    ADD CONSTRAINT shops_payment_processor_check CHECK (payment_processor IN ('stripe', 'lemonsqueezy'));
+
+COMMENT ON COLUMN shops.payment_processor IS 'Payment processor new orders are checked out through';
+COMMENT ON COLUMN shops.payment_config IS 'Credentials for non-Stripe processors; secrets are encrypted';
+
+ALTER TABLE orders
+    ADD COLUMN provider_payment_id TEXT;
+
+ALTER TABLE orders DROP CONSTRAINT orders_checkout_method_check;
+ALTER TABLE orders
+    ADD CONSTRAINT orders_checkout_method_check CHECK (checkout_method IN ('checkout_session', 'payment_link', 'lemonsqueezy_checkout'));
+
+COMMENT ON COLUMN orders.provider_payment_id IS 'Payment or order id at a non-Stripe processor, used for refunds';
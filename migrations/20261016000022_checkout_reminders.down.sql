@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_orders_checkout_reminder_due;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS checkout_reminder_sent_at,
+    DROP COLUMN IF EXISTS checkout_expires_at;
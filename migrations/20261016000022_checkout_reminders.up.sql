@@ -0,0 +1,9 @@
+ALTER TABLE orders
+    ADD COLUMN checkout_expires_at TIMESTAMPTZ,
+    ADD COLUMN checkout_reminder_sent_at TIMESTAMPTZ;
+
+CREATE INDEX idx_orders_checkout_reminder_due ON orders (checkout_expires_at)
+    WHERE status = 'pending_payment' AND checkout_reminder_sent_at IS NULL;
+
+COMMENT ON COLUMN orders.checkout_expires_at IS 'When the checkout link posted on the order issue expires; NULL for links that stay open';
+COMMENT ON COLUMN orders.checkout_reminder_sent_at IS 'When the buyer was reminded that the checkout link is about to expire';
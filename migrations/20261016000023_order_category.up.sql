@@ -0,0 +1,5 @@
+ALTER TABLE orders ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_orders_shop_category ON orders (shop_id, category);
+
+COMMENT ON COLUMN orders.category IS 'The product category from gitshop.yaml when the order was placed';
@@ -0,0 +1,16 @@
+CREATE TABLE order_edits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    previous_sku TEXT NOT NULL,
+    sku TEXT NOT NULL,
+    previous_options JSONB NOT NULL DEFAULT '{}',
+    options JSONB NOT NULL DEFAULT '{}',
+    previous_total_cents BIGINT NOT NULL,
+    total_cents BIGINT NOT NULL,
+    edited_by TEXT NOT NULL,
+    edited_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_edits_order_edited ON order_edits(order_id, edited_at);
+
+COMMENT ON TABLE order_edits IS 'Audit log of unpaid orders re-priced after the buyer edited the order issue';
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD COLUMN cancelled_at TIMESTAMPTZ,
+    ADD COLUMN cancellation_reason TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN orders.cancellation_reason IS 'Why an unpaid order was cancelled, such as issue_closed or issue_deleted';
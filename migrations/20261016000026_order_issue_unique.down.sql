@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_shop_issue_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_shop_issue ON orders(shop_id, github_issue_number);
@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD CONSTRAINT orders_shop_issue_key UNIQUE USING INDEX idx_orders_shop_issue;
+
+COMMENT ON CONSTRAINT orders_shop_issue_key ON orders IS 'One order per issue, so a redelivered issues.opened webhook reuses the first order';
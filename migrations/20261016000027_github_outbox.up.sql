@@ -0,0 +1,24 @@
+CREATE TABLE github_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    idempotency_key TEXT NOT NULL UNIQUE,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    order_id UUID REFERENCES orders(id) ON DELETE CASCADE,
+    installation_id BIGINT NOT NULL,
+    repo_full_name TEXT NOT NULL,
+    issue_number INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    value TEXT NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_github_outbox_pending ON github_outbox(next_attempt_at) WHERE status = 'pending';
+
+COMMENT ON TABLE github_outbox IS 'GitHub issue comments and label changes waiting to be applied by the outbox worker';
+COMMENT ON COLUMN github_outbox.idempotency_key IS 'Names the effect, such as order:<id>:paid:comment, so a repeated payment webhook queues it once';
+COMMENT ON COLUMN github_outbox.value IS 'Comment body or label name, depending on kind';
@@ -0,0 +1,12 @@
+CREATE TABLE order_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_events_order_created ON order_events (order_id, created_at);
+
+COMMENT ON TABLE order_events IS 'Transitions and side effects of each order, shown on the order page for support';
+COMMENT ON COLUMN order_events.detail IS 'Kind-specific context such as a failure reason or email kind; never customer details';
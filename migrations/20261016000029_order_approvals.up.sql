@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD COLUMN approved_at TIMESTAMPTZ,
+    ADD COLUMN approved_by TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN orders.approved_by IS 'GitHub login of the seller who released an order held for approval';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_orders_active_subscriptions;
+DROP INDEX IF EXISTS idx_orders_stripe_subscription_id;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS subscription_cancelled_at,
+    DROP COLUMN IF EXISTS subscription_status,
+    DROP COLUMN IF EXISTS stripe_subscription_id;
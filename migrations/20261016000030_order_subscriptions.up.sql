@@ -0,0 +1,9 @@
+ALTER TABLE orders
+    ADD COLUMN stripe_subscription_id TEXT,
+    ADD COLUMN subscription_status TEXT NOT NULL DEFAULT '',
+    ADD COLUMN subscription_cancelled_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_orders_stripe_subscription_id ON orders (stripe_subscription_id) WHERE stripe_subscription_id IS NOT NULL;
+CREATE INDEX idx_orders_active_subscriptions ON orders (shop_id, paid_at DESC) WHERE subscription_status = 'active';
+
+COMMENT ON COLUMN orders.subscription_status IS 'active or cancelled for orders paid by a Stripe subscription; empty for one-time orders';
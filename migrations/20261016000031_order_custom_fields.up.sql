@@ -0,0 +1,4 @@
+ALTER TABLE orders
+    ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN orders.custom_fields IS 'Buyer answers to checkout custom fields from gitshop.yaml, as [{key, label, value}]';
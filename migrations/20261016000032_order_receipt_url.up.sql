@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN receipt_url TEXT;
+
+COMMENT ON COLUMN orders.receipt_url IS 'Stripe hosted invoice page for the paid order, where the buyer downloads the invoice and receipt PDFs';
@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS tax_id,
+    DROP COLUMN IF EXISTS tax_id_type;
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD COLUMN tax_id_type TEXT,
+    ADD COLUMN tax_id TEXT;
+
+COMMENT ON COLUMN orders.tax_id_type IS 'Stripe tax ID type the buyer entered at checkout, such as eu_vat';
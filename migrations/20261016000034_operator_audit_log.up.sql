@@ -0,0 +1,14 @@
+CREATE TABLE operator_audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    operator TEXT NOT NULL,
+    action TEXT NOT NULL,
+    shop_id UUID REFERENCES shops(id) ON DELETE SET NULL,
+    repo_full_name TEXT NOT NULL DEFAULT '',
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_operator_audit_log_created ON operator_audit_log(created_at DESC);
+
+COMMENT ON TABLE operator_audit_log IS 'Every action a platform operator took in the internal admin area, including support impersonation';
+COMMENT ON COLUMN operator_audit_log.repo_full_name IS 'Repository of the shop at the time, kept after the shop is deleted';
@@ -0,0 +1,7 @@
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS suspension_appealed_at,
+    DROP COLUMN IF EXISTS suspension_appeal,
+    DROP COLUMN IF EXISTS suspension_notes,
+    DROP COLUMN IF EXISTS suspension_reason,
+    DROP COLUMN IF EXISTS suspended_by,
+    DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,12 @@
+ALTER TABLE shops
+    ADD COLUMN suspended_at TIMESTAMPTZ,
+    ADD COLUMN suspended_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN suspension_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN suspension_notes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN suspension_appeal TEXT NOT NULL DEFAULT '',
+    ADD COLUMN suspension_appealed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN shops.suspended_at IS 'Set while the platform has suspended the shop for a terms violation; unlike disconnected_at, the GitHub App stays installed';
+COMMENT ON COLUMN shops.suspension_reason IS 'Why the shop was suspended, shown to the shop owner';
+COMMENT ON COLUMN shops.suspension_notes IS 'Internal operator notes about the suspension and its appeal';
+COMMENT ON COLUMN shops.suspension_appeal IS 'The shop owner''s latest appeal of the suspension';
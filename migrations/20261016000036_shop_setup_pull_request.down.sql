@@ -0,0 +1,3 @@
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS setup_pr_url,
+    DROP COLUMN IF EXISTS setup_pr_number;
@@ -0,0 +1,5 @@
+ALTER TABLE shops
+    ADD COLUMN setup_pr_number INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN setup_pr_url TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN shops.setup_pr_number IS 'The pull request GitShop opened to bootstrap gitshop.yaml, the order template, and the README; 0 when none was opened';
@@ -0,0 +1,2 @@
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS config_issue_number;
@@ -0,0 +1,4 @@
+ALTER TABLE shops
+    ADD COLUMN config_issue_number INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN shops.config_issue_number IS 'Open issue GitShop keeps in the shop repository while a push has left gitshop.yaml or the order templates broken; 0 when there is none';
@@ -0,0 +1,3 @@
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS repo_orphan_reason,
+    DROP COLUMN IF EXISTS repo_orphaned_at;
@@ -0,0 +1,5 @@
+ALTER TABLE shops
+    ADD COLUMN repo_orphaned_at TIMESTAMPTZ,
+    ADD COLUMN repo_orphan_reason TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN shops.repo_orphaned_at IS 'When GitShop last failed to resolve the shop repository or its installation through the GitHub API; NULL while it resolves';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_orders_paid_unshipped;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS overdue_issue_number,
+    DROP COLUMN IF EXISTS overdue_reminded_at,
+    DROP COLUMN IF EXISTS ship_within_days;
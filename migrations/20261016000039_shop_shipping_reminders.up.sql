@@ -0,0 +1,9 @@
+ALTER TABLE shops
+    ADD COLUMN ship_within_days INTEGER NOT NULL DEFAULT 5,
+    ADD COLUMN overdue_reminded_at TIMESTAMPTZ,
+    ADD COLUMN overdue_issue_number INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN shops.ship_within_days IS 'Days a paid order may wait to ship, copied from gitshop.yaml by the shipping reminder job; 0 turns reminders off';
+COMMENT ON COLUMN shops.overdue_issue_number IS 'Internal issue listing overdue orders, or 0 when none is open';
+
+CREATE INDEX idx_orders_paid_unshipped ON orders (shop_id, paid_at) WHERE status = 'paid';
@@ -0,0 +1,20 @@
+CREATE TABLE order_reviews (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    sku TEXT NOT NULL,
+    comment_id BIGINT NOT NULL DEFAULT 0,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    checked_at TIMESTAMPTZ,
+    close_at TIMESTAMPTZ,
+    rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT '',
+    rated_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_order_reviews_shop_sku ON order_reviews(shop_id, sku) WHERE rating IS NOT NULL;
+CREATE INDEX idx_order_reviews_close_at ON order_reviews(close_at) WHERE close_at IS NOT NULL;
+
+COMMENT ON TABLE order_reviews IS 'Review invitations posted on delivered order issues and the ratings buyers left by reaction or comment';
+COMMENT ON COLUMN order_reviews.comment_id IS 'Invitation comment whose reactions are read as a rating, or 0 when the buyer rated without an invitation';
+COMMENT ON COLUMN order_reviews.close_at IS 'When the order issue is due to be closed; NULL once closed or when the shop leaves issues open';
@@ -0,0 +1,8 @@
+-- Encrypted customer details stay encrypted; this only removes the email lookup index.
+COMMENT ON COLUMN orders.customer_email IS NULL;
+COMMENT ON COLUMN orders.customer_name IS NULL;
+COMMENT ON COLUMN orders.shipping_address IS NULL;
+
+DROP INDEX IF EXISTS idx_orders_shop_customer_email_hash;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_email_hash;
@@ -0,0 +1,8 @@
+ALTER TABLE orders ADD COLUMN customer_email_hash TEXT;
+
+CREATE INDEX idx_orders_shop_customer_email_hash ON orders (shop_id, customer_email_hash) WHERE customer_email_hash IS NOT NULL;
+
+COMMENT ON COLUMN orders.customer_email IS 'Encrypted with the app encryption key; rows written before encryption hold plaintext until cmd/encrypt-customer-data runs';
+COMMENT ON COLUMN orders.customer_name IS 'Encrypted with the app encryption key; rows written before encryption hold plaintext until cmd/encrypt-customer-data runs';
+COMMENT ON COLUMN orders.shipping_address IS 'Encrypted JSON object stored as a JSON string; a JSON object is a plaintext address not yet backfilled';
+COMMENT ON COLUMN orders.customer_email_hash IS 'Keyed HMAC of the lowercased customer email, so orders can be found by email without decrypting';
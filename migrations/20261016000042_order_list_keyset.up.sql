@@ -0,0 +1,3 @@
+-- Serves the shop order list newest first and its keyset pages, which seek to a
+-- (created_at, id) cursor instead of skipping rows.
+CREATE INDEX idx_orders_shop_created ON orders (shop_id, created_at DESC, id DESC);
@@ -0,0 +1,10 @@
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS email_from_bounce_detail,
+    DROP COLUMN IF EXISTS email_from_bounced_at;
+
+DROP TABLE IF EXISTS email_suppressions;
+
+ALTER TABLE order_emails
+    DROP COLUMN IF EXISTS delivery_updated_at,
+    DROP COLUMN IF EXISTS delivery_detail,
+    DROP COLUMN IF EXISTS delivery_status;
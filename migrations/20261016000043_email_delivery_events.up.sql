@@ -0,0 +1,22 @@
+ALTER TABLE order_emails
+    ADD COLUMN delivery_status TEXT NOT NULL DEFAULT 'sent',
+    ADD COLUMN delivery_detail TEXT NOT NULL DEFAULT '',
+    ADD COLUMN delivery_updated_at TIMESTAMPTZ;
+
+CREATE TABLE email_suppressions (
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    recipient_hash TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (shop_id, recipient_hash)
+);
+
+COMMENT ON TABLE email_suppressions IS 'Recipients that hard-bounced or complained, which the shop no longer emails';
+COMMENT ON COLUMN email_suppressions.recipient_hash IS 'Keyed HMAC of the lowercased address, the same index as orders.customer_email_hash';
+
+ALTER TABLE shops
+    ADD COLUMN email_from_bounced_at TIMESTAMPTZ,
+    ADD COLUMN email_from_bounce_detail TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN shops.email_from_bounced_at IS 'When mail to the shop''s own from address last hard-bounced; cleared when email settings are saved';
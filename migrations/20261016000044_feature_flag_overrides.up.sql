@@ -0,0 +1,16 @@
+CREATE TABLE feature_flag_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    flag TEXT NOT NULL,
+    shop_id UUID REFERENCES shops(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percent SMALLINT NOT NULL DEFAULT 0 CHECK (rollout_percent BETWEEN 0 AND 100),
+    note TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_feature_flag_overrides_global ON feature_flag_overrides (flag) WHERE shop_id IS NULL;
+CREATE UNIQUE INDEX idx_feature_flag_overrides_shop ON feature_flag_overrides (flag, shop_id) WHERE shop_id IS NOT NULL;
+
+COMMENT ON TABLE feature_flag_overrides IS 'Operator overrides of the FEATURE_FLAGS defaults';
+COMMENT ON COLUMN feature_flag_overrides.shop_id IS 'NULL sets the rule for every shop; otherwise enabled turns the flag on or off for this shop only';
+COMMENT ON COLUMN feature_flag_overrides.rollout_percent IS 'Share of shops that get the flag when enabled is false on a global row';
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS test_mode;
+ALTER TABLE shops DROP COLUMN IF EXISTS stripe_test_mode;
@@ -0,0 +1,5 @@
+ALTER TABLE shops ADD COLUMN stripe_test_mode BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE orders ADD COLUMN test_mode BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN shops.stripe_test_mode IS 'New orders check out with the platform''s Stripe test keys, so no real payment is taken';
+COMMENT ON COLUMN orders.test_mode IS 'Order was placed while the shop was in test mode; left out of analytics';
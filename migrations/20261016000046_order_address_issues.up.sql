@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN address_issue TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN orders.address_issue IS 'Why address validation found the shipping address undeliverable; empty once it is confirmed';
@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS checkout_reminder_opt_out;
@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN checkout_reminder_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN orders.checkout_reminder_opt_out IS 'Whether the buyer asked on the order form not to get a checkout reminder email';
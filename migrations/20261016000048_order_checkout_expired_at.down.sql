@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_shop_checkout_expired;
+ALTER TABLE orders DROP COLUMN IF EXISTS checkout_expired_at;
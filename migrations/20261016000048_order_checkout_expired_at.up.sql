@@ -0,0 +1,6 @@
+ALTER TABLE orders ADD COLUMN checkout_expired_at TIMESTAMPTZ;
+
+-- Serves the abandoned checkout panel, which reads a shop's recent expiries.
+CREATE INDEX idx_orders_shop_checkout_expired ON orders (shop_id, checkout_expired_at DESC) WHERE checkout_expired_at IS NOT NULL;
+
+COMMENT ON COLUMN orders.checkout_expired_at IS 'When the order''s first checkout expired unpaid; kept after the checkout is re-sent so recoveries can be counted';
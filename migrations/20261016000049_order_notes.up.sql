@@ -0,0 +1,11 @@
+CREATE TABLE order_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    author TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_notes_order_created ON order_notes(order_id, created_at);
+
+COMMENT ON TABLE order_notes IS 'Private seller notes on orders, shown only in the dashboard';
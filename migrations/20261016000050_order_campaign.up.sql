@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN campaign TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN orders.campaign IS 'Name of the gitshop.yaml campaign whose sale price the order was charged, empty at list price';
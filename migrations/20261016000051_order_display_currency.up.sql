@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN display_currency TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN orders.display_currency IS 'Lowercase currency the buyer picked for price estimates; the order is charged in the shop currency';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_orders_shop_buyer;
@@ -0,0 +1,2 @@
+-- Serves returning customer lookups and the top customers panel, which match buyers by username.
+CREATE INDEX idx_orders_shop_buyer ON orders (shop_id, LOWER(github_username)) WHERE paid_at IS NOT NULL;
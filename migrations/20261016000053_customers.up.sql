@@ -0,0 +1,32 @@
+CREATE TABLE customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    github_username TEXT NOT NULL,
+    email_hash TEXT,
+    orders_count INTEGER NOT NULL DEFAULT 0,
+    lifetime_value_cents BIGINT NOT NULL DEFAULT 0,
+    first_order_at TIMESTAMPTZ NOT NULL,
+    last_order_at TIMESTAMPTZ NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    notes_updated_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (shop_id, github_username)
+);
+
+CREATE INDEX idx_customers_shop_value ON customers (shop_id, lifetime_value_cents DESC);
+CREATE INDEX idx_customers_shop_email_hash ON customers (shop_id, email_hash) WHERE email_hash IS NOT NULL;
+
+INSERT INTO customers (shop_id, github_username, email_hash, orders_count, lifetime_value_cents, first_order_at, last_order_at)
+SELECT shop_id, LOWER(github_username),
+       (ARRAY_AGG(customer_email_hash ORDER BY created_at DESC) FILTER (WHERE customer_email_hash IS NOT NULL))[1],
+       COUNT(*),
+       COALESCE(SUM(total_cents) FILTER (WHERE status IN ('paid', 'address_issue', 'shipped', 'delivered')), 0),
+       MIN(created_at), MAX(created_at)
+FROM orders
+WHERE github_username <> '' AND paid_at IS NOT NULL AND NOT test_mode
+GROUP BY shop_id, LOWER(github_username);
+
+COMMENT ON TABLE customers IS 'Buyers of each shop with stats recomputed from their paid orders, and private seller notes';
+COMMENT ON COLUMN customers.github_username IS 'Lowercased GitHub username; GitHub treats usernames case-insensitively';
+COMMENT ON COLUMN customers.email_hash IS 'Blind index of the email on the buyer''s latest paid order, matching orders.customer_email_hash';
@@ -0,0 +1,17 @@
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    token_prefix TEXT NOT NULL,
+    customer_data BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_keys_shop_created ON api_keys (shop_id, created_at DESC);
+
+COMMENT ON TABLE api_keys IS 'Shop-scoped keys for the GraphQL API; only a SHA-256 hash of each token is stored';
+COMMENT ON COLUMN api_keys.customer_data IS 'Whether the key may read buyers'' usernames, names, emails, and addresses';
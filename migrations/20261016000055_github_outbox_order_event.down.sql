@@ -0,0 +1,2 @@
+ALTER TABLE github_outbox DROP COLUMN IF EXISTS event_detail;
+ALTER TABLE github_outbox DROP COLUMN IF EXISTS event_kind;
@@ -0,0 +1,10 @@
+ALTER TABLE github_outbox ADD COLUMN event_kind TEXT NOT NULL DEFAULT '';
+ALTER TABLE github_outbox ADD COLUMN event_detail TEXT NOT NULL DEFAULT '';
+
+-- Comments queued before these columns existed carry their step in the idempotency key.
+UPDATE github_outbox
+SET event_kind = 'comment_posted', event_detail = split_part(idempotency_key, ':', 3)
+WHERE kind = 'comment' AND status = 'pending' AND order_id IS NOT NULL;
+
+COMMENT ON COLUMN github_outbox.event_kind IS 'Order event logged when the effect is applied, or empty for none';
+COMMENT ON COLUMN github_outbox.event_detail IS 'Detail of the logged order event, such as the step that queued the comment';
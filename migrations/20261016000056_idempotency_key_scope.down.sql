@@ -0,0 +1,6 @@
+-- Scoped keys may repeat across sessions, so drop the short-lived rows before narrowing the key.
+DELETE FROM idempotency_keys;
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key, path);
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS user_id;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS installation_id;
@@ -0,0 +1,10 @@
+ALTER TABLE idempotency_keys ADD COLUMN installation_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE idempotency_keys ADD COLUMN user_id BIGINT NOT NULL DEFAULT 0;
+
+-- Keys are only unique per signed-in user and installation, so one user's stored response is
+-- never replayed to another. Unscoped rows left from before expire within minutes.
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (installation_id, user_id, key, path);
+
+COMMENT ON COLUMN idempotency_keys.installation_id IS 'GitHub App installation of the session that claimed the key';
+COMMENT ON COLUMN idempotency_keys.user_id IS 'GitHub user ID of the session that claimed the key';
@@ -0,0 +1,6 @@
+-- Encrypted recipients stay encrypted; this only removes the recipient lookup index.
+COMMENT ON COLUMN order_emails.recipient IS NULL;
+
+DROP INDEX IF EXISTS idx_order_emails_recipient_hash;
+
+ALTER TABLE order_emails DROP COLUMN IF EXISTS recipient_hash;
@@ -0,0 +1,6 @@
+ALTER TABLE order_emails ADD COLUMN recipient_hash TEXT;
+
+CREATE INDEX idx_order_emails_recipient_hash ON order_emails (recipient_hash) WHERE recipient_hash IS NOT NULL;
+
+COMMENT ON COLUMN order_emails.recipient IS 'Encrypted with the app encryption key; rows written before encryption hold plaintext until cmd/encrypt-customer-data runs';
+COMMENT ON COLUMN order_emails.recipient_hash IS 'Keyed HMAC of the lowercased recipient, the same index as orders.customer_email_hash';
@@ -58,6 +58,7 @@ func New(cfg *config.Config, logger *slog.Logger, h *handlers.Handlers) (*Server
 		IdleTimeout:       60 * time.Second,
 		MaxHeaderBytes:    1 << 20,
 	}
+	s.httpServer.RegisterOnShutdown(h.CloseStreams)
 
 	return s, nil
 }
@@ -89,6 +90,7 @@ func (s *Server) buildRouter() *mux.Router {
 	h := s.handlers
 
 	r := mux.NewRouter()
+	r.Use(h.RequestID)
 	r.Use(h.MetricsContext)
 	r.Use(h.RequestLogger)
 	r.Use(h.SecurityHeaders)
@@ -96,8 +98,13 @@ func (s *Server) buildRouter() *mux.Router {
 	r.HandleFunc("/health", h.Health).Methods("GET").Name("health")
 	r.HandleFunc("/terms", h.TermsOfUse).Methods("GET").Name("legal.terms")
 	r.HandleFunc("/privacy", h.PrivacyPolicy).Methods("GET").Name("legal.privacy")
+	r.HandleFunc("/files/{key:.+}", h.SignedFile).Methods("GET").Name("files.signed")
+	r.HandleFunc("/badge/{owner}/{repo}.svg", h.ShopBadgeSVG).Methods("GET").Name("badge.svg")
+	r.HandleFunc("/badge/{owner}/{repo}.json", h.ShopBadgeJSON).Methods("GET").Name("badge.json")
 	r.HandleFunc("/webhooks/github", h.GitHubWebhook).Methods("POST").Name("webhooks.github")
 	r.HandleFunc("/webhooks/stripe", h.StripeWebhook).Methods("POST").Name("webhooks.stripe")
+	r.HandleFunc("/webhooks/payments/{shop_id}", h.PaymentWebhook).Methods("POST").Name("webhooks.payments")
+	r.HandleFunc("/webhooks/email/{shop_id}", h.EmailWebhook).Methods("POST").Name("webhooks.email")
 
 	// 404 handler - must be last
 	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -122,20 +129,66 @@ func (s *Server) buildRouter() *mux.Router {
 	adminRouter.Use(h.SessionMiddleware)
 	adminRouter.Use(h.RequireAuth)
 	adminRouter.Use(h.RequireSameOrigin)
+	adminRouter.Use(h.Idempotency)
 	adminRouter.HandleFunc("", h.AdminSetup).Methods("GET").Name("admin.root")
 	adminRouter.HandleFunc("/setup", h.AdminSetup).Methods("GET").Name("admin.setup")
 	adminRouter.HandleFunc("/setup/stripe", h.AdminSetupStripe).Methods("POST").Name("admin.setup.stripe")
+	adminRouter.HandleFunc("/setup/bootstrap", h.AdminSetupBootstrap).Methods("POST").Name("admin.setup.bootstrap")
 	adminRouter.HandleFunc("/setup/labels", h.AdminSetupLabels).Methods("POST").Name("admin.setup.labels")
 	adminRouter.HandleFunc("/setup/yaml", h.AdminSetupYAML).Methods("POST").Name("admin.setup.yaml")
+	adminRouter.HandleFunc("/setup/template/convert", h.AdminSetupConvertTemplate).Methods("POST").Name("admin.setup.template.convert")
 	adminRouter.HandleFunc("/setup/template", h.AdminSetupTemplate).Methods("POST").Name("admin.setup.template")
+	adminRouter.HandleFunc("/setup/simulate", h.AdminSetupSimulate).Methods("POST").Name("admin.setup.simulate")
 	adminRouter.HandleFunc("/shops", h.ShopSelection).Methods("GET").Name("admin.shops")
 	adminRouter.HandleFunc("/shops/select", h.SelectShop).Methods("POST").Name("admin.shops.select")
+	adminRouter.HandleFunc("/overview", h.AdminOverview).Methods("GET").Name("admin.overview")
 	adminRouter.HandleFunc("/dashboard", h.AdminDashboard).Methods("GET").Name("admin.dashboard")
 	adminRouter.HandleFunc("/dashboard/storefront", h.AdminDashboardStorefront).Methods("GET").Name("admin.dashboard.storefront")
 	adminRouter.HandleFunc("/dashboard/orders", h.AdminDashboardOrders).Methods("GET").Name("admin.dashboard.orders")
+	adminRouter.HandleFunc("/dashboard/customer-data", h.AdminDashboardCustomerData).Methods("GET").Name("admin.dashboard.customer_data")
+	adminRouter.HandleFunc("/dashboard/abandoned", h.AdminDashboardAbandoned).Methods("GET").Name("admin.dashboard.abandoned")
+	adminRouter.HandleFunc("/dashboard/campaigns", h.AdminDashboardCampaigns).Methods("GET").Name("admin.dashboard.campaigns")
+	adminRouter.HandleFunc("/dashboard/customers", h.AdminDashboardCustomers).Methods("GET").Name("admin.dashboard.customers")
+	adminRouter.HandleFunc("/customers/export", h.AdminCustomerDataExport).Methods("GET").Name("admin.customers.export")
+	adminRouter.HandleFunc("/customers/erase", h.AdminCustomerDataErase).Methods("POST").Name("admin.customers.erase")
+	adminRouter.HandleFunc("/customers", h.AdminCustomers).Methods("GET").Name("admin.customers")
+	adminRouter.HandleFunc("/customers/{id}", h.AdminCustomerDetail).Methods("GET").Name("admin.customers.detail")
+	adminRouter.HandleFunc("/customers/{id}/notes", h.AdminCustomerNotes).Methods("POST").Name("admin.customers.notes")
 	adminRouter.HandleFunc("/settings", h.AdminSettings).Methods("GET").Name("admin.settings")
 	adminRouter.HandleFunc("/settings/email", h.AdminSettingsEmail).Methods("POST").Name("admin.settings.email")
+	adminRouter.HandleFunc("/settings/email/test", h.AdminSettingsEmailTest).Methods("POST").Name("admin.settings.email.test")
+	adminRouter.HandleFunc("/settings/email/domain", h.AdminSettingsEmailDomain).Methods("GET").Name("admin.settings.email.domain")
+	adminRouter.HandleFunc("/settings/email/domain/verify", h.AdminSettingsEmailDomainVerify).Methods("POST").Name("admin.settings.email.domain.verify")
+	adminRouter.HandleFunc("/settings/webhooks", h.AdminSettingsWebhooks).Methods("POST").Name("admin.settings.webhooks")
+	adminRouter.HandleFunc("/settings/api-keys", h.AdminSettingsAPIKeys).Methods("POST").Name("admin.settings.api_keys")
+	adminRouter.HandleFunc("/settings/api-keys/{id}/revoke", h.AdminSettingsAPIKeyRevoke).Methods("POST").Name("admin.settings.api_keys.revoke")
+	adminRouter.HandleFunc("/settings/payments", h.AdminSettingsPayments).Methods("POST").Name("admin.settings.payments")
+	adminRouter.HandleFunc("/settings/payments/test-mode", h.AdminSettingsStripeTestMode).Methods("POST").Name("admin.settings.payments.test_mode")
+	adminRouter.HandleFunc("/settings/processing-region", h.AdminSettingsProcessingRegion).Methods("POST").Name("admin.settings.processing_region")
+	adminRouter.HandleFunc("/settings/clone", h.AdminSettingsClone).Methods("POST").Name("admin.settings.clone")
+	adminRouter.HandleFunc("/settings/delete-data", h.AdminSettingsDeleteData).Methods("POST").Name("admin.settings.delete_data")
+	adminRouter.HandleFunc("/orders/packing-slips", h.AdminPackingSlips).Methods("GET").Name("admin.orders.packing_slips")
+	adminRouter.HandleFunc("/orders/bulk", h.AdminBulkOrderAction).Methods("POST").Name("admin.orders.bulk")
+	adminRouter.HandleFunc("/orders/{id}", h.AdminOrderDetail).Methods("GET").Name("admin.orders.detail")
+	adminRouter.HandleFunc("/orders/{id}/packing-slip", h.AdminPackingSlip).Methods("GET").Name("admin.orders.packing_slip")
 	adminRouter.HandleFunc("/orders/{id}/ship", h.AdminShipOrder).Methods("POST").Name("admin.orders.ship")
+	adminRouter.HandleFunc("/orders/{id}/deliver", h.AdminMarkDelivered).Methods("POST").Name("admin.orders.deliver")
+	adminRouter.HandleFunc("/orders/{id}/approve", h.AdminApproveOrder).Methods("POST").Name("admin.orders.approve")
+	adminRouter.HandleFunc("/orders/{id}/address", h.AdminUpdateOrderAddress).Methods("POST").Name("admin.orders.address")
+	adminRouter.HandleFunc("/orders/{id}/reject", h.AdminRejectOrder).Methods("POST").Name("admin.orders.reject")
+	adminRouter.HandleFunc("/orders/{id}/cancel-subscription", h.AdminCancelSubscription).Methods("POST").Name("admin.orders.cancel_subscription")
+	adminRouter.HandleFunc("/orders/{id}/refund", h.AdminRefundOrder).Methods("POST").Name("admin.orders.refund")
+	adminRouter.HandleFunc("/orders/{id}/return", h.AdminReturnOrder).Methods("POST").Name("admin.orders.return")
+	adminRouter.HandleFunc("/orders/{id}/resend-email", h.AdminResendOrderEmail).Methods("POST").Name("admin.orders.resend_email")
+	adminRouter.HandleFunc("/orders/{id}/convert", h.AdminConvertInquiry).Methods("POST").Name("admin.orders.convert")
+	adminRouter.HandleFunc("/orders/{id}/resend-checkout", h.AdminResendCheckout).Methods("POST").Name("admin.orders.resend_checkout")
+	adminRouter.HandleFunc("/previews/{status}", h.AdminMessagePreviews).Methods("GET").Name("admin.previews")
+	adminRouter.HandleFunc("/previews/{status}/{name}", h.AdminMessagePreviews).Methods("GET").Name("admin.previews.message")
+	adminRouter.HandleFunc("/webhooks", h.AdminWebhooks).Methods("GET").Name("admin.webhooks")
+	adminRouter.HandleFunc("/webhooks/{id}/replay", h.AdminWebhookReplay).Methods("POST").Name("admin.webhooks.replay")
+	adminRouter.HandleFunc("/webhooks/redeliver", h.AdminWebhookRedeliver).Methods("POST").Name("admin.webhooks.redeliver")
+	adminRouter.HandleFunc("/debug/webhook-tap", h.AdminWebhookTap).Methods("GET").Name("admin.debug.webhook_tap")
+	adminRouter.HandleFunc("/suspension/appeal", h.AdminSuspensionAppeal).Methods("POST").Name("admin.suspension.appeal")
 	adminRouter.HandleFunc("/template/sync", h.AdminSyncTemplate).Methods("POST").Name("admin.template.sync")
 	adminRouter.HandleFunc("/no-installations", h.NoInstallation).Methods("GET").Name("admin.no_installations")
 
@@ -145,5 +198,27 @@ func (s *Server) buildRouter() *mux.Router {
 	adminRouter.HandleFunc("/stripe/status", h.StripeConnectionStatus).Methods("GET").Name("admin.stripe.status")
 	adminRouter.HandleFunc("/stripe/disconnect", h.StripeDisconnect).Methods("POST").Name("admin.stripe.disconnect")
 
+	// JSON API for signed-in sellers - handlers answer 401 instead of redirecting to login
+	apiRouter := r.PathPrefix("/api/v1").Subrouter()
+	apiRouter.Use(h.SessionMiddleware)
+	apiRouter.HandleFunc("/shops/{id}/orders", h.APIShopOrders).Methods("GET").Name("api.shops.orders")
+	apiRouter.HandleFunc("/shops/{id}/orders/{order_id}/events", h.APIOrderEvents).Methods("GET").Name("api.shops.orders.events")
+	// GraphQL answers to a dashboard session or a shop API key sent as a bearer token
+	r.Handle("/api/graphql", h.SessionMiddleware(http.HandlerFunc(h.APIGraphQL))).Methods("GET", "POST").Name("api.graphql")
+
+	// Platform operator area - handlers check the operator allowlist
+	internalRouter := r.PathPrefix("/internal/admin").Subrouter()
+	internalRouter.Use(h.SessionMiddleware)
+	internalRouter.Use(h.RequireAuth)
+	internalRouter.Use(h.RequireSameOrigin)
+	internalRouter.Use(h.Idempotency)
+	internalRouter.HandleFunc("", h.OperatorHome).Methods("GET").Name("internal.admin")
+	internalRouter.HandleFunc("/shops/{id}", h.OperatorShop).Methods("GET").Name("internal.admin.shop")
+	internalRouter.HandleFunc("/shops/{id}/impersonate", h.OperatorImpersonate).Methods("POST").Name("internal.admin.shop.impersonate")
+	internalRouter.HandleFunc("/shops/{id}/suspend", h.OperatorSuspendShop).Methods("POST").Name("internal.admin.shop.suspend")
+	internalRouter.HandleFunc("/shops/{id}/unsuspend", h.OperatorUnsuspendShop).Methods("POST").Name("internal.admin.shop.unsuspend")
+	internalRouter.HandleFunc("/shops/{id}/suspension-notes", h.OperatorSuspensionNotes).Methods("POST").Name("internal.admin.shop.suspension_notes")
+	internalRouter.HandleFunc("/impersonation/end", h.OperatorEndImpersonation).Methods("POST").Name("internal.admin.impersonation.end")
+
 	return r
 }
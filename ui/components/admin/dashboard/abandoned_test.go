@@ -0,0 +1,35 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAbandonedCheckoutsSection(t *testing.T) {
+	t.Parallel()
+
+	var empty strings.Builder
+	if err := AbandonedCheckoutsSection(nil).Render(t.Context(), &empty); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if empty.Len() != 0 {
+		t.Fatalf("expected no panel without abandoned checkouts, got %q", empty.String())
+	}
+
+	var b strings.Builder
+	abandoned := &AbandonedCheckouts{
+		Abandoned: 3,
+		Value:     "$75.00",
+		Recovered: 1,
+		Products:  []AbandonedProduct{{SKU: "MUG_V1", Checkouts: 2, Value: "$50.00"}},
+		Orders:    []AbandonedOrder{{ID: "order-1", OrderNumber: 12, SKU: "MUG_V1", Total: "$25.00", DaysAgo: 2}},
+	}
+	if err := AbandonedCheckoutsSection(abandoned).Render(t.Context(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"$75.00", "MUG_V1", "Order #12", `action="/admin/orders/order-1/resend-checkout"`} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("panel missing %q:\n%s", want, b.String())
+		}
+	}
+}
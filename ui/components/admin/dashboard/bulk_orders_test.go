@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBulkOrderResults(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	result := BulkOrderResult{
+		Succeeded: 1,
+		Failed:    1,
+		Items: []BulkOrderResultItem{
+			{OrderID: "order-1", OrderNumber: 12, Detail: "Marked shipped"},
+			{OrderID: "order-2", OrderNumber: 13, Error: "No row for this order in the tracking CSV."},
+		},
+	}
+	if err := BulkOrderResults(result).Render(t.Context(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"1 succeeded", "1 failed", "#12", "Marked shipped", `href="/admin/orders/order-2"`, "No row for this order"} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("results missing %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCampaignRevenueSection(t *testing.T) {
+	t.Parallel()
+
+	var empty strings.Builder
+	if err := CampaignRevenueSection(nil).Render(t.Context(), &empty); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if empty.Len() != 0 {
+		t.Fatalf("expected no panel without campaign sales, got %q", empty.String())
+	}
+
+	var b strings.Builder
+	revenue := &CampaignRevenue{
+		Campaigns: []CampaignSales{{Name: "Black Friday", Orders: 4, Revenue: "$80.00"}},
+		ListPrice: CampaignSales{Orders: 2, Revenue: "$50.00"},
+	}
+	if err := CampaignRevenueSection(revenue).Render(t.Context(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"Black Friday", "4 order(s) · $80.00", "List price", "2 order(s) · $50.00"} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("panel missing %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+)
+
+func TestTopCustomersSection(t *testing.T) {
+	t.Parallel()
+
+	var empty strings.Builder
+	if err := TopCustomersSection(nil).Render(t.Context(), &empty); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if empty.Len() != 0 {
+		t.Fatalf("expected no panel without paid orders, got %q", empty.String())
+	}
+
+	var b strings.Builder
+	customers := []CustomerSummary{{ID: "c1", GitHubUsername: "octocat", Orders: 3, LifetimeValue: "$60.00", LastOrderAt: time.Now().Add(-48 * time.Hour)}}
+	if err := TopCustomersSection(customers).Render(t.Context(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"@octocat", `href="/admin/customers/c1"`, "3 order(s) · $60.00 · last 2 days ago", `href="/admin/customers"`} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("panel missing %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestCustomerDetailSection(t *testing.T) {
+	t.Parallel()
+
+	detail := &CustomerDetail{
+		CustomerSummary: CustomerSummary{ID: "c1", GitHubUsername: "octocat", Orders: 2, LifetimeValue: "$40.00"},
+		Email:           "octocat@example.com",
+		Notes:           "Prefers decaf.",
+		NotesUpdatedBy:  "seller",
+		History: []*db.Order{
+			{OrderNumber: 12, SKU: "MUG_V1", Status: db.StatusShipped, TotalCents: 2000},
+			{OrderNumber: 7, SKU: "MUG_V1", Status: db.StatusRefunded, TotalCents: 2000},
+		},
+	}
+
+	var b strings.Builder
+	if err := CustomerDetailSection(detail).Render(t.Context(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"@octocat", "mailto:octocat@example.com", "$40.00", "Prefers decaf.", "Last edited by @seller", `action="/admin/customers/c1/notes"`, "#12", "#7"} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("page missing %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -79,3 +79,23 @@ func TestIsStorefrontReady(t *testing.T) {
 		})
 	}
 }
+
+func TestProductCategories(t *testing.T) {
+	t.Parallel()
+
+	products := []ProductSummary{
+		{SKU: "TSHIRT", Category: "Apparel"},
+		{SKU: "MUG"},
+		{SKU: "HOODIE", Category: "Apparel"},
+	}
+	categories := productCategories(products)
+	if len(categories) != 2 {
+		t.Fatalf("expected 2 categories, got %+v", categories)
+	}
+	if categories[0].Name != "Apparel" || len(categories[0].Products) != 2 || categories[0].Products[1].SKU != "HOODIE" {
+		t.Fatalf("unexpected first category: %+v", categories[0])
+	}
+	if categories[1].Name != "" || len(categories[1].Products) != 1 {
+		t.Fatalf("unexpected uncategorized group: %+v", categories[1])
+	}
+}
@@ -0,0 +1,34 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackingSlipCard(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	slip := PackingSlip{
+		OrderNumber:     7,
+		PlacedAt:        time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		SKU:             "TSHIRT",
+		ProductName:     "T-Shirt",
+		Quantity:        2,
+		Options:         []PackingSlipLine{{Label: "Size", Value: "M"}},
+		CustomerName:    "Mona Lisa",
+		ShippingAddress: "1 Main St\nSpringfield, IL 62701",
+	}
+	if err := PackingSlipCard("Octo Shop", slip).Render(t.Context(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{"Octo Shop", "Order #7", "Mar 4, 2026", "T-Shirt", "Size", "Mona Lisa", "1 Main St"} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("slip missing %q:\n%s", want, b.String())
+		}
+	}
+	if strings.Contains(b.String(), "$") {
+		t.Fatalf("slip shows a price:\n%s", b.String())
+	}
+}
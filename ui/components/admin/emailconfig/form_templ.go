@@ -9,6 +9,7 @@ import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
 import (
+	"github.com/gitshopapp/gitshop/ui/components/admin/idempotency"
 	"github.com/gitshopapp/gitshop/ui/components/button"
 	"github.com/gitshopapp/gitshop/ui/components/dialog"
 	"github.com/gitshopapp/gitshop/ui/components/input"
@@ -17,12 +18,14 @@ import (
 )
 
 type FormProps struct {
-	FormID              string
-	ProviderSelectID    string
-	ProviderTriggerID   string
-	APIKeyID            string
-	FromEmailID         string
-	DomainID            string
+	FormID            string
+	ProviderSelectID  string
+	ProviderTriggerID string
+	APIKeyID          string
+	FromEmailID       string
+	DomainID          string
+	// WebhookSecretID adds the delivery webhook secret field when set.
+	WebhookSecretID     string
 	ResultID            string
 	ProviderValue       string
 	SubmitLabel         string
@@ -58,7 +61,7 @@ func Form(props FormProps) templ.Component {
 		var templ_7745c5c3_Var2 string
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(props.FormID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 27, Col: 19}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 30, Col: 19}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
@@ -71,7 +74,7 @@ func Form(props FormProps) templ.Component {
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs("#" + props.ResultID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 29, Col: 34}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 32, Col: 34}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -91,6 +94,10 @@ func Form(props FormProps) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		templ_7745c5c3_Err = idempotency.Field().Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
 		templ_7745c5c3_Err = providerSection(props.ProviderSelectID, props.ProviderTriggerID, props.ProviderValue).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -99,7 +106,7 @@ func Form(props FormProps) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = credentialsSection(props.APIKeyID, props.FromEmailID, props.DomainID).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = credentialsSection(props.APIKeyID, props.FromEmailID, props.DomainID, props.WebhookSecretID).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -175,7 +182,7 @@ func Form(props FormProps) templ.Component {
 					var templ_7745c5c3_Var8 string
 					templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(props.SubmitLabel)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 52, Col: 24}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 54, Col: 24}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 					if templ_7745c5c3_Err != nil {
@@ -209,7 +216,7 @@ func Form(props FormProps) templ.Component {
 				var templ_7745c5c3_Var10 string
 				templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(props.SubmitLabel)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 57, Col: 23}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 59, Col: 23}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
 				if templ_7745c5c3_Err != nil {
@@ -229,7 +236,7 @@ func Form(props FormProps) templ.Component {
 		var templ_7745c5c3_Var11 string
 		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(props.ResultID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 61, Col: 25}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `ui/components/admin/emailconfig/form.templ`, Line: 63, Col: 25}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
 		if templ_7745c5c3_Err != nil {
@@ -444,7 +451,7 @@ func providerSection(selectID, triggerID, providerValue string) templ.Component
 	})
 }
 
-func credentialsSection(apiKeyID, fromEmailID, domainID string) templ.Component {
+func credentialsSection(apiKeyID, fromEmailID, domainID, webhookSecretID string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -555,7 +562,47 @@ func credentialsSection(apiKeyID, fromEmailID, domainID string) templ.Component
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "</div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "</div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if webhookSecretID != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "<div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Var24 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+				if !templ_7745c5c3_IsBuffer {
+					defer func() {
+						templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+						if templ_7745c5c3_Err == nil {
+							templ_7745c5c3_Err = templ_7745c5c3_BufErr
+						}
+					}()
+				}
+				ctx = templ.InitializeContext(ctx)
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "Webhook Signing Secret (optional) ")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				return nil
+			})
+			templ_7745c5c3_Err = label.Label(label.Props{For: webhookSecretID}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var24), templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = input.Input(input.Props{ID: webhookSecretID, Name: "webhook_secret", Type: input.TypePassword, Placeholder: "Leave blank to keep the saved secret"}).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "<p class=\"mt-1 text-xs text-muted-foreground\">Lets gitshop record bounces and complaints. Use the Mailgun webhook signing key, the Resend webhook secret, or for Postmark a password you also put in the webhook URL.</p></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "</div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -579,12 +626,12 @@ func formScript() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var24 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var24 == nil {
-			templ_7745c5c3_Var24 = templ.NopComponent
+		templ_7745c5c3_Var25 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var25 == nil {
+			templ_7745c5c3_Var25 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "<script>\n\t\t(function () {\n\t\t\tfunction syncEmailConfigForm(form) {\n\t\t\t\tif (!form) return;\n\t\t\t\tvar domainField = form.querySelector(\"[data-mailgun-domain-field]\");\n\t\t\t\tvar providerInput = form.querySelector(\"[data-email-provider-input]\");\n\t\t\t\tif (!domainField || !providerInput) return;\n\n\t\t\t\tvar isMailgun = (providerInput.value || \"\").toLowerCase() === \"mailgun\";\n\t\t\t\tdomainField.classList.toggle(\"hidden\", !isMailgun);\n\t\t\t}\n\n\t\t\tfunction syncAll(root) {\n\t\t\t\tif (!root || typeof root.querySelectorAll !== \"function\") return;\n\t\t\t\troot.querySelectorAll(\"[data-email-config-form]\").forEach(function (form) {\n\t\t\t\t\tsyncEmailConfigForm(form);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tif (!window.__gitshopEmailConfigBound) {\n\t\t\t\twindow.__gitshopEmailConfigBound = true;\n\t\t\t\tdocument.addEventListener(\"change\", function (event) {\n\t\t\t\t\tvar target = event.target;\n\t\t\t\t\tif (!target || !target.matches(\"[data-email-provider-input]\")) return;\n\t\t\t\t\tvar form = target.closest(\"[data-email-config-form]\");\n\t\t\t\t\tsyncEmailConfigForm(form);\n\t\t\t\t});\n\t\t\t\tdocument.addEventListener(\"htmx:afterSwap\", function (event) {\n\t\t\t\t\tsyncAll(event && event.target ? event.target : document);\n\t\t\t\t});\n\t\t\t\tdocument.body.addEventListener(\"email-settings-updated\", function () {\n\t\t\t\t\tif (document.querySelector(\"[data-email-config-form][data-email-reload-on-success=\\\"true\\\"]\")) {\n\t\t\t\t\t\twindow.location.reload();\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tif (document.readyState === \"loading\") {\n\t\t\t\tdocument.addEventListener(\"DOMContentLoaded\", function () {\n\t\t\t\t\tsyncAll(document);\n\t\t\t\t});\n\t\t\t} else {\n\t\t\t\tsyncAll(document);\n\t\t\t}\n\t\t})();\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "<script>\n\t\t(function () {\n\t\t\tfunction syncEmailConfigForm(form) {\n\t\t\t\tif (!form) return;\n\t\t\t\tvar domainField = form.querySelector(\"[data-mailgun-domain-field]\");\n\t\t\t\tvar providerInput = form.querySelector(\"[data-email-provider-input]\");\n\t\t\t\tif (!domainField || !providerInput) return;\n\n\t\t\t\tvar isMailgun = (providerInput.value || \"\").toLowerCase() === \"mailgun\";\n\t\t\t\tdomainField.classList.toggle(\"hidden\", !isMailgun);\n\t\t\t}\n\n\t\t\tfunction syncAll(root) {\n\t\t\t\tif (!root || typeof root.querySelectorAll !== \"function\") return;\n\t\t\t\troot.querySelectorAll(\"[data-email-config-form]\").forEach(function (form) {\n\t\t\t\t\tsyncEmailConfigForm(form);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tif (!window.__gitshopEmailConfigBound) {\n\t\t\t\twindow.__gitshopEmailConfigBound = true;\n\t\t\t\tdocument.addEventListener(\"change\", function (event) {\n\t\t\t\t\tvar target = event.target;\n\t\t\t\t\tif (!target || !target.matches(\"[data-email-provider-input]\")) return;\n\t\t\t\t\tvar form = target.closest(\"[data-email-config-form]\");\n\t\t\t\t\tsyncEmailConfigForm(form);\n\t\t\t\t});\n\t\t\t\tdocument.addEventListener(\"htmx:afterSwap\", function (event) {\n\t\t\t\t\tsyncAll(event && event.target ? event.target : document);\n\t\t\t\t});\n\t\t\t\tdocument.body.addEventListener(\"email-settings-updated\", function () {\n\t\t\t\t\tif (document.querySelector(\"[data-email-config-form][data-email-reload-on-success=\\\"true\\\"]\")) {\n\t\t\t\t\t\twindow.location.reload();\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tif (document.readyState === \"loading\") {\n\t\t\t\tdocument.addEventListener(\"DOMContentLoaded\", function () {\n\t\t\t\t\tsyncAll(document);\n\t\t\t\t});\n\t\t\t} else {\n\t\t\t\tsyncAll(document);\n\t\t\t}\n\t\t})();\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -0,0 +1,32 @@
+package operator
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/internal/webhooktap"
+	"github.com/gitshopapp/gitshop/ui/components/badge"
+)
+
+func receivedWebhookStatusVariant(status db.ReceivedWebhookStatus) badge.Variant {
+	switch status {
+	case db.ReceivedWebhookProcessed:
+		return badge.VariantDefault
+	case db.ReceivedWebhookFailed:
+		return badge.VariantDestructive
+	default:
+		return badge.VariantSecondary
+	}
+}
+
+// redactedPayload shows a stored payload with buyer details and secrets removed, the same way
+// the webhook tap does.
+func redactedPayload(payload []byte) string {
+	sanitized := webhooktap.Sanitize(payload)
+	var out bytes.Buffer
+	if err := json.Indent(&out, sanitized, "", "  "); err != nil {
+		return string(sanitized)
+	}
+	return out.String()
+}
@@ -0,0 +1,27 @@
+package settings
+
+import (
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/ui/components/badge"
+)
+
+func apiKeyAccessLabel(key *db.APIKey) string {
+	if key.CustomerData {
+		return "Orders and customers"
+	}
+	return "Orders"
+}
+
+func apiKeyAccessVariant(key *db.APIKey) badge.Variant {
+	if key.CustomerData {
+		return badge.VariantDefault
+	}
+	return badge.VariantSecondary
+}
+
+func apiKeyLastUsedLabel(key *db.APIKey) string {
+	if key.LastUsedAt == nil {
+		return "Never"
+	}
+	return key.LastUsedAt.Format("Jan 2, 2006 15:04")
+}
@@ -3,8 +3,9 @@ package settings
 import "encoding/json"
 
 type shopEmailConfigView struct {
-	APIKey string `json:"api_key"`
-	Domain string `json:"domain"`
+	APIKey        string `json:"api_key"`
+	Domain        string `json:"domain"`
+	WebhookSecret string `json:"webhook_secret"`
 }
 
 func decodeShopEmailConfig(config map[string]any) shopEmailConfigView {
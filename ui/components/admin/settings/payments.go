@@ -0,0 +1,14 @@
+package settings
+
+// Form values for the payment processor select.
+const (
+	PaymentProcessorStripe       = "stripe"
+	PaymentProcessorLemonSqueezy = "lemonsqueezy"
+)
+
+func paymentSecretPlaceholder(saved bool) string {
+	if saved {
+		return "Leave blank to keep the saved value"
+	}
+	return ""
+}
@@ -0,0 +1,13 @@
+package settings
+
+import "strings"
+
+// Form values for the processing region email log select.
+const (
+	EmailLogKeep   = "keep"
+	EmailLogRedact = "redact"
+)
+
+func regionLabel(region string) string {
+	return strings.ToUpper(region)
+}
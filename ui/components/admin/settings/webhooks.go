@@ -0,0 +1,39 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/gitshopapp/gitshop/internal/db"
+	"github.com/gitshopapp/gitshop/ui/components/badge"
+)
+
+func webhookSecretPlaceholder(hasSecret bool) string {
+	if hasSecret {
+		return "Leave blank to keep the current secret"
+	}
+	return "At least 16 characters"
+}
+
+func webhookStatusVariant(status db.WebhookDeliveryStatus) badge.Variant {
+	switch status {
+	case db.WebhookDeliverySucceeded:
+		return badge.VariantDefault
+	case db.WebhookDeliveryFailed:
+		return badge.VariantDestructive
+	default:
+		return badge.VariantSecondary
+	}
+}
+
+func webhookResponseLabel(delivery *db.WebhookDelivery) string {
+	switch {
+	case delivery.LastError != "" && delivery.LastResponseStatus > 0:
+		return fmt.Sprintf("HTTP %d: %s", delivery.LastResponseStatus, delivery.LastError)
+	case delivery.LastError != "":
+		return delivery.LastError
+	case delivery.LastResponseStatus > 0:
+		return fmt.Sprintf("HTTP %d", delivery.LastResponseStatus)
+	default:
+		return "—"
+	}
+}
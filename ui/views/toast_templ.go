@@ -104,8 +104,8 @@ func ToastOOB(payload ToastPayload) templ.Component {
 	})
 }
 
-// Convenience helpers
-func ToastSuccessOOB(title, description string) templ.Component {
+// toastFinalizeScript moves server-rendered toasts into the toast root and strips the toast query parameter.
+func toastFinalizeScript() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -126,7 +126,7 @@ func ToastSuccessOOB(title, description string) templ.Component {
 			templ_7745c5c3_Var3 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = ToastOOB(ToastPayload{Title: title, Description: description, Variant: toast.VariantSuccess}).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<script>\n\t\t(function () {\n\t\t\tfunction finalizeToast() {\n\t\t\t\tvar root = document.getElementById(\"toast-root\");\n\t\t\t\tif (root) {\n\t\t\t\t\tdocument.querySelectorAll(\"[data-tui-toast]\").forEach(function (toast) {\n\t\t\t\t\t\tif (!root.contains(toast)) {\n\t\t\t\t\t\t\troot.appendChild(toast);\n\t\t\t\t\t\t}\n\t\t\t\t\t});\n\t\t\t\t}\n\t\t\t\tif (window.history && window.history.replaceState) {\n\t\t\t\t\tvar url = new URL(window.location.href);\n\t\t\t\t\turl.searchParams.delete(\"toast\");\n\t\t\t\t\twindow.history.replaceState({}, \"\", url.toString());\n\t\t\t\t}\n\t\t\t}\n\t\t\tif (document.readyState === \"loading\") {\n\t\t\t\tdocument.addEventListener(\"DOMContentLoaded\", finalizeToast);\n\t\t\t} else {\n\t\t\t\tfinalizeToast();\n\t\t\t}\n\t\t})();\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -134,7 +134,8 @@ func ToastSuccessOOB(title, description string) templ.Component {
 	})
 }
 
-func ToastErrorOOB(title, description string) templ.Component {
+// Convenience helpers
+func ToastSuccessOOB(title, description string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -155,6 +156,35 @@ func ToastErrorOOB(title, description string) templ.Component {
 			templ_7745c5c3_Var4 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = ToastOOB(ToastPayload{Title: title, Description: description, Variant: toast.VariantSuccess}).Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+func ToastErrorOOB(title, description string) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var5 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var5 == nil {
+			templ_7745c5c3_Var5 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
 		templ_7745c5c3_Err = ToastOOB(ToastPayload{Title: title, Description: description, Variant: toast.VariantError}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err